@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gittuf provides an experimental API for Go programs that embed
+// gittuf. The API is not stable and may change without notice.
+package gittuf
+
+import (
+	"context"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/go-git/go-git/v5/storage"
+)
+
+// RefUpdate is a single ref update received by a Git server, such as when a
+// client pushes to it.
+type RefUpdate = repository.RefUpdate
+
+// RefUpdateDecision records whether a received ref update must be allowed.
+type RefUpdateDecision = repository.RefUpdateDecision
+
+var (
+	ErrRefUpdateNotInRSL   = repository.ErrRefUpdateNotInRSL
+	ErrRSLNotFastForward   = repository.ErrRSLNotFastForward
+	ErrRSLUpdateNotAllowed = repository.ErrRSLUpdateNotAllowed
+)
+
+// VerifyReceivePack decides if the ref updates received by a Git server that
+// embeds go-git must be allowed. It must be invoked after the objects sent by
+// the client are written to the storer, but before any refs are updated, like
+// a pre-receive hook. Updates whose decision is not allowed must be rejected.
+func VerifyReceivePack(ctx context.Context, s storage.Storer, updates []*RefUpdate) ([]*RefUpdateDecision, error) {
+	repo, err := repository.LoadRepositoryFromStorer(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.VerifyReceivedRefUpdates(ctx, updates)
+}
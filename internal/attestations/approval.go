@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	GitHubPullRequestApprovalPredicateType = "https://gittuf.dev/github-pull-request-approval/v0.1"
+	approvalTargetRefKey                   = "targetRef"
+	approvalCommitIDKey                    = "commitID"
+)
+
+var (
+	ErrInvalidGitHubPullRequestApprovalAttestation  = errors.New("GitHub pull request approval attestation does not match expected details")
+	ErrGitHubPullRequestApprovalAttestationNotFound = errors.New("requested GitHub pull request approval attestation not found")
+)
+
+// GitHubPullRequestApproval is a lightweight record of the approvals a GitHub
+// pull request has received for a specific commit. It is meant to be used as a
+// "predicate" in an in-toto attestation.
+type GitHubPullRequestApproval struct {
+	Owner             string   `json:"owner"`
+	Repository        string   `json:"repository"`
+	PullRequestNumber int      `json:"pullRequestNumber"`
+	TargetRef         string   `json:"targetRef"`
+	CommitID          string   `json:"commitID"`
+	Approvers         []string `json:"approvers"`
+}
+
+// NewGitHubPullRequestApprovalAttestation creates a new GitHub pull request
+// approval attestation recording that the specified approvers approved merging
+// the pull request into `targetRef` when its head was at `commitID`. Approvers
+// are identified by their GitHub usernames, or by the IDs of their keys in the
+// policy when mapped from their usernames, and are sorted so the attestation
+// is deterministic.
+func NewGitHubPullRequestApprovalAttestation(owner, repository string, pullRequestNumber int, targetRef, commitID string, approvers []string) (*ita.Statement, error) {
+	approvers = slices.Clone(approvers)
+	slices.Sort(approvers)
+
+	predicate := &GitHubPullRequestApproval{
+		Owner:             owner,
+		Repository:        repository,
+		PullRequestNumber: pullRequestNumber,
+		TargetRef:         targetRef,
+		CommitID:          commitID,
+		Approvers:         slices.Compact(approvers),
+	}
+
+	predicateBytes, err := json.Marshal(predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	predicateInterface := &map[string]any{}
+	if err := json.Unmarshal(predicateBytes, predicateInterface); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(*predicateInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Uri:    fmt.Sprintf("https://github.com/%s/%s/pull/%d", owner, repository, pullRequestNumber),
+				Digest: map[string]string{digestGitCommitKey: commitID},
+			},
+		},
+		PredicateType: GitHubPullRequestApprovalPredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// GetGitHubPullRequestApprovers returns the approvers recorded in a GitHub pull
+// request approval attestation.
+func GetGitHubPullRequestApprovers(env *sslibdsse.Envelope) ([]string, error) {
+	statement, err := dsse.DecodePayload[ita.Statement](payloadCache, env, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if statement.PredicateType != GitHubPullRequestApprovalPredicateType || statement.Predicate == nil {
+		return nil, ErrInvalidGitHubPullRequestApprovalAttestation
+	}
+
+	predicateBytes, err := statement.Predicate.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	predicate := &GitHubPullRequestApproval{}
+	if err := json.Unmarshal(predicateBytes, predicate); err != nil {
+		return nil, err
+	}
+
+	return predicate.Approvers, nil
+}
+
+// SetGitHubPullRequestApprovalAttestation writes the new GitHub pull request
+// approval attestation to the object store and tracks it in the current
+// attestations state. Any existing approval attestation for the same ref and
+// commit is replaced.
+func (a *Attestations) SetGitHubPullRequestApprovalAttestation(repo *git.Repository, env *sslibdsse.Envelope, targetRef, commitID string) error {
+	if err := validateGitHubPullRequestApprovalAttestation(env, targetRef, commitID); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := gitinterface.WriteBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.githubPullRequestApprovalAttestations == nil {
+		a.githubPullRequestApprovalAttestations = map[string]plumbing.Hash{}
+	}
+
+	a.githubPullRequestApprovalAttestations[GitHubPullRequestApprovalAttestationPath(targetRef, commitID)] = blobID
+	return nil
+}
+
+// RemoveGitHubPullRequestApprovalAttestation removes the GitHub pull request
+// approval attestation for the ref and commit from the current attestations
+// state.
+func (a *Attestations) RemoveGitHubPullRequestApprovalAttestation(targetRef, commitID string) error {
+	approvalPath := GitHubPullRequestApprovalAttestationPath(targetRef, commitID)
+	if _, has := a.githubPullRequestApprovalAttestations[approvalPath]; !has {
+		return ErrGitHubPullRequestApprovalAttestationNotFound
+	}
+
+	delete(a.githubPullRequestApprovalAttestations, approvalPath)
+	return nil
+}
+
+// GetGitHubPullRequestApprovalAttestationFor returns the requested GitHub pull
+// request approval attestation (with its signatures).
+func (a *Attestations) GetGitHubPullRequestApprovalAttestationFor(repo *git.Repository, targetRef, commitID string) (*sslibdsse.Envelope, error) {
+	blobID, has := a.githubPullRequestApprovalAttestations[GitHubPullRequestApprovalAttestationPath(targetRef, commitID)]
+	if !has {
+		return nil, ErrGitHubPullRequestApprovalAttestationNotFound
+	}
+
+	envBytes, err := gitinterface.ReadBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	if err := validateGitHubPullRequestApprovalAttestation(env, targetRef, commitID); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// GitHubPullRequestApprovalAttestationPath constructs the expected path on-disk
+// for the GitHub pull request approval attestation.
+func GitHubPullRequestApprovalAttestationPath(targetRef, commitID string) string {
+	return path.Join(targetRef, commitID)
+}
+
+func validateGitHubPullRequestApprovalAttestation(env *sslibdsse.Envelope, targetRef, commitID string) error {
+	statement, err := dsse.DecodePayload[ita.Statement](payloadCache, env, nil)
+	if err != nil {
+		return err
+	}
+
+	if statement.PredicateType != GitHubPullRequestApprovalPredicateType || statement.Predicate == nil {
+		return ErrInvalidGitHubPullRequestApprovalAttestation
+	}
+
+	predicate := statement.Predicate.AsMap()
+
+	if predicate[approvalTargetRefKey] != targetRef {
+		return ErrInvalidGitHubPullRequestApprovalAttestation
+	}
+
+	if predicate[approvalCommitIDKey] != commitID {
+		return ErrInvalidGitHubPullRequestApprovalAttestation
+	}
+
+	return nil
+}
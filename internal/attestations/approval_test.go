@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGitHubPullRequestApprovalAttestation(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := plumbing.ZeroHash.String()
+
+	approval, err := NewGitHubPullRequestApprovalAttestation("gittuf", "gittuf", 1, testRef, testID, []string{"bob", "alice", "bob"})
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, approval.Type)
+	assert.Equal(t, GitHubPullRequestApprovalPredicateType, approval.PredicateType)
+	assert.Equal(t, "https://github.com/gittuf/gittuf/pull/1", approval.Subject[0].Uri)
+	assert.Equal(t, testID, approval.Subject[0].Digest[digestGitCommitKey])
+
+	predicate := approval.Predicate.AsMap()
+	assert.Equal(t, testRef, predicate[approvalTargetRefKey])
+	assert.Equal(t, testID, predicate[approvalCommitIDKey])
+	assert.Equal(t, []any{"alice", "bob"}, predicate["approvers"])
+}
+
+func TestGitHubPullRequestApprovalAttestation(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := plumbing.ZeroHash.String()
+
+	approval, err := NewGitHubPullRequestApprovalAttestation("gittuf", "gittuf", 1, testRef, testID, []string{"alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(approval)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	err = attestations.SetGitHubPullRequestApprovalAttestation(repo, env, "refs/heads/feature", testID)
+	assert.ErrorIs(t, err, ErrInvalidGitHubPullRequestApprovalAttestation)
+
+	err = attestations.SetGitHubPullRequestApprovalAttestation(repo, env, testRef, testID)
+	assert.Nil(t, err)
+	assert.Contains(t, attestations.githubPullRequestApprovalAttestations, GitHubPullRequestApprovalAttestationPath(testRef, testID))
+
+	_, err = attestations.GetGitHubPullRequestApprovalAttestationFor(repo, "refs/heads/feature", testID)
+	assert.ErrorIs(t, err, ErrGitHubPullRequestApprovalAttestationNotFound)
+
+	storedEnv, err := attestations.GetGitHubPullRequestApprovalAttestationFor(repo, testRef, testID)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+
+	approvers, err := GetGitHubPullRequestApprovers(storedEnv)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"alice"}, approvers)
+
+	assert.Empty(t, attestations.CheckIntegrity(repo))
+
+	err = attestations.RemoveGitHubPullRequestApprovalAttestation(testRef, testID)
+	assert.Nil(t, err)
+	assert.Empty(t, attestations.githubPullRequestApprovalAttestations)
+
+	err = attestations.RemoveGitHubPullRequestApprovalAttestation(testRef, testID)
+	assert.ErrorIs(t, err, ErrGitHubPullRequestApprovalAttestationNotFound)
+}
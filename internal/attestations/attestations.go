@@ -3,26 +3,44 @@
 package attestations
 
 import (
+	"encoding/json"
 	"errors"
+	"path"
 
+	"filippo.io/age"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/perf"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 )
 
 const (
-	Ref                                        = "refs/gittuf/attestations"
-	referenceAuthorizationsTreeEntryName       = "reference-authorizations"
-	githubPullRequestAttestationsTreeEntryName = "github-pull-requests"
-	initialCommitMessage                       = "Initial commit"
-	defaultCommitMessage                       = "Update attestations"
+	Ref                                                = "refs/gittuf/attestations"
+	referenceAuthorizationsTreeEntryName               = "reference-authorizations"
+	githubPullRequestAttestationsTreeEntryName         = "github-pull-requests"
+	githubPullRequestApprovalAttestationsTreeEntryName = "github-pull-request-approvals"
+	githubReleaseAttestationsTreeEntryName             = "github-releases"
+	verificationSummariesTreeEntryName                 = "verification-summaries"
+	buildEnvironmentsTreeEntryName                     = "build-environments"
+	changeSetsTreeEntryName                            = "change-sets"
+	provenanceTreeEntryName                            = "provenance"
+	automationDelegationsTreeEntryName                 = "automation-delegations"
+	customAttestationsTreeEntryName                    = "custom"
+	initialCommitMessage                               = "Initial commit"
+	defaultCommitMessage                               = "Update attestations"
 )
 
 var ErrAttestationsExist = errors.New("cannot initialize attestations namespace as it exists already")
 
+// payloadCache memoizes decoded attestation statements for the lifetime of the
+// process, as the same attestations are read repeatedly during verification.
+var payloadCache = dsse.NewPayloadCache()
+
 // InitializeNamespace creates a namespace to store attestations for
 // verification with gittuf. The ref is created with an initial, unsigned commit
 // that is unsigned.
@@ -61,6 +79,63 @@ type Attestations struct {
 	// `<ref-path>/<commit-id>`, where `ref-path` is the absolute ref path, and
 	// `commit-id` is the ID of the merged commit.
 	githubPullRequestAttestations map[string]plumbing.Hash
+
+	// githubPullRequestApprovalAttestations maps the approvals a GitHub pull
+	// request has received for a commit. The key is a path of the form
+	// `<ref-path>/<commit-id>`, where `ref-path` is the absolute ref path of
+	// the pull request's base branch, and `commit-id` is the ID of the pull
+	// request's head commit that was approved.
+	githubPullRequestApprovalAttestations map[string]plumbing.Hash
+
+	// githubReleaseAttestations maps information about a GitHub release to
+	// the RSL entry of the release's tag. The key is a path of the form
+	// `<tag-ref-path>/<rsl-entry-id>`, where `tag-ref-path` is the absolute
+	// ref path of the tag, and `rsl-entry-id` is the ID of the tag's RSL
+	// entry.
+	githubReleaseAttestations map[string]plumbing.Hash
+
+	// verificationSummaries maps the verification summaries signed by
+	// observers to the RSL entry of the verified ref. The key is a path of the
+	// form `<ref-path>/<rsl-entry-id>`, where `ref-path` is the absolute ref
+	// path, and `rsl-entry-id` is the ID of the ref's RSL entry that was
+	// verified.
+	verificationSummaries map[string]plumbing.Hash
+
+	// buildEnvironments maps the build environments that created RSL entries
+	// on behalf of automation to the entries. The key is a path of the form
+	// `<ref-path>/<rsl-entry-id>`, where `ref-path` is the absolute ref path,
+	// and `rsl-entry-id` is the ID of the ref's RSL entry created in the build
+	// environment.
+	buildEnvironments map[string]plumbing.Hash
+
+	// changeSets maps the changes authorized as part of a change set spanning
+	// multiple repositories to the set's attestation. The key is a path of the
+	// form `<ref-path>/<from-id>-<to-id>`, as for reference authorizations.
+	// Each of the set's changes for this repository is tracked, so the same
+	// attestation may be stored under multiple keys.
+	changeSets map[string]plumbing.Hash
+
+	// provenanceAttestations maps the SLSA provenance produced by external
+	// builders to the commits and tags built. The key is a path of the form
+	// `<object-id>/<payload-digest>`, where `object-id` is the ID of the
+	// commit or tag object, and `payload-digest` is the SHA-256 digest of the
+	// attestation's payload, distinguishing multiple attestations for the same
+	// object.
+	provenanceAttestations map[string]plumbing.Hash
+
+	// automationDelegations maps the delegations granting automation keys
+	// permission to record RSL entries for specific refs. The key is the
+	// SHA-256 digest of the attestation's payload.
+	automationDelegations map[string]plumbing.Hash
+
+	// customAttestations maps attestations with custom predicates to the RSL
+	// entries they record evidence for. The key is a path of the form
+	// `<ref-path>/<rsl-entry-id>-<predicate-type-digest>`, where `ref-path` is
+	// the absolute ref path, `rsl-entry-id` is the ID of the ref's RSL entry,
+	// and `predicate-type-digest` is the SHA-256 digest of the predicate type,
+	// distinguishing attestations with different predicates for the same
+	// entry.
+	customAttestations map[string]plumbing.Hash
 }
 
 // LoadCurrentAttestations inspects the repository's attestations namespace and
@@ -81,6 +156,8 @@ func LoadCurrentAttestations(repo *git.Repository) (*Attestations, error) {
 // LoadAttestationsForEntry loads the repository's attestations for a particular
 // RSL entry for the attestations namespace.
 func LoadAttestationsForEntry(repo *git.Repository, entry *rsl.ReferenceEntry) (*Attestations, error) {
+	defer perf.Track(perf.AttestationLookups)()
+
 	if entry.RefName != Ref {
 		return nil, rsl.ErrRSLEntryDoesNotMatchRef
 	}
@@ -103,15 +180,40 @@ func LoadAttestationsForEntry(repo *git.Repository, entry *rsl.ReferenceEntry) (
 	}
 
 	var (
-		authorizationsTreeID     plumbing.Hash
-		githubPullRequestsTreeID plumbing.Hash
+		authorizationsTreeID             plumbing.Hash
+		githubPullRequestsTreeID         plumbing.Hash
+		githubPullRequestApprovalsTreeID plumbing.Hash
+		githubReleasesTreeID             plumbing.Hash
+		verificationSummariesTreeID      plumbing.Hash
+		buildEnvironmentsTreeID          plumbing.Hash
+		changeSetsTreeID                 plumbing.Hash
+		provenanceTreeID                 plumbing.Hash
+		automationDelegationsTreeID      plumbing.Hash
+		customAttestationsTreeID         plumbing.Hash
 	)
 
 	for _, e := range attestationsRootTree.Entries {
-		if e.Name == referenceAuthorizationsTreeEntryName {
+		switch e.Name {
+		case referenceAuthorizationsTreeEntryName:
 			authorizationsTreeID = e.Hash
-		} else if e.Name == githubPullRequestAttestationsTreeEntryName {
+		case githubPullRequestAttestationsTreeEntryName:
 			githubPullRequestsTreeID = e.Hash
+		case githubPullRequestApprovalAttestationsTreeEntryName:
+			githubPullRequestApprovalsTreeID = e.Hash
+		case githubReleaseAttestationsTreeEntryName:
+			githubReleasesTreeID = e.Hash
+		case verificationSummariesTreeEntryName:
+			verificationSummariesTreeID = e.Hash
+		case buildEnvironmentsTreeEntryName:
+			buildEnvironmentsTreeID = e.Hash
+		case changeSetsTreeEntryName:
+			changeSetsTreeID = e.Hash
+		case provenanceTreeEntryName:
+			provenanceTreeID = e.Hash
+		case automationDelegationsTreeEntryName:
+			automationDelegationsTreeID = e.Hash
+		case customAttestationsTreeEntryName:
+			customAttestationsTreeID = e.Hash
 		}
 	}
 
@@ -140,9 +242,159 @@ func LoadAttestationsForEntry(repo *git.Repository, entry *rsl.ReferenceEntry) (
 		return nil, err
 	}
 
+	// The GitHub pull request approvals tree is only written when approval
+	// attestations exist, so it may be missing in older attestation states
+	if !githubPullRequestApprovalsTreeID.IsZero() {
+		githubPullRequestApprovalsTree, err := gitinterface.GetTree(repo, githubPullRequestApprovalsTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.githubPullRequestApprovalAttestations, err = gitinterface.GetAllFilesInTree(githubPullRequestApprovalsTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The GitHub releases tree is only written when release attestations
+	// exist, so it may be missing in older attestation states
+	if !githubReleasesTreeID.IsZero() {
+		githubReleasesTree, err := gitinterface.GetTree(repo, githubReleasesTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.githubReleaseAttestations, err = gitinterface.GetAllFilesInTree(githubReleasesTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The verification summaries tree is only written when verification
+	// summaries exist, so it may be missing in older attestation states
+	if !verificationSummariesTreeID.IsZero() {
+		verificationSummariesTree, err := gitinterface.GetTree(repo, verificationSummariesTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.verificationSummaries, err = gitinterface.GetAllFilesInTree(verificationSummariesTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The build environments tree is only written when build environment
+	// attestations exist, so it may be missing in older attestation states
+	if !buildEnvironmentsTreeID.IsZero() {
+		buildEnvironmentsTree, err := gitinterface.GetTree(repo, buildEnvironmentsTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.buildEnvironments, err = gitinterface.GetAllFilesInTree(buildEnvironmentsTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The change sets tree is only written when change set attestations
+	// exist, so it may be missing in older attestation states
+	if !changeSetsTreeID.IsZero() {
+		changeSetsTree, err := gitinterface.GetTree(repo, changeSetsTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.changeSets, err = gitinterface.GetAllFilesInTree(changeSetsTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The provenance tree is only written when provenance attestations exist,
+	// so it may be missing in older attestation states
+	if !provenanceTreeID.IsZero() {
+		provenanceTree, err := gitinterface.GetTree(repo, provenanceTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.provenanceAttestations, err = gitinterface.GetAllFilesInTree(provenanceTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The automation delegations tree is only written when automation
+	// delegations exist, so it may be missing in older attestation states
+	if !automationDelegationsTreeID.IsZero() {
+		automationDelegationsTree, err := gitinterface.GetTree(repo, automationDelegationsTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.automationDelegations, err = gitinterface.GetAllFilesInTree(automationDelegationsTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The custom attestations tree is only written when custom attestations
+	// exist, so it may be missing in older attestation states
+	if !customAttestationsTreeID.IsZero() {
+		customAttestationsTree, err := gitinterface.GetTree(repo, customAttestationsTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.customAttestations, err = gitinterface.GetAllFilesInTree(customAttestationsTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return attestations, nil
 }
 
+// GetAllAttestations returns every attestation (with its signatures) tracked in
+// the attestations state. The attestations are keyed by their path in the
+// attestations tree, prefixed with the name of the subtree they are stored in.
+func (a *Attestations) GetAllAttestations(repo *git.Repository) (map[string]*sslibdsse.Envelope, error) {
+	allAttestations := map[string]*sslibdsse.Envelope{}
+
+	subtrees := map[string]map[string]plumbing.Hash{
+		referenceAuthorizationsTreeEntryName:               a.referenceAuthorizations,
+		githubPullRequestAttestationsTreeEntryName:         a.githubPullRequestAttestations,
+		githubPullRequestApprovalAttestationsTreeEntryName: a.githubPullRequestApprovalAttestations,
+		githubReleaseAttestationsTreeEntryName:             a.githubReleaseAttestations,
+		verificationSummariesTreeEntryName:                 a.verificationSummaries,
+		buildEnvironmentsTreeEntryName:                     a.buildEnvironments,
+		changeSetsTreeEntryName:                            a.changeSets,
+		provenanceTreeEntryName:                            a.provenanceAttestations,
+		automationDelegationsTreeEntryName:                 a.automationDelegations,
+		customAttestationsTreeEntryName:                    a.customAttestations,
+	}
+
+	for subtreeName, blobIDs := range subtrees {
+		for attestationPath, blobID := range blobIDs {
+			envBytes, err := gitinterface.ReadBlob(repo, blobID)
+			if err != nil {
+				return nil, err
+			}
+
+			env := &sslibdsse.Envelope{}
+			if err := json.Unmarshal(envBytes, env); err != nil {
+				return nil, err
+			}
+
+			allAttestations[path.Join(subtreeName, attestationPath)] = env
+		}
+	}
+
+	return allAttestations, nil
+}
+
 // Commit writes the state of the attestations to the repository, creating a new
 // commit with the changes made. An RSL entry is also recorded for the
 // namespace.
@@ -176,6 +428,112 @@ func (a *Attestations) Commit(repo *git.Repository, commitMessage string, signCo
 		Hash: githubPullRequestsTreeID,
 	})
 
+	// Add GitHub pull request approvals tree, only if approval attestations
+	// exist
+	if len(a.githubPullRequestApprovalAttestations) != 0 {
+		githubPullRequestApprovalsTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.githubPullRequestApprovalAttestations)
+		if err != nil {
+			return err
+		}
+		attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+			Name: githubPullRequestApprovalAttestationsTreeEntryName,
+			Mode: filemode.Dir,
+			Hash: githubPullRequestApprovalsTreeID,
+		})
+	}
+
+	// Add GitHub releases tree, only if release attestations exist
+	if len(a.githubReleaseAttestations) != 0 {
+		githubReleasesTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.githubReleaseAttestations)
+		if err != nil {
+			return err
+		}
+		attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+			Name: githubReleaseAttestationsTreeEntryName,
+			Mode: filemode.Dir,
+			Hash: githubReleasesTreeID,
+		})
+	}
+
+	// Add verification summaries tree, only if verification summaries exist
+	if len(a.verificationSummaries) != 0 {
+		verificationSummariesTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.verificationSummaries)
+		if err != nil {
+			return err
+		}
+		attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+			Name: verificationSummariesTreeEntryName,
+			Mode: filemode.Dir,
+			Hash: verificationSummariesTreeID,
+		})
+	}
+
+	// Add build environments tree, only if build environment attestations
+	// exist
+	if len(a.buildEnvironments) != 0 {
+		buildEnvironmentsTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.buildEnvironments)
+		if err != nil {
+			return err
+		}
+		attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+			Name: buildEnvironmentsTreeEntryName,
+			Mode: filemode.Dir,
+			Hash: buildEnvironmentsTreeID,
+		})
+	}
+
+	// Add change sets tree, only if change set attestations exist
+	if len(a.changeSets) != 0 {
+		changeSetsTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.changeSets)
+		if err != nil {
+			return err
+		}
+		attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+			Name: changeSetsTreeEntryName,
+			Mode: filemode.Dir,
+			Hash: changeSetsTreeID,
+		})
+	}
+
+	// Add provenance tree, only if provenance attestations exist
+	if len(a.provenanceAttestations) != 0 {
+		provenanceTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.provenanceAttestations)
+		if err != nil {
+			return err
+		}
+		attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+			Name: provenanceTreeEntryName,
+			Mode: filemode.Dir,
+			Hash: provenanceTreeID,
+		})
+	}
+
+	// Add automation delegations tree, only if automation delegations exist
+	if len(a.automationDelegations) != 0 {
+		automationDelegationsTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.automationDelegations)
+		if err != nil {
+			return err
+		}
+		attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+			Name: automationDelegationsTreeEntryName,
+			Mode: filemode.Dir,
+			Hash: automationDelegationsTreeID,
+		})
+	}
+
+	// Add custom attestations tree, only if custom attestations exist
+	if len(a.customAttestations) != 0 {
+		customAttestationsTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.customAttestations)
+		if err != nil {
+			return err
+		}
+		attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+			Name: customAttestationsTreeEntryName,
+			Mode: filemode.Dir,
+			Hash: customAttestationsTreeID,
+		})
+	}
+
 	attestationsTreeID, err := gitinterface.WriteTree(repo, attestationsTreeEntries)
 	if err != nil {
 		return err
@@ -200,3 +558,38 @@ func (a *Attestations) Commit(repo *git.Repository, commitMessage string, signCo
 
 	return nil
 }
+
+// IsAttestationsTree returns true if the tree has the layout of the
+// attestations namespace, i.e., it only contains trees for known types of
+// attestations. The empty tree is not considered an attestations tree as it is
+// also used by other gittuf objects.
+func IsAttestationsTree(tree *object.Tree) bool {
+	if len(tree.Entries) == 0 {
+		return false
+	}
+
+	for _, e := range tree.Entries {
+		switch e.Name {
+		case referenceAuthorizationsTreeEntryName, githubPullRequestAttestationsTreeEntryName, githubPullRequestApprovalAttestationsTreeEntryName, githubReleaseAttestationsTreeEntryName, verificationSummariesTreeEntryName, buildEnvironmentsTreeEntryName, changeSetsTreeEntryName, provenanceTreeEntryName, automationDelegationsTreeEntryName, customAttestationsTreeEntryName:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// decryptAttestation returns the attestation as it was before it was
+// encrypted, decrypting it using the identity. Attestations that aren't
+// encrypted are returned as is, and the identity may be nil.
+func decryptAttestation(env *sslibdsse.Envelope, identity age.Identity) (*sslibdsse.Envelope, error) {
+	if !dsse.IsEncrypted(env) {
+		return env, nil
+	}
+
+	if identity == nil {
+		return nil, dsse.ErrEnvelopeEncrypted
+	}
+
+	return dsse.DecryptEnvelope(env, identity)
+}
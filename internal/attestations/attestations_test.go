@@ -4,6 +4,7 @@ package attestations
 
 import (
 	"encoding/json"
+	"path"
 	"testing"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
@@ -321,4 +322,89 @@ func TestAttestationsCommit(t *testing.T) {
 	attestations, err = LoadCurrentAttestations(repo)
 	assert.Nil(t, err)
 	assert.Equal(t, attestations.referenceAuthorizations, authorizations)
+
+	t.Run("with GitHub release attestations", func(t *testing.T) {
+		testTagRef := "refs/tags/v1"
+		release, err := NewGitHubReleaseAttestation("gittuf", "gittuf", 1, testTagRef, testID, testID, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		releaseEnv, err := dsse.CreateEnvelope(release)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := attestations.SetGitHubReleaseAttestation(repo, releaseEnv, testTagRef, testID); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := attestations.Commit(repo, "Test commit", false); err != nil {
+			t.Fatal(err)
+		}
+
+		ref, err := repo.Reference(plumbing.ReferenceName(Ref), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commit, err := gitinterface.GetCommit(repo, ref.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rootTree, err := gitinterface.GetTree(repo, commit.TreeHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 3, len(rootTree.Entries))
+		assert.Equal(t, githubPullRequestAttestationsTreeEntryName, rootTree.Entries[0].Name)
+		assert.Equal(t, githubReleaseAttestationsTreeEntryName, rootTree.Entries[1].Name)
+		assert.Equal(t, referenceAuthorizationsTreeEntryName, rootTree.Entries[2].Name)
+
+		if err := rsl.NewReferenceEntry(Ref, ref.Hash()).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		loadedAttestations, err := LoadCurrentAttestations(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, attestations.githubReleaseAttestations, loadedAttestations.githubReleaseAttestations)
+	})
+}
+
+func TestGetAllAttestations(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := plumbing.ZeroHash.String()
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	allAttestations, err := attestations.GetAllAttestations(repo)
+	assert.Nil(t, err)
+	assert.Empty(t, allAttestations)
+
+	authorization := createReferenceAuthorizationAttestationEnvelopes(t, testRef, testID, testID)
+	if err := attestations.SetReferenceAuthorization(repo, authorization, testRef, testID, testID); err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := NewGitHubReleaseAttestation("gittuf", "gittuf", 1, "refs/tags/v1", testID, testID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	releaseEnv, err := dsse.CreateEnvelope(release)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := attestations.SetGitHubReleaseAttestation(repo, releaseEnv, "refs/tags/v1", testID); err != nil {
+		t.Fatal(err)
+	}
+
+	allAttestations, err = attestations.GetAllAttestations(repo)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(allAttestations))
+	assert.Equal(t, authorization, allAttestations[path.Join(referenceAuthorizationsTreeEntryName, ReferenceAuthorizationPath(testRef, testID, testID))])
+	assert.Equal(t, releaseEnv, allAttestations[path.Join(githubReleaseAttestationsTreeEntryName, GitHubReleaseAttestationPath("refs/tags/v1", testID))])
 }
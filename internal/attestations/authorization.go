@@ -9,6 +9,7 @@ import (
 	"path"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	ita "github.com/in-toto/attestation/go/v1"
@@ -147,14 +148,13 @@ func ReferenceAuthorizationPath(refName, fromID, toID string) string {
 }
 
 func validateReferenceAuthorization(env *sslibdsse.Envelope, targetRef, fromRevisionID, targetTreeID string) error {
-	payload, err := env.DecodeB64Payload()
+	attestation, err := dsse.DecodePayload[ita.Statement](payloadCache, env, nil)
 	if err != nil {
 		return err
 	}
 
-	attestation := &ita.Statement{}
-	if err := json.Unmarshal(payload, attestation); err != nil {
-		return err
+	if len(attestation.Subject) == 0 || attestation.Predicate == nil {
+		return ErrInvalidAuthorization
 	}
 
 	if attestation.Subject[0].Digest[digestGitTreeKey] != targetTreeID {
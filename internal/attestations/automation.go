@@ -0,0 +1,260 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	AutomationDelegationPredicateType = "https://gittuf.dev/automation-delegation/v0.1"
+
+	// MaxAutomationDelegationValidity is the longest time window an automation
+	// delegation may be valid for. Delegations are meant to be issued for each
+	// run of the automation, so that a leaked automation key is only useful
+	// for a short time.
+	MaxAutomationDelegationValidity = 24 * time.Hour
+)
+
+var (
+	ErrInvalidAutomationDelegation  = errors.New("invalid automation delegation")
+	ErrAutomationDelegationNotFound = errors.New("requested automation delegation not found")
+)
+
+// AutomationDelegation grants an automation key, such as the key of a CI job,
+// permission to record RSL entries for specific refs within a time window on
+// behalf of the keys that sign the delegation. It is meant to be used as a
+// "predicate" in an in-toto attestation.
+type AutomationDelegation struct {
+	// Key is the automation key granted permission.
+	Key *tuf.Key `json:"key"`
+
+	// Refs are the patterns of the absolute refs the automation key may
+	// record entries for, such as refs/heads/main or refs/heads/release/*.
+	// They're matched like the patterns of rules.
+	Refs []string `json:"refs"`
+
+	// NotBefore is the start of the time window the delegation is valid for.
+	NotBefore time.Time `json:"notBefore"`
+
+	// NotAfter is the end of the time window the delegation is valid for.
+	NotAfter time.Time `json:"notAfter"`
+}
+
+// Validate checks that the delegation grants permission to a key for at least
+// one ref, and that its time window is no longer than
+// MaxAutomationDelegationValidity.
+func (d *AutomationDelegation) Validate() error {
+	if d.Key == nil || d.Key.KeyID == "" {
+		return fmt.Errorf("%w: automation key must be set", ErrInvalidAutomationDelegation)
+	}
+
+	if len(d.Refs) == 0 {
+		return fmt.Errorf("%w: at least one ref must be specified", ErrInvalidAutomationDelegation)
+	}
+	for _, pattern := range d.Refs {
+		if !strings.HasPrefix(pattern, gitinterface.RefPrefix) {
+			return fmt.Errorf("%w: ref '%s' must be absolute", ErrInvalidAutomationDelegation, pattern)
+		}
+		if err := tuf.ValidatePattern(pattern); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidAutomationDelegation, err)
+		}
+	}
+
+	if !d.NotAfter.After(d.NotBefore) {
+		return fmt.Errorf("%w: validity must end after it starts", ErrInvalidAutomationDelegation)
+	}
+	if d.NotAfter.Sub(d.NotBefore) > MaxAutomationDelegationValidity {
+		return fmt.Errorf("%w: validity may not exceed %s", ErrInvalidAutomationDelegation, MaxAutomationDelegationValidity)
+	}
+
+	return nil
+}
+
+// Matches returns true if the delegation grants permission for the ref.
+func (d *AutomationDelegation) Matches(refName string) bool {
+	return (&tuf.Delegation{Paths: d.Refs}).Matches(refName)
+}
+
+// ActiveAt returns true if the specified time is within the delegation's time
+// window.
+func (d *AutomationDelegation) ActiveAt(at time.Time) bool {
+	return !at.Before(d.NotBefore) && !at.After(d.NotAfter)
+}
+
+// NewAutomationDelegationAttestation creates a new automation delegation
+// attestation for the provided delegation. The automation key is recorded as
+// the subject of the in-toto statement.
+func NewAutomationDelegationAttestation(delegation *AutomationDelegation) (*ita.Statement, error) {
+	if err := delegation.Validate(); err != nil {
+		return nil, err
+	}
+
+	predicateBytes, err := json.Marshal(delegation)
+	if err != nil {
+		return nil, err
+	}
+
+	predicateInterface := &map[string]any{}
+	if err := json.Unmarshal(predicateBytes, predicateInterface); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(*predicateInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Name: delegation.Key.KeyID,
+			},
+		},
+		PredicateType: AutomationDelegationPredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// GetAutomationDelegation returns the delegation recorded in an automation
+// delegation attestation.
+func GetAutomationDelegation(env *sslibdsse.Envelope) (*AutomationDelegation, error) {
+	statement, err := dsse.DecodePayload[ita.Statement](payloadCache, env, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if statement.PredicateType != AutomationDelegationPredicateType || statement.Predicate == nil {
+		return nil, ErrInvalidAutomationDelegation
+	}
+
+	predicateBytes, err := statement.Predicate.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	delegation := &AutomationDelegation{}
+	if err := json.Unmarshal(predicateBytes, delegation); err != nil {
+		return nil, err
+	}
+
+	if err := delegation.Validate(); err != nil {
+		return nil, err
+	}
+
+	return delegation, nil
+}
+
+// SetAutomationDelegation writes the automation delegation attestation to the
+// object store and tracks it in the current attestations state.
+func (a *Attestations) SetAutomationDelegation(repo *git.Repository, env *sslibdsse.Envelope) error {
+	if _, err := GetAutomationDelegation(env); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := gitinterface.WriteBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.automationDelegations == nil {
+		a.automationDelegations = map[string]plumbing.Hash{}
+	}
+
+	a.automationDelegations[AutomationDelegationPath(dsse.PayloadDigest(env))] = blobID
+	return nil
+}
+
+// GetAutomationDelegations returns every automation delegation attestation
+// (with its signatures) tracked in the attestations state, sorted by path.
+func (a *Attestations) GetAutomationDelegations(repo *git.Repository) ([]*sslibdsse.Envelope, error) {
+	paths := make([]string, 0, len(a.automationDelegations))
+	for delegationPath := range a.automationDelegations {
+		paths = append(paths, delegationPath)
+	}
+	sort.Strings(paths)
+
+	envelopes := make([]*sslibdsse.Envelope, 0, len(paths))
+	for _, delegationPath := range paths {
+		envBytes, err := gitinterface.ReadBlob(repo, a.automationDelegations[delegationPath])
+		if err != nil {
+			return nil, err
+		}
+
+		env := &sslibdsse.Envelope{}
+		if err := json.Unmarshal(envBytes, env); err != nil {
+			return nil, err
+		}
+
+		envelopes = append(envelopes, env)
+	}
+
+	return envelopes, nil
+}
+
+// RemoveAutomationDelegationsFor removes every automation delegation for the
+// specified automation key, revoking the key's permissions.
+func (a *Attestations) RemoveAutomationDelegationsFor(repo *git.Repository, keyID string) error {
+	removed := false
+	for delegationPath, blobID := range a.automationDelegations {
+		delegation, err := loadAutomationDelegation(repo, blobID)
+		if err != nil {
+			return err
+		}
+
+		if delegation.Key.KeyID == keyID {
+			delete(a.automationDelegations, delegationPath)
+			removed = true
+		}
+	}
+
+	if !removed {
+		return fmt.Errorf("%w: '%s'", ErrAutomationDelegationNotFound, keyID)
+	}
+
+	return nil
+}
+
+// AutomationDelegationPath constructs the expected path on-disk for the
+// automation delegation attestation. Delegations are identified by the SHA-256
+// digest of their payload, as the IDs of keys such as Sigstore identities may
+// not be valid paths.
+func AutomationDelegationPath(payloadDigest string) string {
+	return payloadDigest
+}
+
+// loadAutomationDelegation reads the automation delegation attestation with the
+// specified blob ID and returns the delegation recorded in it.
+func loadAutomationDelegation(repo *git.Repository, blobID plumbing.Hash) (*AutomationDelegation, error) {
+	envBytes, err := gitinterface.ReadBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	return GetAutomationDelegation(env)
+}
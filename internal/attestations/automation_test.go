@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutomationDelegationValidate(t *testing.T) {
+	key, err := tuf.LoadKeyFromBytes(artifacts.SSLibKey1Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notBefore := time.Date(1995, time.October, 26, 9, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		delegation *AutomationDelegation
+		expectErr  bool
+	}{
+		"valid delegation": {
+			delegation: &AutomationDelegation{Key: key, Refs: []string{"refs/heads/main", "refs/heads/release/*"}, NotBefore: notBefore, NotAfter: notBefore.Add(time.Hour)},
+		},
+		"no key": {
+			delegation: &AutomationDelegation{Refs: []string{"refs/heads/main"}, NotBefore: notBefore, NotAfter: notBefore.Add(time.Hour)},
+			expectErr:  true,
+		},
+		"no refs": {
+			delegation: &AutomationDelegation{Key: key, NotBefore: notBefore, NotAfter: notBefore.Add(time.Hour)},
+			expectErr:  true,
+		},
+		"relative ref": {
+			delegation: &AutomationDelegation{Key: key, Refs: []string{"main"}, NotBefore: notBefore, NotAfter: notBefore.Add(time.Hour)},
+			expectErr:  true,
+		},
+		"validity ends before it starts": {
+			delegation: &AutomationDelegation{Key: key, Refs: []string{"refs/heads/main"}, NotBefore: notBefore, NotAfter: notBefore.Add(-time.Hour)},
+			expectErr:  true,
+		},
+		"validity too long": {
+			delegation: &AutomationDelegation{Key: key, Refs: []string{"refs/heads/main"}, NotBefore: notBefore, NotAfter: notBefore.Add(MaxAutomationDelegationValidity + time.Second)},
+			expectErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.delegation.Validate()
+			if test.expectErr {
+				assert.ErrorIs(t, err, ErrInvalidAutomationDelegation)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestAutomationDelegation(t *testing.T) {
+	key, err := tuf.LoadKeyFromBytes(artifacts.SSLibKey1Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notBefore := time.Date(1995, time.October, 26, 9, 0, 0, 0, time.UTC)
+	delegation := &AutomationDelegation{
+		Key:       key,
+		Refs:      []string{"refs/heads/release/*"},
+		NotBefore: notBefore,
+		NotAfter:  notBefore.Add(time.Hour),
+	}
+
+	assert.True(t, delegation.Matches("refs/heads/release/v1"))
+	assert.False(t, delegation.Matches("refs/heads/main"))
+	assert.True(t, delegation.ActiveAt(notBefore))
+	assert.True(t, delegation.ActiveAt(notBefore.Add(time.Hour)))
+	assert.False(t, delegation.ActiveAt(notBefore.Add(-time.Second)))
+	assert.False(t, delegation.ActiveAt(notBefore.Add(time.Hour+time.Second)))
+
+	statement, err := NewAutomationDelegationAttestation(delegation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, AutomationDelegationPredicateType, statement.PredicateType)
+	assert.Equal(t, key.KeyID, statement.Subject[0].Name)
+
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decodedDelegation, err := GetAutomationDelegation(env)
+	assert.Nil(t, err)
+	assert.Equal(t, delegation, decodedDelegation)
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	err = attestations.RemoveAutomationDelegationsFor(repo, key.KeyID)
+	assert.ErrorIs(t, err, ErrAutomationDelegationNotFound)
+
+	err = attestations.SetAutomationDelegation(repo, env)
+	assert.Nil(t, err)
+
+	envelopes, err := attestations.GetAutomationDelegations(repo)
+	assert.Nil(t, err)
+	assert.Len(t, envelopes, 1)
+	assert.Equal(t, env.Payload, envelopes[0].Payload)
+
+	// The delegation ended in 1995
+	removed, err := attestations.Compact(repo, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{path.Join(automationDelegationsTreeEntryName, AutomationDelegationPath(dsse.PayloadDigest(env)))}, removed)
+	assert.Empty(t, attestations.automationDelegations)
+
+	err = attestations.SetAutomationDelegation(repo, env)
+	assert.Nil(t, err)
+
+	err = attestations.RemoveAutomationDelegationsFor(repo, key.KeyID)
+	assert.Nil(t, err)
+	assert.Empty(t, attestations.automationDelegations)
+}
@@ -0,0 +1,229 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+
+	"filippo.io/age"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	BuildEnvironmentPredicateType = "https://gittuf.dev/build-environment/v0.1"
+	buildEnvironmentRefKey        = "ref"
+	buildEnvironmentRSLEntryIDKey = "rslEntryID"
+)
+
+var (
+	ErrInvalidBuildEnvironmentAttestation  = errors.New("build environment attestation does not match expected details")
+	ErrBuildEnvironmentAttestationNotFound = errors.New("requested build environment attestation not found")
+)
+
+// BuildEnvironment records the machine identity of the build environment, such
+// as a CI runner, that created an RSL entry on behalf of automation. It is
+// meant to be used as a "predicate" in an in-toto attestation.
+type BuildEnvironment struct {
+	// Ref is the ref the RSL entry is for.
+	Ref string `json:"ref"`
+
+	// RSLEntryID is the ID of the RSL entry created in the build environment.
+	RSLEntryID string `json:"rslEntryID"`
+
+	// Platform identifies the automation platform, such as "github-actions".
+	Platform string `json:"platform,omitempty"`
+
+	// Hostname is the hostname of the machine the entry was created on.
+	Hostname string `json:"hostname"`
+
+	// RunnerID identifies the runner on the automation platform.
+	RunnerID string `json:"runnerID,omitempty"`
+
+	// OIDCClaims contains the claims of the OIDC token issued to the build
+	// environment by the automation platform, if any.
+	OIDCClaims map[string]any `json:"oidcClaims,omitempty"`
+}
+
+// NewBuildEnvironmentAttestation creates a new build environment attestation
+// for the ref at the specified RSL entry. The ref's target is recorded as the
+// subject of the in-toto statement.
+func NewBuildEnvironmentAttestation(environment *BuildEnvironment, targetID string) (*ita.Statement, error) {
+	predicateBytes, err := json.Marshal(environment)
+	if err != nil {
+		return nil, err
+	}
+
+	predicateInterface := &map[string]any{}
+	if err := json.Unmarshal(predicateBytes, predicateInterface); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(*predicateInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Name:   environment.Ref,
+				Digest: map[string]string{digestGitCommitKey: targetID},
+			},
+		},
+		PredicateType: BuildEnvironmentPredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// GetBuildEnvironment returns the build environment recorded in a build
+// environment attestation. An encrypted attestation is decrypted using the
+// identity, which may be nil otherwise.
+func GetBuildEnvironment(env *sslibdsse.Envelope, identity age.Identity) (*BuildEnvironment, error) {
+	env, err := decryptAttestation(env, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	statement, err := dsse.DecodePayload[ita.Statement](payloadCache, env, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if statement.PredicateType != BuildEnvironmentPredicateType || statement.Predicate == nil {
+		return nil, ErrInvalidBuildEnvironmentAttestation
+	}
+
+	predicateBytes, err := statement.Predicate.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	environment := &BuildEnvironment{}
+	if err := json.Unmarshal(predicateBytes, environment); err != nil {
+		return nil, err
+	}
+
+	return environment, nil
+}
+
+// SetBuildEnvironmentAttestation writes the new build environment attestation
+// to the object store and tracks it in the current attestations state.
+// Encrypted attestations must be set using
+// SetEncryptedBuildEnvironmentAttestation.
+func (a *Attestations) SetBuildEnvironmentAttestation(repo *git.Repository, env *sslibdsse.Envelope, refName, rslEntryID string) error {
+	if dsse.IsEncrypted(env) {
+		return dsse.ErrEnvelopeEncrypted
+	}
+
+	if err := validateBuildEnvironmentAttestation(env, refName, rslEntryID); err != nil {
+		return err
+	}
+
+	return a.setBuildEnvironmentAttestation(repo, env, refName, rslEntryID)
+}
+
+// SetEncryptedBuildEnvironmentAttestation writes the new encrypted build
+// environment attestation to the object store and tracks it in the current
+// attestations state. The attestation is validated using decryptedEnv, the
+// attestation it was encrypted from, as the writer may not be able to decrypt
+// it.
+func (a *Attestations) SetEncryptedBuildEnvironmentAttestation(repo *git.Repository, env, decryptedEnv *sslibdsse.Envelope, refName, rslEntryID string) error {
+	if !dsse.IsEncrypted(env) {
+		return dsse.ErrEnvelopeNotEncrypted
+	}
+
+	if err := validateBuildEnvironmentAttestation(decryptedEnv, refName, rslEntryID); err != nil {
+		return err
+	}
+
+	return a.setBuildEnvironmentAttestation(repo, env, refName, rslEntryID)
+}
+
+// GetBuildEnvironmentAttestationFor returns the requested build environment
+// attestation (with its signatures). An encrypted attestation is returned
+// encrypted, but is validated after it's decrypted using the identity, which
+// may be nil otherwise.
+func (a *Attestations) GetBuildEnvironmentAttestationFor(repo *git.Repository, refName, rslEntryID string, identity age.Identity) (*sslibdsse.Envelope, error) {
+	blobID, has := a.buildEnvironments[BuildEnvironmentAttestationPath(refName, rslEntryID)]
+	if !has {
+		return nil, ErrBuildEnvironmentAttestationNotFound
+	}
+
+	envBytes, err := gitinterface.ReadBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	decryptedEnv, err := decryptAttestation(env, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateBuildEnvironmentAttestation(decryptedEnv, refName, rslEntryID); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// BuildEnvironmentAttestationPath constructs the expected path on-disk for the
+// build environment attestation.
+func BuildEnvironmentAttestationPath(refName, rslEntryID string) string {
+	return path.Join(refName, rslEntryID)
+}
+
+func (a *Attestations) setBuildEnvironmentAttestation(repo *git.Repository, env *sslibdsse.Envelope, refName, rslEntryID string) error {
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := gitinterface.WriteBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.buildEnvironments == nil {
+		a.buildEnvironments = map[string]plumbing.Hash{}
+	}
+
+	a.buildEnvironments[BuildEnvironmentAttestationPath(refName, rslEntryID)] = blobID
+	return nil
+}
+
+func validateBuildEnvironmentAttestation(env *sslibdsse.Envelope, refName, rslEntryID string) error {
+	statement, err := dsse.DecodePayload[ita.Statement](payloadCache, env, nil)
+	if err != nil {
+		return err
+	}
+
+	if statement.PredicateType != BuildEnvironmentPredicateType || statement.Predicate == nil {
+		return ErrInvalidBuildEnvironmentAttestation
+	}
+
+	predicate := statement.Predicate.AsMap()
+
+	if predicate[buildEnvironmentRefKey] != refName {
+		return ErrInvalidBuildEnvironmentAttestation
+	}
+
+	if predicate[buildEnvironmentRSLEntryIDKey] != rslEntryID {
+		return ErrInvalidBuildEnvironmentAttestation
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	sshsv "github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestNewBuildEnvironmentAttestation(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := plumbing.ZeroHash.String()
+	environment := &BuildEnvironment{
+		Ref:        testRef,
+		RSLEntryID: testID,
+		Platform:   "github-actions",
+		Hostname:   "runner-host",
+		RunnerID:   "GitHub Actions 2",
+		OIDCClaims: map[string]any{"iss": "https://token.actions.githubusercontent.com", "repository": "gittuf/gittuf"},
+	}
+
+	statement, err := NewBuildEnvironmentAttestation(environment, testID)
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, statement.Type)
+	assert.Equal(t, BuildEnvironmentPredicateType, statement.PredicateType)
+	assert.Equal(t, 1, len(statement.Subject))
+	assert.Equal(t, testRef, statement.Subject[0].Name)
+	assert.Equal(t, testID, statement.Subject[0].Digest[digestGitCommitKey])
+
+	predicate := statement.Predicate.AsMap()
+	assert.Equal(t, testRef, predicate[buildEnvironmentRefKey])
+	assert.Equal(t, testID, predicate[buildEnvironmentRSLEntryIDKey])
+	assert.Equal(t, "runner-host", predicate["hostname"])
+}
+
+func TestBuildEnvironmentAttestation(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := plumbing.ZeroHash.String()
+	environment := &BuildEnvironment{
+		Ref:        testRef,
+		RSLEntryID: testID,
+		Platform:   "github-actions",
+		Hostname:   "runner-host",
+		RunnerID:   "GitHub Actions 2",
+		OIDCClaims: map[string]any{"iss": "https://token.actions.githubusercontent.com", "repository": "gittuf/gittuf"},
+	}
+
+	statement, err := NewBuildEnvironmentAttestation(environment, testID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	err = attestations.SetBuildEnvironmentAttestation(repo, env, "refs/heads/feature", testID)
+	assert.ErrorIs(t, err, ErrInvalidBuildEnvironmentAttestation)
+
+	err = attestations.SetBuildEnvironmentAttestation(repo, env, testRef, testID)
+	assert.Nil(t, err)
+	assert.Contains(t, attestations.buildEnvironments, BuildEnvironmentAttestationPath(testRef, testID))
+
+	_, err = attestations.GetBuildEnvironmentAttestationFor(repo, "refs/heads/feature", testID, nil)
+	assert.ErrorIs(t, err, ErrBuildEnvironmentAttestationNotFound)
+
+	storedEnv, err := attestations.GetBuildEnvironmentAttestationFor(repo, testRef, testID, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+
+	storedEnvironment, err := GetBuildEnvironment(storedEnv, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, environment, storedEnvironment)
+
+	if err := attestations.Commit(repo, "Test commit", false); err != nil {
+		t.Fatal(err)
+	}
+
+	loadedAttestations, err := LoadCurrentAttestations(repo)
+	assert.Nil(t, err)
+	assert.Equal(t, attestations.buildEnvironments, loadedAttestations.buildEnvironments)
+}
+
+func TestEncryptedBuildEnvironmentAttestation(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := plumbing.ZeroHash.String()
+	environment := &BuildEnvironment{
+		Ref:        testRef,
+		RSLEntryID: testID,
+		Hostname:   "runner-host",
+	}
+
+	statement, err := NewBuildEnvironmentAttestation(environment, testID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	identity, key := createTestEncryptionKey(t)
+	encryptedEnv, err := dsse.EncryptEnvelope(env, []*tuf.Key{key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	attestations := &Attestations{}
+
+	// Encrypted attestations are only set along with the attestation they
+	// were encrypted from, so that the predicate is validated
+	err = attestations.SetBuildEnvironmentAttestation(repo, encryptedEnv, testRef, testID)
+	assert.ErrorIs(t, err, dsse.ErrEnvelopeEncrypted)
+
+	err = attestations.SetEncryptedBuildEnvironmentAttestation(repo, env, env, testRef, testID)
+	assert.ErrorIs(t, err, dsse.ErrEnvelopeNotEncrypted)
+
+	err = attestations.SetEncryptedBuildEnvironmentAttestation(repo, encryptedEnv, env, "refs/heads/feature", testID)
+	assert.ErrorIs(t, err, ErrInvalidBuildEnvironmentAttestation)
+
+	err = attestations.SetEncryptedBuildEnvironmentAttestation(repo, encryptedEnv, env, testRef, testID)
+	assert.Nil(t, err)
+
+	// The predicate is validated after the attestation is decrypted
+	_, err = attestations.GetBuildEnvironmentAttestationFor(repo, testRef, testID, nil)
+	assert.ErrorIs(t, err, dsse.ErrEnvelopeEncrypted)
+
+	storedEnv, err := attestations.GetBuildEnvironmentAttestationFor(repo, testRef, testID, identity)
+	assert.Nil(t, err)
+	assert.Equal(t, encryptedEnv, storedEnv)
+
+	_, err = GetBuildEnvironment(storedEnv, nil)
+	assert.ErrorIs(t, err, dsse.ErrEnvelopeEncrypted)
+
+	storedEnvironment, err := GetBuildEnvironment(storedEnv, identity)
+	assert.Nil(t, err)
+	assert.Equal(t, environment, storedEnvironment)
+
+	// An encrypted attestation for another entry is rejected once decrypted
+	attestations.buildEnvironments[BuildEnvironmentAttestationPath("refs/heads/feature", testID)] = attestations.buildEnvironments[BuildEnvironmentAttestationPath(testRef, testID)]
+	_, err = attestations.GetBuildEnvironmentAttestationFor(repo, "refs/heads/feature", testID, identity)
+	assert.ErrorIs(t, err, ErrInvalidBuildEnvironmentAttestation)
+}
+
+// createTestEncryptionKey returns the identity to decrypt attestations using
+// the test ED25519 SSH key, and the key to encrypt them for.
+func createTestEncryptionKey(t *testing.T) (age.Identity, *tuf.Key) {
+	t.Helper()
+
+	identity, err := dsse.LoadDecryptionKey(artifacts.SSHED25519Private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshKey, _, _, _, err := ssh.ParseAuthorizedKey(artifacts.SSHED25519PublicSSH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &tuf.Key{
+		KeyID:   ssh.FingerprintSHA256(sshKey),
+		KeyType: sshsv.SSHKeyType,
+		Scheme:  sshKey.Type(),
+		KeyVal: sslibsv.KeyVal{
+			Public: base64.StdEncoding.EncodeToString(sshKey.Marshal()),
+		},
+	}
+
+	return identity, key
+}
@@ -0,0 +1,271 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const ChangeSetPredicateType = "https://gittuf.dev/change-set/v0.1"
+
+var (
+	ErrInvalidChangeSet            = errors.New("invalid change set")
+	ErrInvalidChangeSetAttestation = errors.New("change set attestation does not match expected details")
+	ErrChangeSetNotFound           = errors.New("requested change set attestation not found")
+)
+
+// ChangeSet is a coordinated set of changes across one or more repositories
+// that are authorized together, such as for a release spanning multiple
+// repositories. It is meant to be used as a "predicate" in an in-toto
+// attestation.
+type ChangeSet struct {
+	// ID identifies the change set, such as the name of the release.
+	ID string `json:"id"`
+
+	// Changes are the changes that make up the set.
+	Changes []*ChangeSetMember `json:"changes"`
+}
+
+// ChangeSetMember is a single change in a change set. Like a reference
+// authorization, it authorizes moving a ref in a repository from a commit to
+// a commit with the specified tree.
+type ChangeSetMember struct {
+	// Repository identifies the repository the change is for, such as its
+	// URL.
+	Repository string `json:"repository"`
+
+	// TargetRef is the absolute name of the ref that is changed.
+	TargetRef string `json:"targetRef"`
+
+	// FromRevisionID is the ID of the commit the ref is changed from, or the
+	// zero ID if the ref is created.
+	FromRevisionID string `json:"fromRevisionID"`
+
+	// TargetTreeID is the ID of the tree of the commit the ref is changed to.
+	TargetTreeID string `json:"targetTreeID"`
+}
+
+// Validate checks that the change set has an ID and at least one change, and
+// that each change is fully specified. A ref may only be changed once per
+// repository in a set.
+func (c *ChangeSet) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("%w: change set must have an ID", ErrInvalidChangeSet)
+	}
+
+	if len(c.Changes) == 0 {
+		return fmt.Errorf("%w: change set '%s' has no changes", ErrInvalidChangeSet, c.ID)
+	}
+
+	seen := map[string]bool{}
+	for _, change := range c.Changes {
+		if change == nil || change.Repository == "" {
+			return fmt.Errorf("%w: change in change set '%s' does not specify a repository", ErrInvalidChangeSet, c.ID)
+		}
+
+		if !strings.HasPrefix(change.TargetRef, gitinterface.RefPrefix) {
+			return fmt.Errorf("%w: change for '%s' must specify an absolute ref, found '%s'", ErrInvalidChangeSet, change.Repository, change.TargetRef)
+		}
+
+		if !plumbing.IsHash(change.FromRevisionID) || !plumbing.IsHash(change.TargetTreeID) {
+			return fmt.Errorf("%w: change for '%s' in '%s' must specify valid from and target tree IDs", ErrInvalidChangeSet, change.TargetRef, change.Repository)
+		}
+
+		key := change.Repository + "\x00" + change.TargetRef
+		if seen[key] {
+			return fmt.Errorf("%w: '%s' is changed more than once in '%s'", ErrInvalidChangeSet, change.TargetRef, change.Repository)
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
+
+// ChangesForRepository returns the changes in the set for the specified
+// repository.
+func (c *ChangeSet) ChangesForRepository(repository string) []*ChangeSetMember {
+	changes := []*ChangeSetMember{}
+	for _, change := range c.Changes {
+		if change.Repository == repository {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// Repositories returns the repositories with changes in the set, in the order
+// they first appear.
+func (c *ChangeSet) Repositories() []string {
+	repositories := []string{}
+	for _, change := range c.Changes {
+		if !slices.Contains(repositories, change.Repository) {
+			repositories = append(repositories, change.Repository)
+		}
+	}
+
+	return repositories
+}
+
+// includes returns true if the set has a change for the ref from the revision
+// to the tree, in any of its repositories.
+func (c *ChangeSet) includes(refName, fromRevisionID, targetTreeID string) bool {
+	for _, change := range c.Changes {
+		if change.TargetRef == refName && change.FromRevisionID == fromRevisionID && change.TargetTreeID == targetTreeID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewChangeSetAttestation creates a new change set attestation for the
+// provided change set. Each change is recorded as a subject of the in-toto
+// statement, identified by its repository and ref.
+func NewChangeSetAttestation(changeSet *ChangeSet) (*ita.Statement, error) {
+	if err := changeSet.Validate(); err != nil {
+		return nil, err
+	}
+
+	predicateBytes, err := json.Marshal(changeSet)
+	if err != nil {
+		return nil, err
+	}
+
+	predicateInterface := &map[string]any{}
+	if err := json.Unmarshal(predicateBytes, predicateInterface); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(*predicateInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	subjects := make([]*ita.ResourceDescriptor, 0, len(changeSet.Changes))
+	for _, change := range changeSet.Changes {
+		subjects = append(subjects, &ita.ResourceDescriptor{
+			Uri:    change.Repository,
+			Name:   change.TargetRef,
+			Digest: map[string]string{digestGitTreeKey: change.TargetTreeID},
+		})
+	}
+
+	return &ita.Statement{
+		Type:          ita.StatementTypeUri,
+		Subject:       subjects,
+		PredicateType: ChangeSetPredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// GetChangeSet returns the change set recorded in a change set attestation.
+func GetChangeSet(env *sslibdsse.Envelope) (*ChangeSet, error) {
+	statement, err := dsse.DecodePayload[ita.Statement](payloadCache, env, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if statement.PredicateType != ChangeSetPredicateType || statement.Predicate == nil {
+		return nil, ErrInvalidChangeSetAttestation
+	}
+
+	predicateBytes, err := statement.Predicate.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	changeSet := &ChangeSet{}
+	if err := json.Unmarshal(predicateBytes, changeSet); err != nil {
+		return nil, err
+	}
+
+	if err := changeSet.Validate(); err != nil {
+		return nil, errors.Join(ErrInvalidChangeSetAttestation, err)
+	}
+
+	return changeSet, nil
+}
+
+// SetChangeSetAttestation writes the change set attestation to the object
+// store and tracks it in the current attestations state for the specified
+// change, which must be part of the set.
+func (a *Attestations) SetChangeSetAttestation(repo *git.Repository, env *sslibdsse.Envelope, refName, fromRevisionID, targetTreeID string) error {
+	if err := validateChangeSetAttestation(env, refName, fromRevisionID, targetTreeID); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := gitinterface.WriteBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.changeSets == nil {
+		a.changeSets = map[string]plumbing.Hash{}
+	}
+
+	a.changeSets[ChangeSetAttestationPath(refName, fromRevisionID, targetTreeID)] = blobID
+	return nil
+}
+
+// GetChangeSetAttestationFor returns the change set attestation (with its
+// signatures) that includes the specified change.
+func (a *Attestations) GetChangeSetAttestationFor(repo *git.Repository, refName, fromRevisionID, targetTreeID string) (*sslibdsse.Envelope, error) {
+	blobID, has := a.changeSets[ChangeSetAttestationPath(refName, fromRevisionID, targetTreeID)]
+	if !has {
+		return nil, ErrChangeSetNotFound
+	}
+
+	envBytes, err := gitinterface.ReadBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	if err := validateChangeSetAttestation(env, refName, fromRevisionID, targetTreeID); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// ChangeSetAttestationPath constructs the expected path on-disk for the change
+// set attestation that includes the specified change.
+func ChangeSetAttestationPath(refName, fromID, toID string) string {
+	return path.Join(refName, fmt.Sprintf("%s-%s", fromID, toID))
+}
+
+func validateChangeSetAttestation(env *sslibdsse.Envelope, refName, fromRevisionID, targetTreeID string) error {
+	changeSet, err := GetChangeSet(env)
+	if err != nil {
+		return err
+	}
+
+	if !changeSet.includes(refName, fromRevisionID, targetTreeID) {
+		return ErrInvalidChangeSetAttestation
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+// The IDs are sized for the object format gittuf is built for.
+var (
+	testChangeSetFromID = plumbing.NewHash("a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2").String()
+	testChangeSetTreeID = plumbing.NewHash("b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3").String()
+)
+
+func createTestChangeSet() *ChangeSet {
+	return &ChangeSet{
+		ID: "release-1.0",
+		Changes: []*ChangeSetMember{
+			{
+				Repository:     "https://example.com/app",
+				TargetRef:      "refs/heads/main",
+				FromRevisionID: testChangeSetFromID,
+				TargetTreeID:   testChangeSetTreeID,
+			},
+			{
+				Repository:     "https://example.com/lib",
+				TargetRef:      "refs/heads/main",
+				FromRevisionID: plumbing.ZeroHash.String(),
+				TargetTreeID:   testChangeSetTreeID,
+			},
+			{
+				Repository:     "https://example.com/app",
+				TargetRef:      "refs/heads/release",
+				FromRevisionID: plumbing.ZeroHash.String(),
+				TargetTreeID:   testChangeSetTreeID,
+			},
+		},
+	}
+}
+
+func TestChangeSetValidate(t *testing.T) {
+	tests := map[string]struct {
+		modify func(*ChangeSet)
+		valid  bool
+	}{
+		"valid change set": {
+			modify: func(*ChangeSet) {},
+			valid:  true,
+		},
+		"no ID": {
+			modify: func(c *ChangeSet) { c.ID = "" },
+		},
+		"no changes": {
+			modify: func(c *ChangeSet) { c.Changes = nil },
+		},
+		"no repository": {
+			modify: func(c *ChangeSet) { c.Changes[0].Repository = "" },
+		},
+		"relative ref": {
+			modify: func(c *ChangeSet) { c.Changes[0].TargetRef = "main" },
+		},
+		"invalid tree ID": {
+			modify: func(c *ChangeSet) { c.Changes[0].TargetTreeID = "tree" },
+		},
+		"ref changed twice in repository": {
+			modify: func(c *ChangeSet) { c.Changes[2].TargetRef = "refs/heads/main" },
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			changeSet := createTestChangeSet()
+			test.modify(changeSet)
+
+			err := changeSet.Validate()
+			if test.valid {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidChangeSet)
+			}
+		})
+	}
+}
+
+func TestChangeSetRepositories(t *testing.T) {
+	changeSet := createTestChangeSet()
+
+	assert.Equal(t, []string{"https://example.com/app", "https://example.com/lib"}, changeSet.Repositories())
+	assert.Equal(t, []*ChangeSetMember{changeSet.Changes[0], changeSet.Changes[2]}, changeSet.ChangesForRepository("https://example.com/app"))
+	assert.Empty(t, changeSet.ChangesForRepository("https://example.com/other"))
+}
+
+func TestNewChangeSetAttestation(t *testing.T) {
+	changeSet := createTestChangeSet()
+
+	statement, err := NewChangeSetAttestation(changeSet)
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, statement.Type)
+	assert.Equal(t, ChangeSetPredicateType, statement.PredicateType)
+	assert.Equal(t, 3, len(statement.Subject))
+	assert.Equal(t, "https://example.com/lib", statement.Subject[1].Uri)
+	assert.Equal(t, "refs/heads/main", statement.Subject[1].Name)
+	assert.Equal(t, testChangeSetTreeID, statement.Subject[1].Digest[digestGitTreeKey])
+
+	changeSet.ID = ""
+	_, err = NewChangeSetAttestation(changeSet)
+	assert.ErrorIs(t, err, ErrInvalidChangeSet)
+}
+
+func TestChangeSetAttestation(t *testing.T) {
+	changeSet := createTestChangeSet()
+
+	statement, err := NewChangeSetAttestation(changeSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	// The change must be part of the set
+	err = attestations.SetChangeSetAttestation(repo, env, "refs/heads/main", plumbing.ZeroHash.String(), plumbing.ZeroHash.String())
+	assert.ErrorIs(t, err, ErrInvalidChangeSetAttestation)
+
+	err = attestations.SetChangeSetAttestation(repo, env, "refs/heads/main", testChangeSetFromID, testChangeSetTreeID)
+	assert.Nil(t, err)
+	assert.Contains(t, attestations.changeSets, ChangeSetAttestationPath("refs/heads/main", testChangeSetFromID, testChangeSetTreeID))
+
+	_, err = attestations.GetChangeSetAttestationFor(repo, "refs/heads/release", plumbing.ZeroHash.String(), testChangeSetTreeID)
+	assert.ErrorIs(t, err, ErrChangeSetNotFound)
+
+	storedEnv, err := attestations.GetChangeSetAttestationFor(repo, "refs/heads/main", testChangeSetFromID, testChangeSetTreeID)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+
+	storedChangeSet, err := GetChangeSet(storedEnv)
+	assert.Nil(t, err)
+	assert.Equal(t, changeSet, storedChangeSet)
+
+	if err := attestations.Commit(repo, "Test commit", false); err != nil {
+		t.Fatal(err)
+	}
+
+	loadedAttestations, err := LoadCurrentAttestations(repo)
+	assert.Nil(t, err)
+	assert.Equal(t, attestations.changeSets, loadedAttestations.changeSets)
+}
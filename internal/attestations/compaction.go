@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"errors"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var ErrInvalidRetention = errors.New("invalid retention, at least one RSL entry per ref must be retained")
+
+// refHistory is the recent history of a ref as recorded in the RSL.
+type refHistory struct {
+	// entryIDs are the IDs of the ref's retained RSL entries.
+	entryIDs map[plumbing.Hash]bool
+
+	// targetIDs are the targets of the ref's retained RSL entries, and the
+	// target of the entry preceding the oldest retained entry, i.e., the IDs
+	// the ref moved from in the retained entries. The latest target is the ID
+	// the ref moves from in its next entry. If the ref's first entry is
+	// retained, the zero ID it was created from is included.
+	targetIDs map[plumbing.Hash]bool
+
+	// deleted indicates the ref's latest entry records its deletion.
+	deleted bool
+}
+
+// Compact removes the attestations that are superseded or expired, retaining
+// those that apply to the latest keepEntries RSL entries of each ref and to
+// the next update of each ref. The removed attestations are returned as paths
+// prefixed with the name of the subtree they were stored in, in sorted order.
+//
+// Reference authorizations and change sets are superseded once the ref has
+// moved on from the ID they authorize a change from, while GitHub pull request
+// attestations are superseded once the merged commit is no longer one of the
+// ref's retained targets. Verification summaries, build environments, custom
+// attestations, and GitHub release attestations are superseded once the RSL
+// entry they are for is no longer retained. GitHub pull request approvals expire once the ref is
+// deleted. Automation delegations expire once their time window ends.
+// Attestations for refs not in the RSL and provenance attestations are always
+// retained.
+//
+// Compaction only changes the current attestations. Earlier attestation states
+// are unchanged, so RSL entries recorded before the compaction are verified
+// using the attestations that applied when they were recorded.
+func (a *Attestations) Compact(repo *git.Repository, keepEntries int) ([]string, error) {
+	if keepEntries < 1 {
+		return nil, ErrInvalidRetention
+	}
+
+	histories, err := loadRefHistories(repo, keepEntries)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := []string{}
+	compact := func(subtreeName string, blobIDs map[string]plumbing.Hash, isRetained func(history *refHistory, key string) bool) {
+		for key := range blobIDs {
+			history, has := histories[path.Dir(key)]
+			if !has || isRetained(history, path.Base(key)) {
+				continue
+			}
+
+			delete(blobIDs, key)
+			removed = append(removed, path.Join(subtreeName, key))
+		}
+	}
+
+	// Keys of the form <ref-path>/<from-id>-<to-id>
+	fromIDRetained := func(history *refHistory, name string) bool {
+		fromID, _, _ := strings.Cut(name, "-")
+		return history.targetIDs[plumbing.NewHash(fromID)]
+	}
+
+	// Keys of the form <ref-path>/<commit-id>
+	targetIDRetained := func(history *refHistory, name string) bool {
+		return history.targetIDs[plumbing.NewHash(name)]
+	}
+
+	// Keys of the form <ref-path>/<rsl-entry-id>
+	entryIDRetained := func(history *refHistory, name string) bool {
+		return history.entryIDs[plumbing.NewHash(name)]
+	}
+
+	// Keys of the form <ref-path>/<rsl-entry-id>-<predicate-type-digest>
+	entryIDPrefixRetained := func(history *refHistory, name string) bool {
+		entryID, _, _ := strings.Cut(name, "-")
+		return history.entryIDs[plumbing.NewHash(entryID)]
+	}
+
+	// Retained unless the ref's latest entry deletes it
+	refExists := func(history *refHistory, _ string) bool {
+		return !history.deleted
+	}
+
+	compact(referenceAuthorizationsTreeEntryName, a.referenceAuthorizations, fromIDRetained)
+	compact(changeSetsTreeEntryName, a.changeSets, fromIDRetained)
+	compact(githubPullRequestAttestationsTreeEntryName, a.githubPullRequestAttestations, targetIDRetained)
+	compact(githubPullRequestApprovalAttestationsTreeEntryName, a.githubPullRequestApprovalAttestations, refExists)
+	compact(githubReleaseAttestationsTreeEntryName, a.githubReleaseAttestations, entryIDRetained)
+	compact(verificationSummariesTreeEntryName, a.verificationSummaries, entryIDRetained)
+	compact(buildEnvironmentsTreeEntryName, a.buildEnvironments, entryIDRetained)
+	compact(customAttestationsTreeEntryName, a.customAttestations, entryIDPrefixRetained)
+
+	now := time.Now()
+	for key, blobID := range a.automationDelegations {
+		delegation, err := loadAutomationDelegation(repo, blobID)
+		if err != nil {
+			return nil, err
+		}
+
+		if delegation.NotAfter.Before(now) {
+			delete(a.automationDelegations, key)
+			removed = append(removed, path.Join(automationDelegationsTreeEntryName, key))
+		}
+	}
+
+	sort.Strings(removed)
+	return removed, nil
+}
+
+// loadRefHistories walks the RSL and returns the history of each ref recorded
+// in it, retaining the latest keepEntries entries of each ref.
+func loadRefHistories(repo *git.Repository, keepEntries int) (map[string]*refHistory, error) {
+	// The latest keepEntries+1 entries of each ref, latest first
+	entriesByRef := map[string][]*rsl.ReferenceEntry{}
+
+	iterator, err := rsl.NewIterator(repo)
+	if err != nil && !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return nil, err
+	}
+
+	for iterator != nil {
+		entry, err := iterator.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		referenceEntry, isReferenceEntry := entry.(*rsl.ReferenceEntry)
+		if !isReferenceEntry {
+			continue
+		}
+
+		if len(entriesByRef[referenceEntry.RefName]) <= keepEntries {
+			entriesByRef[referenceEntry.RefName] = append(entriesByRef[referenceEntry.RefName], referenceEntry)
+		}
+	}
+
+	histories := map[string]*refHistory{}
+	for refName, entries := range entriesByRef {
+		history := &refHistory{
+			entryIDs:  map[plumbing.Hash]bool{},
+			targetIDs: map[plumbing.Hash]bool{},
+			deleted:   entries[0].TargetID.IsZero(),
+		}
+
+		for index, entry := range entries {
+			if index < keepEntries {
+				history.entryIDs[entry.ID] = true
+			}
+			history.targetIDs[entry.TargetID] = true
+		}
+
+		if len(entries) <= keepEntries {
+			// The ref's first entry is retained
+			history.targetIDs[plumbing.ZeroHash] = true
+		}
+
+		histories[refName] = history
+	}
+
+	return histories, nil
+}
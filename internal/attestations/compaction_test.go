@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompact(t *testing.T) {
+	mainRef := "refs/heads/main"
+	featureRef := "refs/heads/feature"
+	newRef := "refs/heads/new"
+
+	commitA := plumbing.NewHash("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	commitB := plumbing.NewHash("bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+	commitC := plumbing.NewHash("cccccccccccccccccccccccccccccccccccccccc")
+	treeID := plumbing.NewHash("dddddddddddddddddddddddddddddddddddddddd").String()
+	zeroID := plumbing.ZeroHash.String()
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// main moves from A to B to C, feature is created and deleted
+	mainEntryIDs := []plumbing.Hash{}
+	for _, targetID := range []plumbing.Hash{commitA, commitB, commitC} {
+		if err := rsl.NewReferenceEntry(mainRef, targetID).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+		entry, err := rsl.GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mainEntryIDs = append(mainEntryIDs, entry.GetID())
+	}
+	if err := rsl.NewReferenceEntry(featureRef, commitA).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := rsl.NewReferenceEntry(featureRef, plumbing.ZeroHash).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+
+	newAttestations := func() *Attestations {
+		blobID := plumbing.NewHash("eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee")
+		return &Attestations{
+			referenceAuthorizations: map[string]plumbing.Hash{
+				ReferenceAuthorizationPath(mainRef, zeroID, treeID):           blobID,
+				ReferenceAuthorizationPath(mainRef, commitA.String(), treeID): blobID,
+				ReferenceAuthorizationPath(mainRef, commitB.String(), treeID): blobID,
+				ReferenceAuthorizationPath(mainRef, commitC.String(), treeID): blobID,
+				ReferenceAuthorizationPath(newRef, zeroID, treeID):            blobID,
+			},
+			githubPullRequestApprovalAttestations: map[string]plumbing.Hash{
+				GitHubPullRequestApprovalAttestationPath(mainRef, commitA.String()):    blobID,
+				GitHubPullRequestApprovalAttestationPath(featureRef, commitA.String()): blobID,
+			},
+			verificationSummaries: map[string]plumbing.Hash{
+				VerificationSummaryPath(mainRef, mainEntryIDs[1].String()): blobID,
+				VerificationSummaryPath(mainRef, mainEntryIDs[2].String()): blobID,
+			},
+			provenanceAttestations: map[string]plumbing.Hash{
+				ProvenanceAttestationPath(commitA.String(), "digest"): blobID,
+			},
+			customAttestations: map[string]plumbing.Hash{
+				CustomAttestationPath(mainRef, mainEntryIDs[1].String(), "https://example.com/audit-notes/v1"): blobID,
+				CustomAttestationPath(mainRef, mainEntryIDs[2].String(), "https://example.com/audit-notes/v1"): blobID,
+			},
+		}
+	}
+
+	t.Run("retain latest entry", func(t *testing.T) {
+		attestations := newAttestations()
+		removed, err := attestations.Compact(repo, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			"custom/" + CustomAttestationPath(mainRef, mainEntryIDs[1].String(), "https://example.com/audit-notes/v1"),
+			"github-pull-request-approvals/" + GitHubPullRequestApprovalAttestationPath(featureRef, commitA.String()),
+			"reference-authorizations/" + ReferenceAuthorizationPath(mainRef, zeroID, treeID),
+			"reference-authorizations/" + ReferenceAuthorizationPath(mainRef, commitA.String(), treeID),
+			"verification-summaries/" + VerificationSummaryPath(mainRef, mainEntryIDs[1].String()),
+		}, removed)
+
+		assert.Contains(t, attestations.referenceAuthorizations, ReferenceAuthorizationPath(mainRef, commitB.String(), treeID))
+		assert.Contains(t, attestations.referenceAuthorizations, ReferenceAuthorizationPath(mainRef, commitC.String(), treeID))
+		assert.Contains(t, attestations.referenceAuthorizations, ReferenceAuthorizationPath(newRef, zeroID, treeID))
+		assert.Contains(t, attestations.verificationSummaries, VerificationSummaryPath(mainRef, mainEntryIDs[2].String()))
+		assert.Contains(t, attestations.customAttestations, CustomAttestationPath(mainRef, mainEntryIDs[2].String(), "https://example.com/audit-notes/v1"))
+		assert.Len(t, attestations.provenanceAttestations, 1)
+	})
+
+	t.Run("retain all entries", func(t *testing.T) {
+		attestations := newAttestations()
+		removed, err := attestations.Compact(repo, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			"github-pull-request-approvals/" + GitHubPullRequestApprovalAttestationPath(featureRef, commitA.String()),
+		}, removed)
+		assert.Len(t, attestations.referenceAuthorizations, 5)
+	})
+
+	t.Run("invalid retention", func(t *testing.T) {
+		_, err := newAttestations().Compact(repo, 0)
+		assert.ErrorIs(t, err, ErrInvalidRetention)
+	})
+}
@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	customRSLEntryIDKey = "rslEntryID"
+
+	// gittufPredicateTypePrefix is the prefix of the predicate types defined
+	// by gittuf, which can't be used for custom attestations.
+	gittufPredicateTypePrefix = "https://gittuf.dev/"
+)
+
+var (
+	ErrInvalidCustomAttestation  = errors.New("custom attestation does not match expected details")
+	ErrInvalidCustomPredicate    = errors.New("custom attestations must have a predicate type not defined by gittuf")
+	ErrCustomAttestationNotFound = errors.New("requested custom attestation not found")
+)
+
+// NewCustomAttestation creates a new attestation with a custom predicate, such
+// as internal audit notes, for the ref at the specified RSL entry. The ref and
+// its target are recorded as the subject of the in-toto statement, which is
+// annotated with the ID of the RSL entry.
+func NewCustomAttestation(refName, rslEntryID, targetID, predicateType string, predicate map[string]any) (*ita.Statement, error) {
+	if predicateType == "" || strings.HasPrefix(predicateType, gittufPredicateTypePrefix) {
+		return nil, ErrInvalidCustomPredicate
+	}
+
+	predicateStruct, err := structpb.NewStruct(predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations, err := structpb.NewStruct(map[string]any{customRSLEntryIDKey: rslEntryID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Name:        refName,
+				Digest:      map[string]string{digestGitCommitKey: targetID},
+				Annotations: annotations,
+			},
+		},
+		PredicateType: predicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// GetCustomPredicate returns the predicate recorded in a custom attestation. An
+// encrypted attestation is decrypted using the identity, which may be nil
+// otherwise.
+func GetCustomPredicate(env *sslibdsse.Envelope, identity age.Identity) (map[string]any, error) {
+	env, err := decryptAttestation(env, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	statement, err := dsse.DecodePayload[ita.Statement](payloadCache, env, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if statement.Predicate == nil {
+		return nil, ErrInvalidCustomAttestation
+	}
+
+	return statement.Predicate.AsMap(), nil
+}
+
+// SetCustomAttestation writes the new custom attestation to the object store
+// and tracks it in the current attestations state. Encrypted attestations must
+// be set using SetEncryptedCustomAttestation.
+func (a *Attestations) SetCustomAttestation(repo *git.Repository, env *sslibdsse.Envelope, refName, rslEntryID string) error {
+	if dsse.IsEncrypted(env) {
+		return dsse.ErrEnvelopeEncrypted
+	}
+
+	predicateType, err := validateCustomAttestation(env, refName, rslEntryID)
+	if err != nil {
+		return err
+	}
+
+	return a.setCustomAttestation(repo, env, refName, rslEntryID, predicateType)
+}
+
+// SetEncryptedCustomAttestation writes the new encrypted custom attestation to
+// the object store and tracks it in the current attestations state. The
+// attestation is validated using decryptedEnv, the attestation it was
+// encrypted from, as the writer may not be able to decrypt it.
+func (a *Attestations) SetEncryptedCustomAttestation(repo *git.Repository, env, decryptedEnv *sslibdsse.Envelope, refName, rslEntryID string) error {
+	if !dsse.IsEncrypted(env) {
+		return dsse.ErrEnvelopeNotEncrypted
+	}
+
+	predicateType, err := validateCustomAttestation(decryptedEnv, refName, rslEntryID)
+	if err != nil {
+		return err
+	}
+
+	return a.setCustomAttestation(repo, env, refName, rslEntryID, predicateType)
+}
+
+// GetCustomAttestationFor returns the requested custom attestation (with its
+// signatures). An encrypted attestation is returned encrypted, but is
+// validated after it's decrypted using the identity, which may be nil
+// otherwise.
+func (a *Attestations) GetCustomAttestationFor(repo *git.Repository, refName, rslEntryID, predicateType string, identity age.Identity) (*sslibdsse.Envelope, error) {
+	blobID, has := a.customAttestations[CustomAttestationPath(refName, rslEntryID, predicateType)]
+	if !has {
+		return nil, ErrCustomAttestationNotFound
+	}
+
+	envBytes, err := gitinterface.ReadBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	decryptedEnv, err := decryptAttestation(env, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	storedPredicateType, err := validateCustomAttestation(decryptedEnv, refName, rslEntryID)
+	if err != nil {
+		return nil, err
+	}
+	if storedPredicateType != predicateType {
+		return nil, ErrInvalidCustomAttestation
+	}
+
+	return env, nil
+}
+
+// CustomAttestationPath constructs the expected path on-disk for the custom
+// attestation.
+func CustomAttestationPath(refName, rslEntryID, predicateType string) string {
+	return path.Join(refName, fmt.Sprintf("%s-%x", rslEntryID, sha256.Sum256([]byte(predicateType))))
+}
+
+func (a *Attestations) setCustomAttestation(repo *git.Repository, env *sslibdsse.Envelope, refName, rslEntryID, predicateType string) error {
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := gitinterface.WriteBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.customAttestations == nil {
+		a.customAttestations = map[string]plumbing.Hash{}
+	}
+
+	a.customAttestations[CustomAttestationPath(refName, rslEntryID, predicateType)] = blobID
+	return nil
+}
+
+// validateCustomAttestation checks that the custom attestation is for the ref
+// at the RSL entry, and returns its predicate type.
+func validateCustomAttestation(env *sslibdsse.Envelope, refName, rslEntryID string) (string, error) {
+	statement, err := dsse.DecodePayload[ita.Statement](payloadCache, env, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if statement.PredicateType == "" || strings.HasPrefix(statement.PredicateType, gittufPredicateTypePrefix) {
+		return "", ErrInvalidCustomPredicate
+	}
+
+	if len(statement.Subject) != 1 || statement.Predicate == nil {
+		return "", ErrInvalidCustomAttestation
+	}
+
+	subject := statement.Subject[0]
+	if subject.Name != refName {
+		return "", ErrInvalidCustomAttestation
+	}
+
+	if subject.Annotations == nil || subject.Annotations.AsMap()[customRSLEntryIDKey] != rslEntryID {
+		return "", ErrInvalidCustomAttestation
+	}
+
+	return statement.PredicateType, nil
+}
@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCustomPredicateType = "https://example.com/audit-notes/v1"
+
+func TestNewCustomAttestation(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := plumbing.ZeroHash.String()
+	predicate := map[string]any{"notes": "reviewed by internal audit"}
+
+	statement, err := NewCustomAttestation(testRef, testID, testID, testCustomPredicateType, predicate)
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, statement.Type)
+	assert.Equal(t, testCustomPredicateType, statement.PredicateType)
+	assert.Equal(t, 1, len(statement.Subject))
+	assert.Equal(t, testRef, statement.Subject[0].Name)
+	assert.Equal(t, testID, statement.Subject[0].Digest[digestGitCommitKey])
+	assert.Equal(t, testID, statement.Subject[0].Annotations.AsMap()[customRSLEntryIDKey])
+	assert.Equal(t, predicate, statement.Predicate.AsMap())
+
+	_, err = NewCustomAttestation(testRef, testID, testID, "", predicate)
+	assert.ErrorIs(t, err, ErrInvalidCustomPredicate)
+
+	_, err = NewCustomAttestation(testRef, testID, testID, BuildEnvironmentPredicateType, predicate)
+	assert.ErrorIs(t, err, ErrInvalidCustomPredicate)
+}
+
+func TestCustomAttestation(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := plumbing.ZeroHash.String()
+	predicate := map[string]any{"notes": "reviewed by internal audit"}
+
+	statement, err := NewCustomAttestation(testRef, testID, testID, testCustomPredicateType, predicate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	err = attestations.SetCustomAttestation(repo, env, "refs/heads/feature", testID)
+	assert.ErrorIs(t, err, ErrInvalidCustomAttestation)
+
+	err = attestations.SetCustomAttestation(repo, env, testRef, testID)
+	assert.Nil(t, err)
+	assert.Contains(t, attestations.customAttestations, CustomAttestationPath(testRef, testID, testCustomPredicateType))
+
+	_, err = attestations.GetCustomAttestationFor(repo, testRef, testID, "https://example.com/other/v1", nil)
+	assert.ErrorIs(t, err, ErrCustomAttestationNotFound)
+
+	storedEnv, err := attestations.GetCustomAttestationFor(repo, testRef, testID, testCustomPredicateType, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+
+	storedPredicate, err := GetCustomPredicate(storedEnv, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, predicate, storedPredicate)
+
+	if err := attestations.Commit(repo, "Test commit", false); err != nil {
+		t.Fatal(err)
+	}
+
+	loadedAttestations, err := LoadCurrentAttestations(repo)
+	assert.Nil(t, err)
+	assert.Equal(t, attestations.customAttestations, loadedAttestations.customAttestations)
+
+	t.Run("encrypted custom attestation", func(t *testing.T) {
+		identity, key := createTestEncryptionKey(t)
+		encryptedEnv, err := dsse.EncryptEnvelope(env, []*tuf.Key{key})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		attestations := &Attestations{}
+
+		err = attestations.SetCustomAttestation(repo, encryptedEnv, testRef, testID)
+		assert.ErrorIs(t, err, dsse.ErrEnvelopeEncrypted)
+
+		err = attestations.SetEncryptedCustomAttestation(repo, encryptedEnv, env, "refs/heads/feature", testID)
+		assert.ErrorIs(t, err, ErrInvalidCustomAttestation)
+
+		err = attestations.SetEncryptedCustomAttestation(repo, encryptedEnv, env, testRef, testID)
+		assert.Nil(t, err)
+
+		_, err = attestations.GetCustomAttestationFor(repo, testRef, testID, testCustomPredicateType, nil)
+		assert.ErrorIs(t, err, dsse.ErrEnvelopeEncrypted)
+
+		storedEnv, err := attestations.GetCustomAttestationFor(repo, testRef, testID, testCustomPredicateType, identity)
+		assert.Nil(t, err)
+		assert.Equal(t, encryptedEnv, storedEnv)
+
+		storedPredicate, err := GetCustomPredicate(storedEnv, identity)
+		assert.Nil(t, err)
+		assert.Equal(t, predicate, storedPredicate)
+
+		// An encrypted attestation stored for another predicate type is
+		// rejected once decrypted
+		otherPredicateType := "https://example.com/other/v1"
+		attestations.customAttestations[CustomAttestationPath(testRef, testID, otherPredicateType)] = attestations.customAttestations[CustomAttestationPath(testRef, testID, testCustomPredicateType)]
+		_, err = attestations.GetCustomAttestationFor(repo, testRef, testID, otherPredicateType, identity)
+		assert.ErrorIs(t, err, ErrInvalidCustomAttestation)
+	})
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var (
+	ErrInvalidAttestationPath              = errors.New("attestation is stored at an invalid path")
+	ErrInvalidGitHubPullRequestAttestation = errors.New("GitHub pull request attestation does not match expected details")
+)
+
+// CheckIntegrity validates every attestation tracked in the attestations
+// state, ensuring that it can be parsed and that its contents match the path it
+// is stored at. Signatures are not verified. The problems found are returned
+// keyed by the attestation's path in the attestations tree, prefixed with the
+// name of the subtree it is stored in, like GetAllAttestations.
+func (a *Attestations) CheckIntegrity(repo *git.Repository) map[string]error {
+	problems := map[string]error{}
+
+	subtrees := map[string]struct {
+		blobIDs  map[string]plumbing.Hash
+		validate func(*sslibdsse.Envelope, string, string) error
+	}{
+		referenceAuthorizationsTreeEntryName:               {a.referenceAuthorizations, validateReferenceAuthorizationAtPath},
+		githubPullRequestAttestationsTreeEntryName:         {a.githubPullRequestAttestations, validateGitHubPullRequestAttestation},
+		githubPullRequestApprovalAttestationsTreeEntryName: {a.githubPullRequestApprovalAttestations, validateGitHubPullRequestApprovalAttestation},
+		githubReleaseAttestationsTreeEntryName:             {a.githubReleaseAttestations, validateGitHubReleaseAttestation},
+	}
+
+	for subtreeName, subtree := range subtrees {
+		for attestationPath, blobID := range subtree.blobIDs {
+			if err := checkAttestationBlob(repo, blobID, attestationPath, subtree.validate); err != nil {
+				problems[path.Join(subtreeName, attestationPath)] = err
+			}
+		}
+	}
+
+	return problems
+}
+
+func checkAttestationBlob(repo *git.Repository, blobID plumbing.Hash, attestationPath string, validate func(*sslibdsse.Envelope, string, string) error) error {
+	envBytes, err := gitinterface.ReadBlob(repo, blobID)
+	if err != nil {
+		return err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return err
+	}
+
+	// All attestations are stored at a path of the form `<ref-path>/<id>`
+	refName, id := path.Split(attestationPath)
+	refName = strings.TrimSuffix(refName, "/")
+	if refName == "" || id == "" {
+		return ErrInvalidAttestationPath
+	}
+
+	return validate(env, refName, id)
+}
+
+func validateReferenceAuthorizationAtPath(env *sslibdsse.Envelope, refName, id string) error {
+	fromRevisionID, targetTreeID, found := strings.Cut(id, "-")
+	if !found {
+		return ErrInvalidAttestationPath
+	}
+
+	return validateReferenceAuthorization(env, refName, fromRevisionID, targetTreeID)
+}
+
+func validateGitHubPullRequestAttestation(env *sslibdsse.Envelope, _, commitID string) error {
+	statement, err := dsse.DecodePayload[ita.Statement](payloadCache, env, nil)
+	if err != nil {
+		return err
+	}
+
+	if statement.PredicateType != GitHubPullRequestPredicateType || len(statement.Subject) == 0 {
+		return ErrInvalidGitHubPullRequestAttestation
+	}
+
+	if statement.Subject[0].Digest[digestGitCommitKey] != commitID {
+		return ErrInvalidGitHubPullRequestAttestation
+	}
+
+	return nil
+}
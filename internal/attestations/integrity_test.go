@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"path"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	testRef := "refs/heads/main"
+	testAnotherRef := "refs/heads/feature"
+	testID := plumbing.ZeroHash.String()
+	mainZeroZero := createReferenceAuthorizationAttestationEnvelopes(t, testRef, testID, testID)
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+	if err := attestations.SetReferenceAuthorization(repo, mainZeroZero, testRef, testID, testID); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("valid attestations", func(t *testing.T) {
+		assert.Empty(t, attestations.CheckIntegrity(repo))
+	})
+
+	t.Run("attestation at wrong path", func(t *testing.T) {
+		wrongPath := ReferenceAuthorizationPath(testAnotherRef, testID, testID)
+		attestations.referenceAuthorizations[wrongPath] = attestations.referenceAuthorizations[ReferenceAuthorizationPath(testRef, testID, testID)]
+		defer delete(attestations.referenceAuthorizations, wrongPath)
+
+		problems := attestations.CheckIntegrity(repo)
+		assert.Equal(t, 1, len(problems))
+		assert.ErrorIs(t, problems[path.Join(referenceAuthorizationsTreeEntryName, wrongPath)], ErrInvalidAuthorization)
+	})
+
+	t.Run("malformed path", func(t *testing.T) {
+		wrongPath := path.Join(testRef, testID)
+		attestations.referenceAuthorizations[wrongPath] = attestations.referenceAuthorizations[ReferenceAuthorizationPath(testRef, testID, testID)]
+		defer delete(attestations.referenceAuthorizations, wrongPath)
+
+		problems := attestations.CheckIntegrity(repo)
+		assert.Equal(t, 1, len(problems))
+		assert.ErrorIs(t, problems[path.Join(referenceAuthorizationsTreeEntryName, wrongPath)], ErrInvalidAttestationPath)
+	})
+
+	t.Run("unparseable attestation", func(t *testing.T) {
+		blobID, err := gitinterface.WriteBlob(repo, []byte("not an attestation"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		attestationPath := GitHubPullRequestAttestationPath(testRef, testID)
+		attestations.githubPullRequestAttestations = map[string]plumbing.Hash{attestationPath: blobID}
+		defer func() { attestations.githubPullRequestAttestations = nil }()
+
+		problems := attestations.CheckIntegrity(repo)
+		assert.Equal(t, 1, len(problems))
+		assert.NotNil(t, problems[path.Join(githubPullRequestAttestationsTreeEntryName, attestationPath)])
+	})
+}
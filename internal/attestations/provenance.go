@@ -0,0 +1,260 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	SLSAProvenanceV1PredicateType  = "https://slsa.dev/provenance/v1"
+	SLSAProvenanceV02PredicateType = "https://slsa.dev/provenance/v0.2"
+)
+
+var (
+	ErrInvalidProvenanceAttestation  = errors.New("provenance attestation is not a valid SLSA provenance statement")
+	ErrProvenanceAttestationNotFound = errors.New("requested provenance attestation not found")
+	ErrProvenanceNotForObject        = errors.New("provenance attestation does not reference the object")
+)
+
+// provenanceDigestKeys are the digest algorithms used to identify Git objects
+// in the subjects and source materials of SLSA provenance. GitHub's SLSA
+// generator identifies the source commit using `sha1`.
+var provenanceDigestKeys = []string{digestGitCommitKey, "gitTag", "sha1"}
+
+// LoadProvenanceEnvelopes parses the SLSA provenance DSSE envelopes produced by
+// an external builder, such as GitHub Actions or GitLab CI. The contents may
+// be a single envelope or multiple envelopes in the JSON Lines format, as in
+// `.intoto.jsonl` files.
+func LoadProvenanceEnvelopes(contents []byte) ([]*sslibdsse.Envelope, error) {
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(contents, env); err == nil {
+		return []*sslibdsse.Envelope{env}, nil
+	}
+
+	envelopes := []*sslibdsse.Envelope{}
+	for _, line := range bytes.Split(contents, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		env := &sslibdsse.Envelope{}
+		if err := json.Unmarshal(line, env); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidProvenanceAttestation, err)
+		}
+
+		envelopes = append(envelopes, env)
+	}
+
+	if len(envelopes) == 0 {
+		return nil, fmt.Errorf("%w: no envelopes found", ErrInvalidProvenanceAttestation)
+	}
+
+	return envelopes, nil
+}
+
+// GetProvenanceBuilderID returns the ID of the builder that produced the SLSA
+// provenance, such as the URI of the GitHub Actions workflow that ran the
+// build.
+func GetProvenanceBuilderID(env *sslibdsse.Envelope) (string, error) {
+	statement, err := decodeProvenanceStatement(env)
+	if err != nil {
+		return "", err
+	}
+
+	return provenanceBuilderID(statement)
+}
+
+// SetProvenanceAttestation writes the SLSA provenance attestation to the object
+// store and tracks it in the current attestations state for the specified
+// commit or tag. The provenance must reference the object, or the commit a tag
+// points to, in its subjects or source materials. Multiple provenance
+// attestations may be tracked for an object, such as for the builds of
+// different artifacts.
+func (a *Attestations) SetProvenanceAttestation(repo *git.Repository, env *sslibdsse.Envelope, objectID string) error {
+	if err := validateProvenanceAttestation(repo, env, objectID); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := gitinterface.WriteBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.provenanceAttestations == nil {
+		a.provenanceAttestations = map[string]plumbing.Hash{}
+	}
+
+	a.provenanceAttestations[ProvenanceAttestationPath(objectID, dsse.PayloadDigest(env))] = blobID
+	return nil
+}
+
+// GetProvenanceAttestationsFor returns the SLSA provenance attestations (with
+// their signatures) tracked for the specified commit or tag.
+func (a *Attestations) GetProvenanceAttestationsFor(repo *git.Repository, objectID string) ([]*sslibdsse.Envelope, error) {
+	paths := []string{}
+	for provenancePath := range a.provenanceAttestations {
+		if strings.HasPrefix(provenancePath, objectID+"/") {
+			paths = append(paths, provenancePath)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, ErrProvenanceAttestationNotFound
+	}
+	sort.Strings(paths)
+
+	envelopes := make([]*sslibdsse.Envelope, 0, len(paths))
+	for _, provenancePath := range paths {
+		envBytes, err := gitinterface.ReadBlob(repo, a.provenanceAttestations[provenancePath])
+		if err != nil {
+			return nil, err
+		}
+
+		env := &sslibdsse.Envelope{}
+		if err := json.Unmarshal(envBytes, env); err != nil {
+			return nil, err
+		}
+
+		if err := validateProvenanceAttestation(repo, env, objectID); err != nil {
+			return nil, err
+		}
+
+		envelopes = append(envelopes, env)
+	}
+
+	return envelopes, nil
+}
+
+// ProvenanceAttestationPath constructs the expected path on-disk for the SLSA
+// provenance attestation for the object. The payload digest distinguishes
+// multiple attestations for the same object.
+func ProvenanceAttestationPath(objectID, payloadDigest string) string {
+	return path.Join(objectID, payloadDigest)
+}
+
+func validateProvenanceAttestation(repo *git.Repository, env *sslibdsse.Envelope, objectID string) error {
+	statement, err := decodeProvenanceStatement(env)
+	if err != nil {
+		return err
+	}
+
+	if _, err := provenanceBuilderID(statement); err != nil {
+		return err
+	}
+
+	objectIDs := []string{objectID}
+	if tag, err := gitinterface.GetTag(repo, plumbing.NewHash(objectID)); err == nil {
+		objectIDs = append(objectIDs, tag.Target.String())
+	}
+
+	for _, referencedID := range provenanceReferencedIDs(statement) {
+		if slices.Contains(objectIDs, referencedID) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w '%s'", ErrProvenanceNotForObject, objectID)
+}
+
+// decodeProvenanceStatement decodes the in-toto statement in the envelope. As
+// the statement is produced by external tools, it is decoded using the
+// protobuf JSON mapping that in-toto specifies, rather than gittuf's encoding.
+func decodeProvenanceStatement(env *sslibdsse.Envelope) (*ita.Statement, error) {
+	payloadBytes, err := env.DecodeB64Payload()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProvenanceAttestation, err)
+	}
+
+	statement := &ita.Statement{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(payloadBytes, statement); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProvenanceAttestation, err)
+	}
+
+	switch statement.PredicateType {
+	case SLSAProvenanceV1PredicateType, SLSAProvenanceV02PredicateType:
+	default:
+		return nil, fmt.Errorf("%w: unexpected predicate type '%s'", ErrInvalidProvenanceAttestation, statement.PredicateType)
+	}
+
+	if statement.Predicate == nil {
+		return nil, fmt.Errorf("%w: missing predicate", ErrInvalidProvenanceAttestation)
+	}
+
+	return statement, nil
+}
+
+// provenanceBuilderID returns the builder ID recorded in the statement, which
+// is at `runDetails.builder.id` for SLSA v1 and `builder.id` for SLSA v0.2.
+func provenanceBuilderID(statement *ita.Statement) (string, error) {
+	predicate := statement.Predicate.AsMap()
+
+	builder := predicate["builder"]
+	if statement.PredicateType == SLSAProvenanceV1PredicateType {
+		runDetails, _ := predicate["runDetails"].(map[string]any)
+		builder = runDetails["builder"]
+	}
+
+	builderMap, _ := builder.(map[string]any)
+	builderID, _ := builderMap["id"].(string)
+	if builderID == "" {
+		return "", fmt.Errorf("%w: missing builder ID", ErrInvalidProvenanceAttestation)
+	}
+
+	return builderID, nil
+}
+
+// provenanceReferencedIDs returns the Git object IDs in the statement's
+// subjects and source materials, which are at
+// `buildDefinition.resolvedDependencies` for SLSA v1 and `materials` for SLSA
+// v0.2.
+func provenanceReferencedIDs(statement *ita.Statement) []string {
+	ids := []string{}
+	for _, subject := range statement.Subject {
+		for _, key := range provenanceDigestKeys {
+			if id, has := subject.Digest[key]; has {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	predicate := statement.Predicate.AsMap()
+
+	materials, _ := predicate["materials"].([]any)
+	if statement.PredicateType == SLSAProvenanceV1PredicateType {
+		buildDefinition, _ := predicate["buildDefinition"].(map[string]any)
+		materials, _ = buildDefinition["resolvedDependencies"].([]any)
+	}
+
+	for _, material := range materials {
+		materialMap, _ := material.(map[string]any)
+		digest, _ := materialMap["digest"].(map[string]any)
+		for _, key := range provenanceDigestKeys {
+			if id, ok := digest[key].(string); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+const testBuilderID = "https://github.com/gittuf/gittuf/.github/workflows/release.yml@refs/tags/v1"
+
+func TestLoadProvenanceEnvelopes(t *testing.T) {
+	env := common.CreateTestSLSAProvenanceEnvelope(t, testBuilderID, plumbing.ZeroHash)
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("single envelope", func(t *testing.T) {
+		envelopes, err := LoadProvenanceEnvelopes(envBytes)
+		assert.Nil(t, err)
+		assert.Equal(t, []*sslibdsse.Envelope{env}, envelopes)
+	})
+
+	t.Run("JSON lines", func(t *testing.T) {
+		contents := append(append(append([]byte{}, envBytes...), '\n'), envBytes...)
+		contents = append(contents, '\n')
+
+		envelopes, err := LoadProvenanceEnvelopes(contents)
+		assert.Nil(t, err)
+		assert.Equal(t, []*sslibdsse.Envelope{env, env}, envelopes)
+	})
+
+	t.Run("invalid contents", func(t *testing.T) {
+		_, err := LoadProvenanceEnvelopes([]byte("not an envelope"))
+		assert.ErrorIs(t, err, ErrInvalidProvenanceAttestation)
+
+		_, err = LoadProvenanceEnvelopes([]byte("\n"))
+		assert.ErrorIs(t, err, ErrInvalidProvenanceAttestation)
+	})
+}
+
+func TestGetProvenanceBuilderID(t *testing.T) {
+	t.Run("SLSA v1", func(t *testing.T) {
+		env := common.CreateTestSLSAProvenanceEnvelope(t, testBuilderID, plumbing.ZeroHash)
+
+		builderID, err := GetProvenanceBuilderID(env)
+		assert.Nil(t, err)
+		assert.Equal(t, testBuilderID, builderID)
+	})
+
+	t.Run("SLSA v0.2", func(t *testing.T) {
+		env := createTestProvenanceEnvelope(t, map[string]any{
+			"_type":         "https://in-toto.io/Statement/v0.1",
+			"predicateType": SLSAProvenanceV02PredicateType,
+			"subject":       []any{},
+			"predicate": map[string]any{
+				"builder":   map[string]any{"id": "https://gitlab.com/gittuf/gittuf/-/runners/1"},
+				"materials": []any{map[string]any{"digest": map[string]any{"sha1": plumbing.ZeroHash.String()}}},
+			},
+		})
+
+		builderID, err := GetProvenanceBuilderID(env)
+		assert.Nil(t, err)
+		assert.Equal(t, "https://gitlab.com/gittuf/gittuf/-/runners/1", builderID)
+	})
+
+	t.Run("not provenance", func(t *testing.T) {
+		env := createTestProvenanceEnvelope(t, map[string]any{
+			"_type":         "https://in-toto.io/Statement/v1",
+			"predicateType": "https://spdx.dev/Document",
+			"subject":       []any{},
+			"predicate":     map[string]any{},
+		})
+
+		_, err := GetProvenanceBuilderID(env)
+		assert.ErrorIs(t, err, ErrInvalidProvenanceAttestation)
+	})
+
+	t.Run("no builder", func(t *testing.T) {
+		env := createTestProvenanceEnvelope(t, map[string]any{
+			"_type":         "https://in-toto.io/Statement/v1",
+			"predicateType": SLSAProvenanceV1PredicateType,
+			"subject":       []any{},
+			"predicate":     map[string]any{"runDetails": map[string]any{}},
+		})
+
+		_, err := GetProvenanceBuilderID(env)
+		assert.ErrorIs(t, err, ErrInvalidProvenanceAttestation)
+	})
+}
+
+func TestProvenanceAttestation(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, "refs/heads/main", 2, artifacts.GPGKey1Private)
+	tagID := common.CreateTestSignedTag(t, repo, "v1", commitIDs[1], artifacts.GPGKey1Private)
+
+	env := common.CreateTestSLSAProvenanceEnvelope(t, testBuilderID, commitIDs[1])
+
+	attestations := &Attestations{}
+
+	// The provenance doesn't reference the first commit
+	err = attestations.SetProvenanceAttestation(repo, env, commitIDs[0].String())
+	assert.ErrorIs(t, err, ErrProvenanceNotForObject)
+
+	// The provenance references the commit the tag points to
+	err = attestations.SetProvenanceAttestation(repo, env, tagID.String())
+	assert.Nil(t, err)
+
+	err = attestations.SetProvenanceAttestation(repo, env, commitIDs[1].String())
+	assert.Nil(t, err)
+
+	_, err = attestations.GetProvenanceAttestationsFor(repo, commitIDs[0].String())
+	assert.ErrorIs(t, err, ErrProvenanceAttestationNotFound)
+
+	storedEnvs, err := attestations.GetProvenanceAttestationsFor(repo, tagID.String())
+	assert.Nil(t, err)
+	assert.Equal(t, []*sslibdsse.Envelope{env}, storedEnvs)
+
+	// Multiple attestations may be tracked for an object
+	otherEnv := common.CreateTestSLSAProvenanceEnvelope(t, "https://gitlab.com/gittuf/gittuf/-/runners/1", commitIDs[1])
+	err = attestations.SetProvenanceAttestation(repo, otherEnv, tagID.String())
+	assert.Nil(t, err)
+
+	storedEnvs, err = attestations.GetProvenanceAttestationsFor(repo, tagID.String())
+	assert.Nil(t, err)
+	assert.Len(t, storedEnvs, 2)
+
+	if err := attestations.Commit(repo, "Test commit", false); err != nil {
+		t.Fatal(err)
+	}
+
+	loadedAttestations, err := LoadCurrentAttestations(repo)
+	assert.Nil(t, err)
+	assert.Equal(t, attestations.provenanceAttestations, loadedAttestations.provenanceAttestations)
+}
+
+func createTestProvenanceEnvelope(t *testing.T, statement map[string]any) *sslibdsse.Envelope {
+	t.Helper()
+
+	statementBytes, err := json.Marshal(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &sslibdsse.Envelope{
+		PayloadType: "application/vnd.in-toto+json",
+		Payload:     base64.StdEncoding.EncodeToString(statementBytes),
+		Signatures:  []sslibdsse.Signature{},
+	}
+}
@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	GitHubReleasePredicateType = "https://gittuf.dev/github-release/v0.1"
+	digestGitTagKey            = "gitTag"
+	digestSHA256Key            = "sha256"
+	releaseTagRefKey           = "tagRef"
+	releaseRSLEntryIDKey       = "rslEntryID"
+)
+
+var (
+	ErrInvalidGitHubReleaseAttestation  = errors.New("GitHub release attestation does not match expected details")
+	ErrGitHubReleaseAttestationNotFound = errors.New("requested GitHub release attestation not found")
+)
+
+// GitHubReleaseAsset records the name and SHA-256 digest of a single asset
+// published with a GitHub release.
+type GitHubReleaseAsset struct {
+	Name   string
+	SHA256 string
+}
+
+// GitHubRelease is a lightweight record of a GitHub release that binds the
+// release to the verified RSL entry of its tag. It is meant to be used as a
+// "predicate" in an in-toto attestation. The release's assets are recorded as
+// the attestation's subjects.
+type GitHubRelease struct {
+	Owner      string `json:"owner"`
+	Repository string `json:"repository"`
+	ReleaseID  int64  `json:"releaseID"`
+	TagRef     string `json:"tagRef"`
+	RSLEntryID string `json:"rslEntryID"`
+	TargetID   string `json:"targetID"`
+}
+
+// NewGitHubReleaseAttestation creates a new GitHub release attestation for the
+// tag recorded in the specified RSL entry. Each asset is recorded as a subject
+// of the in-toto statement, identified by its name and SHA-256 digest. The tag
+// itself is recorded as an additional subject identified by the release URL.
+func NewGitHubReleaseAttestation(owner, repository string, releaseID int64, tagRef, rslEntryID, targetID string, assets []*GitHubReleaseAsset) (*ita.Statement, error) {
+	predicate := &GitHubRelease{
+		Owner:      owner,
+		Repository: repository,
+		ReleaseID:  releaseID,
+		TagRef:     tagRef,
+		RSLEntryID: rslEntryID,
+		TargetID:   targetID,
+	}
+
+	predicateBytes, err := json.Marshal(predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	predicateInterface := &map[string]any{}
+	if err := json.Unmarshal(predicateBytes, predicateInterface); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(*predicateInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	subjects := []*ita.ResourceDescriptor{
+		{
+			Uri:    fmt.Sprintf("https://github.com/%s/%s/releases/tag/%s", owner, repository, plumbing.ReferenceName(tagRef).Short()),
+			Digest: map[string]string{digestGitTagKey: targetID},
+		},
+	}
+	for _, asset := range assets {
+		subjects = append(subjects, &ita.ResourceDescriptor{
+			Name:   asset.Name,
+			Digest: map[string]string{digestSHA256Key: asset.SHA256},
+		})
+	}
+
+	return &ita.Statement{
+		Type:          ita.StatementTypeUri,
+		Subject:       subjects,
+		PredicateType: GitHubReleasePredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// GetGitHubReleaseAssets returns the assets recorded in a GitHub release
+// attestation.
+func GetGitHubReleaseAssets(env *sslibdsse.Envelope) ([]*GitHubReleaseAsset, error) {
+	statement, err := dsse.DecodePayload[ita.Statement](payloadCache, env, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	assets := []*GitHubReleaseAsset{}
+	for _, subject := range statement.Subject {
+		if subject.Name == "" {
+			// This is the subject for the tag
+			continue
+		}
+
+		assets = append(assets, &GitHubReleaseAsset{Name: subject.Name, SHA256: subject.Digest[digestSHA256Key]})
+	}
+
+	return assets, nil
+}
+
+// SetGitHubReleaseAttestation writes the new GitHub release attestation to the
+// object store and tracks it in the current attestations state.
+func (a *Attestations) SetGitHubReleaseAttestation(repo *git.Repository, env *sslibdsse.Envelope, tagRef, rslEntryID string) error {
+	if err := validateGitHubReleaseAttestation(env, tagRef, rslEntryID); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := gitinterface.WriteBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.githubReleaseAttestations == nil {
+		a.githubReleaseAttestations = map[string]plumbing.Hash{}
+	}
+
+	a.githubReleaseAttestations[GitHubReleaseAttestationPath(tagRef, rslEntryID)] = blobID
+	return nil
+}
+
+// GetGitHubReleaseAttestationFor returns the requested GitHub release
+// attestation (with its signatures).
+func (a *Attestations) GetGitHubReleaseAttestationFor(repo *git.Repository, tagRef, rslEntryID string) (*sslibdsse.Envelope, error) {
+	blobID, has := a.githubReleaseAttestations[GitHubReleaseAttestationPath(tagRef, rslEntryID)]
+	if !has {
+		return nil, ErrGitHubReleaseAttestationNotFound
+	}
+
+	envBytes, err := gitinterface.ReadBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	if err := validateGitHubReleaseAttestation(env, tagRef, rslEntryID); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// GitHubReleaseAttestationPath constructs the expected path on-disk for the
+// GitHub release attestation.
+func GitHubReleaseAttestationPath(tagRef, rslEntryID string) string {
+	return path.Join(tagRef, rslEntryID)
+}
+
+func validateGitHubReleaseAttestation(env *sslibdsse.Envelope, tagRef, rslEntryID string) error {
+	statement, err := dsse.DecodePayload[ita.Statement](payloadCache, env, nil)
+	if err != nil {
+		return err
+	}
+
+	if statement.PredicateType != GitHubReleasePredicateType || statement.Predicate == nil {
+		return ErrInvalidGitHubReleaseAttestation
+	}
+
+	predicate := statement.Predicate.AsMap()
+
+	if predicate[releaseTagRefKey] != tagRef {
+		return ErrInvalidGitHubReleaseAttestation
+	}
+
+	if predicate[releaseRSLEntryIDKey] != rslEntryID {
+		return ErrInvalidGitHubReleaseAttestation
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGitHubReleaseAttestation(t *testing.T) {
+	testRef := "refs/tags/v1"
+	testID := plumbing.ZeroHash.String()
+	assets := []*GitHubReleaseAsset{{Name: "gittuf_linux_amd64", SHA256: "abcd"}}
+
+	release, err := NewGitHubReleaseAttestation("gittuf", "gittuf", 1, testRef, testID, testID, assets)
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, release.Type)
+	assert.Equal(t, GitHubReleasePredicateType, release.PredicateType)
+
+	// The tag is the first subject, followed by the assets
+	assert.Equal(t, 2, len(release.Subject))
+	assert.Equal(t, "https://github.com/gittuf/gittuf/releases/tag/v1", release.Subject[0].Uri)
+	assert.Equal(t, testID, release.Subject[0].Digest[digestGitTagKey])
+	assert.Equal(t, "gittuf_linux_amd64", release.Subject[1].Name)
+	assert.Equal(t, "abcd", release.Subject[1].Digest[digestSHA256Key])
+
+	predicate := release.Predicate.AsMap()
+	assert.Equal(t, testRef, predicate[releaseTagRefKey])
+	assert.Equal(t, testID, predicate[releaseRSLEntryIDKey])
+}
+
+func TestGitHubReleaseAttestation(t *testing.T) {
+	testRef := "refs/tags/v1"
+	testID := plumbing.ZeroHash.String()
+	assets := []*GitHubReleaseAsset{
+		{Name: "gittuf_linux_amd64", SHA256: "abcd"},
+		{Name: "gittuf_darwin_arm64", SHA256: "efgh"},
+	}
+
+	release, err := NewGitHubReleaseAttestation("gittuf", "gittuf", 1, testRef, testID, testID, assets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(release)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	err = attestations.SetGitHubReleaseAttestation(repo, env, "refs/tags/v2", testID)
+	assert.ErrorIs(t, err, ErrInvalidGitHubReleaseAttestation)
+
+	err = attestations.SetGitHubReleaseAttestation(repo, env, testRef, testID)
+	assert.Nil(t, err)
+	assert.Contains(t, attestations.githubReleaseAttestations, GitHubReleaseAttestationPath(testRef, testID))
+
+	_, err = attestations.GetGitHubReleaseAttestationFor(repo, "refs/tags/v2", testID)
+	assert.ErrorIs(t, err, ErrGitHubReleaseAttestationNotFound)
+
+	storedEnv, err := attestations.GetGitHubReleaseAttestationFor(repo, testRef, testID)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+
+	storedAssets, err := GetGitHubReleaseAssets(storedEnv)
+	assert.Nil(t, err)
+	assert.Equal(t, assets, storedAssets)
+}
@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	VerificationSummaryPredicateType = "https://gittuf.dev/verification-summary/v0.1"
+	VerificationResultPassed         = "PASSED"
+	VerificationResultFailed         = "FAILED"
+	verificationRefKey               = "ref"
+	verificationRSLEntryIDKey        = "rslEntryID"
+)
+
+var (
+	ErrInvalidVerificationSummary  = errors.New("verification summary does not match expected details")
+	ErrVerificationSummaryNotFound = errors.New("requested verification summary not found")
+)
+
+// VerificationSummaryVerifier identifies the observer that verified a ref.
+type VerificationSummaryVerifier struct {
+	ID string `json:"id"`
+}
+
+// VerificationSummaryPolicy identifies the policy state a ref was verified
+// against.
+type VerificationSummaryPolicy struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest"`
+}
+
+// VerificationSummary records the result of verifying a ref at a specific RSL
+// entry against the gittuf policy. It is modeled after the SLSA verification
+// summary attestation and is meant to be used as a "predicate" in an in-toto
+// attestation signed by an observer.
+type VerificationSummary struct {
+	Verifier           VerificationSummaryVerifier `json:"verifier"`
+	TimeVerified       string                      `json:"timeVerified"`
+	ResourceURI        string                      `json:"resourceUri"`
+	Policy             VerificationSummaryPolicy   `json:"policy"`
+	VerificationResult string                      `json:"verificationResult"`
+	Ref                string                      `json:"ref"`
+	RSLEntryID         string                      `json:"rslEntryID"`
+}
+
+// NewVerificationSummaryAttestation creates a new verification summary for the
+// ref at the specified RSL entry. The ref's target is recorded as the subject
+// of the in-toto statement.
+func NewVerificationSummaryAttestation(summary *VerificationSummary, targetID string) (*ita.Statement, error) {
+	predicateBytes, err := json.Marshal(summary)
+	if err != nil {
+		return nil, err
+	}
+
+	predicateInterface := &map[string]any{}
+	if err := json.Unmarshal(predicateBytes, predicateInterface); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(*predicateInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Name:   summary.Ref,
+				Digest: map[string]string{digestGitCommitKey: targetID},
+			},
+		},
+		PredicateType: VerificationSummaryPredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// GetVerificationSummary returns the summary recorded in a verification
+// summary attestation.
+func GetVerificationSummary(env *sslibdsse.Envelope) (*VerificationSummary, error) {
+	statement, err := dsse.DecodePayload[ita.Statement](payloadCache, env, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if statement.PredicateType != VerificationSummaryPredicateType || statement.Predicate == nil {
+		return nil, ErrInvalidVerificationSummary
+	}
+
+	predicateBytes, err := statement.Predicate.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &VerificationSummary{}
+	if err := json.Unmarshal(predicateBytes, summary); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// SetVerificationSummary writes the new verification summary to the object
+// store and tracks it in the current attestations state.
+func (a *Attestations) SetVerificationSummary(repo *git.Repository, env *sslibdsse.Envelope, refName, rslEntryID string) error {
+	if err := validateVerificationSummary(env, refName, rslEntryID); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := gitinterface.WriteBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.verificationSummaries == nil {
+		a.verificationSummaries = map[string]plumbing.Hash{}
+	}
+
+	a.verificationSummaries[VerificationSummaryPath(refName, rslEntryID)] = blobID
+	return nil
+}
+
+// GetVerificationSummaryFor returns the requested verification summary (with
+// its signatures).
+func (a *Attestations) GetVerificationSummaryFor(repo *git.Repository, refName, rslEntryID string) (*sslibdsse.Envelope, error) {
+	blobID, has := a.verificationSummaries[VerificationSummaryPath(refName, rslEntryID)]
+	if !has {
+		return nil, ErrVerificationSummaryNotFound
+	}
+
+	envBytes, err := gitinterface.ReadBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	if err := validateVerificationSummary(env, refName, rslEntryID); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// VerificationSummaryPath constructs the expected path on-disk for the
+// verification summary.
+func VerificationSummaryPath(refName, rslEntryID string) string {
+	return path.Join(refName, rslEntryID)
+}
+
+func validateVerificationSummary(env *sslibdsse.Envelope, refName, rslEntryID string) error {
+	statement, err := dsse.DecodePayload[ita.Statement](payloadCache, env, nil)
+	if err != nil {
+		return err
+	}
+
+	if statement.PredicateType != VerificationSummaryPredicateType || statement.Predicate == nil {
+		return ErrInvalidVerificationSummary
+	}
+
+	predicate := statement.Predicate.AsMap()
+
+	if predicate[verificationRefKey] != refName {
+		return ErrInvalidVerificationSummary
+	}
+
+	if predicate[verificationRSLEntryIDKey] != rslEntryID {
+		return ErrInvalidVerificationSummary
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVerificationSummaryAttestation(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := plumbing.ZeroHash.String()
+	summary := &VerificationSummary{
+		Verifier:           VerificationSummaryVerifier{ID: "observer"},
+		TimeVerified:       "2024-01-01T00:00:00Z",
+		ResourceURI:        testRef + "@" + testID,
+		Policy:             VerificationSummaryPolicy{URI: "refs/gittuf/policy", Digest: map[string]string{digestGitCommitKey: testID}},
+		VerificationResult: VerificationResultPassed,
+		Ref:                testRef,
+		RSLEntryID:         testID,
+	}
+
+	statement, err := NewVerificationSummaryAttestation(summary, testID)
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, statement.Type)
+	assert.Equal(t, VerificationSummaryPredicateType, statement.PredicateType)
+	assert.Equal(t, 1, len(statement.Subject))
+	assert.Equal(t, testRef, statement.Subject[0].Name)
+	assert.Equal(t, testID, statement.Subject[0].Digest[digestGitCommitKey])
+
+	predicate := statement.Predicate.AsMap()
+	assert.Equal(t, testRef, predicate[verificationRefKey])
+	assert.Equal(t, testID, predicate[verificationRSLEntryIDKey])
+	assert.Equal(t, VerificationResultPassed, predicate["verificationResult"])
+}
+
+func TestVerificationSummary(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := plumbing.ZeroHash.String()
+	summary := &VerificationSummary{
+		Verifier:           VerificationSummaryVerifier{ID: "observer"},
+		TimeVerified:       "2024-01-01T00:00:00Z",
+		ResourceURI:        testRef + "@" + testID,
+		Policy:             VerificationSummaryPolicy{URI: "refs/gittuf/policy", Digest: map[string]string{digestGitCommitKey: testID}},
+		VerificationResult: VerificationResultFailed,
+		Ref:                testRef,
+		RSLEntryID:         testID,
+	}
+
+	statement, err := NewVerificationSummaryAttestation(summary, testID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	err = attestations.SetVerificationSummary(repo, env, "refs/heads/feature", testID)
+	assert.ErrorIs(t, err, ErrInvalidVerificationSummary)
+
+	err = attestations.SetVerificationSummary(repo, env, testRef, testID)
+	assert.Nil(t, err)
+	assert.Contains(t, attestations.verificationSummaries, VerificationSummaryPath(testRef, testID))
+
+	_, err = attestations.GetVerificationSummaryFor(repo, "refs/heads/feature", testID)
+	assert.ErrorIs(t, err, ErrVerificationSummaryNotFound)
+
+	storedEnv, err := attestations.GetVerificationSummaryFor(repo, testRef, testID)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+
+	storedSummary, err := GetVerificationSummary(storedEnv)
+	assert.Nil(t, err)
+	assert.Equal(t, summary, storedSummary)
+
+	if err := attestations.Commit(repo, "Test commit", false); err != nil {
+		t.Fatal(err)
+	}
+
+	loadedAttestations, err := LoadCurrentAttestations(repo)
+	assert.Nil(t, err)
+	assert.Equal(t, attestations.verificationSummaries, loadedAttestations.verificationSummaries)
+}
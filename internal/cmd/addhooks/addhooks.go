@@ -6,12 +6,17 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/gittuf/gittuf/internal/hooks"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
 type options struct {
-	force bool
+	force        bool
+	server       bool
+	checkout     bool
+	refTx        bool
+	enforcedRefs []string
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -22,6 +27,34 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		false,
 		"overwrite hooks, if they already exist",
 	)
+
+	cmd.Flags().BoolVar(
+		&o.server,
+		"server",
+		false,
+		"add server-side pre-receive hook that verifies pushes instead of client-side pre-push hook",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.checkout,
+		"checkout",
+		false,
+		"also add client-side post-checkout hook that restores the previous checkout if a checked out branch is not covered by verified RSL entries",
+	)
+	cmd.Flags().BoolVar(
+		&o.refTx,
+		"reference-transaction",
+		false,
+		"add server-side reference-transaction hook that verifies every ref update, including those not made by pushes, instead of client-side pre-push hook",
+	)
+	cmd.MarkFlagsMutuallyExclusive("server", "checkout", "reference-transaction")
+
+	cmd.Flags().StringArrayVar(
+		&o.enforcedRefs,
+		"enforce-ref",
+		[]string{},
+		fmt.Sprintf("pattern of refs to enforce gittuf for (default %v)", hooks.DefaultEnforcedRefs),
+	)
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
@@ -30,17 +63,52 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	err = repo.UpdateHook(repository.HookPrePush, prePushScript, o.force)
-	var hookErr *repository.ErrHookExists
-	if errors.As(err, &hookErr) {
-		fmt.Fprintf(
-			cmd.ErrOrStderr(),
-			"'%s' already exists. Use --force flag or merge existing hook and the following script manually:\n\n%s\n",
-			string(hookErr.HookType),
-			prePushScript,
+	hookOptions := &hooks.Options{EnforcedRefs: o.enforcedRefs}
+
+	hookTypes := []repository.HookType{repository.HookPrePush}
+	if o.server {
+		hookTypes = []repository.HookType{repository.HookPreReceive}
+	} else if o.refTx {
+		hookTypes = []repository.HookType{repository.HookReferenceTransaction}
+	} else if o.checkout {
+		hookTypes = append(hookTypes, repository.HookPostCheckout)
+	}
+
+	for _, hookType := range hookTypes {
+		var (
+			script []byte
+			err    error
 		)
+		switch hookType {
+		case repository.HookPreReceive:
+			script, err = hooks.GeneratePreReceiveScript(hookOptions)
+		case repository.HookReferenceTransaction:
+			script, err = hooks.GenerateReferenceTransactionScript(hookOptions)
+		case repository.HookPostCheckout:
+			script, err = hooks.GeneratePostCheckoutScript(hookOptions)
+		default:
+			script, err = hooks.GeneratePrePushScript(hookOptions)
+		}
+		if err != nil {
+			return err
+		}
+
+		err = repo.UpdateHook(hookType, script, o.force)
+		var hookErr *repository.ErrHookExists
+		if errors.As(err, &hookErr) {
+			fmt.Fprintf(
+				cmd.ErrOrStderr(),
+				"'%s' already exists. Use --force flag or merge existing hook and the following script manually:\n\n%s\n",
+				string(hookErr.HookType),
+				script,
+			)
+		}
+		if err != nil {
+			return err
+		}
 	}
-	return err
+
+	return nil
 }
 
 func New() *cobra.Command {
@@ -48,6 +116,7 @@ func New() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "add-hooks",
 		Short:             "Add git hooks that automatically create and sync RSL",
+		Long:              "The 'add-hooks' command adds a pre-push hook that records pushed refs in the RSL, verifies them, and syncs the RSL with the remote. With --checkout, it also adds a post-checkout hook that verifies checked out branches are covered by verified RSL entries, and restores the previous checkout otherwise. With --server, it instead adds a pre-receive hook to a repository hosted on a Git server, which rejects pushes that fail gittuf verification. With --reference-transaction, it instead adds a reference-transaction hook to a repository hosted on a Git server, which aborts any ref update that fails gittuf verification, whether or not it is made by a push.",
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
 	}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"io"
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/attest/persistent"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p *persistent.Options
+}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	var input io.Reader = cmd.InOrStdin()
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close() //nolint:errcheck
+		input = file
+	}
+
+	requests, err := repository.ParseAttestationRequests(input)
+	if err != nil {
+		return err
+	}
+
+	var defaultSigner sslibdsse.SignerVerifier
+	if o.p.SigningKey != "" {
+		defaultSigner, err = loadSigner(o.p.SigningKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	return repo.ApplyAttestationRequests(cmd.Context(), requests, defaultSigner, loadSigner, true)
+}
+
+func loadSigner(signingKey string) (sslibdsse.SignerVerifier, error) {
+	return common.LoadSignerFromKeyRef(signingKey)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "apply <file>",
+		Short: "Create attestations described in JSON",
+		Long: `This command creates and signs the attestations described in the JSON file, or in standard input if the file is "-". The input is a single attestation request or an array of requests, which are committed together. Each request has the following fields:
+
+  type:      the attestation type, "reference-authorization" or "github-pull-request-approval", or its predicate type URI
+  subject:   optional, the target tree ID for reference authorizations or the commit ID for pull request approvals, which must match the predicate
+  predicate: the attestation's predicate, such as {"targetRef": "refs/heads/main", "fromRevisionID": "...", "targetTreeID": "..."} for reference authorizations
+  signer:    optional, the path to the signing key, defaults to the key set using --signing-key
+
+If an attestation with the same details exists, the signature is added to it.`,
+		Args:              cobra.ExactArgs(1),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
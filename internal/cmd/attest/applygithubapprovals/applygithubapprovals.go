@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package applygithubapprovals
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/cmd/attest/persistent"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p               *persistent.Options
+	repository      string
+	identityMapping string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.repository,
+		"repository",
+		"",
+		"GitHub repository the pull request was merged in, of form {owner}/{repo}, defaults to GITHUB_REPOSITORY if set",
+	)
+
+	cmd.Flags().StringVar(
+		&o.identityMapping,
+		"identity-mapping",
+		"",
+		"path to JSON file mapping GitHub usernames to the IDs of their keys in the policy",
+	)
+	cmd.MarkFlagRequired("identity-mapping") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if o.repository == "" {
+		o.repository = os.Getenv("GITHUB_REPOSITORY")
+	}
+	repositoryParts := strings.Split(o.repository, "/")
+	if len(repositoryParts) != 2 {
+		return fmt.Errorf("invalid format for repository, must be {owner}/{repo}")
+	}
+
+	mappingFile, err := os.Open(o.identityMapping)
+	if err != nil {
+		return err
+	}
+	defer mappingFile.Close() //nolint:errcheck
+
+	mapping, err := repository.ParseGitHubIdentityMapping(mappingFile)
+	if err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	approvals, err := repo.ApplyGitHubPullRequestApprovals(cmd.Context(), signer, repositoryParts[0], repositoryParts[1], args[0], mapping, true)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Pull request %d merged into '%s' at '%s'\n", approvals.PullRequestNumber, approvals.BaseBranch, approvals.CommitID)
+	if len(approvals.Approvers) == 0 {
+		fmt.Println("No approvals by mapped users to record")
+	} else {
+		fmt.Println("Recorded approvals by keys:")
+		for _, keyID := range approvals.Approvers {
+			fmt.Printf("    %s\n", keyID)
+		}
+	}
+	if len(approvals.UnmappedReviewers) != 0 {
+		fmt.Println("Skipped approvals by unmapped users:")
+		for _, reviewer := range approvals.UnmappedReviewers {
+			fmt.Printf("    %s\n", reviewer)
+		}
+	}
+
+	return nil
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "apply-github-approvals <merge-commit>",
+		Short: "Record the approvals of a merged GitHub pull request",
+		Long: `This command is intended to run in CI after a pull request is merged. It identifies the GitHub pull request merged using the specified merge commit, and records the approvals of the pull request's head at the time of merging in a GitHub pull request approval attestation signed by the user's key, such as the CI system's key. Approvals of earlier commits and dismissed approvals are not recorded.
+
+Reviewers are mapped to their keys in the policy using the identity mapping, a JSON object such as {"alice": "<key ID>"}, and the attestation records the key IDs, so that approvals can be counted towards the thresholds of the policy's rules. Approvals by users who are not in the mapping are skipped. The authentication token for the GitHub API is read from the GITHUB_TOKEN environment variable.`,
+		Args:              cobra.ExactArgs(1),
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attest
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/attest/apply"
+	"github.com/gittuf/gittuf/internal/cmd/attest/applygithubapprovals"
+	"github.com/gittuf/gittuf/internal/cmd/attest/authorize"
+	"github.com/gittuf/gittuf/internal/cmd/attest/buildenvironment"
+	"github.com/gittuf/gittuf/internal/cmd/attest/changeset"
+	"github.com/gittuf/gittuf/internal/cmd/attest/custom"
+	"github.com/gittuf/gittuf/internal/cmd/attest/delegateautomation"
+	"github.com/gittuf/gittuf/internal/cmd/attest/gc"
+	"github.com/gittuf/gittuf/internal/cmd/attest/persistent"
+	"github.com/gittuf/gittuf/internal/cmd/attest/provenance"
+	"github.com/gittuf/gittuf/internal/cmd/attest/pull"
+	"github.com/gittuf/gittuf/internal/cmd/attest/push"
+	"github.com/gittuf/gittuf/internal/cmd/attest/revoke"
+	"github.com/gittuf/gittuf/internal/cmd/attest/revokeautomation"
+	"github.com/gittuf/gittuf/internal/cmd/attest/verificationsummary"
+	"github.com/gittuf/gittuf/internal/cmd/attest/verify"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	o := &persistent.Options{}
+	cmd := &cobra.Command{
+		Use:               "attest",
+		Short:             "Tools for attesting to repository changes",
+		DisableAutoGenTag: true,
+	}
+	o.AddPersistentFlags(cmd)
+
+	cmd.AddCommand(apply.New(o))
+	cmd.AddCommand(applygithubapprovals.New(o))
+	cmd.AddCommand(authorize.New(o))
+	cmd.AddCommand(buildenvironment.New(o))
+	cmd.AddCommand(changeset.New(o))
+	cmd.AddCommand(custom.New(o))
+	cmd.AddCommand(delegateautomation.New(o))
+	cmd.AddCommand(gc.New())
+	cmd.AddCommand(provenance.New())
+	cmd.AddCommand(pull.New())
+	cmd.AddCommand(push.New())
+	cmd.AddCommand(revoke.New(o))
+	cmd.AddCommand(revokeautomation.New())
+	cmd.AddCommand(verificationsummary.New(o))
+	cmd.AddCommand(verify.New())
+
+	return cmd
+}
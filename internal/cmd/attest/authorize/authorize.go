@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package authorize
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/attest/persistent"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p *persistent.Options
+}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.AddReferenceAuthorizationForIDs(cmd.Context(), signer, args[0], args[1], args[2], true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "authorize <ref> <from> <to-tree>",
+		Short: "Authorize a change to a ref",
+		Long: `This command adds the user's signature to a reference authorization, which authorizes updating the ref from the commit <from> to a commit with the tree <to-tree>. If the authorization doesn't exist, it is created. Otherwise, the signature is added to the existing authorization, allowing multiple developers to co-sign it.
+
+Authorizations are recorded in the attestations namespace and can be shared using "gittuf attest push" so that other developers can add their signatures.`,
+		Args:              cobra.ExactArgs(3),
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package buildenvironment
+
+import (
+	"os"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/cmd/attest/persistent"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p             *persistent.Options
+	platform      string
+	hostname      string
+	runnerID      string
+	oidcTokenPath string
+	encryptFor    []string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.platform,
+		"platform",
+		"",
+		"automation platform the entry was created on, detected for GitHub Actions and GitLab CI if unset",
+	)
+
+	cmd.Flags().StringVar(
+		&o.hostname,
+		"hostname",
+		"",
+		"hostname of the build environment, defaults to the current host's name",
+	)
+
+	cmd.Flags().StringVar(
+		&o.runnerID,
+		"runner-ID",
+		"",
+		"identifier of the runner on the automation platform, detected for GitHub Actions and GitLab CI if unset",
+	)
+
+	cmd.Flags().StringVar(
+		&o.oidcTokenPath,
+		"oidc-token",
+		"",
+		"path to OIDC token issued to the build environment, whose claims are recorded",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&o.encryptFor,
+		"encrypt-for",
+		[]string{},
+		"ID of a key in the policy to encrypt the build environment for",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	environment := &attestations.BuildEnvironment{
+		Platform: o.platform,
+		Hostname: o.hostname,
+		RunnerID: o.runnerID,
+	}
+
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		if environment.Platform == "" {
+			environment.Platform = "github-actions"
+		}
+		if environment.RunnerID == "" {
+			environment.RunnerID = os.Getenv("RUNNER_NAME")
+		}
+	case os.Getenv("GITLAB_CI") == "true":
+		if environment.Platform == "" {
+			environment.Platform = "gitlab-ci"
+		}
+		if environment.RunnerID == "" {
+			environment.RunnerID = os.Getenv("CI_RUNNER_ID")
+		}
+	}
+
+	if environment.Hostname == "" {
+		environment.Hostname, err = os.Hostname()
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.oidcTokenPath != "" {
+		token, err := os.ReadFile(o.oidcTokenPath)
+		if err != nil {
+			return err
+		}
+
+		environment.OIDCClaims, err = repository.DecodeOIDCTokenClaims(string(token))
+		if err != nil {
+			return err
+		}
+	}
+
+	return repo.AddBuildEnvironmentAttestation(cmd.Context(), signer, args[0], environment, o.encryptFor, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "build-environment <ref>",
+		Short: "Record the build environment that created a ref's latest RSL entry",
+		Long: `This command records the build environment, such as a CI runner, that created the latest RSL entry for the ref in an attestation signed by the user's key. The key must be trusted for the ref in the policy. The attestation records the platform, the hostname, and the runner's identifier, allowing auditors to distinguish changes pushed by automation from those pushed by humans.
+
+If an OIDC token issued to the build environment is specified, its claims are recorded as well. The token's signature is not verified, the claims are vouched for by the signing key.
+
+The build environment can be encrypted for one or more keys in the policy using --encrypt-for, so that only the holders of those keys can read it. Encryption uses age, and ED25519 and RSA keys, including SSH keys, are supported. The attestation is encrypted before it is signed, so its signature can be verified without decrypting it.`,
+		Args:              cobra.ExactArgs(1),
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
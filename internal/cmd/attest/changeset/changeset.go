@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package changeset
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/attest/persistent"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	repository string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.repository,
+		"repository",
+		"",
+		"identifier of this repository in the change set",
+	)
+	cmd.MarkFlagRequired("repository") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	changeSet, err := repository.LoadChangeSet(args[0])
+	if err != nil {
+		return err
+	}
+
+	return repo.AddChangeSetAttestation(cmd.Context(), signer, changeSet, o.repository, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "change-set <path>",
+		Short: "Authorize a set of changes spanning multiple repositories",
+		Long: `This command adds the user's signature to an attestation authorizing a coordinated set of changes across multiple repositories, such as for a release that must update several repositories together. The set is read from the JSON file at <path>, which specifies the set's "id" and its "changes", each with the "repository", "targetRef", "fromRevisionID", and "targetTreeID" of the change.
+
+The attestation is recorded for the changes to the repository identified by --repository, and authorizes them like a reference authorization, with the signatures counting towards the thresholds of the rules protecting the changed refs. If the attestation already exists, the signature is added to it, allowing multiple developers to co-sign the set. The command must be run in each repository in the set, so that each repository's gittuf verifies its changes against the same set.`,
+		Args:              cobra.ExactArgs(1),
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package custom
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/attest/persistent"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p             *persistent.Options
+	predicateType string
+	encryptFor    []string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.predicateType,
+		"predicate-type",
+		"",
+		"type URI of the predicate",
+	)
+	cmd.MarkFlagRequired("predicate-type") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.encryptFor,
+		"encrypt-for",
+		[]string{},
+		"ID of a key in the policy to encrypt the predicate for",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	contents, err := os.ReadFile(args[1])
+	if err != nil {
+		return err
+	}
+
+	predicate := map[string]any{}
+	if err := json.Unmarshal(contents, &predicate); err != nil {
+		return err
+	}
+
+	return repo.AddCustomAttestation(cmd.Context(), signer, args[0], o.predicateType, predicate, o.encryptFor, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "custom <ref> <path>",
+		Short: "Record an attestation with a custom predicate for a ref's latest RSL entry",
+		Long: `This command records an attestation with a custom predicate, such as internal audit notes, for the latest RSL entry of the ref, signed by the user's key. The key must be trusted for the ref in the policy. The file at <path> must contain the predicate as a JSON object, and its type must be specified using --predicate-type. Predicate types defined by gittuf can't be used.
+
+The predicate can be encrypted for one or more keys in the policy using --encrypt-for, so that sensitive evidence can be stored in the repository while remaining readable only by the holders of those keys. Encryption uses age, and ED25519 and RSA keys, including SSH keys, are supported. The attestation is encrypted before it is signed, so its signature can be verified without decrypting it.`,
+		Args:              cobra.ExactArgs(2),
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
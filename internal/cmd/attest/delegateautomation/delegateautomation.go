@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package delegateautomation
+
+import (
+	"time"
+
+	"github.com/gittuf/gittuf/internal/cmd/attest/persistent"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p             *persistent.Options
+	automationKey string
+	refs          []string
+	validFor      time.Duration
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.automationKey,
+		"automation-key",
+		"",
+		"automation key to grant permission to",
+	)
+	cmd.MarkFlagRequired("automation-key") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.refs,
+		"ref",
+		[]string{},
+		"pattern of the absolute refs the automation key may record entries for, such as refs/heads/main (can be repeated)",
+	)
+	cmd.MarkFlagRequired("ref") //nolint:errcheck
+
+	cmd.Flags().DurationVar(
+		&o.validFor,
+		"valid-for",
+		time.Hour,
+		"how long the delegation is valid for, at most 24h",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	automationKey, err := common.LoadPublicKey(o.automationKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.DelegateToAutomation(cmd.Context(), signer, automationKey, o.refs, o.validFor, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "delegate-automation",
+		Short: "Grant an automation key short-lived permission to record entries for specific refs",
+		Long: `This command records a delegation, signed using the user's key, that grants an automation key, such as the key of a CI job, permission to record RSL entries for the specified refs from now until the delegation expires. During verification, the automation key's signatures on RSL entries for those refs count as the user's signatures for the rules that trust the user's key. Delegations are valid for at most 24 hours, limiting how long a leaked automation key can be used, and can be revoked earlier using "gittuf attest revoke-automation". As the automation key controls the timestamps of the entries it signs, its entries remain valid after the delegation expires only if they are followed by an RSL entry signed using a key in the policy before the delegation expires.
+
+Note that the automation key can be specified from disk, from the GPG keyring using the "gpg:<fingerprint>" format, or as a Sigstore identity as "fulcio:<identity>::<issuer>".`,
+		Args:              cobra.NoArgs,
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gc
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	keepEntries int
+	dryRun      bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(
+		&o.keepEntries,
+		"keep-entries",
+		1,
+		"number of latest RSL entries of each ref whose attestations are retained",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.dryRun,
+		"dry-run",
+		false,
+		"report superseded and expired attestations without removing them",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	removed, err := repo.CompactAttestations(o.keepEntries, o.dryRun, true)
+	if err != nil {
+		return err
+	}
+
+	action := "removed"
+	if o.dryRun {
+		action = "can be removed"
+	}
+
+	for _, attestationPath := range removed {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s (%s)\n", attestationPath, action)
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "gc",
+		Short:             "Remove superseded and expired attestations",
+		Long:              "The 'attest gc' command compacts the attestations namespace by removing attestations that no longer apply to the repository's refs. Reference authorizations and change sets are removed once the ref has moved on from the revision they authorize a change from, attestations for specific RSL entries once the entries are no longer among the latest entries of the ref, and GitHub pull request approvals once the ref is deleted. The attestations for the latest RSL entries of each ref, set using --keep-entries, and for the next update of each ref are retained. The compacted attestations are committed and the compaction is recorded in the RSL. As the history of the attestations namespace is not rewritten, RSL entries recorded before the compaction are still verified using the attestations that applied to them.",
+		Args:              cobra.NoArgs,
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package persistent
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type Options struct {
+	SigningKey string
+}
+
+func (o *Options) AddPersistentFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(
+		&o.SigningKey,
+		"signing-key",
+		"k",
+		"",
+		fmt.Sprintf("signing key to use to sign attestations (path to a key file, or piv:slot-<slot> for a key on a hardware token), defaults to %s", repository.SigningKeyConfigKey),
+	)
+}
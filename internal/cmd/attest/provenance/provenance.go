@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package provenance
+
+import (
+	"os"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) Run(_ *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	contents, err := os.ReadFile(args[1])
+	if err != nil {
+		return err
+	}
+
+	envelopes, err := attestations.LoadProvenanceEnvelopes(contents)
+	if err != nil {
+		return err
+	}
+
+	return repo.AddProvenanceAttestations(args[0], envelopes, true)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "provenance <target> <path>",
+		Short: "Attach SLSA provenance produced by an external builder to a commit or tag",
+		Long: `This command attaches SLSA provenance produced by an external builder, such as GitHub Actions or GitLab CI, to the commit or tag identified by <target> in the attestations namespace. The target may be a ref or the ID of a commit or tag object. The file at <path> must contain the DSSE envelope of an in-toto statement with a SLSA provenance v1 or v0.2 predicate, or multiple such envelopes in the JSON Lines format of ".intoto.jsonl" files. Each statement must reference the commit or tag, or the commit the tag points to, in its subjects or source materials.
+
+Rules may require tags to have provenance from specific builders using "gittuf policy set-allowed-builders". Such provenance must be signed by one of the rule's keys, which is checked when the tag is verified.`,
+		Args:              cobra.ExactArgs(2),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pull
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	remote, err := common.RemoteFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	return repo.PullAttestations(cmd.Context(), remote)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "pull [remote]",
+		Short:             "Pull attestations from the specified remote",
+		Args:              cobra.MaximumNArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
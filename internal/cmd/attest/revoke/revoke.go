@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package revoke
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/attest/persistent"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p *persistent.Options
+}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.RemoveReferenceAuthorization(cmd.Context(), signer, args[0], args[1], args[2], true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "revoke <ref> <from> <to-tree>",
+		Short:             "Revoke an authorization for a change to a ref",
+		Long:              `This command removes the user's signature from a reference authorization. Signatures from other developers are retained, and the authorization is removed once it has no signatures.`,
+		Args:              cobra.ExactArgs(3),
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
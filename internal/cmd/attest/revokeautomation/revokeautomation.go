@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package revokeautomation
+
+import (
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	return repo.RevokeAutomationDelegations(cmd.Context(), strings.ToLower(args[0]), true)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "revoke-automation <automation-key-ID>",
+		Short:             "Revoke the delegations to an automation key",
+		Long:              `This command removes every delegation to the automation key, so that RSL entries it records from now on are not trusted.`,
+		Args:              cobra.ExactArgs(1),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verificationsummary
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/attest/persistent"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p *persistent.Options
+}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.AddVerificationSummary(cmd.Context(), signer, args[0], true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "verification-summary <ref>",
+		Short: "Record a signed verification summary for a ref",
+		Long: `This command verifies the ref against the gittuf policy and records the result in a verification summary signed by the user's key. The key must be trusted as an observer in the root of trust, which allows automation accounts to report verification results without being trusted for changes to the RSL or the policy.
+
+The summary is recorded for the ref's latest RSL entry even if verification fails, in which case the command exits with the verification error.`,
+		Args:              cobra.ExactArgs(1),
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
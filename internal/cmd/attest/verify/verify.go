@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	var input io.Reader = cmd.InOrStdin()
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close() //nolint:errcheck
+		input = file
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.NewDecoder(input).Decode(env); err != nil {
+		return fmt.Errorf("unable to parse DSSE envelope: %w", err)
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	verification, err := repo.VerifyAttestationEnvelope(cmd.Context(), env)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Signed by:")
+	for _, key := range verification.Signers {
+		line := fmt.Sprintf("    %s (%s", key.KeyID, key.KeyType)
+		if key.KeyVal.Identity != "" && !strings.Contains(key.KeyID, key.KeyVal.Identity) {
+			line += fmt.Sprintf(", identity %s", key.KeyVal.Identity)
+		}
+		fmt.Println(line + ")")
+	}
+
+	statement := verification.Statement
+	if statement == nil {
+		fmt.Printf("Payload type: %s\n", env.PayloadType)
+		return nil
+	}
+
+	fmt.Printf("Predicate type: %s\n", statement.GetPredicateType())
+
+	fmt.Println("Subjects:")
+	for _, subject := range statement.GetSubject() {
+		digests := []string{}
+		for algorithm, digest := range subject.GetDigest() {
+			digests = append(digests, fmt.Sprintf("%s:%s", algorithm, digest))
+		}
+		slices.Sort(digests)
+		fmt.Printf("    %s (%s)\n", subject.GetName(), strings.Join(digests, ", "))
+	}
+
+	predicate := statement.GetPredicate().AsMap()
+	fields := make([]string, 0, len(predicate))
+	for field := range predicate {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	fmt.Println("Predicate:")
+	for _, field := range fields {
+		switch value := predicate[field].(type) {
+		case string, bool, float64, nil:
+			fmt.Printf("    %s: %v\n", field, value)
+		default:
+			valueBytes, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("    %s: %s\n", field, valueBytes)
+		}
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "verify <file>",
+		Short: "Verify an attestation envelope against the keys trusted in policy",
+		Long: `This command verifies the signatures on a DSSE envelope, read from the file or from standard input if the file is "-", against the keys trusted in the repository's current policy. This is useful to check attestations exchanged out of band before they're recorded in the repository. The keys that signed the envelope are printed along with a summary of the attested statement.
+
+The envelope must be signed by at least one key trusted in policy. Whether the signers are authorized for what the envelope attests to is only checked when the attestation is used during verification.`,
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package channel
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/channel/list"
+	"github.com/gittuf/gittuf/internal/cmd/channel/promote"
+	"github.com/gittuf/gittuf/internal/cmd/channel/verify"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "channel",
+		Short:             "Tools to manage release channels that point at verified tags",
+		Long:              "The channel command manages release channels, such as stable and beta, that point at verified tags. Each channel is recorded in a ref under refs/gittuf/channels and in the RSL, so promotions are governed by the policy's rules for the channel's ref, for example git:refs/gittuf/channels/stable.",
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(list.New())
+	cmd.AddCommand(promote.New())
+	cmd.AddCommand(verify.New())
+
+	return cmd
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package promote
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	from string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.from,
+		"from",
+		"",
+		"channel the tag is promoted from, which must currently point at the tag",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	return repo.PromoteToChannel(cmd.Context(), args[0], args[1], o.from, true)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "promote <channel> <tag>",
+		Short:             "Point a release channel at a verified tag",
+		Long:              "The promote command points the specified release channel at a tag. The tag must pass verification, and if --from is set, the tag must be the current tag of that channel. The promotion is recorded in the RSL, and must be signed by the keys the policy trusts for the channel's ref to pass verification.",
+		Args:              cobra.ExactArgs(2),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verify
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	channel, err := repo.VerifyChannel(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Channel '%s' points at '%s' (%s)\n", channel.Name, channel.Tag, channel.TagID)
+	if channel.PromotedFrom != "" {
+		fmt.Printf("    Promoted from: %s\n", channel.PromotedFrom)
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "verify <channel>",
+		Short:             "Verify a release channel and the tag it points at",
+		Long:              "The verify command verifies the latest promotion of the specified release channel against the policy, and verifies the tag the channel points at. The tag must still be at the object recorded when it was promoted.",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
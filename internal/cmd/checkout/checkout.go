@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package checkout
+
+import (
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	verifyOnly bool
+	force      bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&o.verifyOnly,
+		"verify-only",
+		false,
+		"verify the ref is covered by verified RSL entries without checking it out",
+	)
+
+	cmd.Flags().BoolVarP(
+		&o.force,
+		"force",
+		"f",
+		false,
+		"discard changes in the working tree when checking out the ref",
+	)
+	cmd.MarkFlagsMutuallyExclusive("verify-only", "force")
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	if o.verifyOnly {
+		return repo.VerifyCheckout(cmd.Context(), args[0])
+	}
+
+	return repo.SafeCheckout(cmd.Context(), args[0], o.force)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "checkout <ref>",
+		Short:             "Check out a ref only if its state is covered by verified RSL entries",
+		Long:              "The 'checkout' command updates the working tree to the specified ref, refusing to do so if the ref's state is not the state recorded in its latest RSL entry or that entry fails verification. This protects against building branches fetched from a compromised remote. Remote-tracking refs such as refs/remotes/origin/main are verified against the RSL entries for the corresponding branch, so the RSL must be pulled using 'gittuf rsl remote pull' after fetching. Branches are checked out as the current branch, while other refs are checked out with a detached HEAD. To verify checkouts made using Git directly, add the post-checkout hook using 'gittuf add-hooks --checkout'.",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -4,6 +4,7 @@ package clone
 
 import (
 	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/progress"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/spf13/cobra"
@@ -12,6 +13,7 @@ import (
 type options struct {
 	branch           string
 	expectedRootKeys common.PublicKeys
+	expectedRootHash string
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -27,6 +29,12 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"root-key",
 		"set of initial root of trust keys for the repository (supported values: paths to SSH keys, GPG key fingerprints, Sigstore/Fulcio identities)",
 	)
+	cmd.Flags().StringVar(
+		&o.expectedRootHash,
+		"root-hash",
+		"",
+		"hash of the repository's initial root of trust metadata, obtained out of band, to pin for the repository",
+	)
 }
 
 func (o *options) Run(cmd *cobra.Command, args []string) error {
@@ -46,16 +54,20 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 		expectedRootKeys[index] = key
 	}
 
-	_, err := repository.Clone(cmd.Context(), args[0], dir, o.branch, expectedRootKeys)
+	finish := progress.Start(progress.OperationClone)
+	_, err := repository.Clone(cmd.Context(), args[0], dir, o.branch, expectedRootKeys, o.expectedRootHash)
+	finish(err)
+
 	return err
 }
 
 func New() *cobra.Command {
 	o := &options{}
 	cmd := &cobra.Command{
-		Use:               "clone",
+		Use:               "clone <url> [dir]",
 		Short:             "Clone repository and its gittuf references",
-		Args:              cobra.MinimumNArgs(1),
+		Long:              "The 'clone' command clones the repository along with its gittuf references, and verifies the RSL and policy from the root of trust for the checked out branch. The working tree is only checked out if verification succeeds. The expected root keys or the hash of the initial root of trust metadata, as shown by 'gittuf trust show-root-hash', can be specified to bootstrap trust in the repository's root of trust. A root hash is pinned for the repository, so that subsequent verifications also check the root of trust against it.",
+		Args:              cobra.RangeArgs(1, 2),
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
 	}
@@ -3,14 +3,19 @@
 package common
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
 
+	"github.com/gittuf/gittuf/internal/cmd/exitcodes"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/piv"
+	"github.com/gittuf/gittuf/internal/signerverifier/x509"
 	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
 	"github.com/gittuf/gittuf/internal/tuf"
 	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
@@ -18,8 +23,10 @@ import (
 )
 
 const (
-	GPGKeyPrefix = "gpg:"
-	FulcioPrefix = "fulcio:"
+	GPGKeyPrefix  = "gpg:"
+	FulcioPrefix  = "fulcio:"
+	X509KeyPrefix = "x509:"
+	PIVKeyPrefix  = piv.KeyPrefix
 )
 
 // PublicKeys is a custom type to represent a list of paths
@@ -41,8 +48,8 @@ func (p *PublicKeys) Type() string {
 	return "public-keys"
 }
 
-// LoadPublicKey returns a tuf.Key object for a PGP / Sigstore Fulcio / SSH
-// (on-disk) key for use in gittuf metadata.
+// LoadPublicKey returns a tuf.Key object for a PGP / Sigstore Fulcio / X.509
+// certificate / SSH (on-disk) key for use in gittuf metadata.
 func LoadPublicKey(key string) (*tuf.Key, error) {
 	var keyObj *tuf.Key
 
@@ -76,6 +83,26 @@ func LoadPublicKey(key string) (*tuf.Key, error) {
 				Issuer:   ks[1],
 			},
 		}
+	case strings.HasPrefix(key, X509KeyPrefix):
+		// x509:<certificates-path>[::<identity>]
+		certificatesPath, identity, _ := strings.Cut(strings.TrimPrefix(key, X509KeyPrefix), "::")
+
+		certificates, err := os.ReadFile(certificatesPath)
+		if err != nil {
+			return nil, err
+		}
+
+		keyObj, err = x509.NewKeyFromBytes(certificates, identity)
+		if err != nil {
+			return nil, err
+		}
+	case strings.HasPrefix(key, PIVKeyPrefix):
+		// piv:slot-<slot>
+		var err error
+		keyObj, err = piv.NewKeyFromKeyRef(key)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		kb, err := os.ReadFile(key)
 		if err != nil {
@@ -93,14 +120,49 @@ func LoadPublicKey(key string) (*tuf.Key, error) {
 
 // LoadSigner loads a signer for the specified key bytes. The key must be
 // encoded either in a standard PEM format. For now, the custom securesystemslib
-// format is also supported.
+// format is also supported. If the key is bundled with its PEM encoded X.509
+// certificate and chain, signatures are made using the certificate.
 func LoadSigner(keyBytes []byte) (sslibdsse.SignerVerifier, error) {
+	if bytes.Contains(keyBytes, []byte("-----BEGIN CERTIFICATE-----")) {
+		signer, err := x509.NewSignerFromBytes(keyBytes)
+		if err != nil {
+			return nil, exitcodes.AsSigningFailure(err)
+		}
+		return signer, nil
+	}
+
 	signer, err := sslibsv.NewSignerVerifierFromPEM(keyBytes)
 	if err == nil {
 		return signer, nil
 	}
 
-	return signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(keyBytes) //nolint:staticcheck
+	signer, err = signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(keyBytes) //nolint:staticcheck
+	if err != nil {
+		return nil, exitcodes.AsSigningFailure(err)
+	}
+
+	return signer, nil
+}
+
+// LoadSignerFromKeyRef loads a signer for the signing key specified using a
+// flag such as "signing-key". The key is either a key stored in a PIV slot of a
+// hardware token, in the form "piv:slot-<slot>", or the path to a key file
+// loaded using LoadSigner.
+func LoadSignerFromKeyRef(keyRef string) (sslibdsse.SignerVerifier, error) {
+	if strings.HasPrefix(keyRef, PIVKeyPrefix) {
+		signer, err := piv.NewSignerFromKeyRef(keyRef)
+		if err != nil {
+			return nil, exitcodes.AsSigningFailure(err)
+		}
+		return signer, nil
+	}
+
+	keyBytes, err := os.ReadFile(keyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadSigner(keyBytes)
 }
 
 // CheckIfSigningViableWithFlag checks if a signing key was specified via the
@@ -110,7 +172,7 @@ func CheckIfSigningViableWithFlag(cmd *cobra.Command, _ []string) error {
 
 	// Check if a signing key was specified via the "signing-key" flag
 	if signingKeyFlag.Value.String() == "" {
-		return fmt.Errorf("required flag \"signing-key\" not set")
+		return exitcodes.AsSigningFailure(fmt.Errorf("required flag \"signing-key\" not set"))
 	}
 
 	return CheckIfSigningViable(cmd, []string{""})
@@ -121,5 +183,47 @@ func CheckIfSigningViableWithFlag(cmd *cobra.Command, _ []string) error {
 func CheckIfSigningViable(_ *cobra.Command, _ []string) error {
 	_, _, err := gitinterface.GetSigningCommand()
 
-	return err
+	return exitcodes.AsSigningFailure(err)
+}
+
+// ApplyConfigDefaults sets the flags of the command that are not specified to
+// the defaults in the repository's gittuf configuration.
+func ApplyConfigDefaults(cmd *cobra.Command, config *repository.Config) error {
+	defaults := map[string]string{
+		"signing-key":   config.SigningKey,
+		EnforcementFlag: config.VerificationEnforcement,
+	}
+
+	for name, value := range defaults {
+		if value == "" {
+			continue
+		}
+
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoteFromArgs returns the remote specified as the first argument, or the
+// RSL remote set in the repository's gittuf configuration if no arguments are
+// specified.
+func RemoteFromArgs(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	config, err := repository.LoadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return config.RemoteOrDefault("")
 }
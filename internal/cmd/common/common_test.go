@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/repository"
 	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -28,3 +30,43 @@ func TestLoadSigner(t *testing.T) {
 		assert.Nil(t, err)
 	}
 }
+
+func TestApplyConfigDefaults(t *testing.T) {
+	newCommand := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("signing-key", "", "")
+		return cmd
+	}
+
+	t.Run("flag not set", func(t *testing.T) {
+		cmd := newCommand()
+		err := ApplyConfigDefaults(cmd, &repository.Config{SigningKey: "config-key"})
+		assert.Nil(t, err)
+		assert.Equal(t, "config-key", cmd.Flags().Lookup("signing-key").Value.String())
+	})
+
+	t.Run("flag set", func(t *testing.T) {
+		cmd := newCommand()
+		if err := cmd.Flags().Set("signing-key", "flag-key"); err != nil {
+			t.Fatal(err)
+		}
+		err := ApplyConfigDefaults(cmd, &repository.Config{SigningKey: "config-key"})
+		assert.Nil(t, err)
+		assert.Equal(t, "flag-key", cmd.Flags().Lookup("signing-key").Value.String())
+	})
+
+	t.Run("command without flag", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		err := ApplyConfigDefaults(cmd, &repository.Config{SigningKey: "config-key"})
+		assert.Nil(t, err)
+	})
+
+	t.Run("enforcement flag not set", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		var enforcement string
+		AddEnforcementFlag(cmd, &enforcement)
+		err := ApplyConfigDefaults(cmd, &repository.Config{VerificationEnforcement: repository.VerificationEnforcementAudit})
+		assert.Nil(t, err)
+		assert.Equal(t, repository.VerificationEnforcementAudit, enforcement)
+	})
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/gittuf/gittuf/internal/cmd/exitcodes"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+// EnforcementFlag is the name of the flag that selects how verification
+// failures are handled.
+const EnforcementFlag = "enforcement"
+
+// AddEnforcementFlag adds the flag that selects how verification failures are
+// handled to the command. The flag defaults to the repository's
+// gittuf.verify.enforcement setting.
+func AddEnforcementFlag(cmd *cobra.Command, enforcement *string) {
+	cmd.Flags().StringVar(
+		enforcement,
+		EnforcementFlag,
+		repository.VerificationEnforcementEnforce,
+		fmt.Sprintf("how verification failures are handled: %s fails, %s exits with a distinct exit code (%d), %s only logs them (defaults to %s if set)", repository.VerificationEnforcementEnforce, repository.VerificationEnforcementWarn, exitcodes.VerificationWarning, repository.VerificationEnforcementAudit, repository.VerificationEnforcementConfigKey),
+	)
+}
+
+// IsVerificationFailure returns true if err indicates that verification found
+// the gittuf policy wasn't met or the RSL doesn't match the repository, rather
+// than that verification could not be performed.
+func IsVerificationFailure(err error) bool {
+	code := exitcodes.FromError(err)
+	return code == exitcodes.PolicyViolation || code == exitcodes.RSLDivergence
+}
+
+// ApplyEnforcement returns the error for a verification command that failed
+// with err, based on the enforcement. Errors that aren't verification failures
+// are returned as is.
+func ApplyEnforcement(enforcement string, err error) error {
+	if err == nil || !IsVerificationFailure(err) {
+		return err
+	}
+
+	switch enforcement {
+	case repository.VerificationEnforcementAudit:
+		slog.Warn(fmt.Sprintf("Verification failed (audit mode, not enforced): %s", err.Error()))
+		return nil
+	case repository.VerificationEnforcementWarn:
+		return exitcodes.AsVerificationWarning(fmt.Errorf("verification failed (warn mode): %w", err))
+	default:
+		return err
+	}
+}
+
+// EnforceRefUpdateDecisions reports the ref updates denied by a Git server's
+// hook to w, and returns true if the updates must be rejected based on the
+// enforcement. Denied updates are only reported as warnings when the
+// enforcement is warn, and only logged when it is audit.
+func EnforceRefUpdateDecisions(w io.Writer, enforcement string, decisions []*repository.RefUpdateDecision) bool {
+	rejected := false
+	for _, decision := range decisions {
+		if decision.Allowed {
+			continue
+		}
+
+		switch enforcement {
+		case repository.VerificationEnforcementAudit:
+			slog.Warn(fmt.Sprintf("Update to '%s' failed verification (audit mode, not enforced): %s", decision.Name, decision.Reason))
+		case repository.VerificationEnforcementWarn:
+			fmt.Fprintf(w, "Warning: update to '%s' failed verification (warn mode, not enforced): %s\n", decision.Name, decision.Reason)
+		default:
+			rejected = true
+			fmt.Fprintf(w, "Rejecting update to '%s': %s\n", decision.Name, decision.Reason)
+		}
+	}
+
+	return rejected
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/cmd/exitcodes"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyEnforcement(t *testing.T) {
+	violation := fmt.Errorf("verification failed: %w", policy.ErrUnauthorizedSignature)
+	other := errors.New("unable to load repository")
+
+	tests := map[string]struct {
+		enforcement  string
+		err          error
+		expectedCode int
+	}{
+		"enforce, violation": {
+			enforcement:  repository.VerificationEnforcementEnforce,
+			err:          violation,
+			expectedCode: exitcodes.PolicyViolation,
+		},
+		"warn, violation": {
+			enforcement:  repository.VerificationEnforcementWarn,
+			err:          violation,
+			expectedCode: exitcodes.VerificationWarning,
+		},
+		"warn, RSL divergence": {
+			enforcement:  repository.VerificationEnforcementWarn,
+			err:          repository.ErrRefStateDoesNotMatchRSL,
+			expectedCode: exitcodes.VerificationWarning,
+		},
+		"audit, violation": {
+			enforcement:  repository.VerificationEnforcementAudit,
+			err:          violation,
+			expectedCode: exitcodes.OK,
+		},
+		"audit, other error": {
+			enforcement:  repository.VerificationEnforcementAudit,
+			err:          other,
+			expectedCode: exitcodes.Failure,
+		},
+		"warn, no error": {
+			enforcement:  repository.VerificationEnforcementWarn,
+			expectedCode: exitcodes.OK,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ApplyEnforcement(test.enforcement, test.err)
+			assert.Equal(t, test.expectedCode, exitcodes.FromError(err))
+			if err != nil {
+				assert.ErrorIs(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestEnforceRefUpdateDecisions(t *testing.T) {
+	decisions := []*repository.RefUpdateDecision{
+		{Name: "refs/heads/main", Allowed: true},
+		{Name: "refs/heads/feature", Allowed: false, Reason: policy.ErrUnauthorizedSignature},
+	}
+
+	t.Run("enforce", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		rejected := EnforceRefUpdateDecisions(output, repository.VerificationEnforcementEnforce, decisions)
+		assert.True(t, rejected)
+		assert.Contains(t, output.String(), "Rejecting update to 'refs/heads/feature'")
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		rejected := EnforceRefUpdateDecisions(output, repository.VerificationEnforcementWarn, decisions)
+		assert.False(t, rejected)
+		assert.Contains(t, output.String(), "Warning: update to 'refs/heads/feature' failed verification")
+	})
+
+	t.Run("audit", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		rejected := EnforceRefUpdateDecisions(output, repository.VerificationEnforcementAudit, decisions)
+		assert.False(t, rejected)
+		assert.Empty(t, output.String())
+	})
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/policy"
+)
+
+// WritePolicyDiff writes the differences between two policy states in a human
+// readable format, indenting each line by the specified level.
+func WritePolicyDiff(w io.Writer, diff *policy.StateDiff, level int) error {
+	lines := []string{}
+	indent := strings.Repeat("    ", level)
+	nestedIndent := strings.Repeat("    ", level+1)
+
+	if diff.RootMetadataChanged && diff.RootExpires == nil && len(diff.UpdatedRoles) == 0 {
+		lines = append(lines, indent+"Root metadata modified")
+	}
+	if diff.RootExpires != nil {
+		lines = append(lines, indent+describeExpiryChange("Root metadata", diff.RootExpires))
+	}
+	if diff.TargetsExpires != nil {
+		lines = append(lines, indent+describeExpiryChange("Targets metadata", diff.TargetsExpires))
+	}
+
+	for _, roleDiff := range diff.UpdatedRoles {
+		if roleDiff.Threshold != nil {
+			lines = append(lines, indent+describeThresholdChange(fmt.Sprintf("Role %s", roleDiff.Name), roleDiff.Threshold))
+		}
+		for _, keyID := range roleDiff.AddedKeys {
+			lines = append(lines, fmt.Sprintf(indent+"Key added to role %s: %s", roleDiff.Name, keyID))
+		}
+		for _, keyID := range roleDiff.RemovedKeys {
+			lines = append(lines, fmt.Sprintf(indent+"Key removed from role %s: %s", roleDiff.Name, keyID))
+		}
+	}
+
+	for _, name := range diff.AddedRules {
+		lines = append(lines, fmt.Sprintf(indent+"Rule added: %s", name))
+	}
+	for _, name := range diff.RemovedRules {
+		lines = append(lines, fmt.Sprintf(indent+"Rule removed: %s", name))
+	}
+	for _, ruleDiff := range diff.UpdatedRules {
+		lines = append(lines, fmt.Sprintf(indent+"Rule modified: %s", ruleDiff.Name))
+		if ruleDiff.Threshold != nil {
+			lines = append(lines, fmt.Sprintf(nestedIndent+"Threshold changed: %d -> %d", ruleDiff.Threshold.From, ruleDiff.Threshold.To))
+		}
+		for _, keyID := range ruleDiff.AddedKeys {
+			lines = append(lines, fmt.Sprintf(nestedIndent+"Key added: %s", keyID))
+		}
+		for _, keyID := range ruleDiff.RemovedKeys {
+			lines = append(lines, fmt.Sprintf(nestedIndent+"Key removed: %s", keyID))
+		}
+		for _, path := range ruleDiff.AddedPaths {
+			lines = append(lines, fmt.Sprintf(nestedIndent+"Path added: %s", path))
+		}
+		for _, path := range ruleDiff.RemovedPaths {
+			lines = append(lines, fmt.Sprintf(nestedIndent+"Path removed: %s", path))
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func describeThresholdChange(subject string, change *policy.ThresholdChange) string {
+	switch {
+	case change.From == 0:
+		return fmt.Sprintf("%s added with threshold %d", subject, change.To)
+	case change.To == 0:
+		return fmt.Sprintf("%s removed", subject)
+	default:
+		return fmt.Sprintf("%s threshold changed: %d -> %d", subject, change.From, change.To)
+	}
+}
+
+func describeExpiryChange(subject string, change *policy.ExpiryChange) string {
+	switch {
+	case change.From == "":
+		return fmt.Sprintf("%s expires: %s", subject, change.To)
+	case change.To == "":
+		return fmt.Sprintf("%s removed", subject)
+	default:
+		return fmt.Sprintf("%s expiry changed: %s -> %s", subject, change.From, change.To)
+	}
+}
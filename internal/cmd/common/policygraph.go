@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/policy"
+)
+
+// WriteDelegationTree writes the delegation graph as an ASCII tree. Each role
+// is followed by its trusted keys and the patterns it's delegated.
+func WriteDelegationTree(w io.Writer, root *policy.GraphNode) error {
+	lines := []string{describeGraphNode(root)}
+	lines = append(lines, describeGraphNodeDetails(root, treePrefix(root, ""))...)
+	lines = append(lines, describeGraphChildren(root, "")...)
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// WriteDelegationDOT writes the delegation graph in the Graphviz DOT format,
+// with an edge from each role to each role it delegates to.
+func WriteDelegationDOT(w io.Writer, root *policy.GraphNode) error {
+	lines := []string{"digraph policy {", "\tnode [shape=box];"}
+
+	count := 0
+	var addNode func(node *policy.GraphNode) string
+	addNode = func(node *policy.GraphNode) string {
+		id := fmt.Sprintf("n%d", count)
+		count++
+
+		label := []string{describeGraphNode(node)}
+		label = append(label, describeGraphNodeDetails(node, "")...)
+		lines = append(lines, fmt.Sprintf("\t%s [label=\"%s\"];", id, escapeDOT(strings.Join(label, "\n"))))
+
+		for _, child := range node.Children {
+			childID := addNode(child)
+			lines = append(lines, fmt.Sprintf("\t%s -> %s;", id, childID))
+		}
+
+		return id
+	}
+	addNode(root)
+
+	lines = append(lines, "}")
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func describeGraphChildren(node *policy.GraphNode, prefix string) []string {
+	lines := []string{}
+	for i, child := range node.Children {
+		connector, childPrefix := "├── ", prefix+"│   "
+		if i == len(node.Children)-1 {
+			connector, childPrefix = "└── ", prefix+"    "
+		}
+
+		lines = append(lines, prefix+connector+describeGraphNode(child))
+		lines = append(lines, describeGraphNodeDetails(child, treePrefix(child, childPrefix))...)
+		lines = append(lines, describeGraphChildren(child, childPrefix)...)
+	}
+
+	return lines
+}
+
+// treePrefix returns the prefix for the details of a node, which continue the
+// tree's line to the node's children if it has any.
+func treePrefix(node *policy.GraphNode, prefix string) string {
+	if len(node.Children) > 0 {
+		return prefix + "│   "
+	}
+	return prefix + "    "
+}
+
+func describeGraphNode(node *policy.GraphNode) string {
+	description := fmt.Sprintf("%s (threshold %d)", node.Name, node.Threshold)
+	if node.Terminating {
+		description += " [terminating]"
+	}
+	if node.Repeated {
+		description += " [delegations listed above]"
+	}
+
+	return description
+}
+
+func describeGraphNodeDetails(node *policy.GraphNode, prefix string) []string {
+	lines := []string{}
+	if len(node.KeyIDs) > 0 {
+		lines = append(lines, prefix+"keys: "+strings.Join(node.KeyIDs, ", "))
+	}
+	if len(node.Patterns) > 0 {
+		lines = append(lines, prefix+"patterns: "+strings.Join(node.Patterns, ", "))
+	}
+
+	return lines
+}
+
+func escapeDOT(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
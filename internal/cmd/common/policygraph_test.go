@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteDelegationGraph(t *testing.T) {
+	graph := &policy.GraphNode{
+		Name:      "root",
+		KeyIDs:    []string{"root-key"},
+		Threshold: 1,
+		Children: []*policy.GraphNode{
+			{
+				Name:      "targets",
+				KeyIDs:    []string{"targets-key"},
+				Threshold: 1,
+				Children: []*policy.GraphNode{
+					{
+						Name:        "protect-main",
+						KeyIDs:      []string{"alice", "bob"},
+						Threshold:   2,
+						Patterns:    []string{"git:refs/heads/main"},
+						Terminating: true,
+					},
+					{
+						Name:      "protect-docs",
+						KeyIDs:    []string{"carol"},
+						Threshold: 1,
+						Patterns:  []string{"file:docs/*"},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("tree", func(t *testing.T) {
+		expected := `root (threshold 1)
+│   keys: root-key
+└── targets (threshold 1)
+    │   keys: targets-key
+    ├── protect-main (threshold 2) [terminating]
+    │       keys: alice, bob
+    │       patterns: git:refs/heads/main
+    └── protect-docs (threshold 1)
+            keys: carol
+            patterns: file:docs/*
+`
+
+		output := &bytes.Buffer{}
+		err := WriteDelegationTree(output, graph)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, output.String())
+	})
+
+	t.Run("dot", func(t *testing.T) {
+		expected := `digraph policy {
+	node [shape=box];
+	n0 [label="root (threshold 1)\nkeys: root-key"];
+	n1 [label="targets (threshold 1)\nkeys: targets-key"];
+	n2 [label="protect-main (threshold 2) [terminating]\nkeys: alice, bob\npatterns: git:refs/heads/main"];
+	n1 -> n2;
+	n3 [label="protect-docs (threshold 1)\nkeys: carol\npatterns: file:docs/*"];
+	n1 -> n3;
+	n0 -> n1;
+}
+`
+
+		output := &bytes.Buffer{}
+		err := WriteDelegationDOT(output, graph)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, output.String())
+	})
+}
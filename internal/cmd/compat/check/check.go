@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package check
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+// maxUsesShown is the number of uses listed for each feature.
+const maxUsesShown = 3
+
+type options struct {
+	clientVersion string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.clientVersion,
+		"client-version",
+		"",
+		"gittuf version to check, such as v0.4.0",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	report, err := repo.CheckCompatibility(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	var incompatible []*repository.CompatibilityFeature
+	if o.clientVersion != "" {
+		incompatible, err = report.IncompatibleFeatures(o.clientVersion)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(report.Features) == 0 {
+		fmt.Println("The repository uses no features that need a specific gittuf version")
+	} else {
+		fmt.Printf("Minimum gittuf version: %s\n", report.MinVersion)
+		for _, feature := range report.Features {
+			fmt.Printf("\n%s (%s)\n", feature.Name, feature.MinVersion)
+			fmt.Printf("    %s\n", feature.Effect)
+
+			uses := feature.Uses
+			if len(uses) > maxUsesShown {
+				uses = append(uses[:maxUsesShown:maxUsesShown], fmt.Sprintf("and %d more", len(feature.Uses)-maxUsesShown))
+			}
+			fmt.Printf("    Used by: %s\n", strings.Join(uses, ", "))
+		}
+	}
+
+	if o.clientVersion == "" {
+		return nil
+	}
+
+	if len(incompatible) == 0 {
+		fmt.Printf("\ngittuf %s can verify the repository\n", o.clientVersion)
+		return nil
+	}
+
+	names := make([]string, 0, len(incompatible))
+	for _, feature := range incompatible {
+		names = append(names, feature.Name)
+	}
+	return fmt.Errorf("%w: %s does not support %s", repository.ErrIncompatibleClientVersion, o.clientVersion, strings.Join(names, ", "))
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Report which gittuf versions can verify the repository",
+		Long: `This command inspects the repository's current policy, RSL entries, attestations, and identities for features that older versions of gittuf cannot verify. For each feature in use, it reports the first gittuf version that supports it, how older versions handle it, and where it's used. Older versions may fail to verify a repository that uses such features, or verify it without enforcing them.
+
+If --client-version is set, the command fails if that version cannot verify the repository, which helps coordinate upgrading clients before adopting new policy features.`,
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package compat
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/compat/check"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "compat",
+		Short:             "Tools to check which gittuf versions can verify the repository",
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(check.New())
+
+	return cmd
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package daemon
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultInterval = 2 * time.Second
+	defaultDebounce = 5 * time.Second
+)
+
+type options struct {
+	refNames []string
+	interval time.Duration
+	debounce time.Duration
+	dryRun   bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(
+		&o.refNames,
+		"ref",
+		[]string{},
+		"ref to watch and record in the RSL when it changes (can be repeated)",
+	)
+	cmd.MarkFlagRequired("ref") //nolint:errcheck
+
+	cmd.Flags().DurationVar(
+		&o.interval,
+		"interval",
+		defaultInterval,
+		"how often the refs are checked for changes",
+	)
+
+	cmd.Flags().DurationVar(
+		&o.debounce,
+		"debounce",
+		defaultDebounce,
+		"how long a ref must remain unchanged before it is recorded",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.dryRun,
+		"dry-run",
+		false,
+		"report the changes that would be recorded without recording them",
+	)
+}
+
+func (o *options) PreRunE(cmd *cobra.Command, args []string) error {
+	if o.dryRun {
+		return nil
+	}
+
+	return common.CheckIfSigningViable(cmd, args)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	daemonOptions := &repository.DaemonOptions{
+		RefNames: o.refNames,
+		Interval: o.interval,
+		Debounce: o.debounce,
+		DryRun:   o.dryRun,
+	}
+
+	slog.Info(fmt.Sprintf("Watching %d ref(s) for changes...", len(o.refNames)))
+	return repo.RunDaemon(ctx, daemonOptions, true, func(record *repository.DaemonRecord) {
+		if record.Recorded {
+			fmt.Fprintf(cmd.OutOrStdout(), "recorded %s at %s\n", record.RefName, record.TargetID.String())
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s changed to %s (not recorded)\n", record.RefName, record.TargetID.String())
+		}
+	})
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "daemon",
+		Short:             "Watch refs and record their changes in the RSL automatically",
+		Long:              "The 'daemon' command watches the specified refs by polling them, and records an RSL entry for a ref when it changes locally, so that the RSL is kept up to date for changes made without the gittuf hooks. A ref is recorded once it remains unchanged for the debounce period, so that a burst of changes such as a rebase results in a single entry. Changes already recorded in the RSL are not recorded again. The daemon runs until it is interrupted.",
+		Args:              cobra.NoArgs,
+		PreRunE:           o.PreRunE,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
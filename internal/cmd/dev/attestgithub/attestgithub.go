@@ -4,7 +4,7 @@ package attestgithub
 
 import (
 	"fmt"
-	"os"
+	"strconv"
 	"strings"
 
 	"github.com/gittuf/gittuf/internal/cmd/common"
@@ -17,6 +17,7 @@ type options struct {
 	signingKey        string
 	repository        string
 	pullRequestNumber int
+	pullRequestRange  string
 	commitID          string
 	baseBranch        string
 }
@@ -46,6 +47,13 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"pull request number to record in attestation",
 	)
 
+	cmd.Flags().StringVar(
+		&o.pullRequestRange,
+		"pull-request-range",
+		"",
+		"range of pull request numbers to backfill attestations for, of form {first}-{last}",
+	)
+
 	cmd.Flags().StringVar(
 		&o.commitID,
 		"commit",
@@ -64,7 +72,8 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 	// pull requests
 	cmd.MarkFlagsRequiredTogether("commit", "base-branch")
 
-	cmd.MarkFlagsOneRequired("pull-request-number", "commit")
+	cmd.MarkFlagsOneRequired("pull-request-number", "pull-request-range", "commit")
+	cmd.MarkFlagsMutuallyExclusive("pull-request-number", "pull-request-range", "commit")
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
@@ -78,13 +87,18 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	keyBytes, err := os.ReadFile(o.signingKey)
+	signer, err := common.LoadSignerFromKeyRef(o.signingKey)
 	if err != nil {
 		return err
 	}
-	signer, err := common.LoadSigner(keyBytes)
-	if err != nil {
-		return err
+
+	if o.pullRequestRange != "" {
+		firstNumber, lastNumber, err := parsePullRequestRange(o.pullRequestRange)
+		if err != nil {
+			return err
+		}
+
+		return repo.BackfillGitHubPullRequestAttestations(cmd.Context(), signer, repositoryParts[0], repositoryParts[1], firstNumber, lastNumber, true)
 	}
 
 	if o.commitID != "" {
@@ -94,6 +108,24 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 	return repo.AddGitHubPullRequestAttestationForNumber(cmd.Context(), signer, repositoryParts[0], repositoryParts[1], o.pullRequestNumber, true)
 }
 
+func parsePullRequestRange(pullRequestRange string) (int, int, error) {
+	first, last, found := strings.Cut(pullRequestRange, "-")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid format for pull request range, must be {first}-{last}")
+	}
+
+	firstNumber, err := strconv.Atoi(first)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid first pull request number: %w", err)
+	}
+	lastNumber, err := strconv.Atoi(last)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid last pull request number: %w", err)
+	}
+
+	return firstNumber, lastNumber, nil
+}
+
 func New() *cobra.Command {
 	o := &options{}
 	cmd := &cobra.Command{
@@ -6,7 +6,9 @@ import (
 	"fmt"
 
 	"github.com/gittuf/gittuf/internal/cmd/dev/attestgithub"
+	"github.com/gittuf/gittuf/internal/cmd/dev/attestgithubrelease"
 	"github.com/gittuf/gittuf/internal/cmd/dev/authorize"
+	"github.com/gittuf/gittuf/internal/cmd/dev/replay"
 	"github.com/gittuf/gittuf/internal/cmd/dev/rslrecordat"
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/spf13/cobra"
@@ -22,6 +24,8 @@ func New() *cobra.Command {
 
 	cmd.AddCommand(authorize.New())
 	cmd.AddCommand(attestgithub.New())
+	cmd.AddCommand(attestgithubrelease.New())
+	cmd.AddCommand(replay.New())
 	cmd.AddCommand(rslrecordat.New())
 
 	return cmd
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	dir string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.dir,
+		"dir",
+		"",
+		"empty directory to replay the trace in (a temporary directory is used if unset)",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	trace, err := repository.LoadReplayTrace(args[0])
+	if err != nil {
+		return err
+	}
+
+	keysDir, err := filepath.Abs(filepath.Dir(args[0]))
+	if err != nil {
+		return err
+	}
+
+	dir := o.dir
+	if dir == "" {
+		dir, err = os.MkdirTemp("", "gittuf-replay-")
+		if err != nil {
+			return err
+		}
+	}
+
+	_, results, err := repository.Replay(cmd.Context(), trace, dir, keysDir)
+	for _, result := range results {
+		status := "ok"
+		if result.Err != nil {
+			status = fmt.Sprintf("failed: %s", result.Err.Error())
+		}
+		if result.Mismatch {
+			status += " (does not match trace)"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%d\t%s\t%s\n", result.Index, result.Operation.Type, status)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Replayed repository available at '%s'\n", dir)
+
+	return err
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "replay",
+		Short:             fmt.Sprintf("Replay a recorded trace of operations against a fresh repository (developer mode only, set %s=1)", dev.DevModeKey),
+		Long:              "The replay command applies a recorded sequence of operations (policy changes, commits, RSL entries, annotations, pushes, and verifications) to a freshly initialized repository. Verification operations are compared against the outcome recorded in the trace, which allows reported verification discrepancies to be reproduced deterministically. Key paths in the trace are resolved relative to the trace file's directory.",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,245 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package exitcodes maps the errors returned by gittuf's commands to exit
+// codes, so that scripts and CI systems can branch on the class of failure
+// rather than parsing error messages.
+package exitcodes
+
+import (
+	"errors"
+	"net"
+	"net/url"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+const (
+	// OK is returned when the command succeeds.
+	OK = 0
+
+	// Failure is returned for errors that don't fall into a more specific
+	// class, such as invalid arguments.
+	Failure = 1
+
+	// PolicyViolation is returned when verification finds that the
+	// repository's gittuf policy was not met.
+	PolicyViolation = 2
+
+	// RSLDivergence is returned when the RSL or the refs it records don't
+	// match, such as when a ref's state isn't recorded in the RSL, or the
+	// local and remote RSLs have diverged.
+	RSLDivergence = 3
+
+	// SigningFailure is returned when the command could not sign using the
+	// specified key, or the key isn't trusted to sign.
+	SigningFailure = 4
+
+	// TransportError is returned when communicating with a remote repository
+	// or service fails.
+	TransportError = 5
+
+	// VerificationWarning is returned when verification found a policy
+	// violation or RSL divergence, but the verification enforcement is set to
+	// warn rather than fail.
+	VerificationWarning = 6
+)
+
+// Descriptions documents each exit code, in order.
+var Descriptions = []struct {
+	Code        int
+	Description string
+}{
+	{OK, "success"},
+	{Failure, "general failure, such as invalid arguments"},
+	{PolicyViolation, "the gittuf policy was not met"},
+	{RSLDivergence, "the RSL does not match the repository's refs or has diverged from the remote's RSL"},
+	{SigningFailure, "signing failed or the signing key is not trusted"},
+	{TransportError, "communicating with a remote failed"},
+	{VerificationWarning, "verification failed, but is only enforced as a warning"},
+}
+
+var (
+	policyViolationErrors = []error{
+		policy.ErrUnauthorizedSignature,
+		policy.ErrInvalidEntryNotSkipped,
+		policy.ErrLastGoodEntryIsSkipped,
+		policy.ErrTicketRequired,
+		policy.ErrChangedPathsMismatch,
+		policy.ErrSubmodulesMismatch,
+		policy.ErrVerifierConditionsUnmet,
+		policy.ErrNotAnnotatedTag,
+		policy.ErrTagNameMismatch,
+		policy.ErrTagTargetMismatch,
+		policy.ErrTagTargetNotCommit,
+		policy.ErrMultipleTagRSLEntries,
+		policy.ErrProvenanceRequired,
+		policy.ErrBuildEnvironmentNotSignedByTrustedKey,
+		policy.ErrRootPinMismatch,
+		policy.ErrInitialRootHashMismatch,
+		policy.ErrGraftsFound,
+		policy.ErrReplaceRefNotInRSL,
+		policy.ErrUnprotectedReplaceRef,
+		policy.ErrNotSignedByObserverKeys,
+		repository.ErrPolicyExpired,
+		repository.ErrSubmoduleNotVerified,
+		repository.ErrSubmodulesNotRecorded,
+		repository.ErrNetworkVerificationFailed,
+		repository.ErrNetworkRepositoryNotVerified,
+		repository.ErrNetworkReferenceNotVerified,
+		repository.ErrGitHubReleaseAssetsMismatch,
+		repository.ErrChannelTagMismatch,
+	}
+
+	rslDivergenceErrors = []error{
+		repository.ErrRefStateDoesNotMatchRSL,
+		repository.ErrCheckoutNotVerified,
+		repository.ErrRefUpdateNotInRSL,
+		repository.ErrTagRefMismatch,
+		repository.ErrRSLNotFastForward,
+		repository.ErrDivergedFromUpstream,
+		repository.ErrUpstreamRSLRewritten,
+		policy.ErrRangeNotInRSL,
+		rsl.ErrRSLBranchDetected,
+		rsl.ErrBackendDiverged,
+		gitinterface.ErrNotFastForward,
+	}
+
+	signingFailureErrors = []error{
+		gitinterface.ErrUnableToSign,
+		gitinterface.ErrSigningKeyNotSpecified,
+		gitinterface.ErrUnknownSigningMethod,
+		gpg.ErrAmbiguousSigningKey,
+		gpg.ErrKeyCannotSign,
+		gpg.ErrNoSmartcardSigningKey,
+		gpg.ErrSigningKeyNotFound,
+		gpg.ErrUnableToSignUsingGPG,
+		repository.ErrUnauthorizedKey,
+		repository.ErrSigningKeyNotTrustedForRef,
+		repository.ErrSignerNotObserver,
+	}
+
+	transportErrors = []error{
+		transport.ErrRepositoryNotFound,
+		transport.ErrEmptyRemoteRepository,
+		transport.ErrAuthenticationRequired,
+		transport.ErrAuthorizationFailed,
+		transport.ErrEmptyUploadPackRequest,
+		transport.ErrInvalidAuthMethod,
+		transport.ErrAlreadyConnected,
+		gitinterface.ErrUnknownSSHHost,
+		gitinterface.ErrSSHHostKeyMismatch,
+		repository.ErrCloningRepository,
+		repository.ErrPushingRSL,
+		repository.ErrPullingRSL,
+		repository.ErrPushingPolicy,
+		repository.ErrPullingPolicy,
+		repository.ErrPushingAttestations,
+		repository.ErrPullingAttestations,
+		rsl.ErrBackendRequestFailed,
+	}
+)
+
+// Error is an error explicitly assigned an exit code, for errors that can't be
+// classified using the error alone.
+type Error struct {
+	Code int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// AsPolicyViolation assigns the PolicyViolation exit code to err. It returns
+// nil if err is nil.
+func AsPolicyViolation(err error) error {
+	return withCode(PolicyViolation, err)
+}
+
+// AsRSLDivergence assigns the RSLDivergence exit code to err. It returns nil
+// if err is nil.
+func AsRSLDivergence(err error) error {
+	return withCode(RSLDivergence, err)
+}
+
+// AsSigningFailure assigns the SigningFailure exit code to err. It returns nil
+// if err is nil.
+func AsSigningFailure(err error) error {
+	return withCode(SigningFailure, err)
+}
+
+// AsTransportError assigns the TransportError exit code to err. It returns nil
+// if err is nil.
+func AsTransportError(err error) error {
+	return withCode(TransportError, err)
+}
+
+// AsVerificationWarning assigns the VerificationWarning exit code to err. It
+// returns nil if err is nil.
+func AsVerificationWarning(err error) error {
+	return withCode(VerificationWarning, err)
+}
+
+// FromError returns the exit code for err. Exit codes assigned using Error
+// take precedence, otherwise err is classified using the errors it wraps.
+// When err wraps errors of several classes, such as when verification records
+// multiple violations, policy violations take precedence, followed by RSL
+// divergence, signing failures, and transport errors.
+func FromError(err error) int {
+	if err == nil {
+		return OK
+	}
+
+	var codeErr *Error
+	if errors.As(err, &codeErr) {
+		return codeErr.Code
+	}
+
+	var violationsErr *policy.ErrPolicyViolations
+	if errors.As(err, &violationsErr) || isAny(err, policyViolationErrors) {
+		return PolicyViolation
+	}
+
+	if isAny(err, rslDivergenceErrors) {
+		return RSLDivergence
+	}
+
+	if isAny(err, signingFailureErrors) {
+		return SigningFailure
+	}
+
+	var netErr net.Error
+	var urlErr *url.Error
+	if isAny(err, transportErrors) || errors.As(err, &netErr) || errors.As(err, &urlErr) {
+		return TransportError
+	}
+
+	return Failure
+}
+
+func withCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{Code: code, Err: err}
+}
+
+func isAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
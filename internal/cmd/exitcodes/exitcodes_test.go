@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package exitcodes
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromError(t *testing.T) {
+	tests := map[string]struct {
+		err          error
+		expectedCode int
+	}{
+		"no error": {
+			err:          nil,
+			expectedCode: OK,
+		},
+		"unclassified error": {
+			err:          errors.New("unexpected error"),
+			expectedCode: Failure,
+		},
+		"unauthorized signature": {
+			err:          fmt.Errorf("verifying entry: %w", policy.ErrUnauthorizedSignature),
+			expectedCode: PolicyViolation,
+		},
+		"multiple policy violations": {
+			err: &policy.ErrPolicyViolations{Violations: []*policy.PolicyViolation{
+				{EntryID: plumbing.ZeroHash, Err: errors.New("violation")},
+			}},
+			expectedCode: PolicyViolation,
+		},
+		"policy violation and RSL divergence": {
+			err:          errors.Join(repository.ErrRefStateDoesNotMatchRSL, policy.ErrUnauthorizedSignature),
+			expectedCode: PolicyViolation,
+		},
+		"ref state does not match RSL": {
+			err:          fmt.Errorf("%w: tip mismatch", repository.ErrRefStateDoesNotMatchRSL),
+			expectedCode: RSLDivergence,
+		},
+		"RSL branch detected": {
+			err:          rsl.ErrRSLBranchDetected,
+			expectedCode: RSLDivergence,
+		},
+		"push not fast-forward": {
+			err:          fmt.Errorf("%w: %w", repository.ErrPushingRSL, gitinterface.ErrNotFastForward),
+			expectedCode: RSLDivergence,
+		},
+		"unable to sign": {
+			err:          gitinterface.ErrUnableToSign,
+			expectedCode: SigningFailure,
+		},
+		"unauthorized key": {
+			err:          repository.ErrUnauthorizedKey,
+			expectedCode: SigningFailure,
+		},
+		"pushing RSL": {
+			err:          fmt.Errorf("%w: connection reset", repository.ErrPushingRSL),
+			expectedCode: TransportError,
+		},
+		"authentication required": {
+			err:          transport.ErrAuthenticationRequired,
+			expectedCode: TransportError,
+		},
+		"URL error": {
+			err:          &url.Error{Op: "Get", URL: "https://example.com", Err: errors.New("connection refused")},
+			expectedCode: TransportError,
+		},
+		"explicit code": {
+			err:          AsSigningFailure(errors.New("invalid key")),
+			expectedCode: SigningFailure,
+		},
+		"explicit code takes precedence": {
+			err:          AsTransportError(policy.ErrUnauthorizedSignature),
+			expectedCode: TransportError,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expectedCode, FromError(test.err))
+		})
+	}
+}
+
+func TestWithCode(t *testing.T) {
+	t.Run("nil error", func(t *testing.T) {
+		assert.Nil(t, AsPolicyViolation(nil))
+		assert.Nil(t, AsRSLDivergence(nil))
+		assert.Nil(t, AsSigningFailure(nil))
+		assert.Nil(t, AsTransportError(nil))
+	})
+
+	t.Run("wrapped error is preserved", func(t *testing.T) {
+		err := AsRSLDivergence(rsl.ErrBackendDiverged)
+		assert.ErrorIs(t, err, rsl.ErrBackendDiverged)
+		assert.Equal(t, rsl.ErrBackendDiverged.Error(), err.Error())
+		assert.Equal(t, RSLDivergence, FromError(err))
+	})
+}
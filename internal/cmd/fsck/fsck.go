@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package fsck
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+var ErrIssuesFound = errors.New("integrity issues found in gittuf metadata")
+
+type options struct {
+	repair bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&o.repair,
+		"repair",
+		false,
+		"repair issues that can be fixed safely",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	issues, err := repo.Fsck(cmd.Context(), o.repair)
+	if err != nil {
+		return err
+	}
+
+	unrepaired := 0
+	for _, issue := range issues {
+		status := ""
+		switch {
+		case issue.Repaired:
+			status = " (repaired)"
+		case issue.Repairable:
+			status = " (can be repaired with --repair)"
+		}
+		fmt.Printf("[%s] %s: %s%s\n", issue.Check, issue.Name, issue.Problem, status)
+
+		if !issue.Repaired {
+			unrepaired++
+		}
+	}
+
+	if unrepaired > 0 {
+		return ErrIssuesFound
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "fsck",
+		Short:             "Check the integrity of the repository's gittuf metadata",
+		Long:              "Check the structural integrity of the repository's gittuf metadata. The RSL must be an unbroken chain of valid entries, every policy and attestations state recorded in the RSL must be reachable, every attestation must be parseable and match the path it is stored at, and remote tracker refs must not exist for removed remotes. Signatures are not verified, use verify-ref for that.",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	dryRun        bool
+	maxCacheSize  int
+	maxTrackerAge time.Duration
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&o.dryRun,
+		"dry-run",
+		false,
+		"report gittuf-local state exceeding the retention budgets without removing it",
+	)
+
+	cmd.Flags().IntVar(
+		&o.maxCacheSize,
+		"max-cache-size",
+		repository.DefaultGCMaxCacheSize,
+		fmt.Sprintf("maximum number of updates retained in the verification cache's history, 0 to disable, overrides %s", repository.GCMaxCacheSizeConfigKey),
+	)
+
+	cmd.Flags().DurationVar(
+		&o.maxTrackerAge,
+		"max-tracker-age",
+		repository.DefaultGCMaxTrackerAge,
+		fmt.Sprintf("remove remote tracker refs whose latest entry is older than this duration, 0 to disable, overrides %s", repository.GCMaxTrackerAgeConfigKey),
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	config, err := repository.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	gcOptions := config.GC
+	if cmd.Flags().Changed("max-cache-size") {
+		gcOptions.MaxCacheSize = o.maxCacheSize
+	}
+	if cmd.Flags().Changed("max-tracker-age") {
+		gcOptions.MaxTrackerAge = o.maxTrackerAge
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	result, err := repo.CollectGarbage(gcOptions, o.dryRun)
+	if err != nil {
+		return err
+	}
+
+	action := "removed"
+	if o.dryRun {
+		action = "can be removed"
+	}
+
+	if result.CacheCompacted {
+		fmt.Fprintf(cmd.OutOrStdout(), "verification cache with %d updates exceeds budget of %d (%s)\n", result.CacheSize, gcOptions.MaxCacheSize, action)
+		for _, refName := range result.CacheRefsRemoved {
+			fmt.Fprintf(cmd.OutOrStdout(), "cached result for deleted ref %s (%s)\n", refName, action)
+		}
+	}
+
+	for _, refName := range result.TrackersRemoved {
+		fmt.Fprintf(cmd.OutOrStdout(), "remote tracker %s older than %s (%s)\n", refName, gcOptions.MaxTrackerAge.String(), action)
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "gc",
+		Short:             "Enforce retention budgets on gittuf-local state",
+		Long:              "The 'gc' command enforces retention budgets on state gittuf keeps locally and never pushes. The verification cache's history grows with every verification run, and is compacted to its latest update once it exceeds the maximum cache size, dropping cached results for refs that no longer exist. Remote tracker refs whose latest entry is older than the maximum tracker age are removed, and are recreated when the remote's metadata is next fetched. Objects that become unreachable are left for 'git gc'.",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
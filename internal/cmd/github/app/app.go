@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/github/app/serve"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "app",
+		Short:             "Tools for running gittuf as a GitHub App",
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(serve.New())
+
+	return cmd
+}
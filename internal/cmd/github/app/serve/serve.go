@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package serve
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/githubapp"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+type options struct {
+	signingKey        string
+	repository        string
+	webhookSecretFile string
+	listenAddress     string
+	remoteName        string
+	gcInterval        time.Duration
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key to use for signing approval attestations",
+	)
+	cmd.MarkFlagRequired("signing-key") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.repository,
+		"repository",
+		"",
+		"GitHub repository to record approvals for, of form {owner}/{repo}",
+	)
+	cmd.MarkFlagRequired("repository") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.webhookSecretFile,
+		"webhook-secret-file",
+		"",
+		"path to file containing the GitHub App's webhook secret",
+	)
+	cmd.MarkFlagRequired("webhook-secret-file") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.listenAddress,
+		"listen-address",
+		":8080",
+		"address to listen for webhook deliveries on",
+	)
+
+	cmd.Flags().StringVar(
+		&o.remoteName,
+		"remote",
+		"origin",
+		"remote to pull attestations from and push attestations to, set to empty to only record attestations locally",
+	)
+
+	cmd.Flags().DurationVar(
+		&o.gcInterval,
+		"gc-interval",
+		time.Hour,
+		"interval at which the retention budgets of 'gittuf gc' are enforced, 0 to disable",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if !dev.InDevMode() {
+		return dev.ErrNotInDevMode
+	}
+
+	repositoryParts := strings.Split(o.repository, "/")
+	if len(repositoryParts) != 2 {
+		return fmt.Errorf("invalid format for repository, must be {owner}/{repo}")
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.signingKey)
+	if err != nil {
+		return err
+	}
+
+	webhookSecret, err := os.ReadFile(o.webhookSecretFile)
+	if err != nil {
+		return err
+	}
+	webhookSecret = bytes.TrimSpace(webhookSecret)
+	if len(webhookSecret) == 0 {
+		return fmt.Errorf("webhook secret must not be empty")
+	}
+
+	handler := githubapp.NewHandler(repo, &githubapp.Options{
+		Owner:         repositoryParts[0],
+		Repository:    repositoryParts[1],
+		WebhookSecret: webhookSecret,
+		Signer:        signer,
+		SignCommit:    true,
+		RemoteName:    o.remoteName,
+	})
+
+	server := &http.Server{
+		Addr:              o.listenAddress,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx) //nolint:errcheck
+	}()
+
+	if o.gcInterval > 0 {
+		config, err := repository.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		go collectGarbagePeriodically(ctx, repo, handler, config.GC, o.gcInterval)
+	}
+
+	slog.Info(fmt.Sprintf("Listening for GitHub webhook deliveries on '%s'...", o.listenAddress))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// collectGarbagePeriodically enforces the retention budgets on the repository's
+// gittuf-local state at the interval until the context is canceled. Webhook
+// deliveries are not processed while garbage is collected.
+func collectGarbagePeriodically(ctx context.Context, repo *repository.Repository, handler *githubapp.Handler, gcOptions *repository.GCOptions, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := handler.WithLock(func() error {
+				_, err := repo.CollectGarbage(gcOptions, false)
+				return err
+			})
+			if err != nil {
+				slog.Error(fmt.Sprintf("Unable to collect garbage: %s", err.Error()))
+			}
+		}
+	}
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "serve",
+		Short:             fmt.Sprintf("Serve GitHub webhooks to record pull request approvals as attestations (developer mode only, set %s=1)", dev.DevModeKey),
+		Long:              "The 'serve' command runs a long-lived server that receives the webhooks of a GitHub App. When a pull request review approving the pull request is submitted, the approval is recorded in a signed attestation in the attestations namespace and pushed to the remote. Dismissing the review removes the approval. The App must be subscribed to 'pull_request_review' events, and its webhook secret must match the one provided. The retention budgets of 'gittuf gc' are enforced periodically while the server runs.",
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
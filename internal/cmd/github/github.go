@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package github
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/github/app"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "github",
+		Short:             "Tools for integrating gittuf with GitHub",
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(app.New())
+
+	return cmd
+}
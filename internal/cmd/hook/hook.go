@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package hook
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/hook/referencetransaction"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "hook",
+		Short:             "Commands meant to be invoked from Git hooks",
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(referencetransaction.New())
+
+	return cmd
+}
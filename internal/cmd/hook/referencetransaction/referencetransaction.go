@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package referencetransaction
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/exitcodes"
+	"github.com/gittuf/gittuf/internal/hooks"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+// The states a reference transaction is in when Git invokes the
+// reference-transaction hook.
+const (
+	statePrepared  = "prepared"
+	stateCommitted = "committed"
+	stateAborted   = "aborted"
+)
+
+var ErrTransactionRejected = errors.New("reference transaction rejected by gittuf")
+
+type options struct {
+	enforcedRefs []string
+	enforcement  string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(
+		&o.enforcedRefs,
+		"enforce-ref",
+		[]string{},
+		fmt.Sprintf("pattern of refs to enforce gittuf for (default %v)", hooks.DefaultEnforcedRefs),
+	)
+
+	common.AddEnforcementFlag(cmd, &o.enforcement)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	switch args[0] {
+	case statePrepared:
+	case stateCommitted, stateAborted:
+		// Git ignores the hook's result once the transaction is committed
+		// or aborted, so there's nothing to verify
+		_, err := io.Copy(io.Discard, cmd.InOrStdin())
+		return err
+	default:
+		return fmt.Errorf("unknown reference transaction state '%s', must be one of %s, %s, %s", args[0], statePrepared, stateCommitted, stateAborted)
+	}
+
+	hookOptions := &hooks.Options{EnforcedRefs: o.enforcedRefs}
+	if err := hookOptions.Validate(); err != nil {
+		return err
+	}
+
+	if err := repository.ValidateVerificationEnforcement(o.enforcement); err != nil {
+		return err
+	}
+
+	transactionUpdates, err := repository.ParseReferenceTransactionUpdates(cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+
+	updates := []*repository.RefUpdate{}
+	for _, update := range transactionUpdates {
+		if hookOptions.ShouldVerify(update.Name) {
+			updates = append(updates, update)
+		}
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	repo, err := repository.LoadRepositoryForReceive()
+	if err != nil {
+		return err
+	}
+
+	decisions, err := repo.VerifyReferenceTransaction(cmd.Context(), updates)
+	if err != nil {
+		return err
+	}
+
+	rejected := common.EnforceRefUpdateDecisions(cmd.ErrOrStderr(), o.enforcement, decisions)
+	if rejected {
+		return exitcodes.AsPolicyViolation(ErrTransactionRejected)
+	}
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "reference-transaction <prepared|committed|aborted>",
+		Short:             "Verify the ref updates of a reference transaction on a Git server",
+		Long:              "The 'hook reference-transaction' command is meant to be invoked by a Git server's reference-transaction hook, such as the one added using 'gittuf add-hooks --reference-transaction'. When a transaction is prepared, it reads the transaction's ref updates from standard input, and aborts the transaction if any update to a ref gittuf is enforced for is not recorded in the RSL or fails verification. Unlike the pre-receive hook, this also covers ref updates that aren't made by pushes. As Git updates each ref pushed in its own transaction unless the push is atomic, the RSL must be pushed before the refs it records, as the pre-push hook does, or in the same atomic push.",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
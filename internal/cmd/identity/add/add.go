@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package add
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/identity/persistent"
+	"github.com/gittuf/gittuf/internal/identities"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p       *persistent.Options
+	forge   string
+	account string
+	keyID   string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.forge,
+		"forge",
+		identities.ForgeGitHub,
+		fmt.Sprintf("kind of account (%s)", strings.Join(identities.Forges, ", ")),
+	)
+
+	cmd.Flags().StringVar(
+		&o.account,
+		"account",
+		"",
+		"username on the forge, or email address",
+	)
+	cmd.MarkFlagRequired("account") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.keyID,
+		"key-ID",
+		"",
+		"ID of the account holder's key in the policy",
+	)
+	cmd.MarkFlagRequired("key-ID") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.AddIdentity(cmd.Context(), signer, o.forge, o.account, strings.ToLower(o.keyID), true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "add",
+		Short:             "Map an account to a key in the policy",
+		Long:              `This command records an identity mapping an account on a forge to the ID of a key in the policy, signed using the user's key. Any existing identity for the account is replaced. The identity is only used during verification once it is signed by the required number of policy administrators, who may sign it using "gittuf identity attest".`,
+		Args:              cobra.NoArgs,
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/identity/persistent"
+	"github.com/gittuf/gittuf/internal/identities"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p     *persistent.Options
+	forge string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.forge,
+		"forge",
+		identities.ForgeGitHub,
+		fmt.Sprintf("kind of account (%s)", strings.Join(identities.Forges, ", ")),
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.AttestIdentity(cmd.Context(), signer, o.forge, args[0], true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "attest <account>",
+		Short:             "Sign the identity recorded for an account",
+		Long:              `This command adds the user's signature to the identity recorded for the account. Identities signed by the required number of policy administrators, i.e., holders of root or top-level targets keys, are used during verification.`,
+		Args:              cobra.ExactArgs(1),
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
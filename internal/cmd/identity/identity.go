@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/identity/add"
+	"github.com/gittuf/gittuf/internal/cmd/identity/attest"
+	"github.com/gittuf/gittuf/internal/cmd/identity/list"
+	"github.com/gittuf/gittuf/internal/cmd/identity/persistent"
+	"github.com/gittuf/gittuf/internal/cmd/identity/remove"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	o := &persistent.Options{}
+	cmd := &cobra.Command{
+		Use:               "identity",
+		Short:             "Tools to map forge accounts to keys in the policy",
+		Long:              `The identity command manages signed mappings between accounts, such as GitHub or GitLab usernames and email addresses, and the IDs of keys in the policy. Identities are recorded in refs/gittuf/identities. An identity signed by the required number of policy administrators, i.e., holders of root or top-level targets keys, is used during verification to count the approvals recorded for the account, such as GitHub pull request approvals, towards the thresholds of the rules protecting the approved refs. The number of policy administrators required is the root role's threshold, or the policy approval threshold if it is higher.`,
+		DisableAutoGenTag: true,
+	}
+	o.AddPersistentFlags(cmd)
+
+	cmd.AddCommand(add.New(o))
+	cmd.AddCommand(attest.New(o))
+	cmd.AddCommand(list.New())
+	cmd.AddCommand(remove.New())
+
+	return cmd
+}
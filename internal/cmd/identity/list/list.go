@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package list
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/identities"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	statuses, err := repo.ListIdentities(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		trust := "untrusted"
+		if status.Trusted {
+			trust = "trusted"
+		}
+
+		fmt.Printf("%s: %s (%s)\n", identities.IdentityPath(status.Forge, status.Account), status.KeyID, trust)
+		fmt.Printf("    Signed by: %s\n", strings.Join(status.SignedBy, ", "))
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "list",
+		Short:             "List the identities recorded in the repository",
+		Long:              `This command lists the identities recorded in the repository, the keys that signed them, and whether they are trusted by the current policy.`,
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package incident
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/incident/start"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "incident",
+		Short:             "Tools to respond to security incidents, such as key compromises",
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(start.New())
+
+	return cmd
+}
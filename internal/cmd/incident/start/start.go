@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package start
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	signingKey string
+	keyID      string
+	since      string
+	reportFile string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		fmt.Sprintf("root signing key to use to revoke the compromised key, defaults to %s", repository.SigningKeyConfigKey),
+	)
+
+	cmd.Flags().StringVar(
+		&o.keyID,
+		"key",
+		"",
+		"ID of the compromised key",
+	)
+	cmd.MarkFlagRequired("key") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.since,
+		"since",
+		"",
+		"RFC 3339 timestamp from which the key is considered compromised, all of the key's usages are considered compromised if not set",
+	)
+
+	cmd.Flags().StringVar(
+		&o.reportFile,
+		"report",
+		"",
+		"file to also write the incident report to",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	var since time.Time
+	if o.since != "" {
+		var err error
+		since, err = time.Parse(time.RFC3339, o.since)
+		if err != nil {
+			return fmt.Errorf("invalid since timestamp: %w", err)
+		}
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.signingKey)
+	if err != nil {
+		return err
+	}
+
+	report, reportID, err := repo.StartIncident(cmd.Context(), signer, o.keyID, since, true)
+	if err != nil {
+		return err
+	}
+
+	if o.reportFile != "" {
+		contents, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(o.reportFile, contents, 0o600); err != nil {
+			return err
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Revoked key '%s' from %d roles and %d rules\n", o.keyID, len(report.RevokedRoles), len(report.RevokedRules))
+	fmt.Fprintf(out, "Skipped %d RSL entries signed using the key\n", len(report.SkippedEntries))
+	fmt.Fprintf(out, "Found %d policy signatures and %d attestations to review\n", len(report.PolicySignatures), len(report.Attestations))
+	fmt.Fprintf(out, "Incident report written to blob %s\n", reportID.String())
+
+	if len(report.RevokedRoles) != 0 || len(report.RevokedRules) != 0 {
+		fmt.Fprintln(out, "\nNext steps:")
+		for _, ruleFileName := range report.UnsignedRuleFiles {
+			fmt.Fprintf(out, "  Sign rule file '%s' using 'gittuf policy sign --policy-name %s'\n", ruleFileName, ruleFileName)
+		}
+		fmt.Fprintln(out, "  Apply the revocation using 'gittuf policy apply'")
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "start",
+		Short:             "Respond to the compromise of a key",
+		Long:              "The start command responds to the compromise of a key in one flow. It revokes the key in the policy staging area, skips the RSL entries signed using the key since the specified time, identifies the policy metadata and attestations it signed, and writes an incident report to a Git blob. Rule files updated to revoke the key must be signed before the policy is applied.",
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	usages, err := repo.AuditKey(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(usages) == 0 {
+		fmt.Printf("No signatures found for key '%s'\n", args[0])
+		return nil
+	}
+
+	for _, usage := range usages {
+		fmt.Printf("%s %s %s\n", usage.RSLEntryID.String(), usage.Type, usage.Name)
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "audit <key-id>",
+		Short:             "List every RSL entry, policy signature, and attestation signed by a key",
+		Long:              "The audit command lists every RSL entry, policy metadata signature, and attestation signature made by the specified key across the repository's history. This is meant to support incident response when a key is suspected to be compromised. The key must be present in at least one policy in the repository's history.",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
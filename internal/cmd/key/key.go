@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package key
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/key/audit"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "key",
+		Short:             "Tools to inspect the use of keys in the repository",
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(audit.New())
+
+	return cmd
+}
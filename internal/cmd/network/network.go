@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package network
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/network/verify"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "network",
+		Short:             "Tools for verifying a network of related repositories",
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(verify.New())
+
+	return cmd
+}
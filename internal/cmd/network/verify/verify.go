@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	manifest    string
+	jsonOutput  bool
+	enforcement string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.manifest,
+		"manifest",
+		"",
+		"path to the network manifest listing the repositories to verify",
+	)
+	cmd.MarkFlagRequired("manifest") //nolint:errcheck
+
+	cmd.Flags().BoolVar(
+		&o.jsonOutput,
+		"json",
+		false,
+		"print the verification report as JSON",
+	)
+
+	common.AddEnforcementFlag(cmd, &o.enforcement)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if err := repository.ValidateVerificationEnforcement(o.enforcement); err != nil {
+		return err
+	}
+
+	err := o.verify(cmd, args)
+	return common.ApplyEnforcement(o.enforcement, err)
+}
+
+func (o *options) verify(cmd *cobra.Command, _ []string) error {
+	manifest, err := repository.LoadNetworkManifest(o.manifest)
+	if err != nil {
+		return err
+	}
+
+	report, err := repository.VerifyNetwork(cmd.Context(), manifest)
+	if report == nil {
+		return err
+	}
+
+	// The report is printed even if some repositories failed verification
+	if o.jsonOutput {
+		contents, marshalErr := json.MarshalIndent(report, "", "  ")
+		if marshalErr != nil {
+			return errors.Join(err, marshalErr)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(contents))
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Root of trust: %s\n", report.RootOfTrust)
+	for _, repositoryReport := range report.Repositories {
+		status := "verified"
+		if repositoryReport.Error != "" {
+			status = fmt.Sprintf("failed: %s", repositoryReport.Error)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Repository '%s': %s\n", repositoryReport.Repository, status)
+	}
+
+	for _, reference := range report.References {
+		status := "resolved"
+		if reference.Error != "" {
+			status = fmt.Sprintf("failed: %s", reference.Error)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s '%s' in '%s' at '%s' -> '%s': %s\n", reference.Kind, reference.Path, reference.Repository, reference.Ref, reference.Target, status)
+	}
+
+	return err
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify a network of related repositories share a root of trust and reference verified states",
+		Long: `This command verifies a set of related repositories listed in a network manifest. Each repository's refs are verified against its own gittuf policy, and every repository must share the root of trust of the first repository in the manifest. Submodules and dependency pins at the tip of each verified ref that refer to another repository in the network must resolve to commits in the verified history of that repository's refs. The manifest is a JSON file with the following fields:
+
+  name:         the network's name
+  repositories: the repositories, each with a unique "name", the "path" of a local clone, optionally the "url" that submodules use to refer to it, the "refs" to verify (default refs/heads/main), and "pins", files at a "path" containing the ID of a commit in another "repository"`,
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
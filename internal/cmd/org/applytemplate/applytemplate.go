@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package applytemplate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	signingKey  string
+	rolloutFile string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key to use to sign the policy of each repository",
+	)
+	cmd.MarkFlagRequired("signing-key") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.rolloutFile,
+		"rollout-file",
+		"",
+		"file to record the rollout in, printed to standard output if not set",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	manifest, err := repository.LoadOrganizationManifest(args[0])
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.signingKey)
+	if err != nil {
+		return err
+	}
+
+	rollout, err := repository.ApplyOrganizationTemplate(cmd.Context(), manifest, signer, common.LoadPublicKey, true)
+	if rollout == nil {
+		return err
+	}
+
+	// The rollout is recorded even if the template could not be applied to
+	// some repositories
+	if o.rolloutFile != "" {
+		if saveErr := rollout.Save(o.rolloutFile); saveErr != nil {
+			return errors.Join(err, saveErr)
+		}
+		return err
+	}
+
+	contents, marshalErr := json.MarshalIndent(rollout, "", "  ")
+	if marshalErr != nil {
+		return errors.Join(err, marshalErr)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), string(contents))
+
+	return err
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "apply-template <manifest>",
+		Short: "Apply an organization's baseline policy to its repositories",
+		Long: `This command applies the baseline policy in an organization manifest to each repository listed in it, keeping the policies of the repositories in sync with the organization's standards. The manifest is a JSON file with the following fields:
+
+  name:         the organization's name
+  repositories: the repositories, each with the "path" of a local clone and optionally the "remote" to push the updated policy to
+  template:     the baseline policy, with named "keysets" of keys, "rules" that authorize keysets for patterns with a threshold, and "appTrust" entries that trust an app's key in rules for specific operations
+
+Rules in the template are added to or updated in each repository's top level policy, and the changes are signed using the specified key, which must be trusted for the top level policy in each repository. The outcome for each repository is recorded in the rollout, along with the digest of the template and its policy entry.`,
+		Args:              cobra.ExactArgs(1),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package org
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/org/applytemplate"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "org",
+		Short:             "Tools for managing gittuf policy across an organization's repositories",
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(applytemplate.New())
+
+	return cmd
+}
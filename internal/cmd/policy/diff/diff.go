@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	toEntryID := ""
+	if len(args) > 1 {
+		toEntryID = args[1]
+	}
+
+	diff, err := repo.DiffPolicy(cmd.Context(), args[0], toEntryID)
+	if err != nil {
+		return err
+	}
+
+	if !diff.HasChanges() {
+		fmt.Println("No policy changes")
+		return nil
+	}
+
+	return common.WritePolicyDiff(os.Stdout, diff, 0)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "diff <from-entry> [<to-entry>]",
+		Short:             "Show the changes between the policy states in effect at two RSL entries",
+		Long:              "The 'diff' command shows the keys, thresholds, rules, and expiry dates that differ between the policy in effect at one RSL entry and the policy in effect at another. If the second entry is omitted, the latest applied policy is used.",
+		Args:              cobra.RangeArgs(1, 2),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
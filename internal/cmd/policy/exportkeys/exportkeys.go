@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package exportkeys
+
+import (
+	"os"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	allowedSignersPath string
+	gpgKeyringPath     string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.allowedSignersPath,
+		"allowed-signers",
+		"",
+		"path to write SSH allowed_signers file with the SSH keys trusted in policy",
+	)
+
+	cmd.Flags().StringVar(
+		&o.gpgKeyringPath,
+		"gpg-keyring",
+		"",
+		"path to write armored GPG keyring with the GPG keys trusted in policy",
+	)
+
+	cmd.MarkFlagsOneRequired("allowed-signers", "gpg-keyring")
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	if o.allowedSignersPath != "" {
+		allowedSigners, err := repo.ExportAllowedSigners(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(o.allowedSignersPath, allowedSigners, 0o644); err != nil {
+			return err
+		}
+	}
+
+	if o.gpgKeyringPath != "" {
+		keyring, err := repo.ExportGPGKeyring(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(o.gpgKeyringPath, keyring, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "export-keys",
+		Short: "Export keys trusted in policy for use with Git's signature verification",
+		Long: `Export the keys trusted in the current policy so that Git's own signature verification agrees with gittuf policy.
+
+The SSH allowed_signers file can be used by setting 'gpg.ssh.allowedSignersFile' in the Git config. The GPG keyring can be imported using 'gpg --import'. The exported keys are trusted for any identity; gittuf policy remains the authority on which key may modify which refs and files.`,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
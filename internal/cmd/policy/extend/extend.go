@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package extend
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p      *persistent.Options
+	role   string
+	period string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.role,
+		"role",
+		policy.TargetsRoleName,
+		"name of role whose metadata expiration is extended, such as root, targets, or a delegated policy file",
+	)
+
+	cmd.Flags().StringVar(
+		&o.period,
+		"period",
+		"365d",
+		"period from now after which the metadata expires, such as 90d or 720h",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	period, err := repository.ParsePeriod(o.period)
+	if err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.ExtendPolicyExpiration(cmd.Context(), signer, o.role, period, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "extend",
+		Short:             "Extend the expiration of policy metadata",
+		Long:              `This command sets the expiration of the specified role's metadata to the specified period from now, and re-signs it using the signing key. It can be run on a schedule to renew the policy before it expires. As the metadata is modified, its existing signatures are discarded, and further signatures needed to meet the role's threshold must be added using "gittuf policy sign" or "gittuf trust sign". The changes must be applied using "gittuf policy apply".`,
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
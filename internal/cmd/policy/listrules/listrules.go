@@ -12,6 +12,7 @@ import (
 
 type options struct {
 	targetRef string
+	path      string
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -21,6 +22,13 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"policy",
 		"specify which policy ref should be inspected",
 	)
+
+	cmd.Flags().StringVar(
+		&o.path,
+		"path",
+		"",
+		"list only the rules that apply to the specified path (git:<ref> or file:<path>)",
+	)
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
@@ -29,6 +37,10 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
+	if o.path != "" {
+		return o.listRulesForPath(cmd, repo)
+	}
+
 	rules, err := repo.ListRules(cmd.Context(), o.targetRef)
 	if err != nil {
 		return err
@@ -62,10 +74,55 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 
 		fmt.Println(strings.Repeat("    ", curRule.Depth+1) + "Authorized keys:")
 		for _, key := range curRule.Delegation.Role.KeyIDs {
+			if operations, restricted := curRule.Delegation.KeyOperations[key]; restricted {
+				fmt.Printf(strings.Repeat("    ", curRule.Depth+2)+"%s (only for: %s)\n", key, strings.Join(operations, ", "))
+				continue
+			}
 			fmt.Printf(strings.Repeat("    ", curRule.Depth+2)+"%s\n", key)
 		}
 
 		fmt.Println(strings.Repeat("    ", curRule.Depth+1) + fmt.Sprintf("Required valid signatures: %d", curRule.Delegation.Role.Threshold))
+
+		if rotation := curRule.Delegation.Rotation; rotation != nil {
+			fmt.Println(strings.Repeat("    ", curRule.Depth+1) + fmt.Sprintf("Rotation every %s from %s:", rotation.Period, rotation.Start))
+			for i, shift := range rotation.Shifts {
+				fmt.Printf(strings.Repeat("    ", curRule.Depth+2)+"Shift %d: %s\n", i+1, strings.Join(shift, ", "))
+			}
+		}
+
+		if curRule.Delegation.RequireTicket {
+			fmt.Println(strings.Repeat("    ", curRule.Depth+1) + "Requires ticket in RSL entries")
+		}
+
+		if len(curRule.Delegation.AllowedBuilders) > 0 {
+			fmt.Println(strings.Repeat("    ", curRule.Depth+1) + "Requires provenance from builders:")
+			for _, builder := range curRule.Delegation.AllowedBuilders {
+				fmt.Printf(strings.Repeat("    ", curRule.Depth+2)+"%s\n", builder)
+			}
+		}
+	}
+	return nil
+}
+
+func (o *options) listRulesForPath(cmd *cobra.Command, repo *repository.Repository) error {
+	verifiers, err := repo.ListRulesForPath(cmd.Context(), o.targetRef, o.path)
+	if err != nil {
+		return err
+	}
+
+	if len(verifiers) == 0 {
+		fmt.Printf("No rules apply to '%s'\n", o.path)
+		return nil
+	}
+
+	for _, verifier := range verifiers {
+		fmt.Printf("Rule %s:\n", verifier.Name())
+		fmt.Println(strings.Repeat("    ", 1) + "Authorized keys:")
+		for _, key := range verifier.Keys() {
+			fmt.Printf(strings.Repeat("    ", 2)+"%s\n", key.KeyID)
+		}
+
+		fmt.Println(strings.Repeat("    ", 1) + fmt.Sprintf("Required valid signatures: %d", verifier.Threshold()))
 	}
 	return nil
 }
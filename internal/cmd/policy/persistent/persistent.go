@@ -2,7 +2,12 @@
 
 package persistent
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
 
 type Options struct {
 	SigningKey string
@@ -14,6 +19,6 @@ func (o *Options) AddPersistentFlags(cmd *cobra.Command) {
 		"signing-key",
 		"k",
 		"",
-		"signing key to use to sign policy file",
+		fmt.Sprintf("signing key to use to sign policy file (path to a key file, or piv:slot-<slot> for a key on a hardware token), defaults to %s", repository.SigningKeyConfigKey),
 	)
 }
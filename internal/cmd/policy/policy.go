@@ -5,11 +5,23 @@ package policy
 import (
 	"github.com/gittuf/gittuf/internal/cmd/policy/addkey"
 	"github.com/gittuf/gittuf/internal/cmd/policy/addrule"
+	"github.com/gittuf/gittuf/internal/cmd/policy/diff"
+	"github.com/gittuf/gittuf/internal/cmd/policy/discard"
+	"github.com/gittuf/gittuf/internal/cmd/policy/exportkeys"
+	"github.com/gittuf/gittuf/internal/cmd/policy/extend"
 	i "github.com/gittuf/gittuf/internal/cmd/policy/init"
 	"github.com/gittuf/gittuf/internal/cmd/policy/listrules"
 	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
 	"github.com/gittuf/gittuf/internal/cmd/policy/removerule"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setallowedbuilders"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setallowedupdatetypes"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setrefmappings"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setrotation"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setticketrequirement"
+	"github.com/gittuf/gittuf/internal/cmd/policy/showgraph"
 	"github.com/gittuf/gittuf/internal/cmd/policy/sign"
+	"github.com/gittuf/gittuf/internal/cmd/policy/stage"
+	"github.com/gittuf/gittuf/internal/cmd/policy/trustgithubwebflow"
 	"github.com/gittuf/gittuf/internal/cmd/policy/updaterule"
 	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/apply"
 	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/remote"
@@ -29,10 +41,22 @@ func New() *cobra.Command {
 	cmd.AddCommand(addkey.New(o))
 	cmd.AddCommand(apply.New())
 	cmd.AddCommand(addrule.New(o))
+	cmd.AddCommand(diff.New())
+	cmd.AddCommand(discard.New())
+	cmd.AddCommand(exportkeys.New())
+	cmd.AddCommand(extend.New(o))
 	cmd.AddCommand(listrules.New())
 	cmd.AddCommand(remote.New())
 	cmd.AddCommand(removerule.New(o))
+	cmd.AddCommand(setallowedbuilders.New(o))
+	cmd.AddCommand(setallowedupdatetypes.New(o))
+	cmd.AddCommand(setrefmappings.New(o))
+	cmd.AddCommand(setrotation.New(o))
+	cmd.AddCommand(setticketrequirement.New(o))
+	cmd.AddCommand(showgraph.New())
 	cmd.AddCommand(sign.New(o))
+	cmd.AddCommand(stage.New())
+	cmd.AddCommand(trustgithubwebflow.New(o))
 	cmd.AddCommand(updaterule.New(o))
 
 	return cmd
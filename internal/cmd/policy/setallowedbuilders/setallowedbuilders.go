@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setallowedbuilders
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	ruleName   string
+	builders   []string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file containing the rule",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.builders,
+		"builder",
+		[]string{},
+		"ID of builder allowed to produce provenance for the rule's tags, such as the URI of a CI workflow (can be repeated, omit to remove the requirement)",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetDelegationAllowedBuilders(cmd.Context(), signer, o.policyName, o.ruleName, o.builders, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "set-allowed-builders",
+		Short: "Require tags protected by a rule to have SLSA provenance from allowed builders",
+		Long: `This command allows users to require that release tags protected by a rule are built by trusted builders. Such tags must have SLSA provenance, attached using "gittuf attest provenance", that records one of the builder IDs specified using --builder and is signed by one of the rule's keys. The requirement is checked by "gittuf verify-tag", using the repository's current attestations as provenance is typically produced after the tag is pushed.
+
+If no builders are specified, the rule no longer requires provenance.`,
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
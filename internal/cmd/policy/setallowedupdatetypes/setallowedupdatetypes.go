@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setallowedupdatetypes
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p           *persistent.Options
+	policyName  string
+	ruleName    string
+	updateTypes []string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file containing the rule",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.updateTypes,
+		"allow",
+		[]string{},
+		fmt.Sprintf("update type allowed for the rule's branches, one of %s, %s, %s, and %s (can be repeated, omit to allow all update types)", tuf.UpdateTypeFastForward, tuf.UpdateTypeSquash, tuf.UpdateTypeMerge, tuf.UpdateTypeNonFastForward),
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetDelegationAllowedUpdateTypes(cmd.Context(), signer, o.policyName, o.ruleName, o.updateTypes, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "set-allowed-update-types",
+		Short: "Restrict how branches protected by a rule may be updated",
+		Long: `This command allows users to restrict how the branches protected by a rule may be updated between consecutive RSL entries, such as to require linear history or to require merge commits for traceability. Each update is one of:
+
+- fast-forward: adds several commits, none of which are merge commits
+- squash: adds a single commit that is not a merge commit
+- merge: adds at least one merge commit
+- non-fast-forward: the previous target is not an ancestor of the new target, such as when history is rewritten
+
+Creating and deleting branches is not restricted. The restriction is checked by "gittuf verify-ref".
+
+If no update types are specified, all update types are allowed.`,
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setrefmappings
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	ruleName   string
+	mappings   map[string]string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file containing the rule",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().StringToStringVar(
+		&o.mappings,
+		"map",
+		map[string]string{},
+		"mapping of a ref on a mirror to the upstream ref protected by the rule, in the format {mirror-ref}={upstream-ref} (can be repeated, omit to remove the mappings)",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetDelegationRefMappings(cmd.Context(), signer, o.policyName, o.ruleName, o.mappings, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "set-ref-mappings",
+		Short: "Map refs on mirrors to the upstream refs protected by a rule",
+		Long: `This command allows users to record that refs on a mirror of the repository are named differently from the upstream refs protected by a rule, such as when a mirror stores "refs/heads/main" as "refs/heads/upstream/main". Each mapping, specified using --map, must use fully qualified ref names and the upstream ref must be protected by the rule.
+
+"gittuf verify-ref" and "gittuf checkout" automatically verify a mapped ref on the mirror using the RSL entries and rules of its upstream ref.
+
+If no mappings are specified, the rule's mappings are removed.`,
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setrotation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	ruleName   string
+	start      string
+	period     time.Duration
+	shifts     []string
+	remove     bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file containing the rule",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.start,
+		"start",
+		"",
+		"RFC 3339 timestamp at which the first shift begins",
+	)
+
+	cmd.Flags().DurationVar(
+		&o.period,
+		"period",
+		7*24*time.Hour,
+		"length of each shift",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&o.shifts,
+		"shift",
+		[]string{},
+		"comma separated authorized public keys for a shift, in rotation order",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.remove,
+		"remove",
+		false,
+		"remove the rule's rotation schedule",
+	)
+
+	cmd.MarkFlagsMutuallyExclusive("remove", "start")
+	cmd.MarkFlagsMutuallyExclusive("remove", "shift")
+	cmd.MarkFlagsOneRequired("remove", "shift")
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	if o.remove {
+		return repo.SetDelegationRotation(cmd.Context(), signer, o.policyName, o.ruleName, nil, true)
+	}
+
+	start := time.Now().UTC()
+	if o.start != "" {
+		start, err = time.Parse(time.RFC3339, o.start)
+		if err != nil {
+			return fmt.Errorf("invalid start: %w", err)
+		}
+	}
+
+	rotation := &tuf.RotationSchedule{
+		Start:  start.Format(time.RFC3339),
+		Period: o.period.String(),
+		Shifts: make([][]string, 0, len(o.shifts)),
+	}
+	for _, shift := range o.shifts {
+		keyIDs := []string{}
+		for _, keyPath := range strings.Split(shift, ",") {
+			key, err := common.LoadPublicKey(strings.TrimSpace(keyPath))
+			if err != nil {
+				return err
+			}
+
+			keyIDs = append(keyIDs, key.KeyID)
+		}
+
+		rotation.Shifts = append(rotation.Shifts, keyIDs)
+	}
+
+	return repo.SetDelegationRotation(cmd.Context(), signer, o.policyName, o.ruleName, rotation, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "set-rotation",
+		Short: "Set a rotation schedule for the keys authorized by a rule",
+		Long: `This command allows users to rotate the keys authorized by a rule on a calendar, such as for on-call or release duty rotations. Time is divided into consecutive shifts of the specified period, starting at the specified time (now by default). Each shift is assigned the next set of keys specified using --shift, cycling back to the first after the last. RSL entries are verified using the keys of the shift their timestamp falls in.
+
+The keys in each shift must already be authorized by the rule, and each shift must meet the rule's threshold. Note that keys can be specified from disk, from the GPG keyring using the "gpg:<fingerprint>" format, as a Sigstore identity as "fulcio:<identity>::<issuer>", or as trusted X.509 certificates, optionally restricted to an identity, as "x509:<certificates-path>[::<identity>]".`,
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
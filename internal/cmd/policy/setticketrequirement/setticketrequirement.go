@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setticketrequirement
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	ruleName   string
+	remove     bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file containing the rule",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().BoolVar(
+		&o.remove,
+		"remove",
+		false,
+		"remove the rule's ticket requirement",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetDelegationRequireTicket(cmd.Context(), signer, o.policyName, o.ruleName, !o.remove, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "set-ticket-requirement",
+		Short: "Require RSL entries for the refs protected by a rule to reference a ticket",
+		Long: `This command allows users to require that changes to the refs protected by a rule, such as release or hotfix branches, are tracked in an issue tracker. RSL entries for such refs must record at least one ticket URI, using "gittuf rsl record --ticket <uri>", or they fail verification.
+
+Tickets recorded in RSL annotations are informational and do not satisfy the requirement, as the ticket must be covered by the signature that is verified against the rule.`,
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
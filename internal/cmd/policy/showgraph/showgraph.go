@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package showgraph
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+const (
+	formatTree = "tree"
+	formatDOT  = "dot"
+)
+
+type options struct {
+	targetRef string
+	format    string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.targetRef,
+		"target-ref",
+		"policy",
+		"specify which policy ref should be inspected",
+	)
+
+	cmd.Flags().StringVar(
+		&o.format,
+		"format",
+		formatTree,
+		fmt.Sprintf("output format (%s, %s)", formatTree, formatDOT),
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if o.format != formatTree && o.format != formatDOT {
+		return fmt.Errorf("unknown format '%s', must be one of %s, %s", o.format, formatTree, formatDOT)
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	graph, err := repo.GetDelegationGraph(cmd.Context(), o.targetRef)
+	if err != nil {
+		return err
+	}
+
+	if o.format == formatDOT {
+		return common.WriteDelegationDOT(cmd.OutOrStdout(), graph)
+	}
+	return common.WriteDelegationTree(cmd.OutOrStdout(), graph)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "show-graph",
+		Short:             "Show the delegation graph of the policy",
+		Long:              "The 'show-graph' command renders the policy's delegation graph, starting at the root of trust, with the keys, threshold, and patterns of each role. The graph is written as an ASCII tree by default, or in the Graphviz DOT format, which can be rendered using tools such as 'dot -Tsvg'.",
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -3,7 +3,7 @@
 package sign
 
 import (
-	"os"
+	"fmt"
 
 	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
@@ -15,6 +15,7 @@ import (
 type options struct {
 	p          *persistent.Options
 	policyName string
+	checkOnly  bool
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -24,6 +25,22 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		policy.TargetsRoleName,
 		"name of policy file to sign",
 	)
+
+	cmd.Flags().BoolVar(
+		&o.checkOnly,
+		"check-only",
+		false,
+		"report which policy files are fully signed and which signatures are missing without signing",
+	)
+}
+
+func (o *options) PreRunE(cmd *cobra.Command, args []string) error {
+	if o.checkOnly {
+		// No signing key is needed to report the signing status
+		return nil
+	}
+
+	return common.CheckIfSigningViableWithFlag(cmd, args)
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
@@ -32,16 +49,47 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	keyBytes, err := os.ReadFile(o.p.SigningKey)
+	if o.checkOnly {
+		return o.reportSigningStatus(cmd, repo)
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
 	if err != nil {
 		return err
 	}
-	signer, err := common.LoadSigner(keyBytes)
+
+	return repo.SignTargets(cmd.Context(), signer, o.policyName, true)
+}
+
+func (o *options) reportSigningStatus(cmd *cobra.Command, repo *repository.Repository) error {
+	statuses, err := repo.GetPolicySigningStatus(cmd.Context())
 	if err != nil {
 		return err
 	}
 
-	return repo.SignTargets(cmd.Context(), signer, o.policyName, true)
+	for _, status := range statuses {
+		state := "threshold met"
+		if !status.IsFullySigned() {
+			state = "threshold not met"
+		}
+		fmt.Printf("Policy file '%s': %d of %d required signatures, %s\n", status.Name, len(status.SignedBy), status.Threshold, state)
+
+		if len(status.SignedBy) > 0 {
+			fmt.Println("    Signed by:")
+			for _, keyID := range status.SignedBy {
+				fmt.Printf("        %s\n", keyID)
+			}
+		}
+
+		if len(status.Missing) > 0 {
+			fmt.Println("    Not yet signed by:")
+			for _, keyID := range status.Missing {
+				fmt.Printf("        %s\n", keyID)
+			}
+		}
+	}
+
+	return nil
 }
 
 func New(persistent *persistent.Options) *cobra.Command {
@@ -50,7 +98,7 @@ func New(persistent *persistent.Options) *cobra.Command {
 		Use:               "sign",
 		Short:             "Sign policy file",
 		Long:              "This command allows users to add their signature to the specified policy file.",
-		PreRunE:           common.CheckIfSigningViableWithFlag,
+		PreRunE:           o.PreRunE,
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
 	}
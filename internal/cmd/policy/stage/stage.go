@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package stage
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	changes, err := repo.GetStagedPolicyChanges(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	if !changes.HasChanges() {
+		fmt.Println("No changes staged in policy-staging")
+		return nil
+	}
+
+	fmt.Println("Changes staged in policy-staging:")
+	if err := common.WritePolicyDiff(os.Stdout, changes, 1); err != nil {
+		return err
+	}
+
+	statuses, err := repo.GetPolicySigningStatus(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Signing status:")
+	for _, status := range statuses {
+		fmt.Printf(strings.Repeat("    ", 1)+"Role %s: %d of %d required signatures\n", status.Name, len(status.SignedBy), status.Threshold)
+		if !status.IsFullySigned() && len(status.Missing) > 0 {
+			fmt.Printf(strings.Repeat("    ", 2)+"Can be signed by: %s\n", strings.Join(status.Missing, ", "))
+		}
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "stage",
+		Short:             "Show the changes staged in policy-staging",
+		Long:              "The 'stage' command shows the changes accumulated in policy-staging by other policy commands, such as 'add-rule' and 'add-key', compared to the applied policy, along with the signatures still required for them. Once signed, staged changes are validated and applied atomically using 'apply', or dropped using 'discard'.",
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
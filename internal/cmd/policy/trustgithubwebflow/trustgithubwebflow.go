@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package trustgithubwebflow
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	ruleName   string
+	webFlowKey string
+	operations []string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file containing the rule",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule to trust GitHub's web-flow key in",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.webFlowKey,
+		"web-flow-key",
+		"",
+		"GitHub's web-flow public key",
+	)
+	cmd.MarkFlagRequired("web-flow-key") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.operations,
+		"operation",
+		[]string{tuf.KeyOperationMergeCommit},
+		"operation the web-flow key is trusted for (merge-commit, commit)",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	webFlowKey, err := common.LoadPublicKey(o.webFlowKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.AddRestrictedKeyToDelegation(cmd.Context(), signer, o.policyName, o.ruleName, webFlowKey, o.operations, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "trust-github-web-flow",
+		Short: "Trust GitHub's web-flow key in a rule for specific operations",
+		Long: `This command allows users to trust the key GitHub uses to sign commits created using its web UI, such as when a pull request is merged, in the specified rule. Unlike other authorized keys, the web-flow key only counts towards the rule's threshold for the specified operations. By default, it is only trusted for merge commits.
+
+GitHub's web-flow key can be obtained from https://github.com/web-flow.gpg. It can be specified from disk or, after importing it, from the GPG keyring using the "gpg:<fingerprint>" format.`,
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pruneunreachable
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	dryRun bool
+	expire time.Duration
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&o.dryRun,
+		"dry-run",
+		false,
+		"report unreachable gittuf objects without removing them",
+	)
+
+	cmd.Flags().DurationVar(
+		&o.expire,
+		"expire",
+		repository.DefaultPruneExpiry,
+		"only remove unreachable objects older than this duration",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	unreachableObjects, err := repo.PruneUnreachableGittufObjects(time.Now().Add(-o.expire), o.dryRun)
+	if err != nil {
+		return err
+	}
+
+	packed := 0
+	for _, obj := range unreachableObjects {
+		status := ""
+		switch {
+		case obj.Removed:
+			status = "removed"
+		case obj.Packed:
+			status = "packed, will be dropped by git gc"
+			packed++
+		case obj.Recent:
+			status = "newer than --expire"
+		default:
+			status = "can be removed"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %s (%s)\n", obj.Type.String(), obj.ID.String(), status)
+	}
+
+	if packed > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "\n%d unreachable gittuf objects are packed, run 'git gc' to remove them\n", packed)
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "prune-unreachable",
+		Short:             "Remove gittuf objects that are no longer reachable",
+		Long:              "The 'prune-unreachable' command identifies objects created by gittuf, such as RSL entries, policy states, and attestations, that are no longer reachable from any ref, and removes those stored as loose objects. Objects reachable from any ref are never removed, and objects newer than the expiry are kept in case they are in use by a concurrent operation. Packed objects are left for 'git gc', which drops unreachable objects when it repacks the repository.",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
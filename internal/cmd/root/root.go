@@ -3,25 +3,57 @@
 package root
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
 
 	"github.com/gittuf/gittuf/internal/cmd/addhooks"
+	"github.com/gittuf/gittuf/internal/cmd/attest"
+	"github.com/gittuf/gittuf/internal/cmd/channel"
+	"github.com/gittuf/gittuf/internal/cmd/checkout"
 	"github.com/gittuf/gittuf/internal/cmd/clone"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/compat"
+	"github.com/gittuf/gittuf/internal/cmd/daemon"
 	"github.com/gittuf/gittuf/internal/cmd/dev"
+	"github.com/gittuf/gittuf/internal/cmd/exitcodes"
+	"github.com/gittuf/gittuf/internal/cmd/fsck"
+	"github.com/gittuf/gittuf/internal/cmd/gc"
+	"github.com/gittuf/gittuf/internal/cmd/github"
+	"github.com/gittuf/gittuf/internal/cmd/hook"
+	"github.com/gittuf/gittuf/internal/cmd/identity"
+	"github.com/gittuf/gittuf/internal/cmd/incident"
+	"github.com/gittuf/gittuf/internal/cmd/key"
+	"github.com/gittuf/gittuf/internal/cmd/network"
+	"github.com/gittuf/gittuf/internal/cmd/org"
 	"github.com/gittuf/gittuf/internal/cmd/policy"
 	"github.com/gittuf/gittuf/internal/cmd/profile"
+	"github.com/gittuf/gittuf/internal/cmd/pruneunreachable"
 	"github.com/gittuf/gittuf/internal/cmd/rsl"
+	"github.com/gittuf/gittuf/internal/cmd/serve"
+	"github.com/gittuf/gittuf/internal/cmd/stats"
+	"github.com/gittuf/gittuf/internal/cmd/status"
 	"github.com/gittuf/gittuf/internal/cmd/trust"
 	"github.com/gittuf/gittuf/internal/cmd/verifycommit"
+	"github.com/gittuf/gittuf/internal/cmd/verifygithubrelease"
+	"github.com/gittuf/gittuf/internal/cmd/verifyreceive"
 	"github.com/gittuf/gittuf/internal/cmd/verifyref"
 	"github.com/gittuf/gittuf/internal/cmd/verifytag"
 	"github.com/gittuf/gittuf/internal/cmd/version"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/progress"
+	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
 type options struct {
 	verbose           bool
+	logFormat         string
+	logLevel          string
+	logModuleLevels   []string
+	logFile           string
+	progressFormat    string
 	profile           bool
 	cpuProfileFile    string
 	memoryProfileFile string
@@ -35,6 +67,41 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"enable verbose logging",
 	)
 
+	cmd.PersistentFlags().StringVar(
+		&o.logFormat,
+		"log-format",
+		logging.FormatText,
+		fmt.Sprintf("format of logs (text, json), overrides %s", logging.FormatConfigKey),
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.logLevel,
+		"log-level",
+		"info",
+		fmt.Sprintf("minimum level of logs (debug, info, warn, error), overrides %s", logging.LevelConfigKey),
+	)
+
+	cmd.PersistentFlags().StringArrayVar(
+		&o.logModuleLevels,
+		"log-module-level",
+		[]string{},
+		fmt.Sprintf("minimum level of logs for a module, of form {module}={level} such as policy=debug, overrides %s", logging.ModuleLevelsConfigKey),
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.logFile,
+		"log-file",
+		"",
+		fmt.Sprintf("file to append logs to instead of stderr, overrides %s", logging.FileConfigKey),
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.progressFormat,
+		"progress-format",
+		progress.FormatNone,
+		"format of progress events written to stderr during clone, verify, and sync operations (none, json)",
+	)
+
 	cmd.PersistentFlags().BoolVar(
 		&o.profile,
 		"profile",
@@ -57,17 +124,32 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 	)
 }
 
-func (o *options) PreRunE(_ *cobra.Command, _ []string) error {
+func (o *options) PreRunE(cmd *cobra.Command, _ []string) error {
 	// Setup logging
-	level := slog.LevelInfo
+	logOptions, err := o.loggingOptions(cmd)
+	if err != nil {
+		return err
+	}
+	if err := logging.Configure(logOptions, os.Stderr); err != nil {
+		return err
+	}
 
-	if o.verbose {
-		level = slog.LevelDebug
+	// Setup progress events for GUI frontends
+	if err := progress.ValidateFormat(o.progressFormat); err != nil {
+		return err
+	}
+	if o.progressFormat == progress.FormatJSON {
+		progress.Enable(os.Stderr)
 	}
 
-	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-		Level: level,
-	})))
+	// Use the repository's configured defaults for flags that are not set
+	config, err := repository.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if err := common.ApplyConfigDefaults(cmd, config); err != nil {
+		return err
+	}
 
 	// Start profiling if flag is set
 	if o.profile {
@@ -77,11 +159,71 @@ func (o *options) PreRunE(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
+// loggingOptions returns the logging options set in the Git config, overridden
+// by any logging flags that are set.
+func (o *options) loggingOptions(cmd *cobra.Command) (*logging.Options, error) {
+	logOptions := logging.DefaultOptions()
+
+	// The Git config may not be readable, such as when no config is set or
+	// Git is not installed, in which case the defaults are used
+	gitConfig, err := gitinterface.GetConfig()
+	if err == nil {
+		logOptions, err = logging.LoadOptionsFromConfig(gitConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	flags := cmd.Flags()
+
+	if flags.Changed("log-format") {
+		logOptions.Format = o.logFormat
+	}
+
+	if flags.Changed("log-level") {
+		if err := logOptions.Level.UnmarshalText([]byte(o.logLevel)); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.verbose {
+		logOptions.Level = slog.LevelDebug
+	}
+
+	if flags.Changed("log-module-level") {
+		moduleLevels, err := logging.ParseModuleLevels(o.logModuleLevels)
+		if err != nil {
+			return nil, err
+		}
+		for module, level := range moduleLevels {
+			logOptions.ModuleLevels[module] = level
+		}
+	}
+
+	if flags.Changed("log-file") {
+		logOptions.File = o.logFile
+	}
+
+	return logOptions, nil
+}
+
+// longDescription documents gittuf's exit codes, so that scripts can branch on
+// the class of failure.
+func longDescription() string {
+	description := "A security layer for Git repositories, powered by TUF\n\nExit codes:\n"
+	for _, exitCode := range exitcodes.Descriptions {
+		description += fmt.Sprintf("  %d  %s\n", exitCode.Code, exitCode.Description)
+	}
+
+	return description
+}
+
 func New() *cobra.Command {
 	o := &options{}
 	cmd := &cobra.Command{
 		Use:               "gittuf",
 		Short:             "A security layer for Git repositories, powered by TUF",
+		Long:              longDescription(),
 		SilenceUsage:      true,
 		DisableAutoGenTag: true,
 		PersistentPreRunE: o.PreRunE,
@@ -90,12 +232,32 @@ func New() *cobra.Command {
 	o.AddFlags(cmd)
 
 	cmd.AddCommand(addhooks.New())
+	cmd.AddCommand(attest.New())
+	cmd.AddCommand(channel.New())
+	cmd.AddCommand(checkout.New())
 	cmd.AddCommand(clone.New())
+	cmd.AddCommand(compat.New())
+	cmd.AddCommand(daemon.New())
 	cmd.AddCommand(dev.New())
+	cmd.AddCommand(fsck.New())
+	cmd.AddCommand(gc.New())
+	cmd.AddCommand(github.New())
+	cmd.AddCommand(hook.New())
+	cmd.AddCommand(identity.New())
+	cmd.AddCommand(incident.New())
+	cmd.AddCommand(key.New())
+	cmd.AddCommand(network.New())
+	cmd.AddCommand(org.New())
 	cmd.AddCommand(trust.New())
 	cmd.AddCommand(policy.New())
+	cmd.AddCommand(pruneunreachable.New())
 	cmd.AddCommand(rsl.New())
+	cmd.AddCommand(serve.New())
+	cmd.AddCommand(stats.New())
+	cmd.AddCommand(status.New())
 	cmd.AddCommand(verifycommit.New())
+	cmd.AddCommand(verifygithubrelease.New())
+	cmd.AddCommand(verifyreceive.New())
 	cmd.AddCommand(verifyref.New())
 	cmd.AddCommand(verifytag.New())
 	cmd.AddCommand(version.New())
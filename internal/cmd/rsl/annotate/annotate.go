@@ -3,14 +3,22 @@
 package annotate
 
 import (
+	"errors"
+
 	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
+var ErrInvalidArguments = errors.New("either RSL entry IDs or a range must be specified, but not both")
+
 type options struct {
-	skip    bool
-	message string
+	skip       bool
+	message    string
+	rangeRef   string
+	rangeStart string
+	rangeEnd   string
+	tickets    []string
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -30,15 +38,66 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"annotation message",
 	)
 	cmd.MarkFlagRequired("message") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.rangeRef,
+		"range-ref",
+		"",
+		"annotate all RSL entries for this ref between --range-start and --range-end (inclusive) instead of specified entries",
+	)
+
+	cmd.Flags().StringVar(
+		&o.rangeStart,
+		"range-start",
+		"",
+		"ID of the first RSL entry in the annotated range",
+	)
+
+	cmd.Flags().StringVar(
+		&o.rangeEnd,
+		"range-end",
+		"",
+		"ID of the last RSL entry in the annotated range",
+	)
+	cmd.MarkFlagsRequiredTogether("range-ref", "range-start", "range-end")
+
+	cmd.Flags().StringArrayVar(
+		&o.tickets,
+		"ticket",
+		[]string{},
+		"URI of an issue or ticket relevant to the annotated entries, such as https://github.com/gittuf/gittuf/issues/1",
+	)
 }
 
-func (o *options) Run(_ *cobra.Command, args []string) error {
+func (o *options) Run(cmd *cobra.Command, args []string) error {
 	repo, err := repository.LoadRepository()
 	if err != nil {
 		return err
 	}
 
-	return repo.RecordRSLAnnotation(args, o.skip, o.message, true)
+	if len(o.rangeRef) != 0 {
+		if len(args) != 0 {
+			return ErrInvalidArguments
+		}
+
+		if err := repo.RecordRSLAnnotationForRange(o.rangeRef, o.rangeStart, o.rangeEnd, o.skip, o.message, o.tickets, true); err != nil {
+			return err
+		}
+
+		repo.MirrorRSL(cmd.Context())
+		return nil
+	}
+
+	if len(args) == 0 {
+		return ErrInvalidArguments
+	}
+
+	if err := repo.RecordRSLAnnotation(args, o.skip, o.message, o.tickets, true); err != nil {
+		return err
+	}
+
+	repo.MirrorRSL(cmd.Context())
+	return nil
 }
 
 func New() *cobra.Command {
@@ -46,7 +105,6 @@ func New() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "annotate",
 		Short:             "Annotate prior RSL entries",
-		Args:              cobra.MinimumNArgs(1),
 		PreRunE:           common.CheckIfSigningViable,
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
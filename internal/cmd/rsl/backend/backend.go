@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package backend
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/rsl/backend/sync"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "backend",
+		Short:             "Tools for managing backends the RSL is mirrored into",
+		Long:              "Tools for managing append-only logs the RSL is mirrored into, in addition to the RSL ref. A backend is either a local log file or a remote log served over HTTP, set using the --backend flag or the gittuf.rsl.backend Git config. When the backend is configured, RSL entries recorded using gittuf are mirrored into it automatically.",
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(sync.New())
+
+	return cmd
+}
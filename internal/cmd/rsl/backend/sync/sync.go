@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/progress"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	backend string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.backend,
+		"backend",
+		"",
+		fmt.Sprintf("location of the backend, either the path to a local log file or the URL of a remote log, overrides %s", repository.RSLBackendConfigKey),
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	location := o.backend
+	if location == "" {
+		config, err := repository.LoadConfig()
+		if err != nil {
+			return err
+		}
+		if config.RSLBackend == "" {
+			return fmt.Errorf("backend not specified and %s is not set", repository.RSLBackendConfigKey)
+		}
+		location = config.RSLBackend
+	}
+
+	backend, err := rsl.NewBackend(location)
+	if err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	finish := progress.Start(progress.OperationSync)
+	appended, err := repo.SyncRSLBackend(cmd.Context(), backend)
+	finish(err)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Appended %d entries to RSL backend '%s'\n", appended, location)
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "sync",
+		Short:             "Reconcile the RSL backend with the RSL",
+		Long:              "The 'sync' command appends the RSL entries missing from the backend. The backend's existing records must match the entries at the start of the RSL, otherwise the command fails as the backend or the RSL has been tampered with, or the local RSL is behind the backend.",
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
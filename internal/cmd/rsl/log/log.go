@@ -3,16 +3,28 @@
 package log
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/display"
 	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/spf13/cobra"
 )
 
 type options struct {
-	page     bool
-	filePath string
+	page      bool
+	filePath  string
+	refNames  []string
+	since     string
+	until     string
+	signedBy  string
+	skipped   bool
+	unskipped bool
+	offset    int
+	limit     int
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -29,15 +41,97 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"",
 		"write log to file at specified path",
 	)
+
+	cmd.Flags().StringArrayVar(
+		&o.refNames,
+		"ref",
+		[]string{},
+		"only show entries for the ref, can be repeated",
+	)
+
+	cmd.Flags().StringVar(
+		&o.since,
+		"since",
+		"",
+		"only show entries recorded at or after the RFC 3339 timestamp",
+	)
+
+	cmd.Flags().StringVar(
+		&o.until,
+		"until",
+		"",
+		"only show entries recorded at or before the RFC 3339 timestamp",
+	)
+
+	cmd.Flags().StringVar(
+		&o.signedBy,
+		"signed-by",
+		"",
+		"only show entries signed using the key with the specified ID",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.skipped,
+		"skipped",
+		false,
+		"only show entries that are skipped",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.unskipped,
+		"unskipped",
+		false,
+		"only show entries that are not skipped",
+	)
+	cmd.MarkFlagsMutuallyExclusive("skipped", "unskipped")
+
+	cmd.Flags().IntVar(
+		&o.offset,
+		"offset",
+		0,
+		"number of matching entries, from the latest entry, to leave out",
+	)
+
+	cmd.Flags().IntVar(
+		&o.limit,
+		"limit",
+		0,
+		"maximum number of entries to show, all entries are shown if not set",
+	)
 }
 
-func (o *options) Run(_ *cobra.Command, _ []string) error {
-	repo, err := repository.LoadRepository()
-	if err != nil {
-		return err
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	logOptions := &repository.RSLEntryLogOptions{
+		Query: rsl.Query{
+			RefNames: o.refNames,
+		},
+		SignedBy: o.signedBy,
+		Offset:   o.offset,
+		Limit:    o.limit,
 	}
 
-	entries, annotationMap, err := repository.GetRSLEntryLog(repo)
+	var err error
+	if o.since != "" {
+		logOptions.Since, err = time.Parse(time.RFC3339, o.since)
+		if err != nil {
+			return fmt.Errorf("invalid since timestamp: %w", err)
+		}
+	}
+	if o.until != "" {
+		logOptions.Until, err = time.Parse(time.RFC3339, o.until)
+		if err != nil {
+			return fmt.Errorf("invalid until timestamp: %w", err)
+		}
+	}
+
+	switch {
+	case o.skipped:
+		logOptions.Skipped = rsl.SkipFilterSkipped
+	case o.unskipped:
+		logOptions.Skipped = rsl.SkipFilterUnskipped
+	}
+
+	repo, err := repository.LoadRepository()
 	if err != nil {
 		return err
 	}
@@ -51,18 +145,31 @@ func (o *options) Run(_ *cobra.Command, _ []string) error {
 		o.page = false // override page since we're not writing to stdout
 	}
 
-	outputContents := display.PrepareRSLLogOutput(entries, annotationMap)
 	writer := display.NewDisplayWriter(output, o.page)
 
-	_, err = writer.Write([]byte(outputContents))
-	return err
+	isFirstEntry := true
+	err = repository.QueryRSLEntryLog(cmd.Context(), repo, logOptions, func(entry *rsl.ReferenceEntry, annotations []*rsl.AnnotationEntry) error {
+		outputContents := display.PrepareRSLLogEntryOutput(entry, annotations)
+		if !isFirstEntry {
+			outputContents = "\n" + outputContents
+		}
+		isFirstEntry = false
+
+		_, err := writer.Write([]byte(outputContents))
+		return err
+	})
+
+	return errors.Join(err, writer.Close())
 }
 
 func New() *cobra.Command {
 	o := &options{}
 	cmd := &cobra.Command{
-		Use:               "log",
-		Short:             "Display the Reference State Log",
+		Use:   "log",
+		Short: "Display the Reference State Log",
+		Long: `This command displays the reference entries in the Reference State Log (RSL), from the latest entry to the first entry, with the annotations that apply to each entry shown inline.
+
+The entries shown can be filtered by ref, by the time they were recorded at, by whether they are skipped, and by the key used to sign them. Refs that are not fully qualified match both the branch and the tag with the name. The key specified using "--signed-by" must be present in a gittuf policy in the repository's history. The "--offset" and "--limit" flags can be used to page through the matching entries.`,
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
 	}
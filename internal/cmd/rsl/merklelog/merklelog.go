@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package merklelog
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/rsl/merklelog/proveconsistency"
+	"github.com/gittuf/gittuf/internal/cmd/rsl/merklelog/proveinclusion"
+	"github.com/gittuf/gittuf/internal/cmd/rsl/merklelog/treehead"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "merkle-log",
+		Short:             "Tools to export the RSL as a Certificate Transparency style Merkle log",
+		Long:              "Tools to export the RSL as a Certificate Transparency style Merkle log (RFC 6962). The log has one leaf per RSL entry, allowing third-party monitors to check that the RSL is append-only and that specific entries are in it without fetching the entire RSL.",
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(proveconsistency.New())
+	cmd.AddCommand(proveinclusion.New())
+	cmd.AddCommand(treehead.New())
+
+	return cmd
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package proveconsistency
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	treeSize uint64
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64Var(
+		&o.treeSize,
+		"tree-size",
+		0,
+		"size of the newer Merkle log, defaults to the current size",
+	)
+}
+
+func (o *options) Run(_ *cobra.Command, args []string) error {
+	oldTreeSize, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	proof, err := repo.GetRSLConsistencyProof(oldTreeSize, o.treeSize)
+	if err != nil {
+		return err
+	}
+
+	proofBytes, err := json.MarshalIndent(proof, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(proofBytes))
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "prove-consistency <old-tree-size>",
+		Short:             "Print a proof that an older RSL Merkle log is a prefix of a newer one",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package proveinclusion
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	treeSize uint64
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64Var(
+		&o.treeSize,
+		"tree-size",
+		0,
+		"size of the Merkle log to prove inclusion in, defaults to the current size",
+	)
+}
+
+func (o *options) Run(_ *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	proof, err := repo.GetRSLInclusionProof(args[0], o.treeSize)
+	if err != nil {
+		return err
+	}
+
+	proofBytes, err := json.MarshalIndent(proof, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(proofBytes))
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "prove-inclusion <entry-id>",
+		Short:             "Print a proof that an RSL entry is included in the RSL's Merkle log",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package treehead
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	signingKey string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key to use for signing the tree head",
+	)
+	cmd.MarkFlagRequired("signing-key") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.signingKey)
+	if err != nil {
+		return err
+	}
+
+	env, err := repo.SignRSLTreeHead(cmd.Context(), signer)
+	if err != nil {
+		return err
+	}
+
+	envBytes, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(envBytes))
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "tree-head",
+		Short:             "Sign and print the tree head of the RSL's Merkle log",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
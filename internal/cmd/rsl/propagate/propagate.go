@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package propagate
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	if err := repo.PropagateFromUpstream(cmd.Context(), args[0], true); err != nil {
+		return err
+	}
+
+	repo.MirrorRSL(cmd.Context())
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "propagate <upstream-remote>",
+		Short:             "Record the latest state of an upstream repository's RSL in the RSL",
+		Long:              "The 'propagate' command fetches the RSL of the upstream repository configured as the specified remote and records a propagation entry referencing its latest entry. This is used by mirrors and forks to indicate they have incorporated the upstream's changes, which can be checked using 'gittuf rsl verify-propagation'.",
+		Args:              cobra.ExactArgs(1),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
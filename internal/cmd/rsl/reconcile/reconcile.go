@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reconcile
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	strategy string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.strategy,
+		"strategy",
+		repository.RSLReconcileStrategyAbort,
+		fmt.Sprintf("how diverged RSLs are reconciled, one of %s and %s", repository.RSLReconcileStrategyLocalRebase, repository.RSLReconcileStrategyAbort),
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	remote, err := common.RemoteFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	result, err := repo.ReconcileRSL(cmd.Context(), remote, o.strategy, true)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !result.Updated:
+		fmt.Fprintln(cmd.OutOrStdout(), "local RSL is up to date")
+	case !result.Diverged:
+		fmt.Fprintln(cmd.OutOrStdout(), "fast-forwarded local RSL to remote RSL")
+	default:
+		for _, entry := range result.Reconciled {
+			fmt.Fprintf(cmd.OutOrStdout(), "re-recorded entry %s as %s\n", entry.OriginalID.String(), entry.ID.String())
+		}
+	}
+
+	if result.Updated {
+		repo.MirrorRSL(cmd.Context())
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "reconcile [remote]",
+		Short: "Reconcile the local RSL with the specified remote's RSL",
+		Long: `This command fetches the remote's RSL and updates the local RSL to include it. If the local RSL is behind, it is fast-forwarded.
+
+If the local and remote RSLs have diverged, --strategy determines how they are reconciled. With "local-rebase", the entries only in the local RSL are recorded again, in order, atop the remote RSL, and annotations are updated to refer to the new entries. The new entries are signed using the configured signing key. With "abort", the default, the local RSL is left unchanged and an error is returned.
+
+The reconciled RSL can then be pushed using "gittuf rsl remote push".`,
+		Args:              cobra.MaximumNArgs(1),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -3,22 +3,94 @@
 package record
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
 	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
-type options struct{}
+type options struct {
+	changedPaths bool
+	tickets      []string
+	submodules   bool
+	message      string
+	template     string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&o.changedPaths,
+		"changed-paths",
+		false,
+		"record the top-level paths changed since the previous entry for the reference",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&o.tickets,
+		"ticket",
+		[]string{},
+		"URI of an issue or ticket tracking the change, such as https://github.com/gittuf/gittuf/issues/1",
+	)
 
-func (o *options) AddFlags(_ *cobra.Command) {}
+	cmd.Flags().BoolVar(
+		&o.submodules,
+		"with-submodules",
+		false,
+		"record the commits of the submodules in the reference's target, so they can be verified using verify-ref --with-submodules",
+	)
+
+	cmd.Flags().StringVarP(
+		&o.message,
+		"message",
+		"m",
+		"",
+		"message stored in the entry, such as the reason for the change or a link to its approval",
+	)
+
+	cmd.Flags().StringVar(
+		&o.template,
+		"commit-message-template",
+		"",
+		"path to a Go text/template file used to create the message stored in the entry, executed with the entry's RefName, TargetID, and Tickets",
+	)
+
+	cmd.MarkFlagsMutuallyExclusive("message", "commit-message-template")
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	recordOptions := &repository.RecordRSLEntryOptions{ChangedPaths: o.changedPaths, Tickets: o.tickets, Submodules: o.submodules, Message: o.message}
+	if o.template != "" {
+		templateBytes, err := os.ReadFile(o.template)
+		if err != nil {
+			return err
+		}
+		recordOptions.MessageTemplate = string(templateBytes)
+	}
 
-func (o *options) Run(_ *cobra.Command, args []string) error {
 	repo, err := repository.LoadRepository()
 	if err != nil {
 		return err
 	}
 
-	return repo.RecordRSLEntryForReference(args[0], true)
+	// Warn upfront if the entry will fail verification due to the signing key
+	if err := repo.CheckSigningKeyForRef(cmd.Context(), args[0]); err != nil {
+		if errors.Is(err, repository.ErrSigningKeyNotTrustedForRef) {
+			slog.Warn(err.Error())
+		} else {
+			slog.Debug(fmt.Sprintf("Unable to check signing key against policy: %s", err.Error()))
+		}
+	}
+
+	if err := repo.RecordRSLEntryForReferenceWithOptions(cmd.Context(), args[0], recordOptions, true); err != nil {
+		return err
+	}
+
+	repo.MirrorRSL(cmd.Context())
+	return nil
 }
 
 func New() *cobra.Command {
@@ -3,6 +3,8 @@
 package push
 
 import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/progress"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
@@ -11,20 +13,29 @@ type options struct {
 }
 
 func (o *options) Run(cmd *cobra.Command, args []string) error {
+	remote, err := common.RemoteFromArgs(args)
+	if err != nil {
+		return err
+	}
+
 	repo, err := repository.LoadRepository()
 	if err != nil {
 		return err
 	}
 
-	return repo.PushRSL(cmd.Context(), args[0])
+	finish := progress.Start(progress.OperationSync)
+	err = repo.PushRSL(cmd.Context(), remote)
+	finish(err)
+
+	return err
 }
 
 func New() *cobra.Command {
 	o := &options{}
 	cmd := &cobra.Command{
-		Use:               "push <remote>",
+		Use:               "push [remote]",
 		Short:             "Push RSL to the specified remote",
-		Args:              cobra.ExactArgs(1),
+		Args:              cobra.MaximumNArgs(1),
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
 	}
@@ -3,10 +3,19 @@
 package rsl
 
 import (
+	"fmt"
+
 	"github.com/gittuf/gittuf/internal/cmd/rsl/annotate"
+	"github.com/gittuf/gittuf/internal/cmd/rsl/backend"
 	"github.com/gittuf/gittuf/internal/cmd/rsl/log"
+	"github.com/gittuf/gittuf/internal/cmd/rsl/merklelog"
+	"github.com/gittuf/gittuf/internal/cmd/rsl/propagate"
+	"github.com/gittuf/gittuf/internal/cmd/rsl/reconcile"
 	"github.com/gittuf/gittuf/internal/cmd/rsl/record"
 	"github.com/gittuf/gittuf/internal/cmd/rsl/remote"
+	"github.com/gittuf/gittuf/internal/cmd/rsl/verifypropagation"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/piv"
 	"github.com/spf13/cobra"
 )
 
@@ -14,13 +23,19 @@ func New() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "rsl",
 		Short:             "Tools to manage the repository's reference state log",
+		Long:              fmt.Sprintf("Tools to manage the repository's reference state log. RSL entries are signed using the signing method in the user's Git config. To sign them using the key in a PIV slot of a hardware token, set %s to the slot, such as piv:slot-9c. The PIN for the token can be set using %s.", gitinterface.PIVSigningKeyConfigKey, piv.PINEnvKey),
 		DisableAutoGenTag: true,
 	}
 
 	cmd.AddCommand(annotate.New())
+	cmd.AddCommand(backend.New())
 	cmd.AddCommand(log.New())
+	cmd.AddCommand(merklelog.New())
+	cmd.AddCommand(propagate.New())
+	cmd.AddCommand(reconcile.New())
 	cmd.AddCommand(record.New())
 	cmd.AddCommand(remote.New())
+	cmd.AddCommand(verifypropagation.New())
 
 	return cmd
 }
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verifypropagation
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	refNames    []string
+	enforcement string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(
+		&o.refNames,
+		"ref",
+		[]string{},
+		"ref that must not diverge from upstream (default all refs recorded in both RSLs)",
+	)
+
+	common.AddEnforcementFlag(cmd, &o.enforcement)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if err := repository.ValidateVerificationEnforcement(o.enforcement); err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	err = repo.VerifyPropagation(cmd.Context(), args[0], o.refNames)
+	return common.ApplyEnforcement(o.enforcement, err)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "verify-propagation <upstream-remote>",
+		Short:             "Verify the repository has not diverged from an upstream repository",
+		Long:              "The 'verify-propagation' command checks that the upstream RSL entries recorded in the RSL's propagation entries are still present in the RSL of the upstream repository configured as the specified remote, and that the refs recorded in the RSL include their upstream states as of the latest propagation entry.",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
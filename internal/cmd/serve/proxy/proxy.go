@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/proxy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+type options struct {
+	remoteName      string
+	listenAddress   string
+	refreshInterval time.Duration
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.remoteName,
+		"remote",
+		gitinterface.DefaultRemoteName,
+		"remote for the upstream whose verified refs are served",
+	)
+
+	cmd.Flags().StringVar(
+		&o.listenAddress,
+		"listen-address",
+		":8080",
+		"address to serve the verified refs on",
+	)
+
+	cmd.Flags().DurationVar(
+		&o.refreshInterval,
+		"refresh-interval",
+		time.Minute,
+		"interval at which the upstream's refs are fetched and verified",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if o.refreshInterval <= 0 {
+		return fmt.Errorf("refresh interval must be positive")
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	gitRepo, err := gitinterface.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	handler, err := proxy.NewHandler(repo, &proxy.Options{
+		GitDir:     gitRepo.GetGitDir(),
+		RemoteName: o.remoteName,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The verified refs must be available before the first request is
+	// served
+	if err := refresh(ctx, handler); err != nil {
+		return err
+	}
+	go refreshPeriodically(ctx, handler, o.refreshInterval)
+
+	server := &http.Server{
+		Addr:              o.listenAddress,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx) //nolint:errcheck
+	}()
+
+	slog.Info(fmt.Sprintf("Serving verified refs of '%s' on '%s'...", o.remoteName, o.listenAddress))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// refreshPeriodically refreshes the served refs at the interval until the
+// context is canceled. The previously verified refs continue to be served if a
+// refresh fails.
+func refreshPeriodically(ctx context.Context, handler *proxy.Handler, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := refresh(ctx, handler); err != nil {
+				slog.Error(fmt.Sprintf("Unable to refresh verified refs: %s", err.Error()))
+			}
+		}
+	}
+}
+
+func refresh(ctx context.Context, handler *proxy.Handler) error {
+	statuses, err := handler.Refresh(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		if status.Verified {
+			slog.Info(fmt.Sprintf("Serving '%s' at '%s'", status.RefName, status.TargetID.String()))
+		} else {
+			slog.Warn(fmt.Sprintf("Not serving '%s' at '%s': %s", status.RefName, status.TargetID.String(), status.Err.Error()))
+		}
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "proxy",
+		Short: "Serve the verified refs of an upstream remote to Git clients",
+		Long: `The 'proxy' command runs a read-only Git HTTP server that fronts an upstream remote, so that consumers such as build farms can fetch exclusively verified states. The upstream's RSL, policy, and attestations, and the branches and tags it advertises, are fetched into the repository periodically. A branch or tag is served only if the upstream's target for it is recorded in its latest RSL entry, and that entry passes verification. A ref that fails verification continues to be served at its last verified target. The upstream's RSL is fetched fast-forward only, so the served refs are not updated if it has diverged.
+
+The verified refs are recorded in the Git namespace 'gittuf-verified' of the repository, and served using 'git http-backend' at every URL on the server. Only fetches using the smart HTTP protocol version 0 are supported, so that objects which are not reachable from the verified refs cannot be requested. The proxy runs until it is interrupted.`,
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
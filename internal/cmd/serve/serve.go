@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package serve
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/serve/proxy"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "serve",
+		Short:             "Serve the repository over the network",
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(proxy.New())
+
+	return cmd
+}
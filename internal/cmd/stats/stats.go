@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package stats
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/stats/storage"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "stats",
+		Short:             "Report statistics about the repository's gittuf metadata",
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(storage.New())
+
+	return cmd
+}
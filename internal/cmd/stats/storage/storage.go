@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+const defaultPeriod = 7 * 24 * time.Hour
+
+type options struct {
+	period time.Duration
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(
+		&o.period,
+		"period",
+		defaultPeriod,
+		"length of the periods growth is reported for",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	stats, err := repo.GetStorageStats(o.period)
+	if err != nil {
+		return err
+	}
+
+	lines := []string{"Storage:"}
+	for _, usage := range stats.Usage {
+		lines = append(lines, fmt.Sprintf("    %s: %s", usage.Category, describeUsage(usage)))
+	}
+	lines = append(lines, fmt.Sprintf("    total: %s", describeUsage(stats.Total)))
+
+	lines = append(lines, fmt.Sprintf("Growth per %s:", o.period.String()))
+	if len(stats.Growth) == 0 {
+		lines = append(lines, "    RSL has no entries")
+	}
+	for _, growth := range stats.Growth {
+		lines = append(lines, fmt.Sprintf("    %s: %d RSL entries, +%s", growth.Start.Format(time.RFC3339), growth.Entries, formatSize(growth.Size)))
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), strings.Join(lines, "\n"))
+	return nil
+}
+
+func describeUsage(usage *repository.StorageUsage) string {
+	return fmt.Sprintf("%d commits, %d objects, %s", usage.Commits, usage.Objects, formatSize(usage.Size))
+}
+
+// formatSize formats a size in bytes using binary units.
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "storage",
+		Short:             "Report how much of the repository's size is gittuf metadata",
+		Long:              "The 'storage' command reports the number and uncompressed size of the objects reachable from the RSL, the policy and policy staging refs, the attestations ref, the refs tracking remotes' gittuf metadata, and other refs in the gittuf namespace such as the verification cache. Objects shared between categories are counted in each, and once in the total. Growth is reported for each period in which RSL entries were recorded, counting the entries and the new policy and attestations states they record, to help plan compaction with 'gittuf gc' and packing with 'git gc'.",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
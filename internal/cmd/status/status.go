@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	status, err := repo.GetStatus(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	lines := []string{"RSL:"}
+	if status.RSL.LatestEntryID.IsZero() {
+		lines = append(lines, "    Local RSL has no entries")
+	} else {
+		lines = append(lines, fmt.Sprintf("    Latest local entry: %s", status.RSL.LatestEntryID.String()))
+	}
+	for _, remote := range status.RSL.Remotes {
+		lines = append(lines, fmt.Sprintf("    Remote '%s': %s", remote.Name, describeRemoteRSL(remote)))
+	}
+
+	if ref := status.CheckedOutRef; ref != nil {
+		lines = append(lines, fmt.Sprintf("Checked out ref %s:", ref.Name))
+		switch {
+		case ref.IsRecorded():
+			lines = append(lines, fmt.Sprintf("    Tip %s is recorded in the RSL", ref.TipID.String()))
+		case ref.LatestEntryTargetID.IsZero():
+			lines = append(lines, fmt.Sprintf("    Tip %s is not recorded in the RSL, ref has no RSL entries", ref.TipID.String()))
+		default:
+			lines = append(lines, fmt.Sprintf("    Tip %s is not recorded in the RSL, latest entry is for %s", ref.TipID.String(), ref.LatestEntryTargetID.String()))
+		}
+
+		switch {
+		case ref.SigningKeyID == "":
+			lines = append(lines, "    Unable to identify configured signing key")
+		case ref.SigningKeyErr != nil:
+			lines = append(lines, fmt.Sprintf("    Signing key is not authorized: %s", ref.SigningKeyErr.Error()))
+		default:
+			lines = append(lines, fmt.Sprintf("    Signing key %s is authorized", ref.SigningKeyID))
+		}
+
+		if authorization := ref.PendingAuthorization; authorization != nil {
+			signedBy := "not signed by your key"
+			if authorization.SignedBySigningKey {
+				signedBy = "signed by your key"
+			}
+			lines = append(lines, fmt.Sprintf("    Reference authorization for unrecorded changes has %d signature(s), %s", len(authorization.SignerKeyIDs), signedBy))
+		}
+	}
+
+	lines = append(lines, "Policy:")
+	if status.Policy == nil {
+		lines = append(lines, "    No policy has been applied")
+	} else {
+		now := time.Now()
+		for _, metadata := range status.Policy.Metadata {
+			expiry := "expires"
+			if metadata.Expires.Before(now) {
+				expiry = "EXPIRED"
+			}
+			lines = append(lines, fmt.Sprintf("    Metadata for '%s' %s: %s", metadata.RoleName, expiry, metadata.Expires.Format(time.RFC3339)))
+		}
+	}
+
+	fmt.Println(strings.Join(lines, "\n"))
+	return nil
+}
+
+func describeRemoteRSL(remote *repository.RemoteRSLStatus) string {
+	switch {
+	case remote.TrackerEntryID.IsZero():
+		return "RSL not fetched"
+	case remote.LocalAhead && remote.RemoteAhead:
+		return "local and remote RSLs have diverged"
+	case remote.LocalAhead:
+		return "local RSL is ahead"
+	case remote.RemoteAhead:
+		return "remote RSL is ahead"
+	default:
+		return "up to date"
+	}
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "status",
+		Short:             "Summarize the repository's trust state",
+		Long:              "Summarize the repository's trust state: the local RSL compared with each remote's RSL as of the last fetch, whether the checked out branch is recorded in the RSL, whether the configured signing key is authorized for it, any reference authorization for its unrecorded changes, and when the policy metadata expires. Remotes are not contacted, fetch the RSL first for an up to date comparison.",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package addobserverkey
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p           *persistent.Options
+	observerKey string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.observerKey,
+		"observer-key",
+		"",
+		"observer key to add to root of trust",
+	)
+	cmd.MarkFlagRequired("observer-key") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	observerKey, err := common.LoadPublicKey(o.observerKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.AddObserverKey(cmd.Context(), signer, observerKey, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "add-observer-key",
+		Short:             "Add observer key to gittuf root of trust",
+		Long:              `This command allows users to add a key that may only sign verification reports for the repository, such as the key of an automation account. Observer keys are never trusted for changes to the RSL or the policy. Note that authorized keys can be specified from disk, from the GPG keyring using the "gpg:<fingerprint>" format, as a Sigstore identity as "fulcio:<identity>::<issuer>", or as trusted X.509 certificates, optionally restricted to an identity, as "x509:<certificates-path>[::<identity>]".`,
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
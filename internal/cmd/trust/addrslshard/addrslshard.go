@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package addrslshard
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	name       string
+	namespaces []string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.name,
+		"name",
+		"",
+		"name of the RSL shard",
+	)
+	cmd.MarkFlagRequired("name") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.namespaces,
+		"namespace",
+		[]string{},
+		"ref namespace whose entries are recorded in the shard, such as 'refs/heads/team-a/', can be repeated",
+	)
+	cmd.MarkFlagRequired("namespace") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.AddRSLShard(cmd.Context(), signer, o.name, o.namespaces, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "add-rsl-shard",
+		Short: "Record the RSL entries for ref namespaces in an RSL shard",
+		Long: `This command records the RSL entries for refs in the specified namespaces in an RSL shard, rather than the RSL itself. Each shard is a separate chain of entries stored in "refs/gittuf/rsl/<name>". When an entry is added to a shard, an index entry recording the shard's new tip is added to the RSL, so the RSL continues to order all changes and to record the policy in effect for them. Each entry is verified against the policy in effect when it was recorded, so entries recorded in the RSL before the shard was added remain valid, and entries recorded in the RSL afterwards are rejected. Verification, recording, and syncing the RSL read and update the shards as needed.
+
+If the shard exists, the namespaces are added to it. A namespace may only be recorded in one shard, and the gittuf namespace cannot be sharded.`,
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package allowexpiredgpgkeys
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p       *persistent.Options
+	disable bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&o.disable,
+		"disable",
+		false,
+		"stop trusting signatures made using GPG keys that have since expired",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetAllowExpiredGPGKeys(cmd.Context(), signer, !o.disable, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "allow-expired-gpg-keys",
+		Short: "Trust signatures made using GPG keys before they expired",
+		Long: `This command allows signatures made using GPG keys in the gittuf policy to be trusted after the keys expire, so that older history does not fail verification as keys age out. A signature made using an expired key is trusted if the key was valid when the signed commit or tag was recorded in the RSL, and the signature was created no later than that. As the timestamp of an RSL entry is set by its signer, the time an entry was recorded is taken from the earliest later RSL entry signed using a key in the policy, or the current time if there is no such entry. Revoked keys are never trusted.
+
+By default, signatures made using expired GPG keys are not trusted. Use "--disable" to restore this behavior.`,
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
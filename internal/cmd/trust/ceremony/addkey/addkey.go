@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package addkey
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	rootKey string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.rootKey,
+		"root-key",
+		"",
+		"public root key to add to the ceremony",
+	)
+	cmd.MarkFlagRequired("root-key") //nolint:errcheck
+}
+
+func (o *options) Run(_ *cobra.Command, args []string) error {
+	ceremony, err := repository.LoadRootCeremony(args[0])
+	if err != nil {
+		return err
+	}
+
+	rootKey, err := common.LoadPublicKey(o.rootKey)
+	if err != nil {
+		return err
+	}
+
+	if err := ceremony.AddKey(rootKey); err != nil {
+		return err
+	}
+
+	return ceremony.Save(args[0])
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "add-key <ceremony-file>",
+		Short:             "Add a key holder's public root key to the key ceremony",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package assemble
+
+import (
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(_ *cobra.Command, args []string) error {
+	ceremony, err := repository.LoadRootCeremony(args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := ceremony.Assemble(); err != nil {
+		return err
+	}
+
+	return ceremony.Save(args[0])
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "assemble <ceremony-file>",
+		Short:             "Assemble the root metadata from the keys collected in the key ceremony",
+		Long:              "Assemble the unsigned root metadata trusting the keys collected in the key ceremony with its threshold. No more keys can be added once the root metadata is assembled.",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
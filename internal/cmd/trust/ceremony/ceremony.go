@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ceremony
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/trust/ceremony/addkey"
+	"github.com/gittuf/gittuf/internal/cmd/trust/ceremony/assemble"
+	"github.com/gittuf/gittuf/internal/cmd/trust/ceremony/sign"
+	"github.com/gittuf/gittuf/internal/cmd/trust/ceremony/start"
+	"github.com/gittuf/gittuf/internal/cmd/trust/ceremony/verify"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/spf13/cobra"
+)
+
+func New(persistent *persistent.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ceremony",
+		Short: "Tools for creating the root of trust in a key ceremony",
+		Long: `Tools for creating the root of trust in a key ceremony where the root keys are held on separate, possibly offline, machines. The ceremony is recorded in a file passed between the machines, which only ever contains public keys and signatures:
+
+1. Start the ceremony with the root threshold using 'start'.
+2. On each key holder's machine, add their public key using 'add-key'.
+3. Once enough keys are collected, assemble the root metadata using 'assemble'.
+4. On each key holder's machine, sign the root metadata using 'sign'.
+5. Verify the signed root metadata and review the transcript using 'verify'.
+6. Publish the root of trust in the repository using 'gittuf trust init --ceremony'.
+
+Every step is recorded in the ceremony's transcript for audit.`,
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(addkey.New())
+	cmd.AddCommand(assemble.New())
+	cmd.AddCommand(sign.New(persistent))
+	cmd.AddCommand(start.New())
+	cmd.AddCommand(verify.New())
+
+	return cmd
+}
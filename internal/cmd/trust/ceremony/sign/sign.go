@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sign
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p *persistent.Options
+}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if o.p.SigningKey == "" {
+		return fmt.Errorf("required flag \"signing-key\" not set")
+	}
+
+	ceremony, err := repository.LoadRootCeremony(args[0])
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	if err := ceremony.Sign(cmd.Context(), signer); err != nil {
+		return err
+	}
+
+	return ceremony.Save(args[0])
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "sign <ceremony-file>",
+		Short:             "Sign the root metadata assembled in the key ceremony",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
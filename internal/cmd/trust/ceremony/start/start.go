@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package start
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+var ErrCeremonyFileExists = errors.New("ceremony file already exists")
+
+type options struct {
+	threshold int
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(
+		&o.threshold,
+		"threshold",
+		1,
+		"number of root keys that must sign the root metadata",
+	)
+}
+
+func (o *options) Run(_ *cobra.Command, args []string) error {
+	if _, err := os.Stat(args[0]); err == nil {
+		return fmt.Errorf("%w: '%s'", ErrCeremonyFileExists, args[0])
+	}
+
+	ceremony, err := repository.NewRootCeremony(o.threshold)
+	if err != nil {
+		return err
+	}
+
+	return ceremony.Save(args[0])
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "start <ceremony-file>",
+		Short:             "Start a key ceremony for the root of trust",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verify
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	ceremony, err := repository.LoadRootCeremony(args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := ceremony.Verify(cmd.Context()); err != nil {
+		return err
+	}
+
+	fmt.Printf("Root metadata is signed by %d of %d keys with threshold %d\n", len(ceremony.RootEnvelope.Signatures), len(ceremony.Keys), ceremony.Threshold)
+	fmt.Println("Transcript:")
+	for _, entry := range ceremony.Transcript {
+		line := fmt.Sprintf("    %s %s", entry.Timestamp, entry.Action)
+		if entry.Hostname != "" {
+			line += fmt.Sprintf(" on %s", entry.Hostname)
+		}
+		if entry.KeyID != "" {
+			line += fmt.Sprintf(" with key %s", entry.KeyID)
+		}
+		if entry.PayloadDigest != "" {
+			line += fmt.Sprintf(" (root metadata %s)", entry.PayloadDigest)
+		}
+		fmt.Println(line)
+	}
+
+	return ceremony.Save(args[0])
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "verify <ceremony-file>",
+		Short:             "Verify the root metadata signed in the key ceremony",
+		Long:              "Verify that the key ceremony's transcript is consistent, and that the assembled root metadata trusts exactly the collected keys and is signed by a threshold of them. The transcript is printed for review. The root of trust can only be published after it is verified.",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -3,36 +3,156 @@
 package init
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/spf13/cobra"
 )
 
 type options struct {
-	p *persistent.Options
+	p         *persistent.Options
+	ceremony  string
+	offline   bool
+	bundles   []string
+	rootKeys  common.PublicKeys
+	threshold int
 }
 
-func (o *options) AddFlags(_ *cobra.Command) {}
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.ceremony,
+		"ceremony",
+		"",
+		"initialize root of trust using the root metadata signed in the specified key ceremony file",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.offline,
+		"offline",
+		false,
+		"export a signing bundle with unsigned root metadata to the path specified with --bundle for offline root key holders to sign",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&o.bundles,
+		"bundle",
+		[]string{},
+		"path of signing bundle to export with --offline, or signed signing bundles to initialize root of trust from",
+	)
+
+	cmd.Flags().Var(
+		&o.rootKeys,
+		"root-key",
+		"root key to trust in the exported signing bundle",
+	)
+
+	cmd.Flags().IntVar(
+		&o.threshold,
+		"threshold",
+		1,
+		"threshold of root keys in the exported signing bundle",
+	)
+
+	cmd.MarkFlagsMutuallyExclusive("ceremony", "offline")
+	cmd.MarkFlagsMutuallyExclusive("ceremony", "bundle")
+	cmd.MarkFlagsRequiredTogether("offline", "root-key")
+}
+
+func (o *options) PreRunE(cmd *cobra.Command, args []string) error {
+	if o.offline {
+		// Nothing is signed or committed when exporting the signing bundle
+		return nil
+	}
+
+	if o.ceremony != "" || len(o.bundles) > 0 {
+		// The root metadata is already signed by the ceremony's key holders
+		return common.CheckIfSigningViable(cmd, args)
+	}
+
+	return common.CheckIfSigningViableWithFlag(cmd, args)
+}
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if o.offline {
+		return o.exportSigningBundle()
+	}
+
 	repo, err := repository.LoadRepository()
 	if err != nil {
 		return err
 	}
 
-	keyBytes, err := os.ReadFile(o.p.SigningKey)
+	if o.ceremony != "" {
+		ceremony, err := repository.LoadRootCeremony(o.ceremony)
+		if err != nil {
+			return err
+		}
+
+		if err := repo.InitializeRootFromCeremony(cmd.Context(), ceremony, true); err != nil {
+			return err
+		}
+
+		return ceremony.Save(o.ceremony)
+	}
+
+	if len(o.bundles) > 0 {
+		bundles := []*policy.SigningBundle{}
+		for _, path := range o.bundles {
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			bundle, err := policy.LoadSigningBundle(contents)
+			if err != nil {
+				return err
+			}
+
+			bundles = append(bundles, bundle)
+		}
+
+		return repo.InitializeRootFromSigningBundles(cmd.Context(), bundles, true)
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.InitializeRoot(cmd.Context(), signer, true)
+}
+
+func (o *options) exportSigningBundle() error {
+	if len(o.bundles) != 1 {
+		return fmt.Errorf("exactly one --bundle path must be specified with --offline")
+	}
+
+	rootKeys := []*tuf.Key{}
+	for _, key := range o.rootKeys {
+		rootKey, err := common.LoadPublicKey(key)
+		if err != nil {
+			return err
+		}
+
+		rootKeys = append(rootKeys, rootKey)
+	}
+
+	bundle, err := policy.NewRootSigningBundle(rootKeys, o.threshold)
 	if err != nil {
 		return err
 	}
-	signer, err := common.LoadSigner(keyBytes)
+
+	contents, err := bundle.Marshal()
 	if err != nil {
 		return err
 	}
 
-	return repo.InitializeRoot(cmd.Context(), signer, true)
+	return os.WriteFile(o.bundles[0], contents, 0o644)
 }
 
 func New(persistent *persistent.Options) *cobra.Command {
@@ -40,7 +160,7 @@ func New(persistent *persistent.Options) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "init",
 		Short:             "Initialize gittuf root of trust for repository",
-		PreRunE:           common.CheckIfSigningViableWithFlag,
+		PreRunE:           o.PreRunE,
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
 	}
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pin
+
+import (
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) Run(_ *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	return repo.PinInitialRoot(args[0])
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "pin <root-hash>",
+		Short: "Pin the hash of the initial root of trust metadata",
+		Long: `This command pins the hash of the repository's initial root of trust metadata, obtained out of band, such as from the output of 'gittuf trust show-root-hash' for a trusted copy of the repository. As every root of trust is verified from the initial root of trust, subsequent verifications fail if the repository's root of trust lineage doesn't begin with root metadata matching the pinned hash, instead of trusting the root of trust keys on first use.
+
+If the repository's policy is available, it is checked against the hash immediately. A mismatch always fails verification, regardless of the root pin mode.`,
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
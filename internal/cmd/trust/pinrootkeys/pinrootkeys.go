@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pinrootkeys
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	return repo.PinRootKeys(cmd.Context())
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "pin-root-keys",
+		Short: "Pin the root of trust keys",
+		Long: fmt.Sprintf(`This command pins the root of trust keys of the repository's current policy. The keys are also pinned when the repository is cloned using gittuf. Once the keys are pinned, verifications fail if the root of trust keys change without being rotated from the pinned keys. Set %s to "%s" to only warn instead. Verification never updates the pin.
+
+If no keys are pinned yet, the current keys are trusted on first use, unless the hash of the initial root of trust metadata is pinned using 'gittuf trust pin', in which case the keys are verified from the initial root of trust. If the pinned keys were rotated to the current keys, the pin is updated to the current keys.`, repository.RootPinModeConfigKey, repository.RootPinModeWarn),
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
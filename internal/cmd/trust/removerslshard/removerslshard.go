@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package removerslshard
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p    *persistent.Options
+	name string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.name,
+		"name",
+		"",
+		"name of the RSL shard",
+	)
+	cmd.MarkFlagRequired("name") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.RemoveRSLShard(cmd.Context(), signer, o.name, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "remove-rsl-shard",
+		Short:             "Record the RSL entries for an RSL shard's namespaces in the RSL again",
+		Long:              "This command removes the specified RSL shard, so that the RSL entries for refs in its namespaces are recorded in the RSL itself again. Entries already recorded in the shard remain valid, as each entry is verified against the policy in effect when it was recorded.",
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
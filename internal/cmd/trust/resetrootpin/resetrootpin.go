@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package resetrootpin
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) Run(_ *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	return repo.ResetRootPin()
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "reset-root-pin",
+		Short: "Reset the pinned root of trust keys",
+		Long: fmt.Sprintf(`This command removes the root of trust keys pinned for the repository. The keys of the root of trust are pinned when the repository is cloned or using 'gittuf trust pin-root-keys', and subsequent verifications fail if the root of trust keys change without being rotated from the pinned keys. Set %s to "%s" to only warn instead.
+
+After the pin is reset, verifications don't check the root of trust keys until they're pinned again, when the keys observed are trusted on first use. Only reset the pin after confirming the new root of trust keys out of band.`, repository.RootPinModeConfigKey, repository.RootPinModeWarn),
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
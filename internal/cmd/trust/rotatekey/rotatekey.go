@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rotatekey
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p             *persistent.Options
+	oldKeyID      string
+	newKey        string
+	newSigningKey string
+	oldSigningKey string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.oldKeyID,
+		"old",
+		"",
+		"ID of the key to rotate",
+	)
+	cmd.MarkFlagRequired("old") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.newKey,
+		"new",
+		"",
+		"key to replace the rotated key",
+	)
+	cmd.MarkFlagRequired("new") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.newSigningKey,
+		"new-signing-key",
+		"",
+		"signing key for the new key, used to sign the rotation proof and the metadata signed using the old key",
+	)
+
+	cmd.Flags().StringVar(
+		&o.oldSigningKey,
+		"old-signing-key",
+		"",
+		"signing key for the old key, used to sign the rotation proof if the old key is still available",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	newKey, err := common.LoadPublicKey(o.newKey)
+	if err != nil {
+		return err
+	}
+
+	newSigner, err := loadOptionalSigner(o.newSigningKey)
+	if err != nil {
+		return err
+	}
+
+	oldSigner, err := loadOptionalSigner(o.oldSigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.RotateKey(cmd.Context(), signer, o.oldKeyID, newKey, newSigner, oldSigner, true)
+}
+
+func loadOptionalSigner(path string) (sslibdsse.SignerVerifier, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	return common.LoadSignerFromKeyRef(path)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "rotate-key",
+		Short:             "Rotate a key trusted in the policy to a new key",
+		Long:              "The rotate-key command replaces a key trusted in the policy with a new key, recording a proof of the rotation signed using the new key and, if it's still available, the old key. RSL entries up to the rotation may be signed using the old key, while later entries must be signed using the new key.",
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
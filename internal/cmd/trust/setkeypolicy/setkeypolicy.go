@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setkeypolicy
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p                 *persistent.Options
+	allowedAlgorithms []string
+	minimumKeySizes   map[string]int
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(
+		&o.allowedAlgorithms,
+		"allow-algorithm",
+		[]string{},
+		"key algorithm permitted in the policy (one of rsa, dsa, ecdsa, ed25519, ed448, elgamal), can be repeated; if unset, all algorithms are permitted",
+	)
+
+	cmd.Flags().StringToIntVar(
+		&o.minimumKeySizes,
+		"minimum-key-size",
+		map[string]int{},
+		"minimum key size in bits for an algorithm, of form {algorithm}={bits}, can be repeated",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetKeyPolicy(cmd.Context(), signer, o.allowedAlgorithms, o.minimumKeySizes, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "set-key-policy",
+		Short:             "Set the key algorithms and minimum key sizes permitted in the policy",
+		Long:              "This command sets the key algorithms and minimum key sizes permitted for keys in the gittuf policy. All keys currently in the policy must satisfy the new key policy. Once set, keys that do not satisfy the key policy cannot be added to the policy, and signatures from such keys are not trusted during verification. Running the command without any flags removes the key policy.",
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setpolicyapprovalthreshold
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p         *persistent.Options
+	threshold int
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(
+		&o.threshold,
+		"threshold",
+		-1,
+		"number of policy administrators who must approve a change to the policy, 0 to remove the requirement",
+	)
+	cmd.MarkFlagRequired("threshold") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetPolicyApprovalThreshold(cmd.Context(), signer, o.threshold, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "set-policy-approval-threshold",
+		Short: "Require multiple policy administrators to approve changes to the policy",
+		Long: `This command sets the number of policy administrators, i.e., holders of root and top-level policy keys, who must approve a change to the policy before it takes effect. This closes the gap where a single administrator can weaken the policy unilaterally.
+
+An administrator approves a staged change by signing the changed root or top-level policy metadata, or by signing a reference authorization for the policy ref from the applied policy commit to the tree of the staged policy commit, using "gittuf attest authorize refs/gittuf/policy <applied-commit> <staged-tree>". Signatures on metadata that is unchanged by the staged change are not counted.
+
+"gittuf policy apply" fails if the staged change is not approved, and verification rejects policy states that were applied without approval. The threshold in the applied policy governs changes to it, so lowering the threshold requires approval under the current threshold. Use "--threshold 0" to remove the requirement.`,
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
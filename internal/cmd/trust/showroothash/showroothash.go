@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package showroothash
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) Run(_ *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	rootHash, err := repo.GetInitialRootHash()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(rootHash)
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "show-root-hash",
+		Short:             "Show the hash of the initial root of trust metadata",
+		Long:              `This command shows the hash of the repository's initial root of trust metadata. The hash can be shared out of band and pinned using 'gittuf trust pin' or 'gittuf clone --root-hash' to bootstrap trust in the repository's root of trust.`,
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
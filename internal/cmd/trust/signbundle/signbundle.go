@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package signbundle
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p *persistent.Options
+}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if o.p.SigningKey == "" {
+		return fmt.Errorf("required flag \"signing-key\" not set")
+	}
+
+	contents, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	bundle, err := policy.LoadSigningBundle(contents)
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerFromKeyRef(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	if err := bundle.Sign(cmd.Context(), signer); err != nil {
+		return err
+	}
+
+	contents, err = bundle.Marshal()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(args[0], contents, 0o644)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "sign-bundle <bundle-file>",
+		Short:             "Sign the metadata in a signing bundle exported for offline signing",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
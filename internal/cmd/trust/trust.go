@@ -3,13 +3,29 @@
 package trust
 
 import (
+	"github.com/gittuf/gittuf/internal/cmd/trust/addgithubappkey"
+	"github.com/gittuf/gittuf/internal/cmd/trust/addobserverkey"
 	"github.com/gittuf/gittuf/internal/cmd/trust/addpolicykey"
 	"github.com/gittuf/gittuf/internal/cmd/trust/addrootkey"
+	"github.com/gittuf/gittuf/internal/cmd/trust/addrslshard"
+	"github.com/gittuf/gittuf/internal/cmd/trust/allowexpiredgpgkeys"
+	"github.com/gittuf/gittuf/internal/cmd/trust/ceremony"
 	i "github.com/gittuf/gittuf/internal/cmd/trust/init"
 	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/cmd/trust/pin"
+	"github.com/gittuf/gittuf/internal/cmd/trust/pinrootkeys"
+	"github.com/gittuf/gittuf/internal/cmd/trust/removegithubappkey"
+	"github.com/gittuf/gittuf/internal/cmd/trust/removeobserverkey"
 	"github.com/gittuf/gittuf/internal/cmd/trust/removepolicykey"
 	"github.com/gittuf/gittuf/internal/cmd/trust/removerootkey"
+	"github.com/gittuf/gittuf/internal/cmd/trust/removerslshard"
+	"github.com/gittuf/gittuf/internal/cmd/trust/resetrootpin"
+	"github.com/gittuf/gittuf/internal/cmd/trust/rotatekey"
+	"github.com/gittuf/gittuf/internal/cmd/trust/setkeypolicy"
+	"github.com/gittuf/gittuf/internal/cmd/trust/setpolicyapprovalthreshold"
+	"github.com/gittuf/gittuf/internal/cmd/trust/showroothash"
 	"github.com/gittuf/gittuf/internal/cmd/trust/sign"
+	"github.com/gittuf/gittuf/internal/cmd/trust/signbundle"
 	"github.com/gittuf/gittuf/internal/cmd/trust/updatepolicythreshold"
 	"github.com/gittuf/gittuf/internal/cmd/trust/updaterootthreshold"
 	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/apply"
@@ -27,13 +43,29 @@ func New() *cobra.Command {
 	o.AddPersistentFlags(cmd)
 
 	cmd.AddCommand(i.New(o))
+	cmd.AddCommand(addgithubappkey.New(o))
+	cmd.AddCommand(addobserverkey.New(o))
 	cmd.AddCommand(addpolicykey.New(o))
 	cmd.AddCommand(addrootkey.New(o))
+	cmd.AddCommand(addrslshard.New(o))
+	cmd.AddCommand(allowexpiredgpgkeys.New(o))
 	cmd.AddCommand(apply.New())
+	cmd.AddCommand(ceremony.New(o))
+	cmd.AddCommand(pin.New())
+	cmd.AddCommand(pinrootkeys.New())
 	cmd.AddCommand(remote.New())
+	cmd.AddCommand(removegithubappkey.New(o))
+	cmd.AddCommand(removeobserverkey.New(o))
 	cmd.AddCommand(removepolicykey.New(o))
 	cmd.AddCommand(removerootkey.New(o))
+	cmd.AddCommand(removerslshard.New(o))
+	cmd.AddCommand(resetrootpin.New())
+	cmd.AddCommand(rotatekey.New(o))
+	cmd.AddCommand(setkeypolicy.New(o))
+	cmd.AddCommand(setpolicyapprovalthreshold.New(o))
+	cmd.AddCommand(showroothash.New())
 	cmd.AddCommand(sign.New(o))
+	cmd.AddCommand(signbundle.New(o))
 	cmd.AddCommand(updatepolicythreshold.New(o))
 	cmd.AddCommand(updaterootthreshold.New(o))
 
@@ -3,17 +3,35 @@
 package verifycommit
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/gittuf/gittuf/internal/perf"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
-type options struct{}
+type options struct {
+	perf bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&o.perf,
+		"perf",
+		false,
+		"print a breakdown of the time spent verifying to stderr",
+	)
+}
 
-func (o *options) AddFlags(_ *cobra.Command) {}
+func (o *options) Run(cmd *cobra.Command, args []string) (err error) {
+	if o.perf {
+		perf.Enable()
+		defer func() {
+			err = errors.Join(err, perf.WriteReport(cmd.ErrOrStderr()))
+		}()
+	}
 
-func (o *options) Run(cmd *cobra.Command, args []string) error {
 	repo, err := repository.LoadRepository()
 	if err != nil {
 		return err
@@ -31,8 +49,9 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 func New() *cobra.Command {
 	o := &options{}
 	cmd := &cobra.Command{
-		Use:               "verify-commit",
+		Use:               "verify-commit <commit-ish>...",
 		Short:             "Verify commit signatures using gittuf metadata",
+		Long:              "Verify commit signatures using gittuf metadata. The signing key must be trusted by the applicable policy for every file path modified by the commit. Commits that haven't been recorded in the RSL, such as those under review, are verified using the latest policy.",
 		Args:              cobra.MinimumNArgs(1),
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verifygithubrelease
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	repository  string
+	enforcement string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.repository,
+		"repository",
+		"",
+		"path to GitHub repository the release is published in, of form {owner}/{repo}",
+	)
+	cmd.MarkFlagRequired("repository") //nolint:errcheck
+
+	common.AddEnforcementFlag(cmd, &o.enforcement)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repositoryParts := strings.Split(o.repository, "/")
+	if len(repositoryParts) != 2 {
+		return fmt.Errorf("invalid format for repository, must be {owner}/{repo}")
+	}
+
+	if err := repository.ValidateVerificationEnforcement(o.enforcement); err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	err = repo.VerifyGitHubRelease(cmd.Context(), repositoryParts[0], repositoryParts[1], args[0])
+	return common.ApplyEnforcement(o.enforcement, err)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "verify-github-release <tag>",
+		Short:             "Verify that assets published with a GitHub release match the attested assets for the tag",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verifyreceive
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/exitcodes"
+	"github.com/gittuf/gittuf/internal/hooks"
+	"github.com/gittuf/gittuf/internal/perf"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+var ErrPushRejected = errors.New("push rejected by gittuf")
+
+type options struct {
+	enforcedRefs []string
+	enforcement  string
+	perf         bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(
+		&o.enforcedRefs,
+		"enforce-ref",
+		[]string{},
+		fmt.Sprintf("pattern of refs to enforce gittuf for (default %v)", hooks.DefaultEnforcedRefs),
+	)
+
+	common.AddEnforcementFlag(cmd, &o.enforcement)
+
+	cmd.Flags().BoolVar(
+		&o.perf,
+		"perf",
+		false,
+		"print a breakdown of the time spent verifying to stderr",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) (err error) {
+	if o.perf {
+		perf.Enable()
+		defer func() {
+			err = errors.Join(err, perf.WriteReport(cmd.ErrOrStderr()))
+		}()
+	}
+
+	hookOptions := &hooks.Options{EnforcedRefs: o.enforcedRefs}
+	if err := hookOptions.Validate(); err != nil {
+		return err
+	}
+
+	if err := repository.ValidateVerificationEnforcement(o.enforcement); err != nil {
+		return err
+	}
+
+	receivedUpdates, err := repository.ParseReceivedRefUpdates(cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+
+	updates := []*repository.RefUpdate{}
+	for _, update := range receivedUpdates {
+		if hookOptions.ShouldVerify(update.Name) {
+			updates = append(updates, update)
+		}
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	repo, err := repository.LoadRepositoryForReceive()
+	if err != nil {
+		return err
+	}
+
+	decisions, err := repo.VerifyReceivedRefUpdates(cmd.Context(), updates)
+	if err != nil {
+		return err
+	}
+
+	rejected := common.EnforceRefUpdateDecisions(cmd.ErrOrStderr(), o.enforcement, decisions)
+	if rejected {
+		return exitcodes.AsPolicyViolation(ErrPushRejected)
+	}
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "verify-receive",
+		Short:             "Verify ref updates received by a Git server",
+		Long:              "The 'verify-receive' command is meant to be invoked by a Git server's pre-receive hook, such as the one added using 'gittuf add-hooks --server'. It reads the received ref updates from standard input in the format used by pre-receive hooks, and rejects the push if any update to a ref gittuf is enforced for is not recorded in the RSL or fails verification.",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -3,16 +3,40 @@
 package verifyref
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/perf"
+	"github.com/gittuf/gittuf/internal/progress"
+	"github.com/gittuf/gittuf/internal/report"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
 type options struct {
 	latestOnly bool
 	fromEntry  string
+	noCache    bool
+	oldID      string
+	newID      string
+	rslTip     string
+	perf       bool
+	submodules bool
+	keepGoing  bool
+	format     string
+
+	enforcement string
+
+	expiryGracePeriod string
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -20,7 +44,7 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		&o.latestOnly,
 		"latest-only",
 		false,
-		"perform verification against latest entry in the RSL",
+		fmt.Sprintf("perform verification against latest entry in the RSL, overrides %s", repository.VerificationStrictnessConfigKey),
 	)
 
 	cmd.Flags().StringVar(
@@ -30,10 +54,141 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		fmt.Sprintf("perform verification from specified RSL entry (developer mode only, set %s=1)", dev.DevModeKey),
 	)
 
+	cmd.Flags().BoolVar(
+		&o.noCache,
+		"no-cache",
+		false,
+		"discard results of prior verification runs and verify the entire RSL",
+	)
+
+	cmd.Flags().StringVar(
+		&o.oldID,
+		"old-id",
+		"",
+		"verify the update of the ref from this ID, such as the old ID reported to a pre-receive hook (zero ID if the ref is being created)",
+	)
+
+	cmd.Flags().StringVar(
+		&o.newID,
+		"new-id",
+		"",
+		"verify the update of the ref to this ID, which must be recorded in the ref's latest RSL entry",
+	)
+
+	cmd.Flags().StringVar(
+		&o.rslTip,
+		"rsl-tip",
+		"",
+		"identify RSL entries for the update using the RSL as of this entry, such as the RSL tip received in a push",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.perf,
+		"perf",
+		false,
+		"print a breakdown of the time spent verifying to stderr",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.submodules,
+		"with-submodules",
+		false,
+		"also verify that the submodule commits recorded in the ref's latest RSL entry are verified in the submodules' repositories",
+	)
+
+	cmd.Flags().StringVar(
+		&o.expiryGracePeriod,
+		"expiry-grace-period",
+		"",
+		fmt.Sprintf("fail if policy metadata expired longer than this period ago, such as 7d or 0d, overrides %s", repository.ExpiryGracePeriodConfigKey),
+	)
+
+	cmd.Flags().BoolVar(
+		&o.keepGoing,
+		"keep-going",
+		false,
+		"continue verification past policy violations and report all of them, verifying the entire RSL without using the verification cache",
+	)
+
+	cmd.Flags().StringVar(
+		&o.format,
+		"format",
+		formatText,
+		fmt.Sprintf("output format (%s, %s), %s writes a verification report to stdout", formatText, formatJSON, formatJSON),
+	)
+
+	common.AddEnforcementFlag(cmd, &o.enforcement)
+
 	cmd.MarkFlagsMutuallyExclusive("latest-only", "from-entry")
+	cmd.MarkFlagsMutuallyExclusive("latest-only", "no-cache")
+	cmd.MarkFlagsRequiredTogether("old-id", "new-id")
+	cmd.MarkFlagsMutuallyExclusive("old-id", "latest-only")
+	cmd.MarkFlagsMutuallyExclusive("old-id", "from-entry")
+	cmd.MarkFlagsMutuallyExclusive("old-id", "no-cache")
+	cmd.MarkFlagsMutuallyExclusive("old-id", "with-submodules")
+	cmd.MarkFlagsMutuallyExclusive("from-entry", "with-submodules")
+	cmd.MarkFlagsMutuallyExclusive("keep-going", "latest-only")
+	cmd.MarkFlagsMutuallyExclusive("keep-going", "from-entry")
+	cmd.MarkFlagsMutuallyExclusive("keep-going", "with-submodules")
 }
 
 func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if o.format != formatText && o.format != formatJSON {
+		return fmt.Errorf("unknown format '%s', must be one of %s, %s", o.format, formatText, formatJSON)
+	}
+
+	if err := repository.ValidateVerificationEnforcement(o.enforcement); err != nil {
+		return err
+	}
+
+	if o.format == formatJSON {
+		report.Enable()
+	}
+
+	if o.perf {
+		perf.Enable()
+	}
+
+	finish := progress.Start(progress.OperationVerify)
+	verifyErr := o.verify(cmd, args)
+	finish(verifyErr)
+
+	// The report records the outcome of verification regardless of how
+	// failures are enforced
+	err := common.ApplyEnforcement(o.enforcement, verifyErr)
+	if o.perf {
+		err = errors.Join(err, perf.WriteReport(cmd.ErrOrStderr()))
+	}
+	if o.format == formatJSON {
+		err = errors.Join(err, writeReport(cmd, report.Build(args[0], verifyErr)))
+	}
+
+	return err
+}
+
+func (o *options) verify(cmd *cobra.Command, args []string) error {
+	if o.oldID != "" {
+		// Objects received in a push are quarantined until the pre-receive
+		// hook accepts it
+		repo, err := repository.LoadRepositoryForReceive()
+		if err != nil {
+			return err
+		}
+
+		if err := o.verifyPolicyExpiration(cmd, repo); err != nil {
+			return err
+		}
+
+		if o.keepGoing {
+			return repo.VerifyRefRangeKeepGoing(cmd.Context(), args[0], o.oldID, o.newID, o.rslTip)
+		}
+		return repo.VerifyRefRange(cmd.Context(), args[0], o.oldID, o.newID, o.rslTip)
+	}
+
+	if o.rslTip != "" {
+		return fmt.Errorf("--rsl-tip can only be used with --old-id and --new-id")
+	}
+
 	repo, err := repository.LoadRepository()
 	if err != nil {
 		return err
@@ -47,7 +202,68 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 		return repo.VerifyRefFromEntry(cmd.Context(), args[0], o.fromEntry)
 	}
 
-	return repo.VerifyRef(cmd.Context(), args[0], o.latestOnly)
+	if err := o.verifyPolicyExpiration(cmd, repo); err != nil {
+		return err
+	}
+
+	if o.noCache {
+		if err := repo.ResetVerificationCache(); err != nil {
+			return err
+		}
+	}
+
+	if o.keepGoing {
+		return repo.VerifyRefKeepGoing(cmd.Context(), args[0])
+	}
+
+	if !o.noCache && !cmd.Flags().Changed("latest-only") {
+		config, err := repository.LoadConfig()
+		if err != nil {
+			return err
+		}
+		o.latestOnly = config.VerificationStrictness == repository.VerificationStrictnessLatestOnly
+	}
+
+	if err := repo.VerifyRef(cmd.Context(), args[0], o.latestOnly); err != nil {
+		return err
+	}
+
+	if o.submodules {
+		return repo.VerifySubmodules(cmd.Context(), args[0])
+	}
+
+	return nil
+}
+
+// writeReport writes the verification report to stdout as JSON.
+func writeReport(cmd *cobra.Command, verificationReport *report.Report) error {
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(verificationReport)
+}
+
+// verifyPolicyExpiration checks the expiry of the policy metadata if a grace
+// period is set using the flag or the Git config.
+func (o *options) verifyPolicyExpiration(cmd *cobra.Command, repo *repository.Repository) error {
+	var gracePeriod time.Duration
+	if cmd.Flags().Changed("expiry-grace-period") {
+		period, err := repository.ParsePeriod(o.expiryGracePeriod)
+		if err != nil {
+			return err
+		}
+		gracePeriod = period
+	} else {
+		config, err := repository.LoadConfig()
+		if err != nil {
+			return err
+		}
+		if !config.EnforceExpiry {
+			return nil
+		}
+		gracePeriod = config.ExpiryGracePeriod
+	}
+
+	return repo.VerifyPolicyExpiration(cmd.Context(), gracePeriod)
 }
 
 func New() *cobra.Command {
@@ -3,17 +3,35 @@
 package verifytag
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/gittuf/gittuf/internal/perf"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
-type options struct{}
+type options struct {
+	perf bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&o.perf,
+		"perf",
+		false,
+		"print a breakdown of the time spent verifying to stderr",
+	)
+}
 
-func (o *options) AddFlags(_ *cobra.Command) {}
+func (o *options) Run(cmd *cobra.Command, args []string) (err error) {
+	if o.perf {
+		perf.Enable()
+		defer func() {
+			err = errors.Join(err, perf.WriteReport(cmd.ErrOrStderr()))
+		}()
+	}
 
-func (o *options) Run(cmd *cobra.Command, args []string) error {
 	repo, err := repository.LoadRepository()
 	if err != nil {
 		return err
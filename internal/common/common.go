@@ -4,9 +4,13 @@ package common
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -19,6 +23,7 @@ import (
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/jonboulle/clockwork"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 )
 
 const (
@@ -42,7 +47,9 @@ var (
 // CreateTestRSLReferenceEntryCommit is a test helper used to create a
 // **signed** reference entry using the specified GPG key. It is used to
 // substitute for the default RSL entry creation and signing mechanism which
-// relies on the user's Git config.
+// relies on the user's Git config. If the entry's shard is set, the entry is
+// added to the RSL shard, and the caller must record the index entry for the
+// new shard tip.
 func CreateTestRSLReferenceEntryCommit(t *testing.T, repo *git.Repository, entry *rsl.ReferenceEntry, signingKeyBytes []byte) plumbing.Hash {
 	t.Helper()
 
@@ -54,14 +61,66 @@ func CreateTestRSLReferenceEntryCommit(t *testing.T, repo *git.Repository, entry
 		fmt.Sprintf("%s: %s", rsl.RefKey, entry.RefName),
 		fmt.Sprintf("%s: %s", rsl.TargetIDKey, entry.TargetID.String()),
 	}
+	if entry.Shard != "" {
+		lines = append(lines, fmt.Sprintf("%s: %s", rsl.ShardKey, entry.Shard))
+	}
+	if entry.ChangedPaths != nil {
+		lines = append(lines, fmt.Sprintf("%s: %d", rsl.ChangedPathsKey, len(entry.ChangedPaths)))
+		for _, path := range entry.ChangedPaths {
+			lines = append(lines, fmt.Sprintf("%s: %s", rsl.ChangedPathKey, strconv.Quote(path)))
+		}
+	}
+	for _, ticket := range entry.Tickets {
+		lines = append(lines, fmt.Sprintf("%s: %s", rsl.TicketKey, ticket))
+	}
+	if entry.Submodules != nil {
+		paths := make([]string, 0, len(entry.Submodules))
+		for path := range entry.Submodules {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		lines = append(lines, fmt.Sprintf("%s: %d", rsl.SubmodulesKey, len(paths)))
+		for _, path := range paths {
+			lines = append(lines, fmt.Sprintf("%s: %s %s", rsl.SubmoduleKey, entry.Submodules[path].String(), strconv.Quote(path)))
+		}
+	}
+
+	if len(entry.Message) != 0 {
+		var message strings.Builder
+		messageBlock := pem.Block{
+			Type:  rsl.AnnotationMessageBlockType,
+			Bytes: []byte(entry.Message),
+		}
+		if err := pem.Encode(&message, &messageBlock); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, strings.TrimSpace(message.String()))
+	}
 
 	commitMessage := strings.Join(lines, "\n")
 
-	ref, err := repo.Reference(plumbing.ReferenceName(rsl.Ref), true)
+	refName := rsl.Ref
+	if entry.Shard != "" {
+		refName = rsl.ShardRef(entry.Shard)
+		if _, err := repo.Reference(plumbing.ReferenceName(refName), true); errors.Is(err, plumbing.ErrReferenceNotFound) {
+			if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	ref, err := repo.Reference(plumbing.ReferenceName(refName), true)
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	parentHashes := []plumbing.Hash{ref.Hash()}
+	if entry.Shard != "" && ref.Hash().IsZero() {
+		// This is the first entry in the shard
+		parentHashes = nil
+	}
+
 	testCommit := &object.Commit{
 		Author: object.Signature{
 			Name:  testName,
@@ -75,7 +134,7 @@ func CreateTestRSLReferenceEntryCommit(t *testing.T, repo *git.Repository, entry
 		},
 		Message:      commitMessage,
 		TreeHash:     gitinterface.EmptyTree(),
-		ParentHashes: []plumbing.Hash{ref.Hash()},
+		ParentHashes: parentHashes,
 	}
 
 	testCommit = SignTestCommit(t, repo, testCommit, signingKeyBytes)
@@ -102,8 +161,14 @@ func CreateTestRSLAnnotationEntryCommit(t *testing.T, repo *git.Repository, anno
 		"",
 	}
 
-	for _, entry := range annotation.RSLEntryIDs {
-		lines = append(lines, fmt.Sprintf("%s: %s", rsl.EntryIDKey, entry.String()))
+	if annotation.IsRange() {
+		lines = append(lines, fmt.Sprintf("%s: %s", rsl.RangeRefKey, annotation.RangeRefName))
+		lines = append(lines, fmt.Sprintf("%s: %s", rsl.RangeStartKey, annotation.RangeStartID.String()))
+		lines = append(lines, fmt.Sprintf("%s: %s", rsl.RangeEndKey, annotation.RangeEndID.String()))
+	} else {
+		for _, entry := range annotation.RSLEntryIDs {
+			lines = append(lines, fmt.Sprintf("%s: %s", rsl.EntryIDKey, entry.String()))
+		}
 	}
 
 	if annotation.Skip {
@@ -303,3 +368,47 @@ func CreateTestSignedTag(t *testing.T, repo *git.Repository, tagName string, tar
 
 	return tagHash
 }
+
+// CreateTestSLSAProvenanceEnvelope is a test helper used to create an unsigned
+// DSSE envelope containing SLSA v1 provenance from the specified builder, as
+// produced by external builders such as GitHub Actions. The provenance records
+// the commit as the build's source.
+func CreateTestSLSAProvenanceEnvelope(t *testing.T, builderID string, commitID plumbing.Hash) *sslibdsse.Envelope {
+	t.Helper()
+
+	statement := map[string]any{
+		"_type": "https://in-toto.io/Statement/v1",
+		"subject": []any{
+			map[string]any{
+				"name":   "gittuf_linux_amd64",
+				"digest": map[string]any{"sha256": "c7a1b5e3b5c9d0e7f4a3b2c1d0e9f8a7b6c5d4e3f2a1b0c9d8e7f6a5b4c3d2e1"},
+			},
+		},
+		"predicateType": "https://slsa.dev/provenance/v1",
+		"predicate": map[string]any{
+			"buildDefinition": map[string]any{
+				"buildType": "https://actions.github.io/buildtypes/workflow/v1",
+				"resolvedDependencies": []any{
+					map[string]any{
+						"uri":    "git+https://github.com/gittuf/gittuf@refs/tags/v1",
+						"digest": map[string]any{"gitCommit": commitID.String()},
+					},
+				},
+			},
+			"runDetails": map[string]any{
+				"builder": map[string]any{"id": builderID},
+			},
+		},
+	}
+
+	statementBytes, err := json.Marshal(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &sslibdsse.Envelope{
+		PayloadType: "application/vnd.in-toto+json",
+		Payload:     base64.StdEncoding.EncodeToString(statementBytes),
+		Signatures:  []sslibdsse.Signature{},
+	}
+}
@@ -36,38 +36,42 @@ func pageWriter(pagerBin string, defaultOutput io.Writer) *pager {
 	return &pager{command: cmd}
 }
 
+// pager writes contents to the pager's standard input. The pager is started on
+// the first write, and contents can be written incrementally until the pager
+// is closed.
 type pager struct {
 	command *exec.Cmd
+	stdIn   io.WriteCloser
 }
 
 func (p *pager) Write(contents []byte) (int, error) {
-	stdInWriter, err := p.command.StdinPipe()
-	if err != nil {
-		return -1, err
-	}
+	if p.stdIn == nil {
+		stdInWriter, err := p.command.StdinPipe()
+		if err != nil {
+			return -1, err
+		}
 
-	if err := p.command.Start(); err != nil {
-		return -1, err
-	}
+		if err := p.command.Start(); err != nil {
+			return -1, err
+		}
 
-	written, writeErr := stdInWriter.Write(contents)
-	if writeErr != nil {
-		return -1, writeErr
+		p.stdIn = stdInWriter
 	}
 
-	if err := stdInWriter.Close(); err != nil {
-		return -1, err
-	}
+	return p.stdIn.Write(contents)
+}
 
-	if err := p.command.Wait(); err != nil {
-		return -1, err
+// Close closes the pager's standard input and waits for the pager to exit.
+func (p *pager) Close() error {
+	if p.stdIn == nil {
+		return nil
 	}
 
-	return written, nil
-}
+	if err := p.stdIn.Close(); err != nil {
+		return err
+	}
 
-func (p *pager) Close() error {
-	return nil
+	return p.command.Wait()
 }
 
 type noopwriter struct {
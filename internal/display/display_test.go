@@ -47,6 +47,12 @@ func TestNewDisplayWriter(t *testing.T) {
 				t.Fatal(err)
 			}
 
+			// The pager is only guaranteed to have written its output once
+			// it's closed
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
 			if gotOutput := defaultOutput.String(); gotOutput != tt.wantOutput {
 				t.Errorf("unexpected result with Display(), got stdout = %v, want %v", gotOutput, tt.wantOutput)
 			}
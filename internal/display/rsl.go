@@ -4,6 +4,8 @@ package display
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -28,44 +30,67 @@ entry <entryID> (skipped)
       <message>
 */
 func PrepareRSLLogOutput(entries []*rsl.ReferenceEntry, annotationMap map[plumbing.Hash][]*rsl.AnnotationEntry) string {
-	log := ""
-
+	entryOutputs := make([]string, 0, len(entries))
 	for _, entry := range entries {
-		log += fmt.Sprintf("entry %v", entry.ID)
-
-		skipped := false
-		if annotations, ok := annotationMap[entry.ID]; ok {
-			for _, annotation := range annotations {
-				if annotation.Skip {
-					skipped = true
-					break
-				}
-			}
-		}
+		entryOutputs = append(entryOutputs, PrepareRSLLogEntryOutput(entry, annotationMap[entry.ID]))
+	}
 
-		if skipped {
-			log += " (skipped)"
-		}
-		log += "\n"
+	return strings.Join(entryOutputs, "\n")
+}
 
-		log += fmt.Sprintf("\n  Ref:    %s", entry.RefName)
-		log += fmt.Sprintf("\n  Target: %s", entry.TargetID.String())
-
-		if annotations, ok := annotationMap[entry.ID]; ok {
-			for _, annotation := range annotations {
-				log += "\n"
-				log += fmt.Sprintf("\n    Annotation ID: %s", annotation.ID.String())
-				if annotation.Skip {
-					log += "\n    Skip:          yes"
-				} else {
-					log += "\n    Skip:          no"
-				}
-				log += fmt.Sprintf("\n    Message:\n      %s", annotation.Message)
-			}
+// PrepareRSLLogEntryOutput returns the string representation of a single RSL
+// entry with its annotations, in the format used by PrepareRSLLogOutput. This
+// allows the RSL to be displayed as it is walked, one entry at a time.
+func PrepareRSLLogEntryOutput(entry *rsl.ReferenceEntry, annotations []*rsl.AnnotationEntry) string {
+	log := fmt.Sprintf("entry %v", entry.ID)
+
+	skipped := false
+	for _, annotation := range annotations {
+		if annotation.Skip {
+			skipped = true
+			break
 		}
+	}
 
-		log += "\n\n"
+	if skipped {
+		log += " (skipped)"
 	}
+	log += "\n"
+
+	log += fmt.Sprintf("\n  Ref:    %s", entry.RefName)
+	log += fmt.Sprintf("\n  Target: %s", entry.TargetID.String())
+	for _, ticket := range entry.Tickets {
+		log += fmt.Sprintf("\n  Ticket: %s", ticket)
+	}
+
+	submodulePaths := make([]string, 0, len(entry.Submodules))
+	for path := range entry.Submodules {
+		submodulePaths = append(submodulePaths, path)
+	}
+	sort.Strings(submodulePaths)
+	for _, path := range submodulePaths {
+		log += fmt.Sprintf("\n  Submodule: %s %s", entry.Submodules[path].String(), path)
+	}
+
+	if entry.Message != "" {
+		log += fmt.Sprintf("\n  Message:\n    %s", strings.ReplaceAll(entry.Message, "\n", "\n    "))
+	}
+
+	for _, annotation := range annotations {
+		log += "\n"
+		log += fmt.Sprintf("\n    Annotation ID: %s", annotation.ID.String())
+		if annotation.Skip {
+			log += "\n    Skip:          yes"
+		} else {
+			log += "\n    Skip:          no"
+		}
+		for _, ticket := range annotation.Tickets {
+			log += fmt.Sprintf("\n    Ticket:        %s", ticket)
+		}
+		log += fmt.Sprintf("\n    Message:\n      %s", annotation.Message)
+	}
+
+	log += "\n"
 
-	return log[:len(log)-1]
+	return log
 }
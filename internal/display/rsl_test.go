@@ -19,21 +19,57 @@ func TestPrepareRSLLogOutput(t *testing.T) {
 		branchEntry := rsl.NewReferenceEntry("refs/heads/main", plumbing.ZeroHash)
 		tagEntry := rsl.NewReferenceEntry("refs/tags/v1", plumbing.ZeroHash)
 
-		expectedOutput := `entry 0000000000000000000000000000000000000000
+		expectedOutput := fmt.Sprintf(`entry %[1]s
 
   Ref:    refs/heads/main
-  Target: 0000000000000000000000000000000000000000
+  Target: %[1]s
 
-entry 0000000000000000000000000000000000000000
+entry %[1]s
 
   Ref:    refs/tags/v1
-  Target: 0000000000000000000000000000000000000000
-`
+  Target: %[1]s
+`, plumbing.ZeroHash.String())
 
 		logOutput := PrepareRSLLogOutput([]*rsl.ReferenceEntry{branchEntry, tagEntry}, nil)
 		assert.Equal(t, expectedOutput, logOutput)
 	})
 
+	t.Run("with tickets and submodules", func(t *testing.T) {
+		entry := rsl.NewReferenceEntry("refs/heads/release", plumbing.ZeroHash)
+		entry.Tickets = []string{"https://example.com/issues/1", "urn:jira:GTF-2"}
+		entry.Submodules = map[string]plumbing.Hash{"vendor/tool": plumbing.ZeroHash, "lib": plumbing.ZeroHash}
+
+		expectedOutput := fmt.Sprintf(`entry %[1]s
+
+  Ref:    refs/heads/release
+  Target: %[1]s
+  Ticket: https://example.com/issues/1
+  Ticket: urn:jira:GTF-2
+  Submodule: %[1]s lib
+  Submodule: %[1]s vendor/tool
+`, plumbing.ZeroHash.String())
+
+		logOutput := PrepareRSLLogOutput([]*rsl.ReferenceEntry{entry}, nil)
+		assert.Equal(t, expectedOutput, logOutput)
+	})
+
+	t.Run("with message", func(t *testing.T) {
+		entry := rsl.NewReferenceEntry("refs/heads/main", plumbing.ZeroHash)
+		entry.Message = "Hotfix for outage\nApproved in https://example.com/reviews/1"
+
+		expectedOutput := fmt.Sprintf(`entry %[1]s
+
+  Ref:    refs/heads/main
+  Target: %[1]s
+  Message:
+    Hotfix for outage
+    Approved in https://example.com/reviews/1
+`, plumbing.ZeroHash.String())
+
+		logOutput := PrepareRSLLogOutput([]*rsl.ReferenceEntry{entry}, nil)
+		assert.Equal(t, expectedOutput, logOutput)
+	})
+
 	t.Run("with annotations", func(t *testing.T) {
 		repo, err := git.Init(memory.NewStorage(), memfs.New())
 		if err != nil {
@@ -64,21 +100,21 @@ entry 0000000000000000000000000000000000000000
 			t.Fatal(err)
 		}
 
-		expectedOutput := fmt.Sprintf(`entry %s
+		expectedOutput := fmt.Sprintf(`entry %[1]s
 
   Ref:    refs/tags/v1
-  Target: 0000000000000000000000000000000000000000
+  Target: %[4]s
 
-entry %s (skipped)
+entry %[2]s (skipped)
 
   Ref:    refs/heads/main
-  Target: 0000000000000000000000000000000000000000
+  Target: %[4]s
 
-    Annotation ID: %s
+    Annotation ID: %[3]s
     Skip:          yes
     Message:
       msg
-`, tagEntry.ID.String(), branchEntry.ID.String(), annotationEntry.GetID().String())
+`, tagEntry.ID.String(), branchEntry.ID.String(), annotationEntry.GetID().String(), plumbing.ZeroHash.String())
 
 		logOutput := PrepareRSLLogOutput([]*rsl.ReferenceEntry{tagEntry, branchEntry}, map[plumbing.Hash][]*rsl.AnnotationEntry{branchEntry.ID: {annotationEntry.(*rsl.AnnotationEntry)}})
 		assert.Equal(t, expectedOutput, logOutput)
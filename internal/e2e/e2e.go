@@ -0,0 +1,254 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build e2e
+
+// Package e2e is a harness for end-to-end tests that run the gittuf binary
+// against real Git servers. It provides a Git smart HTTP server backed by git
+// http-backend, an SSH server that runs git-upload-pack and git-receive-pack,
+// and a stub of the GitHub API, so that changes to gittuf's sync flows are
+// validated against real protocol traffic.
+//
+// The tests are only built with the e2e build tag, and require Git to be
+// installed:
+//
+//	go test -tags e2e ./internal/e2e/...
+package e2e
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+)
+
+// BuildGittuf builds the gittuf binary into dir using the specified build
+// tags, returning its path.
+func BuildGittuf(dir string, buildTags ...string) (string, error) {
+	binaryPath := filepath.Join(dir, "gittuf")
+	if filepath.Separator == '\\' {
+		binaryPath += ".exe"
+	}
+
+	// The harness is at internal/e2e, gittuf's main package is at the root
+	// of the module
+	command := exec.Command("go", "build", "-tags", strings.Join(buildTags, ","), "-o", binaryPath, "../..")
+	output, err := command.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("unable to build gittuf: %w: %s", err, string(output))
+	}
+
+	return binaryPath, nil
+}
+
+// Env is an isolated environment for a user of gittuf, with its own home
+// directory, Git configuration, and keys. Commands are run in the Dir
+// directory unless specified otherwise.
+type Env struct {
+	t          *testing.T
+	gittufPath string
+	env        []string
+
+	// Home is the home directory of the environment's user.
+	Home string
+
+	// Dir is the directory commands are run in.
+	Dir string
+
+	// Keys is the directory holding the keys used in the environment. The
+	// root, policy, and developer keys are written to it as SSH private keys,
+	// with PEM encoded public keys of the same name with the .pem extension.
+	Keys string
+}
+
+// NewEnv returns a new environment that runs the gittuf binary at gittufPath.
+// The environment's commits and RSL entries are signed using the developer
+// key.
+func NewEnv(t *testing.T, gittufPath string) *Env {
+	t.Helper()
+
+	home := t.TempDir()
+	keysDir := filepath.Join(home, "keys")
+	if err := os.Mkdir(keysDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := map[string][2][]byte{
+		"root":      {artifacts.SSHRSAPrivate, artifacts.SSHRSAPublic},
+		"policy":    {artifacts.SSHECDSAPrivate, artifacts.SSHECDSAPublic},
+		"developer": {artifacts.SSHED25519Private, artifacts.SSHED25519Public},
+	}
+	for name, keyBytes := range keys {
+		if err := os.WriteFile(filepath.Join(keysDir, name), keyBytes[0], 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(keysDir, name+".pem"), keyBytes[1], 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	globalConfig := filepath.Join(home, ".gitconfig")
+	e := &Env{
+		t:          t,
+		gittufPath: gittufPath,
+		env: append(os.Environ(),
+			"HOME="+home,
+			"GIT_CONFIG_GLOBAL="+globalConfig,
+			"GIT_CONFIG_NOSYSTEM=1",
+			"GIT_TERMINAL_PROMPT=0",
+			// Hooks added by gittuf invoke it from the PATH
+			"PATH="+filepath.Dir(gittufPath)+string(os.PathListSeparator)+os.Getenv("PATH"),
+		),
+		Home: home,
+		Dir:  home,
+		Keys: keysDir,
+	}
+
+	e.Git("config", "--global", "user.name", "gittuf e2e")
+	e.Git("config", "--global", "user.email", "e2e@gittuf.dev")
+	e.Git("config", "--global", "init.defaultBranch", "main")
+	e.Git("config", "--global", "gpg.format", "ssh")
+	e.Git("config", "--global", "user.signingkey", e.Key("developer"))
+	e.Git("config", "--global", "commit.gpgsign", "true")
+	e.Git("config", "--global", "tag.gpgsign", "true")
+
+	return e
+}
+
+// Key returns the path to the named private key.
+func (e *Env) Key(name string) string {
+	return filepath.Join(e.Keys, name)
+}
+
+// PublicKey returns the path to the named PEM encoded public key.
+func (e *Env) PublicKey(name string) string {
+	return filepath.Join(e.Keys, name+".pem")
+}
+
+// Setenv sets an environment variable for commands run in the environment.
+func (e *Env) Setenv(key, value string) {
+	e.env = append(e.env, key+"="+value)
+}
+
+// InDir returns a copy of the environment that runs commands in dir, which is
+// relative to the current directory unless it is absolute.
+func (e *Env) InDir(dir string) *Env {
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(e.Dir, dir)
+	}
+
+	copied := *e
+	copied.env = append([]string{}, e.env...)
+	copied.Dir = dir
+	return &copied
+}
+
+// Result is the result of running a command.
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// Gittuf runs gittuf with the specified arguments and returns the result. It
+// fails the test only if the binary can't be run, so that tests can check the
+// exit code.
+func (e *Env) Gittuf(args ...string) *Result {
+	e.t.Helper()
+
+	return e.run(e.gittufPath, args...)
+}
+
+// MustGittuf runs gittuf with the specified arguments, failing the test if it
+// does not succeed.
+func (e *Env) MustGittuf(args ...string) *Result {
+	e.t.Helper()
+
+	result := e.Gittuf(args...)
+	if result.ExitCode != 0 {
+		e.t.Fatalf("gittuf %s exited with %d: %s", strings.Join(args, " "), result.ExitCode, result.Stderr)
+	}
+
+	return result
+}
+
+// Git runs Git with the specified arguments, failing the test if it does not
+// succeed. Its standard output is returned.
+func (e *Env) Git(args ...string) string {
+	e.t.Helper()
+
+	result := e.run("git", args...)
+	if result.ExitCode != 0 {
+		e.t.Fatalf("git %s exited with %d: %s", strings.Join(args, " "), result.ExitCode, result.Stderr)
+	}
+
+	return strings.TrimSpace(result.Stdout)
+}
+
+// Commit writes a file relative to the current directory and commits it,
+// returning the commit's ID.
+func (e *Env) Commit(path, contents, message string) string {
+	e.t.Helper()
+
+	if err := os.WriteFile(filepath.Join(e.Dir, path), []byte(contents), 0o600); err != nil {
+		e.t.Fatal(err)
+	}
+
+	e.Git("add", path)
+	e.Git("commit", "-m", message)
+	return e.Git("rev-parse", "HEAD")
+}
+
+// InitRepository creates a repository in dir with gittuf's root of trust and
+// a policy that protects the main branch using the developer key. The
+// environment for the repository is returned.
+func (e *Env) InitRepository(dir string) *Env {
+	e.t.Helper()
+
+	return e.InitRepositoryWithObjectFormat(dir, "sha1")
+}
+
+// InitRepositoryWithObjectFormat creates a repository like InitRepository
+// that uses the specified object format, one of sha1 and sha256.
+func (e *Env) InitRepositoryWithObjectFormat(dir, objectFormat string) *Env {
+	e.t.Helper()
+
+	e.Git("init", "--object-format", objectFormat, dir)
+	repo := e.InDir(dir)
+
+	repo.MustGittuf("trust", "init", "-k", e.Key("root"))
+	repo.MustGittuf("trust", "add-policy-key", "-k", e.Key("root"), "--policy-key", e.PublicKey("policy"))
+	repo.MustGittuf("policy", "init", "-k", e.Key("policy"), "--policy-name", "targets")
+	repo.MustGittuf("policy", "add-rule", "-k", e.Key("policy"), "--rule-name", "protect-main", "--rule-pattern", "git:refs/heads/main", "--authorize-key", e.PublicKey("developer"))
+	repo.MustGittuf("policy", "apply")
+
+	return repo
+}
+
+func (e *Env) run(name string, args ...string) *Result {
+	e.t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	command := exec.Command(name, args...)
+	command.Dir = e.Dir
+	command.Env = e.env
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	err := command.Run()
+	result := &Result{Stdout: stdout.String(), Stderr: stderr.String()}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			e.t.Fatalf("unable to run %s: %s", name, err.Error())
+		}
+		result.ExitCode = exitErr.ExitCode()
+	}
+
+	return result
+}
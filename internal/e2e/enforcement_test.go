@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build e2e
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/cmd/exitcodes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerificationEnforcement(t *testing.T) {
+	e := NewEnv(t, gittufPath)
+	repo := e.InitRepository("repo")
+
+	repo.Commit("README.md", "Hello, world!\n", "Initial commit")
+	repo.MustGittuf("rsl", "record", "main")
+
+	// The update isn't recorded in the RSL, so verification fails
+	repo.Commit("README.md", "Hello, gittuf!\n", "Update README")
+
+	t.Run("enforce", func(t *testing.T) {
+		result := repo.Gittuf("verify-ref", "main")
+		assert.Equal(t, exitcodes.RSLDivergence, result.ExitCode)
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		result := repo.Gittuf("verify-ref", "--enforcement", "warn", "main")
+		assert.Equal(t, exitcodes.VerificationWarning, result.ExitCode)
+	})
+
+	t.Run("audit", func(t *testing.T) {
+		result := repo.Gittuf("verify-ref", "--enforcement", "audit", "main")
+		assert.Equal(t, exitcodes.OK, result.ExitCode)
+	})
+
+	t.Run("warn using config", func(t *testing.T) {
+		repo.Git("config", "gittuf.verify.enforcement", "warn")
+		defer repo.Git("config", "--unset", "gittuf.verify.enforcement")
+
+		result := repo.Gittuf("verify-ref", "main")
+		assert.Equal(t, exitcodes.VerificationWarning, result.ExitCode)
+
+		result = repo.Gittuf("verify-ref", "--enforcement", "enforce", "main")
+		assert.Equal(t, exitcodes.RSLDivergence, result.ExitCode)
+	})
+}
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build e2e
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/cmd/exitcodes"
+	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHubRelease(t *testing.T) {
+	stub := NewGitHubAPIStub(t)
+	stub.Handle("/repos/gittuf/gittuf/releases/tags/v1", `{"id": 1, "tag_name": "v1", "html_url": "https://github.com/gittuf/gittuf/releases/tag/v1", "assets": [{"id": 1, "name": "gittuf_linux_amd64"}]}`)
+	stub.Handle("/repos/gittuf/gittuf/releases/assets/1", "gittuf linux binary")
+
+	e := NewEnv(t, gittufPath)
+	e.Setenv(dev.DevModeKey, "1")
+	e.Setenv(repository.GitHubAPIURLEnvKey, stub.URL)
+
+	repo := e.InitRepository("repo")
+	repo.Commit("README.md", "Hello, world!\n", "Initial commit")
+	repo.MustGittuf("rsl", "record", "main")
+	repo.Git("tag", "-m", "v1", "v1")
+	repo.MustGittuf("rsl", "record", "v1")
+
+	repo.MustGittuf("dev", "attest-github-release", "-k", e.Key("policy"), "--repository", "gittuf/gittuf", "v1")
+	repo.MustGittuf("verify-github-release", "--repository", "gittuf/gittuf", "v1")
+
+	// The published asset is replaced after the release was attested
+	stub.Handle("/repos/gittuf/gittuf/releases/assets/1", "malicious binary")
+	result := repo.Gittuf("verify-github-release", "--repository", "gittuf/gittuf", "v1")
+	assert.Equal(t, exitcodes.PolicyViolation, result.ExitCode, result.Stderr)
+}
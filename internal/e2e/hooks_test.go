@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build e2e
+
+package e2e
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferenceTransactionHook(t *testing.T) {
+	e := NewEnv(t, gittufPath)
+	remotePath := filepath.Join(e.Home, "remote.git")
+	e.Git("init", "--bare", remotePath)
+	e.InDir(remotePath).MustGittuf("add-hooks", "--reference-transaction")
+
+	repo := e.InitRepository("repo")
+	repo.Git("remote", "add", "origin", remotePath)
+
+	recordedID := repo.Commit("README.md", "Hello, world!\n", "Initial commit")
+	repo.MustGittuf("rsl", "record", "main")
+	repo.MustGittuf("rsl", "remote", "push", "origin")
+	repo.MustGittuf("policy", "remote", "push", "origin")
+	repo.Git("push", "origin", "main")
+	assert.Equal(t, recordedID, e.InDir(remotePath).Git("rev-parse", "main"))
+
+	t.Run("update not recorded in RSL is rejected", func(t *testing.T) {
+		repo.Commit("README.md", "Hello, gittuf!\n", "Update README")
+
+		result := repo.run("git", "push", "origin", "main")
+		assert.NotEqual(t, 0, result.ExitCode)
+		assert.Contains(t, result.Stderr, "Rejecting update to 'refs/heads/main'")
+		assert.Equal(t, recordedID, e.InDir(remotePath).Git("rev-parse", "main"))
+	})
+
+	t.Run("update not made by a push is rejected", func(t *testing.T) {
+		remote := e.InDir(remotePath)
+		result := remote.run("git", "update-ref", "refs/heads/main", repo.Git("rev-parse", "main"))
+		assert.NotEqual(t, 0, result.ExitCode)
+		assert.Equal(t, recordedID, remote.Git("rev-parse", "main"))
+	})
+
+	t.Run("recorded update is accepted", func(t *testing.T) {
+		repo.MustGittuf("rsl", "record", "main")
+		repo.MustGittuf("rsl", "remote", "push", "origin")
+		repo.Git("push", "origin", "main")
+		assert.Equal(t, repo.Git("rev-parse", "main"), e.InDir(remotePath).Git("rev-parse", "main"))
+	})
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build e2e
+
+package e2e
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var (
+	gittufPath string
+
+	// gittufSHA256Path is the path to gittuf built for repositories that use
+	// the SHA-256 object format
+	gittufSHA256Path string
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	binaryDir, err := os.MkdirTemp("", "gittuf-e2e")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+	defer os.RemoveAll(binaryDir) //nolint:errcheck
+
+	gittufPath, err = BuildGittuf(binaryDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	sha256Dir := filepath.Join(binaryDir, "sha256")
+	if err := os.Mkdir(sha256Dir, 0o755); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+	gittufSHA256Path, err = BuildGittuf(sha256Dir, "sha256")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	return m.Run()
+}
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build e2e
+
+package e2e
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSHA256Repository(t *testing.T) {
+	alice := NewEnv(t, gittufSHA256Path)
+	aliceRepo := alice.InitRepositoryWithObjectFormat("repo", "sha256")
+	aliceRepo.Commit("README.md", "Hello, world!\n", "Initial commit")
+	aliceRepo.MustGittuf("rsl", "record", "main")
+	aliceRepo.MustGittuf("verify-ref", "main")
+
+	remoteURL := filepath.Join(alice.Home, "remote.git")
+	alice.Git("init", "--bare", "--object-format", "sha256", remoteURL)
+
+	aliceRepo.Git("remote", "add", "origin", remoteURL)
+	aliceRepo.MustGittuf("rsl", "remote", "push", "origin")
+	aliceRepo.MustGittuf("policy", "remote", "push", "origin")
+	aliceRepo.Git("push", "origin", "main")
+
+	t.Run("clone and pull", func(t *testing.T) {
+		bob := NewEnv(t, gittufSHA256Path)
+		bob.MustGittuf("clone", "--root-key", bob.PublicKey("root"), "--branch", "main", remoteURL, "repo")
+		bobRepo := bob.InDir("repo")
+		bobRepo.MustGittuf("verify-ref", "main")
+		assert.Equal(t, aliceRepo.Git("rev-parse", "main"), bobRepo.Git("rev-parse", "main"))
+
+		aliceRepo.Commit("README.md", "Hello, gittuf!\n", "Update README")
+		aliceRepo.MustGittuf("rsl", "record", "main")
+		aliceRepo.MustGittuf("rsl", "remote", "push", "origin")
+		aliceRepo.Git("push", "origin", "main")
+
+		bobRepo.MustGittuf("rsl", "remote", "pull", "origin")
+		bobRepo.Git("pull", "--ff-only", "origin", "main")
+		bobRepo.MustGittuf("verify-ref", "main")
+		assert.Equal(t, aliceRepo.Git("rev-parse", "refs/gittuf/reference-state-log"), bobRepo.Git("rev-parse", "refs/gittuf/reference-state-log"))
+	})
+
+	t.Run("SHA-1 build refuses repository", func(t *testing.T) {
+		e := NewEnv(t, gittufPath).InDir(aliceRepo.Dir)
+		result := e.Gittuf("verify-ref", "main")
+		assert.NotEqual(t, 0, result.ExitCode)
+		assert.Contains(t, result.Stderr, "object format")
+	})
+}
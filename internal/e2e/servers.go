@@ -0,0 +1,415 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build e2e
+
+package e2e
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/cgi"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// HTTPServer is a Git server using the smart HTTP protocol, served by git
+// http-backend. Pushes are accepted without authentication.
+type HTTPServer struct {
+	root   string
+	server *httptest.Server
+}
+
+// NewHTTPServer starts an HTTP server for Git repositories, which is stopped
+// when the test completes.
+func NewHTTPServer(t *testing.T) *HTTPServer {
+	t.Helper()
+
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	handler := &cgi.Handler{
+		Path: gitPath,
+		Args: []string{"http-backend"},
+		Env: []string{
+			"GIT_PROJECT_ROOT=" + root,
+			"GIT_HTTP_EXPORT_ALL=1",
+			"GIT_CONFIG_NOSYSTEM=1",
+		},
+		Stderr: io.Discard,
+	}
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return &HTTPServer{root: root, server: server}
+}
+
+// CreateRepository creates an empty bare repository on the server, returning
+// its URL.
+func (s *HTTPServer) CreateRepository(t *testing.T, name string) string {
+	t.Helper()
+
+	repoPath := createBareRepository(t, s.root, name)
+	runGit(t, repoPath, "config", "http.receivepack", "true")
+
+	return s.server.URL + "/" + name
+}
+
+// SSHServer is a Git server using the SSH protocol. It runs git-upload-pack
+// and git-receive-pack for clients that authenticate using its client key,
+// which is served by an SSH agent.
+type SSHServer struct {
+	root     string
+	address  string
+	listener net.Listener
+
+	// AgentSocket is the path to the socket of the SSH agent holding the
+	// client key, to be used as SSH_AUTH_SOCK.
+	AgentSocket string
+
+	// KnownHostsFile is the path to a known_hosts file holding the server's
+	// host key.
+	KnownHostsFile string
+
+	wg sync.WaitGroup
+}
+
+// NewSSHServer starts an SSH server for Git repositories, which is stopped
+// when the test completes.
+func NewSSHServer(t *testing.T) *SSHServer {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	_, hostPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostSigner, err := ssh.NewSignerFromKey(hostPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, clientPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientSigner, err := ssh.NewSignerFromKey(clientPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientPublicKey := clientSigner.PublicKey().Marshal()
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(clientPublicKey) {
+				return nil, fmt.Errorf("unknown public key")
+			}
+			return &ssh.Permissions{}, nil
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unix socket paths are limited in length, so the agent's socket isn't
+	// placed in the test's temporary directory
+	agentDir, err := os.MkdirTemp("", "gittuf-e2e-agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	agentSocket := filepath.Join(agentDir, "agent.sock")
+	agentListener, err := net.Listen("unix", agentSocket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: clientPrivateKey}); err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	knownHostsFile := filepath.Join(t.TempDir(), "known_hosts")
+	knownHostsLine := knownhosts.Line([]string{knownhosts.Normalize(listener.Addr().String())}, hostSigner.PublicKey())
+	if err := os.WriteFile(knownHostsFile, []byte(knownHostsLine+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &SSHServer{
+		root:           root,
+		address:        listener.Addr().String(),
+		listener:       listener,
+		AgentSocket:    agentSocket,
+		KnownHostsFile: knownHostsFile,
+	}
+
+	s.wg.Add(2)
+	go func() {
+		defer s.wg.Done()
+		s.serveAgent(agentListener, keyring)
+	}()
+	go func() {
+		defer s.wg.Done()
+		s.serve(config)
+	}()
+
+	t.Cleanup(func() {
+		listener.Close()      //nolint:errcheck
+		agentListener.Close() //nolint:errcheck
+		s.wg.Wait()
+		os.RemoveAll(agentDir) //nolint:errcheck
+	})
+
+	return s
+}
+
+// CreateRepository creates an empty bare repository on the server, returning
+// its URL.
+func (s *SSHServer) CreateRepository(t *testing.T, name string) string {
+	t.Helper()
+
+	createBareRepository(t, s.root, name)
+
+	return fmt.Sprintf("ssh://git@%s/%s", s.address, name)
+}
+
+// Configure sets up the environment to connect to the server, using the
+// server's SSH agent and known_hosts file.
+func (s *SSHServer) Configure(e *Env) {
+	e.Setenv("SSH_AUTH_SOCK", s.AgentSocket)
+	e.Git("config", "--global", "gittuf.ssh.knownhostsfile", s.KnownHostsFile)
+	e.Git("config", "--global", "core.sshCommand", fmt.Sprintf("ssh -o UserKnownHostsFile=%s -o StrictHostKeyChecking=yes", s.KnownHostsFile))
+}
+
+func (s *SSHServer) serveAgent(listener net.Listener, keyring agent.Agent) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()              //nolint:errcheck
+			agent.ServeAgent(keyring, conn) //nolint:errcheck
+		}()
+	}
+}
+
+func (s *SSHServer) serve(config *ssh.ServerConfig) {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handleConn(conn, config)
+		}()
+	}
+}
+
+func (s *SSHServer) handleConn(conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close() //nolint:errcheck
+
+	serverConn, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	defer serverConn.Close() //nolint:errcheck
+
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "only sessions are supported") //nolint:errcheck
+			continue
+		}
+
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handleSession(channel, channelRequests)
+		}()
+	}
+}
+
+// handleSession runs the Git service requested by the session's exec request,
+// such as git-upload-pack '/repo.git'. Other requests are refused.
+func (s *SSHServer) handleSession(channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer channel.Close() //nolint:errcheck
+
+	for request := range requests {
+		if request.Type != "exec" {
+			request.Reply(false, nil) //nolint:errcheck
+			continue
+		}
+
+		command, err := parseExecPayload(request.Payload)
+		if err != nil {
+			request.Reply(false, nil) //nolint:errcheck
+			return
+		}
+
+		service, repoPath, err := s.parseGitCommand(command)
+		if err != nil {
+			request.Reply(false, nil) //nolint:errcheck
+			return
+		}
+		request.Reply(true, nil) //nolint:errcheck
+
+		gitCommand := exec.Command("git", service, repoPath)
+		gitCommand.Env = append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1")
+		gitCommand.Stdin = channel
+		gitCommand.Stdout = channel
+		gitCommand.Stderr = channel.Stderr()
+
+		exitStatus := uint32(0)
+		if err := gitCommand.Run(); err != nil {
+			exitStatus = 1
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				exitStatus = uint32(exitErr.ExitCode())
+			}
+		}
+
+		channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{exitStatus})) //nolint:errcheck
+		return
+	}
+}
+
+// parseGitCommand returns the Git service and the repository's path for
+// commands of the form git-upload-pack '/repo.git'.
+func (s *SSHServer) parseGitCommand(command string) (string, string, error) {
+	serviceName, repoArg, found := strings.Cut(command, " ")
+	if !found {
+		return "", "", fmt.Errorf("invalid command '%s'", command)
+	}
+
+	var service string
+	switch serviceName {
+	case "git-upload-pack":
+		service = "upload-pack"
+	case "git-receive-pack":
+		service = "receive-pack"
+	default:
+		return "", "", fmt.Errorf("unsupported service '%s'", serviceName)
+	}
+
+	repoName := path.Clean("/" + strings.Trim(repoArg, "'\""))
+	return service, filepath.Join(s.root, filepath.FromSlash(repoName)), nil
+}
+
+func parseExecPayload(payload []byte) (string, error) {
+	if len(payload) < 4 {
+		return "", fmt.Errorf("invalid exec request")
+	}
+
+	length := binary.BigEndian.Uint32(payload[:4])
+	if uint64(len(payload)-4) < uint64(length) {
+		return "", fmt.Errorf("invalid exec request")
+	}
+
+	return string(payload[4 : 4+length]), nil
+}
+
+// GitHubAPIStub is a stub of the GitHub REST API, which serves the responses
+// registered with it. gittuf uses it when GITHUB_API_URL is set to its URL.
+type GitHubAPIStub struct {
+	mu        sync.Mutex
+	responses map[string]string
+
+	// URL is the base URL of the stub.
+	URL string
+}
+
+// NewGitHubAPIStub starts a GitHub API stub, which is stopped when the test
+// completes.
+func NewGitHubAPIStub(t *testing.T) *GitHubAPIStub {
+	t.Helper()
+
+	stub := &GitHubAPIStub{responses: map[string]string{}}
+	server := httptest.NewServer(http.HandlerFunc(stub.serveHTTP))
+	t.Cleanup(server.Close)
+	stub.URL = server.URL
+
+	return stub
+}
+
+// Handle sets the body of responses to GET requests for the path, such as
+// /repos/gittuf/gittuf/releases/tags/v1.
+func (s *GitHubAPIStub) Handle(path, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responses[path] = body
+}
+
+func (s *GitHubAPIStub) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	body, has := s.responses[r.URL.Path]
+	s.mu.Unlock()
+
+	if r.Method != http.MethodGet || !has {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message": "Not Found"}`)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, body)
+}
+
+func createBareRepository(t *testing.T, root, name string) string {
+	t.Helper()
+
+	repoPath := filepath.Join(root, name)
+	runGit(t, root, "init", "--bare", "--initial-branch", "main", repoPath)
+
+	return repoPath
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	command := exec.Command("git", args...)
+	command.Dir = dir
+	command.Env = append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1")
+	if output, err := command.CombinedOutput(); err != nil {
+		t.Fatalf("git %s failed: %s: %s", strings.Join(args, " "), err.Error(), string(output))
+	}
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build e2e
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/cmd/exitcodes"
+	"github.com/stretchr/testify/assert"
+)
+
+// remote is a Git server a test repository is created on, and the setup
+// needed for an environment to connect to it.
+type remote struct {
+	createRepository func(t *testing.T, name string) string
+	configure        func(e *Env)
+}
+
+var remotes = map[string]func(t *testing.T) *remote{
+	"http": func(t *testing.T) *remote {
+		server := NewHTTPServer(t)
+		return &remote{createRepository: server.CreateRepository, configure: func(*Env) {}}
+	},
+	"ssh": func(t *testing.T) *remote {
+		server := NewSSHServer(t)
+		return &remote{createRepository: server.CreateRepository, configure: server.Configure}
+	},
+}
+
+func TestSync(t *testing.T) {
+	for name, newRemote := range remotes {
+		t.Run(name, func(t *testing.T) {
+			server := newRemote(t)
+			remoteURL := server.createRepository(t, "repo.git")
+
+			alice := NewEnv(t, gittufPath)
+			server.configure(alice)
+			aliceRepo := alice.InitRepository("repo")
+			aliceRepo.Commit("README.md", "Hello, world!\n", "Initial commit")
+			aliceRepo.MustGittuf("rsl", "record", "main")
+
+			aliceRepo.Git("remote", "add", "origin", remoteURL)
+			aliceRepo.MustGittuf("rsl", "remote", "push", "origin")
+			aliceRepo.MustGittuf("policy", "remote", "push", "origin")
+			aliceRepo.Git("push", "origin", "main")
+
+			t.Run("clone verifies the repository", func(t *testing.T) {
+				bob := NewEnv(t, gittufPath)
+				server.configure(bob)
+				bob.MustGittuf("clone", "--root-key", bob.PublicKey("root"), remoteURL, "repo")
+
+				bobRepo := bob.InDir("repo")
+				bobRepo.MustGittuf("verify-ref", "main")
+				assert.Equal(t, aliceRepo.Git("rev-parse", "main"), bobRepo.Git("rev-parse", "main"))
+			})
+
+			t.Run("pull fetches new RSL entries", func(t *testing.T) {
+				bob := NewEnv(t, gittufPath)
+				server.configure(bob)
+				bob.MustGittuf("clone", remoteURL, "repo")
+				bobRepo := bob.InDir("repo")
+
+				aliceRepo.Commit("README.md", "Hello, gittuf!\n", "Update README")
+				aliceRepo.MustGittuf("rsl", "record", "main")
+				aliceRepo.MustGittuf("rsl", "remote", "push", "origin")
+				aliceRepo.Git("push", "origin", "main")
+
+				bobRepo.MustGittuf("rsl", "remote", "pull", "origin")
+				bobRepo.Git("pull", "--ff-only", "origin", "main")
+				bobRepo.MustGittuf("verify-ref", "main")
+				assert.Equal(t, aliceRepo.Git("rev-parse", "refs/gittuf/reference-state-log"), bobRepo.Git("rev-parse", "refs/gittuf/reference-state-log"))
+			})
+
+			t.Run("diverged RSL is rejected", func(t *testing.T) {
+				bob := NewEnv(t, gittufPath)
+				server.configure(bob)
+				bob.MustGittuf("clone", remoteURL, "repo")
+				bobRepo := bob.InDir("repo")
+
+				aliceRepo.Commit("alice.txt", "alice\n", "Add alice.txt")
+				aliceRepo.MustGittuf("rsl", "record", "main")
+				aliceRepo.MustGittuf("rsl", "remote", "push", "origin")
+
+				bobRepo.Commit("bob.txt", "bob\n", "Add bob.txt")
+				bobRepo.MustGittuf("rsl", "record", "main")
+
+				result := bobRepo.Gittuf("rsl", "remote", "push", "origin")
+				assert.Equal(t, exitcodes.RSLDivergence, result.ExitCode, result.Stderr)
+
+				result = bobRepo.Gittuf("rsl", "remote", "pull", "origin")
+				assert.Equal(t, exitcodes.RSLDivergence, result.ExitCode, result.Stderr)
+			})
+		})
+	}
+}
+
+func TestUnreachableRemote(t *testing.T) {
+	server := NewHTTPServer(t)
+	remoteURL := server.CreateRepository(t, "repo.git")
+
+	e := NewEnv(t, gittufPath)
+	result := e.Gittuf("clone", remoteURL+"-missing", "repo")
+	assert.Equal(t, exitcodes.TransportError, result.ExitCode, result.Stderr)
+}
@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package githubapp implements the webhook handler for running gittuf as a
+// GitHub App. The handler listens for pull request review events and records
+// the approvals of pull requests as signed attestations in the repository.
+package githubapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/google/go-github/v61/github"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+const (
+	pingEventType              = "ping"
+	pullRequestReviewEventType = "pull_request_review"
+
+	reviewActionSubmitted = "submitted"
+	reviewActionDismissed = "dismissed"
+	reviewStateApproved   = "approved"
+)
+
+var ErrUnexpectedRepository = errors.New("webhook event is for an unexpected repository")
+
+// Repository is the subset of the gittuf repository's functionality used to
+// record approvals.
+type Repository interface {
+	AddGitHubPullRequestApprover(ctx context.Context, signer sslibdsse.SignerVerifier, owner, repository string, pullRequestNumber int, baseBranch, commitID, approver string, signCommit bool) error
+	DismissGitHubPullRequestApprover(ctx context.Context, signer sslibdsse.SignerVerifier, owner, repository string, pullRequestNumber int, baseBranch, commitID, approver string, signCommit bool) error
+	PullAttestations(ctx context.Context, remoteName string) error
+	PushAttestations(ctx context.Context, remoteName string) error
+}
+
+// Options configures the webhook handler.
+type Options struct {
+	// Owner and Repository identify the GitHub repository the handler records
+	// approvals for. Events for other repositories are rejected.
+	Owner      string
+	Repository string
+
+	// WebhookSecret is the secret configured for the GitHub App's webhook,
+	// used to authenticate events.
+	WebhookSecret []byte
+
+	// Signer is used to sign the approval attestations.
+	Signer sslibdsse.SignerVerifier
+
+	// SignCommit indicates if the attestation and RSL commits are signed.
+	SignCommit bool
+
+	// RemoteName is the remote the attestations are pulled from before and
+	// pushed to after they are updated. If empty, the attestations are only
+	// updated locally.
+	RemoteName string
+}
+
+// Handler is an http.Handler that processes GitHub webhook events.
+type Handler struct {
+	repo    Repository
+	options *Options
+
+	// mu serializes updates to the attestations, as concurrent deliveries
+	// would otherwise race to update the attestations and RSL refs.
+	mu sync.Mutex
+}
+
+// NewHandler returns a webhook handler that records approvals in the repository.
+func NewHandler(repo Repository, options *Options) *Handler {
+	return &Handler{repo: repo, options: options}
+}
+
+// WithLock runs fn while no webhook delivery is being processed, such as to
+// perform maintenance on the repository.
+func (h *Handler) WithLock(fn func() error) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return fn()
+}
+
+// ServeHTTP authenticates and processes a single webhook delivery.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := github.ValidatePayload(r, h.options.WebhookSecret)
+	if err != nil {
+		slog.Debug(fmt.Sprintf("Rejecting webhook delivery: %s", err.Error()))
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	eventType := github.WebHookType(r)
+	switch eventType {
+	case pingEventType:
+		slog.Debug("Received ping event")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case pullRequestReviewEventType:
+	default:
+		slog.Debug(fmt.Sprintf("Ignoring '%s' event", eventType))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	event, err := github.ParseWebHook(eventType, payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.handlePullRequestReview(r.Context(), event.(*github.PullRequestReviewEvent)); err != nil {
+		if errors.Is(err, ErrUnexpectedRepository) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		slog.Error(fmt.Sprintf("Unable to process pull request review: %s", err.Error()))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlePullRequestReview records the approval for submitted approving reviews
+// and removes it for dismissed reviews. Other reviews are ignored.
+func (h *Handler) handlePullRequestReview(ctx context.Context, event *github.PullRequestReviewEvent) error {
+	owner := event.GetRepo().GetOwner().GetLogin()
+	repository := event.GetRepo().GetName()
+	if !strings.EqualFold(owner, h.options.Owner) || !strings.EqualFold(repository, h.options.Repository) {
+		return fmt.Errorf("%w: '%s/%s'", ErrUnexpectedRepository, owner, repository)
+	}
+
+	var update func(context.Context, sslibdsse.SignerVerifier, string, string, int, string, string, string, bool) error
+	switch action := event.GetAction(); {
+	case action == reviewActionSubmitted && strings.EqualFold(event.GetReview().GetState(), reviewStateApproved):
+		update = h.repo.AddGitHubPullRequestApprover
+	case action == reviewActionDismissed:
+		update = h.repo.DismissGitHubPullRequestApprover
+	default:
+		slog.Debug(fmt.Sprintf("Ignoring pull request review with action '%s' and state '%s'", action, event.GetReview().GetState()))
+		return nil
+	}
+
+	pullRequestNumber := event.GetPullRequest().GetNumber()
+	baseBranch := gitinterface.BranchReferenceName(event.GetPullRequest().GetBase().GetRef())
+	commitID := event.GetReview().GetCommitID()
+	approver := event.GetReview().GetUser().GetLogin()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.options.RemoteName != "" {
+		if err := h.repo.PullAttestations(ctx, h.options.RemoteName); err != nil {
+			return err
+		}
+	}
+
+	slog.Debug(fmt.Sprintf("Updating approval by '%s' of pull request %d at '%s'...", approver, pullRequestNumber, commitID))
+	if err := update(ctx, h.options.Signer, owner, repository, pullRequestNumber, baseBranch, commitID, approver, h.options.SignCommit); err != nil {
+		return err
+	}
+
+	if h.options.RemoteName != "" {
+		return h.repo.PushAttestations(ctx, h.options.RemoteName)
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package githubapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+var testWebhookSecret = []byte("secret")
+
+type approval struct {
+	owner             string
+	repository        string
+	pullRequestNumber int
+	baseBranch        string
+	commitID          string
+	approver          string
+}
+
+type fakeRepository struct {
+	added     []approval
+	dismissed []approval
+	pulled    []string
+	pushed    []string
+}
+
+func (f *fakeRepository) AddGitHubPullRequestApprover(_ context.Context, _ sslibdsse.SignerVerifier, owner, repository string, pullRequestNumber int, baseBranch, commitID, approver string, _ bool) error {
+	f.added = append(f.added, approval{owner, repository, pullRequestNumber, baseBranch, commitID, approver})
+	return nil
+}
+
+func (f *fakeRepository) DismissGitHubPullRequestApprover(_ context.Context, _ sslibdsse.SignerVerifier, owner, repository string, pullRequestNumber int, baseBranch, commitID, approver string, _ bool) error {
+	f.dismissed = append(f.dismissed, approval{owner, repository, pullRequestNumber, baseBranch, commitID, approver})
+	return nil
+}
+
+func (f *fakeRepository) PullAttestations(_ context.Context, remoteName string) error {
+	f.pulled = append(f.pulled, remoteName)
+	return nil
+}
+
+func (f *fakeRepository) PushAttestations(_ context.Context, remoteName string) error {
+	f.pushed = append(f.pushed, remoteName)
+	return nil
+}
+
+func TestHandler(t *testing.T) {
+	reviewPayload := func(repository, action, state string) []byte {
+		return []byte(fmt.Sprintf(`{
+	"action": "%s",
+	"review": {"id": 1, "state": "%s", "commit_id": "abcdef", "user": {"login": "alice"}},
+	"pull_request": {"number": 3, "base": {"ref": "main"}},
+	"repository": {"name": "%s", "owner": {"login": "gittuf"}}
+}`, action, state, repository))
+	}
+
+	tests := map[string]struct {
+		eventType         string
+		payload           []byte
+		secret            []byte
+		expectedStatus    int
+		expectedAdded     []approval
+		expectedDismissed []approval
+	}{
+		"approving review": {
+			eventType:      pullRequestReviewEventType,
+			payload:        reviewPayload("gittuf", reviewActionSubmitted, reviewStateApproved),
+			secret:         testWebhookSecret,
+			expectedStatus: http.StatusNoContent,
+			expectedAdded:  []approval{{"gittuf", "gittuf", 3, "refs/heads/main", "abcdef", "alice"}},
+		},
+		"dismissed review": {
+			eventType:         pullRequestReviewEventType,
+			payload:           reviewPayload("gittuf", reviewActionDismissed, "dismissed"),
+			secret:            testWebhookSecret,
+			expectedStatus:    http.StatusNoContent,
+			expectedDismissed: []approval{{"gittuf", "gittuf", 3, "refs/heads/main", "abcdef", "alice"}},
+		},
+		"review requesting changes": {
+			eventType:      pullRequestReviewEventType,
+			payload:        reviewPayload("gittuf", reviewActionSubmitted, "changes_requested"),
+			secret:         testWebhookSecret,
+			expectedStatus: http.StatusNoContent,
+		},
+		"review for unexpected repository": {
+			eventType:      pullRequestReviewEventType,
+			payload:        reviewPayload("other", reviewActionSubmitted, reviewStateApproved),
+			secret:         testWebhookSecret,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"invalid signature": {
+			eventType:      pullRequestReviewEventType,
+			payload:        reviewPayload("gittuf", reviewActionSubmitted, reviewStateApproved),
+			secret:         []byte("incorrect"),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"ping event": {
+			eventType:      pingEventType,
+			payload:        []byte(`{"zen": "Keep it logically awesome."}`),
+			secret:         testWebhookSecret,
+			expectedStatus: http.StatusNoContent,
+		},
+		"unhandled event": {
+			eventType:      "push",
+			payload:        []byte(`{}`),
+			secret:         testWebhookSecret,
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo := &fakeRepository{}
+			handler := NewHandler(repo, &Options{
+				Owner:         "gittuf",
+				Repository:    "gittuf",
+				WebhookSecret: testWebhookSecret,
+				RemoteName:    "origin",
+			})
+
+			mac := hmac.New(sha256.New, test.secret)
+			mac.Write(test.payload)
+
+			request := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(test.payload))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("X-GitHub-Event", test.eventType)
+			request.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code, fmt.Sprintf("unexpected status in test '%s'", name))
+			assert.Equal(t, test.expectedAdded, repo.added, fmt.Sprintf("unexpected approvals in test '%s'", name))
+			assert.Equal(t, test.expectedDismissed, repo.dismissed, fmt.Sprintf("unexpected dismissals in test '%s'", name))
+
+			if len(test.expectedAdded) != 0 || len(test.expectedDismissed) != 0 {
+				assert.Equal(t, []string{"origin"}, repo.pulled)
+				assert.Equal(t, []string{"origin"}, repo.pushed)
+			} else {
+				assert.Empty(t, repo.pushed)
+			}
+		})
+	}
+
+	t.Run("method not allowed", func(t *testing.T) {
+		handler := NewHandler(&fakeRepository{}, &Options{WebhookSecret: testWebhookSecret})
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	})
+}
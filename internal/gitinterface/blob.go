@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/gittuf/gittuf/internal/perf"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
@@ -90,6 +91,8 @@ func (r *Repository) WriteBlob(contents []byte) (Hash, error) {
 
 // GetBlob returns the requested blob object.
 func GetBlob(repo *git.Repository, blobID plumbing.Hash) (*object.Blob, error) {
+	defer perf.Track(perf.ObjectReads)()
+
 	return repo.BlobObject(blobID)
 }
 
@@ -46,7 +46,7 @@ func TestReadBlob(t *testing.T) {
 	}
 
 	t.Run("test expected file", func(t *testing.T) {
-		expectedHash := "2ecdd330475d93568ed27f717a84a7fe207d1c58"
+		expectedHash := hashForBuildObjectFormat("2ecdd330475d93568ed27f717a84a7fe207d1c58", "047894a12f246539044e561e8b744b7c99dad81f041db3aceb79c8e1cd4375f8")
 
 		contents, err := ReadBlob(repo, plumbing.NewHash(expectedHash))
 		if err != nil {
@@ -84,7 +84,7 @@ func TestRepositoryReadBlob(t *testing.T) {
 	repo := CreateTestGitRepository(t, tempDir)
 
 	contents := []byte("test file read")
-	expectedBlobID, err := NewHash("2ecdd330475d93568ed27f717a84a7fe207d1c58")
+	expectedBlobID, err := NewHash(hashForBuildObjectFormat("2ecdd330475d93568ed27f717a84a7fe207d1c58", "047894a12f246539044e561e8b744b7c99dad81f041db3aceb79c8e1cd4375f8"))
 	require.Nil(t, err)
 
 	blobID, err := repo.WriteBlob(contents)
@@ -118,7 +118,7 @@ func TestWriteBlob(t *testing.T) {
 		t.Error(err)
 	}
 
-	expectedHash := plumbing.NewHash("999c05e9578e5d244920306842f516789a2498f7")
+	expectedHash := plumbing.NewHash(hashForBuildObjectFormat("999c05e9578e5d244920306842f516789a2498f7", "baebf03e05fd2d60f0e2eb3728c29823cff29defbd809bde369a92f53abb670c"))
 	assert.Equal(t, expectedHash, blobID)
 
 	obj, err := GetBlob(repo, blobID)
@@ -143,7 +143,7 @@ func TestRepositoryWriteBlob(t *testing.T) {
 	repo := CreateTestGitRepository(t, tempDir)
 
 	contents := []byte("test file write")
-	expectedBlobID, err := NewHash("999c05e9578e5d244920306842f516789a2498f7")
+	expectedBlobID, err := NewHash(hashForBuildObjectFormat("999c05e9578e5d244920306842f516789a2498f7", "baebf03e05fd2d60f0e2eb3728c29823cff29defbd809bde369a92f53abb670c"))
 	require.Nil(t, err)
 
 	blobID, err := repo.WriteBlob(contents)
@@ -154,7 +154,7 @@ func TestRepositoryWriteBlob(t *testing.T) {
 func TestEmptyBlob(t *testing.T) {
 	hash := EmptyBlob()
 
-	// SHA-1 ID used by Git to denote an empty blob
+	// ID used by Git to denote an empty blob
 	// $ git hash-object -t blob --stdin < /dev/null
-	assert.Equal(t, "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391", hash.String())
+	assert.Equal(t, hashForBuildObjectFormat("e69de29bb2d1d6434b8b29ae775ad8c2e48c5391", "473a0f4c3be8a93681a267e3b1e9a7dcda1185436fe141f7749120a303721813"), hash.String())
 }
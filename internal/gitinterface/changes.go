@@ -5,26 +5,26 @@ package gitinterface
 import (
 	"container/heap"
 	"fmt"
+	"path"
 	"sort"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
 // GetCommitFilePaths returns all the file paths of the provided commit object.
 // This strictly enumerates all the files recursively in the commit object's
-// tree.
+// tree. Only trees are read, so the blobs need not be present, as is the case
+// in blobless partial clones.
 func GetCommitFilePaths(commit *object.Commit) ([]string, error) {
-	filesIter, err := commit.Files()
+	tree, err := commit.Tree()
 	if err != nil {
 		return nil, err
 	}
 
-	paths := []string{}
-	if err := filesIter.ForEach(func(f *object.File) error {
-		paths = append(paths, f.Name)
-		return nil
-	}); err != nil {
+	paths, err := getTreeFilePaths(tree, "")
+	if err != nil {
 		return nil, err
 	}
 
@@ -35,6 +35,36 @@ func GetCommitFilePaths(commit *object.Commit) ([]string, error) {
 	return paths, nil
 }
 
+// getTreeFilePaths is a helper that recursively enumerates the paths of all
+// the files in the tree, prefixed by the tree's path. Unlike go-git's tree
+// walker, an error is returned if a subtree is missing rather than skipping it.
+func getTreeFilePaths(tree *object.Tree, treePath string) ([]string, error) {
+	paths := []string{}
+	for _, entry := range tree.Entries {
+		entryPath := path.Join(treePath, entry.Name)
+
+		switch entry.Mode {
+		case filemode.Submodule:
+			continue
+		case filemode.Dir:
+			subtree, err := tree.Tree(entry.Name)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read tree '%s': %w", entryPath, err)
+			}
+
+			subtreePaths, err := getTreeFilePaths(subtree, entryPath)
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, subtreePaths...)
+		default:
+			paths = append(paths, entryPath)
+		}
+	}
+
+	return paths, nil
+}
+
 // GetFilePathsChangedByCommit returns the paths changed by the commit relative
 // to its parent commit. If the commit is a merge commit, i.e., it has more than
 // one parent, check if the commit is the same as at least one of its parents.
@@ -133,10 +163,12 @@ func GetDiffFilePaths(commitA, commitB *object.Commit) ([]string, error) {
 
 // diff is a helper that enumerates and sorts the paths of all files that differ
 // between the two trees. If a file is renamed, both its source name and
-// destination name are recorded.
+// destination name are recorded. Renames aren't detected as that requires
+// reading the blobs and the same paths are recorded for the deletion and
+// insertion anyway.
 func diff(treeA, treeB *object.Tree) ([]string, error) {
 	changesSet := map[string]bool{}
-	changes, err := treeA.Diff(treeB)
+	changes, err := object.DiffTree(treeA, treeB)
 	if err != nil {
 		return nil, err
 	}
@@ -162,6 +194,51 @@ func diff(treeA, treeB *object.Tree) ([]string, error) {
 	return paths, nil
 }
 
+// GetTopLevelPathsChanged returns the sorted names of the entries at the root
+// of the trees of the two commits that differ, i.e., the top-level files and
+// directories that have been added, removed, or modified. Unlike
+// GetDiffFilePaths, the trees are not compared recursively. If commitA is nil,
+// all of commitB's top-level entries are returned.
+func GetTopLevelPathsChanged(commitA, commitB *object.Commit) ([]string, error) {
+	if commitB == nil {
+		return nil, fmt.Errorf("commit to compare with cannot be empty")
+	}
+
+	treeB, err := commitB.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	entriesA := map[string]object.TreeEntry{}
+	if commitA != nil {
+		treeA, err := commitA.Tree()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range treeA.Entries {
+			entriesA[entry.Name] = entry
+		}
+	}
+
+	paths := []string{}
+	for _, entry := range treeB.Entries {
+		entryA, has := entriesA[entry.Name]
+		if !has || entryA.Hash != entry.Hash || entryA.Mode != entry.Mode {
+			paths = append(paths, entry.Name)
+		}
+		delete(entriesA, entry.Name)
+	}
+	for name := range entriesA {
+		// Remaining entries were removed
+		paths = append(paths, name)
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
 type diffHeap []string
 
 func (h diffHeap) Len() int           { return len(h) }
@@ -340,6 +340,100 @@ func TestGetDiffFilePaths(t *testing.T) {
 	})
 }
 
+func TestGetTopLevelPathsChanged(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blobIDs := []plumbing.Hash{}
+	for i := 0; i < 3; i++ {
+		blobID, err := WriteBlob(repo, []byte(fmt.Sprintf("%d", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		blobIDs = append(blobIDs, blobID)
+	}
+
+	subtreeA, err := WriteTree(repo, []object.TreeEntry{{Name: "x", Mode: filemode.Regular, Hash: blobIDs[0]}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	subtreeB, err := WriteTree(repo, []object.TreeEntry{{Name: "x", Mode: filemode.Regular, Hash: blobIDs[1]}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	treeA, err := WriteTree(repo, []object.TreeEntry{
+		{Name: "a", Mode: filemode.Regular, Hash: blobIDs[0]},
+		{Name: "dir", Mode: filemode.Dir, Hash: subtreeA},
+		{Name: "old", Mode: filemode.Regular, Hash: blobIDs[0]},
+		{Name: "same", Mode: filemode.Regular, Hash: blobIDs[2]},
+		{Name: "script", Mode: filemode.Regular, Hash: blobIDs[2]},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeB, err := WriteTree(repo, []object.TreeEntry{
+		{Name: "a", Mode: filemode.Regular, Hash: blobIDs[1]},
+		{Name: "dir", Mode: filemode.Dir, Hash: subtreeB},
+		{Name: "new", Mode: filemode.Regular, Hash: blobIDs[0]},
+		{Name: "same", Mode: filemode.Regular, Hash: blobIDs[2]},
+		{Name: "script", Mode: filemode.Executable, Hash: blobIDs[2]},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cAID, err := WriteCommit(repo, CreateCommitObject(testGitConfig, treeA, []plumbing.Hash{plumbing.ZeroHash}, "Test commit", testClock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cBID, err := WriteCommit(repo, CreateCommitObject(testGitConfig, treeB, []plumbing.Hash{cAID}, "Test commit", testClock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitA, err := GetCommit(repo, cAID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitB, err := GetCommit(repo, cBID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		commitA       *object.Commit
+		commitB       *object.Commit
+		expectedPaths []string
+	}{
+		"changes between commits": {
+			commitA:       commitA,
+			commitB:       commitB,
+			expectedPaths: []string{"a", "dir", "new", "old", "script"},
+		},
+		"no changes": {
+			commitA:       commitB,
+			commitB:       commitB,
+			expectedPaths: []string{},
+		},
+		"no prior commit": {
+			commitA:       nil,
+			commitB:       commitA,
+			expectedPaths: []string{"a", "dir", "old", "same", "script"},
+		},
+	}
+
+	for name, test := range tests {
+		paths, err := GetTopLevelPathsChanged(test.commitA, test.commitB)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		assert.Equal(t, test.expectedPaths, paths, fmt.Sprintf("unexpected paths in test '%s'", name))
+	}
+
+	_, err = GetTopLevelPathsChanged(commitA, nil)
+	assert.NotNil(t, err)
+}
+
 func TestGetFilePathsChangedByCommit(t *testing.T) {
 	repo, err := git.Init(memory.NewStorage(), memfs.New())
 	if err != nil {
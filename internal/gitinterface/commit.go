@@ -10,7 +10,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gittuf/gittuf/internal/perf"
 	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/x509"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
@@ -253,6 +255,18 @@ func VerifyCommitSignature(ctx context.Context, commit *object.Commit, key *tuf.
 			return errors.Join(ErrIncorrectVerificationKey, err)
 		}
 
+		return nil
+	case x509.X509KeyType:
+		commitContents, err := getCommitBytesWithoutSignature(commit)
+		if err != nil {
+			return errors.Join(ErrVerifyingX509Signature, err)
+		}
+		commitSignature := []byte(commit.PGPSignature)
+
+		if err := verifyX509Signature(ctx, key, commitContents, commitSignature); err != nil {
+			return errors.Join(ErrIncorrectVerificationKey, err)
+		}
+
 		return nil
 	case signerverifier.FulcioKeyType:
 		commitContents, err := getCommitBytesWithoutSignature(commit)
@@ -271,6 +285,29 @@ func VerifyCommitSignature(ctx context.Context, commit *object.Commit, key *tuf.
 	return ErrUnknownSigningMethod
 }
 
+// VerifyCommitSignatureAt verifies the commit's signature like
+// VerifyCommitSignature. Additionally, a GPG signature made using a key that
+// has since expired is accepted if the key was valid at recordedAt, the trusted
+// time the commit was recorded at, and the signature was created no later. If
+// recordedAt is zero, this is the same as VerifyCommitSignature.
+func VerifyCommitSignatureAt(ctx context.Context, commit *object.Commit, key *tuf.Key, recordedAt time.Time) error {
+	if key.KeyType != signerverifier.GPGKeyType || recordedAt.IsZero() {
+		return VerifyCommitSignature(ctx, commit, key)
+	}
+
+	commitContents, err := getCommitBytesWithoutSignature(commit)
+	if err != nil {
+		return err
+	}
+	commitSignature := []byte(commit.PGPSignature)
+
+	if err := verifyGPGSignatureAt(key, commitContents, commitSignature, recordedAt); err != nil {
+		return errors.Join(ErrIncorrectVerificationKey, err)
+	}
+
+	return nil
+}
+
 // verifyCommitSignature verifies a signature for the specified commit using
 // the provided public key.
 func (r *Repository) verifyCommitSignature(ctx context.Context, commitID Hash, key *tuf.Key) error {
@@ -279,7 +316,7 @@ func (r *Repository) verifyCommitSignature(ctx context.Context, commitID Hash, k
 		return fmt.Errorf("error opening repository: %w", err)
 	}
 
-	commit, err := goGitRepo.CommitObject(plumbing.NewHash(commitID.String()))
+	commit, err := GetCommit(goGitRepo, plumbing.NewHash(commitID.String()))
 	if err != nil {
 		return fmt.Errorf("unable to load commit object: %w", err)
 	}
@@ -302,6 +339,18 @@ func (r *Repository) verifyCommitSignature(ctx context.Context, commitID Hash, k
 			return errors.Join(ErrIncorrectVerificationKey, err)
 		}
 
+		return nil
+	case x509.X509KeyType:
+		commitContents, err := getCommitBytesWithoutSignature(commit)
+		if err != nil {
+			return errors.Join(ErrVerifyingX509Signature, err)
+		}
+		commitSignature := []byte(commit.PGPSignature)
+
+		if err := verifyX509Signature(ctx, key, commitContents, commitSignature); err != nil {
+			return errors.Join(ErrIncorrectVerificationKey, err)
+		}
+
 		return nil
 	case signerverifier.FulcioKeyType:
 		commitContents, err := getCommitBytesWithoutSignature(commit)
@@ -420,6 +469,8 @@ func CreateCommitObject(gitConfig *config.Config, treeHash plumbing.Hash, parent
 // path to commit. If commit is the same as the commit under test or if commit
 // is an ancestor of commit under test, KnowsCommit returns true.
 func KnowsCommit(repo *git.Repository, commitID plumbing.Hash, commit *object.Commit) (bool, error) {
+	defer perf.Track(perf.AncestryWalks)()
+
 	if commitID == commit.Hash {
 		return true, nil
 	}
@@ -447,9 +498,75 @@ func (r *Repository) KnowsCommit(testCommitID, ancestorCommitID Hash) (bool, err
 	return err == nil, nil
 }
 
-// GetCommit returns the requested commit object.
+// signatureHeaderSHA256 is the header Git records commit signatures in for
+// repositories that use the SHA-256 object format. go-git only reads
+// signatures recorded in the gpgsig header.
+const signatureHeaderSHA256 = "gpgsig-sha256"
+
+// GetCommit returns the requested commit object. When gittuf is built for the
+// SHA-256 object format, the commit's signature is also read from the
+// gpgsig-sha256 header.
 func GetCommit(repo *git.Repository, commitID plumbing.Hash) (*object.Commit, error) {
-	return repo.CommitObject(commitID)
+	defer perf.Track(perf.ObjectReads)()
+
+	commit, err := repo.CommitObject(commitID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(commit.PGPSignature) == 0 && BuildObjectFormat() == ObjectFormatSHA256 {
+		commitObj, err := repo.Storer.EncodedObject(plumbing.CommitObject, commitID)
+		if err != nil {
+			return nil, err
+		}
+		commit.PGPSignature, err = readSHA256CommitSignature(commitObj)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return commit, nil
+}
+
+// readSHA256CommitSignature returns the signature recorded in the commit's
+// gpgsig-sha256 header, or an empty string if the commit doesn't have the
+// header. Like the gpgsig header, the signature continues on subsequent lines
+// that start with a space.
+func readSHA256CommitSignature(commitObj plumbing.EncodedObject) (string, error) {
+	reader, err := commitObj.Reader()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close() //nolint:errcheck
+
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+
+	signature := strings.Builder{}
+	inSignature := false
+	for _, line := range strings.SplitAfter(string(contents), "\n") {
+		if inSignature {
+			if strings.HasPrefix(line, " ") {
+				signature.WriteString(strings.TrimLeft(line, " "))
+				continue
+			}
+			break
+		}
+
+		if strings.TrimSpace(line) == "" {
+			// The headers end at the first empty line
+			break
+		}
+
+		if value, has := strings.CutPrefix(line, signatureHeaderSHA256+" "); has {
+			signature.WriteString(value)
+			inSignature = true
+		}
+	}
+
+	return signature.String(), nil
 }
 
 func signCommit(commit *object.Commit) (string, error) {
@@ -5,13 +5,18 @@ package gitinterface
 import (
 	"bytes"
 	"context"
+	"encoding/pem"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/digitorus/pkcs7"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/x509"
 	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
 	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
 	"github.com/gittuf/gittuf/internal/tuf"
@@ -58,7 +63,7 @@ func TestRepositoryCommit(t *testing.T) {
 	}
 
 	// Create initial commit with no tree
-	expectedInitialCommitID := "648c569f3958b899e832f04750de52cf5d0db2fa"
+	expectedInitialCommitID := hashForBuildObjectFormat("648c569f3958b899e832f04750de52cf5d0db2fa", "2551c16c775f1c7bfa2ea94c2bf7bf4dd425ff1a5cc6d3bc5c37d9c14ef8951e")
 	commitID, err := repo.Commit(emptyTreeID, refName, "Initial commit\n", false)
 	assert.Nil(t, err)
 	assert.Equal(t, expectedInitialCommitID, commitID.String())
@@ -70,7 +75,7 @@ func TestRepositoryCommit(t *testing.T) {
 	assert.Equal(t, expectedInitialCommitID, refHead.String())
 
 	// Create second commit with tree
-	expectedSecondCommitID := "3d7200c158ccfedf35a68a7d24842d60cac4ec0d"
+	expectedSecondCommitID := hashForBuildObjectFormat("3d7200c158ccfedf35a68a7d24842d60cac4ec0d", "ab55164de5126918bfedcdc238fb5e7de9c65c7e6cc08ee0b1fd3aec7ef498e2")
 	commitID, err = repo.Commit(treeWithContentsID, refName, "Add README\n", false)
 	assert.Nil(t, err)
 	assert.Equal(t, expectedSecondCommitID, commitID.String())
@@ -82,7 +87,7 @@ func TestRepositoryCommit(t *testing.T) {
 	assert.Equal(t, expectedSecondCommitID, refHead.String())
 
 	// Create third commit with same tree but sign this time
-	expectedThirdCommitID := "eed43c23f781ddc10359ce25e0fc486a000a8c9f"
+	expectedThirdCommitID := hashForBuildObjectFormat("eed43c23f781ddc10359ce25e0fc486a000a8c9f", "531afd786b1e09f5290ba6485457de03a3efdd13ff5cc89e1731951e2bd33724")
 	commitID, err = repo.Commit(treeWithContentsID, refName, "Signing this commit\n", true)
 	assert.Nil(t, err)
 	assert.Equal(t, expectedThirdCommitID, commitID.String())
@@ -103,7 +108,7 @@ func TestCreateCommitObject(t *testing.T) {
 			t.Error(err)
 		}
 
-		assert.Equal(t, "22ddfd55fb5fba7b37b50b068d1527a1b0f9f561", enc.Hash().String())
+		assert.Equal(t, hashForBuildObjectFormat("22ddfd55fb5fba7b37b50b068d1527a1b0f9f561", "76716751d2e16767e6867770604923113fc4798fef51094cc2b49f678b1655f8"), enc.Hash().String())
 	})
 
 	t.Run("zero commit and single non-zero parent", func(t *testing.T) {
@@ -213,12 +218,60 @@ oYBpMWLgg6AUzpxx9mITZ2EKr4c=
 		assert.Nil(t, err)
 	})
 
+	t.Run("gpg subkey signed commit with primary key", func(t *testing.T) {
+		// The primary key can only certify, so the commit is signed using the
+		// subkey
+		subkeySignedCommit := createTestSignedCommitUsingGPGKey(t, artifacts.GPGKey3Private)
+		subkeyPrimaryKey, err := gpg.LoadGPGKeyFromBytes(artifacts.GPGKey3Public)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = VerifyCommitSignature(context.Background(), subkeySignedCommit, subkeyPrimaryKey)
+		assert.Nil(t, err)
+
+		err = VerifyCommitSignature(context.Background(), subkeySignedCommit, gpgKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+
 	// FIXME: fix gitsign testing
 	// t.Run("gitsign signed commit", func(t *testing.T) {
 	// 	err := VerifyCommitSignature(context.Background(), gitsignSignedCommit, fulcioKey)
 	// 	assert.Nil(t, err)
 	// })
 
+	t.Run("gitsign signed commit with pinned certificate", func(t *testing.T) {
+		if BuildObjectFormat() != ObjectFormatSHA1 {
+			t.Skip("the gitsign signed commit is from a SHA-1 repository")
+		}
+
+		// The short-lived certificate expired long ago, it's verified at the
+		// signing time
+		block, _ := pem.Decode([]byte(gitsignSignedCommit.PGPSignature))
+		signature, err := pkcs7.Parse(block.Bytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: signature.Certificates[0].Raw})
+
+		x509Key, err := x509.NewKeyFromBytes(certificate, "aditya@saky.in")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = VerifyCommitSignature(context.Background(), gitsignSignedCommit, x509Key)
+		assert.Nil(t, err)
+
+		err = VerifyCommitSignature(context.Background(), gpgSignedCommit, x509Key)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+
+		x509Key, err = x509.NewKeyFromBytes(certificate, "someone@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = VerifyCommitSignature(context.Background(), gitsignSignedCommit, x509Key)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+
 	t.Run("use gpg signed commit with gitsign key", func(t *testing.T) {
 		err := VerifyCommitSignature(context.Background(), gpgSignedCommit, fulcioKey)
 		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
@@ -246,6 +299,86 @@ oYBpMWLgg6AUzpxx9mITZ2EKr4c=
 	})
 }
 
+func TestVerifyCommitSignatureAt(t *testing.T) {
+	// The key is valid for a day, from two days ago
+	keyCreatedAt := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	signedAt := keyCreatedAt.Add(12 * time.Hour)
+	expiredAt := keyCreatedAt.Add(24 * time.Hour)
+
+	entity, err := openpgp.NewEntity("Jane Doe", "", "jane.doe@example.com", &packet.Config{
+		Time:            func() time.Time { return keyCreatedAt },
+		KeyLifetimeSecs: 24 * 60 * 60,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey := new(bytes.Buffer)
+	armorWriter, err := armor.Encode(publicKey, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(armorWriter); err != nil {
+		t.Fatal(err)
+	}
+	armorWriter.Close() //nolint:errcheck
+
+	key, err := gpg.LoadGPGKeyFromBytes(publicKey.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit := &object.Commit{
+		Author:    object.Signature{Name: "Jane Doe", Email: "jane.doe@example.com", When: signedAt},
+		Committer: object.Signature{Name: "Jane Doe", Email: "jane.doe@example.com", When: signedAt},
+		Message:   "Test commit\n",
+		TreeHash:  plumbing.NewHash("4b825dc642cb6eb9a060e54bf8d69288fbee4904"),
+	}
+	commitContents, err := getCommitBytesWithoutSignature(commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature := new(strings.Builder)
+	if err := openpgp.ArmoredDetachSign(signature, entity, bytes.NewReader(commitContents), &packet.Config{Time: func() time.Time { return signedAt }}); err != nil {
+		t.Fatal(err)
+	}
+	commit.PGPSignature = signature.String()
+
+	t.Run("expired key is not trusted now", func(t *testing.T) {
+		err := VerifyCommitSignature(context.Background(), commit, key)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+
+		err = VerifyCommitSignatureAt(context.Background(), commit, key, time.Time{})
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+
+	t.Run("key valid when recorded", func(t *testing.T) {
+		err := VerifyCommitSignatureAt(context.Background(), commit, key, signedAt.Add(time.Hour))
+		assert.Nil(t, err)
+	})
+
+	t.Run("recorded after key expired", func(t *testing.T) {
+		err := VerifyCommitSignatureAt(context.Background(), commit, key, expiredAt.Add(time.Hour))
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+
+	t.Run("recorded before signature was created", func(t *testing.T) {
+		err := VerifyCommitSignatureAt(context.Background(), commit, key, signedAt.Add(-time.Hour))
+		assert.ErrorIs(t, err, ErrSignedAfterRecorded)
+	})
+
+	t.Run("signature verified using another key", func(t *testing.T) {
+		gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = VerifyCommitSignatureAt(context.Background(), commit, gpgKey, signedAt.Add(time.Hour))
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+}
+
 func TestRepositoryVerifyCommit(t *testing.T) {
 	// TODO: support multiple signing types
 
@@ -348,6 +481,12 @@ func TestKnowsCommit(t *testing.T) {
 func createTestSignedCommit(t *testing.T) *object.Commit {
 	t.Helper()
 
+	return createTestSignedCommitUsingGPGKey(t, gpgPrivateKey)
+}
+
+func createTestSignedCommitUsingGPGKey(t *testing.T, privateKeyBytes []byte) *object.Commit {
+	t.Helper()
+
 	repo, err := git.Init(memory.NewStorage(), memfs.New())
 	if err != nil {
 		t.Fatal(err)
@@ -377,7 +516,7 @@ func createTestSignedCommit(t *testing.T) *object.Commit {
 		t.Fatal(err)
 	}
 
-	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(gpgPrivateKey))
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(privateKeyBytes))
 	if err != nil {
 		t.Fatal(err)
 	}
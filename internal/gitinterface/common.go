@@ -35,7 +35,7 @@ var (
 // CreateTestGitRepository creates a Git repository in the specified directory.
 // This is meant to be used by tests across gittuf packages. This helper also
 // sets up an ED25519 signing key that can be used to create reproducible
-// commits.
+// commits. The repository uses the object format gittuf is built for.
 func CreateTestGitRepository(t *testing.T, dir string) *Repository {
 	t.Helper()
 
@@ -43,7 +43,7 @@ func CreateTestGitRepository(t *testing.T, dir string) *Repository {
 
 	setupSigningKeys(t, keysDir)
 
-	cmd := exec.Command(binary, "init", "-b", "main", dir)
+	cmd := exec.Command(binary, "init", "-b", "main", "--object-format", BuildObjectFormat(), dir)
 	if err := cmd.Run(); err != nil {
 		t.Fatal(err)
 	}
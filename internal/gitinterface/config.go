@@ -23,7 +23,7 @@ var (
 // because go-git has difficulty combining local, global, and system configs
 // while maintaining all of their fields.
 // See: https://github.com/go-git/go-git/issues/508
-func getConfig() (map[string]string, error) {
+func GetConfig() (map[string]string, error) {
 	configReader, err := getGitConfigFromCommand()
 	if err != nil {
 		return nil, err
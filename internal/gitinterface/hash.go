@@ -8,6 +8,9 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"fmt"
+
+	"github.com/go-git/go-git/v5/plumbing/hash"
 )
 
 var (
@@ -15,9 +18,16 @@ var (
 	zeroSHA256HashBytes = [sha256.Size]byte{}
 )
 
+const (
+	ObjectFormatSHA1   = "sha1"
+	ObjectFormatSHA256 = "sha256"
+)
+
 var (
 	ErrInvalidHashEncoding = errors.New("hash string is not hex encoded")
 	ErrInvalidHashLength   = errors.New("hash string is wrong length")
+	ErrUnknownObjectFormat = errors.New("unknown object format (not one of sha1, sha256)")
+	ErrHashFormatMismatch  = errors.New("hash does not match repository's object format")
 )
 
 // Hash represents a Git object hash. It is a lightweight wrapper around the
@@ -36,8 +46,8 @@ func (h Hash) IsZero() bool {
 	return bytes.Equal(h, zeroSHA1HashBytes[:]) || bytes.Equal(h, zeroSHA256HashBytes[:])
 }
 
-// ZeroHash represents an empty Hash.
-// TODO: use SHA-256 zero hash for repositories that have that as the default.
+// ZeroHash represents an empty Hash. For the zero hash of a specific
+// repository, use Repository.GetZeroHash.
 var ZeroHash = Hash(zeroSHA1HashBytes[:])
 
 // NewHash returns a Hash object after ensuring the input string is correctly
@@ -54,3 +64,64 @@ func NewHash(h string) (Hash, error) {
 
 	return hash, nil
 }
+
+// BuildObjectFormat returns the object format gittuf reads and writes Git
+// objects using, one of ObjectFormatSHA1 and ObjectFormatSHA256. The RSL,
+// policy, and attestations are read and written using go-git, whose object
+// IDs are sized when it's built, so gittuf must be built using the sha256
+// build tag to operate on SHA-256 repositories.
+func BuildObjectFormat() string {
+	if hash.Size == sha256.Size {
+		return ObjectFormatSHA256
+	}
+	return ObjectFormatSHA1
+}
+
+// GetObjectFormat returns the object format used by the repository, one of
+// ObjectFormatSHA1 and ObjectFormatSHA256.
+func (r *Repository) GetObjectFormat() (string, error) {
+	objectFormat, err := r.executeGitCommandString("rev-parse", "--show-object-format")
+	if err != nil {
+		return "", fmt.Errorf("unable to identify object format: %w", err)
+	}
+
+	switch objectFormat {
+	case ObjectFormatSHA1, ObjectFormatSHA256:
+		return objectFormat, nil
+	default:
+		return "", ErrUnknownObjectFormat
+	}
+}
+
+// GetZeroHash returns the zero hash for the repository's object format.
+func (r *Repository) GetZeroHash() (Hash, error) {
+	objectFormat, err := r.GetObjectFormat()
+	if err != nil {
+		return nil, err
+	}
+
+	if objectFormat == ObjectFormatSHA256 {
+		return Hash(zeroSHA256HashBytes[:]), nil
+	}
+	return Hash(zeroSHA1HashBytes[:]), nil
+}
+
+// NewHashForRepository returns a Hash object after ensuring the input string is
+// correctly encoded and matches the repository's object format.
+func (r *Repository) NewHashForRepository(h string) (Hash, error) {
+	objectFormat, err := r.GetObjectFormat()
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := NewHash(h)
+	if err != nil {
+		return nil, err
+	}
+
+	if (objectFormat == ObjectFormatSHA1 && len(hash) != sha1.Size) || (objectFormat == ObjectFormatSHA256 && len(hash) != sha256.Size) {
+		return nil, ErrHashFormatMismatch
+	}
+
+	return hash, nil
+}
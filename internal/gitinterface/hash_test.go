@@ -5,6 +5,8 @@ package gitinterface
 import (
 	"encoding/hex"
 	"fmt"
+	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -59,3 +61,61 @@ func TestNewHash(t *testing.T) {
 		}
 	}
 }
+
+func TestRepositoryObjectFormat(t *testing.T) {
+	t.Run("SHA-1 repository", func(t *testing.T) {
+		dir := t.TempDir()
+		cmd := exec.Command(binary, "init", "--object-format=sha1", dir)
+		if err := cmd.Run(); err != nil {
+			t.Fatal(err)
+		}
+		repo := &Repository{gitDirPath: filepath.Join(dir, ".git"), clock: testClock}
+
+		objectFormat, err := repo.GetObjectFormat()
+		assert.Nil(t, err)
+		assert.Equal(t, ObjectFormatSHA1, objectFormat)
+
+		zeroHash, err := repo.GetZeroHash()
+		assert.Nil(t, err)
+		assert.Equal(t, ZeroHash, zeroHash)
+
+		_, err = repo.NewHashForRepository("e69de29bb2d1d6434b8b29ae775ad8c2e48c5391")
+		assert.Nil(t, err)
+
+		_, err = repo.NewHashForRepository("61658570165bc04af68cef20d72da49b070dc9d8cd7c8a526c950b658f4d3ccf")
+		assert.ErrorIs(t, err, ErrHashFormatMismatch)
+	})
+
+	t.Run("SHA-256 repository", func(t *testing.T) {
+		dir := t.TempDir()
+		cmd := exec.Command(binary, "init", "--object-format=sha256", dir)
+		if err := cmd.Run(); err != nil {
+			t.Fatal(err)
+		}
+		repo := &Repository{gitDirPath: filepath.Join(dir, ".git"), clock: testClock}
+
+		objectFormat, err := repo.GetObjectFormat()
+		assert.Nil(t, err)
+		assert.Equal(t, ObjectFormatSHA256, objectFormat)
+
+		zeroHash, err := repo.GetZeroHash()
+		assert.Nil(t, err)
+		assert.Equal(t, Hash(zeroSHA256HashBytes[:]), zeroHash)
+		assert.True(t, zeroHash.IsZero())
+
+		_, err = repo.NewHashForRepository("61658570165bc04af68cef20d72da49b070dc9d8cd7c8a526c950b658f4d3ccf")
+		assert.Nil(t, err)
+
+		_, err = repo.NewHashForRepository("e69de29bb2d1d6434b8b29ae775ad8c2e48c5391")
+		assert.ErrorIs(t, err, ErrHashFormatMismatch)
+	})
+}
+
+// hashForBuildObjectFormat returns the ID of a go-git object for the object
+// format gittuf is built for.
+func hashForBuildObjectFormat(sha1ID, sha256ID string) string {
+	if BuildObjectFormat() == ObjectFormatSHA256 {
+		return sha256ID
+	}
+	return sha1ID
+}
@@ -11,12 +11,21 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hiddeco/sshsig"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
 	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/piv"
+	"github.com/gittuf/gittuf/internal/signerverifier/sigcache"
+	"github.com/gittuf/gittuf/internal/signerverifier/x509"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
 	gitsignVerifier "github.com/sigstore/gitsign/pkg/git"
@@ -32,9 +41,22 @@ var (
 	ErrIncorrectVerificationKey   = errors.New("incorrect key provided to verify signature")
 	ErrVerifyingSigstoreSignature = errors.New("unable to verify Sigstore signature")
 	ErrVerifyingSSHSignature      = errors.New("unable to verify SSH signature")
+	ErrVerifyingX509Signature     = errors.New("unable to verify X.509 signature")
 	ErrInvalidSignature           = errors.New("unable to parse signature / signature has unexpected header")
+	ErrSignedAfterRecorded        = errors.New("signature was created after the signed object was recorded")
 )
 
+// GPGSmartcardConfigKey is the Git config key used to sign using the
+// signature key on the connected smartcard rather than user.signingkey when the
+// signing method is GPG.
+const GPGSmartcardConfigKey = "gittuf.gpg.smartcard"
+
+// PIVSigningKeyConfigKey is the Git config key used to sign using the key in a
+// PIV slot of a hardware token, in the form "piv:slot-<slot>", rather than
+// using the signing method in the user's Git config. The signature is an SSH
+// signature, verified using the key like one made using an on-disk ECDSA key.
+const PIVSigningKeyConfigKey = "gittuf.piv.signingkey"
+
 type SigningMethod int
 
 const (
@@ -102,8 +124,16 @@ func GetSigningCommand() (string, []string, error) {
 	return program, args, nil
 }
 
+// GetSigningKeyInfo returns the signing method and the signing key configured
+// in the user's Git config. The key information is empty if user.signingkey is
+// not set.
+func GetSigningKeyInfo() (SigningMethod, string, error) {
+	signingMethod, keyInfo, _, err := getSigningInfo()
+	return signingMethod, keyInfo, err
+}
+
 func getSigningInfo() (SigningMethod, string, string, error) {
-	gitConfig, err := getConfig()
+	gitConfig, err := GetConfig()
 	if err != nil {
 		return -1, "", "", err
 	}
@@ -173,6 +203,30 @@ func getSigningProgram(gitConfig map[string]string, signingMethod SigningMethod)
 // signGitObject signs a Git commit or tag using the user's configured Git
 // config.
 func signGitObject(contents []byte) (string, error) {
+	pivSigner, err := getPIVSigner()
+	if err != nil {
+		return "", err
+	}
+	if pivSigner != nil {
+		signature, err := signGitObjectUsingSSHSigner(contents, pivSigner)
+		if err != nil {
+			return "", errors.Join(ErrUnableToSign, err)
+		}
+		return signature, nil
+	}
+
+	gpgSigner, err := getGPGSigner()
+	if err != nil {
+		return "", err
+	}
+	if gpgSigner != nil {
+		signature, err := gpgSigner.Sign(contents)
+		if err != nil {
+			return "", errors.Join(ErrUnableToSign, err)
+		}
+		return string(signature), nil
+	}
+
 	command, args, err := GetSigningCommand()
 	if err != nil {
 		return "", err
@@ -233,6 +287,61 @@ func signGitObject(contents []byte) (string, error) {
 	return string(sig), nil
 }
 
+// getGPGSigner returns a signer for the GPG key in the user's Git config, or
+// the signature key on the connected smartcard if GPGSmartcardConfigKey is set.
+// Unlike passing user.signingkey to gpg as is, this resolves the exact subkey
+// used to sign. If the signing method isn't GPG or no key is specified, nil is
+// returned and gpg's default key is used.
+func getGPGSigner() (*gpg.Signer, error) {
+	gitConfig, err := GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	signingMethod, err := getSigningMethod(gitConfig)
+	if err != nil {
+		return nil, err
+	}
+	if signingMethod != SigningMethodGPG {
+		return nil, nil
+	}
+
+	program := getSigningProgram(gitConfig, signingMethod)
+
+	if useSmartcard, _ := strconv.ParseBool(gitConfig[GPGSmartcardConfigKey]); useSmartcard {
+		return gpg.NewSmartcardSigner(program)
+	}
+
+	keyInfo := getSigningKeyInfo(gitConfig)
+	if keyInfo == "" {
+		return nil, nil
+	}
+
+	return gpg.NewSigner(program, keyInfo)
+}
+
+// getPIVSigner returns an SSH signer for the key in the PIV slot set using
+// PIVSigningKeyConfigKey in the user's Git config. If it isn't set, nil is
+// returned.
+func getPIVSigner() (ssh.Signer, error) {
+	gitConfig, err := GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	keyRef := gitConfig[PIVSigningKeyConfigKey]
+	if keyRef == "" {
+		return nil, nil
+	}
+
+	signer, err := piv.NewSignerFromKeyRef(keyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return ssh.NewSignerFromSigner(signer.CryptoSigner())
+}
+
 func signGitObjectUsingKey(contents, pemKeyBytes []byte) (string, error) {
 	block, _ := pem.Decode(pemKeyBytes)
 	if block == nil {
@@ -274,6 +383,10 @@ func signGitObjectUsingSSHKey(contents, pemKeyBytes []byte) (string, error) {
 		return "", err
 	}
 
+	return signGitObjectUsingSSHSigner(contents, signer)
+}
+
+func signGitObjectUsingSSHSigner(contents []byte, signer ssh.Signer) (string, error) {
 	sshSig, err := sshsig.Sign(bytes.NewReader(contents), signer, sshsig.HashSHA512, namespaceSSHSignature)
 	if err != nil {
 		return "", err
@@ -285,8 +398,17 @@ func signGitObjectUsingSSHKey(contents, pemKeyBytes []byte) (string, error) {
 }
 
 // verifyGitsignSignature handles the Sigstore-specific workflow involved in
-// verifying commit or tag signatures issued by gitsign.
+// verifying commit or tag signatures issued by gitsign. The signature cache is
+// consulted first.
 func verifyGitsignSignature(ctx context.Context, key *tuf.Key, data, signature []byte) error {
+	return verifyWithSignatureCache(key, data, signature, func() error {
+		return checkGitsignSignature(ctx, key, data, signature)
+	})
+}
+
+// checkGitsignSignature verifies the gitsign signature without consulting the
+// signature cache.
+func checkGitsignSignature(ctx context.Context, key *tuf.Key, data, signature []byte) error {
 	root, err := fulcioroots.Get()
 	if err != nil {
 		return errors.Join(ErrVerifyingSigstoreSignature, err)
@@ -338,8 +460,72 @@ func verifyGitsignSignature(ctx context.Context, key *tuf.Key, data, signature [
 	return nil
 }
 
-// verifySSHKeySignature verifies Git signatures issued by SSH keys.
+// verifyGPGSignatureAt verifies Git signatures issued using GPG keys. If the
+// key or signature has expired, the signature is accepted if it was created no
+// later than recordedAt, and the key and signature were valid at recordedAt.
+// Revoked keys are never accepted.
+func verifyGPGSignatureAt(key *tuf.Key, data, signature []byte, recordedAt time.Time) error {
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.KeyVal.Public))
+	if err != nil {
+		return err
+	}
+
+	block, err := armor.Decode(bytes.NewReader(signature))
+	if err != nil {
+		return errors.Join(ErrInvalidSignature, err)
+	}
+	signatureBytes, err := io.ReadAll(block.Body)
+	if err != nil {
+		return errors.Join(ErrInvalidSignature, err)
+	}
+
+	sig, _, err := openpgp.VerifyDetachedSignature(keyring, bytes.NewReader(data), bytes.NewReader(signatureBytes), nil)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, pgperrors.ErrKeyExpired) && !errors.Is(err, pgperrors.ErrSignatureExpired) {
+		return err
+	}
+
+	// The signature is cryptographically valid, but the key or signature has
+	// since expired
+	if sig.CreationTime.After(recordedAt) {
+		return ErrSignedAfterRecorded
+	}
+
+	config := &packet.Config{Time: func() time.Time { return recordedAt }}
+	_, _, err = openpgp.VerifyDetachedSignature(keyring, bytes.NewReader(data), bytes.NewReader(signatureBytes), config)
+	return err
+}
+
+// verifyX509Signature verifies Git signatures issued using X.509 certificates,
+// such as by gpgsm or gitsign, against the certificates trusted by the key.
+// The verification isn't cached, as whether the signature is accepted depends
+// on the validity of the certificates.
+func verifyX509Signature(ctx context.Context, key *tuf.Key, data, signature []byte) error {
+	verifier, err := x509.NewVerifierFromKey(key)
+	if err != nil {
+		return errors.Join(ErrVerifyingX509Signature, err)
+	}
+
+	if err := verifier.Verify(ctx, data, signature); err != nil {
+		return errors.Join(ErrIncorrectVerificationKey, err)
+	}
+
+	return nil
+}
+
+// verifySSHKeySignature verifies Git signatures issued by SSH keys. The
+// signature cache is consulted first.
 func verifySSHKeySignature(key *tuf.Key, data, signature []byte) error {
+	return verifyWithSignatureCache(key, data, signature, func() error {
+		return checkSSHKeySignature(key, data, signature)
+	})
+}
+
+// checkSSHKeySignature verifies the SSH signature without consulting the
+// signature cache.
+func checkSSHKeySignature(key *tuf.Key, data, signature []byte) error {
 	verifier, err := signerverifier.NewSignerVerifierFromTUFKey(key) //nolint:staticcheck
 	if err != nil {
 		return errors.Join(ErrVerifyingSSHSignature, err)
@@ -361,3 +547,22 @@ func verifySSHKeySignature(key *tuf.Key, data, signature []byte) error {
 
 	return nil
 }
+
+// verifyWithSignatureCache invokes verify unless the signature cache records a
+// successful verification of the signature over data using the key. The
+// verification is keyed by the key's material in addition to its ID. GPG and
+// X.509 signatures are not cached, as whether they're accepted depends on the
+// validity of the key or certificates at the time of verification.
+func verifyWithSignatureCache(key *tuf.Key, data, signature []byte, verify func() error) error {
+	digest := sigcache.Digest(
+		data,
+		signature,
+		[]byte(key.KeyType),
+		[]byte(key.Scheme),
+		[]byte(key.KeyVal.Public),
+		[]byte(key.KeyVal.Certificate),
+		[]byte(key.KeyVal.Identity),
+		[]byte(key.KeyVal.Issuer),
+	)
+	return sigcache.Verify(digest, key.KeyID, verify)
+}
@@ -4,16 +4,24 @@ package gitinterface
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
 	"io"
+	"strings"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/signerverifier/piv"
 	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	format "github.com/go-git/go-git/v5/plumbing/format/config"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
 )
 
 var (
@@ -176,3 +184,52 @@ func TestGetSigningInfo(t *testing.T) {
 		}
 	}
 }
+
+func TestGetPIVSigner(t *testing.T) {
+	defaultGetGitConfigFromCommand := getGitConfigFromCommand
+	defer func() { getGitConfigFromCommand = defaultGetGitConfigFromCommand }()
+
+	t.Run("not configured", func(t *testing.T) {
+		getGitConfigFromCommand = func() (io.Reader, error) {
+			return bytes.NewReader(testConfig2), nil
+		}
+
+		signer, err := getPIVSigner()
+		assert.Nil(t, err)
+		assert.Nil(t, signer)
+	})
+
+	t.Run("invalid slot", func(t *testing.T) {
+		getGitConfigFromCommand = func() (io.Reader, error) {
+			return strings.NewReader(fmt.Sprintf("%s piv:slot-82\n", PIVSigningKeyConfigKey)), nil
+		}
+
+		_, err := getPIVSigner()
+		assert.ErrorIs(t, err, piv.ErrInvalidSlot)
+	})
+}
+
+func TestSignGitObjectUsingSSHSigner(t *testing.T) {
+	// Keys on a hardware token are used through crypto.Signer
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromSigner(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents := []byte("test object")
+	signature, err := signGitObjectUsingSSHSigner(contents, signer)
+	assert.Nil(t, err)
+
+	// The signature is verified using the key as it appears in gittuf
+	// metadata
+	key, err := sslibsv.NewKey(privateKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, checkSSHKeySignature(key, contents, []byte(signature)))
+	assert.ErrorIs(t, checkSSHKeySignature(key, []byte("other object"), []byte(signature)), ErrIncorrectVerificationKey)
+}
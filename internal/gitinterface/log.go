@@ -6,6 +6,7 @@ import (
 	"errors"
 	"sort"
 
+	"github.com/gittuf/gittuf/internal/perf"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
@@ -21,6 +22,8 @@ import (
 // an effect of walking the graph anyway, so the sort by ID ensures the returned
 // commit slice is deterministic.
 func GetCommitsBetweenRange(repo *git.Repository, commitNewID, commitOldID plumbing.Hash) ([]*object.Commit, error) {
+	defer perf.Track(perf.AncestryWalks)()
+
 	all := false
 
 	if commitOldID.IsZero() {
@@ -224,6 +224,9 @@ func TestGetCommitsBetweenRangeForMergeCommits(t *testing.T) {
 		commits, err := GetCommitsBetweenRange(repo, commitIDs[0], plumbing.ZeroHash)
 		assert.Nil(t, err)
 		expectedCommits := []*object.Commit{allCommits[0]}
+		sort.Slice(expectedCommits, func(i, j int) bool {
+			return expectedCommits[i].ID().String() < expectedCommits[j].ID().String()
+		})
 		assert.Equal(t, expectedCommits, commits)
 	})
 
@@ -232,6 +235,9 @@ func TestGetCommitsBetweenRangeForMergeCommits(t *testing.T) {
 		commits, err := GetCommitsBetweenRange(repo, commitIDs[1], plumbing.ZeroHash)
 		assert.Nil(t, err)
 		expectedCommits := []*object.Commit{allCommits[1], allCommits[0]}
+		sort.Slice(expectedCommits, func(i, j int) bool {
+			return expectedCommits[i].ID().String() < expectedCommits[j].ID().String()
+		})
 		assert.Equal(t, expectedCommits, commits)
 	})
 
@@ -240,6 +246,9 @@ func TestGetCommitsBetweenRangeForMergeCommits(t *testing.T) {
 		commits, err := GetCommitsBetweenRange(repo, commitIDs[2], plumbing.ZeroHash)
 		assert.Nil(t, err)
 		expectedCommits := []*object.Commit{allCommits[0], allCommits[2]}
+		sort.Slice(expectedCommits, func(i, j int) bool {
+			return expectedCommits[i].ID().String() < expectedCommits[j].ID().String()
+		})
 		assert.Equal(t, expectedCommits, commits)
 	})
 
@@ -248,6 +257,9 @@ func TestGetCommitsBetweenRangeForMergeCommits(t *testing.T) {
 		commits, err := GetCommitsBetweenRange(repo, commitIDs[3], plumbing.ZeroHash)
 		assert.Nil(t, err)
 		expectedCommits := []*object.Commit{allCommits[1], allCommits[0], allCommits[3]}
+		sort.Slice(expectedCommits, func(i, j int) bool {
+			return expectedCommits[i].ID().String() < expectedCommits[j].ID().String()
+		})
 		assert.Equal(t, expectedCommits, commits)
 	})
 
@@ -256,6 +268,9 @@ func TestGetCommitsBetweenRangeForMergeCommits(t *testing.T) {
 		commits, err := GetCommitsBetweenRange(repo, commitIDs[4], plumbing.ZeroHash)
 		assert.Nil(t, err)
 		expectedCommits := []*object.Commit{allCommits[4], allCommits[1], allCommits[0], allCommits[2]}
+		sort.Slice(expectedCommits, func(i, j int) bool {
+			return expectedCommits[i].ID().String() < expectedCommits[j].ID().String()
+		})
 		assert.Equal(t, expectedCommits, commits)
 	})
 
@@ -264,6 +279,9 @@ func TestGetCommitsBetweenRangeForMergeCommits(t *testing.T) {
 		commits, err := GetCommitsBetweenRange(repo, commitIDs[5], plumbing.ZeroHash)
 		assert.Nil(t, err)
 		expectedCommits := []*object.Commit{allCommits[0], allCommits[5], allCommits[2]}
+		sort.Slice(expectedCommits, func(i, j int) bool {
+			return expectedCommits[i].ID().String() < expectedCommits[j].ID().String()
+		})
 		assert.Equal(t, expectedCommits, commits)
 	})
 }
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/jonboulle/clockwork"
+)
+
+// ErrMissingPromisorObjects is returned when objects missing in a partial
+// clone could not be fetched from the promisor remote.
+var ErrMissingPromisorObjects = errors.New("unable to fetch objects missing in partial clone")
+
+// GetPromisorRemote returns the name of the remote that objects missing in a
+// partial clone are fetched from. Partial clones, such as blobless or treeless
+// clones, are created using `git clone --filter`. If the repository is not a
+// partial clone, an empty string is returned.
+func GetPromisorRemote(repo *git.Repository) (string, error) {
+	repoConfig, err := repo.Config()
+	if err != nil {
+		return "", err
+	}
+
+	// Older versions of Git record the promisor remote as an extension
+	if remoteName := repoConfig.Raw.Section("extensions").Option("partialClone"); remoteName != "" {
+		return remoteName, nil
+	}
+
+	for _, remote := range repoConfig.Raw.Section("remote").Subsections {
+		if strings.EqualFold(remote.Option("promisor"), "true") {
+			return remote.Name, nil
+		}
+	}
+
+	return "", nil
+}
+
+// FetchMissingObjects fetches the objects reachable from the specified objects
+// that are missing in a partial clone from the promisor remote. go-git cannot
+// fetch missing objects on demand like the Git binary, so this must be done
+// before reading the objects using go-git. If the repository is not a partial
+// clone, this is a no-op.
+func FetchMissingObjects(repo *git.Repository, objectIDs ...plumbing.Hash) error {
+	return fetchMissingObjects(repo, objectIDs, true)
+}
+
+// FetchMissingTrees fetches the commits and trees reachable from the specified
+// objects that are missing in a partial clone from the promisor remote, like
+// FetchMissingObjects. Blobs are not fetched, which suffices to enumerate the
+// paths changed by commits as only the blob IDs are compared.
+func FetchMissingTrees(repo *git.Repository, objectIDs ...plumbing.Hash) error {
+	return fetchMissingObjects(repo, objectIDs, false)
+}
+
+func fetchMissingObjects(repo *git.Repository, objectIDs []plumbing.Hash, includeBlobs bool) error {
+	if len(objectIDs) == 0 {
+		return nil
+	}
+
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		// Only repositories on disk can be partial clones
+		return nil
+	}
+
+	remoteName, err := GetPromisorRemote(repo)
+	if err != nil {
+		return err
+	}
+	if remoteName == "" {
+		return nil
+	}
+
+	r := &Repository{gitDirPath: storage.Filesystem().Root(), clock: clockwork.NewRealClock()}
+
+	fetched := map[string]bool{}
+	for {
+		missingIDs, err := r.listMissingObjects(objectIDs, includeBlobs)
+		if err != nil {
+			return err
+		}
+		if len(missingIDs) == 0 {
+			return nil
+		}
+
+		for _, missingID := range missingIDs {
+			if fetched[missingID] {
+				// The promisor remote didn't send the object when it was
+				// requested before
+				return fmt.Errorf("%w: '%s' not available from remote '%s'", ErrMissingPromisorObjects, missingID, remoteName)
+			}
+			fetched[missingID] = true
+		}
+
+		slog.Debug(fmt.Sprintf("Fetching %d missing objects from promisor remote '%s'...", len(missingIDs), remoteName))
+		if err := r.fetchPromisorObjects(remoteName, missingIDs); err != nil {
+			return fmt.Errorf("%w: %w", ErrMissingPromisorObjects, err)
+		}
+
+		// go-git only looks for new packfiles when its index is reset
+		storage.Reindex()
+	}
+}
+
+// listMissingObjects returns the IDs of the objects reachable from the
+// specified objects that are missing in the repository. Blobs beneath missing
+// trees can only be identified once the trees are fetched.
+func (r *Repository) listMissingObjects(objectIDs []plumbing.Hash, includeBlobs bool) ([]string, error) {
+	stdIn := new(bytes.Buffer)
+	for _, objectID := range objectIDs {
+		stdIn.WriteString(objectID.String() + "\n")
+	}
+
+	args := []string{"rev-list", "--objects", "--missing=print", "--stdin"}
+	if !includeBlobs {
+		args = append(args, "--filter=blob:none")
+	}
+
+	stdOut, err := r.executeGitCommandWithStdInString(stdIn, args...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to identify missing objects: %w", err)
+	}
+
+	missingIDs := []string{}
+	for _, line := range strings.Split(stdOut, "\n") {
+		if missingID, isMissing := strings.CutPrefix(line, "?"); isMissing {
+			missingIDs = append(missingIDs, missingID)
+		}
+	}
+
+	return missingIDs, nil
+}
+
+// fetchPromisorObjects fetches the specified objects from the promisor remote
+// along with the trees reachable from them, like the Git binary does when it
+// encounters a missing object. Blobs are only sent if they're requested
+// explicitly.
+func (r *Repository) fetchPromisorObjects(remoteName string, objectIDs []string) error {
+	stdIn := bytes.NewBufferString(strings.Join(objectIDs, "\n") + "\n")
+
+	_, err := r.executeGitCommandWithStdInString(stdIn, "-c", "fetch.negotiationAlgorithm=noop", "fetch", remoteName, "--no-tags", "--no-write-fetch-head", "--recurse-submodules=no", "--filter=blob:none", "--stdin")
+	return err
+}
@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPromisorRemote(t *testing.T) {
+	sourceDir := t.TempDir()
+	createTestPromisorRepository(t, sourceDir)
+
+	sourceRepo, err := git.PlainOpen(sourceDir)
+	require.Nil(t, err)
+
+	remoteName, err := GetPromisorRemote(sourceRepo)
+	assert.Nil(t, err)
+	assert.Empty(t, remoteName)
+
+	clone := clonePartially(t, sourceDir, "blob:none")
+
+	remoteName, err = GetPromisorRemote(clone)
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultRemoteName, remoteName)
+}
+
+func TestFetchMissingObjects(t *testing.T) {
+	sourceDir := t.TempDir()
+	tipID := createTestPromisorRepository(t, sourceDir)
+
+	t.Run("not a partial clone", func(t *testing.T) {
+		sourceRepo, err := git.PlainOpen(sourceDir)
+		require.Nil(t, err)
+
+		err = FetchMissingObjects(sourceRepo, tipID)
+		assert.Nil(t, err)
+
+		err = FetchMissingTrees(sourceRepo, tipID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("blobless clone", func(t *testing.T) {
+		clone := clonePartially(t, sourceDir, "blob:none")
+
+		// The blobs aren't needed to enumerate changed paths
+		commit, err := GetCommit(clone, tipID)
+		require.Nil(t, err)
+
+		paths, err := GetCommitFilePaths(commit)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"dir/file", "renamed"}, paths)
+
+		paths, err = GetFilePathsChangedByCommit(clone, commit)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"file", "renamed"}, paths)
+
+		tree, err := commit.Tree()
+		require.Nil(t, err)
+		entry, err := tree.FindEntry("renamed")
+		require.Nil(t, err)
+
+		err = FetchMissingTrees(clone, tipID)
+		assert.Nil(t, err)
+
+		_, err = clone.BlobObject(entry.Hash)
+		assert.ErrorIs(t, err, plumbing.ErrObjectNotFound)
+
+		err = FetchMissingObjects(clone, tipID)
+		assert.Nil(t, err)
+
+		blob, err := clone.BlobObject(entry.Hash)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), blob.Size)
+	})
+
+	t.Run("treeless clone", func(t *testing.T) {
+		clone := clonePartially(t, sourceDir, "tree:0")
+
+		commit, err := GetCommit(clone, tipID)
+		require.Nil(t, err)
+
+		_, err = GetFilePathsChangedByCommit(clone, commit)
+		assert.ErrorIs(t, err, plumbing.ErrObjectNotFound)
+
+		err = FetchMissingTrees(clone, tipID)
+		assert.Nil(t, err)
+
+		paths, err := GetFilePathsChangedByCommit(clone, commit)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"file", "renamed"}, paths)
+
+		parentCommit, err := GetCommit(clone, commit.ParentHashes[0])
+		require.Nil(t, err)
+
+		paths, err = GetCommitFilePaths(parentCommit)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"dir/file", "file"}, paths)
+	})
+}
+
+// createTestPromisorRepository creates a repository in the specified directory
+// that can serve partial clones. The first commit adds a file that the second
+// commit renames and modifies. The ID of the second commit is returned.
+func createTestPromisorRepository(t *testing.T, dir string) plumbing.Hash {
+	t.Helper()
+
+	repo := CreateTestGitRepository(t, dir)
+	require.Nil(t, repo.SetGitConfig("uploadpack.allowFilter", "true"))
+	require.Nil(t, repo.SetGitConfig("uploadpack.allowAnySHA1InWant", "true"))
+
+	treeBuilder := NewReplacementTreeBuilder(repo)
+
+	blobAID, err := repo.WriteBlob([]byte("a"))
+	require.Nil(t, err)
+	blobBID, err := repo.WriteBlob([]byte("b"))
+	require.Nil(t, err)
+	blobCID, err := repo.WriteBlob([]byte("c"))
+	require.Nil(t, err)
+
+	treeID, err := treeBuilder.WriteRootTreeFromBlobIDs(map[string]Hash{"file": blobAID, "dir/file": blobBID})
+	require.Nil(t, err)
+	_, err = repo.Commit(treeID, "refs/heads/main", "Initial commit\n", false)
+	require.Nil(t, err)
+
+	treeID, err = treeBuilder.WriteRootTreeFromBlobIDs(map[string]Hash{"renamed": blobCID, "dir/file": blobBID})
+	require.Nil(t, err)
+	commitID, err := repo.Commit(treeID, "refs/heads/main", "Rename file\n", false)
+	require.Nil(t, err)
+
+	return plumbing.NewHash(commitID.String())
+}
+
+// clonePartially creates a partial clone of the repository in the specified
+// directory using the filter. The worktree is not checked out so that no
+// missing objects are fetched.
+func clonePartially(t *testing.T, sourceDir, filter string) *git.Repository {
+	t.Helper()
+
+	cloneDir := filepath.Join(t.TempDir(), "clone")
+	cmd := exec.Command(binary, "clone", "--quiet", "--no-checkout", "--filter="+filter, "file://"+sourceDir, cloneDir) //nolint:gosec
+	output, err := cmd.CombinedOutput()
+	require.Nil(t, err, string(output))
+
+	repo, err := git.PlainOpen(cloneDir)
+	require.Nil(t, err)
+
+	return repo
+}
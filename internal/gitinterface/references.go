@@ -50,8 +50,18 @@ func (r *Repository) SetReference(refName string, gitID Hash) error {
 }
 
 // CheckAndSetReference sets the specified reference to the provided Git ID if
-// the reference is currently set to `oldGitID`.
+// the reference is currently set to `oldGitID`. If `oldGitID` is the zero hash
+// of either object format, the reference must not exist.
 func (r *Repository) CheckAndSetReference(refName string, newGitID, oldGitID Hash) error {
+	if oldGitID.IsZero() {
+		// Git only accepts the zero hash of the repository's object format
+		zeroHash, err := r.GetZeroHash()
+		if err != nil {
+			return err
+		}
+		oldGitID = zeroHash
+	}
+
 	_, stdErr, err := r.executeGitCommand("update-ref", "--create-reflog", refName, newGitID.String(), oldGitID.String())
 	if err != nil {
 		return fmt.Errorf("unable to set Git reference '%s' to '%s': %s", refName, newGitID.String(), stdErr)
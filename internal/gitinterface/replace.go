@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+const (
+	// ReplaceRefPrefix is the namespace of Git replace refs. Each ref in this
+	// namespace is named after the object it replaces and points to the
+	// replacement object. The Git CLI transparently substitutes the
+	// replacement object for the original object when reading history.
+	ReplaceRefPrefix = "refs/replace/"
+
+	graftsFilePath = "info/grafts"
+)
+
+// GetReplaceRefs returns the replace refs in the repository, mapping the name
+// of each ref to the ID of the replacement object.
+func GetReplaceRefs(repo *git.Repository) (map[string]plumbing.Hash, error) {
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+
+	replaceRefs := map[string]plumbing.Hash{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if !strings.HasPrefix(ref.Name().String(), ReplaceRefPrefix) {
+			return nil
+		}
+
+		if ref.Type() == plumbing.SymbolicReference {
+			resolved, err := repo.Reference(ref.Name(), true)
+			if err != nil {
+				return err
+			}
+			ref = resolved
+		}
+
+		replaceRefs[ref.Name().String()] = ref.Hash()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return replaceRefs, nil
+}
+
+// HasGrafts returns true if the repository has a grafts file, which the Git
+// CLI uses to rewrite the parents of commits when reading history. Shallow
+// clones are not considered as they only truncate history.
+func HasGrafts(repo *git.Repository) (bool, error) {
+	s, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		// Grafts are only supported for repositories on disk
+		return false, nil
+	}
+
+	info, err := s.Filesystem().Stat(graftsFilePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return info.Size() > 0, nil
+}
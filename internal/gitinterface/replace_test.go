@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetReplaceRefs(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := CreateTestGitRepository(t, tempDir)
+
+	refName := "refs/heads/main"
+	treeBuilder := NewReplacementTreeBuilder(repo)
+
+	emptyTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(nil)
+	require.Nil(t, err)
+
+	originalCommitID, err := repo.Commit(emptyTreeID, refName, "Original commit\n", false)
+	require.Nil(t, err)
+	replacementCommitID, err := repo.Commit(emptyTreeID, "refs/heads/feature", "Replacement commit\n", false)
+	require.Nil(t, err)
+
+	goGitRepo, err := repo.GetGoGitRepository()
+	require.Nil(t, err)
+
+	replaceRefs, err := GetReplaceRefs(goGitRepo)
+	assert.Nil(t, err)
+	assert.Empty(t, replaceRefs)
+
+	_, err = repo.executeGitCommandString("replace", originalCommitID.String(), replacementCommitID.String())
+	require.Nil(t, err)
+
+	replaceRefs, err = GetReplaceRefs(goGitRepo)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]plumbing.Hash{ReplaceRefPrefix + originalCommitID.String(): plumbing.NewHash(replacementCommitID.String())}, replaceRefs)
+
+	// gittuf reads the original commit rather than its replacement
+	message, err := repo.GetCommitMessage(originalCommitID)
+	assert.Nil(t, err)
+	assert.Equal(t, "Original commit", message)
+}
+
+func TestHasGrafts(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := CreateTestGitRepository(t, tempDir)
+
+	goGitRepo, err := repo.GetGoGitRepository()
+	require.Nil(t, err)
+
+	hasGrafts, err := HasGrafts(goGitRepo)
+	assert.Nil(t, err)
+	assert.False(t, hasGrafts)
+
+	graftsPath := filepath.Join(repo.GetGitDir(), "info", "grafts")
+	require.Nil(t, os.MkdirAll(filepath.Dir(graftsPath), 0o755))
+	require.Nil(t, os.WriteFile(graftsPath, []byte(""), 0o600))
+
+	// An empty grafts file doesn't alter history
+	hasGrafts, err = HasGrafts(goGitRepo)
+	assert.Nil(t, err)
+	assert.False(t, hasGrafts)
+
+	require.Nil(t, os.WriteFile(graftsPath, []byte("abcdef\n"), 0o600))
+
+	hasGrafts, err = HasGrafts(goGitRepo)
+	assert.Nil(t, err)
+	assert.True(t, hasGrafts)
+}
@@ -18,6 +18,11 @@ const (
 	binary           = "git"
 	committerTimeKey = "GIT_COMMITTER_DATE"
 	authorTimeKey    = "GIT_AUTHOR_DATE"
+
+	// noReplaceObjectsEnv disables replace refs for Git commands executed by
+	// gittuf, so that gittuf always reads the objects recorded in the RSL
+	// rather than their replacements.
+	noReplaceObjectsEnv = "GIT_NO_REPLACE_OBJECTS=1"
 )
 
 // Repository is a lightweight wrapper around a Git repository. It stores the
@@ -144,7 +149,8 @@ func (r *Repository) executeGitCommandWithEnv(env []string, args ...string) (io.
 // directory without specifying the GIT_DIR explicitly.
 func (r *Repository) executeGitCommandDirectWithEnv(env []string, args ...string) (io.Reader, io.Reader, error) {
 	cmd := exec.Command(binary, args...)
-	cmd.Env = append(os.Environ(), env...)
+	cmd.Env = append(os.Environ(), noReplaceObjectsEnv)
+	cmd.Env = append(cmd.Env, env...)
 
 	var (
 		stdOut bytes.Buffer
@@ -185,6 +191,7 @@ func (r *Repository) executeGitCommandWithStdInString(stdIn *bytes.Buffer, args
 // the current directory without specifying the GIT_DIR explicitly.
 func (r *Repository) executeGitCommandDirectWithStdIn(stdIn *bytes.Buffer, args ...string) (io.Reader, io.Reader, error) {
 	cmd := exec.Command(binary, args...)
+	cmd.Env = append(os.Environ(), noReplaceObjectsEnv)
 
 	var (
 		stdOut bytes.Buffer
@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	// SSHKnownHostsFileConfigKey is the Git config key used to specify the
+	// known_hosts file used to verify SSH host keys. It defaults to
+	// ~/.ssh/known_hosts.
+	SSHKnownHostsFileConfigKey = "gittuf.ssh.knownhostsfile"
+
+	// SSHStrictHostKeyCheckingConfigKey is the Git config key used to specify
+	// how unknown SSH hosts are handled. It is one of
+	// SSHStrictHostKeyCheckingYes (the default) and
+	// SSHStrictHostKeyCheckingAcceptNew.
+	SSHStrictHostKeyCheckingConfigKey = "gittuf.ssh.stricthostkeychecking"
+
+	// SSHStrictHostKeyCheckingYes rejects connections to hosts that are not
+	// in the known_hosts file.
+	SSHStrictHostKeyCheckingYes = "yes"
+
+	// SSHStrictHostKeyCheckingAcceptNew adds the keys of hosts that are not in
+	// the known_hosts file to it. Connections to known hosts that present a
+	// different key are still rejected.
+	SSHStrictHostKeyCheckingAcceptNew = "accept-new"
+)
+
+var (
+	ErrUnknownSSHHost                 = errors.New("SSH host is not in known_hosts file")
+	ErrSSHHostKeyMismatch             = errors.New("SSH host key does not match known_hosts file, possible man-in-the-middle attack")
+	ErrInvalidStrictHostKeyCheckValue = errors.New("invalid value for strict SSH host key checking (not one of yes, accept-new)")
+)
+
+// getSSHAuth returns the authentication method for SSH remotes. Credentials
+// are obtained from the SSH agent, and the host key presented by the remote is
+// verified strictly against the known_hosts file configured for gittuf. For
+// remotes that do not use SSH, nil is returned so the default authentication
+// is used.
+func getSSHAuth(remoteURL string) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if endpoint.Protocol != "ssh" {
+		return nil, nil
+	}
+
+	gitConfig, err := GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	knownHostsFile, err := getSSHKnownHostsFile(gitConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	strictHostKeyChecking, has := gitConfig[SSHStrictHostKeyCheckingConfigKey]
+	if !has {
+		strictHostKeyChecking = SSHStrictHostKeyCheckingYes
+	}
+
+	hostKeyCallback, err := newSSHHostKeyCallback(knownHostsFile, strictHostKeyChecking)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := gitssh.NewSSHAgentAuth(endpoint.User)
+	if err != nil {
+		return nil, err
+	}
+	auth.HostKeyCallback = hostKeyCallback
+
+	return auth, nil
+}
+
+func getSSHKnownHostsFile(gitConfig map[string]string) (string, error) {
+	if knownHostsFile, has := gitConfig[SSHKnownHostsFileConfigKey]; has {
+		if strings.HasPrefix(knownHostsFile, "~/") {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			knownHostsFile = filepath.Join(homeDir, knownHostsFile[2:])
+		}
+
+		return knownHostsFile, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, ".ssh", "known_hosts"), nil
+}
+
+// newSSHHostKeyCallback returns a callback that verifies SSH host keys against
+// the specified known_hosts file. Hosts presenting a key that doesn't match
+// the known_hosts file are always rejected. Hosts that aren't in the
+// known_hosts file are added to it only if strictHostKeyChecking is
+// SSHStrictHostKeyCheckingAcceptNew.
+func newSSHHostKeyCallback(knownHostsFile, strictHostKeyChecking string) (ssh.HostKeyCallback, error) {
+	switch strictHostKeyChecking {
+	case SSHStrictHostKeyCheckingYes:
+		callback, err := knownhosts.New(knownHostsFile)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			return checkSSHHostKey(callback, hostname, remote, key)
+		}, nil
+
+	case SSHStrictHostKeyCheckingAcceptNew:
+		// The known_hosts file may not exist yet, create it so that new hosts
+		// can be added to it
+		if err := os.MkdirAll(filepath.Dir(knownHostsFile), 0o700); err != nil {
+			return nil, err
+		}
+		file, err := os.OpenFile(knownHostsFile, os.O_CREATE|os.O_RDONLY, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		if err := file.Close(); err != nil {
+			return nil, err
+		}
+
+		return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			// Reload the file each time as it may have been updated
+			callback, err := knownhosts.New(knownHostsFile)
+			if err != nil {
+				return err
+			}
+
+			err = checkSSHHostKey(callback, hostname, remote, key)
+			if !errors.Is(err, ErrUnknownSSHHost) {
+				return err
+			}
+
+			slog.Warn(fmt.Sprintf("Adding host key for '%s' to '%s'...", hostname, knownHostsFile))
+			return addSSHKnownHost(knownHostsFile, hostname, remote, key)
+		}, nil
+
+	default:
+		return nil, ErrInvalidStrictHostKeyCheckValue
+	}
+}
+
+func checkSSHHostKey(callback ssh.HostKeyCallback, hostname string, remote net.Addr, key ssh.PublicKey) error {
+	err := callback(hostname, remote, key)
+	if err == nil {
+		return nil
+	}
+
+	var keyErr *knownhosts.KeyError
+	if errors.As(err, &keyErr) {
+		if len(keyErr.Want) == 0 {
+			return fmt.Errorf("%w: '%s'", ErrUnknownSSHHost, hostname)
+		}
+
+		return fmt.Errorf("%w: '%s'", ErrSSHHostKeyMismatch, hostname)
+	}
+
+	return err
+}
+
+func addSSHKnownHost(knownHostsFile, hostname string, remote net.Addr, key ssh.PublicKey) error {
+	addresses := []string{knownhosts.Normalize(hostname)}
+	if remote != nil {
+		if remoteAddress := knownhosts.Normalize(remote.String()); remoteAddress != addresses[0] {
+			addresses = append(addresses, remoteAddress)
+		}
+	}
+
+	file, err := os.OpenFile(knownHostsFile, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close() //nolint:errcheck
+
+	_, err = file.WriteString(knownhosts.Line(addresses, key) + "\n")
+	return err
+}
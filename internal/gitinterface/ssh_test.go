@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func TestNewSSHHostKeyCallback(t *testing.T) {
+	hostname := "example.com:22"
+	remote := &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 22}
+
+	hostKey := generateTestSSHPublicKey(t)
+	otherHostKey := generateTestSSHPublicKey(t)
+
+	t.Run("strict, known host", func(t *testing.T) {
+		knownHostsFile := filepath.Join(t.TempDir(), "known_hosts")
+		if err := os.WriteFile(knownHostsFile, []byte(knownhosts.Line([]string{knownhosts.Normalize(hostname)}, hostKey)+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		callback, err := newSSHHostKeyCallback(knownHostsFile, SSHStrictHostKeyCheckingYes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = callback(hostname, remote, hostKey)
+		assert.Nil(t, err)
+
+		err = callback(hostname, remote, otherHostKey)
+		assert.ErrorIs(t, err, ErrSSHHostKeyMismatch)
+	})
+
+	t.Run("strict, unknown host", func(t *testing.T) {
+		knownHostsFile := filepath.Join(t.TempDir(), "known_hosts")
+		if err := os.WriteFile(knownHostsFile, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		callback, err := newSSHHostKeyCallback(knownHostsFile, SSHStrictHostKeyCheckingYes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = callback(hostname, remote, hostKey)
+		assert.ErrorIs(t, err, ErrUnknownSSHHost)
+
+		// The unknown host must not be added
+		contents, err := os.ReadFile(knownHostsFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, contents)
+	})
+
+	t.Run("strict, missing known_hosts file", func(t *testing.T) {
+		knownHostsFile := filepath.Join(t.TempDir(), "known_hosts")
+
+		_, err := newSSHHostKeyCallback(knownHostsFile, SSHStrictHostKeyCheckingYes)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("accept new", func(t *testing.T) {
+		knownHostsFile := filepath.Join(t.TempDir(), "ssh", "known_hosts")
+
+		callback, err := newSSHHostKeyCallback(knownHostsFile, SSHStrictHostKeyCheckingAcceptNew)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Unknown host is added
+		err = callback(hostname, remote, hostKey)
+		assert.Nil(t, err)
+
+		err = callback(hostname, remote, hostKey)
+		assert.Nil(t, err)
+
+		// Changed key is still rejected
+		err = callback(hostname, remote, otherHostKey)
+		assert.ErrorIs(t, err, ErrSSHHostKeyMismatch)
+
+		// The added host is trusted in strict mode
+		callback, err = newSSHHostKeyCallback(knownHostsFile, SSHStrictHostKeyCheckingYes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = callback(hostname, remote, hostKey)
+		assert.Nil(t, err)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		_, err := newSSHHostKeyCallback(filepath.Join(t.TempDir(), "known_hosts"), "no")
+		assert.ErrorIs(t, err, ErrInvalidStrictHostKeyCheckValue)
+	})
+}
+
+func TestGetSSHKnownHostsFile(t *testing.T) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		gitConfig              map[string]string
+		expectedKnownHostsFile string
+	}{
+		"default": {
+			gitConfig:              map[string]string{},
+			expectedKnownHostsFile: filepath.Join(homeDir, ".ssh", "known_hosts"),
+		},
+		"absolute path": {
+			gitConfig:              map[string]string{SSHKnownHostsFileConfigKey: "/etc/gittuf/known_hosts"},
+			expectedKnownHostsFile: "/etc/gittuf/known_hosts",
+		},
+		"path in home directory": {
+			gitConfig:              map[string]string{SSHKnownHostsFileConfigKey: "~/gittuf_known_hosts"},
+			expectedKnownHostsFile: filepath.Join(homeDir, "gittuf_known_hosts"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			knownHostsFile, err := getSSHKnownHostsFile(test.gitConfig)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expectedKnownHostsFile, knownHostsFile)
+		})
+	}
+}
+
+func TestGetSSHAuth(t *testing.T) {
+	for _, remoteURL := range []string{"https://github.com/gittuf/gittuf", "/tmp/repository", "file:///tmp/repository"} {
+		auth, err := getSSHAuth(remoteURL)
+		assert.Nil(t, err)
+		assert.Nil(t, auth)
+	}
+}
+
+func generateTestSSHPublicKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sshPublicKey
+}
@@ -5,7 +5,9 @@ package gitinterface
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
@@ -17,6 +19,16 @@ import (
 
 const DefaultRemoteName = "origin"
 
+var (
+	// ErrNotFastForward is returned when a push or fetch is rejected because
+	// the local and remote refs have diverged.
+	ErrNotFastForward = errors.New("ref update is not a fast-forward, local and remote refs have diverged")
+
+	// ErrRemoteRefNotFound is returned when a ref requested in a fetch
+	// doesn't exist in the remote.
+	ErrRemoteRefNotFound = errors.New("remote ref not found")
+)
+
 // PushRefSpec pushes from repo to the specified remote using pre-constructed
 // refspecs. For more information on the Git refspec, please consult:
 // https://git-scm.com/book/en/v2/Git-Internals-The-Refspec.
@@ -24,22 +36,32 @@ const DefaultRemoteName = "origin"
 // All pushes are set to be atomic as the intent of using multiple refs is to
 // sync the RSL.
 func PushRefSpec(ctx context.Context, repo *git.Repository, remoteName string, refs []config.RefSpec) error {
+	if useGitTransport() {
+		return pushRefSpecUsingGit(repo, remoteName, refs)
+	}
+
 	remote, err := repo.Remote(remoteName)
 	if err != nil {
 		return err
 	}
 
+	auth, err := getSSHAuth(remote.Config().URLs[0])
+	if err != nil {
+		return err
+	}
+
 	pushOpts := &git.PushOptions{
 		RemoteName: remoteName,
 		RefSpecs:   refs,
 		Atomic:     true,
+		Auth:       auth,
 	}
 
 	err = remote.PushContext(ctx, pushOpts)
 	if errors.Is(err, git.NoErrAlreadyUpToDate) {
 		return nil
 	}
-	return err
+	return wrapNotFastForward(err)
 }
 
 // Push constructs refspecs for the specified Git refs and pushes from the repo
@@ -64,20 +86,73 @@ func Push(ctx context.Context, repo *git.Repository, remoteName string, refs []s
 // pre-constructed refspecs. For more information on the Git refspec, please
 // consult: https://git-scm.com/book/en/v2/Git-Internals-The-Refspec.
 func FetchRefSpec(ctx context.Context, repo *git.Repository, remoteName string, refs []config.RefSpec) error {
+	if useGitTransport() {
+		return fetchRefSpecUsingGit(repo, remoteName, refs)
+	}
+
 	remote, err := repo.Remote(remoteName)
 	if err != nil {
 		return err
 	}
 
+	auth, err := getSSHAuth(remote.Config().URLs[0])
+	if err != nil {
+		return err
+	}
+
 	fetchOpts := &git.FetchOptions{
 		RemoteName: remoteName,
 		RefSpecs:   refs,
+		Auth:       auth,
 	}
 
 	err = remote.FetchContext(ctx, fetchOpts)
 	if errors.Is(err, transport.ErrEmptyRemoteRepository) || errors.Is(err, git.NoErrAlreadyUpToDate) {
 		return nil
 	}
+	if errors.Is(err, git.NoMatchingRefSpecError{}) {
+		return fmt.Errorf("%w: %w", ErrRemoteRefNotFound, err)
+	}
+	return wrapNotFastForward(err)
+}
+
+// ListRemoteReferences returns the refs advertised by the specified remote.
+// Symbolic refs, such as HEAD, are included with their targets unresolved. An
+// empty remote advertises no refs.
+func ListRemoteReferences(ctx context.Context, repo *git.Repository, remoteName string) ([]*plumbing.Reference, error) {
+	if useGitTransport() {
+		return listRemoteReferencesUsingGit(repo, remoteName)
+	}
+
+	remote, err := repo.Remote(remoteName)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := getSSHAuth(remote.Config().URLs[0])
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth})
+	if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return []*plumbing.Reference{}, nil
+	}
+	return refs, err
+}
+
+// wrapNotFastForward wraps errors for rejected non-fast-forward updates with
+// ErrNotFastForward. go-git reports these for pushes using an error that only
+// identifies the ref, so the error's message must be inspected.
+func wrapNotFastForward(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, git.ErrForceNeeded) || strings.HasPrefix(err.Error(), "non-fast-forward update") {
+		return fmt.Errorf("%w: %w", ErrNotFastForward, err)
+	}
+
 	return err
 }
 
@@ -112,7 +187,58 @@ func Fetch(ctx context.Context, repo *git.Repository, remoteName string, refs []
 // CloneAndFetch clones a repository using the specified URL and additionally
 // fetches the specified refs.
 func CloneAndFetch(ctx context.Context, remoteURL, dir, initialBranch string, refs []string) (*git.Repository, error) {
-	repo, err := git.PlainCloneContext(ctx, dir, false, createCloneOptions(remoteURL, initialBranch))
+	cloneOptions, err := createCloneOptions(remoteURL, initialBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	cloneFn := func() (*git.Repository, error) {
+		return git.PlainCloneContext(ctx, dir, false, cloneOptions)
+	}
+	if useGitTransport() {
+		cloneFn = func() (*git.Repository, error) {
+			return cloneUsingGit(remoteURL, dir, initialBranch, true)
+		}
+	}
+
+	repo, err := cloneOrInitialize(ctx, cloneOptions, cloneFn,
+		func(initOptions git.InitOptions) (*git.Repository, error) {
+			return git.PlainInitWithOptions(dir, &git.PlainInitOptions{InitOptions: initOptions, ObjectFormat: initObjectFormat()})
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return fetchRefs(ctx, repo, refs, true)
+}
+
+// CloneAndFetchWithoutCheckout clones a repository using the specified URL and
+// additionally fetches the specified refs, like CloneAndFetch. However, the
+// working tree is not checked out, so that the repository's contents can be
+// inspected before they are written to disk. CheckoutHead must be used to check
+// out the working tree.
+func CloneAndFetchWithoutCheckout(ctx context.Context, remoteURL, dir, initialBranch string, refs []string) (*git.Repository, error) {
+	cloneOptions, err := createCloneOptions(remoteURL, initialBranch)
+	if err != nil {
+		return nil, err
+	}
+	cloneOptions.NoCheckout = true
+
+	cloneFn := func() (*git.Repository, error) {
+		return git.PlainCloneContext(ctx, dir, false, cloneOptions)
+	}
+	if useGitTransport() {
+		cloneFn = func() (*git.Repository, error) {
+			return cloneUsingGit(remoteURL, dir, initialBranch, false)
+		}
+	}
+
+	repo, err := cloneOrInitialize(ctx, cloneOptions, cloneFn,
+		func(initOptions git.InitOptions) (*git.Repository, error) {
+			return git.PlainInitWithOptions(dir, &git.PlainInitOptions{InitOptions: initOptions, ObjectFormat: initObjectFormat()})
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -120,10 +246,43 @@ func CloneAndFetch(ctx context.Context, remoteURL, dir, initialBranch string, re
 	return fetchRefs(ctx, repo, refs, true)
 }
 
+// CheckoutHead checks out the commit HEAD points to in the repository's
+// working tree and index.
+func CheckoutHead(repo *git.Repository) error {
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	return worktree.Reset(&git.ResetOptions{Commit: head.Hash(), Mode: git.HardReset})
+}
+
 // CloneAndFetchToMemory clones an in-memory repository using the specified URL
-// and additionally fetches the specified refs.
+// and additionally fetches the specified refs. Repositories that use the
+// SHA-256 object format can't be cloned to memory.
 func CloneAndFetchToMemory(ctx context.Context, remoteURL, initialBranch string, refs []string) (*git.Repository, error) {
-	repo, err := git.CloneContext(ctx, memory.NewStorage(), memfs.New(), createCloneOptions(remoteURL, initialBranch))
+	if useGitTransport() {
+		return nil, ErrInMemoryRepository
+	}
+
+	cloneOptions, err := createCloneOptions(remoteURL, initialBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := cloneOrInitialize(ctx, cloneOptions,
+		func() (*git.Repository, error) {
+			return git.CloneContext(ctx, memory.NewStorage(), memfs.New(), cloneOptions)
+		},
+		func(initOptions git.InitOptions) (*git.Repository, error) {
+			return git.InitWithOptions(memory.NewStorage(), memfs.New(), initOptions)
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -131,16 +290,83 @@ func CloneAndFetchToMemory(ctx context.Context, remoteURL, initialBranch string,
 	return fetchRefs(ctx, repo, refs, true)
 }
 
-func createCloneOptions(remoteURL, initialBranch string) *git.CloneOptions {
+// cloneOrInitialize clones the repository using cloneFn. If the remote has no
+// commits for its HEAD, such as a freshly created repository that is empty or
+// only contains gittuf refs, a repository is instead initialized using initFn
+// with the remote configured and its branches, if any, fetched. This mirrors
+// how `git clone` handles empty repositories. HEAD points to the requested
+// branch, or the default branch if none is requested, and is unborn.
+func cloneOrInitialize(ctx context.Context, cloneOptions *git.CloneOptions, cloneFn func() (*git.Repository, error), initFn func(git.InitOptions) (*git.Repository, error)) (*git.Repository, error) {
+	// go-git sets the reference name to HEAD when validating the options
+	branchName := cloneOptions.ReferenceName
+
+	repo, err := cloneFn()
+	if err == nil {
+		return repo, nil
+	}
+
+	// If a specific branch was requested and the remote isn't empty, the
+	// branch must exist
+	isEmptyRemote := errors.Is(err, transport.ErrEmptyRemoteRepository)
+	isUnbornRemoteHead := errors.Is(err, plumbing.ErrReferenceNotFound) && branchName == ""
+	if !isEmptyRemote && !isUnbornRemoteHead {
+		return nil, err
+	}
+
+	if branchName == "" {
+		branchName = defaultBranchName()
+	}
+
+	repo, err = initFn(git.InitOptions{DefaultBranch: branchName})
+	if err != nil {
+		return nil, err
+	}
+
+	remoteConfig := &config.RemoteConfig{
+		Name:  DefaultRemoteName,
+		URLs:  []string{cloneOptions.URL},
+		Fetch: []config.RefSpec{config.RefSpec(fmt.Sprintf(config.DefaultFetchRefSpec, DefaultRemoteName))},
+	}
+	if _, err := repo.CreateRemote(remoteConfig); err != nil {
+		return nil, err
+	}
+
+	if err := FetchRefSpec(ctx, repo, DefaultRemoteName, remoteConfig.Fetch); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// defaultBranchName returns the branch configured in Git's init.defaultBranch
+// option, falling back to Git's default.
+func defaultBranchName() plumbing.ReferenceName {
+	config, err := GetConfig()
+	if err == nil {
+		if branchName, has := config["init.defaultbranch"]; has && branchName != "" {
+			return plumbing.NewBranchReferenceName(branchName)
+		}
+	}
+
+	return plumbing.Master
+}
+
+func createCloneOptions(remoteURL, initialBranch string) (*git.CloneOptions, error) {
+	auth, err := getSSHAuth(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
 	cloneOptions := &git.CloneOptions{
 		URL:      remoteURL,
 		Progress: os.Stdout,
+		Auth:     auth,
 	}
 	if len(initialBranch) > 0 {
 		cloneOptions.ReferenceName = plumbing.ReferenceName(initialBranch)
 	}
 
-	return cloneOptions
+	return cloneOptions, nil
 }
 
 func fetchRefs(ctx context.Context, repo *git.Repository, refs []string, fastForwardOnly bool) (*git.Repository, error) {
@@ -17,6 +17,8 @@ import (
 )
 
 func TestPushRefSpec(t *testing.T) {
+	skipIfGitTransport(t)
+
 	remoteName := "origin"
 	refName := "refs/heads/main"
 	refSpecs := []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", refName, refName))}
@@ -140,6 +142,8 @@ func TestPushRefSpec(t *testing.T) {
 }
 
 func TestPush(t *testing.T) {
+	skipIfGitTransport(t)
+
 	remoteName := "origin"
 	refName := "refs/heads/main"
 	refNameTyped := plumbing.ReferenceName(refName)
@@ -259,9 +263,54 @@ func TestPush(t *testing.T) {
 		err = Push(context.Background(), repoLocal, remoteName, []string{refName})
 		assert.Nil(t, err) // no error when it's already up to date
 	})
+
+	t.Run("assert error when local and remote refs have diverged", func(t *testing.T) {
+		// The local repo can be in-memory
+		repoLocal, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create tmp dir for remote repo so we have a URL for it
+		tmpDir := t.TempDir()
+
+		repoRemote, err := git.PlainInit(tmpDir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = repoLocal.CreateRemote(&config.RemoteConfig{
+			Name: remoteName,
+			URLs: []string{tmpDir},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emptyTreeHash, err := WriteTree(repoLocal, []object.TreeEntry{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Commit(repoLocal, emptyTreeHash, refName, "Local commit", false); err != nil {
+			t.Fatal(err)
+		}
+
+		emptyTreeHash, err = WriteTree(repoRemote, []object.TreeEntry{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Commit(repoRemote, emptyTreeHash, refName, "Remote commit", false); err != nil {
+			t.Fatal(err)
+		}
+
+		err = Push(context.Background(), repoLocal, remoteName, []string{refName})
+		assert.ErrorIs(t, err, ErrNotFastForward)
+	})
 }
 
 func TestFetchRefSpec(t *testing.T) {
+	skipIfGitTransport(t)
+
 	remoteName := "origin"
 	refName := "refs/heads/main"
 	refSpecs := []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", refName, refName))}
@@ -397,6 +446,8 @@ func TestFetchRefSpec(t *testing.T) {
 }
 
 func TestFetch(t *testing.T) {
+	skipIfGitTransport(t)
+
 	remoteName := "origin"
 	refName := "refs/heads/main"
 	refNameTyped := plumbing.ReferenceName(refName)
@@ -526,7 +577,62 @@ func TestFetch(t *testing.T) {
 	})
 }
 
+func TestListRemoteReferences(t *testing.T) {
+	skipIfGitTransport(t)
+
+	remoteName := "origin"
+	refName := "refs/heads/main"
+
+	repoLocal, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	repoRemote, err := git.PlainInit(tmpDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repoLocal.CreateRemote(&config.RemoteConfig{
+		Name: remoteName,
+		URLs: []string{tmpDir},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// An empty remote advertises no refs
+	refs, err := ListRemoteReferences(context.Background(), repoLocal, remoteName)
+	assert.Nil(t, err)
+	assert.Empty(t, refs)
+
+	emptyTreeHash, err := WriteTree(repoRemote, []object.TreeEntry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteCommitID, err := Commit(repoRemote, emptyTreeHash, refName, "Test commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err = ListRemoteReferences(context.Background(), repoLocal, remoteName)
+	assert.Nil(t, err)
+
+	advertised := map[string]plumbing.Hash{}
+	for _, ref := range refs {
+		if ref.Type() == plumbing.HashReference {
+			advertised[ref.Name().String()] = ref.Hash()
+		}
+	}
+	assert.Equal(t, map[string]plumbing.Hash{refName: remoteCommitID}, advertised)
+
+	_, err = ListRemoteReferences(context.Background(), repoLocal, "unknown")
+	assert.ErrorIs(t, err, git.ErrRemoteNotFound)
+}
+
 func TestCloneAndFetch(t *testing.T) {
+	skipIfGitTransport(t)
+
 	refName := "refs/heads/main"
 	anotherRefName := "refs/heads/feature"
 
@@ -652,7 +758,69 @@ func TestCloneAndFetch(t *testing.T) {
 	})
 }
 
+func TestCloneEmptyRemote(t *testing.T) {
+	skipIfGitTransport(t)
+
+	refName := "refs/heads/main"
+	gittufRefName := "refs/gittuf/reference-state-log"
+
+	t.Run("remote has no refs", func(t *testing.T) {
+		remoteTmpDir := t.TempDir()
+		localTmpDir := t.TempDir()
+
+		if _, err := git.PlainInit(remoteTmpDir, true); err != nil {
+			t.Fatal(err)
+		}
+
+		localRepo, err := CloneAndFetch(context.Background(), remoteTmpDir, localTmpDir, refName, []string{"refs/gittuf/*"})
+		assert.Nil(t, err)
+
+		// HEAD points to the requested branch, which has no commits yet
+		head, err := localRepo.Storer.Reference(plumbing.HEAD)
+		assert.Nil(t, err)
+		assert.Equal(t, plumbing.ReferenceName(refName), head.Target())
+		_, err = localRepo.Head()
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+
+		remote, err := localRepo.Remote(DefaultRemoteName)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{remoteTmpDir}, remote.Config().URLs)
+	})
+
+	t.Run("remote has only gittuf refs", func(t *testing.T) {
+		remoteTmpDir := t.TempDir()
+
+		remoteRepo, err := git.PlainInit(remoteTmpDir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remoteRepo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.ReferenceName(refName))); err != nil {
+			t.Fatal(err)
+		}
+		gittufCommitID, err := Commit(remoteRepo, EmptyTree(), gittufRefName, "Commit to gittuf ref", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		localRepo, err := CloneAndFetchToMemory(context.Background(), remoteTmpDir, "", []string{"refs/gittuf/*"})
+		assert.Nil(t, err)
+
+		_, err = localRepo.Head()
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+
+		ref, err := localRepo.Reference(plumbing.ReferenceName(gittufRefName), true)
+		assert.Nil(t, err)
+		assert.Equal(t, gittufCommitID, ref.Hash())
+
+		// If a branch is requested, it must exist
+		_, err = CloneAndFetchToMemory(context.Background(), remoteTmpDir, refName, []string{"refs/gittuf/*"})
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+}
+
 func TestCloneAndFetchToMemory(t *testing.T) {
+	skipIfGitTransport(t)
+
 	refName := "refs/heads/main"
 	anotherRefName := "refs/heads/feature"
 	// refs := []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", anotherRefName, anotherRefName))}
@@ -793,3 +961,14 @@ func assertLocalRefAndRemoteTrackerRef(t *testing.T, repo *git.Repository, refNa
 	}
 	assert.Equal(t, expectedCommitID, localRemoteTrackerRef.Hash())
 }
+
+// skipIfGitTransport skips tests of go-git's transport in builds where remotes
+// are communicated with using the Git binary, which is tested by
+// TestGitTransport.
+func skipIfGitTransport(t *testing.T) {
+	t.Helper()
+
+	if useGitTransport() {
+		t.Skip("remotes are communicated with using the Git binary")
+	}
+}
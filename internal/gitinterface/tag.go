@@ -8,8 +8,11 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
+	"github.com/gittuf/gittuf/internal/perf"
 	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/x509"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
@@ -203,6 +206,18 @@ func VerifyTagSignature(ctx context.Context, tag *object.Tag, key *tuf.Key) erro
 			return errors.Join(ErrIncorrectVerificationKey, err)
 		}
 
+		return nil
+	case x509.X509KeyType:
+		tagContents, err := getTagBytesWithoutSignature(tag)
+		if err != nil {
+			return errors.Join(ErrVerifyingX509Signature, err)
+		}
+		tagSignature := []byte(tag.PGPSignature)
+
+		if err := verifyX509Signature(ctx, key, tagContents, tagSignature); err != nil {
+			return errors.Join(ErrIncorrectVerificationKey, err)
+		}
+
 		return nil
 	case signerverifier.FulcioKeyType:
 		tagContents, err := getTagBytesWithoutSignature(tag)
@@ -221,6 +236,29 @@ func VerifyTagSignature(ctx context.Context, tag *object.Tag, key *tuf.Key) erro
 	return ErrUnknownSigningMethod
 }
 
+// VerifyTagSignatureAt verifies the tag's signature like VerifyTagSignature.
+// Additionally, a GPG signature made using a key that has since expired is
+// accepted if the key was valid at recordedAt, the trusted time the tag was
+// recorded at, and the signature was created no later. If recordedAt is zero,
+// this is the same as VerifyTagSignature.
+func VerifyTagSignatureAt(ctx context.Context, tag *object.Tag, key *tuf.Key, recordedAt time.Time) error {
+	if key.KeyType != signerverifier.GPGKeyType || recordedAt.IsZero() {
+		return VerifyTagSignature(ctx, tag, key)
+	}
+
+	tagContents, err := getTagBytesWithoutSignature(tag)
+	if err != nil {
+		return err
+	}
+	tagSignature := []byte(tag.PGPSignature)
+
+	if err := verifyGPGSignatureAt(key, tagContents, tagSignature, recordedAt); err != nil {
+		return errors.Join(ErrIncorrectVerificationKey, err)
+	}
+
+	return nil
+}
+
 // verifyTagSignature verifies a signature for the specified tag using the
 // provided public key.
 func (r *Repository) verifyTagSignature(ctx context.Context, tagID Hash, key *tuf.Key) error {
@@ -252,6 +290,18 @@ func (r *Repository) verifyTagSignature(ctx context.Context, tagID Hash, key *tu
 			return errors.Join(ErrIncorrectVerificationKey, err)
 		}
 
+		return nil
+	case x509.X509KeyType:
+		tagContents, err := getTagBytesWithoutSignature(tag)
+		if err != nil {
+			return errors.Join(ErrVerifyingX509Signature, err)
+		}
+		tagSignature := []byte(tag.PGPSignature)
+
+		if err := verifyX509Signature(ctx, key, tagContents, tagSignature); err != nil {
+			return errors.Join(ErrIncorrectVerificationKey, err)
+		}
+
 		return nil
 	case signerverifier.FulcioKeyType:
 		tagContents, err := getTagBytesWithoutSignature(tag)
@@ -272,6 +322,8 @@ func (r *Repository) verifyTagSignature(ctx context.Context, tagID Hash, key *tu
 
 // GetTag returns the requested tag object.
 func GetTag(repo *git.Repository, tagID plumbing.Hash) (*object.Tag, error) {
+	defer perf.Track(perf.ObjectReads)()
+
 	return repo.TagObject(tagID)
 }
 
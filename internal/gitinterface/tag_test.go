@@ -52,7 +52,7 @@ func TestTag(t *testing.T) {
 
 	tagHash, err := Tag(repo, commitID, tagName, tagName, false)
 	assert.Nil(t, err)
-	assert.Equal(t, "8b195348588d8a48060ec8d5436459b825a1b352", tagHash.String())
+	assert.Equal(t, hashForBuildObjectFormat("8b195348588d8a48060ec8d5436459b825a1b352", "a84cfb67e234d9b311daebab3e51364226fdc5438886e8d29a5a609cd2a2e80e"), tagHash.String())
 
 	tag, err := GetTag(repo, tagHash)
 	if err != nil {
@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	formatcfg "github.com/go-git/go-git/v5/plumbing/format/config"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/jonboulle/clockwork"
+)
+
+// ErrInMemoryRepository is returned when the Git binary must be used for a
+// repository that only exists in memory.
+var ErrInMemoryRepository = errors.New("operation requires a repository on disk")
+
+// useGitTransport returns true if remotes must be communicated with using the
+// Git binary. go-git's transport only supports SHA-1 object IDs, so this is
+// the case for builds that read and write SHA-256 repositories.
+func useGitTransport() bool {
+	return BuildObjectFormat() == ObjectFormatSHA256
+}
+
+// gitBinaryRepositoryForRemote returns the Repository used to execute Git
+// commands for the go-git repository, after checking that the remote exists.
+func gitBinaryRepositoryForRemote(repo *git.Repository, remoteName string) (*Repository, error) {
+	if _, err := repo.Remote(remoteName); err != nil {
+		return nil, err
+	}
+
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return nil, ErrInMemoryRepository
+	}
+
+	return &Repository{gitDirPath: storage.Filesystem().Root(), clock: clockwork.NewRealClock()}, nil
+}
+
+// pushRefSpecUsingGit atomically pushes the refspecs to the remote using the
+// Git binary.
+func pushRefSpecUsingGit(repo *git.Repository, remoteName string, refs []config.RefSpec) error {
+	r, err := gitBinaryRepositoryForRemote(repo, remoteName)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"push", "--atomic", remoteName}
+	for _, refSpec := range refs {
+		args = append(args, refSpec.String())
+	}
+
+	if _, err := r.executeGitCommandString(args...); err != nil {
+		// Git reports why refs were rejected in parentheses
+		if strings.Contains(err.Error(), "(non-fast-forward)") || strings.Contains(err.Error(), "(fetch first)") {
+			return fmt.Errorf("%w: %w", ErrNotFastForward, err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// fetchRefSpecUsingGit fetches the refspecs from the remote using the Git
+// binary. Like go-git, fetching from an empty remote is a no-op, and fetching a
+// ref the remote doesn't have returns ErrRemoteRefNotFound.
+func fetchRefSpecUsingGit(repo *git.Repository, remoteName string, refs []config.RefSpec) error {
+	r, err := gitBinaryRepositoryForRemote(repo, remoteName)
+	if err != nil {
+		return err
+	}
+
+	advertisedRefs, err := r.listRemoteReferences(remoteName)
+	if err != nil {
+		return err
+	}
+	if len(advertisedRefs) == 0 {
+		return nil
+	}
+
+	args := []string{"fetch", "--no-tags", "--no-write-fetch-head", remoteName}
+	for _, refSpec := range refs {
+		if !refSpec.IsWildcard() && !slices.ContainsFunc(advertisedRefs, func(ref *plumbing.Reference) bool { return ref.Name().String() == refSpec.Src() }) {
+			return fmt.Errorf("%w: '%s'", ErrRemoteRefNotFound, refSpec.Src())
+		}
+		args = append(args, refSpec.String())
+	}
+
+	if _, err := r.executeGitCommandString(args...); err != nil {
+		if strings.Contains(err.Error(), "(non-fast-forward)") {
+			return fmt.Errorf("%w: %w", ErrNotFastForward, err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// listRemoteReferencesUsingGit returns the refs advertised by the remote using
+// the Git binary.
+func listRemoteReferencesUsingGit(repo *git.Repository, remoteName string) ([]*plumbing.Reference, error) {
+	r, err := gitBinaryRepositoryForRemote(repo, remoteName)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.listRemoteReferences(remoteName)
+}
+
+// listRemoteReferences returns the refs advertised by the remote, including
+// HEAD as a symbolic ref if the remote advertises its target. Peeled tags are
+// omitted.
+func (r *Repository) listRemoteReferences(remoteName string) ([]*plumbing.Reference, error) {
+	output, err := r.executeGitCommandString("ls-remote", "--symref", remoteName)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := []*plumbing.Reference{}
+	symbolicRefNames := map[string]bool{}
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+
+		value, refName, found := strings.Cut(line, "\t")
+		if !found {
+			return nil, fmt.Errorf("unexpected output when listing remote refs: '%s'", line)
+		}
+
+		if target, isSymbolic := strings.CutPrefix(value, "ref: "); isSymbolic {
+			symbolicRefNames[refName] = true
+			refs = append(refs, plumbing.NewSymbolicReference(plumbing.ReferenceName(refName), plumbing.ReferenceName(target)))
+			continue
+		}
+
+		if symbolicRefNames[refName] || strings.HasSuffix(refName, "^{}") {
+			continue
+		}
+
+		if !plumbing.IsHash(value) {
+			return nil, fmt.Errorf("unexpected output when listing remote refs: '%s'", line)
+		}
+		refs = append(refs, plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.NewHash(value)))
+	}
+
+	return refs, nil
+}
+
+// cloneUsingGit clones the remote to dir using the Git binary, checking out the
+// initial branch if specified, or the remote's HEAD otherwise. Like go-git,
+// transport.ErrEmptyRemoteRepository is returned if the remote has no refs,
+// and plumbing.ErrReferenceNotFound is returned if the branch to check out
+// doesn't exist, so that cloneOrInitialize can handle empty remotes.
+func cloneUsingGit(remoteURL, dir, initialBranch string, checkout bool) (*git.Repository, error) {
+	r := &Repository{clock: clockwork.NewRealClock()}
+
+	advertisedRefs, err := r.listRemoteReferences(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	if len(advertisedRefs) == 0 {
+		return nil, transport.ErrEmptyRemoteRepository
+	}
+
+	branchName := plumbing.HEAD
+	if len(initialBranch) > 0 {
+		branchName = plumbing.ReferenceName(BranchReferenceName(initialBranch))
+	}
+	for _, ref := range advertisedRefs {
+		if ref.Name() == branchName && ref.Type() == plumbing.SymbolicReference {
+			// The remote's HEAD is advertised as its target
+			branchName = ref.Target()
+			break
+		}
+	}
+	if !slices.ContainsFunc(advertisedRefs, func(ref *plumbing.Reference) bool {
+		return ref.Name() == branchName && ref.Type() == plumbing.HashReference
+	}) {
+		return nil, plumbing.ErrReferenceNotFound
+	}
+
+	args := []string{"clone", "--quiet"}
+	if !checkout {
+		args = append(args, "--no-checkout")
+	}
+	if len(initialBranch) > 0 {
+		args = append(args, "--branch", branchName.Short())
+	}
+	args = append(args, "--", remoteURL, dir)
+
+	if _, err := r.executeGitCommandDirectString(args...); err != nil {
+		return nil, err
+	}
+
+	return git.PlainOpen(dir)
+}
+
+// PlainInit creates a repository in dir using go-git, like git.PlainInit. The
+// repository uses the object format gittuf is built for.
+func PlainInit(dir string, isBare bool) (*git.Repository, error) {
+	return git.PlainInitWithOptions(dir, &git.PlainInitOptions{Bare: isBare, ObjectFormat: initObjectFormat()})
+}
+
+// initObjectFormat returns the object format repositories are initialized
+// using by go-git, which must be set explicitly for SHA-256 repositories.
+func initObjectFormat() formatcfg.ObjectFormat {
+	if BuildObjectFormat() == ObjectFormatSHA256 {
+		return formatcfg.SHA256
+	}
+	return ""
+}
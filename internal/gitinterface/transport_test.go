@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitTransport(t *testing.T) {
+	remoteName := "origin"
+	refName := "refs/heads/main"
+	refSpecs := []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", refName, refName))}
+
+	remoteTmpDir := t.TempDir()
+	repoRemote, err := PlainInit(remoteTmpDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repoRemote.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.ReferenceName(refName))); err != nil {
+		t.Fatal(err)
+	}
+
+	repoLocal, err := PlainInit(t.TempDir(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repoLocal.CreateRemote(&config.RemoteConfig{Name: remoteName, URLs: []string{remoteTmpDir}}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("empty remote", func(t *testing.T) {
+		refs, err := listRemoteReferencesUsingGit(repoLocal, remoteName)
+		assert.Nil(t, err)
+		assert.Empty(t, refs)
+
+		err = fetchRefSpecUsingGit(repoLocal, remoteName, refSpecs)
+		assert.Nil(t, err)
+	})
+
+	t.Run("push and list refs", func(t *testing.T) {
+		commitID, err := Commit(repoLocal, EmptyTree(), refName, "Test commit", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = pushRefSpecUsingGit(repoLocal, remoteName, refSpecs)
+		assert.Nil(t, err)
+
+		ref, err := repoRemote.Reference(plumbing.ReferenceName(refName), true)
+		assert.Nil(t, err)
+		assert.Equal(t, commitID, ref.Hash())
+
+		refs, err := listRemoteReferencesUsingGit(repoLocal, remoteName)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []*plumbing.Reference{
+			plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.ReferenceName(refName)),
+			plumbing.NewHashReference(plumbing.ReferenceName(refName), commitID),
+		}, refs)
+	})
+
+	t.Run("fetch", func(t *testing.T) {
+		remoteCommitID, err := Commit(repoRemote, EmptyTree(), refName, "Remote commit", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = fetchRefSpecUsingGit(repoLocal, remoteName, refSpecs)
+		assert.Nil(t, err)
+
+		ref, err := repoLocal.Reference(plumbing.ReferenceName(refName), true)
+		assert.Nil(t, err)
+		assert.Equal(t, remoteCommitID, ref.Hash())
+
+		err = fetchRefSpecUsingGit(repoLocal, remoteName, []config.RefSpec{"refs/heads/feature:refs/heads/feature"})
+		assert.ErrorIs(t, err, ErrRemoteRefNotFound)
+	})
+
+	t.Run("diverged refs", func(t *testing.T) {
+		if _, err := Commit(repoRemote, EmptyTree(), refName, "Remote commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Commit(repoLocal, EmptyTree(), refName, "Local commit", false); err != nil {
+			t.Fatal(err)
+		}
+
+		err := pushRefSpecUsingGit(repoLocal, remoteName, refSpecs)
+		assert.ErrorIs(t, err, ErrNotFastForward)
+
+		err = fetchRefSpecUsingGit(repoLocal, remoteName, refSpecs)
+		assert.ErrorIs(t, err, ErrNotFastForward)
+	})
+
+	t.Run("unknown remote", func(t *testing.T) {
+		_, err := listRemoteReferencesUsingGit(repoLocal, "unknown")
+		assert.ErrorIs(t, err, git.ErrRemoteNotFound)
+	})
+
+	t.Run("in-memory repository", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.CreateRemote(&config.RemoteConfig{Name: remoteName, URLs: []string{remoteTmpDir}}); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = listRemoteReferencesUsingGit(repo, remoteName)
+		assert.ErrorIs(t, err, ErrInMemoryRepository)
+	})
+}
+
+func TestCloneUsingGit(t *testing.T) {
+	refName := "refs/heads/main"
+
+	remoteTmpDir := t.TempDir()
+	repoRemote, err := PlainInit(remoteTmpDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repoRemote.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.ReferenceName(refName))); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("empty remote", func(t *testing.T) {
+		_, err := cloneUsingGit(remoteTmpDir, filepath.Join(t.TempDir(), "repo"), "", true)
+		assert.ErrorIs(t, err, transport.ErrEmptyRemoteRepository)
+	})
+
+	treeID, err := WriteTree(repoRemote, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitID, err := Commit(repoRemote, treeID, refName, "Test commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("clone without checkout", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "repo")
+		repo, err := cloneUsingGit(remoteTmpDir, dir, "", false)
+		assert.Nil(t, err)
+
+		head, err := repo.Head()
+		assert.Nil(t, err)
+		assert.Equal(t, plumbing.ReferenceName(refName), head.Name())
+		assert.Equal(t, commitID, head.Hash())
+
+		// Nothing is checked out, so the index doesn't exist
+		_, err = os.Stat(filepath.Join(dir, ".git", "index"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("clone branch", func(t *testing.T) {
+		repo, err := cloneUsingGit(remoteTmpDir, filepath.Join(t.TempDir(), "repo"), refName, true)
+		assert.Nil(t, err)
+
+		head, err := repo.Head()
+		assert.Nil(t, err)
+		assert.Equal(t, commitID, head.Hash())
+	})
+
+	t.Run("clone branch using short name", func(t *testing.T) {
+		repo, err := cloneUsingGit(remoteTmpDir, filepath.Join(t.TempDir(), "repo"), "main", true)
+		assert.Nil(t, err)
+
+		head, err := repo.Head()
+		assert.Nil(t, err)
+		assert.Equal(t, plumbing.ReferenceName(refName), head.Name())
+		assert.Equal(t, commitID, head.Hash())
+	})
+
+	t.Run("branch does not exist", func(t *testing.T) {
+		_, err := cloneUsingGit(remoteTmpDir, filepath.Join(t.TempDir(), "repo"), "refs/heads/feature", true)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+}
@@ -7,12 +7,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"path"
 	"sort"
 	"strings"
 
 	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/perf"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/filemode"
@@ -38,6 +40,8 @@ func WriteTree(repo *git.Repository, entries []object.TreeEntry) (plumbing.Hash,
 
 // GetTree returns the requested tree object.
 func GetTree(repo *git.Repository, treeID plumbing.Hash) (*object.Tree, error) {
+	defer perf.Track(perf.ObjectReads)()
+
 	return repo.TreeObject(treeID)
 }
 
@@ -153,6 +157,7 @@ func GetMergeTree(repo *git.Repository, commitAID, commitBID string) (string, er
 
 	// go-git does not support three way merges
 	command := exec.Command("git", "merge-tree", commitAID, commitBID) //nolint:gosec
+	command.Env = append(os.Environ(), noReplaceObjectsEnv)
 	stdOut, err := command.Output()
 	if err != nil {
 		return "", err
@@ -55,16 +55,16 @@ func TestWriteTree(t *testing.T) {
 		t.Error(err)
 	}
 
-	assert.Equal(t, "e8df153fd5749966e7ddf148fcbee17d747753ae", treeHash.String())
+	assert.Equal(t, hashForBuildObjectFormat("e8df153fd5749966e7ddf148fcbee17d747753ae", "d544b903c4590d99fe607f3dee7b9dfc7678c179b30f33d439d3420678197804"), treeHash.String())
 	assert.Equal(t, entries, tree.Entries)
 }
 
 func TestEmptyTree(t *testing.T) {
 	hash := EmptyTree()
 
-	// SHA-1 ID used by Git to denote an empty tree
+	// ID used by Git to denote an empty tree
 	// $ git hash-object -t tree --stdin < /dev/null
-	assert.Equal(t, "4b825dc642cb6eb9a060e54bf8d69288fbee4904", hash.String())
+	assert.Equal(t, hashForBuildObjectFormat("4b825dc642cb6eb9a060e54bf8d69288fbee4904", "6ef19b41225c5369f1c104d45d8d85efa9b057b53b14b4b9b939dd74decc5321"), hash.String())
 }
 
 func TestRepositoryEmptyTree(t *testing.T) {
@@ -74,9 +74,9 @@ func TestRepositoryEmptyTree(t *testing.T) {
 	hash, err := repo.EmptyTree()
 	assert.Nil(t, err)
 
-	// SHA-1 ID used by Git to denote an empty tree
+	// ID used by Git to denote an empty tree
 	// $ git hash-object -t tree --stdin < /dev/null
-	assert.Equal(t, "4b825dc642cb6eb9a060e54bf8d69288fbee4904", hash.String())
+	assert.Equal(t, hashForBuildObjectFormat("4b825dc642cb6eb9a060e54bf8d69288fbee4904", "6ef19b41225c5369f1c104d45d8d85efa9b057b53b14b4b9b939dd74decc5321"), hash.String())
 }
 
 func TestGetAllFilesInTree(t *testing.T) {
@@ -320,7 +320,7 @@ func TestReplacementTreeBuilder(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	emptyTreeID := "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+	emptyTreeID := hashForBuildObjectFormat("4b825dc642cb6eb9a060e54bf8d69288fbee4904", "6ef19b41225c5369f1c104d45d8d85efa9b057b53b14b4b9b939dd74decc5321")
 
 	t.Run("no blobs", func(t *testing.T) {
 		treeBuilder := NewReplacementTreeBuilder(repo)
@@ -30,31 +30,26 @@ func GetTip(repo *git.Repository, refName string) (plumbing.Hash, error) {
 
 // ResetCommit sets a Git reference with the name refName to the commit
 // specified by its hash as commitID. Note that the commit must already be in
-// the repository's object store.
+// the repository's object store. The ref is updated directly in the object
+// store, so refs such as gittuf's are never checked out. The worktree is only
+// reset if refName is the branch currently checked out.
 func ResetCommit(repo *git.Repository, refName string, commitID plumbing.Hash) error {
-	currentHEAD, err := repo.Head()
+	head, err := repo.Reference(plumbing.HEAD, false)
 	if err != nil {
 		return err
 	}
 
-	wt, err := repo.Worktree()
-	if err != nil {
-		return err
-	}
-
-	if err := wt.Checkout(&git.CheckoutOptions{Branch: plumbing.ReferenceName(refName)}); err != nil {
-		return err
-	}
-
-	if err := wt.Reset(&git.ResetOptions{Commit: commitID, Mode: git.MergeReset}); err != nil {
-		return err
-	}
-
-	if currentHEAD.Type() == plumbing.HashReference {
-		return wt.Checkout(&git.CheckoutOptions{Hash: currentHEAD.Hash()})
+	if head.Type() == plumbing.SymbolicReference && head.Target() == plumbing.ReferenceName(refName) {
+		wt, err := repo.Worktree()
+		if err == nil {
+			return wt.Reset(&git.ResetOptions{Commit: commitID, Mode: git.MergeReset})
+		}
+		if !errors.Is(err, git.ErrIsBareRepository) {
+			return err
+		}
 	}
 
-	return wt.Checkout(&git.CheckoutOptions{Branch: currentHEAD.Name()})
+	return repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), commitID))
 }
 
 // ResetDueToError is a helper used to reverse a change applied to a ref due to
@@ -159,3 +159,97 @@ func TestRefSpec(t *testing.T) {
 		assert.Equal(t, test.expectedRefSpec, refSpec, fmt.Sprintf("unexpected refspec returned in test '%s'", name))
 	}
 }
+
+func TestResetCommit(t *testing.T) {
+	createCommits := func(t *testing.T, repo *git.Repository, refName string) (plumbing.Hash, plumbing.Hash) {
+		t.Helper()
+
+		emptyTreeHash, err := WriteTree(repo, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		firstCommitID, err := Commit(repo, emptyTreeHash, refName, "First commit", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		secondCommitID, err := Commit(repo, emptyTreeHash, refName, "Second commit", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return firstCommitID, secondCommitID
+	}
+
+	t.Run("ref that is not checked out", func(t *testing.T) {
+		fs := memfs.New()
+		repo, err := git.Init(memory.NewStorage(), fs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		refName := "refs/gittuf/test"
+		firstCommitID, _ := createCommits(t, repo, refName)
+
+		// The worktree must not be touched
+		file, err := fs.Create("file")
+		if err != nil {
+			t.Fatal(err)
+		}
+		file.Close() //nolint:errcheck
+
+		err = ResetCommit(repo, refName, firstCommitID)
+		assert.Nil(t, err)
+
+		ref, err := repo.Reference(plumbing.ReferenceName(refName), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, firstCommitID, ref.Hash())
+
+		head, err := repo.Reference(plumbing.HEAD, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, plumbing.Master, head.Target())
+
+		_, err = fs.Stat("file")
+		assert.Nil(t, err)
+	})
+
+	t.Run("checked out branch", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		firstCommitID, _ := createCommits(t, repo, plumbing.Master.String())
+
+		err = ResetCommit(repo, plumbing.Master.String(), firstCommitID)
+		assert.Nil(t, err)
+
+		head, err := repo.Head()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, plumbing.Master, head.Name())
+		assert.Equal(t, firstCommitID, head.Hash())
+	})
+
+	t.Run("bare repository", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		firstCommitID, _ := createCommits(t, repo, plumbing.Master.String())
+
+		err = ResetCommit(repo, plumbing.Master.String(), firstCommitID)
+		assert.Nil(t, err)
+
+		ref, err := repo.Reference(plumbing.Master, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, firstCommitID, ref.Hash())
+	})
+}
@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package hooks generates the Git hooks used to enforce gittuf. Client-side
+// pre-push hooks record pushed refs in the RSL and verify them before they are
+// pushed, and client-side post-checkout hooks verify checked out branches.
+// Server-side pre-receive hooks verify pushed refs against gittuf policy before
+// they are accepted, and server-side reference-transaction hooks verify every
+// ref update before it is committed.
+package hooks
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/danwakefield/fnmatch"
+)
+
+var ErrInvalidRefPattern = errors.New("invalid ref pattern (may only contain letters, digits, and the characters '/_.-*?')")
+
+// DefaultEnforcedRefs are the ref patterns enforced by hooks if none are
+// specified.
+var DefaultEnforcedRefs = []string{"refs/heads/*", "refs/tags/*"}
+
+// refPatternRegex restricts ref patterns to characters that are safe to embed
+// in the generated shell scripts.
+var refPatternRegex = regexp.MustCompile(`^[A-Za-z0-9/_.*?-]+$`)
+
+// Options configures the generated hooks.
+type Options struct {
+	// EnforcedRefs are the patterns of the refs gittuf is enforced for.
+	// Patterns use the same semantics as shell `case` patterns, where `*` also
+	// matches `/`. If empty, DefaultEnforcedRefs is used.
+	EnforcedRefs []string
+}
+
+// Validate checks that the options can be used to generate hooks.
+func (o *Options) Validate() error {
+	for _, pattern := range o.enforcedRefs() {
+		if !refPatternRegex.MatchString(pattern) {
+			return fmt.Errorf("%w: '%s'", ErrInvalidRefPattern, pattern)
+		}
+	}
+
+	return nil
+}
+
+// IsEnforced checks if gittuf is enforced for the ref.
+func (o *Options) IsEnforced(refName string) bool {
+	for _, pattern := range o.enforcedRefs() {
+		if fnmatch.Match(pattern, refName, 0) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ShouldVerify checks if updates to the ref must be verified by server-side
+// hooks. Updates to gittuf's refs are always verified as they're needed to
+// verify other refs.
+func (o *Options) ShouldVerify(refName string) bool {
+	return strings.HasPrefix(refName, "refs/gittuf/") || o.IsEnforced(refName)
+}
+
+func (o *Options) enforcedRefs() []string {
+	if len(o.EnforcedRefs) == 0 {
+		return DefaultEnforcedRefs
+	}
+
+	return o.EnforcedRefs
+}
+
+// GeneratePrePushScript returns a client-side pre-push hook. For each pushed
+// ref that gittuf is enforced for, the hook records the ref's new state in the
+// RSL, unless gittuf.rsl.autorecord is set to false, and verifies the ref. The RSL is synchronized with the remote before and
+// after.
+func GeneratePrePushScript(options *Options) ([]byte, error) {
+	return generateScript(prePushTemplate, options)
+}
+
+// GeneratePreReceiveScript returns a server-side pre-receive hook. The hook
+// passes the received ref updates to `gittuf verify-receive`, which rejects
+// the push if any update to a ref gittuf is enforced for fails verification.
+func GeneratePreReceiveScript(options *Options) ([]byte, error) {
+	return generateScript(preReceiveTemplate, options)
+}
+
+// GenerateReferenceTransactionScript returns a server-side
+// reference-transaction hook. The hook passes the ref updates of each
+// transaction to `gittuf hook reference-transaction`, which aborts the
+// transaction when it is prepared if any update to a ref gittuf is enforced
+// for fails verification.
+func GenerateReferenceTransactionScript(options *Options) ([]byte, error) {
+	return generateScript(referenceTransactionTemplate, options)
+}
+
+// GeneratePostCheckoutScript returns a client-side post-checkout hook. When a
+// branch gittuf is enforced for is checked out, the hook verifies that the
+// branch's state is covered by verified RSL entries. As Git has already updated
+// the working tree, the hook switches back to the previously checked out
+// branch or commit if verification fails.
+func GeneratePostCheckoutScript(options *Options) ([]byte, error) {
+	return generateScript(postCheckoutTemplate, options)
+}
+
+func generateScript(scriptTemplate *template.Template, options *Options) ([]byte, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	var script bytes.Buffer
+	if err := scriptTemplate.Execute(&script, options.enforcedRefs()); err != nil {
+		return nil, err
+	}
+
+	return script.Bytes(), nil
+}
+
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+const checkGittufInstalled = `if ! command -v gittuf > /dev/null
+then
+    echo "gittuf could not be found"
+    echo "Download from: https://github.com/gittuf/gittuf/releases/latest"
+    exit 1
+fi`
+
+var prePushTemplate = template.Must(template.New("pre-push").Funcs(templateFuncs).Parse(`#!/bin/sh
+set -e
+
+remote="$1"
+url="$2"
+
+` + checkGittufInstalled + `
+
+autorecord="$(git config --type=bool --default=true gittuf.rsl.autorecord)"
+
+echo "Pulling RSL from ${remote}."
+gittuf rsl remote pull "${remote}" < /dev/null
+
+while read -r local_ref local_oid remote_ref remote_oid
+do
+    case "${local_ref}" in
+    {{ join . "|" }})
+        ;;
+    *)
+        continue
+        ;;
+    esac
+
+    case "${local_oid}" in
+    *[!0]*)
+        ;;
+    *)
+        # The ref is being deleted
+        continue
+        ;;
+    esac
+
+    if [ "${autorecord}" = "true" ]
+    then
+        echo "Creating new RSL record for ${local_ref}."
+        gittuf rsl record "${local_ref}" < /dev/null
+    fi
+    echo "Verifying ${local_ref}."
+    gittuf verify-ref --latest-only "${local_ref}" < /dev/null
+done
+
+echo "Pushing RSL to ${remote}."
+gittuf rsl remote push "${remote}" < /dev/null
+`))
+
+var preReceiveTemplate = template.Must(template.New("pre-receive").Funcs(templateFuncs).Parse(`#!/bin/sh
+set -e
+
+` + checkGittufInstalled + `
+
+exec gittuf verify-receive{{ range . }} --enforce-ref '{{ . }}'{{ end }}
+`))
+
+var referenceTransactionTemplate = template.Must(template.New("reference-transaction").Funcs(templateFuncs).Parse(`#!/bin/sh
+set -e
+
+` + checkGittufInstalled + `
+
+exec gittuf hook reference-transaction "$1"{{ range . }} --enforce-ref '{{ . }}'{{ end }}
+`))
+
+var postCheckoutTemplate = template.Must(template.New("post-checkout").Funcs(templateFuncs).Parse(`#!/bin/sh
+
+# Only verify branch checkouts, and skip the checkout made by this hook to
+# restore the previous state
+if [ "$3" != "1" ] || [ -n "${GITTUF_RESTORING_CHECKOUT}" ]
+then
+    exit 0
+fi
+
+` + checkGittufInstalled + `
+
+ref="$(git symbolic-ref --quiet HEAD)" || exit 0
+
+case "${ref}" in
+{{ join . "|" }})
+    ;;
+*)
+    exit 0
+    ;;
+esac
+
+if ! gittuf checkout --verify-only "${ref}" < /dev/null
+then
+    echo "${ref} is not covered by verified RSL entries, restoring previous checkout."
+    echo "Pull the RSL using 'gittuf rsl remote pull' and check out ${ref} again."
+    GITTUF_RESTORING_CHECKOUT=1 git checkout --quiet - || GITTUF_RESTORING_CHECKOUT=1 git checkout --quiet "$1"
+    exit 1
+fi
+`))
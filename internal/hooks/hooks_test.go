@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package hooks
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	tests := map[string]struct {
+		enforcedRefs []string
+		expectedErr  error
+	}{
+		"default refs": {},
+		"valid patterns": {
+			enforcedRefs: []string{"refs/heads/main", "refs/heads/release-*", "refs/tags/v?.*"},
+		},
+		"pattern with quote": {
+			enforcedRefs: []string{"refs/heads/'main"},
+			expectedErr:  ErrInvalidRefPattern,
+		},
+		"pattern with space": {
+			enforcedRefs: []string{"refs/heads/main refs/heads/dev"},
+			expectedErr:  ErrInvalidRefPattern,
+		},
+		"pattern with alternation": {
+			enforcedRefs: []string{"refs/heads/main|refs/heads/dev"},
+			expectedErr:  ErrInvalidRefPattern,
+		},
+	}
+
+	for name, test := range tests {
+		options := &Options{EnforcedRefs: test.enforcedRefs}
+		err := options.Validate()
+		if test.expectedErr == nil {
+			assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		} else {
+			assert.ErrorIs(t, err, test.expectedErr, fmt.Sprintf("unexpected error in test '%s'", name))
+		}
+	}
+}
+
+func TestOptionsIsEnforced(t *testing.T) {
+	tests := map[string]struct {
+		enforcedRefs []string
+		refName      string
+		expected     bool
+	}{
+		"default refs, branch": {
+			refName:  "refs/heads/main",
+			expected: true,
+		},
+		"default refs, nested branch": {
+			refName:  "refs/heads/feature/x",
+			expected: true,
+		},
+		"default refs, tag": {
+			refName:  "refs/tags/v1",
+			expected: true,
+		},
+		"default refs, other ref": {
+			refName:  "refs/notes/commits",
+			expected: false,
+		},
+		"custom refs, match": {
+			enforcedRefs: []string{"refs/heads/main"},
+			refName:      "refs/heads/main",
+			expected:     true,
+		},
+		"custom refs, no match": {
+			enforcedRefs: []string{"refs/heads/main"},
+			refName:      "refs/heads/feature",
+			expected:     false,
+		},
+	}
+
+	for name, test := range tests {
+		options := &Options{EnforcedRefs: test.enforcedRefs}
+		assert.Equal(t, test.expected, options.IsEnforced(test.refName), fmt.Sprintf("unexpected result in test '%s'", name))
+	}
+}
+
+func TestOptionsShouldVerify(t *testing.T) {
+	options := &Options{EnforcedRefs: []string{"refs/heads/main"}}
+
+	assert.True(t, options.ShouldVerify("refs/heads/main"))
+	assert.True(t, options.ShouldVerify("refs/gittuf/reference-state-log"))
+	assert.True(t, options.ShouldVerify("refs/gittuf/policy"))
+	assert.False(t, options.ShouldVerify("refs/heads/feature"))
+}
+
+func TestGeneratePrePushScript(t *testing.T) {
+	t.Run("default refs", func(t *testing.T) {
+		script, err := GeneratePrePushScript(&Options{})
+		assert.Nil(t, err)
+		assert.Contains(t, string(script), "refs/heads/*|refs/tags/*)")
+		assert.Contains(t, string(script), `gittuf rsl record "${local_ref}"`)
+		assert.Contains(t, string(script), `gittuf verify-ref --latest-only "${local_ref}"`)
+		assert.Contains(t, string(script), `gittuf rsl remote push "${remote}"`)
+		assert.Contains(t, string(script), "git config --type=bool --default=true gittuf.rsl.autorecord")
+	})
+
+	t.Run("custom refs", func(t *testing.T) {
+		script, err := GeneratePrePushScript(&Options{EnforcedRefs: []string{"refs/heads/main"}})
+		assert.Nil(t, err)
+		assert.Contains(t, string(script), "refs/heads/main)")
+		assert.NotContains(t, string(script), "refs/tags/*")
+	})
+
+	t.Run("invalid refs", func(t *testing.T) {
+		_, err := GeneratePrePushScript(&Options{EnforcedRefs: []string{"refs/heads/$(id)"}})
+		assert.ErrorIs(t, err, ErrInvalidRefPattern)
+	})
+}
+
+func TestGeneratePreReceiveScript(t *testing.T) {
+	t.Run("default refs", func(t *testing.T) {
+		script, err := GeneratePreReceiveScript(&Options{})
+		assert.Nil(t, err)
+		assert.Contains(t, string(script), "exec gittuf verify-receive --enforce-ref 'refs/heads/*' --enforce-ref 'refs/tags/*'\n")
+	})
+
+	t.Run("invalid refs", func(t *testing.T) {
+		_, err := GeneratePreReceiveScript(&Options{EnforcedRefs: []string{"refs/heads/'main'"}})
+		assert.ErrorIs(t, err, ErrInvalidRefPattern)
+	})
+}
+
+func TestGenerateReferenceTransactionScript(t *testing.T) {
+	t.Run("default refs", func(t *testing.T) {
+		script, err := GenerateReferenceTransactionScript(&Options{})
+		assert.Nil(t, err)
+		assert.Contains(t, string(script), `exec gittuf hook reference-transaction "$1" --enforce-ref 'refs/heads/*' --enforce-ref 'refs/tags/*'`+"\n")
+	})
+
+	t.Run("invalid refs", func(t *testing.T) {
+		_, err := GenerateReferenceTransactionScript(&Options{EnforcedRefs: []string{"refs/heads/'main'"}})
+		assert.ErrorIs(t, err, ErrInvalidRefPattern)
+	})
+}
+
+func TestGeneratePostCheckoutScript(t *testing.T) {
+	t.Run("default refs", func(t *testing.T) {
+		script, err := GeneratePostCheckoutScript(&Options{})
+		assert.Nil(t, err)
+		assert.Contains(t, string(script), "refs/heads/*|refs/tags/*)")
+		assert.Contains(t, string(script), `gittuf checkout --verify-only "${ref}"`)
+		assert.Contains(t, string(script), "GITTUF_RESTORING_CHECKOUT=1 git checkout --quiet -")
+	})
+
+	t.Run("invalid refs", func(t *testing.T) {
+		_, err := GeneratePostCheckoutScript(&Options{EnforcedRefs: []string{"refs/heads/`id`"}})
+		assert.ErrorIs(t, err, ErrInvalidRefPattern)
+	})
+}
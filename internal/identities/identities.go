@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package identities records mappings between accounts on forges, such as
+// GitHub usernames, and the IDs of the keys trusted in the gittuf policy. Each
+// mapping is a DSSE envelope, so that it can be signed by the parties vouching
+// for it. Whether a mapping is trusted is decided by the policy.
+package identities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+const (
+	Ref = "refs/gittuf/identities"
+
+	ForgeGitHub = "github"
+	ForgeGitLab = "gitlab"
+	ForgeEmail  = "email"
+
+	defaultCommitMessage = "Update identities"
+)
+
+// Forges are the kinds of accounts that may be mapped to keys.
+var Forges = []string{ForgeEmail, ForgeGitHub, ForgeGitLab}
+
+var (
+	ErrUnknownForge     = errors.New("unknown forge")
+	ErrInvalidIdentity  = errors.New("invalid identity")
+	ErrIdentityNotFound = errors.New("identity not found")
+	ErrIdentityMismatch = errors.New("identity does not match the account it's recorded for")
+)
+
+// payloadCache memoizes decoded identities for the lifetime of the process, as
+// the same identities are read repeatedly during verification.
+var payloadCache = dsse.NewPayloadCache()
+
+// Identity maps an account on a forge to a key.
+type Identity struct {
+	// Forge is the kind of account, one of Forges.
+	Forge string `json:"forge"`
+
+	// Account is the username on the forge, or the email address. It's
+	// lowercase, as forge usernames and email addresses are matched
+	// case-insensitively.
+	Account string `json:"account"`
+
+	// KeyID is the ID of the account holder's key in the policy.
+	KeyID string `json:"keyID"`
+}
+
+// NewIdentity returns an identity mapping the account on the forge to the key.
+func NewIdentity(forge, account, keyID string) (*Identity, error) {
+	identity := &Identity{
+		Forge:   forge,
+		Account: strings.ToLower(account),
+		KeyID:   keyID,
+	}
+	if err := identity.validate(); err != nil {
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+func (i *Identity) validate() error {
+	if !slices.Contains(Forges, i.Forge) {
+		return fmt.Errorf("%w: '%s', must be one of %s", ErrUnknownForge, i.Forge, strings.Join(Forges, ", "))
+	}
+
+	if i.Account == "" || i.Account != strings.ToLower(i.Account) || strings.ContainsAny(i.Account, "/ ") {
+		return fmt.Errorf("%w: account '%s'", ErrInvalidIdentity, i.Account)
+	}
+
+	if i.KeyID == "" {
+		return fmt.Errorf("%w: key ID must be set", ErrInvalidIdentity)
+	}
+
+	return nil
+}
+
+// GetIdentity returns the identity recorded in the envelope.
+func GetIdentity(env *sslibdsse.Envelope) (*Identity, error) {
+	return dsse.DecodePayload(payloadCache, env, func(identity *Identity) error {
+		return identity.validate()
+	})
+}
+
+// Identities tracks the identities recorded in a gittuf repository.
+type Identities struct {
+	// identities maps each account to the blob ID of its identity envelope.
+	// The key is a path of the form `<forge>/<account>`.
+	identities map[string]plumbing.Hash
+}
+
+// LoadCurrentIdentities loads the identities recorded in the latest RSL entry
+// for the identities namespace.
+func LoadCurrentIdentities(repo *git.Repository) (*Identities, error) {
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(repo, Ref)
+	if err != nil {
+		if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return nil, err
+		}
+
+		return &Identities{}, nil
+	}
+
+	return LoadIdentitiesForEntry(repo, entry)
+}
+
+// LoadIdentitiesForEntry loads the identities recorded in a particular RSL
+// entry for the identities namespace.
+func LoadIdentitiesForEntry(repo *git.Repository, entry *rsl.ReferenceEntry) (*Identities, error) {
+	if entry.RefName != Ref {
+		return nil, rsl.ErrRSLEntryDoesNotMatchRef
+	}
+
+	commit, err := gitinterface.GetCommit(repo, entry.TargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := gitinterface.GetTree(repo, commit.TreeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := gitinterface.GetAllFilesInTree(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Identities{identities: identities}, nil
+}
+
+// SetIdentity records the identity envelope, replacing any existing identity
+// for the same account.
+func (i *Identities) SetIdentity(repo *git.Repository, env *sslibdsse.Envelope) error {
+	identity, err := GetIdentity(env)
+	if err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := gitinterface.WriteBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if i.identities == nil {
+		i.identities = map[string]plumbing.Hash{}
+	}
+
+	i.identities[IdentityPath(identity.Forge, identity.Account)] = blobID
+	return nil
+}
+
+// RemoveIdentity removes the identity for the account.
+func (i *Identities) RemoveIdentity(forge, account string) error {
+	identityPath := IdentityPath(forge, account)
+	if _, has := i.identities[identityPath]; !has {
+		return fmt.Errorf("%w: '%s'", ErrIdentityNotFound, identityPath)
+	}
+
+	delete(i.identities, identityPath)
+	return nil
+}
+
+// GetIdentityFor returns the identity envelope (with its signatures) recorded
+// for the account.
+func (i *Identities) GetIdentityFor(repo *git.Repository, forge, account string) (*sslibdsse.Envelope, error) {
+	identityPath := IdentityPath(forge, account)
+	blobID, has := i.identities[identityPath]
+	if !has {
+		return nil, fmt.Errorf("%w: '%s'", ErrIdentityNotFound, identityPath)
+	}
+
+	env, err := readEnvelope(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := GetIdentity(env)
+	if err != nil {
+		return nil, err
+	}
+	if IdentityPath(identity.Forge, identity.Account) != identityPath {
+		return nil, fmt.Errorf("%w: '%s' records '%s'", ErrIdentityMismatch, identityPath, IdentityPath(identity.Forge, identity.Account))
+	}
+
+	return env, nil
+}
+
+// GetAllIdentities returns every identity envelope (with its signatures),
+// keyed by the path of the form `<forge>/<account>` it's recorded at.
+func (i *Identities) GetAllIdentities(repo *git.Repository) (map[string]*sslibdsse.Envelope, error) {
+	allIdentities := make(map[string]*sslibdsse.Envelope, len(i.identities))
+	for identityPath, blobID := range i.identities {
+		env, err := readEnvelope(repo, blobID)
+		if err != nil {
+			return nil, err
+		}
+		allIdentities[identityPath] = env
+	}
+
+	return allIdentities, nil
+}
+
+// Commit writes the state of the identities to the repository, creating a new
+// commit with the changes made. An RSL entry is also recorded for the
+// namespace.
+func (i *Identities) Commit(repo *git.Repository, commitMessage string, signCommit bool) error {
+	if len(commitMessage) == 0 {
+		commitMessage = defaultCommitMessage
+	}
+
+	treeID, err := gitinterface.NewTreeBuilder(repo).WriteRootTreeFromBlobIDs(i.identities)
+	if err != nil {
+		return err
+	}
+
+	priorCommitID := plumbing.ZeroHash
+	ref, err := repo.Reference(plumbing.ReferenceName(Ref), true)
+	if err == nil {
+		priorCommitID = ref.Hash()
+	} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return err
+	}
+
+	commitID, err := gitinterface.Commit(repo, treeID, Ref, commitMessage, signCommit)
+	if err != nil {
+		return err
+	}
+
+	// We must reset to the original identities commit if err != nil from here
+	// onwards.
+
+	if err := rsl.NewReferenceEntry(Ref, commitID).Commit(repo, signCommit); err != nil {
+		if priorCommitID.IsZero() {
+			if removeErr := repo.Storer.RemoveReference(plumbing.ReferenceName(Ref)); removeErr != nil {
+				return fmt.Errorf("unable to remove %s, caused by following error: %w", Ref, err)
+			}
+			return err
+		}
+		return gitinterface.ResetDueToError(err, repo, Ref, priorCommitID)
+	}
+
+	return nil
+}
+
+// IdentityPath returns the path the identity for the account is recorded at.
+func IdentityPath(forge, account string) string {
+	return path.Join(forge, strings.ToLower(account))
+}
+
+func readEnvelope(repo *git.Repository, blobID plumbing.Hash) (*sslibdsse.Envelope, error) {
+	envBytes, err := gitinterface.ReadBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
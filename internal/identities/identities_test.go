@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identities
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/storage/memory"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIdentity(t *testing.T) {
+	identity, err := NewIdentity(ForgeGitHub, "Alice", "keyID")
+	assert.Nil(t, err)
+	assert.Equal(t, &Identity{Forge: ForgeGitHub, Account: "alice", KeyID: "keyID"}, identity)
+
+	_, err = NewIdentity("bitbucket", "alice", "keyID")
+	assert.ErrorIs(t, err, ErrUnknownForge)
+
+	_, err = NewIdentity(ForgeGitHub, "", "keyID")
+	assert.ErrorIs(t, err, ErrInvalidIdentity)
+
+	_, err = NewIdentity(ForgeGitLab, "group/alice", "keyID")
+	assert.ErrorIs(t, err, ErrInvalidIdentity)
+
+	_, err = NewIdentity(ForgeEmail, "alice@example.com", "")
+	assert.ErrorIs(t, err, ErrInvalidIdentity)
+}
+
+func TestIdentities(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rsl.InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	allIdentities, err := LoadCurrentIdentities(repo)
+	assert.Nil(t, err)
+
+	_, err = allIdentities.GetIdentityFor(repo, ForgeGitHub, "alice")
+	assert.ErrorIs(t, err, ErrIdentityNotFound)
+
+	identity, err := NewIdentity(ForgeGitHub, "alice", "keyID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = allIdentities.SetIdentity(repo, env)
+	assert.Nil(t, err)
+
+	err = allIdentities.Commit(repo, "", false)
+	assert.Nil(t, err)
+
+	allIdentities, err = LoadCurrentIdentities(repo)
+	assert.Nil(t, err)
+
+	// Accounts are matched case-insensitively
+	recordedEnv, err := allIdentities.GetIdentityFor(repo, ForgeGitHub, "Alice")
+	assert.Nil(t, err)
+	assert.Equal(t, env, recordedEnv)
+
+	_, err = allIdentities.GetIdentityFor(repo, ForgeGitLab, "alice")
+	assert.ErrorIs(t, err, ErrIdentityNotFound)
+
+	envelopes, err := allIdentities.GetAllIdentities(repo)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]*sslibdsse.Envelope{"github/alice": env}, envelopes)
+
+	err = allIdentities.RemoveIdentity(ForgeGitHub, "alice")
+	assert.Nil(t, err)
+
+	err = allIdentities.RemoveIdentity(ForgeGitHub, "alice")
+	assert.ErrorIs(t, err, ErrIdentityNotFound)
+
+	err = allIdentities.Commit(repo, "Remove identity", false)
+	assert.Nil(t, err)
+
+	allIdentities, err = LoadCurrentIdentities(repo)
+	assert.Nil(t, err)
+
+	envelopes, err = allIdentities.GetAllIdentities(repo)
+	assert.Nil(t, err)
+	assert.Empty(t, envelopes)
+}
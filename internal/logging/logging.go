@@ -0,0 +1,289 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package logging configures the structured logger used by gittuf. Logs can be
+// written as text or JSON, to stderr or a file, and the log level can be set
+// for individual gittuf modules such as `policy` or `rsl`.
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+
+	// FormatConfigKey is the Git config key used to set the log format, one of
+	// FormatText (the default) and FormatJSON.
+	FormatConfigKey = "gittuf.log.format"
+
+	// LevelConfigKey is the Git config key used to set the default log level,
+	// one of debug, info (the default), warn, and error.
+	LevelConfigKey = "gittuf.log.level"
+
+	// ModuleLevelsConfigKey is the Git config key used to set the log level of
+	// individual modules. The value is a comma separated list of
+	// `<module>=<level>` pairs, such as `policy=debug,rsl=warn`.
+	ModuleLevelsConfigKey = "gittuf.log.modulelevels"
+
+	// FileConfigKey is the Git config key used to set the file logs are
+	// appended to instead of stderr.
+	FileConfigKey = "gittuf.log.file"
+
+	// ModuleKey is the attribute that records the module that emitted a log
+	// record.
+	ModuleKey = "module"
+
+	modulePrefix = "github.com/gittuf/gittuf/internal/"
+)
+
+var (
+	ErrInvalidLogFormat   = errors.New("invalid log format (not one of text, json)")
+	ErrInvalidModuleLevel = errors.New("invalid module log level, must be of form <module>=<level>")
+)
+
+// Options configures the logger.
+type Options struct {
+	// Format is one of FormatText and FormatJSON.
+	Format string
+
+	// Level is the minimum level of records logged for modules without a
+	// module specific level.
+	Level slog.Level
+
+	// ModuleLevels maps modules to the minimum level of records logged for
+	// them. A module is identified by its package path within gittuf, such as
+	// `policy` or `cmd/rsl/record`, and its level also applies to the packages
+	// it contains. The most specific module's level is used.
+	ModuleLevels map[string]slog.Level
+
+	// File is the file logs are appended to. If empty, logs are written to
+	// stderr.
+	File string
+}
+
+// DefaultOptions returns the options used if gittuf is not configured
+// otherwise.
+func DefaultOptions() *Options {
+	return &Options{
+		Format:       FormatText,
+		Level:        slog.LevelInfo,
+		ModuleLevels: map[string]slog.Level{},
+	}
+}
+
+// LoadOptionsFromConfig returns the logging options set in the Git config,
+// using defaults for options that are not set.
+func LoadOptionsFromConfig(config map[string]string) (*Options, error) {
+	options := DefaultOptions()
+
+	if format, has := config[FormatConfigKey]; has {
+		options.Format = format
+	}
+
+	if level, has := config[LevelConfigKey]; has {
+		if err := options.Level.UnmarshalText([]byte(level)); err != nil {
+			return nil, err
+		}
+	}
+
+	if moduleLevels, has := config[ModuleLevelsConfigKey]; has {
+		var err error
+		options.ModuleLevels, err = ParseModuleLevels(strings.Split(moduleLevels, ","))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if file, has := config[FileConfigKey]; has {
+		options.File = file
+	}
+
+	return options, options.Validate()
+}
+
+// Validate checks that the options can be used to configure the logger.
+func (o *Options) Validate() error {
+	switch o.Format {
+	case FormatText, FormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("%w: '%s'", ErrInvalidLogFormat, o.Format)
+	}
+}
+
+// ParseModuleLevels parses module levels of the form `<module>=<level>`.
+func ParseModuleLevels(values []string) (map[string]slog.Level, error) {
+	moduleLevels := map[string]slog.Level{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		module, levelName, found := strings.Cut(value, "=")
+		module = strings.Trim(strings.TrimSpace(module), "/")
+		if !found || module == "" {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidModuleLevel, value)
+		}
+
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(levelName))); err != nil {
+			return nil, fmt.Errorf("%w: '%s': %w", ErrInvalidModuleLevel, value, err)
+		}
+
+		moduleLevels[module] = level
+	}
+
+	return moduleLevels, nil
+}
+
+// Configure sets the default slog logger as specified in the options. If a log
+// file is specified, it is opened for appending and left open for the lifetime
+// of the process. Otherwise, logs are written to stderr.
+func Configure(options *Options, stderr io.Writer) error {
+	w := stderr
+	if options.File != "" {
+		file, err := os.OpenFile(options.File, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("unable to open log file: %w", err)
+		}
+		w = file
+	}
+
+	handler, err := NewHandler(w, options)
+	if err != nil {
+		return err
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// NewHandler returns a slog handler that writes records to w as specified in
+// the options. Records emitted by gittuf are annotated with their module and
+// filtered using the module's level.
+func NewHandler(w io.Writer, options *Options) (slog.Handler, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	// The wrapped handler must not filter out records that a module specific
+	// level allows
+	minLevel := options.Level
+	for _, level := range options.ModuleLevels {
+		minLevel = min(minLevel, level)
+	}
+	handlerOptions := &slog.HandlerOptions{Level: minLevel}
+
+	var handler slog.Handler
+	if options.Format == FormatJSON {
+		handler = slog.NewJSONHandler(w, handlerOptions)
+	} else {
+		handler = slog.NewTextHandler(w, handlerOptions)
+	}
+
+	return &moduleHandler{
+		handler:      handler,
+		level:        options.Level,
+		moduleLevels: options.ModuleLevels,
+		minLevel:     minLevel,
+	}, nil
+}
+
+// moduleHandler wraps a slog handler to apply module specific levels.
+type moduleHandler struct {
+	handler      slog.Handler
+	level        slog.Level
+	moduleLevels map[string]slog.Level
+	minLevel     slog.Level
+}
+
+func (h *moduleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.minLevel
+}
+
+func (h *moduleHandler) Handle(ctx context.Context, record slog.Record) error {
+	module := moduleForPC(record.PC)
+	if record.Level < h.levelFor(module) {
+		return nil
+	}
+
+	if module != "" {
+		record = record.Clone()
+		record.AddAttrs(slog.String(ModuleKey, module))
+	}
+
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *moduleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &moduleHandler{
+		handler:      h.handler.WithAttrs(attrs),
+		level:        h.level,
+		moduleLevels: h.moduleLevels,
+		minLevel:     h.minLevel,
+	}
+}
+
+func (h *moduleHandler) WithGroup(name string) slog.Handler {
+	return &moduleHandler{
+		handler:      h.handler.WithGroup(name),
+		level:        h.level,
+		moduleLevels: h.moduleLevels,
+		minLevel:     h.minLevel,
+	}
+}
+
+// levelFor returns the level of the most specific module that contains the
+// specified module, or the default level if there is none.
+func (h *moduleHandler) levelFor(module string) slog.Level {
+	for module != "" {
+		if level, has := h.moduleLevels[module]; has {
+			return level
+		}
+
+		index := strings.LastIndex(module, "/")
+		if index == -1 {
+			break
+		}
+		module = module[:index]
+	}
+
+	return h.level
+}
+
+// moduleForPC returns the gittuf module of the function at the program
+// counter, such as `policy` for
+// `github.com/gittuf/gittuf/internal/policy.(*State).Verify`. An empty string
+// is returned for functions outside gittuf.
+func moduleForPC(pc uintptr) string {
+	if pc == 0 {
+		return ""
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return moduleForFunction(frame.Function)
+}
+
+func moduleForFunction(function string) string {
+	if !strings.HasPrefix(function, modulePrefix) {
+		return ""
+	}
+	function = strings.TrimPrefix(function, modulePrefix)
+
+	// The package path ends at the first '.' after the last '/'
+	packageStart := strings.LastIndex(function, "/") + 1
+	if index := strings.Index(function[packageStart:], "."); index != -1 {
+		return function[:packageStart+index]
+	}
+
+	return function
+}
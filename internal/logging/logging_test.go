@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadOptionsFromConfig(t *testing.T) {
+	tests := map[string]struct {
+		config          map[string]string
+		expectedOptions *Options
+		expectedError   error
+	}{
+		"no config": {
+			config:          map[string]string{},
+			expectedOptions: DefaultOptions(),
+		},
+		"all options set": {
+			config: map[string]string{
+				FormatConfigKey:       FormatJSON,
+				LevelConfigKey:        "warn",
+				ModuleLevelsConfigKey: "policy=debug, cmd/rsl=error",
+				FileConfigKey:         "gittuf.log",
+			},
+			expectedOptions: &Options{
+				Format:       FormatJSON,
+				Level:        slog.LevelWarn,
+				ModuleLevels: map[string]slog.Level{"policy": slog.LevelDebug, "cmd/rsl": slog.LevelError},
+				File:         "gittuf.log",
+			},
+		},
+		"invalid format": {
+			config:        map[string]string{FormatConfigKey: "xml"},
+			expectedError: ErrInvalidLogFormat,
+		},
+		"invalid module level": {
+			config:        map[string]string{ModuleLevelsConfigKey: "policy"},
+			expectedError: ErrInvalidModuleLevel,
+		},
+	}
+
+	for name, test := range tests {
+		options, err := LoadOptionsFromConfig(test.config)
+		if test.expectedError != nil {
+			assert.ErrorIs(t, err, test.expectedError, fmt.Sprintf("unexpected error in test '%s'", name))
+		} else {
+			assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+			assert.Equal(t, test.expectedOptions, options, fmt.Sprintf("unexpected options in test '%s'", name))
+		}
+	}
+}
+
+func TestParseModuleLevels(t *testing.T) {
+	moduleLevels, err := ParseModuleLevels([]string{"policy=debug", " rsl = WARN ", "", "cmd/rsl/=error"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]slog.Level{"policy": slog.LevelDebug, "rsl": slog.LevelWarn, "cmd/rsl": slog.LevelError}, moduleLevels)
+
+	_, err = ParseModuleLevels([]string{"=debug"})
+	assert.ErrorIs(t, err, ErrInvalidModuleLevel)
+
+	_, err = ParseModuleLevels([]string{"policy=verbose"})
+	assert.ErrorIs(t, err, ErrInvalidModuleLevel)
+}
+
+func TestModuleForFunction(t *testing.T) {
+	tests := map[string]string{
+		"github.com/gittuf/gittuf/internal/policy.(*State).Verify":        "policy",
+		"github.com/gittuf/gittuf/internal/cmd/rsl/record.(*options).Run": "cmd/rsl/record",
+		"github.com/gittuf/gittuf/internal/logging.TestModuleForFunction": "logging",
+		"github.com/gittuf/gittuf/internal/rsl.GetLatestEntry.func1":      "rsl",
+		"github.com/go-git/go-git/v5.(*Repository).Reference":             "",
+		"main.main": "",
+	}
+
+	for function, expectedModule := range tests {
+		assert.Equal(t, expectedModule, moduleForFunction(function), fmt.Sprintf("unexpected module for '%s'", function))
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	t.Run("json format with module levels", func(t *testing.T) {
+		var buf bytes.Buffer
+		handler, err := NewHandler(&buf, &Options{
+			Format:       FormatJSON,
+			Level:        slog.LevelWarn,
+			ModuleLevels: map[string]slog.Level{"logging": slog.LevelDebug},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		logger := slog.New(handler)
+		logger.Debug("debug message")
+
+		record := map[string]any{}
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "debug message", record[slog.MessageKey])
+		assert.Equal(t, "DEBUG", record[slog.LevelKey])
+		assert.Equal(t, "logging", record[ModuleKey])
+	})
+
+	t.Run("module level more restrictive than default", func(t *testing.T) {
+		var buf bytes.Buffer
+		handler, err := NewHandler(&buf, &Options{
+			Format:       FormatText,
+			Level:        slog.LevelDebug,
+			ModuleLevels: map[string]slog.Level{"logging": slog.LevelError},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		logger := slog.New(handler)
+		logger.Info("info message")
+		assert.Empty(t, buf.String())
+
+		logger.Error("error message")
+		assert.Contains(t, buf.String(), `msg="error message"`)
+		assert.Contains(t, buf.String(), "module=logging")
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, err := NewHandler(&bytes.Buffer{}, &Options{Format: "xml"})
+		assert.ErrorIs(t, err, ErrInvalidLogFormat)
+	})
+}
+
+func TestConfigure(t *testing.T) {
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+
+	logFile := filepath.Join(t.TempDir(), "gittuf.log")
+	options := DefaultOptions()
+	options.File = logFile
+
+	var stderr bytes.Buffer
+	if err := Configure(options, &stderr); err != nil {
+		t.Fatal(err)
+	}
+
+	slog.Info("info message")
+	slog.Debug("debug message")
+
+	contents, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, strings.Count(string(contents), "\n"))
+	assert.Contains(t, string(contents), `msg="info message"`)
+	assert.Empty(t, stderr.String())
+}
@@ -0,0 +1,250 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package merkle implements the Merkle tree used by Certificate Transparency
+// logs, as specified in RFC 6962 and RFC 9162. It supports computing tree
+// heads, inclusion proofs, and consistency proofs for any prior size of the
+// tree, and verifying such proofs without access to the tree.
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"math/bits"
+)
+
+const (
+	leafHashPrefix = 0x00
+	nodeHashPrefix = 0x01
+)
+
+var (
+	ErrInvalidTreeSize  = errors.New("invalid Merkle tree size")
+	ErrInvalidLeafIndex = errors.New("leaf index is not in the Merkle tree")
+	ErrInvalidProof     = errors.New("Merkle tree proof is invalid")
+)
+
+// Tree is an append-only Merkle tree. Only the hashes of the leaves are
+// stored, the hashes of interior nodes are computed as necessary.
+type Tree struct {
+	leafHashes [][]byte
+}
+
+// NewTree returns an empty Merkle tree.
+func NewTree() *Tree {
+	return &Tree{leafHashes: [][]byte{}}
+}
+
+// Append adds a leaf with the specified data to the tree.
+func (t *Tree) Append(data []byte) {
+	t.leafHashes = append(t.leafHashes, LeafHash(data))
+}
+
+// Size returns the number of leaves in the tree.
+func (t *Tree) Size() uint64 {
+	return uint64(len(t.leafHashes))
+}
+
+// LeafHash returns the hash of the leaf at the specified index.
+func (t *Tree) LeafHash(index uint64) ([]byte, error) {
+	if index >= t.Size() {
+		return nil, ErrInvalidLeafIndex
+	}
+
+	return t.leafHashes[index], nil
+}
+
+// RootHash returns the root hash of the tree when it had the specified number
+// of leaves.
+func (t *Tree) RootHash(size uint64) ([]byte, error) {
+	if size > t.Size() {
+		return nil, ErrInvalidTreeSize
+	}
+
+	return subtreeHash(t.leafHashes[:size]), nil
+}
+
+// InclusionProof returns the audit path for the leaf at the specified index in
+// the tree when it had the specified number of leaves.
+func (t *Tree) InclusionProof(index, size uint64) ([][]byte, error) {
+	if size > t.Size() {
+		return nil, ErrInvalidTreeSize
+	}
+	if index >= size {
+		return nil, ErrInvalidLeafIndex
+	}
+
+	return inclusionPath(index, t.leafHashes[:size]), nil
+}
+
+// ConsistencyProof returns the proof that the tree with oldSize leaves is a
+// prefix of the tree with size leaves.
+func (t *Tree) ConsistencyProof(oldSize, size uint64) ([][]byte, error) {
+	if size > t.Size() || oldSize > size || oldSize == 0 {
+		return nil, ErrInvalidTreeSize
+	}
+	if oldSize == size {
+		return [][]byte{}, nil
+	}
+
+	return consistencySubproof(oldSize, t.leafHashes[:size], true), nil
+}
+
+// LeafHash returns the hash of a leaf with the specified data.
+func LeafHash(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{leafHashPrefix})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// VerifyInclusion checks that the proof shows the leaf with the specified hash
+// is at the specified index in the tree with the specified size and root hash.
+func VerifyInclusion(leafHash []byte, index, size uint64, proof [][]byte, rootHash []byte) error {
+	if index >= size {
+		return ErrInvalidLeafIndex
+	}
+
+	fn, sn := index, size-1
+	r := leafHash
+	for _, p := range proof {
+		if sn == 0 {
+			return ErrInvalidProof
+		}
+
+		if fn&1 == 1 || fn == sn {
+			r = nodeHash(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = nodeHash(r, p)
+		}
+
+		fn >>= 1
+		sn >>= 1
+	}
+
+	if sn != 0 || !bytes.Equal(r, rootHash) {
+		return ErrInvalidProof
+	}
+
+	return nil
+}
+
+// VerifyConsistency checks that the proof shows the tree with oldSize leaves
+// and oldRootHash is a prefix of the tree with size leaves and rootHash.
+func VerifyConsistency(oldSize, size uint64, oldRootHash, rootHash []byte, proof [][]byte) error {
+	if oldSize == 0 || oldSize > size {
+		return ErrInvalidTreeSize
+	}
+
+	if oldSize == size {
+		if len(proof) != 0 || !bytes.Equal(oldRootHash, rootHash) {
+			return ErrInvalidProof
+		}
+		return nil
+	}
+
+	if len(proof) == 0 {
+		return ErrInvalidProof
+	}
+
+	// If the old tree is a complete subtree, its root is the starting point
+	// and is omitted from the proof
+	if bits.OnesCount64(oldSize) == 1 {
+		proof = append([][]byte{oldRootHash}, proof...)
+	}
+
+	fn, sn := oldSize-1, size-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+
+	fr, sr := proof[0], proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return ErrInvalidProof
+		}
+
+		if fn&1 == 1 || fn == sn {
+			fr = nodeHash(c, fr)
+			sr = nodeHash(c, sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = nodeHash(sr, c)
+		}
+
+		fn >>= 1
+		sn >>= 1
+	}
+
+	if sn != 0 || !bytes.Equal(fr, oldRootHash) || !bytes.Equal(sr, rootHash) {
+		return ErrInvalidProof
+	}
+
+	return nil
+}
+
+// subtreeHash computes MTH(D[n]) as defined in RFC 6962.
+func subtreeHash(leafHashes [][]byte) []byte {
+	switch len(leafHashes) {
+	case 0:
+		h := sha256.Sum256(nil)
+		return h[:]
+	case 1:
+		return leafHashes[0]
+	}
+
+	k := splitPoint(uint64(len(leafHashes)))
+	return nodeHash(subtreeHash(leafHashes[:k]), subtreeHash(leafHashes[k:]))
+}
+
+// inclusionPath computes PATH(m, D[n]) as defined in RFC 6962.
+func inclusionPath(m uint64, leafHashes [][]byte) [][]byte {
+	n := uint64(len(leafHashes))
+	if n <= 1 {
+		return [][]byte{}
+	}
+
+	k := splitPoint(n)
+	if m < k {
+		return append(inclusionPath(m, leafHashes[:k]), subtreeHash(leafHashes[k:]))
+	}
+	return append(inclusionPath(m-k, leafHashes[k:]), subtreeHash(leafHashes[:k]))
+}
+
+// consistencySubproof computes SUBPROOF(m, D[n], b) as defined in RFC 6962.
+func consistencySubproof(m uint64, leafHashes [][]byte, b bool) [][]byte {
+	n := uint64(len(leafHashes))
+	if m == n {
+		if b {
+			return [][]byte{}
+		}
+		return [][]byte{subtreeHash(leafHashes)}
+	}
+
+	k := splitPoint(n)
+	if m <= k {
+		return append(consistencySubproof(m, leafHashes[:k], b), subtreeHash(leafHashes[k:]))
+	}
+	return append(consistencySubproof(m-k, leafHashes[k:], false), subtreeHash(leafHashes[:k]))
+}
+
+// splitPoint returns the largest power of two smaller than n, for n > 1.
+func splitPoint(n uint64) uint64 {
+	return 1 << (bits.Len64(n-1) - 1)
+}
+
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{nodeHashPrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
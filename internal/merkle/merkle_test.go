@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package merkle
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test vectors from the Certificate Transparency reference implementation
+var (
+	testLeaves = []string{
+		"",
+		"00",
+		"10",
+		"2021",
+		"3031",
+		"40414243",
+		"5051525354555657",
+		"606162636465666768696a6b6c6d6e6f",
+	}
+
+	testRootHashes = []string{
+		"6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+		"fac54203e7cc696cf0dfcb42c92a1d9dbaf70ad9e621f4bd8d98662f00e3c125",
+		"aeb6bcfe274b70a14fb067a5e5578264db0fa9b51af5e0ba159158f329e06e77",
+		"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7",
+		"4e3bbb1f7b478dcfe71fb631631519a3bca12c9aefca1612bfce4c13a86264d4",
+		"76e67dadbcdf1e10e1b74ddc608abd2f98dfb16fbce75277b5232a127f2087ef",
+		"ddb89be403809e325750d3d263cd78929c2942b7942a34b77e122c9594a74c8c",
+		"5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328",
+	}
+)
+
+func TestRootHash(t *testing.T) {
+	tree := NewTree()
+
+	rootHash, err := tree.RootHash(0)
+	assert.Nil(t, err)
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", hex.EncodeToString(rootHash))
+
+	for i, leaf := range testLeaves {
+		data, err := hex.DecodeString(leaf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tree.Append(data)
+
+		rootHash, err := tree.RootHash(tree.Size())
+		assert.Nil(t, err)
+		assert.Equal(t, testRootHashes[i], hex.EncodeToString(rootHash))
+	}
+
+	// Prior sizes of the tree can still be queried
+	rootHash, err = tree.RootHash(3)
+	assert.Nil(t, err)
+	assert.Equal(t, testRootHashes[2], hex.EncodeToString(rootHash))
+
+	_, err = tree.RootHash(tree.Size() + 1)
+	assert.ErrorIs(t, err, ErrInvalidTreeSize)
+}
+
+func TestInclusionProof(t *testing.T) {
+	tree := createTestTree(t, 20)
+
+	for size := uint64(1); size <= tree.Size(); size++ {
+		rootHash, err := tree.RootHash(size)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for index := uint64(0); index < size; index++ {
+			t.Run(fmt.Sprintf("leaf %d in tree of size %d", index, size), func(t *testing.T) {
+				proof, err := tree.InclusionProof(index, size)
+				assert.Nil(t, err)
+
+				leafHash, err := tree.LeafHash(index)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Nil(t, VerifyInclusion(leafHash, index, size, proof, rootHash))
+
+				// The proof must not verify for any other leaf
+				otherLeafHash := LeafHash([]byte("not in tree"))
+				assert.ErrorIs(t, VerifyInclusion(otherLeafHash, index, size, proof, rootHash), ErrInvalidProof)
+
+				// The proof must not verify for another position
+				if size > 1 {
+					assert.NotNil(t, VerifyInclusion(leafHash, (index+1)%size, size, proof, rootHash))
+				}
+			})
+		}
+	}
+
+	_, err := tree.InclusionProof(5, 5)
+	assert.ErrorIs(t, err, ErrInvalidLeafIndex)
+
+	_, err = tree.InclusionProof(0, tree.Size()+1)
+	assert.ErrorIs(t, err, ErrInvalidTreeSize)
+}
+
+func TestConsistencyProof(t *testing.T) {
+	tree := createTestTree(t, 20)
+
+	for size := uint64(1); size <= tree.Size(); size++ {
+		rootHash, err := tree.RootHash(size)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for oldSize := uint64(1); oldSize <= size; oldSize++ {
+			t.Run(fmt.Sprintf("tree of size %d to %d", oldSize, size), func(t *testing.T) {
+				oldRootHash, err := tree.RootHash(oldSize)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				proof, err := tree.ConsistencyProof(oldSize, size)
+				assert.Nil(t, err)
+
+				assert.Nil(t, VerifyConsistency(oldSize, size, oldRootHash, rootHash, proof))
+
+				// The proof must not verify for a different old tree
+				otherRootHash := LeafHash([]byte("not a root"))
+				assert.ErrorIs(t, VerifyConsistency(oldSize, size, otherRootHash, rootHash, proof), ErrInvalidProof)
+				assert.ErrorIs(t, VerifyConsistency(oldSize, size, oldRootHash, otherRootHash, proof), ErrInvalidProof)
+			})
+		}
+	}
+
+	_, err := tree.ConsistencyProof(0, 5)
+	assert.ErrorIs(t, err, ErrInvalidTreeSize)
+
+	_, err = tree.ConsistencyProof(6, 5)
+	assert.ErrorIs(t, err, ErrInvalidTreeSize)
+}
+
+func createTestTree(t *testing.T, size int) *Tree {
+	t.Helper()
+
+	tree := NewTree()
+	for i := 0; i < size; i++ {
+		tree.Append([]byte(fmt.Sprintf("leaf %d", i)))
+	}
+
+	return tree
+}
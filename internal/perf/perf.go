@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package perf records where gittuf spends time during verification, so that
+// users can tune caches and identify slow operations. Recording is disabled by
+// default, in which case tracking an operation is a no-op.
+package perf
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Category identifies a kind of operation performed during verification.
+type Category string
+
+const (
+	SignatureChecks    Category = "signature checks"
+	AncestryWalks      Category = "ancestry walks"
+	PolicyLoading      Category = "policy loading"
+	AttestationLookups Category = "attestation lookups"
+	ObjectReads        Category = "object reads"
+)
+
+// categories lists the categories in the order they are reported.
+var categories = []Category{SignatureChecks, AncestryWalks, PolicyLoading, AttestationLookups, ObjectReads}
+
+type stats struct {
+	duration time.Duration
+	calls    int
+
+	// depth tracks nested operations of the same category, such as a policy
+	// state loaded while loading another, so that they aren't counted twice
+	depth int
+}
+
+type recorder struct {
+	mu      sync.Mutex
+	enabled atomic.Bool
+	start   time.Time
+	stats   map[Category]*stats
+}
+
+var global = &recorder{}
+
+// Enable starts recording operations, discarding anything recorded earlier.
+func Enable() {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	global.enabled.Store(true)
+	global.start = time.Now()
+	global.stats = map[Category]*stats{}
+	for _, category := range categories {
+		global.stats[category] = &stats{}
+	}
+}
+
+// Disable stops recording operations.
+func Disable() {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	global.enabled.Store(false)
+}
+
+// Track records the start of an operation in the specified category. The
+// returned function must be invoked when the operation completes, typically
+// using defer.
+func Track(category Category) func() {
+	if !global.enabled.Load() {
+		return func() {}
+	}
+
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	s := global.stats[category]
+	s.calls++
+	s.depth++
+	if s.depth > 1 {
+		return func() {
+			global.mu.Lock()
+			defer global.mu.Unlock()
+
+			s.depth--
+		}
+	}
+
+	start := time.Now()
+	return func() {
+		global.mu.Lock()
+		defer global.mu.Unlock()
+
+		s.depth--
+		s.duration += time.Since(start)
+	}
+}
+
+// WriteReport writes a breakdown of the time spent in each category since
+// recording was enabled. Categories may overlap: for example, the time spent
+// loading policy includes the object reads performed to do so.
+func WriteReport(w io.Writer) error {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	if !global.enabled.Load() {
+		return nil
+	}
+
+	report := strings.Builder{}
+	report.WriteString("Verification performance:\n")
+	report.WriteString(fmt.Sprintf("    Total: %s\n", time.Since(global.start).Round(time.Microsecond)))
+	for _, category := range categories {
+		s := global.stats[category]
+		report.WriteString(fmt.Sprintf("    %s%s: %s (%d calls)\n", strings.ToUpper(string(category[:1])), category[1:], s.duration.Round(time.Microsecond), s.calls))
+	}
+	report.WriteString("Note: categories overlap, e.g., policy loading includes the object reads it performs.\n")
+
+	_, err := io.WriteString(w, report.String())
+	return err
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package perf
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrack(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		Disable()
+
+		Track(ObjectReads)()
+
+		report := &bytes.Buffer{}
+		err := WriteReport(report)
+		assert.Nil(t, err)
+		assert.Empty(t, report.String())
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		Enable()
+		defer Disable()
+
+		stop := Track(PolicyLoading)
+		// Nested operations in the same category are counted but not timed
+		// again
+		stopNested := Track(PolicyLoading)
+		time.Sleep(time.Millisecond)
+		stopNested()
+		stop()
+
+		Track(ObjectReads)()
+		Track(ObjectReads)()
+
+		assert.Equal(t, 2, global.stats[PolicyLoading].calls)
+		assert.Equal(t, 0, global.stats[PolicyLoading].depth)
+		assert.GreaterOrEqual(t, global.stats[PolicyLoading].duration, time.Millisecond)
+		assert.Equal(t, 2, global.stats[ObjectReads].calls)
+		assert.Equal(t, 0, global.stats[SignatureChecks].calls)
+
+		report := &bytes.Buffer{}
+		err := WriteReport(report)
+		assert.Nil(t, err)
+		assert.Contains(t, report.String(), "Verification performance:\n")
+		assert.Contains(t, report.String(), "    Policy loading: ")
+		assert.Contains(t, report.String(), " (2 calls)\n")
+		assert.Contains(t, report.String(), "    Signature checks: 0s (0 calls)\n")
+	})
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// automationKey is a key delegated permission to record RSL entries on behalf
+// of a key in the policy.
+type automationKey struct {
+	key            *tuf.Key
+	delegatorKeyID string
+}
+
+// getAutomationKeys returns the automation keys that may record the entry on
+// behalf of keys in the policy. A key is returned for each policy key that
+// signed an automation delegation for the key that matches the entry's ref and
+// whose time window includes the trusted time the entry was recorded before.
+// As the automation key sets the entry's timestamp, the entry must be followed
+// by an entry signed using a key in the policy within the window, or the window
+// must include the current time. Only the delegations recorded in the
+// attestations before the entry are considered, so removing a delegation
+// revokes it for later entries. Shard entries are recorded in the RSL by their
+// index entries, so recordedEntryID identifies the entry in the RSL.
+func getAutomationKeys(ctx context.Context, repo *git.Repository, policy *State, attestationsState *attestations.Attestations, entry *rsl.ReferenceEntry, recordedEntryID plumbing.Hash) ([]*automationKey, error) {
+	if attestationsState == nil {
+		return nil, nil
+	}
+
+	envelopes, err := attestationsState.GetAutomationDelegations(repo)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelopes) == 0 {
+		return nil, nil
+	}
+
+	allKeys, err := policy.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+	trustedKeys := make([]*tuf.Key, 0, len(allKeys))
+	for _, key := range allKeys {
+		trustedKeys = append(trustedKeys, key)
+	}
+	trustedKeys, err = policy.filterAuthorizedKeys(trustedKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	var recordedBefore time.Time
+	automationKeys := []*automationKey{}
+	for _, env := range envelopes {
+		delegation, err := attestations.GetAutomationDelegation(env)
+		if err != nil {
+			return nil, err
+		}
+
+		if !delegation.Matches(entry.RefName) {
+			continue
+		}
+
+		if recordedBefore.IsZero() {
+			recordedBefore, err = policy.getRecordedBefore(ctx, repo, recordedEntryID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !delegation.ActiveAt(recordedBefore) {
+			slog.Debug(fmt.Sprintf("Ignoring delegation to automation key '%s' as entry '%s' may have been recorded outside the delegation's window", delegation.Key.KeyID, entry.ID.String()))
+			continue
+		}
+
+		delegators := set.NewSet[string]()
+		if err := addApprovingKeyIDs(ctx, delegators, env, trustedKeys); err != nil {
+			return nil, err
+		}
+
+		delegatorKeyIDs := delegators.Contents()
+		slices.Sort(delegatorKeyIDs)
+		for _, delegatorKeyID := range delegatorKeyIDs {
+			slog.Debug(fmt.Sprintf("Found delegation of '%s' to automation key '%s'", delegatorKeyID, delegation.Key.KeyID))
+			automationKeys = append(automationKeys, &automationKey{key: delegation.Key, delegatorKeyID: delegatorKeyID})
+		}
+	}
+
+	return automationKeys, nil
+}
+
+// withAutomationKeys returns a copy of the verifier that trusts signatures on
+// commits made using the automation keys in place of signatures by the keys
+// that delegated to them. Automation keys delegated by keys the verifier
+// doesn't trust are ignored. If no automation keys apply, the verifier is
+// returned as is.
+func (v *Verifier) withAutomationKeys(automationKeys []*automationKey) *Verifier {
+	applicable := []*automationKey{}
+	for _, automationKey := range automationKeys {
+		if slices.ContainsFunc(v.keys, func(key *tuf.Key) bool { return key.KeyID == automationKey.delegatorKeyID }) {
+			applicable = append(applicable, automationKey)
+		}
+	}
+	if len(applicable) == 0 {
+		return v
+	}
+
+	verifier := *v
+	verifier.automationKeys = applicable
+	return &verifier
+}
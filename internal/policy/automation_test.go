@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyEntryWithAutomationDelegation(t *testing.T) {
+	refName := "refs/heads/main"
+
+	automationKey, err := gpg.LoadGPGKeyFromBytes(artifacts.GPGKey2Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := common.TestClock.Now()
+
+	tests := map[string]struct {
+		refs             []string
+		notBefore        time.Time
+		notAfter         time.Time
+		signerKeyBytes   []byte
+		followerKeyBytes []byte
+		expectedError    error
+	}{
+		"delegation for ref": {
+			refs:             []string{refName},
+			notBefore:        now.Add(-time.Hour),
+			notAfter:         now.Add(time.Hour),
+			signerKeyBytes:   targets1KeyBytes,
+			followerKeyBytes: gpgKeyBytes,
+		},
+		"delegation for matching pattern": {
+			refs:             []string{"refs/heads/*"},
+			notBefore:        now.Add(-time.Hour),
+			notAfter:         now.Add(time.Hour),
+			signerKeyBytes:   targets1KeyBytes,
+			followerKeyBytes: gpgKeyBytes,
+		},
+		"delegation for other ref": {
+			refs:           []string{"refs/heads/feature"},
+			notBefore:      now.Add(-time.Hour),
+			notAfter:       now.Add(time.Hour),
+			signerKeyBytes: targets1KeyBytes,
+			expectedError:  ErrUnauthorizedSignature,
+		},
+		"backdated entry": {
+			// The entry claims to be recorded within the window, but no
+			// trusted entry shows it was recorded before the window ended
+			refs:           []string{refName},
+			notBefore:      now.Add(-time.Hour),
+			notAfter:       now.Add(time.Hour),
+			signerKeyBytes: targets1KeyBytes,
+			expectedError:  ErrUnauthorizedSignature,
+		},
+		"backdated entry followed by automation entry": {
+			refs:             []string{refName},
+			notBefore:        now.Add(-time.Hour),
+			notAfter:         now.Add(time.Hour),
+			signerKeyBytes:   targets1KeyBytes,
+			followerKeyBytes: artifacts.GPGKey2Private,
+			expectedError:    ErrUnauthorizedSignature,
+		},
+		"expired delegation": {
+			refs:           []string{refName},
+			notBefore:      now.Add(-2 * time.Hour),
+			notAfter:       now.Add(-time.Hour),
+			signerKeyBytes: targets1KeyBytes,
+			expectedError:  ErrUnauthorizedSignature,
+		},
+		"delegation signed by key not trusted for ref": {
+			refs:           []string{refName},
+			notBefore:      now.Add(-time.Hour),
+			notAfter:       now.Add(time.Hour),
+			signerKeyBytes: rootKeyBytes,
+			expectedError:  ErrUnauthorizedSignature,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo, state := createTestRepository(t, createTestStateWithAutomationPolicy)
+
+			addTestAutomationDelegation(t, repo, &attestations.AutomationDelegation{
+				Key:       automationKey,
+				Refs:      test.refs,
+				NotBefore: test.notBefore,
+				NotAfter:  test.notAfter,
+			}, test.signerKeyBytes)
+
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+			entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+			entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, artifacts.GPGKey2Private)
+
+			if test.followerKeyBytes != nil {
+				common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), test.followerKeyBytes)
+			}
+
+			err := verifyEntry(testCtx, repo, state, loadTestAttestations(t, repo), entry)
+			if test.expectedError == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expectedError)
+			}
+		})
+	}
+
+	t.Run("no delegation", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithAutomationPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, artifacts.GPGKey2Private)
+
+		err := verifyEntry(testCtx, repo, state, loadTestAttestations(t, repo), entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
+}
+
+func createTestStateWithAutomationPolicy(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	developerKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = UpdateDelegation(targetsMetadata, "protect-main", []*tuf.Key{gpgKey, developerKey}, []string{"git:refs/heads/main"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(testCtx, targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+
+	return state
+}
+
+func addTestAutomationDelegation(t *testing.T, repo *git.Repository, delegation *attestations.AutomationDelegation, signerKeyBytes []byte) {
+	t.Helper()
+
+	statement, err := attestations.NewAutomationDelegationAttestation(delegation)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(signerKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err = dsse.SignEnvelope(testCtx, env, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	currentAttestations := loadTestAttestations(t, repo)
+	if err := currentAttestations.SetAutomationDelegation(repo, env); err != nil {
+		t.Fatal(err)
+	}
+	if err := currentAttestations.Commit(repo, "Add automation delegation", false); err != nil {
+		t.Fatal(err)
+	}
+}
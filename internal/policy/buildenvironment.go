@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var ErrBuildEnvironmentNotSignedByTrustedKey = errors.New("build environment attestation is not signed by a key trusted for the ref")
+
+// VerifyBuildEnvironmentAttestation verifies that the build environment
+// attestation for the ref is signed by one of the keys trusted for the ref, or
+// if no rule protects the ref, by any key in the policy. Unlike attestations
+// that authorize changes, a single signature suffices irrespective of the
+// thresholds of the rules protecting the ref, as the attestation only records
+// where an entry was created. VerifyBuildEnvironmentAttestation does not
+// inspect the attestation's payload, the caller must ensure its validity.
+func (s *State) VerifyBuildEnvironmentAttestation(ctx context.Context, refName string, env *sslibdsse.Envelope) error {
+	if err := s.verifySignedByKeyTrustedForRef(ctx, refName, env); err != nil {
+		if errors.Is(err, ErrVerifierConditionsUnmet) {
+			return ErrBuildEnvironmentNotSignedByTrustedKey
+		}
+		return err
+	}
+
+	return nil
+}
+
+// verifySignedByKeyTrustedForRef verifies that the envelope is signed by one of
+// the keys trusted for the ref, or if no rule protects the ref, by any key in
+// the policy.
+func (s *State) verifySignedByKeyTrustedForRef(ctx context.Context, refName string, env *sslibdsse.Envelope) error {
+	trustedKeys, err := s.FindPublicKeysForPath(ctx, fmt.Sprintf("%s:%s", gitReferenceRuleScheme, refName))
+	if err != nil {
+		return err
+	}
+
+	if len(trustedKeys) == 0 {
+		allKeys, err := s.PublicKeys()
+		if err != nil {
+			return err
+		}
+
+		for _, key := range allKeys {
+			trustedKeys = append(trustedKeys, key)
+		}
+	}
+
+	trustedKeys, err = s.filterAuthorizedKeys(trustedKeys)
+	if err != nil {
+		return err
+	}
+
+	verifier := &Verifier{name: refName, keys: trustedKeys, threshold: 1}
+	return verifier.Verify(ctx, nil, env)
+}
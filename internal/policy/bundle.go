@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+const SigningBundleType = "https://gittuf.dev/signing-bundle/v0.1"
+
+var (
+	ErrNoRootKeys                  = errors.New("at least one root key must be specified")
+	ErrInvalidSigningBundle        = errors.New("invalid signing bundle")
+	ErrNoSigningBundles            = errors.New("no signing bundles specified")
+	ErrSigningBundleMismatch       = errors.New("signing bundles are for different metadata")
+	ErrSignerNotInSigningBundle    = errors.New("signing key is not trusted in the metadata of the signing bundle")
+	ErrUnexpectedSigningBundleRole = errors.New("signing bundle is for unexpected role")
+)
+
+// SigningBundle is a request to sign metadata out-of-band, such as on offline
+// machines that hold root keys. The bundle is serialized to a file that is
+// passed to each key holder, who adds their signature to it. As key holders
+// may sign separate copies of the bundle, their signatures can be merged
+// before the metadata is committed.
+type SigningBundle struct {
+	// Type identifies the format of the bundle.
+	Type string `json:"type"`
+
+	// Role is the name of the role whose metadata is to be signed.
+	Role string `json:"role"`
+
+	// Envelope contains the metadata and the signatures collected for it.
+	Envelope *sslibdsse.Envelope `json:"envelope"`
+}
+
+// NewRootSigningBundle creates a signing bundle containing unsigned root
+// metadata that trusts the specified root keys with the threshold.
+func NewRootSigningBundle(rootKeys []*tuf.Key, threshold int) (*SigningBundle, error) {
+	if len(rootKeys) == 0 {
+		return nil, ErrNoRootKeys
+	}
+	if threshold < 1 {
+		return nil, ErrCannotMeetThreshold
+	}
+
+	rootMetadata := InitializeRootMetadata(rootKeys[0])
+	for _, key := range rootKeys[1:] {
+		rootMetadata = AddRootKey(rootMetadata, key)
+	}
+
+	rootMetadata, err := UpdateRootThreshold(rootMetadata, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningBundle{Type: SigningBundleType, Role: RootRoleName, Envelope: env}, nil
+}
+
+// LoadSigningBundle deserializes a signing bundle.
+func LoadSigningBundle(contents []byte) (*SigningBundle, error) {
+	bundle := &SigningBundle{}
+	if err := json.Unmarshal(contents, bundle); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSigningBundle, err)
+	}
+
+	if bundle.Type != SigningBundleType || bundle.Role == "" || bundle.Envelope == nil {
+		return nil, ErrInvalidSigningBundle
+	}
+
+	return bundle, nil
+}
+
+// Marshal serializes the signing bundle.
+func (b *SigningBundle) Marshal() ([]byte, error) {
+	contents, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(contents, '\n'), nil
+}
+
+// Sign adds the signer's signature to the bundle's metadata. The signer's key
+// must be trusted for the bundle's role in the metadata, so that key holders
+// cannot add signatures that do not count towards the role's threshold.
+func (b *SigningBundle) Sign(ctx context.Context, signer sslibdsse.SignerVerifier) error {
+	if b.Role != RootRoleName {
+		return fmt.Errorf("%w: '%s'", ErrUnexpectedSigningBundleRole, b.Role)
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	state := &State{RootEnvelope: b.Envelope}
+	rootKeys, err := state.GetRootKeys()
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(rootKeys, func(key *tuf.Key) bool { return key.KeyID == keyID }) {
+		return fmt.Errorf("%w: '%s'", ErrSignerNotInSigningBundle, keyID)
+	}
+
+	slog.Debug(fmt.Sprintf("Signing bundle's %s metadata using '%s'...", b.Role, keyID))
+	env, err := dsse.SignEnvelope(ctx, b.Envelope, signer)
+	if err != nil {
+		return err
+	}
+
+	b.Envelope = env
+	return nil
+}
+
+// MergeSigningBundles combines the signatures in the bundles, which must all be
+// for the same metadata. If a key signed more than one of the bundles, its
+// signature is included once.
+func MergeSigningBundles(bundles ...*SigningBundle) (*SigningBundle, error) {
+	if len(bundles) == 0 {
+		return nil, ErrNoSigningBundles
+	}
+
+	merged := &SigningBundle{
+		Type: bundles[0].Type,
+		Role: bundles[0].Role,
+		Envelope: &sslibdsse.Envelope{
+			PayloadType: bundles[0].Envelope.PayloadType,
+			Payload:     bundles[0].Envelope.Payload,
+			Signatures:  []sslibdsse.Signature{},
+		},
+	}
+
+	for _, bundle := range bundles {
+		if bundle.Role != merged.Role || bundle.Envelope.PayloadType != merged.Envelope.PayloadType || bundle.Envelope.Payload != merged.Envelope.Payload {
+			return nil, ErrSigningBundleMismatch
+		}
+
+		for _, signature := range bundle.Envelope.Signatures {
+			if slices.ContainsFunc(merged.Envelope.Signatures, func(s sslibdsse.Signature) bool { return s.KeyID == signature.KeyID }) {
+				continue
+			}
+			merged.Envelope.Signatures = append(merged.Envelope.Signatures, signature)
+		}
+	}
+
+	return merged, nil
+}
+
+// RootState returns the policy state containing the bundle's root metadata,
+// after verifying that it is signed by a threshold of its root keys.
+func (b *SigningBundle) RootState(ctx context.Context) (*State, error) {
+	if b.Role != RootRoleName {
+		return nil, fmt.Errorf("%w: '%s'", ErrUnexpectedSigningBundleRole, b.Role)
+	}
+
+	state := &State{RootEnvelope: b.Envelope}
+	rootKeys, err := state.GetRootKeys()
+	if err != nil {
+		return nil, err
+	}
+	state.RootPublicKeys = rootKeys
+
+	slog.Debug("Verifying root metadata signatures...")
+	if err := state.Verify(ctx); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
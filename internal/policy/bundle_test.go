@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigningBundle(t *testing.T) {
+	rootKey1, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootKey2, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer1, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer2, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets1KeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	untrustedSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets2KeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		_, err := NewRootSigningBundle(nil, 1)
+		assert.ErrorIs(t, err, ErrNoRootKeys)
+
+		_, err = NewRootSigningBundle([]*tuf.Key{rootKey1}, 0)
+		assert.ErrorIs(t, err, ErrCannotMeetThreshold)
+
+		_, err = NewRootSigningBundle([]*tuf.Key{rootKey1}, 2)
+		assert.ErrorIs(t, err, ErrCannotMeetThreshold)
+	})
+
+	t.Run("sign and merge bundles", func(t *testing.T) {
+		bundle, err := NewRootSigningBundle([]*tuf.Key{rootKey1, rootKey2}, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, bundle.Envelope.Signatures)
+
+		contents, err := bundle.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Each key holder signs their own copy of the bundle
+		firstCopy, err := LoadSigningBundle(contents)
+		assert.Nil(t, err)
+		secondCopy, err := LoadSigningBundle(contents)
+		assert.Nil(t, err)
+
+		err = firstCopy.Sign(context.Background(), untrustedSigner)
+		assert.ErrorIs(t, err, ErrSignerNotInSigningBundle)
+
+		assert.Nil(t, firstCopy.Sign(context.Background(), signer1))
+		assert.Nil(t, secondCopy.Sign(context.Background(), signer2))
+
+		// A single signature does not meet the threshold
+		_, err = firstCopy.RootState(context.Background())
+		assert.NotNil(t, err)
+
+		merged, err := MergeSigningBundles(firstCopy, secondCopy, firstCopy)
+		assert.Nil(t, err)
+		assert.Len(t, merged.Envelope.Signatures, 2)
+
+		state, err := merged.RootState(context.Background())
+		assert.Nil(t, err)
+		assert.Len(t, state.RootPublicKeys, 2)
+
+		rootMetadata, err := state.GetRootMetadata()
+		assert.Nil(t, err)
+		assert.Equal(t, 2, rootMetadata.Roles[RootRoleName].Threshold)
+	})
+
+	t.Run("merge mismatched bundles", func(t *testing.T) {
+		_, err := MergeSigningBundles()
+		assert.ErrorIs(t, err, ErrNoSigningBundles)
+
+		firstBundle, err := NewRootSigningBundle([]*tuf.Key{rootKey1}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		secondBundle, err := NewRootSigningBundle([]*tuf.Key{rootKey1, rootKey2}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = MergeSigningBundles(firstBundle, secondBundle)
+		assert.ErrorIs(t, err, ErrSigningBundleMismatch)
+	})
+
+	t.Run("load invalid bundle", func(t *testing.T) {
+		_, err := LoadSigningBundle([]byte("not json"))
+		assert.ErrorIs(t, err, ErrInvalidSigningBundle)
+
+		_, err = LoadSigningBundle([]byte(`{"role": "root"}`))
+		assert.ErrorIs(t, err, ErrInvalidSigningBundle)
+	})
+}
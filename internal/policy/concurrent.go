@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sync"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/progress"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// entryVerification tracks the verification of an RSL entry using the policy
+// and attestations in effect for it.
+type entryVerification struct {
+	entry             *rsl.ReferenceEntry
+	policy            *State
+	attestationsState *attestations.Attestations
+
+	err  error
+	done chan struct{}
+}
+
+func newEntryVerification(entry *rsl.ReferenceEntry, policy *State, attestationsState *attestations.Attestations) *entryVerification {
+	return &entryVerification{
+		entry:             entry,
+		policy:            policy,
+		attestationsState: attestationsState,
+		done:              make(chan struct{}),
+	}
+}
+
+// wait blocks until the entry has been verified and returns the result.
+func (v *entryVerification) wait(ctx context.Context) error {
+	select {
+	case <-v.done:
+		return v.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// verifyEntriesConcurrently verifies the signatures and attestations for the
+// entries in the background using up to GOMAXPROCS workers. The entries must be
+// independent of each other, i.e., the policy and attestations for each must
+// already be known. Workers pick up entries in order so that results for
+// earlier entries are available first. The returned function stops the
+// workers and waits for them to exit, and must be invoked before the repository
+// is modified. Entries not yet verified at that point are never marked done.
+func verifyEntriesConcurrently(ctx context.Context, repo *git.Repository, verifications []*entryVerification) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	wg := sync.WaitGroup{}
+	stop := func() {
+		cancel()
+		wg.Wait()
+	}
+
+	if len(verifications) == 0 {
+		return stop
+	}
+
+	workers := min(runtime.GOMAXPROCS(0), len(verifications))
+	if !supportsConcurrentReads(repo) {
+		workers = 1
+	}
+	slog.Debug(fmt.Sprintf("Verifying %d entries using %d workers...", len(verifications), workers))
+
+	queue := make(chan *entryVerification)
+	go func() {
+		defer close(queue)
+
+		for _, verification := range verifications {
+			select {
+			case queue <- verification:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < workers; i++ {
+		workerRepo, err := openWorkerRepository(repo)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for verification := range queue {
+				if err != nil {
+					verification.err = err
+				} else {
+					verification.err = verifyEntry(ctx, workerRepo, verification.policy, verification.attestationsState, verification.entry)
+				}
+				close(verification.done)
+				progress.Advance()
+			}
+		}()
+	}
+
+	return stop
+}
+
+// supportsConcurrentReads returns true if verification workers can read the
+// repository at the same time. Wrapped storage, such as the views used when
+// receiving pushes, is read by a single worker as it may not be safe for
+// concurrent use.
+func supportsConcurrentReads(repo *git.Repository) bool {
+	switch repo.Storer.(type) {
+	case *filesystem.Storage, *memory.Storage:
+		return true
+	default:
+		return false
+	}
+}
+
+// openWorkerRepository returns a handle to the repository for a verification
+// worker. go-git's on-disk storage lazily loads pack indexes and cannot be
+// read concurrently, so each worker opens its own. In-memory storage is
+// shared.
+func openWorkerRepository(repo *git.Repository) (*git.Repository, error) {
+	storage, isFilesystemStorage := repo.Storer.(*filesystem.Storage)
+	if !isFilesystemStorage {
+		return repo, nil
+	}
+
+	return git.Open(filesystem.NewStorage(storage.Filesystem(), cache.NewObjectLRUDefault()), nil)
+}
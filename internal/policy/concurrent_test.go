@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyEntriesConcurrently(t *testing.T) {
+	refName := "refs/heads/main"
+
+	t.Run("results match sequential verification", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		verifications := []*entryVerification{}
+		for i := 0; i < 8; i++ {
+			signingKeyBytes := gpgKeyBytes
+			if i%3 == 2 {
+				signingKeyBytes = gpgUnauthorizedKeyBytes
+			}
+
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+			entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+			entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, signingKeyBytes)
+
+			verifications = append(verifications, newEntryVerification(entry, state, nil))
+		}
+
+		stop := verifyEntriesConcurrently(testCtx, repo, verifications)
+		defer stop()
+
+		for i, verification := range verifications {
+			err := verification.wait(testCtx)
+			expectedErr := verifyEntry(testCtx, repo, state, nil, verification.entry)
+			assert.Equal(t, expectedErr, err)
+			if i%3 == 2 {
+				assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+			} else {
+				assert.Nil(t, err)
+			}
+		}
+	})
+}
+
+func TestOpenWorkerRepository(t *testing.T) {
+	t.Run("in-memory storage is shared", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+		assert.True(t, supportsConcurrentReads(repo))
+
+		workerRepo, err := openWorkerRepository(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, repo, workerRepo)
+	})
+
+	t.Run("on-disk storage is opened per worker", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		gitinterface.CreateTestGitRepository(t, tmpDir)
+		repo, err := git.PlainOpen(tmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rsl.InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+		if err := rsl.NewReferenceEntry("refs/heads/main", plumbing.ZeroHash).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, supportsConcurrentReads(repo))
+
+		workerRepo, err := openWorkerRepository(repo)
+		assert.Nil(t, err)
+		assert.NotSame(t, repo, workerRepo)
+
+		expectedEntry, err := rsl.GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry, err := rsl.GetLatestEntry(workerRepo)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedEntry.GetID(), entry.GetID())
+	})
+}
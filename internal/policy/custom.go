@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var ErrCustomAttestationNotSignedByTrustedKey = errors.New("custom attestation is not signed by a key trusted for the ref")
+
+// VerifyCustomAttestation verifies that the custom attestation for the ref is
+// signed by one of the keys trusted for the ref, or if no rule protects the
+// ref, by any key in the policy. As with build environment attestations, a
+// single signature suffices as the attestation only records evidence about an
+// entry. VerifyCustomAttestation does not inspect the attestation's payload,
+// the caller must ensure its validity.
+func (s *State) VerifyCustomAttestation(ctx context.Context, refName string, env *sslibdsse.Envelope) error {
+	if err := s.verifySignedByKeyTrustedForRef(ctx, refName, env); err != nil {
+		if errors.Is(err, ErrVerifierConditionsUnmet) {
+			return ErrCustomAttestationNotSignedByTrustedKey
+		}
+		return err
+	}
+
+	return nil
+}
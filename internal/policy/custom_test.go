@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyCustomAttestation(t *testing.T) {
+	state := createTestStateWithPolicy(t)
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err = dsse.SignEnvelope(context.Background(), env, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The key is not trusted for the protected ref
+	err = state.VerifyCustomAttestation(context.Background(), "refs/heads/main", env)
+	assert.ErrorIs(t, err, ErrCustomAttestationNotSignedByTrustedKey)
+
+	// Any key in the policy is trusted for unprotected refs
+	err = state.VerifyCustomAttestation(context.Background(), "refs/heads/feature", env)
+	assert.Nil(t, err)
+}
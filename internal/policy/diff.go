@@ -0,0 +1,276 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+// ThresholdChange records a threshold that differs between two policy states.
+type ThresholdChange struct {
+	From int
+	To   int
+}
+
+// ExpiryChange records an expiry date that differs between two policy states.
+type ExpiryChange struct {
+	From string
+	To   string
+}
+
+// RoleDiff records how a role, either one declared in the root metadata or a
+// rule, differs between two policy states.
+type RoleDiff struct {
+	// Name is the name of the role or rule.
+	Name string
+
+	// AddedKeys contains the IDs of keys authorized only in the newer state.
+	AddedKeys []string
+
+	// RemovedKeys contains the IDs of keys authorized only in the older
+	// state.
+	RemovedKeys []string
+
+	// Threshold is set if the role's threshold changed.
+	Threshold *ThresholdChange
+
+	// AddedPaths contains the patterns a rule protects only in the newer
+	// state.
+	AddedPaths []string
+
+	// RemovedPaths contains the patterns a rule protects only in the older
+	// state.
+	RemovedPaths []string
+}
+
+// StateDiff summarizes the semantic differences between two policy states.
+type StateDiff struct {
+	// RootExpires is set if the expiry of the root metadata changed.
+	RootExpires *ExpiryChange
+
+	// TargetsExpires is set if the expiry of the top level targets metadata
+	// changed.
+	TargetsExpires *ExpiryChange
+
+	// UpdatedRoles records changes to the roles declared in the root
+	// metadata, such as the root and targets roles.
+	UpdatedRoles []*RoleDiff
+
+	// AddedRules contains the names of rules only in the newer state.
+	AddedRules []string
+
+	// RemovedRules contains the names of rules only in the older state.
+	RemovedRules []string
+
+	// UpdatedRules records changes to rules in both states. A rule may be
+	// reported as updated without key, threshold, or path changes if other
+	// parameters, such as its rotation schedule, changed.
+	UpdatedRules []*RoleDiff
+
+	// RootMetadataChanged is set if the root metadata differs between the
+	// states in any way, including changes not captured by the other fields.
+	RootMetadataChanged bool
+}
+
+// HasChanges returns true if the policy states differ.
+func (d *StateDiff) HasChanges() bool {
+	return d.RootMetadataChanged || d.TargetsExpires != nil || len(d.UpdatedRoles) > 0 || len(d.AddedRules) > 0 || len(d.RemovedRules) > 0 || len(d.UpdatedRules) > 0
+}
+
+// GetRoleDiff returns the changes to the specified role declared in the root
+// metadata, or nil if it is unchanged.
+func (d *StateDiff) GetRoleDiff(roleName string) *RoleDiff {
+	for _, roleDiff := range d.UpdatedRoles {
+		if roleDiff.Name == roleName {
+			return roleDiff
+		}
+	}
+
+	return nil
+}
+
+// DiffStates returns the changes made to the state `from` in the state `to`.
+// If `from` is nil, all of `to` is reported as added.
+func DiffStates(from, to *State) (*StateDiff, error) {
+	diff := &StateDiff{}
+
+	toRootMetadata, err := to.GetRootMetadata()
+	if err != nil {
+		return nil, err
+	}
+	toTargetsExpires, err := targetsExpires(to)
+	if err != nil {
+		return nil, err
+	}
+	toRules, err := rulesByName(to)
+	if err != nil {
+		return nil, err
+	}
+
+	fromRootMetadata := &tuf.RootMetadata{}
+	fromTargetsExpires := ""
+	fromRules := map[string]tuf.Delegation{}
+	if from != nil {
+		fromRootMetadata, err = from.GetRootMetadata()
+		if err != nil {
+			return nil, err
+		}
+		fromTargetsExpires, err = targetsExpires(from)
+		if err != nil {
+			return nil, err
+		}
+		fromRules, err = rulesByName(from)
+		if err != nil {
+			return nil, err
+		}
+
+		diff.RootMetadataChanged = from.RootEnvelope.Payload != to.RootEnvelope.Payload
+	} else {
+		diff.RootMetadataChanged = true
+	}
+
+	if fromRootMetadata.Expires != toRootMetadata.Expires {
+		diff.RootExpires = &ExpiryChange{From: fromRootMetadata.Expires, To: toRootMetadata.Expires}
+	}
+	if fromTargetsExpires != toTargetsExpires {
+		diff.TargetsExpires = &ExpiryChange{From: fromTargetsExpires, To: toTargetsExpires}
+	}
+
+	roleNames := []string{}
+	for roleName := range toRootMetadata.Roles {
+		roleNames = append(roleNames, roleName)
+	}
+	for roleName := range fromRootMetadata.Roles {
+		if _, has := toRootMetadata.Roles[roleName]; !has {
+			roleNames = append(roleNames, roleName)
+		}
+	}
+	slices.Sort(roleNames)
+
+	for _, roleName := range roleNames {
+		roleDiff := diffRoles(roleName, fromRootMetadata.Roles[roleName], toRootMetadata.Roles[roleName])
+		if roleDiff != nil {
+			diff.UpdatedRoles = append(diff.UpdatedRoles, roleDiff)
+		}
+	}
+
+	for name, toRule := range toRules {
+		fromRule, has := fromRules[name]
+		if !has {
+			diff.AddedRules = append(diff.AddedRules, name)
+			continue
+		}
+
+		ruleDiff := diffRoles(name, fromRule.Role, toRule.Role)
+		if ruleDiff == nil {
+			ruleDiff = &RoleDiff{Name: name}
+		}
+		ruleDiff.AddedPaths = difference(toRule.Paths, fromRule.Paths)
+		ruleDiff.RemovedPaths = difference(fromRule.Paths, toRule.Paths)
+
+		updated, err := isRuleUpdated(fromRule, toRule)
+		if err != nil {
+			return nil, err
+		}
+		if updated {
+			diff.UpdatedRules = append(diff.UpdatedRules, ruleDiff)
+		}
+	}
+	for name := range fromRules {
+		if _, has := toRules[name]; !has {
+			diff.RemovedRules = append(diff.RemovedRules, name)
+		}
+	}
+
+	slices.Sort(diff.AddedRules)
+	slices.Sort(diff.RemovedRules)
+	slices.SortFunc(diff.UpdatedRules, func(a, b *RoleDiff) int {
+		if a.Name < b.Name {
+			return -1
+		} else if a.Name > b.Name {
+			return 1
+		}
+		return 0
+	})
+
+	return diff, nil
+}
+
+// diffRoles returns the changes to the role's keys and threshold, or nil if
+// they are unchanged.
+func diffRoles(name string, from, to tuf.Role) *RoleDiff {
+	roleDiff := &RoleDiff{
+		Name:        name,
+		AddedKeys:   difference(to.KeyIDs, from.KeyIDs),
+		RemovedKeys: difference(from.KeyIDs, to.KeyIDs),
+	}
+	if from.Threshold != to.Threshold {
+		roleDiff.Threshold = &ThresholdChange{From: from.Threshold, To: to.Threshold}
+	}
+
+	if len(roleDiff.AddedKeys) == 0 && len(roleDiff.RemovedKeys) == 0 && roleDiff.Threshold == nil {
+		return nil
+	}
+
+	return roleDiff
+}
+
+// difference returns the sorted items in a that are not in b.
+func difference(a, b []string) []string {
+	items := []string{}
+	for _, item := range a {
+		if !slices.Contains(b, item) {
+			items = append(items, item)
+		}
+	}
+	slices.Sort(items)
+
+	return slices.Compact(items)
+}
+
+// targetsExpires returns the expiry of the state's top level targets
+// metadata, or an empty string if it has not been initialized.
+func targetsExpires(state *State) (string, error) {
+	if !state.HasTargetsRole(TargetsRoleName) {
+		return "", nil
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		return "", err
+	}
+
+	return targetsMetadata.Expires, nil
+}
+
+// rulesByName maps the names of the rules in the state to their delegations.
+func rulesByName(state *State) (map[string]tuf.Delegation, error) {
+	rules, err := state.ListRules()
+	if err != nil {
+		return nil, err
+	}
+
+	rulesMap := make(map[string]tuf.Delegation, len(rules))
+	for _, rule := range rules {
+		rulesMap[rule.Delegation.Name] = rule.Delegation
+	}
+
+	return rulesMap, nil
+}
+
+// isRuleUpdated returns true if the two delegations for a rule differ.
+func isRuleUpdated(from, to tuf.Delegation) (bool, error) {
+	fromBytes, err := json.Marshal(from)
+	if err != nil {
+		return false, err
+	}
+	toBytes, err := json.Marshal(to)
+	if err != nil {
+		return false, err
+	}
+
+	return string(fromBytes) != string(toBytes), nil
+}
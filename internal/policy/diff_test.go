@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffStates(t *testing.T) {
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	approverKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		diff, err := DiffStates(state, state)
+		assert.Nil(t, err)
+		assert.False(t, diff.HasChanges())
+	})
+
+	t.Run("everything added", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		diff, err := DiffStates(nil, state)
+		assert.Nil(t, err)
+		assert.True(t, diff.HasChanges())
+		assert.True(t, diff.RootMetadataChanged)
+		assert.NotNil(t, diff.RootExpires)
+		assert.Equal(t, "", diff.RootExpires.From)
+		assert.NotNil(t, diff.TargetsExpires)
+		assert.Equal(t, []string{rootKey.KeyID}, diff.GetRoleDiff(RootRoleName).AddedKeys)
+		assert.Equal(t, &ThresholdChange{From: 0, To: 1}, diff.GetRoleDiff(RootRoleName).Threshold)
+		assert.Equal(t, []string{"protect-files-1-and-2", "protect-main"}, diff.AddedRules)
+	})
+
+	t.Run("rule removed", func(t *testing.T) {
+		diff, err := DiffStates(createTestStateWithTagPolicy(t), createTestStateWithPolicy(t))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"protect-tags"}, diff.RemovedRules)
+		assert.Empty(t, diff.AddedRules)
+		assert.Empty(t, diff.UpdatedRules)
+		assert.Empty(t, diff.UpdatedRoles)
+		assert.False(t, diff.RootMetadataChanged)
+	})
+
+	t.Run("rule updated", func(t *testing.T) {
+		diff, err := DiffStates(createTestStateWithPolicy(t), createTestStateWithThresholdPolicy(t))
+		assert.Nil(t, err)
+		assert.Empty(t, diff.AddedRules)
+		assert.Empty(t, diff.RemovedRules)
+		assert.Equal(t, []*RoleDiff{{
+			Name:         "protect-main",
+			AddedKeys:    []string{approverKey.KeyID},
+			RemovedKeys:  []string{},
+			Threshold:    &ThresholdChange{From: 1, To: 2},
+			AddedPaths:   []string{},
+			RemovedPaths: []string{},
+		}}, diff.UpdatedRules)
+
+		diff, err = DiffStates(createTestStateWithThresholdPolicy(t), createTestStateWithPolicy(t))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{approverKey.KeyID}, diff.UpdatedRules[0].RemovedKeys)
+		assert.Equal(t, &ThresholdChange{From: 2, To: 1}, diff.UpdatedRules[0].Threshold)
+		assert.NotContains(t, diff.UpdatedRules[0].RemovedKeys, gpgKey.KeyID)
+	})
+}
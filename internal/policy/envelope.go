@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// GetEnvelopeSigners returns the keys trusted in the policy state that have a
+// valid signature on the envelope, sorted by key ID. The keys are not checked
+// against any rule, so the envelope must still meet the policy where it's
+// used. Keys that cannot verify DSSE signatures, such as GPG keys, are ignored.
+func (s *State) GetEnvelopeSigners(ctx context.Context, env *sslibdsse.Envelope) ([]*tuf.Key, error) {
+	allKeys, err := s.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*tuf.Key, 0, len(allKeys))
+	for _, key := range allKeys {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b *tuf.Key) int { return strings.Compare(a.KeyID, b.KeyID) })
+
+	signerKeyIDs := set.NewSet[string]()
+	if err := addApprovingKeyIDs(ctx, signerKeyIDs, env, keys); err != nil {
+		return nil, err
+	}
+
+	signers := []*tuf.Key{}
+	for _, key := range keys {
+		if signerKeyIDs.Has(key.KeyID) {
+			signers = append(signers, key)
+		}
+	}
+
+	return signers, nil
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+// AllowedSignersPrincipal is the principal used for all keys exported to an
+// allowed_signers file. gittuf keys are not bound to an email address, so the
+// exported keys are trusted for any identity, and gittuf policy remains the
+// authority on which key may modify which refs and files.
+const AllowedSignersPrincipal = "*"
+
+// ExportAllowedSigners returns the SSH keys trusted in the policy state in the
+// format of an SSH allowed_signers file. The file can be used with Git's
+// `gpg.ssh.allowedSignersFile` option so that Git recognizes signatures made
+// by these keys. Each key is restricted to the `git` namespace used by Git for
+// SSH signatures. Keys are sorted by key ID so the output is deterministic.
+func (s *State) ExportAllowedSigners() ([]byte, error) {
+	allKeys, err := s.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	keyIDs := sortedKeyIDs(allKeys, ssh.SSHKeyType)
+
+	var buf bytes.Buffer
+	for _, keyID := range keyIDs {
+		key := allKeys[keyID]
+
+		// Key IDs may include characters that aren't valid in the options
+		// section, so they're recorded as a comment instead
+		fmt.Fprintf(&buf, "# %s\n", keyID)
+		fmt.Fprintf(&buf, "%s namespaces=\"%s\" %s %s\n", AllowedSignersPrincipal, ssh.SSHSigNamespace, key.Scheme, key.KeyVal.Public)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ExportGPGKeyring returns the GPG keys trusted in the policy state as a
+// single armored keyring that can be imported using `gpg --import`. Keys are
+// sorted by key ID so the output is deterministic.
+func (s *State) ExportGPGKeyring() ([]byte, error) {
+	allKeys, err := s.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	keyIDs := sortedKeyIDs(allKeys, signerverifier.GPGKeyType)
+	if len(keyIDs) == 0 {
+		return []byte{}, nil
+	}
+
+	var buf bytes.Buffer
+	writer, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, keyID := range keyIDs {
+		keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(allKeys[keyID].KeyVal.Public))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse GPG key '%s': %w", keyID, err)
+		}
+
+		for _, entity := range keyring {
+			if err := entity.Serialize(writer); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
+
+// sortedKeyIDs returns the IDs of the keys of the specified type, sorted.
+func sortedKeyIDs(keys map[string]*tuf.Key, keyType string) []string {
+	keyIDs := []string{}
+	for keyID, key := range keys {
+		if key.KeyType == keyType {
+			keyIDs = append(keyIDs, keyID)
+		}
+	}
+	slices.Sort(keyIDs)
+	return keyIDs
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportAllowedSigners(t *testing.T) {
+	t.Run("no SSH keys", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		allowedSigners, err := state.ExportAllowedSigners()
+		assert.Nil(t, err)
+		assert.Empty(t, allowedSigners)
+	})
+
+	t.Run("with SSH keys", func(t *testing.T) {
+		state, sshKey := createTestStateWithSSHKey(t)
+
+		allowedSigners, err := state.ExportAllowedSigners()
+		assert.Nil(t, err)
+
+		expected := "# " + sshKey.KeyID + "\n" + `* namespaces="git" ` + strings.TrimSpace(string(artifacts.SSHECDSAPublicSSH)) + "\n"
+		assert.Equal(t, expected, string(allowedSigners))
+	})
+}
+
+func TestExportGPGKeyring(t *testing.T) {
+	t.Run("no GPG keys", func(t *testing.T) {
+		state := createTestStateWithOnlyRoot(t)
+
+		keyring, err := state.ExportGPGKeyring()
+		assert.Nil(t, err)
+		assert.Empty(t, keyring)
+	})
+
+	t.Run("with GPG keys", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		keyring, err := state.ExportGPGKeyring()
+		assert.Nil(t, err)
+
+		entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(keyring))
+		assert.Nil(t, err)
+		assert.Len(t, entities, 1)
+
+		gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exportedKey, err := gpg.LoadGPGKeyFromBytes(keyring)
+		assert.Nil(t, err)
+		assert.Equal(t, gpgKey.KeyID, exportedKey.KeyID)
+	})
+}
+
+func createTestStateWithSSHKey(t *testing.T) (*State, *tuf.Key) {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyPath := filepath.Join(t.TempDir(), "ecdsa.pub")
+	if err := os.WriteFile(keyPath, artifacts.SSHECDSAPublicSSH, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sshKey, err := ssh.NewKeyFromFile(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = AddDelegation(targetsMetadata, "protect-feature", []*tuf.Key{sshKey}, []string{"git:refs/heads/feature"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+
+	return state, sshKey
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+var ErrGitHubAppKeyNil = errors.New("GitHub app key is nil")
+
+// AddGitHubAppKey adds the specified key to the root metadata as a GitHub app
+// key. GitHub pull request approval attestations signed by GitHub app keys are
+// trusted to record the approvals of pull requests.
+func AddGitHubAppKey(rootMetadata *tuf.RootMetadata, appKey *tuf.Key) (*tuf.RootMetadata, error) {
+	if rootMetadata == nil {
+		return nil, ErrRootMetadataNil
+	}
+	if appKey == nil {
+		return nil, ErrGitHubAppKeyNil
+	}
+
+	rootMetadata.Keys[appKey.KeyID] = appKey
+
+	if _, ok := rootMetadata.Roles[GitHubAppRoleName]; !ok {
+		rootMetadata.AddRole(GitHubAppRoleName, tuf.Role{
+			KeyIDs:    []string{appKey.KeyID},
+			Threshold: 1,
+		})
+		return rootMetadata, nil
+	}
+
+	appRole := rootMetadata.Roles[GitHubAppRoleName]
+	if slices.Contains(appRole.KeyIDs, appKey.KeyID) {
+		return rootMetadata, nil
+	}
+
+	appRole.KeyIDs = append(appRole.KeyIDs, appKey.KeyID)
+	rootMetadata.Roles[GitHubAppRoleName] = appRole
+
+	return rootMetadata, nil
+}
+
+// DeleteGitHubAppKey removes the specified key from the GitHub app keys in the
+// root metadata. When the last GitHub app key is removed, the role is removed
+// as well.
+func DeleteGitHubAppKey(rootMetadata *tuf.RootMetadata, keyID string) (*tuf.RootMetadata, error) {
+	if rootMetadata == nil {
+		return nil, ErrRootMetadataNil
+	}
+	if keyID == "" {
+		return nil, ErrKeyIDEmpty
+	}
+	if _, ok := rootMetadata.Roles[GitHubAppRoleName]; !ok {
+		return rootMetadata, nil
+	}
+
+	appRole := rootMetadata.Roles[GitHubAppRoleName]
+	appRole.KeyIDs = slices.DeleteFunc(slices.Clone(appRole.KeyIDs), func(k string) bool {
+		return k == keyID
+	})
+
+	if len(appRole.KeyIDs) == 0 {
+		delete(rootMetadata.Roles, GitHubAppRoleName)
+		return rootMetadata, nil
+	}
+
+	rootMetadata.Roles[GitHubAppRoleName] = appRole
+
+	return rootMetadata, nil
+}
+
+// GetGitHubAppKeys returns the keys trusted as GitHub app keys in the root of
+// trust.
+func (s *State) GetGitHubAppKeys() ([]*tuf.Key, error) {
+	rootMetadata, err := s.getRootMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	return getRoleKeys(rootMetadata, GitHubAppRoleName), nil
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddGitHubAppKey(t *testing.T) {
+	key, err := tuf.LoadKeyFromBytes(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	appKey, err := tuf.LoadKeyFromBytes(targets1KeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = AddGitHubAppKey(nil, appKey)
+	assert.ErrorIs(t, err, ErrRootMetadataNil)
+
+	_, err = AddGitHubAppKey(rootMetadata, nil)
+	assert.ErrorIs(t, err, ErrGitHubAppKeyNil)
+
+	rootMetadata, err = AddGitHubAppKey(rootMetadata, appKey)
+	assert.Nil(t, err)
+	assert.Equal(t, appKey, rootMetadata.Keys[appKey.KeyID])
+	assert.Equal(t, []string{appKey.KeyID}, rootMetadata.Roles[GitHubAppRoleName].KeyIDs)
+
+	// Adding the key again is a no-op
+	rootMetadata, err = AddGitHubAppKey(rootMetadata, appKey)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{appKey.KeyID}, rootMetadata.Roles[GitHubAppRoleName].KeyIDs)
+}
+
+func TestDeleteGitHubAppKey(t *testing.T) {
+	key, err := tuf.LoadKeyFromBytes(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	appKey1, err := tuf.LoadKeyFromBytes(targets1KeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appKey2, err := tuf.LoadKeyFromBytes(targets2KeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err = AddGitHubAppKey(rootMetadata, appKey1)
+	assert.Nil(t, err)
+	rootMetadata, err = AddGitHubAppKey(rootMetadata, appKey2)
+	assert.Nil(t, err)
+
+	_, err = DeleteGitHubAppKey(nil, appKey1.KeyID)
+	assert.ErrorIs(t, err, ErrRootMetadataNil)
+
+	_, err = DeleteGitHubAppKey(rootMetadata, "")
+	assert.ErrorIs(t, err, ErrKeyIDEmpty)
+
+	rootMetadata, err = DeleteGitHubAppKey(rootMetadata, appKey1.KeyID)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{appKey2.KeyID}, rootMetadata.Roles[GitHubAppRoleName].KeyIDs)
+
+	rootMetadata, err = DeleteGitHubAppKey(rootMetadata, appKey2.KeyID)
+	assert.Nil(t, err)
+	assert.NotContains(t, rootMetadata.Roles, GitHubAppRoleName)
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"slices"
+	"sort"
+)
+
+// GraphNode is a role in the policy's delegation graph, with the keys trusted
+// for it and the roles it delegates to.
+type GraphNode struct {
+	Name        string
+	KeyIDs      []string
+	Threshold   int
+	Patterns    []string
+	Terminating bool
+
+	// Repeated is true if the role already appears elsewhere in the graph,
+	// in which case its delegations are only listed the first time.
+	Repeated bool
+
+	Children []*GraphNode
+}
+
+// DelegationGraph returns the roles in the state as a tree, rooted at the
+// Root role. The Root role delegates to the top level Targets role and any
+// other roles in the root metadata, such as the observer role. Each rule's
+// children are the rules in its metadata, in the order they're evaluated.
+func (s *State) DelegationGraph() (*GraphNode, error) {
+	rootMetadata, err := s.GetRootMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	rootRole := rootMetadata.Roles[RootRoleName]
+	root := &GraphNode{
+		Name:      RootRoleName,
+		KeyIDs:    slices.Clone(rootRole.KeyIDs),
+		Threshold: rootRole.Threshold,
+	}
+
+	roleNames := []string{}
+	for roleName := range rootMetadata.Roles {
+		if roleName != RootRoleName {
+			roleNames = append(roleNames, roleName)
+		}
+	}
+	sort.Strings(roleNames)
+
+	seenRoles := map[string]bool{}
+	for _, roleName := range roleNames {
+		role := rootMetadata.Roles[roleName]
+		node := &GraphNode{
+			Name:      roleName,
+			KeyIDs:    slices.Clone(role.KeyIDs),
+			Threshold: role.Threshold,
+		}
+
+		if roleName == TargetsRoleName {
+			if err := s.addDelegationsToGraph(node, seenRoles); err != nil {
+				return nil, err
+			}
+		}
+
+		root.Children = append(root.Children, node)
+	}
+
+	return root, nil
+}
+
+// addDelegationsToGraph adds the rules in the node's metadata to the graph as
+// its children. The allow rule is not included.
+func (s *State) addDelegationsToGraph(node *GraphNode, seenRoles map[string]bool) error {
+	if !s.HasTargetsRole(node.Name) {
+		return nil
+	}
+
+	if seenRoles[node.Name] {
+		node.Repeated = true
+		return nil
+	}
+	seenRoles[node.Name] = true
+
+	metadata, err := s.GetTargetsMetadata(node.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, delegation := range metadata.Delegations.Roles {
+		if delegation.Name == AllowRuleName {
+			continue
+		}
+
+		child := &GraphNode{
+			Name:        delegation.Name,
+			KeyIDs:      slices.Clone(delegation.KeyIDs),
+			Threshold:   delegation.Threshold,
+			Patterns:    slices.Clone(delegation.Paths),
+			Terminating: delegation.Terminating,
+		}
+		if err := s.addDelegationsToGraph(child, seenRoles); err != nil {
+			return err
+		}
+
+		node.Children = append(node.Children, child)
+	}
+
+	return nil
+}
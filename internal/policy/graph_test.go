@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateDelegationGraph(t *testing.T) {
+	key, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("only root", func(t *testing.T) {
+		state := createTestStateWithOnlyRoot(t)
+
+		graph, err := state.DelegationGraph()
+		assert.Nil(t, err)
+
+		expectedGraph := &GraphNode{Name: RootRoleName, KeyIDs: []string{key.KeyID}, Threshold: 1}
+		assert.Equal(t, expectedGraph, graph)
+	})
+
+	t.Run("with delegated policies", func(t *testing.T) {
+		state := createTestStateWithDelegatedPolicies(t)
+
+		graph, err := state.DelegationGraph()
+		assert.Nil(t, err)
+
+		expectedGraph := &GraphNode{
+			Name:      RootRoleName,
+			KeyIDs:    []string{key.KeyID},
+			Threshold: 1,
+			Children: []*GraphNode{
+				{
+					Name:      TargetsRoleName,
+					KeyIDs:    []string{key.KeyID},
+					Threshold: 1,
+					Children: []*GraphNode{
+						{
+							Name:      "1",
+							KeyIDs:    []string{key.KeyID},
+							Threshold: 1,
+							Patterns:  []string{"file:1/*"},
+							Children: []*GraphNode{
+								{Name: "3", KeyIDs: []string{gpgKey.KeyID}, Threshold: 1, Patterns: []string{"file:1/subpath1/*"}},
+								{Name: "4", KeyIDs: []string{gpgKey.KeyID}, Threshold: 1, Patterns: []string{"file:1/subpath2/*"}},
+							},
+						},
+						{Name: "2", KeyIDs: []string{key.KeyID}, Threshold: 1, Patterns: []string{"file:2/*"}},
+					},
+				},
+			},
+		}
+		assert.Equal(t, expectedGraph, graph)
+	})
+}
@@ -344,6 +344,91 @@ func createTestStateWithTagPolicy(t *testing.T) *State {
 	return state
 }
 
+// createTestStateWithMergeCommitOnlyKeyPolicyCreator returns a state creator
+// for a policy with a rule for the specified pattern that trusts a GPG key
+// only to sign merge commits.
+func createTestStateWithMergeCommitOnlyKeyPolicyCreator(pattern string) func(*testing.T) *State {
+	return func(t *testing.T) *State {
+		t.Helper()
+
+		state := createTestStateWithPolicy(t)
+
+		mergeCommitKey, err := gpg.LoadGPGKeyFromBytes(artifacts.GPGKey2Public)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = AddDelegation(targetsMetadata, "merge-commits", []*tuf.Key{mergeCommitKey}, []string{pattern}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = AddRestrictedKeyToDelegation(targetsMetadata, "merge-commits", mergeCommitKey, []string{tuf.KeyOperationMergeCommit})
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.TargetsEnvelope = targetsEnv
+
+		if err := state.loadRuleNames(); err != nil {
+			t.Fatal(err)
+		}
+
+		return state
+	}
+}
+
+func createTestStateWithReplacePolicy(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = AddDelegation(targetsMetadata, "protect-replace-refs", []*tuf.Key{gpgKey}, []string{"git:refs/replace/*"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+
+	if err := state.loadRuleNames(); err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
 func createTestStateWithTagPolicyForUnauthorizedTest(t *testing.T) *State {
 	t.Helper()
 
@@ -381,3 +466,213 @@ func createTestStateWithTagPolicyForUnauthorizedTest(t *testing.T) *State {
 
 	return state
 }
+
+func createTestStateWithFilePolicyForUnauthorizedTest(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = UpdateDelegation(targetsMetadata, "protect-files-1-and-2", []*tuf.Key{rootKey}, []string{"file:1", "file:2"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+
+	if err := state.loadRuleNames(); err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
+// createTestStateWithRotationPolicyCreator returns a state creator for a policy
+// where the keys authorized for main rotate weekly between the GPG key and the
+// targets 1 key, starting at the specified time.
+func createTestStateWithRotationPolicyCreator(start string) func(*testing.T) *State {
+	return func(t *testing.T) *State {
+		t.Helper()
+
+		state := createTestStateWithPolicy(t)
+
+		gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = UpdateDelegation(targetsMetadata, "protect-main", []*tuf.Key{gpgKey, targetsKey}, []string{"git:refs/heads/main"}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = SetDelegationRotation(targetsMetadata, "protect-main", &tuf.RotationSchedule{
+			Start:  start,
+			Period: "168h",
+			Shifts: [][]string{{gpgKey.KeyID}, {targetsKey.KeyID}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.TargetsEnvelope = targetsEnv
+
+		if err := state.loadRuleNames(); err != nil {
+			t.Fatal(err)
+		}
+
+		return state
+	}
+}
+
+// createTestStateWithTicketPolicy returns a policy where entries for main must
+// record a ticket.
+func createTestStateWithTicketPolicy(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = SetDelegationRequireTicket(targetsMetadata, "protect-main", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+
+	if err := state.loadRuleNames(); err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
+func createTestStateWithLinearHistoryPolicy(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = SetDelegationAllowedUpdateTypes(targetsMetadata, "protect-main", []string{tuf.UpdateTypeFastForward, tuf.UpdateTypeSquash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+
+	if err := state.loadRuleNames(); err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
+func createTestStateWithProvenancePolicy(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	builderKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = AddDelegation(targetsMetadata, "protect-tags", []*tuf.Key{gpgKey, builderKey}, []string{"git:refs/tags/*"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = SetDelegationAllowedBuilders(targetsMetadata, "protect-tags", []string{testBuilderID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+
+	if err := state.loadRuleNames(); err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/identities"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var ErrIdentityNotTrusted = errors.New("identity is not signed by the required number of policy administrators")
+
+// VerifyIdentity checks that the identity envelope is signed by the required
+// number of policy administrators, i.e., holders of root or top-level targets
+// keys, and that the key it maps the account to is trusted in the policy. As an
+// identity lets its key stand in for the account's approvals, the threshold is
+// the root role's threshold, or the policy approval threshold if it's higher.
+// Returns the verified identity.
+func (s *State) VerifyIdentity(ctx context.Context, env *sslibdsse.Envelope) (*identities.Identity, error) {
+	identity, err := identities.GetIdentity(env)
+	if err != nil {
+		return nil, err
+	}
+
+	rootMetadata, err := s.GetRootMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	approvers := set.NewSet[string]()
+	if err := addApprovingKeyIDs(ctx, approvers, env, getPolicyAdministratorKeys(rootMetadata)); err != nil {
+		return nil, err
+	}
+	threshold := max(rootMetadata.Roles[RootRoleName].Threshold, rootMetadata.PolicyApprovalThreshold, 1)
+	if approvers.Len() < threshold {
+		return nil, fmt.Errorf("%w: '%s' is signed by %d of %d required policy administrators", ErrIdentityNotTrusted, identities.IdentityPath(identity.Forge, identity.Account), approvers.Len(), threshold)
+	}
+
+	keys, err := s.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+	if _, has := keys[identity.KeyID]; !has {
+		return nil, fmt.Errorf("%w: '%s' is mapped to key '%s', which is not in the policy", ErrIdentityNotTrusted, identities.IdentityPath(identity.Forge, identity.Account), identity.KeyID)
+	}
+
+	return identity, nil
+}
+
+// getApproverKeyIDs returns the IDs of the keys of the users who approved the
+// change recorded in the entry. Approvals are read from GitHub pull request
+// approval attestations signed by a GitHub app key trusted in the policy, for
+// the entry's target and, if the target is a merge commit, for the heads it
+// merged. Approvers recorded by their GitHub usernames are mapped to their
+// keys using the identities recorded before the entry, and are skipped unless
+// their identity is signed by the required number of policy administrators.
+func getApproverKeyIDs(ctx context.Context, repo *git.Repository, policy *State, attestationsState *attestations.Attestations, entry *rsl.ReferenceEntry) ([]string, error) {
+	if attestationsState == nil {
+		return nil, nil
+	}
+
+	appKeys, err := policy.GetGitHubAppKeys()
+	if err != nil {
+		return nil, err
+	}
+	if len(appKeys) == 0 {
+		return nil, nil
+	}
+	appVerifier := &Verifier{name: GitHubAppRoleName, keys: appKeys, threshold: 1}
+
+	targetCommit, err := gitinterface.GetCommit(repo, entry.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	approvedCommitIDs := []string{entry.TargetID.String()}
+	if len(targetCommit.ParentHashes) > 1 {
+		for _, parentID := range targetCommit.ParentHashes[1:] {
+			approvedCommitIDs = append(approvedCommitIDs, parentID.String())
+		}
+	}
+
+	approvers := []string{}
+	for _, commitID := range approvedCommitIDs {
+		env, err := attestationsState.GetGitHubPullRequestApprovalAttestationFor(repo, entry.RefName, commitID)
+		if err != nil {
+			if errors.Is(err, attestations.ErrGitHubPullRequestApprovalAttestationNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		if err := appVerifier.Verify(ctx, nil, env); err != nil {
+			if errors.Is(err, ErrVerifierConditionsUnmet) {
+				slog.Debug(fmt.Sprintf("Ignoring approvals of '%s' as they're not signed by a trusted GitHub app key", commitID))
+				continue
+			}
+			return nil, err
+		}
+
+		commitApprovers, err := attestations.GetGitHubPullRequestApprovers(env)
+		if err != nil {
+			return nil, err
+		}
+		approvers = append(approvers, commitApprovers...)
+	}
+	if len(approvers) == 0 {
+		return nil, nil
+	}
+
+	keys, err := policy.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	var identitiesState *identities.Identities
+	approverKeyIDs := []string{}
+	for _, approver := range approvers {
+		if _, isKeyID := keys[approver]; isKeyID {
+			approverKeyIDs = append(approverKeyIDs, approver)
+			continue
+		}
+
+		if identitiesState == nil {
+			identitiesState, err = loadIdentitiesBefore(repo, entry)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		env, err := identitiesState.GetIdentityFor(repo, identities.ForgeGitHub, approver)
+		if err != nil {
+			if errors.Is(err, identities.ErrIdentityNotFound) {
+				slog.Debug(fmt.Sprintf("Ignoring approval by '%s' as no identity is recorded for them", approver))
+				continue
+			}
+			return nil, err
+		}
+
+		identity, err := policy.VerifyIdentity(ctx, env)
+		if err != nil {
+			if errors.Is(err, ErrIdentityNotTrusted) {
+				slog.Debug(fmt.Sprintf("Ignoring approval by '%s': %s", approver, err.Error()))
+				continue
+			}
+			return nil, err
+		}
+		approverKeyIDs = append(approverKeyIDs, identity.KeyID)
+	}
+
+	slices.Sort(approverKeyIDs)
+	return slices.Compact(approverKeyIDs), nil
+}
+
+// loadIdentitiesBefore loads the identities recorded in the latest RSL entry
+// for the identities namespace before the specified entry.
+func loadIdentitiesBefore(repo *git.Repository, entry *rsl.ReferenceEntry) (*identities.Identities, error) {
+	identitiesEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, identities.Ref, entry.ID)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return &identities.Identities{}, nil
+		}
+		return nil, err
+	}
+
+	return identities.LoadIdentitiesForEntry(repo, identitiesEntry)
+}
@@ -0,0 +1,340 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/identities"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyIdentity(t *testing.T) {
+	state := createTestStateWithThresholdPolicy(t)
+
+	approverKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("signed by policy administrator", func(t *testing.T) {
+		env := createTestIdentityEnvelope(t, "alice", approverKey.KeyID, rootKeyBytes)
+
+		identity, err := state.VerifyIdentity(testCtx, env)
+		assert.Nil(t, err)
+		assert.Equal(t, &identities.Identity{Forge: identities.ForgeGitHub, Account: "alice", KeyID: approverKey.KeyID}, identity)
+	})
+
+	t.Run("signed by developer", func(t *testing.T) {
+		env := createTestIdentityEnvelope(t, "alice", approverKey.KeyID, targets1KeyBytes)
+
+		_, err := state.VerifyIdentity(testCtx, env)
+		assert.ErrorIs(t, err, ErrIdentityNotTrusted)
+	})
+
+	t.Run("key not in policy", func(t *testing.T) {
+		env := createTestIdentityEnvelope(t, "alice", "unknown", rootKeyBytes)
+
+		_, err := state.VerifyIdentity(testCtx, env)
+		assert.ErrorIs(t, err, ErrIdentityNotTrusted)
+	})
+
+	t.Run("root threshold", func(t *testing.T) {
+		secondRootKey, err := tuf.LoadKeyFromBytes(targets2PubKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		state := createTestStateWithThresholdPolicy(t)
+		rootMetadata, err := state.GetRootMetadata()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rootMetadata = AddRootKey(rootMetadata, secondRootKey)
+		rootMetadata, err = UpdateRootThreshold(rootMetadata, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.RootEnvelope, err = dsse.CreateEnvelope(rootMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// One of two required root key holders signed the identity
+		env := createTestIdentityEnvelope(t, "alice", approverKey.KeyID, rootKeyBytes)
+		_, err = state.VerifyIdentity(testCtx, env)
+		assert.ErrorIs(t, err, ErrIdentityNotTrusted)
+
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets2KeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(testCtx, env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		identity, err := state.VerifyIdentity(testCtx, env)
+		assert.Nil(t, err)
+		assert.Equal(t, approverKey.KeyID, identity.KeyID)
+	})
+}
+
+func TestVerifyIdentitiesEntry(t *testing.T) {
+	approverKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		signerKeyBytes []byte
+		expectedError  error
+	}{
+		"signed using key in policy": {
+			signerKeyBytes: gpgKeyBytes,
+		},
+		"signed using key not in policy": {
+			signerKeyBytes: gpgUnauthorizedKeyBytes,
+			expectedError:  ErrUnauthorizedSignature,
+		},
+		"unsigned": {
+			expectedError: ErrUnauthorizedSignature,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+			addTestIdentity(t, repo, createTestIdentityEnvelope(t, "alice", approverKey.KeyID, rootKeyBytes))
+			identitiesRef, err := repo.Reference(plumbing.ReferenceName(identities.Ref), true)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			entry := rsl.NewReferenceEntry(identities.Ref, identitiesRef.Hash())
+			if test.signerKeyBytes == nil {
+				if err := entry.Commit(repo, false); err != nil {
+					t.Fatal(err)
+				}
+				latestEntry, err := rsl.GetLatestEntry(repo)
+				if err != nil {
+					t.Fatal(err)
+				}
+				entry.ID = latestEntry.GetID()
+			} else {
+				entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, test.signerKeyBytes)
+			}
+
+			err = verifyEntry(testCtx, repo, state, nil, entry)
+			if test.expectedError == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestVerifyEntryWithGitHubApprovals(t *testing.T) {
+	refName := "refs/heads/main"
+
+	approverKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("no approvals", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithGitHubAppPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		err := verifyEntry(testCtx, repo, state, loadTestAttestations(t, repo), entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
+
+	t.Run("approval by key ID", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithGitHubAppPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		addTestGitHubApproval(t, repo, refName, commitIDs[0].String(), []string{approverKey.KeyID}, targets2KeyBytes)
+
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		err := verifyEntry(testCtx, repo, state, loadTestAttestations(t, repo), entry)
+		assert.Nil(t, err)
+	})
+
+	t.Run("approval not signed by GitHub app key", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithGitHubAppPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		addTestGitHubApproval(t, repo, refName, commitIDs[0].String(), []string{approverKey.KeyID}, targets1KeyBytes)
+
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		err := verifyEntry(testCtx, repo, state, loadTestAttestations(t, repo), entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
+
+	t.Run("approval by GitHub username", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithGitHubAppPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		addTestGitHubApproval(t, repo, refName, commitIDs[0].String(), []string{"alice"}, targets2KeyBytes)
+
+		// No identity is recorded for alice
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		err := verifyEntry(testCtx, repo, state, loadTestAttestations(t, repo), entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		// The identity is only signed by alice
+		addTestIdentity(t, repo, createTestIdentityEnvelope(t, "alice", approverKey.KeyID, targets1KeyBytes))
+
+		entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		err = verifyEntry(testCtx, repo, state, loadTestAttestations(t, repo), entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		// The identity is signed by a policy administrator
+		addTestIdentity(t, repo, createTestIdentityEnvelope(t, "alice", approverKey.KeyID, rootKeyBytes))
+
+		entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		err = verifyEntry(testCtx, repo, state, loadTestAttestations(t, repo), entry)
+		assert.Nil(t, err)
+	})
+}
+
+func createTestStateWithGitHubAppPolicy(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithThresholdPolicy(t)
+
+	appKey, err := tuf.LoadKeyFromBytes(targets2PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err = AddGitHubAppKey(rootMetadata, appKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err = dsse.SignEnvelope(testCtx, rootEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.RootEnvelope = rootEnv
+
+	return state
+}
+
+func createTestIdentityEnvelope(t *testing.T, account, keyID string, signerKeyBytes []byte) *sslibdsse.Envelope {
+	t.Helper()
+
+	identity, err := identities.NewIdentity(identities.ForgeGitHub, account, keyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := dsse.CreateEnvelope(identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(signerKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err = dsse.SignEnvelope(testCtx, env, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return env
+}
+
+func addTestIdentity(t *testing.T, repo *git.Repository, env *sslibdsse.Envelope) {
+	t.Helper()
+
+	allIdentities, err := identities.LoadCurrentIdentities(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := allIdentities.SetIdentity(repo, env); err != nil {
+		t.Fatal(err)
+	}
+	if err := allIdentities.Commit(repo, "Add identity", false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func addTestGitHubApproval(t *testing.T, repo *git.Repository, refName, commitID string, approvers []string, signerKeyBytes []byte) {
+	t.Helper()
+
+	approval, err := attestations.NewGitHubPullRequestApprovalAttestation("gittuf", "gittuf", 1, refName, commitID, approvers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := dsse.CreateEnvelope(approval)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(signerKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err = dsse.SignEnvelope(testCtx, env, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	currentAttestations := loadTestAttestations(t, repo)
+	if err := currentAttestations.SetGitHubPullRequestApprovalAttestation(repo, env, refName, commitID); err != nil {
+		t.Fatal(err)
+	}
+	if err := currentAttestations.Commit(repo, "Add GitHub pull request approval", false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func loadTestAttestations(t *testing.T, repo *git.Repository) *attestations.Attestations {
+	t.Helper()
+
+	currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return currentAttestations
+}
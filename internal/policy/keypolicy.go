@@ -0,0 +1,271 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"crypto/dsa" //nolint:staticcheck
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgpecdsa "github.com/ProtonMail/go-crypto/openpgp/ecdsa"
+	pgpeddsa "github.com/ProtonMail/go-crypto/openpgp/eddsa"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	"github.com/gittuf/gittuf/internal/signerverifier/x509"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	KeyAlgorithmRSA     = "rsa"
+	KeyAlgorithmDSA     = "dsa"
+	KeyAlgorithmECDSA   = "ecdsa"
+	KeyAlgorithmEd25519 = "ed25519"
+	KeyAlgorithmEd448   = "ed448"
+	KeyAlgorithmElGamal = "elgamal"
+)
+
+var (
+	ErrUnknownKeyAlgorithm    = errors.New("unknown key algorithm")
+	ErrKeyAlgorithmNotAllowed = errors.New("key algorithm not allowed by key policy")
+	ErrKeySizeTooSmall        = errors.New("key size smaller than minimum required by key policy")
+)
+
+var knownKeyAlgorithms = []string{KeyAlgorithmRSA, KeyAlgorithmDSA, KeyAlgorithmECDSA, KeyAlgorithmEd25519, KeyAlgorithmEd448, KeyAlgorithmElGamal}
+
+// SetKeyPolicy sets the key algorithms and minimum key sizes acceptable for
+// keys in the policy. Passing an empty list of allowed algorithms and no
+// minimum sizes removes the key policy.
+func SetKeyPolicy(rootMetadata *tuf.RootMetadata, allowedAlgorithms []string, minimumKeySizes map[string]int) (*tuf.RootMetadata, error) {
+	for _, algorithm := range allowedAlgorithms {
+		if !slices.Contains(knownKeyAlgorithms, algorithm) {
+			return nil, fmt.Errorf("%w: '%s'", ErrUnknownKeyAlgorithm, algorithm)
+		}
+	}
+
+	for algorithm := range minimumKeySizes {
+		if !slices.Contains(knownKeyAlgorithms, algorithm) {
+			return nil, fmt.Errorf("%w: '%s'", ErrUnknownKeyAlgorithm, algorithm)
+		}
+	}
+
+	if len(allowedAlgorithms) == 0 && len(minimumKeySizes) == 0 {
+		rootMetadata.KeyPolicy = nil
+		return rootMetadata, nil
+	}
+
+	rootMetadata.KeyPolicy = &tuf.KeyPolicy{
+		AllowedAlgorithms: allowedAlgorithms,
+		MinimumKeySizes:   minimumKeySizes,
+	}
+
+	return rootMetadata, nil
+}
+
+// SetAllowExpiredGPGKeys sets whether signatures made using GPG keys that have
+// since expired are trusted if the key was valid when the signed object was
+// recorded in the RSL.
+func SetAllowExpiredGPGKeys(rootMetadata *tuf.RootMetadata, allow bool) (*tuf.RootMetadata, error) {
+	if rootMetadata == nil {
+		return nil, ErrRootMetadataNil
+	}
+
+	rootMetadata.AllowExpiredGPGKeys = allow
+	return rootMetadata, nil
+}
+
+// CheckKeyAgainstKeyPolicy checks that the key's algorithm and size are
+// acceptable under the key policy. A nil key policy accepts all keys. Sigstore
+// keyless identities and X.509 certificates are not bound to a long lived key
+// and are always accepted.
+func CheckKeyAgainstKeyPolicy(keyPolicy *tuf.KeyPolicy, key *tuf.Key) error {
+	if keyPolicy == nil || key.KeyType == signerverifier.FulcioKeyType || key.KeyType == x509.X509KeyType {
+		return nil
+	}
+
+	algorithm, size, err := GetKeyAlgorithm(key)
+	if err != nil {
+		return err
+	}
+
+	if len(keyPolicy.AllowedAlgorithms) != 0 && !slices.Contains(keyPolicy.AllowedAlgorithms, algorithm) {
+		return fmt.Errorf("%w: key '%s' uses '%s'", ErrKeyAlgorithmNotAllowed, key.KeyID, algorithm)
+	}
+
+	if minimumSize, has := keyPolicy.MinimumKeySizes[algorithm]; has && size < minimumSize {
+		return fmt.Errorf("%w: key '%s' is %s-%d, minimum is %d", ErrKeySizeTooSmall, key.KeyID, algorithm, size, minimumSize)
+	}
+
+	return nil
+}
+
+// GetKeyAlgorithm returns the algorithm and size in bits of the key. GPG, SSH,
+// and securesystemslib keys are supported.
+func GetKeyAlgorithm(key *tuf.Key) (string, int, error) {
+	switch key.KeyType {
+	case signerverifier.GPGKeyType:
+		return getGPGKeyAlgorithm(key)
+	case ssh.SSHKeyType:
+		verifier, err := ssh.NewVerifierFromKey(key)
+		if err != nil {
+			return "", 0, err
+		}
+		return getCryptoKeyAlgorithm(verifier.Public())
+	case signerverifier.RSAKeyType, signerverifier.ECDSAKeyType, signerverifier.ED25519KeyType:
+		verifier, err := signerverifier.NewSignerVerifierFromTUFKey(key) //nolint:staticcheck
+		if err != nil {
+			return "", 0, err
+		}
+		return getCryptoKeyAlgorithm(verifier.Public())
+	}
+
+	return "", 0, fmt.Errorf("%w: key type '%s'", ErrUnknownKeyAlgorithm, key.KeyType)
+}
+
+func getGPGKeyAlgorithm(key *tuf.Key) (string, int, error) {
+	keyRing, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.KeyVal.Public))
+	if err != nil {
+		return "", 0, err
+	}
+	if len(keyRing) == 0 {
+		return "", 0, fmt.Errorf("%w: empty GPG key", ErrUnknownKeyAlgorithm)
+	}
+
+	primaryKey := keyRing[0].PrimaryKey
+
+	switch primaryKey.PubKeyAlgo {
+	case packet.PubKeyAlgoRSA, packet.PubKeyAlgoRSASignOnly, packet.PubKeyAlgoRSAEncryptOnly:
+		size, err := primaryKey.BitLength()
+		return KeyAlgorithmRSA, int(size), err
+	case packet.PubKeyAlgoDSA:
+		size, err := primaryKey.BitLength()
+		return KeyAlgorithmDSA, int(size), err
+	case packet.PubKeyAlgoElGamal:
+		size, err := primaryKey.BitLength()
+		return KeyAlgorithmElGamal, int(size), err
+	case packet.PubKeyAlgoECDSA:
+		publicKey, ok := primaryKey.PublicKey.(*pgpecdsa.PublicKey)
+		if !ok {
+			return "", 0, ErrUnknownKeyAlgorithm
+		}
+		// Curve names are of the form P-256
+		curveName := publicKey.GetCurve().GetCurveName()
+		size, err := strconv.Atoi(strings.TrimPrefix(curveName, "P-"))
+		if err != nil {
+			return "", 0, fmt.Errorf("%w: curve '%s'", ErrUnknownKeyAlgorithm, curveName)
+		}
+		return KeyAlgorithmECDSA, size, nil
+	case packet.PubKeyAlgoEdDSA:
+		publicKey, ok := primaryKey.PublicKey.(*pgpeddsa.PublicKey)
+		if !ok {
+			return "", 0, ErrUnknownKeyAlgorithm
+		}
+		switch curveName := publicKey.GetCurve().GetCurveName(); curveName {
+		case KeyAlgorithmEd25519:
+			return KeyAlgorithmEd25519, 256, nil
+		case KeyAlgorithmEd448:
+			return KeyAlgorithmEd448, 448, nil
+		default:
+			return "", 0, fmt.Errorf("%w: curve '%s'", ErrUnknownKeyAlgorithm, curveName)
+		}
+	}
+
+	return "", 0, fmt.Errorf("%w: GPG algorithm %d", ErrUnknownKeyAlgorithm, primaryKey.PubKeyAlgo)
+}
+
+func getCryptoKeyAlgorithm(publicKey any) (string, int, error) {
+	switch k := publicKey.(type) {
+	case *rsa.PublicKey:
+		return KeyAlgorithmRSA, k.N.BitLen(), nil
+	case *dsa.PublicKey:
+		return KeyAlgorithmDSA, k.P.BitLen(), nil
+	case *ecdsa.PublicKey:
+		return KeyAlgorithmECDSA, k.Curve.Params().BitSize, nil
+	case ed25519.PublicKey:
+		return KeyAlgorithmEd25519, 256, nil
+	}
+
+	return "", 0, fmt.Errorf("%w: %T", ErrUnknownKeyAlgorithm, publicKey)
+}
+
+// checkKeyPolicy checks that every key in the state is acceptable under the
+// key policy set in the root metadata.
+func (s *State) checkKeyPolicy() error {
+	rootMetadata, err := s.getRootMetadata()
+	if err != nil {
+		return err
+	}
+
+	if rootMetadata.KeyPolicy == nil {
+		return nil
+	}
+
+	allKeys, err := s.PublicKeys()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range allKeys {
+		if err := CheckKeyAgainstKeyPolicy(rootMetadata.KeyPolicy, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// filterKeysByKeyPolicy returns the keys that are acceptable under the key
+// policy set in the root metadata. Signatures from the other keys must not be
+// trusted during verification.
+func (s *State) filterKeysByKeyPolicy(keys []*tuf.Key) ([]*tuf.Key, error) {
+	rootMetadata, err := s.getRootMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	if rootMetadata.KeyPolicy == nil {
+		return keys, nil
+	}
+
+	allowedKeys := make([]*tuf.Key, 0, len(keys))
+	for _, key := range keys {
+		if err := CheckKeyAgainstKeyPolicy(rootMetadata.KeyPolicy, key); err != nil {
+			slog.Debug(fmt.Sprintf("Ignoring key: %s", err.Error()))
+			continue
+		}
+		allowedKeys = append(allowedKeys, key)
+	}
+
+	return allowedKeys, nil
+}
+
+// expiredKeysRecordedAt returns the trusted time the RSL entry was recorded
+// before if the root metadata allows expired GPG keys, and the zero time
+// otherwise. Signatures for the entry made using GPG keys that have since
+// expired are accepted if the keys were valid at the returned time. The entry's
+// own timestamp isn't used as it's set by the signer, who could backdate it
+// using a key that expired after it leaked.
+func (s *State) expiredKeysRecordedAt(ctx context.Context, repo *git.Repository, entryID plumbing.Hash) (time.Time, error) {
+	rootMetadata, err := s.getRootMetadata()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !rootMetadata.AllowExpiredGPGKeys {
+		return time.Time{}, nil
+	}
+
+	return s.getRecordedBefore(ctx, repo, entryID)
+}
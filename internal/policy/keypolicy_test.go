@@ -0,0 +1,294 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetKeyAlgorithm(t *testing.T) {
+	sslibKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyPath := filepath.Join(t.TempDir(), "ecdsa.pub")
+	if err := os.WriteFile(keyPath, artifacts.SSHECDSAPublicSSH, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sshKey, err := ssh.NewKeyFromFile(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		key               *tuf.Key
+		expectedAlgorithm string
+		expectedSize      int
+		expectedError     error
+	}{
+		"securesystemslib ed25519 key": {
+			key:               sslibKey,
+			expectedAlgorithm: KeyAlgorithmEd25519,
+			expectedSize:      256,
+		},
+		"GPG RSA key": {
+			key:               gpgKey,
+			expectedAlgorithm: KeyAlgorithmRSA,
+			expectedSize:      3072,
+		},
+		"SSH ECDSA key": {
+			key:               sshKey,
+			expectedAlgorithm: KeyAlgorithmECDSA,
+			expectedSize:      256,
+		},
+		"unknown key type": {
+			key:           &tuf.Key{KeyType: "unknown"},
+			expectedError: ErrUnknownKeyAlgorithm,
+		},
+	}
+
+	for name, test := range tests {
+		algorithm, size, err := GetKeyAlgorithm(test.key)
+		if test.expectedError != nil {
+			assert.ErrorIs(t, err, test.expectedError, name)
+			continue
+		}
+
+		assert.Nil(t, err, name)
+		assert.Equal(t, test.expectedAlgorithm, algorithm, name)
+		assert.Equal(t, test.expectedSize, size, name)
+	}
+}
+
+func TestCheckKeyAgainstKeyPolicy(t *testing.T) {
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fulcioKey := &tuf.Key{KeyType: signerverifier.FulcioKeyType}
+
+	tests := map[string]struct {
+		keyPolicy     *tuf.KeyPolicy
+		key           *tuf.Key
+		expectedError error
+	}{
+		"no key policy": {
+			key: gpgKey,
+		},
+		"allowed algorithm": {
+			keyPolicy: &tuf.KeyPolicy{AllowedAlgorithms: []string{KeyAlgorithmRSA}},
+			key:       gpgKey,
+		},
+		"disallowed algorithm": {
+			keyPolicy:     &tuf.KeyPolicy{AllowedAlgorithms: []string{KeyAlgorithmEd25519}},
+			key:           gpgKey,
+			expectedError: ErrKeyAlgorithmNotAllowed,
+		},
+		"sufficient key size": {
+			keyPolicy: &tuf.KeyPolicy{MinimumKeySizes: map[string]int{KeyAlgorithmRSA: 3072}},
+			key:       gpgKey,
+		},
+		"insufficient key size": {
+			keyPolicy:     &tuf.KeyPolicy{MinimumKeySizes: map[string]int{KeyAlgorithmRSA: 4096}},
+			key:           gpgKey,
+			expectedError: ErrKeySizeTooSmall,
+		},
+		"Sigstore identity": {
+			keyPolicy: &tuf.KeyPolicy{AllowedAlgorithms: []string{KeyAlgorithmEd25519}},
+			key:       fulcioKey,
+		},
+	}
+
+	for name, test := range tests {
+		err := CheckKeyAgainstKeyPolicy(test.keyPolicy, test.key)
+		if test.expectedError == nil {
+			assert.Nil(t, err, name)
+		} else {
+			assert.ErrorIs(t, err, test.expectedError, name)
+		}
+	}
+}
+
+func TestSetKeyPolicy(t *testing.T) {
+	rootMetadata := tuf.NewRootMetadata()
+
+	_, err := SetKeyPolicy(rootMetadata, []string{"rot13"}, nil)
+	assert.ErrorIs(t, err, ErrUnknownKeyAlgorithm)
+
+	_, err = SetKeyPolicy(rootMetadata, nil, map[string]int{"rot13": 13})
+	assert.ErrorIs(t, err, ErrUnknownKeyAlgorithm)
+
+	rootMetadata, err = SetKeyPolicy(rootMetadata, []string{KeyAlgorithmRSA}, map[string]int{KeyAlgorithmRSA: 3072})
+	assert.Nil(t, err)
+	assert.Equal(t, &tuf.KeyPolicy{AllowedAlgorithms: []string{KeyAlgorithmRSA}, MinimumKeySizes: map[string]int{KeyAlgorithmRSA: 3072}}, rootMetadata.KeyPolicy)
+
+	rootMetadata, err = SetKeyPolicy(rootMetadata, nil, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, rootMetadata.KeyPolicy)
+}
+
+func TestSetAllowExpiredGPGKeys(t *testing.T) {
+	rootMetadata := tuf.NewRootMetadata()
+
+	rootMetadata, err := SetAllowExpiredGPGKeys(rootMetadata, true)
+	assert.Nil(t, err)
+	assert.True(t, rootMetadata.AllowExpiredGPGKeys)
+
+	rootMetadata, err = SetAllowExpiredGPGKeys(rootMetadata, false)
+	assert.Nil(t, err)
+	assert.False(t, rootMetadata.AllowExpiredGPGKeys)
+
+	_, err = SetAllowExpiredGPGKeys(nil, true)
+	assert.ErrorIs(t, err, ErrRootMetadataNil)
+}
+
+func TestExpiredKeysRecordedAt(t *testing.T) {
+	refName := "refs/heads/main"
+
+	t.Run("expired keys not allowed", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+
+		recordedAt, err := state.expiredKeysRecordedAt(testCtx, repo, entryID)
+		assert.Nil(t, err)
+		assert.True(t, recordedAt.IsZero())
+	})
+
+	t.Run("backdated entry", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithExpiredGPGKeysAllowed)
+
+		// The entry claims to be recorded at the test clock's time, but
+		// nothing shows it was recorded before now
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+
+		before := time.Now()
+		recordedAt, err := state.expiredKeysRecordedAt(testCtx, repo, entryID)
+		assert.Nil(t, err)
+		assert.False(t, recordedAt.Before(before))
+	})
+
+	t.Run("backdated entry followed by entry signed using key not in policy", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithExpiredGPGKeysAllowed)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), artifacts.GPGKey2Private)
+
+		before := time.Now()
+		recordedAt, err := state.expiredKeysRecordedAt(testCtx, repo, entryID)
+		assert.Nil(t, err)
+		assert.False(t, recordedAt.Before(before))
+	})
+
+	t.Run("entry followed by entry signed using key in policy", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithExpiredGPGKeysAllowed)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+
+		recordedAt, err := state.expiredKeysRecordedAt(testCtx, repo, entryID)
+		assert.Nil(t, err)
+		assert.True(t, recordedAt.Equal(common.TestClock.Now()))
+	})
+}
+
+func TestStateFindVerifiersForPathWithKeyPolicy(t *testing.T) {
+	state := createTestStateWithPolicy(t)
+
+	verifiers, err := state.FindVerifiersForPath("git:refs/heads/main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, len(verifiers[0].Keys()))
+
+	// Disallow the GPG key used in the rule protecting main
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err = SetKeyPolicy(rootMetadata, nil, map[string]int{KeyAlgorithmRSA: 4096})
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err = dsse.SignEnvelope(testCtx, rootEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state = &State{
+		RootEnvelope:    rootEnv,
+		TargetsEnvelope: state.TargetsEnvelope,
+		RootPublicKeys:  state.RootPublicKeys,
+	}
+
+	verifiers, err = state.FindVerifiersForPath("git:refs/heads/main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, verifiers[0].Keys())
+
+	err = state.checkKeyPolicy()
+	assert.ErrorIs(t, err, ErrKeySizeTooSmall)
+}
+
+func createTestStateWithExpiredGPGKeysAllowed(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err = SetAllowExpiredGPGKeys(rootMetadata, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err = dsse.SignEnvelope(testCtx, rootEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.RootEnvelope = rootEnv
+
+	return state
+}
@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var (
+	ErrKeyNotInPolicy          = errors.New("key is not trusted in the policy")
+	ErrKeyAlreadyRotated       = errors.New("key has already been rotated")
+	ErrInvalidKeyRotation      = errors.New("invalid key rotation")
+	ErrInvalidKeyRotationProof = errors.New("envelope is not a valid proof of the key rotation")
+)
+
+// keyRotationStatement is the payload of a key rotation's proof.
+type keyRotationStatement struct {
+	OldKeyID   string `json:"oldKeyID"`
+	NewKeyID   string `json:"newKeyID"`
+	RSLEntryID string `json:"rslEntryID"`
+}
+
+// NewKeyRotationProof returns an unsigned envelope recording the rotation of
+// the old key to the new key at the specified RSL entry. It must be signed
+// using the new key and, if possible, the old key before it's recorded using
+// RotateKey.
+func NewKeyRotationProof(oldKeyID, newKeyID, rslEntryID string) (*sslibdsse.Envelope, error) {
+	return dsse.CreateEnvelope(&keyRotationStatement{
+		OldKeyID:   oldKeyID,
+		NewKeyID:   newKeyID,
+		RSLEntryID: rslEntryID,
+	})
+}
+
+// RotateKey records the rotation of the old key to the new key in the root
+// metadata. The old key is replaced by the new key in each of the root
+// metadata's roles. Rules that trust the old key are not modified, instead the
+// new key is trusted in place of the old key for RSL entries after the
+// specified entry. The proof must record the rotation, and each of its
+// signatures must be from the old or new key.
+func RotateKey(ctx context.Context, rootMetadata *tuf.RootMetadata, oldKey, newKey *tuf.Key, rslEntryID string, proof *sslibdsse.Envelope) (*tuf.RootMetadata, error) {
+	if rootMetadata == nil {
+		return nil, ErrRootMetadataNil
+	}
+	if oldKey == nil || newKey == nil || oldKey.KeyID == newKey.KeyID || !plumbing.IsHash(rslEntryID) {
+		return nil, ErrInvalidKeyRotation
+	}
+
+	for _, rotation := range rootMetadata.KeyRotations {
+		if rotation.OldKey.KeyID == oldKey.KeyID {
+			return nil, ErrKeyAlreadyRotated
+		}
+	}
+
+	if err := verifyKeyRotationProof(ctx, oldKey, newKey, rslEntryID, proof); err != nil {
+		return nil, err
+	}
+
+	for roleName, role := range rootMetadata.Roles {
+		index := slices.Index(role.KeyIDs, oldKey.KeyID)
+		if index == -1 {
+			continue
+		}
+
+		if slices.Contains(role.KeyIDs, newKey.KeyID) {
+			role.KeyIDs = slices.Delete(role.KeyIDs, index, index+1)
+		} else {
+			role.KeyIDs[index] = newKey.KeyID
+		}
+		rootMetadata.Roles[roleName] = role
+
+		rootMetadata.AddKey(newKey)
+		delete(rootMetadata.Keys, oldKey.KeyID)
+	}
+
+	rootMetadata.KeyRotations = append(rootMetadata.KeyRotations, &tuf.KeyRotation{
+		OldKey:     oldKey,
+		NewKey:     newKey,
+		RSLEntryID: rslEntryID,
+		Proof:      proof,
+	})
+
+	return rootMetadata, nil
+}
+
+// verifyKeyRotationProof checks that the proof records the rotation and that
+// it's signed only using the old and new keys. A signature from the new key is
+// not required, as keys such as GPG keys cannot sign the proof.
+func verifyKeyRotationProof(ctx context.Context, oldKey, newKey *tuf.Key, rslEntryID string, proof *sslibdsse.Envelope) error {
+	if proof == nil {
+		return ErrInvalidKeyRotationProof
+	}
+
+	statement, err := dsse.DecodePayload[keyRotationStatement](nil, proof, nil)
+	if err != nil {
+		return errors.Join(ErrInvalidKeyRotationProof, err)
+	}
+	if statement.OldKeyID != oldKey.KeyID || statement.NewKeyID != newKey.KeyID || statement.RSLEntryID != rslEntryID {
+		return ErrInvalidKeyRotationProof
+	}
+
+	for _, signature := range proof.Signatures {
+		key := newKey
+		if signature.KeyID == oldKey.KeyID {
+			key = oldKey
+		} else if signature.KeyID != newKey.KeyID {
+			return ErrInvalidKeyRotationProof
+		}
+
+		verifier := &Verifier{keys: []*tuf.Key{key}, threshold: 1}
+		singleSignature := &sslibdsse.Envelope{
+			PayloadType: proof.PayloadType,
+			Payload:     proof.Payload,
+			Signatures:  []sslibdsse.Signature{signature},
+		}
+		if err := verifier.Verify(ctx, nil, singleSignature); err != nil {
+			return errors.Join(ErrInvalidKeyRotationProof, err)
+		}
+	}
+
+	return nil
+}
+
+// getKeyRotations returns the key rotations recorded in the state's root
+// metadata.
+func (s *State) getKeyRotations() ([]*tuf.KeyRotation, error) {
+	rootMetadata, err := s.getRootMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	return rootMetadata.KeyRotations, nil
+}
+
+// applyKeyRotations replaces each key that has been rotated with the key that
+// replaced it. Rotations are applied in the order they were recorded, so a key
+// rotated more than once is replaced by the latest key.
+func (s *State) applyKeyRotations(keys []*tuf.Key) ([]*tuf.Key, error) {
+	rotations, err := s.getKeyRotations()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rotation := range rotations {
+		keys = replaceKey(keys, rotation.OldKey, rotation.NewKey)
+	}
+
+	return keys, nil
+}
+
+// getKeyRotationsAfterEntry returns the key rotations in the state that were
+// recorded after the specified RSL entry, in the order they were recorded.
+// The old keys of these rotations are trusted for the entry.
+func (s *State) getKeyRotationsAfterEntry(repo *git.Repository, entry *rsl.ReferenceEntry) ([]*tuf.KeyRotation, error) {
+	rotations, err := s.getKeyRotations()
+	if err != nil {
+		return nil, err
+	}
+
+	rotationsAfterEntry := []*tuf.KeyRotation{}
+	for _, rotation := range rotations {
+		if rotation.RSLEntryID == plumbing.ZeroHash.String() {
+			// The key was rotated before the first entry
+			continue
+		}
+
+		isBefore, err := isEntryAtOrBefore(repo, entry, plumbing.NewHash(rotation.RSLEntryID))
+		if err != nil {
+			return nil, err
+		}
+		if isBefore {
+			rotationsAfterEntry = append(rotationsAfterEntry, rotation)
+		}
+	}
+
+	return rotationsAfterEntry, nil
+}
+
+// isEntryAtOrBefore returns true if the entry is the specified RSL entry or
+// precedes it in the RSL.
+func isEntryAtOrBefore(repo *git.Repository, entry *rsl.ReferenceEntry, otherEntryID plumbing.Hash) (bool, error) {
+	if entry.ID == otherEntryID {
+		return true, nil
+	}
+
+	return rsl.IsEntryBefore(repo, entry.ID, otherEntryID)
+}
+
+// applyKeyRotationsToVerifier returns a verifier that trusts the new key of
+// each rotation in the state in place of its old key. Restrictions on the
+// operations of the old key and its shifts in the verifier's rotation
+// schedule apply to the new key.
+func (s *State) applyKeyRotationsToVerifier(verifier *Verifier) (*Verifier, error) {
+	rotations, err := s.getKeyRotations()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rotation := range rotations {
+		verifier = verifier.withReplacedKey(rotation.OldKey, rotation.NewKey)
+	}
+
+	return verifier, nil
+}
+
+// beforeKeyRotations returns a verifier that trusts the old key of each
+// rotation in place of its new key, for entries made before the rotations.
+// The rotations are undone in the reverse of the order they were recorded.
+func (v *Verifier) beforeKeyRotations(rotations []*tuf.KeyRotation) *Verifier {
+	verifier := v
+	for i := len(rotations) - 1; i >= 0; i-- {
+		verifier = verifier.withReplacedKey(rotations[i].NewKey, rotations[i].OldKey)
+	}
+
+	return verifier
+}
+
+// withReplacedKey returns a copy of the verifier with the key being replaced
+// swapped for its replacement. If the verifier doesn't trust the key being
+// replaced, it is returned as is.
+func (v *Verifier) withReplacedKey(replaced, replacement *tuf.Key) *Verifier {
+	if !slices.ContainsFunc(v.keys, func(key *tuf.Key) bool { return key != nil && key.KeyID == replaced.KeyID }) {
+		return v
+	}
+
+	verifier := *v
+	verifier.keys = replaceKey(v.keys, replaced, replacement)
+
+	if operations, restricted := v.keyOperations[replaced.KeyID]; restricted {
+		verifier.keyOperations = maps.Clone(v.keyOperations)
+		delete(verifier.keyOperations, replaced.KeyID)
+		verifier.keyOperations[replacement.KeyID] = operations
+	}
+
+	if v.rotation != nil {
+		rotation := *v.rotation
+		rotation.Shifts = make([][]string, 0, len(v.rotation.Shifts))
+		for _, shift := range v.rotation.Shifts {
+			shift = slices.Clone(shift)
+			for i, keyID := range shift {
+				if keyID == replaced.KeyID {
+					shift[i] = replacement.KeyID
+				}
+			}
+			rotation.Shifts = append(rotation.Shifts, shift)
+		}
+		verifier.rotation = &rotation
+	}
+
+	return &verifier
+}
+
+// replaceKey returns the keys with the key being replaced swapped for its
+// replacement. Each key is included at most once.
+func replaceKey(keys []*tuf.Key, replaced, replacement *tuf.Key) []*tuf.Key {
+	index := slices.IndexFunc(keys, func(key *tuf.Key) bool { return key != nil && key.KeyID == replaced.KeyID })
+	if index == -1 {
+		return keys
+	}
+
+	keys = slices.Clone(keys)
+	if slices.ContainsFunc(keys, func(key *tuf.Key) bool { return key != nil && key.KeyID == replacement.KeyID }) {
+		return slices.Delete(keys, index, index+1)
+	}
+
+	keys[index] = replacement
+	return keys
+}
@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotateKey(t *testing.T) {
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unrelatedKey, err := tuf.LoadKeyFromBytes(targets2PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	newSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets1KeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	unrelatedSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets2KeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rslEntryID := plumbing.ZeroHash.String()
+
+	createProof := func(t *testing.T, oldKeyID, newKeyID string, signers ...sslibdsse.SignerVerifier) *sslibdsse.Envelope {
+		t.Helper()
+
+		proof, err := NewKeyRotationProof(oldKeyID, newKeyID, rslEntryID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, signer := range signers {
+			proof, err = dsse.SignEnvelope(testCtx, proof, signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return proof
+	}
+
+	createRootMetadata := func(t *testing.T) *tuf.RootMetadata {
+		t.Helper()
+
+		rootMetadata := InitializeRootMetadata(rootKey)
+		rootMetadata, err := AddTargetsKey(rootMetadata, rootKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return rootMetadata
+	}
+
+	t.Run("rotate root and targets key", func(t *testing.T) {
+		proof := createProof(t, rootKey.KeyID, newKey.KeyID, newSigner, rootSigner)
+
+		rootMetadata, err := RotateKey(testCtx, createRootMetadata(t), rootKey, newKey, rslEntryID, proof)
+		assert.Nil(t, err)
+
+		assert.Equal(t, []string{newKey.KeyID}, rootMetadata.Roles[RootRoleName].KeyIDs)
+		assert.Equal(t, []string{newKey.KeyID}, rootMetadata.Roles[TargetsRoleName].KeyIDs)
+		assert.Equal(t, map[string]*tuf.Key{newKey.KeyID: newKey}, rootMetadata.Keys)
+		assert.Equal(t, []*tuf.KeyRotation{{OldKey: rootKey, NewKey: newKey, RSLEntryID: rslEntryID, Proof: proof}}, rootMetadata.KeyRotations)
+	})
+
+	t.Run("rotate key in rules only", func(t *testing.T) {
+		proof := createProof(t, unrelatedKey.KeyID, newKey.KeyID)
+
+		rootMetadata, err := RotateKey(testCtx, createRootMetadata(t), unrelatedKey, newKey, rslEntryID, proof)
+		assert.Nil(t, err)
+
+		assert.Equal(t, []string{rootKey.KeyID}, rootMetadata.Roles[RootRoleName].KeyIDs)
+		assert.NotContains(t, rootMetadata.Keys, newKey.KeyID)
+		assert.Len(t, rootMetadata.KeyRotations, 1)
+	})
+
+	t.Run("key already rotated", func(t *testing.T) {
+		proof := createProof(t, rootKey.KeyID, newKey.KeyID, newSigner)
+
+		rootMetadata, err := RotateKey(testCtx, createRootMetadata(t), rootKey, newKey, rslEntryID, proof)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = RotateKey(testCtx, rootMetadata, rootKey, newKey, rslEntryID, proof)
+		assert.ErrorIs(t, err, ErrKeyAlreadyRotated)
+	})
+
+	t.Run("rotate key to itself", func(t *testing.T) {
+		proof := createProof(t, rootKey.KeyID, rootKey.KeyID)
+
+		_, err := RotateKey(testCtx, createRootMetadata(t), rootKey, rootKey, rslEntryID, proof)
+		assert.ErrorIs(t, err, ErrInvalidKeyRotation)
+	})
+
+	t.Run("proof for different keys", func(t *testing.T) {
+		proof := createProof(t, rootKey.KeyID, unrelatedKey.KeyID, newSigner)
+
+		_, err := RotateKey(testCtx, createRootMetadata(t), rootKey, newKey, rslEntryID, proof)
+		assert.ErrorIs(t, err, ErrInvalidKeyRotationProof)
+	})
+
+	t.Run("proof signed using unrelated key", func(t *testing.T) {
+		proof := createProof(t, rootKey.KeyID, newKey.KeyID, newSigner, unrelatedSigner)
+
+		_, err := RotateKey(testCtx, createRootMetadata(t), rootKey, newKey, rslEntryID, proof)
+		assert.ErrorIs(t, err, ErrInvalidKeyRotationProof)
+	})
+
+	t.Run("proof with invalid signature", func(t *testing.T) {
+		proof := createProof(t, rootKey.KeyID, newKey.KeyID, unrelatedSigner)
+		proof.Signatures[0].KeyID = newKey.KeyID
+
+		_, err := RotateKey(testCtx, createRootMetadata(t), rootKey, newKey, rslEntryID, proof)
+		assert.ErrorIs(t, err, ErrInvalidKeyRotationProof)
+	})
+}
+
+func TestVerifierWithReplacedKey(t *testing.T) {
+	oldKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := tuf.LoadKeyFromBytes(targets2PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := &Verifier{
+		name:          "protect-main",
+		keys:          []*tuf.Key{oldKey, otherKey},
+		threshold:     1,
+		keyOperations: map[string][]string{oldKey.KeyID: {"tag"}},
+		rotation:      &tuf.RotationSchedule{Shifts: [][]string{{oldKey.KeyID}, {otherKey.KeyID}}},
+	}
+
+	rotated := verifier.withReplacedKey(oldKey, newKey)
+	assert.Equal(t, []*tuf.Key{newKey, otherKey}, rotated.keys)
+	assert.Equal(t, map[string][]string{newKey.KeyID: {"tag"}}, rotated.keyOperations)
+	assert.Equal(t, [][]string{{newKey.KeyID}, {otherKey.KeyID}}, rotated.rotation.Shifts)
+
+	// The original verifier is unchanged
+	assert.Equal(t, []*tuf.Key{oldKey, otherKey}, verifier.keys)
+	assert.Equal(t, [][]string{{oldKey.KeyID}, {otherKey.KeyID}}, verifier.rotation.Shifts)
+
+	restored := rotated.beforeKeyRotations([]*tuf.KeyRotation{{OldKey: oldKey, NewKey: newKey}})
+	assert.Equal(t, verifier, restored)
+
+	// Replacing a key with one the verifier already trusts removes it
+	deduplicated := verifier.withReplacedKey(oldKey, otherKey)
+	assert.Equal(t, []*tuf.Key{otherKey}, deduplicated.keys)
+
+	// Verifiers that don't trust the key are unchanged
+	assert.Same(t, rotated, rotated.withReplacedKey(oldKey, otherKey))
+}
+
+func TestVerifyEntryWithKeyRotation(t *testing.T) {
+	refName := "refs/heads/main"
+
+	repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+	oldEntry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	oldEntry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, oldEntry, gpgKeyBytes)
+
+	oldKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := gpg.LoadGPGKeyFromBytes(artifacts.GPGKey2Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// GPG keys can't sign the proof
+	proof, err := NewKeyRotationProof(oldKey.KeyID, newKey.KeyID, oldEntry.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err = RotateKey(testCtx, rootMetadata, oldKey, newKey, oldEntry.ID.String(), proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err = dsse.SignEnvelope(testCtx, rootEnv, rootSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.RootEnvelope = rootEnv
+
+	t.Run("entry before rotation signed using old key", func(t *testing.T) {
+		err := verifyEntry(context.Background(), repo, state, nil, oldEntry)
+		assert.Nil(t, err)
+	})
+
+	t.Run("entry after rotation signed using old key", func(t *testing.T) {
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		err := verifyEntry(context.Background(), repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
+
+	t.Run("entry after rotation signed using new key", func(t *testing.T) {
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, artifacts.GPGKey2Private)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, artifacts.GPGKey2Private)
+
+		err := verifyEntry(context.Background(), repo, state, nil, entry)
+		assert.Nil(t, err)
+	})
+}
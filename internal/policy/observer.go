@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var (
+	ErrObserverKeyNil          = errors.New("observerKey is nil")
+	ErrObserverKeyAuthorized   = errors.New("observer key cannot also be a root or policy key")
+	ErrKeyIsObserverKey        = errors.New("key is an observer key and cannot be trusted for policy changes")
+	ErrNoObserverKeys          = errors.New("no observer keys are trusted in the root of trust")
+	ErrNotSignedByObserverKeys = errors.New("envelope is not signed by a trusted observer key")
+)
+
+// AddObserverKey adds the specified key to the root metadata as an observer.
+// Observers can sign verification reports for the repository but are never
+// trusted for changes to the RSL or the policy, so the key must not already
+// be trusted as a root or policy key.
+func AddObserverKey(rootMetadata *tuf.RootMetadata, observerKey *tuf.Key) (*tuf.RootMetadata, error) {
+	if rootMetadata == nil {
+		return nil, ErrRootMetadataNil
+	}
+	if observerKey == nil {
+		return nil, ErrObserverKeyNil
+	}
+
+	for _, roleName := range []string{RootRoleName, TargetsRoleName} {
+		if slices.Contains(rootMetadata.Roles[roleName].KeyIDs, observerKey.KeyID) {
+			return nil, ErrObserverKeyAuthorized
+		}
+	}
+
+	rootMetadata.Keys[observerKey.KeyID] = observerKey
+
+	if _, ok := rootMetadata.Roles[ObserverRoleName]; !ok {
+		rootMetadata.AddRole(ObserverRoleName, tuf.Role{
+			KeyIDs:    []string{observerKey.KeyID},
+			Threshold: 1,
+		})
+		return rootMetadata, nil
+	}
+
+	observerRole := rootMetadata.Roles[ObserverRoleName]
+	if slices.Contains(observerRole.KeyIDs, observerKey.KeyID) {
+		return rootMetadata, nil
+	}
+
+	observerRole.KeyIDs = append(observerRole.KeyIDs, observerKey.KeyID)
+	rootMetadata.Roles[ObserverRoleName] = observerRole
+
+	return rootMetadata, nil
+}
+
+// DeleteObserverKey removes the specified key from the observers in the root
+// metadata. When the last observer key is removed, the observer role is
+// removed as well.
+func DeleteObserverKey(rootMetadata *tuf.RootMetadata, keyID string) (*tuf.RootMetadata, error) {
+	if rootMetadata == nil {
+		return nil, ErrRootMetadataNil
+	}
+	if keyID == "" {
+		return nil, ErrKeyIDEmpty
+	}
+	if _, ok := rootMetadata.Roles[ObserverRoleName]; !ok {
+		return rootMetadata, nil
+	}
+
+	observerRole := rootMetadata.Roles[ObserverRoleName]
+	observerRole.KeyIDs = slices.DeleteFunc(slices.Clone(observerRole.KeyIDs), func(k string) bool {
+		return k == keyID
+	})
+
+	if len(observerRole.KeyIDs) == 0 {
+		delete(rootMetadata.Roles, ObserverRoleName)
+		return rootMetadata, nil
+	}
+
+	rootMetadata.Roles[ObserverRoleName] = observerRole
+
+	return rootMetadata, nil
+}
+
+// GetObserverKeys returns the keys trusted as observers in the root of trust.
+func (s *State) GetObserverKeys() ([]*tuf.Key, error) {
+	rootMetadata, err := s.getRootMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	observerKeys := make([]*tuf.Key, 0, len(rootMetadata.Roles[ObserverRoleName].KeyIDs))
+	for _, keyID := range rootMetadata.Roles[ObserverRoleName].KeyIDs {
+		key, has := rootMetadata.Keys[keyID]
+		if !has {
+			return nil, ErrObserverKeyNil
+		}
+
+		observerKeys = append(observerKeys, key)
+	}
+
+	return observerKeys, nil
+}
+
+// IsObserverKey returns true if the key is trusted as an observer in the root
+// of trust.
+func (s *State) IsObserverKey(keyID string) (bool, error) {
+	rootMetadata, err := s.getRootMetadata()
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(rootMetadata.Roles[ObserverRoleName].KeyIDs, keyID), nil
+}
+
+// VerifyObserverSignature verifies that the envelope is signed by at least one
+// of the observer keys trusted in the root of trust.
+func (s *State) VerifyObserverSignature(ctx context.Context, env *sslibdsse.Envelope) error {
+	observerKeys, err := s.GetObserverKeys()
+	if err != nil {
+		return err
+	}
+	if len(observerKeys) == 0 {
+		return ErrNoObserverKeys
+	}
+
+	observerKeys, err = s.filterKeysByKeyPolicy(observerKeys)
+	if err != nil {
+		return err
+	}
+
+	verifier := &Verifier{name: ObserverRoleName, keys: observerKeys, threshold: 1}
+	if err := verifier.Verify(ctx, nil, env); err != nil {
+		if errors.Is(err, ErrVerifierConditionsUnmet) {
+			return ErrNotSignedByObserverKeys
+		}
+		return err
+	}
+
+	return nil
+}
+
+// filterAuthorizedKeys returns the keys that may be trusted for changes to the
+// repository. Observer keys are always excluded, even if they are listed in a
+// rule, and the remaining keys must be acceptable under the key policy.
+func (s *State) filterAuthorizedKeys(keys []*tuf.Key) ([]*tuf.Key, error) {
+	rootMetadata, err := s.getRootMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err = s.applyKeyRotations(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	observerKeyIDs := rootMetadata.Roles[ObserverRoleName].KeyIDs
+	if len(observerKeyIDs) != 0 {
+		authorizedKeys := make([]*tuf.Key, 0, len(keys))
+		for _, key := range keys {
+			if slices.Contains(observerKeyIDs, key.KeyID) {
+				slog.Debug(fmt.Sprintf("Ignoring observer key '%s'", key.KeyID))
+				continue
+			}
+			authorizedKeys = append(authorizedKeys, key)
+		}
+		keys = authorizedKeys
+	}
+
+	return s.filterKeysByKeyPolicy(keys)
+}
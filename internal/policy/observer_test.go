@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddObserverKey(t *testing.T) {
+	key, err := tuf.LoadKeyFromBytes(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	observerKey, err := tuf.LoadKeyFromBytes(targets1KeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = AddObserverKey(nil, observerKey)
+	assert.ErrorIs(t, err, ErrRootMetadataNil)
+
+	_, err = AddObserverKey(rootMetadata, nil)
+	assert.ErrorIs(t, err, ErrObserverKeyNil)
+
+	_, err = AddObserverKey(rootMetadata, key)
+	assert.ErrorIs(t, err, ErrObserverKeyAuthorized)
+
+	rootMetadata, err = AddObserverKey(rootMetadata, observerKey)
+	assert.Nil(t, err)
+	assert.Equal(t, observerKey, rootMetadata.Keys[observerKey.KeyID])
+	assert.Equal(t, []string{observerKey.KeyID}, rootMetadata.Roles[ObserverRoleName].KeyIDs)
+
+	// Adding the key again is a no-op
+	rootMetadata, err = AddObserverKey(rootMetadata, observerKey)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{observerKey.KeyID}, rootMetadata.Roles[ObserverRoleName].KeyIDs)
+
+	// Observer keys cannot be made policy keys
+	_, err = AddTargetsKey(rootMetadata, observerKey)
+	assert.ErrorIs(t, err, ErrKeyIsObserverKey)
+}
+
+func TestDeleteObserverKey(t *testing.T) {
+	key, err := tuf.LoadKeyFromBytes(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	observerKey1, err := tuf.LoadKeyFromBytes(targets1KeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	observerKey2, err := tuf.LoadKeyFromBytes(targets2KeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err = AddObserverKey(rootMetadata, observerKey1)
+	assert.Nil(t, err)
+	rootMetadata, err = AddObserverKey(rootMetadata, observerKey2)
+	assert.Nil(t, err)
+
+	_, err = DeleteObserverKey(nil, observerKey1.KeyID)
+	assert.ErrorIs(t, err, ErrRootMetadataNil)
+
+	_, err = DeleteObserverKey(rootMetadata, "")
+	assert.ErrorIs(t, err, ErrKeyIDEmpty)
+
+	rootMetadata, err = DeleteObserverKey(rootMetadata, observerKey1.KeyID)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{observerKey2.KeyID}, rootMetadata.Roles[ObserverRoleName].KeyIDs)
+
+	rootMetadata, err = DeleteObserverKey(rootMetadata, observerKey2.KeyID)
+	assert.Nil(t, err)
+	assert.NotContains(t, rootMetadata.Roles, ObserverRoleName)
+}
+
+func TestObserverKeys(t *testing.T) {
+	rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	observerSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets1KeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	observerKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := createTestStateWithPolicy(t)
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err = AddObserverKey(rootMetadata, observerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err = dsse.SignEnvelope(context.Background(), rootEnv, rootSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.RootEnvelope = rootEnv
+
+	t.Run("get observer keys", func(t *testing.T) {
+		observerKeys, err := state.GetObserverKeys()
+		assert.Nil(t, err)
+		assert.Equal(t, []*tuf.Key{observerKey}, observerKeys)
+
+		isObserver, err := state.IsObserverKey(observerKey.KeyID)
+		assert.Nil(t, err)
+		assert.True(t, isObserver)
+
+		isObserver, err = state.IsObserverKey(state.RootPublicKeys[0].KeyID)
+		assert.Nil(t, err)
+		assert.False(t, isObserver)
+	})
+
+	t.Run("observer keys are not authorized", func(t *testing.T) {
+		authorizedKeys, err := state.filterAuthorizedKeys([]*tuf.Key{observerKey, state.RootPublicKeys[0]})
+		assert.Nil(t, err)
+		assert.Equal(t, []*tuf.Key{state.RootPublicKeys[0]}, authorizedKeys)
+	})
+
+	t.Run("verify observer signature", func(t *testing.T) {
+		env, err := dsse.CreateEnvelope(rootMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rootSignedEnv, err := dsse.SignEnvelope(context.Background(), env, rootSigner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = state.VerifyObserverSignature(context.Background(), rootSignedEnv)
+		assert.ErrorIs(t, err, ErrNotSignedByObserverKeys)
+
+		observerSignedEnv, err := dsse.SignEnvelope(context.Background(), env, observerSigner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = state.VerifyObserverSignature(context.Background(), observerSignedEnv)
+		assert.Nil(t, err)
+	})
+
+	t.Run("no observer keys", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+		err := state.VerifyObserverSignature(context.Background(), rootEnv)
+		assert.ErrorIs(t, err, ErrNoObserverKeys)
+	})
+}
@@ -8,13 +8,21 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"path"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 
+	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/common/set"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/perf"
+	"github.com/gittuf/gittuf/internal/report"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -36,6 +44,12 @@ const (
 	// TargetsRoleName defines the expected name for the top level gittuf policy file.
 	TargetsRoleName = "targets"
 
+	// ObserverRoleName defines the expected name for the role in the root of trust that lists keys which may only sign verification reports.
+	ObserverRoleName = "observer"
+
+	// GitHubAppRoleName defines the expected name for the role in the root of trust that lists keys which may sign GitHub pull request approval attestations.
+	GitHubAppRoleName = "github-app"
+
 	// DefaultCommitMessage defines the fallback message to use when updating the policy ref if an action specific message is unavailable.
 	DefaultCommitMessage = "Update policy state"
 
@@ -46,6 +60,10 @@ const (
 	fileRuleScheme         = "file"
 )
 
+// payloadCache memoizes the decoded policy metadata for the lifetime of the
+// process, as the same policy states are loaded repeatedly during verification.
+var payloadCache = dsse.NewPayloadCache()
+
 var (
 	ErrMetadataNotFound           = errors.New("unable to find requested metadata file; has it been initialized?")
 	ErrInvalidPolicyTree          = errors.New("invalid policy tree structure")
@@ -88,7 +106,14 @@ type State struct {
 	RootPublicKeys      []*tuf.Key
 
 	verifiersCache map[string][]*Verifier
-	ruleNames      *set.Set[string]
+	// verifiersCacheMu guards verifiersCache as entries may be verified
+	// concurrently using the same state
+	verifiersCacheMu sync.Mutex
+	ruleNames        *set.Set[string]
+
+	// recordedBefore caches trusted upper bounds on when RSL entries were
+	// recorded, used to check time windows for signatures
+	recordedBefore recordedBeforeCache
 }
 
 type DelegationWithDepth struct {
@@ -101,6 +126,8 @@ type DelegationWithDepth struct {
 // entry in the RSL. If no policy states are found and the entry is for the
 // policy-staging ref, that entry is returned with no verification.
 func LoadState(ctx context.Context, repo *git.Repository, entry *rsl.ReferenceEntry) (*State, error) {
+	defer perf.Track(perf.PolicyLoading)()
+
 	// Verify prior roots and get the latest applicable policy state
 	currentPolicyState, err := verifySuccessiveRootsAndLoadLatestPolicyState(ctx, repo, entry)
 	if err != nil {
@@ -114,6 +141,9 @@ func LoadState(ctx context.Context, repo *git.Repository, entry *rsl.ReferenceEn
 
 	if currentPolicyState == nil || entry.RefName == PolicyStagingRef {
 		// Either we have just one policy entry or we're loading a staging ref
+		if entry.RefName == PolicyRef {
+			report.RecordPolicyState(entry.ID.String())
+		}
 		return requestedState, nil
 	}
 
@@ -122,10 +152,17 @@ func LoadState(ctx context.Context, repo *git.Repository, entry *rsl.ReferenceEn
 		return nil, fmt.Errorf("unable to verify root of trust for requested state: %w", err)
 	}
 
+	if entry.RefName == PolicyRef {
+		if err := verifyPolicyEntryApproval(ctx, repo, currentPolicyState, requestedState, entry); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := requestedState.Verify(ctx); err != nil {
 		return nil, fmt.Errorf("requested state has invalidly signed metadata: %w", err)
 	}
 
+	report.RecordPolicyState(entry.ID.String())
 	return requestedState, nil
 }
 
@@ -135,6 +172,10 @@ func LoadState(ctx context.Context, repo *git.Repository, entry *rsl.ReferenceEn
 func LoadCurrentState(ctx context.Context, repo *git.Repository, ref string) (*State, error) {
 	entry, _, err := rsl.GetLatestReferenceEntryForRef(repo, ref)
 	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			// This is the case in freshly initialized repositories
+			return nil, fmt.Errorf("%w, has the root of trust been initialized?: %w", ErrPolicyNotFound, err)
+		}
 		return nil, err
 	}
 
@@ -152,6 +193,35 @@ func LoadFirstState(ctx context.Context, repo *git.Repository) (*State, error) {
 	return LoadState(ctx, repo, firstEntry)
 }
 
+// LoadStateForEntryID returns the policy State in effect at the RSL entry with
+// the specified ID. If the entry records a policy state, including a staged
+// one, that state is returned. Otherwise, the state recorded by the latest
+// policy entry preceding the entry is returned. The state is read directly from
+// the repository's object store without checking out any refs, and its root of
+// trust is verified as in LoadState.
+func LoadStateForEntryID(ctx context.Context, repo *git.Repository, entryID plumbing.Hash) (*State, error) {
+	entry, err := rsl.GetEntry(repo, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if referenceEntry, isReferenceEntry := entry.(*rsl.ReferenceEntry); isReferenceEntry {
+		if referenceEntry.RefName == PolicyRef || referenceEntry.RefName == PolicyStagingRef {
+			return LoadState(ctx, repo, referenceEntry)
+		}
+	}
+
+	policyEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, PolicyRef, entryID)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return nil, fmt.Errorf("%w before entry '%s'", ErrPolicyNotFound, entryID.String())
+		}
+		return nil, err
+	}
+
+	return LoadState(ctx, repo, policyEntry)
+}
+
 // GetStateForCommit scans the RSL to identify the first time a commit was seen
 // in the repository. The policy preceding that RSL entry is returned as the
 // State to be used for verifying the commit's signature. If the commit hasn't
@@ -186,7 +256,7 @@ func (s *State) PublicKeys() (map[string]*tuf.Key, error) {
 	}
 
 	// Add keys from the root metadata
-	rootMetadata, err := s.GetRootMetadata()
+	rootMetadata, err := s.getRootMetadata()
 	if err != nil {
 		return nil, err
 	}
@@ -200,7 +270,7 @@ func (s *State) PublicKeys() (map[string]*tuf.Key, error) {
 		// Early states where this hasn't been initialized yet
 		return nil, err
 	}
-	targetsMetadata, err := s.GetTargetsMetadata(TargetsRoleName)
+	targetsMetadata, err := s.getTargetsMetadata(TargetsRoleName)
 	if err != nil {
 		return nil, err
 	}
@@ -211,7 +281,7 @@ func (s *State) PublicKeys() (map[string]*tuf.Key, error) {
 
 	// Add keys from delegated targets metadata
 	for roleName := range s.DelegationEnvelopes {
-		delegatedMetadata, err := s.GetTargetsMetadata(roleName)
+		delegatedMetadata, err := s.getTargetsMetadata(roleName)
 		if err != nil {
 			return nil, err
 		}
@@ -221,9 +291,102 @@ func (s *State) PublicKeys() (map[string]*tuf.Key, error) {
 		}
 	}
 
+	// Keys that have been rotated are replaced by their new keys
+	for _, rotation := range rootMetadata.KeyRotations {
+		if _, has := allKeys[rotation.OldKey.KeyID]; has {
+			delete(allKeys, rotation.OldKey.KeyID)
+			allKeys[rotation.NewKey.KeyID] = rotation.NewKey
+		}
+	}
+
 	return allKeys, nil
 }
 
+// getUnprotectedVerifier returns a verifier that trusts all the keys in the
+// state with a threshold of one, for namespaces not protected by any rule. Keys
+// restricted to specific operations by every rule that trusts them, such as
+// keys that may only sign merge commits, keep their restrictions, as they
+// aren't trusted to sign arbitrary Git objects.
+func (s *State) getUnprotectedVerifier(name string) (*Verifier, error) {
+	allKeys, err := s.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	keyOperations, err := s.getKeyOperations()
+	if err != nil {
+		return nil, err
+	}
+
+	verifier := &Verifier{name: name, threshold: 1, keyOperations: keyOperations}
+	for _, key := range allKeys {
+		verifier.keys = append(verifier.keys, key)
+	}
+	verifier.keys, err = s.filterAuthorizedKeys(verifier.keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return verifier, nil
+}
+
+// getKeyOperations returns the operations that keys restricted by every rule
+// that trusts them may be used for, combined across the rules. Keys that have
+// been rotated are replaced by their new keys.
+func (s *State) getKeyOperations() (map[string][]string, error) {
+	if s.TargetsEnvelope == nil {
+		return nil, nil
+	}
+
+	targetsMetadata, err := s.getTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		return nil, err
+	}
+	rolesToCheck := []*tuf.TargetsMetadata{targetsMetadata}
+	for roleName := range s.DelegationEnvelopes {
+		delegatedMetadata, err := s.getTargetsMetadata(roleName)
+		if err != nil {
+			return nil, err
+		}
+		rolesToCheck = append(rolesToCheck, delegatedMetadata)
+	}
+
+	keyOperations := map[string][]string{}
+	unrestrictedKeyIDs := set.NewSet[string]()
+	for _, role := range rolesToCheck {
+		for _, delegation := range role.Delegations.Roles {
+			for _, keyID := range delegation.KeyIDs {
+				operations, restricted := delegation.KeyOperations[keyID]
+				if !restricted {
+					unrestrictedKeyIDs.Add(keyID)
+					continue
+				}
+				for _, operation := range operations {
+					if !slices.Contains(keyOperations[keyID], operation) {
+						keyOperations[keyID] = append(keyOperations[keyID], operation)
+					}
+				}
+			}
+		}
+	}
+	for _, keyID := range unrestrictedKeyIDs.Contents() {
+		delete(keyOperations, keyID)
+	}
+
+	rotations, err := s.getKeyRotations()
+	if err != nil {
+		return nil, err
+	}
+	for _, rotation := range rotations {
+		if operations, restricted := keyOperations[rotation.OldKey.KeyID]; restricted {
+			delete(keyOperations, rotation.OldKey.KeyID)
+			keyOperations[rotation.NewKey.KeyID] = operations
+		}
+	}
+
+	return keyOperations, nil
+}
+
 // FindPublicKeysForPath identifies the trusted keys for the path. If the path
 // protected in gittuf policy, the trusted keys are returned.
 //
@@ -291,14 +454,17 @@ func (s *State) FindPublicKeysForPath(ctx context.Context, path string) ([]*tuf.
 // specified path. While walking the delegation graph for the path, signatures
 // for delegated metadata files are verified using the verifier context.
 func (s *State) FindVerifiersForPath(path string) ([]*Verifier, error) {
+	s.verifiersCacheMu.Lock()
 	if s.verifiersCache == nil {
 		slog.Debug("Initializing path cache in policy...")
 		s.verifiersCache = map[string][]*Verifier{}
 	} else if verifiers, cacheHit := s.verifiersCache[path]; cacheHit {
 		// Cache hit for this path in this policy
 		slog.Debug(fmt.Sprintf("Found cached verifiers for path '%s'", path))
+		s.verifiersCacheMu.Unlock()
 		return verifiers, nil
 	}
+	s.verifiersCacheMu.Unlock()
 
 	if !s.HasTargetsRole(TargetsRoleName) {
 		// No policies exist
@@ -307,12 +473,13 @@ func (s *State) FindVerifiersForPath(path string) ([]*Verifier, error) {
 
 	// This envelope is verified when state is loaded, as this is
 	// the start for all delegation graph searches
-	targetsMetadata, err := s.GetTargetsMetadata(TargetsRoleName)
+	targetsMetadata, err := s.getTargetsMetadata(TargetsRoleName)
 	if err != nil {
 		return nil, err
 	}
 
-	allPublicKeys := targetsMetadata.Delegations.Keys
+	// The metadata is shared via the payload cache, so we must not modify it
+	allPublicKeys := maps.Clone(targetsMetadata.Delegations.Keys)
 	// each entry is a list of delegations from a particular metadata file
 	groupedDelegations := [][]tuf.Delegation{
 		targetsMetadata.Delegations.Roles,
@@ -324,7 +491,9 @@ func (s *State) FindVerifiersForPath(path string) ([]*Verifier, error) {
 	verifiers := []*Verifier{}
 	for {
 		if len(groupedDelegations) == 0 {
+			s.verifiersCacheMu.Lock()
 			s.verifiersCache[path] = verifiers
+			s.verifiersCacheMu.Unlock()
 			return verifiers, nil
 		}
 
@@ -342,14 +511,27 @@ func (s *State) FindVerifiersForPath(path string) ([]*Verifier, error) {
 
 			if delegation.Matches(path) {
 				verifier := &Verifier{
-					name:      delegation.Name,
-					keys:      make([]*tuf.Key, 0, len(delegation.KeyIDs)),
-					threshold: delegation.Threshold,
+					name:               delegation.Name,
+					keys:               make([]*tuf.Key, 0, len(delegation.KeyIDs)),
+					threshold:          delegation.Threshold,
+					keyOperations:      delegation.KeyOperations,
+					rotation:           delegation.Rotation,
+					requireTicket:      delegation.RequireTicket,
+					allowedBuilders:    delegation.AllowedBuilders,
+					allowedUpdateTypes: delegation.AllowedUpdateTypes,
 				}
 				for _, keyID := range delegation.KeyIDs {
 					key := allPublicKeys[keyID]
 					verifier.keys = append(verifier.keys, key)
 				}
+				verifier, err = s.applyKeyRotationsToVerifier(verifier)
+				if err != nil {
+					return nil, err
+				}
+				verifier.keys, err = s.filterAuthorizedKeys(verifier.keys)
+				if err != nil {
+					return nil, err
+				}
 				verifiers = append(verifiers, verifier)
 
 				if _, seen := seenRoles[delegation.Name]; seen {
@@ -357,7 +539,7 @@ func (s *State) FindVerifiersForPath(path string) ([]*Verifier, error) {
 				}
 
 				if s.HasTargetsRole(delegation.Name) {
-					delegatedMetadata, err := s.GetTargetsMetadata(delegation.Name)
+					delegatedMetadata, err := s.getTargetsMetadata(delegation.Name)
 					if err != nil {
 						return nil, err
 					}
@@ -419,7 +601,7 @@ func (s *State) Verify(ctx context.Context) error {
 		return err
 	}
 
-	targetsMetadata, err := s.GetTargetsMetadata(TargetsRoleName)
+	targetsMetadata, err := s.getTargetsMetadata(TargetsRoleName)
 	if err != nil {
 		return err
 	}
@@ -429,8 +611,9 @@ func (s *State) Verify(ctx context.Context) error {
 		reachedDelegations[delegatedRoleName] = false
 	}
 
-	delegationsQueue := targetsMetadata.Delegations.Roles
-	delegationKeys := targetsMetadata.Delegations.Keys
+	// The metadata is shared via the payload cache, so we must not modify it
+	delegationsQueue := slices.Clone(targetsMetadata.Delegations.Roles)
+	delegationKeys := maps.Clone(targetsMetadata.Delegations.Keys)
 	for {
 		// The last entry in the queue is always the allow rule, which we don't
 		// process during DFS
@@ -450,6 +633,10 @@ func (s *State) Verify(ctx context.Context) error {
 			for _, keyID := range delegation.KeyIDs {
 				keys = append(keys, delegationKeys[keyID])
 			}
+			keys, err = s.applyKeyRotations(keys)
+			if err != nil {
+				return err
+			}
 
 			verifier := &Verifier{
 				name:      delegation.Name,
@@ -461,12 +648,12 @@ func (s *State) Verify(ctx context.Context) error {
 				return err
 			}
 
-			delegatedMetadata, err := s.GetTargetsMetadata(delegation.Name)
+			delegatedMetadata, err := s.getTargetsMetadata(delegation.Name)
 			if err != nil {
 				return err
 			}
 
-			delegationsQueue = append(delegatedMetadata.Delegations.Roles, delegationsQueue...)
+			delegationsQueue = append(slices.Clone(delegatedMetadata.Delegations.Roles), delegationsQueue...)
 			for keyID, key := range delegatedMetadata.Delegations.Keys {
 				delegationKeys[keyID] = key
 			}
@@ -489,6 +676,10 @@ func (s *State) Commit(repo *git.Repository, commitMessage string, signCommit bo
 		commitMessage = DefaultCommitMessage
 	}
 
+	if err := s.checkKeyPolicy(); err != nil {
+		return err
+	}
+
 	metadata := map[string]*sslibdsse.Envelope{}
 	metadata[RootRoleName] = s.RootEnvelope
 	if s.TargetsEnvelope != nil {
@@ -636,6 +827,21 @@ func Apply(ctx context.Context, repo *git.Repository, signRSLEntry bool) error {
 		return fmt.Errorf("staged policy is invalid: %w", err)
 	}
 
+	// Check that the staged changes are approved as required by the applied
+	// policy, so that the change is accepted during verification
+	appliedState, err := LoadCurrentState(ctx, repo, PolicyRef)
+	if err == nil {
+		attestationsState, err := attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			return err
+		}
+		if err := appliedState.VerifyPolicyApproval(ctx, repo, attestationsState, state, policyRef.Hash(), policyStagingCommit.TreeHash); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, ErrPolicyNotFound) {
+		return fmt.Errorf("failed to load applied policy: %w", err)
+	}
+
 	// Update the reference for the base to point to the new commit
 	newPolicyRef := plumbing.NewHashReference(PolicyRef, policyStagingRef.Hash())
 	if err := repo.Storer.SetReference(newPolicyRef); err != nil {
@@ -650,7 +856,7 @@ func Apply(ctx context.Context, repo *git.Repository, signRSLEntry bool) error {
 }
 
 func (s *State) GetRootKeys() ([]*tuf.Key, error) {
-	rootMetadata, err := s.GetRootMetadata()
+	rootMetadata, err := s.getRootMetadata()
 	if err != nil {
 		return nil, err
 	}
@@ -683,6 +889,15 @@ func (s *State) GetRootMetadata() (*tuf.RootMetadata, error) {
 	return rootMetadata, nil
 }
 
+// getRootMetadata returns the deserialized payload of the State's RootEnvelope
+// from the payload cache. The returned metadata is shared and must not be
+// modified; GetRootMetadata must be used to obtain a copy that can be updated.
+func (s *State) getRootMetadata() (*tuf.RootMetadata, error) {
+	return dsse.DecodePayload[tuf.RootMetadata](payloadCache, s.RootEnvelope, nil)
+}
+
+// GetTargetsMetadata returns the deserialized payload of the envelope for the
+// specified targets role.
 func (s *State) GetTargetsMetadata(roleName string) (*tuf.TargetsMetadata, error) {
 	e := s.TargetsEnvelope
 	if roleName != TargetsRoleName {
@@ -710,6 +925,29 @@ func (s *State) GetTargetsMetadata(roleName string) (*tuf.TargetsMetadata, error
 	return targetsMetadata, nil
 }
 
+// getTargetsMetadata returns the deserialized and validated payload of the
+// envelope for the specified targets role from the payload cache. The returned
+// metadata is shared and must not be modified; GetTargetsMetadata must be used
+// to obtain a copy that can be updated.
+func (s *State) getTargetsMetadata(roleName string) (*tuf.TargetsMetadata, error) {
+	e := s.TargetsEnvelope
+	if roleName != TargetsRoleName {
+		env, ok := s.DelegationEnvelopes[roleName]
+		if !ok {
+			return nil, ErrMetadataNotFound
+		}
+		e = env
+	}
+
+	if e == nil {
+		return nil, ErrMetadataNotFound
+	}
+
+	return dsse.DecodePayload(payloadCache, e, func(targetsMetadata *tuf.TargetsMetadata) error {
+		return targetsMetadata.Validate()
+	})
+}
+
 func (s *State) HasTargetsRole(roleName string) bool {
 	if roleName == TargetsRoleName {
 		return s.TargetsEnvelope != nil
@@ -730,7 +968,7 @@ func (s *State) loadRuleNames() error {
 
 	s.ruleNames = set.NewSet[string]()
 
-	targetsMetadata, err := s.GetTargetsMetadata(TargetsRoleName)
+	targetsMetadata, err := s.getTargetsMetadata(TargetsRoleName)
 	if err != nil {
 		return err
 	}
@@ -752,7 +990,7 @@ func (s *State) loadRuleNames() error {
 	}
 
 	for delegatedRoleName := range s.DelegationEnvelopes {
-		delegatedMetadata, err := s.GetTargetsMetadata(delegatedRoleName)
+		delegatedMetadata, err := s.getTargetsMetadata(delegatedRoleName)
 		if err != nil {
 			return err
 		}
@@ -782,11 +1020,18 @@ func ListRules(ctx context.Context, repo *git.Repository, targetRef string) ([]*
 		return nil, err
 	}
 
-	if !state.HasTargetsRole(TargetsRoleName) {
+	return state.ListRules()
+}
+
+// ListRules returns the rules in the state as an array of the delegations in a
+// pre order traversal of the delegation tree, with the depth of each
+// delegation.
+func (s *State) ListRules() ([]*DelegationWithDepth, error) {
+	if !s.HasTargetsRole(TargetsRoleName) {
 		return nil, nil
 	}
 
-	topLevelTargetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	topLevelTargetsMetadata, err := s.GetTargetsMetadata(TargetsRoleName)
 	if err != nil {
 		return nil, err
 	}
@@ -814,8 +1059,8 @@ func ListRules(ctx context.Context, repo *git.Repository, targetRef string) ([]*
 			continue
 		}
 
-		if state.HasTargetsRole(currentDelegation.Delegation.Name) {
-			currentMetadata, err := state.GetTargetsMetadata(currentDelegation.Delegation.Name)
+		if s.HasTargetsRole(currentDelegation.Delegation.Name) {
+			currentMetadata, err := s.GetTargetsMetadata(currentDelegation.Delegation.Name)
 			if err != nil {
 				return nil, err
 			}
@@ -852,7 +1097,7 @@ func (s *State) hasFileRule() (bool, error) {
 		return false, nil
 	}
 
-	targetsRole, err := s.GetTargetsMetadata(TargetsRoleName)
+	targetsRole, err := s.getTargetsMetadata(TargetsRoleName)
 	if err != nil {
 		return false, err
 	}
@@ -862,7 +1107,7 @@ func (s *State) hasFileRule() (bool, error) {
 	// This doesn't consider whether a delegated role is reachable because we
 	// don't know what artifact path this is for
 	for roleName := range s.DelegationEnvelopes {
-		delegatedRole, err := s.GetTargetsMetadata(roleName)
+		delegatedRole, err := s.getTargetsMetadata(roleName)
 		if err != nil {
 			return false, err
 		}
@@ -887,7 +1132,7 @@ func (s *State) hasFileRule() (bool, error) {
 }
 
 func (s *State) getRootVerifier() (*Verifier, error) {
-	rootMetadata, err := s.GetRootMetadata()
+	rootMetadata, err := s.getRootMetadata()
 	if err != nil {
 		return nil, err
 	}
@@ -899,7 +1144,7 @@ func (s *State) getRootVerifier() (*Verifier, error) {
 }
 
 func (s *State) getTargetsVerifier() (*Verifier, error) {
-	rootMetadata, err := s.GetRootMetadata()
+	rootMetadata, err := s.getRootMetadata()
 	if err != nil {
 		return nil, err
 	}
@@ -982,6 +1227,10 @@ func verifySuccessiveRootsAndLoadLatestPolicyState(ctx context.Context, repo *gi
 			return nil, err
 		}
 
+		if err := verifyPolicyEntryApproval(ctx, repo, verifiedState, underTestState, entry); err != nil {
+			return nil, err
+		}
+
 		verifiedState = underTestState
 	}
 
@@ -994,6 +1243,8 @@ func verifySuccessiveRootsAndLoadLatestPolicyState(ctx context.Context, repo *gi
 // must be used. The exception is VerifyRelative... which performs root
 // verification between consecutive policy states.
 func loadStateForEntry(repo *git.Repository, entry *rsl.ReferenceEntry) (*State, error) {
+	defer perf.Track(perf.PolicyLoading)()
+
 	if entry.RefName != PolicyRef && entry.RefName != PolicyStagingRef {
 		return nil, rsl.ErrRSLEntryDoesNotMatchRef
 	}
@@ -1105,3 +1356,21 @@ func verifyRootKeysMatch(keys1, keys2 []*tuf.Key) bool {
 
 	return reflect.DeepEqual(keys1, keys2)
 }
+
+// IsPolicyTree returns true if the tree has the layout of a policy state, i.e.,
+// it only contains the metadata and root public keys trees, and the metadata
+// tree contains the root of trust's metadata.
+func IsPolicyTree(tree *object.Tree) bool {
+	if len(tree.Entries) == 0 || len(tree.Entries) > 2 {
+		return false
+	}
+
+	for _, e := range tree.Entries {
+		if e.Name != metadataTreeEntryName && e.Name != rootPublicKeysTreeEntryName {
+			return false
+		}
+	}
+
+	_, err := tree.File(path.Join(metadataTreeEntryName, fmt.Sprintf("%s.json", RootRoleName)))
+	return err == nil
+}
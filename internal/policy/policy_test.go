@@ -268,6 +268,17 @@ func TestLoadCurrentState(t *testing.T) {
 		t.Error(err)
 	}
 	assert.Equal(t, state, loadedState)
+
+	t.Run("policy not initialized", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = LoadCurrentState(context.Background(), repo, PolicyRef)
+		assert.ErrorIs(t, err, ErrPolicyNotFound)
+		assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
+	})
 }
 
 func TestLoadFirstState(t *testing.T) {
@@ -411,6 +422,33 @@ func TestStateGetRootMetadata(t *testing.T) {
 	assert.Equal(t, "52e3b8e73279d6ebdd62a5016e2725ff284f569665eb92ccb145d83817a02997", rootMetadata.Roles[RootRoleName].KeyIDs[0])
 }
 
+func TestStateGetTargetsMetadataCached(t *testing.T) {
+	state := createTestStateWithDelegatedPolicies(t)
+
+	cachedMetadata, err := state.getTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cachedKeys := len(cachedMetadata.Delegations.Keys)
+
+	// Cached metadata is shared across reads
+	cachedMetadataAgain, err := state.getTargetsMetadata(TargetsRoleName)
+	assert.Nil(t, err)
+	assert.Same(t, cachedMetadata, cachedMetadataAgain)
+
+	// Exported metadata is always a copy that callers can update
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	assert.Nil(t, err)
+	assert.NotSame(t, cachedMetadata, targetsMetadata)
+	assert.Equal(t, cachedMetadata, targetsMetadata)
+
+	// Walking the delegation graph must not modify the cached metadata
+	_, err = state.FindVerifiersForPath("git:refs/heads/main")
+	assert.Nil(t, err)
+	assert.Nil(t, state.Verify(testCtx))
+	assert.Equal(t, cachedKeys, len(cachedMetadata.Delegations.Keys))
+}
+
 func TestStateFindVerifiersForPath(t *testing.T) {
 	t.Run("with policy", func(t *testing.T) {
 		state := createTestStateWithPolicy(t)
@@ -495,6 +533,63 @@ func TestStateFindPublicKeysForPath(t *testing.T) {
 	}
 }
 
+func TestLoadStateForEntryID(t *testing.T) {
+	repo, firstState := createTestRepository(t, createTestStateWithPolicy)
+
+	policyEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, PolicyRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := LoadStateForEntryID(context.Background(), repo, policyEntry.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, firstState, state)
+
+	refName := "refs/heads/main"
+	emptyTreeHash, err := gitinterface.WriteTree(repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitID, err := gitinterface.Commit(repo, emptyTreeHash, refName, "Initial commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rsl.NewReferenceEntry(refName, commitID).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := rsl.GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state, err = LoadStateForEntryID(context.Background(), repo, entry.GetID())
+	assert.Nil(t, err)
+	assert.Equal(t, firstState, state)
+
+	_, err = LoadStateForEntryID(context.Background(), repo, commitID)
+	assert.ErrorIs(t, err, rsl.ErrInvalidRSLEntry)
+
+	t.Run("no policy before entry", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rsl.InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+		if err := rsl.NewReferenceEntry(refName, plumbing.ZeroHash).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+		entry, err := rsl.GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = LoadStateForEntryID(context.Background(), repo, entry.GetID())
+		assert.ErrorIs(t, err, ErrPolicyNotFound)
+	})
+}
+
 func TestGetStateForCommit(t *testing.T) {
 	repo, firstState := createTestRepository(t, createTestStateWithPolicy)
 
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/common"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var (
+	ErrInvalidPolicyApprovalThreshold = errors.New("policy approval threshold must not be negative")
+	ErrPolicyChangeNotApproved        = errors.New("policy change is not approved by the required number of policy administrators")
+)
+
+// SetPolicyApprovalThreshold sets the number of policy administrators, i.e.,
+// holders of root and top-level targets keys, who must approve a change to the
+// policy before it takes effect. A threshold of 0 removes the requirement.
+func SetPolicyApprovalThreshold(rootMetadata *tuf.RootMetadata, threshold int) (*tuf.RootMetadata, error) {
+	if rootMetadata == nil {
+		return nil, ErrRootMetadataNil
+	}
+
+	if threshold < 0 {
+		return nil, ErrInvalidPolicyApprovalThreshold
+	}
+
+	if len(getPolicyAdministratorKeys(rootMetadata)) < threshold {
+		return nil, ErrCannotMeetThreshold
+	}
+
+	rootMetadata.PolicyApprovalThreshold = threshold
+	return rootMetadata, nil
+}
+
+// VerifyPolicyApproval checks that the change from the state to newState is
+// approved by the state's threshold of policy administrators. An administrator
+// approves the change by signing the changed root or top-level targets
+// metadata in newState, or by signing a reference authorization for the policy
+// ref from fromID, the policy commit the state was recorded in, to
+// newStateTreeID, the tree of the policy commit recording newState.
+// Signatures on metadata that is unchanged were made for an earlier state, and
+// are therefore not counted.
+func (s *State) VerifyPolicyApproval(ctx context.Context, repo *git.Repository, attestationsState *attestations.Attestations, newState *State, fromID, newStateTreeID plumbing.Hash) error {
+	rootMetadata, err := s.GetRootMetadata()
+	if err != nil {
+		return err
+	}
+
+	if rootMetadata.PolicyApprovalThreshold < 2 {
+		return nil
+	}
+
+	approvers := set.NewSet[string]()
+
+	if newState.RootEnvelope != nil && (s.RootEnvelope == nil || newState.RootEnvelope.Payload != s.RootEnvelope.Payload) {
+		if err := addApprovingKeyIDs(ctx, approvers, newState.RootEnvelope, getRoleKeys(rootMetadata, RootRoleName)); err != nil {
+			return err
+		}
+	}
+
+	if newState.TargetsEnvelope != nil && (s.TargetsEnvelope == nil || newState.TargetsEnvelope.Payload != s.TargetsEnvelope.Payload) {
+		if err := addApprovingKeyIDs(ctx, approvers, newState.TargetsEnvelope, getRoleKeys(rootMetadata, TargetsRoleName)); err != nil {
+			return err
+		}
+	}
+
+	if attestationsState != nil {
+		authorization, err := attestationsState.GetReferenceAuthorizationFor(repo, PolicyRef, fromID.String(), newStateTreeID.String())
+		if err == nil {
+			if err := addApprovingKeyIDs(ctx, approvers, authorization, getPolicyAdministratorKeys(rootMetadata)); err != nil {
+				return err
+			}
+		} else if !errors.Is(err, attestations.ErrAuthorizationNotFound) {
+			return err
+		}
+	}
+
+	slog.Debug(fmt.Sprintf("Found approvals from %d of %d required policy administrators", approvers.Len(), rootMetadata.PolicyApprovalThreshold))
+	if approvers.Len() < rootMetadata.PolicyApprovalThreshold {
+		return fmt.Errorf("%w: found %d of %d approvals", ErrPolicyChangeNotApproved, approvers.Len(), rootMetadata.PolicyApprovalThreshold)
+	}
+
+	return nil
+}
+
+// verifyPolicyEntryApproval checks that the policy state recorded in the RSL
+// entry for the policy ref is approved as required by trustedState, the
+// policy state recorded in the previous entry for the policy ref. Approvals
+// recorded as attestations after the entry are not considered.
+func verifyPolicyEntryApproval(ctx context.Context, repo *git.Repository, trustedState, newState *State, entry *rsl.ReferenceEntry) error {
+	rootMetadata, err := trustedState.GetRootMetadata()
+	if err != nil {
+		return err
+	}
+
+	if rootMetadata.PolicyApprovalThreshold < 2 {
+		return nil
+	}
+
+	priorEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, PolicyRef, entry.ID)
+	if err != nil {
+		return err
+	}
+
+	var attestationsState *attestations.Attestations
+	attestationsEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, attestations.Ref, entry.ID)
+	if err == nil {
+		attestationsState, err = attestations.LoadAttestationsForEntry(repo, attestationsEntry)
+		if err != nil {
+			return err
+		}
+	} else if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return err
+	}
+
+	newStateCommit, err := gitinterface.GetCommit(repo, entry.TargetID)
+	if err != nil {
+		return err
+	}
+
+	if err := trustedState.VerifyPolicyApproval(ctx, repo, attestationsState, newState, priorEntry.TargetID, newStateCommit.TreeHash); err != nil {
+		return fmt.Errorf("policy entry '%s': %w", entry.ID.String(), err)
+	}
+
+	return nil
+}
+
+// addApprovingKeyIDs adds the IDs of the keys that signed the envelope to
+// approvers. Keys that cannot verify DSSE signatures, such as GPG keys, are
+// ignored.
+func addApprovingKeyIDs(ctx context.Context, approvers *set.Set[string], env *sslibdsse.Envelope, keys []*tuf.Key) error {
+	for _, key := range keys {
+		verifier, err := newDSSEVerifier(key)
+		if err != nil {
+			if errors.Is(err, common.ErrUnknownKeyType) {
+				continue
+			}
+			return err
+		}
+
+		if _, err := dsse.VerifyEnvelopeAndGetKeyIDs(ctx, env, []sslibdsse.Verifier{verifier}, 1); err == nil {
+			approvers.Add(key.KeyID)
+		}
+	}
+
+	return nil
+}
+
+// getPolicyAdministratorKeys returns the keys of the root and top-level
+// targets roles.
+func getPolicyAdministratorKeys(rootMetadata *tuf.RootMetadata) []*tuf.Key {
+	keys := getRoleKeys(rootMetadata, RootRoleName)
+	for _, key := range getRoleKeys(rootMetadata, TargetsRoleName) {
+		if !slices.ContainsFunc(keys, func(k *tuf.Key) bool { return k.KeyID == key.KeyID }) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// getRoleKeys returns the keys of the role in the root metadata.
+func getRoleKeys(rootMetadata *tuf.RootMetadata, roleName string) []*tuf.Key {
+	keys := []*tuf.Key{}
+	for _, keyID := range rootMetadata.Roles[roleName].KeyIDs {
+		if key, has := rootMetadata.Keys[keyID]; has {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
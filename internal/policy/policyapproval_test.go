@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetPolicyApprovalThreshold(t *testing.T) {
+	rootKey, err := tuf.LoadKeyFromBytes(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsKey, err := tuf.LoadKeyFromBytes(targets1KeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(rootKey)
+
+	_, err = SetPolicyApprovalThreshold(rootMetadata, 2)
+	assert.ErrorIs(t, err, ErrCannotMeetThreshold)
+
+	// The same key in the root and targets roles is one administrator
+	rootMetadata, err = AddTargetsKey(rootMetadata, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = SetPolicyApprovalThreshold(rootMetadata, 2)
+	assert.ErrorIs(t, err, ErrCannotMeetThreshold)
+
+	rootMetadata, err = AddTargetsKey(rootMetadata, targetsKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err = SetPolicyApprovalThreshold(rootMetadata, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, rootMetadata.PolicyApprovalThreshold)
+
+	rootMetadata, err = SetPolicyApprovalThreshold(rootMetadata, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, rootMetadata.PolicyApprovalThreshold)
+
+	_, err = SetPolicyApprovalThreshold(rootMetadata, -1)
+	assert.ErrorIs(t, err, ErrInvalidPolicyApprovalThreshold)
+
+	_, err = SetPolicyApprovalThreshold(nil, 1)
+	assert.ErrorIs(t, err, ErrRootMetadataNil)
+}
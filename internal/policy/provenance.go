@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var ErrProvenanceRequired = errors.New("tag does not have SLSA provenance from an allowed builder")
+
+// verifyTagProvenance checks that the tag in the entry has SLSA provenance
+// from one of the builders allowed by each rule protecting the tag that
+// allows specific builders. The provenance must be signed by one of the rule's
+// keys, and may be attached to the tag or to the commit it points to. As
+// provenance is typically produced by builds triggered once the tag is pushed,
+// the repository's current attestations are used rather than those at the
+// tag's entry.
+func verifyTagProvenance(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry) error {
+	verifiers, err := policy.FindVerifiersForPath(fmt.Sprintf("%s:%s", gitReferenceRuleScheme, entry.RefName))
+	if err != nil {
+		return err
+	}
+
+	var envelopes []*sslibdsse.Envelope
+	for _, verifier := range verifiers {
+		if len(verifier.allowedBuilders) == 0 {
+			continue
+		}
+
+		if envelopes == nil {
+			envelopes, err = getProvenanceAttestationsForTag(repo, entry)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !hasProvenanceFromAllowedBuilder(ctx, verifier, envelopes) {
+			return fmt.Errorf("%w: rule '%s' requires provenance for '%s' from one of '%s'", ErrProvenanceRequired, verifier.name, entry.RefName, strings.Join(verifier.allowedBuilders, "', '"))
+		}
+	}
+
+	return nil
+}
+
+// getProvenanceAttestationsForTag returns the provenance attestations attached
+// to the tag object in the entry, and to the commit the tag points to.
+func getProvenanceAttestationsForTag(repo *git.Repository, entry *rsl.ReferenceEntry) ([]*sslibdsse.Envelope, error) {
+	attestationsState, err := attestations.LoadCurrentAttestations(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	objectIDs := []string{entry.TargetID.String()}
+	if tag, err := gitinterface.GetTag(repo, entry.TargetID); err == nil {
+		objectIDs = append(objectIDs, tag.Target.String())
+	}
+
+	envelopes := []*sslibdsse.Envelope{}
+	for _, objectID := range objectIDs {
+		objectEnvelopes, err := attestationsState.GetProvenanceAttestationsFor(repo, objectID)
+		if err != nil {
+			if errors.Is(err, attestations.ErrProvenanceAttestationNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		envelopes = append(envelopes, objectEnvelopes...)
+	}
+
+	return envelopes, nil
+}
+
+// hasProvenanceFromAllowedBuilder returns true if one of the envelopes records
+// a builder allowed by the verifier and is signed by one of its keys.
+func hasProvenanceFromAllowedBuilder(ctx context.Context, verifier *Verifier, envelopes []*sslibdsse.Envelope) bool {
+	keyVerifier := &Verifier{name: verifier.name, keys: verifier.keys, threshold: 1, keyOperations: verifier.keyOperations, rotation: verifier.rotation}
+
+	for _, env := range envelopes {
+		builderID, err := attestations.GetProvenanceBuilderID(env)
+		if err != nil || !slices.Contains(verifier.allowedBuilders, builderID) {
+			continue
+		}
+
+		if err := keyVerifier.Verify(ctx, nil, env); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
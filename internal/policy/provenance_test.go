@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+const testBuilderID = "https://github.com/gittuf/gittuf/.github/workflows/release.yml@refs/tags/v1"
+
+func TestVerifyTagProvenance(t *testing.T) {
+	tagName := "v1"
+	tagRef := string(plumbing.NewTagReferenceName(tagName))
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets1KeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	untrustedSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets2KeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		builderID     string
+		signer        sslibdsse.SignerVerifier
+		attachToTag   bool
+		expectedError error
+	}{
+		"no provenance": {
+			expectedError: ErrProvenanceRequired,
+		},
+		"provenance for commit from allowed builder": {
+			builderID: testBuilderID,
+			signer:    signer,
+		},
+		"provenance for tag from allowed builder": {
+			builderID:   testBuilderID,
+			signer:      signer,
+			attachToTag: true,
+		},
+		"provenance from other builder": {
+			builderID:     "https://gitlab.com/gittuf/gittuf/-/runners/1",
+			signer:        signer,
+			expectedError: ErrProvenanceRequired,
+		},
+		"provenance not signed by rule's key": {
+			builderID:     testBuilderID,
+			signer:        untrustedSigner,
+			expectedError: ErrProvenanceRequired,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo, state := createTestRepository(t, createTestStateWithProvenancePolicy)
+
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, "refs/heads/main", 1, gpgKeyBytes)
+			tagID := common.CreateTestSignedTag(t, repo, tagName, commitIDs[0], gpgKeyBytes)
+
+			entry := rsl.NewReferenceEntry(tagRef, tagID)
+			entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+			if test.signer != nil {
+				env := common.CreateTestSLSAProvenanceEnvelope(t, test.builderID, commitIDs[0])
+				env, err := dsse.SignEnvelope(testCtx, env, test.signer)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				objectID := commitIDs[0]
+				if test.attachToTag {
+					objectID = tagID
+				}
+
+				currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := currentAttestations.SetProvenanceAttestation(repo, env, objectID.String()); err != nil {
+					t.Fatal(err)
+				}
+				if err := currentAttestations.Commit(repo, "Add provenance", false); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := verifyTagProvenance(testCtx, repo, state, entry)
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+			} else {
+				assert.Nil(t, err)
+			}
+
+			_, _, err = VerifyTagRef(testCtx, repo, tagRef)
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+
+	t.Run("tag without provenance requirement", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithTagPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, "refs/heads/main", 1, gpgKeyBytes)
+		tagID := common.CreateTestSignedTag(t, repo, tagName, commitIDs[0], gpgKeyBytes)
+
+		entry := rsl.NewReferenceEntry(tagRef, tagID)
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		err := verifyTagProvenance(testCtx, repo, state, entry)
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// recordedBeforeCache holds the trusted upper bounds on when RSL entries were
+// recorded, found by walking the RSL back from its latest entry. The walk is
+// resumed for entries that precede the ones visited so far.
+type recordedBeforeCache struct {
+	mu sync.Mutex
+
+	// trustedKeys are the keys whose signatures on RSL entries are trusted to
+	// bound when earlier entries were recorded
+	trustedKeys []*tuf.Key
+
+	// bounds maps the IDs of visited entries to their bounds, the zero time
+	// if no later entry is signed using a trusted key
+	bounds map[plumbing.Hash]time.Time
+
+	// next is the entry the walk resumes from, and nextBound its bound
+	next      rsl.Entry
+	nextBound time.Time
+	walked    bool
+}
+
+// getRecordedBefore returns a trusted upper bound on when the RSL entry was
+// recorded. The committer time of an entry is set by whoever signed it, so an
+// entry's own timestamp can't be trusted to be in the past when its signing
+// key may have leaked. Instead, the bound is the earliest committer time of
+// the later RSL entries signed using keys in the policy. Automation keys and
+// GPG keys that have since expired aren't trusted for this. If no later entry
+// is signed using a trusted key, the entry may have been recorded just now, so
+// the current time is returned.
+func (s *State) getRecordedBefore(ctx context.Context, repo *git.Repository, entryID plumbing.Hash) (time.Time, error) {
+	cache := &s.recordedBefore
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.bounds == nil {
+		allKeys, err := s.PublicKeys()
+		if err != nil {
+			return time.Time{}, err
+		}
+		trustedKeys := make([]*tuf.Key, 0, len(allKeys))
+		for _, key := range allKeys {
+			trustedKeys = append(trustedKeys, key)
+		}
+		trustedKeys, err = s.filterAuthorizedKeys(trustedKeys)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		latestEntry, err := rsl.GetLatestEntry(repo)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		cache.trustedKeys = trustedKeys
+		cache.bounds = map[plumbing.Hash]time.Time{}
+		cache.next = latestEntry
+	}
+
+	for {
+		if bound, has := cache.bounds[entryID]; has {
+			if bound.IsZero() {
+				return time.Now(), nil
+			}
+			return bound, nil
+		}
+
+		if cache.walked {
+			return time.Time{}, rsl.ErrRSLEntryNotFound
+		}
+
+		if err := cache.visitNext(ctx, repo); err != nil {
+			return time.Time{}, err
+		}
+	}
+}
+
+// visitNext records the bound for the next entry of the walk and moves the
+// walk to its parent. The caller must hold the lock.
+func (c *recordedBeforeCache) visitNext(ctx context.Context, repo *git.Repository) error {
+	entry := c.next
+	c.bounds[entry.GetID()] = c.nextBound
+
+	commitObj, err := gitinterface.GetCommit(repo, entry.GetID())
+	if err != nil {
+		return err
+	}
+	for _, key := range c.trustedKeys {
+		if err := gitinterface.VerifyCommitSignature(ctx, commitObj, key); err == nil {
+			if c.nextBound.IsZero() || commitObj.Committer.When.Before(c.nextBound) {
+				c.nextBound = commitObj.Committer.When
+			}
+			break
+		}
+	}
+
+	parentEntry, err := rsl.GetParentForEntry(repo, entry)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			c.walked = true
+			return nil
+		}
+		return err
+	}
+	c.next = parentEntry
+
+	return nil
+}
+
+// activeVerifierForEntry returns the verifier with only the keys authorized by
+// its rotation schedule when the entry was recorded. The schedule is evaluated
+// at the trusted time the entry was recorded before, as the entry's own
+// timestamp is set by whoever signed it. If the verifier doesn't have a
+// rotation schedule, it is returned as is.
+func (s *State) activeVerifierForEntry(ctx context.Context, repo *git.Repository, verifier *Verifier, entryID plumbing.Hash) (*Verifier, error) {
+	if verifier.rotation == nil {
+		return verifier, nil
+	}
+
+	recordedBefore, err := s.getRecordedBefore(ctx, repo, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return verifier.ActiveAt(recordedBefore)
+}
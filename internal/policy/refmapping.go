@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+var (
+	ErrInvalidRefMapping     = errors.New("invalid ref mapping")
+	ErrConflictingRefMapping = errors.New("ref is mapped to different upstream refs")
+)
+
+// SetDelegationRefMappings sets the ref name mappings of a delegation in
+// TargetsMetadata, mapping the names of refs on mirrors of the repository to
+// the names of the upstream refs protected by the delegation. Both names must
+// be fully qualified, and the delegation must protect each upstream ref. If
+// mappings is empty, the delegation's mappings are removed.
+func SetDelegationRefMappings(targetsMetadata *tuf.TargetsMetadata, ruleName string, mappings map[string]string) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+
+	for i, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name != ruleName {
+			continue
+		}
+
+		for localRef, upstreamRef := range mappings {
+			if !strings.HasPrefix(localRef, gitinterface.RefPrefix) || !strings.HasPrefix(upstreamRef, gitinterface.RefPrefix) {
+				return nil, fmt.Errorf("%w: '%s' and '%s' must be fully qualified ref names", ErrInvalidRefMapping, localRef, upstreamRef)
+			}
+
+			if !delegation.Matches(fmt.Sprintf("%s:%s", gitReferenceRuleScheme, upstreamRef)) {
+				return nil, fmt.Errorf("%w: '%s' is not protected by rule '%s'", ErrInvalidRefMapping, upstreamRef, ruleName)
+			}
+		}
+
+		if len(mappings) == 0 {
+			mappings = nil
+		}
+
+		delegation.RefMappings = mappings
+		targetsMetadata.Delegations.Roles[i] = delegation
+		return targetsMetadata, nil
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// GetUpstreamRefName returns the name of the upstream ref that the ref on a
+// mirror of the repository is mapped to by the rules in the policy. If the ref
+// isn't mapped, its name is returned as is. If rules map the ref to different
+// upstream refs, ErrConflictingRefMapping is returned.
+func (s *State) GetUpstreamRefName(refName string) (string, error) {
+	if s.TargetsEnvelope == nil {
+		return refName, nil
+	}
+
+	ruleFileNames := []string{TargetsRoleName}
+	for ruleFileName := range s.DelegationEnvelopes {
+		ruleFileNames = append(ruleFileNames, ruleFileName)
+	}
+
+	upstreamRefName := ""
+	for _, ruleFileName := range ruleFileNames {
+		targetsMetadata, err := s.getTargetsMetadata(ruleFileName)
+		if err != nil {
+			return "", err
+		}
+
+		for _, delegation := range targetsMetadata.Delegations.Roles {
+			mappedRefName, isMapped := delegation.RefMappings[refName]
+			if !isMapped {
+				continue
+			}
+
+			if upstreamRefName != "" && upstreamRefName != mappedRefName {
+				return "", fmt.Errorf("%w: '%s' is mapped to '%s' and '%s'", ErrConflictingRefMapping, refName, upstreamRefName, mappedRefName)
+			}
+			upstreamRefName = mappedRefName
+		}
+	}
+
+	if upstreamRefName == "" {
+		return refName, nil
+	}
+
+	return upstreamRefName, nil
+}
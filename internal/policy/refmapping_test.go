@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetDelegationRefMappings(t *testing.T) {
+	targetsMetadata := InitializeTargetsMetadata()
+
+	key, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddDelegation(targetsMetadata, "protect-prod", []*tuf.Key{key}, []string{"git:refs/heads/prod"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = SetDelegationRefMappings(targetsMetadata, "protect-prod", map[string]string{"refs/heads/main": "refs/heads/prod"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"refs/heads/main": "refs/heads/prod"}, targetsMetadata.Delegations.Roles[0].RefMappings)
+
+	_, err = SetDelegationRefMappings(targetsMetadata, "protect-prod", map[string]string{"main": "refs/heads/prod"})
+	assert.ErrorIs(t, err, ErrInvalidRefMapping)
+
+	_, err = SetDelegationRefMappings(targetsMetadata, "protect-prod", map[string]string{"refs/heads/main": "refs/heads/dev"})
+	assert.ErrorIs(t, err, ErrInvalidRefMapping)
+
+	_, err = SetDelegationRefMappings(targetsMetadata, "missing-rule", map[string]string{"refs/heads/main": "refs/heads/prod"})
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetDelegationRefMappings(targetsMetadata, AllowRuleName, nil)
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+
+	targetsMetadata, err = SetDelegationRefMappings(targetsMetadata, "protect-prod", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].RefMappings)
+}
+
+func TestStateGetUpstreamRefName(t *testing.T) {
+	setRefMappings := func(t *testing.T, state *State, ruleName string, mappings map[string]string) {
+		t.Helper()
+
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		targetsMetadata, err = SetDelegationRefMappings(targetsMetadata, ruleName, mappings)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		state.TargetsEnvelope, err = dsse.CreateEnvelope(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("no mappings", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		refName, err := state.GetUpstreamRefName("refs/heads/main")
+		assert.Nil(t, err)
+		assert.Equal(t, "refs/heads/main", refName)
+	})
+
+	t.Run("mapped ref", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+		setRefMappings(t, state, "protect-main", map[string]string{"refs/heads/mirror-main": "refs/heads/main"})
+
+		refName, err := state.GetUpstreamRefName("refs/heads/mirror-main")
+		assert.Nil(t, err)
+		assert.Equal(t, "refs/heads/main", refName)
+
+		refName, err = state.GetUpstreamRefName("refs/heads/feature")
+		assert.Nil(t, err)
+		assert.Equal(t, "refs/heads/feature", refName)
+	})
+
+	t.Run("conflicting mappings", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = AddDelegation(targetsMetadata, "protect-prod", []*tuf.Key{}, []string{"git:refs/heads/prod"}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.TargetsEnvelope, err = dsse.CreateEnvelope(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		setRefMappings(t, state, "protect-main", map[string]string{"refs/heads/mirror": "refs/heads/main"})
+		setRefMappings(t, state, "protect-prod", map[string]string{"refs/heads/mirror": "refs/heads/prod"})
+
+		_, err = state.GetUpstreamRefName("refs/heads/mirror")
+		assert.ErrorIs(t, err, ErrConflictingRefMapping)
+	})
+}
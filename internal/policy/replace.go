@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+)
+
+var (
+	ErrGraftsFound           = errors.New("repository has grafts that alter the history presented by Git, remove info/grafts to proceed")
+	ErrUnprotectedReplaceRef = errors.New("replace ref alters the history presented by Git but is not protected by a gittuf policy rule")
+	ErrReplaceRefNotInRSL    = errors.New("replace ref's current state is not recorded in the RSL")
+)
+
+// VerifyHistoryIntegrity checks that the history Git presents for the
+// repository has not been altered in ways gittuf does not verify. gittuf reads
+// the objects recorded in the RSL, while the Git CLI substitutes replacement
+// objects and applies grafts, which can be used to present alternate history
+// to users of a repository that passes verification.
+//
+// Grafts are deprecated in Git and are always rejected. Replace refs are only
+// accepted if they are explicitly brought under policy control: each replace
+// ref must be protected by a rule in the current policy, its current state must
+// be recorded in the RSL, and its latest RSL entry must be verified using the
+// current policy.
+func VerifyHistoryIntegrity(ctx context.Context, repo *git.Repository) error {
+	slog.Debug("Checking for grafts...")
+	hasGrafts, err := gitinterface.HasGrafts(repo)
+	if err != nil {
+		return err
+	}
+	if hasGrafts {
+		return ErrGraftsFound
+	}
+
+	slog.Debug("Checking for replace refs...")
+	replaceRefs, err := gitinterface.GetReplaceRefs(repo)
+	if err != nil {
+		return err
+	}
+	if len(replaceRefs) == 0 {
+		return nil
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := LoadCurrentState(ctx, repo, PolicyRef)
+	if err != nil {
+		return err
+	}
+
+	refNames := make([]string, 0, len(replaceRefs))
+	for refName := range replaceRefs {
+		refNames = append(refNames, refName)
+	}
+	slices.Sort(refNames)
+
+	for _, refName := range refNames {
+		slog.Debug(fmt.Sprintf("Verifying replace ref '%s'...", refName))
+
+		verifiers, err := state.FindVerifiersForPath(fmt.Sprintf("%s:%s", gitReferenceRuleScheme, refName))
+		if err != nil {
+			return err
+		}
+		if len(verifiers) == 0 {
+			return fmt.Errorf("%w: '%s'", ErrUnprotectedReplaceRef, refName)
+		}
+
+		expectedTip, err := VerifyRef(ctx, repo, refName)
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				return fmt.Errorf("%w: '%s'", ErrReplaceRefNotInRSL, refName)
+			}
+			return fmt.Errorf("unable to verify replace ref '%s': %w", refName, err)
+		}
+
+		if expectedTip != replaceRefs[refName] {
+			return fmt.Errorf("%w: '%s'", ErrReplaceRefNotInRSL, refName)
+		}
+	}
+
+	return nil
+}
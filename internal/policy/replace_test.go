@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyHistoryIntegrity(t *testing.T) {
+	replaceRefName := gitinterface.ReplaceRefPrefix + "1234567890123456789012345678901234567890"
+
+	t.Run("no replace refs", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+		err := VerifyHistoryIntegrity(testCtx, repo)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unprotected replace ref", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, replaceRefName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(replaceRefName, commitIDs[0])
+		common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		err := VerifyHistoryIntegrity(testCtx, repo)
+		assert.ErrorIs(t, err, ErrUnprotectedReplaceRef)
+	})
+
+	t.Run("protected replace ref not in RSL", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithReplacePolicy)
+
+		common.AddNTestCommitsToSpecifiedRef(t, repo, replaceRefName, 1, gpgKeyBytes)
+
+		err := VerifyHistoryIntegrity(testCtx, repo)
+		assert.ErrorIs(t, err, ErrReplaceRefNotInRSL)
+	})
+
+	t.Run("protected replace ref recorded in RSL", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithReplacePolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, replaceRefName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(replaceRefName, commitIDs[0])
+		common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		err := VerifyHistoryIntegrity(testCtx, repo)
+		assert.Nil(t, err)
+
+		// Replace ref is changed without recording it in the RSL
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(replaceRefName), plumbing.NewHash("abcdef1234567890abcdef1234567890abcdef12"))); err != nil {
+			t.Fatal(err)
+		}
+
+		err = VerifyHistoryIntegrity(testCtx, repo)
+		assert.ErrorIs(t, err, ErrReplaceRefNotInRSL)
+	})
+
+	t.Run("protected replace ref with unauthorized entry", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithReplacePolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, replaceRefName, 1, gpgUnauthorizedKeyBytes)
+		entry := rsl.NewReferenceEntry(replaceRefName, commitIDs[0])
+		common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgUnauthorizedKeyBytes)
+
+		err := VerifyHistoryIntegrity(testCtx, repo)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
+}
@@ -4,6 +4,7 @@ package policy
 
 import (
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/gittuf/gittuf/internal/tuf"
@@ -11,6 +12,7 @@ import (
 
 var (
 	ErrCannotMeetThreshold = errors.New("insufficient keys to meet threshold")
+	ErrInvalidThreshold    = errors.New("threshold must be at least 1")
 	ErrRootMetadataNil     = errors.New("rootMetadata is nil")
 	ErrRootKeyNil          = errors.New("root key not found")
 	ErrTargetsMetadataNil  = errors.New("targetsMetadata not found")
@@ -89,6 +91,9 @@ func AddTargetsKey(rootMetadata *tuf.RootMetadata, targetsKey *tuf.Key) (*tuf.Ro
 	if targetsKey == nil {
 		return nil, ErrTargetsKeyNil
 	}
+	if slices.Contains(rootMetadata.Roles[ObserverRoleName].KeyIDs, targetsKey.KeyID) {
+		return nil, ErrKeyIsObserverKey
+	}
 
 	rootMetadata.Keys[targetsKey.KeyID] = targetsKey
 
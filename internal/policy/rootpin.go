@@ -0,0 +1,382 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	// RootPinRef stores the root of trust keys observed when the repository's
+	// policy was first verified. It is local to the repository and is never
+	// recorded in the RSL or pushed to remotes.
+	RootPinRef = "refs/gittuf/root-pin"
+
+	rootPinFileName      = "root-pin.json"
+	rootPinCommitMessage = "Update root of trust pin"
+)
+
+var (
+	ErrRootPinMismatch         = errors.New("root of trust keys do not match pinned keys and were not rotated from them")
+	ErrInitialRootHashMismatch = errors.New("initial root of trust metadata does not match pinned hash")
+	ErrInvalidRootHash         = errors.New("root hash must be a hex encoded SHA-256 hash")
+)
+
+// RootPin records the root of trust keys trusted for a repository.
+type RootPin struct {
+	// RootKeyIDs are the sorted IDs of the root keys.
+	RootKeyIDs []string `json:"rootKeyIDs"`
+
+	// PolicyEntryID is the ID of the RSL entry for the policy state the root
+	// keys were observed in.
+	PolicyEntryID string `json:"policyEntryID"`
+
+	// InitialRootHash is the hash of the root metadata in the repository's
+	// first policy state, as returned by GetInitialRootHash. It is pinned
+	// using a hash obtained out of band, so that the root of trust is not
+	// trusted on first use.
+	InitialRootHash string `json:"initialRootHash,omitempty"`
+}
+
+// LoadRootPin loads the root of trust pin from the repository. If the root has
+// not been pinned yet, nil is returned.
+func LoadRootPin(repo *git.Repository) (*RootPin, error) {
+	ref, err := repo.Reference(plumbing.ReferenceName(RootPinRef), true)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	commit, err := gitinterface.GetCommit(repo, ref.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := gitinterface.GetTree(repo, commit.TreeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := tree.File(rootPinFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := file.Contents()
+	if err != nil {
+		return nil, err
+	}
+
+	pin := &RootPin{}
+	if err := json.Unmarshal([]byte(contents), pin); err != nil {
+		return nil, err
+	}
+
+	return pin, nil
+}
+
+// GetInitialRootHash returns the hex encoded SHA-256 hash of the root metadata
+// in the repository's first policy state. As the root of trust of every later
+// policy state is verified from the first state, the hash identifies the
+// repository's root of trust lineage, and can be shared out of band to
+// bootstrap trust in the repository.
+func GetInitialRootHash(repo *git.Repository) (string, error) {
+	firstEntry, _, err := rsl.GetFirstReferenceEntryForRef(repo, PolicyRef)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return "", fmt.Errorf("%w: %w", ErrPolicyNotFound, err)
+		}
+		return "", err
+	}
+
+	state, err := loadStateForEntry(repo, firstEntry)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := state.RootEnvelope.DecodeB64Payload()
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256(payload)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// PinInitialRoot pins the hash of the root metadata in the repository's first
+// policy state, obtained out of band. Subsequent verifications fail if the
+// repository's initial root metadata doesn't match the hash. If the policy is
+// already available, it is checked against the hash immediately, and the pin
+// is not updated if it doesn't match.
+func PinInitialRoot(repo *git.Repository, rootHash string) error {
+	rootHash = strings.ToLower(rootHash)
+	if decoded, err := hex.DecodeString(rootHash); err != nil || len(decoded) != sha256.Size {
+		return fmt.Errorf("%w: '%s'", ErrInvalidRootHash, rootHash)
+	}
+
+	initialRootHash, err := GetInitialRootHash(repo)
+	if err == nil {
+		if initialRootHash != rootHash {
+			return fmt.Errorf("%w: pinned hash '%s', initial root hash '%s'", ErrInitialRootHashMismatch, rootHash, initialRootHash)
+		}
+	} else if !errors.Is(err, ErrPolicyNotFound) {
+		return err
+	}
+
+	pin, err := LoadRootPin(repo)
+	if err != nil {
+		return err
+	}
+	if pin == nil {
+		pin = &RootPin{}
+	}
+	pin.InitialRootHash = rootHash
+
+	slog.Debug(fmt.Sprintf("Pinning initial root of trust hash '%s'...", rootHash))
+	return pin.Commit(repo)
+}
+
+// ResetRootPin removes the root of trust pin from the repository, so that the
+// root of trust keys are trusted on first use when they're next pinned.
+func ResetRootPin(repo *git.Repository) error {
+	err := repo.Storer.RemoveReference(plumbing.ReferenceName(RootPinRef))
+	if err != nil && !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// Commit writes the root of trust pin to the repository. The commit is never
+// signed as the pin is local state.
+func (p *RootPin) Commit(repo *git.Repository) error {
+	contents, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := gitinterface.WriteBlob(repo, contents)
+	if err != nil {
+		return err
+	}
+
+	treeID, err := gitinterface.NewTreeBuilder(repo).WriteRootTreeFromBlobIDs(map[string]plumbing.Hash{rootPinFileName: blobID})
+	if err != nil {
+		return err
+	}
+
+	_, err = gitinterface.Commit(repo, treeID, RootPinRef, rootPinCommitMessage, false)
+	return err
+}
+
+// VerifyRootPin checks the root of trust keys of the current policy against
+// the keys pinned for the repository, without updating the pin. If no root of
+// trust pin exists, there's nothing to check against, so a warning with the
+// observed root keys is logged and verification is skipped, as a change in the
+// keys can't be detected until they're pinned using PinRootKeys. If the
+// current root keys differ from the pinned keys, they must have
+// been rotated from the pinned keys, i.e., a prior policy state verified as
+// part of the current policy's root of trust chain must use the pinned keys.
+// Otherwise, the root of trust may have been silently swapped, such as by
+// rewriting the policy's history, and ErrRootPinMismatch is returned. If
+// warnOnly is set, the mismatch is logged as a warning instead.
+//
+// If an initial root hash is pinned, the root metadata of the repository's
+// first policy state must match it, and ErrInitialRootHashMismatch is returned
+// otherwise, regardless of warnOnly.
+func VerifyRootPin(ctx context.Context, repo *git.Repository, warnOnly bool) error {
+	slog.Debug("Loading root of trust pin...")
+	pin, err := LoadRootPin(repo)
+	if err != nil {
+		return err
+	}
+	if pin == nil {
+		keyIDs := "unknown"
+		if observedKeyIDs, err := getObservedRootKeyIDs(repo); err == nil {
+			keyIDs = strings.Join(observedKeyIDs, ", ")
+		} else {
+			slog.Debug(fmt.Sprintf("Unable to load root of trust keys: %s", err.Error()))
+		}
+		slog.Warn(fmt.Sprintf("WARNING: root of trust keys are not pinned, observed keys '%s'. A change in the root of trust keys can't be detected until they are pinned using 'gittuf trust pin-root-keys', after confirming them out of band.", keyIDs))
+		return nil
+	}
+
+	_, err = checkRootPin(ctx, repo, pin, warnOnly)
+	return err
+}
+
+// getObservedRootKeyIDs returns the sorted IDs of the root keys of the latest
+// policy state, without verifying the state's root of trust chain. The IDs are
+// only reported to the user, and must not be trusted.
+func getObservedRootKeyIDs(repo *git.Repository) ([]string, error) {
+	latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := loadStateForEntry(repo, latestEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	return rootKeyIDs(state)
+}
+
+// PinRootKeys pins the root of trust keys of the current policy. If no keys
+// are pinned yet, the current root keys are trusted on first use, unless an
+// initial root hash is pinned, in which case the keys are verified from the
+// initial root. If the current root keys were rotated from the pinned keys, the
+// pin is updated to the current keys. Mismatches are handled as in
+// VerifyRootPin, and the pin is left unchanged.
+func PinRootKeys(ctx context.Context, repo *git.Repository, warnOnly bool) error {
+	slog.Debug("Loading root of trust pin...")
+	pin, err := LoadRootPin(repo)
+	if err != nil {
+		return err
+	}
+
+	newPin, err := checkRootPin(ctx, repo, pin, warnOnly)
+	if err != nil {
+		return err
+	}
+	if newPin == nil {
+		return nil
+	}
+
+	switch {
+	case pin == nil:
+		slog.Info(fmt.Sprintf("Pinning root of trust keys '%s' on first use...", strings.Join(newPin.RootKeyIDs, ", ")))
+	case len(pin.RootKeyIDs) == 0:
+		slog.Info(fmt.Sprintf("Pinning root of trust keys '%s' verified from pinned initial root...", strings.Join(newPin.RootKeyIDs, ", ")))
+	default:
+		slog.Info(fmt.Sprintf("Root of trust keys were rotated to '%s', updating pin...", strings.Join(newPin.RootKeyIDs, ", ")))
+	}
+	return newPin.Commit(repo)
+}
+
+// checkRootPin checks the root of trust keys of the current policy against the
+// pin, which may be nil. The pin for the current keys is returned if it must be
+// recorded, i.e., if no keys are pinned yet or the pinned keys were rotated to
+// the current keys. Otherwise, nil is returned.
+func checkRootPin(ctx context.Context, repo *git.Repository, pin *RootPin, warnOnly bool) (*RootPin, error) {
+	slog.Debug("Loading current policy...")
+	// Loading the current state verifies the root of trust chain
+	state, err := LoadCurrentState(ctx, repo, PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	currentKeyIDs, err := rootKeyIDs(state)
+	if err != nil {
+		return nil, err
+	}
+
+	newPin := &RootPin{RootKeyIDs: currentKeyIDs, PolicyEntryID: latestEntry.ID.String()}
+	if pin == nil {
+		return newPin, nil
+	}
+
+	if pin.InitialRootHash != "" {
+		// The root of trust lineage is checked against the hash pinned out
+		// of band, regardless of warnOnly
+		initialRootHash, err := GetInitialRootHash(repo)
+		if err != nil {
+			return nil, err
+		}
+		if initialRootHash != pin.InitialRootHash {
+			return nil, fmt.Errorf("%w: pinned hash '%s', initial root hash '%s'", ErrInitialRootHashMismatch, pin.InitialRootHash, initialRootHash)
+		}
+		newPin.InitialRootHash = pin.InitialRootHash
+
+		if len(pin.RootKeyIDs) == 0 {
+			return newPin, nil
+		}
+	}
+
+	if slices.Equal(pin.RootKeyIDs, currentKeyIDs) {
+		return nil, nil
+	}
+
+	slog.Debug("Root of trust keys have changed, checking for rotation from pinned keys...")
+	rotated, err := isRotatedFrom(repo, latestEntry, pin.RootKeyIDs)
+	if err != nil {
+		return nil, err
+	}
+	if rotated {
+		return newPin, nil
+	}
+
+	err = fmt.Errorf("%w: pinned keys '%s', current keys '%s'", ErrRootPinMismatch, strings.Join(pin.RootKeyIDs, ", "), strings.Join(currentKeyIDs, ", "))
+	if warnOnly {
+		slog.Warn(fmt.Sprintf("WARNING: %s. The root of trust may have been replaced!", err.Error()))
+		return nil, nil
+	}
+	return nil, err
+}
+
+// isRotatedFrom returns true if a policy state preceding the specified policy
+// entry uses the specified root keys. As the root of trust for each policy
+// state is verified using the preceding state, the current root keys were then
+// rotated from the specified keys.
+func isRotatedFrom(repo *git.Repository, entry *rsl.ReferenceEntry, keyIDs []string) (bool, error) {
+	for {
+		var err error
+		entry, _, err = rsl.GetLatestReferenceEntryForRefBefore(repo, PolicyRef, entry.ID)
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				return false, nil
+			}
+			return false, err
+		}
+
+		state, err := loadStateForEntry(repo, entry)
+		if err != nil {
+			return false, err
+		}
+
+		stateKeyIDs, err := rootKeyIDs(state)
+		if err != nil {
+			return false, err
+		}
+
+		if slices.Equal(stateKeyIDs, keyIDs) {
+			return true, nil
+		}
+	}
+}
+
+// rootKeyIDs returns the sorted IDs of the state's root keys.
+func rootKeyIDs(state *State) ([]string, error) {
+	rootKeys, err := state.GetRootKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	keyIDs := make([]string, 0, len(rootKeys))
+	for _, key := range rootKeys {
+		keyIDs = append(keyIDs, key.KeyID)
+	}
+	slices.Sort(keyIDs)
+
+	return keyIDs, nil
+}
@@ -0,0 +1,286 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyRootPin(t *testing.T) {
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRootKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("no pin", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithOnlyRoot)
+
+		logs := &bytes.Buffer{}
+		defaultLogger := slog.Default()
+		slog.SetDefault(slog.New(slog.NewTextHandler(logs, nil)))
+		defer slog.SetDefault(defaultLogger)
+
+		// Verification is skipped and doesn't pin the keys, but warns that
+		// the observed keys aren't pinned
+		err := VerifyRootPin(testCtx, repo, false)
+		assert.Nil(t, err)
+		assert.Contains(t, logs.String(), "root of trust keys are not pinned")
+		assert.Contains(t, logs.String(), rootKey.KeyID)
+
+		pin, err := LoadRootPin(repo)
+		assert.Nil(t, err)
+		assert.Nil(t, pin)
+
+		// The warning is logged even if the policy can't be loaded
+		logs.Reset()
+		emptyRepo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = VerifyRootPin(testCtx, emptyRepo, false)
+		assert.Nil(t, err)
+		assert.Contains(t, logs.String(), "root of trust keys are not pinned")
+	})
+
+	t.Run("pin on first use", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithOnlyRoot)
+
+		pin, err := LoadRootPin(repo)
+		assert.Nil(t, err)
+		assert.Nil(t, pin)
+
+		err = PinRootKeys(testCtx, repo, false)
+		assert.Nil(t, err)
+
+		pin, err = LoadRootPin(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{rootKey.KeyID}, pin.RootKeyIDs)
+
+		policyEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, policyEntry.ID.String(), pin.PolicyEntryID)
+
+		// Verifying with unchanged keys succeeds
+		err = VerifyRootPin(testCtx, repo, false)
+		assert.Nil(t, err)
+
+		err = PinRootKeys(testCtx, repo, false)
+		assert.Nil(t, err)
+
+		err = ResetRootPin(repo)
+		assert.Nil(t, err)
+		pin, err = LoadRootPin(repo)
+		assert.Nil(t, err)
+		assert.Nil(t, pin)
+	})
+
+	t.Run("valid rotation updates pin", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithOnlyRoot)
+
+		err := PinRootKeys(testCtx, repo, false)
+		assert.Nil(t, err)
+
+		// Rotate to a new root key, signed using the pinned key
+		state, err := LoadCurrentState(testCtx, repo, PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rootMetadata, err := state.GetRootMetadata()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rootMetadata = AddRootKey(rootMetadata, newRootKey)
+		rootMetadata, err = DeleteRootKey(rootMetadata, rootKey.KeyID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rootEnv, err = dsse.SignEnvelope(testCtx, rootEnv, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		newSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets1KeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		rootEnv, err = dsse.SignEnvelope(testCtx, rootEnv, newSigner)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		state.RootEnvelope = rootEnv
+		state.RootPublicKeys = []*tuf.Key{newRootKey}
+		if err := state.Commit(repo, "Rotate root key", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := Apply(testCtx, repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		// Verification accepts the rotation without updating the pin
+		err = VerifyRootPin(testCtx, repo, false)
+		assert.Nil(t, err)
+
+		pin, err := LoadRootPin(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{rootKey.KeyID}, pin.RootKeyIDs)
+
+		err = PinRootKeys(testCtx, repo, false)
+		assert.Nil(t, err)
+
+		pin, err = LoadRootPin(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{newRootKey.KeyID}, pin.RootKeyIDs)
+	})
+
+	t.Run("root swapped without rotation", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithOnlyRoot)
+
+		// Mimic a pin from a root of trust that has since been replaced
+		swappedPin := &RootPin{RootKeyIDs: []string{newRootKey.KeyID}, PolicyEntryID: plumbing.ZeroHash.String()}
+		if err := swappedPin.Commit(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		err := VerifyRootPin(testCtx, repo, false)
+		assert.ErrorIs(t, err, ErrRootPinMismatch)
+
+		err = PinRootKeys(testCtx, repo, false)
+		assert.ErrorIs(t, err, ErrRootPinMismatch)
+
+		// Only warn, the pin is not updated
+		err = VerifyRootPin(testCtx, repo, true)
+		assert.Nil(t, err)
+
+		err = PinRootKeys(testCtx, repo, true)
+		assert.Nil(t, err)
+
+		pin, err := LoadRootPin(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, swappedPin, pin)
+	})
+}
+
+func TestPinInitialRoot(t *testing.T) {
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("matching hash", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithOnlyRoot)
+
+		rootHash, err := GetInitialRootHash(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = PinInitialRoot(repo, strings.ToUpper(rootHash))
+		assert.Nil(t, err)
+
+		pin, err := LoadRootPin(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, &RootPin{InitialRootHash: rootHash}, pin)
+
+		// Verification checks the initial root without pinning the keys
+		err = VerifyRootPin(testCtx, repo, false)
+		assert.Nil(t, err)
+
+		pin, err = LoadRootPin(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, &RootPin{InitialRootHash: rootHash}, pin)
+
+		// The root of trust keys are pinned once verified from the initial
+		// root
+		err = PinRootKeys(testCtx, repo, false)
+		assert.Nil(t, err)
+
+		pin, err = LoadRootPin(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{rootKey.KeyID}, pin.RootKeyIDs)
+		assert.Equal(t, rootHash, pin.InitialRootHash)
+	})
+
+	t.Run("mismatched hash", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithOnlyRoot)
+
+		otherHash := strings.Repeat("0", 64)
+		err := PinInitialRoot(repo, otherHash)
+		assert.ErrorIs(t, err, ErrInitialRootHashMismatch)
+
+		pin, err := LoadRootPin(repo)
+		assert.Nil(t, err)
+		assert.Nil(t, pin)
+
+		// Mimic a hash pinned before the policy was fetched
+		mismatchedPin := &RootPin{InitialRootHash: otherHash}
+		if err := mismatchedPin.Commit(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		err = VerifyRootPin(testCtx, repo, false)
+		assert.ErrorIs(t, err, ErrInitialRootHashMismatch)
+
+		// A mismatch fails verification even if only warning
+		err = VerifyRootPin(testCtx, repo, true)
+		assert.ErrorIs(t, err, ErrInitialRootHashMismatch)
+
+		err = PinRootKeys(testCtx, repo, true)
+		assert.ErrorIs(t, err, ErrInitialRootHashMismatch)
+	})
+
+	t.Run("no policy", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = GetInitialRootHash(repo)
+		assert.ErrorIs(t, err, ErrPolicyNotFound)
+
+		rootHash := strings.Repeat("a", 64)
+		err = PinInitialRoot(repo, rootHash)
+		assert.Nil(t, err)
+
+		pin, err := LoadRootPin(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, rootHash, pin.InitialRootHash)
+	})
+
+	t.Run("invalid hash", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithOnlyRoot)
+
+		err := PinInitialRoot(repo, "abc")
+		assert.ErrorIs(t, err, ErrInvalidRootHash)
+
+		err = PinInitialRoot(repo, strings.Repeat("z", 64))
+		assert.ErrorIs(t, err, ErrInvalidRootHash)
+	})
+}
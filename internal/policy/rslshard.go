@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5"
+)
+
+var (
+	ErrInvalidRSLShardNamespace = errors.New("RSL shard namespace must be a ref namespace outside the gittuf namespace ending in '/', such as 'refs/heads/team-a/'")
+	ErrRSLShardNamespaceInUse   = errors.New("ref namespace is already recorded in an RSL shard")
+	ErrRSLShardNotFound         = errors.New("RSL shard not found")
+	ErrRSLShardMismatch         = errors.New("RSL entry is not recorded where the policy in effect records the ref's entries")
+)
+
+// AddRSLShard records that the entries for refs in the namespaces are recorded
+// in the RSL shard with the specified name. If the shard exists, the
+// namespaces are added to it. A namespace may only be recorded in one shard.
+func AddRSLShard(rootMetadata *tuf.RootMetadata, name string, namespaces []string) (*tuf.RootMetadata, error) {
+	if rootMetadata == nil {
+		return nil, ErrRootMetadataNil
+	}
+
+	if err := rsl.ValidateShardName(name); err != nil {
+		return nil, err
+	}
+
+	if len(namespaces) == 0 {
+		return nil, ErrInvalidRSLShardNamespace
+	}
+
+	for _, namespace := range namespaces {
+		if !strings.HasPrefix(namespace, "refs/") || !strings.HasSuffix(namespace, "/") || strings.HasPrefix(namespace, "refs/gittuf/") {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidRSLShardNamespace, namespace)
+		}
+
+		for shardName, shardNamespaces := range rootMetadata.RSLShards {
+			if slices.Contains(shardNamespaces, namespace) {
+				return nil, fmt.Errorf("%w: '%s' is recorded in '%s'", ErrRSLShardNamespaceInUse, namespace, shardName)
+			}
+		}
+	}
+
+	if rootMetadata.RSLShards == nil {
+		rootMetadata.RSLShards = map[string][]string{}
+	}
+
+	shardNamespaces := append(rootMetadata.RSLShards[name], namespaces...)
+	slices.Sort(shardNamespaces)
+	rootMetadata.RSLShards[name] = slices.Compact(shardNamespaces)
+
+	return rootMetadata, nil
+}
+
+// RemoveRSLShard removes the RSL shard with the specified name, so that the
+// entries for refs in its namespaces are recorded in the RSL again.
+func RemoveRSLShard(rootMetadata *tuf.RootMetadata, name string) (*tuf.RootMetadata, error) {
+	if rootMetadata == nil {
+		return nil, ErrRootMetadataNil
+	}
+
+	if _, has := rootMetadata.RSLShards[name]; !has {
+		return nil, fmt.Errorf("%w: '%s'", ErrRSLShardNotFound, name)
+	}
+
+	delete(rootMetadata.RSLShards, name)
+	if len(rootMetadata.RSLShards) == 0 {
+		rootMetadata.RSLShards = nil
+	}
+
+	return rootMetadata, nil
+}
+
+// GetRSLShardForRef returns the name of the RSL shard the entries for the ref
+// are recorded in, or an empty string if they're recorded in the RSL itself.
+// If the ref is in the namespaces of several shards, the shard with the
+// longest matching namespace is used.
+func (s *State) GetRSLShardForRef(refName string) (string, error) {
+	rootMetadata, err := s.getRootMetadata()
+	if err != nil {
+		return "", err
+	}
+
+	shard := ""
+	matchLength := 0
+	for name, namespaces := range rootMetadata.RSLShards {
+		for _, namespace := range namespaces {
+			if strings.HasPrefix(refName, namespace) && len(namespace) > matchLength {
+				shard = name
+				matchLength = len(namespace)
+			}
+		}
+	}
+
+	return shard, nil
+}
+
+// verifyRSLShardForEntry checks that the entry is recorded in the RSL shard the
+// policy records the ref's entries in, or in the RSL itself if the policy
+// doesn't record them in a shard. The policy must be the one in effect when
+// the entry was recorded.
+func (s *State) verifyRSLShardForEntry(entry *rsl.ReferenceEntry) error {
+	shard, err := s.GetRSLShardForRef(entry.RefName)
+	if err != nil {
+		return err
+	}
+
+	if entry.Shard != shard {
+		return fmt.Errorf("%w: entry '%s' for '%s' is recorded in %s instead of %s", ErrRSLShardMismatch, entry.ID.String(), entry.RefName, rslShardDescription(entry.Shard), rslShardDescription(shard))
+	}
+
+	return nil
+}
+
+// verifyRSLShardIndexEntry checks that the RSL shard the index entry records a
+// tip for exists in the policy, and that the shard entries recorded by the
+// index entry are all for refs the policy records in the shard. The policy must
+// be the one in effect when the index entry was recorded.
+func (s *State) verifyRSLShardIndexEntry(repo *git.Repository, indexEntry *rsl.ReferenceEntry) error {
+	rootMetadata, err := s.getRootMetadata()
+	if err != nil {
+		return err
+	}
+
+	shard := strings.TrimPrefix(indexEntry.RefName, rsl.ShardRefPrefix)
+	if _, has := rootMetadata.RSLShards[shard]; !has {
+		return fmt.Errorf("%w: index entry '%s' records a tip for '%s'", ErrRSLShardNotFound, indexEntry.ID.String(), shard)
+	}
+
+	shardEntries, err := rsl.GetShardEntries(repo, indexEntry)
+	if err != nil {
+		return err
+	}
+
+	for _, shardEntry := range shardEntries {
+		if err := s.verifyRSLShardForEntry(shardEntry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rslShardDescription returns how the location of entries recorded in the
+// shard is described in errors.
+func rslShardDescription(shard string) string {
+	if shard == "" {
+		return "the RSL"
+	}
+	return fmt.Sprintf("RSL shard '%s'", shard)
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddRSLShard(t *testing.T) {
+	key, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	rootMetadata, err = AddRSLShard(rootMetadata, "team-a", []string{"refs/heads/team-a/"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]string{"team-a": {"refs/heads/team-a/"}}, rootMetadata.RSLShards)
+
+	// Namespaces are added to existing shards
+	rootMetadata, err = AddRSLShard(rootMetadata, "team-a", []string{"refs/tags/team-a/"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]string{"team-a": {"refs/heads/team-a/", "refs/tags/team-a/"}}, rootMetadata.RSLShards)
+
+	_, err = AddRSLShard(rootMetadata, "team-b", []string{"refs/heads/team-a/"})
+	assert.ErrorIs(t, err, ErrRSLShardNamespaceInUse)
+
+	for _, namespace := range []string{"refs/heads/team-b", "heads/team-b/", "refs/gittuf/team-b/"} {
+		_, err = AddRSLShard(rootMetadata, "team-b", []string{namespace})
+		assert.ErrorIs(t, err, ErrInvalidRSLShardNamespace)
+	}
+
+	_, err = AddRSLShard(rootMetadata, "team-b", nil)
+	assert.ErrorIs(t, err, ErrInvalidRSLShardNamespace)
+
+	_, err = AddRSLShard(rootMetadata, "team/b", []string{"refs/heads/team-b/"})
+	assert.ErrorIs(t, err, rsl.ErrInvalidShardName)
+
+	_, err = AddRSLShard(nil, "team-b", []string{"refs/heads/team-b/"})
+	assert.ErrorIs(t, err, ErrRootMetadataNil)
+}
+
+func TestRemoveRSLShard(t *testing.T) {
+	key, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	_, err = RemoveRSLShard(rootMetadata, "team-a")
+	assert.ErrorIs(t, err, ErrRSLShardNotFound)
+
+	rootMetadata, err = AddRSLShard(rootMetadata, "team-a", []string{"refs/heads/team-a/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err = RemoveRSLShard(rootMetadata, "team-a")
+	assert.Nil(t, err)
+	assert.Nil(t, rootMetadata.RSLShards)
+
+	_, err = RemoveRSLShard(nil, "team-a")
+	assert.ErrorIs(t, err, ErrRootMetadataNil)
+}
+
+func TestGetRSLShardForRef(t *testing.T) {
+	key, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+	rootMetadata, err = AddRSLShard(rootMetadata, "team-a", []string{"refs/heads/team-a/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err = AddRSLShard(rootMetadata, "team-a-release", []string{"refs/heads/team-a/release/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &State{RootEnvelope: rootEnv}
+
+	tests := map[string]struct {
+		refName       string
+		expectedShard string
+	}{
+		"ref in shard": {
+			refName:       "refs/heads/team-a/feature",
+			expectedShard: "team-a",
+		},
+		"ref in longer namespace": {
+			refName:       "refs/heads/team-a/release/v1",
+			expectedShard: "team-a-release",
+		},
+		"ref not in shard": {
+			refName:       "refs/heads/main",
+			expectedShard: "",
+		},
+		"ref with namespace as prefix of name": {
+			refName:       "refs/heads/team-a",
+			expectedShard: "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			shard, err := state.GetRSLShardForRef(test.refName)
+			assert.Nil(t, err, name)
+			assert.Equal(t, test.expectedShard, shard, name)
+		})
+	}
+}
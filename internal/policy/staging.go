@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// GetStagedChanges compares the policy in the staging area with the applied
+// policy. If no policy has been applied yet, all of the staged policy is
+// reported as added.
+func GetStagedChanges(ctx context.Context, repo *git.Repository) (*StateDiff, error) {
+	slog.Debug("Loading staged policy...")
+	stagedState, err := LoadCurrentState(ctx, repo, PolicyStagingRef)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Loading applied policy...")
+	appliedState, err := LoadCurrentState(ctx, repo, PolicyRef)
+	if err != nil {
+		if !errors.Is(err, ErrPolicyNotFound) {
+			return nil, err
+		}
+		appliedState = nil
+	}
+
+	return DiffStates(appliedState, stagedState)
+}
+
+// Discard resets the policy staging area to the applied policy, dropping all
+// staged changes. The reset is recorded in the RSL.
+func Discard(repo *git.Repository, signRSLEntry bool) error {
+	policyRef, err := repo.Reference(plumbing.ReferenceName(PolicyRef), true)
+	if err != nil && !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return err
+	}
+	if policyRef == nil || policyRef.Hash().IsZero() {
+		// The policy namespace is initialized with a zero hash
+		return fmt.Errorf("%w, cannot discard staged changes without an applied policy", ErrPolicyNotFound)
+	}
+
+	policyStagingRef, err := repo.Reference(plumbing.ReferenceName(PolicyStagingRef), true)
+	if err != nil {
+		return fmt.Errorf("failed to get policy staging reference %s: %w", PolicyStagingRef, err)
+	}
+
+	if policyStagingRef.Hash() == policyRef.Hash() {
+		slog.Debug("No staged changes to discard")
+		return nil
+	}
+
+	slog.Debug("Resetting policy staging area to applied policy...")
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(PolicyStagingRef, policyRef.Hash())); err != nil {
+		return fmt.Errorf("failed to set policy staging reference: %w", err)
+	}
+
+	if err := rsl.NewReferenceEntry(PolicyStagingRef, policyRef.Hash()).Commit(repo, signRSLEntry); err != nil {
+		return gitinterface.ResetDueToError(err, repo, PolicyStagingRef, policyStagingRef.Hash())
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStagedChanges(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+	changes, err := GetStagedChanges(testCtx, repo)
+	assert.Nil(t, err)
+	assert.False(t, changes.HasChanges())
+
+	// Stage a new rule
+	if err := createTestStateWithTagPolicy(t).Commit(repo, "Add tag rule", false); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err = GetStagedChanges(testCtx, repo)
+	assert.Nil(t, err)
+	assert.True(t, changes.HasChanges())
+	assert.Equal(t, []string{"protect-tags"}, changes.AddedRules)
+	assert.Empty(t, changes.RemovedRules)
+	assert.Empty(t, changes.UpdatedRules)
+	assert.Empty(t, changes.UpdatedRoles)
+
+	// Stage an updated rule in place of the new rule
+	if err := createTestStateWithThresholdPolicy(t).Commit(repo, "Update main rule", false); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err = GetStagedChanges(testCtx, repo)
+	assert.Nil(t, err)
+	assert.Empty(t, changes.AddedRules)
+	assert.Empty(t, changes.RemovedRules)
+	assert.Equal(t, 1, len(changes.UpdatedRules))
+	assert.Equal(t, "protect-main", changes.UpdatedRules[0].Name)
+}
+
+func TestDiscard(t *testing.T) {
+	t.Run("no applied policy", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+		if err := rsl.InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+		if err := createTestStateWithPolicy(t).Commit(repo, "Create test state", false); err != nil {
+			t.Fatal(err)
+		}
+
+		err = Discard(repo, false)
+		assert.ErrorIs(t, err, ErrPolicyNotFound)
+	})
+
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+	// Discarding without staged changes is a no-op
+	err := Discard(repo, false)
+	assert.Nil(t, err)
+
+	if err := createTestStateWithTagPolicy(t).Commit(repo, "Add tag rule", false); err != nil {
+		t.Fatal(err)
+	}
+
+	err = Discard(repo, false)
+	assert.Nil(t, err)
+
+	policyRef, err := repo.Reference(plumbing.ReferenceName(PolicyRef), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policyStagingRef, err := repo.Reference(plumbing.ReferenceName(PolicyStagingRef), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, policyRef.Hash(), policyStagingRef.Hash())
+
+	changes, err := GetStagedChanges(testCtx, repo)
+	assert.Nil(t, err)
+	assert.False(t, changes.HasChanges())
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/common"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// RoleSigningStatus records which of a role's authorized keys have signed the
+// role's metadata, and which have not.
+type RoleSigningStatus struct {
+	// Name is the name of the role, such as root, targets, or the name of a
+	// delegated role.
+	Name string
+
+	// Threshold is the number of signatures required for the role's metadata.
+	Threshold int
+
+	// SignedBy contains the IDs of authorized keys that have a valid signature
+	// on the role's metadata.
+	SignedBy []string
+
+	// Missing contains the IDs of authorized keys that have not yet signed the
+	// role's metadata.
+	Missing []string
+}
+
+// IsFullySigned returns true if the role's metadata has a threshold of valid
+// signatures.
+func (r *RoleSigningStatus) IsFullySigned() bool {
+	return len(r.SignedBy) >= r.Threshold
+}
+
+// GetSigningStatus reports the signing status of the root role, the top level
+// targets role, and every reachable delegated targets role in the state. Unlike
+// Verify, it does not stop at the first role that isn't fully signed, making
+// it useful to inspect staged policy changes before they are applied. Keys that
+// cannot sign metadata, such as GPG keys, are not reported as missing.
+func (s *State) GetSigningStatus(ctx context.Context) ([]*RoleSigningStatus, error) {
+	statuses := []*RoleSigningStatus{}
+
+	rootVerifier, err := s.getRootVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	rootStatus, err := getRoleSigningStatus(ctx, RootRoleName, rootVerifier.keys, rootVerifier.threshold, s.RootEnvelope)
+	if err != nil {
+		return nil, err
+	}
+	statuses = append(statuses, rootStatus)
+
+	if s.TargetsEnvelope == nil {
+		return statuses, nil
+	}
+
+	targetsVerifier, err := s.getTargetsVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	targetsStatus, err := getRoleSigningStatus(ctx, TargetsRoleName, targetsVerifier.keys, targetsVerifier.threshold, s.TargetsEnvelope)
+	if err != nil {
+		return nil, err
+	}
+	statuses = append(statuses, targetsStatus)
+
+	targetsMetadata, err := s.getTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The metadata is shared via the payload cache, so we must not modify it
+	delegationsQueue := slices.Clone(targetsMetadata.Delegations.Roles)
+	delegationKeys := maps.Clone(targetsMetadata.Delegations.Keys)
+	for {
+		// The last entry in the queue is always the allow rule, which we don't
+		// process during DFS
+		if len(delegationsQueue) <= 1 {
+			break
+		}
+
+		delegation := delegationsQueue[0]
+		delegationsQueue = delegationsQueue[1:]
+
+		if !s.HasTargetsRole(delegation.Name) {
+			continue
+		}
+
+		keys := []*tuf.Key{}
+		for _, keyID := range delegation.KeyIDs {
+			keys = append(keys, delegationKeys[keyID])
+		}
+
+		delegationStatus, err := getRoleSigningStatus(ctx, delegation.Name, keys, delegation.Threshold, s.DelegationEnvelopes[delegation.Name])
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, delegationStatus)
+
+		delegatedMetadata, err := s.getTargetsMetadata(delegation.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		delegationsQueue = append(slices.Clone(delegatedMetadata.Delegations.Roles), delegationsQueue...)
+		for keyID, key := range delegatedMetadata.Delegations.Keys {
+			delegationKeys[keyID] = key
+		}
+	}
+
+	return statuses, nil
+}
+
+func getRoleSigningStatus(ctx context.Context, roleName string, keys []*tuf.Key, threshold int, env *sslibdsse.Envelope) (*RoleSigningStatus, error) {
+	status := &RoleSigningStatus{
+		Name:      roleName,
+		Threshold: threshold,
+		SignedBy:  []string{},
+		Missing:   []string{},
+	}
+
+	for _, key := range keys {
+		verifier, err := newDSSEVerifier(key)
+		if err != nil {
+			if errors.Is(err, common.ErrUnknownKeyType) {
+				// Keys such as GPG keys cannot be used to sign DSSE
+				// envelopes
+				continue
+			}
+			return nil, err
+		}
+
+		if env != nil && dsse.VerifyEnvelope(ctx, env, []sslibdsse.Verifier{verifier}, 1) == nil {
+			status.SignedBy = append(status.SignedBy, key.KeyID)
+		} else {
+			status.Missing = append(status.Missing, key.KeyID)
+		}
+	}
+
+	return status, nil
+}
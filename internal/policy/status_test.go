@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateGetSigningStatus(t *testing.T) {
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("fully signed", func(t *testing.T) {
+		state := createTestStateWithDelegatedPolicies(t)
+
+		statuses, err := state.GetSigningStatus(context.Background())
+		assert.Nil(t, err)
+
+		expectedStatuses := []*RoleSigningStatus{
+			{Name: RootRoleName, Threshold: 1, SignedBy: []string{rootKey.KeyID}, Missing: []string{}},
+			{Name: TargetsRoleName, Threshold: 1, SignedBy: []string{rootKey.KeyID}, Missing: []string{}},
+			{Name: "1", Threshold: 1, SignedBy: []string{rootKey.KeyID}, Missing: []string{}},
+		}
+		assert.Equal(t, expectedStatuses, statuses)
+		for _, status := range statuses {
+			assert.True(t, status.IsFullySigned())
+		}
+	})
+
+	t.Run("targets missing signature", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.TargetsEnvelope, err = dsse.CreateEnvelope(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		statuses, err := state.GetSigningStatus(context.Background())
+		assert.Nil(t, err)
+		assert.Len(t, statuses, 2)
+
+		assert.True(t, statuses[0].IsFullySigned())
+
+		assert.Equal(t, TargetsRoleName, statuses[1].Name)
+		assert.False(t, statuses[1].IsFullySigned())
+		assert.Empty(t, statuses[1].SignedBy)
+		assert.Equal(t, []string{rootKey.KeyID}, statuses[1].Missing)
+	})
+
+	t.Run("only root", func(t *testing.T) {
+		state := createTestStateWithOnlyRoot(t)
+
+		statuses, err := state.GetSigningStatus(context.Background())
+		assert.Nil(t, err)
+		assert.Len(t, statuses, 1)
+		assert.Equal(t, RootRoleName, statuses[0].Name)
+	})
+}
@@ -4,6 +4,8 @@ package policy
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/gittuf/gittuf/internal/tuf"
@@ -21,12 +23,22 @@ func InitializeTargetsMetadata() *tuf.TargetsMetadata {
 	return targetsMetadata
 }
 
-// AddDelegation adds a new delegation to TargetsMetadata.
+// AddDelegation adds a new delegation to TargetsMetadata. The threshold is the
+// number of distinct authorized keys that must sign off on a change protected
+// by the delegation.
 func AddDelegation(targetsMetadata *tuf.TargetsMetadata, ruleName string, authorizedKeys []*tuf.Key, rulePatterns []string, threshold int) (*tuf.TargetsMetadata, error) {
 	if ruleName == AllowRuleName {
 		return nil, ErrCannotManipulateAllowRule
 	}
 
+	if err := validateThreshold(authorizedKeys, threshold); err != nil {
+		return nil, err
+	}
+
+	if err := validateRulePatterns(rulePatterns); err != nil {
+		return nil, err
+	}
+
 	authorizedKeyIDs := []string{}
 	for _, key := range authorizedKeys {
 		targetsMetadata.Delegations.AddKey(key)
@@ -57,8 +69,12 @@ func UpdateDelegation(targetsMetadata *tuf.TargetsMetadata, ruleName string, aut
 		return nil, ErrCannotManipulateAllowRule
 	}
 
-	if len(authorizedKeys) < threshold {
-		return nil, ErrCannotMeetThreshold
+	if err := validateThreshold(authorizedKeys, threshold); err != nil {
+		return nil, err
+	}
+
+	if err := validateRulePatterns(rulePatterns); err != nil {
+		return nil, err
 	}
 
 	authorizedKeyIDs := []string{}
@@ -115,6 +131,164 @@ func RemoveDelegation(targetsMetadata *tuf.TargetsMetadata, ruleName string) (*t
 	return targetsMetadata, nil
 }
 
+// AddRestrictedKeyToDelegation adds a key to a delegation in TargetsMetadata
+// that may only be used for the specified operations, such as the key GitHub
+// uses to sign commits created using its web UI. If the key is already
+// authorized by the delegation, it is restricted to the operations instead.
+// The delegation's threshold is unchanged.
+func AddRestrictedKeyToDelegation(targetsMetadata *tuf.TargetsMetadata, ruleName string, key *tuf.Key, operations []string) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+
+	if len(operations) == 0 {
+		return nil, tuf.ErrUnknownKeyOperation
+	}
+	for _, operation := range operations {
+		if err := tuf.ValidateKeyOperation(operation); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name != ruleName {
+			continue
+		}
+
+		targetsMetadata.Delegations.AddKey(key)
+
+		if !slices.Contains(delegation.KeyIDs, key.KeyID) {
+			delegation.KeyIDs = append(delegation.KeyIDs, key.KeyID)
+		}
+
+		if delegation.KeyOperations == nil {
+			delegation.KeyOperations = map[string][]string{}
+		}
+		delegation.KeyOperations[key.KeyID] = operations
+
+		targetsMetadata.Delegations.Roles[i] = delegation
+		return targetsMetadata, nil
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetDelegationRotation sets the rotation schedule of a delegation in
+// TargetsMetadata. Every key in the schedule must be authorized by the
+// delegation, and each shift must have enough keys to meet the delegation's
+// threshold. Passing a nil schedule removes the delegation's rotation.
+func SetDelegationRotation(targetsMetadata *tuf.TargetsMetadata, ruleName string, rotation *tuf.RotationSchedule) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+
+	for i, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name != ruleName {
+			continue
+		}
+
+		if rotation != nil {
+			if err := rotation.Validate(); err != nil {
+				return nil, err
+			}
+
+			for _, shift := range rotation.Shifts {
+				if len(shift) < delegation.Threshold {
+					return nil, ErrCannotMeetThreshold
+				}
+
+				for _, keyID := range shift {
+					if !slices.Contains(delegation.KeyIDs, keyID) {
+						return nil, fmt.Errorf("%w: key '%s' is not authorized by rule '%s'", tuf.ErrInvalidRotation, keyID, ruleName)
+					}
+				}
+			}
+		}
+
+		delegation.Rotation = rotation
+		targetsMetadata.Delegations.Roles[i] = delegation
+		return targetsMetadata, nil
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetDelegationRequireTicket sets whether a delegation in TargetsMetadata
+// requires RSL entries for its refs to record a ticket.
+func SetDelegationRequireTicket(targetsMetadata *tuf.TargetsMetadata, ruleName string, requireTicket bool) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+
+	for i, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name != ruleName {
+			continue
+		}
+
+		delegation.RequireTicket = requireTicket
+		targetsMetadata.Delegations.Roles[i] = delegation
+		return targetsMetadata, nil
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetDelegationAllowedBuilders sets the builders a delegation in
+// TargetsMetadata allows SLSA provenance for its tags from. If builders is
+// empty, provenance is no longer required.
+func SetDelegationAllowedBuilders(targetsMetadata *tuf.TargetsMetadata, ruleName string, builders []string) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+
+	for i, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name != ruleName {
+			continue
+		}
+
+		if len(builders) == 0 {
+			builders = nil
+		}
+
+		delegation.AllowedBuilders = builders
+		targetsMetadata.Delegations.Roles[i] = delegation
+		return targetsMetadata, nil
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetDelegationAllowedUpdateTypes sets how the branches protected by a
+// delegation in TargetsMetadata may be updated. If updateTypes is empty, all
+// update types are allowed.
+func SetDelegationAllowedUpdateTypes(targetsMetadata *tuf.TargetsMetadata, ruleName string, updateTypes []string) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+
+	for _, updateType := range updateTypes {
+		if err := tuf.ValidateUpdateType(updateType); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name != ruleName {
+			continue
+		}
+
+		if len(updateTypes) == 0 {
+			updateTypes = nil
+		}
+
+		delegation.AllowedUpdateTypes = updateTypes
+		targetsMetadata.Delegations.Roles[i] = delegation
+		return targetsMetadata, nil
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
 // AddKeyToTargets adds public keys to the specified targets metadata.
 func AddKeyToTargets(targetsMetadata *tuf.TargetsMetadata, authorizedKeys []*tuf.Key) (*tuf.TargetsMetadata, error) {
 	for _, key := range authorizedKeys {
@@ -124,6 +298,54 @@ func AddKeyToTargets(targetsMetadata *tuf.TargetsMetadata, authorizedKeys []*tuf
 	return targetsMetadata, nil
 }
 
+// RemoveKeyFromDelegations removes the key from every delegation in
+// TargetsMetadata, including from the delegations' operation restrictions and
+// rotation schedules, and from the metadata's keys. The names of the
+// delegations that authorized the key are returned. A delegation left with no
+// keys blocks all changes to the namespaces it protects, while a delegation left
+// with too few keys to meet its threshold is an error.
+func RemoveKeyFromDelegations(targetsMetadata *tuf.TargetsMetadata, keyID string) ([]string, error) {
+	if targetsMetadata == nil {
+		return nil, ErrTargetsMetadataNil
+	}
+	if keyID == "" {
+		return nil, ErrKeyIDEmpty
+	}
+
+	ruleNames := []string{}
+	for index, delegation := range targetsMetadata.Delegations.Roles {
+		if !slices.Contains(delegation.KeyIDs, keyID) {
+			continue
+		}
+
+		delegation.KeyIDs = slices.DeleteFunc(slices.Clone(delegation.KeyIDs), func(k string) bool { return k == keyID })
+		if len(delegation.KeyIDs) != 0 && len(delegation.KeyIDs) < delegation.Threshold {
+			return nil, fmt.Errorf("%w: rule '%s'", ErrCannotMeetThreshold, delegation.Name)
+		}
+
+		delete(delegation.KeyOperations, keyID)
+		if len(delegation.KeyOperations) == 0 {
+			delegation.KeyOperations = nil
+		}
+
+		if delegation.Rotation != nil {
+			for shiftIndex, shift := range delegation.Rotation.Shifts {
+				delegation.Rotation.Shifts[shiftIndex] = slices.DeleteFunc(slices.Clone(shift), func(k string) bool { return k == keyID })
+			}
+			if err := delegation.Rotation.Validate(); err != nil {
+				return nil, fmt.Errorf("rule '%s': %w", delegation.Name, err)
+			}
+		}
+
+		targetsMetadata.Delegations.Roles[index] = delegation
+		ruleNames = append(ruleNames, delegation.Name)
+	}
+
+	delete(targetsMetadata.Delegations.Keys, keyID)
+
+	return ruleNames, nil
+}
+
 // AllowRule returns the default, last rule for all policy files.
 func AllowRule() tuf.Delegation {
 	return tuf.Delegation{
@@ -136,3 +358,36 @@ func AllowRule() tuf.Delegation {
 		},
 	}
 }
+
+// validateThreshold checks that the threshold can be met by the distinct keys
+// authorized by a delegation. A delegation that authorizes no keys is permitted,
+// as it blocks all changes to the namespaces it protects.
+func validateThreshold(authorizedKeys []*tuf.Key, threshold int) error {
+	if threshold < 1 {
+		return ErrInvalidThreshold
+	}
+
+	if len(authorizedKeys) == 0 {
+		return nil
+	}
+
+	keyIDs := map[string]bool{}
+	for _, key := range authorizedKeys {
+		keyIDs[key.KeyID] = true
+	}
+	if len(keyIDs) < threshold {
+		return ErrCannotMeetThreshold
+	}
+
+	return nil
+}
+
+func validateRulePatterns(rulePatterns []string) error {
+	for _, pattern := range rulePatterns {
+		if err := tuf.ValidatePattern(pattern); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
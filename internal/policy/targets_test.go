@@ -43,6 +43,26 @@ func TestAddDelegation(t *testing.T) {
 		Terminating: false,
 		Role:        tuf.Role{KeyIDs: []string{key1.KeyID, key2.KeyID}, Threshold: 1},
 	}, targetsMetadata.Delegations.Roles[0])
+
+	_, err = AddDelegation(targetsMetadata, "invalid-rule", []*tuf.Key{key1}, []string{"file:src/**/[a-"}, 1)
+	assert.ErrorIs(t, err, tuf.ErrInvalidPattern)
+
+	t.Run("threshold of distinct keys", func(t *testing.T) {
+		targetsMetadata := InitializeTargetsMetadata()
+
+		targetsMetadata, err := AddDelegation(targetsMetadata, "test-rule", []*tuf.Key{key1, key2}, []string{"test/"}, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, targetsMetadata.Delegations.Roles[0].Threshold)
+
+		_, err = AddDelegation(targetsMetadata, "invalid-rule", []*tuf.Key{key1, key2}, []string{"test/"}, 3)
+		assert.ErrorIs(t, err, ErrCannotMeetThreshold)
+
+		_, err = AddDelegation(targetsMetadata, "invalid-rule", []*tuf.Key{key1, key1}, []string{"test/"}, 2)
+		assert.ErrorIs(t, err, ErrCannotMeetThreshold)
+
+		_, err = AddDelegation(targetsMetadata, "invalid-rule", []*tuf.Key{key1}, []string{"test/"}, 0)
+		assert.ErrorIs(t, err, ErrInvalidThreshold)
+	})
 }
 
 func TestUpdateDelegation(t *testing.T) {
@@ -86,6 +106,205 @@ func TestUpdateDelegation(t *testing.T) {
 	}, targetsMetadata.Delegations.Roles[0])
 }
 
+func TestAddRestrictedKeyToDelegation(t *testing.T) {
+	targetsMetadata := InitializeTargetsMetadata()
+
+	key1, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, err := tuf.LoadKeyFromBytes(targets2PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddDelegation(targetsMetadata, "test-rule", []*tuf.Key{key1}, []string{"git:refs/heads/main"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddRestrictedKeyToDelegation(targetsMetadata, "test-rule", key2, []string{tuf.KeyOperationMergeCommit})
+	assert.Nil(t, err)
+	assert.Equal(t, key2, targetsMetadata.Delegations.Keys[key2.KeyID])
+	assert.Equal(t, tuf.Delegation{
+		Name:          "test-rule",
+		Paths:         []string{"git:refs/heads/main"},
+		Terminating:   false,
+		Role:          tuf.Role{KeyIDs: []string{key1.KeyID, key2.KeyID}, Threshold: 1},
+		KeyOperations: map[string][]string{key2.KeyID: {tuf.KeyOperationMergeCommit}},
+	}, targetsMetadata.Delegations.Roles[0])
+
+	// Restricting an authorized key doesn't add it again
+	targetsMetadata, err = AddRestrictedKeyToDelegation(targetsMetadata, "test-rule", key1, []string{tuf.KeyOperationCommit})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{key1.KeyID, key2.KeyID}, targetsMetadata.Delegations.Roles[0].KeyIDs)
+	assert.Equal(t, []string{tuf.KeyOperationCommit}, targetsMetadata.Delegations.Roles[0].KeyOperations[key1.KeyID])
+
+	_, err = AddRestrictedKeyToDelegation(targetsMetadata, "test-rule", key2, []string{"push"})
+	assert.ErrorIs(t, err, tuf.ErrUnknownKeyOperation)
+
+	_, err = AddRestrictedKeyToDelegation(targetsMetadata, "test-rule", key2, nil)
+	assert.ErrorIs(t, err, tuf.ErrUnknownKeyOperation)
+
+	_, err = AddRestrictedKeyToDelegation(targetsMetadata, "missing-rule", key2, []string{tuf.KeyOperationMergeCommit})
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = AddRestrictedKeyToDelegation(targetsMetadata, AllowRuleName, key2, []string{tuf.KeyOperationMergeCommit})
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+}
+
+func TestSetDelegationRotation(t *testing.T) {
+	targetsMetadata := InitializeTargetsMetadata()
+
+	key1, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, err := tuf.LoadKeyFromBytes(targets2PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddDelegation(targetsMetadata, "test-rule", []*tuf.Key{key1, key2}, []string{"git:refs/heads/main"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotation := &tuf.RotationSchedule{
+		Start:  "2024-01-01T00:00:00Z",
+		Period: "168h",
+		Shifts: [][]string{{key1.KeyID}, {key2.KeyID}},
+	}
+
+	targetsMetadata, err = SetDelegationRotation(targetsMetadata, "test-rule", rotation)
+	assert.Nil(t, err)
+	assert.Equal(t, rotation, targetsMetadata.Delegations.Roles[0].Rotation)
+
+	_, err = SetDelegationRotation(targetsMetadata, "test-rule", &tuf.RotationSchedule{
+		Start:  "2024-01-01T00:00:00Z",
+		Period: "168h",
+		Shifts: [][]string{{rootKey.KeyID}},
+	})
+	assert.ErrorIs(t, err, tuf.ErrInvalidRotation)
+
+	_, err = SetDelegationRotation(targetsMetadata, "test-rule", &tuf.RotationSchedule{
+		Start:  "2024-01-01",
+		Period: "168h",
+		Shifts: [][]string{{key1.KeyID}},
+	})
+	assert.ErrorIs(t, err, tuf.ErrInvalidRotation)
+
+	_, err = SetDelegationRotation(targetsMetadata, "missing-rule", rotation)
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetDelegationRotation(targetsMetadata, AllowRuleName, rotation)
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+
+	targetsMetadata, err = UpdateDelegation(targetsMetadata, "test-rule", []*tuf.Key{key1, key2}, []string{"git:refs/heads/main"}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = SetDelegationRotation(targetsMetadata, "test-rule", rotation)
+	assert.ErrorIs(t, err, ErrCannotMeetThreshold)
+
+	targetsMetadata, err = SetDelegationRotation(targetsMetadata, "test-rule", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].Rotation)
+}
+
+func TestSetDelegationRequireTicket(t *testing.T) {
+	targetsMetadata := InitializeTargetsMetadata()
+
+	key, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddDelegation(targetsMetadata, "test-rule", []*tuf.Key{key}, []string{"git:refs/heads/release/*"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = SetDelegationRequireTicket(targetsMetadata, "test-rule", true)
+	assert.Nil(t, err)
+	assert.True(t, targetsMetadata.Delegations.Roles[0].RequireTicket)
+
+	_, err = SetDelegationRequireTicket(targetsMetadata, "missing-rule", true)
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetDelegationRequireTicket(targetsMetadata, AllowRuleName, true)
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+
+	targetsMetadata, err = SetDelegationRequireTicket(targetsMetadata, "test-rule", false)
+	assert.Nil(t, err)
+	assert.False(t, targetsMetadata.Delegations.Roles[0].RequireTicket)
+}
+
+func TestSetDelegationAllowedBuilders(t *testing.T) {
+	targetsMetadata := InitializeTargetsMetadata()
+
+	key, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddDelegation(targetsMetadata, "test-rule", []*tuf.Key{key}, []string{"git:refs/tags/*"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builders := []string{"https://github.com/gittuf/gittuf/.github/workflows/release.yml@refs/tags/v1"}
+	targetsMetadata, err = SetDelegationAllowedBuilders(targetsMetadata, "test-rule", builders)
+	assert.Nil(t, err)
+	assert.Equal(t, builders, targetsMetadata.Delegations.Roles[0].AllowedBuilders)
+
+	_, err = SetDelegationAllowedBuilders(targetsMetadata, "missing-rule", builders)
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetDelegationAllowedBuilders(targetsMetadata, AllowRuleName, builders)
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+
+	targetsMetadata, err = SetDelegationAllowedBuilders(targetsMetadata, "test-rule", []string{})
+	assert.Nil(t, err)
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].AllowedBuilders)
+}
+
+func TestSetDelegationAllowedUpdateTypes(t *testing.T) {
+	targetsMetadata := InitializeTargetsMetadata()
+
+	key, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddDelegation(targetsMetadata, "test-rule", []*tuf.Key{key}, []string{"git:refs/heads/main"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updateTypes := []string{tuf.UpdateTypeFastForward, tuf.UpdateTypeSquash}
+	targetsMetadata, err = SetDelegationAllowedUpdateTypes(targetsMetadata, "test-rule", updateTypes)
+	assert.Nil(t, err)
+	assert.Equal(t, updateTypes, targetsMetadata.Delegations.Roles[0].AllowedUpdateTypes)
+
+	_, err = SetDelegationAllowedUpdateTypes(targetsMetadata, "test-rule", []string{"rebase"})
+	assert.ErrorIs(t, err, tuf.ErrUnknownUpdateType)
+
+	_, err = SetDelegationAllowedUpdateTypes(targetsMetadata, "missing-rule", updateTypes)
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetDelegationAllowedUpdateTypes(targetsMetadata, AllowRuleName, updateTypes)
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+
+	targetsMetadata, err = SetDelegationAllowedUpdateTypes(targetsMetadata, "test-rule", []string{})
+	assert.Nil(t, err)
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].AllowedUpdateTypes)
+}
+
 func TestRemoveDelegation(t *testing.T) {
 	targetsMetadata := InitializeTargetsMetadata()
 
@@ -107,6 +326,97 @@ func TestRemoveDelegation(t *testing.T) {
 	assert.Contains(t, targetsMetadata.Delegations.Keys, key.KeyID)
 }
 
+func TestRemoveKeyFromDelegations(t *testing.T) {
+	key1, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, err := tuf.LoadKeyFromBytes(targets2PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("key removed from rules", func(t *testing.T) {
+		targetsMetadata := InitializeTargetsMetadata()
+		targetsMetadata, err := AddDelegation(targetsMetadata, "rule-1", []*tuf.Key{key1, key2}, []string{"git:refs/heads/main"}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = AddDelegation(targetsMetadata, "rule-2", []*tuf.Key{key1}, []string{"git:refs/heads/release"}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = AddDelegation(targetsMetadata, "rule-3", []*tuf.Key{key2}, []string{"git:refs/tags/*"}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = AddRestrictedKeyToDelegation(targetsMetadata, "rule-1", key1, []string{tuf.KeyOperationCommit})
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = SetDelegationRotation(targetsMetadata, "rule-1", &tuf.RotationSchedule{
+			Start:  "2024-01-01T00:00:00Z",
+			Period: "168h",
+			Shifts: [][]string{{key1.KeyID, key2.KeyID}, {key2.KeyID}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ruleNames, err := RemoveKeyFromDelegations(targetsMetadata, key1.KeyID)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"rule-1", "rule-2"}, ruleNames)
+		assert.NotContains(t, targetsMetadata.Delegations.Keys, key1.KeyID)
+		assert.Contains(t, targetsMetadata.Delegations.Keys, key2.KeyID)
+
+		assert.Equal(t, []string{key2.KeyID}, targetsMetadata.Delegations.Roles[0].KeyIDs)
+		assert.Nil(t, targetsMetadata.Delegations.Roles[0].KeyOperations)
+		assert.Equal(t, [][]string{{key2.KeyID}, {key2.KeyID}}, targetsMetadata.Delegations.Roles[0].Rotation.Shifts)
+
+		// A rule left with no keys blocks all changes
+		assert.Empty(t, targetsMetadata.Delegations.Roles[1].KeyIDs)
+		assert.Equal(t, []string{key2.KeyID}, targetsMetadata.Delegations.Roles[2].KeyIDs)
+	})
+
+	t.Run("threshold cannot be met", func(t *testing.T) {
+		targetsMetadata := InitializeTargetsMetadata()
+		targetsMetadata, err := AddDelegation(targetsMetadata, "rule-1", []*tuf.Key{key1, key2}, []string{"git:refs/heads/main"}, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = RemoveKeyFromDelegations(targetsMetadata, key1.KeyID)
+		assert.ErrorIs(t, err, ErrCannotMeetThreshold)
+	})
+
+	t.Run("rotation shift left with no keys", func(t *testing.T) {
+		targetsMetadata := InitializeTargetsMetadata()
+		targetsMetadata, err := AddDelegation(targetsMetadata, "rule-1", []*tuf.Key{key1, key2}, []string{"git:refs/heads/main"}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = SetDelegationRotation(targetsMetadata, "rule-1", &tuf.RotationSchedule{
+			Start:  "2024-01-01T00:00:00Z",
+			Period: "168h",
+			Shifts: [][]string{{key1.KeyID}, {key2.KeyID}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = RemoveKeyFromDelegations(targetsMetadata, key1.KeyID)
+		assert.ErrorIs(t, err, tuf.ErrInvalidRotation)
+	})
+
+	t.Run("key not in rules", func(t *testing.T) {
+		targetsMetadata := InitializeTargetsMetadata()
+
+		ruleNames, err := RemoveKeyFromDelegations(targetsMetadata, key1.KeyID)
+		assert.Nil(t, err)
+		assert.Empty(t, ruleNames)
+	})
+}
+
 func TestAddKeyToTargets(t *testing.T) {
 	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
 	if err != nil {
@@ -0,0 +1,457 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	// VerificationCacheRef stores the results of prior successful verification
+	// runs. It is local to the repository and is never recorded in the RSL or
+	// pushed to remotes.
+	VerificationCacheRef = "refs/gittuf/verification-cache"
+
+	verificationCacheCommitMessage = "Update verification cache"
+)
+
+// verificationCacheEntry records the last RSL entry verified for a ref along
+// with the policy and attestations entries that applied to it.
+type verificationCacheEntry struct {
+	EntryID             string `json:"entryID"`
+	PolicyEntryID       string `json:"policyEntryID"`
+	AttestationsEntryID string `json:"attestationsEntryID,omitempty"`
+
+	// RSLTipID is the ID of the latest RSL entry when the entry was verified.
+	// Annotations up to it were considered in the verification.
+	RSLTipID string `json:"rslTipID,omitempty"`
+}
+
+// VerificationCache tracks the last verified RSL entry for each ref so that
+// subsequent verification runs only need to verify new entries.
+type VerificationCache struct {
+	// entries is keyed by the absolute ref path, such as `refs/heads/main`.
+	entries map[string]*verificationCacheEntry
+}
+
+// LoadVerificationCache loads the verification cache from the repository. If
+// the cache does not exist, an empty cache is returned.
+func LoadVerificationCache(repo *git.Repository) (*VerificationCache, error) {
+	cache := &VerificationCache{entries: map[string]*verificationCacheEntry{}}
+
+	ref, err := repo.Reference(plumbing.ReferenceName(VerificationCacheRef), true)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return cache, nil
+		}
+		return nil, err
+	}
+
+	commit, err := gitinterface.GetCommit(repo, ref.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := gitinterface.GetTree(repo, commit.TreeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := gitinterface.GetAllFilesInTree(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	for refName, blobID := range files {
+		contents, err := gitinterface.ReadBlob(repo, blobID)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := &verificationCacheEntry{}
+		if err := json.Unmarshal(contents, entry); err != nil {
+			return nil, err
+		}
+
+		cache.entries[refName] = entry
+	}
+
+	return cache, nil
+}
+
+// ResetVerificationCache removes the verification cache from the repository,
+// ensuring the next verification run verifies the entire RSL.
+func ResetVerificationCache(repo *git.Repository) error {
+	err := repo.Storer.RemoveReference(plumbing.ReferenceName(VerificationCacheRef))
+	if err != nil && !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// Commit writes the verification cache to the repository. The commit is never
+// signed as the cache is local state.
+func (c *VerificationCache) Commit(repo *git.Repository) error {
+	files := map[string]plumbing.Hash{}
+	for refName, entry := range c.entries {
+		contents, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+
+		blobID, err := gitinterface.WriteBlob(repo, contents)
+		if err != nil {
+			return err
+		}
+
+		files[refName] = blobID
+	}
+
+	treeID, err := gitinterface.NewTreeBuilder(repo).WriteRootTreeFromBlobIDs(files)
+	if err != nil {
+		return err
+	}
+
+	_, err = gitinterface.Commit(repo, treeID, VerificationCacheRef, verificationCacheCommitMessage, false)
+	return err
+}
+
+// getStartingPoint returns the last verified entry for the ref with the policy
+// and attestations entries that applied to it. If the ref has no cached result,
+// or the cached result is no longer valid, nil entries are returned. A cached
+// result is invalidated when the last verified entry is no longer in the RSL,
+// when the policy or attestations entries that applied to it have changed,
+// which happens when the RSL or the policy refs are rewritten, or when an
+// annotation recorded after it refers to it or to an earlier entry.
+func (c *VerificationCache) getStartingPoint(repo *git.Repository, target string) (*rsl.ReferenceEntry, *rsl.ReferenceEntry, *rsl.ReferenceEntry, error) {
+	cachedEntry, has := c.entries[target]
+	if !has {
+		return nil, nil, nil, nil
+	}
+
+	rslRef, err := repo.Reference(plumbing.ReferenceName(rsl.Ref), true)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	rslTip, err := gitinterface.GetCommit(repo, rslRef.Hash())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Any error from here onwards is treated as the cache being invalid, as
+	// the RSL may have been rewritten since the cache was recorded
+	entryCommit, err := gitinterface.GetCommit(repo, plumbing.NewHash(cachedEntry.EntryID))
+	if err != nil {
+		return nil, nil, nil, nil //nolint:nilerr
+	}
+
+	entry, err := rsl.GetEntry(repo, entryCommit.Hash)
+	if err != nil {
+		return nil, nil, nil, nil //nolint:nilerr
+	}
+	lastVerifiedEntry, isReferenceEntry := entry.(*rsl.ReferenceEntry)
+	if !isReferenceEntry || lastVerifiedEntry.RefName != target {
+		return nil, nil, nil, nil
+	}
+
+	// An entry recorded in an RSL shard is in the RSL if the shard tip
+	// recorded in the RSL includes it
+	tipID := rslTip.Hash
+	if lastVerifiedEntry.Shard != "" {
+		indexEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, rsl.ShardRef(lastVerifiedEntry.Shard))
+		if err != nil {
+			return nil, nil, nil, nil //nolint:nilerr
+		}
+		tipID = indexEntry.TargetID
+	}
+	if knows, err := gitinterface.KnowsCommit(repo, tipID, entryCommit); err != nil || !knows {
+		slog.Debug("Cached entry is not in the RSL, invalidating cache...")
+		return nil, nil, nil, nil //nolint:nilerr
+	}
+
+	policyEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, PolicyRef, lastVerifiedEntry.ID)
+	if err != nil {
+		return nil, nil, nil, nil //nolint:nilerr
+	}
+	if policyEntry.ID.String() != cachedEntry.PolicyEntryID {
+		slog.Debug("Policy applicable to cached entry has changed, invalidating cache...")
+		return nil, nil, nil, nil
+	}
+
+	var attestationsEntry *rsl.ReferenceEntry
+	attestationsEntry, _, err = rsl.GetLatestReferenceEntryForRefBefore(repo, attestations.Ref, lastVerifiedEntry.ID)
+	if err != nil {
+		if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return nil, nil, nil, nil //nolint:nilerr
+		}
+	}
+	attestationsEntryID := ""
+	if attestationsEntry != nil {
+		attestationsEntryID = attestationsEntry.ID.String()
+	}
+	if attestationsEntryID != cachedEntry.AttestationsEntryID {
+		slog.Debug("Attestations applicable to cached entry have changed, invalidating cache...")
+		return nil, nil, nil, nil
+	}
+
+	annotated, err := hasLaterAnnotationsForVerifiedEntries(repo, lastVerifiedEntry, plumbing.NewHash(cachedEntry.RSLTipID))
+	if err != nil || annotated {
+		slog.Debug("Annotations recorded after cached entry refer to verified entries, invalidating cache...")
+		return nil, nil, nil, nil //nolint:nilerr
+	}
+
+	return lastVerifiedEntry, policyEntry, attestationsEntry, nil
+}
+
+// hasLaterAnnotationsForVerifiedEntries returns true if an annotation recorded
+// after the entry refers to the entry or to an entry before it. Such an
+// annotation, such as one skipping an entry that was already verified, changes
+// the result of verifying the entries covered by the cache. If checkedTipID is
+// set, annotations up to it were considered when the entry was verified, and
+// only the annotations after it are checked.
+func hasLaterAnnotationsForVerifiedEntries(repo *git.Repository, entry *rsl.ReferenceEntry, checkedTipID plumbing.Hash) (bool, error) {
+	laterEntryIDs := map[plumbing.Hash]bool{}
+
+	// An entry in an RSL shard is placed in the RSL at the index entry that
+	// records it, and the entries the index entry records after it are later
+	// entries
+	stopID := entry.ID
+	if entry.Shard != "" {
+		indexEntry, err := rsl.GetIndexEntryForShardEntry(repo, entry)
+		if err != nil {
+			return false, err
+		}
+		stopID = indexEntry.ID
+
+		shardEntries, err := rsl.GetShardEntries(repo, indexEntry)
+		if err != nil {
+			return false, err
+		}
+		isLater := false
+		for _, shardEntry := range shardEntries {
+			if isLater {
+				laterEntryIDs[shardEntry.ID] = true
+			}
+			if shardEntry.ID == entry.ID {
+				isLater = true
+			}
+		}
+	}
+
+	iterator, err := rsl.NewIterator(repo)
+	if err != nil {
+		return false, err
+	}
+
+	annotations := []*rsl.AnnotationEntry{}
+	checkAnnotations := true
+	for {
+		current, err := iterator.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return false, rsl.ErrRSLEntryNotFound
+			}
+			return false, err
+		}
+
+		if current.GetID() == stopID {
+			break
+		}
+		laterEntryIDs[current.GetID()] = true
+		if current.GetID() == checkedTipID {
+			checkAnnotations = false
+		}
+
+		switch current := current.(type) {
+		case *rsl.AnnotationEntry:
+			if checkAnnotations {
+				annotations = append(annotations, current)
+			}
+		case *rsl.ReferenceEntry:
+			if rsl.IsShardRef(current.RefName) {
+				shardEntries, err := rsl.GetShardEntries(repo, current)
+				if err != nil {
+					return false, err
+				}
+				for _, shardEntry := range shardEntries {
+					laterEntryIDs[shardEntry.ID] = true
+				}
+			}
+		}
+	}
+
+	for _, annotation := range annotations {
+		for _, entryID := range annotation.RSLEntryIDs {
+			if !laterEntryIDs[entryID] {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// set records entry as the last verified entry for the ref.
+func (c *VerificationCache) set(repo *git.Repository, target string, entry *rsl.ReferenceEntry) error {
+	policyEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, PolicyRef, entry.ID)
+	if err != nil {
+		return err
+	}
+
+	rslTip, err := rsl.GetLatestEntry(repo)
+	if err != nil {
+		return err
+	}
+
+	cachedEntry := &verificationCacheEntry{
+		EntryID:       entry.ID.String(),
+		PolicyEntryID: policyEntry.ID.String(),
+		RSLTipID:      rslTip.GetID().String(),
+	}
+
+	attestationsEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, attestations.Ref, entry.ID)
+	if err != nil {
+		if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return err
+		}
+	} else {
+		cachedEntry.AttestationsEntryID = attestationsEntry.ID.String()
+	}
+
+	c.entries[target] = cachedEntry
+	return nil
+}
+
+// VerifyRefFullWithCache verifies the RSL for the target ref like
+// VerifyRefFull, but only verifies entries recorded after the last entry
+// verified by a prior successful run. The verification cache is updated when
+// verification succeeds. The expected Git ID for the ref in the latest RSL
+// entry is returned if the policy verification is successful.
+func VerifyRefFullWithCache(ctx context.Context, repo *git.Repository, target string) (plumbing.Hash, error) {
+	slog.Debug("Loading verification cache...")
+	cache, err := LoadVerificationCache(repo)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	lastVerifiedEntry, policyEntry, attestationsEntry, err := cache.getStartingPoint(repo, target)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	// Find latest entry for target
+	slog.Debug(fmt.Sprintf("Identifying latest RSL entry for '%s'...", target))
+	latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, target)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	if lastVerifiedEntry == nil {
+		slog.Debug("No valid cached verification found, verifying all entries...")
+		if _, err := VerifyRefFull(ctx, repo, target); err != nil {
+			return plumbing.ZeroHash, err
+		}
+	} else if lastVerifiedEntry.ID != latestEntry.ID {
+		slog.Debug(fmt.Sprintf("Verifying entries after cached entry '%s'...", lastVerifiedEntry.ID.String()))
+		if err := VerifyRelativeForRef(ctx, repo, policyEntry, attestationsEntry, lastVerifiedEntry, latestEntry, target); err != nil {
+			return plumbing.ZeroHash, err
+		}
+	} else {
+		slog.Debug("Latest entry has been verified already...")
+		return latestEntry.TargetID, nil
+	}
+
+	slog.Debug("Updating verification cache...")
+	if err := cache.set(repo, target, latestEntry); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if err := cache.Commit(repo); err != nil {
+		// Verification succeeded, failing to cache the result only means the
+		// next run will do more work
+		slog.Warn(fmt.Sprintf("Unable to update verification cache: %s", err.Error()))
+	}
+
+	return latestEntry.TargetID, nil
+}
+
+// VerificationCacheHistoryLength returns the number of updates recorded in the
+// history of the verification cache. Only the latest update is used, so the
+// history can be discarded using CompactVerificationCache.
+func VerificationCacheHistoryLength(repo *git.Repository) (int, error) {
+	ref, err := repo.Reference(plumbing.ReferenceName(VerificationCacheRef), true)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	length := 0
+	commitID := ref.Hash()
+	for !commitID.IsZero() {
+		commit, err := gitinterface.GetCommit(repo, commitID)
+		if err != nil {
+			return 0, err
+		}
+		length++
+
+		commitID = plumbing.ZeroHash
+		if len(commit.ParentHashes) > 0 {
+			commitID = commit.ParentHashes[0]
+		}
+	}
+
+	return length, nil
+}
+
+// CompactVerificationCache rewrites the verification cache as a single update
+// without history, dropping the cached results for refs that no longer exist in
+// the repository. The names of the refs whose results are dropped are returned.
+// If dryRun is set, the cache is not modified.
+func CompactVerificationCache(repo *git.Repository, dryRun bool) ([]string, error) {
+	cache, err := LoadVerificationCache(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	removedRefs := []string{}
+	for refName := range cache.entries {
+		if _, err := repo.Reference(plumbing.ReferenceName(refName), true); err != nil {
+			if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+				return nil, err
+			}
+
+			removedRefs = append(removedRefs, refName)
+			delete(cache.entries, refName)
+		}
+	}
+	sort.Strings(removedRefs)
+
+	if dryRun {
+		return removedRefs, nil
+	}
+
+	// Removing the ref first means the cache is committed without a parent
+	if err := ResetVerificationCache(repo); err != nil {
+		return nil, err
+	}
+
+	if len(cache.entries) == 0 {
+		return removedRefs, nil
+	}
+
+	return removedRefs, cache.Commit(repo)
+}
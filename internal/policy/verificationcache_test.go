@@ -0,0 +1,247 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyRefFullWithCache(t *testing.T) {
+	t.Run("cache is populated and used", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+		refName := "refs/heads/main"
+
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		currentTip, err := VerifyRefFullWithCache(testCtx, repo, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, commitIDs[0], currentTip)
+
+		cache, err := LoadVerificationCache(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, entryID.String(), cache.entries[refName].EntryID)
+
+		// Verification with no new entries succeeds
+		currentTip, err = VerifyRefFullWithCache(testCtx, repo, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, commitIDs[0], currentTip)
+
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		currentTip, err = VerifyRefFullWithCache(testCtx, repo, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, commitIDs[0], currentTip)
+
+		cache, err = LoadVerificationCache(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, entryID.String(), cache.entries[refName].EntryID)
+
+		// New entries are still verified
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgUnauthorizedKeyBytes)
+		entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+		common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgUnauthorizedKeyBytes)
+
+		_, err = VerifyRefFullWithCache(testCtx, repo, refName)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		// The cache is not updated on failure
+		cache, err = LoadVerificationCache(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, entryID.String(), cache.entries[refName].EntryID)
+	})
+
+	t.Run("entries before cached entry are not verified again", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+		refName := "refs/heads/main"
+
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+
+		// Policy violation
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgUnauthorizedKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgUnauthorizedKeyBytes)
+
+		// Not policy violation by itself
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		_, err := VerifyRefFullWithCache(testCtx, repo, refName)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		// Record the non-violating entry in the cache
+		cache, err := LoadVerificationCache(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cache.set(repo, refName, entry); err != nil {
+			t.Fatal(err)
+		}
+		if err := cache.Commit(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		currentTip, err := VerifyRefFullWithCache(testCtx, repo, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, commitIDs[0], currentTip)
+
+		// The cache is invalidated if the policy for the cached entry changes
+		cache.entries[refName].PolicyEntryID = plumbing.ZeroHash.String()
+		if err := cache.Commit(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = VerifyRefFullWithCache(testCtx, repo, refName)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		// Resetting the cache results in full verification
+		if err := cache.set(repo, refName, entry); err != nil {
+			t.Fatal(err)
+		}
+		if err := cache.Commit(repo); err != nil {
+			t.Fatal(err)
+		}
+		if err := ResetVerificationCache(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = VerifyRefFullWithCache(testCtx, repo, refName)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
+
+	t.Run("cache is invalidated by later annotations for verified entries", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+		refName := "refs/heads/main"
+
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		if _, err := VerifyRefFullWithCache(testCtx, repo, refName); err != nil {
+			t.Fatal(err)
+		}
+
+		cache, err := LoadVerificationCache(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lastVerifiedEntry, _, _, err := cache.getStartingPoint(repo, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, entryID, lastVerifiedEntry.ID)
+
+		// An annotation for the cached entry invalidates the cache
+		annotation := rsl.NewAnnotationEntry([]plumbing.Hash{entryID}, true, "skip verified entry")
+		common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, gpgKeyBytes)
+
+		lastVerifiedEntry, _, _, err = cache.getStartingPoint(repo, refName)
+		assert.Nil(t, err)
+		assert.Nil(t, lastVerifiedEntry)
+
+		// Annotations considered when the entry was verified don't invalidate
+		// the cache
+		if _, err := VerifyRefFullWithCache(testCtx, repo, refName); err != nil {
+			t.Fatal(err)
+		}
+
+		cache, err = LoadVerificationCache(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lastVerifiedEntry, _, _, err = cache.getStartingPoint(repo, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, entryID, lastVerifiedEntry.ID)
+
+		// Annotations for entries after the cached entry don't invalidate the
+		// cache
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+		newEntryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		annotation = rsl.NewAnnotationEntry([]plumbing.Hash{newEntryID}, false, "annotate new entry")
+		common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, gpgKeyBytes)
+
+		lastVerifiedEntry, _, _, err = cache.getStartingPoint(repo, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, entryID, lastVerifiedEntry.ID)
+	})
+}
+
+func TestCompactVerificationCache(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		if _, err := VerifyRefFullWithCache(testCtx, repo, refName); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	length, err := VerificationCacheHistoryLength(repo)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, length)
+
+	// Mimic a cached result for a ref that has since been deleted
+	cache, err := LoadVerificationCache(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.entries["refs/heads/deleted"] = cache.entries[refName]
+	if err := cache.Commit(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	removedRefs, err := CompactVerificationCache(repo, true)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"refs/heads/deleted"}, removedRefs)
+
+	length, err = VerificationCacheHistoryLength(repo)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, length)
+
+	removedRefs, err = CompactVerificationCache(repo, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"refs/heads/deleted"}, removedRefs)
+
+	length, err = VerificationCacheHistoryLength(repo)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, length)
+
+	cache, err = LoadVerificationCache(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, cache.entries, refName)
+	assert.NotContains(t, cache.entries, "refs/heads/deleted")
+}
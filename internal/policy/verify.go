@@ -4,17 +4,26 @@ package policy
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/identities"
+	"github.com/gittuf/gittuf/internal/perf"
+	"github.com/gittuf/gittuf/internal/progress"
+	"github.com/gittuf/gittuf/internal/report"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/signerverifier/common"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/x509"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -30,6 +39,7 @@ const (
 	unableToLoadPolicyMessageFmt      = "unable to load applicable gittuf policy: %s"
 	unableToFindPolicyMessage         = "unable to find applicable gittuf policy"
 	goodSignatureMessageFmt           = "good signature from key '%s:%s'"
+	unauthorizedForPathMessageFmt     = "good signature from key '%s:%s', but key is not authorized to modify '%s'"
 	goodTagSignatureMessage           = "good signature for RSL entry and tag"
 	goodSignatureMessageForRSLEntry   = "good signature for RSL entry"
 	badSignatureMessageForRSLEntry    = "bad signature for RSL entry"
@@ -46,6 +56,17 @@ var (
 	ErrUnknownObjectType       = errors.New("unknown object type passed to verify signature")
 	ErrInvalidVerifier         = errors.New("verifier has invalid parameters (is threshold 0?)")
 	ErrVerifierConditionsUnmet = errors.New("verifier's key and threshold constraints not met")
+	ErrNotTagRef               = errors.New(nonTagMessage)
+	ErrMultipleTagRSLEntries   = errors.New(multipleTagRSLEntriesFoundMessage)
+	ErrRangeNotInRSL           = errors.New("range of ref updates is not recorded in the RSL")
+	ErrChangedPathsMismatch    = errors.New("changed paths recorded in RSL entry do not match the changes to the ref")
+	ErrSubmodulesMismatch      = errors.New("submodules recorded in RSL entry do not match the submodules in the ref's target")
+	ErrTicketRequired          = errors.New("RSL entry does not record a ticket, which is required for changes to the ref")
+	ErrUpdateTypeNotAllowed    = errors.New("RSL entry records an update to the ref that is not allowed")
+	ErrNotAnnotatedTag         = errors.New("tag is not an annotated tag")
+	ErrTagNameMismatch         = errors.New("tag object's name does not match tag reference")
+	ErrTagTargetMismatch       = errors.New("tag reference set to unexpected target")
+	ErrTagTargetNotCommit      = errors.New("tag does not resolve to a commit")
 )
 
 // VerifyRef verifies the signature on the latest RSL entry for the target ref
@@ -74,13 +95,32 @@ func VerifyRef(ctx context.Context, repo *git.Repository, target string) (plumbi
 	}
 
 	slog.Debug("Verifying entry...")
-	return latestEntry.TargetID, verifyEntry(ctx, repo, policyState, attestationsState, latestEntry)
+	progress.Stage("verifying RSL entries", 1)
+	report.RecordEntry(latestEntry.ID.String(), latestEntry.RefName, latestEntry.TargetID.String())
+	if err := verifyEntry(ctx, repo, policyState, attestationsState, latestEntry); err != nil {
+		report.RecordViolation(latestEntry.ID.String(), err)
+		return latestEntry.TargetID, err
+	}
+	progress.Advance()
+
+	return latestEntry.TargetID, nil
 }
 
 // VerifyRefFull verifies the entire RSL for the target ref from the first
 // entry. The expected Git ID for the ref in the latest RSL entry is returned if
 // the policy verification is successful.
 func VerifyRefFull(ctx context.Context, repo *git.Repository, target string) (plumbing.Hash, error) {
+	return verifyRefFull(ctx, repo, target, false)
+}
+
+// VerifyRefFullKeepGoing verifies the entire RSL for the target ref like
+// VerifyRefFull, but continues past policy violations. If any are found, an
+// ErrPolicyViolations recording all of them is returned.
+func VerifyRefFullKeepGoing(ctx context.Context, repo *git.Repository, target string) (plumbing.Hash, error) {
+	return verifyRefFull(ctx, repo, target, true)
+}
+
+func verifyRefFull(ctx context.Context, repo *git.Repository, target string, keepGoing bool) (plumbing.Hash, error) {
 	// Trace RSL back to the start
 	slog.Debug("Identifying first RSL entry...")
 	firstEntry, _, err := rsl.GetFirstEntry(repo)
@@ -98,7 +138,7 @@ func VerifyRefFull(ctx context.Context, repo *git.Repository, target string) (pl
 	// Do a relative verify from start entry to the latest entry (firstEntry here == policyEntry)
 	// Also, attestations is initially nil because we haven't seen any yet
 	slog.Debug("Verifying all entries...")
-	return latestEntry.TargetID, VerifyRelativeForRef(ctx, repo, firstEntry, nil, firstEntry, latestEntry, target)
+	return latestEntry.TargetID, verifyRelativeForRef(ctx, repo, firstEntry, nil, firstEntry, latestEntry, target, keepGoing)
 }
 
 // VerifyRefFromEntry performs verification for the reference from a specific
@@ -147,11 +187,92 @@ func VerifyRefFromEntry(ctx context.Context, repo *git.Repository, target string
 	return latestEntry.TargetID, VerifyRelativeForRef(ctx, repo, policyEntry, attestationsEntry, fromEntry, latestEntry, target)
 }
 
+// VerifyRefRange verifies the RSL entries for the target ref that record the
+// ref being updated from oldID to newID, such as the range a Git server's
+// pre-receive hook reports for a push. The latest RSL entry for the ref must
+// record newID. If oldID is zero, i.e., the ref is being created, every entry
+// for the ref is verified. Otherwise, verification starts at the latest entry
+// for the ref that records oldID.
+func VerifyRefRange(ctx context.Context, repo *git.Repository, target string, oldID, newID plumbing.Hash) error {
+	return verifyRefRange(ctx, repo, target, oldID, newID, false)
+}
+
+// VerifyRefRangeKeepGoing verifies the RSL entries for the update of the
+// target ref like VerifyRefRange, but continues past policy violations. If any
+// are found, an ErrPolicyViolations recording all of them is returned.
+func VerifyRefRangeKeepGoing(ctx context.Context, repo *git.Repository, target string, oldID, newID plumbing.Hash) error {
+	return verifyRefRange(ctx, repo, target, oldID, newID, true)
+}
+
+func verifyRefRange(ctx context.Context, repo *git.Repository, target string, oldID, newID plumbing.Hash, keepGoing bool) error {
+	slog.Debug(fmt.Sprintf("Identifying latest RSL entry for '%s'...", target))
+	latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, target)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return fmt.Errorf("%w: no entry found for '%s'", ErrRangeNotInRSL, target)
+		}
+		return err
+	}
+	if latestEntry.TargetID != newID {
+		return fmt.Errorf("%w: latest entry '%s' records '%s', not '%s'", ErrRangeNotInRSL, latestEntry.ID.String(), latestEntry.TargetID.String(), newID.String())
+	}
+
+	if oldID.IsZero() {
+		slog.Debug("Identifying first RSL entry...")
+		firstEntry, _, err := rsl.GetFirstEntry(repo)
+		if err != nil {
+			return err
+		}
+
+		slog.Debug("Verifying all entries...")
+		return verifyRelativeForRef(ctx, repo, firstEntry, nil, firstEntry, latestEntry, target, keepGoing)
+	}
+
+	slog.Debug(fmt.Sprintf("Identifying RSL entry that records '%s'...", oldID.String()))
+	fromEntry := latestEntry
+	for fromEntry.TargetID != oldID {
+		fromEntry, _, err = rsl.GetLatestReferenceEntryForRefBefore(repo, target, fromEntry.ID)
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				return fmt.Errorf("%w: no entry found for '%s' recording '%s'", ErrRangeNotInRSL, target, oldID.String())
+			}
+			return err
+		}
+	}
+
+	slog.Debug("Identifying applicable policy entry...")
+	policyEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, PolicyRef, fromEntry.ID)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Identifying applicable attestations entry...")
+	attestationsEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, attestations.Ref, fromEntry.ID)
+	if err != nil {
+		if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return err
+		}
+		attestationsEntry = nil
+	}
+
+	slog.Debug("Verifying entries in range...")
+	return verifyRelativeForRef(ctx, repo, policyEntry, attestationsEntry, fromEntry, latestEntry, target, keepGoing)
+}
+
 // VerifyRelativeForRef verifies the RSL between specified start and end entries
 // using the provided policy entry for the first entry.
 //
 // TODO: should the policy entry be inferred from the specified first entry?
 func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPolicyEntry, initialAttestationsEntry, firstEntry, lastEntry *rsl.ReferenceEntry, target string) error {
+	return verifyRelativeForRef(ctx, repo, initialPolicyEntry, initialAttestationsEntry, firstEntry, lastEntry, target, false)
+}
+
+// verifyRelativeForRef verifies the RSL between the specified start and end
+// entries. If keepGoing is set, verification continues past policy violations
+// where possible, and the violations found are returned together.
+func verifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPolicyEntry, initialAttestationsEntry, firstEntry, lastEntry *rsl.ReferenceEntry, target string, keepGoing bool) error {
+	violations := newViolationCollector(keepGoing)
+
 	var (
 		currentPolicy       *State
 		currentAttestations *attestations.Attestations
@@ -176,12 +297,84 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 
 	// Enumerate RSL entries between firstEntry and lastEntry, ignoring irrelevant ones
 	slog.Debug("Identifying all entries in range...")
+	progress.Stage("identifying RSL entries", 0)
 	entries, annotations, err := rsl.GetReferenceEntriesInRangeForRef(repo, firstEntry.ID, lastEntry.ID, target)
 	if err != nil {
 		return err
 	}
 
-	// Verify each entry, looking for a fix when an invalid entry is encountered
+	// Identify the policy and attestations in effect for each entry. Policy
+	// changes are verified here in order, so that the signatures and
+	// attestations for the remaining entries can be verified concurrently.
+	slog.Debug("Identifying applicable policy and attestations for all entries...")
+	verifications := map[plumbing.Hash]*entryVerification{}
+	pendingVerifications := []*entryVerification{}
+	transitionErrs := map[plumbing.Hash]error{}
+	shardErrs := map[plumbing.Hash]error{}
+	for _, entry := range entries {
+		if entry.RefName == PolicyStagingRef {
+			continue
+		}
+
+		if rsl.IsShardRef(entry.RefName) {
+			// The shard entries recorded by an index entry must be for refs
+			// the policy in effect records in the shard
+			if err := currentPolicy.verifyRSLShardIndexEntry(repo, entry); err != nil {
+				shardErrs[entry.ID] = err
+			}
+			continue
+		}
+
+		if entry.RefName == PolicyRef {
+			// TODO: this is repetition if the firstEntry is for policy
+			newPolicy, err := loadStateForEntry(repo, entry)
+			if err == nil {
+				slog.Debug(fmt.Sprintf("Verifying new policy in entry '%s' using current policy...", entry.ID.String()))
+				err = currentPolicy.VerifyNewState(ctx, newPolicy)
+			}
+			if err == nil && entry.ID != initialPolicyEntry.ID {
+				// The initial policy's approvals were verified when it was
+				// loaded, using the policy before it
+				err = verifyPolicyEntryApproval(ctx, repo, currentPolicy, newPolicy, entry)
+			}
+			if err != nil {
+				// Entries from here on can't be verified, the error is
+				// returned if verification reaches this entry
+				transitionErrs[entry.ID] = err
+				break
+			}
+
+			currentPolicy = newPolicy
+			report.RecordPolicyState(entry.ID.String())
+			continue
+		}
+
+		if entry.RefName == attestations.Ref {
+			newAttestationsState, err := attestations.LoadAttestationsForEntry(repo, entry)
+			if err != nil {
+				transitionErrs[entry.ID] = err
+				break
+			}
+
+			currentAttestations = newAttestationsState
+			continue
+		}
+
+		if err := currentPolicy.verifyRSLShardForEntry(entry); err != nil {
+			shardErrs[entry.ID] = err
+		}
+
+		verification := newEntryVerification(entry, currentPolicy, currentAttestations)
+		verifications[entry.ID] = verification
+		pendingVerifications = append(pendingVerifications, verification)
+	}
+
+	progress.Stage("verifying RSL entries", len(pendingVerifications))
+	stopVerification := verifyEntriesConcurrently(ctx, repo, pendingVerifications)
+	defer stopVerification()
+
+	// Check the results of each entry in order, looking for a fix when an
+	// invalid entry is encountered
 	var invalidEntry *rsl.ReferenceEntry
 	var verificationErr error
 	for len(entries) != 0 {
@@ -196,41 +389,52 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 			if entry.RefName == PolicyStagingRef {
 				continue
 			}
-			slog.Debug("Checking if entry is for policy reference...")
-			if entry.RefName == PolicyRef {
-				// TODO: this is repetition if the firstEntry is for policy
-				newPolicy, err := loadStateForEntry(repo, entry)
-				if err != nil {
-					return err
-				}
-
-				slog.Debug("Verifying new policy using current policy...")
-				if err := currentPolicy.VerifyNewState(ctx, newPolicy); err != nil {
-					return err
+			slog.Debug("Checking if entry is an RSL shard index entry...")
+			if rsl.IsShardRef(entry.RefName) {
+				// The shard entries for the target recorded by the index
+				// entry are verified as entries of their own
+				if err := shardErrs[entry.ID]; err != nil {
+					if err := violations.record(entry.ID, err); err != nil {
+						return err
+					}
 				}
-
-				slog.Debug("Updating current policy...")
-				currentPolicy = newPolicy
 				continue
 			}
-
-			slog.Debug("Checking if entry is for attestations reference...")
-			if entry.RefName == attestations.Ref {
-				newAttestationsState, err := attestations.LoadAttestationsForEntry(repo, entry)
-				if err != nil {
-					return err
+			slog.Debug("Checking if entry is for policy or attestations reference...")
+			if entry.RefName == PolicyRef || entry.RefName == attestations.Ref {
+				// These were loaded and verified when identifying the policy
+				// and attestations for each entry
+				if err := transitionErrs[entry.ID]; err != nil {
+					// Later entries can't be verified without the policy
+					// or attestations in this entry
+					return violations.stop(entry.ID, err)
 				}
-
-				currentAttestations = newAttestationsState
 				continue
 			}
 
-			slog.Debug("Verifying changes...")
-			if err := verifyEntry(ctx, repo, currentPolicy, currentAttestations, entry); err != nil {
+			verification, scheduled := verifications[entry.ID]
+			if !scheduled {
+				// Only entries after a failed policy or attestations update
+				// aren't scheduled, and verification stops at the update
+				return fmt.Errorf("entry '%s' was not scheduled for verification", entry.ID.String())
+			}
+			report.RecordEntry(entry.ID.String(), entry.RefName, entry.TargetID.String())
+
+			slog.Debug("Waiting for verification of changes...")
+			err := verification.wait(ctx)
+			if err == nil {
+				// The entry must be recorded where the policy in effect
+				// records the ref's entries
+				err = shardErrs[entry.ID]
+			}
+			if err != nil {
 				slog.Debug("Violation found, checking if entry has been revoked...")
 				// If the invalid entry is never marked as skipped, we return err
 				if !entry.SkippedBy(annotations[entry.ID]) {
-					return err
+					if err := violations.record(entry.ID, err); err != nil {
+						return err
+					}
+					continue
 				}
 
 				// The invalid entry's been marked as skipped but we still need
@@ -241,7 +445,10 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 
 				if len(entries) == 0 {
 					// Fix entry does not exist after revoking annotation
-					return verificationErr
+					if err := violations.record(entry.ID, verificationErr); err != nil {
+						return err
+					}
+					invalidEntry = nil
 				}
 			}
 			continue
@@ -271,7 +478,12 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 		}
 		slog.Debug("Verifying identified last valid entry has not been revoked...")
 		if lastGoodEntry.SkippedBy(lastGoodEntryAnnotations) {
-			return ErrLastGoodEntryIsSkipped
+			if err := violations.record(invalidEntry.ID, ErrLastGoodEntryIsSkipped); err != nil {
+				return err
+			}
+			invalidEntry = nil
+			verificationErr = nil
+			continue
 		}
 		lastGoodEntryCommit, err := gitinterface.GetCommit(repo, lastGoodEntry.TargetID)
 		if err != nil {
@@ -333,13 +545,17 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 
 		if !fixed {
 			// If we haven't found a fix, return the original error
-			return verificationErr
+			if err := violations.record(invalidEntry.ID, verificationErr); err != nil {
+				return err
+			}
 		}
 
-		if len(invalidIntermediateEntries) != 0 {
-			// We may have found a fix but if an invalid intermediate entry
-			// wasn't skipped, return error
-			return ErrInvalidEntryNotSkipped
+		// We may have found a fix but if an invalid intermediate entry wasn't
+		// skipped, return error
+		for _, invalidIntermediateEntry := range invalidIntermediateEntries {
+			if err := violations.record(invalidIntermediateEntry.ID, ErrInvalidEntryNotSkipped); err != nil {
+				return err
+			}
 		}
 
 		// Reset these trackers to continue verification with rest of the queue
@@ -350,18 +566,22 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 		entries = newEntryQueue
 	}
 
-	return nil
+	return violations.result()
 }
 
 // VerifyCommit verifies the signature on the specified commits (identified by
 // their hash or via a reference that is resolved). For each commit, the policy
 // applicable when the commit was first recorded (directly or indirectly) in the
-// RSL is used. The function returns a map that identifies the verification
-// status for each of the submitted IDs. All commit IDs that are passed in will
-// have an entry in the returned status. The status is currently meant to be
-// consumed directly by the user, as this is used for a special, user-invoked
-// workflow. gittuf's other verification workflows are currently not expected to
-// use this function.
+// RSL is used. Commits that haven't been recorded in the RSL yet, such as those
+// under review, are verified using the latest policy. Keys restricted to
+// specific operations, such as keys that may only sign merge commits, are only
+// trusted for those operations. The signing key must be trusted for every file
+// path modified by the commit. The function returns a map that identifies the
+// verification status for each of the submitted IDs. All commit IDs that are
+// passed in will have an entry in the returned status. The status is currently
+// meant to be consumed directly by the user, as this is used for a special,
+// user-invoked workflow. gittuf's other verification workflows are currently
+// not expected to use this function.
 func VerifyCommit(ctx context.Context, repo *git.Repository, ids ...string) map[string]string {
 	status := make(map[string]string, len(ids))
 	commits := make(map[string]*object.Commit, len(ids))
@@ -393,7 +613,6 @@ func VerifyCommit(ctx context.Context, repo *git.Repository, ids ...string) map[
 	}
 
 	for id, commit := range commits {
-		verified := false
 		if len(commit.PGPSignature) == 0 {
 			status[id] = noSignatureMessage
 			continue
@@ -405,24 +624,41 @@ func VerifyCommit(ctx context.Context, repo *git.Repository, ids ...string) map[
 			continue
 		}
 		if commitPolicy == nil {
-			status[id] = unableToFindPolicyMessage
-			continue
+			// The commit hasn't been recorded in the RSL yet, such as when it
+			// is being reviewed, so we use the latest policy
+			commitPolicy, err = LoadCurrentState(ctx, repo, PolicyRef)
+			if err != nil {
+				if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+					status[id] = unableToFindPolicyMessage
+				} else {
+					status[id] = fmt.Sprintf(unableToLoadPolicyMessageFmt, err.Error())
+				}
+				continue
+			}
 		}
 
-		// TODO: Add `applyFilePolicies` flag that uses the commitPolicy to
-		// check that the commit signature is from a key trusted for all the
-		// paths modified by the commit.
-
-		keys, err := commitPolicy.PublicKeys()
+		policyVerifier, err := commitPolicy.getUnprotectedVerifier(id)
 		if err != nil {
 			status[id] = fmt.Sprintf(unableToLoadPolicyMessageFmt, err.Error())
 			continue
 		}
-		for _, key := range keys {
+
+		// Keys restricted to specific operations, such as keys that may only
+		// sign merge commits, aren't trusted for other commits
+		operation := tuf.KeyOperationCommit
+		if len(commit.ParentHashes) > 1 {
+			operation = tuf.KeyOperationMergeCommit
+		}
+
+		var signingKey *tuf.Key
+		for _, key := range policyVerifier.Keys() {
+			if !policyVerifier.allowsKeyOperation(key.KeyID, operation) {
+				continue
+			}
+
 			err = gitinterface.VerifyCommitSignature(ctx, commit, key)
 			if err == nil {
-				verified = true
-				status[id] = fmt.Sprintf(goodSignatureMessageFmt, key.KeyType, key.KeyID)
+				signingKey = key
 				break
 			}
 
@@ -437,18 +673,94 @@ func VerifyCommit(ctx context.Context, repo *git.Repository, ids ...string) map[
 			}
 		}
 
-		if !verified {
+		if signingKey == nil {
 			status[id] = noPublicKeyMessage
+			continue
+		}
+
+		// Check that the signing key is trusted for all the paths modified by
+		// the commit
+		unauthorizedPath, err := verifyCommitFilePaths(ctx, repo, commitPolicy, commit)
+		switch {
+		case err != nil:
+			status[id] = fmt.Sprintf(errorVerifyingSignatureMessageFmt, signingKey.KeyType, signingKey.KeyID, err.Error())
+		case unauthorizedPath != "":
+			status[id] = fmt.Sprintf(unauthorizedForPathMessageFmt, signingKey.KeyType, signingKey.KeyID, unauthorizedPath)
+		default:
+			status[id] = fmt.Sprintf(goodSignatureMessageFmt, signingKey.KeyType, signingKey.KeyID)
 		}
 	}
 
 	return status
 }
 
+// verifyCommitFilePaths checks that the commit's signature is trusted by the
+// policy for every file path modified by the commit. The first path for which
+// the signature isn't trusted is returned. If the signature is trusted for all
+// paths, an empty string is returned.
+func verifyCommitFilePaths(ctx context.Context, repo *git.Repository, policy *State, commit *object.Commit) (string, error) {
+	hasFileRule, err := policy.hasFileRule()
+	if err != nil {
+		return "", err
+	}
+	if !hasFileRule {
+		return "", nil
+	}
+
+	paths, err := gitinterface.GetFilePathsChangedByCommit(repo, commit)
+	if err != nil {
+		return "", err
+	}
+
+	// verifierResults caches the result of each verifier as several paths are
+	// likely protected by the same rules
+	verifierResults := map[string]bool{}
+	for _, path := range paths {
+		verifiers, err := policy.FindVerifiersForPath(fmt.Sprintf("%s:%s", fileRuleScheme, path))
+		if err != nil {
+			return "", err
+		}
+
+		if len(verifiers) == 0 {
+			continue
+		}
+
+		pathVerified := false
+		for _, verifier := range verifiers {
+			verified, has := verifierResults[verifier.Name()]
+			if !has {
+				err := verifier.Verify(ctx, commit, nil)
+				if err != nil && !errors.Is(err, ErrVerifierConditionsUnmet) {
+					return "", err
+				}
+				verified = err == nil
+				verifierResults[verifier.Name()] = verified
+			}
+
+			if verified {
+				pathVerified = true
+				break
+			}
+		}
+
+		if !pathVerified {
+			return path, nil
+		}
+	}
+
+	return "", nil
+}
+
 // VerifyTag verifies the signature on the RSL entries for the specified tags.
 // In addition, each tag object's signature is also verified using the same set
 // of trusted keys. If the tag is not protected by policy, then all keys in the
-// applicable policy are used to verify the signatures.
+// applicable policy are used to verify the signatures. Keys restricted to
+// specific operations, such as keys that may only sign merge commits, are not
+// trusted to sign tags. The tag must be an
+// annotated tag for the same name, the tag reference must point to the tag
+// object recorded in the RSL, and the tag must resolve to a commit. If a rule
+// protecting the tag allows specific builders, the tag must also have SLSA
+// provenance from one of them.
 func VerifyTag(ctx context.Context, repo *git.Repository, ids []string) map[string]string {
 	status := make(map[string]string, len(ids))
 
@@ -499,16 +811,93 @@ func VerifyTag(ctx context.Context, repo *git.Repository, ids []string) map[stri
 			continue
 		}
 
-		if err := verifyTagEntry(ctx, repo, policy, entry); err == nil {
-			status[id] = goodTagSignatureMessage
-		} else {
+		if err := verifyTagEntry(ctx, repo, policy, entry); err != nil {
+			status[id] = err.Error()
+			continue
+		}
+
+		if err := verifyTagProvenance(ctx, repo, policy, entry); err != nil {
 			status[id] = err.Error()
+			continue
 		}
+
+		status[id] = goodTagSignatureMessage
 	}
 
 	return status
 }
 
+// VerifyTagRef verifies the RSL entry and the tag object for the specified tag
+// reference using the policy applicable at the time the tag was recorded. If a
+// rule protecting the tag allows specific builders, the tag must also have SLSA
+// provenance from one of them. If verification is successful, the tag's RSL
+// entry and the applicable policy are returned.
+func VerifyTagRef(ctx context.Context, repo *git.Repository, tagRef string) (*rsl.ReferenceEntry, *State, error) {
+	if !strings.HasPrefix(tagRef, gitinterface.TagRefPrefix) {
+		return nil, nil, ErrNotTagRef
+	}
+
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(repo, tagRef)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, tagRef, entry.GetID()); err == nil {
+		return nil, nil, ErrMultipleTagRSLEntries
+	}
+
+	policyEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, PolicyRef, entry.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf(unableToLoadPolicyMessageFmt, err.Error())
+	}
+
+	policy, err := LoadState(ctx, repo, policyEntry)
+	if err != nil {
+		return nil, nil, fmt.Errorf(unableToLoadPolicyMessageFmt, err.Error())
+	}
+
+	if err := verifyTagEntry(ctx, repo, policy, entry); err != nil {
+		return nil, nil, err
+	}
+
+	if err := verifyTagProvenance(ctx, repo, policy, entry); err != nil {
+		return nil, nil, err
+	}
+
+	return entry, policy, nil
+}
+
+// VerifyTagAttestation verifies the signatures on an attestation that makes
+// claims about the specified tag. The attestation must be signed by the keys
+// trusted for the tag's namespace. If no rule protects the tag, the attestation
+// may be signed by any key in the policy. VerifyTagAttestation does not inspect
+// the attestation's payload, the caller must ensure its validity.
+func (s *State) VerifyTagAttestation(ctx context.Context, tagRef string, env *sslibdsse.Envelope) error {
+	verifiers, err := s.FindVerifiersForPath(fmt.Sprintf("%s:%s", gitReferenceRuleScheme, tagRef))
+	if err != nil {
+		return err
+	}
+
+	if len(verifiers) == 0 {
+		verifier, err := s.getUnprotectedVerifier(tagRef)
+		if err != nil {
+			return err
+		}
+		verifiers = append(verifiers, verifier)
+	}
+
+	for _, verifier := range verifiers {
+		err := verifier.Verify(ctx, nil, env)
+		if err == nil {
+			return nil
+		} else if !errors.Is(err, ErrVerifierConditionsUnmet) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("verifying attestation for tag failed, %w", ErrUnauthorizedSignature)
+}
+
 // VerifyNewState ensures that when a new policy is encountered, its root role
 // is signed by keys trusted in the current policy.
 func (s *State) VerifyNewState(ctx context.Context, newPolicy *State) error {
@@ -531,10 +920,34 @@ func verifyEntry(ctx context.Context, repo *git.Repository, policy *State, attes
 		return nil
 	}
 
+	if entry.RefName == identities.Ref {
+		return verifyIdentitiesEntry(ctx, repo, policy, entry)
+	}
+
+	if err := verifyTickets(policy, entry); err != nil {
+		return err
+	}
+
 	if strings.HasPrefix(entry.RefName, gitinterface.TagRefPrefix) {
 		return verifyTagEntry(ctx, repo, policy, entry)
 	}
 
+	if err := verifyUpdateType(repo, policy, entry); err != nil {
+		return err
+	}
+
+	if entry.ChangedPaths != nil {
+		if err := verifyChangedPaths(repo, entry); err != nil {
+			return err
+		}
+	}
+
+	if entry.Submodules != nil {
+		if err := verifySubmodules(repo, entry); err != nil {
+			return err
+		}
+	}
+
 	var (
 		gitNamespaceVerified  = false
 		pathNamespaceVerified = true // Assume paths are verified until we find out otherwise
@@ -563,14 +976,59 @@ func verifyEntry(ctx context.Context, repo *git.Repository, policy *State, attes
 		if err != nil {
 			return err
 		}
+		if authorizationAttestation != nil {
+			report.RecordAttestation(entry.ID.String(), getPredicateType(authorizationAttestation))
+		}
+	}
+
+	// If the policy allows it, GPG keys that have since expired are trusted
+	// if they were valid when the entry was recorded. Shard entries are
+	// recorded in the RSL by their index entries.
+	recordedEntryID := getRecordedEntryID(entry)
+	recordedAt, err := policy.expiredKeysRecordedAt(ctx, repo, recordedEntryID)
+	if err != nil {
+		return err
+	}
+
+	// Keys rotated after the entry are trusted for it in place of their new
+	// keys, such as when the latest policy is used to verify an older entry
+	keyRotations, err := policy.getKeyRotationsAfterEntry(repo, entry)
+	if err != nil {
+		return err
+	}
+
+	// Approvals recorded for the change, such as in GitHub pull requests,
+	// count towards the verifiers' thresholds, and automation keys may sign
+	// the entry on behalf of the keys that delegated to them
+	var (
+		approverKeyIDs []string
+		automationKeys []*automationKey
+	)
+	if len(verifiers) != 0 {
+		approverKeyIDs, err = getApproverKeyIDs(ctx, repo, policy, attestationsState, entry)
+		if err != nil {
+			return err
+		}
+
+		automationKeys, err = getAutomationKeys(ctx, repo, policy, attestationsState, entry, recordedEntryID)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Use each verifier to verify signature
 	for _, verifier := range verifiers {
-		err := verifier.Verify(ctx, commitObj, authorizationAttestation)
+		verifier, err := policy.activeVerifierForEntry(ctx, repo, verifier.beforeKeyRotations(keyRotations), recordedEntryID)
+		if err != nil {
+			return err
+		}
+		verifier = verifier.withRecordedAt(recordedAt).withApprovers(approverKeyIDs).withAutomationKeys(automationKeys)
+
+		keyIDs, err := verifier.verify(ctx, commitObj, authorizationAttestation)
 		if err == nil {
 			// Signature verification succeeded
 			gitNamespaceVerified = true
+			report.RecordSignature(entry.ID.String(), commitObj.Hash.String(), verifier.Name(), keyIDs)
 			break
 		} else if !errors.Is(err, ErrVerifierConditionsUnmet) {
 			// Unexpected error
@@ -647,11 +1105,18 @@ func verifyEntry(ctx context.Context, repo *git.Repository, policy *State, attes
 			}
 
 			for _, verifier := range verifiers {
-				err := verifier.Verify(ctx, commit, authorizationAttestation)
+				verifier, err := policy.activeVerifierForEntry(ctx, repo, verifier.beforeKeyRotations(keyRotations), recordedEntryID)
+				if err != nil {
+					return err
+				}
+				verifier = verifier.withRecordedAt(recordedAt)
+
+				keyIDs, err := verifier.verify(ctx, commit, authorizationAttestation)
 				if err == nil {
 					// Signature verification succeeded
 					pathsVerified[j] = true
 					verifiedUsing = verifier.Name()
+					report.RecordSignature(entry.ID.String(), commit.Hash.String(), verifier.Name(), keyIDs)
 					break
 				} else if !errors.Is(err, ErrVerifierConditionsUnmet) {
 					// Unexpected error
@@ -686,44 +1151,129 @@ func verifyEntry(ctx context.Context, repo *git.Repository, policy *State, attes
 	return nil
 }
 
-func verifyTagEntry(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry) error {
-	// 1. Find authorized public keys for tag's RSL entry
-	trustedKeys, err := policy.FindPublicKeysForPath(ctx, fmt.Sprintf("git:%s", entry.RefName))
+// verifyIdentitiesEntry verifies the signature on the RSL entry for the
+// identities namespace. The entry must be signed as required by the rules
+// protecting the namespace, or by a key in the policy if no rule protects it.
+// The identities recorded in the entry are only trusted once signed by the
+// required number of policy administrators, which is checked by VerifyIdentity
+// when they're used. File rules don't apply to the namespace, as it only
+// contains identity envelopes.
+func verifyIdentitiesEntry(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry) error {
+	verifiers, err := policy.FindVerifiersForPath(fmt.Sprintf("%s:%s", gitReferenceRuleScheme, entry.RefName))
 	if err != nil {
 		return err
 	}
 
-	if len(trustedKeys) == 0 {
-		allKeys, err := policy.PublicKeys()
+	if len(verifiers) == 0 {
+		verifier, err := policy.getUnprotectedVerifier(entry.RefName)
 		if err != nil {
 			return err
 		}
-
-		// FIXME: decide if we want to pass around map or slice for these APIs
-		for _, key := range allKeys {
-			trustedKeys = append(trustedKeys, key)
-		}
+		verifiers = append(verifiers, verifier)
 	}
 
-	// 2. Find commit object for the RSL entry
 	commitObj, err := gitinterface.GetCommit(repo, entry.ID)
 	if err != nil {
 		return err
 	}
 
-	// 3. Use each trusted key to verify signature
-	rslEntryVerified := false
-	for _, key := range trustedKeys {
-		err := gitinterface.VerifyCommitSignature(ctx, commitObj, key)
-		if err == nil {
-			// Signature verification succeeded
-			rslEntryVerified = true
-			break
-		}
-		if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
-			// We encounter this for key types that can be used for gittuf
-			// policy metadata but not Git objects
-			continue
+	recordedEntryID := getRecordedEntryID(entry)
+	recordedAt, err := policy.expiredKeysRecordedAt(ctx, repo, recordedEntryID)
+	if err != nil {
+		return err
+	}
+
+	keyRotations, err := policy.getKeyRotationsAfterEntry(repo, entry)
+	if err != nil {
+		return err
+	}
+
+	for _, verifier := range verifiers {
+		verifier, err := policy.activeVerifierForEntry(ctx, repo, verifier.beforeKeyRotations(keyRotations), recordedEntryID)
+		if err != nil {
+			return err
+		}
+		verifier = verifier.withRecordedAt(recordedAt)
+
+		keyIDs, err := verifier.verify(ctx, commitObj, nil)
+		if err == nil {
+			report.RecordSignature(entry.ID.String(), commitObj.Hash.String(), verifier.Name(), keyIDs)
+			return nil
+		} else if !errors.Is(err, ErrVerifierConditionsUnmet) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("verifying identities entry failed, %w", ErrUnauthorizedSignature)
+}
+
+func verifyTagEntry(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry) error {
+	recordedEntryID := getRecordedEntryID(entry)
+
+	// 1. Find authorized keys for tag's RSL entry and tag object
+	verifiers, err := policy.FindVerifiersForPath(fmt.Sprintf("%s:%s", gitReferenceRuleScheme, entry.RefName))
+	if err != nil {
+		return err
+	}
+
+	if len(verifiers) == 0 {
+		verifier, err := policy.getUnprotectedVerifier(entry.RefName)
+		if err != nil {
+			return err
+		}
+		verifiers = append(verifiers, verifier)
+	}
+
+	keyRotations, err := policy.getKeyRotationsAfterEntry(repo, entry)
+	if err != nil {
+		return err
+	}
+
+	// The RSL entry is a commit, while keys restricted to specific operations
+	// may not sign tag objects
+	trustedKeys := []*tuf.Key{}
+	trustedTagKeys := []*tuf.Key{}
+	for _, verifier := range verifiers {
+		verifier, err := policy.activeVerifierForEntry(ctx, repo, verifier.beforeKeyRotations(keyRotations), recordedEntryID)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range verifier.Keys() {
+			if verifier.allowsKeyOperation(key.KeyID, tuf.KeyOperationCommit) {
+				trustedKeys = append(trustedKeys, key)
+			}
+			if !verifier.isRestrictedKey(key.KeyID) {
+				trustedTagKeys = append(trustedTagKeys, key)
+			}
+		}
+	}
+
+	// 2. Find commit object for the RSL entry
+	commitObj, err := gitinterface.GetCommit(repo, entry.ID)
+	if err != nil {
+		return err
+	}
+
+	recordedAt, err := policy.expiredKeysRecordedAt(ctx, repo, recordedEntryID)
+	if err != nil {
+		return err
+	}
+
+	// 3. Use each trusted key to verify signature
+	rslEntryVerified := false
+	for _, key := range trustedKeys {
+		err := gitinterface.VerifyCommitSignatureAt(ctx, commitObj, key, recordedAt)
+		if err == nil {
+			// Signature verification succeeded
+			rslEntryVerified = true
+			report.RecordSignature(entry.ID.String(), commitObj.Hash.String(), "", []string{key.KeyID})
+			break
+		}
+		if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+			// We encounter this for key types that can be used for gittuf
+			// policy metadata but not Git objects
+			continue
 		}
 		if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
 			// Unexpected error
@@ -740,29 +1290,45 @@ func verifyTagEntry(ctx context.Context, repo *git.Repository, policy *State, en
 	tagObjVerified := false
 	tagObj, err := gitinterface.GetTag(repo, entry.TargetID)
 	if err != nil {
-		// Likely indicates the ref is not pointing to a tag object
-		// What about lightweight tags?
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			if _, err := repo.Object(plumbing.AnyObject, entry.TargetID); err == nil {
+				// Lightweight tags point directly to the tagged object and
+				// cannot carry a signature
+				return ErrNotAnnotatedTag
+			}
+		}
 		return err
 	}
 
+	// The tag object must be for the tag recorded in the RSL, otherwise a
+	// signed tag for one release can be presented as another
+	if tagObj.Name != strings.TrimPrefix(entry.RefName, gitinterface.TagRefPrefix) {
+		return fmt.Errorf("%w: tag object is for '%s'", ErrTagNameMismatch, tagObj.Name)
+	}
+
 	entryTagRef, err := repo.Reference(plumbing.ReferenceName(entry.RefName), true)
 	if err != nil {
 		return err
 	}
 
-	if entry.TargetID != entryTagRef.Hash() && entry.TargetID != tagObj.Target {
-		return fmt.Errorf("verifying RSL entry failed, tag reference set to unexpected target")
+	if entry.TargetID != entryTagRef.Hash() {
+		return fmt.Errorf("verifying RSL entry failed, %w", ErrTagTargetMismatch)
+	}
+
+	if _, err := resolveTagTarget(repo, tagObj); err != nil {
+		return err
 	}
 
 	if len(tagObj.PGPSignature) == 0 {
 		return fmt.Errorf(noSignatureMessage)
 	}
 
-	for _, key := range trustedKeys {
-		err := gitinterface.VerifyTagSignature(ctx, tagObj, key)
+	for _, key := range trustedTagKeys {
+		err := gitinterface.VerifyTagSignatureAt(ctx, tagObj, key, recordedAt)
 		if err == nil {
 			// Signature verification succeeded
 			tagObjVerified = true
+			report.RecordSignature(entry.ID.String(), tagObj.Hash.String(), "", []string{key.KeyID})
 			break
 		}
 		if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
@@ -784,7 +1350,41 @@ func verifyTagEntry(ctx context.Context, repo *git.Repository, policy *State, en
 	return nil
 }
 
+// getRecordedEntryID returns the ID of the RSL entry that records the entry.
+// Shard entries are recorded in the RSL by their index entries.
+func getRecordedEntryID(entry *rsl.ReferenceEntry) plumbing.Hash {
+	if !entry.IndexEntryID.IsZero() {
+		return entry.IndexEntryID
+	}
+
+	return entry.ID
+}
+
+// resolveTagTarget peels the annotated tag, following any annotated tags it
+// points to, and returns the ID of the commit it tags.
+func resolveTagTarget(repo *git.Repository, tagObj *object.Tag) (plumbing.Hash, error) {
+	for {
+		switch tagObj.TargetType {
+		case plumbing.CommitObject:
+			if _, err := gitinterface.GetCommit(repo, tagObj.Target); err != nil {
+				return plumbing.ZeroHash, err
+			}
+			return tagObj.Target, nil
+		case plumbing.TagObject:
+			nextTagObj, err := gitinterface.GetTag(repo, tagObj.Target)
+			if err != nil {
+				return plumbing.ZeroHash, err
+			}
+			tagObj = nextTagObj
+		default:
+			return plumbing.ZeroHash, fmt.Errorf("%w: tag points to %s", ErrTagTargetNotCommit, tagObj.TargetType)
+		}
+	}
+}
+
 func getAuthorizationAttestation(repo *git.Repository, attestationsState *attestations.Attestations, entry *rsl.ReferenceEntry) (*sslibdsse.Envelope, error) {
+	defer perf.Track(perf.AttestationLookups)()
+
 	firstEntry := false
 
 	priorRefEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, entry.RefName, entry.ID)
@@ -807,17 +1407,92 @@ func getAuthorizationAttestation(repo *git.Repository, attestationsState *attest
 	}
 
 	attestation, err := attestationsState.GetReferenceAuthorizationFor(repo, entry.RefName, fromID.String(), currentCommit.TreeHash.String())
+	if err == nil {
+		return attestation, nil
+	}
+	if !errors.Is(err, attestations.ErrAuthorizationNotFound) {
+		return nil, err
+	}
+
+	// The change may instead be authorized as part of a change set spanning
+	// multiple repositories
+	attestation, err = attestationsState.GetChangeSetAttestationFor(repo, entry.RefName, fromID.String(), currentCommit.TreeHash.String())
 	if err != nil {
-		if errors.Is(err, attestations.ErrAuthorizationNotFound) {
+		if errors.Is(err, attestations.ErrChangeSetNotFound) {
 			return nil, nil
 		}
 
 		return nil, err
 	}
 
+	changeSet, err := attestations.GetChangeSet(attestation)
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug(fmt.Sprintf("Change to '%s' in entry '%s' is authorized as part of change set '%s' spanning %d repositories", entry.RefName, entry.ID.String(), changeSet.ID, len(changeSet.Repositories())))
+
 	return attestation, nil
 }
 
+// getPredicateType returns the predicate type of the in-toto statement in the
+// attestation, for reporting the evidence used during verification. An empty
+// string is returned if the statement can't be decoded.
+func getPredicateType(env *sslibdsse.Envelope) string {
+	payloadBytes, err := env.DecodeB64Payload()
+	if err != nil {
+		return ""
+	}
+
+	statement := struct {
+		PredicateType string `json:"predicateType"`
+	}{}
+	if err := json.Unmarshal(payloadBytes, &statement); err != nil {
+		return ""
+	}
+
+	return statement.PredicateType
+}
+
+// verifyChangedPaths checks that the top-level paths recorded in the entry
+// match the changes made to the ref since its previous entry, so that the
+// recorded paths can be relied on without recomputing them.
+func verifyChangedPaths(repo *git.Repository, entry *rsl.ReferenceEntry) error {
+	priorEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, entry.RefName, entry.ID)
+	if err != nil {
+		if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return err
+		}
+		priorEntry = nil
+	}
+
+	changedPaths, err := rsl.GetChangedPaths(repo, priorEntry, entry.TargetID)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Equal(changedPaths, entry.ChangedPaths) {
+		return fmt.Errorf("%w: entry '%s' records '%s', expected '%s'", ErrChangedPathsMismatch, entry.ID.String(), strings.Join(entry.ChangedPaths, ", "), strings.Join(changedPaths, ", "))
+	}
+
+	return nil
+}
+
+// verifySubmodules checks that the submodule commits recorded in the entry
+// match the gitlinks in the entry's target. Whether the submodule commits are
+// themselves verified is checked using the submodules' own repositories.
+func verifySubmodules(repo *git.Repository, entry *rsl.ReferenceEntry) error {
+	submodules, err := rsl.GetSubmoduleCommits(repo, entry.TargetID)
+	if err != nil {
+		return err
+	}
+
+	if !maps.Equal(submodules, entry.Submodules) {
+		return fmt.Errorf("%w: entry '%s'", ErrSubmodulesMismatch, entry.ID.String())
+	}
+
+	return nil
+}
+
 // getCommits identifies the commits introduced to the entry's ref since the
 // last RSL entry for the same ref. These commits are then verified for file
 // policies.
@@ -840,6 +1515,97 @@ func getCommits(repo *git.Repository, entry *rsl.ReferenceEntry) ([]*object.Comm
 	return gitinterface.GetCommitsBetweenRange(repo, entry.TargetID, priorRefEntry.TargetID)
 }
 
+// verifyTickets checks that the entry records a ticket if any of the rules
+// protecting its ref require one. The ticket must be recorded in the entry
+// itself so that it is covered by the signature verified against the rules.
+func verifyTickets(policy *State, entry *rsl.ReferenceEntry) error {
+	verifiers, err := policy.FindVerifiersForPath(fmt.Sprintf("%s:%s", gitReferenceRuleScheme, entry.RefName))
+	if err != nil {
+		return err
+	}
+
+	for _, verifier := range verifiers {
+		if verifier.requireTicket && len(entry.Tickets) == 0 {
+			return fmt.Errorf("%w: rule '%s' requires a ticket for '%s' in entry '%s'", ErrTicketRequired, verifier.name, entry.RefName, entry.ID.String())
+		}
+	}
+
+	return nil
+}
+
+// verifyUpdateType checks that the entry updates its ref in a way allowed by
+// the rules protecting the ref, compared with the previous unskipped entry for
+// the ref. Creating and deleting the ref are not restricted.
+func verifyUpdateType(repo *git.Repository, policy *State, entry *rsl.ReferenceEntry) error {
+	verifiers, err := policy.FindVerifiersForPath(fmt.Sprintf("%s:%s", gitReferenceRuleScheme, entry.RefName))
+	if err != nil {
+		return err
+	}
+
+	if !slices.ContainsFunc(verifiers, func(verifier *Verifier) bool { return len(verifier.allowedUpdateTypes) != 0 }) {
+		return nil
+	}
+
+	priorEntry, _, err := rsl.GetLatestUnskippedReferenceEntryForRefBefore(repo, entry.RefName, entry.ID)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return nil
+		}
+		return err
+	}
+	if priorEntry.TargetID.IsZero() || entry.TargetID.IsZero() || priorEntry.TargetID == entry.TargetID {
+		return nil
+	}
+
+	updateType, err := GetUpdateType(repo, priorEntry.TargetID, entry.TargetID)
+	if err != nil {
+		return err
+	}
+
+	for _, verifier := range verifiers {
+		if len(verifier.allowedUpdateTypes) != 0 && !slices.Contains(verifier.allowedUpdateTypes, updateType) {
+			return fmt.Errorf("%w: rule '%s' does not allow %s updates to '%s' in entry '%s'", ErrUpdateTypeNotAllowed, verifier.name, updateType, entry.RefName, entry.ID.String())
+		}
+	}
+
+	return nil
+}
+
+// GetUpdateType identifies how a ref was updated from the old commit to the new
+// commit, returning one of tuf.UpdateTypeFastForward, tuf.UpdateTypeSquash,
+// tuf.UpdateTypeMerge, and tuf.UpdateTypeNonFastForward.
+func GetUpdateType(repo *git.Repository, oldID, newID plumbing.Hash) (string, error) {
+	oldCommit, err := gitinterface.GetCommit(repo, oldID)
+	if err != nil {
+		return "", err
+	}
+
+	knows, err := gitinterface.KnowsCommit(repo, newID, oldCommit)
+	if err != nil {
+		return "", err
+	}
+	if !knows {
+		return tuf.UpdateTypeNonFastForward, nil
+	}
+
+	commits, err := gitinterface.GetCommitsBetweenRange(repo, newID, oldID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, commit := range commits {
+		if len(commit.ParentHashes) > 1 {
+			return tuf.UpdateTypeMerge, nil
+		}
+	}
+
+	if len(commits) == 1 {
+		return tuf.UpdateTypeSquash, nil
+	}
+
+	return tuf.UpdateTypeFastForward, nil
+}
+
 // getChangedPaths identifies the paths of all the files changed using the
 // specified RSL entry. The entry's commit ID is compared with the commit ID
 // from the previous RSL entry for the same namespace.
@@ -877,9 +1643,33 @@ func getChangedPaths(repo *git.Repository, entry *rsl.ReferenceEntry) ([]string,
 }
 
 type Verifier struct {
-	name      string
-	keys      []*tuf.Key
-	threshold int
+	name            string
+	keys            []*tuf.Key
+	threshold       int
+	keyOperations   map[string][]string
+	rotation        *tuf.RotationSchedule
+	requireTicket   bool
+	allowedBuilders []string
+
+	// allowedUpdateTypes restricts how the branches verified using the
+	// verifier may be updated. All update types are allowed if it's empty.
+	allowedUpdateTypes []string
+
+	// recordedAt is the trusted time the verified Git objects were recorded
+	// in the RSL before. If set, GPG signatures made using keys that have
+	// since expired are accepted if the keys were valid at that time.
+	recordedAt time.Time
+
+	// approverKeyIDs are the IDs of the keys whose holders approved the
+	// change being verified, such as in a GitHub pull request. Approvals by
+	// the verifier's keys count towards its threshold, but at least one
+	// signature is still required.
+	approverKeyIDs []string
+
+	// automationKeys are keys delegated permission to sign commits, i.e.,
+	// RSL entries, on behalf of the verifier's keys. A signature by an
+	// automation key counts as a signature by the key that delegated to it.
+	automationKeys []*automationKey
 }
 
 func (v *Verifier) Name() string {
@@ -894,85 +1684,137 @@ func (v *Verifier) Threshold() int {
 	return v.threshold
 }
 
+// ActiveAt returns a verifier with only the keys authorized at the specified
+// time by the verifier's rotation schedule. If the verifier doesn't have a
+// rotation schedule, it is returned as is.
+func (v *Verifier) ActiveAt(at time.Time) (*Verifier, error) {
+	if v.rotation == nil {
+		return v, nil
+	}
+
+	activeKeyIDs, err := v.rotation.ActiveKeyIDs(at)
+	if err != nil {
+		return nil, err
+	}
+
+	activeVerifier := &Verifier{
+		name:               v.name,
+		keys:               []*tuf.Key{},
+		threshold:          v.threshold,
+		keyOperations:      v.keyOperations,
+		requireTicket:      v.requireTicket,
+		allowedBuilders:    v.allowedBuilders,
+		allowedUpdateTypes: v.allowedUpdateTypes,
+		recordedAt:         v.recordedAt,
+		approverKeyIDs:     v.approverKeyIDs,
+		automationKeys:     v.automationKeys,
+	}
+	for _, key := range v.keys {
+		if slices.Contains(activeKeyIDs, key.KeyID) {
+			activeVerifier.keys = append(activeVerifier.keys, key)
+		}
+	}
+
+	return activeVerifier, nil
+}
+
+// withRecordedAt returns a copy of the verifier that accepts GPG signatures
+// made using keys that have since expired if the keys were valid at the
+// specified time. If the time is zero, the verifier is returned as is.
+func (v *Verifier) withRecordedAt(recordedAt time.Time) *Verifier {
+	if recordedAt.IsZero() {
+		return v
+	}
+
+	verifier := *v
+	verifier.recordedAt = recordedAt
+	return &verifier
+}
+
+// withApprovers returns a copy of the verifier that counts approvals by the
+// specified keys towards its threshold. If no keys are specified, the verifier
+// is returned as is.
+func (v *Verifier) withApprovers(approverKeyIDs []string) *Verifier {
+	if len(approverKeyIDs) == 0 {
+		return v
+	}
+
+	verifier := *v
+	verifier.approverKeyIDs = approverKeyIDs
+	return &verifier
+}
+
 // Verify is used to check for a threshold of signatures using the verifier. The
 // threshold of signatures may be met using a combination of at most one Git
-// signature and signatures embedded in a DSSE envelope. Verify does not inspect
+// signature and signatures embedded in a DSSE envelope. Each of the verifier's
+// keys counts at most once towards the threshold. Verify does not inspect
 // the envelope's payload, but instead only verifies the signatures. The caller
-// must ensure the validity of the envelope's contents.
+// must ensure the validity of the envelope's contents. If the verifier has a
+// rotation schedule, only the keys authorized at the current time are used;
+// ActiveAt must be used to verify signatures made at another time.
 func (v *Verifier) Verify(ctx context.Context, gitObject object.Object, env *sslibdsse.Envelope) error {
-	if v.threshold < 1 || len(v.keys) < 1 {
-		return ErrInvalidVerifier
+	_, err := v.verify(ctx, gitObject, env)
+	return err
+}
+
+// verify checks for a threshold of signatures like Verify, and returns the IDs
+// of the keys whose signatures met the threshold.
+func (v *Verifier) verify(ctx context.Context, gitObject object.Object, env *sslibdsse.Envelope) ([]string, error) {
+	defer perf.Track(perf.SignatureChecks)()
+
+	if v.threshold < 1 {
+		return nil, ErrInvalidVerifier
+	}
+
+	if v.rotation != nil {
+		activeVerifier, err := v.ActiveAt(time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return activeVerifier.verify(ctx, gitObject, env)
+	}
+
+	if len(v.keys) < 1 {
+		// All of the verifier's keys may have been excluded by the key policy
+		return nil, ErrVerifierConditionsUnmet
+	}
+
+	if approvals := v.getApprovals(); len(approvals) != 0 {
+		return v.verifyWithApprovals(ctx, gitObject, env, approvals)
 	}
 
 	if gitObject == nil {
 		if env == nil {
 			// Nothing to verify, but fail closed
-			return ErrVerifierConditionsUnmet
+			return nil, ErrVerifierConditionsUnmet
 		} else if len(env.Signatures) < v.threshold {
 			// Envelope doesn't have enough signatures to meet threshold
-			return ErrVerifierConditionsUnmet
+			return nil, ErrVerifierConditionsUnmet
 		}
 	} else {
 		if env == nil {
 			if v.threshold > 1 {
 				// Single valid signature at most, so cannot meet threshold
-				return ErrVerifierConditionsUnmet
+				return nil, ErrVerifierConditionsUnmet
 			}
 		} else {
 			if (1 + len(env.Signatures)) < v.threshold {
 				// Combining the attestation and the git object we still do not
 				// have sufficient signatures
-				return ErrVerifierConditionsUnmet
+				return nil, ErrVerifierConditionsUnmet
 			}
 		}
 	}
 
-	var keyIDUsed string
-	gitObjectVerified := false
-
 	// First, verify the gitObject's signature if one is presented
-	if gitObject != nil {
-		switch o := gitObject.(type) {
-		case *object.Commit:
-			for _, key := range v.keys {
-				err := gitinterface.VerifyCommitSignature(ctx, o, key)
-				if err == nil {
-					// Signature verification succeeded
-					keyIDUsed = key.KeyID
-					gitObjectVerified = true
-					break
-				}
-				if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
-					continue
-				}
-				if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
-					return err
-				}
-			}
-		case *object.Tag:
-			for _, key := range v.keys {
-				err := gitinterface.VerifyTagSignature(ctx, o, key)
-				if err == nil {
-					// Signature verification succeeded
-					keyIDUsed = key.KeyID
-					gitObjectVerified = true
-					break
-				}
-				if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
-					continue
-				}
-				if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
-					return err
-				}
-			}
-		default:
-			return ErrUnknownObjectType
-		}
+	keyIDUsed, gitObjectVerified, err := v.verifyGitObject(ctx, gitObject)
+	if err != nil {
+		return nil, err
 	}
 
 	// If threshold is 1 and the Git signature is verified, we can return
 	if v.threshold == 1 && gitObjectVerified {
-		return nil
+		return []string{keyIDUsed}, nil
 	}
 
 	// Second, verify signatures on the attestation, subtracting the threshold
@@ -990,16 +1832,211 @@ func (v *Verifier) Verify(ctx context.Context, gitObject object.Object, env *ssl
 			continue
 		}
 
-		verifier, err := signerverifier.NewSignerVerifierFromTUFKey(key) //nolint:staticcheck
-		if err != nil && !errors.Is(err, common.ErrUnknownKeyType) {
-			return err
+		if v.isRestrictedKey(key.KeyID) {
+			// Restricted keys may only sign Git objects
+			continue
+		}
+
+		verifier, err := newDSSEVerifier(key)
+		if err != nil {
+			if errors.Is(err, common.ErrUnknownKeyType) {
+				// Keys such as GPG keys cannot be used to verify DSSE
+				// envelopes
+				continue
+			}
+			return nil, err
 		}
 		verifiers = append(verifiers, verifier)
 	}
 
-	if err := dsse.VerifyEnvelope(ctx, env, verifiers, envelopeThreshold); err != nil {
-		return ErrVerifierConditionsUnmet
+	keyIDs, err := dsse.VerifyEnvelopeAndGetKeyIDs(ctx, env, verifiers, envelopeThreshold)
+	if err != nil {
+		return nil, ErrVerifierConditionsUnmet
 	}
 
-	return nil
+	if gitObjectVerified {
+		keyIDs = append([]string{keyIDUsed}, keyIDs...)
+	}
+
+	return keyIDs, nil
+}
+
+// getApprovals returns the IDs of the verifier's keys that approved the change.
+// Restricted keys may only sign Git objects, so their approvals are not
+// counted.
+func (v *Verifier) getApprovals() []string {
+	approvals := []string{}
+	for _, key := range v.keys {
+		if v.isRestrictedKey(key.KeyID) {
+			continue
+		}
+		if slices.Contains(v.approverKeyIDs, key.KeyID) && !slices.Contains(approvals, key.KeyID) {
+			approvals = append(approvals, key.KeyID)
+		}
+	}
+
+	return approvals
+}
+
+// verifyWithApprovals checks for a threshold of signatures and approvals using
+// the verifier. The Git object or the envelope must be signed by at least one
+// of the verifier's keys, and each key counts at most once towards the
+// threshold, whether it signed or approved the change.
+func (v *Verifier) verifyWithApprovals(ctx context.Context, gitObject object.Object, env *sslibdsse.Envelope, approvals []string) ([]string, error) {
+	keyIDs := []string{}
+
+	keyIDUsed, gitObjectVerified, err := v.verifyGitObject(ctx, gitObject)
+	if err != nil {
+		return nil, err
+	}
+	if gitObjectVerified {
+		keyIDs = append(keyIDs, keyIDUsed)
+	}
+
+	if env != nil {
+		verifiers := make([]sslibdsse.Verifier, 0, len(v.keys))
+		for _, key := range v.keys {
+			if key.KeyID == keyIDUsed || v.isRestrictedKey(key.KeyID) {
+				continue
+			}
+
+			verifier, err := newDSSEVerifier(key)
+			if err != nil {
+				if errors.Is(err, common.ErrUnknownKeyType) {
+					continue
+				}
+				return nil, err
+			}
+			verifiers = append(verifiers, verifier)
+		}
+
+		// Each key that signed the envelope is identified, so that keys that
+		// also approved the change are counted once
+		for _, verifier := range verifiers {
+			envelopeKeyIDs, err := dsse.VerifyEnvelopeAndGetKeyIDs(ctx, env, []sslibdsse.Verifier{verifier}, 1)
+			if err == nil {
+				keyIDs = append(keyIDs, envelopeKeyIDs...)
+			}
+		}
+	}
+
+	if len(keyIDs) == 0 {
+		// Approvals alone are not sufficient
+		return nil, ErrVerifierConditionsUnmet
+	}
+
+	for _, keyID := range approvals {
+		if !slices.Contains(keyIDs, keyID) {
+			keyIDs = append(keyIDs, keyID)
+		}
+	}
+
+	if len(keyIDs) < v.threshold {
+		return nil, ErrVerifierConditionsUnmet
+	}
+
+	return keyIDs, nil
+}
+
+// verifyGitObject verifies the Git object's signature using the verifier's
+// keys, returning the ID of the key that verified it. If the object is nil or
+// isn't signed by one of the keys, it returns false.
+func (v *Verifier) verifyGitObject(ctx context.Context, gitObject object.Object) (string, bool, error) {
+	if gitObject == nil {
+		return "", false, nil
+	}
+
+	switch o := gitObject.(type) {
+	case *object.Commit:
+		operation := tuf.KeyOperationCommit
+		if len(o.ParentHashes) > 1 {
+			operation = tuf.KeyOperationMergeCommit
+		}
+
+		for _, key := range v.keys {
+			if !v.allowsKeyOperation(key.KeyID, operation) {
+				continue
+			}
+
+			err := gitinterface.VerifyCommitSignatureAt(ctx, o, key, v.recordedAt)
+			if err == nil {
+				// Signature verification succeeded
+				return key.KeyID, true, nil
+			}
+			if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+				continue
+			}
+			if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
+				return "", false, err
+			}
+		}
+
+		for _, automationKey := range v.automationKeys {
+			if !v.allowsKeyOperation(automationKey.delegatorKeyID, operation) {
+				continue
+			}
+
+			err := gitinterface.VerifyCommitSignatureAt(ctx, o, automationKey.key, v.recordedAt)
+			if err == nil {
+				// The signature counts as the delegator's
+				return automationKey.delegatorKeyID, true, nil
+			}
+			if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+				continue
+			}
+			if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
+				return "", false, err
+			}
+		}
+	case *object.Tag:
+		for _, key := range v.keys {
+			if v.isRestrictedKey(key.KeyID) {
+				// Restricted keys may only sign commits
+				continue
+			}
+
+			err := gitinterface.VerifyTagSignatureAt(ctx, o, key, v.recordedAt)
+			if err == nil {
+				// Signature verification succeeded
+				return key.KeyID, true, nil
+			}
+			if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+				continue
+			}
+			if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
+				return "", false, err
+			}
+		}
+	default:
+		return "", false, ErrUnknownObjectType
+	}
+
+	return "", false, nil
+}
+
+// newDSSEVerifier returns a verifier for DSSE signatures made using the key.
+// It returns common.ErrUnknownKeyType for keys that cannot sign DSSE envelopes.
+func newDSSEVerifier(key *tuf.Key) (sslibdsse.Verifier, error) {
+	if key.KeyType == x509.X509KeyType {
+		return x509.NewVerifierFromKey(key)
+	}
+
+	return signerverifier.NewSignerVerifierFromTUFKey(key) //nolint:staticcheck
+}
+
+// isRestrictedKey checks if the key may only be used for specific operations.
+func (v *Verifier) isRestrictedKey(keyID string) bool {
+	_, restricted := v.keyOperations[keyID]
+	return restricted
+}
+
+// allowsKeyOperation checks if the key may be used for the operation. Keys
+// that are not restricted may be used for all operations.
+func (v *Verifier) allowsKeyOperation(keyID, operation string) bool {
+	operations, restricted := v.keyOperations[keyID]
+	if !restricted {
+		return true
+	}
+
+	return slices.Contains(operations, operation)
 }
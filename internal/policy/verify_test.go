@@ -4,6 +4,7 @@ package policy
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"testing"
@@ -88,6 +89,64 @@ func TestVerifyRefFull(t *testing.T) {
 	assert.Equal(t, commitIDs[0], currentTip)
 }
 
+func TestVerifyRefFullKeepGoing(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Policy violation
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgUnauthorizedKeyBytes)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	firstViolationID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgUnauthorizedKeyBytes)
+
+	// Not policy violation by itself
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+	entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+	oldID := commitIDs[0]
+
+	// Policy violation
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgUnauthorizedKeyBytes)
+	entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+	secondViolationID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgUnauthorizedKeyBytes)
+
+	// Not policy violation by itself
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+	entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+	newID := commitIDs[0]
+
+	t.Run("full", func(t *testing.T) {
+		currentTip, err := VerifyRefFullKeepGoing(testCtx, repo, refName)
+		assert.Equal(t, newID, currentTip)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		violations := &ErrPolicyViolations{}
+		if assert.ErrorAs(t, err, &violations) {
+			assert.Len(t, violations.Violations, 2)
+			assert.Equal(t, firstViolationID, violations.Violations[0].EntryID)
+			assert.Equal(t, secondViolationID, violations.Violations[1].EntryID)
+		}
+
+		// Without keep going, verification stops at the first violation
+		_, err = VerifyRefFull(testCtx, repo, refName)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+		assert.False(t, errors.As(err, &violations))
+	})
+
+	t.Run("range", func(t *testing.T) {
+		err := VerifyRefRangeKeepGoing(testCtx, repo, refName, oldID, newID)
+		violations := &ErrPolicyViolations{}
+		if assert.ErrorAs(t, err, &violations) {
+			assert.Len(t, violations.Violations, 1)
+			assert.Equal(t, secondViolationID, violations.Violations[0].EntryID)
+		}
+	})
+}
+
 func TestVerifyRefFromEntry(t *testing.T) {
 	repo, _ := createTestRepository(t, createTestStateWithPolicy)
 	refName := "refs/heads/main"
@@ -117,6 +176,75 @@ func TestVerifyRefFromEntry(t *testing.T) {
 	assert.Equal(t, commitIDs[1], currentTip)
 }
 
+func TestVerifyRefRange(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Policy violation
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 3, gpgUnauthorizedKeyBytes)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[2])
+	common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgUnauthorizedKeyBytes)
+	violatingID := commitIDs[2]
+
+	// Not policy violation by itself
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 3, gpgKeyBytes)
+	entry = rsl.NewReferenceEntry(refName, commitIDs[2])
+	common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+	oldID := commitIDs[2]
+
+	// Not policy violation by itself
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyBytes)
+	entry = rsl.NewReferenceEntry(refName, commitIDs[1])
+	common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+	newID := commitIDs[1]
+
+	tests := map[string]struct {
+		oldID       plumbing.Hash
+		newID       plumbing.Hash
+		expectedErr error
+	}{
+		"range from non-violating state": {
+			oldID: oldID,
+			newID: newID,
+		},
+		"range from violating state": {
+			oldID:       violatingID,
+			newID:       newID,
+			expectedErr: ErrUnauthorizedSignature,
+		},
+		"ref creation": {
+			oldID:       plumbing.ZeroHash,
+			newID:       newID,
+			expectedErr: ErrUnauthorizedSignature,
+		},
+		"new ID not recorded in latest entry": {
+			oldID:       violatingID,
+			newID:       oldID,
+			expectedErr: ErrRangeNotInRSL,
+		},
+		"old ID not recorded in RSL": {
+			oldID:       commitIDs[0],
+			newID:       newID,
+			expectedErr: ErrRangeNotInRSL,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := VerifyRefRange(testCtx, repo, refName, test.oldID, test.newID)
+			if test.expectedErr == nil {
+				assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+			} else {
+				assert.ErrorIs(t, err, test.expectedErr, fmt.Sprintf("unexpected error in test '%s'", name))
+			}
+		})
+	}
+}
+
 func TestVerifyRelativeForRef(t *testing.T) {
 	t.Run("no recovery", func(t *testing.T) {
 		repo, _ := createTestRepository(t, createTestStateWithPolicy)
@@ -728,6 +856,98 @@ func TestVerifyRelativeForRef(t *testing.T) {
 		err = VerifyRelativeForRef(context.Background(), repo, policyEntry, nil, policyEntry, entry, refName)
 		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
 	})
+
+	t.Run("with recovery, commit-same, invalid entries skipped by range annotation, recovered by authorized user", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+		refName := "refs/heads/main"
+
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+
+		policyEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		validCommitID := commitIDs[0] // track this for later
+
+		// Simulate a bot pushing many unauthorized changes
+		invalidEntryIDs := []plumbing.Hash{}
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 5, gpgUnauthorizedKeyBytes)
+		for _, commitID := range commitIDs {
+			entry = rsl.NewReferenceEntry(refName, commitID)
+			entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgUnauthorizedKeyBytes)
+			entry.ID = entryID
+			invalidEntryIDs = append(invalidEntryIDs, entryID)
+		}
+
+		// It's in an invalid state right now, error out
+		err = VerifyRelativeForRef(context.Background(), repo, policyEntry, nil, policyEntry, entry, refName)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		// Fix using the known-good commit
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), validCommitID)); err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a skip annotation that leaves out the last invalid entry
+		annotation := rsl.NewAnnotationEntryForRange(refName, invalidEntryIDs[0], invalidEntryIDs[3], true, "invalid entries")
+		common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, gpgKeyBytes)
+		// Create a new entry moving branch back to valid commit
+		entry = rsl.NewReferenceEntry(refName, validCommitID)
+		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		// An invalid entry is not marked as skipped
+		err = VerifyRelativeForRef(context.Background(), repo, policyEntry, nil, policyEntry, entry, refName)
+		assert.ErrorIs(t, err, ErrInvalidEntryNotSkipped)
+
+		// Create a skip annotation for the full range of invalid entries
+		annotation = rsl.NewAnnotationEntryForRange(refName, invalidEntryIDs[0], invalidEntryIDs[len(invalidEntryIDs)-1], true, "invalid entries")
+		common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, gpgKeyBytes)
+
+		// No error anymore
+		err = VerifyRelativeForRef(context.Background(), repo, policyEntry, nil, policyEntry, entry, refName)
+		assert.Nil(t, err)
+	})
+
+	t.Run("with invalid range annotation", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+		refName := "refs/heads/main"
+
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+
+		policyEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entryIDs := []plumbing.Hash{}
+		for i := 0; i < 2; i++ {
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+			entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+			entryIDs = append(entryIDs, common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes))
+		}
+
+		// Record an annotation whose range is reversed, bypassing the checks
+		// when committing an annotation
+		annotation := rsl.NewAnnotationEntryForRange(refName, entryIDs[1], entryIDs[0], true, "invalid range")
+		common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, gpgKeyBytes)
+
+		entry := rsl.NewReferenceEntry(refName, entryIDs[1])
+		entry.ID = entryIDs[1]
+
+		err = VerifyRelativeForRef(context.Background(), repo, policyEntry, nil, policyEntry, entry, refName)
+		assert.ErrorIs(t, err, rsl.ErrInvalidAnnotationRange)
+	})
 }
 
 func TestVerifyCommit(t *testing.T) {
@@ -776,12 +996,39 @@ func TestVerifyCommit(t *testing.T) {
 	status = VerifyCommit(testCtx, repo, tagHash.String())
 	assert.Equal(t, expectedStatus, status)
 
-	// Add a commit but don't record it in the RSL
+	// Add a commit but don't record it in the RSL, the latest policy is used
 	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
 
-	expectedStatus = map[string]string{commitIDs[0].String(): unableToFindPolicyMessage}
+	expectedStatus = map[string]string{commitIDs[0].String(): fmt.Sprintf(goodSignatureMessageFmt, gpgKey.KeyType, gpgKey.KeyID)}
 	status = VerifyCommit(testCtx, repo, commitIDs[0].String())
 	assert.Equal(t, expectedStatus, status)
+
+	t.Run("key not authorized for modified path", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithFilePolicyForUnauthorizedTest)
+
+		// The commit adds file 1, which only the root key may modify
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+
+		expectedStatus := map[string]string{commitIDs[0].String(): fmt.Sprintf(unauthorizedForPathMessageFmt, gpgKey.KeyType, gpgKey.KeyID, "1")}
+		status := VerifyCommit(testCtx, repo, commitIDs[0].String())
+		assert.Equal(t, expectedStatus, status)
+	})
+
+	t.Run("no policy", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rsl.InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+
+		expectedStatus := map[string]string{commitIDs[0].String(): unableToFindPolicyMessage}
+		status := VerifyCommit(testCtx, repo, commitIDs[0].String())
+		assert.Equal(t, expectedStatus, status)
+	})
 }
 
 func TestVerifyTag(t *testing.T) {
@@ -871,6 +1118,94 @@ func TestVerifyTag(t *testing.T) {
 	})
 }
 
+func TestVerifyTagRef(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+	tagName := "v1"
+	tagRef := string(plumbing.NewTagReferenceName(tagName))
+	tagID := common.CreateTestSignedTag(t, repo, tagName, commitIDs[0], gpgKeyBytes)
+
+	_, _, err := VerifyTagRef(testCtx, repo, refName)
+	assert.ErrorIs(t, err, ErrNotTagRef)
+
+	_, _, err = VerifyTagRef(testCtx, repo, tagRef)
+	assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
+
+	entry = rsl.NewReferenceEntry(tagRef, tagID)
+	entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+	tagEntry, policy, err := VerifyTagRef(testCtx, repo, tagRef)
+	assert.Nil(t, err)
+	assert.Equal(t, entryID, tagEntry.ID)
+	assert.NotNil(t, policy)
+
+	common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+	_, _, err = VerifyTagRef(testCtx, repo, tagRef)
+	assert.ErrorIs(t, err, ErrMultipleTagRSLEntries)
+}
+
+func TestStateVerifyTagAttestation(t *testing.T) {
+	state := createTestStateWithPolicy(t)
+	tagRef := "refs/tags/v1"
+
+	release, err := attestations.NewGitHubReleaseAttestation("gittuf", "gittuf", 1, tagRef, plumbing.ZeroHash.String(), plumbing.ZeroHash.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("unsigned attestation", func(t *testing.T) {
+		env, err := dsse.CreateEnvelope(release)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = state.VerifyTagAttestation(testCtx, tagRef, env)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
+
+	t.Run("attestation signed by policy key", func(t *testing.T) {
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := dsse.CreateEnvelope(release)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signedEnv, err := dsse.SignEnvelope(testCtx, env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = state.VerifyTagAttestation(testCtx, tagRef, signedEnv)
+		assert.Nil(t, err)
+	})
+
+	t.Run("attestation signed by untrusted key", func(t *testing.T) {
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets1KeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := dsse.CreateEnvelope(release)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signedEnv, err := dsse.SignEnvelope(testCtx, env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = state.VerifyTagAttestation(testCtx, tagRef, signedEnv)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
+}
+
 func TestVerifyEntry(t *testing.T) {
 	refName := "refs/heads/main"
 
@@ -886,6 +1221,52 @@ func TestVerifyEntry(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("successful verification with changed paths", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyBytes)
+		entry := rsl.NewReferenceEntryWithChangedPaths(refName, commitIDs[1], []string{"1", "2"})
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		err := verifyEntry(context.Background(), repo, state, nil, entry)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unsuccessful verification with incorrect changed paths", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyBytes)
+		entry := rsl.NewReferenceEntryWithChangedPaths(refName, commitIDs[1], []string{"1"})
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		err := verifyEntry(context.Background(), repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrChangedPathsMismatch)
+	})
+
+	t.Run("successful verification with submodules", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.Submodules = map[string]plumbing.Hash{}
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		err := verifyEntry(context.Background(), repo, state, nil, entry)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unsuccessful verification with incorrect submodules", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.Submodules = map[string]plumbing.Hash{"lib": commitIDs[0]}
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		err := verifyEntry(context.Background(), repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrSubmodulesMismatch)
+	})
+
 	t.Run("successful verification with higher threshold", func(t *testing.T) {
 		repo, state := createTestRepository(t, createTestStateWithThresholdPolicy)
 
@@ -940,6 +1321,190 @@ func TestVerifyEntry(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("successful verification with higher threshold using change set", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithThresholdPolicy)
+
+		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+
+		commit, err := gitinterface.GetCommit(repo, commitIDs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Authorize this change as part of a change set spanning another
+		// repository
+		changeSet := &attestations.ChangeSet{
+			ID: "release",
+			Changes: []*attestations.ChangeSetMember{
+				{Repository: "app", TargetRef: refName, FromRevisionID: plumbing.ZeroHash.String(), TargetTreeID: commit.TreeHash.String()},
+				{Repository: "lib", TargetRef: refName, FromRevisionID: plumbing.ZeroHash.String(), TargetTreeID: commit.TreeHash.String()},
+			},
+		}
+		statement, err := attestations.NewChangeSetAttestation(changeSet)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets1KeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := dsse.CreateEnvelope(statement)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(testCtx, env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := currentAttestations.SetChangeSetAttestation(repo, env, refName, plumbing.ZeroHash.String(), commit.TreeHash.String()); err != nil {
+			t.Fatal(err)
+		}
+		if err := currentAttestations.Commit(repo, "Add change set", false); err != nil {
+			t.Fatal(err)
+		}
+
+		currentAttestations, err = attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unsuccessful verification with higher threshold without authorization", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithThresholdPolicy)
+
+		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
+
+	t.Run("successful verification with key in current shift of rotation", func(t *testing.T) {
+		// The test entries are created on 1995-10-26, in the first shift
+		repo, state := createTestRepository(t, createTestStateWithRotationPolicyCreator("1995-10-20T00:00:00Z"))
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		// The rotation is evaluated at the time of the next entry signed
+		// using a key in the policy
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+
+		err := verifyEntry(testCtx, repo, state, nil, entry)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unsuccessful verification with key not in current shift of rotation", func(t *testing.T) {
+		// The test entries are created on 1995-10-26, in the second shift
+		repo, state := createTestRepository(t, createTestStateWithRotationPolicyCreator("1995-10-13T00:00:00Z"))
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		// The rotation is evaluated at the time of the next entry signed
+		// using a key in the policy
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+
+		err := verifyEntry(testCtx, repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
+
+	t.Run("unsuccessful verification before rotation starts", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithRotationPolicyCreator("2000-01-01T00:00:00Z"))
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		// The rotation is evaluated at the time of the next entry signed
+		// using a key in the policy
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+
+		err := verifyEntry(testCtx, repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
+
+	t.Run("successful verification with ticket required by rule", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithTicketPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.Tickets = []string{"https://example.com/issues/1"}
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err := verifyEntry(testCtx, repo, state, nil, entry)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unsuccessful verification without ticket required by rule", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithTicketPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err := verifyEntry(testCtx, repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrTicketRequired)
+	})
+
+	t.Run("successful verification of fast-forward with linear history required by rule", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithLinearHistoryPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[1])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err := verifyEntry(testCtx, repo, state, nil, entry)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unsuccessful verification of non-fast-forward with linear history required by rule", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithLinearHistoryPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[1]), gpgKeyBytes)
+
+		// Rewind the ref
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err := verifyEntry(testCtx, repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrUpdateTypeNotAllowed)
+	})
+
 	// FIXME: test for file policy passing for situations where a commit is seen
 	// by the RSL before its signing key is rotated out. This commit should be
 	// trusted for merges under the new policy because it predates the policy
@@ -948,6 +1513,69 @@ func TestVerifyEntry(t *testing.T) {
 	// signature, unseen by the RSL.
 }
 
+func TestGetUpdateType(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 3, gpgKeyBytes)
+	featureCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, "refs/heads/feature", 1, gpgKeyBytes)
+
+	mergeCommit := gitinterface.CreateCommitObject(common.TestGitConfig, gitinterface.EmptyTree(), []plumbing.Hash{commitIDs[2], featureCommitIDs[0]}, "Test merge commit", common.TestClock)
+	ref, err := repo.Reference(plumbing.ReferenceName(refName), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mergeCommitID, err := gitinterface.ApplyCommit(repo, mergeCommit, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		oldID              plumbing.Hash
+		newID              plumbing.Hash
+		expectedUpdateType string
+	}{
+		"single commit": {
+			oldID:              commitIDs[0],
+			newID:              commitIDs[1],
+			expectedUpdateType: tuf.UpdateTypeSquash,
+		},
+		"several commits": {
+			oldID:              commitIDs[0],
+			newID:              commitIDs[2],
+			expectedUpdateType: tuf.UpdateTypeFastForward,
+		},
+		"merge commit": {
+			oldID:              commitIDs[2],
+			newID:              mergeCommitID,
+			expectedUpdateType: tuf.UpdateTypeMerge,
+		},
+		"fast-forward including merge commit": {
+			oldID:              commitIDs[0],
+			newID:              mergeCommitID,
+			expectedUpdateType: tuf.UpdateTypeMerge,
+		},
+		"rewind": {
+			oldID:              commitIDs[2],
+			newID:              commitIDs[0],
+			expectedUpdateType: tuf.UpdateTypeNonFastForward,
+		},
+		"unrelated history": {
+			oldID:              commitIDs[2],
+			newID:              featureCommitIDs[0],
+			expectedUpdateType: tuf.UpdateTypeNonFastForward,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			updateType, err := GetUpdateType(repo, test.oldID, test.newID)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expectedUpdateType, updateType)
+		})
+	}
+}
+
 func TestVerifyTagEntry(t *testing.T) {
 	t.Run("no tag specific policy", func(t *testing.T) {
 		repo, policy := createTestRepository(t, createTestStateWithPolicy)
@@ -1008,6 +1636,106 @@ func TestVerifyTagEntry(t *testing.T) {
 		err := verifyTagEntry(context.Background(), repo, policy, entry)
 		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
 	})
+
+	t.Run("tag signed using key restricted to merge commits", func(t *testing.T) {
+		for _, pattern := range []string{"git:refs/tags/*", "git:refs/heads/main"} {
+			// The key is either trusted by the rule protecting the tag, or
+			// the tag isn't protected and all keys in the policy are used
+			repo, policy := createTestRepository(t, createTestStateWithMergeCommitOnlyKeyPolicyCreator(pattern))
+
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, "refs/heads/main", 1, gpgKeyBytes)
+			tagID := common.CreateTestSignedTag(t, repo, "v1", commitIDs[0], gpgUnauthorizedKeyBytes)
+
+			entry := rsl.NewReferenceEntry(string(plumbing.NewTagReferenceName("v1")), tagID)
+			entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgUnauthorizedKeyBytes)
+			entry.ID = entryID
+
+			err := verifyTagEntry(context.Background(), repo, policy, entry)
+			assert.ErrorIs(t, err, ErrUnauthorizedSignature, pattern)
+		}
+	})
+
+	t.Run("lightweight tag", func(t *testing.T) {
+		repo, policy := createTestRepository(t, createTestStateWithPolicy)
+		refName := "refs/heads/main"
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+
+		tagRefName := plumbing.NewTagReferenceName("v1")
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(tagRefName, commitIDs[0])); err != nil {
+			t.Fatal(err)
+		}
+
+		entry := rsl.NewReferenceEntry(string(tagRefName), commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err := verifyTagEntry(context.Background(), repo, policy, entry)
+		assert.ErrorIs(t, err, ErrNotAnnotatedTag)
+	})
+
+	t.Run("tag object for different tag", func(t *testing.T) {
+		repo, policy := createTestRepository(t, createTestStateWithPolicy)
+		refName := "refs/heads/main"
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		tagID := common.CreateTestSignedTag(t, repo, "v1", commitIDs[0], gpgKeyBytes)
+
+		// Present the signed tag for v1 as v2
+		tagRefName := plumbing.NewTagReferenceName("v2")
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(tagRefName, tagID)); err != nil {
+			t.Fatal(err)
+		}
+
+		entry := rsl.NewReferenceEntry(string(tagRefName), tagID)
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err := verifyTagEntry(context.Background(), repo, policy, entry)
+		assert.ErrorIs(t, err, ErrTagNameMismatch)
+	})
+
+	t.Run("tag reference changed after recording", func(t *testing.T) {
+		repo, policy := createTestRepository(t, createTestStateWithPolicy)
+		refName := "refs/heads/main"
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyBytes)
+		tagID := common.CreateTestSignedTag(t, repo, "v1", commitIDs[0], gpgKeyBytes)
+
+		entry := rsl.NewReferenceEntry(string(plumbing.NewTagReferenceName("v1")), tagID)
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		// Retag v1 without recording it in the RSL
+		common.CreateTestSignedTag(t, repo, "v1", commitIDs[1], gpgKeyBytes)
+
+		err := verifyTagEntry(context.Background(), repo, policy, entry)
+		assert.ErrorIs(t, err, ErrTagTargetMismatch)
+	})
+
+	t.Run("nested annotated tag", func(t *testing.T) {
+		repo, policy := createTestRepository(t, createTestStateWithPolicy)
+		refName := "refs/heads/main"
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		innerTagID := common.CreateTestSignedTag(t, repo, "v1-rc", commitIDs[0], gpgKeyBytes)
+		tagID := common.CreateTestSignedTag(t, repo, "v1", innerTagID, gpgKeyBytes)
+
+		entry := rsl.NewReferenceEntry(string(plumbing.NewTagReferenceName("v1")), tagID)
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err := verifyTagEntry(context.Background(), repo, policy, entry)
+		assert.Nil(t, err)
+
+		tagObj, err := gitinterface.GetTag(repo, tagID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetID, err := resolveTagTarget(repo, tagObj)
+		assert.Nil(t, err)
+		assert.Equal(t, commitIDs[0], targetID)
+	})
 }
 
 func TestGetCommits(t *testing.T) {
@@ -1178,6 +1906,11 @@ func TestVerifier(t *testing.T) {
 	tag := gitinterface.CreateTagObject(common.TestGitConfig, commit, "test-tag", "test-tag", common.TestClock)
 	tag = common.SignTestTag(t, repo, tag, gpgKeyBytes)
 
+	mergeCommit := gitinterface.CreateCommitObject(common.TestGitConfig, gitinterface.EmptyTree(), []plumbing.Hash{commitID, commitID}, "Test merge commit", common.TestClock)
+	mergeCommit = common.SignTestCommit(t, repo, mergeCommit, gpgKeyBytes)
+
+	mergeCommitOnly := map[string][]string{gpgKey.KeyID: {tuf.KeyOperationMergeCommit}}
+
 	attestation, err := dsse.CreateEnvelope(nil)
 	if err != nil {
 		t.Fatal(err)
@@ -1209,8 +1942,17 @@ func TestVerifier(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// Signatures from the same key must only be counted once towards the
+	// threshold
+	attestationWithRepeatedSigs := &sslibdsse.Envelope{
+		PayloadType: attestation.PayloadType,
+		Payload:     attestation.Payload,
+		Signatures:  []sslibdsse.Signature{attestation.Signatures[0], attestation.Signatures[0]},
+	}
+
 	tests := map[string]struct {
 		keys          []*tuf.Key
+		keyOperations map[string][]string
 		threshold     int
 		gitObject     object.Object
 		attestation   *sslibdsse.Envelope
@@ -1252,6 +1994,19 @@ func TestVerifier(t *testing.T) {
 			gitObject:   commit,
 			attestation: attestationWithTwoSigs,
 		},
+		"commit, attestation with repeated signatures from one key, threshold 3": {
+			keys:          []*tuf.Key{gpgKey, rootPubKey, targetsPubKey},
+			threshold:     3,
+			gitObject:     commit,
+			attestation:   attestationWithRepeatedSigs,
+			expectedError: ErrVerifierConditionsUnmet,
+		},
+		"no Git object, attestation with repeated signatures from one key, threshold 2": {
+			keys:          []*tuf.Key{rootPubKey, targetsPubKey},
+			threshold:     2,
+			attestation:   attestationWithRepeatedSigs,
+			expectedError: ErrVerifierConditionsUnmet,
+		},
 		"tag, no attestation, valid key, threshold 1": {
 			keys:      []*tuf.Key{gpgKey},
 			threshold: 1,
@@ -1288,10 +2043,50 @@ func TestVerifier(t *testing.T) {
 			gitObject:   tag,
 			attestation: attestationWithTwoSigs,
 		},
+		"merge commit, no attestation, key restricted to merge commits, threshold 1": {
+			keys:          []*tuf.Key{gpgKey},
+			keyOperations: mergeCommitOnly,
+			threshold:     1,
+			gitObject:     mergeCommit,
+		},
+		"commit, no attestation, key restricted to merge commits, threshold 1": {
+			keys:          []*tuf.Key{gpgKey},
+			keyOperations: mergeCommitOnly,
+			threshold:     1,
+			gitObject:     commit,
+			expectedError: ErrVerifierConditionsUnmet,
+		},
+		"commit, no attestation, key restricted to commits, threshold 1": {
+			keys:          []*tuf.Key{gpgKey},
+			keyOperations: map[string][]string{gpgKey.KeyID: {tuf.KeyOperationCommit}},
+			threshold:     1,
+			gitObject:     commit,
+		},
+		"tag, no attestation, key restricted to merge commits, threshold 1": {
+			keys:          []*tuf.Key{gpgKey},
+			keyOperations: mergeCommitOnly,
+			threshold:     1,
+			gitObject:     tag,
+			expectedError: ErrVerifierConditionsUnmet,
+		},
+		"merge commit, attestation, restricted and unrestricted keys, threshold 2": {
+			keys:          []*tuf.Key{gpgKey, rootPubKey},
+			keyOperations: mergeCommitOnly,
+			threshold:     2,
+			gitObject:     mergeCommit,
+			attestation:   attestation,
+		},
+		"no Git object, attestation, key restricted to merge commits, threshold 1": {
+			keys:          []*tuf.Key{rootPubKey},
+			keyOperations: map[string][]string{rootPubKey.KeyID: {tuf.KeyOperationMergeCommit}},
+			threshold:     1,
+			attestation:   attestation,
+			expectedError: ErrVerifierConditionsUnmet,
+		},
 	}
 
 	for name, test := range tests {
-		verifier := Verifier{name: "test-verifier", keys: test.keys, threshold: test.threshold}
+		verifier := Verifier{name: "test-verifier", keys: test.keys, threshold: test.threshold, keyOperations: test.keyOperations}
 		err := verifier.Verify(context.Background(), test.gitObject, test.attestation)
 		if test.expectedError == nil {
 			assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/report"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ErrPolicyViolations is returned when verification continues past the first
+// policy violation. It records every violation found, in the order of the RSL.
+type ErrPolicyViolations struct {
+	Violations []*PolicyViolation
+}
+
+// PolicyViolation is a violation of the policy found during verification.
+type PolicyViolation struct {
+	// EntryID is the ID of the RSL entry that violates the policy. It is the
+	// zero ID if the violation isn't for a specific entry.
+	EntryID plumbing.Hash
+
+	Err error
+}
+
+func (e *ErrPolicyViolations) Error() string {
+	lines := []string{fmt.Sprintf("found %d policy violations:", len(e.Violations))}
+	for _, violation := range e.Violations {
+		if violation.EntryID.IsZero() {
+			lines = append(lines, fmt.Sprintf("  - %s", violation.Err.Error()))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  - entry '%s': %s", violation.EntryID.String(), violation.Err.Error()))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (e *ErrPolicyViolations) Unwrap() []error {
+	errs := make([]error, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		errs = append(errs, violation.Err)
+	}
+
+	return errs
+}
+
+// Add records a violation for the specified entry.
+func (e *ErrPolicyViolations) Add(entryID plumbing.Hash, err error) {
+	e.Violations = append(e.Violations, &PolicyViolation{EntryID: entryID, Err: err})
+}
+
+// ErrOrNil returns the violations as an error, or nil if no violations were
+// recorded.
+func (e *ErrPolicyViolations) ErrOrNil() error {
+	if len(e.Violations) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// violationCollector records violations when verification keeps going past
+// them, otherwise it returns each violation so verification stops.
+type violationCollector struct {
+	keepGoing  bool
+	violations *ErrPolicyViolations
+}
+
+func newViolationCollector(keepGoing bool) *violationCollector {
+	return &violationCollector{keepGoing: keepGoing, violations: &ErrPolicyViolations{}}
+}
+
+// record returns the error if verification must stop, or nil if the violation
+// was recorded and verification can continue.
+func (c *violationCollector) record(entryID plumbing.Hash, err error) error {
+	report.RecordViolation(entryID.String(), err)
+	if !c.keepGoing {
+		return err
+	}
+
+	c.violations.Add(entryID, err)
+	return nil
+}
+
+// stop returns the error that ends verification when it can't continue past
+// the violation, including the violations recorded so far.
+func (c *violationCollector) stop(entryID plumbing.Hash, err error) error {
+	report.RecordViolation(entryID.String(), err)
+	if !c.keepGoing {
+		return err
+	}
+
+	c.violations.Add(entryID, err)
+	return c.violations
+}
+
+// result returns the violations recorded, if any.
+func (c *violationCollector) result() error {
+	return c.violations.ErrOrNil()
+}
@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package progress reports the progress of long running operations, such as
+// cloning, verifying, and syncing, as machine-parseable events so that GUI
+// frontends and IDE plugins can display determinate progress without scraping
+// gittuf's human-oriented output. Reporting is disabled by default, in which
+// case reporting progress is a no-op.
+package progress
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	FormatNone = "none"
+	FormatJSON = "json"
+
+	OperationClone  = "clone"
+	OperationVerify = "verify"
+	OperationSync   = "sync"
+
+	// EventStart is emitted when an operation starts.
+	EventStart = "start"
+
+	// EventStage is emitted when an operation enters a new stage.
+	EventStage = "stage"
+
+	// EventProgress is emitted when a step of the current stage completes.
+	EventProgress = "progress"
+
+	// EventFinish is emitted when an operation completes, successfully or
+	// not.
+	EventFinish = "finish"
+)
+
+var ErrInvalidProgressFormat = errors.New("invalid progress format (not one of none, json)")
+
+// Event describes the progress of an operation. Events are written as JSON
+// objects, one per line. Unlike logs, every event has an `event` key.
+type Event struct {
+	Time time.Time `json:"time"`
+
+	// Event is one of EventStart, EventStage, EventProgress, and EventFinish.
+	Event string `json:"event"`
+
+	// Operation is the operation the event belongs to, such as
+	// OperationVerify.
+	Operation string `json:"operation"`
+
+	// Stage describes what the operation is currently doing, such as
+	// verifying RSL entries. It is empty until the operation enters its first
+	// stage.
+	Stage string `json:"stage,omitempty"`
+
+	// Current is the number of steps of the stage that have completed.
+	Current int `json:"current"`
+
+	// Total is the number of steps in the stage. It is zero if the number of
+	// steps isn't known, in which case progress is indeterminate.
+	Total int `json:"total"`
+
+	// Error is the error the operation failed with, set only for
+	// EventFinish.
+	Error string `json:"error,omitempty"`
+}
+
+type operation struct {
+	name    string
+	stage   string
+	current int
+	total   int
+}
+
+type reporter struct {
+	mu      sync.Mutex
+	enabled atomic.Bool
+	w       io.Writer
+
+	// operations are the operations in progress, the innermost last
+	operations []*operation
+}
+
+var global = &reporter{}
+
+// ValidateFormat checks that the progress format is one of FormatNone and
+// FormatJSON.
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatNone, FormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("%w: '%s'", ErrInvalidProgressFormat, format)
+	}
+}
+
+// Enable starts writing progress events to w, discarding any operations
+// started earlier.
+func Enable(w io.Writer) {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	global.enabled.Store(true)
+	global.w = w
+	global.operations = []*operation{}
+}
+
+// Disable stops writing progress events.
+func Disable() {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	global.enabled.Store(false)
+	global.w = nil
+}
+
+// Start records the start of an operation. Stages and progress reported until
+// the operation completes belong to it. The returned function must be invoked
+// with the operation's result when it completes.
+func Start(name string) func(error) {
+	if !global.enabled.Load() {
+		return func(error) {}
+	}
+
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	op := &operation{name: name}
+	global.operations = append(global.operations, op)
+	global.emit(EventStart, op, nil)
+
+	return func(err error) {
+		if !global.enabled.Load() {
+			return
+		}
+
+		global.mu.Lock()
+		defer global.mu.Unlock()
+
+		index := slices.Index(global.operations, op)
+		if index == -1 {
+			// Reporting was re-enabled after the operation started
+			return
+		}
+
+		global.operations = slices.Delete(global.operations, index, index+1)
+		global.emit(EventFinish, op, err)
+	}
+}
+
+// Stage records that the current operation has entered a new stage with the
+// specified number of steps. The number of steps is zero if it isn't known.
+// If no operation is in progress, this is a no-op.
+func Stage(stage string, total int) {
+	if !global.enabled.Load() {
+		return
+	}
+
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	op := global.current()
+	if op == nil {
+		return
+	}
+
+	op.stage = stage
+	op.current = 0
+	op.total = total
+	global.emit(EventStage, op, nil)
+}
+
+// Advance records that a step of the current operation's stage has completed.
+// Steps may complete concurrently. If no operation is in progress, this is a
+// no-op.
+func Advance() {
+	if !global.enabled.Load() {
+		return
+	}
+
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	op := global.current()
+	if op == nil {
+		return
+	}
+
+	op.current++
+	global.emit(EventProgress, op, nil)
+}
+
+// current returns the innermost operation in progress, if any. The caller must
+// hold the lock.
+func (r *reporter) current() *operation {
+	if len(r.operations) == 0 {
+		return nil
+	}
+
+	return r.operations[len(r.operations)-1]
+}
+
+// emit writes an event for the operation. Progress is informational, so
+// failing to write it doesn't fail the operation. The caller must hold the
+// lock.
+func (r *reporter) emit(event string, op *operation, err error) {
+	e := &Event{
+		Time:      time.Now().UTC(),
+		Event:     event,
+		Operation: op.name,
+		Stage:     op.stage,
+		Current:   op.current,
+		Total:     op.total,
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	// The encoder writes each event, including its trailing newline, in a
+	// single write so that events aren't interleaved with logs
+	_ = json.NewEncoder(r.w).Encode(e)
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFormat(t *testing.T) {
+	assert.Nil(t, ValidateFormat(FormatNone))
+	assert.Nil(t, ValidateFormat(FormatJSON))
+	assert.ErrorIs(t, ValidateFormat("text"), ErrInvalidProgressFormat)
+}
+
+func TestReporter(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		Disable()
+
+		finish := Start(OperationVerify)
+		Stage("verifying RSL entries", 2)
+		Advance()
+		finish(nil)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		Enable(output)
+		defer Disable()
+
+		// Progress outside an operation is dropped
+		Stage("verifying RSL entries", 1)
+		Advance()
+
+		finish := Start(OperationClone)
+		Stage("fetching repository", 0)
+		Stage("verifying RSL entries", 2)
+		Advance()
+		Advance()
+		finish(errors.New("verification failed"))
+
+		events := decodeEvents(t, output)
+		assert.Len(t, events, 6)
+
+		for _, event := range events {
+			assert.Equal(t, OperationClone, event.Operation)
+			assert.False(t, event.Time.IsZero())
+		}
+
+		assert.Equal(t, EventStart, events[0].Event)
+		assert.Empty(t, events[0].Stage)
+
+		assert.Equal(t, EventStage, events[1].Event)
+		assert.Equal(t, "fetching repository", events[1].Stage)
+		assert.Equal(t, 0, events[1].Total)
+
+		assert.Equal(t, EventStage, events[2].Event)
+		assert.Equal(t, "verifying RSL entries", events[2].Stage)
+		assert.Equal(t, 0, events[2].Current)
+		assert.Equal(t, 2, events[2].Total)
+
+		assert.Equal(t, EventProgress, events[3].Event)
+		assert.Equal(t, 1, events[3].Current)
+		assert.Equal(t, EventProgress, events[4].Event)
+		assert.Equal(t, 2, events[4].Current)
+
+		assert.Equal(t, EventFinish, events[5].Event)
+		assert.Equal(t, "verification failed", events[5].Error)
+	})
+
+	t.Run("nested operations", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		Enable(output)
+		defer Disable()
+
+		finishSync := Start(OperationSync)
+		finishVerify := Start(OperationVerify)
+		Stage("verifying RSL entries", 1)
+		finishVerify(nil)
+		Stage("pushing RSL", 0)
+		finishSync(nil)
+
+		events := decodeEvents(t, output)
+		operations := []string{}
+		for _, event := range events {
+			operations = append(operations, event.Operation+" "+event.Event)
+		}
+		assert.Equal(t, []string{"sync start", "verify start", "verify stage", "verify finish", "sync stage", "sync finish"}, operations)
+		assert.Empty(t, events[5].Error)
+	})
+}
+
+func decodeEvents(t *testing.T, output *bytes.Buffer) []*Event {
+	t.Helper()
+
+	events := []*Event{}
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		event := &Event{}
+		if err := json.Unmarshal([]byte(line), event); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+
+	return events
+}
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package proxy implements a read-only Git smart HTTP server that fronts an
+// upstream remote, serving only the refs that pass gittuf verification. The
+// upstream's refs are fetched and verified periodically, and the verified refs
+// are recorded in a Git namespace that is served using git http-backend.
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cgi"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gittuf/gittuf/internal/repository"
+)
+
+const (
+	uploadPackService  = "git-upload-pack"
+	receivePackService = "git-receive-pack"
+
+	infoRefsPath = "/info/refs"
+)
+
+var ErrGitNotFound = errors.New("unable to find Git binary, is Git installed?")
+
+// Options configures the proxy.
+type Options struct {
+	// GitDir is the Git directory of the repository the upstream's refs are
+	// fetched into and served from.
+	GitDir string
+
+	// RemoteName is the remote for the upstream.
+	RemoteName string
+}
+
+// Handler is an http.Handler that serves the verified refs of the upstream
+// using the Git smart HTTP protocol. Only fetches are supported.
+type Handler struct {
+	repo    *repository.Repository
+	options *Options
+	backend *cgi.Handler
+
+	// mu ensures the served refs are not updated while a request is
+	// served, so that the refs advertised to a client remain available for
+	// the rest of its fetch.
+	mu sync.RWMutex
+}
+
+// NewHandler returns a handler that serves the verified refs of the upstream
+// fetched into the repository.
+func NewHandler(repo *repository.Repository, options *Options) (*Handler, error) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		return nil, ErrGitNotFound
+	}
+
+	gitDir, err := filepath.Abs(options.GitDir)
+	if err != nil {
+		return nil, err
+	}
+
+	backend := &cgi.Handler{
+		Path: gitPath,
+		Args: []string{"http-backend"},
+		Env: []string{
+			"GIT_PROJECT_ROOT=" + gitDir,
+			"GIT_HTTP_EXPORT_ALL=1",
+			// Only the refs in the namespace are advertised and may be
+			// fetched
+			"GIT_NAMESPACE=" + repository.ProxyNamespace,
+		},
+	}
+
+	return &Handler{repo: repo, options: options, backend: backend}, nil
+}
+
+// Refresh fetches and verifies the upstream's refs, updating the served refs.
+func (h *Handler) Refresh(ctx context.Context) ([]*repository.ProxyRefStatus, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.repo.RefreshProxyRefs(ctx, h.options.RemoteName)
+}
+
+// ServeHTTP implements the http.Handler interface. The repository is served at
+// every path, so clients may use any URL on the server. Requests other than
+// those of the smart HTTP protocol for fetches are rejected, as the dumb HTTP
+// protocol would serve objects regardless of whether they were verified.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var backendPath string
+	switch {
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, infoRefsPath):
+		switch service := r.URL.Query().Get("service"); service {
+		case uploadPackService:
+			backendPath = infoRefsPath
+		case receivePackService:
+			http.Error(w, "pushing is not supported by the gittuf proxy", http.StatusForbidden)
+			return
+		default:
+			http.Error(w, fmt.Sprintf("unsupported service '%s', the gittuf proxy only serves the smart HTTP protocol", service), http.StatusForbidden)
+			return
+		}
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/"+uploadPackService):
+		backendPath = "/" + uploadPackService
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/"+receivePackService):
+		http.Error(w, "pushing is not supported by the gittuf proxy", http.StatusForbidden)
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	backendRequest := r.Clone(r.Context())
+	backendRequest.URL.Path = backendPath
+	// Protocol v2 is not negotiated, as git upload-pack then accepts requests
+	// for objects that are not reachable from the advertised refs
+	backendRequest.Header.Del("Git-Protocol")
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	h.backend.ServeHTTP(w, backendRequest)
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	gitDir := t.TempDir()
+	repo, err := gitinterface.PlainInit(gitDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	treeID, err := gitinterface.WriteTree(repo, []object.TreeEntry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifiedCommitID, err := gitinterface.Commit(repo, treeID, "refs/namespaces/"+repository.ProxyNamespace+"/refs/heads/main", "Verified commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unverifiedCommitID, err := gitinterface.Commit(repo, treeID, "refs/heads/unverified", "Unverified commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler, err := NewHandler(nil, &Options{GitDir: gitDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	t.Run("only verified refs are advertised", func(t *testing.T) {
+		for _, protocolVersion := range []string{"0", "2"} {
+			output, err := runGit(t, "", "-c", "protocol.version="+protocolVersion, "ls-remote", server.URL+"/repo.git")
+			assert.Nil(t, err, output)
+			assert.Equal(t, verifiedCommitID.String()+"\trefs/heads/main", output)
+		}
+	})
+
+	t.Run("verified refs can be fetched", func(t *testing.T) {
+		localDir := t.TempDir()
+		if _, err := runGit(t, localDir, "init", "--bare", "--object-format", gitinterface.BuildObjectFormat()); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, protocolVersion := range []string{"0", "2"} {
+			output, err := runGit(t, localDir, "-c", "protocol.version="+protocolVersion, "fetch", server.URL, "refs/heads/main")
+			assert.Nil(t, err, output)
+		}
+	})
+
+	t.Run("unverified objects cannot be fetched", func(t *testing.T) {
+		localDir := t.TempDir()
+		if _, err := runGit(t, localDir, "init", "--bare", "--object-format", gitinterface.BuildObjectFormat()); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, protocolVersion := range []string{"0", "2"} {
+			_, err := runGit(t, localDir, "-c", "protocol.version="+protocolVersion, "fetch", server.URL, unverifiedCommitID.String())
+			assert.NotNil(t, err)
+		}
+	})
+
+	t.Run("pushes and dumb HTTP requests are rejected", func(t *testing.T) {
+		tests := map[string]struct {
+			method         string
+			path           string
+			expectedStatus int
+		}{
+			"receive-pack refs": {
+				method:         http.MethodGet,
+				path:           "/info/refs?service=git-receive-pack",
+				expectedStatus: http.StatusForbidden,
+			},
+			"receive-pack": {
+				method:         http.MethodPost,
+				path:           "/git-receive-pack",
+				expectedStatus: http.StatusForbidden,
+			},
+			"dumb refs": {
+				method:         http.MethodGet,
+				path:           "/info/refs",
+				expectedStatus: http.StatusForbidden,
+			},
+			"dumb object": {
+				method:         http.MethodGet,
+				path:           "/objects/" + unverifiedCommitID.String()[:2] + "/" + unverifiedCommitID.String()[2:],
+				expectedStatus: http.StatusNotFound,
+			},
+			"dumb HEAD": {
+				method:         http.MethodGet,
+				path:           "/HEAD",
+				expectedStatus: http.StatusNotFound,
+			},
+		}
+
+		for name, test := range tests {
+			request, err := http.NewRequest(test.method, server.URL+test.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			response.Body.Close() //nolint:errcheck
+			assert.Equal(t, test.expectedStatus, response.StatusCode, name)
+		}
+	})
+
+	// The unverified commit remains in the repository
+	_, err = gitinterface.GetCommit(repo, unverifiedCommitID)
+	assert.Nil(t, err)
+	_, err = repo.Reference(plumbing.ReferenceName("refs/heads/unverified"), true)
+	assert.Nil(t, err)
+}
+
+func runGit(t *testing.T, dir string, args ...string) (string, error) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(), "GIT_CONFIG_NOSYSTEM=1", "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.CombinedOutput()
+	return strings.TrimSpace(string(output)), err
+}
@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package report records what gittuf checked during verification, such as the
+// RSL entries verified, the signatures and attestations used, and the policy
+// violations found, so that the results can be consumed by CI systems.
+// Recording is disabled by default, in which case recording is a no-op.
+package report
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Report is a machine-readable summary of the verification of a ref.
+type Report struct {
+	Ref      string `json:"ref"`
+	Verified bool   `json:"verified"`
+
+	// Error is the error verification failed with, if any.
+	Error string `json:"error,omitempty"`
+
+	// EntriesChecked is the number of RSL entries for the ref that were
+	// verified. Entries verified by earlier runs and recorded in the
+	// verification cache are not checked again.
+	EntriesChecked int      `json:"entriesChecked"`
+	Entries        []*Entry `json:"entries"`
+
+	// PolicyStates are the IDs of the RSL entries for the policy states used
+	// during verification.
+	PolicyStates []string `json:"policyStates"`
+
+	Violations []*Violation `json:"violations"`
+}
+
+// Entry is an RSL entry checked during verification.
+type Entry struct {
+	ID       string `json:"id"`
+	RefName  string `json:"refName"`
+	TargetID string `json:"targetID"`
+
+	// Signatures are the signatures verified for the entry, on the entry
+	// itself, the commits it records, or the tag it records.
+	Signatures []*Signature `json:"signatures"`
+
+	// Attestations are the predicate types of the attestations used as
+	// evidence for the entry, such as reference authorizations.
+	Attestations []string `json:"attestations"`
+}
+
+// Signature is a threshold of signatures verified for a Git object.
+type Signature struct {
+	ObjectID string `json:"objectID"`
+
+	// Rule is the name of the rule the signatures were verified using. It is
+	// empty if the signatures were verified using any key in the policy, such
+	// as for tags.
+	Rule string `json:"rule,omitempty"`
+
+	// KeyIDs are the IDs of the keys whose signatures met the threshold,
+	// including keys that signed attestations.
+	KeyIDs []string `json:"keyIDs"`
+}
+
+// Violation is a policy violation found during verification.
+type Violation struct {
+	// EntryID is the ID of the RSL entry that violates the policy. It is empty
+	// if the violation isn't for a specific entry.
+	EntryID string `json:"entryID,omitempty"`
+
+	Error string `json:"error"`
+}
+
+type recorder struct {
+	mu      sync.Mutex
+	enabled atomic.Bool
+
+	entries      []*Entry
+	entriesByID  map[string]*Entry
+	policyStates []string
+	violations   []*Violation
+}
+
+var global = &recorder{}
+
+// Enable starts recording verification, discarding anything recorded earlier.
+func Enable() {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	global.enabled.Store(true)
+	global.entries = []*Entry{}
+	global.entriesByID = map[string]*Entry{}
+	global.policyStates = []string{}
+	global.violations = []*Violation{}
+}
+
+// Disable stops recording verification.
+func Disable() {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	global.enabled.Store(false)
+}
+
+// RecordEntry records that the RSL entry was checked. Entries are reported in
+// the order they're first recorded.
+func RecordEntry(entryID, refName, targetID string) {
+	if !global.enabled.Load() {
+		return
+	}
+
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	entry := global.getEntry(entryID)
+	entry.RefName = refName
+	entry.TargetID = targetID
+	if !slices.Contains(global.entries, entry) {
+		global.entries = append(global.entries, entry)
+	}
+}
+
+// RecordSignature records the signatures verified for the object while
+// checking the RSL entry.
+func RecordSignature(entryID, objectID, rule string, keyIDs []string) {
+	if !global.enabled.Load() {
+		return
+	}
+
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	entry := global.getEntry(entryID)
+	entry.Signatures = append(entry.Signatures, &Signature{ObjectID: objectID, Rule: rule, KeyIDs: slices.Clone(keyIDs)})
+}
+
+// RecordAttestation records that an attestation of the specified predicate
+// type was used as evidence while checking the RSL entry.
+func RecordAttestation(entryID, predicateType string) {
+	if !global.enabled.Load() {
+		return
+	}
+
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	entry := global.getEntry(entryID)
+	if !slices.Contains(entry.Attestations, predicateType) {
+		entry.Attestations = append(entry.Attestations, predicateType)
+	}
+}
+
+// RecordPolicyState records that the policy state in the RSL entry was used.
+func RecordPolicyState(entryID string) {
+	if !global.enabled.Load() {
+		return
+	}
+
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	if !slices.Contains(global.policyStates, entryID) {
+		global.policyStates = append(global.policyStates, entryID)
+	}
+}
+
+// RecordViolation records a policy violation. The entry ID is empty if the
+// violation isn't for a specific entry.
+func RecordViolation(entryID string, err error) {
+	if !global.enabled.Load() {
+		return
+	}
+
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	global.violations = append(global.violations, &Violation{EntryID: entryID, Error: err.Error()})
+}
+
+// Build returns the report for the verification of the ref, which returned
+// err. It returns nil if recording is disabled.
+func Build(ref string, err error) *Report {
+	if !global.enabled.Load() {
+		return nil
+	}
+
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	report := &Report{
+		Ref:            ref,
+		Verified:       err == nil,
+		EntriesChecked: len(global.entries),
+		Entries:        global.entries,
+		PolicyStates:   global.policyStates,
+		Violations:     global.violations,
+	}
+
+	for _, entry := range report.Entries {
+		if entry.Signatures == nil {
+			entry.Signatures = []*Signature{}
+		}
+		if entry.Attestations == nil {
+			entry.Attestations = []string{}
+		}
+	}
+
+	if err != nil {
+		report.Error = err.Error()
+	}
+
+	return report
+}
+
+// getEntry returns the entry with the specified ID, creating it if it hasn't
+// been recorded yet. The caller must hold the lock.
+func (r *recorder) getEntry(entryID string) *Entry {
+	entry, has := r.entriesByID[entryID]
+	if !has {
+		entry = &Entry{ID: entryID}
+		r.entriesByID[entryID] = entry
+	}
+
+	return entry
+}
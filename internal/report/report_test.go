@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		Disable()
+
+		RecordEntry("entry", "refs/heads/main", "target")
+		RecordViolation("entry", errors.New("violation"))
+
+		assert.Nil(t, Build("refs/heads/main", nil))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		Enable()
+		defer Disable()
+
+		// Signatures may be recorded before the entry, as entries are
+		// verified concurrently
+		RecordSignature("entry-1", "commit-1", "protect-main", []string{"key-1"})
+		RecordAttestation("entry-1", "https://gittuf.dev/reference-authorization/v0.1")
+		RecordAttestation("entry-1", "https://gittuf.dev/reference-authorization/v0.1")
+		RecordEntry("entry-1", "refs/heads/main", "commit-1")
+		RecordEntry("entry-2", "refs/heads/main", "commit-2")
+		RecordPolicyState("policy-1")
+		RecordPolicyState("policy-1")
+		RecordViolation("entry-2", errors.New("unauthorized signature"))
+
+		report := Build("refs/heads/main", errors.New("verification failed"))
+		assert.Equal(t, "refs/heads/main", report.Ref)
+		assert.False(t, report.Verified)
+		assert.Equal(t, "verification failed", report.Error)
+		assert.Equal(t, 2, report.EntriesChecked)
+		assert.Equal(t, []string{"policy-1"}, report.PolicyStates)
+
+		assert.Equal(t, "entry-1", report.Entries[0].ID)
+		assert.Equal(t, []*Signature{{ObjectID: "commit-1", Rule: "protect-main", KeyIDs: []string{"key-1"}}}, report.Entries[0].Signatures)
+		assert.Equal(t, []string{"https://gittuf.dev/reference-authorization/v0.1"}, report.Entries[0].Attestations)
+
+		assert.Equal(t, "entry-2", report.Entries[1].ID)
+		assert.Empty(t, report.Entries[1].Signatures)
+		assert.NotNil(t, report.Entries[1].Signatures)
+
+		assert.Equal(t, []*Violation{{EntryID: "entry-2", Error: "unauthorized signature"}}, report.Violations)
+	})
+
+	t.Run("enable discards earlier recording", func(t *testing.T) {
+		Enable()
+		RecordEntry("entry-1", "refs/heads/main", "commit-1")
+
+		Enable()
+		defer Disable()
+
+		report := Build("refs/heads/main", nil)
+		assert.True(t, report.Verified)
+		assert.Empty(t, report.Entries)
+		assert.Empty(t, report.Violations)
+	})
+}
@@ -0,0 +1,289 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/google/go-github/v61/github"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var (
+	ErrNotAbsoluteBranchRef         = errors.New("base branch must be an absolute branch ref")
+	ErrMergedPullRequestNotFound    = errors.New("merged pull request not found for merge commit")
+	ErrInvalidGitHubIdentityMapping = errors.New("invalid GitHub identity mapping")
+)
+
+// GitHubIdentityMapping maps GitHub usernames to the IDs of the users' keys in
+// the policy.
+type GitHubIdentityMapping map[string]string
+
+// GitHubPullRequestApprovals describes the approvals recorded for a merged
+// GitHub pull request using ApplyGitHubPullRequestApprovals.
+type GitHubPullRequestApprovals struct {
+	PullRequestNumber int
+	BaseBranch        string
+
+	// CommitID is the head of the pull request when it was merged.
+	CommitID string
+
+	// Approvers are the IDs of the policy keys of the users who approved
+	// CommitID.
+	Approvers []string
+
+	// UnmappedReviewers are the users who approved CommitID but are not in
+	// the identity mapping. Their approvals are not recorded.
+	UnmappedReviewers []string
+}
+
+// ParseGitHubIdentityMapping reads a JSON object that maps GitHub usernames to
+// key IDs, such as {"alice": "<key ID>"}. As on GitHub, usernames are matched
+// case-insensitively.
+func ParseGitHubIdentityMapping(reader io.Reader) (GitHubIdentityMapping, error) {
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	rawMapping := map[string]string{}
+	if err := json.Unmarshal(contents, &rawMapping); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidGitHubIdentityMapping, err)
+	}
+
+	mapping := GitHubIdentityMapping{}
+	for username, keyID := range rawMapping {
+		if username == "" || keyID == "" {
+			return nil, fmt.Errorf("%w: usernames and key IDs must not be empty", ErrInvalidGitHubIdentityMapping)
+		}
+
+		username = strings.ToLower(username)
+		if _, has := mapping[username]; has {
+			return nil, fmt.Errorf("%w: '%s' is mapped more than once", ErrInvalidGitHubIdentityMapping, username)
+		}
+		mapping[username] = keyID
+	}
+
+	return mapping, nil
+}
+
+// ApplyGitHubPullRequestApprovals records the approvals of the GitHub pull
+// request merged using the specified merge commit in an approval attestation
+// signed using signer, such as a CI system's key. Only approvals of the pull
+// request's head at the time it was merged are recorded. Approvers are mapped
+// to their keys in the current policy using the identity mapping, and the
+// attestation records the key IDs rather than the usernames, so that approvals
+// can be counted towards the thresholds of the policy's rules. Approvers who
+// are not in the mapping are skipped. Currently, the authentication token for
+// the GitHub API is read from the GITHUB_TOKEN environment variable.
+func (r *Repository) ApplyGitHubPullRequestApprovals(ctx context.Context, signer sslibdsse.SignerVerifier, owner, repository, mergeCommitID string, mapping GitHubIdentityMapping, signCommit bool) (*GitHubPullRequestApprovals, error) {
+	client, err := getGitHubClient()
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug(fmt.Sprintf("Identifying GitHub pull request merged using '%s'...", mergeCommitID))
+	pullRequests, response, err := client.PullRequests.ListPullRequestsWithCommit(ctx, owner, repository, mergeCommitID, nil)
+	if err != nil {
+		return nil, checkGitHubResponse(fmt.Sprintf("list pull requests for commit '%s'", mergeCommitID), gitHubCommitPullRequestPermissions, response, err)
+	}
+
+	index := slices.IndexFunc(pullRequests, func(pullRequest *github.PullRequest) bool {
+		// pullRequest.Merged is not set on this endpoint
+		return pullRequest.MergedAt != nil && pullRequest.GetMergeCommitSHA() == mergeCommitID
+	})
+	if index == -1 {
+		return nil, fmt.Errorf("%w: '%s'", ErrMergedPullRequestNotFound, mergeCommitID)
+	}
+	pullRequest := pullRequests[index]
+
+	result := &GitHubPullRequestApprovals{
+		PullRequestNumber: pullRequest.GetNumber(),
+		BaseBranch:        gitinterface.BranchReferenceName(pullRequest.GetBase().GetRef()),
+		CommitID:          pullRequest.GetHead().GetSHA(),
+		Approvers:         []string{},
+		UnmappedReviewers: []string{},
+	}
+
+	approvals, err := getGitHubPullRequestApprovals(ctx, client, owner, repository, result.PullRequestNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+	policyKeys, err := state.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reviewer := range approvals[result.CommitID] {
+		keyID, has := mapping[strings.ToLower(reviewer)]
+		if !has {
+			slog.Debug(fmt.Sprintf("'%s' is not in the identity mapping, skipping approval...", reviewer))
+			result.UnmappedReviewers = append(result.UnmappedReviewers, reviewer)
+			continue
+		}
+
+		if _, has := policyKeys[keyID]; !has {
+			return nil, fmt.Errorf("%w: '%s' is mapped to key '%s', which is not in the policy", ErrInvalidGitHubIdentityMapping, reviewer, keyID)
+		}
+		result.Approvers = append(result.Approvers, keyID)
+	}
+	slices.Sort(result.Approvers)
+	result.Approvers = slices.Compact(result.Approvers)
+	slices.Sort(result.UnmappedReviewers)
+
+	if len(result.Approvers) == 0 {
+		slog.Debug("No approvals to record")
+		return result, nil
+	}
+
+	slog.Debug("Loading current set of attestations...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	predicate := &attestations.GitHubPullRequestApproval{
+		Owner:             owner,
+		Repository:        repository,
+		PullRequestNumber: result.PullRequestNumber,
+		TargetRef:         result.BaseBranch,
+		CommitID:          result.CommitID,
+		Approvers:         result.Approvers,
+	}
+	if err := r.signGitHubPullRequestApproval(ctx, allAttestations, signer, result.BaseBranch, predicate); err != nil {
+		return nil, err
+	}
+
+	commitMessage := fmt.Sprintf("Apply GitHub pull request approvals for '%s' at '%s'\n\nSource: https://github.com/%s/%s/pull/%d\n", result.BaseBranch, result.CommitID, owner, repository, result.PullRequestNumber)
+
+	slog.Debug("Committing attestations...")
+	if err := allAttestations.Commit(r.r, commitMessage, signCommit); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AddGitHubPullRequestApprover records that the approver approved the GitHub
+// pull request for merging into the base branch when the pull request's head
+// was at the specified commit. The approver is added to the existing approval
+// attestation for the base branch and commit, if any, and the attestation is
+// signed afresh. Currently, this is limited to developer mode.
+func (r *Repository) AddGitHubPullRequestApprover(ctx context.Context, signer sslibdsse.SignerVerifier, owner, repository string, pullRequestNumber int, baseBranch, commitID, approver string, signCommit bool) error {
+	return r.updateGitHubPullRequestApprovers(ctx, signer, owner, repository, pullRequestNumber, baseBranch, commitID, approver, false, signCommit)
+}
+
+// DismissGitHubPullRequestApprover removes the approver from the approval
+// attestation of the GitHub pull request for the base branch and commit. If no
+// approvers remain, the attestation is removed. Currently, this is limited to
+// developer mode.
+func (r *Repository) DismissGitHubPullRequestApprover(ctx context.Context, signer sslibdsse.SignerVerifier, owner, repository string, pullRequestNumber int, baseBranch, commitID, approver string, signCommit bool) error {
+	return r.updateGitHubPullRequestApprovers(ctx, signer, owner, repository, pullRequestNumber, baseBranch, commitID, approver, true, signCommit)
+}
+
+func (r *Repository) updateGitHubPullRequestApprovers(ctx context.Context, signer sslibdsse.SignerVerifier, owner, repository string, pullRequestNumber int, baseBranch, commitID, approver string, dismiss, signCommit bool) error {
+	if !dev.InDevMode() {
+		return dev.ErrNotInDevMode
+	}
+
+	if !strings.HasPrefix(baseBranch, gitinterface.BranchRefPrefix) {
+		return fmt.Errorf("%w: '%s'", ErrNotAbsoluteBranchRef, baseBranch)
+	}
+
+	slog.Debug("Loading current set of attestations...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return err
+	}
+
+	approvers := []string{}
+	env, err := allAttestations.GetGitHubPullRequestApprovalAttestationFor(r.r, baseBranch, commitID)
+	if err == nil {
+		slog.Debug("Found existing GitHub pull request approval attestation...")
+		approvers, err = attestations.GetGitHubPullRequestApprovers(env)
+		if err != nil {
+			return err
+		}
+	} else if !errors.Is(err, attestations.ErrGitHubPullRequestApprovalAttestationNotFound) {
+		return err
+	}
+
+	var commitMessage string
+	if dismiss {
+		if !slices.Contains(approvers, approver) {
+			slog.Debug(fmt.Sprintf("'%s' has not approved pull request, nothing to dismiss", approver))
+			return nil
+		}
+		approvers = slices.DeleteFunc(approvers, func(a string) bool { return a == approver })
+		commitMessage = fmt.Sprintf("Dismiss GitHub pull request approval by '%s' for '%s' at '%s'\n\nSource: https://github.com/%s/%s/pull/%d\n", approver, baseBranch, commitID, owner, repository, pullRequestNumber)
+	} else {
+		if slices.Contains(approvers, approver) {
+			slog.Debug(fmt.Sprintf("'%s' has already approved pull request", approver))
+			return nil
+		}
+		approvers = append(approvers, approver)
+		commitMessage = fmt.Sprintf("Add GitHub pull request approval by '%s' for '%s' at '%s'\n\nSource: https://github.com/%s/%s/pull/%d\n", approver, baseBranch, commitID, owner, repository, pullRequestNumber)
+	}
+
+	if len(approvers) == 0 {
+		slog.Debug("Removing GitHub pull request approval attestation as no approvers remain...")
+		if err := allAttestations.RemoveGitHubPullRequestApprovalAttestation(baseBranch, commitID); err != nil {
+			return err
+		}
+	} else {
+		if err := r.setGitHubPullRequestApprovalAttestation(ctx, signer, allAttestations, owner, repository, pullRequestNumber, baseBranch, commitID, approvers); err != nil {
+			return err
+		}
+	}
+
+	slog.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// setGitHubPullRequestApprovalAttestation signs an approval attestation for the
+// approvers of the pull request at the specified commit and sets it in
+// allAttestations, replacing any existing attestation for the base branch and
+// commit.
+func (r *Repository) setGitHubPullRequestApprovalAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, allAttestations *attestations.Attestations, owner, repository string, pullRequestNumber int, baseBranch, commitID string, approvers []string) error {
+	slog.Debug("Creating GitHub pull request approval attestation...")
+	statement, err := attestations.NewGitHubPullRequestApprovalAttestation(owner, repository, pullRequestNumber, baseBranch, commitID, approvers)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		return err
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing GitHub pull request approval attestation using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	return allAttestations.SetGitHubPullRequestApprovalAttestation(r.r, env, baseBranch, commitID)
+}
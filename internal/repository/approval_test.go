@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/google/go-github/v61/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHubPullRequestApprovers(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := repo.InitializeNamespaces(); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	baseBranch := "refs/heads/main"
+	commitID := "4dcd174e182cb9f5a5a14a6ba3ea9a2d2ac8c6e5"
+
+	getApprovers := func(t *testing.T) []string {
+		t.Helper()
+
+		allAttestations, err := attestations.LoadCurrentAttestations(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		env, err := allAttestations.GetGitHubPullRequestApprovalAttestationFor(r, baseBranch, commitID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		approvers, err := attestations.GetGitHubPullRequestApprovers(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return approvers
+	}
+
+	err = repo.AddGitHubPullRequestApprover(testCtx, signer, "gittuf", "gittuf", 1, "main", commitID, "alice", false)
+	assert.ErrorIs(t, err, ErrNotAbsoluteBranchRef)
+
+	err = repo.AddGitHubPullRequestApprover(testCtx, signer, "gittuf", "gittuf", 1, baseBranch, commitID, "alice", false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"alice"}, getApprovers(t))
+
+	err = repo.AddGitHubPullRequestApprover(testCtx, signer, "gittuf", "gittuf", 1, baseBranch, commitID, "bob", false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"alice", "bob"}, getApprovers(t))
+
+	err = repo.DismissGitHubPullRequestApprover(testCtx, signer, "gittuf", "gittuf", 1, baseBranch, commitID, "alice", false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"bob"}, getApprovers(t))
+
+	err = repo.DismissGitHubPullRequestApprover(testCtx, signer, "gittuf", "gittuf", 1, baseBranch, commitID, "bob", false)
+	assert.Nil(t, err)
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = allAttestations.GetGitHubPullRequestApprovalAttestationFor(r, baseBranch, commitID)
+	assert.ErrorIs(t, err, attestations.ErrGitHubPullRequestApprovalAttestationNotFound)
+
+	t.Run("not in dev mode", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "0")
+
+		err := repo.AddGitHubPullRequestApprover(testCtx, signer, "gittuf", "gittuf", 1, baseBranch, commitID, "alice", false)
+		assert.ErrorIs(t, err, dev.ErrNotInDevMode)
+	})
+}
+
+func TestParseGitHubIdentityMapping(t *testing.T) {
+	mapping, err := ParseGitHubIdentityMapping(strings.NewReader(`{"Alice": "key-1", "bob": "key-2"}`))
+	assert.Nil(t, err)
+	assert.Equal(t, GitHubIdentityMapping{"alice": "key-1", "bob": "key-2"}, mapping)
+
+	_, err = ParseGitHubIdentityMapping(strings.NewReader(`{"alice": "key-1", "ALICE": "key-2"}`))
+	assert.ErrorIs(t, err, ErrInvalidGitHubIdentityMapping)
+
+	_, err = ParseGitHubIdentityMapping(strings.NewReader(`{"alice": ""}`))
+	assert.ErrorIs(t, err, ErrInvalidGitHubIdentityMapping)
+
+	_, err = ParseGitHubIdentityMapping(strings.NewReader(`["alice"]`))
+	assert.ErrorIs(t, err, ErrInvalidGitHubIdentityMapping)
+}
+
+func TestApplyGitHubPullRequestApprovals(t *testing.T) {
+	mergeCommitID := "0d3a3b5bb0d0b2e5f5f1a6ee1f6a4f8b10c2d6a1"
+	headCommitID := "4dcd174e182cb9f5a5a14a6ba3ea9a2d2ac8c6e5"
+	staleCommitID := "9a2b7c1e1f8d4c3b2a1908f7e6d5c4b3a2918070"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/gittuf/gittuf/commits/"+mergeCommitID+"/pulls", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `[{"number": 2, "merged_at": null, "base": {"ref": "main"}, "head": {"sha": "%s"}}, {"number": 1, "merged_at": "2024-01-01T00:00:00Z", "merge_commit_sha": "%s", "base": {"ref": "main"}, "head": {"sha": "%s"}}]`, headCommitID, mergeCommitID, headCommitID)
+	})
+	mux.HandleFunc("/repos/gittuf/gittuf/commits/"+headCommitID+"/pulls", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/repos/gittuf/gittuf/pulls/1/reviews", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `[
+			{"state": "APPROVED", "commit_id": "%s", "user": {"login": "Alice"}},
+			{"state": "APPROVED", "commit_id": "%s", "user": {"login": "bob"}},
+			{"state": "APPROVED", "commit_id": "%s", "user": {"login": "carol"}},
+			{"state": "DISMISSED", "commit_id": "%s", "user": {"login": "dave"}}
+		]`, headCommitID, headCommitID, staleCommitID, headCommitID)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	baseURL, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := github.NewClient(nil)
+	client.BaseURL = baseURL
+
+	currentClient := githubClient
+	githubClient = client
+	defer func() {
+		githubClient = currentClient
+	}()
+
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("pull request not found", func(t *testing.T) {
+		_, err := repo.ApplyGitHubPullRequestApprovals(testCtx, signer, "gittuf", "gittuf", headCommitID, GitHubIdentityMapping{}, false)
+		assert.ErrorIs(t, err, ErrMergedPullRequestNotFound)
+	})
+
+	t.Run("key not in policy", func(t *testing.T) {
+		_, err := repo.ApplyGitHubPullRequestApprovals(testCtx, signer, "gittuf", "gittuf", mergeCommitID, GitHubIdentityMapping{"alice": "unknown-key"}, false)
+		assert.ErrorIs(t, err, ErrInvalidGitHubIdentityMapping)
+	})
+
+	t.Run("no mapped approvers", func(t *testing.T) {
+		approvals, err := repo.ApplyGitHubPullRequestApprovals(testCtx, signer, "gittuf", "gittuf", mergeCommitID, GitHubIdentityMapping{"carol": gpgKey.KeyID}, false)
+		assert.Nil(t, err)
+		assert.Empty(t, approvals.Approvers)
+		assert.Equal(t, []string{"Alice", "bob"}, approvals.UnmappedReviewers)
+
+		allAttestations, err := attestations.LoadCurrentAttestations(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = allAttestations.GetGitHubPullRequestApprovalAttestationFor(repo.r, "refs/heads/main", headCommitID)
+		assert.ErrorIs(t, err, attestations.ErrGitHubPullRequestApprovalAttestationNotFound)
+	})
+
+	t.Run("approvals recorded", func(t *testing.T) {
+		approvals, err := repo.ApplyGitHubPullRequestApprovals(testCtx, signer, "gittuf", "gittuf", mergeCommitID, GitHubIdentityMapping{"alice": gpgKey.KeyID}, false)
+		assert.Nil(t, err)
+		assert.Equal(t, &GitHubPullRequestApprovals{
+			PullRequestNumber: 1,
+			BaseBranch:        "refs/heads/main",
+			CommitID:          headCommitID,
+			Approvers:         []string{gpgKey.KeyID},
+			UnmappedReviewers: []string{"bob"},
+		}, approvals)
+
+		allAttestations, err := attestations.LoadCurrentAttestations(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := allAttestations.GetGitHubPullRequestApprovalAttestationFor(repo.r, "refs/heads/main", headCommitID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		approvers, err := attestations.GetGitHubPullRequestApprovers(env)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{gpgKey.KeyID}, approvers)
+		assert.Len(t, env.Signatures, 1)
+	})
+}
@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+const (
+	// ReferenceAuthorizationRequestType is the short name of the reference
+	// authorization attestation type in attestation requests.
+	ReferenceAuthorizationRequestType = "reference-authorization"
+
+	// GitHubPullRequestApprovalRequestType is the short name of the GitHub
+	// pull request approval attestation type in attestation requests.
+	GitHubPullRequestApprovalRequestType = "github-pull-request-approval"
+)
+
+var (
+	ErrNoAttestationRequests          = errors.New("no attestation requests found")
+	ErrUnsupportedAttestationType     = errors.New("unsupported attestation type")
+	ErrAttestationSubjectMismatch     = errors.New("attestation subject does not match predicate")
+	ErrNoSignerForAttestationRequest  = errors.New("no signer specified for attestation request and no default signing key set")
+	ErrInvalidAttestationRequestInput = errors.New("attestation requests must be a JSON object or an array of JSON objects")
+)
+
+// AttestationRequest describes an attestation to be created and signed. It
+// allows automation to create many attestations of different types in a single
+// invocation.
+type AttestationRequest struct {
+	// Type is the attestation's predicate type, either as its URI or its short
+	// name, such as `reference-authorization`.
+	Type string `json:"type"`
+
+	// Subject is the Git ID the attestation is about: the target tree ID for
+	// reference authorizations, and the commit ID for GitHub pull request
+	// approvals. If set, it must match the predicate. Otherwise, it is
+	// derived from the predicate.
+	Subject string `json:"subject,omitempty"`
+
+	// Predicate contains the attestation's details in the format of the
+	// predicate type.
+	Predicate json.RawMessage `json:"predicate"`
+
+	// Signer identifies the key used to sign the attestation, typically its
+	// path. If empty, the default signer is used.
+	Signer string `json:"signer,omitempty"`
+}
+
+// ParseAttestationRequests reads attestation requests from JSON, which may be a
+// single request or an array of requests.
+func ParseAttestationRequests(reader io.Reader) ([]*AttestationRequest, error) {
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	contents = bytes.TrimSpace(contents)
+
+	requests := []*AttestationRequest{}
+	switch {
+	case bytes.HasPrefix(contents, []byte("[")):
+		if err := json.Unmarshal(contents, &requests); err != nil {
+			return nil, errors.Join(ErrInvalidAttestationRequestInput, err)
+		}
+	case bytes.HasPrefix(contents, []byte("{")):
+		request := &AttestationRequest{}
+		if err := json.Unmarshal(contents, request); err != nil {
+			return nil, errors.Join(ErrInvalidAttestationRequestInput, err)
+		}
+		requests = append(requests, request)
+	default:
+		return nil, ErrInvalidAttestationRequestInput
+	}
+
+	if len(requests) == 0 {
+		return nil, ErrNoAttestationRequests
+	}
+
+	return requests, nil
+}
+
+// ApplyAttestationRequests creates and signs the requested attestations, and
+// commits them to the attestations namespace together. If an attestation with
+// the same details already exists, the signature is added to it. Signers named
+// in requests are loaded using loadSigner. Requests that don't name a signer
+// are signed using defaultSigner, which may be nil if every request names a
+// signer. If any request is invalid, no attestations are committed.
+func (r *Repository) ApplyAttestationRequests(ctx context.Context, requests []*AttestationRequest, defaultSigner sslibdsse.SignerVerifier, loadSigner func(string) (sslibdsse.SignerVerifier, error), signCommit bool) error {
+	if len(requests) == 0 {
+		return ErrNoAttestationRequests
+	}
+
+	slog.Debug("Loading current set of attestations...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return err
+	}
+
+	signers := map[string]sslibdsse.SignerVerifier{}
+	for index, request := range requests {
+		signer := defaultSigner
+		if request.Signer != "" {
+			if _, loaded := signers[request.Signer]; !loaded {
+				signers[request.Signer], err = loadSigner(request.Signer)
+				if err != nil {
+					return fmt.Errorf("unable to load signer for attestation request %d: %w", index, err)
+				}
+			}
+			signer = signers[request.Signer]
+		}
+		if signer == nil {
+			return fmt.Errorf("%w: attestation request %d", ErrNoSignerForAttestationRequest, index)
+		}
+
+		slog.Debug(fmt.Sprintf("Applying attestation request %d of type '%s'...", index, request.Type))
+		if err := r.applyAttestationRequest(ctx, allAttestations, signer, request); err != nil {
+			return fmt.Errorf("unable to apply attestation request %d: %w", index, err)
+		}
+	}
+
+	commitMessage := fmt.Sprintf("Apply %d attestation requests", len(requests))
+	if len(requests) == 1 {
+		commitMessage = "Apply 1 attestation request"
+	}
+
+	slog.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+func (r *Repository) applyAttestationRequest(ctx context.Context, allAttestations *attestations.Attestations, signer sslibdsse.SignerVerifier, request *AttestationRequest) error {
+	switch request.Type {
+	case ReferenceAuthorizationRequestType, attestations.ReferenceAuthorizationPredicateType:
+		predicate := &attestations.ReferenceAuthorization{}
+		if err := json.Unmarshal(request.Predicate, predicate); err != nil {
+			return err
+		}
+
+		if request.Subject != "" && request.Subject != predicate.TargetTreeID {
+			return fmt.Errorf("%w: subject '%s', target tree '%s'", ErrAttestationSubjectMismatch, request.Subject, predicate.TargetTreeID)
+		}
+
+		targetRef, err := r.absoluteTargetRef(predicate.TargetRef)
+		if err != nil {
+			return err
+		}
+		if !plumbing.IsHash(predicate.FromRevisionID) {
+			return fmt.Errorf("%w: '%s'", ErrInvalidObjectID, predicate.FromRevisionID)
+		}
+		if !plumbing.IsHash(predicate.TargetTreeID) {
+			return fmt.Errorf("%w: '%s'", ErrInvalidObjectID, predicate.TargetTreeID)
+		}
+
+		return r.signReferenceAuthorization(ctx, allAttestations, signer, targetRef, predicate.FromRevisionID, predicate.TargetTreeID)
+
+	case GitHubPullRequestApprovalRequestType, attestations.GitHubPullRequestApprovalPredicateType:
+		predicate := &attestations.GitHubPullRequestApproval{}
+		if err := json.Unmarshal(request.Predicate, predicate); err != nil {
+			return err
+		}
+
+		if request.Subject != "" && request.Subject != predicate.CommitID {
+			return fmt.Errorf("%w: subject '%s', commit '%s'", ErrAttestationSubjectMismatch, request.Subject, predicate.CommitID)
+		}
+
+		targetRef, err := r.absoluteTargetRef(predicate.TargetRef)
+		if err != nil {
+			return err
+		}
+		if !plumbing.IsHash(predicate.CommitID) {
+			return fmt.Errorf("%w: '%s'", ErrInvalidObjectID, predicate.CommitID)
+		}
+
+		return r.signGitHubPullRequestApproval(ctx, allAttestations, signer, targetRef, predicate)
+
+	default:
+		return fmt.Errorf("%w: '%s'", ErrUnsupportedAttestationType, request.Type)
+	}
+}
+
+// signGitHubPullRequestApproval signs the GitHub pull request approval
+// attestation for the predicate in the attestations state. If an attestation
+// already exists for the same ref and commit with the same approvers, the
+// signature is added to it. Otherwise, it is replaced.
+func (r *Repository) signGitHubPullRequestApproval(ctx context.Context, allAttestations *attestations.Attestations, signer sslibdsse.SignerVerifier, targetRef string, predicate *attestations.GitHubPullRequestApproval) error {
+	statement, err := attestations.NewGitHubPullRequestApprovalAttestation(predicate.Owner, predicate.Repository, predicate.PullRequestNumber, targetRef, predicate.CommitID, predicate.Approvers)
+	if err != nil {
+		return err
+	}
+
+	env, err := allAttestations.GetGitHubPullRequestApprovalAttestationFor(r.r, targetRef, predicate.CommitID)
+	if err == nil {
+		existingApprovers, err := attestations.GetGitHubPullRequestApprovers(env)
+		if err != nil {
+			return err
+		}
+
+		newApprovers := slices.Clone(predicate.Approvers)
+		slices.Sort(newApprovers)
+		newApprovers = slices.Compact(newApprovers)
+		if !slices.Equal(existingApprovers, newApprovers) {
+			slog.Debug("Replacing existing GitHub pull request approval attestation with different approvers...")
+			env = nil
+		}
+	} else if !errors.Is(err, attestations.ErrGitHubPullRequestApprovalAttestationNotFound) {
+		return err
+	}
+
+	if env == nil {
+		env, err = dsse.CreateEnvelope(statement)
+		if err != nil {
+			return err
+		}
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing GitHub pull request approval attestation using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	return allAttestations.SetGitHubPullRequestApprovalAttestation(r.r, env, targetRef, predicate.CommitID)
+}
@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAttestationRequests(t *testing.T) {
+	tests := map[string]struct {
+		input           string
+		expectedLength  int
+		expectedSigners []string
+		err             error
+	}{
+		"single request": {
+			input:           `{"type": "reference-authorization", "predicate": {}, "signer": "key"}`,
+			expectedLength:  1,
+			expectedSigners: []string{"key"},
+		},
+		"multiple requests": {
+			input:           `[{"type": "reference-authorization", "predicate": {}}, {"type": "github-pull-request-approval", "predicate": {}, "signer": "key"}]`,
+			expectedLength:  2,
+			expectedSigners: []string{"", "key"},
+		},
+		"no requests": {
+			input: `[]`,
+			err:   ErrNoAttestationRequests,
+		},
+		"not JSON object": {
+			input: `"reference-authorization"`,
+			err:   ErrInvalidAttestationRequestInput,
+		},
+		"invalid JSON": {
+			input: `{"type": `,
+			err:   ErrInvalidAttestationRequestInput,
+		},
+	}
+
+	for name, test := range tests {
+		requests, err := ParseAttestationRequests(strings.NewReader(test.input))
+		if test.err != nil {
+			assert.ErrorIs(t, err, test.err, fmt.Sprintf("unexpected error in test '%s'", name))
+			continue
+		}
+
+		assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		assert.Len(t, requests, test.expectedLength, fmt.Sprintf("unexpected requests in test '%s'", name))
+		for i, request := range requests {
+			assert.Equal(t, test.expectedSigners[i], request.Signer, fmt.Sprintf("unexpected signer in test '%s'", name))
+		}
+	}
+}
+
+func TestApplyAttestationRequests(t *testing.T) {
+	tempDir := t.TempDir()
+	r, err := gitinterface.PlainInit(tempDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := repo.InitializeNamespaces(); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, "refs/heads/main", 2, gpgKeyBytes)
+	fromCommitID := commitIDs[0].String()
+	toCommit, err := gitinterface.GetCommit(r, commitIDs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	toTreeID := toCommit.TreeHash.String()
+
+	firstSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	loadSigner := func(name string) (sslibdsse.SignerVerifier, error) {
+		if name == "second" {
+			return secondSigner, nil
+		}
+		return nil, fmt.Errorf("unknown signer '%s'", name)
+	}
+
+	input := fmt.Sprintf(`[
+	{"type": "reference-authorization", "subject": "%[2]s", "predicate": {"targetRef": "main", "fromRevisionID": "%[1]s", "targetTreeID": "%[2]s"}},
+	{"type": "%[4]s", "predicate": {"targetRef": "refs/heads/main", "fromRevisionID": "%[1]s", "targetTreeID": "%[2]s"}, "signer": "second"},
+	{"type": "github-pull-request-approval", "subject": "%[3]s", "predicate": {"owner": "gittuf", "repository": "gittuf", "pullRequestNumber": 1, "targetRef": "main", "commitID": "%[3]s", "approvers": ["bob", "alice"]}}
+]`, fromCommitID, toTreeID, commitIDs[1].String(), attestations.ReferenceAuthorizationPredicateType)
+
+	t.Run("apply requests", func(t *testing.T) {
+		requests, err := ParseAttestationRequests(strings.NewReader(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = repo.ApplyAttestationRequests(testCtx, requests, firstSigner, loadSigner, false)
+		assert.Nil(t, err)
+
+		allAttestations, err := attestations.LoadCurrentAttestations(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		env, err := allAttestations.GetReferenceAuthorizationFor(r, "refs/heads/main", fromCommitID, toTreeID)
+		assert.Nil(t, err)
+		assert.Len(t, env.Signatures, 2)
+
+		env, err = allAttestations.GetGitHubPullRequestApprovalAttestationFor(r, "refs/heads/main", commitIDs[1].String())
+		assert.Nil(t, err)
+		assert.Len(t, env.Signatures, 1)
+		approvers, err := attestations.GetGitHubPullRequestApprovers(env)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"alice", "bob"}, approvers)
+	})
+
+	t.Run("invalid request is not committed", func(t *testing.T) {
+		before, err := attestations.LoadCurrentAttestations(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		requests := []*AttestationRequest{
+			{Type: ReferenceAuthorizationRequestType, Predicate: []byte(fmt.Sprintf(`{"targetRef": "refs/heads/feature", "fromRevisionID": "%s", "targetTreeID": "%s"}`, fromCommitID, toTreeID))},
+			{Type: ReferenceAuthorizationRequestType, Subject: fromCommitID, Predicate: []byte(fmt.Sprintf(`{"targetRef": "main", "fromRevisionID": "%s", "targetTreeID": "%s"}`, fromCommitID, toTreeID))},
+		}
+		err = repo.ApplyAttestationRequests(testCtx, requests, firstSigner, loadSigner, false)
+		assert.ErrorIs(t, err, ErrAttestationSubjectMismatch)
+
+		after, err := attestations.LoadCurrentAttestations(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, before, after)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := map[string]struct {
+			request       *AttestationRequest
+			defaultSigner sslibdsse.SignerVerifier
+			err           error
+		}{
+			"unsupported type": {
+				request:       &AttestationRequest{Type: "unknown", Predicate: []byte(`{}`)},
+				defaultSigner: firstSigner,
+				err:           ErrUnsupportedAttestationType,
+			},
+			"no signer": {
+				request: &AttestationRequest{Type: ReferenceAuthorizationRequestType, Predicate: []byte(`{}`)},
+				err:     ErrNoSignerForAttestationRequest,
+			},
+			"invalid object ID": {
+				request:       &AttestationRequest{Type: ReferenceAuthorizationRequestType, Predicate: []byte(fmt.Sprintf(`{"targetRef": "main", "fromRevisionID": "main", "targetTreeID": "%s"}`, toTreeID))},
+				defaultSigner: firstSigner,
+				err:           ErrInvalidObjectID,
+			},
+		}
+
+		for name, test := range tests {
+			err := repo.ApplyAttestationRequests(testCtx, []*AttestationRequest{test.request}, test.defaultSigner, loadSigner, false)
+			assert.ErrorIs(t, err, test.err, fmt.Sprintf("unexpected error in test '%s'", name))
+		}
+	})
+}
@@ -7,19 +7,41 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"net/url"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/google/go-github/v61/github"
+	ita "github.com/in-toto/attestation/go/v1"
 	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 )
 
-var ErrNotSigningKey = errors.New("expected signing key")
+var (
+	ErrNotSigningKey       = errors.New("expected signing key")
+	ErrPushingAttestations = errors.New("unable to push attestations")
+	ErrPullingAttestations = errors.New("unable to pull attestations")
+	ErrInvalidObjectID     = errors.New("invalid Git object ID")
+	ErrNotAbsoluteRef      = errors.New("ref must exist locally or be specified as an absolute ref")
+
+	ErrInvalidPullRequestRange = errors.New("invalid range of pull request numbers")
+
+	ErrEnvelopeNotSignedByPolicyKey = errors.New("envelope is not signed by any key trusted in policy")
+)
+
+// GitHubAPIURLEnvKey is the environment variable used to specify the URL of
+// the GitHub API, such as for GitHub Enterprise Server. GitHub Actions sets it
+// for workflows. The public GitHub API is used if it isn't set.
+const GitHubAPIURLEnvKey = "GITHUB_API_URL"
 
 var githubClient *github.Client
 
@@ -79,65 +101,37 @@ func (r *Repository) AddReferenceAuthorization(ctx context.Context, signer sslib
 	}
 	toID = mergeTreeID
 
-	slog.Debug("Loading current set of attestations...")
-	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
-	if err != nil {
-		return err
-	}
-
-	// Does a reference authorization already exist for the parameters?
-	hasAuthorization := false
-	env, err := allAttestations.GetReferenceAuthorizationFor(r.r, targetRef, fromID, toID)
-	if err == nil {
-		slog.Debug("Found existing reference authorization...")
-		hasAuthorization = true
-	} else if !errors.Is(err, attestations.ErrAuthorizationNotFound) {
-		return err
-	}
-
-	if !hasAuthorization {
-		// Create a new reference authorization and embed in env
-		slog.Debug("Creating new reference authorization...")
-		statement, err := attestations.NewReferenceAuthorization(targetRef, fromID, toID)
-		if err != nil {
-			return err
-		}
+	return r.addReferenceAuthorization(ctx, signer, targetRef, fromID, toID, signCommit)
+}
 
-		env, err = dsse.CreateEnvelope(statement)
-		if err != nil {
-			return err
-		}
-	}
+// AddReferenceAuthorizationForIDs adds a signature from the signer to the
+// reference authorization attestation for the specified target ref, from ID,
+// and to tree ID. If an authorization already exists for these parameters, the
+// signature is added to the existing attestation's envelope. This allows
+// multiple developers to asynchronously co-sign the same authorization until
+// the threshold for the target ref is met.
+func (r *Repository) AddReferenceAuthorizationForIDs(ctx context.Context, signer sslibdsse.SignerVerifier, targetRef, fromID, toID string, signCommit bool) error {
+	var err error
 
-	keyID, err := signer.KeyID()
+	targetRef, err = r.absoluteTargetRef(targetRef)
 	if err != nil {
 		return err
 	}
 
-	slog.Debug(fmt.Sprintf("Signing reference authorization using '%s'...", keyID))
-	env, err = dsse.SignEnvelope(ctx, env, signer)
-	if err != nil {
-		return err
+	if !plumbing.IsHash(fromID) {
+		return fmt.Errorf("%w: '%s'", ErrInvalidObjectID, fromID)
 	}
-
-	if err := allAttestations.SetReferenceAuthorization(r.r, env, targetRef, fromID, toID); err != nil {
-		return err
+	if !plumbing.IsHash(toID) {
+		return fmt.Errorf("%w: '%s'", ErrInvalidObjectID, toID)
 	}
 
-	commitMessage := fmt.Sprintf("Add reference authorization for '%s' from '%s' to '%s'", targetRef, fromID, toID)
-
-	slog.Debug("Committing attestations...")
-	return allAttestations.Commit(r.r, commitMessage, signCommit)
+	return r.addReferenceAuthorization(ctx, signer, targetRef, fromID, toID, signCommit)
 }
 
 // RemoveReferenceAuthorization removes a previously issued authorization for
 // the specified parameters. The issuer of the authorization is identified using
-// their key. Currently, this is limited to developer mode.
+// their key.
 func (r *Repository) RemoveReferenceAuthorization(ctx context.Context, signer sslibdsse.SignerVerifier, targetRef, fromID, toID string, signCommit bool) error {
-	if !dev.InDevMode() {
-		return dev.ErrNotInDevMode
-	}
-
 	// Ensure only the key that created a reference authorization can remove it
 	slog.Debug("Evaluating if key can sign...")
 	_, err := signer.Sign(ctx, nil)
@@ -149,7 +143,7 @@ func (r *Repository) RemoveReferenceAuthorization(ctx context.Context, signer ss
 		return err
 	}
 
-	targetRef, err = gitinterface.AbsoluteReference(r.r, targetRef)
+	targetRef, err = r.absoluteTargetRef(targetRef)
 	if err != nil {
 		return err
 	}
@@ -200,6 +194,87 @@ func (r *Repository) RemoveReferenceAuthorization(ctx context.Context, signer ss
 	return allAttestations.Commit(r.r, commitMessage, signCommit)
 }
 
+// addReferenceAuthorization signs the reference authorization for the
+// specified parameters, creating it if it doesn't already exist, and commits
+// it to the attestations namespace.
+func (r *Repository) addReferenceAuthorization(ctx context.Context, signer sslibdsse.SignerVerifier, targetRef, fromID, toID string, signCommit bool) error {
+	slog.Debug("Loading current set of attestations...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return err
+	}
+
+	if err := r.signReferenceAuthorization(ctx, allAttestations, signer, targetRef, fromID, toID); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add reference authorization for '%s' from '%s' to '%s'", targetRef, fromID, toID)
+
+	slog.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// signReferenceAuthorization signs the reference authorization for the
+// specified parameters in the attestations state, creating it if it doesn't
+// already exist.
+func (r *Repository) signReferenceAuthorization(ctx context.Context, allAttestations *attestations.Attestations, signer sslibdsse.SignerVerifier, targetRef, fromID, toID string) error {
+	// Does a reference authorization already exist for the parameters?
+	hasAuthorization := false
+	env, err := allAttestations.GetReferenceAuthorizationFor(r.r, targetRef, fromID, toID)
+	if err == nil {
+		slog.Debug("Found existing reference authorization...")
+		hasAuthorization = true
+	} else if !errors.Is(err, attestations.ErrAuthorizationNotFound) {
+		return err
+	}
+
+	if !hasAuthorization {
+		// Create a new reference authorization and embed in env
+		slog.Debug("Creating new reference authorization...")
+		statement, err := attestations.NewReferenceAuthorization(targetRef, fromID, toID)
+		if err != nil {
+			return err
+		}
+
+		env, err = dsse.CreateEnvelope(statement)
+		if err != nil {
+			return err
+		}
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing reference authorization using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	return allAttestations.SetReferenceAuthorization(r.r, env, targetRef, fromID, toID)
+}
+
+// absoluteTargetRef returns the absolute name of the target ref. As the target
+// ref may not exist locally, for example when co-signing an authorization for
+// a branch that hasn't been fetched, names that can't be resolved are expected
+// to already be absolute.
+func (r *Repository) absoluteTargetRef(targetRef string) (string, error) {
+	absTargetRef, err := gitinterface.AbsoluteReference(r.r, targetRef)
+	if err != nil {
+		if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return "", err
+		}
+		if !strings.HasPrefix(targetRef, gitinterface.RefPrefix) {
+			return "", fmt.Errorf("%w: '%s'", ErrNotAbsoluteRef, targetRef)
+		}
+		absTargetRef = targetRef
+	}
+
+	return absTargetRef, nil
+}
+
 // AddGitHubPullRequestAttestationForCommit identifies the pull request for a
 // specified commit ID and triggers AddGitHubPullRequestAttestationForNumber for
 // that pull request. Currently, the authentication token for the GitHub API is
@@ -209,12 +284,15 @@ func (r *Repository) AddGitHubPullRequestAttestationForCommit(ctx context.Contex
 		return dev.ErrNotInDevMode
 	}
 
-	client := getGitHubClient()
+	client, err := getGitHubClient()
+	if err != nil {
+		return err
+	}
 
 	slog.Debug("Identifying GitHub pull requests for commit...")
-	pullRequests, _, err := client.PullRequests.ListPullRequestsWithCommit(ctx, owner, repository, commitID, nil)
+	pullRequests, response, err := client.PullRequests.ListPullRequestsWithCommit(ctx, owner, repository, commitID, nil)
 	if err != nil {
-		return err
+		return checkGitHubResponse(fmt.Sprintf("list pull requests for commit '%s'", commitID), gitHubCommitPullRequestPermissions, response, err)
 	}
 
 	baseBranch, err = gitinterface.AbsoluteReference(r.r, baseBranch)
@@ -244,18 +322,139 @@ func (r *Repository) AddGitHubPullRequestAttestationForNumber(ctx context.Contex
 		return dev.ErrNotInDevMode
 	}
 
-	client := getGitHubClient()
+	client, err := getGitHubClient()
+	if err != nil {
+		return err
+	}
 
 	slog.Debug(fmt.Sprintf("Inspecting GitHub pull request %d...", pullRequestNumber))
-	pullRequest, _, err := client.PullRequests.Get(ctx, owner, repository, pullRequestNumber)
+	pullRequest, response, err := client.PullRequests.Get(ctx, owner, repository, pullRequestNumber)
 	if err != nil {
-		return err
+		return checkGitHubResponse(fmt.Sprintf("get pull request %d", pullRequestNumber), gitHubPullRequestPermissions, response, err)
 	}
 
 	return r.addGitHubPullRequestAttestation(ctx, signer, owner, repository, pullRequest, signCommit)
 }
 
+// BackfillGitHubPullRequestAttestations records GitHub pull request and
+// approval attestations for the merged pull requests numbered firstNumber to
+// lastNumber, inclusive. This allows repositories that adopt gittuf to attest
+// to changes merged before they did so. Pull requests that were not merged and
+// numbers that do not belong to pull requests are skipped. The attestations are
+// recorded in a single commit. Currently, the authentication token for the
+// GitHub API is read from the GITHUB_TOKEN environment variable.
+func (r *Repository) BackfillGitHubPullRequestAttestations(ctx context.Context, signer sslibdsse.SignerVerifier, owner, repository string, firstNumber, lastNumber int, signCommit bool) error {
+	if !dev.InDevMode() {
+		return dev.ErrNotInDevMode
+	}
+
+	if firstNumber < 1 || lastNumber < firstNumber {
+		return fmt.Errorf("%w: %d to %d", ErrInvalidPullRequestRange, firstNumber, lastNumber)
+	}
+
+	client, err := getGitHubClient()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current set of attestations...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return err
+	}
+
+	backfilled := 0
+	for number := firstNumber; number <= lastNumber; number++ {
+		slog.Debug(fmt.Sprintf("Inspecting GitHub pull request %d...", number))
+		pullRequest, response, err := client.PullRequests.Get(ctx, owner, repository, number)
+		if err != nil {
+			err = checkGitHubResponse(fmt.Sprintf("get pull request %d", number), gitHubPullRequestPermissions, response, err)
+
+			// Unless the token is known to lack access, a 404 means the number
+			// doesn't belong to a pull request
+			if response != nil && response.StatusCode == http.StatusNotFound && !isConfirmedGitHubTokenError(err) {
+				slog.Debug(fmt.Sprintf("GitHub pull request %d not found, skipping...", number))
+				continue
+			}
+			return err
+		}
+
+		if pullRequest.MergedAt == nil {
+			slog.Debug(fmt.Sprintf("GitHub pull request %d was not merged, skipping...", number))
+			continue
+		}
+
+		if _, _, err := r.setGitHubPullRequestAttestation(ctx, signer, allAttestations, owner, repository, pullRequest); err != nil {
+			return err
+		}
+
+		approvals, err := getGitHubPullRequestApprovals(ctx, client, owner, repository, number)
+		if err != nil {
+			return err
+		}
+
+		baseBranch := gitinterface.BranchReferenceName(pullRequest.GetBase().GetRef())
+		commitIDs := make([]string, 0, len(approvals))
+		for commitID := range approvals {
+			commitIDs = append(commitIDs, commitID)
+		}
+		slices.Sort(commitIDs)
+		for _, commitID := range commitIDs {
+			approvers := approvals[commitID]
+
+			// Retain approvals that were already recorded, such as by the
+			// gittuf GitHub app
+			env, err := allAttestations.GetGitHubPullRequestApprovalAttestationFor(r.r, baseBranch, commitID)
+			if err == nil {
+				existingApprovers, err := attestations.GetGitHubPullRequestApprovers(env)
+				if err != nil {
+					return err
+				}
+				approvers = append(approvers, existingApprovers...)
+			} else if !errors.Is(err, attestations.ErrGitHubPullRequestApprovalAttestationNotFound) {
+				return err
+			}
+
+			if err := r.setGitHubPullRequestApprovalAttestation(ctx, signer, allAttestations, owner, repository, number, baseBranch, commitID, approvers); err != nil {
+				return err
+			}
+		}
+
+		backfilled++
+	}
+
+	if backfilled == 0 {
+		slog.Debug("No merged GitHub pull requests found, nothing to backfill")
+		return nil
+	}
+
+	commitMessage := fmt.Sprintf("Backfill GitHub pull request attestations for pull requests %d to %d\n\nSource: https://github.com/%s/%s\n", firstNumber, lastNumber, owner, repository)
+
+	slog.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
 func (r *Repository) addGitHubPullRequestAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, owner, repository string, pullRequest *github.PullRequest, signCommit bool) error {
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return err
+	}
+
+	targetRef, targetCommitID, err := r.setGitHubPullRequestAttestation(ctx, signer, allAttestations, owner, repository, pullRequest)
+	if err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add GitHub pull request attestation for '%s' at '%s'\n\nSource: https://github.com/%s/%s/pull/%d\n", targetRef, targetCommitID, owner, repository, *pullRequest.Number)
+
+	slog.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// setGitHubPullRequestAttestation signs an attestation for the pull request and
+// sets it in allAttestations. It returns the ref and commit the attestation is
+// recorded for.
+func (r *Repository) setGitHubPullRequestAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, allAttestations *attestations.Attestations, owner, repository string, pullRequest *github.PullRequest) (string, string, error) {
 	var (
 		targetRef      string
 		targetCommitID string
@@ -274,44 +473,206 @@ func (r *Repository) addGitHubPullRequestAttestation(ctx context.Context, signer
 	slog.Debug("Creating GitHub pull request attestation...")
 	statement, err := attestations.NewGitHubPullRequestAttestation(owner, repository, *pullRequest.Number, targetCommitID, pullRequest)
 	if err != nil {
-		return err
+		return "", "", err
 	}
 
 	env, err := dsse.CreateEnvelope(statement)
 	if err != nil {
-		return err
+		return "", "", err
 	}
 
 	keyID, err := signer.KeyID()
 	if err != nil {
-		return err
+		return "", "", err
 	}
 
 	slog.Debug(fmt.Sprintf("Signing GitHub pull request attestation using '%s'...", keyID))
 	env, err = dsse.SignEnvelope(ctx, env, signer)
 	if err != nil {
-		return err
+		return "", "", err
+	}
+
+	if err := allAttestations.SetGitHubPullRequestAuthorization(r.r, env, targetRef, targetCommitID); err != nil {
+		return "", "", err
 	}
 
+	return targetRef, targetCommitID, nil
+}
+
+// CompactAttestations removes the attestations that are superseded or expired
+// from the attestations namespace, retaining those that apply to the latest
+// keepEntries RSL entries of each ref and to the next update of each ref. The
+// compacted attestations are committed, and an annotation recording the
+// compaction is added to the RSL for the attestations' entry. The history of
+// the namespace is not rewritten, so RSL entries recorded before the
+// compaction are still verified using the attestations that applied when they
+// were recorded. If dryRun is set, the attestations that would be removed are
+// returned without compacting the namespace.
+func (r *Repository) CompactAttestations(keepEntries int, dryRun, signCommit bool) ([]string, error) {
+	slog.Debug("Loading current set of attestations...")
 	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := allAttestations.SetGitHubPullRequestAuthorization(r.r, env, targetRef, targetCommitID); err != nil {
-		return err
+	slog.Debug("Identifying superseded and expired attestations...")
+	removed, err := allAttestations.Compact(r.r, keepEntries)
+	if err != nil {
+		return nil, err
 	}
 
-	commitMessage := fmt.Sprintf("Add GitHub pull request attestation for '%s' at '%s'\n\nSource: https://github.com/%s/%s/pull/%d\n", targetRef, targetCommitID, owner, repository, *pullRequest.Number)
+	if len(removed) == 0 || dryRun {
+		return removed, nil
+	}
+
+	commitMessage := fmt.Sprintf("Compact attestations, removing %d superseded or expired attestations", len(removed))
 
 	slog.Debug("Committing attestations...")
-	return allAttestations.Commit(r.r, commitMessage, signCommit)
+	if err := allAttestations.Commit(r.r, commitMessage, signCommit); err != nil {
+		return nil, err
+	}
+
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, attestations.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Recording compaction in RSL...")
+	annotationMessage := fmt.Sprintf("Compacted attestations, retaining attestations for the latest %d entries of each ref", keepEntries)
+	if err := rsl.NewAnnotationEntry([]plumbing.Hash{entry.ID}, false, annotationMessage).Commit(r.r, signCommit); err != nil {
+		return nil, err
+	}
+
+	return removed, nil
+}
+
+// PushAttestations pushes the local attestations to the specified remote. As
+// this push defaults to fast-forward only, divergent attestation states are
+// detected. Note that this also pushes the RSL as the attestations cannot change
+// without an update to the RSL.
+func (r *Repository) PushAttestations(ctx context.Context, remoteName string) error {
+	refs, err := r.populatedRefs([]string{attestations.Ref, rsl.Ref})
+	if err != nil {
+		return errors.Join(ErrPushingAttestations, err)
+	}
+	if len(refs) == 0 {
+		slog.Debug("Attestations have not been recorded yet, nothing to push")
+		return nil
+	}
+
+	slog.Debug(fmt.Sprintf("Pushing attestations and RSL references to '%s'...", remoteName))
+	if err := gitinterface.Push(ctx, r.r, remoteName, refs); err != nil {
+		return errors.Join(ErrPushingAttestations, err)
+	}
+
+	return nil
+}
+
+// PullAttestations fetches the attestations from the specified remote. The
+// fetch is marked as fast forward only to detect divergence. Note that this
+// also fetches the RSL as the attestations must be updated in sync with the
+// RSL.
+func (r *Repository) PullAttestations(ctx context.Context, remoteName string) error {
+	slog.Debug(fmt.Sprintf("Pulling attestations and RSL references from '%s'...", remoteName))
+	err := gitinterface.Fetch(ctx, r.r, remoteName, []string{attestations.Ref, rsl.Ref}, true)
+	if errors.Is(err, gitinterface.ErrRemoteRefNotFound) {
+		// The remote may not have any attestations yet
+		slog.Debug(fmt.Sprintf("Pulling RSL reference only from '%s'...", remoteName))
+		err = gitinterface.Fetch(ctx, r.r, remoteName, []string{rsl.Ref}, true)
+	}
+	if err != nil {
+		return errors.Join(ErrPullingAttestations, err)
+	}
+
+	return nil
 }
 
-func getGitHubClient() *github.Client {
+// getGitHubPullRequestApprovals returns the users who approved the pull request,
+// grouped by the commit they approved.
+func getGitHubPullRequestApprovals(ctx context.Context, client *github.Client, owner, repository string, pullRequestNumber int) (map[string][]string, error) {
+	approvals := map[string][]string{}
+
+	options := &github.ListOptions{PerPage: 100}
+	for {
+		reviews, response, err := client.PullRequests.ListReviews(ctx, owner, repository, pullRequestNumber, options)
+		if err != nil {
+			return nil, checkGitHubResponse(fmt.Sprintf("list reviews for pull request %d", pullRequestNumber), gitHubPullRequestPermissions, response, err)
+		}
+
+		for _, review := range reviews {
+			// Dismissed approvals have the state DISMISSED
+			if !strings.EqualFold(review.GetState(), "APPROVED") {
+				continue
+			}
+			approvals[review.GetCommitID()] = append(approvals[review.GetCommitID()], review.GetUser().GetLogin())
+		}
+
+		if response.NextPage == 0 {
+			break
+		}
+		options.Page = response.NextPage
+	}
+
+	return approvals, nil
+}
+
+func getGitHubClient() (*github.Client, error) {
 	if githubClient == nil {
-		githubClient = github.NewClient(nil).WithAuthToken(os.Getenv("GITHUB_TOKEN"))
+		client := github.NewClient(nil).WithAuthToken(os.Getenv("GITHUB_TOKEN"))
+
+		if apiURL := os.Getenv(GitHubAPIURLEnvKey); apiURL != "" {
+			baseURL, err := url.Parse(strings.TrimSuffix(apiURL, "/") + "/")
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", GitHubAPIURLEnvKey, err)
+			}
+			client.BaseURL = baseURL
+		}
+
+		githubClient = client
+	}
+
+	return githubClient, nil
+}
+
+// EnvelopeVerification describes a DSSE envelope verified using
+// VerifyAttestationEnvelope.
+type EnvelopeVerification struct {
+	// Signers are the keys trusted in the policy that signed the envelope.
+	Signers []*tuf.Key
+
+	// Statement is the in-toto statement in the envelope's payload. It's nil
+	// if the payload isn't a statement.
+	Statement *ita.Statement
+}
+
+// VerifyAttestationEnvelope verifies the signatures on a DSSE envelope, such as
+// an attestation exchanged out of band before it's recorded in the
+// repository, against the keys trusted in the current policy. The envelope
+// must be signed by at least one trusted key. Whether the signers are
+// authorized for what the envelope attests to is only checked when the
+// attestation is used during verification.
+func (r *Repository) VerifyAttestationEnvelope(ctx context.Context, env *sslibdsse.Envelope) (*EnvelopeVerification, error) {
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Verifying envelope signatures...")
+	signers, err := state.GetEnvelopeSigners(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	if len(signers) == 0 {
+		return nil, ErrEnvelopeNotSignedByPolicyKey
+	}
+
+	verification := &EnvelopeVerification{Signers: signers}
+
+	statement, err := dsse.DecodePayload[ita.Statement](nil, env, nil)
+	if err == nil && statement.GetPredicateType() != "" {
+		verification.Statement = statement
 	}
 
-	return githubClient
+	return verification, nil
 }
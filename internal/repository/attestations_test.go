@@ -4,15 +4,24 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path"
 	"testing"
 
 	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/common"
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier"
-	"github.com/go-git/go-git/v5"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/google/go-github/v61/github"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -30,7 +39,7 @@ func TestAddAndRemoveReferenceAuthorization(t *testing.T) {
 	}
 	defer os.Chdir(currentDir) //nolint:errcheck
 
-	r, err := git.PlainInit(testDir, false)
+	r, err := gitinterface.PlainInit(testDir, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -45,7 +54,7 @@ func TestAddAndRemoveReferenceAuthorization(t *testing.T) {
 	// Add a single commit
 	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, absTargetRef, 1, gpgKeyBytes)
 	fromCommitID := commitIDs[0].String()
-	if err := repo.RecordRSLEntryForReference(targetRef, false); err != nil {
+	if err := repo.RecordRSLEntryForReference(testCtx, targetRef, false); err != nil {
 		t.Fatal(err)
 	}
 
@@ -55,7 +64,7 @@ func TestAddAndRemoveReferenceAuthorization(t *testing.T) {
 	// Add two commits
 	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, r, absFeatureRef, 2, gpgKeyBytes)
 	featureCommitID := commitIDs[1].String()
-	if err := repo.RecordRSLEntryForReference(featureRef, false); err != nil {
+	if err := repo.RecordRSLEntryForReference(testCtx, featureRef, false); err != nil {
 		t.Fatal(err)
 	}
 
@@ -131,3 +140,385 @@ func TestAddAndRemoveReferenceAuthorization(t *testing.T) {
 	assert.Len(t, env.Signatures, 1)
 	assert.Equal(t, firstKeyID, env.Signatures[0].KeyID)
 }
+
+func TestAddReferenceAuthorizationForIDs(t *testing.T) {
+	tempDir := t.TempDir()
+	r, err := gitinterface.PlainInit(tempDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := repo.InitializeNamespaces(); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, "refs/heads/main", 2, gpgKeyBytes)
+	fromCommitID := commitIDs[0].String()
+	toCommit, err := gitinterface.GetCommit(r, commitIDs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	toTreeID := toCommit.TreeHash.String()
+
+	firstSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	firstKeyID, err := firstSigner.KeyID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondKeyID, err := secondSigner.KeyID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("invalid IDs", func(t *testing.T) {
+		err := repo.AddReferenceAuthorizationForIDs(testCtx, firstSigner, "main", "main", toTreeID, false)
+		assert.ErrorIs(t, err, ErrInvalidObjectID)
+
+		err = repo.AddReferenceAuthorizationForIDs(testCtx, firstSigner, "main", fromCommitID, "abc", false)
+		assert.ErrorIs(t, err, ErrInvalidObjectID)
+	})
+
+	t.Run("unknown ref that isn't absolute", func(t *testing.T) {
+		err := repo.AddReferenceAuthorizationForIDs(testCtx, firstSigner, "feature", fromCommitID, toTreeID, false)
+		assert.ErrorIs(t, err, ErrNotAbsoluteRef)
+	})
+
+	t.Run("co-sign authorization", func(t *testing.T) {
+		err := repo.AddReferenceAuthorizationForIDs(testCtx, firstSigner, "main", fromCommitID, toTreeID, false)
+		assert.Nil(t, err)
+
+		// Signing again with the same key doesn't add another signature
+		err = repo.AddReferenceAuthorizationForIDs(testCtx, firstSigner, "refs/heads/main", fromCommitID, toTreeID, false)
+		assert.Nil(t, err)
+
+		err = repo.AddReferenceAuthorizationForIDs(testCtx, secondSigner, "main", fromCommitID, toTreeID, false)
+		assert.Nil(t, err)
+
+		allAttestations, err := attestations.LoadCurrentAttestations(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		env, err := allAttestations.GetReferenceAuthorizationFor(r, "refs/heads/main", fromCommitID, toTreeID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, env.Signatures, 2)
+		assert.Equal(t, firstKeyID, env.Signatures[0].KeyID)
+		assert.Equal(t, secondKeyID, env.Signatures[1].KeyID)
+
+		err = repo.RemoveReferenceAuthorization(testCtx, firstSigner, "main", fromCommitID, toTreeID, false)
+		assert.Nil(t, err)
+
+		allAttestations, err = attestations.LoadCurrentAttestations(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		env, err = allAttestations.GetReferenceAuthorizationFor(r, "refs/heads/main", fromCommitID, toTreeID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, env.Signatures, 1)
+		assert.Equal(t, secondKeyID, env.Signatures[0].KeyID)
+	})
+
+	t.Run("authorization for ref that doesn't exist locally", func(t *testing.T) {
+		err := repo.AddReferenceAuthorizationForIDs(testCtx, firstSigner, "refs/heads/feature", plumbing.ZeroHash.String(), toTreeID, false)
+		assert.Nil(t, err)
+
+		allAttestations, err := attestations.LoadCurrentAttestations(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = allAttestations.GetReferenceAuthorizationFor(r, "refs/heads/feature", plumbing.ZeroHash.String(), toTreeID)
+		assert.Nil(t, err)
+	})
+}
+
+func TestCompactAttestations(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+	refName := "refs/heads/main"
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each update to main is authorized before it is recorded
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 3, gpgKeyBytes)
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+	for i := 1; i < len(commitIDs); i++ {
+		commit, err := gitinterface.GetCommit(repo.r, commitIDs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.AddReferenceAuthorizationForIDs(testCtx, signer, refName, commitIDs[i-1].String(), commit.TreeHash.String(), false); err != nil {
+			t.Fatal(err)
+		}
+		common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, commitIDs[i]), gpgKeyBytes)
+	}
+
+	firstCommit, err := gitinterface.GetCommit(repo.r, commitIDs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	supersededPath := path.Join("reference-authorizations", attestations.ReferenceAuthorizationPath(refName, commitIDs[0].String(), firstCommit.TreeHash.String()))
+
+	t.Run("dry run", func(t *testing.T) {
+		latestEntry, err := rsl.GetLatestEntry(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		removed, err := repo.CompactAttestations(1, true, false)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{supersededPath}, removed)
+
+		currentEntry, err := rsl.GetLatestEntry(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, latestEntry.GetID(), currentEntry.GetID())
+	})
+
+	t.Run("compact", func(t *testing.T) {
+		removed, err := repo.CompactAttestations(1, false, false)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{supersededPath}, removed)
+
+		allAttestations, err := attestations.LoadCurrentAttestations(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = allAttestations.GetReferenceAuthorizationFor(repo.r, refName, commitIDs[0].String(), firstCommit.TreeHash.String())
+		assert.ErrorIs(t, err, attestations.ErrAuthorizationNotFound)
+
+		// The compaction is recorded for the attestations' entry
+		latestEntry, err := rsl.GetLatestEntry(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		attestationsEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo.r, attestations.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		annotation, isAnnotation := latestEntry.(*rsl.AnnotationEntry)
+		assert.True(t, isAnnotation)
+		assert.True(t, annotation.RefersTo(attestationsEntry.ID))
+
+		// Nothing more to compact
+		removed, err = repo.CompactAttestations(1, false, false)
+		assert.Nil(t, err)
+		assert.Empty(t, removed)
+	})
+
+	t.Run("verification across compaction", func(t *testing.T) {
+		assert.Nil(t, repo.VerifyRef(testCtx, refName, false))
+		assert.Nil(t, repo.VerifyRef(testCtx, refName, true))
+
+		// Updates recorded after the compaction are verified too
+		newCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, newCommitIDs[0]), gpgKeyBytes)
+		assert.Nil(t, repo.VerifyRef(testCtx, refName, false))
+	})
+
+	t.Run("invalid retention", func(t *testing.T) {
+		_, err := repo.CompactAttestations(0, false, false)
+		assert.ErrorIs(t, err, attestations.ErrInvalidRetention)
+	})
+}
+
+func TestBackfillGitHubPullRequestAttestations(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	mergeCommitID := "1111111111111111111111111111111111111111"
+	headCommitID := "2222222222222222222222222222222222222222"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/gittuf/gittuf/pulls/1", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"number": 1, "merged_at": "2024-01-01T00:00:00Z", "merge_commit_sha": "%s", "base": {"ref": "main", "user": {"login": "gittuf", "id": 1}}, "head": {"ref": "feature", "sha": "%s", "user": {"login": "alice", "id": 2}}}`, mergeCommitID, headCommitID)
+	})
+	mux.HandleFunc("/repos/gittuf/gittuf/pulls/1/reviews", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `[{"state": "APPROVED", "commit_id": "%s", "user": {"login": "bob"}}, {"state": "DISMISSED", "commit_id": "%s", "user": {"login": "carol"}}, {"state": "COMMENTED", "commit_id": "%s", "user": {"login": "dave"}}]`, headCommitID, headCommitID, headCommitID)
+	})
+	mux.HandleFunc("/repos/gittuf/gittuf/pulls/2", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"number": 2, "base": {"ref": "main", "user": {"login": "gittuf", "id": 1}}, "head": {"ref": "other", "sha": "%s", "user": {"login": "alice", "id": 2}}}`, headCommitID)
+	})
+	// Pull request 3 doesn't exist
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	baseURL, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := github.NewClient(nil)
+	client.BaseURL = baseURL
+
+	currentClient := githubClient
+	githubClient = client
+	defer func() {
+		githubClient = currentClient
+	}()
+
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.BackfillGitHubPullRequestAttestations(testCtx, signer, "gittuf", "gittuf", 2, 1, false)
+	assert.ErrorIs(t, err, ErrInvalidPullRequestRange)
+
+	attestationsRef, err := repo.r.Reference(plumbing.ReferenceName(attestations.Ref), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing to backfill
+	err = repo.BackfillGitHubPullRequestAttestations(testCtx, signer, "gittuf", "gittuf", 2, 3, false)
+	assert.Nil(t, err)
+	currentAttestationsRef, err := repo.r.Reference(plumbing.ReferenceName(attestations.Ref), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, attestationsRef.Hash(), currentAttestationsRef.Hash())
+
+	err = repo.BackfillGitHubPullRequestAttestations(testCtx, signer, "gittuf", "gittuf", 1, 3, false)
+	assert.Nil(t, err)
+
+	allAttestations, err := attestations.LoadCurrentAttestations(repo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	envs, err := allAttestations.GetAllAttestations(repo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One pull request attestation and one approval attestation
+	assert.Len(t, envs, 2)
+
+	env, err := allAttestations.GetGitHubPullRequestApprovalAttestationFor(repo.r, "refs/heads/main", headCommitID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	approvers, err := attestations.GetGitHubPullRequestApprovers(env)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"bob"}, approvers)
+}
+
+func TestGetGitHubClient(t *testing.T) {
+	currentClient := githubClient
+	defer func() {
+		githubClient = currentClient
+	}()
+
+	t.Run("public API", func(t *testing.T) {
+		t.Setenv(GitHubAPIURLEnvKey, "")
+		githubClient = nil
+
+		client, err := getGitHubClient()
+		assert.Nil(t, err)
+		assert.Equal(t, "https://api.github.com/", client.BaseURL.String())
+	})
+
+	t.Run("API URL set", func(t *testing.T) {
+		t.Setenv(GitHubAPIURLEnvKey, "https://github.example.com/api/v3")
+		githubClient = nil
+
+		client, err := getGitHubClient()
+		assert.Nil(t, err)
+		assert.Equal(t, "https://github.example.com/api/v3/", client.BaseURL.String())
+	})
+
+	t.Run("invalid API URL", func(t *testing.T) {
+		t.Setenv(GitHubAPIURLEnvKey, "://github.example.com")
+		githubClient = nil
+
+		_, err := getGitHubClient()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestVerifyAttestationEnvelope(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	statement, err := attestations.NewReferenceAuthorization("refs/heads/main", plumbing.ZeroHash.String(), plumbing.ZeroHash.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("signed by trusted key", func(t *testing.T) {
+		env, err := dsse.CreateEnvelope(statement)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(testCtx, env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyID, err := signer.KeyID()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verification, err := repo.VerifyAttestationEnvelope(testCtx, env)
+		assert.Nil(t, err)
+		assert.Len(t, verification.Signers, 1)
+		assert.Equal(t, keyID, verification.Signers[0].KeyID)
+		assert.Equal(t, attestations.ReferenceAuthorizationPredicateType, verification.Statement.GetPredicateType())
+	})
+
+	t.Run("signed by untrusted key", func(t *testing.T) {
+		env, err := dsse.CreateEnvelope(statement)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(artifacts.SSLibKey3Private) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(testCtx, env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = repo.VerifyAttestationEnvelope(testCtx, env)
+		assert.ErrorIs(t, err, ErrEnvelopeNotSignedByPolicyKey)
+	})
+
+	t.Run("payload is not a statement", func(t *testing.T) {
+		env, err := dsse.CreateEnvelope(map[string]string{"hello": "world"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(testCtx, env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verification, err := repo.VerifyAttestationEnvelope(testCtx, env)
+		assert.Nil(t, err)
+		assert.Len(t, verification.Signers, 1)
+		assert.Nil(t, verification.Statement)
+	})
+}
@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+const (
+	KeyUsageRSLEntry        = "rsl-entry"
+	KeyUsagePolicySignature = "policy-signature"
+	KeyUsageAttestation     = "attestation"
+)
+
+var ErrKeyNotFoundInPolicy = errors.New("key not found in any policy in the repository's history")
+
+// KeyUsage records a single use of a key in the repository's history.
+type KeyUsage struct {
+	// Type is one of KeyUsageRSLEntry, KeyUsagePolicySignature, or
+	// KeyUsageAttestation.
+	Type string
+
+	// RSLEntryID is the ID of the RSL entry the usage was found in. For policy
+	// signatures and attestations, this is the RSL entry that first recorded
+	// the signed metadata.
+	RSLEntryID plumbing.Hash
+
+	// Name identifies what the key signed: the ref for RSL entries, the role
+	// name for policy signatures, and the attestation's path for
+	// attestations.
+	Name string
+}
+
+// AuditKey lists every RSL entry, policy metadata signature, and attestation
+// signature made by the specified key across the repository's history. The key
+// must be present in at least one policy state, as its public key is necessary
+// to verify signatures on RSL entries. The usages are returned in the order
+// they were recorded in the RSL.
+func (r *Repository) AuditKey(ctx context.Context, keyID string) ([]*KeyUsage, error) {
+	slog.Debug("Loading RSL entries...")
+	entries := []rsl.Entry{}
+
+	iteratorEntry, err := rsl.GetLatestEntry(r.r)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		entries = append(entries, iteratorEntry)
+
+		iteratorEntry, err = rsl.GetParentForEntry(r.r, iteratorEntry)
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				break
+			}
+			return nil, err
+		}
+	}
+	slices.Reverse(entries)
+
+	// Policy signatures and attestations are audited first, as they also let
+	// us find the public key to verify RSL entry signatures
+	slog.Debug(fmt.Sprintf("Auditing policy and attestation signatures for '%s'...", keyID))
+	var (
+		key                 *tuf.Key
+		metadataUsages      = map[plumbing.Hash][]*KeyUsage{}
+		seenPolicySigs      = map[string]bool{}
+		seenAttestationSigs = map[string]bool{}
+	)
+
+	for _, entry := range entries {
+		entry, isReferenceEntry := entry.(*rsl.ReferenceEntry)
+		if !isReferenceEntry {
+			continue
+		}
+
+		switch entry.RefName {
+		case policy.PolicyRef:
+			state, err := policy.LoadState(ctx, r.r, entry)
+			if err != nil {
+				return nil, err
+			}
+
+			if key == nil {
+				allKeys, err := state.PublicKeys()
+				if err != nil {
+					return nil, err
+				}
+				key = allKeys[keyID]
+			}
+
+			envelopes := map[string]*sslibdsse.Envelope{
+				policy.RootRoleName:    state.RootEnvelope,
+				policy.TargetsRoleName: state.TargetsEnvelope,
+			}
+			for roleName, env := range state.DelegationEnvelopes {
+				envelopes[roleName] = env
+			}
+
+			for _, roleName := range sortedKeys(envelopes) {
+				if signatureByKey(envelopes[roleName], keyID, roleName, seenPolicySigs) {
+					metadataUsages[entry.ID] = append(metadataUsages[entry.ID], &KeyUsage{Type: KeyUsagePolicySignature, RSLEntryID: entry.ID, Name: roleName})
+				}
+			}
+
+		case attestations.Ref:
+			attestationsState, err := attestations.LoadAttestationsForEntry(r.r, entry)
+			if err != nil {
+				return nil, err
+			}
+
+			envelopes, err := attestationsState.GetAllAttestations(r.r)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, attestationPath := range sortedKeys(envelopes) {
+				if signatureByKey(envelopes[attestationPath], keyID, attestationPath, seenAttestationSigs) {
+					metadataUsages[entry.ID] = append(metadataUsages[entry.ID], &KeyUsage{Type: KeyUsageAttestation, RSLEntryID: entry.ID, Name: attestationPath})
+				}
+			}
+		}
+	}
+
+	if key == nil {
+		return nil, ErrKeyNotFoundInPolicy
+	}
+
+	slog.Debug(fmt.Sprintf("Auditing RSL entry signatures for '%s'...", keyID))
+	usages := []*KeyUsage{}
+	for _, entry := range entries {
+		commit, err := gitinterface.GetCommit(r.r, entry.GetID())
+		if err != nil {
+			return nil, err
+		}
+
+		err = gitinterface.VerifyCommitSignature(ctx, commit, key)
+		switch {
+		case err == nil:
+			name := rsl.AnnotationEntryHeader
+			switch entry := entry.(type) {
+			case *rsl.ReferenceEntry:
+				name = entry.RefName
+			case *rsl.PropagationEntry:
+				name = rsl.PropagationEntryHeader
+			}
+			usages = append(usages, &KeyUsage{Type: KeyUsageRSLEntry, RSLEntryID: entry.GetID(), Name: name})
+		case errors.Is(err, gitinterface.ErrUnknownSigningMethod), errors.Is(err, gitinterface.ErrIncorrectVerificationKey):
+			// Key cannot be used for Git signatures or did not sign the entry
+		default:
+			// Unsigned entries also fail verification, we skip them
+			slog.Debug(fmt.Sprintf("Unable to verify RSL entry '%s': %s", entry.GetID().String(), err.Error()))
+		}
+
+		usages = append(usages, metadataUsages[entry.GetID()]...)
+	}
+
+	return usages, nil
+}
+
+// signatureByKey checks if the envelope has a signature from the specified key
+// that has not been seen before for the same name. Metadata that is unchanged
+// across states is therefore reported only once.
+func signatureByKey(env *sslibdsse.Envelope, keyID, name string, seen map[string]bool) bool {
+	if env == nil {
+		return false
+	}
+
+	for _, signature := range env.Signatures {
+		if signature.KeyID != keyID {
+			continue
+		}
+
+		seenKey := name + "\x00" + signature.Sig
+		if seen[seenKey] {
+			return false
+		}
+		seen[seenKey] = true
+		return true
+	}
+
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
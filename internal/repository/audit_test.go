@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"path"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditKey(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	refName := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+	entryID := common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsKeyID, err := signer.KeyID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allAttestations, err := attestations.LoadCurrentAttestations(repo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorization, err := attestations.NewReferenceAuthorization(refName, plumbing.ZeroHash.String(), plumbing.ZeroHash.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(authorization)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err = dsse.SignEnvelope(testCtx, env, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := allAttestations.SetReferenceAuthorization(repo.r, env, refName, plumbing.ZeroHash.String(), plumbing.ZeroHash.String()); err != nil {
+		t.Fatal(err)
+	}
+	if err := allAttestations.Commit(repo.r, "Add authorization", false); err != nil {
+		t.Fatal(err)
+	}
+	attestationsEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo.r, attestations.Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("SSLib key used for policy and attestation", func(t *testing.T) {
+		usages, err := repo.AuditKey(testCtx, targetsKeyID)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(usages))
+
+		assert.Equal(t, KeyUsagePolicySignature, usages[0].Type)
+		assert.Equal(t, policy.TargetsRoleName, usages[0].Name)
+
+		assert.Equal(t, KeyUsageAttestation, usages[1].Type)
+		assert.Equal(t, attestationsEntry.ID, usages[1].RSLEntryID)
+		assert.Equal(t, path.Join("reference-authorizations", attestations.ReferenceAuthorizationPath(refName, plumbing.ZeroHash.String(), plumbing.ZeroHash.String())), usages[1].Name)
+	})
+
+	t.Run("GPG key used for RSL entry", func(t *testing.T) {
+		gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		usages, err := repo.AuditKey(testCtx, gpgKey.KeyID)
+		assert.Nil(t, err)
+		assert.Equal(t, []*KeyUsage{{Type: KeyUsageRSLEntry, RSLEntryID: entryID, Name: refName}}, usages)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := repo.AuditKey(testCtx, "unknown")
+		assert.ErrorIs(t, err, ErrKeyNotFoundInPolicy)
+	})
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// DelegateToAutomation records a delegation, signed using the signer, that
+// grants the automation key permission to record RSL entries for the refs
+// matching the specified patterns from now until validFor has passed. During
+// verification, the automation key's signatures on RSL entries count as the
+// signer's for the rules that trust the signer.
+func (r *Repository) DelegateToAutomation(ctx context.Context, signer sslibdsse.SignerVerifier, automationKey *tuf.Key, refs []string, validFor time.Duration, signCommit bool) error {
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	notBefore := time.Now().UTC().Truncate(time.Second)
+	delegation := &attestations.AutomationDelegation{
+		Key:       automationKey,
+		Refs:      refs,
+		NotBefore: notBefore,
+		NotAfter:  notBefore.Add(validFor),
+	}
+
+	statement, err := attestations.NewAutomationDelegationAttestation(delegation)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing automation delegation using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current set of attestations...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return err
+	}
+
+	if err := allAttestations.SetAutomationDelegation(r.r, env); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Delegate to automation key '%s' until %s", automationKey.KeyID, delegation.NotAfter.Format(time.RFC3339))
+
+	slog.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// RevokeAutomationDelegations removes every delegation to the automation key,
+// so that entries it records from now on are not trusted.
+func (r *Repository) RevokeAutomationDelegations(_ context.Context, automationKeyID string, signCommit bool) error {
+	slog.Debug("Loading current set of attestations...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return err
+	}
+
+	if err := allAttestations.RemoveAutomationDelegationsFor(r.r, automationKeyID); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Revoke delegations to automation key '%s'", automationKeyID)
+
+	slog.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutomationDelegations(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	automationKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("invalid delegation", func(t *testing.T) {
+		err := repo.DelegateToAutomation(testCtx, targetsSigner, automationKey, []string{"main"}, time.Hour, false)
+		assert.ErrorIs(t, err, attestations.ErrInvalidAutomationDelegation)
+
+		err = repo.DelegateToAutomation(testCtx, targetsSigner, automationKey, []string{"refs/heads/main"}, 2*attestations.MaxAutomationDelegationValidity, false)
+		assert.ErrorIs(t, err, attestations.ErrInvalidAutomationDelegation)
+	})
+
+	t.Run("delegate and revoke", func(t *testing.T) {
+		err := repo.DelegateToAutomation(testCtx, targetsSigner, automationKey, []string{"refs/heads/main"}, time.Hour, false)
+		assert.Nil(t, err)
+
+		currentAttestations, err := attestations.LoadCurrentAttestations(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		envelopes, err := currentAttestations.GetAutomationDelegations(repo.r)
+		assert.Nil(t, err)
+		assert.Len(t, envelopes, 1)
+
+		delegation, err := attestations.GetAutomationDelegation(envelopes[0])
+		assert.Nil(t, err)
+		assert.Equal(t, automationKey.KeyID, delegation.Key.KeyID)
+		assert.Equal(t, []string{"refs/heads/main"}, delegation.Refs)
+		assert.Equal(t, time.Hour, delegation.NotAfter.Sub(delegation.NotBefore))
+
+		err = repo.RevokeAutomationDelegations(testCtx, automationKey.KeyID, false)
+		assert.Nil(t, err)
+
+		currentAttestations, err = attestations.LoadCurrentAttestations(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		envelopes, err = currentAttestations.GetAutomationDelegations(repo.r)
+		assert.Nil(t, err)
+		assert.Empty(t, envelopes)
+
+		err = repo.RevokeAutomationDelegations(testCtx, automationKey.KeyID, false)
+		assert.ErrorIs(t, err, attestations.ErrAutomationDelegationNotFound)
+	})
+}
@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var (
+	ErrInvalidOIDCToken         = errors.New("invalid OIDC token")
+	ErrEncryptionKeyNotInPolicy = errors.New("key to encrypt for not found in policy")
+)
+
+// AddBuildEnvironmentAttestation records the build environment, such as a CI
+// runner, that created the latest RSL entry for the specified ref. The
+// attestation must be signed using a key trusted for the ref in the current
+// policy. This allows auditors to tell entries created by automation apart
+// from those pushed by humans. If the IDs of keys in the current policy are
+// specified in encryptFor, the build environment is encrypted so that only
+// the holders of those keys can read it.
+func (r *Repository) AddBuildEnvironmentAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, target string, environment *attestations.BuildEnvironment, encryptFor []string, signCommit bool) error {
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	refName, err := gitinterface.AbsoluteReference(r.r, target)
+	if err != nil {
+		return err
+	}
+
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, refName)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Creating build environment attestation...")
+	buildEnvironment := *environment
+	buildEnvironment.Ref = refName
+	buildEnvironment.RSLEntryID = entry.ID.String()
+	statement, err := attestations.NewBuildEnvironmentAttestation(&buildEnvironment, entry.TargetID.String())
+	if err != nil {
+		return err
+	}
+
+	decryptedEnv, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		return err
+	}
+	env := decryptedEnv
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		return err
+	}
+
+	if len(encryptFor) > 0 {
+		slog.Debug("Encrypting build environment attestation...")
+		recipients, err := getEncryptionKeys(state, encryptFor)
+		if err != nil {
+			return err
+		}
+
+		env, err = dsse.EncryptEnvelope(decryptedEnv, recipients)
+		if err != nil {
+			return err
+		}
+	}
+
+	slog.Debug(fmt.Sprintf("Signing build environment attestation using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Checking build environment attestation is signed by a key trusted for the ref...")
+	if err := state.VerifyBuildEnvironmentAttestation(ctx, refName, env); err != nil {
+		return err
+	}
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return err
+	}
+
+	if dsse.IsEncrypted(env) {
+		err = allAttestations.SetEncryptedBuildEnvironmentAttestation(r.r, env, decryptedEnv, refName, entry.ID.String())
+	} else {
+		err = allAttestations.SetBuildEnvironmentAttestation(r.r, env, refName, entry.ID.String())
+	}
+	if err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add build environment attestation for '%s' at '%s'", refName, entry.ID.String())
+	if len(encryptFor) == 0 {
+		// The hostname must not be disclosed for encrypted attestations
+		commitMessage += fmt.Sprintf("\n\nHostname: %s\n", buildEnvironment.Hostname)
+	}
+
+	slog.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// GetBuildEnvironment returns the build environment recorded for the latest RSL
+// entry of the specified ref. The attestation must be signed by a key trusted
+// for the ref in the current policy. An encrypted build environment is
+// decrypted using the identity, which may be nil otherwise.
+func (r *Repository) GetBuildEnvironment(ctx context.Context, target string, identity age.Identity) (*attestations.BuildEnvironment, error) {
+	refName, err := gitinterface.AbsoluteReference(r.r, target)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, refName)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Loading build environment attestation...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := allAttestations.GetBuildEnvironmentAttestationFor(r.r, refName, entry.ID.String(), identity)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Verifying signature on build environment attestation...")
+	if err := state.VerifyBuildEnvironmentAttestation(ctx, refName, env); err != nil {
+		return nil, err
+	}
+
+	return attestations.GetBuildEnvironment(env, identity)
+}
+
+// getEncryptionKeys returns the keys in the policy state with the specified
+// IDs, to encrypt attestations for.
+func getEncryptionKeys(state *policy.State, keyIDs []string) ([]*tuf.Key, error) {
+	allKeys, err := state.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*tuf.Key, 0, len(keyIDs))
+	for _, keyID := range keyIDs {
+		key, has := allKeys[keyID]
+		if !has {
+			return nil, fmt.Errorf("%w: '%s'", ErrEncryptionKeyNotInPolicy, keyID)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// DecodeOIDCTokenClaims returns the claims in the payload of the OIDC token.
+// The token's signature is not verified, the claims are instead vouched for by
+// the key that signs the build environment attestation they are recorded in.
+func DecodeOIDCTokenClaims(token string) (map[string]any, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: token must have three parts", ErrInvalidOIDCToken)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidOIDCToken, err)
+	}
+
+	claims := map[string]any{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidOIDCToken, err)
+	}
+
+	return claims, nil
+}
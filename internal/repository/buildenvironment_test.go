@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+func TestBuildEnvironmentAttestation(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	runnerSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(artifacts.SSLibKey3Private) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	runnerKey, err := tuf.LoadKeyFromBytes(artifacts.SSLibKey3Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The auditor's key is only used to decrypt build environments
+	auditorIdentity, err := dsse.LoadDecryptionKey(artifacts.SSHRSAPrivate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditorKey := createTestSSHKey(t, artifacts.SSHRSAPublicSSH)
+
+	if err := repo.AddDelegation(testCtx, targetsSigner, policy.TargetsRoleName, "protect-ci", []*tuf.Key{runnerKey, auditorKey}, []string{"git:refs/heads/ci"}, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := policy.Apply(testCtx, repo.r, false); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/ci"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+
+	environment := &attestations.BuildEnvironment{
+		Platform:   "github-actions",
+		Hostname:   "runner-host",
+		RunnerID:   "GitHub Actions 2",
+		OIDCClaims: map[string]any{"repository": "gittuf/gittuf"},
+	}
+
+	// Attestation doesn't exist yet
+	_, err = repo.GetBuildEnvironment(testCtx, refName, nil)
+	assert.ErrorIs(t, err, attestations.ErrBuildEnvironmentAttestationNotFound)
+
+	// Only keys trusted for the ref can attest to its build environment
+	err = repo.AddBuildEnvironmentAttestation(testCtx, targetsSigner, refName, environment, nil, false)
+	assert.ErrorIs(t, err, policy.ErrBuildEnvironmentNotSignedByTrustedKey)
+
+	err = repo.AddBuildEnvironmentAttestation(testCtx, runnerSigner, refName, environment, nil, false)
+	assert.Nil(t, err)
+
+	buildEnvironment, err := repo.GetBuildEnvironment(testCtx, refName, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, refName, buildEnvironment.Ref)
+	assert.Equal(t, "github-actions", buildEnvironment.Platform)
+	assert.Equal(t, "runner-host", buildEnvironment.Hostname)
+	assert.Equal(t, "GitHub Actions 2", buildEnvironment.RunnerID)
+	assert.Equal(t, "gittuf/gittuf", buildEnvironment.OIDCClaims["repository"])
+
+	// The caller's environment is not modified
+	assert.Empty(t, environment.Ref)
+
+	// A new entry for the ref has no recorded build environment
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+	entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+
+	_, err = repo.GetBuildEnvironment(testCtx, refName, nil)
+	assert.ErrorIs(t, err, attestations.ErrBuildEnvironmentAttestationNotFound)
+
+	t.Run("encrypted build environment", func(t *testing.T) {
+		err := repo.AddBuildEnvironmentAttestation(testCtx, runnerSigner, refName, environment, []string{"unknown-key"}, false)
+		assert.ErrorIs(t, err, ErrEncryptionKeyNotInPolicy)
+
+		err = repo.AddBuildEnvironmentAttestation(testCtx, runnerSigner, refName, environment, []string{auditorKey.KeyID}, false)
+		assert.Nil(t, err)
+
+		// The build environment isn't disclosed in the attestations
+		allAttestations, err := attestations.LoadCurrentAttestations(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo.r, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := allAttestations.GetBuildEnvironmentAttestationFor(repo.r, refName, latestEntry.ID.String(), auditorIdentity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, dsse.IsEncrypted(env))
+		assert.NotContains(t, env.Payload, base64.StdEncoding.EncodeToString([]byte("runner-host")))
+		attestationsRef, err := repo.r.Reference(plumbing.ReferenceName(attestations.Ref), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		attestationsCommit, err := repo.r.CommitObject(attestationsRef.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.NotContains(t, attestationsCommit.Message, "runner-host")
+
+		_, err = repo.GetBuildEnvironment(testCtx, refName, nil)
+		assert.ErrorIs(t, err, dsse.ErrEnvelopeEncrypted)
+
+		runnerIdentity, err := dsse.LoadDecryptionKey(artifacts.SSHED25519Private)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = repo.GetBuildEnvironment(testCtx, refName, runnerIdentity)
+		assert.ErrorIs(t, err, dsse.ErrNotEncryptedForKey)
+
+		buildEnvironment, err := repo.GetBuildEnvironment(testCtx, refName, auditorIdentity)
+		assert.Nil(t, err)
+		assert.Equal(t, "runner-host", buildEnvironment.Hostname)
+		assert.Equal(t, "gittuf/gittuf", buildEnvironment.OIDCClaims["repository"])
+	})
+}
+
+// createTestSSHKey returns the key in the policy for the SSH public key in the
+// authorized keys format.
+func createTestSSHKey(t *testing.T, publicKeyBytes []byte) *tuf.Key {
+	t.Helper()
+
+	sshKey, _, _, _, err := gossh.ParseAuthorizedKey(publicKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &tuf.Key{
+		KeyID:   gossh.FingerprintSHA256(sshKey),
+		KeyType: ssh.SSHKeyType,
+		Scheme:  sshKey.Type(),
+		KeyVal: sslibsv.KeyVal{
+			Public: base64.StdEncoding.EncodeToString(sshKey.Marshal()),
+		},
+	}
+}
+
+func TestDecodeOIDCTokenClaims(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"iss": "https://token.actions.githubusercontent.com", "repository": "gittuf/gittuf"}`))
+
+	claims, err := DecodeOIDCTokenClaims("header." + payload + ".signature\n")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{"iss": "https://token.actions.githubusercontent.com", "repository": "gittuf/gittuf"}, claims)
+
+	_, err = DecodeOIDCTokenClaims("not-a-token")
+	assert.ErrorIs(t, err, ErrInvalidOIDCToken)
+
+	_, err = DecodeOIDCTokenClaims("header.!!!.signature")
+	assert.ErrorIs(t, err, ErrInvalidOIDCToken)
+}
@@ -0,0 +1,350 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+const (
+	CeremonyActionStart    = "start"
+	CeremonyActionAddKey   = "add-key"
+	CeremonyActionAssemble = "assemble"
+	CeremonyActionSign     = "sign"
+	CeremonyActionVerify   = "verify"
+	CeremonyActionPublish  = "publish"
+)
+
+var (
+	ErrInvalidCeremonyThreshold     = errors.New("root threshold must be at least 1")
+	ErrCeremonyKeyAlreadyCollected  = errors.New("key has already been collected in the ceremony")
+	ErrCeremonyKeyNotCollected      = errors.New("key has not been collected in the ceremony")
+	ErrCeremonyNotEnoughKeys        = errors.New("fewer root keys have been collected than the root threshold")
+	ErrCeremonyAlreadyAssembled     = errors.New("root metadata has already been assembled in the ceremony")
+	ErrCeremonyNotAssembled         = errors.New("root metadata has not been assembled in the ceremony")
+	ErrCeremonyRootMetadataMismatch = errors.New("assembled root metadata does not match the keys and threshold of the ceremony")
+	ErrCeremonyTranscriptMismatch   = errors.New("ceremony transcript is inconsistent, it may have been modified")
+	ErrCeremonyNotVerified          = errors.New("ceremony must be verified after its last change before the root of trust is published")
+)
+
+// RootCeremony records a key ceremony for a new root of trust whose keys are
+// held on separate machines. The ceremony is stored in a file that is passed
+// between the machines, so it only contains public keys and signatures. Each
+// key holder adds their public key, the root metadata is assembled offline
+// once enough keys are collected, each key holder signs it, and the signed
+// root metadata is verified before it is published as the repository's root
+// of trust. Every step is recorded in the ceremony's transcript for audit.
+type RootCeremony struct {
+	// Threshold is the number of root keys that must sign the root metadata.
+	Threshold int `json:"threshold"`
+
+	// Keys contains the public root keys collected from the key holders.
+	Keys []*tuf.Key `json:"keys"`
+
+	// RootEnvelope contains the assembled root metadata and the signatures
+	// collected for it.
+	RootEnvelope *sslibdsse.Envelope `json:"rootEnvelope,omitempty"`
+
+	// Transcript records each step of the ceremony in order.
+	Transcript []*CeremonyTranscriptEntry `json:"transcript"`
+}
+
+// CeremonyTranscriptEntry records a step of a root key ceremony.
+type CeremonyTranscriptEntry struct {
+	// Timestamp is when the step was performed, in RFC 3339 format.
+	Timestamp string `json:"timestamp"`
+
+	// Action is the step performed, such as CeremonyActionSign.
+	Action string `json:"action"`
+
+	// Hostname identifies the machine the step was performed on.
+	Hostname string `json:"hostname,omitempty"`
+
+	// KeyID is the ID of the key added or used to sign in the step.
+	KeyID string `json:"keyID,omitempty"`
+
+	// PayloadDigest is the digest of the root metadata at the time of the
+	// step, once it has been assembled.
+	PayloadDigest string `json:"payloadDigest,omitempty"`
+
+	// Digest is the SHA-256 digest of the previous entry's digest and this
+	// entry's other fields, chaining the transcript together.
+	Digest string `json:"digest"`
+}
+
+// NewRootCeremony starts a ceremony for a root of trust with the specified
+// threshold.
+func NewRootCeremony(threshold int) (*RootCeremony, error) {
+	if threshold < 1 {
+		return nil, ErrInvalidCeremonyThreshold
+	}
+
+	ceremony := &RootCeremony{Threshold: threshold, Keys: []*tuf.Key{}}
+	ceremony.record(CeremonyActionStart, "")
+
+	return ceremony, nil
+}
+
+// LoadRootCeremony reads a ceremony from the file at path.
+func LoadRootCeremony(path string) (*RootCeremony, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ceremony := &RootCeremony{}
+	if err := json.Unmarshal(contents, ceremony); err != nil {
+		return nil, fmt.Errorf("unable to parse ceremony: %w", err)
+	}
+
+	return ceremony, nil
+}
+
+// Save writes the ceremony to the file at path.
+func (c *RootCeremony) Save(path string) error {
+	contents, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(contents, '\n'), 0o644)
+}
+
+// AddKey adds a key holder's public key to the ceremony. Keys can only be
+// added before the root metadata is assembled.
+func (c *RootCeremony) AddKey(key *tuf.Key) error {
+	if c.RootEnvelope != nil {
+		return ErrCeremonyAlreadyAssembled
+	}
+
+	if c.hasKey(key.KeyID) {
+		return fmt.Errorf("%w: '%s'", ErrCeremonyKeyAlreadyCollected, key.KeyID)
+	}
+
+	slog.Debug(fmt.Sprintf("Adding key '%s' to ceremony...", key.KeyID))
+	c.Keys = append(c.Keys, key)
+	c.record(CeremonyActionAddKey, key.KeyID)
+
+	return nil
+}
+
+// Assemble creates the unsigned root metadata trusting the collected keys with
+// the ceremony's threshold.
+func (c *RootCeremony) Assemble() error {
+	if c.RootEnvelope != nil {
+		return ErrCeremonyAlreadyAssembled
+	}
+	if len(c.Keys) < c.Threshold {
+		return fmt.Errorf("%w: %d of %d", ErrCeremonyNotEnoughKeys, len(c.Keys), c.Threshold)
+	}
+
+	slog.Debug("Assembling root metadata...")
+	rootMetadata := policy.InitializeRootMetadata(c.Keys[0])
+	for _, key := range c.Keys[1:] {
+		rootMetadata = policy.AddRootKey(rootMetadata, key)
+	}
+	rootMetadata, err := policy.UpdateRootThreshold(rootMetadata, c.Threshold)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		return err
+	}
+
+	c.RootEnvelope = env
+	c.record(CeremonyActionAssemble, "")
+
+	return nil
+}
+
+// Sign adds the signer's signature to the assembled root metadata. The
+// signer's key must have been collected in the ceremony.
+func (c *RootCeremony) Sign(ctx context.Context, signer sslibdsse.SignerVerifier) error {
+	if c.RootEnvelope == nil {
+		return ErrCeremonyNotAssembled
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+	if !c.hasKey(keyID) {
+		return fmt.Errorf("%w: '%s'", ErrCeremonyKeyNotCollected, keyID)
+	}
+
+	slog.Debug(fmt.Sprintf("Signing root metadata using '%s'...", keyID))
+	env, err := dsse.SignEnvelope(ctx, c.RootEnvelope, signer)
+	if err != nil {
+		return err
+	}
+
+	c.RootEnvelope = env
+	c.record(CeremonyActionSign, keyID)
+
+	return nil
+}
+
+// Verify checks that the transcript is consistent, that the assembled root
+// metadata trusts exactly the collected keys with the ceremony's threshold,
+// and that it is signed by a threshold of them. If verification succeeds, it
+// is recorded in the transcript.
+func (c *RootCeremony) Verify(ctx context.Context) error {
+	if err := c.verifyTranscript(); err != nil {
+		return err
+	}
+
+	if c.RootEnvelope == nil {
+		return ErrCeremonyNotAssembled
+	}
+
+	state := c.state()
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		return err
+	}
+
+	rootRole, hasRootRole := rootMetadata.Roles[policy.RootRoleName]
+	if !hasRootRole || rootRole.Threshold != c.Threshold || len(rootRole.KeyIDs) != len(c.Keys) {
+		return ErrCeremonyRootMetadataMismatch
+	}
+	for _, keyID := range rootRole.KeyIDs {
+		if !c.hasKey(keyID) {
+			return ErrCeremonyRootMetadataMismatch
+		}
+	}
+
+	slog.Debug("Verifying root metadata signatures...")
+	if err := state.Verify(ctx); err != nil {
+		return err
+	}
+
+	c.record(CeremonyActionVerify, "")
+	return nil
+}
+
+// InitializeRootFromCeremony creates the repository's root of trust using the
+// root metadata assembled and signed in the ceremony. The ceremony must have
+// been verified since it was last changed, and it is verified again before
+// the root metadata is committed. Publication is recorded in the transcript.
+func (r *Repository) InitializeRootFromCeremony(ctx context.Context, ceremony *RootCeremony, signCommit bool) error {
+	if len(ceremony.Transcript) == 0 || ceremony.Transcript[len(ceremony.Transcript)-1].Action != CeremonyActionVerify {
+		return ErrCeremonyNotVerified
+	}
+
+	if err := ceremony.Verify(ctx); err != nil {
+		return err
+	}
+
+	if err := r.InitializeNamespaces(); err != nil {
+		return err
+	}
+
+	state := ceremony.state()
+
+	slog.Debug("Committing policy...")
+	if err := state.Commit(r.r, "Initialize root of trust from key ceremony", signCommit); err != nil {
+		return err
+	}
+
+	ceremony.record(CeremonyActionPublish, "")
+	return nil
+}
+
+func (c *RootCeremony) state() *policy.State {
+	// Verification may reorder the state's keys, the ceremony's order must be
+	// preserved to match the transcript
+	return &policy.State{
+		RootPublicKeys: slices.Clone(c.Keys),
+		RootEnvelope:   c.RootEnvelope,
+	}
+}
+
+func (c *RootCeremony) hasKey(keyID string) bool {
+	return slices.ContainsFunc(c.Keys, func(key *tuf.Key) bool {
+		return key.KeyID == keyID
+	})
+}
+
+func (c *RootCeremony) record(action, keyID string) {
+	entry := &CeremonyTranscriptEntry{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Action:    action,
+		KeyID:     keyID,
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		entry.Hostname = hostname
+	}
+	if c.RootEnvelope != nil {
+		entry.PayloadDigest = dsse.PayloadDigest(c.RootEnvelope)
+	}
+
+	previousDigest := ""
+	if len(c.Transcript) > 0 {
+		previousDigest = c.Transcript[len(c.Transcript)-1].Digest
+	}
+	entry.Digest = entry.computeDigest(previousDigest)
+
+	c.Transcript = append(c.Transcript, entry)
+}
+
+// verifyTranscript checks that the transcript's entries are chained together,
+// that every collected key was added in the transcript, and that the root
+// metadata matches the transcript's latest digest of it.
+func (c *RootCeremony) verifyTranscript() error {
+	if len(c.Transcript) == 0 || c.Transcript[0].Action != CeremonyActionStart {
+		return ErrCeremonyTranscriptMismatch
+	}
+
+	previousDigest := ""
+	addedKeyIDs := []string{}
+	payloadDigest := ""
+	for _, entry := range c.Transcript {
+		if entry.Digest != entry.computeDigest(previousDigest) {
+			return ErrCeremonyTranscriptMismatch
+		}
+		previousDigest = entry.Digest
+
+		if entry.Action == CeremonyActionAddKey {
+			addedKeyIDs = append(addedKeyIDs, entry.KeyID)
+		}
+		payloadDigest = entry.PayloadDigest
+	}
+
+	if len(addedKeyIDs) != len(c.Keys) {
+		return ErrCeremonyTranscriptMismatch
+	}
+	for index, key := range c.Keys {
+		if addedKeyIDs[index] != key.KeyID {
+			return ErrCeremonyTranscriptMismatch
+		}
+	}
+
+	if c.RootEnvelope != nil && payloadDigest != dsse.PayloadDigest(c.RootEnvelope) {
+		return ErrCeremonyTranscriptMismatch
+	}
+
+	return nil
+}
+
+func (e *CeremonyTranscriptEntry) computeDigest(previousDigest string) string {
+	hash := sha256.New()
+	hash.Write([]byte(strings.Join([]string{previousDigest, e.Timestamp, e.Action, e.Hostname, e.KeyID, e.PayloadDigest}, "\n")))
+	return hex.EncodeToString(hash.Sum(nil))
+}
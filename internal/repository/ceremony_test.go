@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootCeremony(t *testing.T) {
+	firstKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondKey, err := tuf.LoadKeyFromBytes(targetsPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	firstSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("invalid threshold", func(t *testing.T) {
+		_, err := NewRootCeremony(0)
+		assert.ErrorIs(t, err, ErrInvalidCeremonyThreshold)
+	})
+
+	t.Run("successful ceremony across machines", func(t *testing.T) {
+		ceremonyPath := filepath.Join(t.TempDir(), "ceremony.json")
+
+		// Each step loads the ceremony from the file as if on a separate
+		// machine
+		step := func(f func(ceremony *RootCeremony) error) error {
+			ceremony, err := LoadRootCeremony(ceremonyPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := f(ceremony); err != nil {
+				return err
+			}
+			return ceremony.Save(ceremonyPath)
+		}
+
+		ceremony, err := NewRootCeremony(2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ceremony.Save(ceremonyPath); err != nil {
+			t.Fatal(err)
+		}
+
+		err = step(func(ceremony *RootCeremony) error { return ceremony.AddKey(firstKey) })
+		assert.Nil(t, err)
+
+		err = step(func(ceremony *RootCeremony) error { return ceremony.AddKey(firstKey) })
+		assert.ErrorIs(t, err, ErrCeremonyKeyAlreadyCollected)
+
+		err = step(func(ceremony *RootCeremony) error { return ceremony.Assemble() })
+		assert.ErrorIs(t, err, ErrCeremonyNotEnoughKeys)
+
+		err = step(func(ceremony *RootCeremony) error { return ceremony.AddKey(secondKey) })
+		assert.Nil(t, err)
+
+		err = step(func(ceremony *RootCeremony) error { return ceremony.Sign(testCtx, firstSigner) })
+		assert.ErrorIs(t, err, ErrCeremonyNotAssembled)
+
+		err = step(func(ceremony *RootCeremony) error { return ceremony.Assemble() })
+		assert.Nil(t, err)
+
+		err = step(func(ceremony *RootCeremony) error { return ceremony.AddKey(firstKey) })
+		assert.ErrorIs(t, err, ErrCeremonyAlreadyAssembled)
+
+		err = step(func(ceremony *RootCeremony) error { return ceremony.Sign(testCtx, firstSigner) })
+		assert.Nil(t, err)
+
+		// Not enough signatures yet
+		err = step(func(ceremony *RootCeremony) error { return ceremony.Verify(testCtx) })
+		assert.NotNil(t, err)
+
+		err = step(func(ceremony *RootCeremony) error { return ceremony.Sign(testCtx, secondSigner) })
+		assert.Nil(t, err)
+
+		r, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		repo := &Repository{r: r}
+
+		err = step(func(ceremony *RootCeremony) error { return repo.InitializeRootFromCeremony(testCtx, ceremony, false) })
+		assert.ErrorIs(t, err, ErrCeremonyNotVerified)
+
+		err = step(func(ceremony *RootCeremony) error { return ceremony.Verify(testCtx) })
+		assert.Nil(t, err)
+
+		err = step(func(ceremony *RootCeremony) error { return repo.InitializeRootFromCeremony(testCtx, ceremony, false) })
+		assert.Nil(t, err)
+
+		state, err := policy.LoadCurrentState(testCtx, r, policy.PolicyStagingRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rootMetadata, err := state.GetRootMetadata()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 2, rootMetadata.Roles[policy.RootRoleName].Threshold)
+		assert.Equal(t, []string{firstKey.KeyID, secondKey.KeyID}, rootMetadata.Roles[policy.RootRoleName].KeyIDs)
+		assert.Len(t, state.RootEnvelope.Signatures, 2)
+
+		ceremony, err = LoadRootCeremony(ceremonyPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actions := []string{}
+		for _, entry := range ceremony.Transcript {
+			actions = append(actions, entry.Action)
+		}
+		assert.Equal(t, []string{
+			CeremonyActionStart,
+			CeremonyActionAddKey,
+			CeremonyActionAddKey,
+			CeremonyActionAssemble,
+			CeremonyActionSign,
+			CeremonyActionSign,
+			CeremonyActionVerify,
+			CeremonyActionVerify,
+			CeremonyActionPublish,
+		}, actions)
+		assert.Nil(t, ceremony.verifyTranscript())
+	})
+
+	t.Run("sign with key not in ceremony", func(t *testing.T) {
+		ceremony, err := NewRootCeremony(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ceremony.AddKey(firstKey); err != nil {
+			t.Fatal(err)
+		}
+		if err := ceremony.Assemble(); err != nil {
+			t.Fatal(err)
+		}
+
+		err = ceremony.Sign(testCtx, secondSigner)
+		assert.ErrorIs(t, err, ErrCeremonyKeyNotCollected)
+	})
+
+	t.Run("tampered ceremony", func(t *testing.T) {
+		ceremony, err := NewRootCeremony(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ceremony.AddKey(firstKey); err != nil {
+			t.Fatal(err)
+		}
+		if err := ceremony.Assemble(); err != nil {
+			t.Fatal(err)
+		}
+		if err := ceremony.Sign(testCtx, firstSigner); err != nil {
+			t.Fatal(err)
+		}
+		assert.Nil(t, ceremony.Verify(testCtx))
+
+		// Swapping in a key after assembly is caught by the transcript
+		tampered := *ceremony
+		tampered.Keys = []*tuf.Key{secondKey}
+		err = tampered.Verify(testCtx)
+		assert.ErrorIs(t, err, ErrCeremonyTranscriptMismatch)
+
+		// Rewriting a transcript entry breaks the chain
+		tampered = *ceremony
+		tampered.Transcript = append([]*CeremonyTranscriptEntry{}, ceremony.Transcript...)
+		modifiedEntry := *tampered.Transcript[1]
+		modifiedEntry.Hostname = "elsewhere"
+		tampered.Transcript[1] = &modifiedEntry
+		err = tampered.Verify(testCtx)
+		assert.ErrorIs(t, err, ErrCeremonyTranscriptMismatch)
+
+		// Replacing the root metadata is caught by the transcript
+		tampered = *ceremony
+		otherCeremony, err := NewRootCeremony(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := otherCeremony.AddKey(secondKey); err != nil {
+			t.Fatal(err)
+		}
+		if err := otherCeremony.Assemble(); err != nil {
+			t.Fatal(err)
+		}
+		tampered.RootEnvelope = otherCeremony.RootEnvelope
+		err = tampered.Verify(testCtx)
+		assert.ErrorIs(t, err, ErrCeremonyTranscriptMismatch)
+	})
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var (
+	ErrRepositoryNotInChangeSet = errors.New("change set has no changes for repository")
+	ErrChangeSetMismatch        = errors.New("change set differs from the change set already recorded for its changes")
+)
+
+// LoadChangeSet reads a change set from the JSON file at the specified path.
+func LoadChangeSet(path string) (*attestations.ChangeSet, error) {
+	changeSetBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	changeSet := &attestations.ChangeSet{}
+	if err := json.Unmarshal(changeSetBytes, changeSet); err != nil {
+		return nil, fmt.Errorf("%w: %w", attestations.ErrInvalidChangeSet, err)
+	}
+
+	if err := changeSet.Validate(); err != nil {
+		return nil, err
+	}
+
+	return changeSet, nil
+}
+
+// AddChangeSetAttestation adds a signature from the signer to the attestation
+// for the change set, and records it for each of the set's changes to the
+// specified repository, which identifies this repository in the set. When a
+// change is verified, the attestation's signatures count towards the threshold
+// of the rules protecting its ref, as for a reference authorization. If the
+// set's attestation already exists, the signature is added to it, allowing
+// multiple developers to co-sign the set. As each repository in the set tracks
+// the attestation independently, it must be recorded in all of them to
+// authorize the set's changes.
+func (r *Repository) AddChangeSetAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, changeSet *attestations.ChangeSet, repositoryName string, signCommit bool) error {
+	if err := changeSet.Validate(); err != nil {
+		return err
+	}
+
+	changes := changeSet.ChangesForRepository(repositoryName)
+	if len(changes) == 0 {
+		return fmt.Errorf("%w '%s'", ErrRepositoryNotInChangeSet, repositoryName)
+	}
+
+	slog.Debug("Loading current set of attestations...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return err
+	}
+
+	// Does an attestation already exist for the set?
+	var env *sslibdsse.Envelope
+	for _, change := range changes {
+		existingEnv, err := allAttestations.GetChangeSetAttestationFor(r.r, change.TargetRef, change.FromRevisionID, change.TargetTreeID)
+		if err != nil {
+			if errors.Is(err, attestations.ErrChangeSetNotFound) {
+				continue
+			}
+			return err
+		}
+
+		existingChangeSet, err := attestations.GetChangeSet(existingEnv)
+		if err != nil {
+			return err
+		}
+
+		if !reflect.DeepEqual(existingChangeSet, changeSet) {
+			return fmt.Errorf("%w: '%s' in '%s' is part of change set '%s'", ErrChangeSetMismatch, change.TargetRef, repositoryName, existingChangeSet.ID)
+		}
+
+		if env == nil {
+			slog.Debug("Found existing change set attestation...")
+			env = existingEnv
+		}
+	}
+
+	if env == nil {
+		slog.Debug("Creating new change set attestation...")
+		statement, err := attestations.NewChangeSetAttestation(changeSet)
+		if err != nil {
+			return err
+		}
+
+		env, err = dsse.CreateEnvelope(statement)
+		if err != nil {
+			return err
+		}
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing change set attestation using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		if err := allAttestations.SetChangeSetAttestation(r.r, env, change.TargetRef, change.FromRevisionID, change.TargetTreeID); err != nil {
+			return err
+		}
+	}
+
+	commitMessage := fmt.Sprintf("Add change set attestation for '%s' in '%s'", changeSet.ID, repositoryName)
+
+	slog.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
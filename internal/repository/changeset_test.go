@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddChangeSetAttestation(t *testing.T) {
+	tempDir := t.TempDir()
+	r, err := gitinterface.PlainInit(tempDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := repo.InitializeNamespaces(); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, "refs/heads/main", 2, gpgKeyBytes)
+	fromCommitID := commitIDs[0].String()
+	toCommit, err := gitinterface.GetCommit(r, commitIDs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	toTreeID := toCommit.TreeHash.String()
+
+	changeSet := &attestations.ChangeSet{
+		ID: "release-1.0",
+		Changes: []*attestations.ChangeSetMember{
+			{Repository: "app", TargetRef: "refs/heads/main", FromRevisionID: fromCommitID, TargetTreeID: toTreeID},
+			{Repository: "lib", TargetRef: "refs/heads/main", FromRevisionID: fromCommitID, TargetTreeID: toTreeID},
+		},
+	}
+
+	firstSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("repository not in change set", func(t *testing.T) {
+		err := repo.AddChangeSetAttestation(testCtx, firstSigner, changeSet, "docs", false)
+		assert.ErrorIs(t, err, ErrRepositoryNotInChangeSet)
+	})
+
+	t.Run("co-sign change set", func(t *testing.T) {
+		err := repo.AddChangeSetAttestation(testCtx, firstSigner, changeSet, "app", false)
+		assert.Nil(t, err)
+
+		err = repo.AddChangeSetAttestation(testCtx, secondSigner, changeSet, "app", false)
+		assert.Nil(t, err)
+
+		allAttestations, err := attestations.LoadCurrentAttestations(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		env, err := allAttestations.GetChangeSetAttestationFor(r, "refs/heads/main", fromCommitID, toTreeID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, env.Signatures, 2)
+
+		storedChangeSet, err := attestations.GetChangeSet(env)
+		assert.Nil(t, err)
+		assert.Equal(t, changeSet, storedChangeSet)
+	})
+
+	t.Run("change already part of different change set", func(t *testing.T) {
+		otherChangeSet := &attestations.ChangeSet{
+			ID:      "release-1.1",
+			Changes: []*attestations.ChangeSetMember{changeSet.Changes[0]},
+		}
+
+		err := repo.AddChangeSetAttestation(testCtx, firstSigner, otherChangeSet, "app", false)
+		assert.ErrorIs(t, err, ErrChangeSetMismatch)
+	})
+}
+
+func TestLoadChangeSet(t *testing.T) {
+	tempDir := t.TempDir()
+
+	changeSetPath := filepath.Join(tempDir, "change-set.json")
+	changeSetContents := fmt.Sprintf(`{"id": "release-1.0", "changes": [{"repository": "app", "targetRef": "refs/heads/main", "fromRevisionID": "%s", "targetTreeID": "%s"}]}`, plumbing.ZeroHash.String(), gitinterface.EmptyTree().String())
+	if err := os.WriteFile(changeSetPath, []byte(changeSetContents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	changeSet, err := LoadChangeSet(changeSetPath)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "release-1.0", changeSet.ID)
+	assert.Equal(t, []string{"app"}, changeSet.Repositories())
+
+	invalidPath := filepath.Join(tempDir, "invalid.json")
+	if err := os.WriteFile(invalidPath, []byte(`{"id": "release-1.0", "changes": []}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = LoadChangeSet(invalidPath)
+	assert.ErrorIs(t, err, attestations.ErrInvalidChangeSet)
+}
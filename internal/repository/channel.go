@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	// ChannelRefPrefix is the prefix of the refs that record release channels.
+	// Channel refs are recorded in the RSL and verified like any other ref, so
+	// the policy's rules for them, such as
+	// git:refs/gittuf/channels/stable, govern who may promote tags to them.
+	ChannelRefPrefix = "refs/gittuf/channels/"
+
+	channelMetadataFileName = "channel.json"
+)
+
+var (
+	ErrInvalidChannelName    = errors.New("invalid channel name")
+	ErrChannelNotFound       = errors.New("channel not found")
+	ErrTagNotInSourceChannel = errors.New("tag is not the current tag of the channel it's promoted from")
+	ErrChannelTagMismatch    = errors.New("tag does not match the tag recorded for the channel")
+	ErrTagRefMismatch        = errors.New("tag does not match its latest RSL entry")
+)
+
+// Channel records the tag a release channel, such as stable or beta, points
+// at.
+type Channel struct {
+	// Name identifies the channel.
+	Name string `json:"name"`
+
+	// Tag is the tag reference the channel points at.
+	Tag string `json:"tag"`
+
+	// TagID is the ID of the tag object recorded in the RSL for Tag when it
+	// was promoted.
+	TagID string `json:"tagID"`
+
+	// PromotedFrom is the channel the tag was promoted from, if any.
+	PromotedFrom string `json:"promotedFrom,omitempty"`
+}
+
+// PromoteToChannel points the channel at the specified tag. The tag must pass
+// verification. If fromChannel is set, the tag must be the current tag of that
+// channel, which must also pass verification. The channel's metadata is
+// committed to its ref and recorded in the RSL, so that the promotion is
+// verified against the policy's rules for the channel ref.
+func (r *Repository) PromoteToChannel(ctx context.Context, channelName, tag, fromChannel string, signCommit bool) error {
+	channelRef, err := channelRefName(channelName)
+	if err != nil {
+		return err
+	}
+
+	tagRef, err := gitinterface.AbsoluteReference(r.r, tag)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Verifying '%s'...", tagRef))
+	tagEntry, _, err := policy.VerifyTagRef(ctx, r.r, tagRef)
+	if err != nil {
+		return err
+	}
+
+	ref, err := r.r.Reference(plumbing.ReferenceName(tagRef), true)
+	if err != nil {
+		return err
+	}
+	if ref.Hash() != tagEntry.TargetID {
+		return fmt.Errorf("%w: '%s' is at '%s', RSL records '%s'", ErrTagRefMismatch, tagRef, ref.Hash().String(), tagEntry.TargetID.String())
+	}
+
+	if fromChannel != "" {
+		source, err := r.VerifyChannel(ctx, fromChannel)
+		if err != nil {
+			return err
+		}
+
+		if source.Tag != tagRef || source.TagID != tagEntry.TargetID.String() {
+			return fmt.Errorf("%w: channel '%s' points at '%s'", ErrTagNotInSourceChannel, fromChannel, source.Tag)
+		}
+	}
+
+	channel := &Channel{
+		Name:         channelName,
+		Tag:          tagRef,
+		TagID:        tagEntry.TargetID.String(),
+		PromotedFrom: fromChannel,
+	}
+
+	contents, err := json.Marshal(channel)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := gitinterface.WriteBlob(r.r, contents)
+	if err != nil {
+		return err
+	}
+
+	treeID, err := gitinterface.NewTreeBuilder(r.r).WriteRootTreeFromBlobIDs(map[string]plumbing.Hash{channelMetadataFileName: blobID})
+	if err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Promote '%s' to channel '%s'", tagRef, channelName)
+	if fromChannel != "" {
+		commitMessage = fmt.Sprintf("Promote '%s' from channel '%s' to channel '%s'", tagRef, fromChannel, channelName)
+	}
+
+	slog.Debug(fmt.Sprintf("Committing channel metadata to '%s'...", channelRef))
+	if _, err := gitinterface.Commit(r.r, treeID, channelRef, commitMessage, signCommit); err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Recording '%s' in the RSL...", channelRef))
+	return r.RecordRSLEntryForReference(ctx, channelRef, signCommit)
+}
+
+// VerifyChannel verifies the channel's latest RSL entry and the tag it points
+// at, returning the channel's metadata. The tag must still be at the object
+// recorded when it was promoted, so a channel cannot be made to point at a
+// different release by moving the tag.
+func (r *Repository) VerifyChannel(ctx context.Context, channelName string) (*Channel, error) {
+	channelRef, err := channelRefName(channelName)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug(fmt.Sprintf("Verifying '%s'...", channelRef))
+	channelID, err := policy.VerifyRef(ctx, r.r, channelRef)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return nil, fmt.Errorf("%w: '%s'", ErrChannelNotFound, channelName)
+		}
+		return nil, err
+	}
+
+	channel, err := r.loadChannel(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug(fmt.Sprintf("Verifying '%s'...", channel.Tag))
+	tagEntry, _, err := policy.VerifyTagRef(ctx, r.r, channel.Tag)
+	if err != nil {
+		return nil, err
+	}
+	if tagEntry.TargetID.String() != channel.TagID {
+		return nil, fmt.Errorf("%w: channel '%s' records '%s' for '%s', RSL records '%s'", ErrChannelTagMismatch, channelName, channel.TagID, channel.Tag, tagEntry.TargetID.String())
+	}
+
+	return channel, nil
+}
+
+// ListChannels returns the names of the channels in the repository, sorted by
+// name.
+func (r *Repository) ListChannels() ([]string, error) {
+	refs, err := r.r.References()
+	if err != nil {
+		return nil, err
+	}
+
+	channels := []string{}
+	if err := refs.ForEach(func(ref *plumbing.Reference) error {
+		if name, isChannel := strings.CutPrefix(ref.Name().String(), ChannelRefPrefix); isChannel {
+			channels = append(channels, name)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Strings(channels)
+
+	return channels, nil
+}
+
+// loadChannel reads the channel metadata recorded in the specified commit.
+func (r *Repository) loadChannel(commitID plumbing.Hash) (*Channel, error) {
+	commit, err := gitinterface.GetCommit(r.r, commitID)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := gitinterface.GetTree(r.r, commit.TreeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := tree.File(channelMetadataFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := file.Contents()
+	if err != nil {
+		return nil, err
+	}
+
+	channel := &Channel{}
+	if err := json.Unmarshal([]byte(contents), channel); err != nil {
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+// channelRefName returns the ref recording the channel.
+func channelRefName(channelName string) (string, error) {
+	if channelName == "" || strings.Contains(channelName, "/") {
+		return "", fmt.Errorf("%w: '%s'", ErrInvalidChannelName, channelName)
+	}
+
+	refName := plumbing.ReferenceName(ChannelRefPrefix + channelName)
+	if err := refName.Validate(); err != nil {
+		return "", fmt.Errorf("%w: '%s'", ErrInvalidChannelName, channelName)
+	}
+
+	return refName.String(), nil
+}
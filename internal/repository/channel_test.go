@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromoteToChannel(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, "refs/heads/main", 2, gpgKeyBytes)
+	v1TagID := common.CreateTestSignedTag(t, repo.r, "v1", commitIDs[0], gpgKeyBytes)
+	v2TagID := common.CreateTestSignedTag(t, repo.r, "v2", commitIDs[1], gpgKeyBytes)
+
+	t.Run("invalid channel name", func(t *testing.T) {
+		err := repo.PromoteToChannel(testCtx, "release/stable", "v1", "", false)
+		assert.ErrorIs(t, err, ErrInvalidChannelName)
+
+		_, err = repo.VerifyChannel(testCtx, "")
+		assert.ErrorIs(t, err, ErrInvalidChannelName)
+	})
+
+	t.Run("tag not recorded in RSL", func(t *testing.T) {
+		err := repo.PromoteToChannel(testCtx, "beta", "v1", "", false)
+		assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
+	})
+
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(string(plumbing.NewTagReferenceName("v1")), v1TagID), gpgKeyBytes)
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(string(plumbing.NewTagReferenceName("v2")), v2TagID), gpgKeyBytes)
+
+	t.Run("channel not found", func(t *testing.T) {
+		_, err := repo.VerifyChannel(testCtx, "beta")
+		assert.ErrorIs(t, err, ErrChannelNotFound)
+	})
+
+	t.Run("promote to channel", func(t *testing.T) {
+		err := repo.PromoteToChannel(testCtx, "beta", "v1", "", false)
+		assert.Nil(t, err)
+
+		channel, err := repo.VerifyChannel(testCtx, "beta")
+		assert.Nil(t, err)
+		assert.Equal(t, &Channel{Name: "beta", Tag: "refs/tags/v1", TagID: v1TagID.String()}, channel)
+
+		err = repo.PromoteToChannel(testCtx, "beta", "v2", "", false)
+		assert.Nil(t, err)
+
+		channel, err = repo.VerifyChannel(testCtx, "beta")
+		assert.Nil(t, err)
+		assert.Equal(t, "refs/tags/v2", channel.Tag)
+		assert.Equal(t, v2TagID.String(), channel.TagID)
+	})
+
+	t.Run("promote from channel", func(t *testing.T) {
+		// v1 is no longer the tag of the beta channel
+		err := repo.PromoteToChannel(testCtx, "stable", "v1", "beta", false)
+		assert.ErrorIs(t, err, ErrTagNotInSourceChannel)
+
+		_, err = repo.VerifyChannel(testCtx, "stable")
+		assert.ErrorIs(t, err, ErrChannelNotFound)
+
+		err = repo.PromoteToChannel(testCtx, "stable", "v2", "beta", false)
+		assert.Nil(t, err)
+
+		channel, err := repo.VerifyChannel(testCtx, "stable")
+		assert.Nil(t, err)
+		assert.Equal(t, &Channel{Name: "stable", Tag: "refs/tags/v2", TagID: v2TagID.String(), PromotedFrom: "beta"}, channel)
+
+		channels, err := repo.ListChannels()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"beta", "stable"}, channels)
+	})
+
+	t.Run("promotion must be approved by policy", func(t *testing.T) {
+		targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repo.AddDelegation(testCtx, targetsSigner, policy.TargetsRoleName, "protect-stable", []*tuf.Key{gpgKey}, []string{"git:" + ChannelRefPrefix + "stable"}, 1, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := policy.Apply(testCtx, repo.r, false); err != nil {
+			t.Fatal(err)
+		}
+
+		// The promotion's RSL entry isn't signed by a key trusted for the
+		// channel
+		err = repo.PromoteToChannel(testCtx, "stable", "v1", "", false)
+		assert.Nil(t, err)
+
+		_, err = repo.VerifyChannel(testCtx, "stable")
+		assert.ErrorIs(t, err, policy.ErrUnauthorizedSignature)
+
+		// An authorized key records the promotion
+		ref, err := repo.r.Reference(plumbing.ReferenceName(ChannelRefPrefix+"stable"), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(ref.Name().String(), ref.Hash()), gpgKeyBytes)
+
+		channel, err := repo.VerifyChannel(testCtx, "stable")
+		assert.Nil(t, err)
+		assert.Equal(t, "refs/tags/v1", channel.Tag)
+	})
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ErrCheckoutNotVerified is returned when the state of a ref being checked out
+// is not the state recorded in its latest verified RSL entry, such as when the
+// ref was fetched from a remote without the corresponding RSL entries.
+var ErrCheckoutNotVerified = errors.New("ref's state is not covered by a verified RSL entry, refusing to check it out")
+
+// SafeCheckout updates the working tree to the target ref's state, only if the
+// state is covered by verified RSL entries. Remote-tracking refs such as
+// refs/remotes/origin/main are verified against the RSL entries for the
+// corresponding branch, so the RSL must be pulled from the remote first.
+// Branches are checked out as the current branch, while other refs are checked
+// out with a detached HEAD. The checkout fails if the working tree has changes
+// unless force is set, in which case the changes are discarded.
+func (r *Repository) SafeCheckout(ctx context.Context, target string, force bool) error {
+	target, commitID, err := r.verifyCheckout(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading repository worktree...")
+	worktree, err := r.r.Worktree()
+	if err != nil {
+		return err
+	}
+
+	checkoutOptions := &git.CheckoutOptions{Force: force}
+	if strings.HasPrefix(target, gitinterface.BranchRefPrefix) {
+		checkoutOptions.Branch = plumbing.ReferenceName(target)
+	} else {
+		checkoutOptions.Hash = commitID
+	}
+
+	slog.Debug(fmt.Sprintf("Checking out '%s'...", target))
+	return worktree.Checkout(checkoutOptions)
+}
+
+// VerifyCheckout checks that the target ref's state is covered by verified RSL
+// entries, without updating the working tree. It is used by the post-checkout
+// hook to verify refs checked out using Git directly.
+func (r *Repository) VerifyCheckout(ctx context.Context, target string) error {
+	_, _, err := r.verifyCheckout(ctx, target)
+	return err
+}
+
+// verifyCheckout verifies the target ref, returning its absolute name and the
+// commit it points to.
+func (r *Repository) verifyCheckout(ctx context.Context, target string) (string, plumbing.Hash, error) {
+	if err := r.VerifyRootPin(ctx); err != nil {
+		return "", plumbing.ZeroHash, err
+	}
+
+	slog.Debug("Checking for alterations to history presented by Git...")
+	if err := policy.VerifyHistoryIntegrity(ctx, r.r); err != nil {
+		return "", plumbing.ZeroHash, err
+	}
+
+	slog.Debug("Identifying absolute reference path...")
+	target, err := gitinterface.AbsoluteReference(r.r, target)
+	if err != nil {
+		return "", plumbing.ZeroHash, err
+	}
+
+	ref, err := r.r.Reference(plumbing.ReferenceName(target), true)
+	if err != nil {
+		return "", plumbing.ZeroHash, err
+	}
+
+	rslRefName, err := r.upstreamRefName(ctx, rslRefNameForCheckout(target))
+	if err != nil {
+		return "", plumbing.ZeroHash, err
+	}
+	slog.Debug(fmt.Sprintf("Verifying gittuf policies for '%s'...", rslRefName))
+	expectedTip, err := policy.VerifyRef(ctx, r.r, rslRefName)
+	if err != nil {
+		return "", plumbing.ZeroHash, err
+	}
+
+	if ref.Hash() != expectedTip {
+		return "", plumbing.ZeroHash, fmt.Errorf("%w: '%s' is at '%s' but the latest RSL entry for '%s' records '%s'", ErrCheckoutNotVerified, target, ref.Hash().String(), rslRefName, expectedTip.String())
+	}
+
+	commitID := ref.Hash()
+	if tag, err := r.r.TagObject(commitID); err == nil {
+		commit, err := tag.Commit()
+		if err != nil {
+			return "", plumbing.ZeroHash, err
+		}
+		commitID = commit.Hash
+	}
+
+	return target, commitID, nil
+}
+
+// rslRefNameForCheckout returns the ref whose RSL entries record the target's
+// state. This is the target itself, except for remote-tracking refs, which
+// are recorded in the RSL as the corresponding branch.
+func rslRefNameForCheckout(target string) string {
+	remoteRef, isRemoteRef := strings.CutPrefix(target, gitinterface.RemoteRefPrefix)
+	if !isRemoteRef {
+		return target
+	}
+
+	_, branchName, hasBranch := strings.Cut(remoteRef, "/")
+	if !hasBranch {
+		return target
+	}
+
+	return plumbing.NewBranchReferenceName(branchName).String()
+}
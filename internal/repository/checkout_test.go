@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeCheckout(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	refName := "refs/heads/main"
+	commitID := addTestCheckoutCommit(t, repo, refName, "README.md")
+	entry := rsl.NewReferenceEntry(refName, commitID)
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+
+	t.Run("verified branch", func(t *testing.T) {
+		err := repo.SafeCheckout(testCtx, "main", false)
+		assert.Nil(t, err)
+
+		head, err := repo.r.Reference(plumbing.HEAD, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, plumbing.ReferenceName(refName), head.Target())
+
+		worktree, err := repo.r.Worktree()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = worktree.Filesystem.Stat("README.md")
+		assert.Nil(t, err)
+	})
+
+	t.Run("verified remote-tracking ref", func(t *testing.T) {
+		remoteRefName := plumbing.ReferenceName("refs/remotes/origin/main")
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(remoteRefName, commitID)); err != nil {
+			t.Fatal(err)
+		}
+
+		err := repo.SafeCheckout(testCtx, remoteRefName.String(), false)
+		assert.Nil(t, err)
+
+		head, err := repo.r.Reference(plumbing.HEAD, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, commitID, head.Hash())
+	})
+
+	t.Run("fetched remote-tracking ref not in RSL", func(t *testing.T) {
+		remoteRefName := "refs/remotes/origin/main"
+		addTestCheckoutCommit(t, repo, remoteRefName, "tampered.sh")
+
+		err := repo.SafeCheckout(testCtx, remoteRefName, false)
+		assert.ErrorIs(t, err, ErrCheckoutNotVerified)
+
+		head, err := repo.r.Reference(plumbing.HEAD, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, commitID, head.Hash())
+	})
+
+	t.Run("branch not in RSL", func(t *testing.T) {
+		addTestCheckoutCommit(t, repo, "refs/heads/feature", "feature.md")
+
+		err := repo.VerifyCheckout(testCtx, "feature")
+		assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
+	})
+
+	t.Run("branch ahead of RSL", func(t *testing.T) {
+		addTestCheckoutCommit(t, repo, refName, "unrecorded.md")
+
+		err := repo.VerifyCheckout(testCtx, "main")
+		assert.ErrorIs(t, err, ErrCheckoutNotVerified)
+	})
+}
+
+// addTestCheckoutCommit adds a commit that adds the file to the ref. Unlike
+// the commits created by common.AddNTestCommitsToSpecifiedRef, the commit's
+// tree can be checked out.
+func addTestCheckoutCommit(t *testing.T, repo *Repository, refName, fileName string) plumbing.Hash {
+	t.Helper()
+
+	files := map[string]plumbing.Hash{}
+	parentIDs := []plumbing.Hash{}
+	ref, err := repo.r.Reference(plumbing.ReferenceName(refName), true)
+	if err == nil {
+		commit, err := gitinterface.GetCommit(repo.r, ref.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		tree, err := commit.Tree()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files, err = gitinterface.GetAllFilesInTree(tree)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parentIDs = append(parentIDs, commit.Hash)
+	}
+
+	blobID, err := gitinterface.WriteBlob(repo.r, []byte(fileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files[fileName] = blobID
+
+	treeID, err := gitinterface.NewTreeBuilder(repo.r).WriteRootTreeFromBlobIDs(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit := gitinterface.CreateCommitObject(common.TestGitConfig, treeID, parentIDs, "Test commit", common.TestClock)
+	commit = common.SignTestCommit(t, repo.r, commit, gpgKeyBytes)
+	commitID, err := gitinterface.WriteCommit(repo.r, commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), commitID)); err != nil {
+		t.Fatal(err)
+	}
+
+	return commitID
+}
+
+func TestRSLRefNameForCheckout(t *testing.T) {
+	assert.Equal(t, "refs/heads/main", rslRefNameForCheckout("refs/heads/main"))
+	assert.Equal(t, "refs/tags/v1", rslRefNameForCheckout("refs/tags/v1"))
+	assert.Equal(t, "refs/heads/main", rslRefNameForCheckout("refs/remotes/origin/main"))
+	assert.Equal(t, "refs/heads/feature/x", rslRefNameForCheckout("refs/remotes/origin/feature/x"))
+	assert.Equal(t, "refs/remotes/origin", rslRefNameForCheckout("refs/remotes/origin"))
+}
@@ -0,0 +1,342 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/identities"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"golang.org/x/mod/semver"
+)
+
+// nextReleaseVersion is the first gittuf release to include the features added
+// since v0.4.0.
+const nextReleaseVersion = "v0.5.0"
+
+var (
+	ErrInvalidClientVersion      = errors.New("invalid gittuf version, expected a semantic version such as v0.4.0")
+	ErrIncompatibleClientVersion = errors.New("gittuf version cannot verify the repository")
+)
+
+// compatibilityFeatures records, for each feature of gittuf's metadata that
+// older versions of gittuf cannot verify, the first version that can and how
+// older versions handle it.
+var compatibilityFeatures = map[string]struct {
+	minVersion string
+	effect     string
+}{
+	"rule-thresholds":               {"v0.4.0", "older versions don't require more than one signature for rules"},
+	"key-policy":                    {nextReleaseVersion, "older versions don't restrict key algorithms and sizes"},
+	"key-rotations":                 {nextReleaseVersion, "older versions don't trust rotated keys in place of the keys they replaced"},
+	"expired-gpg-keys":              {nextReleaseVersion, "older versions reject signatures made before GPG keys expired"},
+	"policy-approval-threshold":     {nextReleaseVersion, "older versions don't require multiple administrators to approve policy changes"},
+	"github-app":                    {nextReleaseVersion, "older versions don't count approvals recorded by the GitHub app"},
+	"rule-key-operations":           {nextReleaseVersion, "older versions trust restricted keys for all operations"},
+	"rule-rotation-schedules":       {nextReleaseVersion, "older versions trust all of a rule's keys at all times"},
+	"rule-required-tickets":         {nextReleaseVersion, "older versions don't require tickets in RSL entries"},
+	"rule-allowed-builders":         {nextReleaseVersion, "older versions don't require provenance for tags"},
+	"rule-ref-mappings":             {nextReleaseVersion, "older versions can't verify refs on mirrors using upstream RSL entries"},
+	"rule-update-types":             {nextReleaseVersion, "older versions don't restrict how branches are updated"},
+	"globstar-patterns":             {nextReleaseVersion, "older versions may match rule patterns using ** differently"},
+	"rsl-range-annotations":         {nextReleaseVersion, "older versions don't skip the entries in annotated ranges"},
+	"rsl-entry-messages":            {nextReleaseVersion, "older versions fail to parse reference entries with messages"},
+	"github-pull-request-approvals": {nextReleaseVersion, "older versions don't count pull request approvals towards thresholds"},
+	"change-set-authorizations":     {nextReleaseVersion, "older versions don't count change set authorizations towards thresholds"},
+	"automation-delegations":        {nextReleaseVersion, "older versions don't trust automation keys delegated to by policy keys"},
+	"identities":                    {nextReleaseVersion, "older versions can't map forge accounts to policy keys"},
+	"rsl-shards":                    {nextReleaseVersion, "older versions don't read the entries recorded in RSL shards"},
+}
+
+// CompatibilityFeature is a feature used by the repository's gittuf metadata
+// that older versions of gittuf cannot verify.
+type CompatibilityFeature struct {
+	// Name identifies the feature.
+	Name string
+
+	// MinVersion is the first gittuf version that can verify repositories
+	// that use the feature.
+	MinVersion string
+
+	// Effect describes how versions older than MinVersion handle the
+	// feature.
+	Effect string
+
+	// Uses lists where the feature is used, such as the names of rules or the
+	// IDs of RSL entries.
+	Uses []string
+}
+
+// CompatibilityReport lists the features used by the repository's gittuf
+// metadata that older versions of gittuf cannot verify.
+type CompatibilityReport struct {
+	// Features are sorted by MinVersion and then by Name.
+	Features []*CompatibilityFeature
+
+	// MinVersion is the first gittuf version that can verify the repository.
+	// It is empty if the repository uses no features that need a specific
+	// version.
+	MinVersion string
+}
+
+// IncompatibleFeatures returns the features in the report that the specified
+// gittuf version cannot verify.
+func (c *CompatibilityReport) IncompatibleFeatures(clientVersion string) ([]*CompatibilityFeature, error) {
+	if !semver.IsValid(clientVersion) {
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidClientVersion, clientVersion)
+	}
+
+	incompatible := []*CompatibilityFeature{}
+	for _, feature := range c.Features {
+		if semver.Compare(clientVersion, feature.MinVersion) < 0 {
+			incompatible = append(incompatible, feature)
+		}
+	}
+
+	return incompatible, nil
+}
+
+// CheckCompatibility inspects the repository's current policy, RSL,
+// attestations, and identities for features that older versions of gittuf
+// cannot verify, so that clients can be upgraded before such features are
+// adopted.
+func (r *Repository) CheckCompatibility(ctx context.Context) (*CompatibilityReport, error) {
+	uses := map[string][]string{}
+
+	slog.Debug("Checking features used by policy...")
+	if err := r.checkPolicyCompatibility(ctx, uses); err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Checking features used by RSL...")
+	if err := r.checkRSLCompatibility(uses); err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Checking features used by attestations...")
+	if err := r.checkAttestationsCompatibility(uses); err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Checking features used by identities...")
+	if err := r.checkIdentitiesCompatibility(uses); err != nil {
+		return nil, err
+	}
+
+	report := &CompatibilityReport{Features: make([]*CompatibilityFeature, 0, len(uses))}
+	for name, featureUses := range uses {
+		spec := compatibilityFeatures[name]
+		report.Features = append(report.Features, &CompatibilityFeature{
+			Name:       name,
+			MinVersion: spec.minVersion,
+			Effect:     spec.effect,
+			Uses:       featureUses,
+		})
+
+		if report.MinVersion == "" || semver.Compare(spec.minVersion, report.MinVersion) > 0 {
+			report.MinVersion = spec.minVersion
+		}
+	}
+
+	sort.Slice(report.Features, func(i, j int) bool {
+		if cmp := semver.Compare(report.Features[i].MinVersion, report.Features[j].MinVersion); cmp != 0 {
+			return cmp < 0
+		}
+		return report.Features[i].Name < report.Features[j].Name
+	})
+
+	return report, nil
+}
+
+// checkPolicyCompatibility records the features used by the root metadata and
+// the rules of the current policy.
+func (r *Repository) checkPolicyCompatibility(ctx context.Context, uses map[string][]string) error {
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		if errors.Is(err, policy.ErrPolicyNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		return err
+	}
+
+	if rootMetadata.KeyPolicy != nil {
+		uses["key-policy"] = append(uses["key-policy"], "root metadata")
+	}
+	if len(rootMetadata.KeyRotations) > 0 {
+		uses["key-rotations"] = append(uses["key-rotations"], "root metadata")
+	}
+	if rootMetadata.AllowExpiredGPGKeys {
+		uses["expired-gpg-keys"] = append(uses["expired-gpg-keys"], "root metadata")
+	}
+	if len(rootMetadata.RSLShards) > 0 {
+		uses["rsl-shards"] = append(uses["rsl-shards"], "root metadata")
+	}
+	if rootMetadata.PolicyApprovalThreshold > 1 {
+		uses["policy-approval-threshold"] = append(uses["policy-approval-threshold"], "root metadata")
+	}
+	if _, hasGitHubApp := rootMetadata.Roles[policy.GitHubAppRoleName]; hasGitHubApp {
+		uses["github-app"] = append(uses["github-app"], "root metadata")
+	}
+
+	roleNames := []string{policy.TargetsRoleName}
+	for roleName := range state.DelegationEnvelopes {
+		roleNames = append(roleNames, roleName)
+	}
+	sort.Strings(roleNames[1:])
+
+	for _, roleName := range roleNames {
+		targetsMetadata, err := state.GetTargetsMetadata(roleName)
+		if err != nil {
+			return err
+		}
+		if targetsMetadata.Delegations == nil {
+			continue
+		}
+
+		for _, rule := range targetsMetadata.Delegations.Roles {
+			if rule.Name == policy.AllowRuleName {
+				continue
+			}
+
+			for _, name := range ruleFeatures(rule) {
+				uses[name] = append(uses[name], fmt.Sprintf("rule '%s'", rule.Name))
+			}
+		}
+	}
+
+	return nil
+}
+
+// ruleFeatures returns the names of the features used by the rule.
+func ruleFeatures(rule tuf.Delegation) []string {
+	names := []string{}
+
+	if rule.Threshold > 1 {
+		names = append(names, "rule-thresholds")
+	}
+	if len(rule.KeyOperations) > 0 {
+		names = append(names, "rule-key-operations")
+	}
+	if rule.Rotation != nil {
+		names = append(names, "rule-rotation-schedules")
+	}
+	if rule.RequireTicket {
+		names = append(names, "rule-required-tickets")
+	}
+	if len(rule.AllowedBuilders) > 0 {
+		names = append(names, "rule-allowed-builders")
+	}
+	if len(rule.RefMappings) > 0 {
+		names = append(names, "rule-ref-mappings")
+	}
+	if len(rule.AllowedUpdateTypes) > 0 {
+		names = append(names, "rule-update-types")
+	}
+	for _, pattern := range rule.Paths {
+		if strings.Contains(pattern, "**") {
+			names = append(names, "globstar-patterns")
+			break
+		}
+	}
+
+	return names
+}
+
+// checkRSLCompatibility records the features used by the entries in the RSL.
+func (r *Repository) checkRSLCompatibility(uses map[string][]string) error {
+	iterator, err := rsl.NewIterator(r.r)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	for {
+		entry, err := iterator.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+
+		location := fmt.Sprintf("RSL entry %s", entry.GetID().String())
+		switch entry := entry.(type) {
+		case *rsl.ReferenceEntry:
+			if entry.Message != "" {
+				uses["rsl-entry-messages"] = append(uses["rsl-entry-messages"], location)
+			}
+		case *rsl.AnnotationEntry:
+			if entry.IsRange() {
+				uses["rsl-range-annotations"] = append(uses["rsl-range-annotations"], location)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkAttestationsCompatibility records the features used by the current
+// attestations.
+func (r *Repository) checkAttestationsCompatibility(uses map[string][]string) error {
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return err
+	}
+
+	envelopes, err := allAttestations.GetAllAttestations(r.r)
+	if err != nil {
+		return err
+	}
+
+	// Attestations are grouped by type into subtrees of the attestations tree
+	subtreeFeatures := map[string]string{
+		"github-pull-request-approvals": "github-pull-request-approvals",
+		"change-sets":                   "change-set-authorizations",
+		"automation-delegations":        "automation-delegations",
+	}
+
+	for _, attestationPath := range sortedKeys(envelopes) {
+		subtreeName, _, _ := strings.Cut(attestationPath, "/")
+		if name, has := subtreeFeatures[subtreeName]; has {
+			uses[name] = append(uses[name], fmt.Sprintf("attestation '%s'", attestationPath))
+		}
+	}
+
+	return nil
+}
+
+// checkIdentitiesCompatibility records whether identities are recorded in the
+// repository.
+func (r *Repository) checkIdentitiesCompatibility(uses map[string][]string) error {
+	allIdentities, err := identities.LoadCurrentIdentities(r.r)
+	if err != nil {
+		return err
+	}
+
+	envelopes, err := allIdentities.GetAllIdentities(r.r)
+	if err != nil {
+		return err
+	}
+
+	for _, identityPath := range sortedKeys(envelopes) {
+		uses["identities"] = append(uses["identities"], fmt.Sprintf("identity '%s'", identityPath))
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	t.Run("no features in use", func(t *testing.T) {
+		report, err := repo.CheckCompatibility(testCtx)
+		assert.Nil(t, err)
+		assert.Empty(t, report.Features)
+		assert.Empty(t, report.MinVersion)
+	})
+
+	t.Run("features in use", func(t *testing.T) {
+		refName := "refs/heads/main"
+		common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+		if err := repo.RecordRSLEntryForReferenceWithOptions(testCtx, refName, &RecordRSLEntryOptions{Message: "release"}, false); err != nil {
+			t.Fatal(err)
+		}
+		entry, err := rsl.GetLatestEntry(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		automationKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.DelegateToAutomation(testCtx, targetsSigner, automationKey, []string{refName}, time.Hour, false); err != nil {
+			t.Fatal(err)
+		}
+
+		report, err := repo.CheckCompatibility(testCtx)
+		assert.Nil(t, err)
+		assert.Equal(t, nextReleaseVersion, report.MinVersion)
+
+		names := []string{}
+		for _, feature := range report.Features {
+			names = append(names, feature.Name)
+		}
+		assert.Equal(t, []string{"automation-delegations", "rsl-entry-messages"}, names)
+		assert.Equal(t, []string{fmt.Sprintf("RSL entry %s", entry.GetID().String())}, report.Features[1].Uses)
+
+		incompatible, err := report.IncompatibleFeatures("v0.4.0")
+		assert.Nil(t, err)
+		assert.Len(t, incompatible, 2)
+
+		incompatible, err = report.IncompatibleFeatures(nextReleaseVersion)
+		assert.Nil(t, err)
+		assert.Empty(t, incompatible)
+
+		_, err = report.IncompatibleFeatures("0.4")
+		assert.ErrorIs(t, err, ErrInvalidClientVersion)
+	})
+}
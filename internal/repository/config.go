@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+)
+
+const (
+	// SigningKeyConfigKey is the Git config key used to set the signing key
+	// used by commands that accept the `--signing-key` flag when the flag is
+	// not specified.
+	SigningKeyConfigKey = "gittuf.signingkey"
+
+	// AutoRecordRSLConfigKey is the Git config key used to set whether the
+	// pre-push hook records pushed refs in the RSL. Defaults to true.
+	AutoRecordRSLConfigKey = "gittuf.rsl.autorecord"
+
+	// RSLRemoteConfigKey is the Git config key used to set the remote that
+	// gittuf metadata is synchronized with when no remote is specified.
+	RSLRemoteConfigKey = "gittuf.rsl.remote"
+
+	// RSLBackendConfigKey is the Git config key used to set the backend the
+	// RSL is mirrored into when it's updated, either the path to a local log
+	// file or the URL of a remote log.
+	RSLBackendConfigKey = "gittuf.rsl.backend"
+
+	// VerificationStrictnessConfigKey is the Git config key used to set how
+	// much of a ref's history is verified by default, one of
+	// VerificationStrictnessFull (the default) and
+	// VerificationStrictnessLatestOnly.
+	VerificationStrictnessConfigKey = "gittuf.verify.strictness"
+
+	VerificationStrictnessFull       = "full"
+	VerificationStrictnessLatestOnly = "latest-only"
+
+	// VerificationEnforcementConfigKey is the Git config key used to set how
+	// verification failures are handled by verification commands and
+	// server-side hooks, one of VerificationEnforcementEnforce (the default),
+	// VerificationEnforcementWarn, and VerificationEnforcementAudit. Warn and
+	// audit ease the staged rollout of gittuf policies.
+	VerificationEnforcementConfigKey = "gittuf.verify.enforcement"
+
+	// VerificationEnforcementEnforce fails when verification fails.
+	VerificationEnforcementEnforce = "enforce"
+
+	// VerificationEnforcementWarn reports verification failures as warnings.
+	// Commands exit with a distinct non-zero exit code, while server-side
+	// hooks accept the ref updates.
+	VerificationEnforcementWarn = "warn"
+
+	// VerificationEnforcementAudit only logs verification failures, and
+	// commands succeed.
+	VerificationEnforcementAudit = "audit"
+
+	// ExpiryGracePeriodConfigKey is the Git config key used to enforce the
+	// expiry of policy metadata when verifying refs, such as `7d`. Metadata
+	// that expired within the grace period is reported as a warning. Expiry
+	// is not enforced if this is not set.
+	ExpiryGracePeriodConfigKey = "gittuf.verify.expirygraceperiod"
+
+	// GCMaxCacheSizeConfigKey is the Git config key used to set the maximum
+	// number of updates retained in the history of the verification cache
+	// before it is compacted by `gittuf gc`. Set to 0 to disable.
+	GCMaxCacheSizeConfigKey = "gittuf.gc.maxcachesize"
+
+	// GCMaxTrackerAgeConfigKey is the Git config key used to set the age,
+	// such as `720h`, after which remote tracker refs whose latest entry is
+	// older are removed by `gittuf gc`. Set to 0 to disable.
+	GCMaxTrackerAgeConfigKey = "gittuf.gc.maxtrackerage"
+
+	DefaultGCMaxCacheSize  = 100
+	DefaultGCMaxTrackerAge = 90 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidVerificationStrictness  = errors.New("invalid verification strictness (not one of full, latest-only)")
+	ErrInvalidVerificationEnforcement = errors.New("invalid verification enforcement (not one of enforce, warn, audit)")
+	ErrInvalidExpiryGracePeriod       = errors.New("invalid expiry grace period (must not be negative)")
+	ErrRemoteNotSpecified             = fmt.Errorf("remote not specified and %s is not set", RSLRemoteConfigKey)
+)
+
+// Config contains the defaults configured for gittuf in a repository, so that
+// they need not be passed as flags to every command.
+type Config struct {
+	// SigningKey is the signing key used when `--signing-key` is not set.
+	SigningKey string
+
+	// AutoRecordRSL indicates if pushed refs are recorded in the RSL by the
+	// pre-push hook.
+	AutoRecordRSL bool
+
+	// RSLRemote is the remote gittuf metadata is synchronized with when a
+	// remote is not specified.
+	RSLRemote string
+
+	// RSLBackend is the location of the backend the RSL is mirrored into, if
+	// any.
+	RSLBackend string
+
+	// VerificationStrictness is one of VerificationStrictnessFull and
+	// VerificationStrictnessLatestOnly.
+	VerificationStrictness string
+
+	// VerificationEnforcement is one of VerificationEnforcementEnforce,
+	// VerificationEnforcementWarn, and VerificationEnforcementAudit.
+	VerificationEnforcement string
+
+	// EnforceExpiry indicates if the expiry of policy metadata is enforced
+	// when verifying refs, which is the case if a grace period is set.
+	EnforceExpiry bool
+
+	// ExpiryGracePeriod is how long after policy metadata expires that
+	// verification continues to succeed with a warning.
+	ExpiryGracePeriod time.Duration
+
+	// GC contains the retention budgets for gittuf's local state.
+	GC *GCOptions
+}
+
+// DefaultConfig returns the configuration used if gittuf is not configured
+// otherwise.
+func DefaultConfig() *Config {
+	return &Config{
+		AutoRecordRSL:           true,
+		VerificationStrictness:  VerificationStrictnessFull,
+		VerificationEnforcement: VerificationEnforcementEnforce,
+		GC: &GCOptions{
+			MaxCacheSize:  DefaultGCMaxCacheSize,
+			MaxTrackerAge: DefaultGCMaxTrackerAge,
+		},
+	}
+}
+
+// LoadConfig returns the gittuf configuration set in the Git config.
+func LoadConfig() (*Config, error) {
+	// The Git config may not be readable, such as when no config is set, in
+	// which case the defaults are used
+	gitConfig, err := gitinterface.GetConfig()
+	if err != nil {
+		return DefaultConfig(), nil //nolint:nilerr
+	}
+
+	return LoadConfigFromGitConfig(gitConfig)
+}
+
+// LoadConfigFromGitConfig returns the gittuf configuration set in the
+// specified Git config, using defaults for options that are not set.
+func LoadConfigFromGitConfig(gitConfig map[string]string) (*Config, error) {
+	config := DefaultConfig()
+
+	if signingKey, has := gitConfig[SigningKeyConfigKey]; has {
+		config.SigningKey = signingKey
+	}
+
+	if autoRecord, has := gitConfig[AutoRecordRSLConfigKey]; has {
+		value, err := strconv.ParseBool(autoRecord)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s' for %s: %w", autoRecord, AutoRecordRSLConfigKey, err)
+		}
+		config.AutoRecordRSL = value
+	}
+
+	if remote, has := gitConfig[RSLRemoteConfigKey]; has {
+		config.RSLRemote = remote
+	}
+
+	if backend, has := gitConfig[RSLBackendConfigKey]; has {
+		config.RSLBackend = backend
+	}
+
+	if strictness, has := gitConfig[VerificationStrictnessConfigKey]; has {
+		config.VerificationStrictness = strictness
+	}
+
+	if enforcement, has := gitConfig[VerificationEnforcementConfigKey]; has {
+		config.VerificationEnforcement = enforcement
+	}
+
+	if gracePeriod, has := gitConfig[ExpiryGracePeriodConfigKey]; has {
+		value, err := ParsePeriod(gracePeriod)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s' for %s: %w", gracePeriod, ExpiryGracePeriodConfigKey, err)
+		}
+		config.EnforceExpiry = true
+		config.ExpiryGracePeriod = value
+	}
+
+	if maxCacheSize, has := gitConfig[GCMaxCacheSizeConfigKey]; has {
+		value, err := strconv.Atoi(maxCacheSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s' for %s: %w", maxCacheSize, GCMaxCacheSizeConfigKey, err)
+		}
+		config.GC.MaxCacheSize = value
+	}
+
+	if maxTrackerAge, has := gitConfig[GCMaxTrackerAgeConfigKey]; has {
+		value, err := time.ParseDuration(maxTrackerAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s' for %s: %w", maxTrackerAge, GCMaxTrackerAgeConfigKey, err)
+		}
+		config.GC.MaxTrackerAge = value
+	}
+
+	return config, config.Validate()
+}
+
+// Validate checks that the configuration is valid.
+func (c *Config) Validate() error {
+	switch c.VerificationStrictness {
+	case VerificationStrictnessFull, VerificationStrictnessLatestOnly:
+	default:
+		return fmt.Errorf("%w: '%s'", ErrInvalidVerificationStrictness, c.VerificationStrictness)
+	}
+
+	if err := ValidateVerificationEnforcement(c.VerificationEnforcement); err != nil {
+		return err
+	}
+
+	if c.ExpiryGracePeriod < 0 {
+		return fmt.Errorf("%w: '%s'", ErrInvalidExpiryGracePeriod, c.ExpiryGracePeriod)
+	}
+
+	return c.GC.Validate()
+}
+
+// ValidateVerificationEnforcement checks that the verification enforcement is
+// one of the supported values.
+func ValidateVerificationEnforcement(enforcement string) error {
+	switch enforcement {
+	case VerificationEnforcementEnforce, VerificationEnforcementWarn, VerificationEnforcementAudit:
+		return nil
+	default:
+		return fmt.Errorf("%w: '%s'", ErrInvalidVerificationEnforcement, enforcement)
+	}
+}
+
+// RemoteOrDefault returns the specified remote, or the configured RSL remote if
+// none is specified.
+func (c *Config) RemoteOrDefault(remote string) (string, error) {
+	if remote != "" {
+		return remote, nil
+	}
+
+	if c.RSLRemote == "" {
+		return "", ErrRemoteNotSpecified
+	}
+
+	return c.RSLRemote, nil
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfigFromGitConfig(t *testing.T) {
+	tests := map[string]struct {
+		gitConfig      map[string]string
+		expectedConfig *Config
+		expectedError  error
+	}{
+		"no config": {
+			gitConfig:      map[string]string{},
+			expectedConfig: DefaultConfig(),
+		},
+		"all options set": {
+			gitConfig: map[string]string{
+				SigningKeyConfigKey:              "/path/to/key",
+				AutoRecordRSLConfigKey:           "false",
+				RSLRemoteConfigKey:               "upstream",
+				RSLBackendConfigKey:              "https://rsl.example.com/log",
+				VerificationStrictnessConfigKey:  VerificationStrictnessLatestOnly,
+				VerificationEnforcementConfigKey: VerificationEnforcementWarn,
+				GCMaxCacheSizeConfigKey:          "10",
+				GCMaxTrackerAgeConfigKey:         "720h",
+				ExpiryGracePeriodConfigKey:       "7d",
+				"user.name":                      "Jane Doe",
+			},
+			expectedConfig: &Config{
+				SigningKey:              "/path/to/key",
+				AutoRecordRSL:           false,
+				RSLRemote:               "upstream",
+				RSLBackend:              "https://rsl.example.com/log",
+				VerificationStrictness:  VerificationStrictnessLatestOnly,
+				VerificationEnforcement: VerificationEnforcementWarn,
+				EnforceExpiry:           true,
+				ExpiryGracePeriod:       7 * 24 * time.Hour,
+				GC:                      &GCOptions{MaxCacheSize: 10, MaxTrackerAge: 720 * time.Hour},
+			},
+		},
+		"invalid strictness": {
+			gitConfig:     map[string]string{VerificationStrictnessConfigKey: "lenient"},
+			expectedError: ErrInvalidVerificationStrictness,
+		},
+		"invalid enforcement": {
+			gitConfig:     map[string]string{VerificationEnforcementConfigKey: "block"},
+			expectedError: ErrInvalidVerificationEnforcement,
+		},
+		"negative expiry grace period": {
+			gitConfig:     map[string]string{ExpiryGracePeriodConfigKey: "-1h"},
+			expectedError: ErrInvalidExpiryGracePeriod,
+		},
+		"negative max cache size": {
+			gitConfig:     map[string]string{GCMaxCacheSizeConfigKey: "-1"},
+			expectedError: ErrInvalidGCOptions,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config, err := LoadConfigFromGitConfig(test.gitConfig)
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.expectedConfig, config)
+		})
+	}
+
+	t.Run("invalid autorecord", func(t *testing.T) {
+		_, err := LoadConfigFromGitConfig(map[string]string{AutoRecordRSLConfigKey: "sometimes"})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("invalid max tracker age", func(t *testing.T) {
+		_, err := LoadConfigFromGitConfig(map[string]string{GCMaxTrackerAgeConfigKey: "90 days"})
+		assert.NotNil(t, err)
+	})
+}
+
+func TestConfigRemoteOrDefault(t *testing.T) {
+	config := DefaultConfig()
+
+	_, err := config.RemoteOrDefault("")
+	assert.ErrorIs(t, err, ErrRemoteNotSpecified)
+
+	remote, err := config.RemoteOrDefault("origin")
+	assert.Nil(t, err)
+	assert.Equal(t, "origin", remote)
+
+	config.RSLRemote = "upstream"
+	remote, err = config.RemoteOrDefault("")
+	assert.Nil(t, err)
+	assert.Equal(t, "upstream", remote)
+
+	remote, err = config.RemoteOrDefault("origin")
+	assert.Nil(t, err)
+	assert.Equal(t, "origin", remote)
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"filippo.io/age"
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// AddCustomAttestation records an attestation with a custom predicate, such as
+// internal audit notes, for the latest RSL entry of the specified ref. The
+// attestation must be signed using a key trusted for the ref in the current
+// policy. If the IDs of keys in the current policy are specified in
+// encryptFor, the predicate is encrypted so that only the holders of those
+// keys can read it.
+func (r *Repository) AddCustomAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, target, predicateType string, predicate map[string]any, encryptFor []string, signCommit bool) error {
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	refName, err := gitinterface.AbsoluteReference(r.r, target)
+	if err != nil {
+		return err
+	}
+
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, refName)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Creating custom attestation...")
+	statement, err := attestations.NewCustomAttestation(refName, entry.ID.String(), entry.TargetID.String(), predicateType, predicate)
+	if err != nil {
+		return err
+	}
+
+	decryptedEnv, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		return err
+	}
+	env := decryptedEnv
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		return err
+	}
+
+	if len(encryptFor) > 0 {
+		slog.Debug("Encrypting custom attestation...")
+		recipients, err := getEncryptionKeys(state, encryptFor)
+		if err != nil {
+			return err
+		}
+
+		env, err = dsse.EncryptEnvelope(decryptedEnv, recipients)
+		if err != nil {
+			return err
+		}
+	}
+
+	slog.Debug(fmt.Sprintf("Signing custom attestation using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Checking custom attestation is signed by a key trusted for the ref...")
+	if err := state.VerifyCustomAttestation(ctx, refName, env); err != nil {
+		return err
+	}
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return err
+	}
+
+	if dsse.IsEncrypted(env) {
+		err = allAttestations.SetEncryptedCustomAttestation(r.r, env, decryptedEnv, refName, entry.ID.String())
+	} else {
+		err = allAttestations.SetCustomAttestation(r.r, env, refName, entry.ID.String())
+	}
+	if err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add custom attestation for '%s' at '%s'\n\nPredicate type: %s\n", refName, entry.ID.String(), predicateType)
+
+	slog.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// GetCustomAttestation returns the predicate of the custom attestation with the
+// predicate type recorded for the latest RSL entry of the specified ref. The
+// attestation must be signed by a key trusted for the ref in the current
+// policy. An encrypted predicate is decrypted using the identity, which may be
+// nil otherwise.
+func (r *Repository) GetCustomAttestation(ctx context.Context, target, predicateType string, identity age.Identity) (map[string]any, error) {
+	refName, err := gitinterface.AbsoluteReference(r.r, target)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, refName)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Loading custom attestation...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := allAttestations.GetCustomAttestationFor(r.r, refName, entry.ID.String(), predicateType, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Verifying signature on custom attestation...")
+	if err := state.VerifyCustomAttestation(ctx, refName, env); err != nil {
+		return nil, err
+	}
+
+	return attestations.GetCustomPredicate(env, identity)
+}
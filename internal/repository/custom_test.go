@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomAttestation(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	reviewerSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(artifacts.SSLibKey3Private) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	reviewerKey, err := tuf.LoadKeyFromBytes(artifacts.SSLibKey3Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The auditor's key is only used to decrypt predicates
+	auditorIdentity, err := dsse.LoadDecryptionKey(artifacts.SSHED25519Private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditorKey := createTestSSHKey(t, artifacts.SSHED25519PublicSSH)
+
+	if err := repo.AddDelegation(testCtx, targetsSigner, policy.TargetsRoleName, "protect-release", []*tuf.Key{reviewerKey, auditorKey}, []string{"git:refs/heads/release"}, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := policy.Apply(testCtx, repo.r, false); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/release"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+
+	predicateType := "https://example.com/audit-notes/v1"
+	predicate := map[string]any{"notes": "sensitive audit notes"}
+
+	// Attestation doesn't exist yet
+	_, err = repo.GetCustomAttestation(testCtx, refName, predicateType, nil)
+	assert.ErrorIs(t, err, attestations.ErrCustomAttestationNotFound)
+
+	// Only keys trusted for the ref can attest to it
+	err = repo.AddCustomAttestation(testCtx, targetsSigner, refName, predicateType, predicate, nil, false)
+	assert.ErrorIs(t, err, policy.ErrCustomAttestationNotSignedByTrustedKey)
+
+	err = repo.AddCustomAttestation(testCtx, reviewerSigner, refName, attestations.BuildEnvironmentPredicateType, predicate, nil, false)
+	assert.ErrorIs(t, err, attestations.ErrInvalidCustomPredicate)
+
+	err = repo.AddCustomAttestation(testCtx, reviewerSigner, refName, predicateType, predicate, nil, false)
+	assert.Nil(t, err)
+
+	storedPredicate, err := repo.GetCustomAttestation(testCtx, refName, predicateType, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, predicate, storedPredicate)
+
+	// A new entry for the ref has no recorded attestation
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+	entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+
+	_, err = repo.GetCustomAttestation(testCtx, refName, predicateType, nil)
+	assert.ErrorIs(t, err, attestations.ErrCustomAttestationNotFound)
+
+	t.Run("encrypted custom attestation", func(t *testing.T) {
+		err := repo.AddCustomAttestation(testCtx, reviewerSigner, refName, predicateType, predicate, []string{"unknown-key"}, false)
+		assert.ErrorIs(t, err, ErrEncryptionKeyNotInPolicy)
+
+		// GPG keys can't be encrypted for
+		gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = repo.AddCustomAttestation(testCtx, reviewerSigner, refName, predicateType, predicate, []string{gpgKey.KeyID}, false)
+		assert.ErrorIs(t, err, dsse.ErrUnsupportedEncryptionKey)
+
+		err = repo.AddCustomAttestation(testCtx, reviewerSigner, refName, predicateType, predicate, []string{auditorKey.KeyID}, false)
+		assert.Nil(t, err)
+
+		// The predicate isn't disclosed in the attestations
+		allAttestations, err := attestations.LoadCurrentAttestations(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo.r, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := allAttestations.GetCustomAttestationFor(repo.r, refName, latestEntry.ID.String(), predicateType, auditorIdentity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, dsse.IsEncrypted(env))
+		assert.NotContains(t, env.Payload, base64.StdEncoding.EncodeToString([]byte("sensitive audit notes")))
+		attestationsRef, err := repo.r.Reference(plumbing.ReferenceName(attestations.Ref), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		attestationsCommit, err := repo.r.CommitObject(attestationsRef.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.NotContains(t, attestationsCommit.Message, "sensitive audit notes")
+
+		_, err = repo.GetCustomAttestation(testCtx, refName, predicateType, nil)
+		assert.ErrorIs(t, err, dsse.ErrEnvelopeEncrypted)
+
+		otherIdentity, err := dsse.LoadDecryptionKey(artifacts.SSHRSAPrivate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = repo.GetCustomAttestation(testCtx, refName, predicateType, otherIdentity)
+		assert.ErrorIs(t, err, dsse.ErrNotEncryptedForKey)
+
+		storedPredicate, err := repo.GetCustomAttestation(testCtx, refName, predicateType, auditorIdentity)
+		assert.Nil(t, err)
+		assert.Equal(t, predicate, storedPredicate)
+	})
+}
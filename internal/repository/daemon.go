@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var ErrInvalidDaemonOptions = errors.New("invalid daemon options, refs must be specified, the interval must be positive, and the debounce must not be negative")
+
+// DaemonOptions configures how the daemon watches refs for changes.
+type DaemonOptions struct {
+	// RefNames are the refs watched. Refs that don't exist yet are watched
+	// once they're created.
+	RefNames []string
+
+	// Interval is how often the refs are checked for changes.
+	Interval time.Duration
+
+	// Debounce is how long a ref must remain unchanged before its new target
+	// is recorded, so that a burst of changes, such as during a rebase,
+	// results in a single entry.
+	Debounce time.Duration
+
+	// DryRun reports the changes that would be recorded without recording
+	// them.
+	DryRun bool
+}
+
+// Validate checks that the options are valid.
+func (o *DaemonOptions) Validate() error {
+	if len(o.RefNames) == 0 || o.Interval <= 0 || o.Debounce < 0 {
+		return ErrInvalidDaemonOptions
+	}
+
+	return nil
+}
+
+// DaemonRecord is a change to a watched ref that the daemon recorded in the
+// RSL, or would record in a dry run.
+type DaemonRecord struct {
+	RefName  string
+	TargetID plumbing.Hash
+	Recorded bool
+}
+
+// RunDaemon watches the specified refs and records an RSL entry for a ref when
+// it changes locally and has remained unchanged for the debounce period. fn is
+// invoked for each change recorded. Refs are polled until the context is
+// cancelled, when nil is returned.
+func (r *Repository) RunDaemon(ctx context.Context, options *DaemonOptions, signCommit bool, fn func(*DaemonRecord)) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	d := newDaemon(r, options, signCommit)
+
+	ticker := time.NewTicker(options.Interval)
+	defer ticker.Stop()
+
+	for {
+		records, err := d.poll(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			fn(record)
+		}
+		if len(records) != 0 && !options.DryRun {
+			r.MirrorRSL(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// observedTarget is the target of a ref and when it was first observed.
+type observedTarget struct {
+	targetID   plumbing.Hash
+	observedAt time.Time
+}
+
+// daemon tracks the state of the watched refs between polls.
+type daemon struct {
+	repo       *Repository
+	options    *DaemonOptions
+	signCommit bool
+
+	// observed records the current target of each existing watched ref.
+	observed map[string]*observedTarget
+
+	// reported records the target last reported for each ref, so that a
+	// change is reported once in a dry run.
+	reported map[string]plumbing.Hash
+}
+
+func newDaemon(repo *Repository, options *DaemonOptions, signCommit bool) *daemon {
+	return &daemon{
+		repo:       repo,
+		options:    options,
+		signCommit: signCommit,
+		observed:   map[string]*observedTarget{},
+		reported:   map[string]plumbing.Hash{},
+	}
+}
+
+// poll checks the watched refs for changes at the specified time, recording
+// the changes that have settled.
+func (d *daemon) poll(ctx context.Context, now time.Time) ([]*DaemonRecord, error) {
+	records := []*DaemonRecord{}
+	for _, refName := range d.options.RefNames {
+		absRefName, err := gitinterface.AbsoluteReference(d.repo.r, refName)
+		if err != nil {
+			// The ref doesn't exist yet
+			continue
+		}
+
+		ref, err := d.repo.r.Reference(plumbing.ReferenceName(absRefName), true)
+		if err != nil {
+			if errors.Is(err, plumbing.ErrReferenceNotFound) {
+				delete(d.observed, absRefName)
+				continue
+			}
+			return nil, err
+		}
+
+		observed, has := d.observed[absRefName]
+		if !has || observed.targetID != ref.Hash() {
+			slog.Debug(fmt.Sprintf("Observed '%s' at '%s'...", absRefName, ref.Hash().String()))
+			observed = &observedTarget{targetID: ref.Hash(), observedAt: now}
+			d.observed[absRefName] = observed
+		}
+
+		if now.Sub(observed.observedAt) < d.options.Debounce || d.reported[absRefName] == observed.targetID {
+			continue
+		}
+
+		isDuplicate, err := d.repo.isDuplicateEntry(absRefName, observed.targetID)
+		if err != nil {
+			return nil, err
+		}
+		if isDuplicate {
+			d.reported[absRefName] = observed.targetID
+			continue
+		}
+
+		record := &DaemonRecord{RefName: absRefName, TargetID: observed.targetID}
+		if !d.options.DryRun {
+			slog.Debug(fmt.Sprintf("Recording '%s' at '%s'...", absRefName, observed.targetID.String()))
+			if err := d.repo.RecordRSLEntryForReference(ctx, absRefName, d.signCommit); err != nil {
+				return nil, err
+			}
+			record.Recorded = true
+		}
+		d.reported[absRefName] = observed.targetID
+		records = append(records, record)
+	}
+
+	return records, nil
+}
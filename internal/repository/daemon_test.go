@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDaemonOptionsValidate(t *testing.T) {
+	assert.Nil(t, (&DaemonOptions{RefNames: []string{"main"}, Interval: time.Second}).Validate())
+	assert.ErrorIs(t, (&DaemonOptions{Interval: time.Second}).Validate(), ErrInvalidDaemonOptions)
+	assert.ErrorIs(t, (&DaemonOptions{RefNames: []string{"main"}}).Validate(), ErrInvalidDaemonOptions)
+	assert.ErrorIs(t, (&DaemonOptions{RefNames: []string{"main"}, Interval: time.Second, Debounce: -time.Second}).Validate(), ErrInvalidDaemonOptions)
+}
+
+func TestDaemonPoll(t *testing.T) {
+	setRef := func(t *testing.T, repo *Repository, refName string, target plumbing.Hash) {
+		t.Helper()
+
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), target)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	createRepo := func(t *testing.T) *Repository {
+		t.Helper()
+
+		r, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		repo := &Repository{r: r}
+		if err := rsl.InitializeNamespace(repo.r); err != nil {
+			t.Fatal(err)
+		}
+
+		return repo
+	}
+
+	refName := "refs/heads/main"
+	firstTarget := plumbing.NewHash("abcdef1234567890")
+	secondTarget := plumbing.NewHash("1234567890abcdef")
+	start := time.Now()
+
+	t.Run("record after debounce", func(t *testing.T) {
+		repo := createRepo(t)
+		d := newDaemon(repo, &DaemonOptions{RefNames: []string{"main", "feature"}, Interval: time.Second, Debounce: 5 * time.Second}, false)
+
+		setRef(t, repo, refName, firstTarget)
+		records, err := d.poll(testCtx, start)
+		assert.Nil(t, err)
+		assert.Empty(t, records)
+
+		// The ref changes again before the debounce period ends
+		setRef(t, repo, refName, secondTarget)
+		records, err = d.poll(testCtx, start.Add(3*time.Second))
+		assert.Nil(t, err)
+		assert.Empty(t, records)
+
+		records, err = d.poll(testCtx, start.Add(6*time.Second))
+		assert.Nil(t, err)
+		assert.Empty(t, records)
+
+		records, err = d.poll(testCtx, start.Add(8*time.Second))
+		assert.Nil(t, err)
+		assert.Equal(t, []*DaemonRecord{{RefName: refName, TargetID: secondTarget, Recorded: true}}, records)
+
+		entry, _, err := rsl.GetLatestReferenceEntryForRef(repo.r, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, secondTarget, entry.TargetID)
+
+		// The change is recorded once
+		records, err = d.poll(testCtx, start.Add(10*time.Second))
+		assert.Nil(t, err)
+		assert.Empty(t, records)
+	})
+
+	t.Run("already recorded", func(t *testing.T) {
+		repo := createRepo(t)
+		d := newDaemon(repo, &DaemonOptions{RefNames: []string{refName}, Interval: time.Second}, false)
+
+		setRef(t, repo, refName, firstTarget)
+		if err := repo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		records, err := d.poll(testCtx, start)
+		assert.Nil(t, err)
+		assert.Empty(t, records)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		repo := createRepo(t)
+		d := newDaemon(repo, &DaemonOptions{RefNames: []string{refName}, Interval: time.Second, DryRun: true}, false)
+
+		setRef(t, repo, refName, firstTarget)
+		records, err := d.poll(testCtx, start)
+		assert.Nil(t, err)
+		assert.Equal(t, []*DaemonRecord{{RefName: refName, TargetID: firstTarget}}, records)
+
+		_, _, err = rsl.GetLatestReferenceEntryForRef(repo.r, refName)
+		assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
+
+		// The change is reported once
+		records, err = d.poll(testCtx, start.Add(time.Second))
+		assert.Nil(t, err)
+		assert.Empty(t, records)
+	})
+}
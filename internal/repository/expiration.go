@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var (
+	ErrPolicyExpired = errors.New("policy metadata has expired")
+	ErrInvalidPeriod = errors.New("invalid period (must be a duration such as 90d or 720h)")
+)
+
+// CheckPolicyExpiration returns when each metadata file in the current policy
+// expires, starting with the root and top level targets metadata, followed by
+// delegated metadata sorted by role name. This allows scheduled jobs to alert
+// administrators before the policy must be renewed using
+// ExtendPolicyExpiration.
+func (r *Repository) CheckPolicyExpiration(ctx context.Context) ([]*MetadataStatus, error) {
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		return nil, err
+	}
+	rootStatus, err := newMetadataStatus(policy.RootRoleName, rootMetadata.Expires)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []*MetadataStatus{rootStatus}
+
+	roleNames := []string{}
+	if state.HasTargetsRole(policy.TargetsRoleName) {
+		roleNames = append(roleNames, policy.TargetsRoleName)
+	}
+	delegatedRoleNames := []string{}
+	for roleName := range state.DelegationEnvelopes {
+		delegatedRoleNames = append(delegatedRoleNames, roleName)
+	}
+	slices.Sort(delegatedRoleNames)
+	roleNames = append(roleNames, delegatedRoleNames...)
+
+	for _, roleName := range roleNames {
+		targetsMetadata, err := state.GetTargetsMetadata(roleName)
+		if err != nil {
+			return nil, err
+		}
+		metadataStatus, err := newMetadataStatus(roleName, targetsMetadata.Expires)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, metadataStatus)
+	}
+
+	return statuses, nil
+}
+
+// VerifyPolicyExpiration checks that no metadata file in the current policy
+// expired more than the grace period ago. Metadata that expired within the
+// grace period is reported as a warning, giving administrators time to renew
+// it before verification fails.
+func (r *Repository) VerifyPolicyExpiration(ctx context.Context, gracePeriod time.Duration) error {
+	if gracePeriod < 0 {
+		return fmt.Errorf("%w: '%s'", ErrInvalidExpiryGracePeriod, gracePeriod)
+	}
+
+	statuses, err := r.CheckPolicyExpiration(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, status := range statuses {
+		if !status.Expires.Before(now) {
+			continue
+		}
+
+		if status.Expires.Add(gracePeriod).Before(now) {
+			return fmt.Errorf("%w: '%s' expired at %s", ErrPolicyExpired, status.RoleName, status.Expires.Format(time.RFC3339))
+		}
+
+		slog.Warn(fmt.Sprintf("Metadata for '%s' expired at %s and must be renewed before the grace period ends at %s", status.RoleName, status.Expires.Format(time.RFC3339), status.Expires.Add(gracePeriod).Format(time.RFC3339)))
+	}
+
+	return nil
+}
+
+// ExtendPolicyExpiration sets the expiry of the specified role's metadata to
+// the period from now and re-signs it using the signer. As the metadata is
+// modified, any existing signatures are discarded, and other signatures needed
+// to meet the role's threshold must be added again.
+func (r *Repository) ExtendPolicyExpiration(ctx context.Context, signer sslibdsse.SignerVerifier, roleName string, period time.Duration, signCommit bool) error {
+	if period <= 0 {
+		return ErrInvalidPeriod
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	expires := time.Now().Add(period).UTC().Format(time.RFC3339)
+	commitMessage := fmt.Sprintf("Extend expiration of '%s' to %s", roleName, expires)
+
+	if roleName == policy.RootRoleName {
+		rootMetadata, err := r.loadRootMetadata(state, keyID)
+		if err != nil {
+			return err
+		}
+
+		slog.Debug(fmt.Sprintf("Setting expiration of root to %s...", expires))
+		rootMetadata.SetExpires(expires)
+
+		return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
+	}
+
+	if !state.HasTargetsRole(roleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(roleName)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Setting expiration of '%s' to %s...", roleName, expires))
+	targetsMetadata.SetExpires(expires)
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if roleName == policy.TargetsRoleName {
+		state.TargetsEnvelope = env
+	} else {
+		state.DelegationEnvelopes[roleName] = env
+	}
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, signCommit)
+}
+
+// ParsePeriod parses a period such as the one metadata expiration is extended
+// by. In addition to Go duration strings such as `720h`, a number of days such
+// as `90d` is accepted.
+func ParsePeriod(value string) (time.Duration, error) {
+	var (
+		period time.Duration
+		err    error
+	)
+	if days, isDays := strings.CutSuffix(value, "d"); isDays {
+		var count int
+		count, err = strconv.Atoi(days)
+		period = time.Duration(count) * 24 * time.Hour
+	} else {
+		period, err = time.ParseDuration(value)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%w: '%s'", ErrInvalidPeriod, value)
+	}
+
+	return period, nil
+}
+
+func newMetadataStatus(roleName, expires string) (*MetadataStatus, error) {
+	expiresTime, err := time.Parse(time.RFC3339, expires)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse expiry of '%s' metadata: %w", roleName, err)
+	}
+
+	return &MetadataStatus{RoleName: roleName, Expires: expiresTime}, nil
+}
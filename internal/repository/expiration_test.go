@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPolicyExpiration(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	statuses, err := repo.CheckPolicyExpiration(testCtx)
+	assert.Nil(t, err)
+
+	roleNames := []string{}
+	for _, status := range statuses {
+		roleNames = append(roleNames, status.RoleName)
+		assert.True(t, status.Expires.After(time.Now().AddDate(0, 11, 0)))
+	}
+	assert.Equal(t, []string{policy.RootRoleName, policy.TargetsRoleName}, roleNames)
+}
+
+func TestExtendPolicyExpiration(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("extend root and targets", func(t *testing.T) {
+		err := repo.ExtendPolicyExpiration(testCtx, rootSigner, policy.RootRoleName, 2*365*24*time.Hour, false)
+		assert.Nil(t, err)
+		err = repo.ExtendPolicyExpiration(testCtx, targetsSigner, policy.TargetsRoleName, 90*24*time.Hour, false)
+		assert.Nil(t, err)
+
+		if err := policy.Apply(testCtx, repo.r, false); err != nil {
+			t.Fatal(err)
+		}
+
+		statuses, err := repo.CheckPolicyExpiration(testCtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.WithinDuration(t, time.Now().Add(2*365*24*time.Hour), statuses[0].Expires, time.Minute)
+		assert.WithinDuration(t, time.Now().Add(90*24*time.Hour), statuses[1].Expires, time.Minute)
+	})
+
+	t.Run("unauthorized root key", func(t *testing.T) {
+		err := repo.ExtendPolicyExpiration(testCtx, targetsSigner, policy.RootRoleName, time.Hour, false)
+		assert.ErrorIs(t, err, ErrUnauthorizedKey)
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		err := repo.ExtendPolicyExpiration(testCtx, targetsSigner, "missing", time.Hour, false)
+		assert.ErrorIs(t, err, policy.ErrMetadataNotFound)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		err := repo.ExtendPolicyExpiration(testCtx, targetsSigner, policy.TargetsRoleName, 0, false)
+		assert.ErrorIs(t, err, ErrInvalidPeriod)
+	})
+}
+
+func TestVerifyPolicyExpiration(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	t.Run("policy not expired", func(t *testing.T) {
+		err := repo.VerifyPolicyExpiration(testCtx, 0)
+		assert.Nil(t, err)
+	})
+
+	// Expire the targets metadata two days ago
+	state, err := policy.LoadCurrentState(testCtx, repo.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata.SetExpires(time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339))
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope, err = dsse.SignEnvelope(testCtx, env, targetsSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Commit(repo.r, "Expire targets", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := policy.Apply(testCtx, repo.r, false); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("policy expired within grace period", func(t *testing.T) {
+		err := repo.VerifyPolicyExpiration(testCtx, 7*24*time.Hour)
+		assert.Nil(t, err)
+	})
+
+	t.Run("policy expired beyond grace period", func(t *testing.T) {
+		err := repo.VerifyPolicyExpiration(testCtx, 24*time.Hour)
+		assert.ErrorIs(t, err, ErrPolicyExpired)
+
+		err = repo.VerifyPolicyExpiration(testCtx, 0)
+		assert.ErrorIs(t, err, ErrPolicyExpired)
+	})
+
+	t.Run("negative grace period", func(t *testing.T) {
+		err := repo.VerifyPolicyExpiration(testCtx, -time.Hour)
+		assert.ErrorIs(t, err, ErrInvalidExpiryGracePeriod)
+	})
+}
+
+func TestParsePeriod(t *testing.T) {
+	tests := map[string]struct {
+		value          string
+		expectedPeriod time.Duration
+		expectedError  error
+	}{
+		"days":             {value: "90d", expectedPeriod: 90 * 24 * time.Hour},
+		"duration":         {value: "720h", expectedPeriod: 720 * time.Hour},
+		"zero days":        {value: "0d", expectedPeriod: 0},
+		"invalid days":     {value: "ninetyd", expectedError: ErrInvalidPeriod},
+		"invalid duration": {value: "90 days", expectedError: ErrInvalidPeriod},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			period, err := ParsePeriod(test.value)
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.expectedPeriod, period)
+		})
+	}
+}
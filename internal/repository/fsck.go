@@ -0,0 +1,365 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	FsckCheckRSL            = "rsl"
+	FsckCheckPolicy         = "policy"
+	FsckCheckAttestations   = "attestations"
+	FsckCheckRemoteTrackers = "remote-trackers"
+
+	remoteTrackerInfix = "/gittuf/"
+)
+
+// FsckIssue records a single problem found when checking the integrity of the
+// repository's gittuf metadata.
+type FsckIssue struct {
+	// Check is one of FsckCheckRSL, FsckCheckPolicy, FsckCheckAttestations,
+	// or FsckCheckRemoteTrackers.
+	Check string
+
+	// Name identifies what the issue was found in: an RSL entry ID, a ref,
+	// or an attestation's path.
+	Name string
+
+	// Problem describes the issue.
+	Problem string
+
+	// Repairable indicates the issue can be repaired automatically.
+	Repairable bool
+
+	// Repaired indicates the issue was repaired.
+	Repaired bool
+}
+
+// Fsck checks the structural integrity of the repository's gittuf metadata. It
+// checks that the RSL is an unbroken chain of valid entries, that every policy
+// and attestations state recorded in the RSL is reachable from the latest one,
+// that every attestation can be parsed and matches the path it is stored at,
+// and that no remote tracker refs exist for remotes that have been removed.
+// Signatures are not verified, use verify-ref for that. If repair is set,
+// issues that can be repaired safely are fixed: dangling remote tracker refs
+// are deleted and gittuf refs are reset to the state recorded in the RSL.
+func (r *Repository) Fsck(ctx context.Context, repair bool) ([]*FsckIssue, error) {
+	slog.Debug("Checking RSL...")
+	entries, issues, err := r.fsckRSL()
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Checking policy states...")
+	policyIssues, err := r.fsckNamespace(FsckCheckPolicy, policy.PolicyRef, entries, repair)
+	if err != nil {
+		return nil, err
+	}
+	issues = append(issues, policyIssues...)
+
+	if latestEntry := latestReferenceEntryForRef(entries, policy.PolicyRef); latestEntry != nil {
+		if _, err := policy.LoadState(ctx, r.r, latestEntry); err != nil {
+			issues = append(issues, &FsckIssue{Check: FsckCheckPolicy, Name: latestEntry.ID.String(), Problem: fmt.Sprintf("unable to load policy state: %s", err.Error())})
+		}
+	}
+
+	slog.Debug("Checking attestations...")
+	attestationsIssues, err := r.fsckNamespace(FsckCheckAttestations, attestations.Ref, entries, repair)
+	if err != nil {
+		return nil, err
+	}
+	issues = append(issues, attestationsIssues...)
+	issues = append(issues, r.fsckAttestations(entries)...)
+
+	slog.Debug("Checking remote tracker refs...")
+	trackerIssues, err := r.fsckRemoteTrackers(repair)
+	if err != nil {
+		return nil, err
+	}
+	issues = append(issues, trackerIssues...)
+
+	return issues, nil
+}
+
+// fsckRSL walks the RSL from its tip, checking that every entry can be parsed,
+// that the RSL has not branched, that annotations only refer to entries that
+// precede them, and that the links recorded in entries match the RSL. The
+// entries that could be parsed are returned, starting with the latest.
+func (r *Repository) fsckRSL() ([]rsl.Entry, []*FsckIssue, error) {
+	issues := []*FsckIssue{}
+
+	ref, err := r.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			issues = append(issues, &FsckIssue{Check: FsckCheckRSL, Name: rsl.Ref, Problem: "RSL does not exist"})
+			return nil, issues, nil
+		}
+		return nil, nil, err
+	}
+
+	var (
+		entries     = []rsl.Entry{}
+		annotations = []*rsl.AnnotationEntry{}
+		// positions records how far each entry is from the tip of the RSL
+		positions = map[plumbing.Hash]int{}
+		currentID = ref.Hash()
+		complete  = true
+	)
+
+	for position := 0; ; position++ {
+		commit, err := gitinterface.GetCommit(r.r, currentID)
+		if err != nil {
+			issues = append(issues, &FsckIssue{Check: FsckCheckRSL, Name: currentID.String(), Problem: "RSL entry is missing from the object store"})
+			complete = false
+			break
+		}
+		positions[currentID] = position
+
+		entry, err := rsl.GetEntry(r.r, currentID)
+		if err != nil {
+			issues = append(issues, &FsckIssue{Check: FsckCheckRSL, Name: currentID.String(), Problem: fmt.Sprintf("unable to parse RSL entry: %s", err.Error())})
+		} else {
+			entries = append(entries, entry)
+			if annotation, isAnnotation := entry.(*rsl.AnnotationEntry); isAnnotation {
+				annotations = append(annotations, annotation)
+			}
+		}
+
+		if len(commit.ParentHashes) == 0 {
+			break
+		}
+		if len(commit.ParentHashes) > 1 {
+			// We continue along the first parent to check as much of the RSL
+			// as possible
+			issues = append(issues, &FsckIssue{Check: FsckCheckRSL, Name: currentID.String(), Problem: "RSL entry has multiple parents, the RSL has branched"})
+		}
+		currentID = commit.ParentHashes[0]
+	}
+
+	for _, annotation := range annotations {
+		for _, entryID := range annotation.RSLEntryIDs {
+			if position, has := positions[entryID]; !has || position <= positions[annotation.ID] {
+				issues = append(issues, &FsckIssue{Check: FsckCheckRSL, Name: annotation.ID.String(), Problem: fmt.Sprintf("annotation refers to entry '%s' that does not precede it in the RSL", entryID.String())})
+			}
+		}
+	}
+
+	if complete {
+		// Entries can only be numbered if the entire RSL was walked
+		issues = append(issues, fsckLinks(entries, positions)...)
+	}
+
+	return entries, issues, nil
+}
+
+// fsckLinks checks that the links recorded in entries match the RSL, as lookups
+// that use incorrect links may miss entries. entries are ordered starting with
+// the latest, and positions records how far each entry is from the tip of the
+// RSL.
+func fsckLinks(entries []rsl.Entry, positions map[plumbing.Hash]int) []*FsckIssue {
+	issues := []*FsckIssue{}
+
+	var (
+		latestEntryIDs       = map[string]plumbing.Hash{}
+		previousAnnotationID = plumbing.ZeroHash
+	)
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		referenceEntry, isReferenceEntry := entry.(*rsl.ReferenceEntry)
+
+		if links := entry.GetLinks(); links != nil {
+			expectedLinks := &rsl.Links{
+				Number:               len(positions) - positions[entry.GetID()],
+				PreviousAnnotationID: previousAnnotationID,
+			}
+			if isReferenceEntry {
+				expectedLinks.PreviousEntryID = latestEntryIDs[referenceEntry.RefName]
+			}
+			if links.Checkpoint != nil {
+				expectedLinks.Checkpoint = maps.Clone(latestEntryIDs)
+			}
+
+			if links.Number != expectedLinks.Number || links.PreviousEntryID != expectedLinks.PreviousEntryID || links.PreviousAnnotationID != expectedLinks.PreviousAnnotationID || !maps.Equal(links.Checkpoint, expectedLinks.Checkpoint) {
+				issues = append(issues, &FsckIssue{Check: FsckCheckRSL, Name: entry.GetID().String(), Problem: "RSL entry's links do not match the RSL"})
+			}
+		}
+
+		if isReferenceEntry {
+			latestEntryIDs[referenceEntry.RefName] = referenceEntry.ID
+		}
+		if _, isAnnotation := entry.(*rsl.AnnotationEntry); isAnnotation {
+			previousAnnotationID = entry.GetID()
+		}
+	}
+
+	return issues
+}
+
+// fsckNamespace checks that the ref of a gittuf namespace matches the latest
+// RSL entry for it, and that every state of the namespace recorded in the RSL
+// is reachable from the latest one.
+func (r *Repository) fsckNamespace(check, refName string, entries []rsl.Entry, repair bool) ([]*FsckIssue, error) {
+	issues := []*FsckIssue{}
+
+	latestEntry := latestReferenceEntryForRef(entries, refName)
+	if latestEntry == nil {
+		return issues, nil
+	}
+
+	latestCommit, err := gitinterface.GetCommit(r.r, latestEntry.TargetID)
+	if err != nil {
+		issues = append(issues, &FsckIssue{Check: check, Name: latestEntry.ID.String(), Problem: fmt.Sprintf("state '%s' is missing from the object store", latestEntry.TargetID.String())})
+		return issues, nil
+	}
+
+	ref, err := r.r.Reference(plumbing.ReferenceName(refName), true)
+	if err != nil && !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, err
+	}
+	if ref == nil || ref.Hash() != latestEntry.TargetID {
+		issue := &FsckIssue{Check: check, Name: refName, Problem: fmt.Sprintf("ref does not match state '%s' recorded in the RSL", latestEntry.TargetID.String()), Repairable: true}
+		if repair {
+			slog.Debug(fmt.Sprintf("Resetting '%s' to '%s'...", refName, latestEntry.TargetID.String()))
+			if err := r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), latestEntry.TargetID)); err != nil {
+				return nil, err
+			}
+			issue.Repaired = true
+		}
+		issues = append(issues, issue)
+	}
+
+	for _, entry := range entries {
+		entry, isReferenceEntry := entry.(*rsl.ReferenceEntry)
+		if !isReferenceEntry || entry.RefName != refName || entry.ID == latestEntry.ID {
+			continue
+		}
+
+		commit, err := gitinterface.GetCommit(r.r, entry.TargetID)
+		if err != nil {
+			issues = append(issues, &FsckIssue{Check: check, Name: entry.ID.String(), Problem: fmt.Sprintf("state '%s' is missing from the object store", entry.TargetID.String())})
+			continue
+		}
+
+		knows, err := gitinterface.KnowsCommit(r.r, latestCommit.Hash, commit)
+		if err != nil {
+			return nil, err
+		}
+		if !knows {
+			issues = append(issues, &FsckIssue{Check: check, Name: entry.ID.String(), Problem: fmt.Sprintf("state '%s' is not reachable from latest state '%s'", entry.TargetID.String(), latestEntry.TargetID.String())})
+		}
+	}
+
+	return issues, nil
+}
+
+// fsckAttestations checks every attestation in every attestations state
+// recorded in the RSL. Each problematic attestation is reported once.
+func (r *Repository) fsckAttestations(entries []rsl.Entry) []*FsckIssue {
+	issues := []*FsckIssue{}
+	reported := map[string]bool{}
+
+	for _, entry := range entries {
+		entry, isReferenceEntry := entry.(*rsl.ReferenceEntry)
+		if !isReferenceEntry || entry.RefName != attestations.Ref {
+			continue
+		}
+
+		attestationsState, err := attestations.LoadAttestationsForEntry(r.r, entry)
+		if err != nil {
+			// Missing states are reported by fsckNamespace
+			if _, commitErr := gitinterface.GetCommit(r.r, entry.TargetID); commitErr == nil {
+				issues = append(issues, &FsckIssue{Check: FsckCheckAttestations, Name: entry.ID.String(), Problem: fmt.Sprintf("unable to load attestations: %s", err.Error())})
+			}
+			continue
+		}
+
+		problems := attestationsState.CheckIntegrity(r.r)
+		for _, attestationPath := range sortedKeys(problems) {
+			problem := problems[attestationPath].Error()
+			if reported[attestationPath+"\x00"+problem] {
+				continue
+			}
+			reported[attestationPath+"\x00"+problem] = true
+
+			issues = append(issues, &FsckIssue{Check: FsckCheckAttestations, Name: attestationPath, Problem: problem})
+		}
+	}
+
+	return issues
+}
+
+// fsckRemoteTrackers identifies gittuf remote tracker refs for remotes that
+// are no longer configured in the repository.
+func (r *Repository) fsckRemoteTrackers(repair bool) ([]*FsckIssue, error) {
+	issues := []*FsckIssue{}
+
+	refs, err := r.r.References()
+	if err != nil {
+		return nil, err
+	}
+
+	danglingRefs := []plumbing.ReferenceName{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		refName := ref.Name().String()
+		if !strings.HasPrefix(refName, gitinterface.RemoteRefPrefix) {
+			return nil
+		}
+
+		remoteName, _, isTracker := strings.Cut(strings.TrimPrefix(refName, gitinterface.RemoteRefPrefix), remoteTrackerInfix)
+		if !isTracker {
+			return nil
+		}
+
+		if _, err := r.r.Remote(remoteName); err != nil {
+			if errors.Is(err, git.ErrRemoteNotFound) {
+				danglingRefs = append(danglingRefs, ref.Name())
+				return nil
+			}
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, refName := range danglingRefs {
+		issue := &FsckIssue{Check: FsckCheckRemoteTrackers, Name: refName.String(), Problem: "remote tracker ref exists for a remote that is not configured", Repairable: true}
+		if repair {
+			slog.Debug(fmt.Sprintf("Removing '%s'...", refName.String()))
+			if err := r.r.Storer.RemoveReference(refName); err != nil {
+				return nil, err
+			}
+			issue.Repaired = true
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues, nil
+}
+
+// latestReferenceEntryForRef returns the first reference entry for the ref in
+// entries, which are ordered starting with the latest.
+func latestReferenceEntryForRef(entries []rsl.Entry, refName string) *rsl.ReferenceEntry {
+	for _, entry := range entries {
+		if entry, isReferenceEntry := entry.(*rsl.ReferenceEntry); isReferenceEntry && entry.RefName == refName {
+			return entry
+		}
+	}
+
+	return nil
+}
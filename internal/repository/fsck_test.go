@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFsck(t *testing.T) {
+	t.Run("no issues", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		issues, err := r.Fsck(testCtx, false)
+		assert.Nil(t, err)
+		assert.Empty(t, issues)
+	})
+
+	t.Run("dangling remote tracker", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		rslRef, err := r.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		trackerRef := plumbing.ReferenceName(rsl.RemoteTrackerRef("origin"))
+		if err := r.r.Storer.SetReference(plumbing.NewHashReference(trackerRef, rslRef.Hash())); err != nil {
+			t.Fatal(err)
+		}
+
+		issues, err := r.Fsck(testCtx, false)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(issues))
+		assert.Equal(t, FsckCheckRemoteTrackers, issues[0].Check)
+		assert.Equal(t, trackerRef.String(), issues[0].Name)
+		assert.True(t, issues[0].Repairable)
+		assert.False(t, issues[0].Repaired)
+
+		issues, err = r.Fsck(testCtx, true)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(issues))
+		assert.True(t, issues[0].Repaired)
+
+		_, err = r.r.Reference(trackerRef, true)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+
+		issues, err = r.Fsck(testCtx, false)
+		assert.Nil(t, err)
+		assert.Empty(t, issues)
+	})
+
+	t.Run("policy ref does not match RSL", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, policy.PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		firstEntry, _, err := rsl.GetFirstReferenceEntryForRef(r.r, policy.PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(policy.PolicyRef), firstEntry.TargetID)); err != nil {
+			t.Fatal(err)
+		}
+
+		issues, err := r.Fsck(testCtx, true)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(issues))
+		assert.Equal(t, FsckCheckPolicy, issues[0].Check)
+		assert.Equal(t, policy.PolicyRef, issues[0].Name)
+		assert.True(t, issues[0].Repaired)
+
+		policyRef, err := r.r.Reference(plumbing.ReferenceName(policy.PolicyRef), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, latestEntry.TargetID, policyRef.Hash())
+	})
+
+	t.Run("missing policy state", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		// Record an RSL entry for a policy state that doesn't exist
+		latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, policy.PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rsl.NewReferenceEntry(policy.PolicyRef, plumbing.ZeroHash).Commit(r.r, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := rsl.NewReferenceEntry(policy.PolicyRef, latestEntry.TargetID).Commit(r.r, false); err != nil {
+			t.Fatal(err)
+		}
+
+		issues, err := r.Fsck(testCtx, false)
+		assert.Nil(t, err)
+		// The missing state also prevents the latest policy from being loaded
+		assert.Equal(t, 2, len(issues))
+		assert.Equal(t, FsckCheckPolicy, issues[0].Check)
+		assert.Contains(t, issues[0].Problem, "missing from the object store")
+		assert.Equal(t, FsckCheckPolicy, issues[1].Check)
+		assert.Contains(t, issues[1].Problem, "unable to load policy state")
+	})
+
+	t.Run("RSL entry with incorrect links", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		latestEntry, err := rsl.GetLatestEntry(r.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The entry claims to be the first in the RSL
+		message := fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: 1\n%s: %s\n%s: %s", rsl.ReferenceEntryHeader, rsl.RefKey, "refs/heads/main", rsl.TargetIDKey, plumbing.ZeroHash.String(), rsl.NumberKey, rsl.PreviousEntryIDKey, plumbing.ZeroHash.String(), rsl.PreviousAnnotationIDKey, plumbing.ZeroHash.String())
+		entryID, err := gitinterface.Commit(r.r, gitinterface.EmptyTree(), rsl.Ref, message, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		issues, err := r.Fsck(testCtx, false)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(issues))
+		assert.Equal(t, FsckCheckRSL, issues[0].Check)
+		assert.Equal(t, entryID.String(), issues[0].Name)
+		assert.Contains(t, issues[0].Problem, "links do not match")
+
+		// Once the entry is removed from the RSL, new entries link correctly
+		if err := r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(rsl.Ref), latestEntry.GetID())); err != nil {
+			t.Fatal(err)
+		}
+		if err := rsl.NewReferenceEntry("refs/heads/main", plumbing.ZeroHash).Commit(r.r, false); err != nil {
+			t.Fatal(err)
+		}
+
+		issues, err = r.Fsck(testCtx, false)
+		assert.Nil(t, err)
+		assert.Empty(t, issues)
+	})
+}
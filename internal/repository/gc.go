@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var ErrInvalidGCOptions = errors.New("invalid gc options, budgets must not be negative")
+
+// GCOptions sets the retention budgets for gittuf's local state, which is
+// never pushed to remotes and would otherwise grow without bound on machines
+// that verify repositories repeatedly.
+type GCOptions struct {
+	// MaxCacheSize is the maximum number of updates retained in the history
+	// of the verification cache. When exceeded, the cache is compacted to a
+	// single update. If 0, the cache is not compacted.
+	MaxCacheSize int
+
+	// MaxTrackerAge is the age after which remote tracker refs whose latest
+	// entry is older are removed. They are recreated when the remote's
+	// metadata is next fetched. If 0, tracker refs are not removed.
+	MaxTrackerAge time.Duration
+}
+
+// Validate checks that the budgets are valid.
+func (o *GCOptions) Validate() error {
+	if o.MaxCacheSize < 0 || o.MaxTrackerAge < 0 {
+		return ErrInvalidGCOptions
+	}
+
+	return nil
+}
+
+// GCResult records the gittuf-local state that exceeded the retention budgets.
+type GCResult struct {
+	// CacheSize is the number of updates in the history of the verification
+	// cache before it was compacted.
+	CacheSize int
+
+	// CacheCompacted indicates the verification cache exceeded its budget.
+	CacheCompacted bool
+
+	// CacheRefsRemoved are the refs whose cached verification results were
+	// dropped as the refs no longer exist.
+	CacheRefsRemoved []string
+
+	// TrackersRemoved are the remote tracker refs that exceeded the maximum
+	// age.
+	TrackersRemoved []string
+}
+
+// CollectGarbage enforces the retention budgets on the verification cache and
+// the remote tracker refs. If dryRun is set, the state exceeding the budgets is
+// reported but not removed.
+func (r *Repository) CollectGarbage(options *GCOptions, dryRun bool) (*GCResult, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &GCResult{CacheRefsRemoved: []string{}, TrackersRemoved: []string{}}
+
+	slog.Debug("Checking size of verification cache...")
+	cacheSize, err := policy.VerificationCacheHistoryLength(r.r)
+	if err != nil {
+		return nil, err
+	}
+	result.CacheSize = cacheSize
+
+	if options.MaxCacheSize > 0 && cacheSize > options.MaxCacheSize {
+		slog.Debug(fmt.Sprintf("Compacting verification cache with %d updates...", cacheSize))
+		result.CacheCompacted = true
+		result.CacheRefsRemoved, err = policy.CompactVerificationCache(r.r, dryRun)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.MaxTrackerAge > 0 {
+		slog.Debug("Checking age of remote tracker refs...")
+		result.TrackersRemoved, err = r.removeExpiredTrackers(time.Now().Add(-options.MaxTrackerAge), dryRun)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// removeExpiredTrackers removes the gittuf remote tracker refs whose tip was
+// committed before the expiry time.
+func (r *Repository) removeExpiredTrackers(expiry time.Time, dryRun bool) ([]string, error) {
+	refs, err := r.r.References()
+	if err != nil {
+		return nil, err
+	}
+
+	expiredRefs := []string{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		refName := ref.Name().String()
+		if !strings.HasPrefix(refName, gitinterface.RemoteRefPrefix) || !strings.Contains(refName, remoteTrackerInfix) {
+			return nil
+		}
+		if ref.Type() != plumbing.HashReference || ref.Hash().IsZero() {
+			return nil
+		}
+
+		commit, err := gitinterface.GetCommit(r.r, ref.Hash())
+		if err != nil {
+			return err
+		}
+
+		if commit.Committer.When.Before(expiry) {
+			expiredRefs = append(expiredRefs, refName)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(expiredRefs)
+
+	if dryRun {
+		return expiredRefs, nil
+	}
+
+	for _, refName := range expiredRefs {
+		slog.Debug(fmt.Sprintf("Removing '%s'...", refName))
+		if err := r.r.Storer.RemoveReference(plumbing.ReferenceName(refName)); err != nil {
+			return nil, err
+		}
+	}
+
+	return expiredRefs, nil
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectGarbage(t *testing.T) {
+	t.Run("verification cache", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		refName := "refs/heads/main"
+		if err := r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3; i++ {
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r.r, refName, 1, gpgKeyBytes)
+			entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+			common.CreateTestRSLReferenceEntryCommit(t, r.r, entry, gpgKeyBytes)
+
+			if err := r.VerifyRef(testCtx, refName, false); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// The cache is within budget
+		result, err := r.CollectGarbage(&GCOptions{MaxCacheSize: 3}, false)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, result.CacheSize)
+		assert.False(t, result.CacheCompacted)
+
+		result, err = r.CollectGarbage(&GCOptions{MaxCacheSize: 2}, true)
+		assert.Nil(t, err)
+		assert.True(t, result.CacheCompacted)
+
+		cacheSize, err := policy.VerificationCacheHistoryLength(r.r)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, cacheSize)
+
+		result, err = r.CollectGarbage(&GCOptions{MaxCacheSize: 2}, false)
+		assert.Nil(t, err)
+		assert.True(t, result.CacheCompacted)
+		assert.Empty(t, result.CacheRefsRemoved)
+
+		cacheSize, err = policy.VerificationCacheHistoryLength(r.r)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, cacheSize)
+
+		// The compacted cache is still used
+		err = r.VerifyRef(testCtx, refName, false)
+		assert.Nil(t, err)
+	})
+
+	t.Run("remote trackers", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		rslRef, err := r.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		trackerRef := plumbing.ReferenceName(rsl.RemoteTrackerRef("origin"))
+		if err := r.r.Storer.SetReference(plumbing.NewHashReference(trackerRef, rslRef.Hash())); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := r.CollectGarbage(&GCOptions{MaxTrackerAge: 100 * 365 * 24 * time.Hour}, false)
+		assert.Nil(t, err)
+		assert.Empty(t, result.TrackersRemoved)
+
+		result, err = r.CollectGarbage(&GCOptions{MaxTrackerAge: time.Nanosecond}, true)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{trackerRef.String()}, result.TrackersRemoved)
+
+		_, err = r.r.Reference(trackerRef, true)
+		assert.Nil(t, err)
+
+		result, err = r.CollectGarbage(&GCOptions{MaxTrackerAge: time.Nanosecond}, false)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{trackerRef.String()}, result.TrackersRemoved)
+
+		_, err = r.r.Reference(trackerRef, true)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		_, err := r.CollectGarbage(&GCOptions{MaxCacheSize: -1}, false)
+		assert.ErrorIs(t, err, ErrInvalidGCOptions)
+	})
+}
@@ -0,0 +1,193 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v61/github"
+)
+
+const (
+	// gitHubTokenScopesHeader lists the scopes of the classic personal access
+	// token used for the request. It is not set for other kinds of tokens.
+	gitHubTokenScopesHeader = "X-OAuth-Scopes"
+
+	// gitHubAcceptedScopesHeader lists the classic token scopes the endpoint
+	// accepts.
+	gitHubAcceptedScopesHeader = "X-Accepted-OAuth-Scopes"
+
+	// gitHubAcceptedPermissionsHeader lists the fine-grained permissions the
+	// endpoint requires, such as `pull_requests=read; contents=read`.
+	gitHubAcceptedPermissionsHeader = "X-Accepted-GitHub-Permissions"
+)
+
+// gitHubPermissions describes the access a GitHub token needs for an
+// operation, used when the API response doesn't indicate it.
+type gitHubPermissions struct {
+	// scopes are the scopes of classic personal access tokens, any of which
+	// grants access.
+	scopes []string
+
+	// permissions are the permissions of fine-grained tokens and GitHub Apps,
+	// of form `<permission>=<access>`, all of which are required.
+	permissions []string
+}
+
+var (
+	gitHubPullRequestPermissions       = &gitHubPermissions{scopes: []string{"repo"}, permissions: []string{"pull_requests=read"}}
+	gitHubCommitPullRequestPermissions = &gitHubPermissions{scopes: []string{"repo"}, permissions: []string{"contents=read", "pull_requests=read"}}
+	gitHubReleasePermissions           = &gitHubPermissions{scopes: []string{"repo"}, permissions: []string{"contents=read"}}
+)
+
+// ErrGitHubTokenPermissions is returned when a GitHub API request fails in a
+// way that indicates the token in GITHUB_TOKEN is invalid or lacks access. As
+// GitHub responds to requests for private repositories the token can't access
+// with 404 rather than 403, it lists the access the operation requires.
+type ErrGitHubTokenPermissions struct {
+	// Operation describes the failed request, such as `get pull request 1`.
+	Operation string
+
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+
+	// Scopes are the classic token scopes, any of which grants access.
+	Scopes []string
+
+	// TokenScopes are the scopes of the classic token used. It is nil if the
+	// token isn't a classic token or the scopes are unknown.
+	TokenScopes []string
+
+	// Permissions are the fine-grained permissions required.
+	Permissions []string
+
+	Err error
+}
+
+func (e *ErrGitHubTokenPermissions) Error() string {
+	message := fmt.Sprintf("unable to %s (%d %s)", e.Operation, e.StatusCode, http.StatusText(e.StatusCode))
+
+	if e.StatusCode == http.StatusUnauthorized {
+		return message + ": the token in GITHUB_TOKEN is invalid or has expired"
+	}
+
+	if e.TokenScopes != nil {
+		message += ": the token in GITHUB_TOKEN lacks the required scopes"
+	} else {
+		message += ": the token in GITHUB_TOKEN may be missing or lack the required access"
+	}
+
+	if len(e.Scopes) > 0 {
+		message += fmt.Sprintf("; classic tokens need one of the scopes %s", quoteAll(e.Scopes))
+		if e.TokenScopes != nil {
+			tokenScopes := "no scopes"
+			if len(e.TokenScopes) > 0 {
+				tokenScopes = quoteAll(e.TokenScopes)
+			}
+			message += fmt.Sprintf(" (token has %s)", tokenScopes)
+		}
+	}
+
+	if len(e.Permissions) > 0 {
+		message += fmt.Sprintf("; fine-grained tokens and GitHub Apps need the permissions %s", quoteAll(e.Permissions))
+	}
+
+	return message
+}
+
+func (e *ErrGitHubTokenPermissions) Unwrap() error {
+	return e.Err
+}
+
+// checkGitHubResponse returns an ErrGitHubTokenPermissions wrapping err if the
+// failed request was likely due to the token's access, using the scopes and
+// permissions reported by GitHub, or the ones in required if GitHub doesn't
+// report them. Other errors, such as rate limiting or a 404 for a classic token
+// with sufficient scopes, are returned unchanged.
+func checkGitHubResponse(operation string, required *gitHubPermissions, response *github.Response, err error) error {
+	if err == nil || response == nil {
+		return err
+	}
+
+	switch response.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+	default:
+		return err
+	}
+
+	var (
+		rateLimitErr      *github.RateLimitError
+		abuseRateLimitErr *github.AbuseRateLimitError
+	)
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseRateLimitErr) {
+		return err
+	}
+
+	tokenErr := &ErrGitHubTokenPermissions{
+		Operation:   operation,
+		StatusCode:  response.StatusCode,
+		Scopes:      required.scopes,
+		Permissions: required.permissions,
+		Err:         err,
+	}
+
+	if scopes := splitGitHubHeader(response.Header.Get(gitHubAcceptedScopesHeader), ","); len(scopes) > 0 {
+		tokenErr.Scopes = scopes
+	}
+
+	if permissions := splitGitHubHeader(response.Header.Get(gitHubAcceptedPermissionsHeader), ";"); len(permissions) > 0 {
+		tokenErr.Permissions = permissions
+	}
+
+	if _, isClassicToken := response.Header[http.CanonicalHeaderKey(gitHubTokenScopesHeader)]; isClassicToken {
+		tokenErr.TokenScopes = splitGitHubHeader(response.Header.Get(gitHubTokenScopesHeader), ",")
+
+		if response.StatusCode == http.StatusNotFound {
+			for _, scope := range tokenErr.Scopes {
+				if slices.Contains(tokenErr.TokenScopes, scope) {
+					// The token has access, so the resource doesn't exist
+					return err
+				}
+			}
+		}
+	}
+
+	return tokenErr
+}
+
+// isConfirmedGitHubTokenError returns true if err is an
+// ErrGitHubTokenPermissions for a classic token known to lack the required
+// scopes, as opposed to one that may lack access.
+func isConfirmedGitHubTokenError(err error) bool {
+	var tokenErr *ErrGitHubTokenPermissions
+	if !errors.As(err, &tokenErr) {
+		return false
+	}
+
+	return tokenErr.StatusCode == http.StatusUnauthorized || tokenErr.TokenScopes != nil
+}
+
+func splitGitHubHeader(value, separator string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, separator) {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func quoteAll(items []string) string {
+	quoted := make([]string, 0, len(items))
+	for _, item := range items {
+		quoted = append(quoted, fmt.Sprintf("'%s'", item))
+	}
+
+	return strings.Join(quoted, ", ")
+}
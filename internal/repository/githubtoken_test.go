@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v61/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckGitHubResponse(t *testing.T) {
+	newResponse := func(statusCode int, headers map[string]string) *github.Response {
+		header := http.Header{}
+		for key, value := range headers {
+			header.Set(key, value)
+		}
+		return &github.Response{Response: &http.Response{StatusCode: statusCode, Header: header}}
+	}
+	apiErr := errors.New("api error")
+
+	t.Run("no error", func(t *testing.T) {
+		err := checkGitHubResponse("get pull request 1", gitHubPullRequestPermissions, newResponse(http.StatusOK, nil), nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unrelated status", func(t *testing.T) {
+		err := checkGitHubResponse("get pull request 1", gitHubPullRequestPermissions, newResponse(http.StatusInternalServerError, nil), apiErr)
+		assert.Equal(t, apiErr, err)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		err := checkGitHubResponse("get pull request 1", gitHubPullRequestPermissions, newResponse(http.StatusUnauthorized, nil), apiErr)
+		assert.ErrorIs(t, err, apiErr)
+		assert.True(t, isConfirmedGitHubTokenError(err))
+		assert.Equal(t, "unable to get pull request 1 (401 Unauthorized): the token in GITHUB_TOKEN is invalid or has expired", err.Error())
+	})
+
+	t.Run("classic token missing scope", func(t *testing.T) {
+		response := newResponse(http.StatusNotFound, map[string]string{
+			gitHubTokenScopesHeader:    "public_repo, read:org",
+			gitHubAcceptedScopesHeader: "repo",
+		})
+		err := checkGitHubResponse("get pull request 1", gitHubPullRequestPermissions, response, apiErr)
+
+		var tokenErr *ErrGitHubTokenPermissions
+		assert.ErrorAs(t, err, &tokenErr)
+		assert.Equal(t, []string{"public_repo", "read:org"}, tokenErr.TokenScopes)
+		assert.True(t, isConfirmedGitHubTokenError(err))
+		assert.Equal(t, "unable to get pull request 1 (404 Not Found): the token in GITHUB_TOKEN lacks the required scopes; classic tokens need one of the scopes 'repo' (token has 'public_repo', 'read:org'); fine-grained tokens and GitHub Apps need the permissions 'pull_requests=read'", err.Error())
+	})
+
+	t.Run("classic token with scope", func(t *testing.T) {
+		response := newResponse(http.StatusNotFound, map[string]string{
+			gitHubTokenScopesHeader:    "repo",
+			gitHubAcceptedScopesHeader: "repo",
+		})
+		err := checkGitHubResponse("get pull request 1", gitHubPullRequestPermissions, response, apiErr)
+		assert.Equal(t, apiErr, err)
+	})
+
+	t.Run("fine-grained token", func(t *testing.T) {
+		response := newResponse(http.StatusForbidden, map[string]string{
+			gitHubAcceptedPermissionsHeader: "contents=read; pull_requests=read",
+		})
+		err := checkGitHubResponse("get release for tag 'v1'", gitHubReleasePermissions, response, apiErr)
+
+		var tokenErr *ErrGitHubTokenPermissions
+		assert.ErrorAs(t, err, &tokenErr)
+		assert.Nil(t, tokenErr.TokenScopes)
+		assert.Equal(t, []string{"contents=read", "pull_requests=read"}, tokenErr.Permissions)
+		assert.False(t, isConfirmedGitHubTokenError(err))
+		assert.Equal(t, "unable to get release for tag 'v1' (403 Forbidden): the token in GITHUB_TOKEN may be missing or lack the required access; classic tokens need one of the scopes 'repo'; fine-grained tokens and GitHub Apps need the permissions 'contents=read', 'pull_requests=read'", err.Error())
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		rateLimitErr := &github.RateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden}}
+		err := checkGitHubResponse("get pull request 1", gitHubPullRequestPermissions, newResponse(http.StatusForbidden, nil), rateLimitErr)
+		assert.Equal(t, rateLimitErr, err)
+	})
+}
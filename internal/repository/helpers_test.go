@@ -6,6 +6,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
@@ -46,7 +47,7 @@ func createTestRepositoryWithRoot(t *testing.T, location string) (*Repository, [
 	if location == "" {
 		repo, err = git.Init(memory.NewStorage(), memfs.New())
 	} else {
-		repo, err = git.PlainInit(location, true)
+		repo, err = gitinterface.PlainInit(location, true)
 	}
 	if err != nil {
 		t.Fatal(err)
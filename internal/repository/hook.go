@@ -9,6 +9,8 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+
+	"github.com/go-git/go-git/v5/storage/filesystem"
 )
 
 type ErrHookExists struct {
@@ -21,28 +23,23 @@ func (e *ErrHookExists) Error() string {
 
 type HookType string
 
-var HookPrePush = HookType("pre-push")
+var (
+	HookPrePush              = HookType("pre-push")
+	HookPreReceive           = HookType("pre-receive")
+	HookPostCheckout         = HookType("post-checkout")
+	HookReferenceTransaction = HookType("reference-transaction")
+)
 
 // UpdateHook updates a git hook in the repositorie's .git/hooks folder.
 // Existing hook files are not overwritten, unless force flag is set.
 func (r *Repository) UpdateHook(hookType HookType, content []byte, force bool) error {
-	// TODO: rely on go-git to find .git folder, once
-	// https://github.com/go-git/go-git/issues/977 is available.
-	// Note, until then gittuf does not support separate git dir.
-
 	slog.Debug("Adding gittuf hooks...")
 
-	slog.Debug("Loading repository worktree...")
-	tree, err := r.r.Worktree()
+	slog.Debug("Locating repository's hooks folder...")
+	hookFolder, err := r.getHooksFolder()
 	if err != nil {
-		return fmt.Errorf("reading worktree: %w", err)
-	}
-	if tree == nil {
-		return fmt.Errorf("worktree is nil, can't update hooks")
+		return err
 	}
-
-	repoRoot := tree.Filesystem.Root()
-	hookFolder := filepath.Join(repoRoot, ".git", "hooks")
 	if err := os.MkdirAll(hookFolder, 0o750); err != nil {
 		return fmt.Errorf("making sure folder exist: %w", err)
 	}
@@ -66,6 +63,30 @@ func (r *Repository) UpdateHook(hookType HookType, content []byte, force bool) e
 	return nil
 }
 
+// getHooksFolder returns the path to the repository's hooks folder. For bare
+// repositories, this is in the repository's root rather than in the `.git`
+// folder of the worktree.
+func (r *Repository) getHooksFolder() (string, error) {
+	if s, ok := r.r.Storer.(*filesystem.Storage); ok {
+		return filepath.Join(s.Filesystem().Root(), "hooks"), nil
+	}
+
+	// TODO: rely on go-git to find .git folder, once
+	// https://github.com/go-git/go-git/issues/977 is available.
+	// Note, until then gittuf does not support separate git dir.
+
+	slog.Debug("Loading repository worktree...")
+	tree, err := r.r.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("reading worktree: %w", err)
+	}
+	if tree == nil {
+		return "", fmt.Errorf("worktree is nil, can't update hooks")
+	}
+
+	return filepath.Join(tree.Filesystem.Root(), ".git", "hooks"), nil
+}
+
 func doesFileExist(path string) (bool, error) {
 	_, err := os.Stat(path)
 	if err != nil {
@@ -7,7 +7,7 @@ import (
 	"path/filepath"
 	"testing"
 
-	"github.com/go-git/go-git/v5"
+	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -16,7 +16,7 @@ func TestUpdatePrePushHook(t *testing.T) {
 	t.Run("write hook", func(t *testing.T) {
 		tmpDir := t.TempDir()
 
-		repo, err := git.PlainInit(tmpDir, false)
+		repo, err := gitinterface.PlainInit(tmpDir, false)
 		require.NoError(t, err)
 		r := &Repository{r: repo}
 
@@ -32,7 +32,7 @@ func TestUpdatePrePushHook(t *testing.T) {
 	t.Run("hook exists", func(t *testing.T) {
 		tmpDir := t.TempDir()
 
-		repo, err := git.PlainInit(tmpDir, false)
+		repo, err := gitinterface.PlainInit(tmpDir, false)
 		require.NoError(t, err)
 		r := &Repository{r: repo}
 
@@ -53,7 +53,7 @@ func TestUpdatePrePushHook(t *testing.T) {
 	t.Run("force overwrite hook", func(t *testing.T) {
 		tmpDir := t.TempDir()
 
-		repo, err := git.PlainInit(tmpDir, false)
+		repo, err := gitinterface.PlainInit(tmpDir, false)
 		require.NoError(t, err)
 		r := &Repository{r: repo}
 
@@ -72,3 +72,20 @@ func TestUpdatePrePushHook(t *testing.T) {
 		assert.Equal(t, []byte("new hook script"), content)
 	})
 }
+
+func TestUpdatePreReceiveHook(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	repo, err := gitinterface.PlainInit(tmpDir, true)
+	require.NoError(t, err)
+	r := &Repository{r: repo}
+
+	err = r.UpdateHook(HookPreReceive, []byte("some content"), false)
+	require.NoError(t, err)
+
+	// Bare repositories don't have a .git directory
+	hookFile := filepath.Join(tmpDir, "hooks", "pre-receive")
+	preReceiveScript, err := os.ReadFile(hookFile)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("some content"), preReceiveScript)
+}
@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/gittuf/gittuf/internal/identities"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// IdentityStatus describes an identity recorded in the repository.
+type IdentityStatus struct {
+	*identities.Identity
+
+	// SignedBy lists the IDs of the keys that signed the identity.
+	SignedBy []string
+
+	// Trusted indicates whether the identity is signed by the required
+	// number of policy administrators, and is therefore used to map approvals
+	// to keys during verification.
+	Trusted bool
+}
+
+// AddIdentity records an identity mapping the account on the forge to the key,
+// signed using the signer. Any existing identity for the account is replaced.
+// The identity is only trusted during verification once it's signed by the
+// required number of policy administrators, i.e., holders of root or top-level
+// targets keys.
+func (r *Repository) AddIdentity(ctx context.Context, signer sslibdsse.SignerVerifier, forge, account, keyID string, signCommit bool) error {
+	identity, err := identities.NewIdentity(forge, account, keyID)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(identity)
+	if err != nil {
+		return err
+	}
+
+	return r.signAndCommitIdentity(ctx, signer, env, fmt.Sprintf("Add identity '%s' for key '%s'", identities.IdentityPath(forge, account), keyID), signCommit)
+}
+
+// AttestIdentity adds a signature using the signer to the identity recorded for
+// the account on the forge.
+func (r *Repository) AttestIdentity(ctx context.Context, signer sslibdsse.SignerVerifier, forge, account string, signCommit bool) error {
+	slog.Debug("Loading current identities...")
+	allIdentities, err := identities.LoadCurrentIdentities(r.r)
+	if err != nil {
+		return err
+	}
+
+	env, err := allIdentities.GetIdentityFor(r.r, forge, account)
+	if err != nil {
+		return err
+	}
+
+	return r.signAndCommitIdentity(ctx, signer, env, fmt.Sprintf("Attest identity '%s'", identities.IdentityPath(forge, account)), signCommit)
+}
+
+// RemoveIdentity removes the identity recorded for the account on the forge.
+func (r *Repository) RemoveIdentity(_ context.Context, forge, account string, signCommit bool) error {
+	slog.Debug("Loading current identities...")
+	allIdentities, err := identities.LoadCurrentIdentities(r.r)
+	if err != nil {
+		return err
+	}
+
+	if err := allIdentities.RemoveIdentity(forge, account); err != nil {
+		return err
+	}
+
+	slog.Debug("Committing identities...")
+	return allIdentities.Commit(r.r, fmt.Sprintf("Remove identity '%s'", identities.IdentityPath(forge, account)), signCommit)
+}
+
+// ListIdentities returns the identities recorded in the repository, sorted by
+// forge and account. Whether each identity is trusted is determined using the
+// current policy.
+func (r *Repository) ListIdentities(ctx context.Context) ([]*IdentityStatus, error) {
+	slog.Debug("Loading current identities...")
+	allIdentities, err := identities.LoadCurrentIdentities(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	envelopes, err := allIdentities.GetAllIdentities(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		if !errors.Is(err, policy.ErrPolicyNotFound) {
+			return nil, err
+		}
+		state = nil
+	}
+
+	statuses := make([]*IdentityStatus, 0, len(envelopes))
+	for _, identityPath := range sortedKeys(envelopes) {
+		env := envelopes[identityPath]
+
+		identity, err := identities.GetIdentity(env)
+		if err != nil {
+			return nil, err
+		}
+
+		status := &IdentityStatus{Identity: identity, SignedBy: make([]string, 0, len(env.Signatures))}
+		for _, signature := range env.Signatures {
+			status.SignedBy = append(status.SignedBy, signature.KeyID)
+		}
+		sort.Strings(status.SignedBy)
+
+		if state != nil {
+			if _, err := state.VerifyIdentity(ctx, env); err == nil {
+				status.Trusted = true
+			} else if !errors.Is(err, policy.ErrIdentityNotTrusted) {
+				return nil, err
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// signAndCommitIdentity signs the identity envelope using the signer and
+// records it in the identities namespace.
+func (r *Repository) signAndCommitIdentity(ctx context.Context, signer sslibdsse.SignerVerifier, env *sslibdsse.Envelope, commitMessage string, signCommit bool) error {
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing identity using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current identities...")
+	allIdentities, err := identities.LoadCurrentIdentities(r.r)
+	if err != nil {
+		return err
+	}
+
+	if err := allIdentities.SetIdentity(r.r, env); err != nil {
+		return err
+	}
+
+	slog.Debug("Committing identities...")
+	return allIdentities.Commit(r.r, commitMessage, signCommit)
+}
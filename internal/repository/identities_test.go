@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/identities"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentities(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootKeyID, err := rootSigner.KeyID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	developerSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(artifacts.SSLibKey3Private) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	developerKeyID, err := developerSigner.KeyID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("no identities", func(t *testing.T) {
+		statuses, err := repo.ListIdentities(testCtx)
+		assert.Nil(t, err)
+		assert.Empty(t, statuses)
+
+		err = repo.AttestIdentity(testCtx, rootSigner, identities.ForgeGitHub, "alice", false)
+		assert.ErrorIs(t, err, identities.ErrIdentityNotFound)
+	})
+
+	t.Run("invalid identity", func(t *testing.T) {
+		err := repo.AddIdentity(testCtx, developerSigner, "bitbucket", "alice", gpgKey.KeyID, false)
+		assert.ErrorIs(t, err, identities.ErrUnknownForge)
+
+		err = repo.AddIdentity(testCtx, developerSigner, identities.ForgeGitHub, "", gpgKey.KeyID, false)
+		assert.ErrorIs(t, err, identities.ErrInvalidIdentity)
+	})
+
+	t.Run("add and attest identity", func(t *testing.T) {
+		err := repo.AddIdentity(testCtx, developerSigner, identities.ForgeGitHub, "Alice", gpgKey.KeyID, false)
+		assert.Nil(t, err)
+
+		statuses, err := repo.ListIdentities(testCtx)
+		assert.Nil(t, err)
+		assert.Equal(t, []*IdentityStatus{{
+			Identity: &identities.Identity{Forge: identities.ForgeGitHub, Account: "alice", KeyID: gpgKey.KeyID},
+			SignedBy: []string{developerKeyID},
+			Trusted:  false,
+		}}, statuses)
+
+		err = repo.AttestIdentity(testCtx, rootSigner, identities.ForgeGitHub, "alice", false)
+		assert.Nil(t, err)
+
+		statuses, err = repo.ListIdentities(testCtx)
+		assert.Nil(t, err)
+		assert.Len(t, statuses, 1)
+		assert.ElementsMatch(t, []string{developerKeyID, rootKeyID}, statuses[0].SignedBy)
+		assert.True(t, statuses[0].Trusted)
+	})
+
+	t.Run("remove identity", func(t *testing.T) {
+		err := repo.RemoveIdentity(testCtx, identities.ForgeGitHub, "alice", false)
+		assert.Nil(t, err)
+
+		statuses, err := repo.ListIdentities(testCtx)
+		assert.Nil(t, err)
+		assert.Empty(t, statuses)
+
+		err = repo.RemoveIdentity(testCtx, identities.ForgeGitHub, "alice", false)
+		assert.ErrorIs(t, err, identities.ErrIdentityNotFound)
+	})
+}
+
+func TestAddAndRemoveGitHubAppKey(t *testing.T) {
+	r, keyBytes := createTestRepositoryWithRoot(t, "")
+
+	sv, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(keyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	appKey, err := tuf.LoadKeyFromBytes(artifacts.SSLibKey3Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.AddGitHubAppKey(testCtx, sv, appKey, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appKeys, err := state.GetGitHubAppKeys()
+	assert.Nil(t, err)
+	assert.Equal(t, []*tuf.Key{appKey}, appKeys)
+
+	err = r.RemoveGitHubAppKey(testCtx, sv, appKey.KeyID, false)
+	assert.Nil(t, err)
+
+	state, err = policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appKeys, err = state.GetGitHubAppKeys()
+	assert.Nil(t, err)
+	assert.Empty(t, appKeys)
+}
@@ -0,0 +1,263 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var ErrCannotRevokeSigningKey = errors.New("cannot revoke the key used to respond to the incident")
+
+// IncidentReport records the response to the compromise of a key.
+type IncidentReport struct {
+	KeyID string `json:"keyID"`
+
+	// Since is the RFC 3339 timestamp from which the key's usages were
+	// considered compromised. It is empty if all usages were considered
+	// compromised.
+	Since     string `json:"since,omitempty"`
+	CreatedAt string `json:"createdAt"`
+
+	// RevokedRoles are the root metadata roles the key was removed from.
+	RevokedRoles []string `json:"revokedRoles"`
+
+	// RevokedRules are the rules the key was removed from, as
+	// <rule-file>/<rule-name>.
+	RevokedRules []string `json:"revokedRules"`
+
+	// UnsignedRuleFiles are the rule files that were updated to revoke the key
+	// and must be signed before the policy is applied.
+	UnsignedRuleFiles []string `json:"unsignedRuleFiles"`
+
+	// SkippedEntries are the RSL reference entries signed using the key that
+	// were skipped.
+	SkippedEntries []*IncidentUsage `json:"skippedEntries"`
+
+	// PolicySignatures and Attestations are the policy metadata and
+	// attestations signed using the key, which must be reviewed.
+	PolicySignatures []*IncidentUsage `json:"policySignatures"`
+	Attestations     []*IncidentUsage `json:"attestations"`
+
+	// AnnotationID is the ID of the RSL annotation that skips the affected
+	// entries. It is empty if no entries were skipped.
+	AnnotationID string `json:"annotationID,omitempty"`
+}
+
+// IncidentUsage is a usage of a compromised key.
+type IncidentUsage struct {
+	RSLEntryID string `json:"rslEntryID"`
+
+	// Name is the ref for RSL entries, the role name for policy signatures,
+	// and the attestation's path for attestations.
+	Name string `json:"name"`
+}
+
+// StartIncident is the interface for the user to respond to the compromise of
+// a key. The key is revoked in the policy staging area: it is removed from the
+// root metadata's roles and from every rule that authorizes it. The signer
+// must be authorized for the Root role, and cannot be the compromised key. Rule
+// files updated to revoke the key are left unsigned, and must be signed before
+// the policy is applied.
+//
+// The RSL entries signed using the key after since, which is ignored if zero,
+// are skipped using an RSL annotation, while the policy metadata and
+// attestations it signed are identified for review. The incident report is
+// returned along with the ID of the blob it's written to.
+func (r *Repository) StartIncident(ctx context.Context, signer sslibdsse.SignerVerifier, keyID string, since time.Time, signCommit bool) (*IncidentReport, plumbing.Hash, error) {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+	if rootKeyID == keyID {
+		return nil, plumbing.ZeroHash, ErrCannotRevokeSigningKey
+	}
+
+	report := &IncidentReport{
+		KeyID:             keyID,
+		CreatedAt:         time.Now().UTC().Format(time.RFC3339),
+		RevokedRoles:      []string{},
+		RevokedRules:      []string{},
+		UnsignedRuleFiles: []string{},
+		SkippedEntries:    []*IncidentUsage{},
+		PolicySignatures:  []*IncidentUsage{},
+		Attestations:      []*IncidentUsage{},
+	}
+	if !since.IsZero() {
+		report.Since = since.UTC().Format(time.RFC3339)
+	}
+
+	slog.Debug(fmt.Sprintf("Identifying usages of '%s'...", keyID))
+	usages, err := r.AuditKey(ctx, keyID)
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+
+	skippedEntryIDs := []plumbing.Hash{}
+	for _, usage := range usages {
+		entry, err := rsl.GetEntry(r.r, usage.RSLEntryID)
+		if err != nil {
+			return nil, plumbing.ZeroHash, err
+		}
+
+		commit, err := gitinterface.GetCommit(r.r, entry.GetID())
+		if err != nil {
+			return nil, plumbing.ZeroHash, err
+		}
+		if commit.Committer.When.Before(since) {
+			continue
+		}
+
+		incidentUsage := &IncidentUsage{RSLEntryID: usage.RSLEntryID.String(), Name: usage.Name}
+		switch usage.Type {
+		case KeyUsageRSLEntry:
+			// Only reference entries can be skipped
+			if _, isReferenceEntry := entry.(*rsl.ReferenceEntry); isReferenceEntry {
+				skippedEntryIDs = append(skippedEntryIDs, usage.RSLEntryID)
+				report.SkippedEntries = append(report.SkippedEntries, incidentUsage)
+			}
+		case KeyUsagePolicySignature:
+			report.PolicySignatures = append(report.PolicySignatures, incidentUsage)
+		case KeyUsageAttestation:
+			report.Attestations = append(report.Attestations, incidentUsage)
+		}
+	}
+
+	if err := r.revokeKey(ctx, signer, keyID, report, signCommit); err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+
+	if len(skippedEntryIDs) != 0 {
+		slog.Debug(fmt.Sprintf("Skipping %d RSL entries...", len(skippedEntryIDs)))
+		annotation := rsl.NewAnnotationEntry(skippedEntryIDs, true, fmt.Sprintf("Skip entries signed using compromised key '%s'", keyID))
+		if err := annotation.Commit(r.r, signCommit); err != nil {
+			return nil, plumbing.ZeroHash, err
+		}
+
+		latestEntry, err := rsl.GetLatestEntry(r.r)
+		if err != nil {
+			return nil, plumbing.ZeroHash, err
+		}
+		report.AnnotationID = latestEntry.GetID().String()
+	}
+
+	reportBytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+
+	slog.Debug("Writing incident report...")
+	reportID, err := gitinterface.WriteBlob(r.r, reportBytes)
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+
+	return report, reportID, nil
+}
+
+// revokeKey removes the key from the root metadata's roles and the rules in
+// the policy staging area, recording what was revoked in the report. Nothing
+// is committed if the policy doesn't trust the key.
+func (r *Repository) revokeKey(ctx context.Context, signer sslibdsse.SignerVerifier, keyID string, report *IncidentReport, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	deleteFuncs := map[string]func(*tuf.RootMetadata, string) (*tuf.RootMetadata, error){
+		policy.RootRoleName:      policy.DeleteRootKey,
+		policy.TargetsRoleName:   policy.DeleteTargetsKey,
+		policy.ObserverRoleName:  policy.DeleteObserverKey,
+		policy.GitHubAppRoleName: policy.DeleteGitHubAppKey,
+	}
+	for _, roleName := range sortedKeys(deleteFuncs) {
+		if !slices.Contains(rootMetadata.Roles[roleName].KeyIDs, keyID) {
+			continue
+		}
+
+		slog.Debug(fmt.Sprintf("Removing key from role '%s'...", roleName))
+		rootMetadata, err = deleteFuncs[roleName](rootMetadata, keyID)
+		if err != nil {
+			return fmt.Errorf("unable to revoke key for role '%s': %w", roleName, err)
+		}
+		report.RevokedRoles = append(report.RevokedRoles, roleName)
+	}
+
+	if len(report.RevokedRoles) != 0 {
+		delete(rootMetadata.Keys, keyID)
+		state.RootPublicKeys = slices.DeleteFunc(state.RootPublicKeys, func(key *tuf.Key) bool { return key.KeyID == keyID })
+	}
+
+	ruleFileNames := sortedKeys(state.DelegationEnvelopes)
+	if state.TargetsEnvelope != nil {
+		ruleFileNames = append([]string{policy.TargetsRoleName}, ruleFileNames...)
+	}
+	for _, ruleFileName := range ruleFileNames {
+		targetsMetadata, err := state.GetTargetsMetadata(ruleFileName)
+		if err != nil {
+			return err
+		}
+
+		ruleNames, err := policy.RemoveKeyFromDelegations(targetsMetadata, keyID)
+		if err != nil {
+			return fmt.Errorf("unable to revoke key in rule file '%s': %w", ruleFileName, err)
+		}
+		if len(ruleNames) == 0 {
+			continue
+		}
+
+		slog.Debug(fmt.Sprintf("Removed key from rules in rule file '%s'...", ruleFileName))
+		for _, ruleName := range ruleNames {
+			report.RevokedRules = append(report.RevokedRules, path.Join(ruleFileName, ruleName))
+		}
+
+		env, err := dsse.CreateEnvelope(targetsMetadata)
+		if err != nil {
+			return err
+		}
+		if ruleFileName == policy.TargetsRoleName {
+			state.TargetsEnvelope = env
+		} else {
+			state.DelegationEnvelopes[ruleFileName] = env
+		}
+		report.UnsignedRuleFiles = append(report.UnsignedRuleFiles, ruleFileName)
+	}
+
+	if len(report.RevokedRoles) == 0 && len(report.RevokedRules) == 0 {
+		slog.Debug(fmt.Sprintf("Key '%s' is not trusted in the current policy", keyID))
+		return nil
+	}
+
+	commitMessage := fmt.Sprintf("Revoke compromised key '%s'", keyID)
+	if len(report.RevokedRoles) != 0 {
+		return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
+	}
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, signCommit)
+}
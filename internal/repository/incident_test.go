@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartIncident(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	refName := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+	entryID := common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+
+	rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootKeyID, err := rootSigner.KeyID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("key revoked, no entries since", func(t *testing.T) {
+		report, reportID, err := repo.StartIncident(testCtx, rootSigner, gpgKey.KeyID, common.TestClock.Now().Add(time.Hour), false)
+		assert.Nil(t, err)
+		assert.Empty(t, report.RevokedRoles)
+		assert.Equal(t, []string{"targets/protect-main"}, report.RevokedRules)
+		assert.Equal(t, []string{policy.TargetsRoleName}, report.UnsignedRuleFiles)
+		assert.Empty(t, report.SkippedEntries)
+		assert.Empty(t, report.AnnotationID)
+
+		reportBytes, err := gitinterface.ReadBlob(repo.r, reportID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		storedReport := &IncidentReport{}
+		if err := json.Unmarshal(reportBytes, storedReport); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, report, storedReport)
+
+		state, err := policy.LoadCurrentState(testCtx, repo.r, policy.PolicyStagingRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, state.TargetsEnvelope.Signatures)
+
+		targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, targetsMetadata.Delegations.Roles[0].KeyIDs)
+		assert.NotContains(t, targetsMetadata.Delegations.Keys, gpgKey.KeyID)
+	})
+
+	t.Run("entries skipped", func(t *testing.T) {
+		// The key was revoked in the staged policy already, but its usages are
+		// still identified
+		report, _, err := repo.StartIncident(testCtx, rootSigner, gpgKey.KeyID, time.Time{}, false)
+		assert.Nil(t, err)
+		assert.Empty(t, report.RevokedRules)
+		assert.Equal(t, []*IncidentUsage{{RSLEntryID: entryID.String(), Name: refName}}, report.SkippedEntries)
+
+		latestEntry, err := rsl.GetLatestEntry(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, latestEntry.GetID().String(), report.AnnotationID)
+
+		annotation, isAnnotation := latestEntry.(*rsl.AnnotationEntry)
+		if !assert.True(t, isAnnotation) {
+			return
+		}
+		assert.True(t, annotation.Skip)
+		assert.Equal(t, entryID, annotation.RSLEntryIDs[0])
+	})
+
+	t.Run("signing key cannot be revoked", func(t *testing.T) {
+		_, _, err := repo.StartIncident(testCtx, rootSigner, rootKeyID, time.Time{}, false)
+		assert.ErrorIs(t, err, ErrCannotRevokeSigningKey)
+	})
+
+	t.Run("signer not authorized for root", func(t *testing.T) {
+		targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, _, err = repo.StartIncident(testCtx, targetsSigner, gpgKey.KeyID, time.Time{}, false)
+		assert.ErrorIs(t, err, ErrUnauthorizedKey)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, _, err := repo.StartIncident(testCtx, rootSigner, "unknown", time.Time{}, false)
+		assert.ErrorIs(t, err, ErrKeyNotFoundInPolicy)
+	})
+}
@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// RotateKey is the interface for the user to rotate a key trusted in the
+// policy to a new key. The signer must be authorized for the Root role. The
+// rotation is recorded at the latest entry in the RSL: entries up to it may
+// be signed using the old key, while entries after it must be signed using the
+// new key. The rotation's proof is signed using the new and old signers, when
+// they're provided. The new signer also signs the policy metadata that was
+// signed using the old key, so that the policy remains valid. Otherwise, this
+// metadata must be signed using the new key before the policy is applied.
+func (r *Repository) RotateKey(ctx context.Context, signer sslibdsse.SignerVerifier, oldKeyID string, newKey *tuf.Key, newSigner, oldSigner sslibdsse.SignerVerifier, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	for _, rotation := range rootMetadata.KeyRotations {
+		if rotation.OldKey.KeyID == oldKeyID {
+			return policy.ErrKeyAlreadyRotated
+		}
+	}
+
+	oldKey, has := rootMetadata.Keys[oldKeyID]
+	if !has {
+		allKeys, err := state.PublicKeys()
+		if err != nil {
+			return err
+		}
+
+		oldKey, has = allKeys[oldKeyID]
+		if !has {
+			return fmt.Errorf("%w: '%s'", policy.ErrKeyNotInPolicy, oldKeyID)
+		}
+	}
+
+	if newSigner != nil {
+		if err := checkSignerKeyID(newSigner, newKey.KeyID); err != nil {
+			return err
+		}
+	}
+	if oldSigner != nil {
+		if err := checkSignerKeyID(oldSigner, oldKey.KeyID); err != nil {
+			return err
+		}
+	}
+
+	// The rotation takes effect after the latest entry, or from the first
+	// entry if the RSL is empty
+	rslEntryID := plumbing.ZeroHash
+	latestEntry, err := rsl.GetLatestEntry(r.r)
+	if err == nil {
+		rslEntryID = latestEntry.GetID()
+	} else if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return err
+	}
+
+	slog.Debug("Creating key rotation proof...")
+	proof, err := policy.NewKeyRotationProof(oldKey.KeyID, newKey.KeyID, rslEntryID.String())
+	if err != nil {
+		return err
+	}
+	for _, proofSigner := range []sslibdsse.SignerVerifier{newSigner, oldSigner} {
+		if proofSigner == nil {
+			continue
+		}
+
+		proof, err = dsse.SignEnvelope(ctx, proof, proofSigner)
+		if err != nil {
+			return err
+		}
+	}
+
+	isRootKey := slices.Contains(rootMetadata.Roles[policy.RootRoleName].KeyIDs, oldKey.KeyID)
+
+	slog.Debug(fmt.Sprintf("Rotating key '%s' to '%s'...", oldKey.KeyID, newKey.KeyID))
+	rootMetadata, err = policy.RotateKey(ctx, rootMetadata, oldKey, newKey, rslEntryID.String(), proof)
+	if err != nil {
+		return err
+	}
+
+	if isRootKey {
+		index := slices.IndexFunc(state.RootPublicKeys, func(key *tuf.Key) bool { return key.KeyID == oldKey.KeyID })
+		if slices.ContainsFunc(state.RootPublicKeys, func(key *tuf.Key) bool { return key.KeyID == newKey.KeyID }) {
+			state.RootPublicKeys = slices.Delete(state.RootPublicKeys, index, index+1)
+		} else {
+			state.RootPublicKeys[index] = newKey
+		}
+	}
+
+	if newSigner != nil {
+		if state.TargetsEnvelope != nil && isSignedUsingKey(state.TargetsEnvelope, oldKey.KeyID) {
+			slog.Debug(fmt.Sprintf("Signing rule file '%s' using '%s'...", policy.TargetsRoleName, newKey.KeyID))
+			state.TargetsEnvelope, err = dsse.SignEnvelope(ctx, state.TargetsEnvelope, newSigner)
+			if err != nil {
+				return err
+			}
+		}
+
+		for roleName, env := range state.DelegationEnvelopes {
+			if !isSignedUsingKey(env, oldKey.KeyID) {
+				continue
+			}
+
+			slog.Debug(fmt.Sprintf("Signing rule file '%s' using '%s'...", roleName, newKey.KeyID))
+			state.DelegationEnvelopes[roleName], err = dsse.SignEnvelope(ctx, env, newSigner)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		slog.Debug("New key's signer not provided, rule files signed using the old key must be signed using the new key...")
+	}
+
+	rootMetadataBytes, err := json.Marshal(rootMetadata)
+	if err != nil {
+		return err
+	}
+
+	env := state.RootEnvelope
+	env.Signatures = []sslibdsse.Signature{}
+	env.Payload = base64.StdEncoding.EncodeToString(rootMetadataBytes)
+
+	// If the old key was a root key, the new key must sign the root metadata
+	// in its place
+	rootSigners := []sslibdsse.SignerVerifier{signer}
+	if isRootKey && newSigner != nil {
+		rootSigners = append(rootSigners, newSigner)
+	}
+
+	slog.Debug("Signing updated root metadata...")
+	for _, rootSigner := range rootSigners {
+		env, err = dsse.SignEnvelope(ctx, env, rootSigner)
+		if err != nil {
+			return err
+		}
+	}
+
+	state.RootEnvelope = env
+
+	commitMessage := fmt.Sprintf("Rotate key '%s' to '%s'", oldKey.KeyID, newKey.KeyID)
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, signCommit)
+}
+
+func checkSignerKeyID(signer sslibdsse.SignerVerifier, expectedKeyID string) error {
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	if keyID != expectedKeyID {
+		return fmt.Errorf("%w: expected signer for key '%s', found '%s'", ErrUnauthorizedKey, expectedKeyID, keyID)
+	}
+
+	return nil
+}
+
+func isSignedUsingKey(env *sslibdsse.Envelope, keyID string) bool {
+	return slices.ContainsFunc(env.Signatures, func(signature sslibdsse.Signature) bool { return signature.KeyID == keyID })
+}
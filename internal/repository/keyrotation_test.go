@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotateKey(t *testing.T) {
+	rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	newSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(artifacts.SSLibKey3Private) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsKey, err := tuf.LoadKeyFromBytes(targetsPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := tuf.LoadKeyFromBytes(artifacts.SSLibKey3Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("rotate targets key", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		latestEntry, err := rsl.GetLatestEntry(r.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = r.RotateKey(testCtx, rootSigner, targetsKey.KeyID, newKey, newSigner, targetsSigner, false)
+		assert.Nil(t, err)
+
+		state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rootMetadata, err := state.GetRootMetadata()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []string{newKey.KeyID}, rootMetadata.Roles[policy.TargetsRoleName].KeyIDs)
+		assert.Len(t, rootMetadata.KeyRotations, 1)
+		assert.Equal(t, latestEntry.GetID().String(), rootMetadata.KeyRotations[0].RSLEntryID)
+		assert.Len(t, rootMetadata.KeyRotations[0].Proof.Signatures, 2)
+
+		// The rule file is signed using the new key, so the policy can be
+		// applied
+		assert.Nil(t, state.Verify(testCtx))
+		assert.Nil(t, policy.Apply(testCtx, r.r, false))
+	})
+
+	t.Run("rotate root key", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		err := r.RotateKey(testCtx, rootSigner, rootKey.KeyID, newKey, newSigner, nil, false)
+		assert.Nil(t, err)
+
+		state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rootMetadata, err := state.GetRootMetadata()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []string{newKey.KeyID}, rootMetadata.Roles[policy.RootRoleName].KeyIDs)
+		assert.Equal(t, []*tuf.Key{newKey}, state.RootPublicKeys)
+		assert.Len(t, rootMetadata.KeyRotations[0].Proof.Signatures, 1)
+
+		assert.Nil(t, policy.Apply(testCtx, r.r, false))
+	})
+
+	t.Run("unauthorized signer", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		err := r.RotateKey(testCtx, targetsSigner, targetsKey.KeyID, newKey, newSigner, nil, false)
+		assert.ErrorIs(t, err, ErrUnauthorizedKey)
+	})
+
+	t.Run("key not in policy", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		err := r.RotateKey(testCtx, rootSigner, newKey.KeyID, targetsKey, nil, nil, false)
+		assert.ErrorIs(t, err, policy.ErrKeyNotInPolicy)
+	})
+
+	t.Run("signer does not match new key", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		err := r.RotateKey(testCtx, rootSigner, targetsKey.KeyID, newKey, targetsSigner, nil, false)
+		assert.ErrorIs(t, err, ErrUnauthorizedKey)
+	})
+
+	t.Run("key already rotated", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		if err := r.RotateKey(testCtx, rootSigner, targetsKey.KeyID, newKey, newSigner, nil, false); err != nil {
+			t.Fatal(err)
+		}
+
+		err := r.RotateKey(testCtx, rootSigner, targetsKey.KeyID, newKey, newSigner, nil, false)
+		assert.ErrorIs(t, err, policy.ErrKeyAlreadyRotated)
+	})
+}
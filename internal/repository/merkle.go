@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/merkle"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// RSLTreeHead is the signed statement of the state of the RSL's Merkle log. The
+// log has one leaf per RSL entry, starting with the first entry. Each leaf's
+// data is the binary ID of the entry.
+type RSLTreeHead struct {
+	TreeSize      uint64    `json:"treeSize"`
+	RootHash      []byte    `json:"rootHash"`
+	LatestEntryID string    `json:"latestEntryID"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// RSLInclusionProof proves that an RSL entry is included in the RSL's Merkle
+// log of the specified size.
+type RSLInclusionProof struct {
+	EntryID   string   `json:"entryID"`
+	LeafIndex uint64   `json:"leafIndex"`
+	TreeSize  uint64   `json:"treeSize"`
+	RootHash  []byte   `json:"rootHash"`
+	Hashes    [][]byte `json:"hashes"`
+}
+
+// Verify checks that the proof is valid for its root hash. Callers must check
+// the root hash matches a tree head they trust.
+func (p *RSLInclusionProof) Verify() error {
+	entryID := plumbing.NewHash(p.EntryID)
+	return merkle.VerifyInclusion(merkle.LeafHash(entryID[:]), p.LeafIndex, p.TreeSize, p.Hashes, p.RootHash)
+}
+
+// RSLConsistencyProof proves that the RSL's Merkle log of the old size is a
+// prefix of the log of the new size, i.e., that the RSL was only appended to.
+type RSLConsistencyProof struct {
+	OldTreeSize uint64   `json:"oldTreeSize"`
+	OldRootHash []byte   `json:"oldRootHash"`
+	TreeSize    uint64   `json:"treeSize"`
+	RootHash    []byte   `json:"rootHash"`
+	Hashes      [][]byte `json:"hashes"`
+}
+
+// Verify checks that the proof is valid for its root hashes. Callers must check
+// the root hashes match tree heads they trust.
+func (p *RSLConsistencyProof) Verify() error {
+	return merkle.VerifyConsistency(p.OldTreeSize, p.TreeSize, p.OldRootHash, p.RootHash, p.Hashes)
+}
+
+// SignRSLTreeHead returns a tree head for the current state of the RSL's Merkle
+// log, signed using the specified signer.
+func (r *Repository) SignRSLTreeHead(ctx context.Context, signer sslibdsse.SignerVerifier) (*sslibdsse.Envelope, error) {
+	tree, entryIDs, err := r.loadRSLMerkleTree()
+	if err != nil {
+		return nil, err
+	}
+
+	rootHash, err := tree.RootHash(tree.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	treeHead := &RSLTreeHead{
+		TreeSize:      tree.Size(),
+		RootHash:      rootHash,
+		LatestEntryID: entryIDs[len(entryIDs)-1].String(),
+		Timestamp:     time.Now().UTC(),
+	}
+
+	env, err := dsse.CreateEnvelope(treeHead)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing tree head for RSL Merkle log of size %d...", treeHead.TreeSize))
+	return dsse.SignEnvelope(ctx, env, signer)
+}
+
+// VerifyRSLTreeHead verifies the signature on a signed tree head using the
+// specified verifier, returning the tree head.
+func VerifyRSLTreeHead(ctx context.Context, env *sslibdsse.Envelope, verifier sslibdsse.Verifier) (*RSLTreeHead, error) {
+	if err := dsse.VerifyEnvelope(ctx, env, []sslibdsse.Verifier{verifier}, 1); err != nil {
+		return nil, err
+	}
+
+	return dsse.DecodePayload[RSLTreeHead](nil, env, nil)
+}
+
+// GetRSLInclusionProof returns a proof that the specified RSL entry is included
+// in the RSL's Merkle log of the specified size. If treeSize is zero, the
+// current size of the log is used.
+func (r *Repository) GetRSLInclusionProof(entryID string, treeSize uint64) (*RSLInclusionProof, error) {
+	tree, entryIDs, err := r.loadRSLMerkleTree()
+	if err != nil {
+		return nil, err
+	}
+
+	if treeSize == 0 {
+		treeSize = tree.Size()
+	}
+
+	leafIndex := slices.Index(entryIDs, plumbing.NewHash(entryID))
+	if leafIndex < 0 {
+		return nil, rsl.ErrRSLEntryNotFound
+	}
+
+	slog.Debug(fmt.Sprintf("Computing inclusion proof for leaf %d in RSL Merkle log of size %d...", leafIndex, treeSize))
+	hashes, err := tree.InclusionProof(uint64(leafIndex), treeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	rootHash, err := tree.RootHash(treeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RSLInclusionProof{
+		EntryID:   entryIDs[leafIndex].String(),
+		LeafIndex: uint64(leafIndex),
+		TreeSize:  treeSize,
+		RootHash:  rootHash,
+		Hashes:    hashes,
+	}, nil
+}
+
+// GetRSLConsistencyProof returns a proof that the RSL's Merkle log of size
+// oldTreeSize is a prefix of the log of size treeSize. If treeSize is zero, the
+// current size of the log is used.
+func (r *Repository) GetRSLConsistencyProof(oldTreeSize, treeSize uint64) (*RSLConsistencyProof, error) {
+	tree, _, err := r.loadRSLMerkleTree()
+	if err != nil {
+		return nil, err
+	}
+
+	if treeSize == 0 {
+		treeSize = tree.Size()
+	}
+
+	slog.Debug(fmt.Sprintf("Computing consistency proof between RSL Merkle logs of size %d and %d...", oldTreeSize, treeSize))
+	hashes, err := tree.ConsistencyProof(oldTreeSize, treeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	oldRootHash, err := tree.RootHash(oldTreeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	rootHash, err := tree.RootHash(treeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RSLConsistencyProof{
+		OldTreeSize: oldTreeSize,
+		OldRootHash: oldRootHash,
+		TreeSize:    treeSize,
+		RootHash:    rootHash,
+		Hashes:      hashes,
+	}, nil
+}
+
+// loadRSLMerkleTree builds the Merkle log for the RSL. As the RSL is append
+// only, the log is deterministically derived from it. The IDs of the RSL
+// entries are returned in the order of the log's leaves.
+func (r *Repository) loadRSLMerkleTree() (*merkle.Tree, []plumbing.Hash, error) {
+	slog.Debug("Loading RSL entries...")
+	entryIDs, err := r.getRSLEntryIDs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	slog.Debug("Building RSL Merkle log...")
+	tree := merkle.NewTree()
+	for _, entryID := range entryIDs {
+		tree.Append(entryID[:])
+	}
+
+	return tree, entryIDs, nil
+}
+
+// getRSLEntryIDs returns the IDs of the RSL's entries, starting with the first
+// entry.
+func (r *Repository) getRSLEntryIDs() ([]plumbing.Hash, error) {
+	entryIDs := []plumbing.Hash{}
+
+	iteratorEntry, err := rsl.GetLatestEntry(r.r)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		entryIDs = append(entryIDs, iteratorEntry.GetID())
+
+		iteratorEntry, err = rsl.GetParentForEntry(r.r, iteratorEntry)
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				break
+			}
+			return nil, err
+		}
+	}
+	slices.Reverse(entryIDs)
+
+	return entryIDs, nil
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/merkle"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRSLMerkleLog(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	firstEntry, _, err := rsl.GetFirstEntry(r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := r.SignRSLTreeHead(testCtx, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldTreeHead, err := VerifyRSLTreeHead(testCtx, env, signer)
+	assert.Nil(t, err)
+
+	latestEntry, err := rsl.GetLatestEntry(r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, latestEntry.GetID().String(), oldTreeHead.LatestEntryID)
+
+	// Add more entries to the RSL
+	refName := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r.r, refName, 3, gpgKeyBytes)
+	for _, commitID := range commitIDs {
+		if err := rsl.NewReferenceEntry(refName, commitID).Commit(r.r, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	env, err = r.SignRSLTreeHead(testCtx, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeHead, err := VerifyRSLTreeHead(testCtx, env, signer)
+	assert.Nil(t, err)
+	assert.Equal(t, oldTreeHead.TreeSize+3, treeHead.TreeSize)
+
+	t.Run("tampered tree head", func(t *testing.T) {
+		tamperedEnv := *env
+		tamperedEnv.Payload = "e30=" // {}
+		_, err := VerifyRSLTreeHead(testCtx, &tamperedEnv, signer)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("inclusion proof", func(t *testing.T) {
+		proof, err := r.GetRSLInclusionProof(firstEntry.ID.String(), 0)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(0), proof.LeafIndex)
+		assert.Equal(t, treeHead.TreeSize, proof.TreeSize)
+		assert.Equal(t, treeHead.RootHash, proof.RootHash)
+		assert.Nil(t, proof.Verify())
+
+		// Proof against the old tree head
+		proof, err = r.GetRSLInclusionProof(firstEntry.ID.String(), oldTreeHead.TreeSize)
+		assert.Nil(t, err)
+		assert.Equal(t, oldTreeHead.RootHash, proof.RootHash)
+		assert.Nil(t, proof.Verify())
+
+		proof.EntryID = latestEntry.GetID().String()
+		assert.ErrorIs(t, proof.Verify(), merkle.ErrInvalidProof)
+
+		// New entries aren't in the old tree
+		_, err = r.GetRSLInclusionProof(commitIDs[0].String(), 0)
+		assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
+
+		newEntry, err := rsl.GetLatestEntry(r.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = r.GetRSLInclusionProof(newEntry.GetID().String(), oldTreeHead.TreeSize)
+		assert.ErrorIs(t, err, merkle.ErrInvalidLeafIndex)
+	})
+
+	t.Run("consistency proof", func(t *testing.T) {
+		proof, err := r.GetRSLConsistencyProof(oldTreeHead.TreeSize, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, oldTreeHead.RootHash, proof.OldRootHash)
+		assert.Equal(t, treeHead.RootHash, proof.RootHash)
+		assert.Nil(t, proof.Verify())
+
+		proof.OldRootHash = plumbing.ZeroHash[:]
+		assert.ErrorIs(t, proof.Verify(), merkle.ErrInvalidProof)
+
+		_, err = r.GetRSLConsistencyProof(treeHead.TreeSize+1, 0)
+		assert.ErrorIs(t, err, merkle.ErrInvalidTreeSize)
+	})
+}
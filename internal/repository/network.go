@@ -0,0 +1,472 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const (
+	NetworkReferenceKindSubmodule = "submodule"
+	NetworkReferenceKindPin       = "pin"
+
+	gitModulesFile = ".gitmodules"
+)
+
+var (
+	ErrNoNetworkRepositories        = errors.New("network manifest does not list any repositories")
+	ErrInvalidNetworkRepository     = errors.New("network repository must have a unique name and a path")
+	ErrUnknownNetworkRepository     = errors.New("network pin refers to unknown repository")
+	ErrRootOfTrustMismatch          = errors.New("root of trust does not match the network's root of trust")
+	ErrNetworkReferenceNotVerified  = errors.New("commit is not part of the verified history of the repository's refs")
+	ErrNetworkVerificationFailed    = errors.New("verification failed for some repositories in the network")
+	ErrNetworkRepositoryNotVerified = errors.New("repository failed verification")
+)
+
+// DefaultNetworkRefs are the refs verified for a network repository if none
+// are specified.
+var DefaultNetworkRefs = []string{"refs/heads/main"}
+
+// NetworkManifest describes a set of related repositories that are expected to
+// share a root of trust.
+type NetworkManifest struct {
+	// Name identifies the network.
+	Name string `json:"name"`
+
+	// Repositories lists the repositories in the network.
+	Repositories []*NetworkRepository `json:"repositories"`
+}
+
+// NetworkRepository identifies a repository in a network.
+type NetworkRepository struct {
+	// Name identifies the repository in the manifest.
+	Name string `json:"name"`
+
+	// Path is the location of a local clone of the repository.
+	Path string `json:"path"`
+
+	// URL is matched against the URLs of submodules in the other repositories
+	// to identify submodules that refer to this repository.
+	URL string `json:"url,omitempty"`
+
+	// Refs are the refs verified for the repository. If empty,
+	// DefaultNetworkRefs is used.
+	Refs []string `json:"refs,omitempty"`
+
+	// Pins are files in the repository that pin a commit of another
+	// repository in the network.
+	Pins []*NetworkPin `json:"pins,omitempty"`
+}
+
+// NetworkPin is a file whose contents are the ID of a commit in another
+// repository in the network, such as a dependency pin.
+type NetworkPin struct {
+	// Path is the location of the file in the repository.
+	Path string `json:"path"`
+
+	// Repository is the name of the repository the commit belongs to.
+	Repository string `json:"repository"`
+}
+
+// NetworkVerificationReport records the outcome of verifying each repository
+// in a network and the references between them.
+type NetworkVerificationReport struct {
+	Network      string                     `json:"network"`
+	RootOfTrust  string                     `json:"rootOfTrust,omitempty"`
+	Repositories []*NetworkRepositoryReport `json:"repositories"`
+	References   []*NetworkReferenceReport  `json:"references"`
+}
+
+// NetworkRepositoryReport records the outcome of verifying a repository.
+type NetworkRepositoryReport struct {
+	Repository  string   `json:"repository"`
+	RootOfTrust string   `json:"rootOfTrust,omitempty"`
+	Refs        []string `json:"refs"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// NetworkReferenceReport records the outcome of resolving a reference from one
+// repository in the network to another.
+type NetworkReferenceReport struct {
+	Repository string `json:"repository"`
+	Ref        string `json:"ref"`
+	Kind       string `json:"kind"`
+	Path       string `json:"path"`
+	Target     string `json:"target"`
+	CommitID   string `json:"commitID,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Failed returns true if any repository or reference failed verification.
+func (n *NetworkVerificationReport) Failed() bool {
+	for _, repository := range n.Repositories {
+		if repository.Error != "" {
+			return true
+		}
+	}
+
+	for _, reference := range n.References {
+		if reference.Error != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LoadNetworkManifest reads a network manifest from the file at path.
+func LoadNetworkManifest(path string) (*NetworkManifest, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &NetworkManifest{}
+	if err := json.Unmarshal(contents, manifest); err != nil {
+		return nil, fmt.Errorf("unable to parse network manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// Validate checks that the manifest's repositories are uniquely named and
+// that pins refer to repositories in the manifest.
+func (n *NetworkManifest) Validate() error {
+	if len(n.Repositories) == 0 {
+		return ErrNoNetworkRepositories
+	}
+
+	names := make([]string, 0, len(n.Repositories))
+	for _, repository := range n.Repositories {
+		if repository.Name == "" || repository.Path == "" || slices.Contains(names, repository.Name) {
+			return fmt.Errorf("%w: '%s'", ErrInvalidNetworkRepository, repository.Name)
+		}
+		names = append(names, repository.Name)
+	}
+
+	for _, repository := range n.Repositories {
+		for _, pin := range repository.Pins {
+			if !slices.Contains(names, pin.Repository) {
+				return fmt.Errorf("%w: '%s' in '%s'", ErrUnknownNetworkRepository, pin.Repository, repository.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifiedNetworkRepository is a network repository that passed verification.
+type verifiedNetworkRepository struct {
+	manifestEntry *NetworkRepository
+	repo          *git.Repository
+
+	// tips maps each verified ref to its tip as recorded in the RSL.
+	tips map[string]plumbing.Hash
+}
+
+// VerifyNetwork verifies the repositories in the network. Each repository's
+// refs must pass verification against its own policy, and every repository
+// must share the root of trust of the first repository in the manifest. Then,
+// the submodules and pins at the tip of each verified ref that refer to other
+// repositories in the network must resolve to commits in the verified history
+// of the refs of those repositories.
+//
+// Every repository and reference is checked, and the outcome of each is
+// recorded in the returned report. If any check fails,
+// ErrNetworkVerificationFailed is returned along with the report.
+func VerifyNetwork(ctx context.Context, manifest *NetworkManifest) (*NetworkVerificationReport, error) {
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+
+	report := &NetworkVerificationReport{
+		Network:      manifest.Name,
+		Repositories: []*NetworkRepositoryReport{},
+		References:   []*NetworkReferenceReport{},
+	}
+
+	verified := map[string]*verifiedNetworkRepository{}
+	for _, repository := range manifest.Repositories {
+		slog.Debug(fmt.Sprintf("Verifying network repository '%s'...", repository.Name))
+		repositoryReport := &NetworkRepositoryReport{Repository: repository.Name, Refs: repository.refs()}
+		report.Repositories = append(report.Repositories, repositoryReport)
+
+		verifiedRepository, rootOfTrust, err := verifyNetworkRepository(ctx, repository)
+		repositoryReport.RootOfTrust = rootOfTrust
+		if err == nil && rootOfTrust != "" {
+			if report.RootOfTrust == "" {
+				report.RootOfTrust = rootOfTrust
+			} else if rootOfTrust != report.RootOfTrust {
+				err = fmt.Errorf("%w: %s", ErrRootOfTrustMismatch, rootOfTrust)
+			}
+		}
+		if err != nil {
+			slog.Debug(fmt.Sprintf("Network repository '%s' failed verification: %s", repository.Name, err.Error()))
+			repositoryReport.Error = err.Error()
+			continue
+		}
+
+		verified[repository.Name] = verifiedRepository
+	}
+
+	for _, repository := range manifest.Repositories {
+		source, isVerified := verified[repository.Name]
+		if !isVerified {
+			continue
+		}
+
+		for _, refName := range repository.refs() {
+			slog.Debug(fmt.Sprintf("Resolving references from '%s' at '%s'...", repository.Name, refName))
+			references, err := resolveNetworkReferences(manifest, verified, source, refName)
+			if err != nil {
+				return nil, err
+			}
+			report.References = append(report.References, references...)
+		}
+	}
+
+	if report.Failed() {
+		return report, ErrNetworkVerificationFailed
+	}
+
+	return report, nil
+}
+
+// verifyNetworkRepository verifies the repository's refs, returning the
+// verified repository and a description of its root of trust.
+func verifyNetworkRepository(ctx context.Context, repository *NetworkRepository) (*verifiedNetworkRepository, string, error) {
+	gitRepo, err := git.PlainOpen(repository.Path)
+	if err != nil {
+		return nil, "", err
+	}
+	r := &Repository{r: gitRepo}
+
+	state, err := policy.LoadCurrentState(ctx, gitRepo, policy.PolicyRef)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rootOfTrust, err := describeRootOfTrust(state)
+	if err != nil {
+		return nil, "", err
+	}
+
+	verifiedRepository := &verifiedNetworkRepository{
+		manifestEntry: repository,
+		repo:          gitRepo,
+		tips:          map[string]plumbing.Hash{},
+	}
+	for _, refName := range repository.refs() {
+		if err := r.VerifyRef(ctx, refName, false); err != nil {
+			return nil, rootOfTrust, fmt.Errorf("%w: '%s': %w", ErrNetworkRepositoryNotVerified, refName, err)
+		}
+
+		entry, _, err := rsl.GetLatestReferenceEntryForRef(gitRepo, refName)
+		if err != nil {
+			return nil, rootOfTrust, err
+		}
+		verifiedRepository.tips[refName] = entry.TargetID
+	}
+
+	return verifiedRepository, rootOfTrust, nil
+}
+
+// describeRootOfTrust returns the root of trust keys and threshold of the
+// policy, such as `2 of [keyA, keyB, keyC]`.
+func describeRootOfTrust(state *policy.State) (string, error) {
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		return "", err
+	}
+
+	rootRole := rootMetadata.Roles[policy.RootRoleName]
+	keyIDs := slices.Clone(rootRole.KeyIDs)
+	slices.Sort(keyIDs)
+
+	return fmt.Sprintf("%d of [%s]", rootRole.Threshold, strings.Join(keyIDs, ", ")), nil
+}
+
+// resolveNetworkReferences identifies the submodules and pins at the verified
+// tip of the ref that refer to other repositories in the network, and checks
+// that each resolves to the verified history of the referenced repository.
+func resolveNetworkReferences(manifest *NetworkManifest, verified map[string]*verifiedNetworkRepository, source *verifiedNetworkRepository, refName string) ([]*NetworkReferenceReport, error) {
+	references := []*NetworkReferenceReport{}
+
+	commit, err := gitinterface.GetCommit(source.repo, source.tips[refName])
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	submodules, err := getSubmodules(tree)
+	if err != nil {
+		return nil, err
+	}
+	for _, submodule := range submodules {
+		target := manifest.repositoryForURL(submodule.URL)
+		if target == nil || target.Name == source.manifestEntry.Name {
+			continue
+		}
+
+		reference := &NetworkReferenceReport{
+			Repository: source.manifestEntry.Name,
+			Ref:        refName,
+			Kind:       NetworkReferenceKindSubmodule,
+			Path:       submodule.Path,
+			Target:     target.Name,
+		}
+		references = append(references, reference)
+
+		entry, err := tree.FindEntry(submodule.Path)
+		if err != nil || entry.Mode != filemode.Submodule {
+			reference.Error = fmt.Sprintf("submodule '%s' is not recorded in the tree", submodule.Path)
+			continue
+		}
+		reference.CommitID = entry.Hash.String()
+
+		if err := resolveNetworkReference(verified, target.Name, entry.Hash); err != nil {
+			reference.Error = err.Error()
+		}
+	}
+
+	for _, pin := range source.manifestEntry.Pins {
+		reference := &NetworkReferenceReport{
+			Repository: source.manifestEntry.Name,
+			Ref:        refName,
+			Kind:       NetworkReferenceKindPin,
+			Path:       pin.Path,
+			Target:     pin.Repository,
+		}
+		references = append(references, reference)
+
+		file, err := tree.File(pin.Path)
+		if err != nil {
+			reference.Error = fmt.Sprintf("unable to read pin '%s': %s", pin.Path, err.Error())
+			continue
+		}
+		contents, err := file.Contents()
+		if err != nil {
+			return nil, err
+		}
+
+		commitID := strings.TrimSpace(contents)
+		if !plumbing.IsHash(commitID) {
+			reference.Error = fmt.Sprintf("pin '%s' does not contain a commit ID", pin.Path)
+			continue
+		}
+		reference.CommitID = commitID
+
+		if err := resolveNetworkReference(verified, pin.Repository, plumbing.NewHash(commitID)); err != nil {
+			reference.Error = err.Error()
+		}
+	}
+
+	return references, nil
+}
+
+// resolveNetworkReference checks that the commit is in the history of one of
+// the verified refs of the target repository.
+func resolveNetworkReference(verified map[string]*verifiedNetworkRepository, targetName string, commitID plumbing.Hash) error {
+	target, isVerified := verified[targetName]
+	if !isVerified {
+		return fmt.Errorf("%w: '%s'", ErrNetworkRepositoryNotVerified, targetName)
+	}
+
+	commit, err := gitinterface.GetCommit(target.repo, commitID)
+	if err != nil {
+		return fmt.Errorf("%w: commit '%s' not found in '%s'", ErrNetworkReferenceNotVerified, commitID.String(), targetName)
+	}
+
+	for _, tip := range target.tips {
+		knows, err := gitinterface.KnowsCommit(target.repo, tip, commit)
+		if err != nil {
+			return err
+		}
+		if knows {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: commit '%s' in '%s'", ErrNetworkReferenceNotVerified, commitID.String(), targetName)
+}
+
+// getSubmodules returns the submodules declared in the tree's .gitmodules
+// file, sorted by path.
+func getSubmodules(tree *object.Tree) ([]*config.Submodule, error) {
+	file, err := tree.File(gitModulesFile)
+	if err != nil {
+		if errors.Is(err, object.ErrFileNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	contents, err := file.Contents()
+	if err != nil {
+		return nil, err
+	}
+
+	modules := config.NewModules()
+	if err := modules.Unmarshal([]byte(contents)); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", gitModulesFile, err)
+	}
+
+	submodules := make([]*config.Submodule, 0, len(modules.Submodules))
+	for _, submodule := range modules.Submodules {
+		submodules = append(submodules, submodule)
+	}
+	slices.SortFunc(submodules, func(a, b *config.Submodule) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return submodules, nil
+}
+
+// repositoryForURL returns the repository in the network with the URL, if
+// any. URLs are compared ignoring a trailing slash or `.git` suffix.
+func (n *NetworkManifest) repositoryForURL(url string) *NetworkRepository {
+	url = normalizeRepositoryURL(url)
+	if url == "" {
+		return nil
+	}
+
+	for _, repository := range n.Repositories {
+		if normalizeRepositoryURL(repository.URL) == url {
+			return repository
+		}
+	}
+
+	return nil
+}
+
+func (n *NetworkRepository) refs() []string {
+	if len(n.Refs) == 0 {
+		return DefaultNetworkRefs
+	}
+
+	return n.Refs
+}
+
+func normalizeRepositoryURL(url string) string {
+	url = strings.TrimSuffix(strings.TrimSpace(url), "/")
+	return strings.TrimSuffix(url, ".git")
+}
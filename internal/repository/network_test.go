@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyNetwork(t *testing.T) {
+	refName := "refs/heads/main"
+
+	tmpDir := t.TempDir()
+	libPath := filepath.Join(tmpDir, "lib")
+	appPath := filepath.Join(tmpDir, "app")
+	lib := createTestRepositoryWithPolicy(t, libPath)
+	app := createTestRepositoryWithPolicy(t, appPath)
+
+	libCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, lib.r, refName, 2, gpgKeyBytes)
+	common.CreateTestRSLReferenceEntryCommit(t, lib.r, rsl.NewReferenceEntry(refName, libCommitIDs[1]), gpgKeyBytes)
+
+	// The feature branch is not verified, its first two commits are identical
+	// to those on main
+	featureCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, lib.r, "refs/heads/feature", 3, gpgKeyBytes)
+	unverifiedCommitID := featureCommitIDs[2]
+
+	// recordAppState records a commit in app with lib as a submodule at
+	// submoduleID, and a pin of lib at pinID
+	recordAppState := func(submoduleID, pinID plumbing.Hash) {
+		t.Helper()
+
+		gitModulesID, err := gitinterface.WriteBlob(app.r, []byte("[submodule \"lib\"]\n\tpath = lib\n\turl = https://example.com/lib.git\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pinBlobID, err := gitinterface.WriteBlob(app.r, []byte(pinID.String()+"\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		treeID, err := gitinterface.WriteTree(app.r, []object.TreeEntry{
+			{Name: ".gitmodules", Mode: filemode.Regular, Hash: gitModulesID},
+			{Name: "lib", Mode: filemode.Submodule, Hash: submoduleID},
+			{Name: "lib.commit", Mode: filemode.Regular, Hash: pinBlobID},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ref, err := app.r.Reference(plumbing.ReferenceName(refName), true)
+		if err != nil {
+			ref = plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)
+			if err := app.r.Storer.SetReference(ref); err != nil {
+				t.Fatal(err)
+			}
+		}
+		commit := gitinterface.CreateCommitObject(common.TestGitConfig, treeID, []plumbing.Hash{ref.Hash()}, "Update lib", common.TestClock)
+		commit = common.SignTestCommit(t, app.r, commit, gpgKeyBytes)
+		commitID, err := gitinterface.ApplyCommit(app.r, commit, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		common.CreateTestRSLReferenceEntryCommit(t, app.r, rsl.NewReferenceEntry(refName, commitID), gpgKeyBytes)
+	}
+
+	manifest := &NetworkManifest{
+		Name: "gittuf",
+		Repositories: []*NetworkRepository{
+			{Name: "app", Path: appPath, Pins: []*NetworkPin{{Path: "lib.commit", Repository: "lib"}}},
+			{Name: "lib", Path: libPath, URL: "https://example.com/lib/"},
+		},
+	}
+
+	t.Run("invalid manifests", func(t *testing.T) {
+		_, err := VerifyNetwork(testCtx, &NetworkManifest{})
+		assert.ErrorIs(t, err, ErrNoNetworkRepositories)
+
+		_, err = VerifyNetwork(testCtx, &NetworkManifest{Repositories: []*NetworkRepository{{Name: "app", Path: appPath}, {Name: "app", Path: libPath}}})
+		assert.ErrorIs(t, err, ErrInvalidNetworkRepository)
+
+		_, err = VerifyNetwork(testCtx, &NetworkManifest{Repositories: []*NetworkRepository{{Name: "app", Path: appPath, Pins: []*NetworkPin{{Path: "lib.commit", Repository: "missing"}}}}})
+		assert.ErrorIs(t, err, ErrUnknownNetworkRepository)
+	})
+
+	t.Run("references resolve to verified history", func(t *testing.T) {
+		recordAppState(libCommitIDs[0], libCommitIDs[1])
+
+		report, err := VerifyNetwork(testCtx, manifest)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, report.RootOfTrust)
+		assert.Len(t, report.Repositories, 2)
+		for _, repositoryReport := range report.Repositories {
+			assert.Empty(t, repositoryReport.Error)
+			assert.Equal(t, report.RootOfTrust, repositoryReport.RootOfTrust)
+		}
+
+		assert.Len(t, report.References, 2)
+		assert.Equal(t, NetworkReferenceKindSubmodule, report.References[0].Kind)
+		assert.Equal(t, "lib", report.References[0].Target)
+		assert.Equal(t, libCommitIDs[0].String(), report.References[0].CommitID)
+		assert.Empty(t, report.References[0].Error)
+		assert.Equal(t, NetworkReferenceKindPin, report.References[1].Kind)
+		assert.Equal(t, libCommitIDs[1].String(), report.References[1].CommitID)
+		assert.Empty(t, report.References[1].Error)
+	})
+
+	t.Run("reference to unverified commit", func(t *testing.T) {
+		recordAppState(unverifiedCommitID, libCommitIDs[1])
+
+		report, err := VerifyNetwork(testCtx, manifest)
+		assert.ErrorIs(t, err, ErrNetworkVerificationFailed)
+		assert.Contains(t, report.References[0].Error, ErrNetworkReferenceNotVerified.Error())
+		assert.Empty(t, report.References[1].Error)
+	})
+
+	t.Run("mismatched root of trust", func(t *testing.T) {
+		recordAppState(libCommitIDs[0], libCommitIDs[1])
+
+		rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		newRootKey, err := tuf.LoadKeyFromBytes(targetsPubKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := lib.AddRootKey(testCtx, rootSigner, newRootKey, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := policy.Apply(testCtx, lib.r, false); err != nil {
+			t.Fatal(err)
+		}
+
+		report, err := VerifyNetwork(testCtx, manifest)
+		assert.ErrorIs(t, err, ErrNetworkVerificationFailed)
+		assert.Empty(t, report.Repositories[0].Error)
+		assert.Contains(t, report.Repositories[1].Error, ErrRootOfTrustMismatch.Error())
+
+		// References to a repository that failed verification don't resolve
+		assert.Contains(t, report.References[0].Error, ErrNetworkRepositoryNotVerified.Error())
+	})
+}
+
+func TestLoadNetworkManifest(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), "network.json")
+	contents := `{"name": "gittuf", "repositories": [{"name": "app", "path": "app", "refs": ["refs/heads/release"], "pins": [{"path": "lib.commit", "repository": "lib"}]}, {"name": "lib", "path": "lib", "url": "https://example.com/lib"}]}`
+	if err := os.WriteFile(manifestPath, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := LoadNetworkManifest(manifestPath)
+	assert.Nil(t, err)
+	assert.Nil(t, manifest.Validate())
+	assert.Equal(t, []string{"refs/heads/release"}, manifest.Repositories[0].refs())
+	assert.Equal(t, DefaultNetworkRefs, manifest.Repositories[1].refs())
+	assert.Equal(t, "lib", manifest.repositoryForURL("https://example.com/lib.git").Name)
+	assert.Nil(t, manifest.repositoryForURL("https://example.com/other.git"))
+}
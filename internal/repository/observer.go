@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var ErrSignerNotObserver = errors.New("signing key is not trusted as an observer in the root of trust")
+
+// AddVerificationSummary verifies the specified ref and records the result in
+// a verification summary signed by an observer. The signer must be trusted as
+// an observer in the current policy. The summary is recorded for the ref's
+// latest RSL entry even if verification fails, in which case the verification
+// error is returned after the summary is committed.
+func (r *Repository) AddVerificationSummary(ctx context.Context, signer sslibdsse.SignerVerifier, target string, signCommit bool) error {
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	refName, err := gitinterface.AbsoluteReference(r.r, target)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		return err
+	}
+
+	isObserver, err := state.IsObserverKey(keyID)
+	if err != nil {
+		return err
+	}
+	if !isObserver {
+		return ErrSignerNotObserver
+	}
+
+	policyEntry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, policy.PolicyRef)
+	if err != nil {
+		return err
+	}
+
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, refName)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Verifying '%s'...", refName))
+	result := attestations.VerificationResultPassed
+	verificationErr := r.VerifyRef(ctx, refName, false)
+	if verificationErr != nil {
+		result = attestations.VerificationResultFailed
+	}
+
+	slog.Debug("Creating verification summary...")
+	summary := &attestations.VerificationSummary{
+		Verifier:     attestations.VerificationSummaryVerifier{ID: keyID},
+		TimeVerified: time.Now().UTC().Format(time.RFC3339),
+		ResourceURI:  fmt.Sprintf("%s@%s", refName, entry.TargetID.String()),
+		Policy: attestations.VerificationSummaryPolicy{
+			URI:    policy.PolicyRef,
+			Digest: map[string]string{"gitCommit": policyEntry.TargetID.String()},
+		},
+		VerificationResult: result,
+		Ref:                refName,
+		RSLEntryID:         entry.ID.String(),
+	}
+	statement, err := attestations.NewVerificationSummaryAttestation(summary, entry.TargetID.String())
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing verification summary using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return err
+	}
+
+	if err := allAttestations.SetVerificationSummary(r.r, env, refName, entry.ID.String()); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add verification summary for '%s' at '%s'\n\nResult: %s\n", refName, entry.ID.String(), result)
+
+	slog.Debug("Committing attestations...")
+	if err := allAttestations.Commit(r.r, commitMessage, signCommit); err != nil {
+		return err
+	}
+
+	return verificationErr
+}
+
+// GetVerificationSummary returns the verification summary recorded for the
+// latest RSL entry of the specified ref. The summary must be signed by an
+// observer trusted in the current policy.
+func (r *Repository) GetVerificationSummary(ctx context.Context, target string) (*attestations.VerificationSummary, error) {
+	refName, err := gitinterface.AbsoluteReference(r.r, target)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, refName)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Loading verification summary...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := allAttestations.GetVerificationSummaryFor(r.r, refName, entry.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Verifying signatures on verification summary...")
+	if err := state.VerifyObserverSignature(ctx, env); err != nil {
+		return nil, err
+	}
+
+	return attestations.GetVerificationSummary(env)
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddObserverKey(t *testing.T) {
+	r, keyBytes := createTestRepositoryWithRoot(t, "")
+
+	rootKey, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(keyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	observerKey, err := tuf.LoadKeyFromBytes(artifacts.SSLibKey3Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.AddObserverKey(testCtx, sv, rootKey, false)
+	assert.ErrorIs(t, err, policy.ErrObserverKeyAuthorized)
+
+	err = r.AddObserverKey(testCtx, sv, observerKey, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{observerKey.KeyID}, rootMetadata.Roles[policy.ObserverRoleName].KeyIDs)
+
+	// Observer keys cannot be trusted for policy or root changes
+	err = r.AddTopLevelTargetsKey(testCtx, sv, observerKey, false)
+	assert.ErrorIs(t, err, policy.ErrKeyIsObserverKey)
+
+	err = r.AddRootKey(testCtx, sv, observerKey, false)
+	assert.ErrorIs(t, err, policy.ErrKeyIsObserverKey)
+
+	err = r.RemoveObserverKey(testCtx, sv, observerKey.KeyID, false)
+	assert.Nil(t, err)
+
+	state, err = policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err = state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, rootMetadata.Roles, policy.ObserverRoleName)
+}
+
+func TestVerificationSummary(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	observerSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(artifacts.SSLibKey3Private) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	observerKey, err := tuf.LoadKeyFromBytes(artifacts.SSLibKey3Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+
+	// Observer hasn't been added yet
+	err = repo.AddVerificationSummary(testCtx, observerSigner, refName, false)
+	assert.ErrorIs(t, err, ErrSignerNotObserver)
+
+	if err := repo.AddObserverKey(testCtx, rootSigner, observerKey, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := policy.Apply(testCtx, repo.r, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Summary doesn't exist yet
+	_, err = repo.GetVerificationSummary(testCtx, refName)
+	assert.ErrorIs(t, err, attestations.ErrVerificationSummaryNotFound)
+
+	// Only observers can sign verification summaries
+	err = repo.AddVerificationSummary(testCtx, targetsSigner, refName, false)
+	assert.ErrorIs(t, err, ErrSignerNotObserver)
+
+	err = repo.AddVerificationSummary(testCtx, observerSigner, refName, false)
+	assert.Nil(t, err)
+
+	summary, err := repo.GetVerificationSummary(testCtx, refName)
+	assert.Nil(t, err)
+	assert.Equal(t, attestations.VerificationResultPassed, summary.VerificationResult)
+	assert.Equal(t, observerKey.KeyID, summary.Verifier.ID)
+	assert.Equal(t, refName, summary.Ref)
+
+	// An unauthorized change is recorded as a failed verification
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgUnauthorizedKeyBytes)
+	entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgUnauthorizedKeyBytes)
+
+	err = repo.AddVerificationSummary(testCtx, observerSigner, refName, false)
+	assert.NotNil(t, err)
+
+	summary, err = repo.GetVerificationSummary(testCtx, refName)
+	assert.Nil(t, err)
+	assert.Equal(t, attestations.VerificationResultFailed, summary.VerificationResult)
+}
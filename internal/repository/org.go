@@ -0,0 +1,369 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+const (
+	RolloutStatusUpdated   = "updated"
+	RolloutStatusUnchanged = "unchanged"
+	RolloutStatusFailed    = "failed"
+)
+
+var (
+	ErrNoOrganizationRepositories = errors.New("organization manifest does not list any repositories")
+	ErrNoOrganizationTemplate     = errors.New("organization manifest does not contain a template")
+	ErrUnknownKeyset              = errors.New("template rule refers to unknown keyset")
+	ErrUnknownTemplateRule        = errors.New("template app trust refers to rule not in template")
+	ErrUnappliedPolicyChanges     = errors.New("policy staging area has changes that have not been applied")
+	ErrOrganizationRolloutFailed  = errors.New("organization template could not be applied to some repositories")
+)
+
+// OrganizationManifest describes the baseline policy of an organization and
+// the repositories it must be applied to.
+type OrganizationManifest struct {
+	// Name identifies the organization.
+	Name string `json:"name"`
+
+	// Repositories lists the repositories the template is applied to.
+	Repositories []*OrganizationRepository `json:"repositories"`
+
+	// Template is the baseline policy applied to each repository.
+	Template *OrganizationTemplate `json:"template"`
+}
+
+// OrganizationRepository identifies a repository in an organization.
+type OrganizationRepository struct {
+	// Path is the location of a local clone of the repository.
+	Path string `json:"path"`
+
+	// Remote is the name of the remote the updated policy is pushed to. If
+	// empty, the policy is only updated locally.
+	Remote string `json:"remote,omitempty"`
+}
+
+// OrganizationTemplate contains the baseline policy of an organization.
+type OrganizationTemplate struct {
+	// Keysets maps the name of a set of keys to the keys in it, identified as
+	// they would be on the command line, such as paths or "gpg:<fingerprint>".
+	Keysets map[string][]string `json:"keysets"`
+
+	// Rules are added to or updated in the repository's top level policy.
+	Rules []*OrganizationRule `json:"rules"`
+
+	// AppTrust lists keys of apps, such as GitHub's web-flow key, that are
+	// trusted in rules only for specific operations.
+	AppTrust []*OrganizationAppTrust `json:"appTrust,omitempty"`
+}
+
+// OrganizationRule is a rule in an organization's baseline policy.
+type OrganizationRule struct {
+	Name      string   `json:"name"`
+	Patterns  []string `json:"patterns"`
+	Keysets   []string `json:"keysets"`
+	Threshold int      `json:"threshold"`
+}
+
+// OrganizationAppTrust trusts an app's key in rules for the specified
+// operations.
+type OrganizationAppTrust struct {
+	Key        string   `json:"key"`
+	Rules      []string `json:"rules"`
+	Operations []string `json:"operations"`
+}
+
+// OrganizationRollout records the result of applying an organization's
+// template to each of its repositories.
+type OrganizationRollout struct {
+	Organization   string                       `json:"organization"`
+	TemplateDigest string                       `json:"templateDigest"`
+	Timestamp      string                       `json:"timestamp"`
+	Results        []*OrganizationRolloutResult `json:"results"`
+}
+
+// OrganizationRolloutResult records the result of applying an organization's
+// template to a repository.
+type OrganizationRolloutResult struct {
+	Repository    string `json:"repository"`
+	Status        string `json:"status"`
+	PolicyEntryID string `json:"policyEntryID,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// LoadOrganizationManifest reads an organization manifest from the file at
+// path.
+func LoadOrganizationManifest(path string) (*OrganizationManifest, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &OrganizationManifest{}
+	if err := json.Unmarshal(contents, manifest); err != nil {
+		return nil, fmt.Errorf("unable to parse organization manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// Save writes the rollout record to the file at path.
+func (o *OrganizationRollout) Save(path string) error {
+	contents, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(contents, '\n'), 0o644)
+}
+
+// ApplyOrganizationTemplate applies the organization's template to each of its
+// repositories. The template's rules are added to or updated in each
+// repository's top level policy, and the template's app keys are trusted in
+// the rules for their operations. The changes are signed using signer, which
+// must be trusted for the top level policy in each repository, and applied to
+// the repository's policy in a single policy entry. If the repository lists a
+// remote, the policy is pushed to it. Keys in the template are loaded using
+// loadPublicKey.
+//
+// Every repository is attempted, and the outcome for each is recorded in the
+// returned rollout. If the template could not be applied to some repositories,
+// ErrOrganizationRolloutFailed is returned along with the rollout.
+func ApplyOrganizationTemplate(ctx context.Context, manifest *OrganizationManifest, signer sslibdsse.SignerVerifier, loadPublicKey func(string) (*tuf.Key, error), signCommit bool) (*OrganizationRollout, error) {
+	if len(manifest.Repositories) == 0 {
+		return nil, ErrNoOrganizationRepositories
+	}
+	if manifest.Template == nil {
+		return nil, ErrNoOrganizationTemplate
+	}
+
+	templateBytes, err := json.Marshal(manifest.Template)
+	if err != nil {
+		return nil, err
+	}
+	templateDigest := sha256.Sum256(templateBytes)
+
+	rollout := &OrganizationRollout{
+		Organization:   manifest.Name,
+		TemplateDigest: hex.EncodeToString(templateDigest[:]),
+		Timestamp:      time.Now().UTC().Format(time.RFC3339),
+		Results:        []*OrganizationRolloutResult{},
+	}
+
+	slog.Debug("Loading template keys...")
+	keysets := map[string][]*tuf.Key{}
+	for name, keys := range manifest.Template.Keysets {
+		for _, key := range keys {
+			publicKey, err := loadPublicKey(key)
+			if err != nil {
+				return nil, fmt.Errorf("unable to load key '%s' in keyset '%s': %w", key, name, err)
+			}
+			keysets[name] = append(keysets[name], publicKey)
+		}
+	}
+
+	templateRules := []string{}
+	for _, rule := range manifest.Template.Rules {
+		for _, keyset := range rule.Keysets {
+			if _, has := keysets[keyset]; !has {
+				return nil, fmt.Errorf("%w: '%s' in rule '%s'", ErrUnknownKeyset, keyset, rule.Name)
+			}
+		}
+		templateRules = append(templateRules, rule.Name)
+	}
+
+	appKeys := make([]*tuf.Key, 0, len(manifest.Template.AppTrust))
+	for _, appTrust := range manifest.Template.AppTrust {
+		for _, rule := range appTrust.Rules {
+			if !slices.Contains(templateRules, rule) {
+				return nil, fmt.Errorf("%w: '%s'", ErrUnknownTemplateRule, rule)
+			}
+		}
+
+		appKey, err := loadPublicKey(appTrust.Key)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load app key '%s': %w", appTrust.Key, err)
+		}
+		appKeys = append(appKeys, appKey)
+	}
+
+	failed := false
+	for _, repository := range manifest.Repositories {
+		slog.Debug(fmt.Sprintf("Applying organization template to '%s'...", repository.Path))
+		result := &OrganizationRolloutResult{Repository: repository.Path}
+		rollout.Results = append(rollout.Results, result)
+
+		policyEntryID, updated, err := applyOrganizationTemplateToRepository(ctx, manifest, rollout.TemplateDigest, keysets, appKeys, repository, signer, signCommit)
+		switch {
+		case err != nil:
+			slog.Debug(fmt.Sprintf("Unable to apply organization template to '%s': %s", repository.Path, err.Error()))
+			result.Status = RolloutStatusFailed
+			result.Error = err.Error()
+			failed = true
+		case updated:
+			result.Status = RolloutStatusUpdated
+		default:
+			result.Status = RolloutStatusUnchanged
+		}
+		if !policyEntryID.IsZero() {
+			result.PolicyEntryID = policyEntryID.String()
+		}
+	}
+
+	if failed {
+		return rollout, ErrOrganizationRolloutFailed
+	}
+
+	return rollout, nil
+}
+
+// applyOrganizationTemplateToRepository applies the template to a single
+// repository. It returns the ID of the repository's latest policy entry and
+// whether the policy was changed.
+func applyOrganizationTemplateToRepository(ctx context.Context, manifest *OrganizationManifest, templateDigest string, keysets map[string][]*tuf.Key, appKeys []*tuf.Key, repository *OrganizationRepository, signer sslibdsse.SignerVerifier, signCommit bool) (plumbing.Hash, bool, error) {
+	gitRepo, err := git.PlainOpen(repository.Path)
+	if err != nil {
+		return plumbing.ZeroHash, false, err
+	}
+	r := &Repository{r: gitRepo}
+
+	// Applying the template also applies the staging area, so it must not
+	// contain changes that were not meant to be published yet
+	policyRef, err := r.r.Reference(plumbing.ReferenceName(policy.PolicyRef), true)
+	if err != nil {
+		return plumbing.ZeroHash, false, err
+	}
+	policyStagingRef, err := r.r.Reference(plumbing.ReferenceName(policy.PolicyStagingRef), true)
+	if err != nil {
+		return plumbing.ZeroHash, false, err
+	}
+	if policyRef.Hash() != policyStagingRef.Hash() {
+		return plumbing.ZeroHash, false, ErrUnappliedPolicyChanges
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return plumbing.ZeroHash, false, err
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	if err != nil {
+		return plumbing.ZeroHash, false, err
+	}
+
+	originalBytes, err := json.Marshal(targetsMetadata)
+	if err != nil {
+		return plumbing.ZeroHash, false, err
+	}
+
+	for _, rule := range manifest.Template.Rules {
+		authorizedKeys := []*tuf.Key{}
+		for _, keyset := range rule.Keysets {
+			for _, key := range keysets[keyset] {
+				if !slices.ContainsFunc(authorizedKeys, func(k *tuf.Key) bool { return k.KeyID == key.KeyID }) {
+					authorizedKeys = append(authorizedKeys, key)
+				}
+			}
+		}
+
+		hasRule := slices.ContainsFunc(targetsMetadata.Delegations.Roles, func(delegation tuf.Delegation) bool {
+			return delegation.Name == rule.Name
+		})
+		if hasRule {
+			slog.Debug(fmt.Sprintf("Updating rule '%s'...", rule.Name))
+			targetsMetadata, err = policy.UpdateDelegation(targetsMetadata, rule.Name, authorizedKeys, rule.Patterns, rule.Threshold)
+		} else {
+			if state.HasRuleName(rule.Name) {
+				// The rule exists in a delegated policy, which the template
+				// does not manage
+				return plumbing.ZeroHash, false, fmt.Errorf("%w: '%s'", policy.ErrDuplicatedRuleName, rule.Name)
+			}
+
+			slog.Debug(fmt.Sprintf("Adding rule '%s'...", rule.Name))
+			targetsMetadata, err = policy.AddDelegation(targetsMetadata, rule.Name, authorizedKeys, rule.Patterns, rule.Threshold)
+		}
+		if err != nil {
+			return plumbing.ZeroHash, false, err
+		}
+	}
+
+	for index, appTrust := range manifest.Template.AppTrust {
+		for _, rule := range appTrust.Rules {
+			slog.Debug(fmt.Sprintf("Trusting app key '%s' in rule '%s'...", appKeys[index].KeyID, rule))
+			targetsMetadata, err = policy.AddRestrictedKeyToDelegation(targetsMetadata, rule, appKeys[index], appTrust.Operations)
+			if err != nil {
+				return plumbing.ZeroHash, false, err
+			}
+		}
+	}
+
+	updatedBytes, err := json.Marshal(targetsMetadata)
+	if err != nil {
+		return plumbing.ZeroHash, false, err
+	}
+
+	updated := !bytes.Equal(originalBytes, updatedBytes)
+	if updated {
+		keyID, err := signer.KeyID()
+		if err != nil {
+			return plumbing.ZeroHash, false, err
+		}
+
+		env, err := dsse.CreateEnvelope(targetsMetadata)
+		if err != nil {
+			return plumbing.ZeroHash, false, err
+		}
+
+		slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+		env, err = dsse.SignEnvelope(ctx, env, signer)
+		if err != nil {
+			return plumbing.ZeroHash, false, err
+		}
+		state.TargetsEnvelope = env
+
+		commitMessage := fmt.Sprintf("Apply template of organization '%s'\n\nTemplate: %s\n", manifest.Name, templateDigest)
+
+		slog.Debug("Committing policy...")
+		if err := state.Commit(r.r, commitMessage, signCommit); err != nil {
+			return plumbing.ZeroHash, false, err
+		}
+
+		slog.Debug("Applying policy...")
+		if err := policy.Apply(ctx, r.r, signCommit); err != nil {
+			return plumbing.ZeroHash, false, err
+		}
+	}
+
+	if repository.Remote != "" {
+		if err := r.PushPolicy(ctx, repository.Remote); err != nil {
+			return plumbing.ZeroHash, updated, err
+		}
+	}
+
+	policyEntry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, policy.PolicyRef)
+	if err != nil {
+		return plumbing.ZeroHash, updated, err
+	}
+
+	return policyEntry.ID, updated, nil
+}
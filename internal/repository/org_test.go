@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyOrganizationTemplate(t *testing.T) {
+	keys := map[string][]byte{
+		"targets.pub": targetsPubKeyBytes,
+		"app.pub":     artifacts.SSLibKey3Public,
+	}
+	loadPublicKey := func(key string) (*tuf.Key, error) {
+		keyBytes, has := keys[key]
+		if !has {
+			return nil, os.ErrNotExist
+		}
+		return tuf.LoadKeyFromBytes(keyBytes)
+	}
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	appKey, err := tuf.LoadKeyFromBytes(artifacts.SSLibKey3Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	firstRepo := createTestRepositoryWithPolicy(t, filepath.Join(tmpDir, "first"))
+	secondRepo := createTestRepositoryWithPolicy(t, filepath.Join(tmpDir, "second"))
+
+	manifest := &OrganizationManifest{
+		Name: "gittuf",
+		Repositories: []*OrganizationRepository{
+			{Path: filepath.Join(tmpDir, "first")},
+			{Path: filepath.Join(tmpDir, "second")},
+		},
+		Template: &OrganizationTemplate{
+			Keysets: map[string][]string{"maintainers": {"targets.pub"}},
+			Rules: []*OrganizationRule{
+				{Name: "protect-release", Patterns: []string{"git:refs/heads/release/*"}, Keysets: []string{"maintainers"}, Threshold: 1},
+			},
+			AppTrust: []*OrganizationAppTrust{
+				{Key: "app.pub", Rules: []string{"protect-release"}, Operations: []string{tuf.KeyOperationMergeCommit}},
+			},
+		},
+	}
+
+	t.Run("invalid manifests", func(t *testing.T) {
+		_, err := ApplyOrganizationTemplate(testCtx, &OrganizationManifest{Template: manifest.Template}, signer, loadPublicKey, false)
+		assert.ErrorIs(t, err, ErrNoOrganizationRepositories)
+
+		_, err = ApplyOrganizationTemplate(testCtx, &OrganizationManifest{Repositories: manifest.Repositories}, signer, loadPublicKey, false)
+		assert.ErrorIs(t, err, ErrNoOrganizationTemplate)
+
+		invalidManifest := &OrganizationManifest{
+			Repositories: manifest.Repositories,
+			Template: &OrganizationTemplate{
+				Rules: []*OrganizationRule{{Name: "protect-release", Keysets: []string{"maintainers"}, Threshold: 1}},
+			},
+		}
+		_, err = ApplyOrganizationTemplate(testCtx, invalidManifest, signer, loadPublicKey, false)
+		assert.ErrorIs(t, err, ErrUnknownKeyset)
+
+		invalidManifest.Template = &OrganizationTemplate{
+			AppTrust: []*OrganizationAppTrust{{Key: "app.pub", Rules: []string{"protect-main"}}},
+		}
+		_, err = ApplyOrganizationTemplate(testCtx, invalidManifest, signer, loadPublicKey, false)
+		assert.ErrorIs(t, err, ErrUnknownTemplateRule)
+	})
+
+	t.Run("apply template", func(t *testing.T) {
+		rollout, err := ApplyOrganizationTemplate(testCtx, manifest, signer, loadPublicKey, false)
+		assert.Nil(t, err)
+		assert.Equal(t, "gittuf", rollout.Organization)
+		assert.NotEmpty(t, rollout.TemplateDigest)
+		assert.Len(t, rollout.Results, 2)
+
+		for index, repo := range []*Repository{firstRepo, secondRepo} {
+			assert.Equal(t, RolloutStatusUpdated, rollout.Results[index].Status)
+			assert.NotEmpty(t, rollout.Results[index].PolicyEntryID)
+
+			state, err := policy.LoadCurrentState(testCtx, repo.r, policy.PolicyRef)
+			if err != nil {
+				t.Fatal(err)
+			}
+			targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The existing rule is retained, the template's rule is added
+			// before the allow rule
+			roles := targetsMetadata.Delegations.Roles
+			assert.Len(t, roles, 3)
+			assert.Equal(t, "protect-main", roles[0].Name)
+			assert.Equal(t, "protect-release", roles[1].Name)
+			assert.Equal(t, []string{"git:refs/heads/release/*"}, roles[1].Paths)
+			assert.Contains(t, roles[1].KeyIDs, appKey.KeyID)
+			assert.Equal(t, []string{tuf.KeyOperationMergeCommit}, roles[1].KeyOperations[appKey.KeyID])
+		}
+
+		// Reapplying the template leaves the repositories unchanged
+		secondRollout, err := ApplyOrganizationTemplate(testCtx, manifest, signer, loadPublicKey, false)
+		assert.Nil(t, err)
+		for index, result := range secondRollout.Results {
+			assert.Equal(t, RolloutStatusUnchanged, result.Status)
+			assert.Equal(t, rollout.Results[index].PolicyEntryID, result.PolicyEntryID)
+		}
+	})
+
+	t.Run("rollout records failures", func(t *testing.T) {
+		failingManifest := &OrganizationManifest{
+			Name: "gittuf",
+			Repositories: []*OrganizationRepository{
+				{Path: filepath.Join(tmpDir, "missing")},
+				{Path: filepath.Join(tmpDir, "first")},
+			},
+			Template: manifest.Template,
+		}
+
+		rollout, err := ApplyOrganizationTemplate(testCtx, failingManifest, signer, loadPublicKey, false)
+		assert.ErrorIs(t, err, ErrOrganizationRolloutFailed)
+		assert.Equal(t, RolloutStatusFailed, rollout.Results[0].Status)
+		assert.NotEmpty(t, rollout.Results[0].Error)
+		assert.Equal(t, RolloutStatusUnchanged, rollout.Results[1].Status)
+
+		rolloutPath := filepath.Join(tmpDir, "rollout.json")
+		assert.Nil(t, rollout.Save(rolloutPath))
+		assert.FileExists(t, rolloutPath)
+	})
+}
+
+func TestLoadOrganizationManifest(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), "manifest.json")
+	manifestContents := `{"name": "gittuf", "repositories": [{"path": "repo", "remote": "origin"}], "template": {"keysets": {"maintainers": ["key.pub"]}, "rules": [{"name": "protect-main", "patterns": ["git:refs/heads/main"], "keysets": ["maintainers"], "threshold": 1}]}}`
+	if err := os.WriteFile(manifestPath, []byte(manifestContents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := LoadOrganizationManifest(manifestPath)
+	assert.Nil(t, err)
+	assert.Equal(t, "gittuf", manifest.Name)
+	assert.Equal(t, []*OrganizationRepository{{Path: "repo", Remote: "origin"}}, manifest.Repositories)
+	assert.Equal(t, []string{"key.pub"}, manifest.Template.Keysets["maintainers"])
+	assert.Equal(t, "protect-main", manifest.Template.Rules[0].Name)
+
+	if err := os.WriteFile(manifestPath, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadOrganizationManifest(manifestPath)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// fetchMissingGittufObjects fetches the commits and trees of gittuf's refs that
+// are missing in a partial clone. Fetching from a promisor remote using the Git
+// binary applies the clone's filter to gittuf's refs too, but gittuf reads
+// their objects using go-git, which cannot fetch them on demand. If the
+// repository is not a partial clone, this is a no-op.
+func (r *Repository) fetchMissingGittufObjects() error {
+	remoteName, err := gitinterface.GetPromisorRemote(r.r)
+	if err != nil {
+		return err
+	}
+	if remoteName == "" {
+		return nil
+	}
+
+	slog.Debug("Repository is a partial clone, checking for missing gittuf objects...")
+	refs, err := r.r.References()
+	if err != nil {
+		return err
+	}
+
+	tipIDs := []plumbing.Hash{}
+	if err := refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference && strings.HasPrefix(ref.Name().String(), gittufRefPrefix) {
+			tipIDs = append(tipIDs, ref.Hash())
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return gitinterface.FetchMissingObjects(r.r, tipIDs...)
+}
+
+// fetchMissingObjectsForRef fetches the commits and trees reachable from the
+// targets of the ref's RSL entries if they're missing in a partial clone, as
+// verification walks their history. If the repository is not a partial clone,
+// this is a no-op.
+func (r *Repository) fetchMissingObjectsForRef(refName string) error {
+	remoteName, err := gitinterface.GetPromisorRemote(r.r)
+	if err != nil {
+		return err
+	}
+	if remoteName == "" {
+		return nil
+	}
+
+	slog.Debug(fmt.Sprintf("Repository is a partial clone, checking for objects missing for '%s'...", refName))
+	iterator, err := rsl.NewIterator(r.r)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	targetIDs := []plumbing.Hash{}
+	for {
+		entry, err := iterator.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+
+		if entry, isReferenceEntry := entry.(*rsl.ReferenceEntry); isReferenceEntry && entry.RefName == refName && !entry.TargetID.IsZero() {
+			targetIDs = append(targetIDs, entry.TargetID)
+		}
+	}
+
+	return gitinterface.FetchMissingTrees(r.r, targetIDs...)
+}
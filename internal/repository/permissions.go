@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+)
+
+var ErrSigningKeyNotTrustedForRef = errors.New("configured signing key is not trusted by the rules protecting the ref")
+
+// CheckSigningKeyForRef checks if the signing key in the user's Git config is
+// trusted by the current policy to sign RSL entries for the specified ref. The
+// returned error identifies the rules that would reject an entry signed by the
+// key. This is meant to warn users before they create an entry that fails
+// verification. If the configured key cannot be identified upfront, such as
+// with Sigstore keyless signing or when a GPG key is specified using a user
+// ID, the check is skipped.
+func (r *Repository) CheckSigningKeyForRef(ctx context.Context, refName string) error {
+	keyID, err := configuredSigningKeyID()
+	if err != nil {
+		return err
+	}
+	if keyID == "" {
+		return nil
+	}
+
+	return r.checkKeyIDForRef(ctx, refName, keyID)
+}
+
+// configuredSigningKeyID returns the ID of the signing key in the user's Git
+// config. If the key cannot be identified, the returned ID is empty.
+func configuredSigningKeyID() (string, error) {
+	signingMethod, keyInfo, err := gitinterface.GetSigningKeyInfo()
+	if err != nil {
+		return "", err
+	}
+
+	switch signingMethod {
+	case gitinterface.SigningMethodGPG:
+		// A trailing "!" tells gpg to use exactly the specified subkey
+		keyID := strings.ToLower(strings.TrimPrefix(strings.TrimSuffix(keyInfo, "!"), "0x"))
+		if !isHex(keyID) {
+			slog.Debug("Unable to identify configured GPG signing key, skipping check...")
+			return "", nil
+		}
+		return keyID, nil
+	case gitinterface.SigningMethodSSH:
+		key, err := ssh.NewKeyFromFile(keyInfo)
+		if err != nil {
+			slog.Debug(fmt.Sprintf("Unable to load configured SSH signing key: %s, skipping check...", err.Error()))
+			return "", nil
+		}
+		return key.KeyID, nil
+	default:
+		slog.Debug("Unable to identify configured signing key, skipping check...")
+		return "", nil
+	}
+}
+
+// checkKeyIDForRef checks if the key is trusted by the current policy for the
+// ref. GPG keys may be specified using their fingerprint or (long or short) key
+// ID, so a GPG key in the policy matches if its fingerprint or the fingerprint
+// of one of its subkeys ends with keyID.
+func (r *Repository) checkKeyIDForRef(ctx context.Context, refName, keyID string) error {
+	absRefName, err := gitinterface.AbsoluteReference(r.r, refName)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			// No policy to check against
+			return nil
+		}
+		return err
+	}
+
+	verifiers, err := state.FindVerifiersForPath(fmt.Sprintf("git:%s", absRefName))
+	if err != nil {
+		return err
+	}
+
+	if len(verifiers) == 0 {
+		// Ref is not protected
+		return nil
+	}
+
+	ruleNames := make([]string, 0, len(verifiers))
+	for _, verifier := range verifiers {
+		for _, key := range verifier.Keys() {
+			if key.KeyID == keyID {
+				return nil
+			}
+
+			if key.KeyType == signerverifier.GPGKeyType {
+				matches, err := gpg.MatchesKeyID(key, keyID)
+				if err != nil {
+					return err
+				}
+				if matches {
+					return nil
+				}
+			}
+		}
+
+		ruleNames = append(ruleNames, fmt.Sprintf("'%s'", verifier.Name()))
+	}
+
+	return fmt.Errorf("%w: '%s' is protected by rule(s) %s, which do not trust key '%s'", ErrSigningKeyNotTrustedForRef, absRefName, strings.Join(ruleNames, ", "), keyID)
+}
+
+func isHex(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+
+	return true
+}
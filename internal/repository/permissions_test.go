@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckKeyIDForRef(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	common.AddNTestCommitsToSpecifiedRef(t, repo.r, "refs/heads/main", 1, gpgKeyBytes)
+	common.AddNTestCommitsToSpecifiedRef(t, repo.r, "refs/heads/feature", 1, gpgKeyBytes)
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		refName       string
+		keyID         string
+		expectedError error
+	}{
+		"trusted key by fingerprint": {
+			refName: "main",
+			keyID:   gpgKey.KeyID,
+		},
+		"trusted key by short key ID": {
+			refName: "main",
+			keyID:   gpgKey.KeyID[len(gpgKey.KeyID)-8:],
+		},
+		"untrusted key": {
+			refName:       "main",
+			keyID:         "abcdef",
+			expectedError: ErrSigningKeyNotTrustedForRef,
+		},
+		"unprotected ref": {
+			refName: "feature",
+			keyID:   "abcdef",
+		},
+	}
+
+	for name, test := range tests {
+		err := repo.checkKeyIDForRef(testCtx, test.refName, test.keyID)
+		if test.expectedError == nil {
+			assert.Nil(t, err, name)
+		} else {
+			assert.ErrorIs(t, err, test.expectedError, name)
+			assert.Contains(t, err.Error(), "'protect-main'", name)
+		}
+	}
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
 var (
@@ -24,8 +26,17 @@ var (
 // Note that this also pushes the RSL as the policy cannot change without an
 // update to the RSL.
 func (r *Repository) PushPolicy(ctx context.Context, remoteName string) error {
+	refs, err := r.populatedRefs([]string{policy.PolicyRef, policy.PolicyStagingRef, rsl.Ref})
+	if err != nil {
+		return errors.Join(ErrPushingPolicy, err)
+	}
+	if len(refs) == 0 {
+		slog.Debug("Policy has not been initialized yet, nothing to push")
+		return nil
+	}
+
 	slog.Debug(fmt.Sprintf("Pushing policy and RSL references to %s...", remoteName))
-	if err := gitinterface.Push(ctx, r.r, remoteName, []string{policy.PolicyRef, policy.PolicyStagingRef, rsl.Ref}); err != nil {
+	if err := gitinterface.Push(ctx, r.r, remoteName, refs); err != nil {
 		return errors.Join(ErrPushingPolicy, err)
 	}
 
@@ -48,9 +59,187 @@ func (r *Repository) ApplyPolicy(ctx context.Context, signRSLEntry bool) error {
 	return policy.Apply(ctx, r.r, signRSLEntry)
 }
 
+// GetStagedPolicyChanges reports how the policy in the staging area differs
+// from the applied policy.
+func (r *Repository) GetStagedPolicyChanges(ctx context.Context) (*policy.StateDiff, error) {
+	return policy.GetStagedChanges(ctx, r.r)
+}
+
+// DiffPolicy reports how the policy state in effect at the RSL entry toEntryID
+// differs from the policy state in effect at the RSL entry fromEntryID. If
+// toEntryID is empty, the latest applied policy is used.
+func (r *Repository) DiffPolicy(ctx context.Context, fromEntryID, toEntryID string) (*policy.StateDiff, error) {
+	if !plumbing.IsHash(fromEntryID) {
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidObjectID, fromEntryID)
+	}
+
+	slog.Debug(fmt.Sprintf("Loading policy for entry '%s'...", fromEntryID))
+	fromState, err := policy.LoadStateForEntryID(ctx, r.r, plumbing.NewHash(fromEntryID))
+	if err != nil {
+		return nil, err
+	}
+
+	var toState *policy.State
+	if toEntryID == "" {
+		slog.Debug("Loading latest policy...")
+		toState, err = policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if !plumbing.IsHash(toEntryID) {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidObjectID, toEntryID)
+		}
+
+		slog.Debug(fmt.Sprintf("Loading policy for entry '%s'...", toEntryID))
+		toState, err = policy.LoadStateForEntryID(ctx, r.r, plumbing.NewHash(toEntryID))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return policy.DiffStates(fromState, toState)
+}
+
+// DiscardPolicy drops the changes in the policy staging area, resetting it to
+// the applied policy.
+func (r *Repository) DiscardPolicy(signRSLEntry bool) error {
+	return policy.Discard(r.r, signRSLEntry)
+}
+
 func (r *Repository) ListRules(ctx context.Context, targetRef string) ([]*policy.DelegationWithDepth, error) {
 	if strings.HasPrefix(targetRef, "refs/gittuf/") {
 		return policy.ListRules(ctx, r.r, targetRef)
 	}
 	return policy.ListRules(ctx, r.r, "refs/gittuf/"+targetRef)
 }
+
+// GetDelegationGraph returns the delegation graph of the policy in the
+// specified policy ref, rooted at the Root role.
+func (r *Repository) GetDelegationGraph(ctx context.Context, targetRef string) (*policy.GraphNode, error) {
+	if !strings.HasPrefix(targetRef, "refs/gittuf/") {
+		targetRef = "refs/gittuf/" + targetRef
+	}
+
+	slog.Debug("Loading policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, targetRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return state.DelegationGraph()
+}
+
+// ListRulesForPath returns the rules that apply to the specified path in the
+// specified policy ref, i.e., the rules whose authorized keys may modify the
+// path. The path must be of the form `git:<ref>` or `file:<path>`. If no
+// scheme is specified, the path is assumed to be a file path.
+func (r *Repository) ListRulesForPath(ctx context.Context, targetRef, path string) ([]*policy.Verifier, error) {
+	if !strings.HasPrefix(targetRef, "refs/gittuf/") {
+		targetRef = "refs/gittuf/" + targetRef
+	}
+
+	slog.Debug("Loading policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, targetRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return state.FindVerifiersForPath(qualifyRulePath(path))
+}
+
+// GetRulesForChanges identifies the file paths changed between the two
+// revisions and maps each path to the rules that apply to it in the current
+// policy. This identifies whose signatures are necessary for the changes to
+// be merged. Paths that aren't protected by any rule are mapped to an empty
+// list.
+func (r *Repository) GetRulesForChanges(ctx context.Context, fromRevision, toRevision string) (map[string][]*policy.Verifier, error) {
+	slog.Debug("Identifying changed paths...")
+	fromCommit, err := r.getCommitForRevision(fromRevision)
+	if err != nil {
+		return nil, err
+	}
+	toCommit, err := r.getCommitForRevision(toRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := gitinterface.GetDiffFilePaths(fromCommit, toCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make(map[string][]*policy.Verifier, len(paths))
+	for _, path := range paths {
+		verifiers, err := state.FindVerifiersForPath(qualifyRulePath(path))
+		if err != nil {
+			return nil, err
+		}
+		rules[path] = verifiers
+	}
+
+	return rules, nil
+}
+
+// ExportAllowedSigners returns the SSH keys trusted in the current policy in
+// the format of an SSH allowed_signers file.
+func (r *Repository) ExportAllowedSigners(ctx context.Context) ([]byte, error) {
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Exporting SSH keys...")
+	return state.ExportAllowedSigners()
+}
+
+// ExportGPGKeyring returns the GPG keys trusted in the current policy as an
+// armored keyring.
+func (r *Repository) ExportGPGKeyring(ctx context.Context) ([]byte, error) {
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Exporting GPG keys...")
+	return state.ExportGPGKeyring()
+}
+
+// GetPolicySigningStatus reports which roles in the policy staging area have a
+// threshold of signatures, and which authorized keys have not yet signed each
+// role.
+func (r *Repository) GetPolicySigningStatus(ctx context.Context) ([]*policy.RoleSigningStatus, error) {
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Checking signatures on policy metadata...")
+	return state.GetSigningStatus(ctx)
+}
+
+func qualifyRulePath(path string) string {
+	if strings.HasPrefix(path, "git:") || strings.HasPrefix(path, "file:") {
+		return path
+	}
+
+	return "file:" + path
+}
+
+func (r *Repository) getCommitForRevision(revision string) (*object.Commit, error) {
+	commitID, err := r.r.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, err
+	}
+
+	return gitinterface.GetCommit(r.r, *commitID)
+}
@@ -4,15 +4,17 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
-	"github.com/go-git/go-billy/v5/memfs"
-	"github.com/go-git/go-git/v5"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
-	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -22,12 +24,12 @@ func TestPushPolicy(t *testing.T) {
 	t.Run("successful push", func(t *testing.T) {
 		remoteTmpDir := t.TempDir()
 
-		remoteRepo, err := git.PlainInit(remoteTmpDir, true)
+		remoteRepo, err := gitinterface.PlainInit(remoteTmpDir, true)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		localRepo := createTestRepositoryWithPolicy(t, "")
+		localRepo := createTestRepositoryWithPolicy(t, t.TempDir())
 
 		if err := policy.Apply(context.Background(), localRepo.r, false); err != nil {
 			t.Fatal(err)
@@ -55,7 +57,7 @@ func TestPushPolicy(t *testing.T) {
 	t.Run("divergent policies, unsuccessful push", func(t *testing.T) {
 		remoteTmpDir := t.TempDir()
 
-		remoteRepo, err := git.PlainInit(remoteTmpDir, true)
+		remoteRepo, err := gitinterface.PlainInit(remoteTmpDir, true)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -68,7 +70,7 @@ func TestPushPolicy(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		localRepo := createTestRepositoryWithPolicy(t, "")
+		localRepo := createTestRepositoryWithPolicy(t, t.TempDir())
 
 		if err := policy.Apply(context.Background(), localRepo.r, false); err != nil {
 			t.Fatal(err)
@@ -96,7 +98,7 @@ func TestPullPolicy(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		localRepoR, err := git.Init(memory.NewStorage(), memfs.New())
+		localRepoR, err := gitinterface.PlainInit(t.TempDir(), false)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -124,7 +126,7 @@ func TestPullPolicy(t *testing.T) {
 		remoteTmpDir := t.TempDir()
 		createTestRepositoryWithPolicy(t, remoteTmpDir)
 
-		localRepoR, err := git.Init(memory.NewStorage(), memfs.New())
+		localRepoR, err := gitinterface.PlainInit(t.TempDir(), false)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -149,3 +151,252 @@ func TestPullPolicy(t *testing.T) {
 		assert.ErrorIs(t, err, ErrPullingPolicy)
 	})
 }
+
+func TestGetPolicySigningStatus(t *testing.T) {
+	r, _ := createTestRepositoryWithRoot(t, "")
+
+	rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootKeyID, err := rootSigner.KeyID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secondKey, err := tuf.LoadKeyFromBytes(targetsPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddRootKey(testCtx, rootSigner, secondKey, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.UpdateRootThreshold(testCtx, rootSigner, 2, false); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := r.GetPolicySigningStatus(testCtx)
+	assert.Nil(t, err)
+	assert.Len(t, statuses, 1)
+	assert.Equal(t, policy.RootRoleName, statuses[0].Name)
+	assert.Equal(t, 2, statuses[0].Threshold)
+	assert.Equal(t, []string{rootKeyID}, statuses[0].SignedBy)
+	assert.Equal(t, []string{secondKey.KeyID}, statuses[0].Missing)
+	assert.False(t, statuses[0].IsFullySigned())
+
+	secondSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SignRoot(testCtx, secondSigner, false); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err = r.GetPolicySigningStatus(testCtx)
+	assert.Nil(t, err)
+	assert.Empty(t, statuses[0].Missing)
+	assert.True(t, statuses[0].IsFullySigned())
+}
+
+func TestStagedPolicyChanges(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := r.GetStagedPolicyChanges(testCtx)
+	assert.Nil(t, err)
+	assert.False(t, changes.HasChanges())
+
+	secondKey, err := tuf.LoadKeyFromBytes(targetsPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddRootKey(testCtx, rootSigner, secondKey, false); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err = r.GetStagedPolicyChanges(testCtx)
+	assert.Nil(t, err)
+	assert.True(t, changes.HasChanges())
+	assert.True(t, changes.RootMetadataChanged)
+	assert.Equal(t, []string{secondKey.KeyID}, changes.GetRoleDiff(policy.RootRoleName).AddedKeys)
+
+	err = r.DiscardPolicy(false)
+	assert.Nil(t, err)
+
+	changes, err = r.GetStagedPolicyChanges(testCtx)
+	assert.Nil(t, err)
+	assert.False(t, changes.HasChanges())
+}
+
+func TestDiffPolicy(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	firstEntry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, policy.PolicyRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddDelegation(testCtx, targetsSigner, policy.TargetsRoleName, "protect-tags", []*tuf.Key{gpgKey}, []string{"git:refs/tags/*"}, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.ApplyPolicy(testCtx, false); err != nil {
+		t.Fatal(err)
+	}
+
+	secondEntry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, policy.PolicyRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := r.DiffPolicy(testCtx, firstEntry.ID.String(), "")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"protect-tags"}, diff.AddedRules)
+	assert.Empty(t, diff.RemovedRules)
+	assert.False(t, diff.RootMetadataChanged)
+
+	diff, err = r.DiffPolicy(testCtx, secondEntry.ID.String(), firstEntry.ID.String())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"protect-tags"}, diff.RemovedRules)
+	assert.Empty(t, diff.AddedRules)
+
+	diff, err = r.DiffPolicy(testCtx, secondEntry.ID.String(), secondEntry.ID.String())
+	assert.Nil(t, err)
+	assert.False(t, diff.HasChanges())
+
+	_, err = r.DiffPolicy(testCtx, "not-an-entry", "")
+	assert.ErrorIs(t, err, ErrInvalidObjectID)
+}
+
+func TestListRulesForPath(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+	addTestGlobstarFileRule(t, repo)
+
+	tests := map[string]struct {
+		path          string
+		expectedRules []string
+	}{
+		"ref path": {
+			path:          "git:refs/heads/main",
+			expectedRules: []string{"protect-main"},
+		},
+		"nested file path": {
+			path:          "file:src/internal/main.go",
+			expectedRules: []string{"protect-go-files"},
+		},
+		"file path without scheme": {
+			path:          "src/main.go",
+			expectedRules: []string{"protect-go-files"},
+		},
+		"unprotected file path": {
+			path:          "file:src/README.md",
+			expectedRules: []string{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			verifiers, err := repo.ListRulesForPath(testCtx, "policy", test.path)
+			assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+
+			ruleNames := []string{}
+			for _, verifier := range verifiers {
+				ruleNames = append(ruleNames, verifier.Name())
+			}
+			assert.Equal(t, test.expectedRules, ruleNames, fmt.Sprintf("unexpected rules in test '%s'", name))
+		})
+	}
+}
+
+func TestGetRulesForChanges(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+	addTestGlobstarFileRule(t, repo)
+
+	emptyBlobID, err := gitinterface.WriteBlob(repo.r, []byte{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeBuilder := gitinterface.NewTreeBuilder(repo.r)
+
+	fromTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(map[string]plumbing.Hash{"README.md": emptyBlobID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromCommitID, err := gitinterface.Commit(repo.r, fromTreeID, "refs/heads/main", "Initial commit\n", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	toTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(map[string]plumbing.Hash{"README.md": emptyBlobID, "src/cmd/main.go": emptyBlobID, "src/notes.txt": emptyBlobID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	toCommitID, err := gitinterface.Commit(repo.r, toTreeID, "refs/heads/main", "Add source\n", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := repo.GetRulesForChanges(testCtx, fromCommitID.String(), toCommitID.String())
+	assert.Nil(t, err)
+	assert.Len(t, rules, 2)
+	assert.Len(t, rules["src/notes.txt"], 0)
+	if assert.Len(t, rules["src/cmd/main.go"], 1) {
+		assert.Equal(t, "protect-go-files", rules["src/cmd/main.go"][0].Name())
+	}
+
+	_, err = repo.GetRulesForChanges(testCtx, fromCommitID.String(), "does-not-exist")
+	assert.NotNil(t, err)
+}
+
+func addTestGlobstarFileRule(t *testing.T, repo *Repository) {
+	t.Helper()
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.AddDelegation(testCtx, targetsSigner, policy.TargetsRoleName, "protect-go-files", []*tuf.Key{gpgKey}, []string{"file:src/**/*.go"}, 1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := policy.Apply(testCtx, repo.r, false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportGPGKeyring(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	keyring, err := repo.ExportGPGKeyring(testCtx)
+	assert.Nil(t, err)
+
+	exportedKey, err := gpg.LoadGPGKeyFromBytes(keyring)
+	assert.Nil(t, err)
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, gpgKey.KeyID, exportedKey.KeyID)
+
+	allowedSigners, err := repo.ExportAllowedSigners(testCtx)
+	assert.Nil(t, err)
+	assert.Empty(t, allowedSigners)
+}
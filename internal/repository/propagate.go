@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var (
+	ErrUpstreamRSLEmpty       = errors.New("upstream repository's RSL is empty")
+	ErrNoPropagationEntry     = errors.New("RSL has no propagation entries for upstream repository")
+	ErrUpstreamRSLRewritten   = errors.New("upstream repository's RSL no longer contains propagated entry")
+	ErrDivergedFromUpstream   = errors.New("ref has diverged from upstream repository")
+	ErrAlreadyPropagatedEntry = errors.New("latest upstream RSL entry has already been propagated")
+)
+
+// PropagateFromUpstream records a propagation entry in the RSL for the latest
+// entry in the RSL of the upstream repository configured as the specified
+// remote. The propagation entry indicates the repository has incorporated the
+// upstream's changes up to that entry, so that the repository can later be
+// checked for divergence from its upstream using VerifyPropagation.
+func (r *Repository) PropagateFromUpstream(ctx context.Context, upstreamRemoteName string, signCommit bool) error {
+	upstreamRepository, upstreamTip, err := r.fetchUpstreamRSL(ctx, upstreamRemoteName)
+	if err != nil {
+		return err
+	}
+
+	propagationEntries, err := rsl.GetLatestPropagationEntries(r.r, upstreamRepository)
+	if err != nil && !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return err
+	}
+	if len(propagationEntries) != 0 && propagationEntries[0].UpstreamEntryID == upstreamTip {
+		return ErrAlreadyPropagatedEntry
+	}
+
+	slog.Debug(fmt.Sprintf("Creating RSL propagation entry for upstream entry '%s'...", upstreamTip.String()))
+	return rsl.NewPropagationEntry(upstreamRepository, upstreamTip).Commit(r.r, signCommit)
+}
+
+// VerifyPropagation checks that the repository has not diverged from the
+// upstream repository configured as the specified remote. First, the upstream
+// entries recorded in the RSL's propagation entries must be present, in order,
+// in the upstream's current RSL, i.e., the upstream's RSL must not have been
+// rewritten since. Next, for each protected ref, the latest state of the ref
+// recorded in the RSL must include the state of the ref in the upstream as of
+// the latest propagation entry. If refNames is empty, all refs recorded in
+// both the RSL and the upstream's RSL are protected.
+func (r *Repository) VerifyPropagation(ctx context.Context, upstreamRemoteName string, refNames []string) error {
+	upstreamRepository, upstreamTip, err := r.fetchUpstreamRSL(ctx, upstreamRemoteName)
+	if err != nil {
+		return err
+	}
+
+	propagationEntries, err := rsl.GetLatestPropagationEntries(r.r, upstreamRepository)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return ErrNoPropagationEntry
+		}
+		return err
+	}
+
+	slog.Debug("Verifying propagated entries are in upstream RSL...")
+	laterEntryID := upstreamTip
+	for _, propagationEntry := range propagationEntries {
+		propagatedEntry, err := gitinterface.GetCommit(r.r, propagationEntry.UpstreamEntryID)
+		if err != nil {
+			return fmt.Errorf("%w: '%s'", ErrUpstreamRSLRewritten, propagationEntry.UpstreamEntryID.String())
+		}
+
+		knows, err := gitinterface.KnowsCommit(r.r, laterEntryID, propagatedEntry)
+		if err != nil {
+			return err
+		}
+		if !knows {
+			return fmt.Errorf("%w: '%s'", ErrUpstreamRSLRewritten, propagationEntry.UpstreamEntryID.String())
+		}
+
+		laterEntryID = propagationEntry.UpstreamEntryID
+	}
+
+	slog.Debug("Loading upstream ref states as of latest propagation entry...")
+	upstreamEntries, err := rsl.GetLatestUnskippedReferenceEntriesFrom(r.r, propagationEntries[0].UpstreamEntryID)
+	if err != nil {
+		return err
+	}
+
+	if len(refNames) == 0 {
+		refNames = make([]string, 0, len(upstreamEntries))
+		for refName := range upstreamEntries {
+			refNames = append(refNames, refName)
+		}
+	}
+
+	for _, refName := range refNames {
+		absRefName, err := gitinterface.AbsoluteReference(r.r, refName)
+		if err != nil {
+			if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+				return err
+			}
+			absRefName = refName
+		}
+
+		upstreamEntry, isRecordedUpstream := upstreamEntries[absRefName]
+		if !isRecordedUpstream {
+			slog.Debug(fmt.Sprintf("Ref '%s' is not recorded in upstream RSL, skipping...", absRefName))
+			continue
+		}
+
+		latestEntry, _, err := rsl.GetLatestUnskippedReferenceEntryForRef(r.r, absRefName)
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				slog.Debug(fmt.Sprintf("Ref '%s' is not recorded in RSL, skipping...", absRefName))
+				continue
+			}
+			return err
+		}
+
+		slog.Debug(fmt.Sprintf("Verifying '%s' includes upstream state '%s'...", absRefName, upstreamEntry.TargetID.String()))
+		if err := r.verifyIncludesUpstreamState(absRefName, latestEntry.TargetID, upstreamEntry.TargetID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyIncludesUpstreamState checks that the target of the ref is the same as
+// or a descendant of the upstream target. Tags must match the upstream target.
+func (r *Repository) verifyIncludesUpstreamState(refName string, targetID, upstreamTargetID plumbing.Hash) error {
+	if targetID == upstreamTargetID {
+		return nil
+	}
+
+	if strings.HasPrefix(refName, gitinterface.TagRefPrefix) {
+		return fmt.Errorf("%w: '%s'", ErrDivergedFromUpstream, refName)
+	}
+
+	// If the upstream target isn't available locally, it can't be included in
+	// the ref
+	upstreamCommit, err := gitinterface.GetCommit(r.r, upstreamTargetID)
+	if err != nil {
+		return fmt.Errorf("%w: '%s'", ErrDivergedFromUpstream, refName)
+	}
+
+	knows, err := gitinterface.KnowsCommit(r.r, targetID, upstreamCommit)
+	if err != nil {
+		return err
+	}
+	if !knows {
+		return fmt.Errorf("%w: '%s'", ErrDivergedFromUpstream, refName)
+	}
+
+	return nil
+}
+
+// fetchUpstreamRSL fetches the RSL of the upstream repository configured as the
+// specified remote to the remote's RSL tracker. The fetch is not fast-forward
+// only so that rewrites of the upstream RSL can be detected. The location of
+// the upstream repository and the ID of its latest RSL entry are returned.
+func (r *Repository) fetchUpstreamRSL(ctx context.Context, upstreamRemoteName string) (string, plumbing.Hash, error) {
+	remote, err := r.r.Remote(upstreamRemoteName)
+	if err != nil {
+		return "", plumbing.ZeroHash, err
+	}
+	upstreamRepository := remote.Config().URLs[0]
+
+	trackerRef := rsl.RemoteTrackerRef(upstreamRemoteName)
+	refSpecs := []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", rsl.Ref, trackerRef))}
+
+	slog.Debug(fmt.Sprintf("Fetching RSL of upstream repository '%s'...", upstreamRepository))
+	if err := gitinterface.FetchRefSpec(ctx, r.r, upstreamRemoteName, refSpecs); err != nil {
+		return "", plumbing.ZeroHash, err
+	}
+
+	ref, err := r.r.Reference(plumbing.ReferenceName(trackerRef), true)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return "", plumbing.ZeroHash, ErrUpstreamRSLEmpty
+		}
+		return "", plumbing.ZeroHash, err
+	}
+	if ref.Hash().IsZero() {
+		return "", plumbing.ZeroHash, ErrUpstreamRSLEmpty
+	}
+
+	return upstreamRepository, ref.Hash(), nil
+}
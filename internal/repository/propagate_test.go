@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropagation(t *testing.T) {
+	upstreamRemoteName := "origin"
+	refName := "refs/heads/main"
+
+	createUpstreamAndDownstream := func(t *testing.T) (*Repository, *Repository, string) {
+		t.Helper()
+
+		tmpDir := t.TempDir()
+
+		upstreamR, err := gitinterface.PlainInit(tmpDir, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		upstreamRepo := &Repository{r: upstreamR}
+
+		if err := rsl.InitializeNamespace(upstreamRepo.r); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := gitinterface.Commit(upstreamRepo.r, gitinterface.EmptyTree(), refName, "Test commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := upstreamRepo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		downstreamR, err := gitinterface.CloneAndFetch(context.Background(), tmpDir, t.TempDir(), refName, []string{rsl.Ref})
+		if err != nil {
+			t.Fatal(err)
+		}
+		downstreamRepo := &Repository{r: downstreamR}
+
+		return upstreamRepo, downstreamRepo, tmpDir
+	}
+
+	t.Run("no propagation entry", func(t *testing.T) {
+		_, downstreamRepo, _ := createUpstreamAndDownstream(t)
+
+		err := downstreamRepo.VerifyPropagation(testCtx, upstreamRemoteName, nil)
+		assert.ErrorIs(t, err, ErrNoPropagationEntry)
+	})
+
+	t.Run("propagate and verify", func(t *testing.T) {
+		upstreamRepo, downstreamRepo, upstreamLocation := createUpstreamAndDownstream(t)
+
+		err := downstreamRepo.PropagateFromUpstream(testCtx, upstreamRemoteName, false)
+		assert.Nil(t, err)
+
+		upstreamTip, err := rsl.GetLatestEntry(upstreamRepo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries, err := rsl.GetLatestPropagationEntries(downstreamRepo.r, upstreamLocation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, upstreamTip.GetID(), entries[0].UpstreamEntryID)
+
+		err = downstreamRepo.PropagateFromUpstream(testCtx, upstreamRemoteName, false)
+		assert.ErrorIs(t, err, ErrAlreadyPropagatedEntry)
+
+		err = downstreamRepo.VerifyPropagation(testCtx, upstreamRemoteName, nil)
+		assert.Nil(t, err)
+
+		// Downstream can build on the upstream state
+		if _, err := gitinterface.Commit(downstreamRepo.r, gitinterface.EmptyTree(), refName, "Downstream commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := downstreamRepo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		err = downstreamRepo.VerifyPropagation(testCtx, upstreamRemoteName, []string{"main"})
+		assert.Nil(t, err)
+
+		// Upstream changes that haven't been propagated yet are not checked
+		if _, err := gitinterface.Commit(upstreamRepo.r, gitinterface.EmptyTree(), refName, "Upstream commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := upstreamRepo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		err = downstreamRepo.VerifyPropagation(testCtx, upstreamRemoteName, nil)
+		assert.Nil(t, err)
+
+		// Once propagated, downstream must include them
+		err = downstreamRepo.PropagateFromUpstream(testCtx, upstreamRemoteName, false)
+		assert.Nil(t, err)
+
+		err = downstreamRepo.VerifyPropagation(testCtx, upstreamRemoteName, nil)
+		assert.ErrorIs(t, err, ErrDivergedFromUpstream)
+	})
+
+	t.Run("downstream diverged", func(t *testing.T) {
+		_, downstreamRepo, _ := createUpstreamAndDownstream(t)
+
+		err := downstreamRepo.PropagateFromUpstream(testCtx, upstreamRemoteName, false)
+		assert.Nil(t, err)
+
+		// Rewrite downstream's main to an unrelated commit
+		divergedID, err := gitinterface.Commit(downstreamRepo.r, gitinterface.EmptyTree(), "refs/heads/diverged", "Diverged commit", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := downstreamRepo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), divergedID)); err != nil {
+			t.Fatal(err)
+		}
+		if err := downstreamRepo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		err = downstreamRepo.VerifyPropagation(testCtx, upstreamRemoteName, nil)
+		assert.ErrorIs(t, err, ErrDivergedFromUpstream)
+
+		// Refs that aren't protected aren't checked
+		err = downstreamRepo.VerifyPropagation(testCtx, upstreamRemoteName, []string{"refs/heads/feature"})
+		assert.Nil(t, err)
+	})
+
+	t.Run("upstream RSL rewritten", func(t *testing.T) {
+		upstreamRepo, downstreamRepo, _ := createUpstreamAndDownstream(t)
+
+		err := downstreamRepo.PropagateFromUpstream(testCtx, upstreamRemoteName, false)
+		assert.Nil(t, err)
+
+		// Replace upstream's RSL with an unrelated history
+		if err := upstreamRepo.r.Storer.RemoveReference(plumbing.ReferenceName(rsl.Ref)); err != nil {
+			t.Fatal(err)
+		}
+		if err := rsl.NewReferenceEntry(refName, plumbing.ZeroHash).Commit(upstreamRepo.r, false); err != nil {
+			t.Fatal(err)
+		}
+
+		err = downstreamRepo.VerifyPropagation(testCtx, upstreamRemoteName, nil)
+		assert.ErrorIs(t, err, ErrUpstreamRSLRewritten)
+	})
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// AddProvenanceAttestations attaches SLSA provenance produced by an external
+// builder, such as GitHub Actions or GitLab CI, to the specified commit or tag
+// in the attestations namespace. The target may be a ref, in which case the
+// object it points to is used, or the ID of a commit or tag object. Each
+// provenance attestation must reference the object, or the commit a tag points
+// to, in its subjects or source materials. The attestations' signatures are
+// not checked when they are attached, they are verified against the policy
+// when a tag that requires provenance is verified.
+func (r *Repository) AddProvenanceAttestations(target string, envelopes []*sslibdsse.Envelope, signCommit bool) error {
+	objectID, err := r.resolveProvenanceTarget(target)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current set of attestations...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return err
+	}
+
+	for _, env := range envelopes {
+		builderID, err := attestations.GetProvenanceBuilderID(env)
+		if err != nil {
+			return err
+		}
+
+		slog.Debug(fmt.Sprintf("Adding provenance from '%s' for '%s'...", builderID, objectID.String()))
+		if err := allAttestations.SetProvenanceAttestation(r.r, env, objectID.String()); err != nil {
+			return err
+		}
+	}
+
+	commitMessage := fmt.Sprintf("Add provenance for '%s'", target)
+
+	slog.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// resolveProvenanceTarget returns the ID of the commit or tag object
+// identified by the target.
+func (r *Repository) resolveProvenanceTarget(target string) (plumbing.Hash, error) {
+	refName, err := gitinterface.AbsoluteReference(r.r, target)
+	if err == nil {
+		ref, err := r.r.Reference(plumbing.ReferenceName(refName), true)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+
+		return ref.Hash(), nil
+	}
+	if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return plumbing.ZeroHash, err
+	}
+
+	if !plumbing.IsHash(target) {
+		return plumbing.ZeroHash, fmt.Errorf("%w: '%s'", ErrInvalidObjectID, target)
+	}
+
+	objectID := plumbing.NewHash(target)
+	if _, err := gitinterface.GetCommit(r.r, objectID); err != nil {
+		if _, err := gitinterface.GetTag(r.r, objectID); err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("%w: '%s' is not a commit or tag", ErrInvalidObjectID, target)
+		}
+	}
+
+	return objectID, nil
+}
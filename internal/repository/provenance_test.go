@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddProvenanceAttestations(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, "refs/heads/main", 2, gpgKeyBytes)
+	tagID := common.CreateTestSignedTag(t, repo.r, "v1", commitIDs[1], gpgKeyBytes)
+
+	builderID := "https://github.com/gittuf/gittuf/.github/workflows/release.yml@refs/tags/v1"
+	env := common.CreateTestSLSAProvenanceEnvelope(t, builderID, commitIDs[1])
+
+	t.Run("invalid target", func(t *testing.T) {
+		err := repo.AddProvenanceAttestations("v2", []*sslibdsse.Envelope{env}, false)
+		assert.ErrorIs(t, err, ErrInvalidObjectID)
+
+		err = repo.AddProvenanceAttestations(plumbing.ZeroHash.String(), []*sslibdsse.Envelope{env}, false)
+		assert.ErrorIs(t, err, ErrInvalidObjectID)
+	})
+
+	t.Run("provenance for other commit", func(t *testing.T) {
+		err := repo.AddProvenanceAttestations(commitIDs[0].String(), []*sslibdsse.Envelope{env}, false)
+		assert.ErrorIs(t, err, attestations.ErrProvenanceNotForObject)
+	})
+
+	t.Run("provenance for tag", func(t *testing.T) {
+		err := repo.AddProvenanceAttestations("v1", []*sslibdsse.Envelope{env}, false)
+		assert.Nil(t, err)
+
+		allAttestations, err := attestations.LoadCurrentAttestations(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		storedEnvs, err := allAttestations.GetProvenanceAttestationsFor(repo.r, tagID.String())
+		assert.Nil(t, err)
+		assert.Equal(t, []*sslibdsse.Envelope{env}, storedEnvs)
+	})
+
+	t.Run("provenance for commit", func(t *testing.T) {
+		err := repo.AddProvenanceAttestations(commitIDs[1].String(), []*sslibdsse.Envelope{env}, false)
+		assert.Nil(t, err)
+
+		allAttestations, err := attestations.LoadCurrentAttestations(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = allAttestations.GetProvenanceAttestationsFor(repo.r, commitIDs[1].String())
+		assert.Nil(t, err)
+	})
+}
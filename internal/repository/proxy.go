@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/identities"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	// ProxyNamespace is the Git namespace, see gitnamespaces(7), that the
+	// refs verified by the proxy are served from.
+	ProxyNamespace = "gittuf-verified"
+
+	proxyNamespaceRefPrefix = "refs/namespaces/" + ProxyNamespace + "/"
+	proxyUpstreamRefPrefix  = "refs/gittuf/proxy-upstream/"
+)
+
+// proxiedGittufRefs are the gittuf refs served by the proxy, in addition to the
+// RSL shards.
+var proxiedGittufRefs = []string{rsl.Ref, policy.PolicyRef, policy.PolicyStagingRef, attestations.Ref, identities.Ref}
+
+var ErrUpstreamRefMismatch = errors.New("ref advertised by upstream does not match its latest RSL entry")
+
+// ProxyRefStatus records the outcome of verifying a ref advertised by the
+// proxy's upstream.
+type ProxyRefStatus struct {
+	RefName  string
+	TargetID plumbing.Hash
+	Verified bool
+	Err      error
+}
+
+// RefreshProxyRefs fetches the refs advertised by the specified remote and
+// verifies the branches and tags among them, updating the refs served from
+// ProxyNamespace. A ref is served at its advertised target only if the target
+// is recorded in the ref's latest RSL entry and that entry passes
+// verification. A ref that fails verification continues to be served at its
+// last verified target, while refs that are no longer advertised are no longer
+// served. The RSL, policy, and attestations are served as fetched, so that
+// consumers can verify the refs themselves.
+//
+// The RSL is fetched fast-forward only. If the upstream RSL has diverged from
+// the RSL fetched previously, an error is returned and the served refs are not
+// updated.
+func (r *Repository) RefreshProxyRefs(ctx context.Context, remoteName string) ([]*ProxyRefStatus, error) {
+	slog.Debug(fmt.Sprintf("Listing refs advertised by '%s'...", remoteName))
+	advertisedRefs, err := gitinterface.ListRemoteReferences(ctx, r.r, remoteName)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		gittufRefs  = []string{}
+		targetRefs  = map[string]plumbing.Hash{}
+		headRefName string
+		refSpecs    = []config.RefSpec{}
+	)
+	for _, ref := range advertisedRefs {
+		refName := ref.Name().String()
+		switch {
+		case ref.Name() == plumbing.HEAD:
+			if ref.Type() == plumbing.SymbolicReference {
+				headRefName = ref.Target().String()
+			}
+		case slices.Contains(proxiedGittufRefs, refName) || rsl.IsShardRef(refName):
+			if !ref.Hash().IsZero() {
+				gittufRefs = append(gittufRefs, refName)
+			}
+		case isProxiedRef(refName) && ref.Type() == plumbing.HashReference:
+			targetRefs[refName] = ref.Hash()
+			refSpecs = append(refSpecs, config.RefSpec(fmt.Sprintf("+%s:%s%s", refName, proxyUpstreamRefPrefix, refName)))
+		}
+	}
+
+	if len(gittufRefs) != 0 {
+		slog.Debug("Fetching gittuf refs...")
+		if err := gitinterface.Fetch(ctx, r.r, remoteName, gittufRefs, true); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(refSpecs) != 0 {
+		slog.Debug("Fetching advertised branches and tags...")
+		if err := gitinterface.FetchRefSpec(ctx, r.r, remoteName, refSpecs); err != nil {
+			return nil, err
+		}
+	}
+	defer r.removeProxyUpstreamRefs(targetRefs)
+
+	statuses := make([]*ProxyRefStatus, 0, len(targetRefs))
+	for refName, targetID := range targetRefs {
+		status := &ProxyRefStatus{RefName: refName, TargetID: targetID}
+		statuses = append(statuses, status)
+
+		slog.Debug(fmt.Sprintf("Verifying '%s'...", refName))
+		expectedTargetID, err := policy.VerifyRef(ctx, r.r, refName)
+		if err != nil {
+			status.Err = err
+			continue
+		}
+		if expectedTargetID != targetID {
+			status.Err = fmt.Errorf("%w: '%s' is at '%s', RSL records '%s'", ErrUpstreamRefMismatch, refName, targetID.String(), expectedTargetID.String())
+			continue
+		}
+
+		status.Verified = true
+		if err := r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(proxyNamespaceRefPrefix+refName), targetID)); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].RefName < statuses[j].RefName })
+
+	// Refs that are no longer advertised are no longer served
+	servedRefs, err := r.r.References()
+	if err != nil {
+		return nil, err
+	}
+	staleRefs := []plumbing.ReferenceName{}
+	if err := servedRefs.ForEach(func(ref *plumbing.Reference) error {
+		refName, isServed := strings.CutPrefix(ref.Name().String(), proxyNamespaceRefPrefix)
+		if !isServed || !isProxiedRef(refName) {
+			return nil
+		}
+		if _, isAdvertised := targetRefs[refName]; !isAdvertised {
+			staleRefs = append(staleRefs, ref.Name())
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	for _, refName := range staleRefs {
+		slog.Debug(fmt.Sprintf("Removing '%s' as it is no longer advertised...", refName))
+		if err := r.r.Storer.RemoveReference(refName); err != nil {
+			return nil, err
+		}
+	}
+
+	// The gittuf refs are served if the root of trust of the fetched policy
+	// is verified
+	if _, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef); err == nil {
+		for _, refName := range gittufRefs {
+			ref, err := r.r.Reference(plumbing.ReferenceName(refName), true)
+			if err != nil {
+				return nil, err
+			}
+			if err := r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(proxyNamespaceRefPrefix+refName), ref.Hash())); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		slog.Debug(fmt.Sprintf("Not serving gittuf refs as policy could not be loaded: %s", err.Error()))
+	}
+
+	if headRefName != "" {
+		if _, err := r.r.Reference(plumbing.ReferenceName(proxyNamespaceRefPrefix+headRefName), false); err == nil {
+			if err := r.r.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.ReferenceName(proxyNamespaceRefPrefix+plumbing.HEAD.String()), plumbing.ReferenceName(proxyNamespaceRefPrefix+headRefName))); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return statuses, nil
+}
+
+// removeProxyUpstreamRefs removes the refs the advertised refs were fetched
+// into. The objects they point to remain reachable from the served refs if
+// they were verified.
+func (r *Repository) removeProxyUpstreamRefs(targetRefs map[string]plumbing.Hash) {
+	for refName := range targetRefs {
+		if err := r.r.Storer.RemoveReference(plumbing.ReferenceName(proxyUpstreamRefPrefix + refName)); err != nil {
+			slog.Debug(fmt.Sprintf("Unable to remove '%s': %s", proxyUpstreamRefPrefix+refName, err.Error()))
+		}
+	}
+}
+
+// isProxiedRef checks if the ref is a branch or tag, which are verified and
+// served by the proxy.
+func isProxiedRef(refName string) bool {
+	return strings.HasPrefix(refName, gitinterface.BranchRefPrefix) || strings.HasPrefix(refName, gitinterface.TagRefPrefix)
+}
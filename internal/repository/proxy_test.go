@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshProxyRefs(t *testing.T) {
+	remoteName := "origin"
+	mainRefName := "refs/heads/main"
+	featureRefName := "refs/heads/feature"
+	unrecordedRefName := "refs/heads/unrecorded"
+
+	upstreamDir := t.TempDir()
+	upstream := createTestRepositoryWithPolicy(t, upstreamDir)
+	if err := upstream.r.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.ReferenceName(mainRefName))); err != nil {
+		t.Fatal(err)
+	}
+
+	// main is protected, the commit and RSL entry are signed by the
+	// authorized key
+	mainCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, upstream.r, mainRefName, 1, gpgKeyBytes)
+	common.CreateTestRSLReferenceEntryCommit(t, upstream.r, rsl.NewReferenceEntry(mainRefName, mainCommitIDs[0]), gpgKeyBytes)
+
+	// feature isn't protected
+	featureCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, upstream.r, featureRefName, 1, gpgKeyBytes)
+	if err := rsl.NewReferenceEntry(featureRefName, featureCommitIDs[0]).Commit(upstream.r, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// unrecorded has no RSL entry
+	common.AddNTestCommitsToSpecifiedRef(t, upstream.r, unrecordedRefName, 2, gpgKeyBytes)
+
+	proxyRepo, err := gitinterface.PlainInit(t.TempDir(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := &Repository{r: proxyRepo}
+	if _, err := proxy.r.CreateRemote(&config.RemoteConfig{
+		Name: remoteName,
+		URLs: []string{upstreamDir},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := proxy.RefreshProxyRefs(testCtx, remoteName)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(statuses))
+	assert.Equal(t, featureRefName, statuses[0].RefName)
+	assert.True(t, statuses[0].Verified)
+	assert.Equal(t, mainRefName, statuses[1].RefName)
+	assert.True(t, statuses[1].Verified)
+	assert.Equal(t, unrecordedRefName, statuses[2].RefName)
+	assert.False(t, statuses[2].Verified)
+	assert.ErrorIs(t, statuses[2].Err, rsl.ErrRSLEntryNotFound)
+
+	assertServedRef(t, proxy, mainRefName, mainCommitIDs[0])
+	assertServedRef(t, proxy, featureRefName, featureCommitIDs[0])
+	assertServedRef(t, proxy, unrecordedRefName, plumbing.ZeroHash)
+	assertServedRef(t, proxy, rsl.Ref, getRefHash(t, upstream, rsl.Ref))
+
+	head, err := proxy.r.Reference(plumbing.ReferenceName(proxyNamespaceRefPrefix+"HEAD"), false)
+	assert.Nil(t, err)
+	assert.Equal(t, plumbing.ReferenceName(proxyNamespaceRefPrefix+mainRefName), head.Target())
+
+	// The refs the upstream's refs were fetched into are removed
+	_, err = proxy.r.Reference(plumbing.ReferenceName(proxyUpstreamRefPrefix+mainRefName), false)
+	assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+
+	t.Run("ref that fails verification is served at last verified target", func(t *testing.T) {
+		// The RSL entry isn't signed by the authorized key
+		newMainCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, upstream.r, mainRefName, 1, gpgKeyBytes)
+		if err := rsl.NewReferenceEntry(mainRefName, newMainCommitIDs[0]).Commit(upstream.r, false); err != nil {
+			t.Fatal(err)
+		}
+
+		statuses, err := proxy.RefreshProxyRefs(testCtx, remoteName)
+		assert.Nil(t, err)
+		assert.Equal(t, mainRefName, statuses[1].RefName)
+		assert.False(t, statuses[1].Verified)
+		assert.NotNil(t, statuses[1].Err)
+
+		assertServedRef(t, proxy, mainRefName, mainCommitIDs[0])
+	})
+
+	t.Run("ref that doesn't match RSL is not served", func(t *testing.T) {
+		newFeatureCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, upstream.r, featureRefName, 1, gpgKeyBytes)
+
+		statuses, err := proxy.RefreshProxyRefs(testCtx, remoteName)
+		assert.Nil(t, err)
+		assert.Equal(t, featureRefName, statuses[0].RefName)
+		assert.Equal(t, newFeatureCommitIDs[0], statuses[0].TargetID)
+		assert.ErrorIs(t, statuses[0].Err, ErrUpstreamRefMismatch)
+
+		assertServedRef(t, proxy, featureRefName, featureCommitIDs[0])
+	})
+
+	t.Run("ref that is no longer advertised is not served", func(t *testing.T) {
+		if err := upstream.r.Storer.RemoveReference(plumbing.ReferenceName(featureRefName)); err != nil {
+			t.Fatal(err)
+		}
+
+		statuses, err := proxy.RefreshProxyRefs(testCtx, remoteName)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(statuses))
+
+		assertServedRef(t, proxy, featureRefName, plumbing.ZeroHash)
+		assertServedRef(t, proxy, mainRefName, mainCommitIDs[0])
+	})
+}
+
+// assertServedRef checks that the ref is served by the proxy at the target. If
+// the target is the zero hash, the ref must not be served.
+func assertServedRef(t *testing.T, r *Repository, refName string, targetID plumbing.Hash) {
+	t.Helper()
+
+	ref, err := r.r.Reference(plumbing.ReferenceName(proxyNamespaceRefPrefix+refName), true)
+	if targetID.IsZero() {
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+		return
+	}
+	if assert.Nil(t, err) {
+		assert.Equal(t, targetID, ref.Hash())
+	}
+}
+
+func getRefHash(t *testing.T, r *Repository, refName string) plumbing.Hash {
+	t.Helper()
+
+	ref, err := r.r.Reference(plumbing.ReferenceName(refName), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ref.Hash()
+}
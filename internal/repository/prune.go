@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/revlist"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// DefaultPruneExpiry matches the default grace period used by `git gc` before
+// unreachable objects are pruned.
+const DefaultPruneExpiry = 14 * 24 * time.Hour
+
+var ErrLooseObjectsNotSupported = errors.New("repository storage does not support loose objects")
+
+// UnreachableObject records a gittuf-created object that is not reachable from
+// any ref.
+type UnreachableObject struct {
+	ID   plumbing.Hash
+	Type plumbing.ObjectType
+
+	// Packed indicates the object is stored in a packfile. Packed objects are
+	// not removed, they are dropped when `git gc` repacks the repository.
+	Packed bool
+
+	// Recent indicates the object is newer than the grace period, and may
+	// still be in use by a concurrent operation.
+	Recent bool
+
+	// Removed indicates the object was removed.
+	Removed bool
+}
+
+// PruneUnreachableGittufObjects identifies objects created by gittuf that are
+// no longer reachable from any ref, such as RSL entries, policy states, and
+// attestations left behind after gittuf refs are rewritten. Objects that are
+// reachable from any ref, including refs outside the gittuf namespace, are
+// never considered. Trees and blobs are only considered if they belong to an
+// unreachable gittuf commit, so unreachable objects created by other tools are
+// left for `git gc`.
+//
+// Unreachable loose objects older than the expiry time are removed unless
+// dryRun is set. Packed objects are only reported, as rewriting packfiles is
+// left to `git gc`, which drops unreachable objects when it repacks.
+func (r *Repository) PruneUnreachableGittufObjects(expiry time.Time, dryRun bool) ([]*UnreachableObject, error) {
+	los, isLooseObjectStorer := r.r.Storer.(storer.LooseObjectStorer)
+	if !isLooseObjectStorer {
+		return nil, ErrLooseObjectsNotSupported
+	}
+
+	slog.Debug("Identifying objects reachable from refs...")
+	reachable, err := r.reachableObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Identifying unreachable gittuf objects...")
+	unreachableIDs, err := r.unreachableGittufObjects(reachable)
+	if err != nil {
+		return nil, err
+	}
+
+	unreachableObjects := make([]*UnreachableObject, 0, len(unreachableIDs))
+	for _, id := range unreachableIDs {
+		obj, err := r.r.Storer.EncodedObject(plumbing.AnyObject, id)
+		if err != nil {
+			return nil, err
+		}
+
+		unreachableObject := &UnreachableObject{ID: id, Type: obj.Type()}
+		unreachableObjects = append(unreachableObjects, unreachableObject)
+
+		modTime, err := los.LooseObjectTime(id)
+		if err != nil {
+			// The object isn't stored loose
+			unreachableObject.Packed = true
+			continue
+		}
+		if !modTime.Before(expiry) {
+			unreachableObject.Recent = true
+			continue
+		}
+
+		if dryRun {
+			continue
+		}
+
+		slog.Debug(fmt.Sprintf("Removing unreachable %s '%s'...", obj.Type().String(), id.String()))
+		if err := los.DeleteLooseObject(id); err != nil {
+			return nil, err
+		}
+		unreachableObject.Removed = true
+	}
+
+	return unreachableObjects, nil
+}
+
+// reachableObjects returns the set of objects reachable from any ref in the
+// repository.
+func (r *Repository) reachableObjects() (map[plumbing.Hash]bool, error) {
+	refs, err := r.r.Storer.IterReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	tips := []plumbing.Hash{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference && !ref.Hash().IsZero() {
+			tips = append(tips, ref.Hash())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reachableIDs, err := revlist.Objects(r.r.Storer, tips, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	reachable := make(map[plumbing.Hash]bool, len(reachableIDs))
+	for _, id := range reachableIDs {
+		reachable[id] = true
+	}
+
+	return reachable, nil
+}
+
+// unreachableGittufObjects returns the IDs of the unreachable gittuf commits and
+// the unreachable trees and blobs they contain.
+func (r *Repository) unreachableGittufObjects(reachable map[plumbing.Hash]bool) ([]plumbing.Hash, error) {
+	commits, err := r.r.CommitObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	unreachableIDs := []plumbing.Hash{}
+	seen := map[plumbing.Hash]bool{}
+	err = commits.ForEach(func(commit *object.Commit) error {
+		if reachable[commit.Hash] {
+			return nil
+		}
+
+		tree, err := commit.Tree()
+		if err != nil {
+			return err
+		}
+
+		if !isGittufCommit(commit, tree) {
+			return nil
+		}
+
+		unreachableIDs = append(unreachableIDs, commit.Hash)
+
+		if !reachable[tree.Hash] && !seen[tree.Hash] {
+			seen[tree.Hash] = true
+			unreachableIDs = append(unreachableIDs, tree.Hash)
+		}
+
+		walker := object.NewTreeWalker(tree, true, nil)
+		defer walker.Close()
+		for {
+			_, entry, err := walker.Next()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return err
+			}
+
+			if reachable[entry.Hash] || seen[entry.Hash] {
+				continue
+			}
+			seen[entry.Hash] = true
+			unreachableIDs = append(unreachableIDs, entry.Hash)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return unreachableIDs, nil
+}
+
+// isGittufCommit returns true if the commit is an RSL entry, a policy state, or
+// an attestations state.
+func isGittufCommit(commit *object.Commit, tree *object.Tree) bool {
+	message := strings.TrimSpace(commit.Message)
+	for _, header := range []string{rsl.ReferenceEntryHeader, rsl.AnnotationEntryHeader, rsl.PropagationEntryHeader} {
+		if strings.HasPrefix(message, header) {
+			return true
+		}
+	}
+
+	return policy.IsPolicyTree(tree) || attestations.IsAttestationsTree(tree)
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPruneUnreachableGittufObjects(t *testing.T) {
+	refName := "refs/heads/main"
+
+	createTestRepository := func(t *testing.T) (*Repository, plumbing.Hash, plumbing.Hash) {
+		t.Helper()
+
+		r, err := gitinterface.PlainInit(t.TempDir(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		repo := &Repository{r: r}
+
+		if err := rsl.InitializeNamespace(repo.r); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := gitinterface.Commit(repo.r, gitinterface.EmptyTree(), refName, "Test commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
+			t.Fatal(err)
+		}
+		firstEntry, err := rsl.GetLatestEntry(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := rsl.NewAnnotationEntry([]plumbing.Hash{firstEntry.GetID()}, false, "test annotation").Commit(repo.r, false); err != nil {
+			t.Fatal(err)
+		}
+		annotationEntry, err := rsl.GetLatestEntry(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Rewind the RSL, leaving the annotation unreachable
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(rsl.Ref), firstEntry.GetID())); err != nil {
+			t.Fatal(err)
+		}
+
+		// Unreachable commits not created by gittuf must be left alone
+		otherCommitID, err := gitinterface.Commit(repo.r, gitinterface.EmptyTree(), "refs/heads/other", "Other commit", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.r.Storer.RemoveReference(plumbing.ReferenceName("refs/heads/other")); err != nil {
+			t.Fatal(err)
+		}
+
+		return repo, annotationEntry.GetID(), otherCommitID
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		repo, annotationID, _ := createTestRepository(t)
+
+		unreachableObjects, err := repo.PruneUnreachableGittufObjects(time.Now().Add(time.Hour), true)
+		assert.Nil(t, err)
+		assert.Equal(t, []*UnreachableObject{{ID: annotationID, Type: plumbing.CommitObject}}, unreachableObjects)
+
+		_, err = gitinterface.GetCommit(repo.r, annotationID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("recent objects are kept", func(t *testing.T) {
+		repo, annotationID, _ := createTestRepository(t)
+
+		unreachableObjects, err := repo.PruneUnreachableGittufObjects(time.Now().Add(-time.Hour), false)
+		assert.Nil(t, err)
+		assert.Equal(t, []*UnreachableObject{{ID: annotationID, Type: plumbing.CommitObject, Recent: true}}, unreachableObjects)
+
+		_, err = gitinterface.GetCommit(repo.r, annotationID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("remove unreachable objects", func(t *testing.T) {
+		repo, annotationID, otherCommitID := createTestRepository(t)
+
+		unreachableObjects, err := repo.PruneUnreachableGittufObjects(time.Now().Add(time.Hour), false)
+		assert.Nil(t, err)
+		assert.Equal(t, []*UnreachableObject{{ID: annotationID, Type: plumbing.CommitObject, Removed: true}}, unreachableObjects)
+
+		_, err = gitinterface.GetCommit(repo.r, annotationID)
+		assert.NotNil(t, err)
+
+		_, err = gitinterface.GetCommit(repo.r, otherCommitID)
+		assert.Nil(t, err)
+
+		// Reachable gittuf objects are kept
+		_, err = rsl.GetLatestEntry(repo.r)
+		assert.Nil(t, err)
+
+		unreachableObjects, err = repo.PruneUnreachableGittufObjects(time.Now().Add(time.Hour), false)
+		assert.Nil(t, err)
+		assert.Empty(t, unreachableObjects)
+	})
+}
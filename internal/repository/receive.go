@@ -0,0 +1,460 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/identities"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/storage"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// gitQuarantinePathEnvKey is the environment variable Git uses to tell
+// pre-receive hooks where the received objects are quarantined.
+const gitQuarantinePathEnvKey = "GIT_QUARANTINE_PATH"
+
+var (
+	ErrRefUpdateNotInRSL   = errors.New("ref update is not recorded in the RSL")
+	ErrRSLNotFastForward   = errors.New("RSL update is not a fast-forward of the current RSL")
+	ErrRSLUpdateNotAllowed = errors.New("RSL update was not allowed")
+	ErrInvalidRefUpdate    = errors.New("invalid ref update, expected '<old-id> <new-id> <ref-name>'")
+)
+
+// RefUpdate is a single ref update received by a Git server, such as when a
+// client pushes to it.
+type RefUpdate struct {
+	// Name is the absolute name of the ref, such as `refs/heads/main`.
+	Name string
+
+	// OldID is the ID the ref currently points to. It is the zero hash if the
+	// ref is being created.
+	OldID plumbing.Hash
+
+	// NewID is the ID the ref is being updated to. It is the zero hash if the
+	// ref is being deleted.
+	NewID plumbing.Hash
+}
+
+// RefUpdateDecision records whether a received ref update must be allowed.
+type RefUpdateDecision struct {
+	// Name is the absolute name of the ref the decision is for.
+	Name string
+
+	// Allowed indicates the update may be applied.
+	Allowed bool
+
+	// Reason is the reason the update was denied. It is nil if the update is
+	// allowed.
+	Reason error
+}
+
+// ParseReceivedRefUpdates parses ref updates in the format Git passes them to
+// pre-receive hooks on standard input, i.e., one `<old-id> <new-id> <ref-name>`
+// line per update.
+func ParseReceivedRefUpdates(input io.Reader) ([]*RefUpdate, error) {
+	return parseRefUpdates(input, false)
+}
+
+// ParseReferenceTransactionUpdates parses ref updates in the format Git passes
+// them to reference-transaction hooks on standard input. This is the format
+// used for pre-receive hooks, except that updates to symbolic refs, whose
+// values are of the form `ref:<target>`, may also be passed. These are skipped
+// as they don't change the object any ref points to.
+func ParseReferenceTransactionUpdates(input io.Reader) ([]*RefUpdate, error) {
+	return parseRefUpdates(input, true)
+}
+
+func parseRefUpdates(input io.Reader, skipSymbolicRefs bool) ([]*RefUpdate, error) {
+	updates := []*RefUpdate{}
+
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if skipSymbolicRefs && len(fields) == 3 && (strings.HasPrefix(fields[0], "ref:") || strings.HasPrefix(fields[1], "ref:")) {
+			continue
+		}
+
+		if len(fields) != 3 || !plumbing.IsHash(fields[0]) || !plumbing.IsHash(fields[1]) {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidRefUpdate, line)
+		}
+
+		updates = append(updates, &RefUpdate{
+			Name:  fields[2],
+			OldID: plumbing.NewHash(fields[0]),
+			NewID: plumbing.NewHash(fields[1]),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return updates, nil
+}
+
+// LoadRepositoryFromStorer loads a gittuf repository backed by the specified
+// go-git storer. This is meant for Git servers that embed go-git.
+func LoadRepositoryFromStorer(s storage.Storer) (*Repository, error) {
+	repo, err := git.Open(s, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Repository{r: repo}, nil
+}
+
+// LoadRepositoryForReceive loads the repository from a Git server's
+// pre-receive hook. Git quarantines the objects received in a push until the
+// pre-receive hook accepts the push, so objects are read from the quarantine
+// directory as well as the repository.
+func LoadRepositoryForReceive() (*Repository, error) {
+	repo, err := LoadRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	quarantinePath := os.Getenv(gitQuarantinePathEnvKey)
+	if quarantinePath == "" {
+		return repo, nil
+	}
+
+	slog.Debug(fmt.Sprintf("Loading quarantined objects from '%s'...", quarantinePath))
+	quarantineStorage := filesystem.NewStorage(&objectsDirFS{Filesystem: osfs.New(quarantinePath)}, cache.NewObjectLRUDefault())
+
+	r, err := git.Open(&quarantineStorer{Storer: repo.r.Storer, quarantine: quarantineStorage}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Repository{r: r}, nil
+}
+
+// VerifyReceivedRefUpdates decides if the ref updates received by a Git server
+// must be allowed. It is meant to be invoked in-process by servers embedding
+// go-git after the objects have been received but before any refs are updated,
+// like a pre-receive hook. An update is allowed only if it is recorded in the
+// updated RSL and passes verification against gittuf policy. The RSL update
+// itself must be a fast-forward; if it isn't allowed, no update is allowed. A
+// decision is returned for every update, in the same order. An error is
+// returned only if the decisions could not be made.
+func (r *Repository) VerifyReceivedRefUpdates(ctx context.Context, updates []*RefUpdate) ([]*RefUpdateDecision, error) {
+	slog.Debug("Applying received ref updates to a view of the repository...")
+	proposedRepo, err := r.withRefUpdates(updates)
+	if err != nil {
+		return nil, err
+	}
+
+	var rslUpdate *RefUpdate
+	for _, update := range updates {
+		if update.Name == rsl.Ref {
+			rslUpdate = update
+			break
+		}
+	}
+
+	if rslUpdate != nil {
+		slog.Debug("Verifying RSL update...")
+		if err := verifyRSLUpdate(proposedRepo, rslUpdate); err != nil {
+			decisions := make([]*RefUpdateDecision, 0, len(updates))
+			for _, update := range updates {
+				reason := err
+				if update != rslUpdate {
+					reason = ErrRSLUpdateNotAllowed
+				}
+				decisions = append(decisions, &RefUpdateDecision{Name: update.Name, Allowed: false, Reason: reason})
+			}
+			return decisions, nil
+		}
+	}
+
+	decisions := make([]*RefUpdateDecision, 0, len(updates))
+	for _, update := range updates {
+		if update == rslUpdate {
+			decisions = append(decisions, &RefUpdateDecision{Name: update.Name, Allowed: true})
+			continue
+		}
+
+		slog.Debug(fmt.Sprintf("Verifying update to '%s'...", update.Name))
+		if err := r.verifyReceivedRefUpdate(ctx, proposedRepo, update); err != nil {
+			decisions = append(decisions, &RefUpdateDecision{Name: update.Name, Allowed: false, Reason: err})
+			continue
+		}
+		decisions = append(decisions, &RefUpdateDecision{Name: update.Name, Allowed: true})
+	}
+
+	return decisions, nil
+}
+
+// VerifyReferenceTransaction decides if the ref updates in a reference
+// transaction must be allowed. It is meant to be invoked by a Git server's
+// reference-transaction hook when the transaction is prepared, before any refs
+// are updated. Unlike a pre-receive hook, this also covers ref updates that
+// aren't made by pushes. Git passes the zero ID as the old ID of forced
+// updates, so the old ID of such updates is read from the repository. Updates
+// are otherwise decided like in VerifyReceivedRefUpdates.
+func (r *Repository) VerifyReferenceTransaction(ctx context.Context, updates []*RefUpdate) ([]*RefUpdateDecision, error) {
+	for _, update := range updates {
+		if !update.OldID.IsZero() {
+			continue
+		}
+
+		ref, err := r.r.Reference(plumbing.ReferenceName(update.Name), true)
+		if err != nil {
+			if errors.Is(err, plumbing.ErrReferenceNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		update.OldID = ref.Hash()
+	}
+
+	return r.VerifyReceivedRefUpdates(ctx, updates)
+}
+
+// verifyRSLUpdate checks that the received RSL update only appends entries to
+// the current RSL.
+func verifyRSLUpdate(proposedRepo *git.Repository, update *RefUpdate) error {
+	if update.NewID.IsZero() {
+		return ErrRSLNotFastForward
+	}
+
+	if update.OldID.IsZero() {
+		return nil
+	}
+
+	oldTip, err := gitinterface.GetCommit(proposedRepo, update.OldID)
+	if err != nil {
+		return err
+	}
+
+	knows, err := gitinterface.KnowsCommit(proposedRepo, update.NewID, oldTip)
+	if err != nil {
+		return err
+	}
+	if !knows {
+		return ErrRSLNotFastForward
+	}
+
+	return nil
+}
+
+// verifyReceivedRefUpdate checks that the update is recorded in the RSL and
+// verifies the new RSL entries for the ref against policy. Only entries
+// recorded after the ref's latest entry in the current RSL are verified.
+func (r *Repository) verifyReceivedRefUpdate(ctx context.Context, proposedRepo *git.Repository, update *RefUpdate) error {
+	latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(proposedRepo, update.Name)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) || errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return ErrRefUpdateNotInRSL
+		}
+		return err
+	}
+	if latestEntry.TargetID != update.NewID {
+		return fmt.Errorf("%w: latest entry '%s' records '%s'", ErrRefUpdateNotInRSL, latestEntry.ID.String(), latestEntry.TargetID.String())
+	}
+
+	switch update.Name {
+	case policy.PolicyRef:
+		// Loading the policy verifies the root of trust for every policy
+		// state
+		_, err := policy.LoadCurrentState(ctx, proposedRepo, policy.PolicyRef)
+		return err
+	case policy.PolicyStagingRef, attestations.Ref, identities.Ref:
+		// These are verified when they are used to verify other refs
+		return nil
+	}
+	if rsl.IsShardRef(update.Name) {
+		// RSL shards are verified with the refs recorded in them
+		return nil
+	}
+
+	previousEntry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, update.Name)
+	if err != nil {
+		if !errors.Is(err, rsl.ErrRSLEntryNotFound) && !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return err
+		}
+
+		slog.Debug(fmt.Sprintf("No prior RSL entry found for '%s', verifying all entries...", update.Name))
+		_, err := policy.VerifyRefFull(ctx, proposedRepo, update.Name)
+		return err
+	}
+
+	_, err = policy.VerifyRefFromEntry(ctx, proposedRepo, update.Name, previousEntry.ID)
+	return err
+}
+
+// withRefUpdates returns a view of the repository with the ref updates
+// applied. The view shares the repository's object store, but changes to refs
+// in the view are not written to the repository.
+func (r *Repository) withRefUpdates(updates []*RefUpdate) (*git.Repository, error) {
+	refs := memory.ReferenceStorage{}
+
+	iter, err := r.r.Storer.IterReferences()
+	if err != nil {
+		return nil, err
+	}
+	if err := iter.ForEach(refs.SetReference); err != nil {
+		return nil, err
+	}
+
+	for _, update := range updates {
+		refName := plumbing.ReferenceName(update.Name)
+		if update.NewID.IsZero() {
+			if err := refs.RemoveReference(refName); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err := refs.SetReference(plumbing.NewHashReference(refName, update.NewID)); err != nil {
+			return nil, err
+		}
+	}
+
+	return git.Open(&refOverlayStorer{Storer: r.r.Storer, refs: refs}, nil)
+}
+
+// refOverlayStorer is a storer that uses the underlying storer for everything
+// but refs, which are read from and written to memory instead.
+type refOverlayStorer struct {
+	storage.Storer
+	refs memory.ReferenceStorage
+}
+
+func (s *refOverlayStorer) SetReference(ref *plumbing.Reference) error {
+	return s.refs.SetReference(ref)
+}
+
+func (s *refOverlayStorer) CheckAndSetReference(ref, old *plumbing.Reference) error {
+	return s.refs.CheckAndSetReference(ref, old)
+}
+
+func (s *refOverlayStorer) Reference(name plumbing.ReferenceName) (*plumbing.Reference, error) {
+	return s.refs.Reference(name)
+}
+
+func (s *refOverlayStorer) IterReferences() (storer.ReferenceIter, error) {
+	return s.refs.IterReferences()
+}
+
+func (s *refOverlayStorer) RemoveReference(name plumbing.ReferenceName) error {
+	return s.refs.RemoveReference(name)
+}
+
+func (s *refOverlayStorer) CountLooseRefs() (int, error) {
+	return s.refs.CountLooseRefs()
+}
+
+func (s *refOverlayStorer) PackRefs() error {
+	return s.refs.PackRefs()
+}
+
+// quarantineStorer is a storer that reads objects from a quarantine directory
+// in addition to the underlying storer.
+type quarantineStorer struct {
+	storage.Storer
+	quarantine storer.EncodedObjectStorer
+}
+
+func (s *quarantineStorer) EncodedObject(objectType plumbing.ObjectType, objectID plumbing.Hash) (plumbing.EncodedObject, error) {
+	obj, err := s.Storer.EncodedObject(objectType, objectID)
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		return s.quarantine.EncodedObject(objectType, objectID)
+	}
+	return obj, err
+}
+
+func (s *quarantineStorer) HasEncodedObject(objectID plumbing.Hash) error {
+	err := s.Storer.HasEncodedObject(objectID)
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		return s.quarantine.HasEncodedObject(objectID)
+	}
+	return err
+}
+
+func (s *quarantineStorer) EncodedObjectSize(objectID plumbing.Hash) (int64, error) {
+	size, err := s.Storer.EncodedObjectSize(objectID)
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		return s.quarantine.EncodedObjectSize(objectID)
+	}
+	return size, err
+}
+
+// objectsDirFS exposes an objects directory, such as Git's quarantine
+// directory, as the `objects` directory of a Git directory, so that it can be
+// read using go-git's filesystem storage.
+type objectsDirFS struct {
+	billy.Filesystem
+}
+
+func (fs *objectsDirFS) path(name string) string {
+	name = filepath.Clean(name)
+	if name == "objects" {
+		return "."
+	}
+	return strings.TrimPrefix(name, "objects"+string(filepath.Separator))
+}
+
+func (fs *objectsDirFS) Create(filename string) (billy.File, error) {
+	return fs.Filesystem.Create(fs.path(filename))
+}
+
+func (fs *objectsDirFS) Open(filename string) (billy.File, error) {
+	return fs.Filesystem.Open(fs.path(filename))
+}
+
+func (fs *objectsDirFS) OpenFile(filename string, flag int, perm os.FileMode) (billy.File, error) {
+	return fs.Filesystem.OpenFile(fs.path(filename), flag, perm)
+}
+
+func (fs *objectsDirFS) Stat(filename string) (os.FileInfo, error) {
+	return fs.Filesystem.Stat(fs.path(filename))
+}
+
+func (fs *objectsDirFS) Lstat(filename string) (os.FileInfo, error) {
+	return fs.Filesystem.Lstat(fs.path(filename))
+}
+
+func (fs *objectsDirFS) Rename(oldpath, newpath string) error {
+	return fs.Filesystem.Rename(fs.path(oldpath), fs.path(newpath))
+}
+
+func (fs *objectsDirFS) Remove(filename string) error {
+	return fs.Filesystem.Remove(fs.path(filename))
+}
+
+func (fs *objectsDirFS) TempFile(dir, prefix string) (billy.File, error) {
+	return fs.Filesystem.TempFile(fs.path(dir), prefix)
+}
+
+func (fs *objectsDirFS) ReadDir(path string) ([]os.FileInfo, error) {
+	return fs.Filesystem.ReadDir(fs.path(path))
+}
+
+func (fs *objectsDirFS) MkdirAll(filename string, perm os.FileMode) error {
+	return fs.Filesystem.MkdirAll(fs.path(filename), perm)
+}
@@ -0,0 +1,251 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReceivedRefUpdates(t *testing.T) {
+	oldID := plumbing.ZeroHash.String()
+	newID := plumbing.NewHash("1c6b1f8a0c3e2b2cf3c58cf8c1b0f7e3b6c6f2a1").String()
+
+	t.Run("valid updates", func(t *testing.T) {
+		input := strings.NewReader(oldID + " " + newID + " refs/heads/main\n\n" + newID + " " + oldID + " refs/tags/v1\n")
+
+		updates, err := ParseReceivedRefUpdates(input)
+		assert.Nil(t, err)
+		assert.Equal(t, []*RefUpdate{
+			{Name: "refs/heads/main", OldID: plumbing.ZeroHash, NewID: plumbing.NewHash(newID)},
+			{Name: "refs/tags/v1", OldID: plumbing.NewHash(newID), NewID: plumbing.ZeroHash},
+		}, updates)
+	})
+
+	t.Run("no updates", func(t *testing.T) {
+		updates, err := ParseReceivedRefUpdates(strings.NewReader(""))
+		assert.Nil(t, err)
+		assert.Empty(t, updates)
+	})
+
+	t.Run("invalid updates", func(t *testing.T) {
+		tests := map[string]string{
+			"missing ref":  oldID + " " + newID,
+			"invalid hash": "abc " + newID + " refs/heads/main",
+		}
+
+		for name, input := range tests {
+			_, err := ParseReceivedRefUpdates(strings.NewReader(input))
+			assert.ErrorIs(t, err, ErrInvalidRefUpdate, fmt.Sprintf("unexpected error in test '%s'", name))
+		}
+	})
+}
+
+func TestParseReferenceTransactionUpdates(t *testing.T) {
+	oldID := plumbing.ZeroHash.String()
+	newID := plumbing.NewHash("1c6b1f8a0c3e2b2cf3c58cf8c1b0f7e3b6c6f2a1").String()
+
+	t.Run("valid updates", func(t *testing.T) {
+		input := strings.NewReader(oldID + " " + newID + " refs/heads/main\n" + oldID + " ref:refs/heads/main HEAD\n")
+
+		updates, err := ParseReferenceTransactionUpdates(input)
+		assert.Nil(t, err)
+		assert.Equal(t, []*RefUpdate{
+			{Name: "refs/heads/main", OldID: plumbing.ZeroHash, NewID: plumbing.NewHash(newID)},
+		}, updates)
+	})
+
+	t.Run("invalid updates", func(t *testing.T) {
+		_, err := ParseReferenceTransactionUpdates(strings.NewReader("abc " + newID + " refs/heads/main"))
+		assert.ErrorIs(t, err, ErrInvalidRefUpdate)
+	})
+
+	t.Run("symbolic refs are not skipped for pre-receive hooks", func(t *testing.T) {
+		_, err := ParseReceivedRefUpdates(strings.NewReader(oldID + " ref:refs/heads/main HEAD"))
+		assert.ErrorIs(t, err, ErrInvalidRefUpdate)
+	})
+}
+
+func TestVerifyReferenceTransaction(t *testing.T) {
+	refName := "refs/heads/main"
+
+	t.Run("authorized updates", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		updates := createTestPush(t, r, refName, gpgKeyBytes, true)
+		decisions, err := r.VerifyReferenceTransaction(testCtx, updates)
+		assert.Nil(t, err)
+		assertAllowed(t, updates, decisions)
+	})
+
+	t.Run("update not recorded in RSL", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		updates := createTestPush(t, r, refName, gpgKeyBytes, false)
+		decisions, err := r.VerifyReferenceTransaction(testCtx, updates)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(decisions))
+		assert.False(t, decisions[0].Allowed)
+		assert.ErrorIs(t, decisions[0].Reason, ErrRefUpdateNotInRSL)
+	})
+
+	t.Run("forced RSL update is not a fast-forward", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		rslTip, err := r.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rslTipCommit, err := r.r.CommitObject(rslTip.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Git passes the zero ID as the old ID of forced updates
+		updates := []*RefUpdate{{Name: rsl.Ref, OldID: plumbing.ZeroHash, NewID: rslTipCommit.ParentHashes[0]}}
+		decisions, err := r.VerifyReferenceTransaction(testCtx, updates)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(decisions))
+		assert.False(t, decisions[0].Allowed)
+		assert.ErrorIs(t, decisions[0].Reason, ErrRSLNotFastForward)
+	})
+}
+
+func TestVerifyReceivedRefUpdates(t *testing.T) {
+	refName := "refs/heads/main"
+
+	t.Run("authorized updates", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		updates := createTestPush(t, r, refName, gpgKeyBytes, true)
+		decisions, err := r.VerifyReceivedRefUpdates(testCtx, updates)
+		assert.Nil(t, err)
+		assertAllowed(t, updates, decisions)
+
+		// A subsequent push is verified from the ref's latest entry
+		applyTestPush(t, r, updates)
+		updates = createTestPush(t, r, refName, gpgKeyBytes, true)
+		decisions, err = r.VerifyReceivedRefUpdates(testCtx, updates)
+		assert.Nil(t, err)
+		assertAllowed(t, updates, decisions)
+	})
+
+	t.Run("unauthorized RSL entry", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		updates := createTestPush(t, r, refName, gpgUnauthorizedKeyBytes, true)
+		decisions, err := r.VerifyReceivedRefUpdates(testCtx, updates)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(decisions))
+		assert.Equal(t, refName, decisions[0].Name)
+		assert.False(t, decisions[0].Allowed)
+		assert.NotNil(t, decisions[0].Reason)
+		assert.Equal(t, rsl.Ref, decisions[1].Name)
+		assert.True(t, decisions[1].Allowed)
+	})
+
+	t.Run("update not recorded in RSL", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		updates := createTestPush(t, r, refName, gpgKeyBytes, false)
+		decisions, err := r.VerifyReceivedRefUpdates(testCtx, updates)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(decisions))
+		assert.False(t, decisions[0].Allowed)
+		assert.ErrorIs(t, decisions[0].Reason, ErrRefUpdateNotInRSL)
+	})
+
+	t.Run("RSL update is not a fast-forward", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		updates := createTestPush(t, r, refName, gpgKeyBytes, true)
+
+		// Rewind the RSL instead
+		rslTip, err := r.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rslTipCommit, err := r.r.CommitObject(rslTip.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		updates[1].NewID = rslTipCommit.ParentHashes[0]
+
+		decisions, err := r.VerifyReceivedRefUpdates(testCtx, updates)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(decisions))
+		assert.False(t, decisions[0].Allowed)
+		assert.ErrorIs(t, decisions[0].Reason, ErrRSLUpdateNotAllowed)
+		assert.False(t, decisions[1].Allowed)
+		assert.ErrorIs(t, decisions[1].Reason, ErrRSLNotFastForward)
+	})
+}
+
+// createTestPush creates the objects for a push of new commits to the ref,
+// optionally recorded in the RSL using the specified key. The repository's refs
+// are left unchanged, and the ref updates of the push are returned.
+func createTestPush(t *testing.T, r *Repository, refName string, signingKeyBytes []byte, recordInRSL bool) []*RefUpdate {
+	t.Helper()
+
+	oldRefID := getTestRefID(t, r, refName)
+	oldRSLID := getTestRefID(t, r, rsl.Ref)
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r.r, refName, 2, gpgKeyBytes)
+	updates := []*RefUpdate{{Name: refName, OldID: oldRefID, NewID: commitIDs[len(commitIDs)-1]}}
+
+	if recordInRSL {
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, r.r, rsl.NewReferenceEntry(refName, commitIDs[len(commitIDs)-1]), signingKeyBytes)
+		updates = append(updates, &RefUpdate{Name: rsl.Ref, OldID: oldRSLID, NewID: entryID})
+	}
+
+	// Reset refs, the objects remain in the object store
+	for _, update := range updates {
+		if err := r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(update.Name), update.OldID)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return updates
+}
+
+func applyTestPush(t *testing.T, r *Repository, updates []*RefUpdate) {
+	t.Helper()
+
+	for _, update := range updates {
+		if err := r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(update.Name), update.NewID)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func getTestRefID(t *testing.T, r *Repository, refName string) plumbing.Hash {
+	t.Helper()
+
+	ref, err := r.r.Reference(plumbing.ReferenceName(refName), true)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return plumbing.ZeroHash
+		}
+		t.Fatal(err)
+	}
+
+	return ref.Hash()
+}
+
+func assertAllowed(t *testing.T, updates []*RefUpdate, decisions []*RefUpdateDecision) {
+	t.Helper()
+
+	assert.Equal(t, len(updates), len(decisions))
+	for i, decision := range decisions {
+		assert.Equal(t, updates[i].Name, decision.Name)
+		assert.True(t, decision.Allowed)
+		assert.Nil(t, decision.Reason)
+	}
+}
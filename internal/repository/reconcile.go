@@ -0,0 +1,323 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	// RSLReconcileStrategyLocalRebase re-records the entries only in the
+	// local RSL atop the remote RSL.
+	RSLReconcileStrategyLocalRebase = "local-rebase"
+
+	// RSLReconcileStrategyAbort leaves diverged RSLs unchanged.
+	RSLReconcileStrategyAbort = "abort"
+)
+
+var (
+	ErrRSLDiverged                 = errors.New("local and remote RSLs have diverged")
+	ErrInvalidRSLReconcileStrategy = errors.New("invalid RSL reconcile strategy (not one of local-rebase, abort)")
+	ErrRSLMergeBaseNotFound        = errors.New("local and remote RSLs have no common entry")
+)
+
+// ReconciledRSLEntry records an entry only in the local RSL that was recorded
+// again atop the remote RSL.
+type ReconciledRSLEntry struct {
+	// OriginalID is the ID of the entry in the local RSL before it was
+	// reconciled.
+	OriginalID plumbing.Hash
+
+	// ID is the ID of the entry recorded atop the remote RSL.
+	ID plumbing.Hash
+}
+
+// RSLReconcileResult records how the local RSL was reconciled with the remote
+// RSL.
+type RSLReconcileResult struct {
+	// Diverged indicates the local and remote RSLs had diverged.
+	Diverged bool
+
+	// Updated indicates the local RSL was updated.
+	Updated bool
+
+	// Reconciled are the entries only in the local RSL that were recorded
+	// again atop the remote RSL, in the order they were recorded.
+	Reconciled []*ReconciledRSLEntry
+}
+
+// ReconcileRSL fetches the remote's RSL and updates the local RSL to include
+// it. If the local RSL is behind, it's fast-forwarded to the remote RSL. If the
+// RSLs have diverged, the strategy determines how they're reconciled: with
+// RSLReconcileStrategyLocalRebase, the local RSL is reset to the remote RSL and
+// the entries only in the local RSL are recorded again atop it in order, with
+// annotations updated to refer to the new entries. With
+// RSLReconcileStrategyAbort, ErrRSLDiverged is returned. The local RSL is
+// restored if reconciliation fails. The reconciled RSL must be pushed to the
+// remote separately.
+func (r *Repository) ReconcileRSL(ctx context.Context, remoteName, strategy string, signCommit bool) (*RSLReconcileResult, error) {
+	if strategy != RSLReconcileStrategyLocalRebase && strategy != RSLReconcileStrategyAbort {
+		return nil, ErrInvalidRSLReconcileStrategy
+	}
+
+	hasUpdates, hasDiverged, err := r.CheckRemoteRSLForUpdates(ctx, remoteName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RSLReconcileResult{Diverged: hasDiverged, Reconciled: []*ReconciledRSLEntry{}}
+	if !hasUpdates {
+		slog.Debug("Local RSL is up to date with remote RSL")
+		return result, nil
+	}
+
+	remoteRef, err := r.r.Reference(plumbing.ReferenceName(rsl.RemoteTrackerRef(remoteName)), true)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasDiverged {
+		slog.Debug("Fast-forwarding local RSL to remote RSL...")
+		if err := r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(rsl.Ref), remoteRef.Hash())); err != nil {
+			return nil, err
+		}
+		if err := r.updateRSLShardsFromRemote(remoteName); err != nil {
+			return nil, err
+		}
+		result.Updated = true
+		return result, nil
+	}
+
+	if strategy == RSLReconcileStrategyAbort {
+		return nil, ErrRSLDiverged
+	}
+
+	localRef, err := r.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Identifying entries only in local RSL...")
+	localEntries, err := r.localOnlyRSLEntries(localRef.Hash(), remoteRef.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	// The shard entries recorded by the local index entries are identified
+	// before the local RSL is reset
+	localShardEntries := map[plumbing.Hash][]*rsl.ReferenceEntry{}
+	for _, entry := range localEntries {
+		if indexEntry, isReferenceEntry := entry.(*rsl.ReferenceEntry); isReferenceEntry && rsl.IsShardRef(indexEntry.RefName) {
+			localShardEntries[indexEntry.ID], err = rsl.GetShardEntries(r.r, indexEntry)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	localShardRefs, err := r.refsWithPrefix(rsl.ShardRefPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Resetting local RSL to remote RSL...")
+	if err := r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(rsl.Ref), remoteRef.Hash())); err != nil {
+		return nil, err
+	}
+
+	err = r.updateRSLShardsFromRemote(remoteName)
+	if err == nil {
+		result.Reconciled, err = r.rerecordRSLEntries(localEntries, localShardEntries, signCommit)
+	}
+	if err != nil {
+		slog.Debug("Restoring local RSL...")
+		restoreErrs := []error{err, r.r.Storer.SetReference(localRef)}
+		for refName, tip := range localShardRefs {
+			restoreErrs = append(restoreErrs, r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), tip)))
+		}
+		return nil, errors.Join(restoreErrs...)
+	}
+	result.Updated = true
+
+	return result, nil
+}
+
+// localOnlyRSLEntries returns the entries in the local RSL that are not in the
+// remote RSL, starting with the earliest.
+func (r *Repository) localOnlyRSLEntries(localID, remoteID plumbing.Hash) ([]rsl.Entry, error) {
+	localCommit, err := gitinterface.GetCommit(r.r, localID)
+	if err != nil {
+		return nil, err
+	}
+	remoteCommit, err := gitinterface.GetCommit(r.r, remoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	mergeBases, err := localCommit.MergeBase(remoteCommit)
+	if err != nil {
+		return nil, err
+	}
+	if len(mergeBases) == 0 {
+		return nil, ErrRSLMergeBaseNotFound
+	}
+	mergeBaseID := mergeBases[0].Hash
+
+	entries := []rsl.Entry{}
+	entry, err := rsl.GetEntry(r.r, localID)
+	if err != nil {
+		return nil, err
+	}
+	for entry.GetID() != mergeBaseID {
+		entries = append([]rsl.Entry{entry}, entries...)
+
+		entry, err = rsl.GetParentForEntry(r.r, entry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
+// updateRSLShardsFromRemote sets the local RSL shards to the remote's RSL
+// shards, which were fetched when checking the remote for updates.
+func (r *Repository) updateRSLShardsFromRemote(remoteName string) error {
+	trackerPrefix := rsl.RemoteShardTrackerRefPrefix(remoteName)
+	trackerRefs, err := r.refsWithPrefix(trackerPrefix)
+	if err != nil {
+		return err
+	}
+
+	for trackerRef, tip := range trackerRefs {
+		shardRef := rsl.ShardRef(strings.TrimPrefix(trackerRef, trackerPrefix))
+		if err := r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(shardRef), tip)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rerecordRSLEntries records the entries again atop the current RSL, in order.
+// The shard entries recorded by index entries, keyed by the index entry's ID,
+// are recorded again in their shards. Annotations that refer to the entries
+// are updated to refer to their new IDs.
+func (r *Repository) rerecordRSLEntries(entries []rsl.Entry, shardEntries map[plumbing.Hash][]*rsl.ReferenceEntry, signCommit bool) ([]*ReconciledRSLEntry, error) {
+	reconciled := []*ReconciledRSLEntry{}
+	newIDs := map[plumbing.Hash]plumbing.Hash{}
+	mapID := func(id plumbing.Hash) plumbing.Hash {
+		if newID, has := newIDs[id]; has {
+			return newID
+		}
+		return id
+	}
+
+	for _, entry := range entries {
+		var newEntry rsl.Entry
+		switch entry := entry.(type) {
+		case *rsl.ReferenceEntry:
+			if rsl.IsShardRef(entry.RefName) {
+				// The shard entries recorded by the index entry are recorded
+				// again atop the remote's shard, each with a new index entry
+				for _, shardEntry := range shardEntries[entry.ID] {
+					newShardEntry, err := r.copyReferenceEntry(shardEntry)
+					if err != nil {
+						return nil, err
+					}
+
+					slog.Debug(fmt.Sprintf("Recording entry '%s' atop remote RSL shard '%s'...", shardEntry.ID.String(), shardEntry.Shard))
+					if err := newShardEntry.CommitToShard(r.r, shardEntry.Shard, signCommit); err != nil {
+						return nil, err
+					}
+
+					ref, err := r.r.Reference(plumbing.ReferenceName(rsl.ShardRef(shardEntry.Shard)), true)
+					if err != nil {
+						return nil, err
+					}
+
+					newIDs[shardEntry.ID] = ref.Hash()
+					reconciled = append(reconciled, &ReconciledRSLEntry{OriginalID: shardEntry.ID, ID: ref.Hash()})
+				}
+				continue
+			}
+
+			newReferenceEntry, err := r.copyReferenceEntry(entry)
+			if err != nil {
+				return nil, err
+			}
+
+			newEntry = newReferenceEntry
+
+		case *rsl.AnnotationEntry:
+			var newAnnotation *rsl.AnnotationEntry
+			if entry.IsRange() {
+				newAnnotation = rsl.NewAnnotationEntryForRange(entry.RangeRefName, mapID(entry.RangeStartID), mapID(entry.RangeEndID), entry.Skip, entry.Message)
+			} else {
+				entryIDs := make([]plumbing.Hash, 0, len(entry.RSLEntryIDs))
+				for _, id := range entry.RSLEntryIDs {
+					entryIDs = append(entryIDs, mapID(id))
+				}
+				newAnnotation = rsl.NewAnnotationEntry(entryIDs, entry.Skip, entry.Message)
+			}
+			newAnnotation.Tickets = entry.Tickets
+
+			newEntry = newAnnotation
+
+		case *rsl.PropagationEntry:
+			newEntry = rsl.NewPropagationEntry(entry.UpstreamRepository, entry.UpstreamEntryID)
+
+		default:
+			return nil, rsl.ErrInvalidRSLEntry
+		}
+
+		slog.Debug(fmt.Sprintf("Recording entry '%s' atop remote RSL...", entry.GetID().String()))
+		if err := newEntry.Commit(r.r, signCommit); err != nil {
+			return nil, err
+		}
+
+		ref, err := r.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		if err != nil {
+			return nil, err
+		}
+
+		newIDs[entry.GetID()] = ref.Hash()
+		reconciled = append(reconciled, &ReconciledRSLEntry{OriginalID: entry.GetID(), ID: ref.Hash()})
+	}
+
+	return reconciled, nil
+}
+
+// copyReferenceEntry returns a new reference entry recording the same
+// information as the entry. Changed paths are recomputed as the previous entry
+// for the ref may now be from the remote RSL.
+func (r *Repository) copyReferenceEntry(entry *rsl.ReferenceEntry) (*rsl.ReferenceEntry, error) {
+	newEntry := rsl.NewReferenceEntry(entry.RefName, entry.TargetID)
+	if entry.ChangedPaths != nil {
+		priorEntry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, entry.RefName)
+		if err != nil {
+			if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				return nil, err
+			}
+			priorEntry = nil
+		}
+
+		changedPaths, err := rsl.GetChangedPaths(r.r, priorEntry, entry.TargetID)
+		if err != nil {
+			return nil, err
+		}
+		newEntry = rsl.NewReferenceEntryWithChangedPaths(entry.RefName, entry.TargetID, changedPaths)
+	}
+	newEntry.Tickets = entry.Tickets
+	newEntry.Submodules = entry.Submodules
+	newEntry.Message = entry.Message
+
+	return newEntry, nil
+}
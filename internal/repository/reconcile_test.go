@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcileRSL(t *testing.T) {
+	remoteName := "origin"
+	refName := "refs/heads/main"
+	anotherRefName := "refs/heads/feature"
+
+	// createRepos returns a remote repository with an RSL entry for refName,
+	// and a local clone of it
+	createRepos := func(t *testing.T) (*Repository, *Repository) {
+		t.Helper()
+
+		remoteR, err := gitinterface.PlainInit(t.TempDir(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		remoteRepo := &Repository{r: remoteR}
+		if err := rsl.InitializeNamespace(remoteRepo.r); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := gitinterface.Commit(remoteRepo.r, gitinterface.EmptyTree(), refName, "Test commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := remoteRepo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		worktree, err := remoteR.Worktree()
+		if err != nil {
+			t.Fatal(err)
+		}
+		localR, err := gitinterface.CloneAndFetch(context.Background(), worktree.Filesystem.Root(), t.TempDir(), refName, []string{rsl.Ref})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return remoteRepo, &Repository{r: localR}
+	}
+
+	t.Run("invalid strategy", func(t *testing.T) {
+		_, localRepo := createRepos(t)
+
+		_, err := localRepo.ReconcileRSL(testCtx, remoteName, "merge", false)
+		assert.ErrorIs(t, err, ErrInvalidRSLReconcileStrategy)
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		_, localRepo := createRepos(t)
+
+		result, err := localRepo.ReconcileRSL(testCtx, remoteName, RSLReconcileStrategyAbort, false)
+		assert.Nil(t, err)
+		assert.False(t, result.Diverged)
+		assert.False(t, result.Updated)
+	})
+
+	t.Run("fast-forward", func(t *testing.T) {
+		remoteRepo, localRepo := createRepos(t)
+
+		if _, err := gitinterface.Commit(remoteRepo.r, gitinterface.EmptyTree(), refName, "Test commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := remoteRepo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := localRepo.ReconcileRSL(testCtx, remoteName, RSLReconcileStrategyAbort, false)
+		assert.Nil(t, err)
+		assert.False(t, result.Diverged)
+		assert.True(t, result.Updated)
+
+		remoteRSLRef, err := remoteRepo.r.Reference(rsl.Ref, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		localRSLRef, err := localRepo.r.Reference(rsl.Ref, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, remoteRSLRef.Hash(), localRSLRef.Hash())
+	})
+
+	t.Run("diverged", func(t *testing.T) {
+		remoteRepo, localRepo := createRepos(t)
+
+		if _, err := gitinterface.Commit(remoteRepo.r, gitinterface.EmptyTree(), refName, "Test commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := remoteRepo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
+			t.Fatal(err)
+		}
+		remoteRSLRef, err := remoteRepo.r.Reference(rsl.Ref, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := gitinterface.Commit(localRepo.r, gitinterface.EmptyTree(), anotherRefName, "Test commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := localRepo.RecordRSLEntryForReferenceWithOptions(testCtx, anotherRefName, &RecordRSLEntryOptions{Message: "local change"}, false); err != nil {
+			t.Fatal(err)
+		}
+		localEntry, err := rsl.GetLatestEntry(localRepo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rsl.NewAnnotationEntry([]plumbing.Hash{localEntry.GetID()}, true, "skip local change").Commit(localRepo.r, false); err != nil {
+			t.Fatal(err)
+		}
+		localAnnotation, err := rsl.GetLatestEntry(localRepo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		localRSLRef, err := localRepo.r.Reference(rsl.Ref, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = localRepo.ReconcileRSL(testCtx, remoteName, RSLReconcileStrategyAbort, false)
+		assert.ErrorIs(t, err, ErrRSLDiverged)
+
+		// The local RSL is unchanged
+		currentRSLRef, err := localRepo.r.Reference(rsl.Ref, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, localRSLRef.Hash(), currentRSLRef.Hash())
+
+		result, err := localRepo.ReconcileRSL(testCtx, remoteName, RSLReconcileStrategyLocalRebase, false)
+		assert.Nil(t, err)
+		assert.True(t, result.Diverged)
+		assert.True(t, result.Updated)
+		assert.Equal(t, 2, len(result.Reconciled))
+		assert.Equal(t, localEntry.GetID(), result.Reconciled[0].OriginalID)
+		assert.Equal(t, localAnnotation.GetID(), result.Reconciled[1].OriginalID)
+
+		// The local entries are recorded atop the remote RSL
+		newAnnotation, err := rsl.GetLatestEntry(localRepo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, result.Reconciled[1].ID, newAnnotation.GetID())
+		assert.Equal(t, []plumbing.Hash{result.Reconciled[0].ID}, newAnnotation.(*rsl.AnnotationEntry).RSLEntryIDs)
+
+		newEntry, err := rsl.GetParentForEntry(localRepo.r, newAnnotation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, result.Reconciled[0].ID, newEntry.GetID())
+		assert.Equal(t, anotherRefName, newEntry.(*rsl.ReferenceEntry).RefName)
+		assert.Equal(t, "local change", newEntry.(*rsl.ReferenceEntry).Message)
+
+		parentEntry, err := rsl.GetParentForEntry(localRepo.r, newEntry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, remoteRSLRef.Hash(), parentEntry.GetID())
+
+		// The local RSL can now be pushed
+		hasUpdates, hasDiverged, err := localRepo.CheckRemoteRSLForUpdates(testCtx, remoteName)
+		assert.Nil(t, err)
+		assert.False(t, hasUpdates)
+		assert.False(t, hasDiverged)
+	})
+}
@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/google/go-github/v61/github"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var ErrGitHubReleaseAssetsMismatch = errors.New("published GitHub release assets do not match attested assets")
+
+// AddGitHubReleaseAttestation creates an attestation that binds the assets
+// published with the GitHub release for the specified tag to the tag's RSL
+// entry. The tag must pass verification against the gittuf policy before the
+// attestation is created. Currently, the authentication token for the GitHub
+// API is read from the GITHUB_TOKEN environment variable.
+func (r *Repository) AddGitHubReleaseAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, owner, repository, tag string, signCommit bool) error {
+	if !dev.InDevMode() {
+		return dev.ErrNotInDevMode
+	}
+
+	tagRef, err := gitinterface.AbsoluteReference(r.r, tag)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Verifying '%s'...", tagRef))
+	entry, _, err := policy.VerifyTagRef(ctx, r.r, tagRef)
+	if err != nil {
+		return err
+	}
+
+	client, err := getGitHubClient()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Inspecting GitHub release for '%s'...", tag))
+	release, response, err := client.Repositories.GetReleaseByTag(ctx, owner, repository, tag)
+	if err != nil {
+		return checkGitHubResponse(fmt.Sprintf("get release for tag '%s'", tag), gitHubReleasePermissions, response, err)
+	}
+
+	assets, err := getGitHubReleaseAssets(ctx, client, owner, repository, release)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Creating GitHub release attestation...")
+	statement, err := attestations.NewGitHubReleaseAttestation(owner, repository, release.GetID(), tagRef, entry.ID.String(), entry.TargetID.String(), assets)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		return err
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing GitHub release attestation using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return err
+	}
+
+	if err := allAttestations.SetGitHubReleaseAttestation(r.r, env, tagRef, entry.ID.String()); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add GitHub release attestation for '%s' at '%s'\n\nSource: %s\n", tagRef, entry.ID.String(), release.GetHTMLURL())
+
+	slog.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// VerifyGitHubRelease verifies that the assets currently published with the
+// GitHub release for the specified tag match the assets recorded in the
+// release's attestation. The tag must pass verification against the gittuf
+// policy, and the attestation must be signed by keys trusted for the tag.
+func (r *Repository) VerifyGitHubRelease(ctx context.Context, owner, repository, tag string) error {
+	tagRef, err := gitinterface.AbsoluteReference(r.r, tag)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Verifying '%s'...", tagRef))
+	entry, _, err := policy.VerifyTagRef(ctx, r.r, tagRef)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	currentPolicy, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading GitHub release attestation...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return err
+	}
+
+	env, err := allAttestations.GetGitHubReleaseAttestationFor(r.r, tagRef, entry.ID.String())
+	if err != nil {
+		return err
+	}
+
+	// The attestation is created after the tag is recorded in the RSL, so we
+	// use the latest policy to verify it
+	slog.Debug("Verifying signatures on GitHub release attestation...")
+	if err := currentPolicy.VerifyTagAttestation(ctx, tagRef, env); err != nil {
+		return err
+	}
+
+	attestedAssets, err := attestations.GetGitHubReleaseAssets(env)
+	if err != nil {
+		return err
+	}
+
+	client, err := getGitHubClient()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Inspecting GitHub release for '%s'...", tag))
+	release, response, err := client.Repositories.GetReleaseByTag(ctx, owner, repository, tag)
+	if err != nil {
+		return checkGitHubResponse(fmt.Sprintf("get release for tag '%s'", tag), gitHubReleasePermissions, response, err)
+	}
+
+	publishedAssets, err := getGitHubReleaseAssets(ctx, client, owner, repository, release)
+	if err != nil {
+		return err
+	}
+
+	if len(attestedAssets) != len(publishedAssets) {
+		return ErrGitHubReleaseAssetsMismatch
+	}
+
+	attestedDigests := make(map[string]string, len(attestedAssets))
+	for _, asset := range attestedAssets {
+		attestedDigests[asset.Name] = asset.SHA256
+	}
+
+	for _, asset := range publishedAssets {
+		digest, has := attestedDigests[asset.Name]
+		if !has {
+			return fmt.Errorf("%w: unexpected asset '%s'", ErrGitHubReleaseAssetsMismatch, asset.Name)
+		}
+
+		if digest != asset.SHA256 {
+			return fmt.Errorf("%w: digest mismatch for asset '%s'", ErrGitHubReleaseAssetsMismatch, asset.Name)
+		}
+	}
+
+	return nil
+}
+
+// getGitHubReleaseAssets downloads each asset published with the release and
+// computes its SHA-256 digest. The assets are returned sorted by name.
+func getGitHubReleaseAssets(ctx context.Context, client *github.Client, owner, repository string, release *github.RepositoryRelease) ([]*attestations.GitHubReleaseAsset, error) {
+	assets := make([]*attestations.GitHubReleaseAsset, 0, len(release.Assets))
+
+	for _, asset := range release.Assets {
+		slog.Debug(fmt.Sprintf("Downloading GitHub release asset '%s'...", asset.GetName()))
+		contents, _, err := client.Repositories.DownloadReleaseAsset(ctx, owner, repository, asset.GetID(), http.DefaultClient)
+		if err != nil {
+			return nil, err
+		}
+
+		hash := sha256.New()
+		_, err = io.Copy(hash, contents)
+		contents.Close() //nolint:errcheck
+		if err != nil {
+			return nil, err
+		}
+
+		assets = append(assets, &attestations.GitHubReleaseAsset{
+			Name:   asset.GetName(),
+			SHA256: hex.EncodeToString(hash.Sum(nil)),
+		})
+	}
+
+	sort.Slice(assets, func(i, j int) bool {
+		return assets[i].Name < assets[j].Name
+	})
+
+	return assets, nil
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/google/go-github/v61/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHubReleaseAttestation(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	assetContents := map[int64]string{
+		1: "gittuf linux binary",
+		2: "gittuf darwin binary",
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/gittuf/gittuf/releases/tags/v1", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id": 1, "tag_name": "v1", "html_url": "https://github.com/gittuf/gittuf/releases/tag/v1", "assets": [{"id": 1, "name": "gittuf_linux_amd64"}, {"id": 2, "name": "gittuf_darwin_arm64"}]}`)
+	})
+	mux.HandleFunc("/repos/gittuf/gittuf/releases/assets/1", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, assetContents[1])
+	})
+	mux.HandleFunc("/repos/gittuf/gittuf/releases/assets/2", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, assetContents[2])
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	baseURL, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := github.NewClient(nil)
+	client.BaseURL = baseURL
+
+	currentClient := githubClient
+	githubClient = client
+	defer func() {
+		githubClient = currentClient
+	}()
+
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, "refs/heads/main", 1, gpgKeyBytes)
+	tagID := common.CreateTestSignedTag(t, repo.r, "v1", commitIDs[0], gpgKeyBytes)
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tag hasn't been recorded in the RSL yet
+	err = repo.AddGitHubReleaseAttestation(testCtx, signer, "gittuf", "gittuf", "v1", false)
+	assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
+
+	tagRef := string(plumbing.NewTagReferenceName("v1"))
+	entry := rsl.NewReferenceEntry(tagRef, tagID)
+	entryID := common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+
+	// Attestation doesn't exist yet
+	err = repo.VerifyGitHubRelease(testCtx, "gittuf", "gittuf", "v1")
+	assert.ErrorIs(t, err, attestations.ErrGitHubReleaseAttestationNotFound)
+
+	err = repo.AddGitHubReleaseAttestation(testCtx, signer, "gittuf", "gittuf", "v1", false)
+	assert.Nil(t, err)
+
+	allAttestations, err := attestations.LoadCurrentAttestations(repo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := allAttestations.GetGitHubReleaseAttestationFor(repo.r, tagRef, entryID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assets, err := attestations.GetGitHubReleaseAssets(env)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(assets))
+	assert.Equal(t, "gittuf_darwin_arm64", assets[0].Name)
+	assert.Equal(t, "gittuf_linux_amd64", assets[1].Name)
+
+	err = repo.VerifyGitHubRelease(testCtx, "gittuf", "gittuf", "v1")
+	assert.Nil(t, err)
+
+	// Replace a published asset
+	assetContents[1] = "malicious gittuf linux binary"
+
+	err = repo.VerifyGitHubRelease(testCtx, "gittuf", "gittuf", "v1")
+	assert.ErrorIs(t, err, ErrGitHubReleaseAssetsMismatch)
+}
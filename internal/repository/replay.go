@@ -0,0 +1,463 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+const (
+	ReplayInitializeRoot   = "init-root"
+	ReplayAddPolicyKey     = "add-policy-key"
+	ReplayInitializePolicy = "init-policy"
+	ReplayAddRule          = "add-rule"
+	ReplayApplyPolicy      = "apply-policy"
+	ReplayCommit           = "commit"
+	ReplayRecord           = "record"
+	ReplayAnnotate         = "annotate"
+	ReplayPush             = "push"
+	ReplayVerifyRef        = "verify-ref"
+
+	replayRemoteName = "origin"
+)
+
+var (
+	ErrReplayDirectoryNotEmpty    = errors.New("replay directory must be empty")
+	ErrUnknownReplayOperation     = errors.New("unknown operation in replay trace")
+	ErrInvalidReplayOperation     = errors.New("replay operation is missing required parameters")
+	ErrReplayEntryNotRecorded     = errors.New("replay operation refers to an operation that did not record an RSL entry")
+	ErrReplayVerificationMismatch = errors.New("verification outcome differs from the outcome recorded in the trace")
+)
+
+// ReplayTrace is a recorded sequence of operations that can be applied to a
+// fresh repository to reproduce verification behavior observed elsewhere.
+type ReplayTrace struct {
+	Operations []*ReplayOperation `json:"operations"`
+}
+
+// ReplayOperation is a single step in a ReplayTrace. Key paths are resolved
+// relative to the directory containing the trace file. Operations that refer
+// to RSL entries created earlier in the trace do so using the index of the
+// operation that created the entry, as entry IDs are not stable across
+// replays.
+type ReplayOperation struct {
+	Type string `json:"type"`
+
+	SigningKey string   `json:"signingKey,omitempty"`
+	PublicKeys []string `json:"publicKeys,omitempty"`
+
+	PolicyName   string   `json:"policyName,omitempty"`
+	RuleName     string   `json:"ruleName,omitempty"`
+	RulePatterns []string `json:"rulePatterns,omitempty"`
+	Threshold    int      `json:"threshold,omitempty"`
+
+	Ref     string            `json:"ref,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Files   map[string]string `json:"files,omitempty"`
+
+	Entries []int `json:"entries,omitempty"`
+	Skip    bool  `json:"skip,omitempty"`
+
+	LatestOnly    bool `json:"latestOnly,omitempty"`
+	ExpectFailure bool `json:"expectFailure,omitempty"`
+}
+
+// ReplayResult records the outcome of a single replayed operation.
+type ReplayResult struct {
+	Index     int
+	Operation *ReplayOperation
+	Err       error
+	Mismatch  bool
+}
+
+// LoadReplayTrace reads and parses the trace file at the specified path.
+func LoadReplayTrace(path string) (*ReplayTrace, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	trace := &ReplayTrace{}
+	if err := json.Unmarshal(contents, trace); err != nil {
+		return nil, fmt.Errorf("unable to parse replay trace: %w", err)
+	}
+
+	return trace, nil
+}
+
+// Replay applies the operations in the trace to a freshly initialized
+// repository at dir. Key paths in the trace are resolved relative to keysDir.
+// The outcome of every operation is returned. Replay stops at the first
+// operation that fails unexpectedly; verification operations are instead
+// compared against the outcome recorded in the trace. Replay is only available
+// in developer mode.
+func Replay(ctx context.Context, trace *ReplayTrace, dir, keysDir string) (*Repository, []*ReplayResult, error) {
+	if !dev.InDevMode() {
+		return nil, nil, dev.ErrNotInDevMode
+	}
+
+	if err := ensureEmptyDirectory(dir); err != nil {
+		return nil, nil, err
+	}
+
+	slog.Debug(fmt.Sprintf("Initializing fresh repository at '%s'...", dir))
+	repo, err := gitinterface.PlainInit(dir, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	r := &Repository{r: repo}
+
+	replayer := &replayer{
+		repo:    r,
+		keysDir: keysDir,
+		entries: map[int]plumbing.Hash{},
+	}
+
+	results := []*ReplayResult{}
+	for index, operation := range trace.Operations {
+		slog.Debug(fmt.Sprintf("Replaying operation %d (%s)...", index, operation.Type))
+		result := &ReplayResult{Index: index, Operation: operation}
+		results = append(results, result)
+
+		err := replayer.apply(ctx, index, operation)
+		if operation.Type == ReplayVerifyRef {
+			result.Err = err
+			result.Mismatch = (err != nil) != operation.ExpectFailure
+			continue
+		}
+
+		if err != nil {
+			result.Err = err
+			return r, results, fmt.Errorf("unable to replay operation %d (%s): %w", index, operation.Type, err)
+		}
+	}
+
+	for _, result := range results {
+		if result.Mismatch {
+			return r, results, ErrReplayVerificationMismatch
+		}
+	}
+
+	return r, results, nil
+}
+
+type replayer struct {
+	repo      *Repository
+	keysDir   string
+	entries   map[int]plumbing.Hash
+	hasRemote bool
+}
+
+func (p *replayer) apply(ctx context.Context, index int, operation *ReplayOperation) error {
+	switch operation.Type {
+	case ReplayInitializeRoot:
+		signer, err := p.loadSigner(operation.SigningKey)
+		if err != nil {
+			return err
+		}
+
+		if err := p.repo.InitializeRoot(ctx, signer, false); err != nil {
+			return err
+		}
+
+		return p.recordLatestEntry(index)
+	case ReplayAddPolicyKey:
+		signer, err := p.loadSigner(operation.SigningKey)
+		if err != nil {
+			return err
+		}
+
+		keys, err := p.loadPublicKeys(operation.PublicKeys)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			if err := p.repo.AddTopLevelTargetsKey(ctx, signer, key, false); err != nil {
+				return err
+			}
+		}
+
+		return p.recordLatestEntry(index)
+	case ReplayInitializePolicy:
+		signer, err := p.loadSigner(operation.SigningKey)
+		if err != nil {
+			return err
+		}
+
+		if err := p.repo.InitializeTargets(ctx, signer, policyNameOrDefault(operation.PolicyName), false); err != nil {
+			return err
+		}
+
+		return p.recordLatestEntry(index)
+	case ReplayAddRule:
+		if operation.RuleName == "" || len(operation.RulePatterns) == 0 {
+			return ErrInvalidReplayOperation
+		}
+
+		signer, err := p.loadSigner(operation.SigningKey)
+		if err != nil {
+			return err
+		}
+
+		keys, err := p.loadPublicKeys(operation.PublicKeys)
+		if err != nil {
+			return err
+		}
+
+		threshold := operation.Threshold
+		if threshold == 0 {
+			threshold = 1
+		}
+
+		if err := p.repo.AddDelegation(ctx, signer, policyNameOrDefault(operation.PolicyName), operation.RuleName, keys, operation.RulePatterns, threshold, false); err != nil {
+			return err
+		}
+
+		return p.recordLatestEntry(index)
+	case ReplayApplyPolicy:
+		if err := p.repo.ApplyPolicy(ctx, false); err != nil {
+			return err
+		}
+
+		return p.recordLatestEntry(index)
+	case ReplayCommit:
+		if operation.Ref == "" {
+			return ErrInvalidReplayOperation
+		}
+
+		keyBytes, err := p.readKey(operation.SigningKey)
+		if err != nil {
+			return err
+		}
+
+		return p.commit(operation.Ref, operation.Message, operation.Files, keyBytes)
+	case ReplayRecord:
+		if operation.Ref == "" {
+			return ErrInvalidReplayOperation
+		}
+
+		keyBytes, err := p.readKey(operation.SigningKey)
+		if err != nil {
+			return err
+		}
+
+		ref, err := p.repo.r.Reference(plumbing.ReferenceName(operation.Ref), true)
+		if err != nil {
+			return err
+		}
+
+		if err := p.repo.RecordRSLEntryForReferenceAtTarget(operation.Ref, ref.Hash().String(), keyBytes); err != nil {
+			return err
+		}
+
+		return p.recordLatestEntry(index)
+	case ReplayAnnotate:
+		if len(operation.Entries) == 0 {
+			return ErrInvalidReplayOperation
+		}
+
+		entryIDs := make([]string, 0, len(operation.Entries))
+		for _, entryIndex := range operation.Entries {
+			entryID, has := p.entries[entryIndex]
+			if !has {
+				return ErrReplayEntryNotRecorded
+			}
+			entryIDs = append(entryIDs, entryID.String())
+		}
+
+		if err := p.repo.RecordRSLAnnotation(entryIDs, operation.Skip, operation.Message, nil, false); err != nil {
+			return err
+		}
+
+		return p.recordLatestEntry(index)
+	case ReplayPush:
+		if operation.Ref == "" {
+			return ErrInvalidReplayOperation
+		}
+
+		if err := p.ensureRemote(); err != nil {
+			return err
+		}
+
+		refs := []string{operation.Ref, rsl.Ref}
+		for _, gittufRef := range []string{policy.PolicyRef, policy.PolicyStagingRef} {
+			if _, err := p.repo.r.Reference(plumbing.ReferenceName(gittufRef), true); err == nil {
+				refs = append(refs, gittufRef)
+			}
+		}
+
+		return gitinterface.Push(ctx, p.repo.r, replayRemoteName, refs)
+	case ReplayVerifyRef:
+		if operation.Ref == "" {
+			return ErrInvalidReplayOperation
+		}
+
+		return p.repo.VerifyRef(ctx, operation.Ref, operation.LatestOnly)
+	}
+
+	return fmt.Errorf("%w: '%s'", ErrUnknownReplayOperation, operation.Type)
+}
+
+// recordLatestEntry tracks the current tip of the RSL as the entry created by
+// the operation at index so later operations can refer to it.
+func (p *replayer) recordLatestEntry(index int) error {
+	latestEntry, err := rsl.GetLatestEntry(p.repo.r)
+	if err != nil {
+		return err
+	}
+
+	p.entries[index] = latestEntry.GetID()
+	return nil
+}
+
+func (p *replayer) commit(refName, message string, files map[string]string, keyBytes []byte) error {
+	blobIDs := map[string]plumbing.Hash{}
+	for name, contents := range files {
+		blobID, err := gitinterface.WriteBlob(p.repo.r, []byte(contents))
+		if err != nil {
+			return err
+		}
+		blobIDs[name] = blobID
+	}
+
+	var (
+		treeID plumbing.Hash
+		err    error
+	)
+	if len(blobIDs) == 0 {
+		treeID, err = gitinterface.WriteTree(p.repo.r, nil)
+	} else {
+		treeID, err = gitinterface.NewTreeBuilder(p.repo.r).WriteRootTreeFromBlobIDs(blobIDs)
+	}
+	if err != nil {
+		return err
+	}
+
+	if message == "" {
+		message = fmt.Sprintf("Update %s", refName)
+	}
+
+	_, err = gitinterface.CommitUsingSpecificKey(p.repo.r, treeID, refName, message, keyBytes)
+	return err
+}
+
+// ensureRemote creates a bare repository to act as the remote for push
+// operations. It is created lazily, only if the trace includes pushes.
+func (p *replayer) ensureRemote() error {
+	if p.hasRemote {
+		return nil
+	}
+
+	remoteDir, err := os.MkdirTemp("", "gittuf-replay-remote-")
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Initializing replay remote at '%s'...", remoteDir))
+	if _, err := gitinterface.PlainInit(remoteDir, true); err != nil {
+		return err
+	}
+
+	if _, err := p.repo.r.CreateRemote(&config.RemoteConfig{
+		Name: replayRemoteName,
+		URLs: []string{remoteDir},
+	}); err != nil {
+		return err
+	}
+
+	p.hasRemote = true
+	return nil
+}
+
+func (p *replayer) readKey(path string) ([]byte, error) {
+	if path == "" {
+		return nil, ErrInvalidReplayOperation
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(p.keysDir, path)
+	}
+
+	return os.ReadFile(path)
+}
+
+func (p *replayer) loadSigner(path string) (sslibdsse.SignerVerifier, error) {
+	keyBytes, err := p.readKey(path)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := sslibsv.NewSignerVerifierFromPEM(keyBytes)
+	if err == nil {
+		return signer, nil
+	}
+
+	return signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(keyBytes) //nolint:staticcheck
+}
+
+func (p *replayer) loadPublicKeys(paths []string) ([]*tuf.Key, error) {
+	keys := make([]*tuf.Key, 0, len(paths))
+	for _, path := range paths {
+		keyBytes, err := p.readKey(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var key *tuf.Key
+		if strings.Contains(string(keyBytes), "BEGIN PGP PUBLIC KEY BLOCK") {
+			key, err = gpg.LoadGPGKeyFromBytes(keyBytes)
+		} else {
+			key, err = tuf.LoadKeyFromBytes(keyBytes)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func policyNameOrDefault(policyName string) string {
+	if policyName == "" {
+		return policy.TargetsRoleName
+	}
+	return policyName
+}
+
+func ensureEmptyDirectory(dir string) error {
+	contents, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	if len(contents) != 0 {
+		return ErrReplayDirectoryNotEmpty
+	}
+
+	return nil
+}
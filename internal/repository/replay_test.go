@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplay(t *testing.T) {
+	keysDir := t.TempDir()
+	keys := map[string][]byte{
+		"root":        rootKeyBytes,
+		"targets":     targetsKeyBytes,
+		"targets.pub": targetsPubKeyBytes,
+		"gpg":         gpgKeyBytes,
+		"gpg.pub":     gpgPubKeyBytes,
+		"gpg-unauth":  gpgUnauthorizedKeyBytes,
+	}
+	for name, contents := range keys {
+		if err := os.WriteFile(filepath.Join(keysDir, name), contents, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	policyOperations := []*ReplayOperation{
+		{Type: ReplayInitializeRoot, SigningKey: "root"},
+		{Type: ReplayApplyPolicy},
+		{Type: ReplayAddPolicyKey, SigningKey: "root", PublicKeys: []string{"targets.pub"}},
+		{Type: ReplayInitializePolicy, SigningKey: "targets"},
+		{Type: ReplayAddRule, SigningKey: "targets", RuleName: "protect-main", RulePatterns: []string{"git:refs/heads/main"}, PublicKeys: []string{"gpg.pub"}},
+		{Type: ReplayApplyPolicy},
+	}
+
+	t.Run("not in dev mode", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "0")
+
+		_, _, err := Replay(testCtx, &ReplayTrace{}, t.TempDir(), keysDir)
+		assert.ErrorIs(t, err, dev.ErrNotInDevMode)
+	})
+
+	t.Run("matching trace", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		operations := append([]*ReplayOperation{}, policyOperations...)
+		operations = append(operations,
+			&ReplayOperation{Type: ReplayCommit, Ref: "refs/heads/main", SigningKey: "gpg", Files: map[string]string{"README.md": "hello"}},
+			&ReplayOperation{Type: ReplayRecord, Ref: "refs/heads/main", SigningKey: "gpg"},
+			&ReplayOperation{Type: ReplayVerifyRef, Ref: "refs/heads/main"},
+			&ReplayOperation{Type: ReplayCommit, Ref: "refs/heads/main", SigningKey: "gpg-unauth"},
+			&ReplayOperation{Type: ReplayRecord, Ref: "refs/heads/main", SigningKey: "gpg-unauth"},
+			&ReplayOperation{Type: ReplayVerifyRef, Ref: "refs/heads/main", ExpectFailure: true},
+			&ReplayOperation{Type: ReplayAnnotate, Entries: []int{10}, Skip: true, Message: "revoke"},
+			&ReplayOperation{Type: ReplayPush, Ref: "refs/heads/main"},
+		)
+
+		tracePath := filepath.Join(keysDir, "trace.json")
+		traceBytes, err := json.Marshal(&ReplayTrace{Operations: operations})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(tracePath, traceBytes, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		trace, err := LoadReplayTrace(tracePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, results, err := Replay(testCtx, trace, t.TempDir(), keysDir)
+		assert.Nil(t, err)
+		assert.Equal(t, len(operations), len(results))
+		assert.NotNil(t, results[11].Err)
+		assert.False(t, results[11].Mismatch)
+	})
+
+	t.Run("mismatched verification", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		operations := append([]*ReplayOperation{}, policyOperations...)
+		operations = append(operations,
+			&ReplayOperation{Type: ReplayCommit, Ref: "refs/heads/main", SigningKey: "gpg"},
+			&ReplayOperation{Type: ReplayRecord, Ref: "refs/heads/main", SigningKey: "gpg"},
+			&ReplayOperation{Type: ReplayVerifyRef, Ref: "refs/heads/main", ExpectFailure: true},
+		)
+
+		_, results, err := Replay(testCtx, &ReplayTrace{Operations: operations}, t.TempDir(), keysDir)
+		assert.ErrorIs(t, err, ErrReplayVerificationMismatch)
+		assert.True(t, results[len(results)-1].Mismatch)
+	})
+
+	t.Run("annotation for unknown entry", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		operations := append([]*ReplayOperation{}, policyOperations...)
+		operations = append(operations, &ReplayOperation{Type: ReplayAnnotate, Entries: []int{42}, Skip: true})
+
+		_, _, err := Replay(testCtx, &ReplayTrace{Operations: operations}, t.TempDir(), keysDir)
+		assert.ErrorIs(t, err, ErrReplayEntryNotRecorded)
+	})
+
+	t.Run("non-empty directory", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "file"), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		_, _, err := Replay(testCtx, &ReplayTrace{}, dir, keysDir)
+		assert.ErrorIs(t, err, ErrReplayDirectoryNotEmpty)
+	})
+}
@@ -6,16 +6,30 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"path/filepath"
 
 	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/sigcache"
 	"github.com/go-git/go-git/v5"
 )
 
+const (
+	// SignatureCacheConfigKey is the Git config key used to disable the
+	// on-disk cache of successful signature verifications by setting it to
+	// "false". The cache is stored in the gittuf directory within the Git
+	// directory.
+	SignatureCacheConfigKey = "gittuf.signaturecache"
+
+	gittufDirName = "gittuf"
+)
+
 var (
-	ErrUnauthorizedKey    = errors.New("unauthorized key presented when updating gittuf metadata")
-	ErrCannotReinitialize = errors.New("cannot reinitialize metadata, it exists already")
+	ErrUnauthorizedKey         = errors.New("unauthorized key presented when updating gittuf metadata")
+	ErrCannotReinitialize      = errors.New("cannot reinitialize metadata, it exists already")
+	ErrUnsupportedObjectFormat = errors.New("repository's object format is not supported by this build of gittuf")
 )
 
 type Repository struct {
@@ -25,14 +39,64 @@ type Repository struct {
 func LoadRepository() (*Repository, error) {
 	slog.Debug("Loading Git repository...")
 
+	// The RSL, policy, and attestations are read and written using go-git,
+	// whose object IDs are sized when gittuf is built, so the repository must
+	// use the object format gittuf is built for rather than corrupt gittuf
+	// metadata
+	slog.Debug("Checking repository's object format...")
+	gitRepo, err := gitinterface.LoadRepository()
+	if err != nil {
+		return nil, err
+	}
+	objectFormat, err := gitRepo.GetObjectFormat()
+	if err != nil {
+		return nil, err
+	}
+	if buildObjectFormat := gitinterface.BuildObjectFormat(); objectFormat != buildObjectFormat {
+		return nil, fmt.Errorf("%w: repository uses '%s' but gittuf is built for '%s', gittuf must be built using the sha256 build tag for SHA-256 repositories", ErrUnsupportedObjectFormat, objectFormat, buildObjectFormat)
+	}
+
 	repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{DetectDotGit: true})
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		// Bare repositories, such as those hosted on Git servers, have no
+		// .git directory to detect, so they're opened using their Git
+		// directory
+		repo, err = git.PlainOpen(gitRepo.GetGitDir())
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &Repository{
+	enableSignatureCache(gitRepo.GetGitDir())
+
+	r := &Repository{
 		r: repo,
-	}, nil
+	}
+
+	if err := r.fetchMissingGittufObjects(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// enableSignatureCache enables the on-disk cache of successful signature
+// verifications for the repository, unless disabled using
+// SignatureCacheConfigKey. The cache only speeds up verification, so it's not
+// an error if it can't be enabled.
+func enableSignatureCache(gitDir string) {
+	gitConfig, err := gitinterface.GetConfig()
+	if err == nil && gitConfig[SignatureCacheConfigKey] == "false" {
+		slog.Debug("Signature cache is disabled")
+		sigcache.Disable()
+		return
+	}
+
+	slog.Debug("Loading signature cache...")
+	if err := sigcache.Enable(filepath.Join(gitDir, gittufDirName)); err != nil {
+		slog.Debug(fmt.Sprintf("Unable to load signature cache: %s", err.Error()))
+		sigcache.Disable()
+	}
 }
 
 func (r *Repository) InitializeNamespaces() error {
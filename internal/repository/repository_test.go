@@ -4,8 +4,12 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"os/exec"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-billy/v5/memfs"
@@ -15,9 +19,61 @@ import (
 )
 
 func TestLoadRepository(t *testing.T) {
-	repository, err := LoadRepository()
-	assert.Nil(t, err)
-	assert.NotNil(t, repository.r)
+	t.Run("current repository", func(t *testing.T) {
+		if gitinterface.BuildObjectFormat() != gitinterface.ObjectFormatSHA1 {
+			t.Skip("gittuf's repository uses the SHA-1 object format")
+		}
+
+		repository, err := LoadRepository()
+		assert.Nil(t, err)
+		assert.NotNil(t, repository.r)
+	})
+
+	t.Run("bare repository", func(t *testing.T) {
+		testDir := t.TempDir()
+
+		currentDir, err := os.Getwd()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chdir(testDir); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chdir(currentDir) //nolint:errcheck
+
+		if _, err := gitinterface.PlainInit(testDir, true); err != nil {
+			t.Fatal(err)
+		}
+
+		repository, err := LoadRepository()
+		assert.Nil(t, err)
+		assert.NotNil(t, repository.r)
+	})
+
+	t.Run("SHA-256 repository", func(t *testing.T) {
+		testDir := t.TempDir()
+
+		currentDir, err := os.Getwd()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chdir(testDir); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chdir(currentDir) //nolint:errcheck
+
+		if output, err := exec.Command("git", "init", "--object-format", gitinterface.ObjectFormatSHA256).CombinedOutput(); err != nil {
+			t.Fatal(fmt.Errorf("%w: %s", err, string(output)))
+		}
+
+		repository, err := LoadRepository()
+		if gitinterface.BuildObjectFormat() == gitinterface.ObjectFormatSHA256 {
+			assert.Nil(t, err)
+			assert.NotNil(t, repository.r)
+		} else {
+			assert.ErrorIs(t, err, ErrUnsupportedObjectFormat)
+		}
+	})
 }
 
 func TestInitializeNamespaces(t *testing.T) {
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
 
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
@@ -54,6 +55,32 @@ func (r *Repository) InitializeRoot(ctx context.Context, signer sslibdsse.Signer
 	return state.Commit(r.r, commitMessage, signCommit)
 }
 
+// InitializeRootFromSigningBundles creates the repository's root of trust using
+// root metadata signed out-of-band by its root key holders. Each bundle may be
+// signed by a different set of key holders, their signatures are merged and
+// must meet the root threshold before the root metadata is committed.
+func (r *Repository) InitializeRootFromSigningBundles(ctx context.Context, bundles []*policy.SigningBundle, signCommit bool) error {
+	slog.Debug("Merging signatures in signing bundles...")
+	bundle, err := policy.MergeSigningBundles(bundles...)
+	if err != nil {
+		return err
+	}
+
+	state, err := bundle.RootState(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := r.InitializeNamespaces(); err != nil {
+		return err
+	}
+
+	commitMessage := "Initialize root of trust from offline signing bundles"
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, signCommit)
+}
+
 // AddRootKey is the interface for the user to add an authorized key
 // for the Root role.
 func (r *Repository) AddRootKey(ctx context.Context, signer sslibdsse.SignerVerifier, newRootKey *tuf.Key, signCommit bool) error {
@@ -73,6 +100,10 @@ func (r *Repository) AddRootKey(ctx context.Context, signer sslibdsse.SignerVeri
 		return err
 	}
 
+	if slices.Contains(rootMetadata.Roles[policy.ObserverRoleName].KeyIDs, newRootKey.KeyID) {
+		return policy.ErrKeyIsObserverKey
+	}
+
 	slog.Debug("Adding root key...")
 	rootMetadata = policy.AddRootKey(rootMetadata, newRootKey)
 
@@ -186,6 +217,123 @@ func (r *Repository) RemoveTopLevelTargetsKey(ctx context.Context, signer sslibd
 	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
 }
 
+// AddObserverKey is the interface for the user to add a key that may sign
+// verification reports for the repository. Observer keys are never trusted for
+// changes to the RSL or the policy.
+func (r *Repository) AddObserverKey(ctx context.Context, signer sslibdsse.SignerVerifier, observerKey *tuf.Key, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Adding observer key...")
+	rootMetadata, err = policy.AddObserverKey(rootMetadata, observerKey)
+	if err != nil {
+		return fmt.Errorf("failed to add observer key: %w", err)
+	}
+
+	commitMessage := fmt.Sprintf("Add observer key '%s' to root", observerKey.KeyID)
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
+}
+
+// RemoveObserverKey is the interface for the user to remove a key trusted to
+// sign verification reports for the repository.
+func (r *Repository) RemoveObserverKey(ctx context.Context, signer sslibdsse.SignerVerifier, observerKeyID string, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Removing observer key...")
+	rootMetadata, err = policy.DeleteObserverKey(rootMetadata, observerKeyID)
+	if err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Remove observer key '%s' from root", observerKeyID)
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
+}
+
+// AddGitHubAppKey is the interface for the user to add a key trusted to sign
+// GitHub pull request approval attestations for the repository.
+func (r *Repository) AddGitHubAppKey(ctx context.Context, signer sslibdsse.SignerVerifier, appKey *tuf.Key, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Adding GitHub app key...")
+	rootMetadata, err = policy.AddGitHubAppKey(rootMetadata, appKey)
+	if err != nil {
+		return fmt.Errorf("failed to add GitHub app key: %w", err)
+	}
+
+	commitMessage := fmt.Sprintf("Add GitHub app key '%s' to root", appKey.KeyID)
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
+}
+
+// RemoveGitHubAppKey is the interface for the user to remove a key trusted to
+// sign GitHub pull request approval attestations for the repository.
+func (r *Repository) RemoveGitHubAppKey(ctx context.Context, signer sslibdsse.SignerVerifier, appKeyID string, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Removing GitHub app key...")
+	rootMetadata, err = policy.DeleteGitHubAppKey(rootMetadata, appKeyID)
+	if err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Remove GitHub app key '%s' from root", appKeyID)
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
+}
+
 // UpdateRootThreshold sets the threshold of valid signatures required for the
 // Root role.
 func (r *Repository) UpdateRootThreshold(ctx context.Context, signer sslibdsse.SignerVerifier, threshold int, signCommit bool) error {
@@ -244,6 +392,157 @@ func (r *Repository) UpdateTopLevelTargetsThreshold(ctx context.Context, signer
 	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
 }
 
+// SetKeyPolicy sets the key algorithms and minimum key sizes acceptable for
+// keys in the policy. Existing keys in the policy must be acceptable under the
+// new key policy.
+func (r *Repository) SetKeyPolicy(ctx context.Context, signer sslibdsse.SignerVerifier, allowedAlgorithms []string, minimumKeySizes map[string]int, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Updating key policy...")
+	rootMetadata, err = policy.SetKeyPolicy(rootMetadata, allowedAlgorithms, minimumKeySizes)
+	if err != nil {
+		return err
+	}
+
+	commitMessage := "Update key policy in root"
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
+}
+
+// SetAllowExpiredGPGKeys sets whether signatures made using GPG keys that have
+// since expired are trusted if the key was valid when the signed object was
+// recorded in the RSL.
+func (r *Repository) SetAllowExpiredGPGKeys(ctx context.Context, signer sslibdsse.SignerVerifier, allow bool, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Updating expired GPG key verification...")
+	rootMetadata, err = policy.SetAllowExpiredGPGKeys(rootMetadata, allow)
+	if err != nil {
+		return err
+	}
+
+	commitMessage := "Disallow expired GPG keys in root"
+	if allow {
+		commitMessage = "Allow expired GPG keys in root"
+	}
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
+}
+
+// AddRSLShard records that the entries for refs in the namespaces are recorded
+// in the RSL shard with the specified name, rather than the RSL itself.
+func (r *Repository) AddRSLShard(ctx context.Context, signer sslibdsse.SignerVerifier, name string, namespaces []string, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Adding RSL shard...")
+	rootMetadata, err = policy.AddRSLShard(rootMetadata, name, namespaces)
+	if err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add RSL shard '%s' to root", name)
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
+}
+
+// RemoveRSLShard removes the RSL shard with the specified name, so that the
+// entries for refs in its namespaces are recorded in the RSL itself.
+func (r *Repository) RemoveRSLShard(ctx context.Context, signer sslibdsse.SignerVerifier, name string, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Removing RSL shard...")
+	rootMetadata, err = policy.RemoveRSLShard(rootMetadata, name)
+	if err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Remove RSL shard '%s' from root", name)
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
+}
+
+// SetPolicyApprovalThreshold sets the number of policy administrators, i.e.,
+// holders of root and top-level targets keys, who must approve a change to the
+// policy before it takes effect. A threshold of 0 removes the requirement.
+func (r *Repository) SetPolicyApprovalThreshold(ctx context.Context, signer sslibdsse.SignerVerifier, threshold int, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Updating policy approval threshold...")
+	rootMetadata, err = policy.SetPolicyApprovalThreshold(rootMetadata, threshold)
+	if err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Set policy approval threshold to %d in root", threshold)
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
+}
+
 // SignRoot adds a signature to the Root envelope. Note that the metadata itself
 // is not modified, so its version remains the same.
 func (r *Repository) SignRoot(ctx context.Context, signer sslibdsse.SignerVerifier, signCommit bool) error {
@@ -5,11 +5,18 @@ package repository
 import (
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
 	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
 	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
 	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 	"github.com/stretchr/testify/assert"
 )
@@ -388,3 +395,288 @@ func TestSignRoot(t *testing.T) {
 
 	assert.Equal(t, 2, len(state.RootEnvelope.Signatures))
 }
+
+func TestSetKeyPolicy(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	sv, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The policy contains a GPG RSA key
+	err = r.SetKeyPolicy(testCtx, sv, []string{policy.KeyAlgorithmEd25519}, nil, false)
+	assert.ErrorIs(t, err, policy.ErrKeyAlgorithmNotAllowed)
+
+	err = r.SetKeyPolicy(testCtx, sv, nil, map[string]int{policy.KeyAlgorithmRSA: 4096}, false)
+	assert.ErrorIs(t, err, policy.ErrKeySizeTooSmall)
+
+	err = r.SetKeyPolicy(testCtx, sv, []string{policy.KeyAlgorithmEd25519, policy.KeyAlgorithmRSA}, map[string]int{policy.KeyAlgorithmRSA: 3072}, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []string{policy.KeyAlgorithmEd25519, policy.KeyAlgorithmRSA}, rootMetadata.KeyPolicy.AllowedAlgorithms)
+	assert.Equal(t, map[string]int{policy.KeyAlgorithmRSA: 3072}, rootMetadata.KeyPolicy.MinimumKeySizes)
+}
+
+func TestSetAllowExpiredGPGKeys(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	sv, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetAllowExpiredGPGKeys(testCtx, sv, true, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, rootMetadata.AllowExpiredGPGKeys)
+
+	err = r.SetAllowExpiredGPGKeys(testCtx, sv, false, false)
+	assert.Nil(t, err)
+
+	state, err = policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err = state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, rootMetadata.AllowExpiredGPGKeys)
+}
+
+func TestAddRSLShard(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	sv, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.AddRSLShard(testCtx, sv, "team-a", []string{"refs/heads/team-a/"}, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string][]string{"team-a": {"refs/heads/team-a/"}}, rootMetadata.RSLShards)
+
+	err = r.AddRSLShard(testCtx, sv, "team-b", []string{"refs/heads/team-a/"}, false)
+	assert.ErrorIs(t, err, policy.ErrRSLShardNamespaceInUse)
+
+	err = r.RemoveRSLShard(testCtx, sv, "team-a", false)
+	assert.Nil(t, err)
+
+	state, err = policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err = state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, rootMetadata.RSLShards)
+
+	err = r.RemoveRSLShard(testCtx, sv, "team-a", false)
+	assert.ErrorIs(t, err, policy.ErrRSLShardNotFound)
+}
+
+func TestSetPolicyApprovalThreshold(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The root and targets keys are the only policy administrators
+	err = r.SetPolicyApprovalThreshold(testCtx, rootSigner, 3, false)
+	assert.ErrorIs(t, err, policy.ErrCannotMeetThreshold)
+
+	err = r.SetPolicyApprovalThreshold(testCtx, rootSigner, 2, false)
+	assert.Nil(t, err)
+
+	// The applied policy doesn't require approvals yet
+	err = r.ApplyPolicy(testCtx, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, rootMetadata.PolicyApprovalThreshold)
+
+	t.Run("change approved using reference authorization", func(t *testing.T) {
+		if err := r.SetAllowExpiredGPGKeys(testCtx, rootSigner, true, false); err != nil {
+			t.Fatal(err)
+		}
+
+		err := r.ApplyPolicy(testCtx, false)
+		assert.ErrorIs(t, err, policy.ErrPolicyChangeNotApproved)
+
+		policyTip, stagedTree := getPolicyTipAndStagedTree(t, r)
+		err = r.AddReferenceAuthorizationForIDs(testCtx, targetsSigner, policy.PolicyRef, policyTip.String(), stagedTree.String(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = r.ApplyPolicy(testCtx, false)
+		assert.Nil(t, err)
+
+		state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyRef)
+		assert.Nil(t, err)
+		rootMetadata, err := state.GetRootMetadata()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, rootMetadata.AllowExpiredGPGKeys)
+	})
+
+	t.Run("unapproved change rejected during verification", func(t *testing.T) {
+		if err := r.SetAllowExpiredGPGKeys(testCtx, rootSigner, false, false); err != nil {
+			t.Fatal(err)
+		}
+
+		// Apply the change bypassing the check in apply
+		stagingRef, err := r.r.Reference(plumbing.ReferenceName(policy.PolicyStagingRef), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.r.Storer.SetReference(plumbing.NewHashReference(policy.PolicyRef, stagingRef.Hash())); err != nil {
+			t.Fatal(err)
+		}
+		if err := rsl.NewReferenceEntry(policy.PolicyRef, stagingRef.Hash()).Commit(r.r, false); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = policy.LoadCurrentState(testCtx, r.r, policy.PolicyRef)
+		assert.ErrorIs(t, err, policy.ErrPolicyChangeNotApproved)
+
+		// Verifying a ref checks every policy change recorded in its RSL
+		refName := "refs/heads/main"
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r.r, refName, 1, gpgKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, r.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+
+		_, err = policy.VerifyRefFull(testCtx, r.r, refName)
+		assert.ErrorIs(t, err, policy.ErrPolicyChangeNotApproved)
+	})
+}
+
+func getPolicyTipAndStagedTree(t *testing.T, r *Repository) (plumbing.Hash, plumbing.Hash) {
+	t.Helper()
+
+	policyRef, err := r.r.Reference(plumbing.ReferenceName(policy.PolicyRef), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stagingRef, err := r.r.Reference(plumbing.ReferenceName(policy.PolicyStagingRef), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stagingCommit, err := gitinterface.GetCommit(r.r, stagingRef.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return policyRef.Hash(), stagingCommit.TreeHash
+}
+
+func TestInitializeRootFromSigningBundles(t *testing.T) {
+	rootKey1, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootKey2, err := tuf.LoadKeyFromBytes(targetsPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer1, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer2, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundle, err := policy.NewRootSigningBundle([]*tuf.Key{rootKey1, rootKey2}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents, err := bundle.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	firstCopy, err := policy.LoadSigningBundle(contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := firstCopy.Sign(testCtx, signer1); err != nil {
+		t.Fatal(err)
+	}
+
+	gitRepo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Repository{r: gitRepo}
+
+	// Threshold not met yet
+	err = r.InitializeRootFromSigningBundles(testCtx, []*policy.SigningBundle{firstCopy}, false)
+	assert.NotNil(t, err)
+
+	secondCopy, err := policy.LoadSigningBundle(contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := secondCopy.Sign(testCtx, signer2); err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.InitializeRootFromSigningBundles(testCtx, []*policy.SigningBundle{firstCopy, secondCopy}, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err := state.GetRootMetadata()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{rootKey1.KeyID, rootKey2.KeyID}, rootMetadata.Roles[policy.RootRoleName].KeyIDs)
+	assert.Equal(t, 2, rootMetadata.Roles[policy.RootRoleName].Threshold)
+	assert.Len(t, state.RootEnvelope.Signatures, 2)
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+)
+
+const (
+	// RootPinModeConfigKey is the Git config key used to set how a change in
+	// the root of trust keys that isn't a rotation from the pinned keys is
+	// handled, one of RootPinModeFail (the default) and RootPinModeWarn.
+	RootPinModeConfigKey = "gittuf.rootpin.mode"
+
+	RootPinModeFail = "fail"
+	RootPinModeWarn = "warn"
+)
+
+var ErrInvalidRootPinMode = errors.New("invalid root pin mode (not one of fail, warn)")
+
+// VerifyRootPin checks that the root of trust keys of the current policy match
+// the keys pinned for the repository or were rotated from them. The pin is not
+// updated, and if the root of trust isn't pinned, a warning is logged and
+// verification is skipped. The handling of a mismatch is set using
+// RootPinModeConfigKey.
+func (r *Repository) VerifyRootPin(ctx context.Context) error {
+	warnOnly, err := getRootPinWarnOnly()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Verifying root of trust keys against pinned keys...")
+	return policy.VerifyRootPin(ctx, r.r, warnOnly)
+}
+
+// PinRootKeys pins the root of trust keys of the current policy. If no keys are
+// pinned yet, the current keys are trusted on first use. If the pinned keys
+// were rotated to the current keys, the pin is updated. The handling of a
+// mismatch is set using RootPinModeConfigKey.
+func (r *Repository) PinRootKeys(ctx context.Context) error {
+	warnOnly, err := getRootPinWarnOnly()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Pinning root of trust keys...")
+	return policy.PinRootKeys(ctx, r.r, warnOnly)
+}
+
+// ResetRootPin removes the root of trust pin, so that the root of trust keys
+// are trusted on first use when they're next pinned. This must only be used
+// after confirming the current root of trust keys out of band.
+func (r *Repository) ResetRootPin() error {
+	slog.Debug("Removing root of trust pin...")
+	return policy.ResetRootPin(r.r)
+}
+
+// GetInitialRootHash returns the hash of the root metadata in the repository's
+// first policy state, which can be shared out of band to bootstrap trust in
+// the repository's root of trust.
+func (r *Repository) GetInitialRootHash() (string, error) {
+	return policy.GetInitialRootHash(r.r)
+}
+
+// PinInitialRoot pins the hash of the repository's initial root metadata,
+// obtained out of band. Subsequent verifications fail if the root of trust
+// lineage of the policy doesn't begin with root metadata matching the hash,
+// regardless of the root pin mode.
+func (r *Repository) PinInitialRoot(rootHash string) error {
+	slog.Debug("Pinning initial root of trust hash...")
+	return policy.PinInitialRoot(r.r, rootHash)
+}
+
+// getRootPinWarnOnly returns true if the root pin mode set using
+// RootPinModeConfigKey is RootPinModeWarn.
+func getRootPinWarnOnly() (bool, error) {
+	mode := RootPinModeFail
+
+	// The Git config may not be readable, such as when no config is set, in
+	// which case the default mode is used
+	gitConfig, err := gitinterface.GetConfig()
+	if err == nil {
+		if configuredMode, has := gitConfig[RootPinModeConfigKey]; has {
+			mode = configuredMode
+		}
+	}
+
+	switch mode {
+	case RootPinModeFail, RootPinModeWarn:
+	default:
+		return false, fmt.Errorf("%w: '%s'", ErrInvalidRootPinMode, mode)
+	}
+
+	return mode == RootPinModeWarn, nil
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyRootPin(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	refName := "refs/heads/main"
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+
+	// Verification doesn't pin the root of trust keys
+	err := repo.VerifyRef(testCtx, refName, true)
+	assert.Nil(t, err)
+
+	pin, err := policy.LoadRootPin(repo.r)
+	assert.Nil(t, err)
+	assert.Nil(t, pin)
+
+	_, err = repo.r.Reference(plumbing.ReferenceName(policy.RootPinRef), true)
+	assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+
+	err = repo.PinRootKeys(testCtx)
+	assert.Nil(t, err)
+
+	pin, err = policy.LoadRootPin(repo.r)
+	assert.Nil(t, err)
+	assert.NotNil(t, pin)
+
+	err = repo.VerifyRef(testCtx, refName, true)
+	assert.Nil(t, err)
+
+	// Mimic a pin for a root of trust that has since been replaced
+	swappedPin := &policy.RootPin{RootKeyIDs: []string{"swapped"}, PolicyEntryID: plumbing.ZeroHash.String()}
+	if err := swappedPin.Commit(repo.r); err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.VerifyRef(testCtx, refName, true)
+	assert.ErrorIs(t, err, policy.ErrRootPinMismatch)
+
+	// After the pin is reset, verification is skipped until the current keys
+	// are pinned again
+	err = repo.ResetRootPin()
+	assert.Nil(t, err)
+
+	err = repo.VerifyRef(testCtx, refName, true)
+	assert.Nil(t, err)
+
+	err = repo.PinRootKeys(testCtx)
+	assert.Nil(t, err)
+
+	newPin, err := policy.LoadRootPin(repo.r)
+	assert.Nil(t, err)
+	assert.Equal(t, pin.RootKeyIDs, newPin.RootKeyIDs)
+}
@@ -6,12 +6,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"slices"
+	"strings"
+	"text/template"
 
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/progress"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/transport"
@@ -21,19 +27,90 @@ var (
 	ErrCommitNotInRef = errors.New("specified commit is not in ref")
 	ErrPushingRSL     = errors.New("unable to push RSL")
 	ErrPullingRSL     = errors.New("unable to pull RSL")
+	ErrUnbornBranch   = errors.New("branch has no commits yet, create a commit before recording it in the RSL")
+
+	ErrInvalidMessageTemplate  = errors.New("invalid RSL entry message template")
+	ErrInvalidRSLLogPagination = errors.New("RSL log offset and limit must not be negative")
+
+	// errStopRSLEntryLog stops walking the RSL once a page of entries is
+	// complete.
+	errStopRSLEntryLog = errors.New("stop walking RSL")
 )
 
+// RecordRSLEntryOptions sets the optional information recorded in an RSL
+// reference entry.
+type RecordRSLEntryOptions struct {
+	// ChangedPaths records the top-level paths changed since the previous
+	// entry for the reference. The reference must point to a commit.
+	ChangedPaths bool
+
+	// Tickets are the URIs of the issues or tickets that track the change.
+	Tickets []string
+
+	// Submodules records the commit IDs of the submodules in the reference's
+	// target. The reference must point to a commit.
+	Submodules bool
+
+	// Message is a note stored in the entry, such as the reason for the
+	// change or a link to its approval.
+	Message string
+
+	// MessageTemplate is a text/template used to create the entry's message
+	// if Message is not set. The template is executed with the entry's
+	// RefName, TargetID, and Tickets, such as "Deploy {{.TargetID}}".
+	MessageTemplate string
+}
+
+// entryMessageTemplateData is the data the message template for an RSL entry
+// is executed with.
+type entryMessageTemplateData struct {
+	RefName  string
+	TargetID string
+	Tickets  []string
+}
+
 // RecordRSLEntryForReference is the interface for the user to add an RSL entry
 // for the specified Git reference.
-func (r *Repository) RecordRSLEntryForReference(refName string, signCommit bool) error {
+func (r *Repository) RecordRSLEntryForReference(ctx context.Context, refName string, signCommit bool) error {
+	return r.RecordRSLEntryForReferenceWithOptions(ctx, refName, &RecordRSLEntryOptions{}, signCommit)
+}
+
+// RecordRSLEntryForReferenceWithChangedPaths adds an RSL entry for the
+// specified Git reference that also records the top-level paths changed since
+// the previous entry for the reference. The reference must point to a commit.
+func (r *Repository) RecordRSLEntryForReferenceWithChangedPaths(ctx context.Context, refName string, signCommit bool) error {
+	return r.RecordRSLEntryForReferenceWithOptions(ctx, refName, &RecordRSLEntryOptions{ChangedPaths: true}, signCommit)
+}
+
+// RecordRSLEntryForReferenceWithOptions adds an RSL entry for the specified Git
+// reference that records the optional information set in options. If the
+// policy records the reference's entries in an RSL shard, the entry is added to
+// the shard and the RSL records the shard's new tip.
+func (r *Repository) RecordRSLEntryForReferenceWithOptions(ctx context.Context, refName string, options *RecordRSLEntryOptions, signCommit bool) error {
+	if err := rsl.ValidateTickets(options.Tickets); err != nil {
+		return err
+	}
+
 	slog.Debug("Identifying absolute reference path...")
 	absRefName, err := gitinterface.AbsoluteReference(r.r, refName)
 	if err != nil {
+		if unbornBranch := r.unbornHeadBranch(); unbornBranch != "" && (refName == unbornBranch || string(plumbing.NewBranchReferenceName(refName)) == unbornBranch) {
+			return fmt.Errorf("%w: '%s'", ErrUnbornBranch, unbornBranch)
+		}
 		return err
 	}
 
 	slog.Debug(fmt.Sprintf("Loading current state of '%s'...", absRefName))
 	ref, err := r.r.Reference(plumbing.ReferenceName(absRefName), true)
+	if err != nil {
+		if unbornBranch := r.unbornHeadBranch(); unbornBranch != "" && absRefName == unbornBranch {
+			return fmt.Errorf("%w: '%s'", ErrUnbornBranch, unbornBranch)
+		}
+		return err
+	}
+
+	slog.Debug("Identifying RSL shard for reference...")
+	shard, err := r.getRSLShardForRef(ctx, absRefName)
 	if err != nil {
 		return err
 	}
@@ -50,8 +127,72 @@ func (r *Repository) RecordRSLEntryForReference(refName string, signCommit bool)
 	// TODO: once policy verification is in place, the signing key used by
 	// signCommit must be verified for the refName in the delegation tree.
 
+	entry := rsl.NewReferenceEntry(absRefName, ref.Hash())
+	if options.ChangedPaths {
+		slog.Debug("Identifying paths changed since previous entry for reference...")
+		priorEntry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, absRefName)
+		if err != nil {
+			if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				return err
+			}
+			priorEntry = nil
+		}
+
+		changedPaths, err := rsl.GetChangedPaths(r.r, priorEntry, ref.Hash())
+		if err != nil {
+			return err
+		}
+
+		entry = rsl.NewReferenceEntryWithChangedPaths(absRefName, ref.Hash(), changedPaths)
+	}
+	entry.Tickets = options.Tickets
+
+	entry.Message = options.Message
+	if entry.Message == "" && options.MessageTemplate != "" {
+		slog.Debug("Creating entry message from template...")
+		entry.Message, err = executeEntryMessageTemplate(options.MessageTemplate, entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	if options.Submodules {
+		slog.Debug("Identifying submodule commits for reference...")
+		entry.Submodules, err = rsl.GetSubmoduleCommits(r.r, ref.Hash())
+		if err != nil {
+			return err
+		}
+	}
+
+	if shard != "" {
+		slog.Debug(fmt.Sprintf("Creating RSL reference entry in shard '%s'...", shard))
+		return entry.CommitToShard(r.r, shard, signCommit)
+	}
+
 	slog.Debug("Creating RSL reference entry...")
-	return rsl.NewReferenceEntry(absRefName, ref.Hash()).Commit(r.r, signCommit)
+	return entry.Commit(r.r, signCommit)
+}
+
+// executeEntryMessageTemplate returns the message for the entry created using
+// the template.
+func executeEntryMessageTemplate(messageTemplate string, entry *rsl.ReferenceEntry) (string, error) {
+	tmpl, err := template.New("message").Option("missingkey=error").Parse(messageTemplate)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidMessageTemplate, err)
+	}
+
+	data := &entryMessageTemplateData{
+		RefName:  entry.RefName,
+		TargetID: entry.TargetID.String(),
+		Tickets:  entry.Tickets,
+	}
+
+	var message strings.Builder
+	if err := tmpl.Execute(&message, data); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidMessageTemplate, err)
+	}
+
+	return strings.TrimSpace(message.String()), nil
 }
 
 // RecordRSLEntryForReferenceAtTarget is a special version of
@@ -77,8 +218,9 @@ func (r *Repository) RecordRSLEntryForReferenceAtTarget(refName string, targetID
 }
 
 // RecordRSLAnnotation is the interface for the user to add an RSL annotation
-// for one or more prior RSL entries.
-func (r *Repository) RecordRSLAnnotation(rslEntryIDs []string, skip bool, message string, signCommit bool) error {
+// for one or more prior RSL entries. The annotation optionally records the URIs
+// of relevant issues or tickets.
+func (r *Repository) RecordRSLAnnotation(rslEntryIDs []string, skip bool, message string, tickets []string, signCommit bool) error {
 	rslEntryHashes := []plumbing.Hash{}
 	for _, id := range rslEntryIDs {
 		rslEntryHashes = append(rslEntryHashes, plumbing.NewHash(id))
@@ -88,7 +230,29 @@ func (r *Repository) RecordRSLAnnotation(rslEntryIDs []string, skip bool, messag
 	// signCommit must be verified for the refNames of the rslEntryIDs.
 
 	slog.Debug("Creating RSL annotation entry...")
-	return rsl.NewAnnotationEntry(rslEntryHashes, skip, message).Commit(r.r, signCommit)
+	annotation := rsl.NewAnnotationEntry(rslEntryHashes, skip, message)
+	annotation.Tickets = tickets
+	return annotation.Commit(r.r, signCommit)
+}
+
+// RecordRSLAnnotationForRange is the interface for the user to add an RSL
+// annotation for all the RSL entries for a ref between the specified start and
+// end entries, inclusive. The annotation optionally records the URIs of
+// relevant issues or tickets.
+func (r *Repository) RecordRSLAnnotationForRange(refName, startID, endID string, skip bool, message string, tickets []string, signCommit bool) error {
+	slog.Debug("Identifying absolute reference path...")
+	absRefName, err := gitinterface.AbsoluteReference(r.r, refName)
+	if err != nil {
+		return err
+	}
+
+	// TODO: once policy verification is in place, the signing key used by
+	// signCommit must be verified for the refName.
+
+	slog.Debug("Creating RSL annotation entry for range...")
+	annotation := rsl.NewAnnotationEntryForRange(absRefName, plumbing.NewHash(startID), plumbing.NewHash(endID), skip, message)
+	annotation.Tickets = tickets
+	return annotation.Commit(r.r, signCommit)
 }
 
 // CheckRemoteRSLForUpdates checks if the RSL at the specified remote
@@ -100,7 +264,12 @@ func (r *Repository) RecordRSLAnnotation(rslEntryIDs []string, skip bool, messag
 // diverged and need to be reconciled.
 func (r *Repository) CheckRemoteRSLForUpdates(ctx context.Context, remoteName string) (bool, bool, error) {
 	trackerRef := rsl.RemoteTrackerRef(remoteName)
-	rslRemoteRefSpec := []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", rsl.Ref, trackerRef))}
+	rslRemoteRefSpec := []config.RefSpec{
+		config.RefSpec(fmt.Sprintf("%s:%s", rsl.Ref, trackerRef)),
+		// The RSL shards are fetched so that the shard tips recorded in the
+		// remote RSL are available
+		config.RefSpec(fmt.Sprintf("+%s*:%s*", rsl.ShardRefPrefix, rsl.RemoteShardTrackerRefPrefix(remoteName))),
+	}
 
 	slog.Debug("Updating remote RSL tracker...")
 	if err := gitinterface.FetchRefSpec(ctx, r.r, remoteName, rslRemoteRefSpec); err != nil {
@@ -113,12 +282,24 @@ func (r *Repository) CheckRemoteRSLForUpdates(ctx context.Context, remoteName st
 
 	remoteRefState, err := r.r.Reference(plumbing.ReferenceName(trackerRef), true)
 	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			// The remote has no RSL, such as when it's freshly created
+			slog.Debug("Remote RSL does not exist")
+			return false, false, nil
+		}
 		return false, false, err
 	}
+	if remoteRefState.Hash().IsZero() {
+		slog.Debug("Remote RSL has not been populated")
+		return false, false, nil
+	}
 
 	localRefState, err := r.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
 	if err != nil {
-		return false, false, err
+		if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return false, false, err
+		}
+		localRefState = plumbing.NewHashReference(plumbing.ReferenceName(rsl.Ref), plumbing.ZeroHash)
 	}
 
 	// Check if local is nil and exit appropriately
@@ -170,28 +351,110 @@ func (r *Repository) CheckRemoteRSLForUpdates(ctx context.Context, remoteName st
 	return true, true, nil
 }
 
-// PushRSL pushes the local RSL to the specified remote. As this push defaults
-// to fast-forward only, divergent RSL states are detected.
+// PushRSL pushes the local RSL and RSL shards to the specified remote. As this
+// push defaults to fast-forward only, divergent RSL states are detected.
 func (r *Repository) PushRSL(ctx context.Context, remoteName string) error {
+	shardRefs, err := r.refsWithPrefix(rsl.ShardRefPrefix)
+	if err != nil {
+		return errors.Join(ErrPushingRSL, err)
+	}
+
+	refNames := []string{rsl.Ref}
+	for refName := range shardRefs {
+		refNames = append(refNames, refName)
+	}
+	slices.Sort(refNames[1:])
+
+	refs, err := r.populatedRefs(refNames)
+	if err != nil {
+		return errors.Join(ErrPushingRSL, err)
+	}
+	if len(refs) == 0 {
+		slog.Debug("RSL has no entries yet, nothing to push")
+		return nil
+	}
+
 	slog.Debug(fmt.Sprintf("Pushing RSL reference to '%s'...", remoteName))
-	if err := gitinterface.Push(ctx, r.r, remoteName, []string{rsl.Ref}); err != nil {
+	progress.Stage("pushing RSL", 0)
+	if err := gitinterface.Push(ctx, r.r, remoteName, refs); err != nil {
 		return errors.Join(ErrPushingRSL, err)
 	}
 
 	return nil
 }
 
-// PullRSL pulls RSL contents from the specified remote to the local RSL. The
-// fetch is marked as fast forward only to detect RSL divergence.
+// PullRSL pulls RSL contents from the specified remote to the local RSL and
+// RSL shards. The fetch is marked as fast forward only to detect RSL
+// divergence.
 func (r *Repository) PullRSL(ctx context.Context, remoteName string) error {
 	slog.Debug(fmt.Sprintf("Pulling RSL reference from '%s'...", remoteName))
-	if err := gitinterface.Fetch(ctx, r.r, remoteName, []string{rsl.Ref}, true); err != nil {
+	progress.Stage("pulling RSL", 0)
+	if err := gitinterface.Fetch(ctx, r.r, remoteName, []string{rsl.Ref, rsl.ShardRefPrefix + "*"}, true); err != nil {
 		return errors.Join(ErrPullingRSL, err)
 	}
 
 	return nil
 }
 
+// populatedRefs returns the specified refs that exist and do not point to the
+// zero hash, as is the case for gittuf refs that have been initialized but not
+// yet populated.
+func (r *Repository) populatedRefs(refNames []string) ([]string, error) {
+	populated := []string{}
+	for _, refName := range refNames {
+		ref, err := r.r.Reference(plumbing.ReferenceName(refName), true)
+		if err != nil {
+			if errors.Is(err, plumbing.ErrReferenceNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		if !ref.Hash().IsZero() {
+			populated = append(populated, refName)
+		}
+	}
+
+	return populated, nil
+}
+
+// refsWithPrefix returns the tips of the refs whose names start with the
+// prefix, such as the refs for the RSL shards.
+func (r *Repository) refsWithPrefix(prefix string) (map[string]plumbing.Hash, error) {
+	refs, err := r.r.References()
+	if err != nil {
+		return nil, err
+	}
+
+	tips := map[string]plumbing.Hash{}
+	if err := refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference && strings.HasPrefix(ref.Name().String(), prefix) {
+			tips[ref.Name().String()] = ref.Hash()
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return tips, nil
+}
+
+// unbornHeadBranch returns the branch HEAD points to if the branch has no
+// commits yet, as is the case in a freshly initialized repository. Otherwise,
+// an empty string is returned.
+func (r *Repository) unbornHeadBranch() string {
+	head, err := r.r.Storer.Reference(plumbing.HEAD)
+	if err != nil || head.Type() != plumbing.SymbolicReference {
+		return ""
+	}
+
+	if _, err := r.r.Reference(head.Target(), true); errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return head.Target().String()
+	}
+
+	return ""
+}
+
 // isDuplicateEntry checks if the latest unskipped entry for the ref has the
 // same target ID Note that it's legal for the RSL to have target A, then B,
 // then A again, this is not considered a duplicate entry
@@ -207,24 +470,180 @@ func (r *Repository) isDuplicateEntry(refName string, targetID plumbing.Hash) (b
 	return latestUnskippedEntry.TargetID == targetID, nil
 }
 
-// GetRSLEntryLog gives us a list of all the rsl entries, and a map with a key being
-// a reference entry, and the value being an array of all applicable annotations for that reference entry
-func GetRSLEntryLog(repo *Repository) ([]*rsl.ReferenceEntry, map[plumbing.Hash][]*rsl.AnnotationEntry, error) {
-	firstEntry, _, err := rsl.GetFirstEntry(repo.r)
+// GetRSLEntryLog walks the RSL from the latest entry to the first entry and
+// invokes fn for each reference entry with the annotations that apply to it,
+// in order of occurrence. Entries are loaded one at a time, so only the
+// annotations that refer to entries not yet visited are held in memory. If fn
+// returns an error, the walk stops and the error is returned.
+func GetRSLEntryLog(repo *Repository, fn func(*rsl.ReferenceEntry, []*rsl.AnnotationEntry) error) error {
+	iterator, err := rsl.NewIterator(repo.r)
 	if err != nil {
-		return nil, nil, err
+		return err
+	}
+
+	// Annotations always follow the entries they refer to, so they are
+	// recorded until the entry is reached
+	pendingAnnotations := map[plumbing.Hash][]*rsl.AnnotationEntry{}
+	for {
+		entry, err := iterator.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		switch entry := entry.(type) {
+		case *rsl.ReferenceEntry:
+			annotations := pendingAnnotations[entry.ID]
+			delete(pendingAnnotations, entry.ID)
+
+			// Annotations were recorded from latest to earliest
+			slices.Reverse(annotations)
+			if err := fn(entry, annotations); err != nil {
+				return err
+			}
+		case *rsl.AnnotationEntry:
+			for _, entryID := range entry.RSLEntryIDs {
+				pendingAnnotations[entryID] = append(pendingAnnotations[entryID], entry)
+			}
+		}
+	}
+}
+
+// RSLEntryLogOptions selects the reference entries in the RSL log.
+type RSLEntryLogOptions struct {
+	rsl.Query
+
+	// SignedBy restricts the log to entries signed using the key with the
+	// specified ID. The key must be present in a policy state in the
+	// repository's history.
+	SignedBy string
+
+	// Offset is the number of selected entries, from the latest entry, that
+	// are left out of the log, and Limit is the maximum number of entries in
+	// the log. The log is not limited if Limit is zero.
+	Offset int
+	Limit  int
+}
+
+// QueryRSLEntryLog walks the RSL like GetRSLEntryLog, but only invokes fn for
+// the reference entries selected by the options. The walk stops once the limit
+// is reached.
+func QueryRSLEntryLog(ctx context.Context, repo *Repository, opts *RSLEntryLogOptions, fn func(*rsl.ReferenceEntry, []*rsl.AnnotationEntry) error) error {
+	if opts.Offset < 0 || opts.Limit < 0 {
+		return ErrInvalidRSLLogPagination
+	}
+
+	var signingKey *tuf.Key
+	if opts.SignedBy != "" {
+		var err error
+		signingKey, err = repo.findKeyInPolicyHistory(ctx, opts.SignedBy)
+		if err != nil {
+			return err
+		}
+	}
+
+	selected := 0
+	err := GetRSLEntryLog(repo, func(entry *rsl.ReferenceEntry, annotations []*rsl.AnnotationEntry) error {
+		matches, err := opts.Query.Matches(repo.r, entry, annotations)
+		if err != nil {
+			return err
+		}
+		if !matches {
+			return nil
+		}
+
+		if signingKey != nil {
+			signed, err := repo.isEntrySignedBy(ctx, entry, signingKey)
+			if err != nil {
+				return err
+			}
+			if !signed {
+				return nil
+			}
+		}
+
+		selected++
+		if selected <= opts.Offset {
+			return nil
+		}
+
+		if err := fn(entry, annotations); err != nil {
+			return err
+		}
+
+		if opts.Limit != 0 && selected-opts.Offset >= opts.Limit {
+			return errStopRSLEntryLog
+		}
+		return nil
+	})
+	if errors.Is(err, errStopRSLEntryLog) {
+		return nil
+	}
+	return err
+}
+
+// findKeyInPolicyHistory returns the key with the specified ID from the latest
+// policy state that has it.
+func (r *Repository) findKeyInPolicyHistory(ctx context.Context, keyID string) (*tuf.Key, error) {
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, policy.PolicyRef)
+	for err == nil {
+		state, loadErr := policy.LoadState(ctx, r.r, entry)
+		if loadErr != nil {
+			return nil, loadErr
+		}
+
+		allKeys, loadErr := state.PublicKeys()
+		if loadErr != nil {
+			return nil, loadErr
+		}
+		if key, has := allKeys[keyID]; has {
+			return key, nil
+		}
+
+		entry, _, err = rsl.GetLatestReferenceEntryForRefBefore(r.r, policy.PolicyRef, entry.ID)
+	}
+	if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return nil, ErrKeyNotFoundInPolicy
 	}
 
-	lastEntry, err := rsl.GetLatestEntry(repo.r)
+	return nil, err
+}
+
+// isEntrySignedBy checks if the RSL entry is signed using the key. Unsigned
+// entries are not signed by the key.
+func (r *Repository) isEntrySignedBy(ctx context.Context, entry rsl.Entry, key *tuf.Key) (bool, error) {
+	commit, err := gitinterface.GetCommit(r.r, entry.GetID())
 	if err != nil {
-		return nil, nil, err
+		return false, err
+	}
+	if len(commit.PGPSignature) == 0 {
+		return false, nil
 	}
 
-	entries, annotationMap, err := rsl.GetReferenceEntriesInRange(repo.r, firstEntry.GetID(), lastEntry.GetID())
+	err = gitinterface.VerifyCommitSignature(ctx, commit, key)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, gitinterface.ErrUnknownSigningMethod), errors.Is(err, gitinterface.ErrIncorrectVerificationKey):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// getRSLShardForRef returns the name of the RSL shard the current policy
+// records the ref's entries in, or an empty string if they're recorded in the
+// RSL itself.
+func (r *Repository) getRSLShardForRef(ctx context.Context, refName string) (string, error) {
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
 	if err != nil {
-		return nil, nil, err
+		if errors.Is(err, policy.ErrPolicyNotFound) {
+			return "", nil
+		}
+		return "", err
 	}
 
-	slices.Reverse(entries)
-	return entries, annotationMap, nil
+	return state.GetRSLShardForRef(refName)
 }
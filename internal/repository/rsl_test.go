@@ -4,15 +4,18 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"slices"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/common"
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
@@ -39,7 +42,7 @@ func TestRecordRSLEntryForReference(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := repo.RecordRSLEntryForReference("refs/heads/main", false); err != nil {
+	if err := repo.RecordRSLEntryForReference(testCtx, "refs/heads/main", false); err != nil {
 		t.Fatal(err)
 	}
 
@@ -67,7 +70,7 @@ func TestRecordRSLEntryForReference(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := repo.RecordRSLEntryForReference("main", false); err != nil {
+	if err := repo.RecordRSLEntryForReference(testCtx, "main", false); err != nil {
 		t.Fatal(err)
 	}
 
@@ -88,7 +91,7 @@ func TestRecordRSLEntryForReference(t *testing.T) {
 	assert.Equal(t, "refs/heads/main", entry.RefName)
 	assert.Equal(t, testHash, entry.TargetID)
 
-	err = repo.RecordRSLEntryForReference("main", false)
+	err = repo.RecordRSLEntryForReference(testCtx, "main", false)
 	assert.Nil(t, err)
 
 	rslRef, err = repo.r.Reference(rsl.Ref, true)
@@ -102,6 +105,135 @@ func TestRecordRSLEntryForReference(t *testing.T) {
 	}
 	// check that a duplicate entry has not been created
 	assert.Equal(t, entry.GetID(), entryType.GetID())
+
+	t.Run("unborn branch", func(t *testing.T) {
+		r, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		repo := &Repository{r: r}
+
+		// HEAD points to a branch with no commits in a freshly initialized
+		// repository
+		err = repo.RecordRSLEntryForReference(testCtx, "HEAD", false)
+		assert.ErrorIs(t, err, ErrUnbornBranch)
+
+		err = repo.RecordRSLEntryForReference(testCtx, "master", false)
+		assert.ErrorIs(t, err, ErrUnbornBranch)
+
+		err = repo.RecordRSLEntryForReference(testCtx, "feature", false)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+}
+
+func TestRecordRSLEntryForReferenceWithChangedPaths(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := rsl.InitializeNamespace(repo.r); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+
+	common.AddNTestCommitsToSpecifiedRef(t, r, refName, 2, gpgKeyBytes)
+	err = repo.RecordRSLEntryForReferenceWithChangedPaths(testCtx, refName, false)
+	assert.Nil(t, err)
+
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r, refName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"1", "2"}, entry.ChangedPaths)
+
+	common.AddNTestCommitsToSpecifiedRef(t, r, refName, 3, gpgKeyBytes)
+	err = repo.RecordRSLEntryForReferenceWithChangedPaths(testCtx, "main", false)
+	assert.Nil(t, err)
+
+	entry, _, err = rsl.GetLatestReferenceEntryForRef(r, refName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"3"}, entry.ChangedPaths)
+
+	// Entries recorded without changed paths don't have them
+	common.AddNTestCommitsToSpecifiedRef(t, r, refName, 1, gpgKeyBytes)
+	err = repo.RecordRSLEntryForReference(testCtx, refName, false)
+	assert.Nil(t, err)
+
+	entry, _, err = rsl.GetLatestReferenceEntryForRef(r, refName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, entry.ChangedPaths)
+
+	// Refs that don't point to commits are rejected
+	tagRef := "refs/tags/v1"
+	if err := r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(tagRef), plumbing.NewHash("abcdef1234567890"))); err != nil {
+		t.Fatal(err)
+	}
+	err = repo.RecordRSLEntryForReferenceWithChangedPaths(testCtx, tagRef, false)
+	assert.ErrorIs(t, err, rsl.ErrChangedPathsNeedCommits)
+}
+
+func TestRecordRSLEntryForReferenceWithOptions(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := rsl.InitializeNamespace(repo.r); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	tickets := []string{"https://example.com/issues/1", "urn:jira:GTF-2"}
+
+	common.AddNTestCommitsToSpecifiedRef(t, r, refName, 1, gpgKeyBytes)
+	err = repo.RecordRSLEntryForReferenceWithOptions(testCtx, refName, &RecordRSLEntryOptions{ChangedPaths: true, Tickets: tickets}, false)
+	assert.Nil(t, err)
+
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r, refName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"1"}, entry.ChangedPaths)
+	assert.Equal(t, tickets, entry.Tickets)
+
+	common.AddNTestCommitsToSpecifiedRef(t, r, refName, 1, gpgKeyBytes)
+	err = repo.RecordRSLEntryForReferenceWithOptions(testCtx, refName, &RecordRSLEntryOptions{Tickets: []string{"GTF-2"}}, false)
+	assert.ErrorIs(t, err, rsl.ErrInvalidTicket)
+
+	t.Run("with message", func(t *testing.T) {
+		err := repo.RecordRSLEntryForReferenceWithOptions(testCtx, refName, &RecordRSLEntryOptions{Message: "Approved in https://example.com/reviews/1"}, false)
+		assert.Nil(t, err)
+
+		entry, _, err := rsl.GetLatestReferenceEntryForRef(r, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "Approved in https://example.com/reviews/1", entry.Message)
+	})
+
+	t.Run("with message template", func(t *testing.T) {
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, refName, 1, gpgKeyBytes)
+		err := repo.RecordRSLEntryForReferenceWithOptions(testCtx, refName, &RecordRSLEntryOptions{Tickets: tickets[:1], MessageTemplate: "Deploy {{.RefName}} at {{.TargetID}} for {{index .Tickets 0}}\n"}, false)
+		assert.Nil(t, err)
+
+		entry, _, err := rsl.GetLatestReferenceEntryForRef(r, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, fmt.Sprintf("Deploy %s at %s for %s", refName, commitIDs[0].String(), tickets[0]), entry.Message)
+	})
+
+	t.Run("invalid message template", func(t *testing.T) {
+		common.AddNTestCommitsToSpecifiedRef(t, r, refName, 1, gpgKeyBytes)
+		err := repo.RecordRSLEntryForReferenceWithOptions(testCtx, refName, &RecordRSLEntryOptions{MessageTemplate: "{{.Reason}}"}, false)
+		assert.ErrorIs(t, err, ErrInvalidMessageTemplate)
+	})
 }
 
 func TestRecordRSLEntryForReferenceAtTarget(t *testing.T) {
@@ -197,10 +329,10 @@ func TestRecordRSLAnnotation(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = repo.RecordRSLAnnotation([]string{plumbing.ZeroHash.String()}, false, "test annotation", false)
+	err = repo.RecordRSLAnnotation([]string{plumbing.ZeroHash.String()}, false, "test annotation", nil, false)
 	assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
 
-	if err := repo.RecordRSLEntryForReference("refs/heads/main", false); err != nil {
+	if err := repo.RecordRSLEntryForReference(testCtx, "refs/heads/main", false); err != nil {
 		t.Fatal(err)
 	}
 
@@ -210,7 +342,7 @@ func TestRecordRSLAnnotation(t *testing.T) {
 	}
 	entryID := latestEntry.GetID()
 
-	err = repo.RecordRSLAnnotation([]string{entryID.String()}, false, "test annotation", false)
+	err = repo.RecordRSLAnnotation([]string{entryID.String()}, false, "test annotation", nil, false)
 	assert.Nil(t, err)
 
 	latestEntry, err = rsl.GetLatestEntry(repo.r)
@@ -224,7 +356,7 @@ func TestRecordRSLAnnotation(t *testing.T) {
 	assert.Equal(t, []plumbing.Hash{entryID}, annotation.RSLEntryIDs)
 	assert.False(t, annotation.Skip)
 
-	err = repo.RecordRSLAnnotation([]string{entryID.String()}, true, "skip annotation", false)
+	err = repo.RecordRSLAnnotation([]string{entryID.String()}, true, "skip annotation", nil, false)
 	assert.Nil(t, err)
 
 	latestEntry, err = rsl.GetLatestEntry(repo.r)
@@ -237,6 +369,69 @@ func TestRecordRSLAnnotation(t *testing.T) {
 	assert.Equal(t, "skip annotation", annotation.Message)
 	assert.Equal(t, []plumbing.Hash{entryID}, annotation.RSLEntryIDs)
 	assert.True(t, annotation.Skip)
+
+	err = repo.RecordRSLAnnotation([]string{entryID.String()}, false, "ticket annotation", []string{"https://example.com/issues/1"}, false)
+	assert.Nil(t, err)
+
+	latestEntry, err = rsl.GetLatestEntry(repo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotation = latestEntry.(*rsl.AnnotationEntry)
+	assert.Equal(t, []string{"https://example.com/issues/1"}, annotation.Tickets)
+
+	err = repo.RecordRSLAnnotation([]string{entryID.String()}, false, "ticket annotation", []string{"not a ticket"}, false)
+	assert.ErrorIs(t, err, rsl.ErrInvalidTicket)
+}
+
+func TestRecordRSLAnnotationForRange(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &Repository{r: r}
+
+	if err := rsl.InitializeNamespace(repo.r); err != nil {
+		t.Fatal(err)
+	}
+
+	ref := plumbing.NewHashReference(plumbing.ReferenceName("refs/heads/main"), plumbing.ZeroHash)
+
+	if err := repo.r.Storer.SetReference(ref); err != nil {
+		t.Fatal(err)
+	}
+
+	entryIDs := []plumbing.Hash{}
+	for _, targetID := range []plumbing.Hash{plumbing.ZeroHash, gitinterface.EmptyBlob(), gitinterface.EmptyTree()} {
+		if err := rsl.NewReferenceEntry("refs/heads/main", targetID).Commit(repo.r, false); err != nil {
+			t.Fatal(err)
+		}
+
+		latestEntry, err := rsl.GetLatestEntry(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entryIDs = append(entryIDs, latestEntry.GetID())
+	}
+
+	err = repo.RecordRSLAnnotationForRange("main", entryIDs[2].String(), entryIDs[0].String(), true, "skip annotation", nil, false)
+	assert.ErrorIs(t, err, rsl.ErrInvalidAnnotationRange)
+
+	err = repo.RecordRSLAnnotationForRange("main", entryIDs[0].String(), entryIDs[2].String(), true, "skip annotation", nil, false)
+	assert.Nil(t, err)
+
+	latestEntry, err := rsl.GetLatestEntry(repo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.IsType(t, &rsl.AnnotationEntry{}, latestEntry)
+
+	annotation := latestEntry.(*rsl.AnnotationEntry)
+	assert.Equal(t, "skip annotation", annotation.Message)
+	assert.Equal(t, "refs/heads/main", annotation.RangeRefName)
+	assert.Equal(t, entryIDs, annotation.RSLEntryIDs)
+	assert.True(t, annotation.Skip)
 }
 
 func TestCheckRemoteRSLForUpdates(t *testing.T) {
@@ -252,7 +447,7 @@ func TestCheckRemoteRSLForUpdates(t *testing.T) {
 		defer os.RemoveAll(tmpDir) //nolint:errcheck
 
 		// Simulate remote actions
-		remoteR, err := git.PlainInit(tmpDir, false)
+		remoteR, err := gitinterface.PlainInit(tmpDir, false)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -267,13 +462,13 @@ func TestCheckRemoteRSLForUpdates(t *testing.T) {
 		if _, err := gitinterface.Commit(remoteRepo.r, gitinterface.EmptyTree(), refName, "Test commit", false); err != nil {
 			t.Fatal(err)
 		}
-		if err := remoteRepo.RecordRSLEntryForReference(refName, false); err != nil {
+		if err := remoteRepo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
 			t.Fatal(err)
 		}
 
 		// Clone remote repository
 		// TODO: this should be handled by the Repository package
-		localR, err := gitinterface.CloneAndFetchToMemory(context.Background(), tmpDir, refName, []string{rsl.Ref})
+		localR, err := gitinterface.CloneAndFetch(context.Background(), tmpDir, t.TempDir(), refName, []string{rsl.Ref})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -283,7 +478,7 @@ func TestCheckRemoteRSLForUpdates(t *testing.T) {
 		if _, err := gitinterface.Commit(remoteRepo.r, gitinterface.EmptyTree(), refName, "Test commit", false); err != nil {
 			t.Fatal(err)
 		}
-		if err := remoteRepo.RecordRSLEntryForReference(refName, false); err != nil {
+		if err := remoteRepo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
 			t.Fatal(err)
 		}
 
@@ -302,7 +497,7 @@ func TestCheckRemoteRSLForUpdates(t *testing.T) {
 		defer os.RemoveAll(tmpDir) //nolint:errcheck
 
 		// Simulate remote actions
-		remoteR, err := git.PlainInit(tmpDir, false)
+		remoteR, err := gitinterface.PlainInit(tmpDir, false)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -317,13 +512,13 @@ func TestCheckRemoteRSLForUpdates(t *testing.T) {
 		if _, err := gitinterface.Commit(remoteRepo.r, gitinterface.EmptyTree(), refName, "Test commit", false); err != nil {
 			t.Fatal(err)
 		}
-		if err := remoteRepo.RecordRSLEntryForReference(refName, false); err != nil {
+		if err := remoteRepo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
 			t.Fatal(err)
 		}
 
 		// Clone remote repository
 		// TODO: this should be handled by the Repository package
-		localR, err := gitinterface.CloneAndFetchToMemory(context.Background(), tmpDir, refName, []string{rsl.Ref})
+		localR, err := gitinterface.CloneAndFetch(context.Background(), tmpDir, t.TempDir(), refName, []string{rsl.Ref})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -344,7 +539,7 @@ func TestCheckRemoteRSLForUpdates(t *testing.T) {
 		defer os.RemoveAll(tmpDir) //nolint:errcheck
 
 		// Simulate remote actions
-		remoteR, err := git.PlainInit(tmpDir, false)
+		remoteR, err := gitinterface.PlainInit(tmpDir, false)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -359,13 +554,13 @@ func TestCheckRemoteRSLForUpdates(t *testing.T) {
 		if _, err := gitinterface.Commit(remoteRepo.r, gitinterface.EmptyTree(), refName, "Test commit", false); err != nil {
 			t.Fatal(err)
 		}
-		if err := remoteRepo.RecordRSLEntryForReference(refName, false); err != nil {
+		if err := remoteRepo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
 			t.Fatal(err)
 		}
 
 		// Clone remote repository
 		// TODO: this should be handled by the Repository package
-		localR, err := gitinterface.CloneAndFetchToMemory(context.Background(), tmpDir, refName, []string{rsl.Ref})
+		localR, err := gitinterface.CloneAndFetch(context.Background(), tmpDir, t.TempDir(), refName, []string{rsl.Ref})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -375,7 +570,7 @@ func TestCheckRemoteRSLForUpdates(t *testing.T) {
 		if _, err := gitinterface.Commit(localRepo.r, gitinterface.EmptyTree(), refName, "Test commit", false); err != nil {
 			t.Fatal(err)
 		}
-		if err := localRepo.RecordRSLEntryForReference(refName, false); err != nil {
+		if err := localRepo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
 			t.Fatal(err)
 		}
 
@@ -394,7 +589,7 @@ func TestCheckRemoteRSLForUpdates(t *testing.T) {
 		defer os.RemoveAll(tmpDir) //nolint:errcheck
 
 		// Simulate remote actions
-		remoteR, err := git.PlainInit(tmpDir, false)
+		remoteR, err := gitinterface.PlainInit(tmpDir, false)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -409,13 +604,13 @@ func TestCheckRemoteRSLForUpdates(t *testing.T) {
 		if _, err := gitinterface.Commit(remoteRepo.r, gitinterface.EmptyTree(), refName, "Test commit", false); err != nil {
 			t.Fatal(err)
 		}
-		if err := remoteRepo.RecordRSLEntryForReference(refName, false); err != nil {
+		if err := remoteRepo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
 			t.Fatal(err)
 		}
 
 		// Clone remote repository
 		// TODO: this should be handled by the Repository package
-		localR, err := gitinterface.CloneAndFetchToMemory(context.Background(), tmpDir, refName, []string{rsl.Ref})
+		localR, err := gitinterface.CloneAndFetch(context.Background(), tmpDir, t.TempDir(), refName, []string{rsl.Ref})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -425,7 +620,7 @@ func TestCheckRemoteRSLForUpdates(t *testing.T) {
 		if _, err := gitinterface.Commit(remoteRepo.r, gitinterface.EmptyTree(), refName, "Test commit", false); err != nil {
 			t.Fatal(err)
 		}
-		if err := remoteRepo.RecordRSLEntryForReference(refName, false); err != nil {
+		if err := remoteRepo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
 			t.Fatal(err)
 		}
 
@@ -433,7 +628,7 @@ func TestCheckRemoteRSLForUpdates(t *testing.T) {
 		if _, err := gitinterface.Commit(localRepo.r, gitinterface.EmptyTree(), anotherRefName, "Test commit", false); err != nil {
 			t.Fatal(err)
 		}
-		if err := localRepo.RecordRSLEntryForReference(anotherRefName, false); err != nil {
+		if err := localRepo.RecordRSLEntryForReference(testCtx, anotherRefName, false); err != nil {
 			t.Fatal(err)
 		}
 
@@ -444,6 +639,31 @@ func TestCheckRemoteRSLForUpdates(t *testing.T) {
 		assert.True(t, hasUpdates)
 		assert.True(t, hasDiverged)
 	})
+
+	t.Run("remote is empty", func(t *testing.T) {
+		remoteTmpDir := t.TempDir()
+
+		if _, err := gitinterface.PlainInit(remoteTmpDir, true); err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := gitinterface.PlainInit(t.TempDir(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		localRepo := &Repository{r: r}
+		if _, err := localRepo.r.CreateRemote(&config.RemoteConfig{
+			Name: remoteName,
+			URLs: []string{remoteTmpDir},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		hasUpdates, hasDiverged, err := localRepo.CheckRemoteRSLForUpdates(context.Background(), remoteName)
+		assert.Nil(t, err)
+		assert.False(t, hasUpdates)
+		assert.False(t, hasDiverged)
+	})
 }
 
 func TestPushRSL(t *testing.T) {
@@ -452,12 +672,12 @@ func TestPushRSL(t *testing.T) {
 	t.Run("successful push", func(t *testing.T) {
 		remoteTmpDir := t.TempDir()
 
-		remoteRepo, err := git.PlainInit(remoteTmpDir, true)
+		remoteRepo, err := gitinterface.PlainInit(remoteTmpDir, true)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		localRepo := createTestRepositoryWithPolicy(t, "")
+		localRepo := createTestRepositoryWithPolicy(t, t.TempDir())
 		if _, err := localRepo.r.CreateRemote(&config.RemoteConfig{
 			Name: remoteName,
 			URLs: []string{remoteTmpDir},
@@ -478,7 +698,7 @@ func TestPushRSL(t *testing.T) {
 	t.Run("divergent RSLs, unsuccessful push", func(t *testing.T) {
 		remoteTmpDir := t.TempDir()
 
-		remoteRepo, err := git.PlainInit(remoteTmpDir, true)
+		remoteRepo, err := gitinterface.PlainInit(remoteTmpDir, true)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -491,7 +711,7 @@ func TestPushRSL(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		localRepo := createTestRepositoryWithPolicy(t, "")
+		localRepo := createTestRepositoryWithPolicy(t, t.TempDir())
 		if _, err := localRepo.r.CreateRemote(&config.RemoteConfig{
 			Name: remoteName,
 			URLs: []string{remoteTmpDir},
@@ -502,6 +722,40 @@ func TestPushRSL(t *testing.T) {
 		err = localRepo.PushRSL(context.Background(), remoteName)
 		assert.ErrorIs(t, err, ErrPushingRSL)
 	})
+
+	t.Run("empty RSL, nothing to push", func(t *testing.T) {
+		remoteTmpDir := t.TempDir()
+
+		remoteRepo, err := gitinterface.PlainInit(remoteTmpDir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := gitinterface.PlainInit(t.TempDir(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		localRepo := &Repository{r: r}
+		if _, err := localRepo.r.CreateRemote(&config.RemoteConfig{
+			Name: remoteName,
+			URLs: []string{remoteTmpDir},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		err = localRepo.PushRSL(context.Background(), remoteName)
+		assert.Nil(t, err)
+
+		if err := rsl.InitializeNamespace(localRepo.r); err != nil {
+			t.Fatal(err)
+		}
+
+		err = localRepo.PushRSL(context.Background(), remoteName)
+		assert.Nil(t, err)
+
+		_, err = remoteRepo.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
 }
 
 func TestPullRSL(t *testing.T) {
@@ -511,7 +765,7 @@ func TestPullRSL(t *testing.T) {
 		remoteTmpDir := t.TempDir()
 		remoteRepo := createTestRepositoryWithPolicy(t, remoteTmpDir)
 
-		localRepoR, err := git.Init(memory.NewStorage(), memfs.New())
+		localRepoR, err := gitinterface.PlainInit(t.TempDir(), false)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -537,7 +791,7 @@ func TestPullRSL(t *testing.T) {
 		remoteTmpDir := t.TempDir()
 		createTestRepositoryWithPolicy(t, remoteTmpDir)
 
-		localRepoR, err := git.Init(memory.NewStorage(), memfs.New())
+		localRepoR, err := gitinterface.PlainInit(t.TempDir(), false)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -571,7 +825,27 @@ func TestGetRSLEntryLog(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	entries, annotationMap, err := GetRSLEntryLog(r)
+	// Annotate the latest entry twice
+	latestEntry, err := rsl.GetLatestEntry(r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rsl.NewAnnotationEntry([]plumbing.Hash{latestEntry.GetID()}, false, "first").Commit(r.r, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := rsl.NewAnnotationEntry([]plumbing.Hash{latestEntry.GetID()}, true, "second").Commit(r.r, false); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []*rsl.ReferenceEntry{}
+	annotationMap := map[plumbing.Hash][]*rsl.AnnotationEntry{}
+	err = GetRSLEntryLog(r, func(entry *rsl.ReferenceEntry, annotations []*rsl.AnnotationEntry) error {
+		entries = append(entries, entry)
+		if len(annotations) != 0 {
+			annotationMap[entry.ID] = annotations
+		}
+		return nil
+	})
 	assert.Nil(t, err)
 
 	firstEntry, _, err := rsl.GetFirstEntry(r.r)
@@ -584,12 +858,102 @@ func TestGetRSLEntryLog(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	expected, _, err := rsl.GetReferenceEntriesInRange(r.r, firstEntry.GetID(), lastEntry.GetID())
+	expected, expectedAnnotationMap, err := rsl.GetReferenceEntriesInRange(r.r, firstEntry.GetID(), lastEntry.GetID())
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	slices.Reverse(expected)
 	assert.Equal(t, expected, entries)
-	assert.Equal(t, map[plumbing.Hash][]*rsl.AnnotationEntry{}, annotationMap)
+	assert.Equal(t, expectedAnnotationMap, annotationMap)
+	assert.Len(t, annotationMap[latestEntry.GetID()], 2)
+	assert.Equal(t, "first", annotationMap[latestEntry.GetID()][0].Message)
+
+	// Errors returned by the callback stop the walk
+	visited := 0
+	err = GetRSLEntryLog(r, func(_ *rsl.ReferenceEntry, _ []*rsl.AnnotationEntry) error {
+		visited++
+		return errTestStopWalk
+	})
+	assert.ErrorIs(t, err, errTestStopWalk)
+	assert.Equal(t, 1, visited)
 }
+
+func TestQueryRSLEntryLog(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	mainRef := "refs/heads/main"
+	featureRef := "refs/heads/feature"
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r.r, mainRef, 2, gpgKeyBytes)
+	firstMainEntryID := common.CreateTestRSLReferenceEntryCommit(t, r.r, rsl.NewReferenceEntry(mainRef, commitIDs[0]), gpgKeyBytes)
+	secondMainEntryID := common.CreateTestRSLReferenceEntryCommit(t, r.r, rsl.NewReferenceEntry(mainRef, commitIDs[1]), gpgKeyBytes)
+	if err := rsl.NewReferenceEntry(featureRef, commitIDs[1]).Commit(r.r, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := rsl.NewAnnotationEntry([]plumbing.Hash{firstMainEntryID}, true, "skip").Commit(r.r, false); err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queryEntryIDs := func(t *testing.T, opts *RSLEntryLogOptions) []plumbing.Hash {
+		t.Helper()
+
+		entryIDs := []plumbing.Hash{}
+		err := QueryRSLEntryLog(testCtx, r, opts, func(entry *rsl.ReferenceEntry, _ []*rsl.AnnotationEntry) error {
+			entryIDs = append(entryIDs, entry.ID)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return entryIDs
+	}
+
+	t.Run("ref", func(t *testing.T) {
+		entryIDs := queryEntryIDs(t, &RSLEntryLogOptions{Query: rsl.Query{RefNames: []string{"main"}}})
+		assert.Equal(t, []plumbing.Hash{secondMainEntryID, firstMainEntryID}, entryIDs)
+	})
+
+	t.Run("skipped", func(t *testing.T) {
+		entryIDs := queryEntryIDs(t, &RSLEntryLogOptions{Query: rsl.Query{RefNames: []string{mainRef}, Skipped: rsl.SkipFilterSkipped}})
+		assert.Equal(t, []plumbing.Hash{firstMainEntryID}, entryIDs)
+
+		entryIDs = queryEntryIDs(t, &RSLEntryLogOptions{Query: rsl.Query{RefNames: []string{mainRef}, Skipped: rsl.SkipFilterUnskipped}})
+		assert.Equal(t, []plumbing.Hash{secondMainEntryID}, entryIDs)
+	})
+
+	t.Run("signed by", func(t *testing.T) {
+		entryIDs := queryEntryIDs(t, &RSLEntryLogOptions{SignedBy: gpgKey.KeyID})
+		assert.Equal(t, []plumbing.Hash{secondMainEntryID, firstMainEntryID}, entryIDs)
+
+		err := QueryRSLEntryLog(testCtx, r, &RSLEntryLogOptions{SignedBy: "unknown"}, func(_ *rsl.ReferenceEntry, _ []*rsl.AnnotationEntry) error {
+			return nil
+		})
+		assert.ErrorIs(t, err, ErrKeyNotFoundInPolicy)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		allEntryIDs := queryEntryIDs(t, &RSLEntryLogOptions{})
+
+		entryIDs := queryEntryIDs(t, &RSLEntryLogOptions{Limit: 2})
+		assert.Equal(t, allEntryIDs[:2], entryIDs)
+
+		entryIDs = queryEntryIDs(t, &RSLEntryLogOptions{Offset: 2, Limit: 2})
+		assert.Equal(t, allEntryIDs[2:4], entryIDs)
+
+		entryIDs = queryEntryIDs(t, &RSLEntryLogOptions{Offset: len(allEntryIDs)})
+		assert.Empty(t, entryIDs)
+
+		err := QueryRSLEntryLog(testCtx, r, &RSLEntryLogOptions{Limit: -1}, func(_ *rsl.ReferenceEntry, _ []*rsl.AnnotationEntry) error {
+			return nil
+		})
+		assert.ErrorIs(t, err, ErrInvalidRSLLogPagination)
+	})
+}
+
+var errTestStopWalk = errors.New("stop walk")
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/gittuf/gittuf/internal/progress"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// SyncRSLBackend reconciles the backend with the RSL, appending the entries
+// the backend is missing. The backend's existing records must match the
+// entries at the start of the RSL, otherwise either the backend or the RSL
+// has been tampered with, or the local RSL is behind the backend, and
+// rsl.ErrBackendDiverged is returned. The number of records appended is
+// returned.
+func (r *Repository) SyncRSLBackend(ctx context.Context, backend rsl.Backend) (int, error) {
+	slog.Debug("Loading RSL entries...")
+	entryIDs, err := r.getRSLEntryIDs()
+	if err != nil {
+		if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return 0, err
+		}
+		entryIDs = []plumbing.Hash{}
+	}
+
+	slog.Debug("Loading RSL backend records...")
+	records, err := backend.Records(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(records) > len(entryIDs) {
+		return 0, fmt.Errorf("%w: backend has %d records but the RSL has %d entries, the RSL may need to be pulled", rsl.ErrBackendDiverged, len(records), len(entryIDs))
+	}
+
+	slog.Debug("Checking RSL backend records match the RSL...")
+	progress.Stage("checking RSL backend records", len(records))
+	for i, record := range records {
+		expectedRecord, err := rsl.NewBackendRecord(r.r, uint64(i)+1, entryIDs[i])
+		if err != nil {
+			return 0, err
+		}
+
+		if *record != *expectedRecord {
+			return 0, fmt.Errorf("%w: record %d does not match RSL entry '%s'", rsl.ErrBackendDiverged, record.Number, entryIDs[i].String())
+		}
+		progress.Advance()
+	}
+
+	newRecords := make([]*rsl.BackendRecord, 0, len(entryIDs)-len(records))
+	for i := len(records); i < len(entryIDs); i++ {
+		record, err := rsl.NewBackendRecord(r.r, uint64(i)+1, entryIDs[i])
+		if err != nil {
+			return 0, err
+		}
+		newRecords = append(newRecords, record)
+	}
+
+	if len(newRecords) == 0 {
+		slog.Debug("RSL backend is up to date")
+		return 0, nil
+	}
+
+	slog.Debug(fmt.Sprintf("Appending %d records to RSL backend...", len(newRecords)))
+	progress.Stage("appending RSL backend records", 0)
+	if err := backend.Append(ctx, newRecords); err != nil {
+		return 0, err
+	}
+
+	return len(newRecords), nil
+}
+
+// MirrorRSL mirrors the RSL into the backend set in the repository's gittuf
+// configuration, if any. As the RSL itself has been updated, failing to mirror
+// it is logged rather than returned, and the backend can be reconciled later
+// using SyncRSLBackend.
+func (r *Repository) MirrorRSL(ctx context.Context) {
+	config, err := LoadConfig()
+	if err != nil {
+		slog.Warn(fmt.Sprintf("Unable to load configuration to mirror RSL: %s", err.Error()))
+		return
+	}
+	if config.RSLBackend == "" {
+		return
+	}
+
+	backend, err := rsl.NewBackend(config.RSLBackend)
+	if err == nil {
+		_, err = r.SyncRSLBackend(ctx, backend)
+	}
+	if err != nil {
+		slog.Warn(fmt.Sprintf("Unable to mirror RSL into backend '%s': %s", config.RSLBackend, err.Error()))
+	}
+}
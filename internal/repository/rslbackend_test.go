@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncRSLBackend(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+	backend := &rsl.FileBackend{Path: filepath.Join(t.TempDir(), "rsl.jsonl")}
+
+	entryIDs, err := r.getRSLEntryIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appended, err := r.SyncRSLBackend(testCtx, backend)
+	assert.Nil(t, err)
+	assert.Equal(t, len(entryIDs), appended)
+
+	t.Run("backend up to date", func(t *testing.T) {
+		appended, err := r.SyncRSLBackend(testCtx, backend)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, appended)
+	})
+
+	t.Run("new entries", func(t *testing.T) {
+		refName := "refs/heads/main"
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r.r, refName, 2, gpgKeyBytes)
+		for _, commitID := range commitIDs {
+			if err := rsl.NewReferenceEntry(refName, commitID).Commit(r.r, false); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		appended, err := r.SyncRSLBackend(testCtx, backend)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, appended)
+
+		records, err := backend.Records(testCtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		latestEntry, err := rsl.GetLatestEntry(r.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, latestEntry.GetID().String(), records[len(records)-1].EntryID)
+	})
+
+	t.Run("backend diverged", func(t *testing.T) {
+		divergedBackend := &rsl.FileBackend{Path: filepath.Join(t.TempDir(), "rsl.jsonl")}
+		records, err := backend.Records(testCtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records[0].Message = "tampered"
+		if err := divergedBackend.Append(testCtx, records); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = r.SyncRSLBackend(testCtx, divergedBackend)
+		assert.ErrorIs(t, err, rsl.ErrBackendDiverged)
+	})
+
+	t.Run("backend ahead of RSL", func(t *testing.T) {
+		aheadBackend := &rsl.FileBackend{Path: filepath.Join(t.TempDir(), "rsl.jsonl")}
+		records, err := backend.Records(testCtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		extraRecord := *records[len(records)-1]
+		extraRecord.Number++
+		records = append(records, &extraRecord)
+		if err := aheadBackend.Append(testCtx, records); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = r.SyncRSLBackend(testCtx, aheadBackend)
+		assert.ErrorIs(t, err, rsl.ErrBackendDiverged)
+	})
+}
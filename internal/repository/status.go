@@ -0,0 +1,339 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// Status summarizes the trust state of the repository.
+type Status struct {
+	// RSL describes the local RSL and how it compares to the RSLs last
+	// fetched from the repository's remotes.
+	RSL *RSLStatus
+
+	// CheckedOutRef describes the branch HEAD points to. It is nil if HEAD is
+	// detached or the branch has no commits yet.
+	CheckedOutRef *RefStatus
+
+	// Policy describes the current policy. It is nil if no policy has been
+	// applied.
+	Policy *PolicyStatus
+}
+
+// RSLStatus describes the local RSL.
+type RSLStatus struct {
+	// LatestEntryID is the ID of the latest entry in the local RSL. It is zero
+	// if the RSL has no entries.
+	LatestEntryID plumbing.Hash
+
+	// Remotes describes the RSL of each remote, sorted by name.
+	Remotes []*RemoteRSLStatus
+}
+
+// RemoteRSLStatus compares the local RSL with the remote's RSL as of the last
+// fetch, using the remote's RSL tracker ref. If LocalAhead and RemoteAhead are
+// both set, the RSLs have diverged.
+type RemoteRSLStatus struct {
+	// Name is the name of the remote.
+	Name string
+
+	// TrackerEntryID is the ID of the latest entry in the remote's RSL tracker.
+	// It is zero if the remote's RSL has not been fetched.
+	TrackerEntryID plumbing.Hash
+
+	// LocalAhead is set if the local RSL has entries not in the remote RSL.
+	LocalAhead bool
+
+	// RemoteAhead is set if the remote RSL has entries not in the local RSL.
+	RemoteAhead bool
+}
+
+// RefStatus describes a ref's state in relation to the RSL and the current
+// policy.
+type RefStatus struct {
+	// Name is the absolute name of the ref.
+	Name string
+
+	// TipID is the ID of the commit the ref points to.
+	TipID plumbing.Hash
+
+	// LatestEntryTargetID is the target of the latest unskipped RSL entry for
+	// the ref. It is zero if the ref has not been recorded in the RSL.
+	LatestEntryTargetID plumbing.Hash
+
+	// SigningKeyID is the ID of the signing key in the user's Git config. It
+	// is empty if the key cannot be identified.
+	SigningKeyID string
+
+	// SigningKeyErr is set if the current policy does not trust the signing
+	// key for the ref.
+	SigningKeyErr error
+
+	// PendingAuthorization describes the reference authorization for the
+	// changes to the ref not yet recorded in the RSL. It is nil if the ref is
+	// recorded or no such authorization exists.
+	PendingAuthorization *PendingAuthorizationStatus
+}
+
+// IsRecorded returns true if the ref's tip matches its latest RSL entry.
+func (s *RefStatus) IsRecorded() bool {
+	return s.TipID == s.LatestEntryTargetID
+}
+
+// PendingAuthorizationStatus describes a reference authorization for changes
+// not yet recorded in the RSL.
+type PendingAuthorizationStatus struct {
+	// FromID is the ref's target in its latest RSL entry.
+	FromID plumbing.Hash
+
+	// TargetTreeID is the tree of the ref's tip.
+	TargetTreeID plumbing.Hash
+
+	// SignerKeyIDs contains the IDs of the keys that have signed the
+	// authorization.
+	SignerKeyIDs []string
+
+	// SignedBySigningKey is set if the signing key in the user's Git config
+	// has signed the authorization.
+	SignedBySigningKey bool
+}
+
+// PolicyStatus describes the current policy.
+type PolicyStatus struct {
+	// Metadata contains the expiry of each policy metadata file, starting
+	// with the root and top level targets metadata, followed by delegated
+	// metadata sorted by role name.
+	Metadata []*MetadataStatus
+}
+
+// MetadataStatus records when a policy metadata file expires.
+type MetadataStatus struct {
+	RoleName string
+	Expires  time.Time
+}
+
+// GetStatus aggregates the trust state of the repository: the local RSL
+// compared with its remote trackers, whether the checked out branch is
+// recorded in the RSL and if the configured signing key is trusted for it,
+// when the policy metadata expires, and reference authorizations for changes
+// not yet recorded. It does not contact remotes, so remote RSL trackers are
+// compared as of the last fetch.
+func (r *Repository) GetStatus(ctx context.Context) (*Status, error) {
+	rslStatus, err := r.getRSLStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	refStatus, err := r.getCheckedOutRefStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policyStatus, err := r.getPolicyStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Status{
+		RSL:           rslStatus,
+		CheckedOutRef: refStatus,
+		Policy:        policyStatus,
+	}, nil
+}
+
+func (r *Repository) getRSLStatus() (*RSLStatus, error) {
+	slog.Debug("Loading local RSL tip...")
+	localTip, err := r.getRefTip(rsl.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	remotes, err := r.r.Remotes()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &RSLStatus{LatestEntryID: localTip}
+	for _, remote := range remotes {
+		remoteName := remote.Config().Name
+
+		slog.Debug(fmt.Sprintf("Comparing local RSL with RSL tracker for '%s'...", remoteName))
+		remoteTip, err := r.getRefTip(rsl.RemoteTrackerRef(remoteName))
+		if err != nil {
+			return nil, err
+		}
+
+		remoteStatus := &RemoteRSLStatus{Name: remoteName, TrackerEntryID: remoteTip}
+		if !remoteTip.IsZero() && remoteTip != localTip {
+			remoteStatus.LocalAhead, err = r.hasCommitsNotIn(localTip, remoteTip)
+			if err != nil {
+				return nil, err
+			}
+			remoteStatus.RemoteAhead, err = r.hasCommitsNotIn(remoteTip, localTip)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		status.Remotes = append(status.Remotes, remoteStatus)
+	}
+
+	slices.SortFunc(status.Remotes, func(a, b *RemoteRSLStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return status, nil
+}
+
+func (r *Repository) getCheckedOutRefStatus(ctx context.Context) (*RefStatus, error) {
+	head, err := r.r.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return nil, err
+	}
+	if head.Type() != plumbing.SymbolicReference {
+		slog.Debug("HEAD is detached, skipping checked out ref status...")
+		return nil, nil
+	}
+
+	refName := head.Target().String()
+	tipID, err := r.getRefTip(refName)
+	if err != nil {
+		return nil, err
+	}
+	if tipID.IsZero() {
+		slog.Debug(fmt.Sprintf("'%s' has no commits yet, skipping checked out ref status...", refName))
+		return nil, nil
+	}
+
+	status := &RefStatus{Name: refName, TipID: tipID}
+
+	slog.Debug(fmt.Sprintf("Loading latest RSL entry for '%s'...", refName))
+	entry, _, err := rsl.GetLatestUnskippedReferenceEntryForRef(r.r, refName)
+	if err == nil {
+		status.LatestEntryTargetID = entry.TargetID
+	} else if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return nil, err
+	}
+
+	slog.Debug("Checking if configured signing key is trusted for ref...")
+	status.SigningKeyID, err = configuredSigningKeyID()
+	if err != nil {
+		return nil, err
+	}
+	if status.SigningKeyID != "" {
+		if err := r.checkKeyIDForRef(ctx, refName, status.SigningKeyID); err != nil {
+			if !errors.Is(err, ErrSigningKeyNotTrustedForRef) {
+				return nil, err
+			}
+			status.SigningKeyErr = err
+		}
+	}
+
+	if !status.IsRecorded() {
+		status.PendingAuthorization, err = r.getPendingAuthorizationStatus(status)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return status, nil
+}
+
+func (r *Repository) getPendingAuthorizationStatus(refStatus *RefStatus) (*PendingAuthorizationStatus, error) {
+	tipCommit, err := gitinterface.GetCommit(r.r, refStatus.TipID)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Loading current set of attestations...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := allAttestations.GetReferenceAuthorizationFor(r.r, refStatus.Name, refStatus.LatestEntryTargetID.String(), tipCommit.TreeHash.String())
+	if err != nil {
+		if errors.Is(err, attestations.ErrAuthorizationNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	status := &PendingAuthorizationStatus{
+		FromID:       refStatus.LatestEntryTargetID,
+		TargetTreeID: tipCommit.TreeHash,
+		SignerKeyIDs: make([]string, 0, len(env.Signatures)),
+	}
+	for _, signature := range env.Signatures {
+		status.SignerKeyIDs = append(status.SignerKeyIDs, signature.KeyID)
+
+		// GPG keys may be configured using their key ID rather than their
+		// fingerprint
+		if refStatus.SigningKeyID != "" && strings.HasSuffix(signature.KeyID, refStatus.SigningKeyID) {
+			status.SignedBySigningKey = true
+		}
+	}
+
+	return status, nil
+}
+
+func (r *Repository) getPolicyStatus(ctx context.Context) (*PolicyStatus, error) {
+	metadata, err := r.CheckPolicyExpiration(ctx)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &PolicyStatus{Metadata: metadata}, nil
+}
+
+// getRefTip returns the commit the ref points to, or the zero hash if the ref
+// does not exist.
+func (r *Repository) getRefTip(refName string) (plumbing.Hash, error) {
+	ref, err := r.r.Reference(plumbing.ReferenceName(refName), true)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return plumbing.ZeroHash, nil
+		}
+		return plumbing.ZeroHash, err
+	}
+
+	return ref.Hash(), nil
+}
+
+// hasCommitsNotIn returns true if the commit has ancestors, including itself,
+// that are not reachable from otherID.
+func (r *Repository) hasCommitsNotIn(commitID, otherID plumbing.Hash) (bool, error) {
+	if commitID.IsZero() {
+		return false, nil
+	}
+	if otherID.IsZero() {
+		return true, nil
+	}
+
+	commit, err := gitinterface.GetCommit(r.r, commitID)
+	if err != nil {
+		return false, err
+	}
+
+	knows, err := gitinterface.KnowsCommit(r.r, otherID, commit)
+	if err != nil {
+		return false, err
+	}
+
+	return !knows, nil
+}
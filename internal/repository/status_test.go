@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStatus(t *testing.T) {
+	// Ensure no signing key is picked up from the user's Git config
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	t.Run("no policy", func(t *testing.T) {
+		r, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		repo := &Repository{r: r}
+		if err := repo.InitializeNamespaces(); err != nil {
+			t.Fatal(err)
+		}
+
+		status, err := repo.GetStatus(testCtx)
+		assert.Nil(t, err)
+		assert.Nil(t, status.Policy)
+		assert.Nil(t, status.CheckedOutRef)
+		assert.Empty(t, status.RSL.Remotes)
+	})
+
+	repo := createTestRepositoryWithPolicy(t, "")
+	refName := "refs/heads/main"
+
+	if err := repo.r.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.ReferenceName(refName))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.r.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"https://example.com/repo.git"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 2, gpgKeyBytes)
+
+	t.Run("unrecorded ref and unfetched remote", func(t *testing.T) {
+		status, err := repo.GetStatus(testCtx)
+		assert.Nil(t, err)
+
+		latestEntry, err := rsl.GetLatestEntry(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, latestEntry.GetID(), status.RSL.LatestEntryID)
+		assert.Equal(t, []*RemoteRSLStatus{{Name: "origin"}}, status.RSL.Remotes)
+
+		assert.Equal(t, refName, status.CheckedOutRef.Name)
+		assert.Equal(t, commitIDs[1], status.CheckedOutRef.TipID)
+		assert.True(t, status.CheckedOutRef.LatestEntryTargetID.IsZero())
+		assert.False(t, status.CheckedOutRef.IsRecorded())
+		assert.Empty(t, status.CheckedOutRef.SigningKeyID)
+		assert.Nil(t, status.CheckedOutRef.SigningKeyErr)
+		assert.Nil(t, status.CheckedOutRef.PendingAuthorization)
+
+		roleNames := []string{}
+		for _, metadata := range status.Policy.Metadata {
+			roleNames = append(roleNames, metadata.RoleName)
+			assert.False(t, metadata.Expires.IsZero())
+		}
+		assert.Equal(t, []string{policy.RootRoleName, policy.TargetsRoleName}, roleNames)
+	})
+
+	// Record the first commit, and have the remote tracker point to the entry
+	trackerEntryID := common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(rsl.RemoteTrackerRef("origin")), trackerEntryID)); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyID, err := signer.KeyID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tipCommit, err := gitinterface.GetCommit(repo.r, commitIDs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddReferenceAuthorizationForIDs(testCtx, signer, refName, commitIDs[0].String(), tipCommit.TreeHash.String(), false); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("pending authorization and local RSL ahead", func(t *testing.T) {
+		status, err := repo.GetStatus(testCtx)
+		assert.Nil(t, err)
+
+		assert.Equal(t, []*RemoteRSLStatus{{Name: "origin", TrackerEntryID: trackerEntryID, LocalAhead: true}}, status.RSL.Remotes)
+
+		assert.Equal(t, commitIDs[0], status.CheckedOutRef.LatestEntryTargetID)
+		assert.False(t, status.CheckedOutRef.IsRecorded())
+		assert.Equal(t, &PendingAuthorizationStatus{
+			FromID:       commitIDs[0],
+			TargetTreeID: tipCommit.TreeHash,
+			SignerKeyIDs: []string{keyID},
+		}, status.CheckedOutRef.PendingAuthorization)
+	})
+
+	t.Run("recorded ref and remote RSL ahead", func(t *testing.T) {
+		latestEntryID := common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, commitIDs[1]), gpgKeyBytes)
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(rsl.RemoteTrackerRef("origin")), latestEntryID)); err != nil {
+			t.Fatal(err)
+		}
+		// Roll back the local RSL to the previous entry
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(rsl.Ref), trackerEntryID)); err != nil {
+			t.Fatal(err)
+		}
+
+		status, err := repo.GetStatus(testCtx)
+		assert.Nil(t, err)
+
+		assert.Equal(t, []*RemoteRSLStatus{{Name: "origin", TrackerEntryID: latestEntryID, RemoteAhead: true}}, status.RSL.Remotes)
+
+		// The local RSL doesn't record the tip anymore
+		assert.False(t, status.CheckedOutRef.IsRecorded())
+
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(rsl.Ref), latestEntryID)); err != nil {
+			t.Fatal(err)
+		}
+
+		status, err = repo.GetStatus(testCtx)
+		assert.Nil(t, err)
+
+		assert.Equal(t, []*RemoteRSLStatus{{Name: "origin", TrackerEntryID: latestEntryID}}, status.RSL.Remotes)
+		assert.True(t, status.CheckedOutRef.IsRecorded())
+		assert.Nil(t, status.CheckedOutRef.PendingAuthorization)
+	})
+}
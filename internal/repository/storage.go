@@ -0,0 +1,313 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/revlist"
+)
+
+const (
+	StorageCategoryRSL            = "rsl"
+	StorageCategoryPolicy         = "policy"
+	StorageCategoryAttestations   = "attestations"
+	StorageCategoryRemoteTrackers = "remote-trackers"
+	StorageCategoryOther          = "other"
+
+	gittufRefPrefix = "refs/gittuf/"
+)
+
+var ErrInvalidStoragePeriod = errors.New("storage growth period must be positive")
+
+// StorageUsage records the objects reachable from a category of gittuf refs.
+// Sizes are the uncompressed sizes of the objects, as Git's compression
+// depends on how the objects are packed.
+type StorageUsage struct {
+	// Category is one of StorageCategoryRSL, StorageCategoryPolicy,
+	// StorageCategoryAttestations, StorageCategoryRemoteTrackers, or
+	// StorageCategoryOther. It is empty for the total across all categories.
+	Category string
+
+	// Refs are the refs in the category.
+	Refs []string
+
+	// Commits is the number of commits reachable from the refs.
+	Commits int
+
+	// Objects is the number of commits, trees, and blobs reachable from the
+	// refs.
+	Objects int
+
+	// Size is the total size of the objects in bytes.
+	Size int64
+}
+
+// StorageGrowth records how much the gittuf metadata grew in a period, based
+// on the RSL entries recorded in the period.
+type StorageGrowth struct {
+	// Start is the start of the period.
+	Start time.Time
+
+	// Entries is the number of RSL entries recorded in the period.
+	Entries int
+
+	// Size is the total size in bytes of the RSL entries and the policy and
+	// attestations states they record that are new in the period.
+	Size int64
+}
+
+// StorageStats reports how much of the repository's size is attributable to
+// gittuf metadata.
+type StorageStats struct {
+	// Usage records the objects reachable from each category of refs. An
+	// object reachable from several categories is counted in each of them.
+	Usage []*StorageUsage
+
+	// Total records the objects reachable from any gittuf ref, counting each
+	// object once.
+	Total *StorageUsage
+
+	// Growth records the growth of the metadata in each period since the
+	// first RSL entry, starting with the earliest period.
+	Growth []*StorageGrowth
+}
+
+// GetStorageStats reports the number and size of the objects reachable from
+// gittuf's refs, including the refs tracking remotes' metadata, and how the
+// metadata grew in each period of the specified duration.
+func (r *Repository) GetStorageStats(period time.Duration) (*StorageStats, error) {
+	if period <= 0 {
+		return nil, ErrInvalidStoragePeriod
+	}
+
+	slog.Debug("Identifying gittuf refs...")
+	categoryRefs, err := r.gittufRefsByCategory()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &StorageStats{Usage: []*StorageUsage{}}
+	allTips := []plumbing.Hash{}
+	for _, category := range []string{StorageCategoryRSL, StorageCategoryPolicy, StorageCategoryAttestations, StorageCategoryRemoteTrackers, StorageCategoryOther} {
+		refNames := []string{}
+		tips := []plumbing.Hash{}
+		for refName, tip := range categoryRefs[category] {
+			refNames = append(refNames, refName)
+			tips = append(tips, tip)
+		}
+		sort.Strings(refNames)
+		allTips = append(allTips, tips...)
+
+		slog.Debug(fmt.Sprintf("Measuring objects in category '%s'...", category))
+		usage, err := r.measureObjects(tips)
+		if err != nil {
+			return nil, err
+		}
+		usage.Category = category
+		usage.Refs = refNames
+		stats.Usage = append(stats.Usage, usage)
+	}
+
+	stats.Total, err = r.measureObjects(allTips)
+	if err != nil {
+		return nil, err
+	}
+	stats.Total.Refs = []string{}
+
+	slog.Debug("Measuring growth of RSL...")
+	stats.Growth, err = r.measureGrowth(period)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// gittufRefsByCategory returns the tips of the refs in the gittuf namespace and
+// the remote tracker refs, grouped by category.
+func (r *Repository) gittufRefsByCategory() (map[string]map[string]plumbing.Hash, error) {
+	refs, err := r.r.Storer.IterReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	categoryRefs := map[string]map[string]plumbing.Hash{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference || ref.Hash().IsZero() {
+			return nil
+		}
+
+		refName := ref.Name().String()
+		var category string
+		switch {
+		case refName == rsl.Ref || rsl.IsShardRef(refName):
+			category = StorageCategoryRSL
+		case refName == policy.PolicyRef || refName == policy.PolicyStagingRef:
+			category = StorageCategoryPolicy
+		case refName == attestations.Ref:
+			category = StorageCategoryAttestations
+		case strings.HasPrefix(refName, gitinterface.RemoteRefPrefix) && strings.Contains(refName, remoteTrackerInfix):
+			category = StorageCategoryRemoteTrackers
+		case strings.HasPrefix(refName, gittufRefPrefix):
+			category = StorageCategoryOther
+		default:
+			return nil
+		}
+
+		if _, has := categoryRefs[category]; !has {
+			categoryRefs[category] = map[string]plumbing.Hash{}
+		}
+		categoryRefs[category][refName] = ref.Hash()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return categoryRefs, nil
+}
+
+// measureObjects counts and sizes the objects reachable from the tips.
+func (r *Repository) measureObjects(tips []plumbing.Hash) (*StorageUsage, error) {
+	usage := &StorageUsage{}
+	if len(tips) == 0 {
+		return usage, nil
+	}
+
+	objectIDs, err := revlist.Objects(r.r.Storer, tips, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, objectID := range objectIDs {
+		obj, err := r.r.Storer.EncodedObject(plumbing.AnyObject, objectID)
+		if err != nil {
+			return nil, err
+		}
+
+		usage.Objects++
+		usage.Size += obj.Size()
+		if obj.Type() == plumbing.CommitObject {
+			usage.Commits++
+		}
+	}
+
+	return usage, nil
+}
+
+// measureGrowth walks the RSL from its first entry, attributing each entry and
+// the new objects of the gittuf states it records to the period the entry was
+// recorded in. Periods without entries are omitted.
+func (r *Repository) measureGrowth(period time.Duration) ([]*StorageGrowth, error) {
+	latestEntry, err := rsl.GetLatestEntry(r.r)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return []*StorageGrowth{}, nil
+		}
+		return nil, err
+	}
+
+	entries := []rsl.Entry{latestEntry}
+	for {
+		parentEntry, err := rsl.GetParentForEntry(r.r, entries[len(entries)-1])
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				break
+			}
+			return nil, err
+		}
+		entries = append(entries, parentEntry)
+	}
+
+	growth := []*StorageGrowth{}
+	seen := map[plumbing.Hash]bool{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+
+		recordedAt, err := rsl.GetEntryTime(r.r, entry.GetID())
+		if err != nil {
+			return nil, err
+		}
+		start := recordedAt.UTC().Truncate(period)
+
+		if len(growth) == 0 || !growth[len(growth)-1].Start.Equal(start) {
+			growth = append(growth, &StorageGrowth{Start: start})
+		}
+		current := growth[len(growth)-1]
+		current.Entries++
+
+		tips := []plumbing.Hash{entry.GetID()}
+		if referenceEntry, isReferenceEntry := entry.(*rsl.ReferenceEntry); isReferenceEntry && strings.HasPrefix(referenceEntry.RefName, gittufRefPrefix) && !referenceEntry.TargetID.IsZero() {
+			tips = append(tips, referenceEntry.TargetID)
+		}
+
+		size, err := r.measureNewObjects(tips, seen)
+		if err != nil {
+			return nil, err
+		}
+		current.Size += size
+	}
+
+	return growth, nil
+}
+
+// measureNewObjects returns the total size of the objects reachable from the
+// tips that have not been seen, marking them as seen. Objects that are missing
+// are ignored, as gittuf states recorded in the RSL may not have been fetched.
+func (r *Repository) measureNewObjects(tips []plumbing.Hash, seen map[plumbing.Hash]bool) (int64, error) {
+	var size int64
+
+	queue := append([]plumbing.Hash{}, tips...)
+	for len(queue) > 0 {
+		objectID := queue[0]
+		queue = queue[1:]
+		if seen[objectID] {
+			continue
+		}
+		seen[objectID] = true
+
+		encodedObj, err := r.r.Storer.EncodedObject(plumbing.AnyObject, objectID)
+		if err != nil {
+			if errors.Is(err, plumbing.ErrObjectNotFound) {
+				continue
+			}
+			return -1, err
+		}
+		size += encodedObj.Size()
+
+		obj, err := object.DecodeObject(r.r.Storer, encodedObj)
+		if err != nil {
+			return -1, err
+		}
+
+		switch obj := obj.(type) {
+		case *object.Commit:
+			queue = append(queue, obj.TreeHash)
+			queue = append(queue, obj.ParentHashes...)
+		case *object.Tree:
+			for _, treeEntry := range obj.Entries {
+				if treeEntry.Mode == filemode.Submodule {
+					continue
+				}
+				queue = append(queue, treeEntry.Hash)
+			}
+		case *object.Tag:
+			queue = append(queue, obj.Target)
+		}
+	}
+
+	return size, nil
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStorageStats(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	_, err := r.GetStorageStats(0)
+	assert.ErrorIs(t, err, ErrInvalidStoragePeriod)
+
+	rslEntries := 0
+	latestEntry, err := rsl.GetLatestEntry(r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for entry := latestEntry; ; rslEntries++ {
+		entry, err = rsl.GetParentForEntry(r.r, entry)
+		if err != nil {
+			rslEntries++
+			break
+		}
+	}
+
+	stats, err := r.GetStorageStats(24 * time.Hour)
+	assert.Nil(t, err)
+
+	usage := map[string]*StorageUsage{}
+	for _, categoryUsage := range stats.Usage {
+		usage[categoryUsage.Category] = categoryUsage
+	}
+
+	assert.Equal(t, []string{rsl.Ref}, usage[StorageCategoryRSL].Refs)
+	assert.Equal(t, rslEntries, usage[StorageCategoryRSL].Commits)
+	assert.Greater(t, usage[StorageCategoryRSL].Size, int64(0))
+
+	assert.Equal(t, []string{policy.PolicyRef, policy.PolicyStagingRef}, usage[StorageCategoryPolicy].Refs)
+	assert.Greater(t, usage[StorageCategoryPolicy].Commits, 0)
+	assert.Greater(t, usage[StorageCategoryPolicy].Objects, usage[StorageCategoryPolicy].Commits)
+
+	assert.Equal(t, []string{attestations.Ref}, usage[StorageCategoryAttestations].Refs)
+	assert.Greater(t, usage[StorageCategoryAttestations].Objects, 0)
+
+	assert.Empty(t, usage[StorageCategoryRemoteTrackers].Refs)
+	assert.Equal(t, 0, usage[StorageCategoryRemoteTrackers].Objects)
+
+	// Objects shared between categories, such as the states of the policy
+	// and policy staging refs, are counted once in the total
+	categoryObjects := 0
+	for _, categoryUsage := range stats.Usage {
+		categoryObjects += categoryUsage.Objects
+	}
+	assert.Less(t, stats.Total.Objects, categoryObjects)
+
+	totalEntries := 0
+	var totalGrowth int64
+	for _, growth := range stats.Growth {
+		totalEntries += growth.Entries
+		totalGrowth += growth.Size
+	}
+	assert.Equal(t, rslEntries, totalEntries)
+	assert.Greater(t, totalGrowth, usage[StorageCategoryRSL].Size)
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sort"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var (
+	ErrSubmodulesNotRecorded = errors.New("latest RSL entry for ref does not record submodules, record it using --with-submodules")
+	ErrSubmoduleNotVerified  = errors.New("submodule commit is not verified in the submodule's repository")
+)
+
+// VerifySubmodules checks that the submodule commits recorded in the latest
+// RSL entry for the target ref are verified in the submodules' own
+// repositories. A submodule commit is verified if it was recorded in the
+// submodule's RSL for a ref that passes verification with the submodule's
+// policy. The submodules must be checked out in the repository's worktree.
+func (r *Repository) VerifySubmodules(ctx context.Context, target string) error {
+	slog.Debug("Identifying absolute reference path...")
+	target, err := gitinterface.AbsoluteReference(r.r, target)
+	if err != nil {
+		return err
+	}
+
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, target)
+	if err != nil {
+		return err
+	}
+	if entry.Submodules == nil {
+		return fmt.Errorf("%w: '%s'", ErrSubmodulesNotRecorded, target)
+	}
+
+	worktree, err := r.r.Worktree()
+	if err != nil {
+		return fmt.Errorf("unable to locate submodules: %w", err)
+	}
+
+	paths := make([]string, 0, len(entry.Submodules))
+	for path := range entry.Submodules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		slog.Debug(fmt.Sprintf("Verifying submodule '%s' at '%s'...", path, entry.Submodules[path].String()))
+		if err := verifySubmoduleCommit(ctx, filepath.Join(worktree.Filesystem.Root(), path), entry.Submodules[path]); err != nil {
+			return fmt.Errorf("submodule '%s': %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// verifySubmoduleCommit checks that the commit is verified in the repository at
+// the specified path.
+func verifySubmoduleCommit(ctx context.Context, path string, commitID plumbing.Hash) error {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return fmt.Errorf("unable to open submodule, check that it is checked out: %w", err)
+	}
+
+	commit, err := gitinterface.GetCommit(repo, commitID)
+	if err != nil {
+		return fmt.Errorf("%w: commit '%s' not found", ErrSubmoduleNotVerified, commitID.String())
+	}
+
+	entry, _, err := rsl.GetFirstReferenceEntryForCommit(repo, commit)
+	if err != nil {
+		if errors.Is(err, rsl.ErrNoRecordOfCommit) {
+			return fmt.Errorf("%w: commit '%s' has not been recorded in the RSL", ErrSubmoduleNotVerified, commitID.String())
+		}
+		return err
+	}
+
+	if _, err := policy.VerifyRefFull(ctx, repo, entry.RefName); err != nil {
+		return fmt.Errorf("%w: '%s': %w", ErrSubmoduleNotVerified, entry.RefName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySubmodules(t *testing.T) {
+	refName := "refs/heads/main"
+
+	appPath := t.TempDir()
+	appRepo, err := gitinterface.PlainInit(appPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rsl.InitializeNamespace(appRepo); err != nil {
+		t.Fatal(err)
+	}
+	app := &Repository{r: appRepo}
+
+	// The submodule is checked out in the superproject's worktree
+	lib := createTestRepositoryWithPolicy(t, filepath.Join(appPath, "lib"))
+	libCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, lib.r, refName, 1, gpgKeyBytes)
+	common.CreateTestRSLReferenceEntryCommit(t, lib.r, rsl.NewReferenceEntry(refName, libCommitIDs[0]), gpgKeyBytes)
+
+	// The feature branch is not recorded in the RSL, its first commit is
+	// identical to the one on main
+	featureCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, lib.r, "refs/heads/feature", 2, gpgKeyBytes)
+	unrecordedCommitID := featureCommitIDs[1]
+
+	// recordAppState records a commit in app with lib as a submodule at
+	// submoduleID
+	recordAppState := func(submoduleID plumbing.Hash) {
+		t.Helper()
+
+		treeID, err := gitinterface.WriteTree(app.r, []object.TreeEntry{{Name: "lib", Mode: filemode.Submodule, Hash: submoduleID}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := app.r.Reference(plumbing.ReferenceName(refName), true); err != nil {
+			if err := app.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := gitinterface.Commit(app.r, treeID, refName, "Update lib", false); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := app.RecordRSLEntryForReferenceWithOptions(testCtx, refName, &RecordRSLEntryOptions{Submodules: true}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("submodules not recorded", func(t *testing.T) {
+		if _, err := gitinterface.Commit(app.r, gitinterface.EmptyTree(), refName, "Initial commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := app.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		err := app.VerifySubmodules(testCtx, refName)
+		assert.ErrorIs(t, err, ErrSubmodulesNotRecorded)
+	})
+
+	t.Run("submodule commit is verified", func(t *testing.T) {
+		recordAppState(libCommitIDs[0])
+
+		entry, _, err := rsl.GetLatestReferenceEntryForRef(app.r, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, map[string]plumbing.Hash{"lib": libCommitIDs[0]}, entry.Submodules)
+
+		err = app.VerifySubmodules(testCtx, "main")
+		assert.Nil(t, err)
+	})
+
+	t.Run("submodule commit is not recorded", func(t *testing.T) {
+		recordAppState(unrecordedCommitID)
+
+		err := app.VerifySubmodules(testCtx, refName)
+		assert.ErrorIs(t, err, ErrSubmoduleNotVerified)
+	})
+}
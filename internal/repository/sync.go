@@ -14,6 +14,7 @@ import (
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/progress"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-git/v5/plumbing"
 )
@@ -22,13 +23,23 @@ var (
 	ErrCloningRepository          = errors.New("unable to clone repository")
 	ErrDirExists                  = errors.New("directory exists")
 	ErrExpectedRootKeysDoNotMatch = errors.Join(ErrCloningRepository, errors.New("cloned root keys do not match the expected keys"))
+	ErrWorkingTreeNotCheckedOut   = errors.New("verification of cloned repository failed, working tree was not checked out")
+	ErrPinningRootKeys            = errors.New("unable to pin root of trust keys of cloned repository, working tree was not checked out")
 )
 
 // Clone wraps a typical git clone invocation, fetching gittuf refs in addition
-// to the standard refs. It performs a verification of the RSL against the
-// specified HEAD after cloning the repository.
-// TODO: resolve how root keys are trusted / bootstrapped.
-func Clone(ctx context.Context, remoteURL, dir, initialBranch string, expectedRootKeys []*tuf.Key) (*Repository, error) {
+// to the standard refs. It performs a verification of the RSL and policy from
+// the root of trust against the specified HEAD after cloning the repository.
+// The working tree is checked out only if verification succeeds, so the
+// contents of an unverified repository are never written to disk. If
+// verification fails, the repository is still returned so that it can be
+// inspected.
+//
+// Trust in the repository's root of trust can be bootstrapped using either the
+// expected root keys or expectedRootHash, the hash of the initial root
+// metadata obtained out of band. The hash is pinned for the clone, so every
+// subsequent verification checks the root of trust lineage against it.
+func Clone(ctx context.Context, remoteURL, dir, initialBranch string, expectedRootKeys []*tuf.Key, expectedRootHash string) (*Repository, error) {
 	slog.Debug(fmt.Sprintf("Cloning from '%s'...", remoteURL))
 
 	if dir == "" {
@@ -55,7 +66,8 @@ func Clone(ctx context.Context, remoteURL, dir, initialBranch string, expectedRo
 	refs := []string{"refs/gittuf/*"}
 
 	slog.Debug("Cloning repository...")
-	r, err := gitinterface.CloneAndFetch(ctx, remoteURL, dir, initialBranch, refs)
+	progress.Stage("fetching repository", 0)
+	r, err := gitinterface.CloneAndFetchWithoutCheckout(ctx, remoteURL, dir, initialBranch, refs)
 	if err != nil {
 		if e := os.RemoveAll(dir); e != nil {
 			return nil, errors.Join(ErrCloningRepository, err, e)
@@ -69,6 +81,12 @@ func Clone(ctx context.Context, remoteURL, dir, initialBranch string, expectedRo
 
 	repository := &Repository{r: r}
 
+	// The verification cache is local state, one fetched from the remote must
+	// not be trusted
+	if err := repository.ResetVerificationCache(); err != nil {
+		return repository, errors.Join(ErrCloningRepository, err)
+	}
+
 	if len(expectedRootKeys) > 0 {
 		slog.Debug("Verifying if root keys are expected root keys...")
 
@@ -98,6 +116,39 @@ func Clone(ctx context.Context, remoteURL, dir, initialBranch string, expectedRo
 		}
 	}
 
+	if expectedRootHash != "" {
+		slog.Debug("Pinning expected initial root hash...")
+		if err := policy.PinInitialRoot(r, expectedRootHash); err != nil {
+			return repository, errors.Join(ErrCloningRepository, err)
+		}
+	}
+
+	if _, err := r.Reference(head.Target(), true); err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			// The remote is freshly created and has no commits yet, there's
+			// nothing to verify or check out
+			slog.Debug(fmt.Sprintf("'%s' has no commits yet, skipping verification...", head.Target().String()))
+			return repository, nil
+		}
+		return repository, errors.Join(ErrCloningRepository, err)
+	}
+
+	// The root of trust keys are pinned when the repository is cloned, so
+	// that later verifications detect if they're swapped
+	if err := repository.PinRootKeys(ctx); err != nil {
+		return repository, errors.Join(ErrPinningRootKeys, err)
+	}
+
 	slog.Debug("Verifying HEAD...")
-	return repository, repository.VerifyRef(ctx, head.Target().String(), false)
+	if err := repository.VerifyRef(ctx, head.Target().String(), false); err != nil {
+		return repository, errors.Join(ErrWorkingTreeNotCheckedOut, err)
+	}
+
+	slog.Debug("Checking out working tree...")
+	progress.Stage("checking out working tree", 0)
+	if err := gitinterface.CheckoutHead(r); err != nil {
+		return repository, errors.Join(ErrCloningRepository, err)
+	}
+
+	return repository, nil
 }
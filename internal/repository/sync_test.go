@@ -5,6 +5,8 @@ package repository
 import (
 	"context"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
@@ -13,7 +15,6 @@ import (
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	"github.com/gittuf/gittuf/internal/tuf"
-	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/stretchr/testify/assert"
 )
@@ -36,7 +37,7 @@ func TestClone(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	remoteR, err := git.PlainInit(remoteTmpDir, true)
+	remoteR, err := gitinterface.PlainInit(remoteTmpDir, true)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -66,7 +67,7 @@ func TestClone(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if err := remoteRepo.RecordRSLEntryForReference(refName, false); err != nil {
+	if err := remoteRepo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
 		t.Fatal(err)
 	}
 	if err := remoteRepo.r.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.ReferenceName(refName))); err != nil {
@@ -75,7 +76,7 @@ func TestClone(t *testing.T) {
 	if err := remoteRepo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(anotherRefName), commitID)); err != nil {
 		t.Fatal(err)
 	}
-	if err := remoteRepo.RecordRSLEntryForReference(anotherRefName, false); err != nil {
+	if err := remoteRepo.RecordRSLEntryForReference(testCtx, anotherRefName, false); err != nil {
 		t.Fatal(err)
 	}
 
@@ -92,7 +93,7 @@ func TestClone(t *testing.T) {
 		}
 		defer os.Chdir(currentDir) //nolint:errcheck
 
-		repo, err := Clone(context.Background(), remoteTmpDir, "", "", nil)
+		repo, err := Clone(context.Background(), remoteTmpDir, "", "", nil, "")
 		assert.Nil(t, err)
 
 		head, err := repo.r.Head()
@@ -103,6 +104,11 @@ func TestClone(t *testing.T) {
 
 		assertLocalAndRemoteRefsMatch(t, repo.r, remoteRepo.r, rsl.Ref)
 		assertLocalAndRemoteRefsMatch(t, repo.r, remoteRepo.r, policy.PolicyRef)
+
+		// The root of trust keys are pinned on clone
+		pin, err := policy.LoadRootPin(repo.r)
+		assert.Nil(t, err)
+		assert.NotNil(t, pin)
 	})
 
 	t.Run("successful clone with dir", func(t *testing.T) {
@@ -114,7 +120,7 @@ func TestClone(t *testing.T) {
 		defer os.Chdir(currentDir) //nolint:errcheck
 
 		dirName := "myRepo"
-		repo, err := Clone(context.Background(), remoteTmpDir, dirName, "", nil)
+		repo, err := Clone(context.Background(), remoteTmpDir, dirName, "", nil, "")
 		assert.Nil(t, err)
 
 		head, err := repo.r.Head()
@@ -139,7 +145,7 @@ func TestClone(t *testing.T) {
 		}
 		defer os.Chdir(currentDir) //nolint:errcheck
 
-		repo, err := Clone(context.Background(), remoteTmpDir, "", anotherRefName, nil)
+		repo, err := Clone(context.Background(), remoteTmpDir, "", anotherRefName, nil, "")
 		assert.Nil(t, err)
 
 		head, err := repo.r.Head()
@@ -161,10 +167,10 @@ func TestClone(t *testing.T) {
 		}
 		defer os.Chdir(currentDir) //nolint:errcheck
 
-		_, err = Clone(context.Background(), remoteTmpDir, "", "", nil)
+		_, err = Clone(context.Background(), remoteTmpDir, "", "", nil, "")
 		assert.Nil(t, err)
 
-		_, err = Clone(context.Background(), remoteTmpDir, "", "", nil)
+		_, err = Clone(context.Background(), remoteTmpDir, "", "", nil, "")
 		assert.ErrorIs(t, err, ErrDirExists)
 	})
 
@@ -181,7 +187,7 @@ func TestClone(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		_, err = Clone(context.Background(), remoteTmpDir, dirName, "", nil)
+		_, err = Clone(context.Background(), remoteTmpDir, dirName, "", nil, "")
 		assert.ErrorIs(t, err, ErrDirExists)
 	})
 
@@ -193,7 +199,7 @@ func TestClone(t *testing.T) {
 		}
 		defer os.Chdir(currentDir) //nolint:errcheck
 
-		repo, err := Clone(context.Background(), remoteTmpDir+"//", "", "", nil)
+		repo, err := Clone(context.Background(), remoteTmpDir+"//", "", "", nil, "")
 		assert.Nil(t, err)
 
 		head, err := repo.r.Head()
@@ -224,7 +230,7 @@ func TestClone(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		repo, err := Clone(context.Background(), remoteTmpDir, "", "", []*tuf.Key{targetsPublicKey, rootPublicKey})
+		repo, err := Clone(context.Background(), remoteTmpDir, "", "", []*tuf.Key{targetsPublicKey, rootPublicKey}, "")
 		assert.Nil(t, err)
 
 		head, err := repo.r.Head()
@@ -255,7 +261,118 @@ func TestClone(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		_, err = Clone(context.Background(), remoteTmpDir, "", "", []*tuf.Key{rootPublicKey, badPublicKey})
+		_, err = Clone(context.Background(), remoteTmpDir, "", "", []*tuf.Key{rootPublicKey, badPublicKey}, "")
 		assert.ErrorIs(t, ErrExpectedRootKeysDoNotMatch, err)
 	})
+
+	t.Run("successful clone with expected root hash", func(t *testing.T) {
+		localTmpDir := t.TempDir()
+
+		if err := os.Chdir(localTmpDir); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chdir(currentDir) //nolint:errcheck
+
+		rootHash, err := remoteRepo.GetInitialRootHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		repo, err := Clone(context.Background(), remoteTmpDir, "", "", nil, rootHash)
+		assert.Nil(t, err)
+
+		head, err := repo.r.Head()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, commitID, head.Hash())
+
+		pin, err := policy.LoadRootPin(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, rootHash, pin.InitialRootHash)
+	})
+
+	t.Run("unsuccessful clone with mismatched root hash", func(t *testing.T) {
+		localTmpDir := t.TempDir()
+
+		if err := os.Chdir(localTmpDir); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chdir(currentDir) //nolint:errcheck
+
+		_, err := Clone(context.Background(), remoteTmpDir, "", "", nil, strings.Repeat("0", 64))
+		assert.ErrorIs(t, err, policy.ErrInitialRootHashMismatch)
+	})
+
+	// The following tests add a file to the remote's main branch
+	blobID, err := gitinterface.WriteBlob(remoteRepo.r, []byte("Hello, world!\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeID, err := gitinterface.NewTreeBuilder(remoteRepo.r).WriteRootTreeFromBlobIDs(map[string]plumbing.Hash{"README.md": blobID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gitinterface.Commit(remoteRepo.r, treeID, refName, "Add README", false); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("unsuccessful clone does not check out working tree, with update not recorded in RSL", func(t *testing.T) {
+		localTmpDir := t.TempDir()
+
+		if err := os.Chdir(localTmpDir); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chdir(currentDir) //nolint:errcheck
+
+		dirName := "myRepo"
+		_, err := Clone(context.Background(), remoteTmpDir, dirName, "", nil, "")
+		assert.ErrorIs(t, err, ErrWorkingTreeNotCheckedOut)
+
+		_, err = os.Stat(filepath.Join(dirName, "README.md"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	if err := remoteRepo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("successful clone checks out working tree", func(t *testing.T) {
+		localTmpDir := t.TempDir()
+
+		if err := os.Chdir(localTmpDir); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chdir(currentDir) //nolint:errcheck
+
+		dirName := "myRepo"
+		_, err := Clone(context.Background(), remoteTmpDir, dirName, "", nil, "")
+		assert.Nil(t, err)
+
+		contents, err := os.ReadFile(filepath.Join(dirName, "README.md"))
+		assert.Nil(t, err)
+		assert.Equal(t, "Hello, world!\n", string(contents))
+	})
+
+	t.Run("successful clone of empty repository", func(t *testing.T) {
+		emptyRemoteTmpDir := t.TempDir()
+		if _, err := gitinterface.PlainInit(emptyRemoteTmpDir, true); err != nil {
+			t.Fatal(err)
+		}
+
+		localTmpDir := t.TempDir()
+
+		if err := os.Chdir(localTmpDir); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chdir(currentDir) //nolint:errcheck
+
+		repo, err := Clone(context.Background(), emptyRemoteTmpDir, "myRepo", "", nil, "")
+		assert.Nil(t, err)
+
+		_, err = repo.r.Head()
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
 }
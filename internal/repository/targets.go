@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
@@ -200,6 +201,401 @@ func (r *Repository) UpdateDelegation(ctx context.Context, signer sslibdsse.Sign
 	return state.Commit(r.r, commitMessage, signCommit)
 }
 
+// AddRestrictedKeyToDelegation is the interface for the user to authorize a key
+// in a rule of gittuf policy only for the specified operations. This is used,
+// for example, to trust GitHub's web-flow key only for merge commits created
+// using GitHub's web UI.
+func (r *Repository) AddRestrictedKeyToDelegation(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName string, ruleName string, key *tuf.Key, operations []string, signCommit bool) error {
+	if ruleName == policy.RootRoleName {
+		return ErrInvalidPolicyName
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current rule file...")
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	// TODO: verify is role can be signed using the presented key. This requires
+	// the user to pass in the delegating role as well as we do not want to
+	// assume which role is the delegating role (diamond delegations are legal).
+	// See: https://github.com/gittuf/gittuf/issues/246.
+
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Adding key '%s' to rule restricted to operations '%s'...", key.KeyID, strings.Join(operations, ", ")))
+	targetsMetadata, err = policy.AddRestrictedKeyToDelegation(targetsMetadata, ruleName, key, operations)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.TargetsEnvelope = env
+	} else {
+		state.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Add restricted key '%s' to rule '%s' in policy '%s'", key.KeyID, ruleName, targetsRoleName)
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, signCommit)
+}
+
+// SetDelegationRotation is the interface for the user to set the rotation
+// schedule of a rule in gittuf policy, so that the rule's authorized keys rotate
+// on a calendar. Passing a nil schedule removes the rule's rotation.
+func (r *Repository) SetDelegationRotation(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName string, ruleName string, rotation *tuf.RotationSchedule, signCommit bool) error {
+	if ruleName == policy.RootRoleName {
+		return ErrInvalidPolicyName
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current rule file...")
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	// TODO: verify is role can be signed using the presented key. This requires
+	// the user to pass in the delegating role as well as we do not want to
+	// assume which role is the delegating role (diamond delegations are legal).
+	// See: https://github.com/gittuf/gittuf/issues/246.
+
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Setting rotation schedule of rule...")
+	targetsMetadata, err = policy.SetDelegationRotation(targetsMetadata, ruleName, rotation)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.TargetsEnvelope = env
+	} else {
+		state.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Set rotation schedule of rule '%s' in policy '%s'", ruleName, targetsRoleName)
+	if rotation == nil {
+		commitMessage = fmt.Sprintf("Remove rotation schedule of rule '%s' in policy '%s'", ruleName, targetsRoleName)
+	}
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, signCommit)
+}
+
+// SetDelegationRequireTicket is the interface for the user to set whether a
+// rule in gittuf policy requires RSL entries for its refs to record a ticket.
+func (r *Repository) SetDelegationRequireTicket(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName string, ruleName string, requireTicket bool, signCommit bool) error {
+	if ruleName == policy.RootRoleName {
+		return ErrInvalidPolicyName
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current rule file...")
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	// TODO: verify is role can be signed using the presented key. This requires
+	// the user to pass in the delegating role as well as we do not want to
+	// assume which role is the delegating role (diamond delegations are legal).
+	// See: https://github.com/gittuf/gittuf/issues/246.
+
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Setting ticket requirement of rule...")
+	targetsMetadata, err = policy.SetDelegationRequireTicket(targetsMetadata, ruleName, requireTicket)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.TargetsEnvelope = env
+	} else {
+		state.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Require tickets for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+	if !requireTicket {
+		commitMessage = fmt.Sprintf("Remove ticket requirement of rule '%s' in policy '%s'", ruleName, targetsRoleName)
+	}
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, signCommit)
+}
+
+// SetDelegationAllowedBuilders is the interface for the user to set the
+// builders a rule in gittuf policy allows SLSA provenance for its tags from. If
+// no builders are specified, the rule no longer requires provenance.
+func (r *Repository) SetDelegationAllowedBuilders(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName string, ruleName string, builders []string, signCommit bool) error {
+	if ruleName == policy.RootRoleName {
+		return ErrInvalidPolicyName
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current rule file...")
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	// TODO: verify is role can be signed using the presented key. This requires
+	// the user to pass in the delegating role as well as we do not want to
+	// assume which role is the delegating role (diamond delegations are legal).
+	// See: https://github.com/gittuf/gittuf/issues/246.
+
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Setting allowed builders of rule...")
+	targetsMetadata, err = policy.SetDelegationAllowedBuilders(targetsMetadata, ruleName, builders)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.TargetsEnvelope = env
+	} else {
+		state.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Set allowed builders of rule '%s' in policy '%s'", ruleName, targetsRoleName)
+	if len(builders) == 0 {
+		commitMessage = fmt.Sprintf("Remove allowed builders of rule '%s' in policy '%s'", ruleName, targetsRoleName)
+	}
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, signCommit)
+}
+
+// SetDelegationAllowedUpdateTypes is the interface for the user to set how the
+// branches protected by a rule in gittuf policy may be updated, such as to
+// require linear history. If no update types are specified, all update types
+// are allowed.
+func (r *Repository) SetDelegationAllowedUpdateTypes(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName string, ruleName string, updateTypes []string, signCommit bool) error {
+	if ruleName == policy.RootRoleName {
+		return ErrInvalidPolicyName
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current rule file...")
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	// TODO: verify is role can be signed using the presented key. This requires
+	// the user to pass in the delegating role as well as we do not want to
+	// assume which role is the delegating role (diamond delegations are legal).
+	// See: https://github.com/gittuf/gittuf/issues/246.
+
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Setting allowed update types of rule...")
+	targetsMetadata, err = policy.SetDelegationAllowedUpdateTypes(targetsMetadata, ruleName, updateTypes)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.TargetsEnvelope = env
+	} else {
+		state.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Set allowed update types of rule '%s' in policy '%s'", ruleName, targetsRoleName)
+	if len(updateTypes) == 0 {
+		commitMessage = fmt.Sprintf("Remove allowed update types of rule '%s' in policy '%s'", ruleName, targetsRoleName)
+	}
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, signCommit)
+}
+
+// SetDelegationRefMappings is the interface for the user to map the names of
+// refs on mirrors of the repository to the names of the upstream refs protected
+// by a rule in gittuf policy. If no mappings are specified, the rule's mappings
+// are removed.
+func (r *Repository) SetDelegationRefMappings(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName string, ruleName string, mappings map[string]string, signCommit bool) error {
+	if ruleName == policy.RootRoleName {
+		return ErrInvalidPolicyName
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current rule file...")
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	// TODO: verify is role can be signed using the presented key. This requires
+	// the user to pass in the delegating role as well as we do not want to
+	// assume which role is the delegating role (diamond delegations are legal).
+	// See: https://github.com/gittuf/gittuf/issues/246.
+
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Setting ref mappings of rule...")
+	targetsMetadata, err = policy.SetDelegationRefMappings(targetsMetadata, ruleName, mappings)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.TargetsEnvelope = env
+	} else {
+		state.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Set ref mappings of rule '%s' in policy '%s'", ruleName, targetsRoleName)
+	if len(mappings) == 0 {
+		commitMessage = fmt.Sprintf("Remove ref mappings of rule '%s' in policy '%s'", ruleName, targetsRoleName)
+	}
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, signCommit)
+}
+
 // RemoveDelegation is the interface for a user to remove a rule from gittuf
 // policy.
 func (r *Repository) RemoveDelegation(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName string, ruleName string, signCommit bool) error {
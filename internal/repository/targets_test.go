@@ -168,6 +168,217 @@ func TestUpdateDelegation(t *testing.T) {
 	})
 }
 
+func TestAddRestrictedKeyToDelegation(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	webFlowKey, err := gpg.LoadGPGKeyFromBytes(gpgUnauthorizedKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.AddRestrictedKeyToDelegation(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", webFlowKey, []string{tuf.KeyOperationMergeCommit}, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Contains(t, targetsMetadata.Delegations.Roles, tuf.Delegation{
+		Name:          "protect-main",
+		Paths:         []string{"git:refs/heads/main"},
+		Terminating:   false,
+		Role:          tuf.Role{KeyIDs: []string{gpgKey.KeyID, webFlowKey.KeyID}, Threshold: 1},
+		KeyOperations: map[string][]string{webFlowKey.KeyID: {tuf.KeyOperationMergeCommit}},
+	})
+
+	err = r.AddRestrictedKeyToDelegation(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", webFlowKey, []string{"push"}, false)
+	assert.ErrorIs(t, err, tuf.ErrUnknownKeyOperation)
+}
+
+func TestSetDelegationRotation(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotation := &tuf.RotationSchedule{
+		Start:  "2024-01-01T00:00:00Z",
+		Period: "168h",
+		Shifts: [][]string{{gpgKey.KeyID}},
+	}
+
+	err = r.SetDelegationRotation(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", rotation, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, rotation, targetsMetadata.Delegations.Roles[0].Rotation)
+
+	err = r.SetDelegationRotation(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", nil, false)
+	assert.Nil(t, err)
+
+	state, err = policy.LoadCurrentState(context.Background(), r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = state.GetTargetsMetadata(policy.TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].Rotation)
+}
+
+func TestSetDelegationRequireTicket(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetDelegationRequireTicket(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", true, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, targetsMetadata.Delegations.Roles[0].RequireTicket)
+
+	err = r.SetDelegationRequireTicket(testCtx, targetsSigner, policy.TargetsRoleName, "missing-rule", true, false)
+	assert.ErrorIs(t, err, policy.ErrDelegationNotFound)
+}
+
+func TestSetDelegationAllowedBuilders(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builders := []string{"https://github.com/gittuf/gittuf/.github/workflows/release.yml@refs/tags/v1"}
+	err = r.SetDelegationAllowedBuilders(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", builders, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, builders, targetsMetadata.Delegations.Roles[0].AllowedBuilders)
+
+	err = r.SetDelegationAllowedBuilders(testCtx, targetsSigner, policy.TargetsRoleName, "missing-rule", builders, false)
+	assert.ErrorIs(t, err, policy.ErrDelegationNotFound)
+}
+
+func TestSetDelegationAllowedUpdateTypes(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updateTypes := []string{tuf.UpdateTypeMerge}
+	err = r.SetDelegationAllowedUpdateTypes(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", updateTypes, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, updateTypes, targetsMetadata.Delegations.Roles[0].AllowedUpdateTypes)
+
+	err = r.SetDelegationAllowedUpdateTypes(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", []string{"rebase"}, false)
+	assert.ErrorIs(t, err, tuf.ErrUnknownUpdateType)
+}
+
+func TestSetDelegationRefMappings(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mappings := map[string]string{"refs/heads/upstream/main": "refs/heads/main"}
+	err = r.SetDelegationRefMappings(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", mappings, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, mappings, targetsMetadata.Delegations.Roles[0].RefMappings)
+
+	err = r.SetDelegationRefMappings(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", map[string]string{"refs/heads/upstream/main": "refs/heads/feature"}, false)
+	assert.ErrorIs(t, err, policy.ErrInvalidRefMapping)
+
+	err = r.SetDelegationRefMappings(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", nil, false)
+	assert.Nil(t, err)
+
+	state, err = policy.LoadCurrentState(context.Background(), r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = state.GetTargetsMetadata(policy.TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].RefMappings)
+}
+
 func TestRemoveDelegation(t *testing.T) {
 	r := createTestRepositoryWithPolicy(t, "")
 
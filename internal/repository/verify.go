@@ -11,6 +11,8 @@ import (
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/report"
+	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/go-git/go-git/v5/plumbing"
 )
 
@@ -24,6 +26,15 @@ import (
 var ErrRefStateDoesNotMatchRSL = errors.New("Git reference's current state does not match latest RSL entry") //nolint:stylecheck
 
 func (r *Repository) VerifyRef(ctx context.Context, target string, latestOnly bool) error {
+	if err := r.VerifyRootPin(ctx); err != nil {
+		return err
+	}
+
+	slog.Debug("Checking for alterations to history presented by Git...")
+	if err := policy.VerifyHistoryIntegrity(ctx, r.r); err != nil {
+		return err
+	}
+
 	var (
 		expectedTip plumbing.Hash
 		err         error
@@ -35,12 +46,21 @@ func (r *Repository) VerifyRef(ctx context.Context, target string, latestOnly bo
 		return err
 	}
 
-	slog.Debug(fmt.Sprintf("Verifying gittuf policies for '%s'", target))
+	upstreamTarget, err := r.upstreamRefName(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	if err := r.fetchMissingObjectsForRef(upstreamTarget); err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Verifying gittuf policies for '%s'", upstreamTarget))
 
 	if latestOnly {
-		expectedTip, err = policy.VerifyRef(ctx, r.r, target)
+		expectedTip, err = policy.VerifyRef(ctx, r.r, upstreamTarget)
 	} else {
-		expectedTip, err = policy.VerifyRefFull(ctx, r.r, target)
+		expectedTip, err = policy.VerifyRefFullWithCache(ctx, r.r, upstreamTarget)
 	}
 	if err != nil {
 		return err
@@ -48,6 +68,9 @@ func (r *Repository) VerifyRef(ctx context.Context, target string, latestOnly bo
 
 	slog.Debug("Verifying if tip of reference matches expected value from RSL...")
 	if err := r.verifyRefTip(target, expectedTip); err != nil {
+		if errors.Is(err, ErrRefStateDoesNotMatchRSL) {
+			report.RecordViolation("", err)
+		}
 		return err
 	}
 
@@ -55,11 +78,68 @@ func (r *Repository) VerifyRef(ctx context.Context, target string, latestOnly bo
 	return nil
 }
 
+// VerifyRefKeepGoing verifies the entire RSL for the target ref like
+// VerifyRef, but continues past policy violations so that all of them are
+// reported together. The verification cache is not used or updated. If any
+// violations are found, a policy.ErrPolicyViolations is returned.
+func (r *Repository) VerifyRefKeepGoing(ctx context.Context, target string) error {
+	if err := r.VerifyRootPin(ctx); err != nil {
+		return err
+	}
+
+	slog.Debug("Checking for alterations to history presented by Git...")
+	if err := policy.VerifyHistoryIntegrity(ctx, r.r); err != nil {
+		return err
+	}
+
+	slog.Debug("Identifying absolute reference path...")
+	target, err := gitinterface.AbsoluteReference(r.r, target)
+	if err != nil {
+		return err
+	}
+
+	upstreamTarget, err := r.upstreamRefName(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	if err := r.fetchMissingObjectsForRef(upstreamTarget); err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Verifying gittuf policies for '%s', continuing past violations", upstreamTarget))
+	violations := &policy.ErrPolicyViolations{}
+	expectedTip, err := policy.VerifyRefFullKeepGoing(ctx, r.r, upstreamTarget)
+	if err != nil && !errors.As(err, &violations) {
+		return err
+	}
+
+	slog.Debug("Verifying if tip of reference matches expected value from RSL...")
+	if err := r.verifyRefTip(target, expectedTip); err != nil {
+		if !errors.Is(err, ErrRefStateDoesNotMatchRSL) {
+			return err
+		}
+		report.RecordViolation("", err)
+		violations.Add(plumbing.ZeroHash, err)
+	}
+
+	return violations.ErrOrNil()
+}
+
 func (r *Repository) VerifyRefFromEntry(ctx context.Context, target, entryID string) error {
 	if !dev.InDevMode() {
 		return dev.ErrNotInDevMode
 	}
 
+	if err := r.VerifyRootPin(ctx); err != nil {
+		return err
+	}
+
+	slog.Debug("Checking for alterations to history presented by Git...")
+	if err := policy.VerifyHistoryIntegrity(ctx, r.r); err != nil {
+		return err
+	}
+
 	var err error
 
 	slog.Debug("Identifying absolute reference path...")
@@ -68,14 +148,26 @@ func (r *Repository) VerifyRefFromEntry(ctx context.Context, target, entryID str
 		return err
 	}
 
-	slog.Debug(fmt.Sprintf("Verifying gittuf policies for '%s' from entry '%s'", target, entryID))
-	expectedTip, err := policy.VerifyRefFromEntry(ctx, r.r, target, plumbing.NewHash(entryID))
+	upstreamTarget, err := r.upstreamRefName(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	if err := r.fetchMissingObjectsForRef(upstreamTarget); err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Verifying gittuf policies for '%s' from entry '%s'", upstreamTarget, entryID))
+	expectedTip, err := policy.VerifyRefFromEntry(ctx, r.r, upstreamTarget, plumbing.NewHash(entryID))
 	if err != nil {
 		return err
 	}
 
 	slog.Debug("Verifying if tip of reference matches expected value from RSL...")
 	if err := r.verifyRefTip(target, expectedTip); err != nil {
+		if errors.Is(err, ErrRefStateDoesNotMatchRSL) {
+			report.RecordViolation("", err)
+		}
 		return err
 	}
 
@@ -83,6 +175,93 @@ func (r *Repository) VerifyRefFromEntry(ctx context.Context, target, entryID str
 	return nil
 }
 
+// VerifyRefRange verifies the RSL entries that record the target ref being
+// updated from oldID to newID, such as the range a Git server's pre-receive
+// hook reports for a push. Unlike VerifyRef, the current tip of the ref is not
+// checked as the update may not have been applied yet. If rslTip is set, the
+// entries are identified using the RSL as of that entry, which must be a
+// fast-forward of the current RSL. This allows verifying a push that also
+// updates the RSL before the RSL is updated.
+func (r *Repository) VerifyRefRange(ctx context.Context, target, oldID, newID, rslTip string) error {
+	return r.verifyRefRange(ctx, target, oldID, newID, rslTip, false)
+}
+
+// VerifyRefRangeKeepGoing verifies the RSL entries that record the target ref
+// being updated from oldID to newID like VerifyRefRange, but continues past
+// policy violations so that all of them are reported together. If any
+// violations are found, a policy.ErrPolicyViolations is returned.
+func (r *Repository) VerifyRefRangeKeepGoing(ctx context.Context, target, oldID, newID, rslTip string) error {
+	return r.verifyRefRange(ctx, target, oldID, newID, rslTip, true)
+}
+
+func (r *Repository) verifyRefRange(ctx context.Context, target, oldID, newID, rslTip string, keepGoing bool) error {
+	if !plumbing.IsHash(oldID) {
+		return fmt.Errorf("%w: '%s'", ErrInvalidObjectID, oldID)
+	}
+	if !plumbing.IsHash(newID) {
+		return fmt.Errorf("%w: '%s'", ErrInvalidObjectID, newID)
+	}
+
+	slog.Debug("Identifying absolute reference path...")
+	target, err := r.absoluteTargetRef(target)
+	if err != nil {
+		return err
+	}
+
+	repo := r.r
+	if rslTip != "" {
+		if !plumbing.IsHash(rslTip) {
+			return fmt.Errorf("%w: '%s'", ErrInvalidObjectID, rslTip)
+		}
+
+		rslUpdate := &RefUpdate{Name: rsl.Ref, NewID: plumbing.NewHash(rslTip)}
+		currentRSL, err := r.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		if err == nil {
+			rslUpdate.OldID = currentRSL.Hash()
+		} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return err
+		}
+
+		slog.Debug(fmt.Sprintf("Using RSL as of entry '%s'...", rslTip))
+		repo, err = r.withRefUpdates([]*RefUpdate{rslUpdate})
+		if err != nil {
+			return err
+		}
+		if err := verifyRSLUpdate(repo, rslUpdate); err != nil {
+			return err
+		}
+	}
+
+	slog.Debug("Checking for alterations to history presented by Git...")
+	if err := policy.VerifyHistoryIntegrity(ctx, repo); err != nil {
+		return err
+	}
+
+	target, err = r.upstreamRefName(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Verifying gittuf policies for '%s' from '%s' to '%s'", target, oldID, newID))
+	verifyRange := policy.VerifyRefRange
+	if keepGoing {
+		verifyRange = policy.VerifyRefRangeKeepGoing
+	}
+	if err := verifyRange(ctx, repo, target, plumbing.NewHash(oldID), plumbing.NewHash(newID)); err != nil {
+		return err
+	}
+
+	slog.Debug("Verification successful!")
+	return nil
+}
+
+// ResetVerificationCache discards the results of prior verification runs, so
+// that the next verification of the RSL starts from the first entry.
+func (r *Repository) ResetVerificationCache() error {
+	slog.Debug("Removing verification cache...")
+	return policy.ResetVerificationCache(r.r)
+}
+
 func (r *Repository) VerifyCommit(ctx context.Context, ids ...string) map[string]string {
 	slog.Debug("Verifying commit signature...")
 	return policy.VerifyCommit(ctx, r.r, ids...)
@@ -93,6 +272,31 @@ func (r *Repository) VerifyTag(ctx context.Context, ids []string) map[string]str
 	return policy.VerifyTag(ctx, r.r, ids)
 }
 
+// upstreamRefName returns the name of the upstream ref that the target ref is
+// mapped to by the latest policy, such as when the target is a ref on a mirror
+// that publishes the upstream ref under a different name. The upstream ref's
+// RSL entries record the target's changes. If the target isn't mapped or there
+// is no policy, the target is returned as is.
+func (r *Repository) upstreamRefName(ctx context.Context, target string) (string, error) {
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		if errors.Is(err, policy.ErrPolicyNotFound) {
+			return target, nil
+		}
+		return "", err
+	}
+
+	upstreamTarget, err := state.GetUpstreamRefName(target)
+	if err != nil {
+		return "", err
+	}
+	if upstreamTarget != target {
+		slog.Debug(fmt.Sprintf("Ref '%s' is mapped to upstream ref '%s'...", target, upstreamTarget))
+	}
+
+	return upstreamTarget, nil
+}
+
 func (r *Repository) verifyRefTip(target string, expectedTip plumbing.Hash) error {
 	ref, err := r.r.Reference(plumbing.ReferenceName(target), true)
 	if err != nil {
@@ -9,8 +9,11 @@ import (
 
 	"github.com/gittuf/gittuf/internal/common"
 	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/report"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/stretchr/testify/assert"
 )
@@ -65,12 +68,326 @@ func TestVerifyRef(t *testing.T) {
 		}
 	}
 
+	// Full verification caches its result
+	_, err := repo.r.Reference(plumbing.ReferenceName(policy.VerificationCacheRef), true)
+	assert.Nil(t, err)
+
+	err = repo.ResetVerificationCache()
+	assert.Nil(t, err)
+	_, err = repo.r.Reference(plumbing.ReferenceName(policy.VerificationCacheRef), true)
+	assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+
 	// Add another commit
 	common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
-	err := repo.VerifyRef(context.Background(), refName, true)
+	err = repo.VerifyRef(context.Background(), refName, true)
 	assert.ErrorIs(t, err, ErrRefStateDoesNotMatchRSL)
 	err = repo.VerifyRef(context.Background(), refName, false)
 	assert.ErrorIs(t, err, ErrRefStateDoesNotMatchRSL)
+
+	// Unprotected replace ref that alters history presented by Git
+	replaceRefName := gitinterface.ReplaceRefPrefix + commitIDs[0].String()
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(replaceRefName), entryID)); err != nil {
+		t.Fatal(err)
+	}
+	err = repo.VerifyRef(context.Background(), refName, true)
+	assert.ErrorIs(t, err, policy.ErrUnprotectedReplaceRef)
+}
+
+func TestVerifyRefInRSLShard(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	sv, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddRSLShard(testCtx, sv, "heads", []string{"refs/heads/"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.ApplyPolicy(testCtx, false); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("record entries in shard", func(t *testing.T) {
+		refName := "refs/heads/feature"
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 2; i++ {
+			common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+			if err := repo.RecordRSLEntryForReference(testCtx, refName, false); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// The entries are recorded in the shard, and the RSL records the
+		// shard's tip
+		latestEntry, err := rsl.GetLatestEntry(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, rsl.ShardRef("heads"), latestEntry.(*rsl.ReferenceEntry).RefName)
+
+		shardEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo.r, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, "heads", shardEntry.Shard)
+		assert.Equal(t, latestEntry.GetID(), shardEntry.IndexEntryID)
+
+		// Recording the same target again is a no-op
+		err = repo.RecordRSLEntryForReference(testCtx, refName, false)
+		assert.Nil(t, err)
+		newLatestEntry, err := rsl.GetLatestEntry(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, latestEntry.GetID(), newLatestEntry.GetID())
+	})
+
+	t.Run("verify ref in shard", func(t *testing.T) {
+		refName := "refs/heads/main"
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+
+		recordInShard := func(signingKeyBytes []byte) {
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, signingKeyBytes)
+			entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+			entry.Shard = "heads"
+			shardTip := common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, signingKeyBytes)
+			common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(rsl.ShardRef("heads"), shardTip), gpgKeyBytes)
+		}
+
+		recordInShard(gpgKeyBytes)
+		recordInShard(gpgKeyBytes)
+
+		err := repo.VerifyRef(testCtx, refName, true)
+		assert.Nil(t, err)
+
+		err = repo.VerifyRef(testCtx, refName, false)
+		assert.Nil(t, err)
+
+		// An unauthorized change recorded in the shard fails verification
+		recordInShard(gpgUnauthorizedKeyBytes)
+
+		err = repo.VerifyRef(testCtx, refName, true)
+		assert.ErrorIs(t, err, policy.ErrUnauthorizedSignature)
+
+		err = repo.VerifyRef(testCtx, refName, false)
+		assert.ErrorIs(t, err, policy.ErrUnauthorizedSignature)
+	})
+}
+
+func TestVerifyRefRSLShardMembership(t *testing.T) {
+	refName := "refs/heads/main"
+
+	createRepositoryWithShard := func(t *testing.T) *Repository {
+		t.Helper()
+
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		// The entry recorded before the shard is added is in the RSL
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+
+		sv, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.AddRSLShard(testCtx, sv, "heads", []string{"refs/heads/"}, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.ApplyPolicy(testCtx, false); err != nil {
+			t.Fatal(err)
+		}
+
+		return repo
+	}
+
+	t.Run("entries recorded before and after the shard is added", func(t *testing.T) {
+		repo := createRepositoryWithShard(t)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.Shard = "heads"
+		shardTip := common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(rsl.ShardRef("heads"), shardTip), gpgKeyBytes)
+
+		err := repo.VerifyRef(testCtx, refName, false)
+		assert.Nil(t, err)
+	})
+
+	t.Run("entry recorded in the RSL instead of the shard", func(t *testing.T) {
+		repo := createRepositoryWithShard(t)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+
+		err := repo.VerifyRef(testCtx, refName, false)
+		assert.ErrorIs(t, err, policy.ErrRSLShardMismatch)
+	})
+
+	t.Run("index entry records entries for refs outside the shard", func(t *testing.T) {
+		repo := createRepositoryWithShard(t)
+
+		tagRefName := "refs/tags/v1"
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, tagRefName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(tagRefName, commitIDs[0])
+		entry.Shard = "heads"
+		shardTip := common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(rsl.ShardRef("heads"), shardTip), gpgKeyBytes)
+
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+		entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.Shard = "heads"
+		shardTip = common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(rsl.ShardRef("heads"), shardTip), gpgKeyBytes)
+
+		// The verification of every ref includes the index entries
+		err := repo.VerifyRef(testCtx, refName, false)
+		assert.ErrorIs(t, err, policy.ErrRSLShardMismatch)
+	})
+
+	t.Run("index entry for a shard not in the policy", func(t *testing.T) {
+		repo := createRepositoryWithShard(t)
+
+		entry := rsl.NewReferenceEntry(refName, plumbing.ZeroHash)
+		entry.Shard = "unknown"
+		shardTip := common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(rsl.ShardRef("unknown"), shardTip), gpgKeyBytes)
+
+		err := repo.VerifyRef(testCtx, refName, false)
+		assert.ErrorIs(t, err, policy.ErrRSLShardNotFound)
+	})
+}
+
+func TestVerifyRefWithRefMapping(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	mirrorRefName := "refs/heads/upstream/main"
+	if err := repo.SetDelegationRefMappings(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", map[string]string{mirrorRefName: refName}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.ApplyPolicy(testCtx, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+
+	// The mirror stores the upstream ref under a different name
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(mirrorRefName), commitIDs[0])); err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.VerifyRef(testCtx, mirrorRefName, true)
+	assert.Nil(t, err)
+	err = repo.VerifyRef(testCtx, mirrorRefName, false)
+	assert.Nil(t, err)
+
+	// The mirror ref must match the upstream ref's latest entry
+	common.AddNTestCommitsToSpecifiedRef(t, repo.r, mirrorRefName, 1, gpgKeyBytes)
+	err = repo.VerifyRef(testCtx, mirrorRefName, true)
+	assert.ErrorIs(t, err, ErrRefStateDoesNotMatchRSL)
+}
+
+func TestVerifyRefKeepGoing(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	refName := "refs/heads/main"
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Policy violation
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgUnauthorizedKeyBytes)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	violationID := common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgUnauthorizedKeyBytes)
+
+	// Not policy violation by itself
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+	entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+
+	// The ref's tip is not recorded in the RSL
+	common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+
+	err := repo.VerifyRefKeepGoing(testCtx, refName)
+	assert.ErrorIs(t, err, policy.ErrUnauthorizedSignature)
+	assert.ErrorIs(t, err, ErrRefStateDoesNotMatchRSL)
+
+	violations := &policy.ErrPolicyViolations{}
+	if assert.ErrorAs(t, err, &violations) {
+		assert.Len(t, violations.Violations, 2)
+		assert.Equal(t, violationID, violations.Violations[0].EntryID)
+		assert.True(t, violations.Violations[1].EntryID.IsZero())
+	}
+}
+
+func TestVerifyRefReport(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	refName := "refs/heads/main"
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	validEntryID := common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+
+	policyEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo.r, policy.PolicyRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report.Enable()
+	defer report.Disable()
+
+	err = repo.VerifyRef(testCtx, refName, true)
+	assert.Nil(t, err)
+
+	verificationReport := report.Build(refName, err)
+	assert.True(t, verificationReport.Verified)
+	assert.Equal(t, 1, verificationReport.EntriesChecked)
+	assert.Equal(t, validEntryID.String(), verificationReport.Entries[0].ID)
+	assert.Equal(t, commitIDs[0].String(), verificationReport.Entries[0].TargetID)
+	if assert.Len(t, verificationReport.Entries[0].Signatures, 1) {
+		signature := verificationReport.Entries[0].Signatures[0]
+		assert.Equal(t, validEntryID.String(), signature.ObjectID)
+		assert.Equal(t, "protect-main", signature.Rule)
+		assert.Len(t, signature.KeyIDs, 1)
+	}
+	assert.Contains(t, verificationReport.PolicyStates, policyEntry.ID.String())
+	assert.Empty(t, verificationReport.Violations)
+
+	// Policy violation
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgUnauthorizedKeyBytes)
+	entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+	violationID := common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgUnauthorizedKeyBytes)
+
+	report.Enable()
+
+	err = repo.VerifyRefKeepGoing(testCtx, refName)
+	assert.ErrorIs(t, err, policy.ErrUnauthorizedSignature)
+
+	verificationReport = report.Build(refName, err)
+	assert.False(t, verificationReport.Verified)
+	assert.Equal(t, err.Error(), verificationReport.Error)
+	assert.Equal(t, 2, verificationReport.EntriesChecked)
+	if assert.Len(t, verificationReport.Violations, 1) {
+		assert.Equal(t, violationID.String(), verificationReport.Violations[0].EntryID)
+	}
+	assert.Empty(t, verificationReport.Entries[1].Signatures)
 }
 
 func TestVerifyRefFromEntry(t *testing.T) {
@@ -147,3 +464,88 @@ func TestVerifyRefFromEntry(t *testing.T) {
 	err = repo.VerifyRefFromEntry(testCtx, refName, violatingEntryID.String())
 	assert.ErrorIs(t, err, policy.ErrUnauthorizedSignature)
 }
+
+func TestVerifyRefRange(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	refName := "refs/heads/main"
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+	firstID := commitIDs[0]
+
+	rslRef, err := repo.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	currentRSLTip := rslRef.Hash()
+
+	// Record the next update in the RSL, then reset the RSL to mimic a
+	// pre-receive hook where the RSL update has not been applied yet
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+	entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+	receivedRSLTip := common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyBytes)
+	secondID := commitIDs[0]
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(rsl.Ref), currentRSLTip)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		target string
+		oldID  string
+		newID  string
+		rslTip string
+		err    error
+	}{
+		"ref creation, current RSL": {
+			target: "main",
+			oldID:  plumbing.ZeroHash.String(),
+			newID:  firstID.String(),
+		},
+		"ref update, current RSL": {
+			target: "refs/heads/main",
+			oldID:  plumbing.ZeroHash.String(),
+			newID:  secondID.String(),
+			err:    policy.ErrRangeNotInRSL,
+		},
+		"ref update, received RSL": {
+			target: "refs/heads/main",
+			oldID:  firstID.String(),
+			newID:  secondID.String(),
+			rslTip: receivedRSLTip.String(),
+		},
+		"ref update, received RSL is not fast-forward": {
+			target: "refs/heads/main",
+			oldID:  firstID.String(),
+			newID:  secondID.String(),
+			rslTip: secondID.String(),
+			err:    ErrRSLNotFastForward,
+		},
+		"invalid ID": {
+			target: "refs/heads/main",
+			oldID:  "main",
+			newID:  secondID.String(),
+			err:    ErrInvalidObjectID,
+		},
+	}
+
+	for name, test := range tests {
+		err := repo.VerifyRefRange(testCtx, test.target, test.oldID, test.newID, test.rslTip)
+		if test.err != nil {
+			assert.ErrorIs(t, err, test.err, fmt.Sprintf("unexpected error in test '%s'", name))
+		} else {
+			assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		}
+	}
+
+	// The RSL is not updated
+	rslRef, err = repo.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, currentRSLTip, rslRef.Hash())
+}
@@ -0,0 +1,266 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rsl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var (
+	ErrInvalidBackendRecord = errors.New("invalid RSL backend record")
+	ErrBackendDiverged      = errors.New("RSL backend does not match the RSL")
+	ErrBackendRequestFailed = errors.New("request to RSL backend failed")
+)
+
+// Backend is an append-only log the RSL is mirrored into, in addition to the
+// RSL ref. As each record holds an RSL entry's ID and commit message, a
+// backend is a tamper-evident copy of the RSL outside the repository that can
+// be queried without Git.
+type Backend interface {
+	// Records returns the records in the backend, in the order they were
+	// appended.
+	Records(ctx context.Context) ([]*BackendRecord, error)
+
+	// Append adds the records to the end of the backend. The number of the
+	// first record must follow the number of the backend's last record.
+	Append(ctx context.Context, records []*BackendRecord) error
+}
+
+// BackendRecord is an RSL entry mirrored into a backend.
+type BackendRecord struct {
+	// Number is the position of the entry in the RSL, starting at 1 for the
+	// first entry.
+	Number uint64 `json:"number"`
+
+	// EntryID is the ID of the entry's commit in the RSL.
+	EntryID string `json:"entryID"`
+
+	// Message is the entry's commit message, which records the entry's
+	// contents.
+	Message string `json:"message"`
+}
+
+// NewBackendRecord returns the record for the specified RSL entry at the
+// specified position in the RSL.
+func NewBackendRecord(repo *git.Repository, number uint64, entryID plumbing.Hash) (*BackendRecord, error) {
+	commit, err := gitinterface.GetCommit(repo, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BackendRecord{Number: number, EntryID: entryID.String(), Message: commit.Message}, nil
+}
+
+// NewBackend returns the backend at the specified location. HTTP and HTTPS
+// URLs identify remote logs, while other locations are paths to local log
+// files.
+func NewBackend(location string) (Backend, error) {
+	if location == "" {
+		return nil, fmt.Errorf("RSL backend location must be specified")
+	}
+
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return &HTTPBackend{URL: location, Client: http.DefaultClient}, nil
+	}
+
+	return &FileBackend{Path: location}, nil
+}
+
+// FileBackend is a backend stored in a local file, with one JSON encoded record
+// per line. Records are only ever appended to the file.
+type FileBackend struct {
+	Path string
+}
+
+// Records returns the records in the file. A file that does not exist has no
+// records.
+func (f *FileBackend) Records(_ context.Context) ([]*BackendRecord, error) {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []*BackendRecord{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	records := []*BackendRecord{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		record := &BackendRecord{}
+		if err := json.Unmarshal(line, record); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidBackendRecord, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := validateBackendRecords(records, 1); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// Append adds the records to the end of the file, creating it if necessary.
+func (f *FileBackend) Append(ctx context.Context, records []*BackendRecord) error {
+	existingRecords, err := f.Records(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := validateBackendRecords(records, uint64(len(existingRecords))+1); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0o755); err != nil {
+		return err
+	}
+
+	contents := []byte{}
+	for _, record := range records {
+		recordBytes, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		contents = append(contents, recordBytes...)
+		contents = append(contents, '\n')
+	}
+
+	file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(contents); err != nil {
+		file.Close() //nolint:errcheck
+		return err
+	}
+
+	return file.Close()
+}
+
+// HTTPBackend is a backend served by a remote log. A GET request to the URL
+// returns the log's records as a JSON array, and a POST request with a JSON
+// array of records appends them. The log is expected to reject records whose
+// numbers don't follow its last record.
+type HTTPBackend struct {
+	URL    string
+	Client *http.Client
+}
+
+// Records returns the records in the remote log.
+func (h *HTTPBackend) Records(ctx context.Context) ([]*BackendRecord, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, h.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/json")
+
+	responseBody, err := h.do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []*BackendRecord{}
+	if err := json.Unmarshal(responseBody, &records); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBackendRecord, err)
+	}
+
+	if err := validateBackendRecords(records, 1); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// Append sends the records to the remote log.
+func (h *HTTPBackend) Append(ctx context.Context, records []*BackendRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	if err := validateBackendRecords(records, records[0].Number); err != nil {
+		return err
+	}
+
+	recordsBytes, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(recordsBytes))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	_, err = h.do(request)
+	return err
+}
+
+func (h *HTTPBackend) do(request *http.Request) ([]byte, error) {
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close() //nolint:errcheck
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("%w: %s %s returned %d: %s", ErrBackendRequestFailed, request.Method, h.URL, response.StatusCode, strings.TrimSpace(string(responseBody)))
+	}
+
+	return responseBody, nil
+}
+
+// validateBackendRecords checks that the records are numbered consecutively
+// from the specified number, and that each identifies an entry.
+func validateBackendRecords(records []*BackendRecord, firstNumber uint64) error {
+	for i, record := range records {
+		if record == nil {
+			return fmt.Errorf("%w: record is empty", ErrInvalidBackendRecord)
+		}
+
+		if record.Number != firstNumber+uint64(i) {
+			return fmt.Errorf("%w: expected record number %d, found %d", ErrInvalidBackendRecord, firstNumber+uint64(i), record.Number)
+		}
+
+		if !plumbing.IsHash(record.EntryID) {
+			return fmt.Errorf("%w: record %d has invalid entry ID '%s'", ErrInvalidBackendRecord, record.Number, record.EntryID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rsl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBackend(t *testing.T) {
+	backend, err := NewBackend("https://rsl.example.com/log")
+	assert.Nil(t, err)
+	assert.IsType(t, &HTTPBackend{}, backend)
+
+	backend, err = NewBackend("rsl.log")
+	assert.Nil(t, err)
+	assert.Equal(t, &FileBackend{Path: "rsl.log"}, backend)
+
+	_, err = NewBackend("")
+	assert.NotNil(t, err)
+}
+
+func TestFileBackend(t *testing.T) {
+	backend := &FileBackend{Path: filepath.Join(t.TempDir(), "rsl", "log.jsonl")}
+	records := createTestBackendRecords(3)
+
+	existingRecords, err := backend.Records(context.Background())
+	assert.Nil(t, err)
+	assert.Empty(t, existingRecords)
+
+	err = backend.Append(context.Background(), records[:2])
+	assert.Nil(t, err)
+
+	err = backend.Append(context.Background(), records[2:])
+	assert.Nil(t, err)
+
+	existingRecords, err = backend.Records(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, records, existingRecords)
+
+	t.Run("append out of order", func(t *testing.T) {
+		err := backend.Append(context.Background(), records[1:2])
+		assert.ErrorIs(t, err, ErrInvalidBackendRecord)
+
+		existingRecords, err := backend.Records(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, records, existingRecords)
+	})
+}
+
+func TestHTTPBackend(t *testing.T) {
+	var mu sync.Mutex
+	log := []*BackendRecord{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(log) //nolint:errcheck
+		case http.MethodPost:
+			records := []*BackendRecord{}
+			if err := json.NewDecoder(r.Body).Decode(&records); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if len(records) > 0 && records[0].Number != uint64(len(log))+1 {
+				http.Error(w, "unexpected record number", http.StatusConflict)
+				return
+			}
+			log = append(log, records...)
+		}
+	}))
+	defer server.Close()
+
+	backend := &HTTPBackend{URL: server.URL, Client: server.Client()}
+	records := createTestBackendRecords(3)
+
+	existingRecords, err := backend.Records(context.Background())
+	assert.Nil(t, err)
+	assert.Empty(t, existingRecords)
+
+	err = backend.Append(context.Background(), records)
+	assert.Nil(t, err)
+
+	existingRecords, err = backend.Records(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, records, existingRecords)
+
+	t.Run("rejected by remote log", func(t *testing.T) {
+		err := backend.Append(context.Background(), records[:1])
+		assert.ErrorIs(t, err, ErrBackendRequestFailed)
+	})
+}
+
+func createTestBackendRecords(n int) []*BackendRecord {
+	records := make([]*BackendRecord, 0, n)
+	for i := 0; i < n; i++ {
+		entryID := plumbing.ComputeHash(plumbing.BlobObject, []byte{byte(i)})
+		records = append(records, &BackendRecord{
+			Number:  uint64(i) + 1,
+			EntryID: entryID.String(),
+			Message: "RSL Reference Entry\n\nref: refs/heads/main\ntargetID: " + entryID.String(),
+		})
+	}
+
+	return records
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rsl
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// Iterator walks the RSL from the latest entry to the first entry. Entries are
+// loaded one at a time as the iterator advances, so walking the RSL does not
+// require holding its full history in memory.
+type Iterator struct {
+	repo   *git.Repository
+	nextID plumbing.Hash
+	err    error
+}
+
+// NewIterator returns an iterator positioned at the latest entry in the RSL.
+// If the RSL has not been created yet, ErrRSLEntryNotFound is returned.
+func NewIterator(repo *git.Repository) (*Iterator, error) {
+	ref, err := repo.Reference(plumbing.ReferenceName(Ref), true)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil, ErrRSLEntryNotFound
+		}
+		return nil, err
+	}
+
+	return &Iterator{repo: repo, nextID: ref.Hash()}, nil
+}
+
+// Seek positions the iterator so that the next call to Next returns the entry
+// with the specified ID. Iteration then continues from that entry towards the
+// first entry in the RSL.
+func (it *Iterator) Seek(entryID plumbing.Hash) {
+	it.nextID = entryID
+	it.err = nil
+}
+
+// Next returns the next entry, i.e., the parent of the previously returned
+// entry. Once the first entry in the RSL has been returned, io.EOF is
+// returned.
+func (it *Iterator) Next() (Entry, error) {
+	if it.err != nil {
+		return nil, it.err
+	}
+	if it.nextID.IsZero() {
+		return nil, io.EOF
+	}
+
+	commitObj, err := gitinterface.GetCommit(it.repo, it.nextID)
+	if err != nil {
+		return nil, ErrRSLEntryNotFound
+	}
+
+	entry, err := loadRSLEntry(it.repo, commitObj.Hash, commitObj.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(commitObj.ParentHashes) {
+	case 0:
+		it.nextID = plumbing.ZeroHash
+	case 1:
+		it.nextID = commitObj.ParentHashes[0]
+	default:
+		// The entry itself is valid, but the RSL can't be walked past it
+		it.err = ErrRSLBranchDetected
+	}
+
+	return entry, nil
+}
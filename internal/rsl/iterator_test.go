@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rsl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIterator(t *testing.T) {
+	t.Run("no RSL", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = NewIterator(repo)
+		assert.ErrorIs(t, err, ErrRSLEntryNotFound)
+	})
+
+	t.Run("walk and seek", func(t *testing.T) {
+		repo := createTestRSL(t, 5)
+
+		iterator, err := NewIterator(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entryIDs := []plumbing.Hash{}
+		for {
+			entry, err := iterator.Next()
+			if err != nil {
+				assert.ErrorIs(t, err, io.EOF)
+				break
+			}
+			entryIDs = append(entryIDs, entry.GetID())
+		}
+		assert.Len(t, entryIDs, 5)
+
+		latestEntry, err := GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, latestEntry.GetID(), entryIDs[0])
+
+		firstEntry, _, err := GetFirstEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, firstEntry.GetID(), entryIDs[4])
+
+		// Calling Next after the end of the RSL continues to return io.EOF
+		_, err = iterator.Next()
+		assert.ErrorIs(t, err, io.EOF)
+
+		iterator.Seek(entryIDs[2])
+		entry, err := iterator.Next()
+		assert.Nil(t, err)
+		assert.Equal(t, entryIDs[2], entry.GetID())
+		entry, err = iterator.Next()
+		assert.Nil(t, err)
+		assert.Equal(t, entryIDs[3], entry.GetID())
+
+		iterator.Seek(plumbing.NewHash("abcdef12345678"))
+		_, err = iterator.Next()
+		assert.ErrorIs(t, err, ErrRSLEntryNotFound)
+	})
+}
+
+// BenchmarkIterator walks RSLs of increasing length using the iterator and
+// reports the heap memory retained while walking. As entries are loaded one at
+// a time, the retained memory does not grow with the length of the RSL.
+func BenchmarkIterator(b *testing.B) {
+	for _, numEntries := range []int{100, 1000, 10000} {
+		b.Run(fmt.Sprintf("entries=%d", numEntries), func(b *testing.B) {
+			repo := createTestRSL(b, numEntries)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			var retained uint64
+			for i := 0; i < b.N; i++ {
+				iterator, err := NewIterator(repo)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				baseline := heapInUse()
+				for {
+					_, err := iterator.Next()
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatal(err)
+					}
+				}
+				if after := heapInUse(); after > baseline {
+					retained += after - baseline
+				}
+			}
+
+			b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+		})
+	}
+}
+
+// BenchmarkGetReferenceEntriesInRange loads RSLs of increasing length into
+// memory for comparison with BenchmarkIterator.
+func BenchmarkGetReferenceEntriesInRange(b *testing.B) {
+	for _, numEntries := range []int{100, 1000, 10000} {
+		b.Run(fmt.Sprintf("entries=%d", numEntries), func(b *testing.B) {
+			repo := createTestRSL(b, numEntries)
+
+			firstEntry, _, err := GetFirstEntry(repo)
+			if err != nil {
+				b.Fatal(err)
+			}
+			latestEntry, err := GetLatestEntry(repo)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			var retained uint64
+			for i := 0; i < b.N; i++ {
+				baseline := heapInUse()
+				entries, _, err := GetReferenceEntriesInRange(repo, firstEntry.GetID(), latestEntry.GetID())
+				if err != nil {
+					b.Fatal(err)
+				}
+				if after := heapInUse(); after > baseline {
+					retained += after - baseline
+				}
+				runtime.KeepAlive(entries)
+			}
+
+			b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+		})
+	}
+}
+
+func createTestRSL(t testing.TB, numEntries int) *git.Repository {
+	t.Helper()
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < numEntries; i++ {
+		if err := NewReferenceEntry(fmt.Sprintf("refs/heads/%d", i), plumbing.ZeroHash).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return repo
+}
+
+// heapInUse returns the heap memory in use after a garbage collection, i.e.,
+// the memory that is still reachable.
+func heapInUse() uint64 {
+	runtime.GC()
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
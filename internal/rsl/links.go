@@ -0,0 +1,635 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rsl
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	NumberKey               = "number"
+	PreviousEntryIDKey      = "previousEntryID"
+	PreviousAnnotationIDKey = "previousAnnotationID"
+	CheckpointKey           = "checkpoint"
+	CheckpointEntryKey      = "checkpointEntry"
+
+	// CheckpointInterval is how often entries record a checkpoint: every
+	// entry whose number is a multiple of the interval is a checkpoint.
+	CheckpointInterval = 64
+)
+
+// errEntryNotLinked is returned when a lookup using links reaches an entry
+// that does not record links or whose links are incorrect, in which case the RSL must be walked one entry
+// at a time instead.
+var errEntryNotLinked = errors.New("RSL entry does not record links")
+
+// Links contains pointers from an RSL entry to earlier entries in the RSL.
+// They allow lookups for a ref to jump directly between the ref's entries
+// rather than walking every entry in the RSL. Links are recorded when an entry
+// is committed. Entries created by older versions of gittuf do not have links,
+// lookups that reach such entries fall back to walking the RSL, and the first
+// entry committed to such an RSL walks it once to create its links. Links are
+// only followed once they've been checked against the RSL, lookups that reach
+// entries with incorrect links also fall back to walking the RSL.
+type Links struct {
+	// Number is the entry's position in the RSL, starting with 1 for the
+	// first entry.
+	Number int
+
+	// PreviousEntryID is the ID of the latest reference entry for the same
+	// ref that precedes the entry, or the zero hash if there is none. It is
+	// only set for reference entries.
+	PreviousEntryID plumbing.Hash
+
+	// PreviousAnnotationID is the ID of the latest annotation entry that
+	// precedes the entry, or the zero hash if there is none. Annotations
+	// therefore form a chain that can be walked without visiting other
+	// entries.
+	PreviousAnnotationID plumbing.Hash
+
+	// Checkpoint maps each ref to the ID of the latest reference entry for
+	// the ref that precedes the entry. It is only set for checkpoint entries,
+	// which are recorded every CheckpointInterval entries.
+	Checkpoint map[string]plumbing.Hash
+}
+
+func (l *Links) lines(isReferenceEntry bool) []string {
+	lines := []string{fmt.Sprintf("%s: %d", NumberKey, l.Number)}
+	if isReferenceEntry {
+		lines = append(lines, fmt.Sprintf("%s: %s", PreviousEntryIDKey, l.PreviousEntryID.String()))
+	}
+	lines = append(lines, fmt.Sprintf("%s: %s", PreviousAnnotationIDKey, l.PreviousAnnotationID.String()))
+
+	if l.Checkpoint != nil {
+		refNames := make([]string, 0, len(l.Checkpoint))
+		for refName := range l.Checkpoint {
+			refNames = append(refNames, refName)
+		}
+		sort.Strings(refNames)
+
+		// As with changed paths, the count distinguishes an empty checkpoint
+		// from entries that are not checkpoints. Ref names cannot contain
+		// spaces, so they're recorded after the entry ID.
+		lines = append(lines, fmt.Sprintf("%s: %d", CheckpointKey, len(refNames)))
+		for _, refName := range refNames {
+			lines = append(lines, fmt.Sprintf("%s: %s %s", CheckpointEntryKey, l.Checkpoint[refName].String(), refName))
+		}
+	}
+
+	return lines
+}
+
+// linksParser accumulates the links recorded in an entry's text.
+type linksParser struct {
+	links                 *Links
+	hasNumber             bool
+	hasPreviousEntry      bool
+	hasPreviousAnnotation bool
+	checkpointCount       int
+	checkpointEntryIDs    map[string]plumbing.Hash
+}
+
+func newLinksParser() *linksParser {
+	return &linksParser{links: &Links{}, checkpointCount: -1}
+}
+
+// parse handles the key and value if they record a link, returning false if
+// they do not.
+func (p *linksParser) parse(key, value string) (bool, error) {
+	switch key {
+	case NumberKey:
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 1 {
+			return true, ErrInvalidRSLEntry
+		}
+		p.links.Number = number
+		p.hasNumber = true
+	case PreviousEntryIDKey:
+		previousEntryID, err := parseHash(value)
+		if err != nil {
+			return true, err
+		}
+		p.links.PreviousEntryID = previousEntryID
+		p.hasPreviousEntry = true
+	case PreviousAnnotationIDKey:
+		previousAnnotationID, err := parseHash(value)
+		if err != nil {
+			return true, err
+		}
+		p.links.PreviousAnnotationID = previousAnnotationID
+		p.hasPreviousAnnotation = true
+	case CheckpointKey:
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 0 {
+			return true, ErrInvalidRSLEntry
+		}
+		p.checkpointCount = count
+	case CheckpointEntryKey:
+		entryID, refName, found := strings.Cut(value, " ")
+		if !found || len(refName) == 0 || !plumbing.IsHash(entryID) {
+			return true, ErrInvalidRSLEntry
+		}
+		if p.checkpointEntryIDs == nil {
+			p.checkpointEntryIDs = map[string]plumbing.Hash{}
+		}
+		p.checkpointEntryIDs[refName] = plumbing.NewHash(entryID)
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// result returns the parsed links, or nil if the entry does not record links.
+// An entry must record all its links or none of them.
+func (p *linksParser) result(isReferenceEntry bool) (*Links, error) {
+	if !p.hasNumber && !p.hasPreviousEntry && !p.hasPreviousAnnotation && p.checkpointCount == -1 && p.checkpointEntryIDs == nil {
+		return nil, nil
+	}
+
+	if !p.hasNumber || !p.hasPreviousAnnotation || p.hasPreviousEntry != isReferenceEntry {
+		return nil, ErrInvalidRSLEntry
+	}
+
+	if p.checkpointCount != -1 {
+		if p.checkpointCount != len(p.checkpointEntryIDs) {
+			return nil, ErrInvalidRSLEntry
+		}
+		p.links.Checkpoint = p.checkpointEntryIDs
+		if p.links.Checkpoint == nil {
+			p.links.Checkpoint = map[string]plumbing.Hash{}
+		}
+	} else if p.checkpointEntryIDs != nil {
+		// Checkpoint entries without a count are not a complete record
+		return nil, ErrInvalidRSLEntry
+	}
+
+	return p.links, nil
+}
+
+// newLinks returns the links for a new entry added to the RSL. For reference
+// entries, the caller must also set the PreviousEntryID. The links are
+// computed from the RSL rather than copied from the latest entry, so that an
+// entry with incorrect links doesn't affect the links of later entries.
+func newLinks(repo *git.Repository) (*Links, error) {
+	latestEntry, err := GetLatestEntry(repo)
+	if err != nil {
+		if errors.Is(err, ErrRSLEntryNotFound) {
+			// This is the first entry in the RSL
+			return &Links{Number: 1}, nil
+		}
+		return nil, err
+	}
+
+	latestCheck, err := checkLinks(repo, latestEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	links := &Links{
+		Number:               latestCheck.number + 1,
+		PreviousAnnotationID: latestCheck.lastAnnotationID,
+	}
+
+	if links.Number%CheckpointInterval == 0 {
+		links.Checkpoint, err = createCheckpoint(repo, latestEntry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return links, nil
+}
+
+// createCheckpoint returns the latest reference entry for each ref at or
+// before the specified entry. The RSL is walked back to the previous
+// checkpoint whose links are correct, so this is bounded by
+// CheckpointInterval once the RSL has a checkpoint.
+func createCheckpoint(repo *git.Repository, entry Entry) (map[string]plumbing.Hash, error) {
+	checkpoint := map[string]plumbing.Hash{}
+
+	for {
+		if referenceEntry, isReferenceEntry := entry.(*ReferenceEntry); isReferenceEntry {
+			if _, has := checkpoint[referenceEntry.RefName]; !has {
+				checkpoint[referenceEntry.RefName] = referenceEntry.ID
+			}
+		}
+
+		links, err := getCheckedLinks(repo, entry)
+		if err != nil {
+			return nil, err
+		}
+		if links != nil && links.Checkpoint != nil {
+			for refName, entryID := range links.Checkpoint {
+				if _, has := checkpoint[refName]; !has {
+					checkpoint[refName] = entryID
+				}
+			}
+			return checkpoint, nil
+		}
+
+		parentEntry, err := GetParentForEntry(repo, entry)
+		if err != nil {
+			if errors.Is(err, ErrRSLEntryNotFound) {
+				return checkpoint, nil
+			}
+			return nil, err
+		}
+		entry = parentEntry
+	}
+}
+
+// getLatestReferenceEntryForRefBeforeUsingLinks implements
+// GetLatestReferenceEntryForRefBefore using the entries' links. If an entry
+// that must be visited does not have links, or its links don't match the RSL,
+// errEntryNotLinked is returned.
+func getLatestReferenceEntryForRefBeforeUsingLinks(repo *git.Repository, refName string, anchor plumbing.Hash) (*ReferenceEntry, []*AnnotationEntry, error) {
+	latestEntry, err := GetLatestEntry(repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	latestLinks, err := getCheckedLinks(repo, latestEntry)
+	if err != nil {
+		return nil, nil, err
+	}
+	if latestLinks == nil {
+		return nil, nil, errEntryNotLinked
+	}
+
+	var targetEntry *ReferenceEntry
+	if anchor.IsZero() {
+		targetEntry, err = findReferenceEntryForRefUsingLinks(repo, latestEntry, refName)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		if !isCheckedEntryAtOrBefore(repo, anchor, latestEntry.GetID()) {
+			// The anchor is not in the checked part of the RSL, walking the
+			// RSL determines whether it exists
+			return nil, nil, errEntryNotLinked
+		}
+
+		anchorEntry, err := GetEntry(repo, anchor)
+		if err != nil {
+			return nil, nil, err
+		}
+		anchorLinks, err := getCheckedLinks(repo, anchorEntry)
+		if err != nil {
+			return nil, nil, err
+		}
+		if anchorLinks == nil {
+			return nil, nil, errEntryNotLinked
+		}
+
+		if entry, isReferenceEntry := anchorEntry.(*ReferenceEntry); isReferenceEntry && entry.RefName == refName {
+			// The anchor links directly to the previous entry for the ref
+			if anchorLinks.PreviousEntryID.IsZero() {
+				return nil, nil, ErrRSLEntryNotFound
+			}
+			targetEntry, err = getLinkedReferenceEntryForRef(repo, anchorLinks.PreviousEntryID, refName)
+		} else {
+			var parentEntry Entry
+			parentEntry, err = GetParentForEntry(repo, anchorEntry)
+			if err != nil {
+				return nil, nil, err
+			}
+			targetEntry, err = findReferenceEntryForRefUsingLinks(repo, parentEntry, refName)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Annotations for the target entry must follow it, so we walk the chain
+	// of annotations from the latest entry back to the target entry
+	allAnnotations := []*AnnotationEntry{}
+	annotationID := previousAnnotationIDIncluding(latestEntry)
+	for !annotationID.IsZero() && annotationID != targetEntry.Links.PreviousAnnotationID {
+		entry, err := GetEntry(repo, annotationID)
+		if err != nil {
+			return nil, nil, err
+		}
+		annotation, isAnnotation := entry.(*AnnotationEntry)
+		if !isAnnotation {
+			return nil, nil, ErrInvalidRSLEntry
+		}
+		annotationLinks, err := getCheckedLinks(repo, annotation)
+		if err != nil {
+			return nil, nil, err
+		}
+		if annotationLinks == nil {
+			return nil, nil, errEntryNotLinked
+		}
+
+		allAnnotations = append(allAnnotations, annotation)
+		annotationID = annotationLinks.PreviousAnnotationID
+	}
+
+	annotations := filterAnnotationsForRelevantAnnotations(allAnnotations, targetEntry.ID)
+
+	return targetEntry, annotations, nil
+}
+
+// findReferenceEntryForRefUsingLinks returns the latest reference entry for
+// the ref at or before the specified entry. The RSL is walked until an entry
+// for the ref or a checkpoint is found.
+func findReferenceEntryForRefUsingLinks(repo *git.Repository, entry Entry, refName string) (*ReferenceEntry, error) {
+	for {
+		links, err := getCheckedLinks(repo, entry)
+		if err != nil {
+			return nil, err
+		}
+		if links == nil {
+			return nil, errEntryNotLinked
+		}
+
+		if referenceEntry, isReferenceEntry := entry.(*ReferenceEntry); isReferenceEntry && referenceEntry.RefName == refName {
+			return referenceEntry, nil
+		}
+
+		if links.Checkpoint != nil {
+			entryID, has := links.Checkpoint[refName]
+			if !has {
+				return nil, ErrRSLEntryNotFound
+			}
+			return getLinkedReferenceEntryForRef(repo, entryID, refName)
+		}
+
+		parentEntry, err := GetParentForEntry(repo, entry)
+		if err != nil {
+			return nil, err
+		}
+		entry = parentEntry
+	}
+}
+
+// getLinkedReferenceEntryForRef loads the reference entry that a link points
+// to, checking that it is for the expected ref and has correct links itself.
+func getLinkedReferenceEntryForRef(repo *git.Repository, entryID plumbing.Hash, refName string) (*ReferenceEntry, error) {
+	entry, err := GetEntry(repo, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	referenceEntry, isReferenceEntry := entry.(*ReferenceEntry)
+	if !isReferenceEntry || referenceEntry.RefName != refName {
+		return nil, ErrRSLEntryDoesNotMatchRef
+	}
+
+	links, err := getCheckedLinks(repo, referenceEntry)
+	if err != nil {
+		return nil, err
+	}
+	if links == nil {
+		return nil, errEntryNotLinked
+	}
+
+	return referenceEntry, nil
+}
+
+// previousAnnotationIDIncluding returns the ID of the latest annotation at or
+// before the entry, which must have links.
+func previousAnnotationIDIncluding(entry Entry) plumbing.Hash {
+	if _, isAnnotation := entry.(*AnnotationEntry); isAnnotation {
+		return entry.GetID()
+	}
+	return entry.GetLinks().PreviousAnnotationID
+}
+
+// linkCheck records an entry's position in the RSL, computed by walking the
+// RSL, and whether the links recorded in the entry match the RSL.
+type linkCheck struct {
+	number int
+
+	// lastAnnotationID is the ID of the latest annotation at or before the
+	// entry.
+	lastAnnotationID plumbing.Hash
+
+	// valid is true if the entry's links match the RSL or it doesn't have
+	// links.
+	valid bool
+}
+
+// linkChecker caches the results of checking a repository's entries' links
+// against its RSL, so that lookups only follow links that are correct. Links
+// are recorded by whoever creates an entry and aren't covered by verification,
+// so following incorrect links could hide entries. An entry's ID determines
+// every entry before it, so results remain correct for the lifetime of the
+// process. The cache only holds entries at or before the tip, the latest entry
+// checked.
+type linkChecker struct {
+	mu     sync.Mutex
+	checks map[plumbing.Hash]*linkCheck
+
+	tipID plumbing.Hash
+
+	// tipEntryIDs maps each ref to the latest reference entry for it at or
+	// before the tip.
+	tipEntryIDs map[string]plumbing.Hash
+}
+
+// maxLinkCheckers bounds the number of repositories whose link checks are
+// cached at once. When a checker is needed for another repository, the least
+// recently used checker is dropped.
+const maxLinkCheckers = 8
+
+// linkCheckers holds the link checker for each repository, so checks are only
+// shared by lookups in the same repository. The lock is only held while a
+// checker is looked up, each checker has its own lock.
+var linkCheckers = &linkCheckerRegistry{checkers: map[*git.Repository]*linkChecker{}}
+
+type linkCheckerRegistry struct {
+	mu       sync.Mutex
+	checkers map[*git.Repository]*linkChecker
+
+	// repos lists the repositories with checkers, from least to most
+	// recently used.
+	repos []*git.Repository
+}
+
+// getLinkChecker returns the link checker for the repository, creating it if
+// necessary.
+func getLinkChecker(repo *git.Repository) *linkChecker {
+	linkCheckers.mu.Lock()
+	defer linkCheckers.mu.Unlock()
+
+	for i, other := range linkCheckers.repos {
+		if other == repo {
+			linkCheckers.repos = append(linkCheckers.repos[:i], linkCheckers.repos[i+1:]...)
+			break
+		}
+	}
+	linkCheckers.repos = append(linkCheckers.repos, repo)
+
+	checker, has := linkCheckers.checkers[repo]
+	if !has {
+		checker = &linkChecker{checks: map[plumbing.Hash]*linkCheck{}}
+		linkCheckers.checkers[repo] = checker
+	}
+
+	if len(linkCheckers.repos) > maxLinkCheckers {
+		delete(linkCheckers.checkers, linkCheckers.repos[0])
+		linkCheckers.repos = linkCheckers.repos[1:]
+	}
+
+	return checker
+}
+
+// checkLinks checks the links of the entry and every entry before it against
+// the repository's RSL. Only entries after an entry checked earlier are
+// walked: the walk stops at the tip of earlier checks or at a checked
+// checkpoint whose links are correct. Otherwise, the RSL is walked back to
+// its first entry.
+func checkLinks(repo *git.Repository, entry Entry) (*linkCheck, error) {
+	return getLinkChecker(repo).check(repo, entry)
+}
+
+func (c *linkChecker) check(repo *git.Repository, entry Entry) (*linkCheck, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if check, has := c.checks[entry.GetID()]; has {
+		return check, nil
+	}
+
+	// Walk back to an entry that the checks can resume from, or the first
+	// entry
+	var (
+		pending          = []Entry{}
+		number           int
+		lastAnnotationID plumbing.Hash
+		entryIDs         = map[string]plumbing.Hash{}
+	)
+	for current := entry; ; {
+		if !c.tipID.IsZero() && current.GetID() == c.tipID {
+			tipCheck := c.checks[c.tipID]
+			number = tipCheck.number
+			lastAnnotationID = tipCheck.lastAnnotationID
+			entryIDs = c.tipEntryIDs
+			break
+		}
+
+		if check, has := c.checks[current.GetID()]; has && check.valid && current.GetLinks() != nil && current.GetLinks().Checkpoint != nil {
+			// The checkpoint records the latest entry for each ref before
+			// it, so the checks resume after it
+			number = check.number
+			lastAnnotationID = check.lastAnnotationID
+			maps.Copy(entryIDs, current.GetLinks().Checkpoint)
+			if referenceEntry, isReferenceEntry := current.(*ReferenceEntry); isReferenceEntry {
+				entryIDs[referenceEntry.RefName] = referenceEntry.ID
+			}
+			break
+		}
+
+		pending = append(pending, current)
+
+		parentEntry, err := GetParentForEntry(repo, current)
+		if err != nil {
+			if errors.Is(err, ErrRSLEntryNotFound) {
+				break
+			}
+			return nil, err
+		}
+		current = parentEntry
+	}
+
+	for i := len(pending) - 1; i >= 0; i-- {
+		current := pending[i]
+		number++
+
+		check := &linkCheck{number: number, valid: true}
+		referenceEntry, isReferenceEntry := current.(*ReferenceEntry)
+		if links := current.GetLinks(); links != nil {
+			check.valid = links.Number == number && links.PreviousAnnotationID == lastAnnotationID
+			if isReferenceEntry && links.PreviousEntryID != entryIDs[referenceEntry.RefName] {
+				check.valid = false
+			}
+			if links.Checkpoint != nil && !maps.Equal(links.Checkpoint, entryIDs) {
+				check.valid = false
+			}
+
+			if !check.valid {
+				slog.Debug(fmt.Sprintf("Links in RSL entry '%s' do not match the RSL, ignoring them...", current.GetID().String()))
+			}
+		}
+
+		if isReferenceEntry {
+			entryIDs[referenceEntry.RefName] = referenceEntry.ID
+		}
+		if _, isAnnotation := current.(*AnnotationEntry); isAnnotation {
+			lastAnnotationID = current.GetID()
+		}
+		check.lastAnnotationID = lastAnnotationID
+
+		c.checks[current.GetID()] = check
+	}
+
+	c.tipID = entry.GetID()
+	c.tipEntryIDs = entryIDs
+
+	return c.checks[entry.GetID()], nil
+}
+
+// getCheckedLinks returns the entry's links if they match the RSL. If the
+// entry doesn't have links or they're incorrect, nil is returned.
+func getCheckedLinks(repo *git.Repository, entry Entry) (*Links, error) {
+	check, err := checkLinks(repo, entry)
+	if err != nil {
+		return nil, err
+	}
+	if !check.valid {
+		return nil, nil
+	}
+
+	return entry.GetLinks(), nil
+}
+
+// isCheckedEntryAtOrBefore returns true if both entries have been checked and
+// the entry is the other entry or precedes it in the RSL.
+func isCheckedEntryAtOrBefore(repo *git.Repository, entryID, otherEntryID plumbing.Hash) bool {
+	checker := getLinkChecker(repo)
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+
+	check, has := checker.checks[entryID]
+	if !has {
+		return false
+	}
+	otherCheck, has := checker.checks[otherEntryID]
+	if !has {
+		return false
+	}
+
+	return check.number <= otherCheck.number
+}
+
+// IsEntryBefore returns true if the entry precedes the other entry in the RSL.
+// The entries' positions are determined by walking the RSL rather than using
+// the numbers recorded in their links, and the walk is cached.
+func IsEntryBefore(repo *git.Repository, entryID, otherEntryID plumbing.Hash) (bool, error) {
+	if entryID == otherEntryID {
+		return false, nil
+	}
+
+	otherEntry, err := GetEntry(repo, otherEntryID)
+	if err != nil {
+		return false, err
+	}
+
+	// Checking the other entry checks every entry before it
+	if _, err := checkLinks(repo, otherEntry); err != nil {
+		return false, err
+	}
+
+	return isCheckedEntryAtOrBefore(repo, entryID, otherEntryID), nil
+}
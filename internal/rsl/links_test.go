@@ -0,0 +1,463 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rsl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinks(t *testing.T) {
+	t.Run("links are recorded", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		firstMainEntry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+		featureEntry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash))
+		annotation := commitTestEntry(t, repo, NewAnnotationEntry([]plumbing.Hash{featureEntry.GetID()}, false, annotationMessage))
+		secondMainEntry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+		propagationEntry := commitTestEntry(t, repo, NewPropagationEntry("https://example.com/upstream.git", firstMainEntry.GetID()))
+
+		assert.Equal(t, &Links{Number: 1}, firstMainEntry.GetLinks())
+		assert.Equal(t, &Links{Number: 2}, featureEntry.GetLinks())
+		assert.Equal(t, &Links{Number: 3}, annotation.GetLinks())
+		assert.Equal(t, &Links{Number: 4, PreviousEntryID: firstMainEntry.GetID(), PreviousAnnotationID: annotation.GetID()}, secondMainEntry.GetLinks())
+		assert.Equal(t, &Links{Number: 5, PreviousAnnotationID: annotation.GetID()}, propagationEntry.GetLinks())
+	})
+
+	t.Run("checkpoints", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		rareEntry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/rare", plumbing.ZeroHash))
+		mainEntryIDs := []plumbing.Hash{}
+		for i := 1; i < 2*CheckpointInterval; i++ {
+			mainEntryIDs = append(mainEntryIDs, commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash)).GetID())
+		}
+
+		firstCheckpointEntry, err := GetEntry(repo, mainEntryIDs[CheckpointInterval-2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, CheckpointInterval, firstCheckpointEntry.GetLinks().Number)
+		assert.Equal(t, map[string]plumbing.Hash{
+			"refs/heads/rare": rareEntry.GetID(),
+			"refs/heads/main": mainEntryIDs[CheckpointInterval-3],
+		}, firstCheckpointEntry.GetLinks().Checkpoint)
+
+		// The second checkpoint builds on the first
+		secondCheckpointEntry, err := GetEntry(repo, mainEntryIDs[2*CheckpointInterval-2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, map[string]plumbing.Hash{
+			"refs/heads/rare": rareEntry.GetID(),
+			"refs/heads/main": mainEntryIDs[2*CheckpointInterval-3],
+		}, secondCheckpointEntry.GetLinks().Checkpoint)
+
+		entry, annotations, err := GetLatestReferenceEntryForRef(repo, "refs/heads/rare")
+		assert.Nil(t, err)
+		assert.Nil(t, annotations)
+		assert.Equal(t, rareEntry.GetID(), entry.ID)
+
+		_, _, err = GetLatestReferenceEntryForRef(repo, "refs/heads/unknown")
+		assert.ErrorIs(t, err, ErrRSLEntryNotFound)
+
+		// The rare ref is found by walking back only to the latest checkpoint
+		latestEntry, err := GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry, err = findReferenceEntryForRefUsingLinks(repo, latestEntry, "refs/heads/rare")
+		assert.Nil(t, err)
+		assert.Equal(t, rareEntry.GetID(), entry.ID)
+	})
+
+	t.Run("lookups with annotations", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		firstEntry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+		commitTestEntry(t, repo, NewAnnotationEntry([]plumbing.Hash{firstEntry.GetID()}, false, annotationMessage))
+		secondEntry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+		for i := 0; i < CheckpointInterval; i++ {
+			commitTestEntry(t, repo, NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash))
+		}
+		commitTestEntry(t, repo, NewAnnotationEntry([]plumbing.Hash{secondEntry.GetID()}, true, annotationMessage))
+		commitTestEntry(t, repo, NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash))
+
+		entry, annotations, err := GetLatestReferenceEntryForRef(repo, "refs/heads/main")
+		assert.Nil(t, err)
+		assert.Equal(t, secondEntry.GetID(), entry.ID)
+		assert.Equal(t, 1, len(annotations))
+		assert.True(t, annotations[0].Skip)
+
+		entry, annotations, err = GetLatestUnskippedReferenceEntryForRef(repo, "refs/heads/main")
+		assert.Nil(t, err)
+		assert.Equal(t, firstEntry.GetID(), entry.ID)
+		assert.Equal(t, 1, len(annotations))
+		assertAnnotationsReferToEntry(t, entry, annotations)
+
+		entry, _, err = GetLatestReferenceEntryForRefBefore(repo, "refs/heads/main", secondEntry.GetID())
+		assert.Nil(t, err)
+		assert.Equal(t, firstEntry.GetID(), entry.ID)
+
+		_, _, err = GetLatestReferenceEntryForRefBefore(repo, "refs/heads/main", firstEntry.GetID())
+		assert.ErrorIs(t, err, ErrRSLEntryNotFound)
+	})
+
+	t.Run("RSL created without links", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		// Entries created by older versions of gittuf don't have links
+		mainEntryID := commitTestEntryWithoutLinks(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+		annotationID := commitTestEntryWithoutLinks(t, repo, NewAnnotationEntry([]plumbing.Hash{mainEntryID}, true, annotationMessage))
+		featureEntryID := commitTestEntryWithoutLinks(t, repo, NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash))
+
+		// The first new entry walks the RSL to create its links
+		secondFeatureEntry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash))
+		assert.Equal(t, &Links{Number: 4, PreviousEntryID: featureEntryID, PreviousAnnotationID: annotationID}, secondFeatureEntry.GetLinks())
+
+		// Lookups fall back to walking the RSL when they reach entries
+		// without links
+		entry, annotations, err := GetLatestReferenceEntryForRef(repo, "refs/heads/main")
+		assert.Nil(t, err)
+		assert.Equal(t, mainEntryID, entry.ID)
+		assert.Equal(t, 1, len(annotations))
+
+		entry, _, err = GetLatestReferenceEntryForRefBefore(repo, "refs/heads/feature", secondFeatureEntry.GetID())
+		assert.Nil(t, err)
+		assert.Equal(t, featureEntryID, entry.ID)
+
+		// Entries added by an older version of gittuf after new entries are
+		// also accounted for
+		commitTestEntryWithoutLinks(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+		latestMainEntry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+		assert.Equal(t, 6, latestMainEntry.GetLinks().Number)
+
+		entry, annotations, err = GetLatestUnskippedReferenceEntryForRef(repo, "refs/heads/feature")
+		assert.Nil(t, err)
+		assert.Equal(t, secondFeatureEntry.GetID(), entry.ID)
+		assert.Nil(t, annotations)
+	})
+}
+
+func TestForgedLinks(t *testing.T) {
+	t.Run("forged checkpoint", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		firstMainEntry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+		secondMainEntry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+
+		// An entry for another ref claims the first entry is the latest for
+		// main
+		forgedEntryID := commitTestEntryWithLinks(t, repo, NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash), &Links{
+			Number:     3,
+			Checkpoint: map[string]plumbing.Hash{"refs/heads/main": firstMainEntry.GetID()},
+		})
+
+		entry, _, err := GetLatestReferenceEntryForRef(repo, "refs/heads/main")
+		assert.Nil(t, err)
+		assert.Equal(t, secondMainEntry.GetID(), entry.ID)
+
+		// Entries after the forged entry don't inherit its links
+		featureEntry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash))
+		assert.Equal(t, &Links{Number: 4, PreviousEntryID: forgedEntryID}, featureEntry.GetLinks())
+
+		entry, _, err = GetLatestReferenceEntryForRef(repo, "refs/heads/main")
+		assert.Nil(t, err)
+		assert.Equal(t, secondMainEntry.GetID(), entry.ID)
+	})
+
+	t.Run("forged previous entry", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		firstMainEntry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+		secondMainEntry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+		forgedEntryID := commitTestEntryWithLinks(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash), &Links{
+			Number:          3,
+			PreviousEntryID: firstMainEntry.GetID(),
+		})
+
+		entry, _, err := GetLatestReferenceEntryForRefBefore(repo, "refs/heads/main", forgedEntryID)
+		assert.Nil(t, err)
+		assert.Equal(t, secondMainEntry.GetID(), entry.ID)
+	})
+
+	t.Run("forged previous annotation", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		mainEntry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+		annotation := commitTestEntry(t, repo, NewAnnotationEntry([]plumbing.Hash{mainEntry.GetID()}, true, annotationMessage))
+
+		// The entry hides the annotation skipping the main entry
+		commitTestEntryWithLinks(t, repo, NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash), &Links{Number: 3})
+
+		entry, annotations, err := GetLatestReferenceEntryForRef(repo, "refs/heads/main")
+		assert.Nil(t, err)
+		assert.Equal(t, mainEntry.GetID(), entry.ID)
+		if assert.Equal(t, 1, len(annotations)) {
+			assert.Equal(t, annotation.GetID(), annotations[0].ID)
+		}
+		assert.True(t, entry.SkippedBy(annotations))
+	})
+
+	t.Run("forged number", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		mainEntry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+		forgedEntryID := commitTestEntryWithLinks(t, repo, NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash), &Links{Number: 1})
+
+		isBefore, err := IsEntryBefore(repo, mainEntry.GetID(), forgedEntryID)
+		assert.Nil(t, err)
+		assert.True(t, isBefore)
+
+		isBefore, err = IsEntryBefore(repo, forgedEntryID, mainEntry.GetID())
+		assert.Nil(t, err)
+		assert.False(t, isBefore)
+
+		latestEntry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+		assert.Equal(t, &Links{Number: 3, PreviousEntryID: mainEntry.GetID()}, latestEntry.GetLinks())
+	})
+}
+
+func TestLinkChecker(t *testing.T) {
+	t.Run("checks are per repository", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+		otherRepo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitializeNamespace(otherRepo); err != nil {
+			t.Fatal(err)
+		}
+
+		entry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+		commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+		otherEntry := commitTestEntry(t, otherRepo, NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash))
+		commitTestEntry(t, otherRepo, NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash))
+
+		checker := getLinkChecker(repo)
+		otherChecker := getLinkChecker(otherRepo)
+		assert.NotSame(t, checker, otherChecker)
+		assert.Same(t, checker, getLinkChecker(repo))
+
+		assert.Contains(t, checker.checks, entry.GetID())
+		assert.NotContains(t, checker.checks, otherEntry.GetID())
+		assert.Contains(t, otherChecker.checks, otherEntry.GetID())
+		assert.NotContains(t, otherChecker.checks, entry.GetID())
+	})
+
+	t.Run("checks resume from checkpoint", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		entryIDs := []plumbing.Hash{}
+		for i := 0; i < CheckpointInterval+2; i++ {
+			entryIDs = append(entryIDs, commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash)).GetID())
+		}
+
+		// Rewind the RSL to the entry after the checkpoint, which isn't the
+		// tip of earlier checks. The check of the first entry is dropped to
+		// detect walks back to it.
+		checker := getLinkChecker(repo)
+		delete(checker.checks, entryIDs[0])
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(Ref), entryIDs[CheckpointInterval])); err != nil {
+			t.Fatal(err)
+		}
+		entry := commitTestEntry(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash))
+		assert.Equal(t, &Links{Number: CheckpointInterval + 2, PreviousEntryID: entryIDs[CheckpointInterval]}, entry.GetLinks())
+
+		// The walk stopped at the checkpoint
+		assert.NotContains(t, checker.checks, entryIDs[0])
+
+		check, err := checkLinks(repo, entry)
+		assert.Nil(t, err)
+		assert.True(t, check.valid)
+		assert.Equal(t, CheckpointInterval+2, check.number)
+	})
+}
+
+func TestParseLinks(t *testing.T) {
+	entryID := plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12")
+	referenceEntryHeader := fmt.Sprintf("%s\n\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String())
+
+	tests := map[string]struct {
+		message       string
+		expectedLinks *Links
+		expectedError error
+	}{
+		"reference entry without links": {
+			message: referenceEntryHeader,
+		},
+		"reference entry with links": {
+			message:       fmt.Sprintf("%s\n%s: 3\n%s: %s\n%s: %s", referenceEntryHeader, NumberKey, PreviousEntryIDKey, entryID.String(), PreviousAnnotationIDKey, plumbing.ZeroHash.String()),
+			expectedLinks: &Links{Number: 3, PreviousEntryID: entryID},
+		},
+		"reference entry with checkpoint": {
+			message:       fmt.Sprintf("%s\n%s: 64\n%s: %s\n%s: %s\n%s: 1\n%s: %s %s", referenceEntryHeader, NumberKey, PreviousEntryIDKey, entryID.String(), PreviousAnnotationIDKey, plumbing.ZeroHash.String(), CheckpointKey, CheckpointEntryKey, entryID.String(), "refs/heads/main"),
+			expectedLinks: &Links{Number: 64, PreviousEntryID: entryID, Checkpoint: map[string]plumbing.Hash{"refs/heads/main": entryID}},
+		},
+		"reference entry with empty checkpoint": {
+			message:       fmt.Sprintf("%s\n%s: 64\n%s: %s\n%s: %s\n%s: 0", referenceEntryHeader, NumberKey, PreviousEntryIDKey, entryID.String(), PreviousAnnotationIDKey, plumbing.ZeroHash.String(), CheckpointKey),
+			expectedLinks: &Links{Number: 64, PreviousEntryID: entryID, Checkpoint: map[string]plumbing.Hash{}},
+		},
+		"reference entry with incomplete links": {
+			message:       fmt.Sprintf("%s\n%s: 3\n%s: %s", referenceEntryHeader, NumberKey, PreviousAnnotationIDKey, plumbing.ZeroHash.String()),
+			expectedError: ErrInvalidRSLEntry,
+		},
+		"reference entry with invalid number": {
+			message:       fmt.Sprintf("%s\n%s: 0\n%s: %s\n%s: %s", referenceEntryHeader, NumberKey, PreviousEntryIDKey, entryID.String(), PreviousAnnotationIDKey, plumbing.ZeroHash.String()),
+			expectedError: ErrInvalidRSLEntry,
+		},
+		"reference entry with checkpoint count mismatch": {
+			message:       fmt.Sprintf("%s\n%s: 64\n%s: %s\n%s: %s\n%s: 2\n%s: %s %s", referenceEntryHeader, NumberKey, PreviousEntryIDKey, entryID.String(), PreviousAnnotationIDKey, plumbing.ZeroHash.String(), CheckpointKey, CheckpointEntryKey, entryID.String(), "refs/heads/main"),
+			expectedError: ErrInvalidRSLEntry,
+		},
+		"reference entry with invalid checkpoint entry ID": {
+			message:       fmt.Sprintf("%s\n%s: 64\n%s: %s\n%s: %s\n%s: 1\n%s: %s %s", referenceEntryHeader, NumberKey, PreviousEntryIDKey, entryID.String(), PreviousAnnotationIDKey, plumbing.ZeroHash.String(), CheckpointKey, CheckpointEntryKey, "abcdef", "refs/heads/main"),
+			expectedError: ErrInvalidRSLEntry,
+		},
+		"annotation with links": {
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: true\n%s: 2\n%s: %s", AnnotationEntryHeader, EntryIDKey, entryID.String(), SkipKey, NumberKey, PreviousAnnotationIDKey, entryID.String()),
+			expectedLinks: &Links{Number: 2, PreviousAnnotationID: entryID},
+		},
+		"annotation with previous entry": {
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: true\n%s: 2\n%s: %s\n%s: %s", AnnotationEntryHeader, EntryIDKey, entryID.String(), SkipKey, NumberKey, PreviousEntryIDKey, entryID.String(), PreviousAnnotationIDKey, entryID.String()),
+			expectedError: ErrInvalidRSLEntry,
+		},
+		"propagation entry with links": {
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: 2\n%s: %s", PropagationEntryHeader, UpstreamRepositoryKey, "https://example.com/upstream.git", UpstreamEntryIDKey, entryID.String(), NumberKey, PreviousAnnotationIDKey, plumbing.ZeroHash.String()),
+			expectedLinks: &Links{Number: 2},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			entry, err := parseRSLEntryText(plumbing.ZeroHash, test.message)
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.expectedLinks, entry.GetLinks())
+
+			// Links round trip through the entry's commit message
+			message, err := entry.createCommitMessage()
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.message, message)
+		})
+	}
+}
+
+func commitTestEntry(t *testing.T, repo *git.Repository, entry Entry) Entry {
+	t.Helper()
+
+	if err := entry.Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+
+	latestEntry, err := GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return latestEntry
+}
+
+func commitTestEntryWithoutLinks(t *testing.T, repo *git.Repository, entry Entry) plumbing.Hash {
+	t.Helper()
+
+	message, err := entry.createCommitMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entryID, err := gitinterface.Commit(repo, gitinterface.EmptyTree(), Ref, message, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return entryID
+}
+
+func commitTestEntryWithLinks(t *testing.T, repo *git.Repository, entry Entry, links *Links) plumbing.Hash {
+	t.Helper()
+
+	switch entry := entry.(type) {
+	case *ReferenceEntry:
+		entry.Links = links
+	case *AnnotationEntry:
+		entry.Links = links
+	case *PropagationEntry:
+		entry.Links = links
+	}
+
+	return commitTestEntryWithoutLinks(t, repo, entry)
+}
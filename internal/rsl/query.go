@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rsl
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// SkipFilter selects reference entries by whether they are skipped.
+type SkipFilter int
+
+const (
+	// SkipFilterAll selects entries regardless of whether they are skipped.
+	SkipFilterAll SkipFilter = iota
+
+	// SkipFilterSkipped selects entries marked as to-be-skipped by an
+	// annotation.
+	SkipFilterSkipped
+
+	// SkipFilterUnskipped selects entries that are not skipped.
+	SkipFilterUnskipped
+)
+
+// Query selects reference entries in the RSL. The zero value selects every
+// reference entry.
+type Query struct {
+	// RefNames restricts the query to entries for the specified refs. Names
+	// that are not fully qualified match both the branch and the tag with the
+	// name.
+	RefNames []string
+
+	// Since and Until restrict the query to entries recorded in the time
+	// range, inclusive. An entry is recorded at its commit's committer time.
+	// Either bound is ignored if zero.
+	Since time.Time
+	Until time.Time
+
+	// Skipped selects entries by whether they are skipped.
+	Skipped SkipFilter
+}
+
+// Matches returns true if the reference entry is selected by the query. The
+// annotations must include every annotation that refers to the entry.
+func (q *Query) Matches(repo *git.Repository, entry *ReferenceEntry, annotations []*AnnotationEntry) (bool, error) {
+	if len(q.RefNames) != 0 && !slices.ContainsFunc(q.RefNames, func(refName string) bool { return refNameMatches(refName, entry.RefName) }) {
+		return false, nil
+	}
+
+	switch q.Skipped {
+	case SkipFilterSkipped:
+		if !entry.SkippedBy(annotations) {
+			return false, nil
+		}
+	case SkipFilterUnskipped:
+		if entry.SkippedBy(annotations) {
+			return false, nil
+		}
+	}
+
+	if q.Since.IsZero() && q.Until.IsZero() {
+		return true, nil
+	}
+
+	recordedAt, err := GetEntryTime(repo, entry.ID)
+	if err != nil {
+		return false, err
+	}
+	if !q.Since.IsZero() && recordedAt.Before(q.Since) {
+		return false, nil
+	}
+	if !q.Until.IsZero() && recordedAt.After(q.Until) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// GetEntryTime returns the time the entry with the specified ID was recorded
+// at, i.e., its commit's committer time.
+func GetEntryTime(repo *git.Repository, entryID plumbing.Hash) (time.Time, error) {
+	commitObj, err := gitinterface.GetCommit(repo, entryID)
+	if err != nil {
+		return time.Time{}, ErrRSLEntryNotFound
+	}
+
+	return commitObj.Committer.When, nil
+}
+
+func refNameMatches(filter, refName string) bool {
+	if strings.HasPrefix(filter, gitinterface.RefPrefix) {
+		return filter == refName
+	}
+
+	return refName == string(plumbing.NewBranchReferenceName(filter)) || refName == string(plumbing.NewTagReferenceName(filter))
+}
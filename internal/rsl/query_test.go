@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rsl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryMatches(t *testing.T) {
+	repo := createTestRSL(t, 1)
+
+	entry, _, err := GetLatestReferenceEntryForRef(repo, "refs/heads/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recordedAt, err := GetEntryTime(repo, entry.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	skipAnnotation := NewAnnotationEntry([]plumbing.Hash{entry.ID}, true, "skip")
+	noSkipAnnotation := NewAnnotationEntry([]plumbing.Hash{entry.ID}, false, "note")
+
+	tests := map[string]struct {
+		query       *Query
+		annotations []*AnnotationEntry
+		expected    bool
+	}{
+		"empty query": {
+			query:    &Query{},
+			expected: true,
+		},
+		"matching ref": {
+			query:    &Query{RefNames: []string{"refs/heads/1", "refs/heads/0"}},
+			expected: true,
+		},
+		"matching short ref": {
+			query:    &Query{RefNames: []string{"0"}},
+			expected: true,
+		},
+		"different ref": {
+			query:    &Query{RefNames: []string{"refs/heads/1"}},
+			expected: false,
+		},
+		"tag with same short name": {
+			query:    &Query{RefNames: []string{"refs/tags/0"}},
+			expected: false,
+		},
+		"skipped, not skipped": {
+			query:       &Query{Skipped: SkipFilterSkipped},
+			annotations: []*AnnotationEntry{noSkipAnnotation},
+			expected:    false,
+		},
+		"skipped, skipped": {
+			query:       &Query{Skipped: SkipFilterSkipped},
+			annotations: []*AnnotationEntry{noSkipAnnotation, skipAnnotation},
+			expected:    true,
+		},
+		"unskipped, skipped": {
+			query:       &Query{Skipped: SkipFilterUnskipped},
+			annotations: []*AnnotationEntry{skipAnnotation},
+			expected:    false,
+		},
+		"unskipped, not skipped": {
+			query:    &Query{Skipped: SkipFilterUnskipped},
+			expected: true,
+		},
+		"in time range": {
+			query:    &Query{Since: recordedAt, Until: recordedAt},
+			expected: true,
+		},
+		"before since": {
+			query:    &Query{Since: recordedAt.Add(time.Second)},
+			expected: false,
+		},
+		"after until": {
+			query:    &Query{Until: recordedAt.Add(-time.Second)},
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			matches, err := test.query.Matches(repo, entry, test.annotations)
+			assert.Nil(t, err, name)
+			assert.Equal(t, test.expected, matches, name)
+		})
+	}
+}
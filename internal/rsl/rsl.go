@@ -6,11 +6,19 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"unicode"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
@@ -19,12 +27,23 @@ const (
 	ReferenceEntryHeader       = "RSL Reference Entry"
 	RefKey                     = "ref"
 	TargetIDKey                = "targetID"
+	ChangedPathsKey            = "changedPaths"
+	ChangedPathKey             = "changedPath"
+	TicketKey                  = "ticket"
+	SubmodulesKey              = "submodules"
+	SubmoduleKey               = "submodule"
 	AnnotationEntryHeader      = "RSL Annotation Entry"
 	AnnotationMessageBlockType = "MESSAGE"
 	BeginMessage               = "-----BEGIN MESSAGE-----"
 	EndMessage                 = "-----END MESSAGE-----"
 	EntryIDKey                 = "entryID"
 	SkipKey                    = "skip"
+	RangeRefKey                = "rangeRef"
+	RangeStartKey              = "rangeStart"
+	RangeEndKey                = "rangeEnd"
+	PropagationEntryHeader     = "RSL Propagation Entry"
+	UpstreamRepositoryKey      = "upstreamRepository"
+	UpstreamEntryIDKey         = "upstreamEntryID"
 
 	remoteTrackerRef       = "refs/remotes/%s/gittuf/reference-state-log"
 	gittufNamespacePrefix  = "refs/gittuf/"
@@ -38,6 +57,10 @@ var (
 	ErrInvalidRSLEntry         = errors.New("RSL entry has invalid format or is of unexpected type")
 	ErrRSLEntryDoesNotMatchRef = errors.New("RSL entry does not match requested ref")
 	ErrNoRecordOfCommit        = errors.New("commit has not been encountered before")
+	ErrInvalidAnnotationRange  = errors.New("annotation range is invalid, start and end must be reference entries for the same ref with start preceding end")
+	ErrChangedPathsNeedCommits = errors.New("changed paths can only be recorded for refs that point to commits")
+	ErrSubmodulesNeedCommits   = errors.New("submodules can only be recorded for refs that point to commits")
+	ErrInvalidTicket           = errors.New("ticket must be an absolute URI without whitespace, such as 'https://example.com/issues/1'")
 )
 
 // InitializeNamespace creates a git ref for the reference state log. Initially,
@@ -70,6 +93,7 @@ func RemoteTrackerRef(remote string) string {
 // Entry is the abstract representation of an object in the RSL.
 type Entry interface {
 	GetID() plumbing.Hash
+	GetLinks() *Links
 	Commit(*git.Repository, bool) error
 	createCommitMessage() (string, error)
 }
@@ -85,6 +109,38 @@ type ReferenceEntry struct {
 
 	// TargetID contains the Git hash for the object expected at RefName.
 	TargetID plumbing.Hash
+
+	// ChangedPaths optionally contains the top-level paths that differ
+	// between TargetID and the target of the previous entry for RefName. It is
+	// nil if the paths were not recorded, and empty if they were recorded but
+	// no paths changed.
+	ChangedPaths []string
+
+	// Tickets optionally contains the URIs of the issues or tickets that
+	// track the change, such as links to an issue tracker.
+	Tickets []string
+
+	// Submodules optionally contains the commit IDs of the submodules in
+	// TargetID, keyed by path. It is nil if the submodules were not recorded,
+	// and empty if they were recorded but TargetID has no submodules.
+	Submodules map[string]plumbing.Hash
+
+	// Message optionally contains a note added by the user when creating the
+	// entry, such as the reason for the change or a link to its approval.
+	Message string
+
+	// Links contains pointers to earlier entries in the RSL. It is nil for
+	// entries created by older versions of gittuf.
+	Links *Links
+
+	// Shard contains the name of the RSL shard the entry is recorded in. It
+	// is empty for entries recorded in the RSL itself.
+	Shard string
+
+	// IndexEntryID contains the ID of the index entry in the RSL that records
+	// the entry's shard tip. It is only set for entries read from a shard
+	// using an index entry, and is not recorded in the entry.
+	IndexEntryID plumbing.Hash
 }
 
 // NewReferenceEntry returns a ReferenceEntry object for a normal RSL entry.
@@ -92,15 +148,40 @@ func NewReferenceEntry(refName string, targetID plumbing.Hash) *ReferenceEntry {
 	return &ReferenceEntry{RefName: refName, TargetID: targetID}
 }
 
+// NewReferenceEntryWithChangedPaths returns a ReferenceEntry object for a
+// normal RSL entry that also records the top-level paths changed since the
+// previous entry for the ref.
+func NewReferenceEntryWithChangedPaths(refName string, targetID plumbing.Hash, changedPaths []string) *ReferenceEntry {
+	if changedPaths == nil {
+		changedPaths = []string{}
+	}
+	return &ReferenceEntry{RefName: refName, TargetID: targetID, ChangedPaths: changedPaths}
+}
+
 func (e *ReferenceEntry) GetID() plumbing.Hash {
 	return e.ID
 }
 
+func (e *ReferenceEntry) GetLinks() *Links {
+	return e.Links
+}
+
 // Commit creates a commit object in the RSL for the ReferenceEntry.
 func (e *ReferenceEntry) Commit(repo *git.Repository, sign bool) error {
-	message, _ := e.createCommitMessage() // we have an error return for annotations, always nil here
+	if err := ValidateTickets(e.Tickets); err != nil {
+		return err
+	}
+
+	if err := e.setLinks(repo); err != nil {
+		return err
+	}
 
-	_, err := gitinterface.Commit(repo, gitinterface.EmptyTree(), Ref, message, sign)
+	message, err := e.createCommitMessage()
+	if err != nil {
+		return err
+	}
+
+	_, err = gitinterface.Commit(repo, gitinterface.EmptyTree(), Ref, message, sign)
 	return err
 }
 
@@ -108,9 +189,20 @@ func (e *ReferenceEntry) Commit(repo *git.Repository, sign bool) error {
 // ReferenceEmpty. The commit is signed using the provided PEM encoded SSH or
 // GPG private key. This is only intended for use in gittuf's developer mode.
 func (e *ReferenceEntry) CommitUsingSpecificKey(repo *git.Repository, signingKeyBytes []byte) error {
-	message, _ := e.createCommitMessage() // we have an error return for annotations, always nil here
+	if err := ValidateTickets(e.Tickets); err != nil {
+		return err
+	}
+
+	if err := e.setLinks(repo); err != nil {
+		return err
+	}
 
-	_, err := gitinterface.CommitUsingSpecificKey(repo, gitinterface.EmptyTree(), Ref, message, signingKeyBytes)
+	message, err := e.createCommitMessage()
+	if err != nil {
+		return err
+	}
+
+	_, err = gitinterface.CommitUsingSpecificKey(repo, gitinterface.EmptyTree(), Ref, message, signingKeyBytes)
 	return err
 }
 
@@ -133,9 +225,176 @@ func (e *ReferenceEntry) createCommitMessage() (string, error) {
 		fmt.Sprintf("%s: %s", RefKey, e.RefName),
 		fmt.Sprintf("%s: %s", TargetIDKey, e.TargetID.String()),
 	}
+
+	if e.Shard != "" {
+		lines = append(lines, fmt.Sprintf("%s: %s", ShardKey, e.Shard))
+	}
+
+	if e.ChangedPaths != nil {
+		// The count distinguishes entries that record no changed paths from
+		// entries that don't record paths. Paths are quoted as they may
+		// contain any character, including newlines.
+		lines = append(lines, fmt.Sprintf("%s: %d", ChangedPathsKey, len(e.ChangedPaths)))
+		for _, path := range e.ChangedPaths {
+			lines = append(lines, fmt.Sprintf("%s: %s", ChangedPathKey, strconv.Quote(path)))
+		}
+	}
+
+	lines = append(lines, ticketLines(e.Tickets)...)
+
+	if e.Submodules != nil {
+		paths := make([]string, 0, len(e.Submodules))
+		for path := range e.Submodules {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		// As with changed paths, the count distinguishes entries that record
+		// no submodules from entries that don't record submodules
+		lines = append(lines, fmt.Sprintf("%s: %d", SubmodulesKey, len(paths)))
+		for _, path := range paths {
+			lines = append(lines, fmt.Sprintf("%s: %s %s", SubmoduleKey, e.Submodules[path].String(), strconv.Quote(path)))
+		}
+	}
+
+	if e.Links != nil {
+		lines = append(lines, e.Links.lines(true)...)
+	}
+
+	if len(e.Message) != 0 {
+		messageLines, err := messageBlockLines(e.Message)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, messageLines)
+	}
+
 	return strings.Join(lines, "\n"), nil
 }
 
+// setLinks sets the links for the entry as the next entry in the RSL.
+func (e *ReferenceEntry) setLinks(repo *git.Repository) error {
+	links, err := newLinks(repo)
+	if err != nil {
+		return err
+	}
+
+	previousEntry, _, err := GetLatestReferenceEntryForRef(repo, e.RefName)
+	if err == nil {
+		links.PreviousEntryID = previousEntry.ID
+	} else if !errors.Is(err, ErrRSLEntryNotFound) {
+		return err
+	}
+
+	e.Links = links
+	return nil
+}
+
+// GetChangedPaths returns the top-level paths that differ between the commit
+// targetID and the commit recorded in priorEntry, for use in a ReferenceEntry.
+// If priorEntry is nil or records the ref being deleted, all of the top-level
+// paths in targetID are returned.
+func GetChangedPaths(repo *git.Repository, priorEntry *ReferenceEntry, targetID plumbing.Hash) ([]string, error) {
+	targetCommit, err := getCommitForChangedPaths(repo, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	var priorCommit *object.Commit
+	if priorEntry != nil && !priorEntry.TargetID.IsZero() {
+		priorCommit, err = getCommitForChangedPaths(repo, priorEntry.TargetID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return gitinterface.GetTopLevelPathsChanged(priorCommit, targetCommit)
+}
+
+func getCommitForChangedPaths(repo *git.Repository, commitID plumbing.Hash) (*object.Commit, error) {
+	commit, err := gitinterface.GetCommit(repo, commitID)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil, fmt.Errorf("%w: '%s' is not a commit in the repository", ErrChangedPathsNeedCommits, commitID.String())
+		}
+		return nil, err
+	}
+
+	return commit, nil
+}
+
+// GetSubmoduleCommits returns the commit IDs of the submodules in the commit
+// targetID, keyed by path, for use in a ReferenceEntry. Submodules are
+// identified using the gitlinks in the commit's tree rather than .gitmodules,
+// as the gitlinks determine the submodule commits that are checked out.
+func GetSubmoduleCommits(repo *git.Repository, targetID plumbing.Hash) (map[string]plumbing.Hash, error) {
+	commit, err := gitinterface.GetCommit(repo, targetID)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil, fmt.Errorf("%w: '%s' is not a commit in the repository", ErrSubmodulesNeedCommits, targetID.String())
+		}
+		return nil, err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	submodules := map[string]plumbing.Hash{}
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+	for {
+		path, entry, err := walker.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		if entry.Mode == filemode.Submodule {
+			submodules[path] = entry.Hash
+		}
+	}
+
+	return submodules, nil
+}
+
+// ValidateTickets checks that each ticket is an absolute URI that can be
+// recorded in an RSL entry.
+func ValidateTickets(tickets []string) error {
+	for _, ticket := range tickets {
+		if !isValidTicket(ticket) {
+			return fmt.Errorf("%w: '%s'", ErrInvalidTicket, ticket)
+		}
+	}
+
+	return nil
+}
+
+func isValidTicket(ticket string) bool {
+	if strings.ContainsFunc(ticket, unicode.IsSpace) {
+		return false
+	}
+
+	ticketURI, err := url.Parse(ticket)
+	if err != nil {
+		return false
+	}
+
+	return ticketURI.IsAbs() && (ticketURI.Host != "" || ticketURI.Opaque != "")
+}
+
+func ticketLines(tickets []string) []string {
+	lines := make([]string, 0, len(tickets))
+	for _, ticket := range tickets {
+		lines = append(lines, fmt.Sprintf("%s: %s", TicketKey, ticket))
+	}
+
+	return lines
+}
+
 // AnnotationEntry is a type of RSL record that references prior items in the
 // RSL. It can be used to add extra information for the referenced items.
 // Annotations can also be used to "skip", i.e. revoke, the referenced items. It
@@ -152,6 +411,25 @@ type AnnotationEntry struct {
 
 	// Message contains any messages or notes added by a user for the annotation.
 	Message string
+
+	// RangeRefName, RangeStartID, and RangeEndID are set when the annotation
+	// applies to a contiguous range of entries for a single ref rather than to
+	// explicitly listed entries. The range includes every reference entry for
+	// RangeRefName from RangeStartID to RangeEndID, inclusive. When an
+	// annotation with a range is loaded from the RSL, RSLEntryIDs is populated
+	// with the IDs of the entries in the range.
+	RangeRefName string
+	RangeStartID plumbing.Hash
+	RangeEndID   plumbing.Hash
+
+	// Tickets optionally contains the URIs of the issues or tickets relevant
+	// to the annotated entries, such as the incident that led to them being
+	// skipped.
+	Tickets []string
+
+	// Links contains pointers to earlier entries in the RSL. It is nil for
+	// entries created by older versions of gittuf.
+	Links *Links
 }
 
 // NewAnnotationEntry returns an Annotation object that applies to one or more
@@ -160,19 +438,55 @@ func NewAnnotationEntry(rslEntryIDs []plumbing.Hash, skip bool, message string)
 	return &AnnotationEntry{RSLEntryIDs: rslEntryIDs, Skip: skip, Message: message}
 }
 
+// NewAnnotationEntryForRange returns an Annotation object that applies to all
+// the reference entries for refName between startID and endID, inclusive. This
+// allows a single annotation to skip a large number of entries, such as when
+// recovering from an automated process that pushed many bad changes.
+func NewAnnotationEntryForRange(refName string, startID, endID plumbing.Hash, skip bool, message string) *AnnotationEntry {
+	return &AnnotationEntry{
+		RSLEntryIDs:  []plumbing.Hash{},
+		Skip:         skip,
+		Message:      message,
+		RangeRefName: refName,
+		RangeStartID: startID,
+		RangeEndID:   endID,
+	}
+}
+
 func (a *AnnotationEntry) GetID() plumbing.Hash {
 	return a.ID
 }
 
+func (a *AnnotationEntry) GetLinks() *Links {
+	return a.Links
+}
+
 // Commit creates a commit object in the RSL for the Annotation.
 func (a *AnnotationEntry) Commit(repo *git.Repository, sign bool) error {
-	// Check if referred entries exist in the RSL namespace.
-	for _, id := range a.RSLEntryIDs {
-		if _, err := GetEntry(repo, id); err != nil {
+	if err := ValidateTickets(a.Tickets); err != nil {
+		return err
+	}
+
+	if a.IsRange() {
+		// Check that the range is valid in the RSL namespace.
+		if _, err := getReferenceEntryIDsInRangeForRef(repo, a.RangeStartID, a.RangeEndID, a.RangeRefName); err != nil {
 			return err
 		}
+	} else {
+		// Check if referred entries exist in the RSL namespace.
+		for _, id := range a.RSLEntryIDs {
+			if _, err := GetEntry(repo, id); err != nil {
+				return err
+			}
+		}
 	}
 
+	links, err := newLinks(repo)
+	if err != nil {
+		return err
+	}
+	a.Links = links
+
 	message, err := a.createCommitMessage()
 	if err != nil {
 		return err
@@ -194,14 +508,28 @@ func (a *AnnotationEntry) RefersTo(entryID plumbing.Hash) bool {
 	return false
 }
 
+// IsRange returns true if the annotation applies to a range of entries for a
+// ref rather than to explicitly listed entries.
+func (a *AnnotationEntry) IsRange() bool {
+	return len(a.RangeRefName) != 0
+}
+
 func (a *AnnotationEntry) createCommitMessage() (string, error) {
 	lines := []string{
 		AnnotationEntryHeader,
 		"",
 	}
 
-	for _, entry := range a.RSLEntryIDs {
-		lines = append(lines, fmt.Sprintf("%s: %s", EntryIDKey, entry.String()))
+	if a.IsRange() {
+		// The entries in the range are resolved when the annotation is loaded,
+		// so only the bounds are recorded
+		lines = append(lines, fmt.Sprintf("%s: %s", RangeRefKey, a.RangeRefName))
+		lines = append(lines, fmt.Sprintf("%s: %s", RangeStartKey, a.RangeStartID.String()))
+		lines = append(lines, fmt.Sprintf("%s: %s", RangeEndKey, a.RangeEndID.String()))
+	} else {
+		for _, entry := range a.RSLEntryIDs {
+			lines = append(lines, fmt.Sprintf("%s: %s", EntryIDKey, entry.String()))
+		}
 	}
 
 	if a.Skip {
@@ -210,21 +538,106 @@ func (a *AnnotationEntry) createCommitMessage() (string, error) {
 		lines = append(lines, fmt.Sprintf("%s: false", SkipKey))
 	}
 
+	lines = append(lines, ticketLines(a.Tickets)...)
+
+	if a.Links != nil {
+		lines = append(lines, a.Links.lines(false)...)
+	}
+
 	if len(a.Message) != 0 {
-		var message strings.Builder
-		messageBlock := pem.Block{
-			Type:  AnnotationMessageBlockType,
-			Bytes: []byte(a.Message),
-		}
-		if err := pem.Encode(&message, &messageBlock); err != nil {
+		messageLines, err := messageBlockLines(a.Message)
+		if err != nil {
 			return "", err
 		}
-		lines = append(lines, strings.TrimSpace(message.String()))
+		lines = append(lines, messageLines)
 	}
 
 	return strings.Join(lines, "\n"), nil
 }
 
+// messageBlockLines encodes the message as a PEM block, so that it may contain
+// any text, including lines that look like entry fields.
+func messageBlockLines(message string) (string, error) {
+	var encoded strings.Builder
+	messageBlock := pem.Block{
+		Type:  AnnotationMessageBlockType,
+		Bytes: []byte(message),
+	}
+	if err := pem.Encode(&encoded, &messageBlock); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(encoded.String()), nil
+}
+
+// PropagationEntry is a type of RSL record that references the RSL of an
+// upstream repository. It records that the repository has incorporated the
+// upstream's changes as of the referenced upstream RSL entry, allowing mirrors
+// and forks to be checked for divergence from their upstream. It implements
+// the Entry interface.
+type PropagationEntry struct {
+	// ID contains the Git hash for the commit corresponding to the entry.
+	ID plumbing.Hash
+
+	// UpstreamRepository contains the location of the upstream repository.
+	UpstreamRepository string
+
+	// UpstreamEntryID contains the Git hash for the upstream RSL entry that
+	// was propagated.
+	UpstreamEntryID plumbing.Hash
+
+	// Links contains pointers to earlier entries in the RSL. It is nil for
+	// entries created by older versions of gittuf.
+	Links *Links
+}
+
+// NewPropagationEntry returns a PropagationEntry object for the specified entry
+// in the upstream repository's RSL.
+func NewPropagationEntry(upstreamRepository string, upstreamEntryID plumbing.Hash) *PropagationEntry {
+	return &PropagationEntry{UpstreamRepository: upstreamRepository, UpstreamEntryID: upstreamEntryID}
+}
+
+func (p *PropagationEntry) GetID() plumbing.Hash {
+	return p.ID
+}
+
+func (p *PropagationEntry) GetLinks() *Links {
+	return p.Links
+}
+
+// Commit creates a commit object in the RSL for the PropagationEntry. The
+// upstream RSL entry must be available locally, typically by fetching the
+// upstream RSL to its remote tracker.
+func (p *PropagationEntry) Commit(repo *git.Repository, sign bool) error {
+	if _, err := GetEntry(repo, p.UpstreamEntryID); err != nil {
+		return err
+	}
+
+	links, err := newLinks(repo)
+	if err != nil {
+		return err
+	}
+	p.Links = links
+
+	message, _ := p.createCommitMessage() // we have an error return for annotations, always nil here
+
+	_, err = gitinterface.Commit(repo, gitinterface.EmptyTree(), Ref, message, sign)
+	return err
+}
+
+func (p *PropagationEntry) createCommitMessage() (string, error) {
+	lines := []string{
+		PropagationEntryHeader,
+		"",
+		fmt.Sprintf("%s: %s", UpstreamRepositoryKey, p.UpstreamRepository),
+		fmt.Sprintf("%s: %s", UpstreamEntryIDKey, p.UpstreamEntryID.String()),
+	}
+	if p.Links != nil {
+		lines = append(lines, p.Links.lines(false)...)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
 // GetEntry returns the entry corresponding to entryID.
 func GetEntry(repo *git.Repository, entryID plumbing.Hash) (Entry, error) {
 	commitObj, err := gitinterface.GetCommit(repo, entryID)
@@ -232,7 +645,7 @@ func GetEntry(repo *git.Repository, entryID plumbing.Hash) (Entry, error) {
 		return nil, ErrRSLEntryNotFound
 	}
 
-	return parseRSLEntryText(entryID, commitObj.Message)
+	return loadRSLEntry(repo, entryID, commitObj.Message)
 }
 
 // GetParentForEntry returns the entry's parent RSL entry.
@@ -311,10 +724,15 @@ func GetNonGittufParentReferenceEntryForEntry(repo *git.Repository, entry Entry)
 	return targetEntry, annotations, nil
 }
 
-// GetLatestEntry returns the latest entry available locally in the RSL.
+// GetLatestEntry returns the latest entry available locally in the RSL. If the
+// RSL has not been created yet, such as in a freshly initialized repository,
+// ErrRSLEntryNotFound is returned.
 func GetLatestEntry(repo *git.Repository) (Entry, error) {
 	ref, err := repo.Reference(plumbing.ReferenceName(Ref), true)
 	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil, ErrRSLEntryNotFound
+		}
 		return nil, err
 	}
 
@@ -323,7 +741,7 @@ func GetLatestEntry(repo *git.Repository) (Entry, error) {
 		return nil, ErrRSLEntryNotFound
 	}
 
-	return parseRSLEntryText(commitObj.Hash, commitObj.Message)
+	return loadRSLEntry(repo, commitObj.Hash, commitObj.Message)
 }
 
 // GetLatestNonGittufReferenceEntry returns the first reference entry that is
@@ -371,8 +789,33 @@ func GetLatestReferenceEntryForRef(repo *git.Repository, refName string) (*Refer
 
 // GetLatestReferenceEntryForRefBefore returns the latest reference entry
 // available locally in the RSL for the specified refName before the specified
-// anchor.
+// anchor. Entries for refs outside the gittuf namespace may be recorded in RSL
+// shards, which are searched as well if the repository has any. If the anchor
+// is an entry in an RSL shard, it's placed in the RSL at the index entry that
+// records it.
 func GetLatestReferenceEntryForRefBefore(repo *git.Repository, refName string, anchor plumbing.Hash) (*ReferenceEntry, []*AnnotationEntry, error) {
+	searchShards := false
+	if !anchor.IsZero() {
+		_, searchShards = loadShardEntry(repo, anchor)
+	}
+	if !searchShards && !strings.HasPrefix(refName, gittufNamespacePrefix) {
+		var err error
+		searchShards, err = hasShards(repo)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if searchShards {
+		return getLatestReferenceEntryForRefBeforeIncludingShards(repo, refName, anchor)
+	}
+
+	targetEntry, annotations, err := getLatestReferenceEntryForRefBeforeUsingLinks(repo, refName, anchor)
+	if !errors.Is(err, errEntryNotLinked) {
+		return targetEntry, annotations, err
+	}
+
+	// Some of the entries don't have links, so we walk the RSL one entry at a
+	// time
 	allAnnotations := []*AnnotationEntry{}
 
 	iteratorT, err := GetLatestEntry(repo)
@@ -407,7 +850,6 @@ func GetLatestReferenceEntryForRefBefore(repo *git.Repository, refName string, a
 		}
 	}
 
-	var targetEntry *ReferenceEntry
 	for {
 		switch iterator := iteratorT.(type) {
 		case *ReferenceEntry:
@@ -429,7 +871,7 @@ func GetLatestReferenceEntryForRefBefore(repo *git.Repository, refName string, a
 		}
 	}
 
-	annotations := filterAnnotationsForRelevantAnnotations(allAnnotations, targetEntry.ID)
+	annotations = filterAnnotationsForRelevantAnnotations(allAnnotations, targetEntry.ID)
 
 	return targetEntry, annotations, nil
 }
@@ -464,6 +906,75 @@ func GetLatestUnskippedReferenceEntryForRefBefore(repo *git.Repository, refName
 	}
 }
 
+// GetLatestPropagationEntries returns the propagation entries in the RSL for
+// the specified upstream repository, starting with the latest entry.
+func GetLatestPropagationEntries(repo *git.Repository, upstreamRepository string) ([]*PropagationEntry, error) {
+	entries := []*PropagationEntry{}
+
+	iteratorT, err := GetLatestEntry(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if entry, isPropagation := iteratorT.(*PropagationEntry); isPropagation && entry.UpstreamRepository == upstreamRepository {
+			entries = append(entries, entry)
+		}
+
+		iteratorT, err = GetParentForEntry(repo, iteratorT)
+		if err != nil {
+			if errors.Is(err, ErrRSLEntryNotFound) {
+				break
+			}
+			return nil, err
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, ErrRSLEntryNotFound
+	}
+
+	return entries, nil
+}
+
+// GetLatestUnskippedReferenceEntriesFrom returns the latest reference entry
+// that is not marked as to-be-skipped for each ref outside the gittuf
+// namespace, walking the RSL backwards from the specified entry. As the entry
+// need not be in the local RSL, this can be used with entries fetched from
+// another repository's RSL.
+func GetLatestUnskippedReferenceEntriesFrom(repo *git.Repository, entryID plumbing.Hash) (map[string]*ReferenceEntry, error) {
+	entries := map[string]*ReferenceEntry{}
+	allAnnotations := []*AnnotationEntry{}
+
+	iteratorT, err := GetEntry(repo, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch iterator := iteratorT.(type) {
+		case *ReferenceEntry:
+			// Annotations always follow the entries they refer to, so all the
+			// relevant annotations have been seen
+			if _, seen := entries[iterator.RefName]; !seen && !strings.HasPrefix(iterator.RefName, gittufNamespacePrefix) && !iterator.SkippedBy(allAnnotations) {
+				entries[iterator.RefName] = iterator
+			}
+		case *AnnotationEntry:
+			allAnnotations = append(allAnnotations, iterator)
+		}
+
+		iteratorT, err = GetParentForEntry(repo, iteratorT)
+		if err != nil {
+			if errors.Is(err, ErrRSLEntryNotFound) {
+				break
+			}
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
 // GetFirstEntry returns the very first entry in the RSL. It is expected to be
 // a reference entry as the first entry in the RSL cannot be an annotation.
 func GetFirstEntry(repo *git.Repository) (*ReferenceEntry, []*AnnotationEntry, error) {
@@ -474,7 +985,7 @@ func GetFirstEntry(repo *git.Repository) (*ReferenceEntry, []*AnnotationEntry, e
 // specified ref. It is expected to be a reference entry as the first entry in
 // the RSL for a reference cannot be an annotation.
 func GetFirstReferenceEntryForRef(repo *git.Repository, targetRef string) (*ReferenceEntry, []*AnnotationEntry, error) {
-	iteratorT, err := GetLatestEntry(repo)
+	rslIterator, err := NewIterator(repo)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -483,6 +994,15 @@ func GetFirstReferenceEntryForRef(repo *git.Repository, targetRef string) (*Refe
 	var firstEntry *ReferenceEntry
 
 	for {
+		iteratorT, err := rslIterator.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, nil, err
+		}
+
 		switch entry := iteratorT.(type) {
 		case *ReferenceEntry:
 			if targetRef == "" || entry.RefName == targetRef {
@@ -491,17 +1011,6 @@ func GetFirstReferenceEntryForRef(repo *git.Repository, targetRef string) (*Refe
 		case *AnnotationEntry:
 			allAnnotations = append(allAnnotations, entry)
 		}
-
-		parentT, err := GetParentForEntry(repo, iteratorT)
-		if err != nil {
-			if errors.Is(err, ErrRSLEntryNotFound) {
-				break
-			}
-
-			return nil, nil, err
-		}
-
-		iteratorT = parentT
 	}
 
 	if firstEntry == nil {
@@ -576,33 +1085,49 @@ func GetReferenceEntriesInRange(repo *git.Repository, firstID, lastID plumbing.H
 // ref between the specified range and a map of annotations that refer to each
 // reference entry in the range. The annotations map is keyed by the ID of the
 // reference entry, with the value being a list of annotations that apply to
-// that reference entry.
+// that reference entry. If the ref's entries are recorded in an RSL shard, the
+// shard entries recorded by each index entry in the range are included after
+// the index entry, and the first and last entries may be shard entries.
 func GetReferenceEntriesInRangeForRef(repo *git.Repository, firstID, lastID plumbing.Hash, refName string) ([]*ReferenceEntry, map[plumbing.Hash][]*AnnotationEntry, error) {
+	// Shard entries are placed in the RSL at the index entries that record
+	// them
+	firstRSLID, err := getRSLEntryIDForEntry(repo, firstID)
+	if err != nil {
+		return nil, nil, err
+	}
+	lastRSLID, err := getRSLEntryIDForEntry(repo, lastID)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// We have to iterate from latest to get the annotations that refer to the
 	// last requested entry
-	iterator, err := GetLatestEntry(repo)
+	rslIterator, err := NewIterator(repo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	iterator, err := nextEntry(rslIterator)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	allAnnotations := []*AnnotationEntry{}
-	for iterator.GetID() != lastID {
+	for iterator.GetID() != lastRSLID {
 		// Until we find the entry corresponding to lastID, we just store
 		// annotations
 		if annotation, isAnnotation := iterator.(*AnnotationEntry); isAnnotation {
 			allAnnotations = append(allAnnotations, annotation)
 		}
 
-		parent, err := GetParentForEntry(repo, iterator)
+		iterator, err = nextEntry(rslIterator)
 		if err != nil {
 			return nil, nil, err
 		}
-		iterator = parent
 	}
 
 	entryStack := []*ReferenceEntry{}
-	inRange := map[plumbing.Hash]bool{}
-	for iterator.GetID() != firstID {
+	for iterator.GetID() != firstRSLID {
 		// Here, all items are relevant until the one corresponding to first is
 		// found
 		switch it := iterator.(type) {
@@ -613,17 +1138,15 @@ func GetReferenceEntriesInRangeForRef(repo *git.Repository, firstID, lastID plum
 				// b) the entry's refName matches the set refName, or
 				// c) the entry is for a gittuf namespace
 				entryStack = append(entryStack, it)
-				inRange[it.ID] = true
 			}
 		case *AnnotationEntry:
 			allAnnotations = append(allAnnotations, it)
 		}
 
-		parent, err := GetParentForEntry(repo, iterator)
+		iterator, err = nextEntry(rslIterator)
 		if err != nil {
 			return nil, nil, err
 		}
-		iterator = parent
 	}
 
 	// Handle the item corresponding to first explicitly
@@ -636,10 +1159,30 @@ func GetReferenceEntriesInRangeForRef(repo *git.Repository, firstID, lastID plum
 			// b) the entry's refName matches the set refName, or
 			// c) the entry is for a gittuf namespace
 			entryStack = append(entryStack, entry)
-			inRange[entry.ID] = true
 		}
 	}
 
+	// Reverse entryStack so that it's in order of occurrence rather than in
+	// order of walking back the RSL
+	allEntries := make([]*ReferenceEntry, 0, len(entryStack))
+	for i := len(entryStack) - 1; i >= 0; i-- {
+		allEntries = append(allEntries, entryStack[i])
+	}
+
+	if len(refName) != 0 && !strings.HasPrefix(refName, gittufNamespacePrefix) {
+		allEntries, err = expandShardEntries(repo, allEntries, refName)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		allEntries = trimEntriesToRange(allEntries, firstID, lastID)
+	}
+
+	inRange := map[plumbing.Hash]bool{}
+	for _, entry := range allEntries {
+		inRange[entry.ID] = true
+	}
+
 	// For each annotation, add the entry to each relevant entry it refers to
 	// Process annotations in reverse order so that annotations are listed in
 	// order of occurrence in the map
@@ -659,14 +1202,135 @@ func GetReferenceEntriesInRangeForRef(repo *git.Repository, firstID, lastID plum
 		}
 	}
 
-	// Reverse entryStack so that it's in order of occurrence rather than in
-	// order of walking back the RSL
-	allEntries := make([]*ReferenceEntry, 0, len(entryStack))
-	for i := len(entryStack) - 1; i >= 0; i-- {
-		allEntries = append(allEntries, entryStack[i])
+	return allEntries, annotationMap, nil
+}
+
+// nextEntry returns the iterator's next entry. Reaching the end of the RSL is
+// reported as ErrRSLEntryNotFound, as callers expect to find an entry.
+func nextEntry(iterator *Iterator) (Entry, error) {
+	entry, err := iterator.Next()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrRSLEntryNotFound
 	}
+	return entry, err
+}
 
-	return allEntries, annotationMap, nil
+// getReferenceEntryIDsInRangeForRef returns the IDs of all the reference
+// entries for refName between startID and endID, inclusive, in order of
+// occurrence. Both startID and endID must identify reference entries for
+// refName, and startID must precede or be the same as endID.
+func getReferenceEntryIDsInRangeForRef(repo *git.Repository, startID, endID plumbing.Hash, refName string) ([]plumbing.Hash, error) {
+	// Entries are parsed without being loaded, as annotations in the range
+	// don't need their own ranges resolved
+	commitObj, err := gitinterface.GetCommit(repo, endID)
+	if err != nil {
+		return nil, ErrRSLEntryNotFound
+	}
+	iterator, err := parseRSLEntryText(endID, commitObj.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry, isReferenceEntry := iterator.(*ReferenceEntry); !isReferenceEntry || entry.RefName != refName {
+		return nil, ErrInvalidAnnotationRange
+	}
+
+	idStack := []plumbing.Hash{}
+	for {
+		if entry, isReferenceEntry := iterator.(*ReferenceEntry); isReferenceEntry && entry.RefName == refName {
+			idStack = append(idStack, entry.ID)
+		}
+
+		if iterator.GetID() == startID {
+			break
+		}
+
+		if len(commitObj.ParentHashes) == 0 {
+			// We reached the start of the RSL without finding startID
+			return nil, ErrInvalidAnnotationRange
+		}
+		if len(commitObj.ParentHashes) > 1 {
+			return nil, ErrRSLBranchDetected
+		}
+
+		parentID := commitObj.ParentHashes[0]
+		commitObj, err = gitinterface.GetCommit(repo, parentID)
+		if err != nil {
+			return nil, ErrRSLEntryNotFound
+		}
+		iterator, err = parseRSLEntryText(parentID, commitObj.Message)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if entry, isReferenceEntry := iterator.(*ReferenceEntry); !isReferenceEntry || entry.RefName != refName {
+		return nil, ErrInvalidAnnotationRange
+	}
+
+	ids := make([]plumbing.Hash, 0, len(idStack))
+	for i := len(idStack) - 1; i >= 0; i-- {
+		ids = append(ids, idStack[i])
+	}
+
+	return ids, nil
+}
+
+// annotationRangeCache caches the IDs of the entries in the ranges of
+// annotations, keyed by the annotation's ID, so that each range is only walked
+// once. An annotation's ID determines every entry before it, so results remain
+// correct for the lifetime of the process.
+type annotationRangeCache struct {
+	mu       sync.Mutex
+	entryIDs map[plumbing.Hash][]plumbing.Hash
+}
+
+var resolvedAnnotationRanges = &annotationRangeCache{entryIDs: map[plumbing.Hash][]plumbing.Hash{}}
+
+// getEntryIDsForAnnotationRange returns the IDs of the entries in the
+// annotation's range, walking the RSL only if the range hasn't been resolved
+// before.
+func getEntryIDsForAnnotationRange(repo *git.Repository, annotation *AnnotationEntry) ([]plumbing.Hash, error) {
+	resolvedAnnotationRanges.mu.Lock()
+	entryIDs, has := resolvedAnnotationRanges.entryIDs[annotation.ID]
+	resolvedAnnotationRanges.mu.Unlock()
+	if has {
+		return slices.Clone(entryIDs), nil
+	}
+
+	entryIDs, err := getReferenceEntryIDsInRangeForRef(repo, annotation.RangeStartID, annotation.RangeEndID, annotation.RangeRefName)
+	if err != nil {
+		if errors.Is(err, ErrRSLEntryNotFound) {
+			return nil, ErrInvalidAnnotationRange
+		}
+		return nil, err
+	}
+
+	resolvedAnnotationRanges.mu.Lock()
+	resolvedAnnotationRanges.entryIDs[annotation.ID] = entryIDs
+	resolvedAnnotationRanges.mu.Unlock()
+
+	return slices.Clone(entryIDs), nil
+}
+
+// loadRSLEntry parses the RSL entry and, for annotations that apply to a range
+// of entries, resolves the entries in the range. An annotation with an invalid
+// range is treated as an invalid RSL entry.
+func loadRSLEntry(repo *git.Repository, id plumbing.Hash, text string) (Entry, error) {
+	entry, err := parseRSLEntryText(id, text)
+	if err != nil {
+		return nil, err
+	}
+
+	if annotation, isAnnotation := entry.(*AnnotationEntry); isAnnotation && annotation.IsRange() {
+		rslEntryIDs, err := getEntryIDsForAnnotationRange(repo, annotation)
+		if err != nil {
+			return nil, err
+		}
+		annotation.RSLEntryIDs = rslEntryIDs
+	}
+
+	return entry, nil
 }
 
 func parseRSLEntryText(id plumbing.Hash, text string) (Entry, error) {
@@ -674,33 +1338,117 @@ func parseRSLEntryText(id plumbing.Hash, text string) (Entry, error) {
 	if strings.HasPrefix(text, AnnotationEntryHeader) {
 		return parseAnnotationEntryText(id, text)
 	}
+	if strings.HasPrefix(text, PropagationEntryHeader) {
+		return parsePropagationEntryText(id, text)
+	}
 	return parseReferenceEntryText(id, text)
 }
 
 func parseReferenceEntryText(id plumbing.Hash, text string) (*ReferenceEntry, error) {
 	lines := strings.Split(text, "\n")
-	if len(lines) < 4 {
+	if len(lines) < 4 || lines[0] != ReferenceEntryHeader {
 		return nil, ErrInvalidRSLEntry
 	}
 	lines = lines[2:]
 
 	entry := &ReferenceEntry{ID: id}
+
+	messageBlock, _ := pem.Decode([]byte(text))
+	if messageBlock != nil {
+		entry.Message = string(messageBlock.Bytes)
+	}
+
+	changedPathsCount := -1
+	submodulesCount := -1
+	links := newLinksParser()
 	for _, l := range lines {
 		l = strings.TrimSpace(l)
+		if l == BeginMessage {
+			break
+		}
 
-		ls := strings.Split(l, ":")
-		if len(ls) < 2 {
+		key, value, found := strings.Cut(l, ":")
+		if !found {
 			return nil, ErrInvalidRSLEntry
 		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		if isLink, err := links.parse(key, value); err != nil {
+			return nil, err
+		} else if isLink {
+			continue
+		}
 
-		switch strings.TrimSpace(ls[0]) {
+		switch key {
 		case RefKey:
-			entry.RefName = strings.TrimSpace(ls[1])
+			entry.RefName = value
 		case TargetIDKey:
-			entry.TargetID = plumbing.NewHash(strings.TrimSpace(ls[1]))
+			targetID, err := parseHash(value)
+			if err != nil {
+				return nil, err
+			}
+			entry.TargetID = targetID
+		case ShardKey:
+			if ValidateShardName(value) != nil {
+				return nil, ErrInvalidRSLEntry
+			}
+			entry.Shard = value
+		case ChangedPathsKey:
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 0 {
+				return nil, ErrInvalidRSLEntry
+			}
+			changedPathsCount = count
+			entry.ChangedPaths = []string{}
+		case ChangedPathKey:
+			path, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, ErrInvalidRSLEntry
+			}
+			entry.ChangedPaths = append(entry.ChangedPaths, path)
+		case TicketKey:
+			if !isValidTicket(value) {
+				return nil, ErrInvalidRSLEntry
+			}
+			entry.Tickets = append(entry.Tickets, value)
+		case SubmodulesKey:
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 0 {
+				return nil, ErrInvalidRSLEntry
+			}
+			submodulesCount = count
+			entry.Submodules = map[string]plumbing.Hash{}
+		case SubmoduleKey:
+			commitID, quotedPath, found := strings.Cut(value, " ")
+			if !found || !plumbing.IsHash(commitID) || entry.Submodules == nil {
+				return nil, ErrInvalidRSLEntry
+			}
+			path, err := strconv.Unquote(quotedPath)
+			if err != nil {
+				return nil, ErrInvalidRSLEntry
+			}
+			entry.Submodules[path] = plumbing.NewHash(commitID)
 		}
 	}
 
+	if changedPathsCount != -1 && changedPathsCount != len(entry.ChangedPaths) {
+		return nil, ErrInvalidRSLEntry
+	}
+	if changedPathsCount == -1 && entry.ChangedPaths != nil {
+		// Paths without a count are not a complete record
+		return nil, ErrInvalidRSLEntry
+	}
+	if submodulesCount != -1 && submodulesCount != len(entry.Submodules) {
+		return nil, ErrInvalidRSLEntry
+	}
+
+	var err error
+	entry.Links, err = links.result(true)
+	if err != nil {
+		return nil, err
+	}
+
 	return entry, nil
 }
 
@@ -721,32 +1469,143 @@ func parseAnnotationEntryText(id plumbing.Hash, text string) (*AnnotationEntry,
 	}
 	lines = lines[2:]
 
+	links := newLinksParser()
 	for _, l := range lines {
 		l = strings.TrimSpace(l)
 		if l == BeginMessage {
 			break
 		}
 
-		ls := strings.Split(l, ":")
-		if len(ls) < 2 {
+		// Tickets are URIs that contain ':'
+		key, value, found := strings.Cut(l, ":")
+		if !found {
 			return nil, ErrInvalidRSLEntry
 		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
 
-		switch strings.TrimSpace(ls[0]) {
+		if isLink, err := links.parse(key, value); err != nil {
+			return nil, err
+		} else if isLink {
+			continue
+		}
+
+		switch key {
 		case EntryIDKey:
-			annotation.RSLEntryIDs = append(annotation.RSLEntryIDs, plumbing.NewHash(strings.TrimSpace(ls[1])))
+			entryID, err := parseHash(value)
+			if err != nil {
+				return nil, err
+			}
+			annotation.RSLEntryIDs = append(annotation.RSLEntryIDs, entryID)
+		case RangeRefKey:
+			annotation.RangeRefName = value
+		case RangeStartKey:
+			rangeStartID, err := parseHash(value)
+			if err != nil {
+				return nil, err
+			}
+			annotation.RangeStartID = rangeStartID
+		case RangeEndKey:
+			rangeEndID, err := parseHash(value)
+			if err != nil {
+				return nil, err
+			}
+			annotation.RangeEndID = rangeEndID
 		case SkipKey:
-			if strings.TrimSpace(ls[1]) == "true" {
+			if value == "true" {
 				annotation.Skip = true
 			} else {
 				annotation.Skip = false
 			}
+		case TicketKey:
+			if !isValidTicket(value) {
+				return nil, ErrInvalidRSLEntry
+			}
+			annotation.Tickets = append(annotation.Tickets, value)
 		}
 	}
 
+	if annotation.IsRange() {
+		// A range annotation must specify both bounds and cannot also list
+		// individual entries
+		if annotation.RangeStartID.IsZero() || annotation.RangeEndID.IsZero() || len(annotation.RSLEntryIDs) != 0 {
+			return nil, ErrInvalidRSLEntry
+		}
+	} else if len(annotation.RSLEntryIDs) == 0 {
+		// An annotation must refer to at least one entry
+		return nil, ErrInvalidRSLEntry
+	}
+
+	var err error
+	annotation.Links, err = links.result(false)
+	if err != nil {
+		return nil, err
+	}
+
 	return annotation, nil
 }
 
+func parsePropagationEntryText(id plumbing.Hash, text string) (*PropagationEntry, error) {
+	lines := strings.Split(text, "\n")
+	if len(lines) < 4 {
+		return nil, ErrInvalidRSLEntry
+	}
+	lines = lines[2:]
+
+	entry := &PropagationEntry{ID: id}
+	links := newLinksParser()
+	for _, l := range lines {
+		l = strings.TrimSpace(l)
+
+		// The upstream location may be a URL that contains ':'
+		key, value, found := strings.Cut(l, ":")
+		if !found {
+			return nil, ErrInvalidRSLEntry
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		if isLink, err := links.parse(key, value); err != nil {
+			return nil, err
+		} else if isLink {
+			continue
+		}
+
+		switch key {
+		case UpstreamRepositoryKey:
+			entry.UpstreamRepository = value
+		case UpstreamEntryIDKey:
+			upstreamEntryID, err := parseHash(value)
+			if err != nil {
+				return nil, err
+			}
+			entry.UpstreamEntryID = upstreamEntryID
+		}
+	}
+
+	if len(entry.UpstreamRepository) == 0 || entry.UpstreamEntryID.IsZero() {
+		return nil, ErrInvalidRSLEntry
+	}
+
+	var err error
+	entry.Links, err = links.result(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// parseHash parses an object ID recorded in an entry's text. Entries are
+// fetched from remotes, so unlike plumbing.NewHash, values that are not valid
+// object IDs are rejected rather than silently truncated or zero-padded.
+func parseHash(value string) (plumbing.Hash, error) {
+	if !plumbing.IsHash(value) {
+		return plumbing.ZeroHash, ErrInvalidRSLEntry
+	}
+	return plumbing.NewHash(value), nil
+}
+
 func filterAnnotationsForRelevantAnnotations(allAnnotations []*AnnotationEntry, entryID plumbing.Hash) []*AnnotationEntry {
 	annotations := []*AnnotationEntry{}
 	for _, annotation := range allAnnotations {
@@ -11,6 +11,8 @@ import (
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/stretchr/testify/assert"
 )
@@ -79,7 +81,7 @@ func TestNewReferenceEntry(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	expectedMessage := fmt.Sprintf("%s\n\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "main", TargetIDKey, plumbing.ZeroHash.String())
+	expectedMessage := fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: 1\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "main", TargetIDKey, plumbing.ZeroHash.String(), NumberKey, PreviousEntryIDKey, plumbing.ZeroHash.String(), PreviousAnnotationIDKey, plumbing.ZeroHash.String())
 	assert.Equal(t, expectedMessage, commitObj.Message)
 	assert.Empty(t, commitObj.ParentHashes)
 
@@ -99,7 +101,7 @@ func TestNewReferenceEntry(t *testing.T) {
 		t.Error(err)
 	}
 
-	expectedMessage = fmt.Sprintf("%s\n\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "main", TargetIDKey, plumbing.NewHash("abcdef1234567890"))
+	expectedMessage = fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: 2\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "main", TargetIDKey, plumbing.NewHash("abcdef1234567890"), NumberKey, PreviousEntryIDKey, originalRefHash.String(), PreviousAnnotationIDKey, plumbing.ZeroHash.String())
 	assert.Equal(t, expectedMessage, commitObj.Message)
 	assert.Contains(t, commitObj.ParentHashes, originalRefHash)
 }
@@ -110,10 +112,18 @@ func TestGetLatestEntry(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// The RSL doesn't exist yet
+	_, err = GetLatestEntry(repo)
+	assert.ErrorIs(t, err, ErrRSLEntryNotFound)
+
 	if err := InitializeNamespace(repo); err != nil {
 		t.Error(err)
 	}
 
+	// The RSL is empty
+	_, err = GetLatestEntry(repo)
+	assert.ErrorIs(t, err, ErrRSLEntryNotFound)
+
 	if err := NewReferenceEntry("main", plumbing.ZeroHash).Commit(repo, false); err != nil {
 		t.Error(err)
 	}
@@ -1268,6 +1278,218 @@ func TestAnnotationEntryRefersTo(t *testing.T) {
 	}
 }
 
+func TestAnnotationEntryForRange(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	mainEntryIDs := []plumbing.Hash{}
+	featureEntryIDs := []plumbing.Hash{}
+	for i := 0; i < 5; i++ {
+		if err := NewReferenceEntry("refs/heads/main", plumbing.ZeroHash).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+		latestEntry, err := GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mainEntryIDs = append(mainEntryIDs, latestEntry.GetID())
+
+		if err := NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+		latestEntry, err = GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		featureEntryIDs = append(featureEntryIDs, latestEntry.GetID())
+	}
+
+	t.Run("valid range", func(t *testing.T) {
+		if err := NewAnnotationEntryForRange("refs/heads/main", mainEntryIDs[1], mainEntryIDs[3], true, annotationMessage).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		latestEntry, err := GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		annotation := latestEntry.(*AnnotationEntry)
+		assert.True(t, annotation.IsRange())
+		assert.True(t, annotation.Skip)
+		assert.Equal(t, mainEntryIDs[1:4], annotation.RSLEntryIDs)
+		assert.False(t, annotation.RefersTo(mainEntryIDs[0]))
+		assert.False(t, annotation.RefersTo(featureEntryIDs[2]))
+
+		// The annotation is returned for entries in the range
+		_, annotations, err := GetReferenceEntriesInRangeForRef(repo, mainEntryIDs[0], mainEntryIDs[4], "refs/heads/main")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Nil(t, annotations[mainEntryIDs[0]])
+		assert.Equal(t, []*AnnotationEntry{annotation}, annotations[mainEntryIDs[2]])
+		assert.Nil(t, annotations[mainEntryIDs[4]])
+
+		// The range is resolved once and cached for later loads
+		resolvedAnnotationRanges.mu.Lock()
+		cachedEntryIDs := resolvedAnnotationRanges.entryIDs[annotation.ID]
+		resolvedAnnotationRanges.mu.Unlock()
+		assert.Equal(t, mainEntryIDs[1:4], cachedEntryIDs)
+
+		annotation.RSLEntryIDs[0] = plumbing.ZeroHash
+		entry, err := GetEntry(repo, annotation.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, mainEntryIDs[1:4], entry.(*AnnotationEntry).RSLEntryIDs)
+	})
+
+	t.Run("single entry range", func(t *testing.T) {
+		if err := NewAnnotationEntryForRange("refs/heads/main", mainEntryIDs[2], mainEntryIDs[2], false, annotationMessage).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		latestEntry, err := GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []plumbing.Hash{mainEntryIDs[2]}, latestEntry.(*AnnotationEntry).RSLEntryIDs)
+	})
+
+	t.Run("start after end", func(t *testing.T) {
+		err := NewAnnotationEntryForRange("refs/heads/main", mainEntryIDs[3], mainEntryIDs[1], true, annotationMessage).Commit(repo, false)
+		assert.ErrorIs(t, err, ErrInvalidAnnotationRange)
+	})
+
+	t.Run("bounds for different ref", func(t *testing.T) {
+		err := NewAnnotationEntryForRange("refs/heads/main", featureEntryIDs[0], mainEntryIDs[3], true, annotationMessage).Commit(repo, false)
+		assert.ErrorIs(t, err, ErrInvalidAnnotationRange)
+
+		err = NewAnnotationEntryForRange("refs/heads/main", mainEntryIDs[0], featureEntryIDs[3], true, annotationMessage).Commit(repo, false)
+		assert.ErrorIs(t, err, ErrInvalidAnnotationRange)
+	})
+
+	t.Run("unknown entry", func(t *testing.T) {
+		err := NewAnnotationEntryForRange("refs/heads/main", mainEntryIDs[0], plumbing.ZeroHash, true, annotationMessage).Commit(repo, false)
+		assert.ErrorIs(t, err, ErrRSLEntryNotFound)
+	})
+}
+
+func TestPropagationEntry(t *testing.T) {
+	upstreamRepository := "https://example.com/upstream.git"
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewReferenceEntry("refs/heads/main", plumbing.ZeroHash).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	upstreamEntry, err := GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = GetLatestPropagationEntries(repo, upstreamRepository)
+	assert.ErrorIs(t, err, ErrRSLEntryNotFound)
+
+	// The upstream entry must be available locally
+	err = NewPropagationEntry(upstreamRepository, plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12")).Commit(repo, false)
+	assert.ErrorIs(t, err, ErrRSLEntryNotFound)
+
+	err = NewPropagationEntry(upstreamRepository, upstreamEntry.GetID()).Commit(repo, false)
+	assert.Nil(t, err)
+
+	entry, err := GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	propagationEntry, isPropagation := entry.(*PropagationEntry)
+	if assert.True(t, isPropagation) {
+		assert.Equal(t, upstreamRepository, propagationEntry.UpstreamRepository)
+		assert.Equal(t, upstreamEntry.GetID(), propagationEntry.UpstreamEntryID)
+	}
+
+	if err := NewReferenceEntry("refs/heads/main", plumbing.ZeroHash).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := GetLatestPropagationEntries(repo, upstreamRepository)
+	assert.Nil(t, err)
+	assert.Equal(t, []*PropagationEntry{propagationEntry}, entries)
+
+	_, err = GetLatestPropagationEntries(repo, "https://example.com/other.git")
+	assert.ErrorIs(t, err, ErrRSLEntryNotFound)
+
+	// Propagation entries are ignored when looking for reference entries
+	latestEntry, _, err := GetLatestReferenceEntryForRef(repo, "refs/heads/main")
+	assert.Nil(t, err)
+	assert.Equal(t, "refs/heads/main", latestEntry.RefName)
+}
+
+func TestGetLatestUnskippedReferenceEntriesFrom(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewReferenceEntry("refs/heads/main", plumbing.ZeroHash).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	mainEntry, err := GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	featureEntry, err := GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewReferenceEntry("refs/gittuf/policy", plumbing.ZeroHash).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewReferenceEntry("refs/heads/main", plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12")).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	skippedEntry, err := GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewAnnotationEntry([]plumbing.Hash{skippedEntry.GetID()}, true, annotationMessage).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	latestEntry, err := GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := GetLatestUnskippedReferenceEntriesFrom(repo, latestEntry.GetID())
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]*ReferenceEntry{
+		"refs/heads/main":    mainEntry.(*ReferenceEntry),
+		"refs/heads/feature": featureEntry.(*ReferenceEntry),
+	}, entries)
+
+	// Without the annotation, the skipped entry is the latest for main
+	entries, err = GetLatestUnskippedReferenceEntriesFrom(repo, skippedEntry.GetID())
+	assert.Nil(t, err)
+	assert.Equal(t, skippedEntry.GetID(), entries["refs/heads/main"].ID)
+
+	entries, err = GetLatestUnskippedReferenceEntriesFrom(repo, mainEntry.GetID())
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]*ReferenceEntry{"refs/heads/main": mainEntry.(*ReferenceEntry)}, entries)
+}
+
 func TestReferenceEntryCreateCommitMessage(t *testing.T) {
 	tests := map[string]struct {
 		entry           *ReferenceEntry
@@ -1285,7 +1507,47 @@ func TestReferenceEntryCreateCommitMessage(t *testing.T) {
 				RefName:  "refs/heads/main",
 				TargetID: plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12"),
 			},
-			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, "abcdef12345678900987654321fedcbaabcdef12"),
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12").String()),
+		},
+		"entry, with changed paths": {
+			entry:           NewReferenceEntryWithChangedPaths("refs/heads/main", plumbing.ZeroHash, []string{"docs", "odd:name\n"}),
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: 2\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), ChangedPathsKey, ChangedPathKey, `"docs"`, ChangedPathKey, `"odd:name\n"`),
+		},
+		"entry, with no changed paths": {
+			entry:           NewReferenceEntryWithChangedPaths("refs/heads/main", plumbing.ZeroHash, nil),
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: 0", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), ChangedPathsKey),
+		},
+		"entry, with submodules": {
+			entry: &ReferenceEntry{
+				RefName:    "refs/heads/main",
+				TargetID:   plumbing.ZeroHash,
+				Submodules: map[string]plumbing.Hash{"vendor/tool": gitinterface.EmptyTree(), "lib": gitinterface.EmptyBlob()},
+			},
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: 2\n%s: %s %s\n%s: %s %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), SubmodulesKey, SubmoduleKey, gitinterface.EmptyBlob().String(), `"lib"`, SubmoduleKey, gitinterface.EmptyTree().String(), `"vendor/tool"`),
+		},
+		"entry, with no submodules": {
+			entry: &ReferenceEntry{
+				RefName:    "refs/heads/main",
+				TargetID:   plumbing.ZeroHash,
+				Submodules: map[string]plumbing.Hash{},
+			},
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: 0", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), SubmodulesKey),
+		},
+		"entry, with tickets": {
+			entry: &ReferenceEntry{
+				RefName:  "refs/heads/main",
+				TargetID: plumbing.ZeroHash,
+				Tickets:  []string{"https://example.com/issues/1", "urn:jira:GTF-2"},
+			},
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), TicketKey, "https://example.com/issues/1", TicketKey, "urn:jira:GTF-2"),
+		},
+		"entry, with message": {
+			entry: &ReferenceEntry{
+				RefName:  "refs/heads/main",
+				TargetID: plumbing.ZeroHash,
+				Message:  "Hotfix for outage",
+			},
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s\n%s\n%s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), BeginMessage, base64.StdEncoding.EncodeToString([]byte("Hotfix for outage")), EndMessage),
 		},
 	}
 
@@ -1299,6 +1561,128 @@ func TestReferenceEntryCreateCommitMessage(t *testing.T) {
 	}
 }
 
+func TestGetChangedPaths(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	blobID, err := gitinterface.WriteBlob(repo, []byte("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeA, err := gitinterface.WriteTree(repo, []object.TreeEntry{{Name: "a", Mode: filemode.Regular, Hash: blobID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeB, err := gitinterface.WriteTree(repo, []object.TreeEntry{
+		{Name: "a", Mode: filemode.Regular, Hash: blobID},
+		{Name: "b", Mode: filemode.Regular, Hash: blobID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mainRef := "refs/heads/main"
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(mainRef), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+	commitA, err := gitinterface.Commit(repo, treeA, mainRef, "Test commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitB, err := gitinterface.Commit(repo, treeB, mainRef, "Test commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := GetChangedPaths(repo, nil, commitA)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a"}, paths)
+
+	paths, err = GetChangedPaths(repo, NewReferenceEntry(mainRef, plumbing.ZeroHash), commitA)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a"}, paths)
+
+	paths, err = GetChangedPaths(repo, NewReferenceEntry(mainRef, commitA), commitB)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"b"}, paths)
+
+	// Record and read back the entry
+	if err := NewReferenceEntryWithChangedPaths(mainRef, commitB, paths).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	entry, _, err := GetLatestReferenceEntryForRef(repo, mainRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"b"}, entry.ChangedPaths)
+
+	_, err = GetChangedPaths(repo, nil, treeB)
+	assert.ErrorIs(t, err, ErrChangedPathsNeedCommits)
+}
+
+func TestGetSubmoduleCommits(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	libID := plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12")
+	toolID := plumbing.NewHash("1234567890abcdef1234567890abcdef12345678")
+
+	blobID, err := gitinterface.WriteBlob(repo, []byte("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vendorTree, err := gitinterface.WriteTree(repo, []object.TreeEntry{{Name: "tool", Mode: filemode.Submodule, Hash: toolID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeWithSubmodules, err := gitinterface.WriteTree(repo, []object.TreeEntry{
+		{Name: "README", Mode: filemode.Regular, Hash: blobID},
+		{Name: "lib", Mode: filemode.Submodule, Hash: libID},
+		{Name: "vendor", Mode: filemode.Dir, Hash: vendorTree},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeWithoutSubmodules, err := gitinterface.WriteTree(repo, []object.TreeEntry{{Name: "README", Mode: filemode.Regular, Hash: blobID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mainRef := "refs/heads/main"
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(mainRef), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+	commitWithSubmodules, err := gitinterface.Commit(repo, treeWithSubmodules, mainRef, "Test commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitWithoutSubmodules, err := gitinterface.Commit(repo, treeWithoutSubmodules, mainRef, "Test commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	submodules, err := GetSubmoduleCommits(repo, commitWithSubmodules)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]plumbing.Hash{"lib": libID, "vendor/tool": toolID}, submodules)
+
+	submodules, err = GetSubmoduleCommits(repo, commitWithoutSubmodules)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]plumbing.Hash{}, submodules)
+
+	_, err = GetSubmoduleCommits(repo, treeWithSubmodules)
+	assert.ErrorIs(t, err, ErrSubmodulesNeedCommits)
+}
+
 func TestAnnotationEntryCreateCommitMessage(t *testing.T) {
 	tests := map[string]struct {
 		entry           *AnnotationEntry
@@ -1344,6 +1728,19 @@ func TestAnnotationEntryCreateCommitMessage(t *testing.T) {
 			},
 			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s", AnnotationEntryHeader, EntryIDKey, plumbing.ZeroHash.String(), EntryIDKey, plumbing.ZeroHash.String(), SkipKey, "false"),
 		},
+		"annotation, range, no message": {
+			entry:           NewAnnotationEntryForRange("refs/heads/main", gitinterface.EmptyBlob(), gitinterface.EmptyTree(), true, ""),
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s\n%s: %s", AnnotationEntryHeader, RangeRefKey, "refs/heads/main", RangeStartKey, gitinterface.EmptyBlob().String(), RangeEndKey, gitinterface.EmptyTree().String(), SkipKey, "true"),
+		},
+		"annotation, with ticket and message": {
+			entry: &AnnotationEntry{
+				RSLEntryIDs: []plumbing.Hash{plumbing.ZeroHash},
+				Skip:        true,
+				Message:     "message",
+				Tickets:     []string{"https://example.com/issues/1"},
+			},
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s\n%s\n%s\n%s", AnnotationEntryHeader, EntryIDKey, plumbing.ZeroHash.String(), SkipKey, "true", TicketKey, "https://example.com/issues/1", BeginMessage, base64.StdEncoding.EncodeToString([]byte("message")), EndMessage),
+		},
 	}
 
 	for name, test := range tests {
@@ -1379,7 +1776,81 @@ func TestParseRSLEntryText(t *testing.T) {
 				RefName:  "refs/heads/main",
 				TargetID: plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12"),
 			},
-			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, "abcdef12345678900987654321fedcbaabcdef12"),
+			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12").String()),
+		},
+		"entry, with changed paths": {
+			expectedEntry: &ReferenceEntry{
+				ID:           plumbing.ZeroHash,
+				RefName:      "refs/heads/main",
+				TargetID:     plumbing.ZeroHash,
+				ChangedPaths: []string{"docs", "odd:name\n"},
+			},
+			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: 2\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), ChangedPathsKey, ChangedPathKey, `"docs"`, ChangedPathKey, `"odd:name\n"`),
+		},
+		"entry, with no changed paths": {
+			expectedEntry: &ReferenceEntry{
+				ID:           plumbing.ZeroHash,
+				RefName:      "refs/heads/main",
+				TargetID:     plumbing.ZeroHash,
+				ChangedPaths: []string{},
+			},
+			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: 0", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), ChangedPathsKey),
+		},
+		"entry, invalid target ID": {
+			expectedError: ErrInvalidRSLEntry,
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, "abcdef1234567890"),
+		},
+		"entry, changed paths count mismatch": {
+			expectedError: ErrInvalidRSLEntry,
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: 2\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), ChangedPathsKey, ChangedPathKey, `"docs"`),
+		},
+		"entry, changed paths without count": {
+			expectedError: ErrInvalidRSLEntry,
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), ChangedPathKey, `"docs"`),
+		},
+		"entry, unquoted changed path": {
+			expectedError: ErrInvalidRSLEntry,
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: 1\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), ChangedPathsKey, ChangedPathKey, "docs"),
+		},
+		"entry, with tickets": {
+			expectedEntry: &ReferenceEntry{
+				ID:       plumbing.ZeroHash,
+				RefName:  "refs/heads/main",
+				TargetID: plumbing.ZeroHash,
+				Tickets:  []string{"https://example.com/issues/1", "urn:jira:GTF-2"},
+			},
+			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), TicketKey, "https://example.com/issues/1", TicketKey, "urn:jira:GTF-2"),
+		},
+		"entry, with message": {
+			expectedEntry: &ReferenceEntry{
+				ID:       plumbing.ZeroHash,
+				RefName:  "refs/heads/main",
+				TargetID: plumbing.ZeroHash,
+				Tickets:  []string{"https://example.com/issues/1"},
+				Message:  "Hotfix for outage\nticket: not a ticket",
+			},
+			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s\n%s\n%s\n%s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), TicketKey, "https://example.com/issues/1", BeginMessage, base64.StdEncoding.EncodeToString([]byte("Hotfix for outage\nticket: not a ticket")), EndMessage),
+		},
+		"entry, with submodules": {
+			expectedEntry: &ReferenceEntry{
+				ID:         plumbing.ZeroHash,
+				RefName:    "refs/heads/main",
+				TargetID:   plumbing.ZeroHash,
+				Submodules: map[string]plumbing.Hash{"lib": gitinterface.EmptyBlob(), "odd path": gitinterface.EmptyTree()},
+			},
+			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: 2\n%s: %s %s\n%s: %s %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), SubmodulesKey, SubmoduleKey, gitinterface.EmptyBlob().String(), `"lib"`, SubmoduleKey, gitinterface.EmptyTree().String(), `"odd path"`),
+		},
+		"entry, submodules count mismatch": {
+			expectedError: ErrInvalidRSLEntry,
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: 2\n%s: %s %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), SubmodulesKey, SubmoduleKey, gitinterface.EmptyBlob().String(), `"lib"`),
+		},
+		"entry, submodule without count": {
+			expectedError: ErrInvalidRSLEntry,
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), SubmoduleKey, gitinterface.EmptyBlob().String(), `"lib"`),
+		},
+		"entry, invalid ticket": {
+			expectedError: ErrInvalidRSLEntry,
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String(), TicketKey, "GTF-2"),
 		},
 		"entry, missing header": {
 			expectedError: ErrInvalidRSLEntry,
@@ -1389,6 +1860,18 @@ func TestParseRSLEntryText(t *testing.T) {
 			expectedError: ErrInvalidRSLEntry,
 			message:       fmt.Sprintf("%s\n\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main"),
 		},
+		"propagation entry": {
+			expectedEntry: &PropagationEntry{
+				ID:                 plumbing.ZeroHash,
+				UpstreamRepository: "https://example.com/upstream.git",
+				UpstreamEntryID:    plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12"),
+			},
+			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s", PropagationEntryHeader, UpstreamRepositoryKey, "https://example.com/upstream.git", UpstreamEntryIDKey, plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12").String()),
+		},
+		"propagation entry, missing information": {
+			expectedError: ErrInvalidRSLEntry,
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s", PropagationEntryHeader, UpstreamRepositoryKey, "https://example.com/upstream.git", UpstreamEntryIDKey, plumbing.ZeroHash.String()),
+		},
 		"annotation, no message": {
 			expectedEntry: &AnnotationEntry{
 				ID:          plumbing.ZeroHash,
@@ -1434,6 +1917,36 @@ func TestParseRSLEntryText(t *testing.T) {
 			},
 			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s", AnnotationEntryHeader, EntryIDKey, plumbing.ZeroHash.String(), EntryIDKey, plumbing.ZeroHash.String(), SkipKey, "false"),
 		},
+		"annotation, range, skip true": {
+			expectedEntry: &AnnotationEntry{
+				ID:           plumbing.ZeroHash,
+				RSLEntryIDs:  []plumbing.Hash{},
+				Skip:         true,
+				Message:      "",
+				RangeRefName: "refs/heads/main",
+				RangeStartID: gitinterface.EmptyBlob(),
+				RangeEndID:   gitinterface.EmptyTree(),
+			},
+			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s\n%s: %s", AnnotationEntryHeader, RangeRefKey, "refs/heads/main", RangeStartKey, gitinterface.EmptyBlob().String(), RangeEndKey, gitinterface.EmptyTree().String(), SkipKey, "true"),
+		},
+		"annotation, with ticket and message": {
+			expectedEntry: &AnnotationEntry{
+				ID:          plumbing.ZeroHash,
+				RSLEntryIDs: []plumbing.Hash{plumbing.ZeroHash},
+				Skip:        true,
+				Message:     "message",
+				Tickets:     []string{"https://example.com/issues/1"},
+			},
+			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s\n%s\n%s\n%s", AnnotationEntryHeader, EntryIDKey, plumbing.ZeroHash.String(), SkipKey, "true", TicketKey, "https://example.com/issues/1", BeginMessage, base64.StdEncoding.EncodeToString([]byte("message")), EndMessage),
+		},
+		"annotation, range missing end": {
+			expectedError: ErrInvalidRSLEntry,
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s", AnnotationEntryHeader, RangeRefKey, "refs/heads/main", RangeStartKey, gitinterface.EmptyBlob().String(), SkipKey, "true"),
+		},
+		"annotation, range and entry IDs": {
+			expectedError: ErrInvalidRSLEntry,
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s\n%s: %s\n%s: %s", AnnotationEntryHeader, EntryIDKey, plumbing.ZeroHash.String(), RangeRefKey, "refs/heads/main", RangeStartKey, gitinterface.EmptyBlob().String(), RangeEndKey, gitinterface.EmptyTree().String(), SkipKey, "true"),
+		},
 		"annotation, missing header": {
 			expectedError: ErrInvalidRSLEntry,
 			message:       fmt.Sprintf("%s: %s\n%s: %s\n%s\n%s\n%s", EntryIDKey, plumbing.ZeroHash.String(), SkipKey, "true", BeginMessage, base64.StdEncoding.EncodeToString([]byte("message")), EndMessage),
@@ -1442,6 +1955,14 @@ func TestParseRSLEntryText(t *testing.T) {
 			expectedError: ErrInvalidRSLEntry,
 			message:       fmt.Sprintf("%s\n\n%s: %s", AnnotationEntryHeader, EntryIDKey, plumbing.ZeroHash.String()),
 		},
+		"annotation, no entry IDs": {
+			expectedError: ErrInvalidRSLEntry,
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s", AnnotationEntryHeader, SkipKey, "false", TicketKey, "https://example.com/issues/1"),
+		},
+		"annotation, invalid entry ID": {
+			expectedError: ErrInvalidRSLEntry,
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s", AnnotationEntryHeader, EntryIDKey, "abcdef", SkipKey, "false"),
+		},
 	}
 
 	for name, test := range tests {
@@ -1468,3 +1989,98 @@ func assertAnnotationsReferToEntry(t *testing.T, entry *ReferenceEntry, annotati
 		assert.Equal(t, annotationMessage, annotation.Message)
 	}
 }
+
+func TestValidateTickets(t *testing.T) {
+	tests := map[string]struct {
+		tickets       []string
+		expectedError error
+	}{
+		"no tickets": {
+			tickets: nil,
+		},
+		"URLs and URNs": {
+			tickets: []string{"https://github.com/gittuf/gittuf/issues/1", "urn:jira:GTF-2"},
+		},
+		"ticket ID without scheme": {
+			tickets:       []string{"GTF-2"},
+			expectedError: ErrInvalidTicket,
+		},
+		"scheme without location": {
+			tickets:       []string{"https:"},
+			expectedError: ErrInvalidTicket,
+		},
+		"whitespace": {
+			tickets:       []string{"https://example.com/issues/1 https://example.com/issues/2"},
+			expectedError: ErrInvalidTicket,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateTickets(test.tickets)
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+// FuzzParseRSLEntryText checks that parsing RSL entries, which are fetched
+// from remotes, does not panic on arbitrary input, and that any entry that is
+// parsed is recorded identically when it is written back to the RSL. Inputs
+// that caused failures are recorded in testdata/fuzz/FuzzParseRSLEntryText.
+func FuzzParseRSLEntryText(f *testing.F) {
+	entryID := plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12")
+	seeds := []Entry{
+		&ReferenceEntry{RefName: "refs/heads/main", TargetID: entryID},
+		&ReferenceEntry{
+			RefName:      "refs/heads/main",
+			TargetID:     entryID,
+			ChangedPaths: []string{"docs", "odd:name\n"},
+			Tickets:      []string{"https://example.com/issues/1"},
+			Submodules:   map[string]plumbing.Hash{"lib/dep": entryID},
+			Links: &Links{
+				Number:               CheckpointInterval,
+				PreviousEntryID:      entryID,
+				PreviousAnnotationID: entryID,
+				Checkpoint:           map[string]plumbing.Hash{"refs/heads/main": entryID},
+			},
+		},
+		&AnnotationEntry{RSLEntryIDs: []plumbing.Hash{entryID}, Skip: true, Message: annotationMessage},
+		&AnnotationEntry{
+			RangeRefName: "refs/heads/main",
+			RangeStartID: entryID,
+			RangeEndID:   entryID,
+			Tickets:      []string{"urn:jira:GTF-2"},
+			Links:        &Links{Number: 2, PreviousAnnotationID: entryID},
+		},
+		&PropagationEntry{UpstreamRepository: "https://example.com/upstream", UpstreamEntryID: entryID},
+	}
+	for _, seed := range seeds {
+		message, err := seed.createCommitMessage()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(message)
+	}
+
+	f.Fuzz(func(t *testing.T, text string) {
+		entry, err := parseRSLEntryText(plumbing.ZeroHash, text)
+		if err != nil {
+			return
+		}
+
+		message, err := entry.createCommitMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reparsedEntry, err := parseRSLEntryText(plumbing.ZeroHash, message)
+		if err != nil {
+			t.Fatalf("unable to parse written entry %q: %v", message, err)
+		}
+		assert.Equal(t, entry, reparsedEntry)
+	})
+}
@@ -0,0 +1,446 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rsl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	// ShardRefPrefix is the namespace of the refs for RSL shards. Each shard
+	// is a separate chain of reference entries for the refs in a namespace,
+	// such as refs/gittuf/rsl/team-a. The RSL records the tip of a shard each
+	// time entries are added to it in an index entry, i.e., a reference entry
+	// for the shard's ref.
+	ShardRefPrefix = "refs/gittuf/rsl/"
+	ShardKey       = "shard"
+
+	remoteShardTrackerRefPrefix = "refs/remotes/%s/gittuf/rsl/"
+)
+
+var (
+	ErrInvalidShardName = errors.New("RSL shard name must be a single valid ref name component")
+	ErrShardDiverged    = errors.New("RSL shard does not extend the shard tip recorded in the previous index entry")
+)
+
+// ShardRef returns the ref for the RSL shard with the specified name.
+func ShardRef(name string) string {
+	return ShardRefPrefix + name
+}
+
+// RemoteShardTrackerRefPrefix returns the namespace of the remote tracking refs
+// for the RSL shards of the specified remote. For example, for 'origin', the
+// remote tracker ref for the shard 'team-a' is
+// 'refs/remotes/origin/gittuf/rsl/team-a'.
+func RemoteShardTrackerRefPrefix(remote string) string {
+	return fmt.Sprintf(remoteShardTrackerRefPrefix, remote)
+}
+
+// IsShardRef returns true if the ref is the ref for an RSL shard.
+func IsShardRef(refName string) bool {
+	return strings.HasPrefix(refName, ShardRefPrefix) && len(refName) > len(ShardRefPrefix)
+}
+
+// ValidateShardName checks that the name can be used for an RSL shard.
+func ValidateShardName(name string) error {
+	if name == "" || strings.Contains(name, "/") {
+		return ErrInvalidShardName
+	}
+
+	if err := plumbing.ReferenceName(ShardRef(name)).Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidShardName, err)
+	}
+
+	return nil
+}
+
+// CommitToShard records the entry in the specified RSL shard instead of the
+// RSL. The entry is added to the shard tip recorded in the latest index entry
+// for the shard, and an index entry recording the new shard tip is then added
+// to the RSL. Entries in the shard that were never indexed, such as when a
+// previous attempt failed to update the RSL, are not part of the RSL and are
+// discarded. If the index entry can't be added, the shard is reset to its
+// indexed tip.
+func (e *ReferenceEntry) CommitToShard(repo *git.Repository, shard string, sign bool) error {
+	if err := ValidateShardName(shard); err != nil {
+		return err
+	}
+
+	if err := ValidateTickets(e.Tickets); err != nil {
+		return err
+	}
+
+	shardRef := ShardRef(shard)
+	shardTip := plumbing.ZeroHash
+	indexEntry, _, err := GetLatestReferenceEntryForRef(repo, shardRef)
+	if err == nil {
+		shardTip = indexEntry.TargetID
+	} else if !errors.Is(err, ErrRSLEntryNotFound) {
+		return err
+	}
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(shardRef), shardTip)); err != nil {
+		return err
+	}
+
+	// Links point to earlier entries in the RSL, they're set for the index
+	// entry instead
+	e.Shard = shard
+	e.Links = nil
+
+	message, err := e.createCommitMessage()
+	if err != nil {
+		return err
+	}
+
+	newShardTip, err := gitinterface.Commit(repo, gitinterface.EmptyTree(), shardRef, message, sign)
+	if err != nil {
+		return err
+	}
+
+	if err := NewReferenceEntry(shardRef, newShardTip).Commit(repo, sign); err != nil {
+		if resetErr := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(shardRef), shardTip)); resetErr != nil {
+			return errors.Join(err, resetErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetShardEntries returns the entries recorded in the RSL shard by the index
+// entry, i.e., the entries added to the shard after the tip recorded in the
+// previous index entry for the shard, in order of occurrence.
+func GetShardEntries(repo *git.Repository, indexEntry *ReferenceEntry) ([]*ReferenceEntry, error) {
+	if !IsShardRef(indexEntry.RefName) {
+		return nil, ErrRSLEntryDoesNotMatchRef
+	}
+
+	previousIndexEntry, err := getPreviousIndexEntry(repo, indexEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	return getShardEntriesAfter(repo, indexEntry, previousIndexEntry)
+}
+
+// GetIndexEntryForShardEntry returns the index entry in the RSL that records
+// the shard entry.
+func GetIndexEntryForShardEntry(repo *git.Repository, shardEntry *ReferenceEntry) (*ReferenceEntry, error) {
+	indexEntry, _, err := GetLatestReferenceEntryForRef(repo, ShardRef(shardEntry.Shard))
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		previousIndexEntry, err := getPreviousIndexEntry(repo, indexEntry)
+		if err != nil {
+			return nil, err
+		}
+
+		shardEntries, err := getShardEntriesAfter(repo, indexEntry, previousIndexEntry)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range shardEntries {
+			if entry.ID == shardEntry.ID {
+				return indexEntry, nil
+			}
+		}
+
+		if previousIndexEntry == nil {
+			// The entry was never indexed
+			return nil, ErrRSLEntryNotFound
+		}
+		indexEntry = previousIndexEntry
+	}
+}
+
+// hasShards returns true if the repository has refs for RSL shards. The shards
+// are fetched and pushed with the RSL, so the shard entries recorded in the RSL
+// are only available if the shard refs are.
+func hasShards(repo *git.Repository) (bool, error) {
+	refs, err := repo.References()
+	if err != nil {
+		return false, err
+	}
+	defer refs.Close()
+
+	for {
+		ref, err := refs.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return false, nil
+			}
+			return false, err
+		}
+
+		if IsShardRef(ref.Name().String()) {
+			return true, nil
+		}
+	}
+}
+
+// getLatestReferenceEntryForRefBeforeIncludingShards walks back the RSL from
+// the anchor, searching the entries in the RSL and the shard entries recorded
+// by each index entry in order of occurrence. A shard entry is placed in the
+// RSL at the index entry that records it. The ref's entries may be recorded in
+// any shard, as the policy may have moved the ref between shards and the RSL.
+// The anchor may be an entry in the RSL or in a shard, or zero to search the
+// entire RSL.
+func getLatestReferenceEntryForRefBeforeIncludingShards(repo *git.Repository, refName string, anchor plumbing.Hash) (*ReferenceEntry, []*AnnotationEntry, error) {
+	rslAnchor := anchor
+	var shardAnchor *ReferenceEntry
+	if !anchor.IsZero() {
+		if shardEntry, isShardEntry := loadShardEntry(repo, anchor); isShardEntry {
+			indexEntry, err := GetIndexEntryForShardEntry(repo, shardEntry)
+			if err != nil {
+				return nil, nil, err
+			}
+			rslAnchor = indexEntry.ID
+			shardAnchor = shardEntry
+		}
+	}
+
+	allAnnotations := []*AnnotationEntry{}
+
+	iteratorT, err := GetLatestEntry(repo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !rslAnchor.IsZero() {
+		for iteratorT.GetID() != rslAnchor {
+			if annotation, isAnnotation := iteratorT.(*AnnotationEntry); isAnnotation {
+				allAnnotations = append(allAnnotations, annotation)
+			}
+
+			iteratorT, err = GetParentForEntry(repo, iteratorT)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if shardAnchor == nil {
+			// Only entries strictly before the anchor are considered
+			if annotation, isAnnotation := iteratorT.(*AnnotationEntry); isAnnotation {
+				allAnnotations = append(allAnnotations, annotation)
+			}
+
+			iteratorT, err = GetParentForEntry(repo, iteratorT)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	for {
+		switch iterator := iteratorT.(type) {
+		case *ReferenceEntry:
+			if iterator.RefName == refName {
+				return iterator, filterAnnotationsForRelevantAnnotations(allAnnotations, iterator.ID), nil
+			}
+
+			if IsShardRef(iterator.RefName) {
+				shardEntries, err := GetShardEntries(repo, iterator)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				if shardAnchor != nil && iterator.ID == rslAnchor {
+					// Only the shard entries before the anchor precede it
+					anchorIndex := -1
+					for i, shardEntry := range shardEntries {
+						if shardEntry.ID == shardAnchor.ID {
+							anchorIndex = i
+							break
+						}
+					}
+					if anchorIndex == -1 {
+						return nil, nil, ErrRSLEntryNotFound
+					}
+					shardEntries = shardEntries[:anchorIndex]
+				}
+
+				for i := len(shardEntries) - 1; i >= 0; i-- {
+					if shardEntries[i].RefName == refName {
+						return shardEntries[i], filterAnnotationsForRelevantAnnotations(allAnnotations, shardEntries[i].ID), nil
+					}
+				}
+			}
+		case *AnnotationEntry:
+			allAnnotations = append(allAnnotations, iterator)
+		}
+
+		iteratorT, err = GetParentForEntry(repo, iteratorT)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// getPreviousIndexEntry returns the index entry for the same shard before the
+// index entry, or nil if it's the first index entry for the shard.
+func getPreviousIndexEntry(repo *git.Repository, indexEntry *ReferenceEntry) (*ReferenceEntry, error) {
+	previousIndexEntry, _, err := GetLatestReferenceEntryForRefBefore(repo, indexEntry.RefName, indexEntry.ID)
+	if err != nil {
+		if errors.Is(err, ErrRSLEntryNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return previousIndexEntry, nil
+}
+
+// getShardEntriesAfter returns the shard entries between the shard tip
+// recorded in the previous index entry, which may be nil, and the tip recorded
+// in the index entry, in order of occurrence. The shard must extend the
+// previous tip.
+func getShardEntriesAfter(repo *git.Repository, indexEntry, previousIndexEntry *ReferenceEntry) ([]*ReferenceEntry, error) {
+	shard := strings.TrimPrefix(indexEntry.RefName, ShardRefPrefix)
+	previousTip := plumbing.ZeroHash
+	if previousIndexEntry != nil {
+		previousTip = previousIndexEntry.TargetID
+	}
+
+	entryStack := []*ReferenceEntry{}
+	entryID := indexEntry.TargetID
+	for entryID != previousTip {
+		if entryID.IsZero() {
+			// The start of the shard was reached without finding the
+			// previous tip, so the shard was rewritten
+			return nil, fmt.Errorf("%w: '%s'", ErrShardDiverged, indexEntry.RefName)
+		}
+
+		commitObj, err := gitinterface.GetCommit(repo, entryID)
+		if err != nil {
+			return nil, ErrRSLEntryNotFound
+		}
+		if len(commitObj.ParentHashes) > 1 {
+			return nil, ErrRSLBranchDetected
+		}
+
+		entryT, err := parseRSLEntryText(entryID, commitObj.Message)
+		if err != nil {
+			return nil, err
+		}
+		entry, isReferenceEntry := entryT.(*ReferenceEntry)
+		if !isReferenceEntry || entry.Shard != shard {
+			return nil, ErrInvalidRSLEntry
+		}
+		entry.IndexEntryID = indexEntry.ID
+		entryStack = append(entryStack, entry)
+
+		entryID = plumbing.ZeroHash
+		if len(commitObj.ParentHashes) == 1 {
+			entryID = commitObj.ParentHashes[0]
+		}
+	}
+
+	entries := make([]*ReferenceEntry, 0, len(entryStack))
+	for i := len(entryStack) - 1; i >= 0; i-- {
+		entries = append(entries, entryStack[i])
+	}
+
+	return entries, nil
+}
+
+// expandShardEntries returns the entries with the shard entries for the ref
+// recorded by each index entry added after the index entry.
+func expandShardEntries(repo *git.Repository, entries []*ReferenceEntry, refName string) ([]*ReferenceEntry, error) {
+	previousIndexEntries := map[string]*ReferenceEntry{}
+	expandedEntries := make([]*ReferenceEntry, 0, len(entries))
+	for _, entry := range entries {
+		expandedEntries = append(expandedEntries, entry)
+		if !IsShardRef(entry.RefName) {
+			continue
+		}
+
+		previousIndexEntry, seen := previousIndexEntries[entry.RefName]
+		if !seen {
+			var err error
+			previousIndexEntry, err = getPreviousIndexEntry(repo, entry)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		shardEntries, err := getShardEntriesAfter(repo, entry, previousIndexEntry)
+		if err != nil {
+			return nil, err
+		}
+		for _, shardEntry := range shardEntries {
+			if shardEntry.RefName == refName {
+				expandedEntries = append(expandedEntries, shardEntry)
+			}
+		}
+
+		previousIndexEntries[entry.RefName] = entry
+	}
+
+	return expandedEntries, nil
+}
+
+// loadShardEntry returns the entry if it's recorded in an RSL shard rather
+// than the RSL. The entry's commit message is parsed directly as annotations
+// aren't recorded in shards.
+func loadShardEntry(repo *git.Repository, entryID plumbing.Hash) (*ReferenceEntry, bool) {
+	commitObj, err := gitinterface.GetCommit(repo, entryID)
+	if err != nil {
+		return nil, false
+	}
+
+	if !strings.HasPrefix(strings.TrimSpace(commitObj.Message), ReferenceEntryHeader) {
+		return nil, false
+	}
+
+	entry, err := parseReferenceEntryText(entryID, strings.TrimSpace(commitObj.Message))
+	if err != nil || entry.Shard == "" {
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// getRSLEntryIDForEntry returns the ID of the entry if it's in the RSL, or the
+// ID of the index entry that records it if it's in an RSL shard.
+func getRSLEntryIDForEntry(repo *git.Repository, entryID plumbing.Hash) (plumbing.Hash, error) {
+	shardEntry, isShardEntry := loadShardEntry(repo, entryID)
+	if !isShardEntry {
+		return entryID, nil
+	}
+
+	indexEntry, err := GetIndexEntryForShardEntry(repo, shardEntry)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return indexEntry.ID, nil
+}
+
+// trimEntriesToRange removes the entries before the first entry and after the
+// last entry, which may be shard entries included after their index entries.
+func trimEntriesToRange(entries []*ReferenceEntry, firstID, lastID plumbing.Hash) []*ReferenceEntry {
+	for i, entry := range entries {
+		if entry.ID == firstID {
+			entries = entries[i:]
+			break
+		}
+	}
+
+	for i, entry := range entries {
+		if entry.ID == lastID {
+			return entries[:i+1]
+		}
+	}
+
+	return entries
+}
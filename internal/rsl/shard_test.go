@@ -0,0 +1,237 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rsl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateShardName(t *testing.T) {
+	assert.Nil(t, ValidateShardName("team-a"))
+	assert.ErrorIs(t, ValidateShardName(""), ErrInvalidShardName)
+	assert.ErrorIs(t, ValidateShardName("team/a"), ErrInvalidShardName)
+	assert.ErrorIs(t, ValidateShardName("team..a"), ErrInvalidShardName)
+}
+
+func TestIsShardRef(t *testing.T) {
+	assert.True(t, IsShardRef("refs/gittuf/rsl/team-a"))
+	assert.False(t, IsShardRef(ShardRefPrefix))
+	assert.False(t, IsShardRef(Ref))
+	assert.False(t, IsShardRef("refs/heads/main"))
+}
+
+func TestRSLShards(t *testing.T) {
+	shard := "team-a"
+	refName := "refs/heads/team-a/feature"
+	anotherRefName := "refs/heads/team-a/fix"
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	// Record an entry in the RSL before the shard is used
+	if err := NewReferenceEntry(refName, testHash(1)).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rslEntry := entry.(*ReferenceEntry)
+
+	// Nothing has been recorded in the shard, so the RSL is used
+	latestEntry, _, err := GetLatestReferenceEntryForRef(repo, refName)
+	assert.Nil(t, err)
+	assert.Equal(t, rslEntry.ID, latestEntry.ID)
+
+	if err := NewReferenceEntry(refName, testHash(2)).CommitToShard(repo, shard, false); err != nil {
+		t.Fatal(err)
+	}
+	firstIndexEntry, _, err := GetLatestReferenceEntryForRef(repo, ShardRef(shard))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewReferenceEntry("refs/heads/main", testHash(3)).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewReferenceEntry(refName, testHash(4)).CommitToShard(repo, shard, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewReferenceEntry(anotherRefName, testHash(5)).CommitToShard(repo, shard, false); err != nil {
+		t.Fatal(err)
+	}
+	latestIndexEntry, _, err := GetLatestReferenceEntryForRef(repo, ShardRef(shard))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The shard ref points to the tip recorded in the latest index entry
+	shardRef, err := repo.Reference(plumbing.ReferenceName(ShardRef(shard)), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, latestIndexEntry.TargetID, shardRef.Hash())
+
+	t.Run("get latest entry in shard", func(t *testing.T) {
+		latestEntry, annotations, err := GetLatestReferenceEntryForRef(repo, refName)
+		assert.Nil(t, err)
+		assert.Empty(t, annotations)
+		assert.Equal(t, testHash(4), latestEntry.TargetID)
+		assert.Equal(t, shard, latestEntry.Shard)
+		assert.Empty(t, latestEntry.Links)
+
+		previousIndexEntry, _, err := GetLatestReferenceEntryForRefBefore(repo, ShardRef(shard), latestIndexEntry.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, previousIndexEntry.ID, latestEntry.IndexEntryID)
+
+		latestEntry, _, err = GetLatestReferenceEntryForRef(repo, anotherRefName)
+		assert.Nil(t, err)
+		assert.Equal(t, testHash(5), latestEntry.TargetID)
+		assert.Equal(t, latestIndexEntry.ID, latestEntry.IndexEntryID)
+	})
+
+	t.Run("get entries before shard entry", func(t *testing.T) {
+		latestEntry, _, err := GetLatestReferenceEntryForRef(repo, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		previousEntry, _, err := GetLatestReferenceEntryForRefBefore(repo, refName, latestEntry.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, testHash(2), previousEntry.TargetID)
+		assert.Equal(t, firstIndexEntry.ID, previousEntry.IndexEntryID)
+
+		// The entry before the first shard entry is in the RSL
+		previousEntry, _, err = GetLatestReferenceEntryForRefBefore(repo, refName, previousEntry.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, rslEntry.ID, previousEntry.ID)
+
+		_, _, err = GetLatestReferenceEntryForRefBefore(repo, refName, previousEntry.ID)
+		assert.ErrorIs(t, err, ErrRSLEntryNotFound)
+	})
+
+	t.Run("get shard entries for index entry", func(t *testing.T) {
+		shardEntries, err := GetShardEntries(repo, firstIndexEntry)
+		assert.Nil(t, err)
+		assert.Len(t, shardEntries, 1)
+		assert.Equal(t, testHash(2), shardEntries[0].TargetID)
+
+		shardEntries, err = GetShardEntries(repo, latestIndexEntry)
+		assert.Nil(t, err)
+		assert.Len(t, shardEntries, 1)
+		assert.Equal(t, anotherRefName, shardEntries[0].RefName)
+
+		_, err = GetShardEntries(repo, rslEntry)
+		assert.ErrorIs(t, err, ErrRSLEntryDoesNotMatchRef)
+	})
+
+	t.Run("get entries in range", func(t *testing.T) {
+		latestEntry, _, err := GetLatestReferenceEntryForRef(repo, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entries, _, err := GetReferenceEntriesInRangeForRef(repo, rslEntry.ID, latestEntry.ID, refName)
+		assert.Nil(t, err)
+
+		targetIDs := []plumbing.Hash{}
+		for _, entry := range entries {
+			if !IsShardRef(entry.RefName) {
+				targetIDs = append(targetIDs, entry.TargetID)
+			}
+		}
+		assert.Equal(t, []plumbing.Hash{testHash(1), testHash(2), testHash(4)}, targetIDs)
+		assert.Equal(t, latestEntry.ID, entries[len(entries)-1].ID)
+
+		// The range can start at a shard entry
+		firstShardEntry, _, err := GetLatestReferenceEntryForRefBefore(repo, refName, latestEntry.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries, _, err = GetReferenceEntriesInRangeForRef(repo, firstShardEntry.ID, latestEntry.ID, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, firstShardEntry.ID, entries[0].ID)
+		assert.Equal(t, latestEntry.ID, entries[len(entries)-1].ID)
+	})
+
+	t.Run("annotations for shard entries", func(t *testing.T) {
+		latestEntry, _, err := GetLatestReferenceEntryForRef(repo, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := NewAnnotationEntry([]plumbing.Hash{latestEntry.ID}, true, annotationMessage).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		latestEntry, annotations, err := GetLatestReferenceEntryForRef(repo, refName)
+		assert.Nil(t, err)
+		assert.Len(t, annotations, 1)
+		assert.True(t, latestEntry.SkippedBy(annotations))
+
+		unskippedEntry, _, err := GetLatestUnskippedReferenceEntryForRef(repo, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, testHash(2), unskippedEntry.TargetID)
+	})
+
+	t.Run("ref moved to another shard", func(t *testing.T) {
+		if err := NewReferenceEntry(refName, testHash(7)).CommitToShard(repo, "team-b", false); err != nil {
+			t.Fatal(err)
+		}
+
+		// Entries are found in every shard, not only the ref's current one
+		latestEntry, _, err := GetLatestReferenceEntryForRef(repo, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, testHash(7), latestEntry.TargetID)
+		assert.Equal(t, "team-b", latestEntry.Shard)
+
+		previousEntry, _, err := GetLatestReferenceEntryForRefBefore(repo, refName, latestEntry.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, testHash(4), previousEntry.TargetID)
+		assert.Equal(t, shard, previousEntry.Shard)
+	})
+
+	t.Run("diverged shard", func(t *testing.T) {
+		// Record an index entry for a shard tip that doesn't extend the
+		// previously indexed tip
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(ShardRef(shard)), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+		shardEntry := NewReferenceEntry(refName, testHash(6))
+		shardEntry.Shard = shard
+		message, err := shardEntry.createCommitMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		newShardTip, err := gitinterface.Commit(repo, gitinterface.EmptyTree(), ShardRef(shard), message, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := NewReferenceEntry(ShardRef(shard), newShardTip).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		_, _, err = GetLatestReferenceEntryForRef(repo, refName)
+		assert.ErrorIs(t, err, ErrShardDiverged)
+	})
+}
+
+func testHash(i int) plumbing.Hash {
+	return plumbing.NewHash(fmt.Sprintf("%040d", i))
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package dsse
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// PayloadCache memoizes decoded envelope payloads. Entries are keyed by the
+// SHA-256 digest of the envelope's encoded payload and the type the payload
+// was decoded into. As gittuf reads the same envelopes repeatedly when
+// verifying overlapping RSL ranges, this ensures each payload is decoded and
+// validated only once. Values returned from the cache are shared, and must be
+// treated as read-only by callers.
+type PayloadCache struct {
+	mu      sync.RWMutex
+	entries map[payloadCacheKey]any
+}
+
+type payloadCacheKey struct {
+	digest  string
+	typeKey reflect.Type
+}
+
+// NewPayloadCache returns an empty PayloadCache.
+func NewPayloadCache() *PayloadCache {
+	return &PayloadCache{entries: map[payloadCacheKey]any{}}
+}
+
+// Len returns the number of decoded payloads stored in the cache.
+func (c *PayloadCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.entries)
+}
+
+// Reset removes all decoded payloads from the cache.
+func (c *PayloadCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[payloadCacheKey]any{}
+}
+
+// PayloadDigest returns the hex encoded SHA-256 digest of the envelope's
+// encoded payload. The payload type is included so that envelopes with the
+// same payload bytes but different types are not conflated.
+func PayloadDigest(envelope *dsse.Envelope) string {
+	hash := sha256.New()
+	hash.Write([]byte(envelope.PayloadType))
+	hash.Write([]byte{0})
+	hash.Write([]byte(envelope.Payload))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// DecodePayload decodes the envelope's payload into a new instance of T. If
+// validate is set, the decoded payload is passed to it and only payloads that
+// are successfully validated are cached. If cache is nil, the payload is
+// decoded without memoization.
+func DecodePayload[T any](cache *PayloadCache, envelope *dsse.Envelope, validate func(*T) error) (*T, error) {
+	var key payloadCacheKey
+	if cache != nil {
+		key = payloadCacheKey{digest: PayloadDigest(envelope), typeKey: reflect.TypeFor[T]()}
+
+		cache.mu.RLock()
+		cached, has := cache.entries[key]
+		cache.mu.RUnlock()
+		if has {
+			return cached.(*T), nil
+		}
+	}
+
+	payloadBytes, err := envelope.DecodeB64Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	payload := new(T)
+	if err := json.Unmarshal(payloadBytes, payload); err != nil {
+		return nil, err
+	}
+
+	if validate != nil {
+		if err := validate(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	if cache != nil {
+		cache.mu.Lock()
+		cache.entries[key] = payload
+		cache.mu.Unlock()
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package dsse
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodePayload(t *testing.T) {
+	rootMetadata := tuf.NewRootMetadata()
+	rootMetadata.SetExpires("2030-01-01T00:00:00Z")
+	env, err := CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("without cache", func(t *testing.T) {
+		first, err := DecodePayload[tuf.RootMetadata](nil, env, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, rootMetadata, first)
+
+		second, err := DecodePayload[tuf.RootMetadata](nil, env, nil)
+		assert.Nil(t, err)
+		assert.NotSame(t, first, second)
+	})
+
+	t.Run("with cache", func(t *testing.T) {
+		cache := NewPayloadCache()
+
+		first, err := DecodePayload[tuf.RootMetadata](cache, env, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, rootMetadata, first)
+		assert.Equal(t, 1, cache.Len())
+
+		second, err := DecodePayload[tuf.RootMetadata](cache, env, nil)
+		assert.Nil(t, err)
+		assert.Same(t, first, second)
+		assert.Equal(t, 1, cache.Len())
+
+		// Same envelope decoded into a different type is cached separately
+		_, err = DecodePayload[map[string]any](cache, env, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, cache.Len())
+
+		cache.Reset()
+		assert.Equal(t, 0, cache.Len())
+	})
+
+	t.Run("failed validation is not cached", func(t *testing.T) {
+		cache := NewPayloadCache()
+		errValidation := errors.New("invalid payload")
+
+		_, err := DecodePayload(cache, env, func(*tuf.RootMetadata) error { return errValidation })
+		assert.ErrorIs(t, err, errValidation)
+		assert.Equal(t, 0, cache.Len())
+	})
+}
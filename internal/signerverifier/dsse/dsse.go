@@ -4,10 +4,12 @@ package dsse
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 
 	"github.com/gittuf/gittuf/internal/signerverifier/common"
+	"github.com/gittuf/gittuf/internal/signerverifier/sigcache"
 	"github.com/secure-systems-lab/go-securesystemslib/dsse"
 )
 
@@ -33,7 +35,8 @@ func CreateEnvelope(v any) (*dsse.Envelope, error) {
 // because it assumes the payload is Base 64 encoded, which is the expectation
 // for gittuf metadata. If one or more signatures from the provided signing key
 // already exist, they are all removed in favor of the new signature from that
-// key.
+// key. Signers without a key ID, such as X.509 certificates, don't replace
+// existing signatures.
 func SignEnvelope(ctx context.Context, envelope *dsse.Envelope, signer dsse.Signer) (*dsse.Envelope, error) {
 	keyID, err := signer.KeyID()
 	if err != nil {
@@ -59,7 +62,7 @@ func SignEnvelope(ctx context.Context, envelope *dsse.Envelope, signer dsse.Sign
 	// Preserve signatures that aren't from signer
 	newSignatures := []dsse.Signature{}
 	for _, sig := range envelope.Signatures {
-		if sig.KeyID != keyID {
+		if keyID == "" || sig.KeyID != keyID {
 			newSignatures = append(newSignatures, sig)
 		}
 	}
@@ -76,15 +79,70 @@ func SignEnvelope(ctx context.Context, envelope *dsse.Envelope, signer dsse.Sign
 // a slice of verifiers passed into it. Threshold indicates the number of
 // providers that must validate the envelope.
 func VerifyEnvelope(ctx context.Context, envelope *dsse.Envelope, verifiers []dsse.Verifier, threshold int) error {
+	_, err := VerifyEnvelopeAndGetKeyIDs(ctx, envelope, verifiers, threshold)
+	return err
+}
+
+// VerifyEnvelopeAndGetKeyIDs verifies a DSSE envelope like VerifyEnvelope, and
+// returns the IDs of the keys whose signatures were accepted.
+func VerifyEnvelopeAndGetKeyIDs(ctx context.Context, envelope *dsse.Envelope, verifiers []dsse.Verifier, threshold int) ([]string, error) {
 	if threshold < 1 || threshold > len(verifiers) {
-		return common.ErrInvalidThreshold
+		return nil, common.ErrInvalidThreshold
 	}
 
-	ev, err := dsse.NewMultiEnvelopeVerifier(threshold, verifiers...)
+	cachingVerifiers := make([]dsse.Verifier, 0, len(verifiers))
+	for _, verifier := range verifiers {
+		cachingVerifiers = append(cachingVerifiers, &cachingVerifier{verifier})
+	}
+
+	ev, err := dsse.NewMultiEnvelopeVerifier(threshold, cachingVerifiers...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	_, err = ev.Verify(ctx, envelope)
-	return err
+	acceptedKeys, err := ev.Verify(ctx, envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	keyIDs := make([]string, 0, len(acceptedKeys))
+	for _, key := range acceptedKeys {
+		keyIDs = append(keyIDs, key.KeyID)
+	}
+
+	return keyIDs, nil
+}
+
+// cachingVerifier wraps a verifier to consult the signature cache before
+// verifying a signature, and to record successful verifications in it.
+type cachingVerifier struct {
+	dsse.Verifier
+}
+
+// Verify implements the dsse.Verifier.Verify interface. The verification is
+// keyed by the signed data, the signature, and the verifier's public key.
+// Verifiers that trust certificates rather than a single public key have no
+// public key, and their verifications aren't cached, as whether a signature is
+// accepted depends on the validity of the certificates.
+func (v *cachingVerifier) Verify(ctx context.Context, data, sig []byte) error {
+	verify := func() error {
+		return v.Verifier.Verify(ctx, data, sig)
+	}
+
+	public := v.Public()
+	if public == nil {
+		return verify()
+	}
+
+	keyID, err := v.KeyID()
+	if err != nil {
+		return verify()
+	}
+
+	publicKey, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return verify()
+	}
+
+	return sigcache.Verify(sigcache.Digest(data, sig, publicKey), keyID, verify)
 }
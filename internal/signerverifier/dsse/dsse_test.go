@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/signerverifier/sigcache"
 	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
 	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
 	"github.com/gittuf/gittuf/internal/tuf"
@@ -60,6 +61,42 @@ func TestVerifyEnvelope(t *testing.T) {
 	}
 
 	assert.Nil(t, VerifyEnvelope(context.Background(), env, []sslibdsse.Verifier{signer.Verifier}, 1))
+
+	keyIDs, err := VerifyEnvelopeAndGetKeyIDs(context.Background(), env, []sslibdsse.Verifier{signer.Verifier}, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SHA256:oNYBImx035m3rl1Sn/+j5DPrlS9+zXn7k3mjNrC5eto"}, keyIDs)
+
+	t.Run("with signature cache", func(t *testing.T) {
+		if err := sigcache.Enable(t.TempDir()); err != nil {
+			t.Fatal(err)
+		}
+		defer sigcache.Disable()
+
+		verifier := &countingVerifier{Verifier: signer.Verifier}
+		for i := 0; i < 2; i++ {
+			keyIDs, err := VerifyEnvelopeAndGetKeyIDs(context.Background(), env, []sslibdsse.Verifier{verifier}, 1)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"SHA256:oNYBImx035m3rl1Sn/+j5DPrlS9+zXn7k3mjNrC5eto"}, keyIDs)
+		}
+		assert.Equal(t, 1, verifier.calls)
+
+		// A tampered envelope is verified, and rejected
+		tamperedEnv := *env
+		tamperedEnv.Payload = base64.StdEncoding.EncodeToString([]byte("tampered payload"))
+		err := VerifyEnvelope(context.Background(), &tamperedEnv, []sslibdsse.Verifier{verifier}, 1)
+		assert.NotNil(t, err)
+		assert.Equal(t, 2, verifier.calls)
+	})
+}
+
+type countingVerifier struct {
+	sslibdsse.Verifier
+	calls int
+}
+
+func (v *countingVerifier) Verify(ctx context.Context, data, sig []byte) error {
+	v.calls++
+	return v.Verifier.Verify(ctx, data, sig)
 }
 
 func loadSSHSigner(keyPath string) (*ssh.Signer, error) {
@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package dsse
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"filippo.io/age"
+	"filippo.io/age/agessh"
+	sshsv "github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"golang.org/x/crypto/ssh"
+)
+
+// EncryptedPayloadType is the payload type of envelopes whose payload is
+// encrypted using EncryptEnvelope. The payload is an EncryptedPayload.
+const EncryptedPayloadType = "application/vnd.gittuf.encrypted+json"
+
+var (
+	ErrEnvelopeAlreadySigned    = errors.New("envelope must be encrypted before it is signed")
+	ErrEnvelopeAlreadyEncrypted = errors.New("envelope is already encrypted")
+	ErrEnvelopeNotEncrypted     = errors.New("envelope is not encrypted")
+	ErrEnvelopeEncrypted        = errors.New("envelope is encrypted, a decryption key is required")
+	ErrNoEncryptionRecipients   = errors.New("no recipients specified to encrypt envelope for")
+	ErrUnsupportedEncryptionKey = errors.New("key type does not support encryption (not one of ssh-ed25519, ssh-rsa)")
+	ErrNotEncryptedForKey       = errors.New("envelope is not encrypted for the decryption key")
+)
+
+// EncryptedPayload is the payload of an encrypted envelope. It wraps the
+// original envelope, encrypted using age for the recipients' keys.
+type EncryptedPayload struct {
+	// Recipients contains the IDs of the keys the envelope is encrypted for.
+	// They aren't authenticated and only identify who can decrypt the
+	// envelope.
+	Recipients []string `json:"recipients"`
+
+	// Ciphertext is the original envelope, including its payload type,
+	// encrypted using age.
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// EncryptEnvelope returns a new envelope whose payload is the envelope
+// encrypted for the recipients using age. Only ED25519 and RSA keys, either as
+// SSH keys or securesystemslib keys, can be recipients. The envelope must be
+// encrypted before it is signed, so that signatures over the encrypted
+// envelope can be verified without decrypting it.
+func EncryptEnvelope(envelope *dsse.Envelope, recipients []*sslibsv.SSLibKey) (*dsse.Envelope, error) {
+	if len(envelope.Signatures) != 0 {
+		return nil, ErrEnvelopeAlreadySigned
+	}
+	if IsEncrypted(envelope) {
+		return nil, ErrEnvelopeAlreadyEncrypted
+	}
+	if len(recipients) == 0 {
+		return nil, ErrNoEncryptionRecipients
+	}
+
+	encryptedPayload := &EncryptedPayload{Recipients: make([]string, 0, len(recipients))}
+	ageRecipients := make([]age.Recipient, 0, len(recipients))
+	for _, key := range recipients {
+		recipient, err := getAgeRecipient(key)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encrypt for key '%s': %w", key.KeyID, err)
+		}
+		ageRecipients = append(ageRecipients, recipient)
+		encryptedPayload.Recipients = append(encryptedPayload.Recipients, key.KeyID)
+	}
+
+	envelopeBytes, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext := new(bytes.Buffer)
+	writer, err := age.Encrypt(ciphertext, ageRecipients...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(envelopeBytes); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	encryptedPayload.Ciphertext = ciphertext.Bytes()
+
+	encryptedPayloadBytes, err := json.Marshal(encryptedPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dsse.Envelope{
+		Signatures:  []dsse.Signature{},
+		PayloadType: EncryptedPayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(encryptedPayloadBytes),
+	}, nil
+}
+
+// DecryptEnvelope returns the original envelope of the encrypted envelope,
+// decrypted using the identity. The returned envelope has no signatures,
+// signatures must be verified using the encrypted envelope.
+func DecryptEnvelope(envelope *dsse.Envelope, identity age.Identity) (*dsse.Envelope, error) {
+	encryptedPayload, err := GetEncryptedPayload(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := age.Decrypt(bytes.NewReader(encryptedPayload.Ciphertext), identity)
+	if err != nil {
+		var noMatchErr *age.NoIdentityMatchError
+		if errors.As(err, &noMatchErr) {
+			return nil, ErrNotEncryptedForKey
+		}
+		return nil, err
+	}
+	envelopeBytes, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	decryptedEnvelope := &dsse.Envelope{}
+	if err := json.Unmarshal(envelopeBytes, decryptedEnvelope); err != nil {
+		return nil, err
+	}
+	if IsEncrypted(decryptedEnvelope) {
+		return nil, ErrEnvelopeAlreadyEncrypted
+	}
+	decryptedEnvelope.Signatures = []dsse.Signature{}
+
+	return decryptedEnvelope, nil
+}
+
+// IsEncrypted returns true if the envelope's payload is encrypted.
+func IsEncrypted(envelope *dsse.Envelope) bool {
+	return envelope.PayloadType == EncryptedPayloadType
+}
+
+// GetEncryptedPayload returns the encrypted payload of the envelope, which
+// identifies its recipients.
+func GetEncryptedPayload(envelope *dsse.Envelope) (*EncryptedPayload, error) {
+	if !IsEncrypted(envelope) {
+		return nil, ErrEnvelopeNotEncrypted
+	}
+
+	payloadBytes, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedPayload := &EncryptedPayload{}
+	if err := json.Unmarshal(payloadBytes, encryptedPayload); err != nil {
+		return nil, err
+	}
+
+	return encryptedPayload, nil
+}
+
+// LoadDecryptionKey parses the PEM encoded or OpenSSH ED25519 or RSA private
+// key used to decrypt envelopes.
+func LoadDecryptionKey(keyBytes []byte) (age.Identity, error) {
+	privateKey, err := ssh.ParseRawPrivateKey(keyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := privateKey.(type) {
+	case ed25519.PrivateKey:
+		return agessh.NewEd25519Identity(k)
+	case *ed25519.PrivateKey:
+		return agessh.NewEd25519Identity(*k)
+	case *rsa.PrivateKey:
+		return agessh.NewRSAIdentity(k)
+	}
+
+	return nil, ErrUnsupportedEncryptionKey
+}
+
+// getAgeRecipient returns the age recipient for the key, using the key's SSH
+// public key.
+func getAgeRecipient(key *sslibsv.SSLibKey) (age.Recipient, error) {
+	var sshKey ssh.PublicKey
+	switch key.KeyType {
+	case sslibsv.RSAKeyType, sslibsv.ED25519KeyType:
+		verifier, err := sslibsv.NewVerifierFromSSLibKey(key)
+		if err != nil {
+			return nil, err
+		}
+		sshKey, err = ssh.NewPublicKey(verifier.Public())
+		if err != nil {
+			return nil, err
+		}
+	case sshsv.SSHKeyType:
+		keyBytes, err := base64.StdEncoding.DecodeString(key.KeyVal.Public)
+		if err != nil {
+			return nil, err
+		}
+		sshKey, err = ssh.ParsePublicKey(keyBytes)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrUnsupportedEncryptionKey
+	}
+
+	switch sshKey.Type() {
+	case ssh.KeyAlgoED25519:
+		return agessh.NewEd25519Recipient(sshKey)
+	case ssh.KeyAlgoRSA:
+		return agessh.NewRSARecipient(sshKey)
+	}
+
+	return nil, ErrUnsupportedEncryptionKey
+}
@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package dsse
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+func TestEncryptEnvelope(t *testing.T) {
+	payloadType := "application/vnd.in-toto+json"
+	payload := []byte(`{"predicate": "sensitive"}`)
+	newEnvelope := func() *sslibdsse.Envelope {
+		return &sslibdsse.Envelope{
+			PayloadType: payloadType,
+			Payload:     base64.StdEncoding.EncodeToString(payload),
+			Signatures:  []sslibdsse.Signature{},
+		}
+	}
+
+	privateKeys := map[string][]byte{
+		"rsa":     artifacts.SSHRSAPrivate,
+		"ed25519": artifacts.SSHED25519Private,
+	}
+
+	for name, privateKeyBytes := range privateKeys {
+		identity, err := LoadDecryptionKey(privateKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sslibKey, sshKey := createTestEncryptionKeys(t, privateKeyBytes)
+
+		for keyType, key := range map[string]*tuf.Key{"securesystemslib": sslibKey, "ssh": sshKey} {
+			t.Run(name+" "+keyType+" key", func(t *testing.T) {
+				encryptedEnv, err := EncryptEnvelope(newEnvelope(), []*tuf.Key{key})
+				assert.Nil(t, err)
+				assert.True(t, IsEncrypted(encryptedEnv))
+
+				encryptedPayload, err := GetEncryptedPayload(encryptedEnv)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{key.KeyID}, encryptedPayload.Recipients)
+				assert.NotContains(t, string(encryptedPayload.Ciphertext), "sensitive")
+				assert.NotContains(t, string(encryptedPayload.Ciphertext), payloadType)
+
+				decryptedEnv, err := DecryptEnvelope(encryptedEnv, identity)
+				assert.Nil(t, err)
+				assert.Equal(t, newEnvelope(), decryptedEnv)
+			})
+		}
+	}
+
+	rsaKey, err := LoadDecryptionKey(artifacts.SSHRSAPrivate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, rsaPublicKey := createTestEncryptionKeys(t, artifacts.SSHRSAPrivate)
+	ed25519Key, err := LoadDecryptionKey(artifacts.SSHED25519Private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ed25519PublicKey := createTestEncryptionKeys(t, artifacts.SSHED25519Private)
+
+	t.Run("multiple recipients", func(t *testing.T) {
+		encryptedEnv, err := EncryptEnvelope(newEnvelope(), []*tuf.Key{rsaPublicKey, ed25519PublicKey})
+		assert.Nil(t, err)
+
+		for _, identity := range []age.Identity{rsaKey, ed25519Key} {
+			decryptedEnv, err := DecryptEnvelope(encryptedEnv, identity)
+			assert.Nil(t, err)
+			assert.Equal(t, newEnvelope(), decryptedEnv)
+		}
+
+		encryptedEnv, err = EncryptEnvelope(newEnvelope(), []*tuf.Key{rsaPublicKey})
+		assert.Nil(t, err)
+		_, err = DecryptEnvelope(encryptedEnv, ed25519Key)
+		assert.ErrorIs(t, err, ErrNotEncryptedForKey)
+	})
+
+	t.Run("signed encrypted envelope", func(t *testing.T) {
+		signer, err := loadSSHSigner(setupTestECDSAPair(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		encryptedEnv, err := EncryptEnvelope(newEnvelope(), []*tuf.Key{rsaPublicKey})
+		if err != nil {
+			t.Fatal(err)
+		}
+		encryptedEnv, err = SignEnvelope(context.Background(), encryptedEnv, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The signature is verified without decrypting the envelope
+		assert.Nil(t, VerifyEnvelope(context.Background(), encryptedEnv, []sslibdsse.Verifier{signer.Verifier}, 1))
+
+		decryptedEnv, err := DecryptEnvelope(encryptedEnv, rsaKey)
+		assert.Nil(t, err)
+		assert.Equal(t, newEnvelope(), decryptedEnv)
+
+		// Signed envelopes can't be encrypted
+		_, err = EncryptEnvelope(encryptedEnv, []*tuf.Key{rsaPublicKey})
+		assert.ErrorIs(t, err, ErrEnvelopeAlreadySigned)
+	})
+
+	t.Run("tampered ciphertext", func(t *testing.T) {
+		encryptedEnv, err := EncryptEnvelope(newEnvelope(), []*tuf.Key{ed25519PublicKey})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		encryptedPayload, err := GetEncryptedPayload(encryptedEnv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encryptedPayload.Ciphertext[len(encryptedPayload.Ciphertext)-1] ^= 1
+		tamperedEnv, err := CreateEnvelope(encryptedPayload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tamperedEnv.PayloadType = EncryptedPayloadType
+
+		_, err = DecryptEnvelope(tamperedEnv, ed25519Key)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("invalid envelopes and recipients", func(t *testing.T) {
+		_, err := EncryptEnvelope(newEnvelope(), nil)
+		assert.ErrorIs(t, err, ErrNoEncryptionRecipients)
+
+		gpgKey, err := gpg.LoadGPGKeyFromBytes(artifacts.GPGKey1Public)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = EncryptEnvelope(newEnvelope(), []*tuf.Key{gpgKey})
+		assert.ErrorIs(t, err, ErrUnsupportedEncryptionKey)
+
+		_, ecdsaPublicKey := createTestEncryptionKeys(t, artifacts.SSHECDSAPrivate)
+		_, err = EncryptEnvelope(newEnvelope(), []*tuf.Key{ecdsaPublicKey})
+		assert.ErrorIs(t, err, ErrUnsupportedEncryptionKey)
+
+		_, err = LoadDecryptionKey(artifacts.SSHECDSAPrivate)
+		assert.ErrorIs(t, err, ErrUnsupportedEncryptionKey)
+
+		encryptedEnv, err := EncryptEnvelope(newEnvelope(), []*tuf.Key{rsaPublicKey})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = EncryptEnvelope(encryptedEnv, []*tuf.Key{rsaPublicKey})
+		assert.ErrorIs(t, err, ErrEnvelopeAlreadyEncrypted)
+
+		_, err = DecryptEnvelope(newEnvelope(), rsaKey)
+		assert.ErrorIs(t, err, ErrEnvelopeNotEncrypted)
+	})
+}
+
+// createTestEncryptionKeys returns the public key for the private key as both
+// a securesystemslib key and an SSH key.
+func createTestEncryptionKeys(t *testing.T, privateKeyBytes []byte) (*tuf.Key, *tuf.Key) {
+	t.Helper()
+
+	privateKey, err := gossh.ParseRawPrivateKey(privateKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey := privateKey.(crypto.Signer).Public()
+
+	sslibKey, err := sslibsv.NewKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshPublicKey, err := gossh.NewPublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshKey := &tuf.Key{
+		KeyID:   gossh.FingerprintSHA256(sshPublicKey),
+		KeyType: ssh.SSHKeyType,
+		Scheme:  sshPublicKey.Type(),
+		KeyVal: sslibsv.KeyVal{
+			Public: base64.StdEncoding.EncodeToString(sshPublicKey.Marshal()),
+		},
+	}
+
+	return sslibKey, sshKey
+}
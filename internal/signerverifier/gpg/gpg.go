@@ -22,7 +22,8 @@ func LoadGPGKeyFromBytes(contents []byte) (*tuf.Key, error) {
 		return nil, err
 	}
 
-	// TODO: check if this is correct for subkeys
+	// Subkeys are identified using the primary key, the armored public key
+	// includes them so that their signatures can be verified
 	fingerprint := fmt.Sprintf("%x", keyring[0].PrimaryKey.Fingerprint)
 	publicKey := strings.TrimSpace(string(contents))
 
@@ -37,3 +38,54 @@ func LoadGPGKeyFromBytes(contents []byte) (*tuf.Key, error) {
 
 	return gpgKey, nil
 }
+
+// MatchesKeyID returns true if keyID identifies the GPG key or one of its
+// subkeys. As with gpg, keyID may be a fingerprint or a long or short key ID,
+// optionally prefixed with "0x" or suffixed with "!". This maps subkeys, such
+// as those used for signing or resident on smartcards, to the primary key
+// listed in policy.
+func MatchesKeyID(key *tuf.Key, keyID string) (bool, error) {
+	keyID = normalizeKeyID(keyID)
+	if !isHex(keyID) {
+		return false, nil
+	}
+
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.KeyVal.Public))
+	if err != nil {
+		return false, err
+	}
+
+	for _, entity := range keyring {
+		if strings.HasSuffix(fmt.Sprintf("%x", entity.PrimaryKey.Fingerprint), keyID) {
+			return true, nil
+		}
+
+		for _, subkey := range entity.Subkeys {
+			if strings.HasSuffix(fmt.Sprintf("%x", subkey.PublicKey.Fingerprint), keyID) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func normalizeKeyID(keyID string) string {
+	keyID = strings.TrimSuffix(keyID, subkeyForcedSuffix)
+	keyID = strings.TrimPrefix(strings.ToLower(keyID), "0x")
+	return keyID
+}
+
+func isHex(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -19,3 +19,31 @@ func TestLoadGPGKeyFromBytes(t *testing.T) {
 	assert.Equal(t, signerverifier.GPGKeyType, key.Scheme)
 	assert.Equal(t, "157507bbe151e378ce8126c1dcfe043cdd2db96e", key.KeyID)
 }
+
+func TestMatchesKeyID(t *testing.T) {
+	// Primary key can only certify, the subkey signs
+	key, err := LoadGPGKeyFromBytes(artifacts.GPGKey3Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		keyID   string
+		matches bool
+	}{
+		"primary key fingerprint":     {keyID: "016919de74d2f27546219b837c2920cc2aa32f10", matches: true},
+		"primary key long ID with 0x": {keyID: "0x7C2920CC2AA32F10", matches: true},
+		"subkey fingerprint":          {keyID: "76BBD167E91074FE1C20255046C2FA80B21F9F60", matches: true},
+		"forced subkey long ID":       {keyID: "46C2FA80B21F9F60!", matches: true},
+		"subkey short ID":             {keyID: "B21F9F60", matches: true},
+		"other key":                   {keyID: "157507bbe151e378ce8126c1dcfe043cdd2db96e", matches: false},
+		"user ID":                     {keyID: "jane.doe@example.com", matches: false},
+	}
+
+	assert.Equal(t, "016919de74d2f27546219b837c2920cc2aa32f10", key.KeyID)
+	for name, test := range tests {
+		matches, err := MatchesKeyID(key, test.keyID)
+		assert.Nil(t, err, name)
+		assert.Equal(t, test.matches, matches, name)
+	}
+}
@@ -0,0 +1,269 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gpg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	subkeyForcedSuffix = "!"
+
+	colonRecordSecretKey    = "sec"
+	colonRecordSecretSubkey = "ssb"
+	colonRecordFingerprint  = "fpr"
+	colonValidityRevoked    = "r"
+	colonValidityExpired    = "e"
+	colonCapabilitySign     = "s"
+)
+
+var (
+	ErrSigningKeyNotFound    = errors.New("unable to find GPG signing key with secret key available")
+	ErrAmbiguousSigningKey   = errors.New("GPG signing key matches more than one key, specify the key using its fingerprint")
+	ErrKeyCannotSign         = errors.New("GPG key is not capable of signing")
+	ErrNoSmartcardSigningKey = errors.New("no signature key found on smartcard")
+	ErrUnableToSignUsingGPG  = errors.New("unable to sign using GPG")
+	errInvalidColonListing   = errors.New("unable to parse GPG key listing")
+)
+
+// Signer signs data by invoking the gpg program with a specific key in the
+// user's keyring. The key may be a subkey or be resident on a smartcard, in
+// which case gpg-agent handles the card.
+type Signer struct {
+	program string
+
+	// primaryKeyID is the fingerprint of the primary key, which is what
+	// gittuf policy uses to identify GPG keys.
+	primaryKeyID string
+
+	// signingKeyID is the fingerprint of the primary key or subkey that
+	// issues signatures.
+	signingKeyID string
+}
+
+// NewSigner returns a Signer for the specified key, which may be the
+// fingerprint or key ID of a primary key or a subkey, or a user ID. As with
+// gpg, a subkey is used exactly if its ID is suffixed with "!". Otherwise, if a
+// primary key is specified, the newest valid subkey capable of signing is used,
+// falling back to the primary key. The chosen key must have its secret key
+// available, either in the keyring or on a smartcard.
+func NewSigner(program, keyID string) (*Signer, error) {
+	output, err := runGPG(program, nil, "--batch", "--with-colons", "--fixed-list-mode", "--list-secret-keys", "--", keyID)
+	if err != nil {
+		return nil, errors.Join(ErrSigningKeyNotFound, err)
+	}
+
+	keys, err := parseKeyListing(output)
+	if err != nil {
+		return nil, err
+	}
+
+	primaryKeyID, signingKeyID, err := resolveSigningKey(keys, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{program: program, primaryKeyID: primaryKeyID, signingKeyID: signingKeyID}, nil
+}
+
+// NewSmartcardSigner returns a Signer for the signature key on the smartcard
+// currently connected.
+func NewSmartcardSigner(program string) (*Signer, error) {
+	output, err := runGPG(program, nil, "--batch", "--with-colons", "--card-status")
+	if err != nil {
+		return nil, errors.Join(ErrNoSmartcardSigningKey, err)
+	}
+
+	fingerprint, err := parseCardSigningKey(output)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSigner(program, fingerprint+subkeyForcedSuffix)
+}
+
+// PrimaryKeyID returns the fingerprint of the signer's primary key.
+func (s *Signer) PrimaryKeyID() string {
+	return s.primaryKeyID
+}
+
+// SigningKeyID returns the fingerprint of the key that issues signatures,
+// which differs from the primary key's when a subkey is used.
+func (s *Signer) SigningKeyID() string {
+	return s.signingKeyID
+}
+
+// Sign returns an ASCII armored detached signature for data.
+func (s *Signer) Sign(data []byte) ([]byte, error) {
+	signature, err := runGPG(s.program, data, "--detach-sign", "--armor", "--local-user", s.signingKeyID+subkeyForcedSuffix)
+	if err != nil {
+		return nil, errors.Join(ErrUnableToSignUsingGPG, err)
+	}
+	if len(signature) == 0 {
+		return nil, ErrUnableToSignUsingGPG
+	}
+
+	return signature, nil
+}
+
+// listedKey is a key in the output of gpg --list-secret-keys --with-colons.
+type listedKey struct {
+	fingerprint string
+	canSign     bool
+	isValid     bool
+	subkeys     []*listedKey
+}
+
+// parseKeyListing parses the colon-delimited listing of keys. See
+// doc/DETAILS in the GnuPG source for the format.
+func parseKeyListing(output []byte) ([]*listedKey, error) {
+	keys := []*listedKey{}
+	var current *listedKey
+
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Split(strings.TrimSpace(line), ":")
+		switch fields[0] {
+		case colonRecordSecretKey, colonRecordSecretSubkey:
+			if len(fields) < 12 {
+				return nil, errInvalidColonListing
+			}
+
+			key := &listedKey{
+				canSign: strings.Contains(fields[11], colonCapabilitySign),
+				isValid: fields[1] != colonValidityRevoked && fields[1] != colonValidityExpired,
+			}
+			if fields[0] == colonRecordSecretKey {
+				keys = append(keys, key)
+			} else {
+				if len(keys) == 0 {
+					return nil, errInvalidColonListing
+				}
+				primary := keys[len(keys)-1]
+				primary.subkeys = append(primary.subkeys, key)
+			}
+			current = key
+
+		case colonRecordFingerprint:
+			// Fingerprints follow the key they belong to
+			if current == nil || len(fields) < 10 || current.fingerprint != "" {
+				continue
+			}
+			current.fingerprint = strings.ToLower(fields[9])
+		}
+	}
+
+	for _, key := range keys {
+		if key.fingerprint == "" {
+			return nil, errInvalidColonListing
+		}
+		for _, subkey := range key.subkeys {
+			if subkey.fingerprint == "" {
+				return nil, errInvalidColonListing
+			}
+		}
+	}
+
+	return keys, nil
+}
+
+// resolveSigningKey returns the fingerprints of the primary key and the key
+// used to sign for the requested key ID.
+func resolveSigningKey(keys []*listedKey, keyID string) (string, string, error) {
+	forced := strings.HasSuffix(keyID, subkeyForcedSuffix)
+	normalizedKeyID := normalizeKeyID(keyID)
+
+	var (
+		primary *listedKey
+		signing *listedKey
+	)
+	for _, key := range keys {
+		if isHex(normalizedKeyID) && strings.HasSuffix(key.fingerprint, normalizedKeyID) {
+			if primary != nil {
+				return "", "", ErrAmbiguousSigningKey
+			}
+			primary = key
+			continue
+		}
+
+		for _, subkey := range key.subkeys {
+			if isHex(normalizedKeyID) && strings.HasSuffix(subkey.fingerprint, normalizedKeyID) {
+				if primary != nil {
+					return "", "", ErrAmbiguousSigningKey
+				}
+				primary = key
+				signing = subkey
+			}
+		}
+	}
+
+	if primary == nil {
+		// The key was likely specified using a user ID, which gpg has already
+		// matched
+		if len(keys) != 1 {
+			if len(keys) == 0 {
+				return "", "", ErrSigningKeyNotFound
+			}
+			return "", "", ErrAmbiguousSigningKey
+		}
+		primary = keys[0]
+	}
+
+	if signing == nil && forced {
+		signing = primary
+	}
+
+	if signing == nil {
+		// Like gpg, prefer the newest valid signing subkey
+		for i := len(primary.subkeys) - 1; i >= 0; i-- {
+			if primary.subkeys[i].canSign && primary.subkeys[i].isValid {
+				signing = primary.subkeys[i]
+				break
+			}
+		}
+		if signing == nil {
+			signing = primary
+		}
+	}
+
+	if !signing.canSign || !signing.isValid {
+		return "", "", fmt.Errorf("%w: '%s'", ErrKeyCannotSign, signing.fingerprint)
+	}
+
+	return primary.fingerprint, signing.fingerprint, nil
+}
+
+// parseCardSigningKey returns the fingerprint of the signature key in the
+// output of gpg --card-status --with-colons.
+func parseCardSigningKey(output []byte) (string, error) {
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Split(strings.TrimSpace(line), ":")
+		if fields[0] != colonRecordFingerprint {
+			continue
+		}
+
+		// The signature, encryption, and authentication key fingerprints
+		// follow in that order
+		if len(fields) < 2 || fields[1] == "" {
+			return "", ErrNoSmartcardSigningKey
+		}
+		return strings.ToLower(fields[1]), nil
+	}
+
+	return "", ErrNoSmartcardSigningKey
+}
+
+func runGPG(program string, stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.Command(program, args...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	// gpg-agent may prompt for a passphrase or smartcard PIN
+	cmd.Stderr = os.Stderr
+
+	return cmd.Output()
+}
@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gpg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testPrimaryFingerprint        = "9a69930a909fb9ba3e94a999c23a1cf3ab7b3928"
+	testFirstSigningSubkeyFpr     = "d1af86416fea7ef16ca40397a35fee08e9a1c99c"
+	testEncryptionSubkeyFpr       = "ff2f8ebf277998ca6f49dce306c2bac8455c9b19"
+	testSecondSigningSubkeyFpr    = "d2cfb1217040a2e814062c11e42fc8d81eeb6ae3"
+	testSigningPrimaryFingerprint = "157507bbe151e378ce8126c1dcfe043cdd2db96e"
+
+	// Primary key that can only certify, with two signing subkeys and an
+	// encryption subkey
+	testKeyListingWithSubkeys = `sec:u:255:22:C23A1CF3AB7B3928:1792182233:::u:::cESC:::+::ed25519:::0:
+fpr:::::::::9A69930A909FB9BA3E94A999C23A1CF3AB7B3928:
+grp:::::::::3952C34A9EE77E30AC17DA98963A67B2B9A7D28E:
+uid:u::::1792182233::03DC22E9F897D3AE136C29CF64B7901137DAC1A2::Test <t@example.com>::::::::::0:
+ssb:u:255:22:A35FEE08E9A1C99C:1792182233::::::s:::+::ed25519::
+fpr:::::::::D1AF86416FEA7EF16CA40397A35FEE08E9A1C99C:
+grp:::::::::055DB62BC2C73AEA81E580F54CA56C607706F02C:
+ssb:u:255:18:06C2BAC8455C9B19:1792182233::::::e:::+::cv25519::
+fpr:::::::::FF2F8EBF277998CA6F49DCE306C2BAC8455C9B19:
+grp:::::::::DB0FDD643300F1A88FE14FFBECD41CB21C04933D:
+ssb:u:255:22:E42FC8D81EEB6AE3:1792182233::::::s:::+::ed25519::
+fpr:::::::::D2CFB1217040A2E814062C11E42FC8D81EEB6AE3:
+grp:::::::::9FDF651164C1B62BFE6B863C63ECC823B8DDD85B:
+`
+
+	// Primary key that can sign, resident on a smartcard
+	testKeyListingSigningPrimary = `sec:u:255:22:DCFE043CDD2DB96E:1792182233:::u:::scSC:::D2760001240100000006123456780000::ed25519:::0:
+fpr:::::::::157507BBE151E378CE8126C1DCFE043CDD2DB96E:
+uid:u::::1792182233::03DC22E9F897D3AE136C29CF64B7901137DAC1A2::Other <o@example.com>::::::::::0:
+`
+)
+
+func TestResolveSigningKey(t *testing.T) {
+	expiredSecondSubkey := strings.Replace(testKeyListingWithSubkeys, "ssb:u:255:22:E42FC8D81EEB6AE3", "ssb:e:255:22:E42FC8D81EEB6AE3", 1)
+
+	tests := map[string]struct {
+		listing              string
+		keyID                string
+		expectedPrimaryKeyID string
+		expectedSigningKeyID string
+		expectedError        error
+	}{
+		"primary fingerprint uses newest signing subkey": {
+			listing:              testKeyListingWithSubkeys,
+			keyID:                strings.ToUpper(testPrimaryFingerprint),
+			expectedPrimaryKeyID: testPrimaryFingerprint,
+			expectedSigningKeyID: testSecondSigningSubkeyFpr,
+		},
+		"primary key ID skips expired subkey": {
+			listing:              expiredSecondSubkey,
+			keyID:                "0xC23A1CF3AB7B3928",
+			expectedPrimaryKeyID: testPrimaryFingerprint,
+			expectedSigningKeyID: testFirstSigningSubkeyFpr,
+		},
+		"subkey ID": {
+			listing:              testKeyListingWithSubkeys,
+			keyID:                "A35FEE08E9A1C99C",
+			expectedPrimaryKeyID: testPrimaryFingerprint,
+			expectedSigningKeyID: testFirstSigningSubkeyFpr,
+		},
+		"forced subkey ID": {
+			listing:              testKeyListingWithSubkeys,
+			keyID:                "A35FEE08E9A1C99C!",
+			expectedPrimaryKeyID: testPrimaryFingerprint,
+			expectedSigningKeyID: testFirstSigningSubkeyFpr,
+		},
+		"user ID": {
+			listing:              testKeyListingWithSubkeys,
+			keyID:                "t@example.com",
+			expectedPrimaryKeyID: testPrimaryFingerprint,
+			expectedSigningKeyID: testSecondSigningSubkeyFpr,
+		},
+		"signing primary key on smartcard": {
+			listing:              testKeyListingSigningPrimary,
+			keyID:                testSigningPrimaryFingerprint + "!",
+			expectedPrimaryKeyID: testSigningPrimaryFingerprint,
+			expectedSigningKeyID: testSigningPrimaryFingerprint,
+		},
+		"forced primary key that cannot sign": {
+			listing:       testKeyListingWithSubkeys,
+			keyID:         testPrimaryFingerprint + "!",
+			expectedError: ErrKeyCannotSign,
+		},
+		"encryption subkey": {
+			listing:       testKeyListingWithSubkeys,
+			keyID:         testEncryptionSubkeyFpr,
+			expectedError: ErrKeyCannotSign,
+		},
+		"expired subkey": {
+			listing:       expiredSecondSubkey,
+			keyID:         testSecondSigningSubkeyFpr,
+			expectedError: ErrKeyCannotSign,
+		},
+		"user ID matching multiple keys": {
+			listing:       testKeyListingWithSubkeys + testKeyListingSigningPrimary,
+			keyID:         "example.com",
+			expectedError: ErrAmbiguousSigningKey,
+		},
+		"no keys": {
+			listing:       "",
+			keyID:         "t@example.com",
+			expectedError: ErrSigningKeyNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		keys, err := parseKeyListing([]byte(test.listing))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		primaryKeyID, signingKeyID, err := resolveSigningKey(keys, test.keyID)
+		if test.expectedError != nil {
+			assert.ErrorIs(t, err, test.expectedError, name)
+			continue
+		}
+
+		assert.Nil(t, err, name)
+		assert.Equal(t, test.expectedPrimaryKeyID, primaryKeyID, name)
+		assert.Equal(t, test.expectedSigningKeyID, signingKeyID, name)
+	}
+}
+
+func TestParseKeyListing(t *testing.T) {
+	t.Run("missing fingerprint", func(t *testing.T) {
+		_, err := parseKeyListing([]byte("sec:u:255:22:C23A1CF3AB7B3928:1792182233:::u:::cESC:::+::ed25519:::0:\n"))
+		assert.ErrorIs(t, err, errInvalidColonListing)
+	})
+
+	t.Run("subkey without primary key", func(t *testing.T) {
+		_, err := parseKeyListing([]byte("ssb:u:255:22:A35FEE08E9A1C99C:1792182233::::::s:::+::ed25519::\n"))
+		assert.ErrorIs(t, err, errInvalidColonListing)
+	})
+}
+
+func TestParseCardSigningKey(t *testing.T) {
+	tests := map[string]struct {
+		output              string
+		expectedFingerprint string
+		expectedError       error
+	}{
+		"signature key present": {
+			output:              "Reader:Yubico YubiKey:AID:::\nversion:0304:\nfpr:157507BBE151E378CE8126C1DCFE043CDD2DB96E:FF2F8EBF277998CA6F49DCE306C2BAC8455C9B19::\n",
+			expectedFingerprint: testSigningPrimaryFingerprint,
+		},
+		"no signature key": {
+			output:        "Reader:Yubico YubiKey:AID:::\nfpr::FF2F8EBF277998CA6F49DCE306C2BAC8455C9B19::\n",
+			expectedError: ErrNoSmartcardSigningKey,
+		},
+		"no fingerprints": {
+			output:        "AID:::\n",
+			expectedError: ErrNoSmartcardSigningKey,
+		},
+	}
+
+	for name, test := range tests {
+		fingerprint, err := parseCardSigningKey([]byte(test.output))
+		if test.expectedError != nil {
+			assert.ErrorIs(t, err, test.expectedError, name)
+		} else {
+			assert.Nil(t, err, name)
+			assert.Equal(t, test.expectedFingerprint, fingerprint, name)
+		}
+	}
+}
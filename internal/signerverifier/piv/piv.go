@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package piv implements signing using keys stored in the PIV slots of a
+// hardware token, such as a YubiKey, so that the private key never leaves the
+// token. The token is accessed through its PKCS#11 module using pkcs11-tool,
+// which is part of OpenSC.
+package piv
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+const (
+	// KeyPrefix identifies a key stored in a PIV slot, in the form
+	// "piv:slot-<slot>", such as "piv:slot-9c".
+	KeyPrefix = "piv:"
+
+	// ModuleEnvKey is the environment variable used to set the path to the
+	// PKCS#11 module for the token, such as OpenSC's opensc-pkcs11.so or
+	// Yubico's libykcs11.so. If unset, pkcs11-tool's default module is used.
+	ModuleEnvKey = "GITTUF_PKCS11_MODULE"
+
+	// PINEnvKey is the environment variable used to set the PIN for the
+	// token. The PIN is passed to pkcs11-tool on stdin rather than as an
+	// argument, which other users can see. If unset, pkcs11-tool prompts for
+	// the PIN when signing.
+	PINEnvKey = "GITTUF_PIV_PIN"
+
+	slotPrefix = "slot-"
+)
+
+var (
+	ErrInvalidSlot        = errors.New("invalid PIV slot, must be one of slot-9a, slot-9c, slot-9d, slot-9e")
+	ErrPKCS11ToolNotFound = errors.New("unable to find pkcs11-tool, is OpenSC installed?")
+	ErrUnsupportedKeyType = errors.New("unsupported PIV key type, only ECDSA keys are supported")
+)
+
+// pkcs11Tool is the program used to access the token.
+var pkcs11Tool = "pkcs11-tool"
+
+// slotObjectIDs maps the PIV slots to the IDs of the PKCS#11 objects for
+// their keys, as exposed by both OpenSC and YKCS11.
+var slotObjectIDs = map[string]string{
+	"9a": "01", // authentication
+	"9c": "02", // digital signature
+	"9d": "03", // key management
+	"9e": "04", // card authentication
+}
+
+// Signer is a dsse.SignerVerifier implementation for keys stored in a PIV
+// slot.
+type Signer struct {
+	objectID string
+	verifier *sslibsv.ECDSASignerVerifier
+	key      *tuf.Key
+}
+
+// NewSignerFromKeyRef creates a Signer for the key in the PIV slot identified
+// by keyRef, in the form "piv:slot-<slot>". The public key is read from the
+// token.
+func NewSignerFromKeyRef(keyRef string) (*Signer, error) {
+	objectID, err := parseKeyRef(keyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKeyBytes, err := runPKCS11Tool(nil, os.Stdin, "--read-object", "--type", "pubkey", "--id", objectID)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(publicKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse public key in PIV slot: %w", err)
+	}
+	if _, isECDSA := publicKey.(*ecdsa.PublicKey); !isECDSA {
+		return nil, ErrUnsupportedKeyType
+	}
+
+	key, err := sslibsv.NewKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := sslibsv.NewECDSASignerVerifierFromSSLibKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{objectID: objectID, verifier: verifier, key: key}, nil
+}
+
+// NewKeyFromKeyRef returns the public key in the PIV slot identified by
+// keyRef, for use in gittuf metadata.
+func NewKeyFromKeyRef(keyRef string) (*tuf.Key, error) {
+	signer, err := NewSignerFromKeyRef(keyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.Key(), nil
+}
+
+// Sign implements the dsse.Signer.Sign interface. The data is hashed like for
+// other ECDSA keys, and the digest is signed by the token. The signature is
+// ASN.1 DER encoded, so that it can be verified using the public key like
+// signatures made using an on-disk ECDSA key.
+func (s *Signer) Sign(_ context.Context, data []byte) ([]byte, error) {
+	var h hash.Hash
+	switch curveSize := s.verifier.Public().(*ecdsa.PublicKey).Params().BitSize; {
+	case curveSize <= 256:
+		h = sha256.New()
+	case curveSize <= 384:
+		h = sha512.New384()
+	default:
+		h = sha512.New()
+	}
+	h.Write(data)
+
+	return s.signDigest(h.Sum(nil))
+}
+
+// CryptoSigner returns a crypto.Signer for the key in the PIV slot, for use
+// with libraries that hash the data themselves, such as when creating SSH
+// signatures.
+func (s *Signer) CryptoSigner() crypto.Signer {
+	return &cryptoSigner{signer: s}
+}
+
+// Verify implements the dsse.Verifier.Verify interface.
+func (s *Signer) Verify(ctx context.Context, data, sig []byte) error {
+	return s.verifier.Verify(ctx, data, sig)
+}
+
+// KeyID implements the dsse.Signer.KeyID and dsse.Verifier.KeyID interfaces.
+func (s *Signer) KeyID() (string, error) {
+	return s.verifier.KeyID()
+}
+
+// Public implements the dsse.Verifier.Public interface.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.verifier.Public()
+}
+
+// Key returns the public key in the PIV slot.
+func (s *Signer) Key() *tuf.Key {
+	return s.key
+}
+
+// signDigest signs the digest using the key in the PIV slot, returning an ASN.1
+// DER encoded signature.
+func (s *Signer) signDigest(digest []byte) ([]byte, error) {
+	stdin := io.Reader(os.Stdin)
+	if pin, has := os.LookupEnv(PINEnvKey); has {
+		// pkcs11-tool prompts for the PIN on stdin when logging in
+		stdin = strings.NewReader(pin + "\n")
+	}
+
+	return runPKCS11Tool(digest, stdin, "--sign", "--mechanism", "ECDSA", "--signature-format", "openssl", "--id", s.objectID, "--login")
+}
+
+// cryptoSigner implements crypto.Signer for a Signer.
+type cryptoSigner struct {
+	signer *Signer
+}
+
+// Public implements the crypto.Signer.Public interface.
+func (c *cryptoSigner) Public() crypto.PublicKey {
+	return c.signer.Public()
+}
+
+// Sign implements the crypto.Signer.Sign interface. The digest is signed by
+// the token, so rand is unused.
+func (c *cryptoSigner) Sign(_ io.Reader, digest []byte, _ crypto.SignerOpts) ([]byte, error) {
+	return c.signer.signDigest(digest)
+}
+
+// parseKeyRef returns the ID of the PKCS#11 object for the slot identified by
+// keyRef.
+func parseKeyRef(keyRef string) (string, error) {
+	slot, hasPrefix := strings.CutPrefix(strings.TrimPrefix(keyRef, KeyPrefix), slotPrefix)
+	if !hasPrefix {
+		return "", fmt.Errorf("%w: '%s'", ErrInvalidSlot, keyRef)
+	}
+
+	objectID, has := slotObjectIDs[strings.ToLower(slot)]
+	if !has {
+		return "", fmt.Errorf("%w: '%s'", ErrInvalidSlot, keyRef)
+	}
+
+	return objectID, nil
+}
+
+// runPKCS11Tool invokes pkcs11-tool with the args, returning its output.
+// Input and output are exchanged using temporary files, so that pkcs11-tool
+// can read the PIN from stdin.
+func runPKCS11Tool(input []byte, stdin io.Reader, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath(pkcs11Tool); err != nil {
+		return nil, ErrPKCS11ToolNotFound
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gittuf-piv-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir) //nolint:errcheck
+
+	if module := os.Getenv(ModuleEnvKey); module != "" {
+		args = append(args, "--module", module)
+	}
+
+	if input != nil {
+		inputPath := filepath.Join(tmpDir, "input")
+		if err := os.WriteFile(inputPath, input, 0o600); err != nil {
+			return nil, err
+		}
+		args = append(args, "--input-file", inputPath)
+	}
+
+	outputPath := filepath.Join(tmpDir, "output")
+	args = append(args, "--output-file", outputPath)
+
+	// pkcs11-tool reports the token it uses on stdout, which must not be
+	// mixed with gittuf's output
+	// The PIN is only passed on stdin
+	cmd := exec.Command(pkcs11Tool, args...) //nolint:gosec
+	cmd.Env = slices.DeleteFunc(os.Environ(), func(variable string) bool {
+		return strings.HasPrefix(variable, PINEnvKey+"=")
+	})
+	cmd.Stdin = stdin
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to run %s: %w", pkcs11Tool, err)
+	}
+
+	return os.ReadFile(outputPath)
+}
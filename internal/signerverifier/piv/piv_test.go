@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package piv
+
+import (
+	"bufio"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	fakeTokenEnvKey = "GITTUF_TEST_FAKE_PIV_TOKEN"
+	fakeTokenKeyEnv = "GITTUF_TEST_FAKE_PIV_KEY"
+	fakeTokenPINEnv = "GITTUF_TEST_FAKE_PIV_PIN"
+
+	testPIN = "123456"
+)
+
+// TestMain allows the test binary to act as pkcs11-tool for a fake token, so
+// that signing can be tested without hardware.
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeTokenEnvKey) == "1" {
+		os.Exit(runFakePKCS11Tool(os.Args[1:]))
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestSigner(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKeyBytes, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testExecutable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaultPKCS11Tool := pkcs11Tool
+	pkcs11Tool = testExecutable
+	defer func() { pkcs11Tool = defaultPKCS11Tool }()
+
+	t.Setenv(fakeTokenEnvKey, "1")
+	t.Setenv(fakeTokenKeyEnv, hex.EncodeToString(privateKeyBytes))
+	t.Setenv(fakeTokenPINEnv, testPIN)
+	t.Setenv(PINEnvKey, testPIN)
+
+	signer, err := NewSignerFromKeyRef("piv:slot-9c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedKey, err := sslibsv.NewKey(privateKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, expectedKey, signer.Key())
+	keyID, err := signer.KeyID()
+	assert.Nil(t, err)
+	assert.Equal(t, expectedKey.KeyID, keyID)
+
+	data := []byte("test data")
+	sig, err := signer.Sign(context.Background(), data)
+	assert.Nil(t, err)
+
+	// The signature is verified like one made using an on-disk key
+	verifier, err := sslibsv.NewVerifierFromSSLibKey(expectedKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, verifier.Verify(context.Background(), data, sig))
+	assert.NotNil(t, verifier.Verify(context.Background(), []byte("other data"), sig))
+
+	t.Run("crypto signer", func(t *testing.T) {
+		digest := sha256.Sum256(data)
+		sig, err := signer.CryptoSigner().Sign(rand.Reader, digest[:], crypto.SHA256)
+		assert.Nil(t, err)
+		assert.True(t, ecdsa.VerifyASN1(&privateKey.PublicKey, digest[:], sig))
+	})
+
+	t.Run("incorrect PIN", func(t *testing.T) {
+		t.Setenv(PINEnvKey, "654321")
+
+		_, err := signer.Sign(context.Background(), data)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("empty slot", func(t *testing.T) {
+		_, err := NewSignerFromKeyRef("piv:slot-9d")
+		assert.NotNil(t, err)
+	})
+}
+
+func TestParseKeyRef(t *testing.T) {
+	tests := map[string]struct {
+		keyRef           string
+		expectedObjectID string
+		expectedErr      error
+	}{
+		"signature slot": {
+			keyRef:           "piv:slot-9c",
+			expectedObjectID: "02",
+		},
+		"authentication slot, uppercase": {
+			keyRef:           "piv:slot-9A",
+			expectedObjectID: "01",
+		},
+		"missing slot prefix": {
+			keyRef:      "piv:9c",
+			expectedErr: ErrInvalidSlot,
+		},
+		"unknown slot": {
+			keyRef:      "piv:slot-82",
+			expectedErr: ErrInvalidSlot,
+		},
+	}
+
+	for name, test := range tests {
+		objectID, err := parseKeyRef(test.keyRef)
+		if test.expectedErr != nil {
+			assert.ErrorIs(t, err, test.expectedErr, name)
+		} else {
+			assert.Nil(t, err, name)
+			assert.Equal(t, test.expectedObjectID, objectID, name)
+		}
+	}
+}
+
+// runFakePKCS11Tool emulates the pkcs11-tool invocations used by the signer
+// for a token with an ECDSA key in slot 9c.
+func runFakePKCS11Tool(args []string) int {
+	privateKeyBytes, err := hex.DecodeString(os.Getenv(fakeTokenKeyEnv))
+	if err != nil {
+		return 1
+	}
+	privateKey, err := x509.ParseECPrivateKey(privateKeyBytes)
+	if err != nil {
+		return 1
+	}
+
+	argValue := func(name string) string {
+		index := slices.Index(args, name)
+		if index == -1 || index+1 >= len(args) {
+			return ""
+		}
+		return args[index+1]
+	}
+
+	if argValue("--id") != "02" {
+		return 1
+	}
+
+	var output []byte
+	switch {
+	case slices.Contains(args, "--read-object"):
+		output, err = x509.MarshalPKIXPublicKey(privateKey.Public())
+	case slices.Contains(args, "--sign"):
+		// The PIN must be read from stdin, and must not be visible in the
+		// arguments or environment
+		if slices.Contains(args, "--pin") || os.Getenv(PINEnvKey) != "" {
+			return 1
+		}
+		pin, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil || strings.TrimSuffix(pin, "\n") != os.Getenv(fakeTokenPINEnv) {
+			return 1
+		}
+
+		var digest []byte
+		digest, err = os.ReadFile(argValue("--input-file"))
+		if err == nil {
+			output, err = ecdsa.SignASN1(rand.Reader, privateKey, digest)
+		}
+	default:
+		return 1
+	}
+	if err != nil {
+		return 1
+	}
+
+	if err := os.WriteFile(argValue("--output-file"), output, 0o600); err != nil {
+		return 1
+	}
+	return 0
+}
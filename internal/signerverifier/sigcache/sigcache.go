@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sigcache records successful signature verifications on disk, so that
+// the same signatures aren't verified again in subsequent gittuf invocations.
+// The cache is disabled by default, in which case every signature is verified.
+//
+// A verification is keyed by the digest of the signed content and signature,
+// and the ID and material of the key used. Callers must only cache
+// verifications whose result doesn't change over time, so verifications that
+// depend on the validity of keys or certificates aren't cached.
+//
+// The cache stores an HMAC of each key using a secret generated for the cache,
+// so a cache file copied from elsewhere, such as another repository, doesn't
+// match. The secret is stored alongside the cache in the repository's Git
+// directory, so the cache is only as trustworthy as that directory: anyone who
+// can write to it can read the secret and add entries for signatures that were
+// never verified. Such a user can already alter the repository's Git config and
+// hooks, so the cache must be disabled using the repository's Git config when
+// the Git directory is shared with users who aren't trusted.
+package sigcache
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	cacheFileName  = "signature-cache"
+	secretFileName = "signature-cache.key"
+	secretSize     = 32
+
+	// maxEntries bounds the size of the cache file. When a cache with more
+	// entries is loaded, it's cleared.
+	maxEntries = 100000
+)
+
+var ErrInvalidCacheSecret = errors.New("signature cache secret is invalid")
+
+type cache struct {
+	mu      sync.Mutex
+	path    string
+	secret  []byte
+	entries map[string]bool
+}
+
+var global atomic.Pointer[cache]
+
+// Enable loads the cache stored in dir, creating it if necessary, and consults
+// it for subsequent verifications. Any previously enabled cache is replaced.
+func Enable(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	secret, err := loadSecret(filepath.Join(dir, secretFileName))
+	if err != nil {
+		return err
+	}
+
+	c := &cache{path: filepath.Join(dir, cacheFileName), secret: secret, entries: map[string]bool{}}
+
+	cacheFile, err := os.Open(c.path)
+	if err == nil {
+		defer cacheFile.Close() //nolint:errcheck
+
+		scanner := bufio.NewScanner(cacheFile)
+		for scanner.Scan() {
+			c.entries[scanner.Text()] = true
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if len(c.entries) > maxEntries {
+		slog.Debug(fmt.Sprintf("Clearing signature cache with %d entries...", len(c.entries)))
+		if err := os.Remove(c.path); err != nil {
+			return err
+		}
+		c.entries = map[string]bool{}
+	}
+
+	global.Store(c)
+	return nil
+}
+
+// Disable stops consulting and recording verifications in the cache.
+func Disable() {
+	global.Store(nil)
+}
+
+// Clear removes all verifications recorded in the cache stored in dir.
+func Clear(dir string) error {
+	if c := global.Load(); c != nil && c.path == filepath.Join(dir, cacheFileName) {
+		c.mu.Lock()
+		c.entries = map[string]bool{}
+		c.mu.Unlock()
+	}
+
+	err := os.Remove(filepath.Join(dir, cacheFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Digest returns the hex encoded SHA-256 digest of the parts, such as the
+// signed content, the signature, and the public key used to verify it. Each
+// part is length prefixed so that the boundaries between parts are
+// unambiguous.
+func Digest(parts ...[]byte) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write(binary.BigEndian.AppendUint64(nil, uint64(len(part))))
+		hash.Write(part)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Verify invokes verify unless the cache records a successful verification of
+// the content with the digest using the key with keyID. A successful
+// verification is recorded in the cache. If the cache is disabled, verify is
+// always invoked.
+func Verify(digest, keyID string, verify func() error) error {
+	c := global.Load()
+	if c == nil || keyID == "" {
+		return verify()
+	}
+
+	entry := c.entry(digest, keyID)
+
+	c.mu.Lock()
+	has := c.entries[entry]
+	c.mu.Unlock()
+	if has {
+		return nil
+	}
+
+	if err := verify(); err != nil {
+		return err
+	}
+
+	c.add(entry)
+	return nil
+}
+
+// entry returns the HMAC of the digest and key ID that is recorded in the
+// cache.
+func (c *cache) entry(digest, keyID string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(Digest([]byte(digest), []byte(keyID))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// add records the entry in the cache. Failing to write the entry to disk only
+// means the signature is verified again in a later invocation, so the error is
+// logged rather than returned.
+func (c *cache) add(entry string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries[entry] {
+		return
+	}
+	c.entries[entry] = true
+
+	cacheFile, err := os.OpenFile(c.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		slog.Debug(fmt.Sprintf("Unable to open signature cache: %s", err.Error()))
+		return
+	}
+	defer cacheFile.Close() //nolint:errcheck
+
+	if _, err := cacheFile.WriteString(entry + "\n"); err != nil {
+		slog.Debug(fmt.Sprintf("Unable to write to signature cache: %s", err.Error()))
+	}
+}
+
+// loadSecret loads the cache's secret from path, generating it if it doesn't
+// exist yet.
+func loadSecret(path string) ([]byte, error) {
+	secret, err := os.ReadFile(path)
+	if err == nil {
+		if len(secret) != secretSize {
+			return nil, ErrInvalidCacheSecret
+		}
+		return secret, nil
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	secret = make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	// Entries can be added by anyone who can read the secret, so it must not
+	// be readable by others
+	if err := os.WriteFile(path, secret, 0o600); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sigcache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	errVerification := errors.New("verification failed")
+
+	calls := 0
+	verify := func() error {
+		calls++
+		return nil
+	}
+
+	digest := Digest([]byte("data"), []byte("signature"))
+
+	t.Run("disabled", func(t *testing.T) {
+		Disable()
+		calls = 0
+
+		assert.Nil(t, Verify(digest, "key", verify))
+		assert.Nil(t, Verify(digest, "key", verify))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := Enable(dir); err != nil {
+			t.Fatal(err)
+		}
+		defer Disable()
+		calls = 0
+
+		assert.Nil(t, Verify(digest, "key", verify))
+		assert.Nil(t, Verify(digest, "key", verify))
+		assert.Equal(t, 1, calls)
+
+		// A different key is not a cache hit
+		assert.Nil(t, Verify(digest, "other-key", verify))
+		assert.Equal(t, 2, calls)
+
+		// Failed verifications are not cached
+		err := Verify(Digest([]byte("data"), []byte("bad-signature")), "key", func() error { return errVerification })
+		assert.ErrorIs(t, err, errVerification)
+		err = Verify(Digest([]byte("data"), []byte("bad-signature")), "key", func() error { return errVerification })
+		assert.ErrorIs(t, err, errVerification)
+
+		// The cache persists across invocations
+		if err := Enable(dir); err != nil {
+			t.Fatal(err)
+		}
+		assert.Nil(t, Verify(digest, "key", verify))
+		assert.Equal(t, 2, calls)
+
+		// The secret is only readable by the owner
+		info, err := os.Stat(filepath.Join(dir, secretFileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+		if err := Clear(dir); err != nil {
+			t.Fatal(err)
+		}
+		assert.Nil(t, Verify(digest, "key", verify))
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("entries recorded with another secret are not trusted", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := Enable(dir); err != nil {
+			t.Fatal(err)
+		}
+		defer Disable()
+		calls = 0
+
+		assert.Nil(t, Verify(digest, "key", verify))
+		assert.Equal(t, 1, calls)
+
+		// Replace the secret, so the recorded entry no longer matches,
+		// mimicking a cache copied from another repository
+		if err := os.WriteFile(filepath.Join(dir, secretFileName), []byte(strings.Repeat("a", secretSize)), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := Enable(dir); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Nil(t, Verify(digest, "key", verify))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("invalid secret", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, secretFileName), []byte("short"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		err := Enable(dir)
+		assert.ErrorIs(t, err, ErrInvalidCacheSecret)
+	})
+}
+
+func TestDigest(t *testing.T) {
+	// Part boundaries are unambiguous
+	assert.NotEqual(t, Digest([]byte("ab"), []byte("c")), Digest([]byte("a"), []byte("bc")))
+	assert.Equal(t, Digest([]byte("a"), []byte("bc")), Digest([]byte("a"), []byte("bc")))
+}
@@ -0,0 +1,343 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package x509 verifies signatures made using X.509 certificates, such as
+// S/MIME signatures created by gpgsm and the short-lived certificates used by
+// gitsign. Signatures are detached CMS / PKCS #7 signatures that embed the
+// signing certificate and its chain.
+//
+// Rather than a raw public key, the policy pins trust on certificates: a
+// certificate authority, whose certificates are trusted, or a specific
+// certificate. Trust may be further restricted to certificates issued to a
+// specific identity. Certificates are checked at the signing time recorded in
+// the signature, so that short-lived certificates remain verifiable after they
+// expire. Unlike with Sigstore keyless signing, no transparency log is used to
+// verify the signing time.
+//
+// Signatures made using certificates are not bound to a single key in the
+// policy, as the same certificate may be trusted through a pinned CA and a
+// pinned identity. DSSE signatures made using a Signer therefore don't record a
+// key ID, and are checked using every X.509 key trusted for the metadata.
+package x509
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/digitorus/pkcs7"
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/secure-systems-lab/go-securesystemslib/cjson"
+)
+
+const (
+	X509KeyType   = "x509"
+	X509KeyScheme = "x509-cms"
+
+	// SignatureBlockType is the PEM block type of signatures, matching the
+	// signatures created by gpgsm and gitsign.
+	SignatureBlockType = "SIGNED MESSAGE"
+
+	certificateBlockType = "CERTIFICATE"
+)
+
+var (
+	ErrNoCertificates      = errors.New("no certificates found")
+	ErrNoPrivateKey        = errors.New("no private key found")
+	ErrMultipleSigners     = errors.New("signature must have exactly one signer")
+	ErrIdentityMismatch    = errors.New("certificate was not issued to the expected identity")
+	ErrPrivateKeyMismatch  = errors.New("private key does not match the signing certificate")
+	ErrUnsupportedKeyUsage = errors.New("certificate cannot be used to sign code or email")
+)
+
+// allowedKeyUsages are the extended key usages of certificates that may sign
+// Git objects and metadata. gitsign issues code signing certificates, while
+// S/MIME certificates are issued for email protection.
+var allowedKeyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning, x509.ExtKeyUsageEmailProtection}
+
+// NewKeyFromBytes returns a key that trusts the PEM encoded certificates, and
+// the certificates they issued. If identity is set, only certificates issued
+// to the identity are trusted. The identity is matched against the email
+// addresses and URIs in a certificate's subject alternative names, and its
+// subject's common name.
+func NewKeyFromBytes(certificates []byte, identity string) (*tuf.Key, error) {
+	parsedCertificates, err := parseCertificates(certificates)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedCertificates := encodeCertificates(parsedCertificates)
+
+	// The key ID is calculated like for other securesystemslib keys, over
+	// the trusted certificates and identity
+	keyIDContents, err := cjson.EncodeCanonical(map[string]any{
+		"keytype": X509KeyType,
+		"scheme":  X509KeyScheme,
+		"keyval": map[string]string{
+			"certificate": encodedCertificates,
+			"identity":    identity,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(keyIDContents)
+
+	return &tuf.Key{
+		KeyID:   hex.EncodeToString(digest[:]),
+		KeyType: X509KeyType,
+		Scheme:  X509KeyScheme,
+		KeyVal: sslibsv.KeyVal{
+			Certificate: encodedCertificates,
+			Identity:    identity,
+		},
+	}, nil
+}
+
+// Verifier is a dsse.Verifier implementation for X.509 keys.
+type Verifier struct {
+	keyID    string
+	roots    *x509.CertPool
+	identity string
+}
+
+// NewVerifierFromKey creates a new Verifier from a key of type x509.
+func NewVerifierFromKey(key *tuf.Key) (*Verifier, error) {
+	if key.KeyType != X509KeyType {
+		return nil, fmt.Errorf("wrong keyType: %s", key.KeyType)
+	}
+
+	certificates, err := parseCertificates([]byte(key.KeyVal.Certificate))
+	if err != nil {
+		return nil, err
+	}
+
+	roots := x509.NewCertPool()
+	for _, certificate := range certificates {
+		roots.AddCert(certificate)
+	}
+
+	return &Verifier{
+		keyID:    key.KeyID,
+		roots:    roots,
+		identity: key.KeyVal.Identity,
+	}, nil
+}
+
+// Verify implements the dsse.Verifier.Verify interface for X.509 keys. The
+// signature must be a detached CMS signature for data, either PEM or DER
+// encoded, made using a certificate trusted by the key.
+func (v *Verifier) Verify(_ context.Context, data []byte, sig []byte) error {
+	if block, _ := pem.Decode(sig); block != nil {
+		sig = block.Bytes
+	}
+
+	signature, err := pkcs7.Parse(sig)
+	if err != nil {
+		return fmt.Errorf("failed to parse signature: %w", err)
+	}
+	signature.Content = data
+
+	certificate := signature.GetOnlySigner()
+	if certificate == nil {
+		return ErrMultipleSigners
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, embeddedCertificate := range signature.Certificates {
+		intermediates.AddCert(embeddedCertificate)
+	}
+
+	// The certificate chain is verified at the signing time recorded in the
+	// signature
+	if err := signature.VerifyWithOpts(x509.VerifyOptions{
+		Roots:         v.roots,
+		Intermediates: intermediates,
+		KeyUsages:     allowedKeyUsages,
+	}); err != nil {
+		return fmt.Errorf("failed to verify signature: %w", err)
+	}
+
+	if v.identity != "" && !hasIdentity(certificate, v.identity) {
+		return ErrIdentityMismatch
+	}
+
+	return nil
+}
+
+// KeyID implements the dsse.Verifier.KeyID interface for X.509 keys.
+func (v *Verifier) KeyID() (string, error) {
+	return v.keyID, nil
+}
+
+// Public implements the dsse.Verifier.Public interface for X.509 keys. An X.509
+// key trusts certificates rather than a single public key, so nil is returned.
+func (v *Verifier) Public() crypto.PublicKey {
+	return nil
+}
+
+// Signer is a dsse.SignerVerifier implementation for a private key and its
+// certificate.
+type Signer struct {
+	key crypto.Signer
+
+	// certificates are the signing certificate followed by its chain.
+	certificates []*x509.Certificate
+}
+
+// NewSignerFromBytes creates a new Signer from a PEM encoded private key and
+// certificates. The first certificate must be issued for the private key, and
+// is followed by the certificates in its chain, which are embedded in
+// signatures.
+func NewSignerFromBytes(contents []byte) (*Signer, error) {
+	certificates, err := parseCertificates(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	var key crypto.Signer
+	for block, rest := pem.Decode(contents); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type == certificateBlockType {
+			continue
+		}
+
+		if signer, err := parsePrivateKey(block); err == nil {
+			key = signer
+			break
+		}
+	}
+	if key == nil {
+		return nil, ErrNoPrivateKey
+	}
+
+	publicKey, isComparable := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !isComparable || !publicKey.Equal(certificates[0].PublicKey) {
+		return nil, ErrPrivateKeyMismatch
+	}
+
+	if !slices.ContainsFunc(certificates[0].ExtKeyUsage, func(usage x509.ExtKeyUsage) bool {
+		return slices.Contains(allowedKeyUsages, usage) || usage == x509.ExtKeyUsageAny
+	}) {
+		return nil, ErrUnsupportedKeyUsage
+	}
+
+	return &Signer{key: key, certificates: certificates}, nil
+}
+
+// Sign implements the dsse.Signer.Sign interface for X.509 keys. It returns a
+// PEM encoded detached CMS signature that embeds the signer's certificates.
+func (s *Signer) Sign(_ context.Context, data []byte) ([]byte, error) {
+	signedData, err := pkcs7.NewSignedData(data)
+	if err != nil {
+		return nil, err
+	}
+	signedData.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+
+	if err := signedData.AddSignerChain(s.certificates[0], s.key, s.certificates[1:], pkcs7.SignerInfoConfig{}); err != nil {
+		return nil, err
+	}
+	signedData.Detach()
+
+	signature, err := signedData.Finish()
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: SignatureBlockType, Bytes: signature}), nil
+}
+
+// KeyID implements the dsse.Signer.KeyID interface for X.509 keys. Signatures
+// made using certificates don't identify a key in the policy, so the key ID is
+// empty.
+func (s *Signer) KeyID() (string, error) {
+	return "", nil
+}
+
+// Verify implements the dsse.Verifier.Verify interface, checking the signature
+// was made using the signer's certificate.
+func (s *Signer) Verify(ctx context.Context, data []byte, sig []byte) error {
+	roots := x509.NewCertPool()
+	roots.AddCert(s.certificates[0])
+
+	verifier := &Verifier{roots: roots}
+	return verifier.Verify(ctx, data, sig)
+}
+
+// Public implements the dsse.Verifier.Public interface, returning the signing
+// certificate's public key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+// hasIdentity checks if the certificate was issued to the identity.
+func hasIdentity(certificate *x509.Certificate, identity string) bool {
+	if certificate.Subject.CommonName == identity || slices.Contains(certificate.EmailAddresses, identity) {
+		return true
+	}
+
+	return slices.ContainsFunc(certificate.URIs, func(uri *url.URL) bool { return uri.String() == identity })
+}
+
+// parsePrivateKey parses a PKCS #8, PKCS #1, or SEC 1 encoded private key.
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, isSigner := key.(crypto.Signer)
+	if !isSigner {
+		return nil, ErrNoPrivateKey
+	}
+
+	return signer, nil
+}
+
+func parseCertificates(contents []byte) ([]*x509.Certificate, error) {
+	certificates := []*x509.Certificate{}
+	for block, rest := pem.Decode(contents); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != certificateBlockType {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+
+	if len(certificates) == 0 {
+		return nil, ErrNoCertificates
+	}
+
+	return certificates, nil
+}
+
+func encodeCertificates(certificates []*x509.Certificate) string {
+	var encoded bytes.Buffer
+	for _, certificate := range certificates {
+		pem.Encode(&encoded, &pem.Block{Type: certificateBlockType, Bytes: certificate.Raw}) //nolint:errcheck
+	}
+
+	return encoded.String()
+}
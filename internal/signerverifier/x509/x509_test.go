@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package x509
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewKeyFromBytes(t *testing.T) {
+	ca := createTestCA(t)
+
+	key, err := NewKeyFromBytes(ca.certificatePEM, "alice@example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, X509KeyType, key.KeyType)
+	assert.Equal(t, X509KeyScheme, key.Scheme)
+	assert.Equal(t, string(ca.certificatePEM), key.KeyVal.Certificate)
+	assert.Equal(t, "alice@example.com", key.KeyVal.Identity)
+
+	// The key ID depends on the identity
+	otherKey, err := NewKeyFromBytes(ca.certificatePEM, "bob@example.com")
+	assert.Nil(t, err)
+	assert.NotEqual(t, key.KeyID, otherKey.KeyID)
+
+	sameKey, err := NewKeyFromBytes(ca.certificatePEM, "alice@example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, key.KeyID, sameKey.KeyID)
+
+	_, err = NewKeyFromBytes([]byte("not a certificate"), "")
+	assert.ErrorIs(t, err, ErrNoCertificates)
+}
+
+func TestSignerVerifier(t *testing.T) {
+	ctx := context.Background()
+	data := []byte("test data")
+
+	ca := createTestCA(t)
+	aliceSigner := ca.issueSigner(t, "alice@example.com", x509.ExtKeyUsageCodeSigning)
+	bobSigner := ca.issueSigner(t, "bob@example.com", x509.ExtKeyUsageEmailProtection)
+
+	aliceSignature, err := aliceSigner.Sign(ctx, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobSignature, err := bobSigner.Sign(ctx, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("CA pinned", func(t *testing.T) {
+		verifier := createTestVerifier(t, ca.certificatePEM, "")
+
+		assert.Nil(t, verifier.Verify(ctx, data, aliceSignature))
+		assert.Nil(t, verifier.Verify(ctx, data, bobSignature))
+		assert.NotNil(t, verifier.Verify(ctx, []byte("other data"), aliceSignature))
+	})
+
+	t.Run("CA and identity pinned", func(t *testing.T) {
+		verifier := createTestVerifier(t, ca.certificatePEM, "alice@example.com")
+
+		assert.Nil(t, verifier.Verify(ctx, data, aliceSignature))
+		assert.ErrorIs(t, verifier.Verify(ctx, data, bobSignature), ErrIdentityMismatch)
+	})
+
+	t.Run("certificate pinned", func(t *testing.T) {
+		verifier := createTestVerifier(t, aliceSigner.certificatePEM(), "")
+
+		assert.Nil(t, verifier.Verify(ctx, data, aliceSignature))
+		assert.NotNil(t, verifier.Verify(ctx, data, bobSignature))
+	})
+
+	t.Run("untrusted CA", func(t *testing.T) {
+		otherCA := createTestCA(t)
+		verifier := createTestVerifier(t, otherCA.certificatePEM, "alice@example.com")
+
+		assert.NotNil(t, verifier.Verify(ctx, data, aliceSignature))
+	})
+
+	t.Run("signer verifies own signature", func(t *testing.T) {
+		assert.Nil(t, aliceSigner.Verify(ctx, data, aliceSignature))
+		assert.NotNil(t, aliceSigner.Verify(ctx, data, bobSignature))
+	})
+
+	t.Run("DSSE envelope", func(t *testing.T) {
+		env, err := dsse.CreateEnvelope(map[string]string{"test": "envelope"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		env, err = dsse.SignEnvelope(ctx, env, aliceSigner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(ctx, env, bobSigner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 2, len(env.Signatures))
+
+		aliceKey, err := NewKeyFromBytes(ca.certificatePEM, "alice@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		bobKey, err := NewKeyFromBytes(ca.certificatePEM, "bob@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		aliceVerifier, err := NewVerifierFromKey(aliceKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bobVerifier, err := NewVerifierFromKey(bobKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keyIDs, err := dsse.VerifyEnvelopeAndGetKeyIDs(ctx, env, []sslibdsse.Verifier{aliceVerifier, bobVerifier}, 2)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{aliceKey.KeyID, bobKey.KeyID}, keyIDs)
+	})
+}
+
+func TestNewSignerFromBytes(t *testing.T) {
+	ca := createTestCA(t)
+	signer := ca.issueSigner(t, "alice@example.com", x509.ExtKeyUsageCodeSigning)
+
+	privateKeyBytes, err := x509.MarshalPKCS8PrivateKey(signer.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKeyBytes})
+
+	t.Run("bundle", func(t *testing.T) {
+		loadedSigner, err := NewSignerFromBytes(append(privateKeyPEM, append(signer.certificatePEM(), ca.certificatePEM...)...))
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(loadedSigner.certificates))
+
+		keyID, err := loadedSigner.KeyID()
+		assert.Nil(t, err)
+		assert.Empty(t, keyID)
+	})
+
+	t.Run("no private key", func(t *testing.T) {
+		_, err := NewSignerFromBytes(signer.certificatePEM())
+		assert.ErrorIs(t, err, ErrNoPrivateKey)
+	})
+
+	t.Run("private key does not match certificate", func(t *testing.T) {
+		_, err := NewSignerFromBytes(append(privateKeyPEM, ca.certificatePEM...))
+		assert.ErrorIs(t, err, ErrPrivateKeyMismatch)
+	})
+
+	t.Run("certificate cannot sign", func(t *testing.T) {
+		serverSigner := ca.issueSigner(t, "server.example.com", x509.ExtKeyUsageServerAuth)
+		serverKeyBytes, err := x509.MarshalPKCS8PrivateKey(serverSigner.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = NewSignerFromBytes(append(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: serverKeyBytes}), serverSigner.certificatePEM()...))
+		assert.ErrorIs(t, err, ErrUnsupportedKeyUsage)
+	})
+}
+
+type testCA struct {
+	key            *ecdsa.PrivateKey
+	certificate    *x509.Certificate
+	certificatePEM []byte
+}
+
+func createTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gittuf test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(certificateBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCA{
+		key:            key,
+		certificate:    certificate,
+		certificatePEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificateBytes}),
+	}
+}
+
+// issueSigner returns a signer using a short-lived certificate issued by the
+// CA to the identity.
+func (c *testCA) issueSigner(t *testing.T, identity string, usage x509.ExtKeyUsage) *Signer {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:   serialNumber,
+		Subject:        pkix.Name{CommonName: identity},
+		EmailAddresses: []string{identity},
+		NotBefore:      time.Now().Add(-time.Minute),
+		NotAfter:       time.Now().Add(10 * time.Minute),
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{usage},
+	}
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, c.certificate, key.Public(), c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(certificateBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Signer{key: key, certificates: []*x509.Certificate{certificate, c.certificate}}
+}
+
+func (s *Signer) certificatePEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.certificates[0].Raw})
+}
+
+func createTestVerifier(t *testing.T, certificates []byte, identity string) *Verifier {
+	t.Helper()
+
+	key, err := NewKeyFromBytes(certificates, identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := NewVerifierFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return verifier
+}
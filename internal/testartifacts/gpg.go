@@ -15,3 +15,12 @@ var GPGKey2Public []byte
 
 //go:embed testdata/keys/gpg/2.asc
 var GPGKey2Private []byte
+
+// GPGKey3 has a primary key that can only certify and a subkey used for
+// signing.
+//
+//go:embed testdata/keys/gpg/3.pub.asc
+var GPGKey3Public []byte
+
+//go:embed testdata/keys/gpg/3.asc
+var GPGKey3Private []byte
@@ -11,15 +11,57 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/danwakefield/fnmatch"
 
 	"github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
 	"github.com/secure-systems-lab/go-securesystemslib/cjson"
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// globstar selects globstar semantics when it appears in a delegation's
+// pattern.
+const globstar = "**"
+
+const (
+	// KeyOperationMergeCommit permits a key to sign merge commits, i.e.,
+	// commits with more than one parent.
+	KeyOperationMergeCommit = "merge-commit"
+
+	// KeyOperationCommit permits a key to sign commits that are not merge
+	// commits.
+	KeyOperationCommit = "commit"
+)
+
+const (
+	// UpdateTypeFastForward is an update to a ref that adds several commits,
+	// none of which are merge commits, atop the ref's previous target.
+	UpdateTypeFastForward = "fast-forward"
+
+	// UpdateTypeSquash is an update to a ref that adds a single commit, that
+	// is not a merge commit, atop the ref's previous target, such as when
+	// squash merging a change.
+	UpdateTypeSquash = "squash"
+
+	// UpdateTypeMerge is an update to a ref that adds at least one merge
+	// commit, such as when a change is merged without fast-forwarding.
+	UpdateTypeMerge = "merge"
+
+	// UpdateTypeNonFastForward is an update to a ref whose previous target is
+	// not an ancestor of its new target, such as when history is rewritten.
+	UpdateTypeNonFastForward = "non-fast-forward"
 )
 
 var (
-	ErrTargetsNotEmpty = errors.New("`targets` field in gittuf Targets metadata must be empty")
+	ErrTargetsNotEmpty     = errors.New("`targets` field in gittuf Targets metadata must be empty")
+	ErrInvalidPattern      = errors.New("invalid delegation pattern")
+	ErrUnknownKeyOperation = errors.New("unknown key operation (not one of merge-commit, commit)")
+	ErrUnknownUpdateType   = errors.New("unknown update type (not one of fast-forward, squash, merge, non-fast-forward)")
+	ErrInvalidRotation     = errors.New("invalid rotation schedule")
 )
 
 // Key defines the structure for how public keys are stored in TUF metadata.
@@ -82,10 +124,58 @@ type Role struct {
 
 // RootMetadata defines the schema of TUF's Root role.
 type RootMetadata struct {
-	Type    string          `json:"type"`
-	Expires string          `json:"expires"`
-	Keys    map[string]*Key `json:"keys"`
-	Roles   map[string]Role `json:"roles"`
+	Type         string          `json:"type"`
+	Expires      string          `json:"expires"`
+	Keys         map[string]*Key `json:"keys"`
+	Roles        map[string]Role `json:"roles"`
+	KeyPolicy    *KeyPolicy      `json:"keyPolicy,omitempty"`
+	KeyRotations []*KeyRotation  `json:"keyRotations,omitempty"`
+
+	// AllowExpiredGPGKeys indicates that signatures made using GPG keys that
+	// have since expired are trusted if the key was valid when the signed
+	// object was recorded in the RSL.
+	AllowExpiredGPGKeys bool `json:"allowExpiredGPGKeys,omitempty"`
+
+	// PolicyApprovalThreshold is the number of policy administrators, i.e.,
+	// holders of root and top-level targets keys, who must approve a change
+	// to the policy before it takes effect. Values below 2 require no
+	// approvals beyond the signatures on the changed metadata.
+	PolicyApprovalThreshold int `json:"policyApprovalThreshold,omitempty"`
+
+	// RSLShards maps the names of RSL shards to the ref namespaces whose
+	// entries are recorded in them, such as "refs/heads/team-a/". Refs in
+	// other namespaces are recorded in the RSL itself.
+	RSLShards map[string][]string `json:"rslShards,omitempty"`
+}
+
+// KeyPolicy records the key algorithms and minimum key sizes (in bits) that
+// are acceptable for keys in gittuf policy. An empty list of allowed algorithms
+// permits all algorithms.
+type KeyPolicy struct {
+	AllowedAlgorithms []string       `json:"allowedAlgorithms,omitempty"`
+	MinimumKeySizes   map[string]int `json:"minimumKeySizes,omitempty"`
+}
+
+// KeyRotation records that a key in the policy was replaced by another key,
+// such as when the key is lost or compromised. Wherever the policy trusts the
+// old key, the new key is trusted instead for RSL entries after the rotation,
+// while the old key continues to be trusted for earlier entries.
+type KeyRotation struct {
+	// OldKey is the key that was replaced.
+	OldKey *Key `json:"oldKey"`
+
+	// NewKey is the key that replaced OldKey.
+	NewKey *Key `json:"newKey"`
+
+	// RSLEntryID is the ID of the latest RSL entry when the key was
+	// rotated. OldKey is trusted for entries up to and including it. It is
+	// the zero ID if the RSL had no entries.
+	RSLEntryID string `json:"rslEntryID"`
+
+	// Proof is an envelope recording the rotation, signed using the new key
+	// and, if it's still available, the old key. This demonstrates that the
+	// holder of the old key handed over to the holder of the new key.
+	Proof *dsse.Envelope `json:"proof"`
 }
 
 // NewRootMetadata returns a new instance of RootMetadata.
@@ -183,15 +273,158 @@ type Delegation struct {
 	Terminating bool             `json:"terminating"`
 	Custom      *json.RawMessage `json:"custom,omitempty"`
 	Role
+
+	// KeyOperations restricts some of the delegation's keys to the listed
+	// operations, keyed by key ID. Such a key's signature only counts towards
+	// the threshold for Git objects created by one of its operations. This is
+	// meant for keys held by third parties that sign on behalf of users, such
+	// as the web-flow key GitHub uses to sign commits created in its web UI.
+	// Keys that are not listed are not restricted.
+	KeyOperations map[string][]string `json:"keyOperations,omitempty"`
+
+	// Rotation, if set, rotates which of the delegation's keys are authorized
+	// over time.
+	Rotation *RotationSchedule `json:"rotation,omitempty"`
+
+	// RequireTicket, if set, requires RSL entries for the refs protected by
+	// the delegation to record the URI of at least one issue or ticket that
+	// tracks the change.
+	RequireTicket bool `json:"requireTicket,omitempty"`
+
+	// AllowedBuilders, if set, requires tags protected by the delegation to
+	// have SLSA provenance from one of the listed builders, signed by one of
+	// the delegation's keys.
+	AllowedBuilders []string `json:"allowedBuilders,omitempty"`
+
+	// RefMappings maps the names of refs on mirrors of the repository to the
+	// names of the refs protected by the delegation in the upstream
+	// repository, whose RSL entries record the refs' changes. This allows a
+	// mirror to publish a ref under a different name, such as publishing the
+	// upstream refs/heads/prod as refs/heads/main.
+	RefMappings map[string]string `json:"refMappings,omitempty"`
+
+	// AllowedUpdateTypes, if set, restricts how the branches protected by the
+	// delegation may be updated between consecutive RSL entries to the listed
+	// update types, such as to require linear history or merge commits.
+	AllowedUpdateTypes []string `json:"allowedUpdateTypes,omitempty"`
+}
+
+// RotationSchedule rotates the keys authorized by a delegation on a fixed
+// calendar, such as for on-call or release duty rotations. Time is divided into
+// consecutive periods of the specified length, starting at Start. Each period
+// is assigned the next shift, cycling back to the first shift after the last.
+// Only the keys in a period's shift are authorized for RSL entries created in
+// that period, determined using the entry's timestamp. No keys are authorized
+// before Start.
+type RotationSchedule struct {
+	// Start is the RFC 3339 timestamp at which the first shift begins.
+	Start string `json:"start"`
+
+	// Period is the length of each shift, as a Go duration string such as
+	// `168h`.
+	Period string `json:"period"`
+
+	// Shifts lists the IDs of the keys authorized in each shift.
+	Shifts [][]string `json:"shifts"`
+}
+
+// Validate checks that the rotation schedule is well formed.
+func (r *RotationSchedule) Validate() error {
+	if _, err := time.Parse(time.RFC3339, r.Start); err != nil {
+		return fmt.Errorf("%w: invalid start: %w", ErrInvalidRotation, err)
+	}
+
+	period, err := time.ParseDuration(r.Period)
+	if err != nil {
+		return fmt.Errorf("%w: invalid period: %w", ErrInvalidRotation, err)
+	}
+	if period <= 0 {
+		return fmt.Errorf("%w: period must be positive", ErrInvalidRotation)
+	}
+
+	if len(r.Shifts) == 0 {
+		return fmt.Errorf("%w: no shifts specified", ErrInvalidRotation)
+	}
+	for i, shift := range r.Shifts {
+		if len(shift) == 0 {
+			return fmt.Errorf("%w: shift %d has no keys", ErrInvalidRotation, i+1)
+		}
+	}
+
+	return nil
 }
 
-// Matches checks if any of the delegation's patterns match the target.
+// ActiveKeyIDs returns the IDs of the keys authorized at the specified time.
+func (r *RotationSchedule) ActiveKeyIDs(at time.Time) ([]string, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	// These are checked in Validate
+	start, _ := time.Parse(time.RFC3339, r.Start)
+	period, _ := time.ParseDuration(r.Period)
+
+	if at.Before(start) {
+		return []string{}, nil
+	}
+
+	index := (at.Sub(start) / period) % time.Duration(len(r.Shifts))
+	return r.Shifts[index], nil
+}
+
+// ValidateKeyOperation returns an error if the operation is unknown.
+func ValidateKeyOperation(operation string) error {
+	switch operation {
+	case KeyOperationMergeCommit, KeyOperationCommit:
+		return nil
+	default:
+		return fmt.Errorf("%w: '%s'", ErrUnknownKeyOperation, operation)
+	}
+}
+
+// ValidateUpdateType returns an error if the update type is unknown.
+func ValidateUpdateType(updateType string) error {
+	switch updateType {
+	case UpdateTypeFastForward, UpdateTypeSquash, UpdateTypeMerge, UpdateTypeNonFastForward:
+		return nil
+	default:
+		return fmt.Errorf("%w: '%s'", ErrUnknownUpdateType, updateType)
+	}
+}
+
+// Matches checks if any of the delegation's patterns match the target. By
+// default, patterns are matched using fnmatch semantics, where `*` also matches
+// `/`. So, `file:src/*` matches every file in `src` and its subdirectories.
+//
+// Patterns that contain `**` are matched using globstar semantics instead. Here,
+// `*` and `?` do not match `/`, and `**` as a path component matches zero or
+// more directories. For example, `file:src/**/*.go` matches `file:src/main.go`
+// and `file:src/a/b/main.go`, but not `file:src/main.c`. Braces such as
+// `file:{cmd,internal}/**` are also supported for such patterns.
 func (d *Delegation) Matches(target string) bool {
 	for _, pattern := range d.Paths {
-		// We validate pattern when it's added to / updated in the metadata
+		if strings.Contains(pattern, globstar) {
+			// We validate pattern when it's added to / updated in the
+			// metadata, so an invalid pattern just doesn't match
+			if matches, err := doublestar.Match(pattern, target); err == nil && matches {
+				return true
+			}
+			continue
+		}
+
 		if matches := fnmatch.Match(pattern, target, 0); matches {
 			return true
 		}
 	}
 	return false
 }
+
+// ValidatePattern checks that the pattern can be used in a delegation. See
+// Matches for the supported syntax.
+func ValidatePattern(pattern string) error {
+	if strings.Contains(pattern, globstar) && !doublestar.ValidatePattern(pattern) {
+		return fmt.Errorf("%w: '%s'", ErrInvalidPattern, pattern)
+	}
+
+	return nil
+}
@@ -244,6 +244,41 @@ func TestDelegationMatches(t *testing.T) {
 			target:   "file:src/signatures/rsa/rsa.go",
 			expected: true,
 		},
+		"globstar pattern, artifact in directory, matches": {
+			patterns: []string{"file:src/**/*.go"},
+			target:   "file:src/main.go",
+			expected: true,
+		},
+		"globstar pattern, artifact in subdirectory, matches": {
+			patterns: []string{"file:src/**/*.go"},
+			target:   "file:src/foo/bar/main.go",
+			expected: true,
+		},
+		"globstar pattern, artifact with other extension, does not match": {
+			patterns: []string{"file:src/**/*.go"},
+			target:   "file:src/foo/main.c",
+			expected: false,
+		},
+		"globstar pattern, artifact in other directory, does not match": {
+			patterns: []string{"file:src/**/*.go"},
+			target:   "file:docs/main.go",
+			expected: false,
+		},
+		"globstar pattern, wildcard does not match separator": {
+			patterns: []string{"file:**/foo/*.txt"},
+			target:   "file:a/foo/bar/baz.txt",
+			expected: false,
+		},
+		"globstar pattern, trailing globstar, matches": {
+			patterns: []string{"file:docs/**"},
+			target:   "file:docs/a/b/c.md",
+			expected: true,
+		},
+		"globstar pattern with braces, matches": {
+			patterns: []string{"file:{cmd,internal}/**/*_test.go"},
+			target:   "file:internal/tuf/tuf_test.go",
+			expected: true,
+		},
 	}
 
 	for name, test := range tests {
@@ -253,6 +288,89 @@ func TestDelegationMatches(t *testing.T) {
 	}
 }
 
+func TestValidatePattern(t *testing.T) {
+	tests := map[string]struct {
+		pattern string
+		err     error
+	}{
+		"fnmatch pattern":         {pattern: "file:src/*"},
+		"globstar pattern":        {pattern: "file:src/**/*.go"},
+		"invalid globstar":        {pattern: "file:src/**/[a-", err: ErrInvalidPattern},
+		"unterminated globstar":   {pattern: "file:{src,docs/**", err: ErrInvalidPattern},
+		"git ref globstar":        {pattern: "git:refs/heads/**"},
+		"git ref fnmatch pattern": {pattern: "git:refs/heads/*"},
+	}
+
+	for name, test := range tests {
+		err := ValidatePattern(test.pattern)
+		if test.err != nil {
+			assert.ErrorIs(t, err, test.err, fmt.Sprintf("unexpected error in test '%s'", name))
+		} else {
+			assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		}
+	}
+}
+
+func TestRotationSchedule(t *testing.T) {
+	rotation := &RotationSchedule{
+		Start:  "2024-01-01T00:00:00Z",
+		Period: "24h",
+		Shifts: [][]string{{"alice"}, {"bob", "carol"}, {"dave"}},
+	}
+
+	tests := map[string]struct {
+		at             time.Time
+		expectedKeyIDs []string
+	}{
+		"before start": {
+			at:             time.Date(2023, time.December, 31, 23, 59, 59, 0, time.UTC),
+			expectedKeyIDs: []string{},
+		},
+		"at start": {
+			at:             time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+			expectedKeyIDs: []string{"alice"},
+		},
+		"second shift": {
+			at:             time.Date(2024, time.January, 2, 12, 0, 0, 0, time.UTC),
+			expectedKeyIDs: []string{"bob", "carol"},
+		},
+		"last shift": {
+			at:             time.Date(2024, time.January, 3, 23, 59, 59, 0, time.UTC),
+			expectedKeyIDs: []string{"dave"},
+		},
+		"wraps around": {
+			at:             time.Date(2024, time.January, 4, 0, 0, 0, 0, time.UTC),
+			expectedKeyIDs: []string{"alice"},
+		},
+		"other time zone": {
+			at:             time.Date(2024, time.January, 2, 2, 0, 0, 0, time.FixedZone("UTC-3", -3*60*60)),
+			expectedKeyIDs: []string{"bob", "carol"},
+		},
+	}
+
+	for name, test := range tests {
+		keyIDs, err := rotation.ActiveKeyIDs(test.at)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		assert.Equal(t, test.expectedKeyIDs, keyIDs, fmt.Sprintf("unexpected keys in test '%s'", name))
+	}
+
+	invalidRotations := map[string]*RotationSchedule{
+		"invalid start":  {Start: "2024-01-01", Period: "24h", Shifts: [][]string{{"alice"}}},
+		"invalid period": {Start: "2024-01-01T00:00:00Z", Period: "1 day", Shifts: [][]string{{"alice"}}},
+		"zero period":    {Start: "2024-01-01T00:00:00Z", Period: "0s", Shifts: [][]string{{"alice"}}},
+		"no shifts":      {Start: "2024-01-01T00:00:00Z", Period: "24h"},
+		"empty shift":    {Start: "2024-01-01T00:00:00Z", Period: "24h", Shifts: [][]string{{"alice"}, {}}},
+	}
+
+	for name, rotation := range invalidRotations {
+		err := rotation.Validate()
+		assert.ErrorIs(t, err, ErrInvalidRotation, fmt.Sprintf("unexpected error in test '%s'", name))
+
+		_, err = rotation.ActiveKeyIDs(time.Now())
+		assert.ErrorIs(t, err, ErrInvalidRotation, fmt.Sprintf("unexpected error in test '%s'", name))
+	}
+}
+
 func TestRootMetadataWithSSHKey(t *testing.T) {
 	// Setup test key pair
 	keys := []struct {
@@ -7,6 +7,7 @@ import (
 	"os"
 	"runtime/debug"
 
+	"github.com/gittuf/gittuf/internal/cmd/exitcodes"
 	"github.com/gittuf/gittuf/internal/cmd/profile"
 	"github.com/gittuf/gittuf/internal/cmd/root"
 )
@@ -31,6 +32,6 @@ func main() {
 		// We can ignore the linter here (deferred functions are not executed
 		// when os.Exit is invoked) because if we do have an error, we don't
 		// have a panic, which is what the deferred function is looking for.
-		os.Exit(1) //nolint:gocritic
+		os.Exit(exitcodes.FromError(err)) //nolint:gocritic
 	}
 }